@@ -67,14 +67,23 @@ func New(opts Options, comm client.Communicator) *Agent {
 // Start starts the agent loop. The agent polls the API server for new tasks
 // at interval agentSleepInterval and runs them.
 func (a *Agent) Start(ctx context.Context) error {
-	err := a.startStatusServer(ctx, a.opts.StatusPort)
+	statusCtx, statusCancel := context.WithCancel(ctx)
+	defer statusCancel()
+	err := a.startStatusServer(statusCtx, a.opts.StatusPort)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	if a.opts.Cleanup {
 		tryCleanupDirectory(a.opts.WorkingDirectory)
 	}
-	return errors.Wrap(a.loop(ctx), "error in agent loop, exiting")
+
+	err = a.loop(ctx)
+	if errors.Cause(err) == errAgentUpdated {
+		// the new agent starts its own status server
+		statusCancel()
+		return errors.Wrap(restartAgent(), "error starting updated agent")
+	}
+	return errors.Wrap(err, "error in agent loop, exiting")
 }
 
 func (a *Agent) loop(ctx context.Context) error {
@@ -109,7 +118,10 @@ LOOP:
 			grip.Info("agent loop canceled")
 			return nil
 		case <-timer.C:
-			nextTask, err := a.comm.GetNextTask(ctx, &apimodels.GetNextTaskDetails{TaskGroup: tc.taskGroup})
+			nextTask, err := a.comm.GetNextTask(ctx, &apimodels.GetNextTaskDetails{
+				TaskGroup:     tc.taskGroup,
+				AgentRevision: evergreen.BuildRevision,
+			})
 			if err != nil {
 				// task secret doesn't match, get another task
 				if errors.Cause(err) == client.HTTPConflictError {
@@ -151,6 +163,9 @@ LOOP:
 				// destroy prior task information.
 				tc = &taskContext{}
 			}
+			if nextTask.AgentUpdate != nil {
+				return a.updateAgent(ctx, nextTask.AgentUpdate)
+			}
 			jitteredSleep = util.JitterInterval(agentSleepInterval)
 			grip.Debugf("Agent sleeping %s", jitteredSleep)
 			timer.Reset(jitteredSleep)
@@ -165,7 +180,7 @@ func (a *Agent) prepareNextTask(ctx context.Context, nextTask *apimodels.NextTas
 		setupGroup = true
 		taskDirectory = ""
 		a.runPostGroupCommands(ctx, tc)
-		if nextTask.NewAgent {
+		if nextTask.NewAgent || nextTask.AgentUpdate != nil {
 			return &taskContext{}, true
 		}
 	}
@@ -0,0 +1,20 @@
+// +build !windows
+
+package agent
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// restartAgent replaces the agent process with the agent's executable, which
+// is run with the same arguments and environment.
+func restartAgent() error {
+	exe, err := agentExecutable()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.Wrapf(syscall.Exec(exe, os.Args, os.Environ()), "error running %s", exe)
+}
@@ -0,0 +1,26 @@
+// +build windows
+
+package agent
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/pkg/errors"
+)
+
+// restartAgent starts the agent's executable with the same arguments and
+// environment as the running agent, which should exit once it returns.
+func restartAgent() error {
+	exe, err := agentExecutable()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	cmd := exec.Command(exe, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Start(); err != nil {
+		return errors.Wrapf(err, "error running %s", exe)
+	}
+	return errors.WithStack(cmd.Process.Release())
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// errAgentUpdated is returned by the agent loop once the agent's executable
+// has been replaced, so that the new agent is started in its place.
+var errAgentUpdated = errors.New("agent was updated")
+
+// updateAgent replaces the running agent's executable with the agent of the
+// update.
+func (a *Agent) updateAgent(ctx context.Context, update *apimodels.AgentUpdate) error {
+	exe, err := agentExecutable()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	grip.Info(message.Fields{
+		"message":    "updating agent",
+		"executable": exe,
+		"version":    update.Version,
+		"url":        update.URL,
+	})
+	if err = replaceExecutable(ctx, update.URL, exe); err != nil {
+		return errors.Wrapf(err, "error updating agent to version %s", update.Version)
+	}
+	return errAgentUpdated
+}
+
+// agentExecutable returns the path of the running agent's executable.
+func agentExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", errors.Wrap(err, "error finding agent executable")
+	}
+	exe, err = filepath.EvalSymlinks(exe)
+	return exe, errors.Wrap(err, "error resolving agent executable")
+}
+
+// replaceExecutable downloads the executable at the URL and moves it to the
+// given path. The executable it replaces is kept until the next update, since
+// a running executable cannot be removed on every platform.
+func replaceExecutable(ctx context.Context, url, exe string) error {
+	newExe := exe + ".new"
+	if err := downloadExecutable(ctx, url, newExe); err != nil {
+		grip.Warning(os.Remove(newExe))
+		return errors.WithStack(err)
+	}
+
+	oldExe := exe + ".old"
+	if err := os.Remove(oldExe); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "error removing previous agent executable")
+	}
+	if err := os.Rename(exe, oldExe); err != nil {
+		return errors.Wrap(err, "error moving agent executable")
+	}
+	if err := os.Rename(newExe, exe); err != nil {
+		grip.Error(message.WrapError(os.Rename(oldExe, exe), message.Fields{
+			"message":    "problem restoring agent executable",
+			"executable": exe,
+		}))
+		return errors.Wrap(err, "error moving new agent executable")
+	}
+	return nil
+}
+
+func downloadExecutable(ctx context.Context, url, path string) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "error creating request")
+	}
+	req = req.WithContext(ctx)
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error downloading agent from %s", url)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("error downloading agent from %s: %s", url, resp.Status)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return errors.Wrapf(err, "error creating %s", path)
+	}
+	if _, err = io.Copy(f, resp.Body); err != nil {
+		grip.Warning(f.Close())
+		return errors.Wrapf(err, "error writing %s", path)
+	}
+	return errors.Wrapf(f.Close(), "error closing %s", path)
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceExecutable(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/clients/versions/def/linux_amd64/evergreen" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("new agent"))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "agent-update")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	exe := filepath.Join(dir, "evergreen")
+	require.NoError(ioutil.WriteFile(exe, []byte("old agent"), 0755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Error(replaceExecutable(ctx, srv.URL+"/clients/versions/abc/linux_amd64/evergreen", exe))
+	contents, err := ioutil.ReadFile(exe)
+	require.NoError(err)
+	assert.Equal("old agent", string(contents))
+	_, err = os.Stat(exe + ".new")
+	assert.True(os.IsNotExist(err))
+
+	assert.NoError(replaceExecutable(ctx, srv.URL+"/clients/versions/def/linux_amd64/evergreen", exe))
+	contents, err = ioutil.ReadFile(exe)
+	require.NoError(err)
+	assert.Equal("new agent", string(contents))
+	info, err := os.Stat(exe)
+	require.NoError(err)
+	assert.NotZero(info.Mode() & 0100)
+	contents, err = ioutil.ReadFile(exe + ".old")
+	require.NoError(err)
+	assert.Equal("old agent", string(contents))
+}
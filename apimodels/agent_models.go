@@ -26,6 +26,12 @@ type HeartbeatResponse struct {
 	Abort bool `json:"abort,omitempty"`
 }
 
+// TaskCheckpoint is progress that a running task records, so that it can
+// resume from it if its host is reclaimed.
+type TaskCheckpoint struct {
+	Data string `json:"data"`
+}
+
 // TaskEndDetail contains data sent from the agent to the
 // API server after each task run.
 type TaskEndDetail struct {
@@ -42,6 +48,9 @@ type TaskEndDetails struct {
 
 type GetNextTaskDetails struct {
 	TaskGroup string `json:"task_group"`
+	// AgentRevision is the build revision of the agent. Agents that send
+	// it can update themselves in place.
+	AgentRevision string `json:"agent_revision,omitempty"`
 }
 
 // ExpansionVars is a map of expansion variables for a project.
@@ -66,6 +75,16 @@ type NextTaskResponse struct {
 	// currently in a task group, it should only exit when it has finished
 	// the task group.
 	NewAgent bool `json:"new_agent,omitempty"`
+	// AgentUpdate is the agent the agent should replace itself with. An
+	// agent in a task group only updates itself once it has finished the
+	// task group, and the API sends no task with an update outside of one.
+	AgentUpdate *AgentUpdate `json:"agent_update,omitempty"`
+}
+
+// AgentUpdate is a version of the agent and where to download it from.
+type AgentUpdate struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
 }
 
 // EndTaskResponse is what is returned when the task ends
@@ -166,3 +185,11 @@ func (ch *CreateHost) Validate() error {
 	}
 	return catcher.Resolve()
 }
+
+// TestReport is a raw test report that an agent sends to the api server to
+// be converted into test results.
+type TestReport struct {
+	// Format is either "junit" or "tap".
+	Format  string `json:"format"`
+	Content string `json:"content"`
+}
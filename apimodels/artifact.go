@@ -0,0 +1,32 @@
+package apimodels
+
+import "time"
+
+// ArtifactUploadRequest describes a file that an agent asks the api server to
+// sign an upload URL for.
+type ArtifactUploadRequest struct {
+	// Name is the file's path, relative to the task's directory in the
+	// artifacts bucket.
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// SHA256 is the hex encoded SHA256 digest of the file, which is stored
+	// with it.
+	SHA256 string `json:"sha256"`
+	// MD5 is the base64 encoded MD5 digest of the file, which S3 checks
+	// the upload against.
+	MD5 string `json:"md5"`
+}
+
+// ArtifactUpload is a signed URL that uploads a file to the artifacts bucket
+// with a PUT request, and where the file will be found once it's uploaded.
+type ArtifactUpload struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Headers must all be sent with the upload, since they're signed.
+	Headers   map[string]string `json:"headers"`
+	Link      string            `json:"link"`
+	Bucket    string            `json:"bucket"`
+	Key       string            `json:"key"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -34,3 +34,14 @@ type TaskLog struct {
 	MessageCount int          `json:"c"`
 	Messages     []LogMessage `json:"m"`
 }
+
+// StructuredLogLine is a log line made up of key/value pairs, which the
+// agent sends so that a task's logs can be queried by level, component,
+// and time rather than searched as flat text.
+type StructuredLogLine struct {
+	Component string                 `json:"component"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message,omitempty"`
+	Timestamp time.Time              `json:"ts"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
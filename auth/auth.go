@@ -42,6 +42,13 @@ func LoadUserManager(authConfig evergreen.AuthConfig) (gimlet.UserManager, error
 		}
 		return manager, nil
 	}
+	if authConfig.OIDC != nil {
+		manager, err = NewOIDCUserManager(authConfig.OIDC)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem setting up oidc authentication")
+		}
+		return manager, nil
+	}
 	return nil, errors.New("Must have at least one form of authentication, currently there are none")
 }
 
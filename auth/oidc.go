@@ -0,0 +1,503 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jws"
+)
+
+const (
+	// oidcStateCookie holds the state, nonce and redirect of a login that
+	// is in progress, until the identity provider redirects back.
+	oidcStateCookie = "evergreen-oidc-state"
+	oidcRequestTTL  = 10 * time.Minute
+)
+
+// OIDCUserManager implements the UserManager with an OpenID Connect identity
+// provider, using the authorization code flow.
+// The login handler redirects the user to the provider along with an
+// unguessable state and nonce, which it also keeps in a short-lived cookie.
+// When the provider redirects the user back with a code, the callback checks
+// the state, exchanges the code for an ID token and verifies the token's
+// signature against the provider's published keys, along with its issuer,
+// audience, expiry and nonce. If the user is a member of one of the allowed
+// groups, the callback stores the user, along with the project roles that
+// its groups map to, and starts a session whose token is stored in the
+// login cookie. GetUserByToken looks sessions up until they expire, and
+// logging out ends them.
+type OIDCUserManager struct {
+	conf        evergreen.OIDCConfig
+	sessionTTL  time.Duration
+	redirectURL string
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcKeySet struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// NewOIDCUserManager initializes an OIDCUserManager. The provider's
+// endpoints are discovered when users first log in.
+func NewOIDCUserManager(conf *evergreen.OIDCConfig) (gimlet.UserManager, error) {
+	c := *conf
+	if err := c.ValidateAndDefault(); err != nil {
+		return nil, errors.Wrap(err, "invalid OIDC configuration")
+	}
+	for _, gr := range c.GroupRoles {
+		if r := user.Role(gr.Role); !r.IsValid() || r == user.RoleNone || r == user.RoleSuperuser {
+			return nil, errors.Errorf("OIDC groups can't be given the role '%s'", gr.Role)
+		}
+	}
+
+	return &OIDCUserManager{
+		conf:       c,
+		sessionTTL: time.Duration(c.SessionHours) * time.Hour,
+	}, nil
+}
+
+// GetUserByToken returns the user whose session the token is, unless the
+// session has expired.
+func (m *OIDCUserManager) GetUserByToken(_ context.Context, token string) (gimlet.User, error) {
+	u, valid, err := user.GetLoginCache(token, m.sessionTTL)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding session")
+	}
+	if u == nil {
+		return nil, errors.New("invalid session")
+	}
+	if !valid {
+		return nil, errors.New("session has expired")
+	}
+	return u, nil
+}
+
+// CreateUserToken is not implemented in OIDCUserManager
+func (*OIDCUserManager) CreateUserToken(string, string) (string, error) {
+	return "", errors.New("OIDCUserManager does not create tokens via username/password")
+}
+
+// GetLoginHandler returns the function that starts logging in by redirecting
+// the user to authenticate with the identity provider.
+func (m *OIDCUserManager) GetLoginHandler(callbackUri string) http.HandlerFunc {
+	m.redirectURL = fmt.Sprintf("%s/login/redirect/callback", strings.TrimSuffix(callbackUri, "/"))
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		discovery, err := m.getDiscovery(r.Context())
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "problem discovering OIDC provider",
+				"issuer":  m.conf.Issuer,
+			}))
+			http.Error(w, "identity provider is unavailable", http.StatusBadGateway)
+			return
+		}
+
+		state := util.RandomString()
+		nonce := util.RandomString()
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    encodeOIDCState(state, nonce, safeRedirect(r.FormValue("redirect"))),
+			HttpOnly: true,
+			Secure:   strings.HasPrefix(m.redirectURL, "https://"),
+			Path:     "/login",
+			Expires:  time.Now().Add(oidcRequestTTL),
+		})
+		authURL := m.oauthConfig(discovery).AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce))
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+// GetLoginCallbackHandler returns the function that is called when the
+// identity provider redirects the user back to Evergreen.
+func (m *OIDCUserManager) GetLoginCallbackHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(oidcStateCookie)
+		if err != nil {
+			http.Error(w, "no login is in progress", http.StatusBadRequest)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Value: "", MaxAge: -1, Path: "/login"})
+		state, nonce, redirect, err := decodeOIDCState(cookie.Value)
+		if err != nil || r.FormValue("state") != state {
+			http.Error(w, "login state doesn't match", http.StatusBadRequest)
+			return
+		}
+		if providerErr := r.FormValue("error"); providerErr != "" {
+			grip.Warning(message.Fields{
+				"message":     "OIDC provider refused login",
+				"error":       providerErr,
+				"description": r.FormValue("error_description"),
+			})
+			http.Error(w, "login was refused by the identity provider", http.StatusUnauthorized)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+		defer cancel()
+
+		u, err := m.authenticate(ctx, r.FormValue("code"), nonce)
+		if err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "problem logging in with OIDC",
+				"issuer":  m.conf.Issuer,
+			}))
+			http.Error(w, "could not log in", http.StatusUnauthorized)
+			return
+		}
+		token, err := user.PutLoginCache(u)
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "problem starting session",
+				"user":    u.Username(),
+			}))
+			http.Error(w, "could not start session", http.StatusInternalServerError)
+			return
+		}
+		setLoginToken(token, w)
+		http.Redirect(w, r, redirect, http.StatusFound)
+	}
+}
+
+func (*OIDCUserManager) IsRedirect() bool                           { return true }
+func (*OIDCUserManager) GetUserByID(id string) (gimlet.User, error) { return getUserByID(id) }
+func (*OIDCUserManager) GetOrCreateUser(u gimlet.User) (gimlet.User, error) {
+	return getOrCreateUser(u)
+}
+
+// authenticate exchanges the code for an ID token, and returns the user it
+// identifies, with its roles updated from its groups.
+func (m *OIDCUserManager) authenticate(ctx context.Context, code, nonce string) (*user.DBUser, error) {
+	if code == "" {
+		return nil, errors.New("no code was given")
+	}
+	discovery, err := m.getDiscovery(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+	token, err := m.oauthConfig(discovery).Exchange(context.WithValue(ctx, oauth2.HTTPClient, client), code)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem exchanging code")
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("provider didn't return an ID token")
+	}
+	claims, err := m.verifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ID token")
+	}
+	identity, err := m.identityFromClaims(claims)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	u, err := model.GetOrCreateOIDCUser(identity.username, identity.subject, identity.displayName, identity.email)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = u.SetSystemRoles(identity.roles); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return u, nil
+}
+
+type oidcIdentity struct {
+	username    string
+	subject     string
+	displayName string
+	email       string
+	roles       []string
+}
+
+// identityFromClaims returns the user that the ID token's claims identify,
+// along with the project roles that its groups map to, or an error if the
+// user isn't in any of the allowed groups.
+func (m *OIDCUserManager) identityFromClaims(claims map[string]interface{}) (*oidcIdentity, error) {
+	username, _ := claims[m.conf.UsernameClaim].(string)
+	if username == "" {
+		return nil, errors.Errorf("ID token has no '%s' claim", m.conf.UsernameClaim)
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, errors.New("ID token has no 'sub' claim")
+	}
+	identity := &oidcIdentity{username: username, subject: subject, roles: []string{}}
+	identity.displayName, _ = claims["name"].(string)
+	identity.email, _ = claims["email"].(string)
+
+	groups := []string{}
+	switch v := claims[m.conf.GroupsClaim].(type) {
+	case string:
+		groups = append(groups, v)
+	case []interface{}:
+		for _, group := range v {
+			if name, ok := group.(string); ok {
+				groups = append(groups, name)
+			}
+		}
+	}
+
+	if len(m.conf.AllowedGroups) > 0 {
+		allowed := false
+		for _, group := range groups {
+			allowed = allowed || util.StringSliceContains(m.conf.AllowedGroups, group)
+		}
+		if !allowed {
+			return nil, errors.Errorf("user '%s' isn't in any of the allowed groups", username)
+		}
+	}
+
+	for _, gr := range m.conf.GroupRoles {
+		if !util.StringSliceContains(groups, gr.Group) {
+			continue
+		}
+		if len(gr.Projects) == 0 {
+			identity.roles = append(identity.roles, user.ProjectRoleGrant(user.Role(gr.Role), ""))
+		}
+		for _, project := range gr.Projects {
+			identity.roles = append(identity.roles, user.ProjectRoleGrant(user.Role(gr.Role), project))
+		}
+	}
+
+	return identity, nil
+}
+
+// verifyIDToken checks the ID token's signature, issuer, audience, expiry
+// and nonce, and returns its claims.
+func (m *OIDCUserManager) verifyIDToken(ctx context.Context, rawIDToken, nonce string) (map[string]interface{}, error) {
+	parts := strings.Split(rawIDToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+	header := struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}{}
+	if err := decodeTokenPart(parts[0], &header); err != nil {
+		return nil, errors.Wrap(err, "malformed token header")
+	}
+	if header.Alg != "RS256" {
+		return nil, errors.Errorf("unsupported signing algorithm '%s'", header.Alg)
+	}
+	key, err := m.getKey(ctx, header.Kid)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = jws.Verify(rawIDToken, key); err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+
+	claims := map[string]interface{}{}
+	if err = decodeTokenPart(parts[1], &claims); err != nil {
+		return nil, errors.Wrap(err, "malformed token claims")
+	}
+	discovery, err := m.getDiscovery(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if iss, _ := claims["iss"].(string); iss != discovery.Issuer {
+		return nil, errors.Errorf("token was issued by '%s'", iss)
+	}
+	audiences := []string{}
+	switch aud := claims["aud"].(type) {
+	case string:
+		audiences = append(audiences, aud)
+	case []interface{}:
+		for _, a := range aud {
+			if s, ok := a.(string); ok {
+				audiences = append(audiences, s)
+			}
+		}
+	}
+	if !util.StringSliceContains(audiences, m.conf.ClientId) {
+		return nil, errors.New("token wasn't issued to Evergreen")
+	}
+	if exp, _ := claims["exp"].(float64); time.Unix(int64(exp), 0).Before(time.Now()) {
+		return nil, errors.New("token has expired")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("token nonce doesn't match")
+	}
+
+	return claims, nil
+}
+
+func (m *OIDCUserManager) oauthConfig(discovery *oidcDiscovery) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     m.conf.ClientId,
+		ClientSecret: m.conf.ClientSecret,
+		Scopes:       m.conf.Scopes,
+		RedirectURL:  m.redirectURL,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}
+}
+
+// getDiscovery returns the provider's discovery document, fetching it the
+// first time.
+func (m *OIDCUserManager) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.discovery != nil {
+		return m.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(m.conf.Issuer, "/")
+	discovery := &oidcDiscovery{}
+	if err := getJSON(ctx, issuer+"/.well-known/openid-configuration", discovery); err != nil {
+		return nil, errors.Wrap(err, "problem fetching discovery document")
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, errors.Errorf("discovery document is for issuer '%s'", discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("discovery document is missing endpoints")
+	}
+	m.discovery = discovery
+	return discovery, nil
+}
+
+// getKey returns the provider's signing key with the ID, fetching the
+// provider's keys again if it's unknown, since providers rotate them.
+func (m *OIDCUserManager) getKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	m.mu.Lock()
+	key, ok := m.keys[kid]
+	m.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	discovery, err := m.getDiscovery(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	keySet := &oidcKeySet{}
+	if err = getJSON(ctx, discovery.JWKSURI, keySet); err != nil {
+		return nil, errors.Wrap(err, "problem fetching signing keys")
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range keySet.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, errors.Errorf("unknown signing key '%s'", kid)
+	}
+	return key, nil
+}
+
+func getJSON(ctx context.Context, endpoint string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("'%s' returned status %d", endpoint, resp.StatusCode)
+	}
+	return errors.WithStack(json.NewDecoder(resp.Body).Decode(out))
+}
+
+func decodeTokenPart(part string, out interface{}) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(json.Unmarshal(decoded, out))
+}
+
+func encodeOIDCState(state, nonce, redirect string) string {
+	vals := url.Values{}
+	vals.Set("state", state)
+	vals.Set("nonce", nonce)
+	vals.Set("redirect", redirect)
+	return base64.RawURLEncoding.EncodeToString([]byte(vals.Encode()))
+}
+
+func decodeOIDCState(cookie string) (string, string, string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cookie)
+	if err != nil {
+		return "", "", "", errors.WithStack(err)
+	}
+	vals, err := url.ParseQuery(string(decoded))
+	if err != nil {
+		return "", "", "", errors.WithStack(err)
+	}
+	if vals.Get("state") == "" || vals.Get("nonce") == "" {
+		return "", "", "", errors.New("incomplete login state")
+	}
+	return vals.Get("state"), vals.Get("nonce"), safeRedirect(vals.Get("redirect")), nil
+}
+
+// safeRedirect returns the redirect if it's a path on Evergreen, so that
+// logging in can't redirect users to other sites, or the home page if not.
+func safeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		return "/"
+	}
+	return redirect
+}
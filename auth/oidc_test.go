@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestOIDCUserManager(t *testing.T) {
+	assert := assert.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			assert.NoError(json.NewEncoder(w).Encode(oidcDiscovery{
+				Issuer:                server.URL,
+				AuthorizationEndpoint: server.URL + "/authorize",
+				TokenEndpoint:         server.URL + "/token",
+				JWKSURI:               server.URL + "/keys",
+			}))
+		case "/keys":
+			assert.NoError(json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []map[string]string{{
+					"kty": "RSA",
+					"kid": "key1",
+					"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				}},
+			}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err = NewOIDCUserManager(&evergreen.OIDCConfig{Issuer: server.URL, ClientId: "evergreen"})
+	assert.Error(err)
+	_, err = NewOIDCUserManager(&evergreen.OIDCConfig{
+		Issuer:       server.URL,
+		ClientId:     "evergreen",
+		ClientSecret: "secret",
+		GroupRoles:   []evergreen.OIDCGroupRole{{Group: "admins", Role: "superuser"}},
+	})
+	assert.Error(err)
+
+	um, err := LoadUserManager(evergreen.AuthConfig{OIDC: &evergreen.OIDCConfig{
+		Issuer:        server.URL,
+		ClientId:      "evergreen",
+		ClientSecret:  "secret",
+		AllowedGroups: []string{"engineering"},
+		GroupRoles: []evergreen.OIDCGroupRole{
+			{Group: "engineering", Role: "viewer"},
+			{Group: "release", Role: "project_admin", Projects: []string{"mci", "sys-perf"}},
+		},
+	}})
+	require.NoError(t, err)
+	m, ok := um.(*OIDCUserManager)
+	require.True(t, ok)
+	assert.True(m.IsRedirect())
+	assert.Equal(24*time.Hour, m.sessionTTL)
+	assert.Equal("sub", m.conf.UsernameClaim)
+
+	t.Run("LoginRedirectsToProvider", func(t *testing.T) {
+		handler := m.GetLoginHandler("https://evergreen.example.com/")
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/login/redirect?redirect=%2Fwaterfall%2Fmci", nil))
+		require.Equal(t, http.StatusFound, rec.Code)
+
+		loc, err := url.Parse(rec.Header().Get("Location"))
+		require.NoError(t, err)
+		assert.Equal(server.URL+"/authorize", loc.Scheme+"://"+loc.Host+loc.Path)
+		assert.Equal("evergreen", loc.Query().Get("client_id"))
+		assert.Equal("https://evergreen.example.com/login/redirect/callback", loc.Query().Get("redirect_uri"))
+		assert.Equal("openid profile email", loc.Query().Get("scope"))
+
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		state, nonce, redirect, err := decodeOIDCState(cookies[0].Value)
+		require.NoError(t, err)
+		assert.Equal(loc.Query().Get("state"), state)
+		assert.Equal(loc.Query().Get("nonce"), nonce)
+		assert.Equal("/waterfall/mci", redirect)
+		assert.True(cookies[0].Secure)
+		assert.True(cookies[0].HttpOnly)
+	})
+	t.Run("CallbackRejectsMismatchedState", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/login/redirect/callback?state=other&code=abc", nil)
+		req.AddCookie(&http.Cookie{Name: oidcStateCookie, Value: encodeOIDCState("state", "nonce", "/")})
+		m.GetLoginCallbackHandler()(rec, req)
+		assert.Equal(http.StatusBadRequest, rec.Code)
+
+		rec = httptest.NewRecorder()
+		m.GetLoginCallbackHandler()(rec, httptest.NewRequest(http.MethodGet, "/login/redirect/callback?state=state&code=abc", nil))
+		assert.Equal(http.StatusBadRequest, rec.Code)
+	})
+	t.Run("VerifyIDToken", func(t *testing.T) {
+		ctx := context.Background()
+		claims := func() map[string]interface{} {
+			return map[string]interface{}{
+				"iss":                server.URL,
+				"aud":                []string{"evergreen", "other"},
+				"exp":                time.Now().Add(time.Hour).Unix(),
+				"nonce":              "nonce",
+				"preferred_username": "annie",
+			}
+		}
+
+		verified, err := m.verifyIDToken(ctx, signTestToken(t, key, "key1", claims()), "nonce")
+		require.NoError(t, err)
+		assert.Equal("annie", verified["preferred_username"])
+
+		_, err = m.verifyIDToken(ctx, signTestToken(t, key, "key1", claims()), "other")
+		assert.Error(err)
+		_, err = m.verifyIDToken(ctx, signTestToken(t, key, "key2", claims()), "nonce")
+		assert.Error(err)
+
+		c := claims()
+		c["aud"] = "other"
+		_, err = m.verifyIDToken(ctx, signTestToken(t, key, "key1", c), "nonce")
+		assert.Error(err)
+		c = claims()
+		c["iss"] = "https://attacker.example.com"
+		_, err = m.verifyIDToken(ctx, signTestToken(t, key, "key1", c), "nonce")
+		assert.Error(err)
+		c = claims()
+		c["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err = m.verifyIDToken(ctx, signTestToken(t, key, "key1", c), "nonce")
+		assert.Error(err)
+
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		_, err = m.verifyIDToken(ctx, signTestToken(t, other, "key1", claims()), "nonce")
+		assert.Error(err)
+		_, err = m.verifyIDToken(ctx, "not.a-token", "nonce")
+		assert.Error(err)
+	})
+	t.Run("IdentityFromClaims", func(t *testing.T) {
+		identity, err := m.identityFromClaims(map[string]interface{}{
+			"sub":                "annie",
+			"preferred_username": "root",
+			"name":               "Annie Admin",
+			"email":              "annie@example.com",
+			"groups":             []interface{}{"engineering", "release"},
+		})
+		require.NoError(t, err)
+		assert.Equal("annie", identity.username)
+		assert.Equal("annie", identity.subject)
+		assert.Equal("Annie Admin", identity.displayName)
+		assert.Equal("annie@example.com", identity.email)
+		assert.Equal([]string{"viewer:*", "project_admin:mci", "project_admin:sys-perf"}, identity.roles)
+
+		u := &user.DBUser{Id: identity.username, SystemRoles: identity.roles}
+		assert.Equal(user.RoleProjectAdmin, u.ProjectRole("mci"))
+		assert.Equal(user.RoleViewer, u.ProjectRole("other"))
+
+		_, err = m.identityFromClaims(map[string]interface{}{"sub": "bob", "groups": "sales"})
+		assert.Error(err)
+		_, err = m.identityFromClaims(map[string]interface{}{"groups": []interface{}{"engineering"}})
+		assert.Error(err)
+	})
+}
+
+func TestSafeRedirect(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("/waterfall/mci", safeRedirect("/waterfall/mci"))
+	assert.Equal("/", safeRedirect(""))
+	assert.Equal("/", safeRedirect("https://attacker.example.com"))
+	assert.Equal("/", safeRedirect("//attacker.example.com"))
+	assert.Equal("/", safeRedirect("/\\attacker.example.com"))
+}
@@ -0,0 +1,291 @@
+// +build go1.7
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// azurePriceTTL is how long the hourly price of a VM size is cached.
+const azurePriceTTL = 24 * time.Hour
+
+// azureManager implements the Manager interface for Microsoft Azure.
+type azureManager struct {
+	client azureClient
+}
+
+// AzureSettings specifies the settings used to configure a host instance.
+type AzureSettings struct {
+	ResourceGroup string `mapstructure:"resource_group"`
+	Location      string `mapstructure:"location"`
+	VMSize        string `mapstructure:"vm_size"`
+
+	// ImageGallery and ImageDefinition identify the image in a shared
+	// image gallery to create VMs from. If ImageVersion is blank, the
+	// latest version of the image is used. GalleryResourceGroup defaults
+	// to ResourceGroup.
+	ImageGallery         string `mapstructure:"image_gallery"`
+	ImageDefinition      string `mapstructure:"image_definition"`
+	ImageVersion         string `mapstructure:"image_version"`
+	GalleryResourceGroup string `mapstructure:"gallery_resource_group"`
+
+	// SubnetID is the resource ID of the virtual network subnet that VMs
+	// are attached to.
+	SubnetID string `mapstructure:"subnet_id"`
+
+	DiskType   string `mapstructure:"disk_type"`
+	DiskSizeGB int64  `mapstructure:"disk_size_gb"`
+
+	// AdminUsername defaults to the distro's user.
+	AdminUsername string `mapstructure:"admin_username"`
+	SSHPublicKey  string `mapstructure:"ssh_public_key"`
+}
+
+// Validate verifies a set of AzureSettings.
+func (opts *AzureSettings) Validate() error {
+	if opts.ResourceGroup == "" {
+		return errors.New("Resource group must not be blank")
+	}
+
+	if opts.Location == "" {
+		return errors.New("Location must not be blank")
+	}
+
+	if opts.VMSize == "" {
+		return errors.New("VM size must not be blank")
+	}
+
+	if opts.ImageGallery == "" || opts.ImageDefinition == "" {
+		return errors.New("Image gallery and image definition must not be blank")
+	}
+
+	if opts.SubnetID == "" {
+		return errors.New("Subnet ID must not be blank")
+	}
+
+	if opts.SSHPublicKey == "" {
+		return errors.New("SSH public key must not be blank")
+	}
+
+	if opts.DiskSizeGB < 0 {
+		return errors.New("Disk size must be non-negative")
+	}
+
+	return nil
+}
+
+// GetSettings returns an empty AzureSettings struct since settings are
+// configured on instance creation.
+func (m *azureManager) GetSettings() ProviderSettings {
+	return &AzureSettings{}
+}
+
+// Configure loads the necessary credentials from the global config object.
+func (m *azureManager) Configure(ctx context.Context, s *evergreen.Settings) error {
+	config := s.Providers.Azure
+
+	if m.client == nil {
+		m.client = &azureClientImpl{}
+	}
+
+	if err := m.client.Init(ctx, &config); err != nil {
+		return errors.Wrap(err, "Failed to initialize client connection")
+	}
+
+	return nil
+}
+
+// SpawnHost attempts to create a new host by requesting one from the Azure API.
+// Information about the intended (and eventually created) host is recorded in a DB document.
+//
+// ProviderSettings in the distro should have the following settings:
+//     - ResourceGroup: resource group that VMs are created in
+//     - Location:      region that VMs are created in i.e. eastus
+//     - VMSize:        VM size i.e. Standard_D2s_v3
+//
+//     - ImageGallery:         shared image gallery containing the image
+//     - ImageDefinition:      image definition in the gallery
+//     - ImageVersion:         (optional) image version, defaults to the latest
+//     - GalleryResourceGroup: (optional) resource group of the gallery
+//
+//     - SubnetID:      resource ID of the subnet VMs are attached to
+//     - DiskType:      (optional) OS disk storage type i.e. Premium_LRS
+//     - DiskSizeGB:    (optional) OS disk size, in GB
+//
+//     - AdminUsername: (optional) admin user, defaults to the distro user
+//     - SSHPublicKey:  public key authorized for the admin user
+func (m *azureManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, error) {
+	if h.Distro.Provider != evergreen.ProviderNameAzure {
+		return nil, errors.Errorf("Can't spawn instance of %s for distro %s: provider is %s",
+			evergreen.ProviderNameAzure, h.Distro.Id, h.Distro.Provider)
+	}
+
+	s := &AzureSettings{}
+	if h.Distro.ProviderSettings != nil {
+		if err := mapstructure.Decode(h.Distro.ProviderSettings, s); err != nil {
+			return nil, errors.Wrapf(err, "Error decoding params for distro %s", h.Distro.Id)
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "Invalid settings in distro %s", h.Distro.Id)
+	}
+
+	// Record where the VM is so that later API calls can find it. The
+	// document is updated later in hostinit, rather than here.
+	h.Project = s.ResourceGroup
+	h.Zone = s.Location
+	h.InstanceType = s.VMSize
+
+	// Start the instance, and remove the intent host document if unsuccessful.
+	if _, err := m.client.CreateInstance(ctx, h, s); err != nil {
+		if rmErr := h.Remove(); rmErr != nil {
+			grip.Errorf("Could not remove intent host '%s': %+v", h.Id, rmErr)
+		}
+		grip.Error(err)
+		return nil, errors.Wrapf(err, "Could not start new instance for distro '%s'", h.Distro.Id)
+	}
+
+	event.LogHostStarted(h.Id)
+	grip.Debug(message.Fields{"message": "new azure host", "instance": h.Id, "object": h})
+	return h, nil
+}
+
+// GetInstanceStatus gets the current operational status of the provisioned host,
+func (m *azureManager) GetInstanceStatus(ctx context.Context, host *host.Host) (CloudStatus, error) {
+	view, err := m.client.GetInstanceView(ctx, host)
+	if err != nil {
+		return StatusUnknown, errors.Wrapf(err, "client failed to get instance view for host %s", host.Id)
+	}
+
+	return azureToEvgStatus(view), nil
+}
+
+// TerminateInstance requests a server previously provisioned to be removed.
+func (m *azureManager) TerminateInstance(ctx context.Context, host *host.Host, user string) error {
+	if host.Status == evergreen.HostTerminated {
+		err := errors.Errorf("Can not terminate %s - already marked as terminated!", host.Id)
+		grip.Error(err)
+		return err
+	}
+
+	if err := m.client.DeleteInstance(ctx, host); err != nil {
+		return errors.Wrapf(err, "API call to delete instance %s failed", host.Id)
+	}
+
+	// Set the host status as terminated and update its termination time
+	return host.Terminate(user)
+}
+
+// IsUp checks whether the provisioned host is running.
+func (m *azureManager) IsUp(ctx context.Context, host *host.Host) (bool, error) {
+	status, err := m.GetInstanceStatus(ctx, host)
+	if err != nil {
+		return false, err
+	}
+
+	return status == StatusRunning, nil
+}
+
+// OnUp does nothing since tags are attached in SpawnHost.
+func (m *azureManager) OnUp(context.Context, *host.Host) error {
+	return nil
+}
+
+// GetDNSName returns the public IPv4 address of the host.
+func (m *azureManager) GetDNSName(ctx context.Context, h *host.Host) (string, error) {
+	ip, err := m.client.GetPublicIP(ctx, h)
+	if err != nil {
+		return "", errors.Wrapf(err, "client failed to get IP for host %s", h.Id)
+	}
+
+	return ip, nil
+}
+
+// GetSSHOptions generates the command line args to be passed to SSH to allow connection
+// to the machine.
+func (m *azureManager) GetSSHOptions(host *host.Host, keyPath string) ([]string, error) {
+	if keyPath == "" {
+		return []string{}, errors.Errorf("No key specified for host %s", host.Id)
+	}
+
+	opts := []string{"-i", keyPath}
+	for _, opt := range host.Distro.SSHOptions {
+		opts = append(opts, "-o", opt)
+	}
+
+	return opts, nil
+}
+
+// TimeTilNextPayment returns zero since Azure bills VMs per second.
+func (m *azureManager) TimeTilNextPayment(host *host.Host) time.Duration {
+	return time.Duration(0)
+}
+
+// CostForDuration estimates the cost for a span of time on the given host
+// from the pay-as-you-go price of its VM size. It does not account for
+// storage, networking, or reserved instance discounts.
+func (m *azureManager) CostForDuration(ctx context.Context, h *host.Host, start, end time.Time, s *evergreen.Settings) (float64, error) {
+	if end.Before(start) || util.IsZeroTime(start) || util.IsZeroTime(end) {
+		return 0, errors.New("task timing data is malformed")
+	}
+
+	price, err := azurePrices.get(ctx, m.client, h.Zone, h.InstanceType)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error getting price of '%s' in '%s'", h.InstanceType, h.Zone)
+	}
+
+	cost := price * end.Sub(start).Hours()
+	if cost < 0 {
+		return 0, errors.Errorf("cost appears to be less than 0 (%g) which is impossible", cost)
+	}
+
+	return cost, nil
+}
+
+type azurePrice struct {
+	hourly    float64
+	fetchedAt time.Time
+}
+
+// azurePriceCache caches the hourly prices of VM sizes by location.
+type azurePriceCache struct {
+	prices map[string]azurePrice
+	mu     sync.Mutex
+}
+
+var azurePrices = &azurePriceCache{prices: map[string]azurePrice{}}
+
+func (c *azurePriceCache) get(ctx context.Context, client azureClient, location, vmSize string) (float64, error) {
+	key := fmt.Sprintf("%s/%s", location, vmSize)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.prices[key]; ok && time.Since(p.fetchedAt) < azurePriceTTL {
+		return p.hourly, nil
+	}
+
+	retailPrices, err := client.GetRetailPrices(ctx, location, vmSize)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	hourly, err := azureHourlyPrice(retailPrices)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	c.prices[key] = azurePrice{hourly: hourly, fetchedAt: time.Now()}
+
+	return hourly, nil
+}
@@ -0,0 +1,214 @@
+// +build go1.7
+
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	azureManagementEndpoint = "https://management.azure.com"
+	azureManagementScope    = "https://management.azure.com/.default"
+	azureTokenURLTemplate   = "https://login.microsoftonline.com/%s/oauth2/v2.0/token"
+	azureRetailPricesURL    = "https://prices.azure.com/api/retail/prices"
+
+	azureComputeAPIVersion = "2022-03-01"
+	azureNetworkAPIVersion = "2021-05-01"
+)
+
+// The azureClient interface wraps interaction with the Azure Resource Manager
+// and Retail Prices APIs.
+type azureClient interface {
+	Init(context.Context, *evergreen.AzureConfig) error
+	CreateInstance(context.Context, *host.Host, *AzureSettings) (string, error)
+	GetInstanceView(context.Context, *host.Host) (*azureInstanceView, error)
+	GetPublicIP(context.Context, *host.Host) (string, error)
+	DeleteInstance(context.Context, *host.Host) error
+	GetRetailPrices(ctx context.Context, location, vmSize string) ([]azureRetailPrice, error)
+}
+
+type azureClientImpl struct {
+	client         *http.Client
+	subscriptionID string
+}
+
+// Init creates an HTTP client authenticated as the configured service
+// principal.
+func (c *azureClientImpl) Init(ctx context.Context, config *evergreen.AzureConfig) error {
+	if config.TenantID == "" || config.ClientID == "" || config.ClientSecret == "" {
+		return errors.New("Azure service principal credentials must not be blank")
+	}
+	if config.SubscriptionID == "" {
+		return errors.New("Azure subscription ID must not be blank")
+	}
+
+	credentials := &clientcredentials.Config{
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		TokenURL:     fmt.Sprintf(azureTokenURLTemplate, config.TenantID),
+		Scopes:       []string{azureManagementScope},
+	}
+	c.client = credentials.Client(ctx)
+	c.subscriptionID = config.SubscriptionID
+	grip.Debug("created an OAuth HTTP client to Azure services")
+
+	return nil
+}
+
+// CreateInstance requests a VM to be provisioned. API calls refer to the VM
+// by its name, which is the host's ID.
+func (c *azureClientImpl) CreateInstance(ctx context.Context, h *host.Host, s *AzureSettings) (string, error) {
+	vm := makeAzureVirtualMachine(h, c.subscriptionID, s)
+
+	grip.Debug(message.Fields{
+		"message":        "creating instance",
+		"host":           h.Id,
+		"resource_group": s.ResourceGroup,
+		"location":       s.Location,
+		"vm_size":        s.VMSize,
+		"image":          vm.Properties.StorageProfile.ImageReference.ID,
+	})
+
+	if err := c.do(ctx, http.MethodPut, c.vmURL(h, ""), vm, nil); err != nil {
+		return "", errors.Wrap(err, "API call to create instance failed")
+	}
+
+	return h.Id, nil
+}
+
+// GetInstanceView requests the runtime status of a VM.
+func (c *azureClientImpl) GetInstanceView(ctx context.Context, h *host.Host) (*azureInstanceView, error) {
+	view := &azureInstanceView{}
+	if err := c.do(ctx, http.MethodGet, c.vmURL(h, "/instanceView"), nil, view); err != nil {
+		return nil, errors.Wrap(err, "API call to get instance view failed")
+	}
+
+	return view, nil
+}
+
+// GetPublicIP returns the public IPv4 address of the VM's primary network
+// interface.
+func (c *azureClientImpl) GetPublicIP(ctx context.Context, h *host.Host) (string, error) {
+	vm := &azureVirtualMachine{}
+	if err := c.do(ctx, http.MethodGet, c.vmURL(h, ""), nil, vm); err != nil {
+		return "", errors.Wrap(err, "API call to get instance failed")
+	}
+	if len(vm.Properties.NetworkProfile.NetworkInterfaces) == 0 {
+		return "", errors.New("instance is not attached to any network interface")
+	}
+
+	nic := &azureNetworkInterface{}
+	nicID := vm.Properties.NetworkProfile.NetworkInterfaces[0].ID
+	if err := c.do(ctx, http.MethodGet, c.resourceURL(nicID, azureNetworkAPIVersion), nil, nic); err != nil {
+		return "", errors.Wrap(err, "API call to get network interface failed")
+	}
+	if len(nic.Properties.IPConfigurations) == 0 || nic.Properties.IPConfigurations[0].Properties.PublicIPAddress == nil {
+		return "", errors.New("network interface does not have a public IP")
+	}
+
+	ip := &azurePublicIPAddress{}
+	ipID := nic.Properties.IPConfigurations[0].Properties.PublicIPAddress.ID
+	if err := c.do(ctx, http.MethodGet, c.resourceURL(ipID, azureNetworkAPIVersion), nil, ip); err != nil {
+		return "", errors.Wrap(err, "API call to get public IP failed")
+	}
+	if ip.Properties.IPAddress == "" {
+		return "", errors.New("public IP has not been allocated yet")
+	}
+
+	return ip.Properties.IPAddress, nil
+}
+
+// DeleteInstance requests a VM previously provisioned to be removed. Its OS
+// disk, network interface, and public IP are deleted along with it.
+func (c *azureClientImpl) DeleteInstance(ctx context.Context, h *host.Host) error {
+	if err := c.do(ctx, http.MethodDelete, c.vmURL(h, ""), nil, nil); err != nil {
+		return errors.Wrap(err, "API call to delete instance failed")
+	}
+
+	return nil
+}
+
+// GetRetailPrices returns the retail prices of a VM size in a location. The
+// Retail Prices API is unauthenticated.
+func (c *azureClientImpl) GetRetailPrices(ctx context.Context, location, vmSize string) ([]azureRetailPrice, error) {
+	filter := fmt.Sprintf("serviceName eq 'Virtual Machines' and armRegionName eq '%s' and armSkuName eq '%s'", location, vmSize)
+	next := fmt.Sprintf("%s?$filter=%s", azureRetailPricesURL, url.QueryEscape(filter))
+
+	prices := []azureRetailPrice{}
+	for next != "" {
+		page := &azureRetailPrices{}
+		if err := doAzureRequest(ctx, http.DefaultClient, http.MethodGet, next, nil, page); err != nil {
+			return nil, errors.Wrap(err, "API call to get retail prices failed")
+		}
+		prices = append(prices, page.Items...)
+		next = page.NextPageLink
+	}
+
+	return prices, nil
+}
+
+func (c *azureClientImpl) vmURL(h *host.Host, suffix string) string {
+	return fmt.Sprintf("%s/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/virtualMachines/%s%s?api-version=%s",
+		azureManagementEndpoint, c.subscriptionID, h.Project, h.Id, suffix, azureComputeAPIVersion)
+}
+
+func (c *azureClientImpl) resourceURL(id, apiVersion string) string {
+	return fmt.Sprintf("%s%s?api-version=%s", azureManagementEndpoint, id, apiVersion)
+}
+
+func (c *azureClientImpl) do(ctx context.Context, method, url string, in, out interface{}) error {
+	return doAzureRequest(ctx, c.client, method, url, in, out)
+}
+
+// doAzureRequest sends the request, encoding in as the body if it is not nil,
+// and decodes the response body into out if it is not nil.
+func doAzureRequest(ctx context.Context, client *http.Client, method, url string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling request body")
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return errors.Wrap(err, "error creating request")
+	}
+	req = req.WithContext(ctx)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error sending %s request", method)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("%s request failed with status %d: %s", method, resp.StatusCode, string(msg))
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return errors.Wrap(json.NewDecoder(resp.Body).Decode(out), "error decoding response body")
+}
@@ -0,0 +1,100 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+)
+
+type azureClientMock struct {
+	// API call options
+	failInit   bool
+	failCreate bool
+	failGet    bool
+	failDelete bool
+	failPrices bool
+
+	// Other options
+	isActive bool
+
+	// Recorded arguments
+	settings      *AzureSettings
+	pricesFetched int
+}
+
+func (c *azureClientMock) Init(context.Context, *evergreen.AzureConfig) error {
+	if c.failInit {
+		return errors.New("failed to initialize client")
+	}
+
+	return nil
+}
+
+func (c *azureClientMock) CreateInstance(_ context.Context, h *host.Host, s *AzureSettings) (string, error) {
+	if c.failCreate {
+		return "", errors.New("failed to create instance")
+	}
+	c.settings = s
+
+	return h.Id, nil
+}
+
+func (c *azureClientMock) GetInstanceView(context.Context, *host.Host) (*azureInstanceView, error) {
+	if c.failGet {
+		return nil, errors.New("failed to get instance view")
+	}
+
+	view := &azureInstanceView{
+		Statuses: []azureInstanceViewStatus{
+			{Code: "ProvisioningState/succeeded"},
+			{Code: azurePowerStateDeallocated},
+		},
+	}
+	if c.isActive {
+		view.Statuses[1].Code = azurePowerStateRunning
+	}
+
+	return view, nil
+}
+
+func (c *azureClientMock) GetPublicIP(context.Context, *host.Host) (string, error) {
+	if c.failGet {
+		return "", errors.New("failed to get public IP")
+	}
+
+	return "0.0.0.0", nil
+}
+
+func (c *azureClientMock) DeleteInstance(context.Context, *host.Host) error {
+	if c.failDelete {
+		return errors.New("failed to delete instance")
+	}
+
+	return nil
+}
+
+func (c *azureClientMock) GetRetailPrices(context.Context, string, string) ([]azureRetailPrice, error) {
+	if c.failPrices {
+		return nil, errors.New("failed to get retail prices")
+	}
+	c.pricesFetched++
+
+	return []azureRetailPrice{
+		{
+			RetailPrice:   1.0,
+			UnitOfMeasure: "1 Hour",
+			ProductName:   "Virtual Machines DSv3 Series Windows",
+			SKUName:       "D2s v3",
+			Type:          "Consumption",
+		},
+		{
+			RetailPrice:   0.5,
+			UnitOfMeasure: "1 Hour",
+			ProductName:   "Virtual Machines DSv3 Series",
+			SKUName:       "D2s v3",
+			Type:          "Consumption",
+		},
+	}, nil
+}
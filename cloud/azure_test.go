@@ -0,0 +1,282 @@
+// +build go1.7
+
+package cloud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AzureSuite struct {
+	client   azureClient
+	manager  *azureManager
+	distro   distro.Distro
+	hostOpts HostOptions
+	suite.Suite
+}
+
+func TestAzureSuite(t *testing.T) {
+	suite.Run(t, new(AzureSuite))
+}
+
+func (s *AzureSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *AzureSuite) SetupTest() {
+	s.NoError(db.Clear(host.Collection))
+	s.client = &azureClientMock{
+		isActive: true,
+	}
+	s.manager = &azureManager{
+		client: s.client,
+	}
+	s.distro = distro.Distro{
+		Id:       "host",
+		Provider: evergreen.ProviderNameAzure,
+		ProviderSettings: &map[string]interface{}{
+			"resource_group":   "group",
+			"location":         "eastus",
+			"vm_size":          "Standard_D2s_v3",
+			"image_gallery":    "gallery",
+			"image_definition": "ubuntu",
+			"subnet_id":        "subnet",
+			"ssh_public_key":   "ssh-rsa key",
+		},
+	}
+	s.hostOpts = HostOptions{}
+}
+
+func (s *AzureSuite) TestValidateSettings() {
+	settings := &AzureSettings{
+		ResourceGroup:   "group",
+		Location:        "eastus",
+		VMSize:          "Standard_D2s_v3",
+		ImageGallery:    "gallery",
+		ImageDefinition: "ubuntu",
+		SubnetID:        "subnet",
+		SSHPublicKey:    "ssh-rsa key",
+	}
+	s.NoError(settings.Validate())
+
+	settings.ImageDefinition = ""
+	s.Error(settings.Validate())
+	settings.ImageDefinition = "ubuntu"
+
+	settings.VMSize = ""
+	s.Error(settings.Validate())
+	settings.VMSize = "Standard_D2s_v3"
+
+	settings.DiskSizeGB = -1
+	s.Error(settings.Validate())
+}
+
+func (s *AzureSuite) TestConfigureAPICall() {
+	mock, ok := s.client.(*azureClientMock)
+	s.True(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	settings := &evergreen.Settings{}
+	s.NoError(s.manager.Configure(ctx, settings))
+
+	mock.failInit = true
+	s.Error(s.manager.Configure(ctx, settings))
+}
+
+func (s *AzureSuite) TestIsUpStatuses() {
+	mock, ok := s.client.(*azureClientMock)
+	s.True(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := &host.Host{}
+	active, err := s.manager.IsUp(ctx, h)
+	s.NoError(err)
+	s.True(active)
+
+	mock.isActive = false
+	status, err := s.manager.GetInstanceStatus(ctx, h)
+	s.NoError(err)
+	s.Equal(StatusStopped, status)
+
+	mock.failGet = true
+	active, err = s.manager.IsUp(ctx, h)
+	s.Error(err)
+	s.False(active)
+}
+
+func (s *AzureSuite) TestSpawnAndTerminate() {
+	mock, ok := s.client.(*azureClientMock)
+	s.True(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewIntent(s.distro, s.distro.GenerateName(), s.distro.Provider, s.hostOpts)
+	h, err := s.manager.SpawnHost(ctx, h)
+	s.NoError(err)
+	s.Require().NotNil(h)
+	s.Equal("group", h.Project)
+	s.Equal("eastus", h.Zone)
+	s.Equal("Standard_D2s_v3", h.InstanceType)
+	s.Require().NotNil(mock.settings)
+	s.Equal("gallery", mock.settings.ImageGallery)
+	_, err = h.Upsert()
+	s.NoError(err)
+
+	mock.failDelete = true
+	s.Error(s.manager.TerminateInstance(ctx, h, evergreen.User))
+
+	mock.failDelete = false
+	s.NoError(s.manager.TerminateInstance(ctx, h, evergreen.User))
+	dbHost, err := host.FindOne(host.ById(h.Id))
+	s.NoError(err)
+	s.Require().NotNil(dbHost)
+	s.Equal(evergreen.HostTerminated, dbHost.Status)
+
+	s.Error(s.manager.TerminateInstance(ctx, h, evergreen.User))
+}
+
+func (s *AzureSuite) TestSpawnInvalidSettings() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dProviderName := distro.Distro{Provider: evergreen.ProviderNameGce}
+	h := NewIntent(dProviderName, dProviderName.GenerateName(), dProviderName.Provider, s.hostOpts)
+	h, err := s.manager.SpawnHost(ctx, h)
+	s.Error(err)
+	s.Nil(h)
+
+	dSettingsNone := distro.Distro{Provider: evergreen.ProviderNameAzure}
+	h = NewIntent(dSettingsNone, dSettingsNone.GenerateName(), dSettingsNone.Provider, s.hostOpts)
+	h, err = s.manager.SpawnHost(ctx, h)
+	s.Error(err)
+	s.Nil(h)
+}
+
+func (s *AzureSuite) TestGetDNSName() {
+	mock, ok := s.client.(*azureClientMock)
+	s.True(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := &host.Host{Id: "hostID"}
+	dns, err := s.manager.GetDNSName(ctx, h)
+	s.NoError(err)
+	s.Equal("0.0.0.0", dns)
+
+	mock.failGet = true
+	dns, err = s.manager.GetDNSName(ctx, h)
+	s.Error(err)
+	s.Empty(dns)
+}
+
+func (s *AzureSuite) TestCostForDuration() {
+	mock, ok := s.client.(*azureClientMock)
+	s.True(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	h := &host.Host{Zone: "westus", InstanceType: "Standard_D2s_v3"}
+
+	_, err := s.manager.CostForDuration(ctx, h, start, start.Add(-time.Hour), &evergreen.Settings{})
+	s.Error(err)
+
+	cost, err := s.manager.CostForDuration(ctx, h, start, start.Add(2*time.Hour), &evergreen.Settings{})
+	s.NoError(err)
+	s.InDelta(1.0, cost, 0.0001)
+
+	// the price is cached
+	_, err = s.manager.CostForDuration(ctx, h, start, start.Add(time.Hour), &evergreen.Settings{})
+	s.NoError(err)
+	s.Equal(1, mock.pricesFetched)
+}
+
+func TestAzureToEvgStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(StatusUnknown, azureToEvgStatus(nil))
+	assert.Equal(StatusInitializing, azureToEvgStatus(&azureInstanceView{
+		Statuses: []azureInstanceViewStatus{{Code: azureProvisioningStateCreating}},
+	}))
+	assert.Equal(StatusRunning, azureToEvgStatus(&azureInstanceView{
+		Statuses: []azureInstanceViewStatus{{Code: "ProvisioningState/succeeded"}, {Code: azurePowerStateRunning}},
+	}))
+	assert.Equal(StatusStopped, azureToEvgStatus(&azureInstanceView{
+		Statuses: []azureInstanceViewStatus{{Code: "ProvisioningState/succeeded"}, {Code: azurePowerStateDeallocated}},
+	}))
+	assert.Equal(StatusFailed, azureToEvgStatus(&azureInstanceView{
+		Statuses: []azureInstanceViewStatus{{Code: azureProvisioningStateFailed}, {Code: azurePowerStateRunning}},
+	}))
+	assert.Equal(StatusTerminated, azureToEvgStatus(&azureInstanceView{
+		Statuses: []azureInstanceViewStatus{{Code: azureProvisioningStateDeleting}, {Code: azurePowerStateStopping}},
+	}))
+}
+
+func TestMakeAzureVirtualMachine(t *testing.T) {
+	assert := assert.New(t)
+
+	h := &host.Host{
+		Id:     "evg-host",
+		Distro: distro.Distro{Id: "distro", User: "admin"},
+	}
+	s := &AzureSettings{
+		ResourceGroup:   "group",
+		Location:        "eastus",
+		VMSize:          "Standard_D2s_v3",
+		ImageGallery:    "gallery",
+		ImageDefinition: "ubuntu",
+		SubnetID:        "subnet",
+		SSHPublicKey:    "ssh-rsa key",
+		DiskType:        "Premium_LRS",
+	}
+
+	vm := makeAzureVirtualMachine(h, "sub", s)
+	assert.Equal("eastus", vm.Location)
+	assert.Equal("Standard_D2s_v3", vm.Properties.HardwareProfile.VMSize)
+	assert.Equal("/subscriptions/sub/resourceGroups/group/providers/Microsoft.Compute/galleries/gallery/images/ubuntu",
+		vm.Properties.StorageProfile.ImageReference.ID)
+	assert.Equal("Premium_LRS", vm.Properties.StorageProfile.OSDisk.ManagedDisk.StorageAccountType)
+	assert.Equal("admin", vm.Properties.OSProfile.AdminUsername)
+	assert.Equal("/home/admin/.ssh/authorized_keys", vm.Properties.OSProfile.LinuxConfiguration.SSH.PublicKeys[0].Path)
+	assert.Equal("subnet", vm.Properties.NetworkProfile.NetworkInterfaceConfigurations[0].Properties.IPConfigurations[0].Properties.Subnet.ID)
+
+	s.ImageVersion = "1.0.0"
+	s.GalleryResourceGroup = "images"
+	s.AdminUsername = "evg"
+	vm = makeAzureVirtualMachine(h, "sub", s)
+	assert.Equal("/subscriptions/sub/resourceGroups/images/providers/Microsoft.Compute/galleries/gallery/images/ubuntu/versions/1.0.0",
+		vm.Properties.StorageProfile.ImageReference.ID)
+	assert.Equal("evg", vm.Properties.OSProfile.AdminUsername)
+}
+
+func TestAzureHourlyPrice(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := azureHourlyPrice(nil)
+	assert.Error(err)
+
+	price, err := azureHourlyPrice([]azureRetailPrice{
+		{RetailPrice: 0.1, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series", SKUName: "D2s v3 Spot", Type: "Consumption"},
+		{RetailPrice: 100, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series", SKUName: "D2s v3", Type: "Reservation"},
+		{RetailPrice: 0.2, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series Windows", SKUName: "D2s v3", Type: "Consumption"},
+		{RetailPrice: 0.09, UnitOfMeasure: "1 Hour", ProductName: "Virtual Machines DSv3 Series", SKUName: "D2s v3", Type: "Consumption"},
+	})
+	assert.NoError(err)
+	assert.Equal(0.09, price)
+}
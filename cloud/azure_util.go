@@ -0,0 +1,329 @@
+// +build go1.7
+
+package cloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/pkg/errors"
+)
+
+const (
+	azurePowerStatePrefix        = "PowerState/"
+	azureProvisioningStatePrefix = "ProvisioningState/"
+
+	azurePowerStateStarting     = "PowerState/starting"
+	azurePowerStateRunning      = "PowerState/running"
+	azurePowerStateStopping     = "PowerState/stopping"
+	azurePowerStateStopped      = "PowerState/stopped"
+	azurePowerStateDeallocating = "PowerState/deallocating"
+	azurePowerStateDeallocated  = "PowerState/deallocated"
+
+	azureProvisioningStateCreating = "ProvisioningState/creating"
+	azureProvisioningStateFailed   = "ProvisioningState/failed"
+	azureProvisioningStateDeleting = "ProvisioningState/deleting"
+
+	// azureVMNetworkAPIVersion is the version of the network API used to
+	// create the network interface and public IP along with the VM.
+	azureVMNetworkAPIVersion = "2020-11-01"
+	// azureDeleteOptionDelete deletes a VM's disk, network interface, and
+	// public IP along with the VM.
+	azureDeleteOptionDelete = "Delete"
+)
+
+// The following types are the subset of the Azure Resource Manager
+// representation of a virtual machine that Evergreen reads and writes.
+// See https://docs.microsoft.com/rest/api/compute/virtual-machines
+
+type azureVirtualMachine struct {
+	ID         string                        `json:"id,omitempty"`
+	Name       string                        `json:"name,omitempty"`
+	Location   string                        `json:"location"`
+	Tags       map[string]string             `json:"tags,omitempty"`
+	Properties azureVirtualMachineProperties `json:"properties"`
+}
+
+type azureVirtualMachineProperties struct {
+	HardwareProfile   azureHardwareProfile `json:"hardwareProfile"`
+	StorageProfile    azureStorageProfile  `json:"storageProfile"`
+	OSProfile         *azureOSProfile      `json:"osProfile,omitempty"`
+	NetworkProfile    azureNetworkProfile  `json:"networkProfile"`
+	ProvisioningState string               `json:"provisioningState,omitempty"`
+}
+
+type azureHardwareProfile struct {
+	VMSize string `json:"vmSize"`
+}
+
+type azureStorageProfile struct {
+	ImageReference azureResourceReference `json:"imageReference"`
+	OSDisk         azureOSDisk            `json:"osDisk"`
+}
+
+type azureOSDisk struct {
+	CreateOption string            `json:"createOption"`
+	DeleteOption string            `json:"deleteOption,omitempty"`
+	DiskSizeGB   int64             `json:"diskSizeGB,omitempty"`
+	ManagedDisk  *azureManagedDisk `json:"managedDisk,omitempty"`
+}
+
+type azureManagedDisk struct {
+	StorageAccountType string `json:"storageAccountType"`
+}
+
+type azureOSProfile struct {
+	ComputerName       string                   `json:"computerName"`
+	AdminUsername      string                   `json:"adminUsername"`
+	LinuxConfiguration *azureLinuxConfiguration `json:"linuxConfiguration,omitempty"`
+}
+
+type azureLinuxConfiguration struct {
+	DisablePasswordAuthentication bool                  `json:"disablePasswordAuthentication"`
+	SSH                           azureSSHConfiguration `json:"ssh"`
+}
+
+type azureSSHConfiguration struct {
+	PublicKeys []azureSSHPublicKey `json:"publicKeys"`
+}
+
+type azureSSHPublicKey struct {
+	Path    string `json:"path"`
+	KeyData string `json:"keyData"`
+}
+
+type azureNetworkProfile struct {
+	NetworkAPIVersion              string                               `json:"networkApiVersion,omitempty"`
+	NetworkInterfaceConfigurations []azureNetworkInterfaceConfiguration `json:"networkInterfaceConfigurations,omitempty"`
+	NetworkInterfaces              []azureResourceReference             `json:"networkInterfaces,omitempty"`
+}
+
+type azureNetworkInterfaceConfiguration struct {
+	Name       string                                       `json:"name"`
+	Properties azureNetworkInterfaceConfigurationProperties `json:"properties"`
+}
+
+type azureNetworkInterfaceConfigurationProperties struct {
+	Primary          bool                   `json:"primary"`
+	DeleteOption     string                 `json:"deleteOption,omitempty"`
+	IPConfigurations []azureIPConfiguration `json:"ipConfigurations"`
+}
+
+type azureIPConfiguration struct {
+	Name       string                         `json:"name"`
+	Properties azureIPConfigurationProperties `json:"properties"`
+}
+
+type azureIPConfigurationProperties struct {
+	Subnet                       *azureResourceReference            `json:"subnet,omitempty"`
+	PublicIPAddressConfiguration *azurePublicIPAddressConfiguration `json:"publicIPAddressConfiguration,omitempty"`
+	PublicIPAddress              *azureResourceReference            `json:"publicIPAddress,omitempty"`
+	PrivateIPAddress             string                             `json:"privateIPAddress,omitempty"`
+}
+
+type azurePublicIPAddressConfiguration struct {
+	Name       string                                      `json:"name"`
+	Properties azurePublicIPAddressConfigurationProperties `json:"properties"`
+}
+
+type azurePublicIPAddressConfigurationProperties struct {
+	DeleteOption string `json:"deleteOption,omitempty"`
+}
+
+type azureResourceReference struct {
+	ID string `json:"id"`
+}
+
+type azureInstanceView struct {
+	Statuses []azureInstanceViewStatus `json:"statuses"`
+}
+
+type azureInstanceViewStatus struct {
+	Code string `json:"code"`
+}
+
+type azureNetworkInterface struct {
+	Properties struct {
+		IPConfigurations []azureIPConfiguration `json:"ipConfigurations"`
+	} `json:"properties"`
+}
+
+type azurePublicIPAddress struct {
+	Properties struct {
+		IPAddress string `json:"ipAddress"`
+	} `json:"properties"`
+}
+
+// azureRetailPrice is an item returned by the Azure Retail Prices API.
+// See https://docs.microsoft.com/rest/api/cost-management/retail-prices/azure-retail-prices
+type azureRetailPrice struct {
+	RetailPrice   float64 `json:"retailPrice"`
+	UnitOfMeasure string  `json:"unitOfMeasure"`
+	ProductName   string  `json:"productName"`
+	SKUName       string  `json:"skuName"`
+	Type          string  `json:"type"`
+}
+
+type azureRetailPrices struct {
+	Items        []azureRetailPrice `json:"Items"`
+	NextPageLink string             `json:"NextPageLink"`
+}
+
+// azureToEvgStatus returns the Evergreen status of a VM given the statuses in
+// its instance view. The power state is only reported once the VM has been
+// provisioned, so the provisioning state is used until then, as well as when
+// provisioning failed or the VM is being deleted.
+func azureToEvgStatus(view *azureInstanceView) CloudStatus {
+	if view == nil {
+		return StatusUnknown
+	}
+
+	var powerState, provisioningState string
+	for _, status := range view.Statuses {
+		switch {
+		case strings.HasPrefix(status.Code, azurePowerStatePrefix):
+			powerState = status.Code
+		case strings.HasPrefix(status.Code, azureProvisioningStatePrefix):
+			provisioningState = status.Code
+		}
+	}
+
+	switch provisioningState {
+	case azureProvisioningStateFailed:
+		return StatusFailed
+	case azureProvisioningStateDeleting:
+		return StatusTerminated
+	}
+
+	switch powerState {
+	case azurePowerStateStarting:
+		return StatusInitializing
+	case azurePowerStateRunning:
+		return StatusRunning
+	case azurePowerStateStopping, azurePowerStateStopped, azurePowerStateDeallocating, azurePowerStateDeallocated:
+		return StatusStopped
+	}
+
+	if provisioningState == azureProvisioningStateCreating {
+		return StatusInitializing
+	}
+
+	return StatusUnknown
+}
+
+// makeAzureImageID returns the resource ID of an image in a shared image
+// gallery. If no version is given, the latest version of the image is used.
+func makeAzureImageID(subscriptionID string, s *AzureSettings) string {
+	resourceGroup := s.GalleryResourceGroup
+	if resourceGroup == "" {
+		resourceGroup = s.ResourceGroup
+	}
+
+	id := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Compute/galleries/%s/images/%s",
+		subscriptionID, resourceGroup, s.ImageGallery, s.ImageDefinition)
+	if s.ImageVersion != "" {
+		id = fmt.Sprintf("%s/versions/%s", id, s.ImageVersion)
+	}
+
+	return id
+}
+
+// makeAzureVirtualMachine returns the VM to create for the host. The VM's
+// network interface and public IP are created along with it, and are deleted
+// along with it as well as its OS disk.
+func makeAzureVirtualMachine(h *host.Host, subscriptionID string, s *AzureSettings) *azureVirtualMachine {
+	osDisk := azureOSDisk{
+		CreateOption: "FromImage",
+		DeleteOption: azureDeleteOptionDelete,
+		DiskSizeGB:   s.DiskSizeGB,
+	}
+	if s.DiskType != "" {
+		osDisk.ManagedDisk = &azureManagedDisk{StorageAccountType: s.DiskType}
+	}
+
+	adminUsername := s.AdminUsername
+	if adminUsername == "" {
+		adminUsername = h.Distro.User
+	}
+
+	return &azureVirtualMachine{
+		Location: s.Location,
+		Tags: map[string]string{
+			"evergreen-host":   h.Id,
+			"evergreen-distro": h.Distro.Id,
+		},
+		Properties: azureVirtualMachineProperties{
+			HardwareProfile: azureHardwareProfile{
+				VMSize: s.VMSize,
+			},
+			StorageProfile: azureStorageProfile{
+				ImageReference: azureResourceReference{
+					ID: makeAzureImageID(subscriptionID, s),
+				},
+				OSDisk: osDisk,
+			},
+			OSProfile: &azureOSProfile{
+				ComputerName:  h.Id,
+				AdminUsername: adminUsername,
+				LinuxConfiguration: &azureLinuxConfiguration{
+					DisablePasswordAuthentication: true,
+					SSH: azureSSHConfiguration{
+						PublicKeys: []azureSSHPublicKey{
+							{
+								Path:    fmt.Sprintf("/home/%s/.ssh/authorized_keys", adminUsername),
+								KeyData: s.SSHPublicKey,
+							},
+						},
+					},
+				},
+			},
+			NetworkProfile: azureNetworkProfile{
+				NetworkAPIVersion: azureVMNetworkAPIVersion,
+				NetworkInterfaceConfigurations: []azureNetworkInterfaceConfiguration{
+					{
+						Name: h.Id + "-nic",
+						Properties: azureNetworkInterfaceConfigurationProperties{
+							Primary:      true,
+							DeleteOption: azureDeleteOptionDelete,
+							IPConfigurations: []azureIPConfiguration{
+								{
+									Name: h.Id + "-ipconfig",
+									Properties: azureIPConfigurationProperties{
+										Subnet: &azureResourceReference{ID: s.SubnetID},
+										PublicIPAddressConfiguration: &azurePublicIPAddressConfiguration{
+											Name: h.Id + "-ip",
+											Properties: azurePublicIPAddressConfigurationProperties{
+												DeleteOption: azureDeleteOptionDelete,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// azureHourlyPrice returns the pay-as-you-go hourly price of a Linux VM from
+// the retail prices for its size and location.
+func azureHourlyPrice(prices []azureRetailPrice) (float64, error) {
+	for _, p := range prices {
+		if p.Type != "Consumption" || p.UnitOfMeasure != "1 Hour" {
+			continue
+		}
+		if strings.Contains(p.ProductName, "Windows") {
+			continue
+		}
+		if strings.Contains(p.SKUName, "Spot") || strings.Contains(p.SKUName, "Low Priority") {
+			continue
+		}
+
+		return p.RetailPrice, nil
+	}
+
+	return 0, errors.New("no pay-as-you-go hourly price found")
+}
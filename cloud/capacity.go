@@ -0,0 +1,186 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const capacityFallbackReclaimed = "interruptible capacity was reclaimed"
+
+// CapacitySettings configure falling back from interruptible capacity, i.e.
+// EC2 spot instances or GCE preemptible instances, to on-demand capacity for
+// a distro. They are read from the distro's provider settings alongside the
+// settings of its provider.
+type CapacitySettings struct {
+	// FallbackToOnDemand enables spawning hosts on on-demand capacity when
+	// requests for interruptible capacity fail, and replacing hosts whose
+	// interruptible capacity is reclaimed with on-demand hosts.
+	FallbackToOnDemand bool `mapstructure:"fallback_to_on_demand"`
+
+	// MaxFallbackHosts caps the number of on-demand fallback hosts of the
+	// distro that may be up at once, which bounds the extra cost.
+	MaxFallbackHosts int `mapstructure:"max_fallback_hosts"`
+}
+
+// Validate checks that the budget cap is set if fallback is enabled.
+func (s *CapacitySettings) Validate() error {
+	if s.MaxFallbackHosts < 0 {
+		return errors.New("max fallback hosts must be non-negative")
+	}
+	if s.FallbackToOnDemand && s.MaxFallbackHosts == 0 {
+		return errors.New("max fallback hosts must be positive when falling back to on-demand capacity")
+	}
+	return nil
+}
+
+// ValidateCapacitySettings checks the capacity settings in the distro's
+// provider settings.
+func ValidateCapacitySettings(d *distro.Distro) error {
+	_, err := getCapacitySettings(d)
+	return err
+}
+
+// interruptibleManager is implemented by managers that can spawn hosts on
+// interruptible capacity, and on on-demand capacity for hosts marked with
+// CapacityFallback.
+type interruptibleManager interface {
+	// isInterruptible returns whether the host runs, or would be spawned,
+	// on interruptible capacity.
+	isInterruptible(*host.Host) (bool, error)
+}
+
+func getCapacitySettings(d *distro.Distro) (*CapacitySettings, error) {
+	s := &CapacitySettings{}
+	if d.ProviderSettings != nil {
+		if err := mapstructure.Decode(d.ProviderSettings, s); err != nil {
+			return nil, errors.Wrapf(err, "error decoding capacity settings for distro '%s'", d.Id)
+		}
+	}
+	if err := s.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid capacity settings for distro '%s'", d.Id)
+	}
+	return s, nil
+}
+
+// isInterruptible returns whether the host runs, or would be spawned, on
+// interruptible capacity that may be replaced with on-demand capacity.
+func isInterruptible(mgr Manager, h *host.Host) (bool, error) {
+	if h.CapacityFallback || h.UserHost || h.SpawnOptions.SpawnedByTask {
+		return false, nil
+	}
+	im, ok := mgr.(interruptibleManager)
+	if !ok {
+		return false, nil
+	}
+	return im.isInterruptible(h)
+}
+
+// withinFallbackBudget returns whether the distro allows falling back to
+// on-demand capacity, and another fallback host would not exceed its cap.
+func withinFallbackBudget(d *distro.Distro) (bool, error) {
+	s, err := getCapacitySettings(d)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	if !s.FallbackToOnDemand {
+		return false, nil
+	}
+
+	n, err := host.CountCapacityFallbackHostsByDistro(d.Id)
+	if err != nil {
+		return false, errors.Wrapf(err, "error counting fallback hosts of distro '%s'", d.Id)
+	}
+	return n < s.MaxFallbackHosts, nil
+}
+
+// SpawnHostWithFallback spawns the host, and if requesting interruptible
+// capacity for it fails, spawns it on on-demand capacity instead if its
+// distro allows it. The decision is recorded as a host event.
+func SpawnHostWithFallback(ctx context.Context, mgr Manager, h *host.Host) (*host.Host, error) {
+	spawned, spawnErr := mgr.SpawnHost(ctx, h)
+	if spawnErr == nil {
+		return spawned, nil
+	}
+
+	interruptible, err := isInterruptible(mgr, h)
+	if err != nil || !interruptible {
+		return nil, spawnErr
+	}
+	fallback, err := withinFallbackBudget(&h.Distro)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem checking whether to fall back to on-demand capacity",
+			"host":    h.Id,
+			"distro":  h.Distro.Id,
+		}))
+		return nil, spawnErr
+	}
+	event.LogHostCapacityFallback(h.Id, spawnErr.Error(), "", fallback)
+	if !fallback {
+		return nil, spawnErr
+	}
+
+	grip.Info(message.Fields{
+		"message": "falling back to on-demand capacity",
+		"host":    h.Id,
+		"distro":  h.Distro.Id,
+		"reason":  spawnErr.Error(),
+	})
+
+	h.CapacityFallback = true
+	spawned, err = mgr.SpawnHost(ctx, h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error spawning host on on-demand capacity after interruptible capacity failed: %s", spawnErr.Error())
+	}
+	return spawned, nil
+}
+
+// ReplaceReclaimedHost inserts an intent for an on-demand host to replace the
+// terminated host if it ran on interruptible capacity, and its distro allows
+// falling back to on-demand capacity. The decision is recorded as a host
+// event. It returns the replacement, if any.
+func ReplaceReclaimedHost(mgr Manager, h *host.Host) (*host.Host, error) {
+	interruptible, err := isInterruptible(mgr, h)
+	if err != nil || !interruptible {
+		return nil, errors.WithStack(err)
+	}
+
+	d, err := distro.FindOne(distro.ById(h.Distro.Id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding distro '%s'", h.Distro.Id)
+	}
+	fallback, err := withinFallbackBudget(&d)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !fallback {
+		event.LogHostCapacityFallback(h.Id, capacityFallbackReclaimed, "", false)
+		return nil, nil
+	}
+
+	intent := NewIntent(d, d.GenerateName(), h.Provider, HostOptions{
+		UserName: evergreen.User,
+	})
+	intent.CapacityFallback = true
+	if err = intent.Insert(); err != nil {
+		return nil, errors.Wrapf(err, "error inserting replacement for host '%s'", h.Id)
+	}
+
+	grip.Info(message.Fields{
+		"message":     "replacing reclaimed host with on-demand host",
+		"host":        h.Id,
+		"replacement": intent.Id,
+		"distro":      d.Id,
+	})
+	event.LogHostCapacityFallback(h.Id, capacityFallbackReclaimed, intent.Id, true)
+
+	return intent, nil
+}
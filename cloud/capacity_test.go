@@ -0,0 +1,32 @@
+package cloud
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapacitySettingsValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&CapacitySettings{}).Validate())
+	assert.NoError((&CapacitySettings{FallbackToOnDemand: true, MaxFallbackHosts: 2}).Validate())
+	assert.Error((&CapacitySettings{FallbackToOnDemand: true}).Validate())
+	assert.Error((&CapacitySettings{MaxFallbackHosts: -1}).Validate())
+
+	d := &distro.Distro{
+		Id: "distro",
+		ProviderSettings: &map[string]interface{}{
+			"fallback_to_on_demand": true,
+			"max_fallback_hosts":    3,
+		},
+	}
+	s, err := getCapacitySettings(d)
+	assert.NoError(err)
+	assert.True(s.FallbackToOnDemand)
+	assert.Equal(3, s.MaxFallbackHosts)
+
+	(*d.ProviderSettings)["max_fallback_hosts"] = 0
+	assert.Error(ValidateCapacitySettings(d))
+}
@@ -65,6 +65,12 @@ type ContainerManager interface {
 	CalculateImageSpaceUsage(ctx context.Context, h *host.Host) (int64, error)
 	// BuildContainerImage downloads and builds a container image onto parent specified by URL
 	BuildContainerImage(ctx context.Context, parent *host.Host, url string) error
+	// GetDiskUsage returns the total space used by docker on a specified host
+	GetDiskUsage(ctx context.Context, h *host.Host) (int64, error)
+	// PruneSystem removes containers and images that have been unused for
+	// the duration, volumes, and build cache from a specified host and
+	// returns the number of bytes reclaimed
+	PruneSystem(ctx context.Context, h *host.Host, unusedFor time.Duration) (uint64, error)
 }
 
 // CostCalculator is an interface for cloud providers that can estimate what a span of time on a
@@ -73,12 +79,65 @@ type CostCalculator interface {
 	CostForDuration(context.Context, *host.Host, time.Time, time.Time, *evergreen.Settings) (float64, error)
 }
 
+// InstanceStopper is an interface for cloud providers whose hosts can be
+// stopped and started again later, keeping their disks.
+type InstanceStopper interface {
+	// StopInstance stops the host and marks it stopped.
+	StopInstance(context.Context, *host.Host, string) error
+
+	// StartInstance starts the stopped host and marks it running.
+	StartInstance(context.Context, *host.Host, string) error
+}
+
+// VolumeAttacher is an interface for cloud providers that can attach
+// persistent volumes to hosts.
+type VolumeAttacher interface {
+	// AttachVolume attaches the volume to the host and records it on the
+	// host.
+	AttachVolume(context.Context, *host.Host, host.VolumeAttachment) error
+
+	// DetachVolume detaches the volume with the given ID from the host.
+	DetachVolume(context.Context, *host.Host, string) error
+
+	// GetVolumeOwner returns the user that owns the volume with the given
+	// ID, in the region of the host, or an empty string if it has no owner.
+	GetVolumeOwner(context.Context, *host.Host, string) (string, error)
+}
+
 // BatchManager is an interface for cloud providers that support batch operations.
 type BatchManager interface {
 	// GetInstanceStatuses gets the status of a slice of instances.
 	GetInstanceStatuses(context.Context, []host.Host) ([]CloudStatus, error)
 }
 
+// ImageBaker is an interface for cloud providers that can capture the disk of
+// a running host as an image from which new hosts can be started.
+type ImageBaker interface {
+	// BakeImage starts building an image from the disk of the given host,
+	// tagged with the given container pool, and returns the new image's ID.
+	// The host is restarted, so it must not be running any containers.
+	BakeImage(ctx context.Context, h *host.Host, poolID string) (string, error)
+	// GetBakedImages returns the images baked for the given container pool
+	// in the region of the given host.
+	GetBakedImages(ctx context.Context, h *host.Host, poolID string) ([]BakedImage, error)
+	// DeleteBakedImage deregisters a baked image in the region of the given
+	// host, and deletes the snapshots that back it.
+	DeleteBakedImage(ctx context.Context, h *host.Host, image BakedImage) error
+}
+
+const (
+	BakedImageStatePending   = "pending"
+	BakedImageStateAvailable = "available"
+)
+
+// BakedImage describes an image built by an ImageBaker.
+type BakedImage struct {
+	ID          string
+	State       string
+	CreatedAt   time.Time
+	SnapshotIDs []string
+}
+
 // GetManager returns an implementation of Manager for the given provider name.
 // It returns an error if the provider name doesn't have a known implementation.
 func GetManager(ctx context.Context, providerName string, settings *evergreen.Settings) (Manager, error) {
@@ -105,6 +164,8 @@ func GetManager(ctx context.Context, providerName string, settings *evergreen.Se
 		provider = &gceManager{}
 	case evergreen.ProviderNameVsphere:
 		provider = &vsphereManager{}
+	case evergreen.ProviderNameAzure:
+		provider = &azureManager{}
 	default:
 		return nil, errors.Errorf("No known provider for '%s'", providerName)
 	}
@@ -0,0 +1,84 @@
+package cloud
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/host"
+)
+
+// ContainerRuntime is a provider-agnostic interface to the container engine
+// running on a parent host. The host-management code paths for containers
+// are written against it, so each container backend only needs to implement
+// these operations.
+type ContainerRuntime interface {
+	// Init connects to the runtime using the given API version.
+	Init(string) error
+
+	// EnsureImageDownloaded downloads the image at the given URL onto the
+	// parent if it is not already there, and returns the image's name.
+	EnsureImageDownloaded(context.Context, *host.Host, string) (string, error)
+	// BuildImageWithAgent builds an image with the Evergreen agent on top
+	// of the given base image, and returns the new image's name.
+	BuildImageWithAgent(context.Context, *host.Host, string) (string, error)
+	// GetImageDigest returns the content-addressable digest of an image.
+	GetImageDigest(context.Context, *host.Host, string) (string, error)
+	// ListImages lists the images on the parent.
+	ListImages(context.Context, *host.Host) ([]ContainerImage, error)
+	// RemoveImage forcibly removes an image from the parent.
+	RemoveImage(context.Context, *host.Host, string) error
+
+	// CreateContainer creates the container for the container host on the
+	// parent, configured by the container distro's settings.
+	CreateContainer(context.Context, *host.Host, *host.Host, *dockerSettings) error
+	// StartContainer starts a created container.
+	StartContainer(context.Context, *host.Host, string) error
+	// StopContainer stops a running container, killing it if it does not
+	// stop within the timeout.
+	StopContainer(context.Context, *host.Host, string, time.Duration) error
+	// RemoveContainer forcibly removes a container.
+	RemoveContainer(context.Context, *host.Host, string) error
+	// GetContainer returns information on a container.
+	GetContainer(context.Context, *host.Host, string) (*ContainerInfo, error)
+	// ListContainers lists the running containers on the parent.
+	ListContainers(context.Context, *host.Host) ([]ContainerInfo, error)
+	// GetContainerLogs returns the output of a container.
+	GetContainerLogs(context.Context, *host.Host, string) (io.ReadCloser, error)
+	// CopyFromContainer returns a tar archive of a path in a container.
+	CopyFromContainer(context.Context, *host.Host, string, string) (io.ReadCloser, error)
+	// GetContainerStats returns the current resource usage of a container.
+	GetContainerStats(context.Context, *host.Host, string) (*ContainerStats, error)
+
+	// GetDiskUsage returns the bytes used by the runtime on the parent.
+	GetDiskUsage(context.Context, *host.Host) (int64, error)
+	// PruneSystem removes containers and images that have been unused for
+	// the duration, and build cache, from the parent, and returns the bytes
+	// reclaimed.
+	PruneSystem(context.Context, *host.Host, time.Duration) (uint64, error)
+}
+
+// ContainerInfo describes a container managed by a ContainerRuntime.
+type ContainerInfo struct {
+	ID      string
+	Name    string
+	ImageID string
+	Status  CloudStatus
+}
+
+// ContainerImage describes an image on a parent managed by a
+// ContainerRuntime.
+type ContainerImage struct {
+	ID      string
+	Created time.Time
+	Size    int64
+}
+
+// ContainerStats is a snapshot of the resource usage of a container.
+type ContainerStats struct {
+	// CPUPercent is the CPU usage as a percentage of one CPU, so it can
+	// exceed 100 when the container uses more than one CPU.
+	CPUPercent  float64
+	MemoryUsage uint64
+	MemoryLimit uint64
+}
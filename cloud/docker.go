@@ -2,12 +2,18 @@ package cloud
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/go-units"
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
 	"github.com/mitchellh/mapstructure"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/mongodb/grip"
@@ -15,32 +21,144 @@ import (
 	"github.com/pkg/errors"
 )
 
-// dockerManager implements the Manager interface for Docker.
+// dockerManager implements the Manager interface for containers. It manages
+// container hosts through a ContainerRuntime, of which Docker is the default.
 type dockerManager struct {
-	client dockerClient
+	client   ContainerRuntime
+	uploader containerArtifactUploader
 }
 
 // ProviderSettings specifies the settings used to configure a host instance.
 type dockerSettings struct {
 	// ImageURL is the url of the Docker image to use when building the container.
 	ImageURL string `mapstructure:"image_url" json:"image_url" bson:"image_url"`
+	// WindowsImageURL is the url of the Docker image to use when building the
+	// container on a Windows parent. If it is blank, ImageURL is used instead.
+	WindowsImageURL string `mapstructure:"windows_image_url" json:"windows_image_url,omitempty" bson:"windows_image_url,omitempty"`
+	// AllowedImageDigests optionally restricts containers to images whose
+	// digest appears in the list.
+	AllowedImageDigests []string `mapstructure:"allowed_image_digests" json:"allowed_image_digests,omitempty" bson:"allowed_image_digests,omitempty"`
+	// Ulimits are the resource limits of the container, each in the form
+	// "name=soft[:hard]", e.g. "nofile=1024:2048".
+	Ulimits []string `mapstructure:"ulimits" json:"ulimits,omitempty" bson:"ulimits,omitempty"`
+	// CapDrop lists the Linux capabilities to remove from the container.
+	CapDrop []string `mapstructure:"cap_drop" json:"cap_drop,omitempty" bson:"cap_drop,omitempty"`
+	// SeccompProfile is the JSON seccomp profile to apply to the container,
+	// or "unconfined" to disable seccomp filtering.
+	SeccompProfile string `mapstructure:"seccomp_profile" json:"seccomp_profile,omitempty" bson:"seccomp_profile,omitempty"`
+	// AppArmorProfile is the name of an AppArmor profile loaded on the parent
+	// to apply to the container.
+	AppArmorProfile string `mapstructure:"apparmor_profile" json:"apparmor_profile,omitempty" bson:"apparmor_profile,omitempty"`
 }
 
 // nolint
 var (
 	// bson fields for the ProviderSettings struct
-	imageURLKey = bsonutil.MustHaveTag(dockerSettings{}, "ImageURL")
+	imageURLKey            = bsonutil.MustHaveTag(dockerSettings{}, "ImageURL")
+	windowsImageURLKey     = bsonutil.MustHaveTag(dockerSettings{}, "WindowsImageURL")
+	allowedImageDigestsKey = bsonutil.MustHaveTag(dockerSettings{}, "AllowedImageDigests")
+	ulimitsKey             = bsonutil.MustHaveTag(dockerSettings{}, "Ulimits")
+	capDropKey             = bsonutil.MustHaveTag(dockerSettings{}, "CapDrop")
+	seccompProfileKey      = bsonutil.MustHaveTag(dockerSettings{}, "SeccompProfile")
+	appArmorProfileKey     = bsonutil.MustHaveTag(dockerSettings{}, "AppArmorProfile")
+)
+
+const (
+	imageDigestPrefix = "sha256:"
+	seccompUnconfined = "unconfined"
 )
 
 //Validate checks that the settings from the config file are sane.
 func (settings *dockerSettings) Validate() error {
-	if settings.ImageURL == "" {
+	if settings.ImageURL == "" && settings.WindowsImageURL == "" {
 		return errors.New("ImageURL must not be blank")
 	}
+	for _, digest := range settings.AllowedImageDigests {
+		if !strings.HasPrefix(digest, imageDigestPrefix) {
+			return errors.Errorf("allowed image digest '%s' must start with '%s'", digest, imageDigestPrefix)
+		}
+	}
+	for _, ulimit := range settings.Ulimits {
+		if _, err := units.ParseUlimit(ulimit); err != nil {
+			return errors.Wrapf(err, "invalid ulimit '%s'", ulimit)
+		}
+	}
+	for _, capability := range settings.CapDrop {
+		if strings.TrimSpace(capability) == "" {
+			return errors.New("capabilities to drop must not be blank")
+		}
+	}
+	if settings.SeccompProfile != "" && settings.SeccompProfile != seccompUnconfined &&
+		!json.Valid([]byte(settings.SeccompProfile)) {
+		return errors.Errorf("seccomp profile must be valid JSON or '%s'", seccompUnconfined)
+	}
 
 	return nil
 }
 
+// imageURL returns the url of the image to build containers from on a parent
+// of the given OS, or an empty string if no image is configured for it.
+func (settings *dockerSettings) imageURL(windows bool) string {
+	if windows && settings.WindowsImageURL != "" {
+		return settings.WindowsImageURL
+	}
+	return settings.ImageURL
+}
+
+// hostConfig returns the resource limits and security options that the
+// container is created with.
+func (settings *dockerSettings) hostConfig() (*container.HostConfig, error) {
+	hostConf := &container.HostConfig{
+		CapDrop: settings.CapDrop,
+	}
+
+	for _, ulimit := range settings.Ulimits {
+		parsed, err := units.ParseUlimit(ulimit)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid ulimit '%s'", ulimit)
+		}
+		hostConf.Ulimits = append(hostConf.Ulimits, parsed)
+	}
+
+	if settings.SeccompProfile != "" {
+		hostConf.SecurityOpt = append(hostConf.SecurityOpt, "seccomp="+settings.SeccompProfile)
+	}
+	if settings.AppArmorProfile != "" {
+		hostConf.SecurityOpt = append(hostConf.SecurityOpt, "apparmor="+settings.AppArmorProfile)
+	}
+
+	return hostConf, nil
+}
+
+// verifyImageDigest checks that the digest is permitted by the allow-list, if
+// one is configured.
+func (settings *dockerSettings) verifyImageDigest(digest string) error {
+	if len(settings.AllowedImageDigests) == 0 {
+		return nil
+	}
+	if !util.StringSliceContains(settings.AllowedImageDigests, digest) {
+		return errors.Errorf("image digest '%s' is not in the allowed image digests", digest)
+	}
+	return nil
+}
+
+// ContainerImageURL returns the url of the image that containers of the distro
+// are built from on the given parent, which depends on the parent's OS.
+func ContainerImageURL(d *distro.Distro, parent *host.Host) (string, error) {
+	settings := &dockerSettings{}
+	if d.ProviderSettings != nil {
+		if err := mapstructure.Decode(d.ProviderSettings, settings); err != nil {
+			return "", errors.Wrapf(err, "Error decoding params for distro '%s'", d.Id)
+		}
+	}
+
+	imageURL := settings.imageURL(parent.Distro.IsWindows())
+	if imageURL == "" {
+		return "", errors.Errorf("distro '%s' has no image for the OS of parent '%s'", d.Id, parent.Id)
+	}
+	return imageURL, nil
+}
+
 // GetSettings returns an empty ProviderSettings struct.
 func (*dockerManager) GetSettings() ProviderSettings {
 	return &dockerSettings{}
@@ -74,14 +192,29 @@ func (m *dockerManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host
 	if err = settings.Validate(); err != nil {
 		return nil, errors.Wrapf(err, "Invalid Docker settings for host '%s'", h.Id)
 	}
+	imageURL := settings.imageURL(parentHost.Distro.IsWindows())
+	if imageURL == "" {
+		return nil, errors.Errorf("No image for the OS of parent '%s' is configured for host '%s'", parentHost.Id, h.Id)
+	}
 
 	grip.Info(message.Fields{
 		"message":   "decoded Docker container settings",
 		"container": h.Id,
 		"host_ip":   hostIP,
-		"image_url": settings.ImageURL,
+		"image_url": imageURL,
 	})
 
+	// Verify the provenance of the image before creating a container from it
+	digest, err := m.client.GetImageDigest(ctx, parentHost, imageNameFromURL(imageURL))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get image digest for host '%s'", h.Id)
+	}
+	if err = settings.verifyImageDigest(digest); err != nil {
+		err = errors.Wrapf(err, "Refusing to create container for host '%s'", h.Id)
+		grip.Error(err)
+		return nil, err
+	}
+
 	// Create container
 	if err = m.client.CreateContainer(ctx, parentHost, h, settings); err != nil {
 		err = errors.Wrapf(err, "Failed to create container for host '%s'", hostIP)
@@ -89,6 +222,10 @@ func (m *dockerManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host
 		return nil, err
 	}
 
+	if err = h.SetImageDigest(digest); err != nil {
+		return nil, errors.Wrapf(err, "error setting image digest on host %s", h.Id)
+	}
+
 	if err = h.SetAgentRevision(evergreen.BuildRevision); err != nil {
 		return nil, errors.Wrapf(err, "error setting agent revision on host %s", h.Id)
 	}
@@ -132,7 +269,7 @@ func (m *dockerManager) GetInstanceStatus(ctx context.Context, h *host.Host) (Cl
 		return StatusUnknown, errors.Wrapf(err, "Failed to get container information for host '%v'", h.Id)
 	}
 
-	return toEvgStatus(container.State), nil
+	return container.Status, nil
 }
 
 // GetDNSName does nothing, returning an empty string and no error.
@@ -154,6 +291,10 @@ func (m *dockerManager) TerminateInstance(ctx context.Context, h *host.Host, use
 		return errors.Wrapf(err, "Error retrieving parent for host '%s'", h.Id)
 	}
 
+	if names := m.teardownContainer(ctx, parent, h); len(names) > 0 {
+		event.LogHostArtifactsCollected(h.Id, names)
+	}
+
 	if err := m.client.RemoveContainer(ctx, parent, h.Id); err != nil {
 		return errors.Wrap(err, "API call to remove container failed")
 	}
@@ -180,6 +321,13 @@ func (m *dockerManager) Configure(ctx context.Context, s *evergreen.Settings) er
 		return errors.Wrap(err, "Failed to initialize client connection")
 	}
 
+	if m.uploader == nil && config.ArtifactsBucket != "" {
+		m.uploader = makeS3ArtifactUploader(&aws.Auth{
+			AccessKey: s.Providers.AWS.Id,
+			SecretKey: s.Providers.AWS.Secret,
+		}, config.ArtifactsBucket)
+	}
+
 	return nil
 }
 
@@ -226,13 +374,7 @@ func (m *dockerManager) GetContainers(ctx context.Context, h *host.Host) ([]stri
 
 	ids := []string{}
 	for _, container := range containers {
-		name := container.Names[0]
-		// names in Docker have leading slashes -- https://github.com/moby/moby/issues/6705
-		if !strings.HasPrefix(name, "/") {
-			return nil, errors.New("error reading container name")
-		}
-		name = name[1:]
-		ids = append(ids, name)
+		ids = append(ids, container.Name)
 	}
 
 	return ids, nil
@@ -293,6 +435,33 @@ func (m *dockerManager) CalculateImageSpaceUsage(ctx context.Context, h *host.Ho
 	return spaceBytes, nil
 }
 
+// GetDiskUsage returns the amount of bytes that Docker takes up on disk
+func (m *dockerManager) GetDiskUsage(ctx context.Context, h *host.Host) (int64, error) {
+	if !h.HasContainers {
+		return 0, errors.Errorf("Error getting disk usage: '%s' is not a parent", h.Id)
+	}
+
+	usage, err := m.client.GetDiskUsage(ctx, h)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Error getting disk usage on host '%s'", h.Id)
+	}
+	return usage, nil
+}
+
+// PruneSystem removes unused containers, images, volumes, and build cache from
+// the parent and returns the amount of bytes reclaimed
+func (m *dockerManager) PruneSystem(ctx context.Context, h *host.Host, unusedFor time.Duration) (uint64, error) {
+	if !h.HasContainers {
+		return 0, errors.Errorf("Error pruning system: '%s' is not a parent", h.Id)
+	}
+
+	reclaimed, err := m.client.PruneSystem(ctx, h, unusedFor)
+	if err != nil {
+		return reclaimed, errors.Wrapf(err, "Error pruning system on host '%s'", h.Id)
+	}
+	return reclaimed, nil
+}
+
 // CostForDuration estimates the cost for a span of time on the given container
 // host. The method divides the cost of that span on the parent host by an
 // estimate of the number of containers running during the same interval.
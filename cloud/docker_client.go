@@ -4,19 +4,21 @@ package cloud
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
-	"path"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
 	docker "github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/util"
@@ -25,20 +27,7 @@ import (
 	"github.com/pkg/errors"
 )
 
-// The dockerClient interface wraps the Docker dockerClient interaction.
-type dockerClient interface {
-	Init(string) error
-	EnsureImageDownloaded(context.Context, *host.Host, string) (string, error)
-	BuildImageWithAgent(context.Context, *host.Host, string) (string, error)
-	CreateContainer(context.Context, *host.Host, *host.Host, *dockerSettings) error
-	GetContainer(context.Context, *host.Host, string) (*types.ContainerJSON, error)
-	ListContainers(context.Context, *host.Host) ([]types.Container, error)
-	RemoveImage(context.Context, *host.Host, string) error
-	RemoveContainer(context.Context, *host.Host, string) error
-	StartContainer(context.Context, *host.Host, string) error
-	ListImages(context.Context, *host.Host) ([]types.ImageSummary, error)
-}
-
+// dockerClientImpl is the Docker backend of the ContainerRuntime interface.
 type dockerClientImpl struct {
 	// apiVersion specifies the version of the Docker API.
 	apiVersion string
@@ -134,8 +123,7 @@ func (c *dockerClientImpl) EnsureImageDownloaded(ctx context.Context, h *host.Ho
 	})
 
 	// Extract image name from url
-	baseName := path.Base(url)
-	imageName := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	imageName := imageNameFromURL(url)
 
 	// Check if image already exists on host
 	_, _, err = dockerClient.ImageInspectWithRaw(ctx, imageName)
@@ -286,8 +274,7 @@ func (c *dockerClientImpl) CreateContainer(ctx context.Context, parentHost, cont
 	}
 
 	// Extract image name from url
-	baseName := path.Base(settings.ImageURL)
-	provisionedImage := fmt.Sprintf(provisionedImageTag, strings.TrimSuffix(baseName, filepath.Ext(baseName)))
+	provisionedImage := fmt.Sprintf(provisionedImageTag, imageNameFromURL(settings.imageURL(parentHost.Distro.IsWindows())))
 
 	// Build path to Evergreen executable.
 	pathToExecutable := filepath.Join("root", "evergreen")
@@ -321,10 +308,16 @@ func (c *dockerClientImpl) CreateContainer(ctx context.Context, parentHost, cont
 		User:  containerHost.Distro.User,
 	}
 	networkConf := &network.NetworkingConfig{}
-	hostConf := &container.HostConfig{}
+	hostConf, err := settings.hostConfig()
+	if err != nil {
+		return errors.Wrapf(err, "problem configuring container '%s'", containerHost.Id)
+	}
 
 	msg := makeDockerLogMessage("ContainerCreate", parentHost.Id, message.Fields{
-		"image": containerConf.Image,
+		"image":        containerConf.Image,
+		"ulimits":      settings.Ulimits,
+		"cap_drop":     settings.CapDrop,
+		"security_opt": len(hostConf.SecurityOpt),
 	})
 
 	// Build container
@@ -338,9 +331,25 @@ func (c *dockerClientImpl) CreateContainer(ctx context.Context, parentHost, cont
 	return nil
 }
 
-// GetContainer returns low-level information on the Docker container with the
-// specified ID running on the specified host machine.
-func (c *dockerClientImpl) GetContainer(ctx context.Context, h *host.Host, containerID string) (*types.ContainerJSON, error) {
+// GetImageDigest returns the content-addressable digest of the image with the
+// specified name on the host machine.
+func (c *dockerClientImpl) GetImageDigest(ctx context.Context, h *host.Host, imageName string) (string, error) {
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	image, _, err := dockerClient.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return "", errors.Wrapf(err, "Docker inspect API call failed for image '%s'", imageName)
+	}
+
+	return image.ID, nil
+}
+
+// GetContainer returns information on the Docker container with the specified
+// ID running on the specified host machine.
+func (c *dockerClientImpl) GetContainer(ctx context.Context, h *host.Host, containerID string) (*ContainerInfo, error) {
 	dockerClient, err := c.generateClient(h)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to generate docker client")
@@ -351,11 +360,21 @@ func (c *dockerClientImpl) GetContainer(ctx context.Context, h *host.Host, conta
 		return nil, errors.Wrapf(err, "Docker inspect API call failed for container '%s'", containerID)
 	}
 
-	return &container, nil
+	info := &ContainerInfo{
+		ID:      container.ID,
+		Name:    strings.TrimPrefix(container.Name, "/"),
+		ImageID: container.Image,
+		Status:  StatusUnknown,
+	}
+	if container.State != nil {
+		info.Status = toEvgStatus(container.State)
+	}
+
+	return info, nil
 }
 
 // ListContainers lists all containers running on the specified host machine.
-func (c *dockerClientImpl) ListContainers(ctx context.Context, h *host.Host) ([]types.Container, error) {
+func (c *dockerClientImpl) ListContainers(ctx context.Context, h *host.Host) ([]ContainerInfo, error) {
 	dockerClient, err := c.generateClient(h)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to generate docker client")
@@ -370,11 +389,25 @@ func (c *dockerClientImpl) ListContainers(ctx context.Context, h *host.Host) ([]
 		return nil, err
 	}
 
-	return containers, nil
+	infos := make([]ContainerInfo, 0, len(containers))
+	for _, container := range containers {
+		// names in Docker have leading slashes -- https://github.com/moby/moby/issues/6705
+		if len(container.Names) == 0 || !strings.HasPrefix(container.Names[0], "/") {
+			return nil, errors.Errorf("error reading name of container '%s'", container.ID)
+		}
+		infos = append(infos, ContainerInfo{
+			ID:      container.ID,
+			Name:    container.Names[0][1:],
+			ImageID: container.ImageID,
+			Status:  StatusRunning,
+		})
+	}
+
+	return infos, nil
 }
 
 // ListImages lists all images on the specified host machine.
-func (c *dockerClientImpl) ListImages(ctx context.Context, h *host.Host) ([]types.ImageSummary, error) {
+func (c *dockerClientImpl) ListImages(ctx context.Context, h *host.Host) ([]ContainerImage, error) {
 	dockerClient, err := c.generateClient(h)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to generate docker client")
@@ -388,7 +421,16 @@ func (c *dockerClientImpl) ListImages(ctx context.Context, h *host.Host) ([]type
 		return nil, err
 	}
 
-	return images, nil
+	summaries := make([]ContainerImage, 0, len(images))
+	for _, image := range images {
+		summaries = append(summaries, ContainerImage{
+			ID:      image.ID,
+			Created: time.Unix(image.Created, 0),
+			Size:    image.Size,
+		})
+	}
+
+	return summaries, nil
 }
 
 // RemoveImage forcibly removes an image from its host machine
@@ -443,6 +485,164 @@ func (c *dockerClientImpl) StartContainer(ctx context.Context, h *host.Host, con
 	return nil
 }
 
+// StopContainer gracefully stops a running container by ID on the host
+// machine, killing it if it has not exited after the timeout.
+func (c *dockerClientImpl) StopContainer(ctx context.Context, h *host.Host, containerID string, timeout time.Duration) error {
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	if err := dockerClient.ContainerStop(ctx, containerID, &timeout); err != nil {
+		return errors.Wrapf(err, "Failed to stop container %s", containerID)
+	}
+
+	return nil
+}
+
+// GetContainerLogs returns the combined stdout and stderr logs of a container
+// by ID on the host machine.
+func (c *dockerClientImpl) GetContainerLogs(ctx context.Context, h *host.Host, containerID string) (io.ReadCloser, error) {
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	opts := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+	}
+	logs, err := dockerClient.ContainerLogs(ctx, containerID, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get logs of container %s", containerID)
+	}
+
+	// the container is created without a TTY, so stdout and stderr are
+	// multiplexed into the stream
+	reader, writer := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, logs)
+		grip.Warning(logs.Close())
+		writer.CloseWithError(err)
+	}()
+
+	return reader, nil
+}
+
+// CopyFromContainer returns a tar archive of the path inside a container by ID
+// on the host machine.
+func (c *dockerClientImpl) CopyFromContainer(ctx context.Context, h *host.Host, containerID, path string) (io.ReadCloser, error) {
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	archive, _, err := dockerClient.CopyFromContainer(ctx, containerID, path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to copy '%s' from container %s", path, containerID)
+	}
+
+	return archive, nil
+}
+
+// GetContainerStats returns a snapshot of the resource usage of a container by
+// ID on the host machine.
+func (c *dockerClientImpl) GetContainerStats(ctx context.Context, h *host.Host, containerID string) (*ContainerStats, error) {
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	resp, err := dockerClient.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to get stats of container %s", containerID)
+	}
+	defer resp.Body.Close()
+
+	stats := types.StatsJSON{}
+	if err = json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, errors.Wrapf(err, "Failed to decode stats of container %s", containerID)
+	}
+
+	return toContainerStats(&stats), nil
+}
+
+// GetDiskUsage returns the total number of bytes used by Docker on the host
+// machine, including image layers, writable container layers, volumes, and the
+// build cache.
+func (c *dockerClientImpl) GetDiskUsage(ctx context.Context, h *host.Host) (int64, error) {
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	usage, err := dockerClient.DiskUsage(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "Docker disk usage API call failed")
+	}
+
+	total := usage.LayersSize + usage.BuilderSize
+	for _, container := range usage.Containers {
+		if container != nil {
+			total += container.SizeRw
+		}
+	}
+	for _, volume := range usage.Volumes {
+		if volume != nil && volume.UsageData != nil && volume.UsageData.Size > 0 {
+			total += volume.UsageData.Size
+		}
+	}
+
+	return total, nil
+}
+
+// PruneSystem removes stopped containers and dangling images that have not been
+// used recently, unused volumes, and the build cache from the host machine. It
+// returns the number of bytes reclaimed.
+func (c *dockerClientImpl) PruneSystem(ctx context.Context, h *host.Host, unusedFor time.Duration) (uint64, error) {
+	dockerClient, err := c.generateClient(h)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to generate docker client")
+	}
+
+	untilFilter := filters.NewArgs(filters.Arg("until", unusedFor.String()))
+	reclaimed := uint64(0)
+
+	containersReport, err := dockerClient.ContainersPrune(ctx, untilFilter)
+	if err != nil {
+		return reclaimed, errors.Wrap(err, "Docker container prune API call failed")
+	}
+	reclaimed += containersReport.SpaceReclaimed
+
+	imagesReport, err := dockerClient.ImagesPrune(ctx, untilFilter)
+	if err != nil {
+		return reclaimed, errors.Wrap(err, "Docker image prune API call failed")
+	}
+	reclaimed += imagesReport.SpaceReclaimed
+
+	volumesReport, err := dockerClient.VolumesPrune(ctx, filters.NewArgs())
+	if err != nil {
+		return reclaimed, errors.Wrap(err, "Docker volume prune API call failed")
+	}
+	reclaimed += volumesReport.SpaceReclaimed
+
+	buildCacheReport, err := dockerClient.BuildCachePrune(ctx)
+	if err != nil {
+		return reclaimed, errors.Wrap(err, "Docker build cache prune API call failed")
+	}
+	reclaimed += buildCacheReport.SpaceReclaimed
+
+	grip.Info(makeDockerLogMessage("PruneSystem", h.Id, message.Fields{
+		"containers_deleted": len(containersReport.ContainersDeleted),
+		"images_deleted":     len(imagesReport.ImagesDeleted),
+		"volumes_deleted":    len(volumesReport.VolumesDeleted),
+		"space_reclaimed":    reclaimed,
+	}))
+
+	return reclaimed, nil
+}
+
 func makeDockerLogMessage(name, parent string, data interface{}) message.Fields {
 	return message.Fields{
 		"message":  "Docker API call",
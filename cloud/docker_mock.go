@@ -3,12 +3,12 @@ package cloud
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math/rand"
+	"strings"
 	"time"
 
-	"github.com/docker/docker/api/types"
-	"github.com/docker/docker/api/types/container"
-	"github.com/docker/go-connections/nat"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/pkg/errors"
 )
@@ -23,10 +23,18 @@ type dockerClientMock struct {
 	failList     bool
 	failRemove   bool
 	failStart    bool
+	failStop     bool
+	failLogs     bool
+	failCopy     bool
+	failPrune    bool
+	failUsage    bool
+	failDigest   bool
+	failStats    bool
 
 	// Other options
-	hasOpenPorts bool
-	baseImage    string
+	baseImage   string
+	diskUsage   int64
+	imageDigest string
 }
 
 func (c *dockerClientMock) generateContainerID() string {
@@ -61,55 +69,29 @@ func (c *dockerClientMock) CreateContainer(context.Context, *host.Host, *host.Ho
 	return nil
 }
 
-func (c *dockerClientMock) GetContainer(context.Context, *host.Host, string) (*types.ContainerJSON, error) {
+func (c *dockerClientMock) GetContainer(context.Context, *host.Host, string) (*ContainerInfo, error) {
 	if c.failGet {
 		return nil, errors.New("failed to inspect container")
 	}
 
-	container := &types.ContainerJSON{
-		ContainerJSONBase: &types.ContainerJSONBase{
-			ID:    c.generateContainerID(),
-			State: &types.ContainerState{Running: true},
-		},
-		Config: &container.Config{
-			ExposedPorts: nat.PortSet{"22/tcp": {}},
-		},
-		NetworkSettings: &types.NetworkSettings{
-			NetworkSettingsBase: types.NetworkSettingsBase{
-				Ports: nat.PortMap{
-					"22/tcp": []nat.PortBinding{
-						{
-							HostIP:   "0.0.0.0",
-							HostPort: "5000",
-						},
-					},
-				},
-			},
-		},
-	}
-
-	if !c.hasOpenPorts {
-		container.NetworkSettings = &types.NetworkSettings{}
-	}
-
-	return container, nil
+	id := c.generateContainerID()
+	return &ContainerInfo{
+		ID:     id,
+		Name:   id,
+		Status: StatusRunning,
+	}, nil
 }
 
-func (c *dockerClientMock) ListContainers(context.Context, *host.Host) ([]types.Container, error) {
+func (c *dockerClientMock) ListContainers(context.Context, *host.Host) ([]ContainerInfo, error) {
 	if c.failList {
 		return nil, errors.New("failed to list containers")
 	}
-	container := types.Container{
-		ID: "container-1",
-		Ports: []types.Port{
-			{PublicPort: 5000},
-			{PublicPort: 5001},
-		},
-		Names: []string{
-			"/container-1",
-		},
+	container := ContainerInfo{
+		ID:     "container-1",
+		Name:   "container-1",
+		Status: StatusRunning,
 	}
-	return []types.Container{container}, nil
+	return []ContainerInfo{container}, nil
 }
 
 func (c *dockerClientMock) RemoveContainer(context.Context, *host.Host, string) error {
@@ -119,22 +101,20 @@ func (c *dockerClientMock) RemoveContainer(context.Context, *host.Host, string)
 	return nil
 }
 
-func (c *dockerClientMock) ListImages(context.Context, *host.Host) ([]types.ImageSummary, error) {
+func (c *dockerClientMock) ListImages(context.Context, *host.Host) ([]ContainerImage, error) {
 	if c.failList {
 		return nil, errors.New("failed to list images")
 	}
 	now := time.Now()
-	image1 := types.ImageSummary{
-		ID:         "image-1",
-		Containers: 2,
-		Created:    now.Unix(),
+	image1 := ContainerImage{
+		ID:      "image-1",
+		Created: now,
 	}
-	image2 := types.ImageSummary{
-		ID:         "image-2",
-		Containers: 2,
-		Created:    now.Add(-10 * time.Minute).Unix(),
+	image2 := ContainerImage{
+		ID:      "image-2",
+		Created: now.Add(-10 * time.Minute),
 	}
-	return []types.ImageSummary{image1, image2}, nil
+	return []ContainerImage{image1, image2}, nil
 }
 
 func (c *dockerClientMock) RemoveImage(context.Context, *host.Host, string) error {
@@ -150,3 +130,59 @@ func (c *dockerClientMock) StartContainer(context.Context, *host.Host, string) e
 	}
 	return nil
 }
+
+func (c *dockerClientMock) StopContainer(context.Context, *host.Host, string, time.Duration) error {
+	if c.failStop {
+		return errors.New("failed to stop container")
+	}
+	return nil
+}
+
+func (c *dockerClientMock) GetContainerLogs(context.Context, *host.Host, string) (io.ReadCloser, error) {
+	if c.failLogs {
+		return nil, errors.New("failed to get container logs")
+	}
+	return ioutil.NopCloser(strings.NewReader("container logs")), nil
+}
+
+func (c *dockerClientMock) CopyFromContainer(context.Context, *host.Host, string, string) (io.ReadCloser, error) {
+	if c.failCopy {
+		return nil, errors.New("failed to copy from container")
+	}
+	return ioutil.NopCloser(strings.NewReader("working directory archive")), nil
+}
+
+func (c *dockerClientMock) GetContainerStats(context.Context, *host.Host, string) (*ContainerStats, error) {
+	if c.failStats {
+		return nil, errors.New("failed to get container stats")
+	}
+	return &ContainerStats{
+		CPUPercent:  50,
+		MemoryUsage: 1024,
+		MemoryLimit: 4096,
+	}, nil
+}
+
+func (c *dockerClientMock) GetDiskUsage(context.Context, *host.Host) (int64, error) {
+	if c.failUsage {
+		return 0, errors.New("failed to get disk usage")
+	}
+	return c.diskUsage, nil
+}
+
+func (c *dockerClientMock) PruneSystem(context.Context, *host.Host, time.Duration) (uint64, error) {
+	if c.failPrune {
+		return 0, errors.New("failed to prune system")
+	}
+	return uint64(c.diskUsage), nil
+}
+
+func (c *dockerClientMock) GetImageDigest(context.Context, *host.Host, string) (string, error) {
+	if c.failDigest {
+		return "", errors.New("failed to get image digest")
+	}
+	if c.imageDigest == "" {
+		return "sha256:mock", nil
+	}
+	return c.imageDigest, nil
+}
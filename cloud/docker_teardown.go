@@ -0,0 +1,117 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/goamz/goamz/aws"
+	"github.com/goamz/goamz/s3"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// containerStopTimeout is how long a container is given to exit gracefully
+// before it is killed during teardown.
+const containerStopTimeout = 30 * time.Second
+
+const (
+	// ContainerLogArtifact and ContainerWorkDirArtifact are the names of the
+	// artifacts collected from containers before they are removed.
+	ContainerLogArtifact     = "container.log"
+	ContainerWorkDirArtifact = "working_directory.tar"
+)
+
+// ContainerArtifactKey returns the key that the named artifact of the
+// container is uploaded under.
+func ContainerArtifactKey(hostID, name string) string {
+	return fmt.Sprintf("containers/%s/%s", hostID, name)
+}
+
+// containerArtifactUploader uploads the file at localPath under the given
+// key.
+type containerArtifactUploader func(localPath, key string) error
+
+// makeS3ArtifactUploader uploads artifacts privately. They're linked to
+// through the UI, which signs a URL to download them.
+func makeS3ArtifactUploader(auth *aws.Auth, bucket string) containerArtifactUploader {
+	return func(localPath, key string) error {
+		s3URL := fmt.Sprintf("s3://%s/%s", bucket, key)
+		return errors.Wrapf(thirdparty.PutS3File(auth, localPath, s3URL, "application/octet-stream", string(s3.Private)),
+			"error uploading '%s'", key)
+	}
+}
+
+// teardownContainer gracefully stops the container and, if an artifact
+// uploader is configured, collects the container's logs and the agent's
+// working directory. It returns the names of the collected artifacts. Failures
+// are logged rather than returned so that they never block removal of the
+// container.
+func (m *dockerManager) teardownContainer(ctx context.Context, parent, h *host.Host) []string {
+	grip.Warning(message.WrapError(m.client.StopContainer(ctx, parent, h.Id, containerStopTimeout), message.Fields{
+		"message":   "problem stopping container before removal",
+		"container": h.Id,
+		"parent":    parent.Id,
+	}))
+
+	if m.uploader == nil {
+		return nil
+	}
+
+	names := []string{}
+	collect := func(name string, getArtifact func() (io.ReadCloser, error)) {
+		if err := m.uploadContainerArtifact(h, name, getArtifact); err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message":   "problem collecting container artifact",
+				"artifact":  name,
+				"container": h.Id,
+				"parent":    parent.Id,
+			}))
+			return
+		}
+		names = append(names, name)
+	}
+
+	collect(ContainerLogArtifact, func() (io.ReadCloser, error) {
+		return m.client.GetContainerLogs(ctx, parent, h.Id)
+	})
+	if h.Distro.WorkDir != "" {
+		collect(ContainerWorkDirArtifact, func() (io.ReadCloser, error) {
+			return m.client.CopyFromContainer(ctx, parent, h.Id, h.Distro.WorkDir)
+		})
+	}
+
+	return names
+}
+
+// uploadContainerArtifact writes the artifact to a temporary file and uploads
+// it under a path namespaced by the container's host ID.
+func (m *dockerManager) uploadContainerArtifact(h *host.Host, name string, getArtifact func() (io.ReadCloser, error)) error {
+	artifact, err := getArtifact()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer artifact.Close()
+
+	tmpFile, err := ioutil.TempFile("", "container-artifact")
+	if err != nil {
+		return errors.Wrap(err, "error creating temporary file")
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = io.Copy(tmpFile, artifact)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error writing '%s' to temporary file", name)
+	}
+
+	return m.uploader(tmpFile.Name(), ContainerArtifactKey(h.Id, name))
+}
@@ -5,6 +5,7 @@ package cloud
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/evergreen-ci/evergreen"
@@ -16,7 +17,7 @@ import (
 )
 
 type DockerSuite struct {
-	client     dockerClient
+	client     ContainerRuntime
 	manager    *dockerManager
 	distro     distro.Distro
 	hostOpts   HostOptions
@@ -34,9 +35,7 @@ func (s *DockerSuite) SetupSuite() {
 }
 
 func (s *DockerSuite) SetupTest() {
-	s.client = &dockerClientMock{
-		hasOpenPorts: true,
-	}
+	s.client = &dockerClientMock{}
 	s.manager = &dockerManager{
 		client: s.client,
 	}
@@ -73,6 +72,104 @@ func (s *DockerSuite) TestValidateSettings() {
 	// error when missing image url
 	settingsNoImageURL := &dockerSettings{}
 	s.EqualError(settingsNoImageURL.Validate(), "ImageURL must not be blank")
+
+	// error when an allowed digest is malformed
+	settingsBadDigest := &dockerSettings{
+		ImageURL:            "http://0.0.0.0:8000/docker_image.tgz",
+		AllowedImageDigests: []string{"abcdef"},
+	}
+	s.EqualError(settingsBadDigest.Validate(), "allowed image digest 'abcdef' must start with 'sha256:'")
+
+	// error when a ulimit is malformed
+	settingsBadUlimit := &dockerSettings{
+		ImageURL: "http://0.0.0.0:8000/docker_image.tgz",
+		Ulimits:  []string{"nofile"},
+	}
+	s.Error(settingsBadUlimit.Validate())
+
+	// error when the seccomp profile is not JSON
+	settingsBadSeccomp := &dockerSettings{
+		ImageURL:       "http://0.0.0.0:8000/docker_image.tgz",
+		SeccompProfile: "/etc/docker/seccomp.json",
+	}
+	s.EqualError(settingsBadSeccomp.Validate(), "seccomp profile must be valid JSON or 'unconfined'")
+
+	// hardening settings are valid
+	settingsHardened := &dockerSettings{
+		ImageURL:        "http://0.0.0.0:8000/docker_image.tgz",
+		Ulimits:         []string{"nofile=1024:2048", "nproc=512"},
+		CapDrop:         []string{"NET_RAW", "SYS_ADMIN"},
+		SeccompProfile:  `{"defaultAction": "SCMP_ACT_ERRNO"}`,
+		AppArmorProfile: "docker-default",
+	}
+	s.NoError(settingsHardened.Validate())
+}
+
+func (s *DockerSuite) TestHostConfig() {
+	settings := &dockerSettings{
+		ImageURL: "http://0.0.0.0:8000/docker_image.tgz",
+	}
+	hostConf, err := settings.hostConfig()
+	s.NoError(err)
+	s.Empty(hostConf.Ulimits)
+	s.Empty(hostConf.CapDrop)
+	s.Empty(hostConf.SecurityOpt)
+
+	settings.Ulimits = []string{"nofile=1024:2048"}
+	settings.CapDrop = []string{"NET_RAW"}
+	settings.SeccompProfile = seccompUnconfined
+	settings.AppArmorProfile = "docker-default"
+	hostConf, err = settings.hostConfig()
+	s.NoError(err)
+	s.Require().Len(hostConf.Ulimits, 1)
+	s.Equal("nofile", hostConf.Ulimits[0].Name)
+	s.EqualValues(1024, hostConf.Ulimits[0].Soft)
+	s.EqualValues(2048, hostConf.Ulimits[0].Hard)
+	s.Equal([]string{"NET_RAW"}, []string(hostConf.CapDrop))
+	s.Equal([]string{"seccomp=unconfined", "apparmor=docker-default"}, hostConf.SecurityOpt)
+}
+
+func (s *DockerSuite) TestVerifyImageDigest() {
+	settings := &dockerSettings{
+		ImageURL: "http://0.0.0.0:8000/docker_image.tgz",
+	}
+	s.NoError(settings.verifyImageDigest("sha256:anything"))
+
+	settings.AllowedImageDigests = []string{"sha256:allowed"}
+	s.NoError(settings.verifyImageDigest("sha256:allowed"))
+	s.EqualError(settings.verifyImageDigest("sha256:other"), "image digest 'sha256:other' is not in the allowed image digests")
+}
+
+func (s *DockerSuite) TestImageURLForParentOS() {
+	settings := &dockerSettings{
+		ImageURL: "http://0.0.0.0:8000/docker_image.tgz",
+	}
+	s.Equal(settings.ImageURL, settings.imageURL(false))
+	s.Equal(settings.ImageURL, settings.imageURL(true))
+
+	settings.WindowsImageURL = "http://0.0.0.0:8000/windows_image.tgz"
+	s.NoError(settings.Validate())
+	s.Equal(settings.ImageURL, settings.imageURL(false))
+	s.Equal(settings.WindowsImageURL, settings.imageURL(true))
+
+	settings.ImageURL = ""
+	s.NoError(settings.Validate())
+	s.Empty(settings.imageURL(false))
+
+	d := &distro.Distro{
+		Id: "container",
+		ProviderSettings: &map[string]interface{}{
+			"windows_image_url": settings.WindowsImageURL,
+		},
+	}
+	windowsParent := &host.Host{Id: "windows-parent", Distro: distro.Distro{Arch: "windows_amd64"}}
+	imageURL, err := ContainerImageURL(d, windowsParent)
+	s.NoError(err)
+	s.Equal(settings.WindowsImageURL, imageURL)
+
+	linuxParent := &host.Host{Id: "linux-parent", Distro: distro.Distro{Arch: "linux_amd64"}}
+	_, err = ContainerImageURL(d, linuxParent)
+	s.Error(err)
 }
 
 func (s *DockerSuite) TestConfigureAPICall() {
@@ -180,6 +277,61 @@ func (s *DockerSuite) TestTerminateInstanceDB() {
 	s.Error(err)
 }
 
+func (s *DockerSuite) TestTerminateInstanceCollectsArtifacts() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uploaded := []string{}
+	s.manager.uploader = func(localPath, key string) error {
+		uploaded = append(uploaded, key)
+		return nil
+	}
+
+	s.distro.WorkDir = "/data/mci"
+	myHost := NewIntent(s.distro, s.distro.GenerateName(), s.distro.Provider, s.hostOpts)
+	s.NoError(myHost.Insert())
+	myHost, err := s.manager.SpawnHost(ctx, myHost)
+	s.NoError(err)
+	_, err = myHost.Upsert()
+	s.NoError(err)
+
+	s.NoError(s.manager.TerminateInstance(ctx, myHost, evergreen.User))
+	s.Equal([]string{
+		"containers/" + myHost.Id + "/container.log",
+		"containers/" + myHost.Id + "/working_directory.tar",
+	}, uploaded)
+}
+
+func (s *DockerSuite) TestTerminateInstanceTeardownFailuresDoNotBlockRemoval() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	uploaded := []string{}
+	s.manager.uploader = func(localPath, key string) error {
+		uploaded = append(uploaded, key)
+		return nil
+	}
+
+	myHost := NewIntent(s.distro, s.distro.GenerateName(), s.distro.Provider, s.hostOpts)
+	s.NoError(myHost.Insert())
+	myHost, err := s.manager.SpawnHost(ctx, myHost)
+	s.NoError(err)
+	_, err = myHost.Upsert()
+	s.NoError(err)
+
+	mock, ok := s.client.(*dockerClientMock)
+	s.True(ok)
+	mock.failStop = true
+	mock.failLogs = true
+
+	s.NoError(s.manager.TerminateInstance(ctx, myHost, evergreen.User))
+	s.Empty(uploaded)
+
+	dbHost, err := host.FindOne(host.ById(myHost.Id))
+	s.NoError(err)
+	s.Equal(evergreen.HostTerminated, dbHost.Status)
+}
+
 func (s *DockerSuite) TestGetSSHOptions() {
 	opt := "Option"
 	keyname := "key"
@@ -266,6 +418,33 @@ func (s *DockerSuite) TestSpawnCreateAPICall() {
 	s.Nil(host)
 }
 
+func (s *DockerSuite) TestSpawnRecordsImageDigest() {
+	mock, ok := s.client.(*dockerClientMock)
+	s.True(ok)
+	mock.imageDigest = "sha256:allowed"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewIntent(s.distro, s.distro.GenerateName(), s.distro.Provider, s.hostOpts)
+	s.NoError(h.Insert())
+	h, err := s.manager.SpawnHost(ctx, h)
+	s.NoError(err)
+	s.Require().NotNil(h)
+	s.Equal("sha256:allowed", h.ImageDigest)
+
+	dbHost, err := host.FindOneId(h.Id)
+	s.NoError(err)
+	s.Equal("sha256:allowed", dbHost.ImageDigest)
+
+	(*s.distro.ProviderSettings)["allowed_image_digests"] = []string{"sha256:other"}
+	h = NewIntent(s.distro, s.distro.GenerateName(), s.distro.Provider, s.hostOpts)
+	s.NoError(h.Insert())
+	h, err = s.manager.SpawnHost(ctx, h)
+	s.Error(err)
+	s.Nil(h)
+}
+
 func (s *DockerSuite) TestSpawnStartRemoveAPICall() {
 	mock, ok := s.client.(*dockerClientMock)
 	s.True(ok)
@@ -300,6 +479,27 @@ func (s *DockerSuite) TestUtilToEvgStatus() {
 	s.Equal(StatusUnknown, toEvgStatus(&types.ContainerState{}))
 }
 
+func (s *DockerSuite) TestUtilToContainerStats() {
+	stats := &types.StatsJSON{}
+	stats.MemoryStats.Usage = 1024
+	stats.MemoryStats.Limit = 4096
+	stats.CPUStats.OnlineCPUs = 2
+	stats.CPUStats.CPUUsage.TotalUsage = 300
+	stats.CPUStats.SystemUsage = 2000
+	stats.PreCPUStats.CPUUsage.TotalUsage = 100
+	stats.PreCPUStats.SystemUsage = 1000
+
+	converted := toContainerStats(stats)
+	s.Equal(uint64(1024), converted.MemoryUsage)
+	s.Equal(uint64(4096), converted.MemoryLimit)
+	s.InDelta(40, converted.CPUPercent, 0.001)
+
+	// the first read has no previous usage to compare against
+	stats.PreCPUStats = types.CPUStats{}
+	stats.CPUStats.SystemUsage = 0
+	s.Zero(toContainerStats(stats).CPUPercent)
+}
+
 func (s *DockerSuite) TestSpawnDoesNotPanic() {
 	mock, ok := s.client.(*dockerClientMock)
 	s.True(ok)
@@ -402,3 +602,46 @@ func (s *DockerSuite) TestBuildContainerImageFailedBuild() {
 	err = s.manager.BuildContainerImage(ctx, parent, "image-url")
 	s.EqualError(err, "Failed to build image 'image-url' with agent on host 'parent': failed to build image with agent")
 }
+
+func (s *DockerSuite) TestGetDiskUsage() {
+	mock, ok := s.client.(*dockerClientMock)
+	s.True(ok)
+	mock.diskUsage = 1024
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parent, err := host.FindOneId("parent")
+	s.NoError(err)
+
+	usage, err := s.manager.GetDiskUsage(ctx, parent)
+	s.NoError(err)
+	s.Equal(int64(1024), usage)
+
+	mock.failUsage = true
+	_, err = s.manager.GetDiskUsage(ctx, parent)
+	s.EqualError(err, "Error getting disk usage on host 'parent': failed to get disk usage")
+}
+
+func (s *DockerSuite) TestPruneSystem() {
+	mock, ok := s.client.(*dockerClientMock)
+	s.True(ok)
+	mock.diskUsage = 2048
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	parent, err := host.FindOneId("parent")
+	s.NoError(err)
+
+	reclaimed, err := s.manager.PruneSystem(ctx, parent, 24*time.Hour)
+	s.NoError(err)
+	s.Equal(uint64(2048), reclaimed)
+
+	mock.failPrune = true
+	_, err = s.manager.PruneSystem(ctx, parent, 24*time.Hour)
+	s.EqualError(err, "Error pruning system on host 'parent': failed to prune system")
+
+	_, err = s.manager.PruneSystem(ctx, &host.Host{Id: "container"}, 24*time.Hour)
+	s.EqualError(err, "Error pruning system: 'container' is not a parent")
+}
@@ -3,9 +3,20 @@
 package cloud
 
 import (
+	"path"
+	"path/filepath"
+	"strings"
+
 	"github.com/docker/docker/api/types"
 )
 
+// imageNameFromURL returns the name of the image imported from the tarball at
+// the given URL.
+func imageNameFromURL(url string) string {
+	baseName := path.Base(url)
+	return strings.TrimSuffix(baseName, filepath.Ext(baseName))
+}
+
 // toEvgStatus converts a container state to an Evergreen cloud provider status.
 func toEvgStatus(s *types.ContainerState) CloudStatus {
 	if s.Running {
@@ -19,3 +30,26 @@ func toEvgStatus(s *types.ContainerState) CloudStatus {
 	}
 	return StatusUnknown
 }
+
+// toContainerStats converts Docker container stats to a runtime-agnostic
+// snapshot of the container's resource usage.
+func toContainerStats(s *types.StatsJSON) *ContainerStats {
+	stats := &ContainerStats{
+		MemoryUsage: s.MemoryStats.Usage,
+		MemoryLimit: s.MemoryStats.Limit,
+	}
+
+	// CPU usage is reported as cumulative counters, so the percentage is
+	// the container's share of the system's usage since the previous read
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	if cpuDelta > 0 && systemDelta > 0 {
+		cpus := float64(s.CPUStats.OnlineCPUs)
+		if cpus == 0 {
+			cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+		}
+		stats.CPUPercent = cpuDelta / systemDelta * cpus * 100
+	}
+
+	return stats
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -71,6 +72,48 @@ type EC2ProviderSettings struct {
 	// Region is the EC2 region in which the instance will start. If empty,
 	// the ec2Manager will spawn in "us-east-1".
 	Region string `mapstructure:"region" json:"region" bson:"region,omitempty"`
+
+	// Regions are the regions hosts may be spawned in instead of Region.
+	// Hosts are spawned in the regions with the lowest priority value that
+	// have capacity, spread evenly across regions of equal priority.
+	Regions []EC2RegionSettings `mapstructure:"regions" json:"regions,omitempty" bson:"regions,omitempty"`
+}
+
+// EC2RegionSettings are the settings of one of the regions a distro's hosts
+// may be spawned in. Settings that are left empty are taken from the
+// distro's, but AMIs, key pairs, subnets and security groups are usually
+// specific to a region.
+type EC2RegionSettings struct {
+	Region           string   `mapstructure:"region" json:"region" bson:"region"`
+	Priority         int      `mapstructure:"priority" json:"priority" bson:"priority"`
+	AMI              string   `mapstructure:"ami" json:"ami,omitempty" bson:"ami,omitempty"`
+	KeyName          string   `mapstructure:"key_name" json:"key_name,omitempty" bson:"key_name,omitempty"`
+	SubnetId         string   `mapstructure:"subnet_id" json:"subnet_id,omitempty" bson:"subnet_id,omitempty"`
+	SecurityGroupIDs []string `mapstructure:"security_group_ids" json:"security_group_ids,omitempty" bson:"security_group_ids,omitempty"`
+}
+
+// setRegion applies the settings of one of the regions.
+func (s *EC2ProviderSettings) setRegion(region string) error {
+	for _, r := range s.Regions {
+		if r.Region != region {
+			continue
+		}
+		s.Region = r.Region
+		if r.AMI != "" {
+			s.AMI = r.AMI
+		}
+		if r.KeyName != "" {
+			s.KeyName = r.KeyName
+		}
+		if r.SubnetId != "" {
+			s.SubnetId = r.SubnetId
+		}
+		if len(r.SecurityGroupIDs) != 0 {
+			s.SecurityGroupIDs = r.SecurityGroupIDs
+		}
+		return nil
+	}
+	return errors.Errorf("region '%s' is not one of the distro's regions", region)
 }
 
 // Validate that essential EC2ProviderSettings fields are not empty.
@@ -90,6 +133,19 @@ func (s *EC2ProviderSettings) Validate() error {
 	if _, err := makeBlockDeviceMappings(s.MountPoints); err != nil {
 		return errors.Wrap(err, "block device mappings invalid")
 	}
+	regions := map[string]bool{}
+	for _, r := range s.Regions {
+		if r.Region == "" {
+			return errors.New("regions must have names")
+		}
+		if regions[r.Region] {
+			return errors.Errorf("region '%s' is listed more than once", r.Region)
+		}
+		if r.Priority < 0 {
+			return errors.Errorf("priority of region '%s' must not be negative", r.Region)
+		}
+		regions[r.Region] = true
+	}
 	return nil
 }
 
@@ -362,6 +418,11 @@ func (m *ec2Manager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, e
 		"inputraw":  fmt.Sprintf("%#v", *h.Distro.ProviderSettings),
 		"outputraw": fmt.Sprintf("%#v", *ec2Settings),
 	})
+	if h.Region != "" && len(ec2Settings.Regions) != 0 {
+		if err := ec2Settings.setRegion(h.Region); err != nil {
+			return nil, errors.Wrapf(err, "Invalid region for host %s", h.Id)
+		}
+	}
 	if err := ec2Settings.Validate(); err != nil {
 		return nil, errors.Wrapf(err, "Invalid EC2 settings in distro %s: and %+v", h.Distro.Id, ec2Settings)
 	}
@@ -487,6 +548,13 @@ func (m *ec2Manager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, e
 	return h, nil
 }
 
+// isInterruptible returns whether the host is, or may be, a spot instance.
+// Hosts of distros that choose between spot and on-demand instances are
+// treated as spot instances until the choice is made.
+func (m *ec2Manager) isInterruptible(h *host.Host) (bool, error) {
+	return isHostSpot(h) || h.Distro.Provider == evergreen.ProviderNameEc2Auto, nil
+}
+
 func (m *ec2Manager) getKey(ctx context.Context, h *host.Host) (string, error) {
 	const keyPrefix = "evg_auto_"
 	t, err := task.FindOneId(h.StartedBy)
@@ -587,6 +655,9 @@ func (m *ec2Manager) GetInstanceStatuses(ctx context.Context, hosts []host.Host)
 }
 
 func getRegion(h *host.Host) (string, error) {
+	if h.Region != "" {
+		return h.Region, nil
+	}
 	ec2Settings := &EC2ProviderSettings{}
 	if h.Distro.ProviderSettings != nil {
 		if err := mapstructure.Decode(h.Distro.ProviderSettings, ec2Settings); err != nil {
@@ -600,6 +671,77 @@ func getRegion(h *host.Host) (string, error) {
 	return r, nil
 }
 
+// ec2CapacityErrorCodes are the codes of the errors EC2 returns when a
+// region does not have capacity for an instance.
+var ec2CapacityErrorCodes = []string{
+	"InsufficientInstanceCapacity",
+	"InstanceLimitExceeded",
+	"MaxSpotInstanceCountExceeded",
+	"SpotMaxPriceTooLow",
+	"Unsupported",
+}
+
+// regions returns the regions of the host's distro, in order of priority.
+// Regions of equal priority are ordered by how many up hosts the distro has
+// in them, so that hosts are spread evenly across them.
+func (m *ec2Manager) regions(h *host.Host) ([]string, error) {
+	ec2Settings := &EC2ProviderSettings{}
+	if h.Distro.ProviderSettings != nil {
+		if err := mapstructure.Decode(h.Distro.ProviderSettings, ec2Settings); err != nil {
+			return nil, errors.Wrapf(err, "Error decoding params for distro %s: %+v", h.Distro.Id, ec2Settings)
+		}
+	}
+	if len(ec2Settings.Regions) == 0 {
+		return nil, nil
+	}
+
+	// hosts spawned before the distro listed regions are in its region
+	defaultHostRegion := defaultRegion
+	if ec2Settings.Region != "" {
+		defaultHostRegion = ec2Settings.Region
+	}
+	numHosts := map[string]int{}
+	for _, r := range ec2Settings.Regions {
+		regions := []string{r.Region}
+		if r.Region == defaultHostRegion {
+			regions = append(regions, "")
+		}
+		n, err := host.CountUpHostsByDistroInRegions(h.Distro.Id, regions)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error counting hosts of distro %s in region %s", h.Distro.Id, r.Region)
+		}
+		numHosts[r.Region] = n
+	}
+
+	ordered := append([]EC2RegionSettings{}, ec2Settings.Regions...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+		return numHosts[ordered[i].Region] < numHosts[ordered[j].Region]
+	})
+	regions := make([]string, 0, len(ordered))
+	for _, r := range ordered {
+		regions = append(regions, r.Region)
+	}
+	return regions, nil
+}
+
+// isCapacityError returns whether EC2 failed to spawn a host because the
+// region did not have capacity for it.
+func (m *ec2Manager) isCapacityError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, code := range ec2CapacityErrorCodes {
+		if strings.Contains(msg, code) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetInstanceStatus returns the current status of an EC2 instance.
 func (m *ec2Manager) GetInstanceStatus(ctx context.Context, h *host.Host) (CloudStatus, error) {
 	r, err := getRegion(h)
@@ -1018,3 +1160,165 @@ func (m *ec2Manager) expandUserData(userData string) (string, error) {
 	}
 	return expanded, nil
 }
+
+const (
+	startInstanceRetries = 7
+	startInstancePeriod  = time.Second
+)
+
+// StopInstance stops an on-demand instance, keeping its volumes, and marks
+// the host stopped.
+func (m *ec2Manager) StopInstance(ctx context.Context, h *host.Host, user string) error {
+	if !isHostOnDemand(h) {
+		return errors.Errorf("host '%s' is not an on-demand instance, so it cannot be stopped", h.Id)
+	}
+	r, err := getRegion(h)
+	if err != nil {
+		return errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	_, err = m.client.StopInstances(ctx, &ec2.StopInstancesInput{
+		InstanceIds: []*string{aws.String(h.Id)},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error stopping instance '%s'", h.Id)
+	}
+	return errors.Wrap(h.SetStatus(evergreen.HostStopped, user, ""), "error marking host stopped")
+}
+
+// StartInstance starts a stopped on-demand instance and waits for it to run.
+// Since an instance's public DNS name changes when it starts, the host's DNS
+// name is updated before it is marked running.
+func (m *ec2Manager) StartInstance(ctx context.Context, h *host.Host, user string) error {
+	if !isHostOnDemand(h) {
+		return errors.Errorf("host '%s' is not an on-demand instance, so it cannot be started", h.Id)
+	}
+	r, err := getRegion(h)
+	if err != nil {
+		return errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	_, err = m.client.StartInstances(ctx, &ec2.StartInstancesInput{
+		InstanceIds: []*string{aws.String(h.Id)},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error starting instance '%s'", h.Id)
+	}
+
+	var instance *ec2.Instance
+	_, err = util.Retry(func() (bool, error) {
+		instance, err = m.client.GetInstanceInfo(ctx, h.Id)
+		if err != nil {
+			return false, errors.Wrap(err, "error getting instance info")
+		}
+		if ec2StatusToEvergreenStatus(*instance.State.Name) != StatusRunning {
+			return true, errors.Errorf("instance '%s' is not running yet", h.Id)
+		}
+		return false, nil
+	}, startInstanceRetries, startInstancePeriod)
+	if err != nil {
+		return errors.Wrapf(err, "error waiting for instance '%s' to start", h.Id)
+	}
+
+	if err = h.UpdateDNSName(aws.StringValue(instance.PublicDnsName)); err != nil {
+		return errors.Wrap(err, "error updating host DNS name")
+	}
+	return errors.Wrap(h.SetStatus(evergreen.HostRunning, user, ""), "error marking host running")
+}
+
+// AttachVolume attaches an EBS volume to the host's instance.
+func (m *ec2Manager) AttachVolume(ctx context.Context, h *host.Host, attachment host.VolumeAttachment) error {
+	r, err := getRegion(h)
+	if err != nil {
+		return errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	instanceId := h.Id
+	if isHostSpot(h) {
+		instanceId, err = m.client.GetSpotInstanceId(ctx, h)
+		if err != nil {
+			return errors.Wrapf(err, "error getting instance ID of host '%s'", h.Id)
+		}
+	}
+
+	_, err = m.client.AttachVolume(ctx, &ec2.AttachVolumeInput{
+		InstanceId: aws.String(instanceId),
+		VolumeId:   aws.String(attachment.VolumeID),
+		Device:     aws.String(attachment.DeviceName),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error attaching volume '%s' to host '%s'", attachment.VolumeID, h.Id)
+	}
+	return errors.Wrap(h.AddVolume(attachment), "error recording volume attachment")
+}
+
+// DetachVolume detaches an EBS volume from the host's instance.
+func (m *ec2Manager) DetachVolume(ctx context.Context, h *host.Host, volumeID string) error {
+	r, err := getRegion(h)
+	if err != nil {
+		return errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	instanceId := h.Id
+	if isHostSpot(h) {
+		instanceId, err = m.client.GetSpotInstanceId(ctx, h)
+		if err != nil {
+			return errors.Wrapf(err, "error getting instance ID of host '%s'", h.Id)
+		}
+	}
+
+	_, err = m.client.DetachVolume(ctx, &ec2.DetachVolumeInput{
+		InstanceId: aws.String(instanceId),
+		VolumeId:   aws.String(volumeID),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error detaching volume '%s' from host '%s'", volumeID, h.Id)
+	}
+	return errors.Wrap(h.RemoveVolume(volumeID), "error recording volume detachment")
+}
+
+// GetVolumeOwner returns the owner tag of an EBS volume, which volumes are
+// given along with the spawn hosts they're created with.
+func (m *ec2Manager) GetVolumeOwner(ctx context.Context, h *host.Host, volumeID string) (string, error) {
+	r, err := getRegion(h)
+	if err != nil {
+		return "", errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return "", errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	out, err := m.client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+		VolumeIds: []*string{aws.String(volumeID)},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "error describing volume '%s'", volumeID)
+	}
+	if out == nil || len(out.Volumes) == 0 {
+		return "", errors.Errorf("volume '%s' not found", volumeID)
+	}
+	for _, tag := range out.Volumes[0].Tags {
+		if aws.StringValue(tag.Key) == "owner" {
+			return aws.StringValue(tag.Value), nil
+		}
+	}
+
+	return "", nil
+}
@@ -41,6 +41,12 @@ type AWSClient interface {
 	// TerminateInstances is a wrapper for ec2.TerminateInstances.
 	TerminateInstances(context.Context, *ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error)
 
+	// StopInstances is a wrapper for ec2.StopInstances.
+	StopInstances(context.Context, *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error)
+
+	// StartInstances is a wrapper for ec2.StartInstances.
+	StartInstances(context.Context, *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error)
+
 	// RequestSpotInstances is a wrapper for ec2.RequestSpotInstances.
 	RequestSpotInstances(context.Context, *ec2.RequestSpotInstancesInput) (*ec2.RequestSpotInstancesOutput, error)
 
@@ -59,6 +65,12 @@ type AWSClient interface {
 	// DescribeVolumes is a wrapper for ec2.DescribeVolumes.
 	DescribeVolumes(context.Context, *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
 
+	// AttachVolume is a wrapper for ec2.AttachVolume.
+	AttachVolume(context.Context, *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error)
+
+	// DetachVolume is a wrapper for ec2.DetachVolume.
+	DetachVolume(context.Context, *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error)
+
 	// DescribeSpotPriceHistory is a wrapper for ec2.DescribeSpotPriceHistory.
 	DescribeSpotPriceHistory(context.Context, *ec2.DescribeSpotPriceHistoryInput) (*ec2.DescribeSpotPriceHistoryOutput, error)
 
@@ -76,6 +88,18 @@ type AWSClient interface {
 
 	// DeleteKeyPair is a wrapper for ec2.DeleteKeyPairWithContext.
 	DeleteKeyPair(context.Context, *ec2.DeleteKeyPairInput) (*ec2.DeleteKeyPairOutput, error)
+
+	// CreateImage is a wrapper for ec2.CreateImageWithContext.
+	CreateImage(context.Context, *ec2.CreateImageInput) (*ec2.CreateImageOutput, error)
+
+	// DescribeImages is a wrapper for ec2.DescribeImagesWithContext.
+	DescribeImages(context.Context, *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error)
+
+	// DeregisterImage is a wrapper for ec2.DeregisterImageWithContext.
+	DeregisterImage(context.Context, *ec2.DeregisterImageInput) (*ec2.DeregisterImageOutput, error)
+
+	// DeleteSnapshot is a wrapper for ec2.DeleteSnapshotWithContext.
+	DeleteSnapshot(context.Context, *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error)
 }
 
 // awsClientImpl wraps ec2.EC2.
@@ -513,8 +537,193 @@ func (c *awsClientImpl) DeleteKeyPair(ctx context.Context, input *ec2.DeleteKeyP
 	return output, nil
 }
 
+// CreateImage is a wrapper for ec2.CreateImage.
+func (c *awsClientImpl) CreateImage(ctx context.Context, input *ec2.CreateImageInput) (*ec2.CreateImageOutput, error) {
+	var output *ec2.CreateImageOutput
+	var err error
+	msg := makeAWSLogMessage("CreateImage", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.CreateImageWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// DescribeImages is a wrapper for ec2.DescribeImages.
+func (c *awsClientImpl) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	var output *ec2.DescribeImagesOutput
+	var err error
+	msg := makeAWSLogMessage("DescribeImages", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.DescribeImagesWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// DeregisterImage is a wrapper for ec2.DeregisterImage.
+func (c *awsClientImpl) DeregisterImage(ctx context.Context, input *ec2.DeregisterImageInput) (*ec2.DeregisterImageOutput, error) {
+	var output *ec2.DeregisterImageOutput
+	var err error
+	msg := makeAWSLogMessage("DeregisterImage", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.DeregisterImageWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// DeleteSnapshot is a wrapper for ec2.DeleteSnapshot.
+func (c *awsClientImpl) DeleteSnapshot(ctx context.Context, input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	var output *ec2.DeleteSnapshotOutput
+	var err error
+	msg := makeAWSLogMessage("DeleteSnapshot", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.DeleteSnapshotWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// StopInstances is a wrapper for ec2.StopInstances.
+func (c *awsClientImpl) StopInstances(ctx context.Context, input *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	var output *ec2.StopInstancesOutput
+	var err error
+	msg := makeAWSLogMessage("StopInstances", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.StopInstancesWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// StartInstances is a wrapper for ec2.StartInstances.
+func (c *awsClientImpl) StartInstances(ctx context.Context, input *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error) {
+	var output *ec2.StartInstancesOutput
+	var err error
+	msg := makeAWSLogMessage("StartInstances", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.StartInstancesWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// AttachVolume is a wrapper for ec2.AttachVolume.
+func (c *awsClientImpl) AttachVolume(ctx context.Context, input *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+	var output *ec2.VolumeAttachment
+	var err error
+	msg := makeAWSLogMessage("AttachVolume", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.AttachVolumeWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// DetachVolume is a wrapper for ec2.DetachVolume.
+func (c *awsClientImpl) DetachVolume(ctx context.Context, input *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error) {
+	var output *ec2.VolumeAttachment
+	var err error
+	msg := makeAWSLogMessage("DetachVolume", fmt.Sprintf("%T", c), input)
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.DetachVolumeWithContext(ctx, input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, msg))
+				}
+				return true, err
+			}
+			grip.Info(msg)
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
 // awsClientMock mocks ec2.EC2.
 type awsClientMock struct { //nolint
+	region string
 	*credentials.Credentials
 	*ec2.RunInstancesInput
 	*ec2.DescribeInstancesInput
@@ -529,14 +738,27 @@ type awsClientMock struct { //nolint
 	*ec2.DescribeVpcsInput
 	*ec2.CreateKeyPairInput
 	*ec2.DeleteKeyPairInput
+	*ec2.CreateImageInput
+	*ec2.DescribeImagesInput
+	*ec2.DeregisterImageInput
+	*ec2.DeleteSnapshotInput
+	*ec2.StopInstancesInput
+	*ec2.StartInstancesInput
+	*ec2.AttachVolumeInput
+	*ec2.DetachVolumeInput
 
 	*ec2.DescribeSpotInstanceRequestsOutput
 	*ec2.DescribeInstancesOutput
+	*ec2.DescribeImagesOutput
+
+	// Volumes are returned by DescribeVolumes.
+	Volumes []*ec2.Volume
 }
 
 // Create a new mock client.
 func (c *awsClientMock) Create(creds *credentials.Credentials, region string) error {
 	c.Credentials = creds
+	c.region = region
 	return nil
 }
 
@@ -674,7 +896,7 @@ func (c *awsClientMock) CancelSpotInstanceRequests(ctx context.Context, input *e
 // DescribeVolumes is a mock for ec2.DescribeVolumes.
 func (c *awsClientMock) DescribeVolumes(ctx context.Context, input *ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error) {
 	c.DescribeVolumesInput = input
-	return &ec2.DescribeVolumesOutput{}, nil
+	return &ec2.DescribeVolumesOutput{Volumes: c.Volumes}, nil
 }
 
 // DescribeSpotPriceHistory is a mock for ec2.DescribeSpotPriceHistory.
@@ -739,6 +961,59 @@ func (c *awsClientMock) DeleteKeyPair(ctx context.Context, input *ec2.DeleteKeyP
 	return &ec2.DeleteKeyPairOutput{}, nil
 }
 
+// CreateImage is a mock for ec2.CreateImage.
+func (c *awsClientMock) CreateImage(ctx context.Context, input *ec2.CreateImageInput) (*ec2.CreateImageOutput, error) {
+	c.CreateImageInput = input
+	return &ec2.CreateImageOutput{
+		ImageId: aws.String("ami-baked"),
+	}, nil
+}
+
+// DescribeImages is a mock for ec2.DescribeImages.
+func (c *awsClientMock) DescribeImages(ctx context.Context, input *ec2.DescribeImagesInput) (*ec2.DescribeImagesOutput, error) {
+	c.DescribeImagesInput = input
+	if c.DescribeImagesOutput != nil {
+		return c.DescribeImagesOutput, nil
+	}
+	return &ec2.DescribeImagesOutput{}, nil
+}
+
+// DeregisterImage is a mock for ec2.DeregisterImage.
+func (c *awsClientMock) DeregisterImage(ctx context.Context, input *ec2.DeregisterImageInput) (*ec2.DeregisterImageOutput, error) {
+	c.DeregisterImageInput = input
+	return &ec2.DeregisterImageOutput{}, nil
+}
+
+// DeleteSnapshot is a mock for ec2.DeleteSnapshot.
+func (c *awsClientMock) DeleteSnapshot(ctx context.Context, input *ec2.DeleteSnapshotInput) (*ec2.DeleteSnapshotOutput, error) {
+	c.DeleteSnapshotInput = input
+	return &ec2.DeleteSnapshotOutput{}, nil
+}
+
+// StopInstances is a mock for ec2.StopInstances.
+func (c *awsClientMock) StopInstances(ctx context.Context, input *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	c.StopInstancesInput = input
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+// StartInstances is a mock for ec2.StartInstances.
+func (c *awsClientMock) StartInstances(ctx context.Context, input *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error) {
+	c.StartInstancesInput = input
+	return &ec2.StartInstancesOutput{}, nil
+}
+
+// AttachVolume is a mock for ec2.AttachVolume.
+func (c *awsClientMock) AttachVolume(ctx context.Context, input *ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error) {
+	c.AttachVolumeInput = input
+	return &ec2.VolumeAttachment{VolumeId: input.VolumeId, InstanceId: input.InstanceId, Device: input.Device}, nil
+}
+
+// DetachVolume is a mock for ec2.DetachVolume.
+func (c *awsClientMock) DetachVolume(ctx context.Context, input *ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error) {
+	c.DetachVolumeInput = input
+	return &ec2.VolumeAttachment{VolumeId: input.VolumeId, InstanceId: input.InstanceId}, nil
+}
+
 func makeAWSLogMessage(name, client string, args interface{}) message.Fields {
 	return message.Fields{
 		"message":  "AWS API call",
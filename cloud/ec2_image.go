@@ -0,0 +1,169 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// bakedImagePoolTag is the tag identifying the container pool an image was
+// baked for.
+const bakedImagePoolTag = "evergreen-container-pool"
+
+// BakeImage creates an AMI from a running parent host, which includes Docker
+// and any container images already pulled onto the parent. EC2 shuts the
+// instance down before taking the snapshot, so that the image's file system
+// is consistent, and starts it again afterwards, so the parent must be
+// drained of containers first.
+func (m *ec2Manager) BakeImage(ctx context.Context, h *host.Host, poolID string) (string, error) {
+	if !h.HasContainers {
+		return "", errors.Errorf("Error baking image: '%s' is not a parent", h.Id)
+	}
+
+	r, err := getRegion(h)
+	if err != nil {
+		return "", errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return "", errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	instanceID := h.Id
+	if isHostSpot(h) {
+		instanceID, err = m.client.GetSpotInstanceId(ctx, h)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to get spot request info for %s", h.Id)
+		}
+		if instanceID == "" {
+			return "", errors.Errorf("spot request for %s has no instance", h.Id)
+		}
+	}
+
+	resp, err := m.client.CreateImage(ctx, &ec2.CreateImageInput{
+		InstanceId:  aws.String(instanceID),
+		Name:        aws.String(fmt.Sprintf("evergreen-%s-%d", poolID, time.Now().Unix())),
+		Description: aws.String(fmt.Sprintf("parent image for container pool '%s' baked from %s", poolID, h.Id)),
+		NoReboot:    aws.Bool(false),
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating image from %s", h.Id)
+	}
+	if resp == nil || resp.ImageId == nil {
+		return "", errors.Errorf("no image created from %s", h.Id)
+	}
+
+	_, err = m.client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []*string{resp.ImageId},
+		Tags: []*ec2.Tag{
+			&ec2.Tag{Key: aws.String(bakedImagePoolTag), Value: aws.String(poolID)},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "error tagging image %s", *resp.ImageId)
+	}
+
+	grip.Info(message.Fields{
+		"message": "started baking parent image",
+		"host":    h.Id,
+		"pool":    poolID,
+		"image":   *resp.ImageId,
+	})
+
+	return *resp.ImageId, nil
+}
+
+// GetBakedImages returns the AMIs owned by this account that were baked for
+// the container pool.
+func (m *ec2Manager) GetBakedImages(ctx context.Context, h *host.Host, poolID string) ([]BakedImage, error) {
+	r, err := getRegion(h)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return nil, errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	resp, err := m.client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners: []*string{aws.String("self")},
+		Filters: []*ec2.Filter{
+			&ec2.Filter{
+				Name:   aws.String(fmt.Sprintf("tag:%s", bakedImagePoolTag)),
+				Values: []*string{aws.String(poolID)},
+			},
+		},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error describing images for pool '%s'", poolID)
+	}
+
+	images := []BakedImage{}
+	for _, image := range resp.Images {
+		if image == nil || image.ImageId == nil {
+			continue
+		}
+		baked := BakedImage{
+			ID:    *image.ImageId,
+			State: aws.StringValue(image.State),
+		}
+		for _, mapping := range image.BlockDeviceMappings {
+			if mapping != nil && mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+				baked.SnapshotIDs = append(baked.SnapshotIDs, *mapping.Ebs.SnapshotId)
+			}
+		}
+		if image.CreationDate != nil {
+			baked.CreatedAt, err = time.Parse(time.RFC3339, *image.CreationDate)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error parsing creation date of image %s", *image.ImageId)
+			}
+		}
+		images = append(images, baked)
+	}
+
+	return images, nil
+}
+
+// DeleteBakedImage deregisters the AMI and deletes its EBS snapshots, which
+// would otherwise be kept, and billed for, after the AMI is gone.
+func (m *ec2Manager) DeleteBakedImage(ctx context.Context, h *host.Host, image BakedImage) error {
+	r, err := getRegion(h)
+	if err != nil {
+		return errors.Wrap(err, "problem getting region from host")
+	}
+	if err = m.client.Create(m.credentials, r); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	_, err = m.client.DeregisterImage(ctx, &ec2.DeregisterImageInput{
+		ImageId: aws.String(image.ID),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error deregistering image %s", image.ID)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, snapshotID := range image.SnapshotIDs {
+		_, err = m.client.DeleteSnapshot(ctx, &ec2.DeleteSnapshotInput{
+			SnapshotId: aws.String(snapshotID),
+		})
+		catcher.Add(errors.Wrapf(err, "error deleting snapshot %s of image %s", snapshotID, image.ID))
+	}
+
+	grip.Info(message.Fields{
+		"message":   "deleted baked parent image",
+		"host":      h.Id,
+		"image":     image.ID,
+		"snapshots": image.SnapshotIDs,
+	})
+
+	return catcher.Resolve()
+}
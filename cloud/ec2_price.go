@@ -221,7 +221,7 @@ func (cpf *cachingPriceFetcher) getLatestLowestSpotCostForInstance(ctx context.C
 }
 
 func (m *ec2Manager) getProvider(ctx context.Context, h *host.Host, ec2settings *EC2ProviderSettings) (ec2ProviderType, error) {
-	if h.UserHost {
+	if h.UserHost || h.CapacityFallback {
 		h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
 		return onDemandProvider, nil
 	}
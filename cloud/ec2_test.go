@@ -16,6 +16,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -111,6 +112,99 @@ func (s *EC2Suite) TestValidateProviderSettings() {
 	s.Error(p.Validate())
 	p.SubnetId = "subnet-123456"
 	s.NoError(p.Validate())
+
+	p.Regions = []EC2RegionSettings{{Region: "us-east-1"}, {Region: "us-west-2", Priority: 1}}
+	s.NoError(p.Validate())
+	p.Regions[1].Region = ""
+	s.Error(p.Validate())
+	p.Regions[1].Region = "us-east-1"
+	s.Error(p.Validate())
+	p.Regions[1].Region = "us-west-2"
+	p.Regions[1].Priority = -1
+	s.Error(p.Validate())
+}
+
+func (s *EC2Suite) TestSetRegion() {
+	p := &EC2ProviderSettings{
+		AMI:              "ami",
+		KeyName:          "keyName",
+		SecurityGroupIDs: []string{"sg-123456"},
+		SubnetId:         "subnet-123456",
+		Regions: []EC2RegionSettings{
+			{Region: "us-east-1"},
+			{Region: "us-west-2", AMI: "ami-west", SubnetId: "subnet-west"},
+		},
+	}
+	s.Error(p.setRegion("eu-west-1"))
+
+	s.NoError(p.setRegion("us-west-2"))
+	s.Equal("us-west-2", p.Region)
+	s.Equal("ami-west", p.AMI)
+	s.Equal("keyName", p.KeyName)
+	s.Equal([]string{"sg-123456"}, p.SecurityGroupIDs)
+	s.Equal("subnet-west", p.SubnetId)
+}
+
+func (s *EC2Suite) TestRegions() {
+	h := &host.Host{}
+	h.Distro.Id = "distro_id"
+	h.Distro.ProviderSettings = &map[string]interface{}{}
+	regions, err := s.impl.regions(h)
+	s.NoError(err)
+	s.Empty(regions)
+
+	h.Distro.ProviderSettings = &map[string]interface{}{
+		"regions": []map[string]interface{}{
+			{"region": "eu-west-1", "priority": 1},
+			{"region": "us-east-1"},
+			{"region": "us-west-2"},
+		},
+	}
+	s.NoError((&host.Host{Id: "h1", Distro: h.Distro, Status: evergreen.HostRunning}).Insert())
+	s.NoError((&host.Host{Id: "h2", Distro: h.Distro, Status: evergreen.HostRunning, Region: "us-west-2"}).Insert())
+	s.NoError((&host.Host{Id: "h3", Distro: h.Distro, Status: evergreen.HostRunning, Region: "us-west-2"}).Insert())
+	regions, err = s.impl.regions(h)
+	s.NoError(err)
+	s.Equal([]string{"us-east-1", "us-west-2", "eu-west-1"}, regions)
+
+	s.NoError((&host.Host{Id: "h4", Distro: h.Distro, Status: evergreen.HostRunning}).Insert())
+	s.NoError((&host.Host{Id: "h5", Distro: h.Distro, Status: evergreen.HostRunning, Region: "us-east-1"}).Insert())
+	regions, err = s.impl.regions(h)
+	s.NoError(err)
+	s.Equal([]string{"us-west-2", "us-east-1", "eu-west-1"}, regions)
+}
+
+func (s *EC2Suite) TestIsCapacityError() {
+	s.False(s.impl.isCapacityError(nil))
+	s.False(s.impl.isCapacityError(errors.New("InvalidAMIID.NotFound: the AMI does not exist")))
+	s.True(s.impl.isCapacityError(errors.New("InsufficientInstanceCapacity: there is no capacity")))
+}
+
+func (s *EC2Suite) TestSpawnHostInRegion() {
+	h := &host.Host{Region: "us-west-2"}
+	h.Distro.Id = "distro_id"
+	h.Distro.Provider = evergreen.ProviderNameEc2OnDemand
+	h.Distro.ProviderSettings = &map[string]interface{}{
+		"ami":                "ami",
+		"instance_type":      "instanceType",
+		"key_name":           "keyName",
+		"security_group_ids": []string{"sg-123456"},
+		"regions": []map[string]interface{}{
+			{"region": "us-east-1"},
+			{"region": "us-west-2", "ami": "ami-west"},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := s.onDemandManager.SpawnHost(ctx, h)
+	s.NoError(err)
+
+	mock, ok := s.impl.client.(*awsClientMock)
+	s.Require().True(ok)
+	s.Equal("us-west-2", mock.region)
+	s.Equal("ami-west", *mock.RunInstancesInput.ImageId)
 }
 
 func (s *EC2Suite) TestMakeDeviceMappings() {
@@ -588,6 +682,113 @@ func (s *EC2Suite) TestTerminateInstance() {
 	s.NoError(err)
 }
 
+func (s *EC2Suite) TestBakeImage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	manager, ok := s.onDemandManager.(*ec2Manager)
+	s.True(ok)
+	mock, ok := manager.client.(*awsClientMock)
+	s.True(ok)
+
+	h := &host.Host{Id: "i-parent"}
+	_, err := manager.BakeImage(ctx, h, "pool")
+	s.Error(err)
+
+	h.HasContainers = true
+	imageID, err := manager.BakeImage(ctx, h, "pool")
+	s.NoError(err)
+	s.Equal("ami-baked", imageID)
+	s.Equal("i-parent", *mock.CreateImageInput.InstanceId)
+	s.False(*mock.CreateImageInput.NoReboot)
+	s.Equal("ami-baked", *mock.CreateTagsInput.Resources[0])
+	s.Equal(bakedImagePoolTag, *mock.CreateTagsInput.Tags[0].Key)
+	s.Equal("pool", *mock.CreateTagsInput.Tags[0].Value)
+}
+
+func (s *EC2Suite) TestGetBakedImages() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	manager, ok := s.onDemandManager.(*ec2Manager)
+	s.True(ok)
+	mock, ok := manager.client.(*awsClientMock)
+	s.True(ok)
+	mock.DescribeImagesOutput = &ec2.DescribeImagesOutput{
+		Images: []*ec2.Image{
+			&ec2.Image{
+				ImageId:      aws.String("ami-1"),
+				State:        aws.String(ec2.ImageStateAvailable),
+				CreationDate: aws.String("2018-07-01T12:00:00.000Z"),
+				BlockDeviceMappings: []*ec2.BlockDeviceMapping{
+					&ec2.BlockDeviceMapping{Ebs: &ec2.EbsBlockDevice{SnapshotId: aws.String("snap-1")}},
+				},
+			},
+			&ec2.Image{
+				ImageId: aws.String("ami-2"),
+				State:   aws.String(ec2.ImageStatePending),
+			},
+		},
+	}
+
+	images, err := manager.GetBakedImages(ctx, &host.Host{Id: "i-parent"}, "pool")
+	s.NoError(err)
+	s.Require().Len(images, 2)
+	s.Equal("ami-1", images[0].ID)
+	s.Equal(ec2.ImageStateAvailable, images[0].State)
+	s.Equal(2018, images[0].CreatedAt.Year())
+	s.Equal([]string{"snap-1"}, images[0].SnapshotIDs)
+	s.Equal("ami-2", images[1].ID)
+	s.True(images[1].CreatedAt.IsZero())
+	s.Equal("pool", *mock.DescribeImagesInput.Filters[0].Values[0])
+}
+
+func (s *EC2Suite) TestDeleteBakedImage() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	manager, ok := s.onDemandManager.(*ec2Manager)
+	s.True(ok)
+	mock, ok := manager.client.(*awsClientMock)
+	s.True(ok)
+
+	image := BakedImage{ID: "ami-1", SnapshotIDs: []string{"snap-1"}}
+	s.NoError(manager.DeleteBakedImage(ctx, &host.Host{Id: "i-parent"}, image))
+	s.Equal("ami-1", *mock.DeregisterImageInput.ImageId)
+	s.Equal("snap-1", *mock.DeleteSnapshotInput.SnapshotId)
+}
+
+func (s *EC2Suite) TestGetVolumeOwner() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	manager, ok := s.onDemandManager.(*ec2Manager)
+	s.True(ok)
+	mock, ok := manager.client.(*awsClientMock)
+	s.True(ok)
+
+	h := &host.Host{Id: "i-spawn"}
+	_, err := manager.GetVolumeOwner(ctx, h, "vol-1")
+	s.Error(err)
+
+	mock.Volumes = []*ec2.Volume{{
+		VolumeId: aws.String("vol-1"),
+		Tags: []*ec2.Tag{
+			{Key: aws.String("name"), Value: aws.String("i-spawn")},
+			{Key: aws.String("owner"), Value: aws.String("user0")},
+		},
+	}}
+	owner, err := manager.GetVolumeOwner(ctx, h, "vol-1")
+	s.NoError(err)
+	s.Equal("user0", owner)
+	s.Equal("vol-1", *mock.DescribeVolumesInput.VolumeIds[0])
+
+	mock.Volumes[0].Tags = nil
+	owner, err = manager.GetVolumeOwner(ctx, h, "vol-1")
+	s.NoError(err)
+	s.Empty(owner)
+}
+
 func (s *EC2Suite) TestIsUp() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -890,6 +1091,11 @@ func (s *EC2Suite) TestGetRegion() {
 	r, err = getRegion(h)
 	s.NoError(err)
 	s.Equal("us-west-2", r)
+
+	h.Region = "eu-west-1"
+	r, err = getRegion(h)
+	s.NoError(err)
+	s.Equal("eu-west-1", r)
 }
 
 func (s *EC2Suite) TestUserDataExpand() {
@@ -46,6 +46,23 @@ type GCESettings struct {
 	// By default, GCE uses project-wide SSH keys. Project-wide keys should be manually
 	// added to the project metadata. These SSH keys are optional instance-wide keys.
 	SSHKeys sshKeyGroup `mapstructure:"ssh_keys"`
+
+	// InstanceTemplate is an optional instance template to create instances
+	// from. Machine, image, and disk settings override the template's.
+	InstanceTemplate string `mapstructure:"instance_template"`
+
+	// Preemptible instances are cheaper, but may be stopped by GCE at any time.
+	Preemptible bool `mapstructure:"preemptible"`
+
+	// Network and Subnetwork are the VPC network and subnetwork to attach
+	// instances to, instead of the project's default network.
+	Network    string `mapstructure:"network"`
+	Subnetwork string `mapstructure:"subnetwork"`
+
+	// FirewallSourceRanges are the CIDR ranges allowed to reach the Docker
+	// daemon on container pool parents. If empty, the firewall must be
+	// configured manually using the network tags.
+	FirewallSourceRanges []string `mapstructure:"firewall_source_ranges"`
 }
 
 // Validate verifies a set of GCESettings.
@@ -54,6 +71,18 @@ func (opts *GCESettings) Validate() error {
 	standardMachine := opts.MachineName != ""
 	customMachine := opts.NumCPUs > 0 && opts.MemoryMB > 0
 
+	if opts.InstanceTemplate != "" {
+		if standardMachine && customMachine {
+			return errors.New("Must not specify both machine type and num CPUs and memory")
+		}
+
+		if opts.ImageFamily != "" && opts.ImageName != "" {
+			return errors.New("Must not specify both image family and image name")
+		}
+
+		return nil
+	}
+
 	if standardMachine == customMachine {
 		return errors.New("Must specify either machine type OR num CPUs and memory")
 	}
@@ -119,6 +148,12 @@ func (m *gceManager) Configure(ctx context.Context, s *evergreen.Settings) error
 //
 //     - NetworkTags: (optional) security groups
 //     - SSHKeys:     username-key pairs
+//
+//     - InstanceTemplate:     (optional) template providing the settings above
+//     - Preemptible:          (optional) whether the instance is preemptible
+//     - Network, Subnetwork:  (optional) VPC network and subnetwork
+//     - FirewallSourceRanges: (optional) CIDR ranges allowed to reach the
+//                             Docker daemon on container pool parents
 func (m *gceManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, error) {
 	if h.Distro.Provider != ProviderName {
 		return nil, errors.Errorf("Can't spawn instance of %s for distro %s: provider is %s",
@@ -137,12 +172,27 @@ func (m *gceManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, e
 
 	grip.Debugf("Settings validated for distro %s", h.Distro.Id)
 
+	// Hosts falling back from preemptible capacity run on regular instances.
+	if h.CapacityFallback {
+		s.Preemptible = false
+	}
+
 	// Proactively record all information about the host we want to create. This way, if we are
 	// unable to start it or record its instance ID, we have a way of knowing what went wrong.
 	// the document is updated later in hostinit, rather than here
 	h.Zone = s.Zone
 	h.Project = s.Project
 
+	if h.HasContainers && h.ContainerPoolSettings != nil {
+		if err := m.configureParentNetwork(h, s); err != nil {
+			if rmErr := h.Remove(); rmErr != nil {
+				grip.Errorf("Could not remove intent host '%s': %+v", h.Id, rmErr)
+			}
+			return nil, errors.Wrapf(err, "Could not configure network for parent of container pool '%s'",
+				h.ContainerPoolSettings.Id)
+		}
+	}
+
 	// Start the instance, and remove the intent host document if unsuccessful.
 	if _, err := m.client.CreateInstance(h, s); err != nil {
 		if rmErr := h.Remove(); rmErr != nil {
@@ -157,6 +207,36 @@ func (m *gceManager) SpawnHost(ctx context.Context, h *host.Host) (*host.Host, e
 	return h, nil
 }
 
+// isInterruptible returns whether the host is a preemptible instance.
+func (m *gceManager) isInterruptible(h *host.Host) (bool, error) {
+	s := &GCESettings{}
+	if h.Distro.ProviderSettings != nil {
+		if err := mapstructure.Decode(h.Distro.ProviderSettings, s); err != nil {
+			return false, errors.Wrapf(err, "Error decoding params for distro %s", h.Distro.Id)
+		}
+	}
+	return s.Preemptible, nil
+}
+
+// configureParentNetwork tags a container pool parent with the pool's network
+// tag and, if source ranges are configured, ensures that a firewall rule
+// allows them to reach the parent's Docker daemon.
+func (m *gceManager) configureParentNetwork(h *host.Host, s *GCESettings) error {
+	tag := makeContainerPoolNetworkTag(h.ContainerPoolSettings.Id)
+	s.NetworkTags = append(s.NetworkTags, tag)
+
+	if len(s.FirewallSourceRanges) == 0 {
+		return nil
+	}
+
+	firewall := makeContainerPoolFirewall(h.ContainerPoolSettings, s.Network, tag, s.FirewallSourceRanges)
+	if err := m.client.EnsureFirewall(h.Project, firewall); err != nil {
+		return errors.Wrapf(err, "error ensuring firewall '%s'", firewall.Name)
+	}
+
+	return nil
+}
+
 // GetInstanceStatus gets the current operational status of the provisioned host,
 func (m *gceManager) GetInstanceStatus(ctx context.Context, host *host.Host) (CloudStatus, error) {
 	instance, err := m.client.GetInstance(host)
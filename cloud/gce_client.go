@@ -4,6 +4,7 @@ package cloud
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/mongodb/grip"
@@ -12,6 +13,7 @@ import (
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/jwt"
 	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 )
 
 const (
@@ -24,10 +26,13 @@ type gceClient interface {
 	CreateInstance(*host.Host, *GCESettings) (string, error)
 	GetInstance(*host.Host) (*compute.Instance, error)
 	DeleteInstance(*host.Host) error
+	EnsureFirewall(string, *compute.Firewall) error
 }
 
 type gceClientImpl struct {
-	InstancesService *compute.InstancesService
+	InstancesService         *compute.InstancesService
+	InstanceTemplatesService *compute.InstanceTemplatesService
+	FirewallsService         *compute.FirewallsService
 }
 
 // Init establishes a connection to a Google Compute endpoint and creates a gceClient that
@@ -47,6 +52,8 @@ func (c *gceClientImpl) Init(ctx context.Context, config *jwt.Config) error {
 
 	// Get a handle to a specific service for instance configuration.
 	c.InstancesService = compute.NewInstancesService(service)
+	c.InstanceTemplatesService = compute.NewInstanceTemplatesService(service)
+	c.FirewallsService = compute.NewFirewallsService(service)
 
 	return nil
 }
@@ -56,70 +63,32 @@ func (c *gceClientImpl) Init(ctx context.Context, config *jwt.Config) error {
 // API calls to an instance refer to the instance by the user-provided name (which must be unique)
 // and not the ID. If successful, CreateInstance returns the name of the provisioned instance.
 func (c *gceClientImpl) CreateInstance(h *host.Host, s *GCESettings) (string, error) {
-	// Create instance options to spawn an instance
-	machineType := makeMachineType(h.Zone, s.MachineName, s.NumCPUs, s.MemoryMB)
-	instance := &compute.Instance{
-		Name:        h.Id,
-		MachineType: machineType,
-		Labels:      makeLabels(h),
-		Tags:        &compute.Tags{Items: s.NetworkTags},
+	var template *compute.InstanceProperties
+	if s.InstanceTemplate != "" {
+		t, err := c.InstanceTemplatesService.Get(h.Project, s.InstanceTemplate).Do()
+		if err != nil {
+			return "", errors.Wrapf(err, "API call to get instance template '%s' failed", s.InstanceTemplate)
+		}
+		template = t.Properties
 	}
 
-	grip.Debug(message.Fields{
-		"message":      "creating instance",
-		"host":         h.Id,
-		"machine_type": machineType,
-	})
-
-	// Add the disk with the image URL
-	var imageURL string
-	if s.ImageFamily != "" {
-		imageURL = makeImageFromFamily(s.ImageFamily)
-	} else {
-		imageURL = makeImage(s.ImageName)
-	}
-
-	diskType := makeDiskType(h.Zone, s.DiskType)
-	instance.Disks = []*compute.AttachedDisk{&compute.AttachedDisk{
-		AutoDelete: true,
-		Boot:       true,
-		DeviceName: instance.Name,
-		InitializeParams: &compute.AttachedDiskInitializeParams{
-			DiskSizeGb:  s.DiskSizeGB,
-			DiskType:    diskType,
-			SourceImage: imageURL,
-		},
-	}}
-
-	grip.Debug(message.Fields{
-		"message":      "attaching boot disk",
-		"host":         h.Id,
-		"disk_size":    s.DiskSizeGB,
-		"disk_type":    diskType,
-		"source_image": imageURL,
-	})
-
-	// Attach a network interface
-	instance.NetworkInterfaces = []*compute.NetworkInterface{&compute.NetworkInterface{
-		AccessConfigs: []*compute.AccessConfig{&compute.AccessConfig{}},
-	}}
-
-	// Add the ssh keys
-	keys := s.SSHKeys.String()
-	instance.Metadata = &compute.Metadata{
-		Items: []*compute.MetadataItems{
-			&compute.MetadataItems{Key: "ssh-keys", Value: &keys},
-		},
+	instance, err := makeInstance(h, s, template)
+	if err != nil {
+		return "", errors.Wrapf(err, "error making instance for host '%s'", h.Id)
 	}
 
 	grip.Debug(message.Fields{
-		"message":  "attaching metadata items",
-		"host":     h.Id,
-		"ssh_keys": keys,
+		"message":           "creating instance",
+		"host":              h.Id,
+		"machine_type":      instance.MachineType,
+		"instance_template": s.InstanceTemplate,
+		"preemptible":       s.Preemptible,
+		"network":           s.Network,
+		"subnetwork":        s.Subnetwork,
 	})
 
 	// Make the API call to insert the instance
-	if _, err := c.InstancesService.Insert(h.Project, h.Zone, instance).Do(); err != nil {
+	if _, err = c.InstancesService.Insert(h.Project, h.Zone, instance).Do(); err != nil {
 		return "", errors.Wrap(err, "API call to insert instance failed")
 	}
 
@@ -144,3 +113,29 @@ func (c *gceClientImpl) DeleteInstance(h *host.Host) error {
 
 	return nil
 }
+
+// EnsureFirewall creates the firewall rule in the project if no rule with
+// the same name exists.
+func (c *gceClientImpl) EnsureFirewall(project string, firewall *compute.Firewall) error {
+	_, err := c.FirewallsService.Get(project, firewall.Name).Do()
+	if err == nil {
+		return nil
+	}
+	if apiErr, ok := err.(*googleapi.Error); !ok || apiErr.Code != http.StatusNotFound {
+		return errors.Wrapf(err, "API call to get firewall '%s' failed", firewall.Name)
+	}
+
+	if _, err = c.FirewallsService.Insert(project, firewall).Do(); err != nil {
+		return errors.Wrapf(err, "API call to insert firewall '%s' failed", firewall.Name)
+	}
+
+	grip.Info(message.Fields{
+		"message":  "created firewall",
+		"project":  project,
+		"firewall": firewall.Name,
+		"network":  firewall.Network,
+		"tags":     firewall.TargetTags,
+	})
+
+	return nil
+}
@@ -15,10 +15,15 @@ type gceClientMock struct {
 	failCreate bool
 	failGet    bool
 	failDelete bool
+	failEnsure bool
 
 	// Other options
 	isActive        bool
 	hasAccessConfig bool
+
+	// Recorded arguments
+	settings  *GCESettings
+	firewalls []*compute.Firewall
 }
 
 func (c *gceClientMock) Init(context.Context, *jwt.Config) error {
@@ -30,10 +35,11 @@ func (c *gceClientMock) Init(context.Context, *jwt.Config) error {
 }
 
 // CreateInstance returns a unique identifier for the mock instance.
-func (c *gceClientMock) CreateInstance(h *host.Host, _ *GCESettings) (string, error) {
+func (c *gceClientMock) CreateInstance(h *host.Host, s *GCESettings) (string, error) {
 	if c.failCreate {
 		return "", errors.New("failed to create instance")
 	}
+	c.settings = s
 
 	return h.Id, nil
 }
@@ -71,3 +77,12 @@ func (c *gceClientMock) DeleteInstance(_ *host.Host) error {
 
 	return nil
 }
+
+func (c *gceClientMock) EnsureFirewall(_ string, firewall *compute.Firewall) error {
+	if c.failEnsure {
+		return errors.New("failed to ensure firewall")
+	}
+	c.firewalls = append(c.firewalls, firewall)
+
+	return nil
+}
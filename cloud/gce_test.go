@@ -5,6 +5,7 @@ package cloud
 import (
 	"context"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/evergreen-ci/evergreen/testutil"
 
 	"github.com/stretchr/testify/suite"
+	compute "google.golang.org/api/compute/v1"
 )
 
 type GCESuite struct {
@@ -152,6 +154,27 @@ func (s *GCESuite) TestValidateMachineSettings() {
 	s.Error(settingsUnderSpecified.Validate())
 }
 
+func (s *GCESuite) TestValidateTemplateSettings() {
+	// the template provides the machine, image, and disk
+	settings := &GCESettings{
+		InstanceTemplate: "template",
+	}
+	s.NoError(settings.Validate())
+
+	// settings may override the template
+	settings.MachineName = "machine"
+	settings.ImageFamily = "family"
+	s.NoError(settings.Validate())
+
+	// but must not conflict
+	settings.ImageName = "image"
+	s.Error(settings.Validate())
+	settings.ImageName = ""
+	settings.NumCPUs = 2
+	settings.MemoryMB = 1024
+	s.Error(settings.Validate())
+}
+
 func (s *GCESuite) TestConfigureAPICall() {
 	mock, ok := s.client.(*gceClientMock)
 	s.True(ok)
@@ -403,6 +426,129 @@ func (s *GCESuite) TestSpawnAPICall() {
 	s.Nil(h)
 }
 
+func (s *GCESuite) TestSpawnParentConfiguresNetwork() {
+	dist := distro.Distro{
+		Id:       "id",
+		Provider: "gce",
+		ProviderSettings: &map[string]interface{}{
+			"instance_type":          "machine",
+			"image_name":             "image",
+			"disk_type":              "pd-standard",
+			"network":                "containers",
+			"network_tags":           []string{"abc"},
+			"firewall_source_ranges": []string{"10.0.0.0/8"},
+		},
+	}
+	opts := HostOptions{
+		HasContainers: true,
+		ContainerPoolSettings: &evergreen.ContainerPool{
+			Id:   "Pool_1",
+			Port: 2376,
+		},
+	}
+
+	mock, ok := s.client.(*gceClientMock)
+	s.True(ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := NewIntent(dist, dist.GenerateName(), dist.Provider, opts)
+	h, err := s.manager.SpawnHost(ctx, h)
+	s.NoError(err)
+	s.NotNil(h)
+
+	s.Require().NotNil(mock.settings)
+	s.Equal([]string{"abc", "evergreen-pool-pool-1"}, mock.settings.NetworkTags)
+	s.Require().Len(mock.firewalls, 1)
+	firewall := mock.firewalls[0]
+	s.Equal("evergreen-pool-pool-1-docker", firewall.Name)
+	s.Equal("global/networks/containers", firewall.Network)
+	s.Equal([]string{"10.0.0.0/8"}, firewall.SourceRanges)
+	s.Equal([]string{"evergreen-pool-pool-1"}, firewall.TargetTags)
+	s.Require().Len(firewall.Allowed, 1)
+	s.Equal([]string{"2376"}, firewall.Allowed[0].Ports)
+
+	mock.failEnsure = true
+	h = NewIntent(dist, dist.GenerateName(), dist.Provider, opts)
+	h, err = s.manager.SpawnHost(ctx, h)
+	s.Error(err)
+	s.Nil(h)
+}
+
+func (s *GCESuite) TestUtilMakeInstance() {
+	h := &host.Host{
+		Id:   "host",
+		Zone: "us-east1-c",
+	}
+
+	// without a template
+	settings := &GCESettings{
+		MachineName: "n1-standard-8",
+		ImageFamily: "family",
+		DiskType:    "pd-ssd",
+		DiskSizeGB:  20,
+		NetworkTags: []string{"abc"},
+		Network:     "net",
+		Subnetwork:  "subnet",
+		Preemptible: true,
+	}
+	instance, err := makeInstance(h, settings, nil)
+	s.NoError(err)
+	s.Equal("host", instance.Name)
+	s.Equal("zones/us-east1-c/machineTypes/n1-standard-8", instance.MachineType)
+	s.Equal([]string{"abc"}, instance.Tags.Items)
+	s.Require().Len(instance.Disks, 1)
+	s.True(instance.Disks[0].Boot)
+	s.Equal("global/images/family/family", instance.Disks[0].InitializeParams.SourceImage)
+	s.Equal("zones/us-east1-c/diskTypes/pd-ssd", instance.Disks[0].InitializeParams.DiskType)
+	s.EqualValues(20, instance.Disks[0].InitializeParams.DiskSizeGb)
+	s.Require().Len(instance.NetworkInterfaces, 1)
+	s.Equal("global/networks/net", instance.NetworkInterfaces[0].Network)
+	s.Equal("regions/us-east1/subnetworks/subnet", instance.NetworkInterfaces[0].Subnetwork)
+	s.Require().NotNil(instance.Scheduling)
+	s.True(instance.Scheduling.Preemptible)
+	s.False(*instance.Scheduling.AutomaticRestart)
+	s.Equal("TERMINATE", instance.Scheduling.OnHostMaintenance)
+
+	// with a template, settings override the template's
+	template := &compute.InstanceProperties{
+		MachineType: "n1-standard-1",
+		Disks: []*compute.AttachedDisk{&compute.AttachedDisk{
+			Boot: true,
+			InitializeParams: &compute.AttachedDiskInitializeParams{
+				DiskType:    "pd-standard",
+				SourceImage: "global/images/template-image",
+			},
+		}},
+		Labels: map[string]string{"team": "server"},
+		Tags:   &compute.Tags{Items: []string{"template-tag"}},
+	}
+	settings = &GCESettings{
+		InstanceTemplate: "template",
+		ImageName:        "image",
+		NetworkTags:      []string{"abc"},
+	}
+	instance, err = makeInstance(h, settings, template)
+	s.NoError(err)
+	s.Equal("zones/us-east1-c/machineTypes/n1-standard-1", instance.MachineType)
+	s.Equal([]string{"template-tag", "abc"}, instance.Tags.Items)
+	s.Equal("server", instance.Labels["team"])
+	s.NotEmpty(instance.Labels["start-time"])
+	s.Require().Len(instance.Disks, 1)
+	s.Equal("global/images/image", instance.Disks[0].InitializeParams.SourceImage)
+	s.Equal("zones/us-east1-c/diskTypes/pd-standard", instance.Disks[0].InitializeParams.DiskType)
+	s.Nil(instance.Scheduling)
+	s.Require().Len(instance.NetworkInterfaces, 1)
+	s.Empty(instance.NetworkInterfaces[0].Network)
+}
+
+func (s *GCESuite) TestUtilContainerPoolNetworkTag() {
+	s.Equal("evergreen-pool-pool", makeContainerPoolNetworkTag("pool"))
+	s.Equal("evergreen-pool-my-pool-1", makeContainerPoolNetworkTag("My_Pool.1"))
+	s.Len(makeContainerPoolNetworkTag(strings.Repeat("a", 100)), 63)
+}
+
 func (s *GCESuite) TestUtilToEvgStatus() {
 	s.Equal(StatusInitializing, gceToEvgStatus("PROVISIONING"))
 	s.Equal(StatusInitializing, gceToEvgStatus("STAGING"))
@@ -7,10 +7,13 @@ import (
 	"os"
 	"os/user"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/pkg/errors"
+	compute "google.golang.org/api/compute/v1"
 )
 
 const (
@@ -133,3 +136,171 @@ func makeLabels(intent *host.Host) map[string]string {
 	}
 	return tags
 }
+
+// Returns a network URL for the given network, or the default network.
+func makeNetwork(network string) string {
+	if network == "" {
+		network = "default"
+	}
+	return fmt.Sprintf("global/networks/%s", network)
+}
+
+// Returns a subnetwork URL given the zone.
+func makeSubnetwork(zone, subnetwork string) (string, error) {
+	region, err := zoneToRegion(zone)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("regions/%s/subnetworks/%s", region, subnetwork), nil
+}
+
+// Returns the network tag applied to the parents of a container pool. Tags
+// must be at most 63 lowercase letters, numbers, and hyphens.
+func makeContainerPoolNetworkTag(poolID string) string {
+	r := regexp.MustCompile("[^a-z0-9-]+")
+	tag := "evergreen-pool-" + r.ReplaceAllString(strings.ToLower(poolID), "-")
+	if len(tag) > 63 {
+		tag = tag[:63]
+	}
+	return strings.TrimRight(tag, "-")
+}
+
+// Returns a firewall rule allowing the source ranges to reach the Docker
+// daemon on the parents of a container pool.
+func makeContainerPoolFirewall(pool *evergreen.ContainerPool, network, tag string, sourceRanges []string) *compute.Firewall {
+	return &compute.Firewall{
+		Name:        tag + "-docker",
+		Description: fmt.Sprintf("allow access to the Docker daemon on parents of container pool '%s'", pool.Id),
+		Network:     makeNetwork(network),
+		Allowed: []*compute.FirewallAllowed{&compute.FirewallAllowed{
+			IPProtocol: "tcp",
+			Ports:      []string{strconv.Itoa(int(pool.Port))},
+		}},
+		SourceRanges: sourceRanges,
+		TargetTags:   []string{tag},
+	}
+}
+
+// Makes the instance to insert for the host. If an instance template's
+// properties are given, the instance starts from them, and any machine,
+// image, disk, network, and scheduling settings override the template's.
+func makeInstance(h *host.Host, s *GCESettings, template *compute.InstanceProperties) (*compute.Instance, error) {
+	instance := &compute.Instance{
+		Name: h.Id,
+		Tags: &compute.Tags{},
+	}
+	labels := map[string]string{}
+
+	if template != nil {
+		instance.Description = template.Description
+		instance.CanIpForward = template.CanIpForward
+		instance.Disks = template.Disks
+		instance.Metadata = template.Metadata
+		instance.NetworkInterfaces = template.NetworkInterfaces
+		instance.Scheduling = template.Scheduling
+		instance.ServiceAccounts = template.ServiceAccounts
+		if template.Tags != nil {
+			instance.Tags.Items = append(instance.Tags.Items, template.Tags.Items...)
+		}
+		for k, v := range template.Labels {
+			labels[k] = v
+		}
+
+		// templates refer to machine and disk types by name rather than
+		// by zonal URL
+		if template.MachineType != "" {
+			instance.MachineType = makeMachineType(h.Zone, template.MachineType, 0, 0)
+		}
+		for _, disk := range instance.Disks {
+			if disk.InitializeParams != nil && disk.InitializeParams.DiskType != "" {
+				disk.InitializeParams.DiskType = makeDiskType(h.Zone, disk.InitializeParams.DiskType)
+			}
+		}
+	}
+
+	for k, v := range makeLabels(h) {
+		labels[k] = v
+	}
+	instance.Labels = labels
+	instance.Tags.Items = append(instance.Tags.Items, s.NetworkTags...)
+
+	if s.MachineName != "" || (s.NumCPUs > 0 && s.MemoryMB > 0) {
+		instance.MachineType = makeMachineType(h.Zone, s.MachineName, s.NumCPUs, s.MemoryMB)
+	}
+
+	// Add the disk with the image URL, or override the template's boot disk
+	var bootDisk *compute.AttachedDisk
+	for _, disk := range instance.Disks {
+		if disk.Boot {
+			bootDisk = disk
+			break
+		}
+	}
+	if bootDisk == nil {
+		bootDisk = &compute.AttachedDisk{
+			AutoDelete: true,
+			Boot:       true,
+			DeviceName: instance.Name,
+		}
+		instance.Disks = append([]*compute.AttachedDisk{bootDisk}, instance.Disks...)
+	}
+	if bootDisk.InitializeParams == nil {
+		bootDisk.InitializeParams = &compute.AttachedDiskInitializeParams{}
+	}
+	if s.ImageFamily != "" {
+		bootDisk.InitializeParams.SourceImage = makeImageFromFamily(s.ImageFamily)
+	} else if s.ImageName != "" {
+		bootDisk.InitializeParams.SourceImage = makeImage(s.ImageName)
+	}
+	if s.DiskType != "" {
+		bootDisk.InitializeParams.DiskType = makeDiskType(h.Zone, s.DiskType)
+	}
+	if s.DiskSizeGB > 0 {
+		bootDisk.InitializeParams.DiskSizeGb = s.DiskSizeGB
+	}
+
+	// Attach a network interface
+	if len(instance.NetworkInterfaces) == 0 {
+		instance.NetworkInterfaces = []*compute.NetworkInterface{&compute.NetworkInterface{
+			AccessConfigs: []*compute.AccessConfig{&compute.AccessConfig{}},
+		}}
+	}
+	if s.Network != "" {
+		instance.NetworkInterfaces[0].Network = makeNetwork(s.Network)
+	}
+	if s.Subnetwork != "" {
+		subnetwork, err := makeSubnetwork(h.Zone, s.Subnetwork)
+		if err != nil {
+			return nil, errors.Wrap(err, "error making subnetwork")
+		}
+		instance.NetworkInterfaces[0].Subnetwork = subnetwork
+	}
+
+	// Preemptible instances can neither restart automatically nor migrate
+	// during host maintenance
+	if s.Preemptible {
+		automaticRestart := false
+		instance.Scheduling = &compute.Scheduling{
+			AutomaticRestart:  &automaticRestart,
+			OnHostMaintenance: "TERMINATE",
+			Preemptible:       true,
+		}
+	}
+
+	// Add the ssh keys
+	keys := s.SSHKeys.String()
+	if instance.Metadata == nil {
+		instance.Metadata = &compute.Metadata{}
+	}
+	for _, item := range instance.Metadata.Items {
+		if item.Key == "ssh-keys" {
+			if len(s.SSHKeys) != 0 {
+				item.Value = &keys
+			}
+			return instance, nil
+		}
+	}
+	instance.Metadata.Items = append(instance.Metadata.Items, &compute.MetadataItems{Key: "ssh-keys", Value: &keys})
+
+	return instance, nil
+}
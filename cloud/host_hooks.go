@@ -0,0 +1,49 @@
+package cloud
+
+import (
+	"context"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// RunHook runs the host's distro hook for the stage on the host, if the
+// distro has one, and records its outcome as a host event. The hook's script
+// may use the admin expansions, as the distro's setup script does.
+func (cloudHost *CloudHost) RunHook(ctx context.Context, stage string, settings *evergreen.Settings) error {
+	h := cloudHost.Host
+	hook := h.Distro.Hooks.Get(stage)
+	if hook == nil || h.SpawnOptions.SpawnedByTask {
+		return nil
+	}
+
+	script, err := util.NewExpansions(settings.Expansions).ExpandString(hook.Script)
+	if err != nil {
+		return errors.Wrapf(err, "problem expanding %s hook for host '%s'", stage, h.Id)
+	}
+	sshOptions, err := cloudHost.GetSSHOptions()
+	if err != nil {
+		return errors.Wrapf(err, "problem getting ssh options for host '%s'", h.Id)
+	}
+
+	startTime := time.Now()
+	logs, err := h.RunSSHCommandWithTimeout(ctx, script, sshOptions, hook.Timeout())
+	event.LogHostHookRun(h.Id, stage, logs, err == nil, time.Since(startTime))
+	grip.Info(message.Fields{
+		"message":       "ran distro hook on host",
+		"hook":          stage,
+		"host":          h.Id,
+		"distro":        h.Distro.Id,
+		"success":       err == nil,
+		"duration_secs": time.Since(startTime).Seconds(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error running %s hook on host '%s': %s", stage, h.Id, logs)
+	}
+	return nil
+}
@@ -250,3 +250,59 @@ func (mockMgr *mockManager) GetInstanceStatuses(ctx context.Context, hosts []hos
 func (m *mockManager) CostForDuration(ctx context.Context, h *host.Host, start, end time.Time, s *evergreen.Settings) (float64, error) {
 	return end.Sub(start).Minutes(), nil
 }
+
+func (mockMgr *mockManager) StopInstance(ctx context.Context, host *host.Host, user string) error {
+	l := mockMgr.mutex
+	l.Lock()
+	defer l.Unlock()
+	instance, ok := mockMgr.Instances[host.Id]
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", host.Id)
+	}
+	instance.Status = StatusStopped
+	mockMgr.Instances[host.Id] = instance
+
+	return errors.WithStack(host.SetStatus(evergreen.HostStopped, user, ""))
+}
+
+func (mockMgr *mockManager) StartInstance(ctx context.Context, host *host.Host, user string) error {
+	l := mockMgr.mutex
+	l.Lock()
+	defer l.Unlock()
+	instance, ok := mockMgr.Instances[host.Id]
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", host.Id)
+	}
+	instance.Status = StatusRunning
+	mockMgr.Instances[host.Id] = instance
+
+	return errors.WithStack(host.SetStatus(evergreen.HostRunning, user, ""))
+}
+
+func (mockMgr *mockManager) AttachVolume(ctx context.Context, h *host.Host, attachment host.VolumeAttachment) error {
+	l := mockMgr.mutex
+	l.RLock()
+	_, ok := mockMgr.Instances[h.Id]
+	l.RUnlock()
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", h.Id)
+	}
+	return errors.WithStack(h.AddVolume(attachment))
+}
+
+// GetVolumeOwner returns the owner of the host, since mock volumes belong to
+// the owners of the hosts they're used with.
+func (mockMgr *mockManager) GetVolumeOwner(ctx context.Context, h *host.Host, volumeID string) (string, error) {
+	return h.StartedBy, nil
+}
+
+func (mockMgr *mockManager) DetachVolume(ctx context.Context, h *host.Host, volumeID string) error {
+	l := mockMgr.mutex
+	l.RLock()
+	_, ok := mockMgr.Instances[h.Id]
+	l.RUnlock()
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", h.Id)
+	}
+	return errors.WithStack(h.RemoveVolume(volumeID))
+}
@@ -0,0 +1,68 @@
+package cloud
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// multiRegionManager is implemented by managers that can spawn the hosts of
+// a distro in several regions.
+type multiRegionManager interface {
+	// regions returns the regions the host may be spawned in, in the order
+	// to try them. It returns no regions if the distro lists none.
+	regions(*host.Host) ([]string, error)
+	// isCapacityError returns whether spawning a host failed because its
+	// region was out of capacity.
+	isCapacityError(error) bool
+}
+
+// SpawnHostInRegions spawns the host in the first of its distro's regions
+// that has capacity for it, falling back to on-demand capacity in each region
+// as SpawnHostWithFallback does. The region that served the host is recorded
+// on it, and each region that was out of capacity as a host event.
+func SpawnHostInRegions(ctx context.Context, mgr Manager, h *host.Host) (*host.Host, error) {
+	rm, ok := mgr.(multiRegionManager)
+	if !ok {
+		return SpawnHostWithFallback(ctx, mgr, h)
+	}
+	regions, err := rm.regions(h)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting regions for host '%s'", h.Id)
+	}
+	if len(regions) == 0 {
+		return SpawnHostWithFallback(ctx, mgr, h)
+	}
+
+	capacityFallback := h.CapacityFallback
+	var spawnErr error
+	for i, region := range regions {
+		h.Region = region
+		h.RegionFailover = i > 0
+		h.CapacityFallback = capacityFallback
+
+		var spawned *host.Host
+		spawned, spawnErr = SpawnHostWithFallback(ctx, mgr, h)
+		if spawnErr == nil {
+			return spawned, nil
+		}
+		if !rm.isCapacityError(spawnErr) {
+			return nil, spawnErr
+		}
+
+		event.LogHostRegionFailover(h.Id, region, spawnErr.Error())
+		grip.Warning(message.WrapError(spawnErr, message.Fields{
+			"message": "region is out of capacity",
+			"host":    h.Id,
+			"distro":  h.Distro.Id,
+			"region":  region,
+			"last":    i == len(regions)-1,
+		}))
+	}
+
+	return nil, errors.Wrapf(spawnErr, "all %d regions are out of capacity", len(regions))
+}
@@ -240,6 +240,78 @@ func TerminateSpawnHost(ctx context.Context, host *host.Host, settings *evergree
 	return nil
 }
 
+// ErrUnsupportedOperation is returned when a spawn host's provider does not
+// support an operation on it.
+var ErrUnsupportedOperation = errors.New("operation is not supported by the host's provider")
+
+// StopSpawnHost stops a running spawn host, keeping its disks so that it can
+// be started again.
+func StopSpawnHost(ctx context.Context, h *host.Host, settings *evergreen.Settings, user string) error {
+	mgr, err := GetManager(ctx, h.Provider, settings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	stopper, ok := mgr.(InstanceStopper)
+	if !ok {
+		return errors.WithStack(ErrUnsupportedOperation)
+	}
+	return errors.WithStack(stopper.StopInstance(ctx, h, user))
+}
+
+// StartSpawnHost starts a stopped spawn host.
+func StartSpawnHost(ctx context.Context, h *host.Host, settings *evergreen.Settings, user string) error {
+	mgr, err := GetManager(ctx, h.Provider, settings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	stopper, ok := mgr.(InstanceStopper)
+	if !ok {
+		return errors.WithStack(ErrUnsupportedOperation)
+	}
+	return errors.WithStack(stopper.StartInstance(ctx, h, user))
+}
+
+// AttachSpawnHostVolume attaches a persistent volume to a spawn host.
+func AttachSpawnHostVolume(ctx context.Context, h *host.Host, settings *evergreen.Settings, attachment host.VolumeAttachment) error {
+	mgr, err := GetManager(ctx, h.Provider, settings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	attacher, ok := mgr.(VolumeAttacher)
+	if !ok {
+		return errors.WithStack(ErrUnsupportedOperation)
+	}
+	return errors.WithStack(attacher.AttachVolume(ctx, h, attachment))
+}
+
+// GetSpawnHostVolumeOwner returns the user that owns a persistent volume in
+// the spawn host's region.
+func GetSpawnHostVolumeOwner(ctx context.Context, h *host.Host, settings *evergreen.Settings, volumeID string) (string, error) {
+	mgr, err := GetManager(ctx, h.Provider, settings)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	attacher, ok := mgr.(VolumeAttacher)
+	if !ok {
+		return "", errors.WithStack(ErrUnsupportedOperation)
+	}
+	owner, err := attacher.GetVolumeOwner(ctx, h, volumeID)
+	return owner, errors.WithStack(err)
+}
+
+// DetachSpawnHostVolume detaches a persistent volume from a spawn host.
+func DetachSpawnHostVolume(ctx context.Context, h *host.Host, settings *evergreen.Settings, volumeID string) error {
+	mgr, err := GetManager(ctx, h.Provider, settings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	attacher, ok := mgr.(VolumeAttacher)
+	if !ok {
+		return errors.WithStack(ErrUnsupportedOperation)
+	}
+	return errors.WithStack(attacher.DetachVolume(ctx, h, volumeID))
+}
+
 func MakeExtendedSpawnHostExpiration(host *host.Host, extendBy time.Duration) (time.Time, error) {
 	newExp := host.ExpirationTime.Add(extendBy)
 	remainingDuration := newExp.Sub(time.Now()) //nolint
@@ -56,6 +56,7 @@ func buildApp() *cli.App {
 		operations.TestHistory(),
 		operations.LastGreen(),
 		operations.Subscriptions(),
+		operations.Watch(),
 
 		// Patch creation and management commands (top-level)
 		operations.Patch(),
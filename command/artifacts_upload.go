@@ -0,0 +1,309 @@
+package command
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// artifactsUpload uploads files to the artifacts bucket of the api server
+// through URLs that the server signs, so that unlike s3.put it needs no AWS
+// credentials. The server stores the task and the file's hash with each
+// file, and deletes the files when they expire.
+type artifactsUpload struct {
+	// LocalFile is the local filepath to the file to upload.
+	LocalFile string `mapstructure:"local_file" plugin:"expand"`
+
+	// LocalFilesIncludeFilter is an array of expressions that specify what
+	// files should be uploaded.
+	LocalFilesIncludeFilter []string `mapstructure:"local_files_include_filter" plugin:"expand"`
+
+	// RemoteFile is the path of the file within the task's directory of the
+	// artifacts bucket. It's a prefix when multiple files are uploaded.
+	RemoteFile string `mapstructure:"remote_file" plugin:"expand"`
+
+	// ContentType is the MIME type of the uploaded file.
+	ContentType string `mapstructure:"content_type" plugin:"expand"`
+
+	// ResourceDisplayName is the name of the file that is linked. It's a
+	// prefix to the file's name when multiple files are uploaded.
+	ResourceDisplayName string `mapstructure:"display_name" plugin:"expand"`
+
+	// Visibility determines who can see file links in the UI, as it does
+	// for s3.put.
+	Visibility string `mapstructure:"visibility" plugin:"expand"`
+
+	// Optional, when set to true, skips the command without an error when
+	// local_file does not exist.
+	Optional util.StringOrBool `mapstructure:"optional" plugin:"expand"`
+
+	workDir     string
+	skipMissing bool
+
+	base
+}
+
+// artifactFile is a local file to upload and its digests.
+type artifactFile struct {
+	path       string
+	remoteName string
+	size       int64
+	md5        string
+	sha256     string
+}
+
+func artifactsUploadFactory() Command   { return &artifactsUpload{} }
+func (c *artifactsUpload) Name() string { return "artifacts.upload" }
+
+func (c *artifactsUpload) ParseParams(params map[string]interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		WeaklyTypedInput: true,
+		Result:           c,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = decoder.Decode(params); err != nil {
+		return errors.Wrapf(err, "error decoding %s params", c.Name())
+	}
+
+	return c.validate()
+}
+
+func (c *artifactsUpload) validate() error {
+	catcher := grip.NewSimpleCatcher()
+
+	if c.LocalFile == "" && !c.isMulti() {
+		catcher.Add(errors.New("local_file and local_files_include_filter cannot both be blank"))
+	}
+	if c.LocalFile != "" && c.isMulti() {
+		catcher.Add(errors.New("local_file and local_files_include_filter cannot both be specified"))
+	}
+	if c.skipMissing && c.isMulti() {
+		catcher.Add(errors.New("cannot use optional upload with local_files_include_filter"))
+	}
+	if c.RemoteFile == "" {
+		catcher.Add(errors.New("remote_file cannot be blank"))
+	}
+	if !util.StringSliceContains(artifact.ValidVisibilities, c.Visibility) {
+		catcher.Add(errors.Errorf("invalid visibility setting: %v", c.Visibility))
+	}
+
+	return catcher.Resolve()
+}
+
+func (c *artifactsUpload) isMulti() bool {
+	return len(c.LocalFilesIncludeFilter) != 0
+}
+
+func (c *artifactsUpload) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	var err error
+	if err = util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.WithStack(err)
+	}
+	if c.skipMissing, err = c.Optional.Bool(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = c.validate(); err != nil {
+		return errors.WithStack(err)
+	}
+	c.workDir = conf.WorkDir
+	if filepath.IsAbs(c.LocalFile) {
+		c.workDir = ""
+	}
+
+	files, err := c.localFiles()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(files) == 0 {
+		logger.Task().Info("artifacts upload found no files to upload")
+		return nil
+	}
+
+	reqs := make([]apimodels.ArtifactUploadRequest, 0, len(files))
+	for _, f := range files {
+		reqs = append(reqs, apimodels.ArtifactUploadRequest{
+			Name:        f.remoteName,
+			ContentType: c.ContentType,
+			Size:        f.size,
+			SHA256:      f.sha256,
+			MD5:         f.md5,
+		})
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	uploads, err := comm.CreateArtifactUploads(ctx, td, reqs)
+	if err != nil {
+		return errors.Wrap(err, "problem getting artifact upload URLs")
+	}
+	if len(uploads) != len(files) {
+		return errors.Errorf("got %d artifact upload URLs for %d files", len(uploads), len(files))
+	}
+
+	attached := make([]*artifact.File, 0, len(files))
+	for i, f := range files {
+		logger.Task().Infof("uploading %s to %s", f.path, uploads[i].Link)
+		if err = putArtifactWithRetry(ctx, logger, f, uploads[i]); err != nil {
+			return errors.Wrapf(err, "problem uploading '%s'", f.path)
+		}
+
+		displayName := c.ResourceDisplayName
+		if c.isMulti() || displayName == "" {
+			displayName = fmt.Sprintf("%s %s", c.ResourceDisplayName, filepath.Base(f.path))
+		}
+		attached = append(attached, &artifact.File{
+			Name:       displayName,
+			Link:       uploads[i].Link,
+			Visibility: c.Visibility,
+			Size:       f.size,
+			SHA256:     f.sha256,
+			Bucket:     uploads[i].Bucket,
+			FileKey:    uploads[i].Key,
+			ExpiresAt:  uploads[i].ExpiresAt,
+		})
+	}
+
+	if err = comm.AttachFiles(ctx, td, attached); err != nil {
+		return errors.Wrap(err, "Attach files failed")
+	}
+	logger.Task().Infof("uploaded %d artifacts", len(attached))
+
+	return nil
+}
+
+// localFiles returns the files to upload with their digests.
+func (c *artifactsUpload) localFiles() ([]artifactFile, error) {
+	paths := []string{c.LocalFile}
+	if c.isMulti() {
+		var err error
+		paths, err = util.BuildFileList(c.workDir, c.LocalFilesIncludeFilter...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error processing filter %s",
+				strings.Join(c.LocalFilesIncludeFilter, " "))
+		}
+	}
+
+	files := make([]artifactFile, 0, len(paths))
+	for _, p := range paths {
+		remoteName := c.RemoteFile
+		if c.isMulti() {
+			remoteName = c.RemoteFile + filepath.Base(p)
+		}
+
+		f, err := newArtifactFile(filepath.Join(c.workDir, p), filepath.ToSlash(remoteName))
+		if os.IsNotExist(errors.Cause(err)) && c.skipMissing {
+			continue
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		files = append(files, f)
+	}
+
+	return files, nil
+}
+
+func newArtifactFile(path, remoteName string) (artifactFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return artifactFile{}, errors.Wrapf(err, "problem opening '%s'", path)
+	}
+	defer file.Close()
+
+	md5Hash := md5.New()
+	sha256Hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(md5Hash, sha256Hash), file)
+	if err != nil {
+		return artifactFile{}, errors.Wrapf(err, "problem reading '%s'", path)
+	}
+
+	return artifactFile{
+		path:       path,
+		remoteName: remoteName,
+		size:       size,
+		md5:        base64.StdEncoding.EncodeToString(md5Hash.Sum(nil)),
+		sha256:     hex.EncodeToString(sha256Hash.Sum(nil)),
+	}, nil
+}
+
+func putArtifactWithRetry(ctx context.Context, logger client.LoggerProducer, f artifactFile, upload apimodels.ArtifactUpload) error {
+	backoffCounter := getS3OpBackoff()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	var err error
+	for i := 1; i <= maxS3OpAttempts; i++ {
+		select {
+		case <-ctx.Done():
+			return errors.New("artifacts upload canceled")
+		case <-timer.C:
+			if err = putArtifact(ctx, f, upload); err == nil {
+				return nil
+			}
+			logger.Execution().Errorf("problem uploading artifact [%d of %d]: %s", i, maxS3OpAttempts, err.Error())
+			timer.Reset(backoffCounter.Duration())
+		}
+	}
+
+	return errors.WithStack(err)
+}
+
+func putArtifact(ctx context.Context, f artifactFile, upload apimodels.ArtifactUpload) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return errors.Wrapf(err, "problem opening '%s'", f.path)
+	}
+	defer file.Close()
+
+	req, err := http.NewRequest(http.MethodPut, upload.URL, file)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.ContentLength = f.size
+	for k, v := range upload.Headers {
+		// the client sets these from the request itself
+		if http.CanonicalHeaderKey(k) == "Content-Length" || http.CanonicalHeaderKey(k) == "Host" {
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+
+	httpClient := util.GetHTTPClient()
+	defer util.PutHTTPClient(httpClient)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("upload failed with status %s: %s", resp.Status, string(body))
+	}
+
+	return nil
+}
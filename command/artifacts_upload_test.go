@@ -0,0 +1,147 @@
+package command
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactsUploadParseParams(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := &artifactsUpload{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{}))
+
+	cmd = &artifactsUpload{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{"local_file": "dist.tgz"}))
+
+	cmd = &artifactsUpload{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{
+		"local_file":                 "dist.tgz",
+		"local_files_include_filter": []string{"*.tgz"},
+		"remote_file":                "dist/",
+	}))
+
+	cmd = &artifactsUpload{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{
+		"local_file":  "dist.tgz",
+		"remote_file": "dist.tgz",
+		"visibility":  "everyone",
+	}))
+
+	cmd = &artifactsUpload{}
+	assert.NoError(cmd.ParseParams(map[string]interface{}{
+		"local_file":   "dist.tgz",
+		"remote_file":  "dist.tgz",
+		"content_type": "application/x-gzip",
+		"optional":     true,
+	}))
+	assert.Equal("dist.tgz", cmd.LocalFile)
+	assert.Equal("application/x-gzip", cmd.ContentType)
+}
+
+func TestArtifactsUploadExecute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mu := sync.Mutex{}
+	uploaded := map[string][]byte{}
+	uploadHeaders := map[string]http.Header{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		uploaded[r.URL.Path] = body
+		uploadHeaders[r.URL.Path] = r.Header
+	}))
+	defer srv.Close()
+
+	tmpdir, err := ioutil.TempDir("", "evergreen.command.artifacts_upload.test")
+	require.NoError(err)
+	defer os.RemoveAll(tmpdir)
+	content := []byte("artifact contents")
+	require.NoError(ioutil.WriteFile(filepath.Join(tmpdir, "a.txt"), content, 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(tmpdir, "b.txt"), []byte("other contents"), 0644))
+
+	comm := client.NewMock("http://localhost.com")
+	comm.ArtifactUploadURL = srv.URL
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"dir": "logs"}),
+		Task:       &task.Task{Id: "t1"},
+		Project:    &model.Project{},
+		WorkDir:    tmpdir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	cmd := &artifactsUpload{
+		LocalFile:           "a.txt",
+		RemoteFile:          "${dir}/a.txt",
+		ContentType:         "text/plain",
+		ResourceDisplayName: "A",
+	}
+	require.NoError(cmd.Execute(ctx, comm, logger, conf))
+
+	md5Sum := md5.Sum(content)
+	sha256Sum := sha256.Sum256(content)
+	assert.Equal(content, uploaded["/t1/logs/a.txt"])
+	assert.Equal("text/plain", uploadHeaders["/t1/logs/a.txt"].Get("Content-Type"))
+	assert.Equal(base64.StdEncoding.EncodeToString(md5Sum[:]), uploadHeaders["/t1/logs/a.txt"].Get("Content-Md5"))
+
+	require.Len(comm.AttachedFiles["t1"], 1)
+	file := comm.AttachedFiles["t1"][0]
+	assert.Equal("A", file.Name)
+	assert.Equal(srv.URL+"/t1/logs/a.txt", file.Link)
+	assert.Equal(int64(len(content)), file.Size)
+	assert.Equal(hex.EncodeToString(sha256Sum[:]), file.SHA256)
+	assert.Equal("mock", file.Bucket)
+	assert.Equal("t1/logs/a.txt", file.FileKey)
+
+	cmd = &artifactsUpload{
+		LocalFilesIncludeFilter: []string{"*.txt"},
+		RemoteFile:              "all/",
+	}
+	require.NoError(cmd.Execute(ctx, comm, logger, conf))
+	assert.Equal(content, uploaded["/t1/all/a.txt"])
+	assert.Equal([]byte("other contents"), uploaded["/t1/all/b.txt"])
+	assert.Len(comm.AttachedFiles["t1"], 3)
+
+	cmd = &artifactsUpload{
+		LocalFile:  "missing.txt",
+		RemoteFile: "missing.txt",
+		Optional:   "true",
+	}
+	assert.NoError(cmd.Execute(ctx, comm, logger, conf))
+	assert.Len(comm.AttachedFiles["t1"], 3)
+
+	cmd = &artifactsUpload{
+		LocalFile:  "missing.txt",
+		RemoteFile: "missing.txt",
+	}
+	assert.Error(cmd.Execute(ctx, comm, logger, conf))
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+)
+
+// checkpointRecord records the progress of the task, so that if the task's
+// host is reclaimed, the task's next execution can resume from it using the
+// ${checkpoint} expansion.
+type checkpointRecord struct {
+	// Data is the checkpoint to record, e.g. the last completed step.
+	Data string `mapstructure:"data" plugin:"expand"`
+
+	// File, relative to the working directory, holds the checkpoint to
+	// record instead, so that long-running processes can update it.
+	File string `mapstructure:"file" plugin:"expand"`
+
+	base
+}
+
+func checkpointRecordFactory() Command   { return &checkpointRecord{} }
+func (c *checkpointRecord) Name() string { return "checkpoint.record" }
+
+// ParseParams validates the input to the checkpointRecord, returning an
+// error if something is incorrect. Fulfills Command interface.
+func (c *checkpointRecord) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return err
+	}
+
+	if (c.Data == "") == (c.File == "") {
+		return errors.Errorf("error parsing '%v' params: must specify exactly one of data and file",
+			c.Name())
+	}
+
+	return nil
+}
+
+// Execute records the checkpoint with the API server.
+func (c *checkpointRecord) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.WithStack(err)
+	}
+
+	data := c.Data
+	if c.File != "" {
+		filename := c.File
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(conf.WorkDir, filename)
+		}
+		contents, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return errors.Wrapf(err, "problem reading checkpoint file '%s'", filename)
+		}
+		data = strings.TrimSpace(string(contents))
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	checkpoint, err := comm.SetTaskCheckpoint(ctx, td, data)
+	if err != nil {
+		return errors.Wrap(err, "problem recording checkpoint")
+	}
+	logger.Task().Infof("Recorded checkpoint %d", checkpoint.Sequence)
+
+	conf.Expansions.Put("checkpoint", checkpoint.Data)
+	conf.Expansions.Put("checkpoint_sequence", strconv.Itoa(checkpoint.Sequence))
+	return nil
+}
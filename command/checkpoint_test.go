@@ -0,0 +1,70 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointRecordParseParams(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := &checkpointRecord{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{}))
+
+	cmd = &checkpointRecord{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{"data": "step", "file": "checkpoint.txt"}))
+
+	cmd = &checkpointRecord{}
+	assert.NoError(cmd.ParseParams(map[string]interface{}{"data": "step"}))
+	assert.Equal("step", cmd.Data)
+
+	cmd = &checkpointRecord{}
+	assert.NoError(cmd.ParseParams(map[string]interface{}{"file": "checkpoint.txt"}))
+	assert.Equal("checkpoint.txt", cmd.File)
+}
+
+func TestCheckpointRecordExecute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "evergreen.command.checkpoint.test")
+	require.NoError(err)
+	defer os.RemoveAll(tmpdir)
+	require.NoError(ioutil.WriteFile(filepath.Join(tmpdir, "checkpoint.txt"), []byte("step-2\n"), 0644))
+
+	comm := client.NewMock("http://localhost.com")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"step": "step-1"}),
+		Task:       &task.Task{Id: "t1"},
+		Project:    &model.Project{},
+		WorkDir:    tmpdir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	cmd := &checkpointRecord{Data: "${step}"}
+	require.NoError(cmd.Execute(ctx, comm, logger, conf))
+	assert.Equal("step-1", conf.Expansions.Get("checkpoint"))
+	assert.Equal("1", conf.Expansions.Get("checkpoint_sequence"))
+
+	cmd = &checkpointRecord{File: "checkpoint.txt"}
+	require.NoError(cmd.Execute(ctx, comm, logger, conf))
+	assert.Equal("step-2", conf.Expansions.Get("checkpoint"))
+	assert.Equal("2", conf.Expansions.Get("checkpoint_sequence"))
+	require.Len(comm.Checkpoints["t1"], 2)
+	assert.Equal("step-2", comm.Checkpoints["t1"][1].Data)
+
+	cmd = &checkpointRecord{File: "missing.txt"}
+	assert.Error(cmd.Execute(ctx, comm, logger, conf))
+}
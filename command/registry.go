@@ -23,9 +23,12 @@ func init() {
 		"archive.zip_pack":              zipArchiveCreateFactory,
 		"archive.zip_extract":           zipExtractFactory,
 		"archive.auto_extract":          autoExtractFactory,
+		"artifacts.upload":              artifactsUploadFactory,
 		"attach.results":                attachResultsFactory,
 		"attach.xunit_results":          xunitResultsFactory,
+		"attach.test_report":            testReportResultsFactory,
 		"attach.artifacts":              attachArtifactsFactory,
+		"checkpoint.record":             checkpointRecordFactory,
 		evergreen.CreateHostCommandName: createHostFactory,
 		"host.list":                     listHostFactory,
 		"expansions.fetch_vars":         fetchVarsFactory,
@@ -0,0 +1,94 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mitchellh/mapstructure"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// testReportResults sends raw JUnit XML or TAP reports to the api server,
+// which converts them into test results, so that projects don't need to
+// convert their reports themselves.
+type testReportResults struct {
+	// Files are the relative paths of the reports to send. Globs are
+	// supported.
+	Files []string `mapstructure:"files" plugin:"expand"`
+	// Format is the format of the reports, either "junit" or "tap".
+	Format string `mapstructure:"format" plugin:"expand"`
+	base
+}
+
+func testReportResultsFactory() Command   { return &testReportResults{} }
+func (c *testReportResults) Name() string { return "attach.test_report" }
+
+func (c *testReportResults) ParseParams(params map[string]interface{}) error {
+	if err := mapstructure.Decode(params, c); err != nil {
+		return errors.Wrapf(err, "error decoding '%s' params", c.Name())
+	}
+
+	return c.validate()
+}
+
+func (c *testReportResults) validate() error {
+	catcher := grip.NewBasicCatcher()
+	if len(c.Files) == 0 {
+		catcher.Add(errors.New("must specify at least one file"))
+	}
+	if !util.IsExpandable(c.Format) && !util.StringSliceContains(model.ValidTestReportFormats, c.Format) {
+		catcher.Add(errors.Errorf("format must be one of %v", model.ValidTestReportFormats))
+	}
+	return catcher.Resolve()
+}
+
+func (c *testReportResults) Execute(ctx context.Context,
+	comm client.Communicator, logger client.LoggerProducer, conf *model.TaskConfig) error {
+
+	if err := util.ExpandValues(c, conf.Expansions); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := c.validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	paths, err := getFilePaths(conf.WorkDir, c.Files)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	td := client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret}
+	sent := 0
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return errors.New("operation canceled")
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil || stat.IsDir() {
+			logger.Task().Infof("test report '%s' is not a file", path)
+			continue
+		}
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "problem reading test report '%s'", path)
+		}
+
+		if err = comm.SendTestReport(ctx, td, &apimodels.TestReport{Format: c.Format, Content: string(content)}); err != nil {
+			return errors.Wrapf(err, "problem sending test report '%s'", path)
+		}
+		logger.Task().Infof("sent %s test report '%s'", c.Format, path)
+		sent++
+	}
+
+	if sent == 0 {
+		return errors.New("no test reports found")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestReportResultsParseParams(t *testing.T) {
+	assert := assert.New(t)
+
+	cmd := &testReportResults{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{"format": "tap"}))
+
+	cmd = &testReportResults{}
+	assert.Error(cmd.ParseParams(map[string]interface{}{"files": []string{"report.csv"}, "format": "csv"}))
+
+	cmd = &testReportResults{}
+	assert.NoError(cmd.ParseParams(map[string]interface{}{"files": []string{"report.tap"}, "format": "${report_format}"}))
+
+	cmd = &testReportResults{}
+	assert.NoError(cmd.ParseParams(map[string]interface{}{"files": []string{"*.xml"}, "format": "junit"}))
+	assert.Equal([]string{"*.xml"}, cmd.Files)
+}
+
+func TestTestReportResultsExecute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tmpdir, err := ioutil.TempDir("", "evergreen.command.test_report.test")
+	require.NoError(err)
+	defer os.RemoveAll(tmpdir)
+	require.NoError(ioutil.WriteFile(filepath.Join(tmpdir, "a.tap"), []byte("1..1\nok 1\n"), 0644))
+	require.NoError(ioutil.WriteFile(filepath.Join(tmpdir, "b.tap"), []byte("1..1\nnot ok 1\n"), 0644))
+
+	comm := client.NewMock("http://localhost.com")
+	conf := &model.TaskConfig{
+		Expansions: util.NewExpansions(map[string]string{"report_format": "tap"}),
+		Task:       &task.Task{Id: "t1"},
+		Project:    &model.Project{},
+		WorkDir:    tmpdir,
+	}
+	logger := comm.GetLoggerProducer(ctx, client.TaskData{ID: conf.Task.Id, Secret: conf.Task.Secret})
+
+	cmd := &testReportResults{Files: []string{"*.tap"}, Format: "${report_format}"}
+	require.NoError(cmd.Execute(ctx, comm, logger, conf))
+	require.Len(comm.TestReports, 2)
+	assert.Equal("tap", comm.TestReports[0].Format)
+	assert.Equal("1..1\nok 1\n", comm.TestReports[0].Content)
+
+	cmd = &testReportResults{Files: []string{"*.xml"}, Format: "junit"}
+	assert.Error(cmd.Execute(ctx, comm, logger, conf))
+}
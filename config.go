@@ -23,6 +23,10 @@ var (
 	// Should be specified with -ldflags at build time
 	BuildRevision = ""
 
+	// TestFixturesEnabled is set to "true" with -ldflags for non-production
+	// builds, which may load synthetic test fixtures.
+	TestFixturesEnabled = ""
+
 	// Commandline Version String; used to control auto-updating.
 	ClientVersion = "2018-08-24"
 
@@ -45,10 +49,12 @@ type ConfigSection interface {
 // Settings contains all configuration settings for running Evergreen.
 type Settings struct {
 	Id                 string                    `bson:"_id" json:"id"`
+	AgentUpdate        AgentUpdateConfig         `yaml:"agent_update" bson:"agent_update" json:"agent_update" id:"agent_update"`
 	Alerts             AlertsConfig              `yaml:"alerts" bson:"alerts" json:"alerts" id:"alerts"`
 	Amboy              AmboyConfig               `yaml:"amboy" bson:"amboy" json:"amboy" id:"amboy"`
 	Api                APIConfig                 `yaml:"api" bson:"api" json:"api" id:"api"`
 	ApiUrl             string                    `yaml:"api_url" bson:"api_url" json:"api_url"`
+	Artifacts          ArtifactsConfig           `yaml:"artifacts" bson:"artifacts" json:"artifacts" id:"artifacts"`
 	AuthConfig         AuthConfig                `yaml:"auth" bson:"auth" json:"auth" id:"auth"`
 	Banner             string                    `bson:"banner" json:"banner"`
 	BannerTheme        BannerTheme               `bson:"banner_theme" json:"banner_theme"`
@@ -57,6 +63,7 @@ type Settings struct {
 	ContainerPools     ContainerPoolsConfig      `yaml:"container_pools" bson:"container_pools" json:"container_pools" id:"container_pools"`
 	Credentials        map[string]string         `yaml:"credentials" bson:"credentials" json:"credentials"`
 	CredentialsNew     util.KeyValuePairSlice    `yaml:"credentials_new" bson:"credentials_new" json:"credentials_new"`
+	DataLifecycle      DataLifecycleConfig       `yaml:"data_lifecycle" bson:"data_lifecycle" json:"data_lifecycle" id:"data_lifecycle"`
 	Database           DBSettings                `yaml:"database"`
 	Expansions         map[string]string         `yaml:"expansions" bson:"expansions" json:"expansions"`
 	ExpansionsNew      util.KeyValuePairSlice    `yaml:"expansions_new" bson:"expansions_new" json:"expansions_new"`
@@ -75,6 +82,7 @@ type Settings struct {
 	Providers          CloudProviders            `yaml:"providers" bson:"providers" json:"providers" id:"providers"`
 	RepoTracker        RepoTrackerConfig         `yaml:"repotracker" bson:"repotracker" json:"repotracker" id:"repotracker"`
 	Scheduler          SchedulerConfig           `yaml:"scheduler" bson:"scheduler" json:"scheduler" id:"scheduler"`
+	Secrets            SecretsConfig             `yaml:"secrets" bson:"secrets" json:"secrets" id:"secrets"`
 	ServiceFlags       ServiceFlags              `bson:"service_flags" json:"service_flags" id:"service_flags"`
 	Slack              SlackConfig               `yaml:"slack" bson:"slack" json:"slack" id:"slack"`
 	Splunk             send.SplunkConnectionInfo `yaml:"splunk" bson:"splunk" json:"splunk"`
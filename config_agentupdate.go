@@ -0,0 +1,118 @@
+package evergreen
+
+import (
+	"hash/fnv"
+	"path"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// AgentVersionsDirectory is the subdirectory of the client binaries
+// directory that holds the binaries of agent versions other than the app
+// server's, in a subdirectory per version.
+const AgentVersionsDirectory = "versions"
+
+// AgentUpdateConfig controls the rollout of an agent version to hosts. Agents
+// that can update themselves switch to the version their host should run the
+// next time they are outside of a task group, so rolling a version out or
+// back only takes a change to this section.
+type AgentUpdateConfig struct {
+	// Version is the build revision of the agent being rolled out. Its
+	// binaries are served from the "versions/<version>" subdirectory of the
+	// client binaries directory. Hosts outside of the rollout, or all hosts
+	// if the version is empty, run the app server's agent.
+	Version string `bson:"version" json:"version" yaml:"version"`
+	// RolloutPercent is the percentage of the hosts of distros without their
+	// own rollout percentage that run Version.
+	RolloutPercent int `bson:"rollout_percent" json:"rollout_percent" yaml:"rollout_percent"`
+	// Distros override the rollout percentage of individual distros.
+	Distros []AgentDistroRollout `bson:"distros" json:"distros" yaml:"distros"`
+}
+
+// AgentDistroRollout is the percentage of the hosts of a distro that run the
+// agent version being rolled out.
+type AgentDistroRollout struct {
+	Distro         string `bson:"distro" json:"distro" yaml:"distro"`
+	RolloutPercent int    `bson:"rollout_percent" json:"rollout_percent" yaml:"rollout_percent"`
+}
+
+func (c *AgentUpdateConfig) SectionId() string { return "agent_update" }
+
+func (c *AgentUpdateConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = AgentUpdateConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *AgentUpdateConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			agentUpdateVersionKey:        c.Version,
+			agentUpdateRolloutPercentKey: c.RolloutPercent,
+			agentUpdateDistrosKey:        c.Distros,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *AgentUpdateConfig) ValidateAndDefault() error {
+	if c.RolloutPercent < 0 || c.RolloutPercent > 100 {
+		return errors.New("agent rollout percent must be between 0 and 100")
+	}
+	distros := map[string]bool{}
+	for _, d := range c.Distros {
+		if d.Distro == "" {
+			return errors.New("agent rollout distro cannot be empty")
+		}
+		if distros[d.Distro] {
+			return errors.Errorf("agent rollout for distro '%s' is listed more than once", d.Distro)
+		}
+		distros[d.Distro] = true
+		if d.RolloutPercent < 0 || d.RolloutPercent > 100 {
+			return errors.Errorf("agent rollout percent for distro '%s' must be between 0 and 100", d.Distro)
+		}
+	}
+	return nil
+}
+
+// DistroRolloutPercent returns the percentage of the distro's hosts that run
+// the agent version being rolled out.
+func (c *AgentUpdateConfig) DistroRolloutPercent(distroId string) int {
+	for _, d := range c.Distros {
+		if d.Distro == distroId {
+			return d.RolloutPercent
+		}
+	}
+	return c.RolloutPercent
+}
+
+// DesiredVersion returns the build revision of the agent the host of the
+// distro should run. A host stays in or out of the rollout for as long as
+// the distro's rollout percentage does not change.
+func (c *AgentUpdateConfig) DesiredVersion(distroId, hostId string) string {
+	if c.Version == "" {
+		return BuildRevision
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(hostId))
+	if int(hash.Sum32()%100) < c.DistroRolloutPercent(distroId) {
+		return c.Version
+	}
+	return BuildRevision
+}
+
+// AgentVersionSubPath returns the path of the agent binary of the version,
+// relative to the client binaries directory, given the path of the binary
+// of the app server's version.
+func AgentVersionSubPath(version, executableSubPath string) string {
+	if version == "" || version == BuildRevision {
+		return executableSubPath
+	}
+	return path.Join(AgentVersionsDirectory, version, executableSubPath)
+}
@@ -8,9 +8,34 @@ import (
 
 // NotifyConfig hold logging and email settings for the notify package.
 type NotifyConfig struct {
-	BufferTargetPerInterval int        `bson:"buffer_target_per_interval" json:"buffer_target_per_interval" yaml:"buffer_target_per_interval"`
-	BufferIntervalSeconds   int        `bson:"buffer_interval_seconds" json:"buffer_interval_seconds" yaml:"buffer_interval_seconds"`
-	SMTP                    SMTPConfig `bson:"smtp" json:"smtp" yaml:"smtp"`
+	BufferTargetPerInterval int                     `bson:"buffer_target_per_interval" json:"buffer_target_per_interval" yaml:"buffer_target_per_interval"`
+	BufferIntervalSeconds   int                     `bson:"buffer_interval_seconds" json:"buffer_interval_seconds" yaml:"buffer_interval_seconds"`
+	SMTP                    SMTPConfig              `bson:"smtp" json:"smtp" yaml:"smtp"`
+	RateLimits              NotifyRateLimits        `bson:"rate_limits" json:"rate_limits" yaml:"rate_limits"`
+	Attachments             NotifyAttachmentsConfig `bson:"attachments" json:"attachments" yaml:"attachments"`
+}
+
+// NotifyRateLimits are the maximum number of notifications that each app
+// server sends through each sender per minute. Zero means no limit.
+type NotifyRateLimits struct {
+	SlackPerMinute int `bson:"slack_per_minute" json:"slack_per_minute" yaml:"slack_per_minute"`
+	JIRAPerMinute  int `bson:"jira_per_minute" json:"jira_per_minute" yaml:"jira_per_minute"`
+	EmailPerMinute int `bson:"email_per_minute" json:"email_per_minute" yaml:"email_per_minute"`
+}
+
+// NotifyAttachmentsConfig configures the S3 bucket that notifications may
+// attach files from, so that users can't attach arbitrary objects that the
+// app server's credentials can read.
+type NotifyAttachmentsConfig struct {
+	// Bucket is the only bucket that files may be attached from. Files in
+	// S3 can't be attached if it is empty.
+	Bucket string `bson:"bucket" json:"bucket" yaml:"bucket"`
+	// Prefix is the prefix that the keys of attached files must begin with.
+	Prefix string `bson:"prefix" json:"prefix" yaml:"prefix"`
+	// Key and Secret are the credentials that attached files are read with.
+	// They only need to be allowed to get objects in the bucket.
+	Key    string `bson:"key" json:"key" yaml:"key"`
+	Secret string `bson:"secret" json:"secret" yaml:"secret"`
 }
 
 func (c *NotifyConfig) SectionId() string { return "notify" }
@@ -37,6 +62,13 @@ func (c *NotifyConfig) ValidateAndDefault() error {
 		c.BufferTargetPerInterval = 20
 	}
 
+	if c.RateLimits.SlackPerMinute < 0 || c.RateLimits.JIRAPerMinute < 0 || c.RateLimits.EmailPerMinute < 0 {
+		return errors.New("notification rate limits cannot be negative")
+	}
+	if c.Attachments.Bucket != "" && (c.Attachments.Key == "" || c.Attachments.Secret == "") {
+		return errors.New("notification attachment bucket requires a key and secret to read files with")
+	}
+
 	// cap to 100 jobs/sec per server
 	jobsPerSecond := c.BufferIntervalSeconds / c.BufferTargetPerInterval
 	if jobsPerSecond > maxNotificationsPerSecond {
@@ -10,6 +10,11 @@ import (
 type APIConfig struct {
 	HttpListenAddr      string `bson:"http_listen_addr" json:"http_listen_addr" yaml:"httplistenaddr"`
 	GithubWebhookSecret string `bson:"github_webhook_secret" json:"github_webhook_secret" yaml:"github_webhook_secret"`
+	// GithubAppID and GithubAppKey, the app's PEM-encoded private key,
+	// identify the GitHub App that reports check runs. The app must be
+	// installed on the repositories of the projects reporting them.
+	GithubAppID  int64  `bson:"github_app_id" json:"github_app_id" yaml:"github_app_id"`
+	GithubAppKey string `bson:"github_app_key" json:"github_app_key" yaml:"github_app_key"`
 }
 
 func (c *APIConfig) SectionId() string { return "api" }
@@ -28,6 +33,8 @@ func (c *APIConfig) Set() error {
 		"$set": bson.M{
 			"http_listen_addr":      c.HttpListenAddr,
 			"github_webhook_secret": c.GithubWebhookSecret,
+			"github_app_id":         c.GithubAppID,
+			"github_app_key":        c.GithubAppKey,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -0,0 +1,100 @@
+package evergreen
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	defaultArtifactsRegion           = "us-east-1"
+	defaultArtifactURLExpirationMins = 15
+	// maxArtifactURLExpirationMins is the longest that S3 allows a signed
+	// URL to be valid for.
+	maxArtifactURLExpirationMins = 7 * 24 * 60
+)
+
+// ArtifactsConfig configures the bucket that agents upload task artifacts to
+// through URLs signed by the app server, so that projects do not need AWS
+// credentials of their own to upload artifacts.
+type ArtifactsConfig struct {
+	// Bucket is the S3 bucket artifacts are uploaded to. Signed uploads are
+	// disabled if it is empty.
+	Bucket string `bson:"bucket" json:"bucket" yaml:"bucket"`
+	// Prefix is prepended to the keys of the uploaded artifacts.
+	Prefix string `bson:"prefix" json:"prefix" yaml:"prefix"`
+	Region string `bson:"region" json:"region" yaml:"region"`
+	// Key and Secret are the credentials that upload and download URLs are
+	// signed with. They need to be allowed to put, get, and delete objects
+	// in the bucket.
+	Key    string `bson:"key" json:"key" yaml:"key"`
+	Secret string `bson:"secret" json:"secret" yaml:"secret"`
+	// URLExpirationMins is how long a signed upload or download URL is
+	// valid for.
+	URLExpirationMins int `bson:"url_expiration_mins" json:"url_expiration_mins" yaml:"url_expiration_mins"`
+	// ExpirationDays is how long uploaded artifacts are kept before they
+	// are deleted. If it is 0, artifacts are kept forever.
+	ExpirationDays int `bson:"expiration_days" json:"expiration_days" yaml:"expiration_days"`
+}
+
+func (c *ArtifactsConfig) SectionId() string { return "artifacts" }
+
+func (c *ArtifactsConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = ArtifactsConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *ArtifactsConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			artifactsBucketKey:            c.Bucket,
+			artifactsPrefixKey:            c.Prefix,
+			artifactsRegionKey:            c.Region,
+			artifactsKeyKey:               c.Key,
+			artifactsSecretKey:            c.Secret,
+			artifactsURLExpirationMinsKey: c.URLExpirationMins,
+			artifactsExpirationDaysKey:    c.ExpirationDays,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *ArtifactsConfig) ValidateAndDefault() error {
+	if c.Region == "" {
+		c.Region = defaultArtifactsRegion
+	}
+	if c.URLExpirationMins == 0 {
+		c.URLExpirationMins = defaultArtifactURLExpirationMins
+	}
+	if c.URLExpirationMins < 0 || c.URLExpirationMins > maxArtifactURLExpirationMins {
+		return errors.Errorf("artifact upload URL expiration must be between 1 and %d minutes", maxArtifactURLExpirationMins)
+	}
+	if c.ExpirationDays < 0 {
+		return errors.New("artifact expiration cannot be negative")
+	}
+	if c.Bucket != "" && (c.Key == "" || c.Secret == "") {
+		return errors.New("artifact bucket requires a key and secret to sign uploads with")
+	}
+	return nil
+}
+
+// URLExpiration returns how long a signed upload or download URL is valid
+// for.
+func (c *ArtifactsConfig) URLExpiration() time.Duration {
+	return time.Duration(c.URLExpirationMins) * time.Minute
+}
+
+// ExpiresAt returns when an artifact uploaded at the given time is deleted,
+// or the zero time if artifacts are kept forever.
+func (c *ArtifactsConfig) ExpiresAt(uploaded time.Time) time.Time {
+	if c.ExpirationDays <= 0 {
+		return time.Time{}
+	}
+	return uploaded.AddDate(0, 0, c.ExpirationDays)
+}
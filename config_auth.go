@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -49,12 +50,47 @@ type GithubAuthConfig struct {
 	Organization string   `bson:"organization" json:"organization" yaml:"organization"`
 }
 
+// OIDCConfig holds settings for logging in with an OpenID Connect identity
+// provider, such as an organization's SSO. Issuer is the provider's URL,
+// whose discovery document lists its endpoints. Users are named by the
+// UsernameClaim of their ID tokens, which defaults to their immutable
+// subject, and GroupsClaim lists their groups.
+// If AllowedGroups is set, only their members can log in. GroupRoles give
+// the members of groups roles on projects. Sessions last SessionHours.
+type OIDCConfig struct {
+	Issuer        string          `bson:"issuer" json:"issuer" yaml:"issuer"`
+	ClientId      string          `bson:"client_id" json:"client_id" yaml:"client_id"`
+	ClientSecret  string          `bson:"client_secret" json:"client_secret" yaml:"client_secret"`
+	Scopes        []string        `bson:"scopes" json:"scopes" yaml:"scopes"`
+	UsernameClaim string          `bson:"username_claim" json:"username_claim" yaml:"username_claim"`
+	GroupsClaim   string          `bson:"groups_claim" json:"groups_claim" yaml:"groups_claim"`
+	AllowedGroups []string        `bson:"allowed_groups" json:"allowed_groups" yaml:"allowed_groups"`
+	GroupRoles    []OIDCGroupRole `bson:"group_roles" json:"group_roles" yaml:"group_roles"`
+	SessionHours  int             `bson:"session_hours" json:"session_hours" yaml:"session_hours"`
+}
+
+// OIDCGroupRole gives the members of an identity provider's group a role,
+// one of viewer, contributor or project_admin, on the projects, or on every
+// project if none are listed.
+type OIDCGroupRole struct {
+	Group    string   `bson:"group" json:"group" yaml:"group"`
+	Role     string   `bson:"role" json:"role" yaml:"role"`
+	Projects []string `bson:"projects" json:"projects" yaml:"projects"`
+}
+
+const (
+	defaultOIDCUsernameClaim = "sub"
+	defaultOIDCGroupsClaim   = "groups"
+	defaultOIDCSessionHours  = 24
+)
+
 // AuthConfig has a pointer to either a CrowConfig or a NaiveAuthConfig.
 type AuthConfig struct {
 	LDAP   *LDAPConfig       `bson:"ldap,omitempty" json:"ldap" yaml:"ldap"`
 	Crowd  *CrowdConfig      `bson:"crowd,omitempty" json:"crowd" yaml:"crowd"`
 	Naive  *NaiveAuthConfig  `bson:"naive,omitempty" json:"naive" yaml:"naive"`
 	Github *GithubAuthConfig `bson:"github,omitempty" json:"github" yaml:"github"`
+	OIDC   *OIDCConfig       `bson:"oidc,omitempty" json:"oidc" yaml:"oidc"`
 }
 
 func (c *AuthConfig) SectionId() string { return "auth" }
@@ -75,6 +111,7 @@ func (c *AuthConfig) Set() error {
 			"ldap":   c.LDAP,
 			"naive":  c.Naive,
 			"github": c.Github,
+			"oidc":   c.OIDC,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -82,7 +119,7 @@ func (c *AuthConfig) Set() error {
 
 func (c *AuthConfig) ValidateAndDefault() error {
 	catcher := grip.NewSimpleCatcher()
-	if c.Crowd == nil && c.LDAP == nil && c.Naive == nil && c.Github == nil {
+	if c.Crowd == nil && c.LDAP == nil && c.Naive == nil && c.Github == nil && c.OIDC == nil {
 		catcher.Add(errors.New("You must specify one form of authentication"))
 	}
 	if c.Naive != nil {
@@ -99,5 +136,41 @@ func (c *AuthConfig) ValidateAndDefault() error {
 			catcher.Add(errors.New("Must specify either a set of users or an organization for Github Authentication"))
 		}
 	}
+	if c.OIDC != nil {
+		catcher.Add(c.OIDC.ValidateAndDefault())
+	}
+	return catcher.Resolve()
+}
+
+// ValidateAndDefault checks the OIDC settings and fills in the defaults of
+// those left empty.
+func (c *OIDCConfig) ValidateAndDefault() error {
+	catcher := grip.NewSimpleCatcher()
+	if c.Issuer == "" || c.ClientId == "" || c.ClientSecret == "" {
+		catcher.Add(errors.New("Must specify an issuer, client ID and client secret for OIDC authentication"))
+	}
+	if c.SessionHours < 0 {
+		catcher.Add(errors.New("OIDC session hours can't be negative"))
+	}
+	for _, gr := range c.GroupRoles {
+		if gr.Group == "" {
+			catcher.Add(errors.New("Must specify the group of each OIDC group role"))
+		}
+		if !util.StringSliceContains([]string{"viewer", "contributor", "project_admin"}, gr.Role) {
+			catcher.Add(errors.Errorf("Invalid role '%s' for OIDC group '%s'", gr.Role, gr.Group))
+		}
+	}
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"openid", "profile", "email"}
+	}
+	if c.UsernameClaim == "" {
+		c.UsernameClaim = defaultOIDCUsernameClaim
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = defaultOIDCGroupsClaim
+	}
+	if c.SessionHours == 0 {
+		c.SessionHours = defaultOIDCSessionHours
+	}
 	return catcher.Resolve()
 }
@@ -1,6 +1,8 @@
 package evergreen
 
 import (
+	"time"
+
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -9,6 +11,7 @@ import (
 // CloudProviders stores configuration settings for the supported cloud host providers.
 type CloudProviders struct {
 	AWS       AWSConfig       `bson:"aws" json:"aws" yaml:"aws"`
+	Azure     AzureConfig     `bson:"azure" json:"azure" yaml:"azure"`
 	Docker    DockerConfig    `bson:"docker" json:"docker" yaml:"docker"`
 	GCE       GCEConfig       `bson:"gce" json:"gce" yaml:"gce"`
 	OpenStack OpenStackConfig `bson:"openstack" json:"openstack" yaml:"openstack"`
@@ -30,6 +33,7 @@ func (c *CloudProviders) Set() error {
 	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
 		"$set": bson.M{
 			"aws":       c.AWS,
+			"azure":     c.Azure,
 			"docker":    c.Docker,
 			"gce":       c.GCE,
 			"openstack": c.OpenStack,
@@ -39,7 +43,9 @@ func (c *CloudProviders) Set() error {
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
 }
 
-func (c *CloudProviders) ValidateAndDefault() error { return nil }
+func (c *CloudProviders) ValidateAndDefault() error {
+	return errors.Wrap(c.Docker.validateAndDefault(), "invalid docker settings")
+}
 
 // AWSConfig stores auth info for Amazon Web Services.
 type AWSConfig struct {
@@ -47,9 +53,61 @@ type AWSConfig struct {
 	Id     string `bson:"aws_id" json:"aws_id" yaml:"aws_id"`
 }
 
+// AzureConfig stores auth info for Microsoft Azure. The credentials are those
+// of a service principal with access to the subscription, which can be
+// created with `az ad sp create-for-rbac`.
+type AzureConfig struct {
+	TenantID       string `bson:"tenant_id" json:"tenant_id" yaml:"tenant_id"`
+	ClientID       string `bson:"client_id" json:"client_id" yaml:"client_id"`
+	ClientSecret   string `bson:"client_secret" json:"client_secret" yaml:"client_secret"`
+	SubscriptionID string `bson:"subscription_id" json:"subscription_id" yaml:"subscription_id"`
+}
+
 // DockerConfig stores auth info for Docker.
 type DockerConfig struct {
 	APIVersion string `bson:"api_version" json:"api_version" yaml:"api_version"`
+	// ArtifactsBucket is the S3 bucket that container logs and agent
+	// diagnostics are uploaded to before containers are removed.
+	ArtifactsBucket string `bson:"artifacts_bucket" json:"artifacts_bucket" yaml:"artifacts_bucket"`
+	// PruneThresholdGB is the disk space that Docker can use on a parent
+	// before its unused resources are pruned.
+	PruneThresholdGB int `bson:"prune_threshold_gb" json:"prune_threshold_gb" yaml:"prune_threshold_gb"`
+	// PruneUnusedHours is how long containers and images must have been
+	// unused for before they're pruned.
+	PruneUnusedHours int `bson:"prune_unused_hours" json:"prune_unused_hours" yaml:"prune_unused_hours"`
+}
+
+const (
+	defaultDockerPruneThresholdGB = 150
+	defaultDockerPruneUnusedHours = 24
+)
+
+func (c *DockerConfig) validateAndDefault() error {
+	if c.PruneThresholdGB < 0 {
+		return errors.New("prune threshold cannot be negative")
+	}
+	if c.PruneUnusedHours < 0 {
+		return errors.New("prune unused hours cannot be negative")
+	}
+	if c.PruneThresholdGB == 0 {
+		c.PruneThresholdGB = defaultDockerPruneThresholdGB
+	}
+	if c.PruneUnusedHours == 0 {
+		c.PruneUnusedHours = defaultDockerPruneUnusedHours
+	}
+	return nil
+}
+
+// PruneThreshold returns the bytes that Docker can use on a parent before its
+// unused resources are pruned.
+func (c *DockerConfig) PruneThreshold() int64 {
+	return int64(c.PruneThresholdGB) * 1024 * 1024 * 1024
+}
+
+// PruneUnusedFor returns how long containers and images must have been unused
+// for before they're pruned.
+func (c *DockerConfig) PruneUnusedFor() time.Duration {
+	return time.Duration(c.PruneUnusedHours) * time.Hour
 }
 
 // OpenStackConfig stores auth info for Linaro using Identity V3. All fields required.
@@ -16,6 +16,35 @@ type ContainerPool struct {
 	MaxContainers int `bson:"max_containers" json:"max_containers" yaml:"max_containers"`
 	// Port number to start at for SSH connections
 	Port uint16 `bson:"port" json:"port" yaml:"port"`
+	// Hours between baking new parent host images for this pool, or 0 to
+	// never bake images
+	ImageBakeIntervalHours int `bson:"image_bake_interval_hours" json:"image_bake_interval_hours" yaml:"image_bake_interval_hours"`
+	// Distro of Windows parent hosts in a pool that mixes Windows and Linux
+	// parents. Containers of Windows distros run on these parents, and all
+	// other containers run on parents of Distro.
+	WindowsDistro string `bson:"windows_distro,omitempty" json:"windows_distro,omitempty" yaml:"windows_distro"`
+}
+
+// IsMixed returns whether the pool contains both Windows and Linux parents.
+func (p *ContainerPool) IsMixed() bool {
+	return p.WindowsDistro != ""
+}
+
+// ParentDistro returns the distro of the pool's parents that run containers
+// of the given OS.
+func (p *ContainerPool) ParentDistro(windows bool) string {
+	if windows && p.IsMixed() {
+		return p.WindowsDistro
+	}
+	return p.Distro
+}
+
+// ParentDistros returns the distros of all of the pool's parents.
+func (p *ContainerPool) ParentDistros() []string {
+	if p.IsMixed() {
+		return []string{p.Distro, p.WindowsDistro}
+	}
+	return []string{p.Distro}
 }
 
 type ContainerPoolsConfig struct {
@@ -59,6 +88,12 @@ func (c *ContainerPoolsConfig) ValidateAndDefault() error {
 		if pool.MaxContainers <= 0 {
 			return errors.Errorf("container pool field max_containers must be positive integer")
 		}
+		if pool.ImageBakeIntervalHours < 0 {
+			return errors.Errorf("container pool field image_bake_interval_hours must not be negative")
+		}
+		if pool.IsMixed() && pool.WindowsDistro == pool.Distro {
+			return errors.Errorf("container pool field windows_distro must differ from distro")
+		}
 	}
 	return nil
 }
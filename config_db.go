@@ -11,7 +11,7 @@ var (
 	ConfigDocID      = "global"
 )
 
-//nolint: megacheck, deadcode, unused
+// nolint: megacheck, deadcode, unused
 var (
 	idKey                 = bsonutil.MustHaveTag(Settings{}, "Id")
 	bannerKey             = bsonutil.MustHaveTag(Settings{}, "Banner")
@@ -49,30 +49,62 @@ var (
 	containerPoolsKey     = bsonutil.MustHaveTag(Settings{}, "ContainerPools")
 
 	// degraded mode flags
-	taskDispatchKey                 = bsonutil.MustHaveTag(ServiceFlags{}, "TaskDispatchDisabled")
-	hostinitKey                     = bsonutil.MustHaveTag(ServiceFlags{}, "HostinitDisabled")
-	monitorKey                      = bsonutil.MustHaveTag(ServiceFlags{}, "MonitorDisabled")
-	alertsKey                       = bsonutil.MustHaveTag(ServiceFlags{}, "AlertsDisabled")
-	taskrunnerKey                   = bsonutil.MustHaveTag(ServiceFlags{}, "TaskrunnerDisabled")
-	repotrackerKey                  = bsonutil.MustHaveTag(ServiceFlags{}, "RepotrackerDisabled")
-	schedulerKey                    = bsonutil.MustHaveTag(ServiceFlags{}, "SchedulerDisabled")
-	githubPRTestingDisabledKey      = bsonutil.MustHaveTag(ServiceFlags{}, "GithubPRTestingDisabled")
-	repotrackerPushEventDisabledKey = bsonutil.MustHaveTag(ServiceFlags{}, "RepotrackerPushEventDisabled")
-	cliUpdatesDisabledKey           = bsonutil.MustHaveTag(ServiceFlags{}, "CLIUpdatesDisabled")
-	backgroundStatsDisabledKey      = bsonutil.MustHaveTag(ServiceFlags{}, "BackgroundStatsDisabled")
-	eventProcessingDisabledKey      = bsonutil.MustHaveTag(ServiceFlags{}, "EventProcessingDisabled")
-	jiraNotificationsDisabledKey    = bsonutil.MustHaveTag(ServiceFlags{}, "JIRANotificationsDisabled")
-	slackNotificationsDisabledKey   = bsonutil.MustHaveTag(ServiceFlags{}, "SlackNotificationsDisabled")
-	emailNotificationsDisabledKey   = bsonutil.MustHaveTag(ServiceFlags{}, "EmailNotificationsDisabled")
-	webhookNotificationsDisabledKey = bsonutil.MustHaveTag(ServiceFlags{}, "WebhookNotificationsDisabled")
-	githubStatusAPIDisabledKey      = bsonutil.MustHaveTag(ServiceFlags{}, "GithubStatusAPIDisabled")
-	taskLoggingDisabledKey          = bsonutil.MustHaveTag(ServiceFlags{}, "TaskLoggingDisabled")
+	taskDispatchKey                  = bsonutil.MustHaveTag(ServiceFlags{}, "TaskDispatchDisabled")
+	hostinitKey                      = bsonutil.MustHaveTag(ServiceFlags{}, "HostinitDisabled")
+	monitorKey                       = bsonutil.MustHaveTag(ServiceFlags{}, "MonitorDisabled")
+	alertsKey                        = bsonutil.MustHaveTag(ServiceFlags{}, "AlertsDisabled")
+	taskrunnerKey                    = bsonutil.MustHaveTag(ServiceFlags{}, "TaskrunnerDisabled")
+	repotrackerKey                   = bsonutil.MustHaveTag(ServiceFlags{}, "RepotrackerDisabled")
+	schedulerKey                     = bsonutil.MustHaveTag(ServiceFlags{}, "SchedulerDisabled")
+	githubPRTestingDisabledKey       = bsonutil.MustHaveTag(ServiceFlags{}, "GithubPRTestingDisabled")
+	repotrackerPushEventDisabledKey  = bsonutil.MustHaveTag(ServiceFlags{}, "RepotrackerPushEventDisabled")
+	cliUpdatesDisabledKey            = bsonutil.MustHaveTag(ServiceFlags{}, "CLIUpdatesDisabled")
+	backgroundStatsDisabledKey       = bsonutil.MustHaveTag(ServiceFlags{}, "BackgroundStatsDisabled")
+	eventProcessingDisabledKey       = bsonutil.MustHaveTag(ServiceFlags{}, "EventProcessingDisabled")
+	jiraNotificationsDisabledKey     = bsonutil.MustHaveTag(ServiceFlags{}, "JIRANotificationsDisabled")
+	slackNotificationsDisabledKey    = bsonutil.MustHaveTag(ServiceFlags{}, "SlackNotificationsDisabled")
+	emailNotificationsDisabledKey    = bsonutil.MustHaveTag(ServiceFlags{}, "EmailNotificationsDisabled")
+	webhookNotificationsDisabledKey  = bsonutil.MustHaveTag(ServiceFlags{}, "WebhookNotificationsDisabled")
+	opsgenieNotificationsDisabledKey = bsonutil.MustHaveTag(ServiceFlags{}, "OpsgenieNotificationsDisabled")
+	githubStatusAPIDisabledKey       = bsonutil.MustHaveTag(ServiceFlags{}, "GithubStatusAPIDisabled")
+	taskLoggingDisabledKey           = bsonutil.MustHaveTag(ServiceFlags{}, "TaskLoggingDisabled")
+	apiWritesDisabledKey             = bsonutil.MustHaveTag(ServiceFlags{}, "APIWritesDisabled")
+	notificationDispatchDisabledKey  = bsonutil.MustHaveTag(ServiceFlags{}, "NotificationDispatchDisabled")
+
+	// AgentUpdateConfig keys
+	agentUpdateVersionKey        = bsonutil.MustHaveTag(AgentUpdateConfig{}, "Version")
+	agentUpdateRolloutPercentKey = bsonutil.MustHaveTag(AgentUpdateConfig{}, "RolloutPercent")
+	agentUpdateDistrosKey        = bsonutil.MustHaveTag(AgentUpdateConfig{}, "Distros")
+
+	// ArtifactsConfig keys
+	artifactsBucketKey            = bsonutil.MustHaveTag(ArtifactsConfig{}, "Bucket")
+	artifactsPrefixKey            = bsonutil.MustHaveTag(ArtifactsConfig{}, "Prefix")
+	artifactsRegionKey            = bsonutil.MustHaveTag(ArtifactsConfig{}, "Region")
+	artifactsKeyKey               = bsonutil.MustHaveTag(ArtifactsConfig{}, "Key")
+	artifactsSecretKey            = bsonutil.MustHaveTag(ArtifactsConfig{}, "Secret")
+	artifactsURLExpirationMinsKey = bsonutil.MustHaveTag(ArtifactsConfig{}, "URLExpirationMins")
+	artifactsExpirationDaysKey    = bsonutil.MustHaveTag(ArtifactsConfig{}, "ExpirationDays")
+
+	// DataLifecycleConfig keys
+	dataLifecycleArchiveAfterDaysKey = bsonutil.MustHaveTag(DataLifecycleConfig{}, "ArchiveAfterDays")
+	dataLifecycleBatchSizeKey        = bsonutil.MustHaveTag(DataLifecycleConfig{}, "BatchSize")
+	dataLifecycleTargetKey           = bsonutil.MustHaveTag(DataLifecycleConfig{}, "Target")
+	dataLifecycleBucketKey           = bsonutil.MustHaveTag(DataLifecycleConfig{}, "Bucket")
+	dataLifecyclePrefixKey           = bsonutil.MustHaveTag(DataLifecycleConfig{}, "Prefix")
+	dataLifecycleRegionKey           = bsonutil.MustHaveTag(DataLifecycleConfig{}, "Region")
+	dataLifecycleKeyKey              = bsonutil.MustHaveTag(DataLifecycleConfig{}, "Key")
+	dataLifecycleSecretKey           = bsonutil.MustHaveTag(DataLifecycleConfig{}, "Secret")
 
 	// ContainerPoolsConfig keys
 	poolsKey = bsonutil.MustHaveTag(ContainerPoolsConfig{}, "Pools")
 
 	// ContainerPool keys
 	ContainerPoolIdKey = bsonutil.MustHaveTag(ContainerPool{}, "Id")
+
+	// SecretsConfig keys
+	secretsAWSKey    = bsonutil.MustHaveTag(SecretsConfig{}, "AWS")
+	secretsVaultKey  = bsonutil.MustHaveTag(SecretsConfig{}, "Vault")
+	secretsPrefixKey = bsonutil.MustHaveTag(SecretsConfig{}, "Prefix")
 )
 
 func byId(id string) bson.M {
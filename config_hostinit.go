@@ -1,6 +1,8 @@
 package evergreen
 
 import (
+	"time"
+
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -9,6 +11,27 @@ import (
 // HostInitConfig holds logging settings for the hostinit process.
 type HostInitConfig struct {
 	SSHTimeoutSeconds int64 `bson:"ssh_timeout_secs" json:"ssh_timeout_secs" yaml:"sshtimeoutseconds"`
+
+	// ProblemHostFailureThreshold is how many tasks must system fail on a
+	// host, or on the containers of a parent host, within
+	// ProblemHostWindowMinutes for the host to be quarantined. Problem host
+	// detection is disabled if it is 0.
+	ProblemHostFailureThreshold int `bson:"problem_host_failure_threshold" json:"problem_host_failure_threshold" yaml:"problem_host_failure_threshold"`
+	ProblemHostWindowMinutes    int `bson:"problem_host_window_mins" json:"problem_host_window_mins" yaml:"problem_host_window_mins"`
+	// TerminateProblemHosts decommissions problem hosts instead of
+	// quarantining them, so that they are terminated and replaced once
+	// they are idle. Static hosts and container parents are always
+	// quarantined.
+	TerminateProblemHosts bool `bson:"terminate_problem_hosts" json:"terminate_problem_hosts" yaml:"terminate_problem_hosts"`
+}
+
+// ProblemHostWindow returns how far back system failures are correlated to
+// hosts.
+func (c *HostInitConfig) ProblemHostWindow() time.Duration {
+	if c.ProblemHostWindowMinutes <= 0 {
+		return time.Hour
+	}
+	return time.Duration(c.ProblemHostWindowMinutes) * time.Minute
 }
 
 func (c *HostInitConfig) SectionId() string { return "hostinit" }
@@ -25,10 +48,21 @@ func (c *HostInitConfig) Get() error {
 func (c *HostInitConfig) Set() error {
 	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
 		"$set": bson.M{
-			"ssh_timeout_secs": c.SSHTimeoutSeconds,
+			"ssh_timeout_secs":               c.SSHTimeoutSeconds,
+			"problem_host_failure_threshold": c.ProblemHostFailureThreshold,
+			"problem_host_window_mins":       c.ProblemHostWindowMinutes,
+			"terminate_problem_hosts":        c.TerminateProblemHosts,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
 }
 
-func (c *HostInitConfig) ValidateAndDefault() error { return nil }
+func (c *HostInitConfig) ValidateAndDefault() error {
+	if c.ProblemHostFailureThreshold < 0 {
+		return errors.New("problem host failure threshold cannot be negative")
+	}
+	if c.ProblemHostWindowMinutes < 0 {
+		return errors.New("problem host window cannot be negative")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package evergreen
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// ArchiveTargetCollection keeps archived documents in an archive
+	// collection next to the collection they came from.
+	ArchiveTargetCollection = "collection"
+	// ArchiveTargetS3 keeps archived documents in an S3 bucket.
+	ArchiveTargetS3 = "s3"
+
+	defaultArchiveBatchSize = 1000
+	defaultArchiveRegion    = "us-east-1"
+)
+
+// DataLifecycleConfig configures moving finished tasks and builds, and the
+// test results of the tasks, out of their collections once they are old
+// enough, so that the collections that are queried the most stay small.
+// Archived documents can still be found by their IDs.
+type DataLifecycleConfig struct {
+	// ArchiveAfterDays is how long after they finish tasks and builds are
+	// archived. If it is 0, nothing is archived.
+	ArchiveAfterDays int `bson:"archive_after_days" json:"archive_after_days" yaml:"archive_after_days"`
+	// BatchSize is the most tasks and builds that are archived at a time.
+	BatchSize int `bson:"batch_size" json:"batch_size" yaml:"batch_size"`
+	// Target is where archived documents are kept, either in archive
+	// collections or in S3.
+	Target string `bson:"target" json:"target" yaml:"target"`
+	// Bucket, Prefix, Region, Key and Secret describe the S3 bucket that
+	// archived documents are kept in if the target is S3.
+	Bucket string `bson:"bucket" json:"bucket" yaml:"bucket"`
+	Prefix string `bson:"prefix" json:"prefix" yaml:"prefix"`
+	Region string `bson:"region" json:"region" yaml:"region"`
+	Key    string `bson:"key" json:"key" yaml:"key"`
+	Secret string `bson:"secret" json:"secret" yaml:"secret"`
+}
+
+func (c *DataLifecycleConfig) SectionId() string { return "data_lifecycle" }
+
+func (c *DataLifecycleConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = DataLifecycleConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *DataLifecycleConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			dataLifecycleArchiveAfterDaysKey: c.ArchiveAfterDays,
+			dataLifecycleBatchSizeKey:        c.BatchSize,
+			dataLifecycleTargetKey:           c.Target,
+			dataLifecycleBucketKey:           c.Bucket,
+			dataLifecyclePrefixKey:           c.Prefix,
+			dataLifecycleRegionKey:           c.Region,
+			dataLifecycleKeyKey:              c.Key,
+			dataLifecycleSecretKey:           c.Secret,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *DataLifecycleConfig) ValidateAndDefault() error {
+	if c.ArchiveAfterDays < 0 {
+		return errors.New("archive age cannot be negative")
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = defaultArchiveBatchSize
+	}
+	if c.BatchSize < 0 {
+		return errors.New("archive batch size cannot be negative")
+	}
+	if c.Target == "" {
+		c.Target = ArchiveTargetCollection
+	}
+	if !util.StringSliceContains([]string{ArchiveTargetCollection, ArchiveTargetS3}, c.Target) {
+		return errors.Errorf("'%s' is not a valid archive target", c.Target)
+	}
+	if c.Target == ArchiveTargetS3 {
+		if c.Region == "" {
+			c.Region = defaultArchiveRegion
+		}
+		if c.Bucket == "" || c.Key == "" || c.Secret == "" {
+			return errors.New("archiving to S3 requires a bucket, key and secret")
+		}
+	}
+	return nil
+}
+
+// ArchiveCutoff returns the time before which finished tasks and builds are
+// archived, or the zero time if nothing is archived.
+func (c *DataLifecycleConfig) ArchiveCutoff(now time.Time) time.Time {
+	if c.ArchiveAfterDays <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -c.ArchiveAfterDays)
+}
@@ -31,18 +31,22 @@ type ConfigSectionRegistry struct {
 
 func resetRegistry() error {
 	ConfigSections := []ConfigSection{
+		&AgentUpdateConfig{},
 		&AlertsConfig{},
 		&AmboyConfig{},
 		&APIConfig{},
+		&ArtifactsConfig{},
 		&AuthConfig{},
 		&CloudProviders{},
 		&ContainerPoolsConfig{},
+		&DataLifecycleConfig{},
 		&HostInitConfig{},
 		&JiraConfig{},
 		&LoggerConfig{},
 		&NotifyConfig{},
 		&RepoTrackerConfig{},
 		&SchedulerConfig{},
+		&SecretsConfig{},
 		&ServiceFlags{},
 		&SlackConfig{},
 		&UIConfig{},
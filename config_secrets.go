@@ -0,0 +1,91 @@
+package evergreen
+
+import (
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	defaultSecretsRegion         = "us-east-1"
+	defaultVaultSecretsMountPath = "secret"
+)
+
+// SecretsConfig configures the secrets managers that project variables can
+// be stored in, so that their values don't need to be stored in the
+// database. A backend is disabled unless its credentials are set.
+type SecretsConfig struct {
+	AWS   AWSSecretsConfig   `bson:"aws" json:"aws" yaml:"aws"`
+	Vault VaultSecretsConfig `bson:"vault" json:"vault" yaml:"vault"`
+	// Prefix is the path that the secrets of all projects are named under.
+	// A project can only reference the secrets under its own identifier.
+	Prefix string `bson:"prefix" json:"prefix" yaml:"prefix"`
+}
+
+// AWSSecretsConfig configures access to AWS Secrets Manager.
+type AWSSecretsConfig struct {
+	Region string `bson:"region" json:"region" yaml:"region"`
+	Key    string `bson:"key" json:"key" yaml:"key"`
+	Secret string `bson:"secret" json:"secret" yaml:"secret"`
+}
+
+// VaultSecretsConfig configures access to the version 2 key/value store of
+// a Vault server.
+type VaultSecretsConfig struct {
+	URL   string `bson:"url" json:"url" yaml:"url"`
+	Token string `bson:"token" json:"token" yaml:"token"`
+	// MountPath is the path that the key/value store is mounted at.
+	MountPath string `bson:"mount_path" json:"mount_path" yaml:"mount_path"`
+}
+
+func (c *SecretsConfig) SectionId() string { return "secrets" }
+
+func (c *SecretsConfig) Get() error {
+	err := db.FindOneQ(ConfigCollection, db.Query(byId(c.SectionId())), c)
+	if err != nil && err.Error() == errNotFound {
+		*c = SecretsConfig{}
+		return nil
+	}
+	return errors.Wrapf(err, "error retrieving section %s", c.SectionId())
+}
+
+func (c *SecretsConfig) Set() error {
+	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
+		"$set": bson.M{
+			secretsAWSKey:    c.AWS,
+			secretsVaultKey:  c.Vault,
+			secretsPrefixKey: c.Prefix,
+		},
+	})
+	return errors.Wrapf(err, "error updating section %s", c.SectionId())
+}
+
+func (c *SecretsConfig) ValidateAndDefault() error {
+	if c.AWS.Region == "" {
+		c.AWS.Region = defaultSecretsRegion
+	}
+	if (c.AWS.Key == "") != (c.AWS.Secret == "") {
+		return errors.New("AWS Secrets Manager requires both a key and a secret")
+	}
+	if c.Vault.MountPath == "" {
+		c.Vault.MountPath = defaultVaultSecretsMountPath
+	}
+	c.Prefix = strings.Trim(c.Prefix, "/")
+	if (c.Vault.URL == "") != (c.Vault.Token == "") {
+		return errors.New("Vault requires both a URL and a token")
+	}
+	return nil
+}
+
+// AWSEnabled returns whether project variables can be stored in AWS Secrets
+// Manager.
+func (c *SecretsConfig) AWSEnabled() bool {
+	return c.AWS.Key != "" && c.AWS.Secret != ""
+}
+
+// VaultEnabled returns whether project variables can be stored in Vault.
+func (c *SecretsConfig) VaultEnabled() bool {
+	return c.Vault.URL != "" && c.Vault.Token != ""
+}
@@ -20,14 +20,21 @@ type ServiceFlags struct {
 	CLIUpdatesDisabled           bool `bson:"cli_updates_disabled" json:"cli_updates_disabled"`
 	BackgroundStatsDisabled      bool `bson:"background_stats_disabled" json:"background_stats_disabled"`
 	TaskLoggingDisabled          bool `bson:"task_logging_disabled" json:"task_logging_disabled"`
+	// APIWritesDisabled makes the REST API read-only for users other
+	// than superusers.
+	APIWritesDisabled bool `bson:"api_writes_disabled" json:"api_writes_disabled"`
 
 	// Notification Flags
-	EventProcessingDisabled      bool `bson:"event_processing_disabled" json:"event_processing_disabled"`
-	JIRANotificationsDisabled    bool `bson:"jira_notifications_disabled" json:"jira_notifications_disabled"`
-	SlackNotificationsDisabled   bool `bson:"slack_notifications_disabled" json:"slack_notifications_disabled"`
-	EmailNotificationsDisabled   bool `bson:"email_notifications_disabled" json:"email_notifications_disabled"`
-	WebhookNotificationsDisabled bool `bson:"webhook_notifications_disabled" json:"webhook_notifications_disabled"`
-	GithubStatusAPIDisabled      bool `bson:"github_status_api_disabled" json:"github_status_api_disabled"`
+	EventProcessingDisabled       bool `bson:"event_processing_disabled" json:"event_processing_disabled"`
+	JIRANotificationsDisabled     bool `bson:"jira_notifications_disabled" json:"jira_notifications_disabled"`
+	SlackNotificationsDisabled    bool `bson:"slack_notifications_disabled" json:"slack_notifications_disabled"`
+	EmailNotificationsDisabled    bool `bson:"email_notifications_disabled" json:"email_notifications_disabled"`
+	WebhookNotificationsDisabled  bool `bson:"webhook_notifications_disabled" json:"webhook_notifications_disabled"`
+	OpsgenieNotificationsDisabled bool `bson:"opsgenie_notifications_disabled" json:"opsgenie_notifications_disabled"`
+	GithubStatusAPIDisabled       bool `bson:"github_status_api_disabled" json:"github_status_api_disabled"`
+	// NotificationDispatchDisabled holds notifications until dispatch is
+	// enabled again, rather than dropping them like the sender flags.
+	NotificationDispatchDisabled bool `bson:"notification_dispatch_disabled" json:"notification_dispatch_disabled"`
 }
 
 func (c *ServiceFlags) SectionId() string { return "service_flags" }
@@ -44,24 +51,27 @@ func (c *ServiceFlags) Get() error {
 func (c *ServiceFlags) Set() error {
 	_, err := db.Upsert(ConfigCollection, byId(c.SectionId()), bson.M{
 		"$set": bson.M{
-			taskDispatchKey:                 c.TaskDispatchDisabled,
-			hostinitKey:                     c.HostinitDisabled,
-			monitorKey:                      c.MonitorDisabled,
-			alertsKey:                       c.AlertsDisabled,
-			taskrunnerKey:                   c.TaskrunnerDisabled,
-			repotrackerKey:                  c.RepotrackerDisabled,
-			schedulerKey:                    c.SchedulerDisabled,
-			githubPRTestingDisabledKey:      c.GithubPRTestingDisabled,
-			repotrackerPushEventDisabledKey: c.RepotrackerPushEventDisabled,
-			cliUpdatesDisabledKey:           c.CLIUpdatesDisabled,
-			backgroundStatsDisabledKey:      c.BackgroundStatsDisabled,
-			eventProcessingDisabledKey:      c.EventProcessingDisabled,
-			jiraNotificationsDisabledKey:    c.JIRANotificationsDisabled,
-			slackNotificationsDisabledKey:   c.SlackNotificationsDisabled,
-			emailNotificationsDisabledKey:   c.EmailNotificationsDisabled,
-			webhookNotificationsDisabledKey: c.WebhookNotificationsDisabled,
-			githubStatusAPIDisabledKey:      c.GithubStatusAPIDisabled,
-			taskLoggingDisabledKey:          c.TaskLoggingDisabled,
+			taskDispatchKey:                  c.TaskDispatchDisabled,
+			hostinitKey:                      c.HostinitDisabled,
+			monitorKey:                       c.MonitorDisabled,
+			alertsKey:                        c.AlertsDisabled,
+			taskrunnerKey:                    c.TaskrunnerDisabled,
+			repotrackerKey:                   c.RepotrackerDisabled,
+			schedulerKey:                     c.SchedulerDisabled,
+			githubPRTestingDisabledKey:       c.GithubPRTestingDisabled,
+			repotrackerPushEventDisabledKey:  c.RepotrackerPushEventDisabled,
+			cliUpdatesDisabledKey:            c.CLIUpdatesDisabled,
+			backgroundStatsDisabledKey:       c.BackgroundStatsDisabled,
+			eventProcessingDisabledKey:       c.EventProcessingDisabled,
+			jiraNotificationsDisabledKey:     c.JIRANotificationsDisabled,
+			slackNotificationsDisabledKey:    c.SlackNotificationsDisabled,
+			emailNotificationsDisabledKey:    c.EmailNotificationsDisabled,
+			webhookNotificationsDisabledKey:  c.WebhookNotificationsDisabled,
+			opsgenieNotificationsDisabledKey: c.OpsgenieNotificationsDisabled,
+			githubStatusAPIDisabledKey:       c.GithubStatusAPIDisabled,
+			taskLoggingDisabledKey:           c.TaskLoggingDisabled,
+			apiWritesDisabledKey:             c.APIWritesDisabled,
+			notificationDispatchDisabledKey:  c.NotificationDispatchDisabled,
 		},
 	})
 	return errors.Wrapf(err, "error updating section %s", c.SectionId())
@@ -1,9 +1,11 @@
 package evergreen
 
 import (
+	"fmt"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/util"
@@ -188,6 +190,138 @@ func (s *AdminSuite) TestServiceFlags() {
 	})
 }
 
+func (s *AdminSuite) TestAgentUpdateConfig() {
+	config := AgentUpdateConfig{
+		Version:        "abcdef",
+		RolloutPercent: 10,
+		Distros: []AgentDistroRollout{
+			{Distro: "d1", RolloutPercent: 100},
+		},
+	}
+	s.NoError(config.ValidateAndDefault())
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.AgentUpdate)
+
+	s.Equal(100, config.DistroRolloutPercent("d1"))
+	s.Equal(10, config.DistroRolloutPercent("d2"))
+	s.Equal("abcdef", config.DesiredVersion("d1", "host"))
+	config.Distros[0].RolloutPercent = 0
+	s.Equal(BuildRevision, config.DesiredVersion("d1", "host"))
+	config.Version = ""
+	config.Distros[0].RolloutPercent = 100
+	s.Equal(BuildRevision, config.DesiredVersion("d1", "host"))
+
+	config.Distros = append(config.Distros, AgentDistroRollout{Distro: "d1", RolloutPercent: 10})
+	s.Error(config.ValidateAndDefault())
+	config.Distros = []AgentDistroRollout{{Distro: "d1", RolloutPercent: 101}}
+	s.Error(config.ValidateAndDefault())
+	config.Distros = nil
+	config.RolloutPercent = -1
+	s.Error(config.ValidateAndDefault())
+}
+
+func (s *AdminSuite) TestArtifactsConfig() {
+	config := ArtifactsConfig{
+		Bucket:         "bucket",
+		Prefix:         "artifacts",
+		Key:            "key",
+		Secret:         "secret",
+		ExpirationDays: 30,
+	}
+	s.NoError(config.ValidateAndDefault())
+	s.Equal("us-east-1", config.Region)
+	s.Equal(15*time.Minute, config.URLExpiration())
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.Artifacts)
+
+	uploaded := time.Date(2019, time.January, 31, 0, 0, 0, 0, time.UTC)
+	s.Equal(time.Date(2019, time.March, 2, 0, 0, 0, 0, time.UTC), config.ExpiresAt(uploaded))
+	config.ExpirationDays = 0
+	s.True(config.ExpiresAt(uploaded).IsZero())
+
+	config.ExpirationDays = -1
+	s.Error(config.ValidateAndDefault())
+	config.ExpirationDays = 0
+	config.URLExpirationMins = 8 * 24 * 60
+	s.Error(config.ValidateAndDefault())
+	config.URLExpirationMins = 0
+	config.Secret = ""
+	s.Error(config.ValidateAndDefault())
+}
+
+func (s *AdminSuite) TestSecretsConfig() {
+	config := SecretsConfig{
+		AWS:    AWSSecretsConfig{Key: "key", Secret: "secret"},
+		Vault:  VaultSecretsConfig{URL: "https://vault.example.com", Token: "token"},
+		Prefix: "/evergreen/",
+	}
+	s.NoError(config.ValidateAndDefault())
+	s.Equal("us-east-1", config.AWS.Region)
+	s.Equal("secret", config.Vault.MountPath)
+	s.Equal("evergreen", config.Prefix)
+	s.True(config.AWSEnabled())
+	s.True(config.VaultEnabled())
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.Secrets)
+
+	config.Vault.Token = ""
+	s.Error(config.ValidateAndDefault())
+	config.Vault.URL = ""
+	config.AWS.Secret = ""
+	s.Error(config.ValidateAndDefault())
+	config.AWS.Key = ""
+	s.NoError(config.ValidateAndDefault())
+	s.False(config.AWSEnabled())
+	s.False(config.VaultEnabled())
+}
+
+func (s *AdminSuite) TestDataLifecycleConfig() {
+	config := DataLifecycleConfig{ArchiveAfterDays: 365}
+	s.NoError(config.ValidateAndDefault())
+	s.Equal(ArchiveTargetCollection, config.Target)
+	s.Equal(1000, config.BatchSize)
+
+	err := config.Set()
+	s.NoError(err)
+	settings, err := GetConfig()
+	s.NoError(err)
+	s.NotNil(settings)
+	s.Equal(config, settings.DataLifecycle)
+
+	now := time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC)
+	s.Equal(time.Date(2018, time.March, 1, 0, 0, 0, 0, time.UTC), config.ArchiveCutoff(now))
+	config.ArchiveAfterDays = 0
+	s.True(config.ArchiveCutoff(now).IsZero())
+
+	config.ArchiveAfterDays = -1
+	s.Error(config.ValidateAndDefault())
+	config.ArchiveAfterDays = 30
+	config.Target = "tape"
+	s.Error(config.ValidateAndDefault())
+	config.Target = ArchiveTargetS3
+	s.Error(config.ValidateAndDefault())
+	config.Bucket = "bucket"
+	config.Key = "key"
+	config.Secret = "secret"
+	s.NoError(config.ValidateAndDefault())
+	s.Equal("us-east-1", config.Region)
+}
+
 func (s *AdminSuite) TestAlertsConfig() {
 	config := AlertsConfig{
 		SMTP: SMTPConfig{
@@ -230,6 +364,8 @@ func (s *AdminSuite) TestApiConfig() {
 	config := APIConfig{
 		HttpListenAddr:      "addr",
 		GithubWebhookSecret: "secret",
+		GithubAppID:         1234,
+		GithubAppKey:        "key",
 	}
 
 	err := config.Set()
@@ -276,7 +412,10 @@ func (s *AdminSuite) TestAuthConfig() {
 
 func (s *AdminSuite) TestHostinitConfig() {
 	config := HostInitConfig{
-		SSHTimeoutSeconds: 10,
+		SSHTimeoutSeconds:           10,
+		ProblemHostFailureThreshold: 5,
+		ProblemHostWindowMinutes:    30,
+		TerminateProblemHosts:       true,
 	}
 
 	err := config.Set()
@@ -327,6 +466,12 @@ func (s *AdminSuite) TestProvidersConfig() {
 			ProjectID:        "project_id",
 			Region:           "region",
 		},
+		Azure: AzureConfig{
+			TenantID:       "tenant",
+			ClientID:       "azure_client",
+			ClientSecret:   "azure_secret",
+			SubscriptionID: "subscription",
+		},
 		VSphere: VSphereConfig{
 			Host:     "host",
 			Username: "vsphere",
@@ -377,8 +522,9 @@ func (s *AdminSuite) TestSlackConfig() {
 			Fields:    true,
 			FieldsSet: map[string]bool{},
 		},
-		Token: "token",
-		Level: "info",
+		Token:         "token",
+		Level:         "info",
+		SigningSecret: "signing_secret",
 	}
 
 	err := config.Set()
@@ -557,6 +703,27 @@ func (s *AdminSuite) TestContainerPoolsConfig() {
 	s.NotNil(lookup)
 	s.Equal(*lookup, validConfig.Pools[0])
 
+	mixedConfig := ContainerPoolsConfig{
+		Pools: []ContainerPool{
+			ContainerPool{
+				Distro:        "d1",
+				WindowsDistro: "d1",
+				Id:            "test-pool-1",
+				MaxContainers: 100,
+			},
+		},
+	}
+	s.EqualError(mixedConfig.ValidateAndDefault(), "container pool field windows_distro must differ from distro")
+
+	mixedConfig.Pools[0].WindowsDistro = "d1-windows"
+	s.NoError(mixedConfig.ValidateAndDefault())
+	s.True(mixedConfig.Pools[0].IsMixed())
+	s.Equal("d1-windows", mixedConfig.Pools[0].ParentDistro(true))
+	s.Equal("d1", mixedConfig.Pools[0].ParentDistro(false))
+	s.Equal([]string{"d1", "d1-windows"}, mixedConfig.Pools[0].ParentDistros())
+	s.False(validConfig.Pools[1].IsMixed())
+	s.Equal("d2", validConfig.Pools[1].ParentDistro(true))
+
 	lookup = settings.ContainerPools.GetContainerPool("test-pool-2")
 	s.NotNil(lookup)
 	s.Equal(*lookup, validConfig.Pools[1])
@@ -625,3 +792,52 @@ func (s *AdminSuite) TestJIRANotificationsConfig() {
 	}
 	s.EqualError(c.ValidateAndDefault(), "template: this-is:1: unexpected \"}\" in operand")
 }
+
+func TestAgentUpdateRollout(t *testing.T) {
+	assert := assert.New(t)
+
+	config := AgentUpdateConfig{Version: "abcdef", RolloutPercent: 50}
+	updated := 0
+	for i := 0; i < 1000; i++ {
+		hostId := fmt.Sprintf("host-%d", i)
+		version := config.DesiredVersion("distro", hostId)
+		assert.Equal(version, config.DesiredVersion("distro", hostId))
+		if version == config.Version {
+			updated++
+		}
+	}
+	assert.True(updated > 400 && updated < 600, "%d of 1000 hosts were updated", updated)
+
+	// hosts in a rollout stay in it as the rollout grows
+	config.RolloutPercent = 20
+	inRollout := []string{}
+	for i := 0; i < 100; i++ {
+		hostId := fmt.Sprintf("host-%d", i)
+		if config.DesiredVersion("distro", hostId) == config.Version {
+			inRollout = append(inRollout, hostId)
+		}
+	}
+	config.RolloutPercent = 60
+	for _, hostId := range inRollout {
+		assert.Equal(config.Version, config.DesiredVersion("distro", hostId))
+	}
+}
+
+func TestDockerConfigValidateAndDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	providers := CloudProviders{}
+	assert.NoError(providers.ValidateAndDefault())
+	assert.Equal(int64(150*1024*1024*1024), providers.Docker.PruneThreshold())
+	assert.Equal(24*time.Hour, providers.Docker.PruneUnusedFor())
+
+	providers.Docker = DockerConfig{PruneThresholdGB: 20, PruneUnusedHours: 2}
+	assert.NoError(providers.ValidateAndDefault())
+	assert.Equal(int64(20*1024*1024*1024), providers.Docker.PruneThreshold())
+	assert.Equal(2*time.Hour, providers.Docker.PruneUnusedFor())
+
+	providers.Docker = DockerConfig{PruneThresholdGB: -1}
+	assert.Error(providers.ValidateAndDefault())
+	providers.Docker = DockerConfig{PruneUnusedHours: -1}
+	assert.Error(providers.ValidateAndDefault())
+}
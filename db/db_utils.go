@@ -3,7 +3,9 @@ package db
 import (
 	"fmt"
 	"io"
+	"time"
 
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
@@ -18,8 +20,16 @@ var (
 	NoLimit      = 0
 )
 
+var dbOperationDuration = util.NewPrometheusHistogramVec("evergreen_db_operation_duration_seconds",
+	"Duration of database operations, by operation and collection.", util.DefaultPrometheusBuckets, "operation", "collection")
+
+func observeOperation(operation, collection string, start time.Time) {
+	dbOperationDuration.Observe(time.Since(start).Seconds(), operation, collection)
+}
+
 // Insert inserts the specified item into the specified collection.
 func Insert(collection string, item interface{}) error {
+	defer observeOperation("insert", collection, time.Now())
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return errors.WithStack(err)
@@ -33,6 +43,7 @@ func InsertMany(collection string, items ...interface{}) error {
 	if len(items) == 0 {
 		return nil
 	}
+	defer observeOperation("insert_many", collection, time.Now())
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return errors.WithStack(err)
@@ -46,6 +57,7 @@ func InsertManyUnordered(c string, items ...interface{}) error {
 	if len(items) == 0 {
 		return nil
 	}
+	defer observeOperation("insert_many", c, time.Now())
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return errors.WithStack(err)
@@ -105,6 +117,7 @@ func DropIndex(collection string, key ...string) error {
 
 // Remove removes one item matching the query from the specified collection.
 func Remove(collection string, query interface{}) error {
+	defer observeQuery("remove", collection, query, nil, time.Now())
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return err
@@ -116,6 +129,7 @@ func Remove(collection string, query interface{}) error {
 
 // RemoveAll removes all items matching the query from the specified collection.
 func RemoveAll(collection string, query interface{}) error {
+	defer observeQuery("remove_all", collection, query, nil, time.Now())
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return err
@@ -130,6 +144,7 @@ func RemoveAll(collection string, query interface{}) error {
 // provided interface, which must be a pointer.
 func FindOne(collection string, query interface{},
 	projection interface{}, sort []string, out interface{}) error {
+	defer observeQuery("find_one", collection, query, sort, time.Now())
 
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
@@ -150,6 +165,7 @@ func FindOne(collection string, query interface{},
 func FindAll(collection string, query interface{},
 	projection interface{}, sort []string, skip int, limit int,
 	out interface{}) error {
+	defer observeQuery("find_all", collection, query, sort, time.Now())
 
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
@@ -169,6 +185,7 @@ func FindAll(collection string, query interface{},
 // Update updates one matching document in the collection.
 func Update(collection string, query interface{},
 	update interface{}) error {
+	defer observeQuery("update", collection, query, nil, time.Now())
 
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
@@ -183,6 +200,7 @@ func Update(collection string, query interface{},
 
 // UpdateId updates one _id-matching document in the collection.
 func UpdateId(collection string, id, update interface{}) error {
+	defer observeQuery("update", collection, bson.M{"_id": id}, nil, time.Now())
 
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
@@ -197,6 +215,7 @@ func UpdateId(collection string, id, update interface{}) error {
 
 // UpdateAll updates all matching documents in the collection.
 func UpdateAll(collection string, query interface{}, update interface{}) (*mgo.ChangeInfo, error) {
+	defer observeQuery("update_all", collection, query, nil, time.Now())
 	switch query.(type) {
 	case *Q, Q:
 		grip.EmergencyPanic(message.Fields{
@@ -227,6 +246,7 @@ func UpdateAll(collection string, query interface{}, update interface{}) (*mgo.C
 // Upsert run the specified update against the collection as an upsert operation.
 func Upsert(collection string, query interface{},
 	update interface{}) (*mgo.ChangeInfo, error) {
+	defer observeQuery("upsert", collection, query, nil, time.Now())
 
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
@@ -241,6 +261,7 @@ func Upsert(collection string, query interface{},
 
 // Count run a count command with the specified query against the collection.
 func Count(collection string, query interface{}) (int, error) {
+	defer observeQuery("count", collection, query, nil, time.Now())
 
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
@@ -257,6 +278,7 @@ func Count(collection string, query interface{}) (int, error) {
 // unmarshaling the result into the specified interface.
 func FindAndModify(collection string, query interface{}, sort []string,
 	change mgo.Change, out interface{}) (*mgo.ChangeInfo, error) {
+	defer observeQuery("find_and_modify", collection, query, sort, time.Now())
 
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
@@ -282,8 +304,24 @@ func WriteGridFile(fsPrefix, name string, source io.Reader) error {
 		return err
 	}
 	defer file.Close()
-	_, err = io.Copy(file, source)
-	return err
+	if _, err = io.Copy(file, source); err != nil {
+		// don't leave a partial file behind
+		file.Abort()
+		return err
+	}
+	return nil
+}
+
+// DeleteGridFile removes every file stored with the given name under the
+// GridFS prefix.
+func DeleteGridFile(fsPrefix, name string) error {
+	session, db, err := GetGlobalSessionFactory().GetSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	return errors.WithStack(db.GridFS(fsPrefix).Remove(name))
 }
 
 type sessionBackedGridFile struct {
@@ -320,6 +358,7 @@ func ClearGridCollections(fsPrefix string) error {
 // the results to the given "out" interface (usually a pointer
 // to an array of structs/bson.M)
 func Aggregate(collection string, pipeline interface{}, out interface{}) error {
+	defer observeQuery("aggregate", collection, pipelineMatch(pipeline), nil, time.Now())
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		err = errors.Wrap(err, "error establishing db connection")
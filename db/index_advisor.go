@@ -0,0 +1,502 @@
+package db
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// SlowQueryThreshold is how long an operation runs before it's
+	// reported as a slow query.
+	SlowQueryThreshold = 100 * time.Millisecond
+
+	// maxQueryPatterns bounds the number of distinct query patterns that
+	// are sampled, and maxSlowQueries the number of the most recent slow
+	// queries that are kept.
+	maxQueryPatterns = 1000
+	maxSlowQueries   = 100
+)
+
+// QueryPattern is the shape of the queries run against a collection: the
+// fields that they filter on and sort by, without their values, along with
+// how long the queries took.
+type QueryPattern struct {
+	Collection     string
+	EqualityFields []string
+	RangeFields    []string
+	Sort           []string
+	Count          int
+	SlowCount      int
+	TotalDuration  time.Duration
+	MaxDuration    time.Duration
+	LastSeen       time.Time
+}
+
+func (p *QueryPattern) key() string {
+	return strings.Join([]string{
+		p.Collection,
+		strings.Join(p.EqualityFields, ","),
+		strings.Join(p.RangeFields, ","),
+		strings.Join(p.Sort, ","),
+	}, "|")
+}
+
+// String describes the pattern's filter and sort, in the shape of a query.
+func (p *QueryPattern) String() string {
+	filter := []string{}
+	for _, f := range p.EqualityFields {
+		filter = append(filter, f+": <value>")
+	}
+	for _, f := range p.RangeFields {
+		filter = append(filter, f+": <range>")
+	}
+	out := "{" + strings.Join(filter, ", ") + "}"
+	if len(p.Sort) > 0 {
+		out += " sort " + strings.Join(p.Sort, ",")
+	}
+
+	return out
+}
+
+// SuggestedIndex returns the keys of an index for the pattern, which puts
+// the fields matched exactly first, then the sort, then the ranges.
+func (p *QueryPattern) SuggestedIndex() []string {
+	keys := append([]string{}, p.EqualityFields...)
+	seen := map[string]bool{}
+	for _, f := range keys {
+		seen[f] = true
+	}
+	for _, s := range p.Sort {
+		if f := strings.TrimPrefix(s, "-"); !seen[f] {
+			seen[f] = true
+			keys = append(keys, s)
+		}
+	}
+	for _, f := range p.RangeFields {
+		if !seen[f] {
+			seen[f] = true
+			keys = append(keys, f)
+		}
+	}
+
+	return keys
+}
+
+// fields returns the fields that the pattern filters on.
+func (p *QueryPattern) fields() map[string]bool {
+	out := map[string]bool{}
+	for _, f := range p.EqualityFields {
+		out[f] = true
+	}
+	for _, f := range p.RangeFields {
+		out[f] = true
+	}
+
+	return out
+}
+
+// canUseIndex reports whether the pattern's queries can use the index with
+// the given keys, which they can when they filter on its first field, or
+// when they don't filter and sort by it.
+func (p *QueryPattern) canUseIndex(keys []string) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	first := indexKeyField(keys[0])
+	if p.fields()[first] {
+		return true
+	}
+
+	return len(p.EqualityFields) == 0 && len(p.RangeFields) == 0 &&
+		len(p.Sort) > 0 && strings.TrimPrefix(p.Sort[0], "-") == first
+}
+
+// SlowQuery is an operation that took longer than the SlowQueryThreshold.
+type SlowQuery struct {
+	Collection string
+	Operation  string
+	Pattern    string
+	Duration   time.Duration
+	Time       time.Time
+}
+
+type querySampler struct {
+	mu       sync.Mutex
+	patterns map[string]*QueryPattern
+	slow     []SlowQuery
+}
+
+var globalQuerySampler = &querySampler{patterns: map[string]*QueryPattern{}}
+
+// observeQuery records the duration of an operation that runs a query,
+// and samples the query's pattern.
+func observeQuery(operation, collection string, query interface{}, sortKeys []string, start time.Time) {
+	observeOperation(operation, collection, start)
+	globalQuerySampler.sample(operation, collection, query, sortKeys, time.Since(start))
+}
+
+func (s *querySampler) sample(operation, collection string, query interface{}, sortKeys []string, duration time.Duration) {
+	pattern := &QueryPattern{Collection: collection, Sort: append([]string{}, sortKeys...)}
+	pattern.EqualityFields, pattern.RangeFields = queryFields(query)
+	key := pattern.key()
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	existing, ok := s.patterns[key]
+	if !ok {
+		if len(s.patterns) >= maxQueryPatterns {
+			return
+		}
+		existing = pattern
+		s.patterns[key] = existing
+	}
+	existing.Count++
+	existing.TotalDuration += duration
+	if duration > existing.MaxDuration {
+		existing.MaxDuration = duration
+	}
+	existing.LastSeen = now
+
+	if duration >= SlowQueryThreshold {
+		existing.SlowCount++
+		s.slow = append(s.slow, SlowQuery{
+			Collection: collection,
+			Operation:  operation,
+			Pattern:    existing.String(),
+			Duration:   duration,
+			Time:       now,
+		})
+		if len(s.slow) > maxSlowQueries {
+			s.slow = s.slow[len(s.slow)-maxSlowQueries:]
+		}
+	}
+}
+
+func (s *querySampler) getPatterns() []QueryPattern {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]QueryPattern, 0, len(s.patterns))
+	for _, p := range s.patterns {
+		out = append(out, *p)
+	}
+
+	return out
+}
+
+// GetSlowQueries returns the most recent slow queries, most recent first.
+func GetSlowQueries() []SlowQuery {
+	s := globalQuerySampler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]SlowQuery, 0, len(s.slow))
+	for i := len(s.slow) - 1; i >= 0; i-- {
+		out = append(out, s.slow[i])
+	}
+
+	return out
+}
+
+// ResetQuerySamples discards the sampled query patterns and slow queries,
+// for example to check the effect of adding an index.
+func ResetQuerySamples() {
+	s := globalQuerySampler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.patterns = map[string]*QueryPattern{}
+	s.slow = nil
+}
+
+// pipelineMatch returns the filter of an aggregation pipeline's first stage,
+// when it's a $match, since only that stage can use the collection's indexes.
+func pipelineMatch(pipeline interface{}) interface{} {
+	var first interface{}
+	switch stages := pipeline.(type) {
+	case []bson.M:
+		if len(stages) > 0 {
+			first = stages[0]
+		}
+	case []interface{}:
+		if len(stages) > 0 {
+			first = stages[0]
+		}
+	}
+	stage, ok := asDoc(first)
+	if !ok || len(stage) == 0 || stage[0].Name != "$match" {
+		return nil
+	}
+
+	return stage[0].Value
+}
+
+// queryFields returns the fields that a query matches exactly, and the
+// fields that it matches with other operators, such as ranges.
+func queryFields(query interface{}) ([]string, []string) {
+	equality := map[string]bool{}
+	ranges := map[string]bool{}
+	collectQueryFields(query, "", equality, ranges)
+
+	for f := range equality {
+		delete(ranges, f)
+	}
+
+	return sortedKeys(equality), sortedKeys(ranges)
+}
+
+func collectQueryFields(query interface{}, prefix string, equality, ranges map[string]bool) {
+	doc, ok := queryDoc(query)
+	if !ok {
+		return
+	}
+	for _, elem := range doc {
+		switch elem.Name {
+		case "$and", "$or", "$nor":
+			if clauses, ok := elem.Value.([]interface{}); ok {
+				for _, c := range clauses {
+					collectQueryFields(c, prefix, equality, ranges)
+				}
+			} else if clauses, ok := elem.Value.([]bson.M); ok {
+				for _, c := range clauses {
+					collectQueryFields(c, prefix, equality, ranges)
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(elem.Name, "$") {
+			continue
+		}
+
+		field := prefix + elem.Name
+		value, ok := asDoc(elem.Value)
+		if !ok || len(value) == 0 || !strings.HasPrefix(value[0].Name, "$") {
+			equality[field] = true
+			continue
+		}
+		for _, op := range value {
+			switch op.Name {
+			case "$eq", "$in":
+				equality[field] = true
+			case "$elemMatch":
+				ranges[field] = true
+				collectQueryFields(op.Value, field+".", equality, ranges)
+			default:
+				ranges[field] = true
+			}
+		}
+	}
+}
+
+// asDoc converts the document types that queries are built from to a
+// bson.D, ordering the fields of maps by name.
+func asDoc(v interface{}) (bson.D, bool) {
+	switch d := v.(type) {
+	case bson.D:
+		return d, true
+	case bson.M:
+		return mapToDoc(d), true
+	case map[string]interface{}:
+		return mapToDoc(d), true
+	}
+
+	return nil, false
+}
+
+// queryDoc converts a query to a bson.D, including queries that are
+// structs, which are marshaled.
+func queryDoc(query interface{}) (bson.D, bool) {
+	if query == nil {
+		return nil, false
+	}
+	if doc, ok := asDoc(query); ok {
+		return doc, true
+	}
+
+	raw, err := bson.Marshal(query)
+	if err != nil {
+		return nil, false
+	}
+	doc := bson.D{}
+	if err = bson.Unmarshal(raw, &doc); err != nil {
+		return nil, false
+	}
+
+	return doc, true
+}
+
+func mapToDoc(m map[string]interface{}) bson.D {
+	doc := make(bson.D, 0, len(m))
+	for _, k := range sortedKeys(toSet(m)) {
+		doc = append(doc, bson.DocElem{Name: k, Value: m[k]})
+	}
+
+	return doc
+}
+
+func toSet(m map[string]interface{}) map[string]bool {
+	out := make(map[string]bool, len(m))
+	for k := range m {
+		out[k] = true
+	}
+
+	return out
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+// indexKeyField returns the field of an index key, which may be prefixed
+// with its direction or type, such as "-field" or "$text:field".
+func indexKeyField(key string) string {
+	if strings.HasPrefix(key, "$") {
+		if i := strings.Index(key, ":"); i >= 0 {
+			key = key[i+1:]
+		}
+	}
+
+	return strings.TrimLeft(key, "-+")
+}
+
+// IndexSuggestion is an index that would serve the queries of a pattern,
+// none of which can use an existing index.
+type IndexSuggestion struct {
+	Keys    []string
+	Pattern QueryPattern
+}
+
+// IndexUsage is an existing index and the number of times it has been used
+// since the time it was first tracked, which is -1 when the server does not
+// report index usage.
+type IndexUsage struct {
+	Name     string
+	Keys     []string
+	Accesses int64
+	Since    time.Time
+}
+
+// IndexReport compares the query patterns sampled for a collection with its
+// indexes, suggesting indexes for patterns that can't use one and reporting
+// the indexes that go unused.
+type IndexReport struct {
+	Collection     string
+	Patterns       []QueryPattern
+	MissingIndexes []IndexSuggestion
+	UnusedIndexes  []IndexUsage
+}
+
+type indexStats struct {
+	Name     string `bson:"name"`
+	Accesses struct {
+		Ops   int64     `bson:"ops"`
+		Since time.Time `bson:"since"`
+	} `bson:"accesses"`
+}
+
+// GetIndexReports returns a report for each collection that queries have
+// been sampled for, ordered by the total time spent running its queries.
+func GetIndexReports() ([]IndexReport, error) {
+	byCollection := map[string][]QueryPattern{}
+	for _, p := range globalQuerySampler.getPatterns() {
+		byCollection[p.Collection] = append(byCollection[p.Collection], p)
+	}
+	if len(byCollection) == 0 {
+		return []IndexReport{}, nil
+	}
+
+	session, db, err := GetGlobalSessionFactory().GetSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "error establishing db connection")
+	}
+	defer session.Close()
+
+	reports := make([]IndexReport, 0, len(byCollection))
+	totals := map[string]time.Duration{}
+	for collection, patterns := range byCollection {
+		c := db.C(collection)
+		indexes, err := c.Indexes()
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem listing indexes of collection '%s'", collection)
+		}
+		stats := []indexStats{}
+		if err = c.Pipe([]bson.M{{"$indexStats": bson.M{}}}).All(&stats); err != nil {
+			stats = nil
+		}
+
+		reports = append(reports, buildIndexReport(collection, patterns, indexes, stats))
+		for _, p := range patterns {
+			totals[collection] += p.TotalDuration
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return totals[reports[i].Collection] > totals[reports[j].Collection]
+	})
+
+	return reports, nil
+}
+
+// buildIndexReport compares a collection's query patterns with its indexes.
+// The index usage the server reports is used to find unused indexes when
+// it's given, and otherwise the indexes that no pattern can use are
+// reported.
+func buildIndexReport(collection string, patterns []QueryPattern, indexes []mgo.Index, stats []indexStats) IndexReport {
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].TotalDuration > patterns[j].TotalDuration })
+	report := IndexReport{
+		Collection:     collection,
+		Patterns:       patterns,
+		MissingIndexes: []IndexSuggestion{},
+		UnusedIndexes:  []IndexUsage{},
+	}
+
+	used := map[string]bool{}
+	for _, p := range patterns {
+		usable := false
+		for _, idx := range indexes {
+			if p.canUseIndex(idx.Key) {
+				usable = true
+				used[idx.Name] = true
+			}
+		}
+		if !usable && (len(p.EqualityFields) > 0 || len(p.RangeFields) > 0 || len(p.Sort) > 0) {
+			report.MissingIndexes = append(report.MissingIndexes, IndexSuggestion{Keys: p.SuggestedIndex(), Pattern: p})
+		}
+	}
+
+	accesses := map[string]indexStats{}
+	for _, s := range stats {
+		accesses[s.Name] = s
+	}
+	for _, idx := range indexes {
+		if idx.Name == "_id_" {
+			continue
+		}
+		usage := IndexUsage{Name: idx.Name, Keys: idx.Key, Accesses: -1}
+		if stats != nil {
+			s, ok := accesses[idx.Name]
+			if !ok || s.Accesses.Ops > 0 {
+				continue
+			}
+			usage.Accesses = s.Accesses.Ops
+			usage.Since = s.Accesses.Since
+		} else if used[idx.Name] {
+			continue
+		}
+		report.UnusedIndexes = append(report.UnusedIndexes, usage)
+	}
+
+	return report
+}
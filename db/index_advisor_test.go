@@ -0,0 +1,139 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestQueryFields(t *testing.T) {
+	assert := assert.New(t)
+
+	for name, test := range map[string]struct {
+		query    interface{}
+		equality []string
+		ranges   []string
+	}{
+		"Nil":       {nil, []string{}, []string{}},
+		"Equality":  {bson.M{"r": "abc", "branch": "master"}, []string{"branch", "r"}, []string{}},
+		"Operators": {bson.M{"branch": "master", "order": bson.M{"$lt": 10}, "status": bson.M{"$in": []string{"a"}}}, []string{"branch", "status"}, []string{"order"}},
+		"Document":  {bson.D{{Name: "a", Value: 1}, {Name: "b", Value: bson.D{{Name: "$exists", Value: true}}}}, []string{"a"}, []string{"b"}},
+		"Logical": {
+			bson.M{"$or": []bson.M{{"a": 1}, {"b": bson.M{"$gt": 2}}}, "$and": []interface{}{bson.M{"c": 1}}},
+			[]string{"a", "c"}, []string{"b"},
+		},
+		"ElemMatch": {bson.M{"tasks": bson.M{"$elemMatch": bson.M{"id": "t1"}}}, []string{"tasks.id"}, []string{"tasks"}},
+		"Struct": {
+			struct {
+				Project string `bson:"project"`
+			}{Project: "mci"},
+			[]string{"project"}, []string{},
+		},
+	} {
+		equality, ranges := queryFields(test.query)
+		assert.Equal(test.equality, equality, name)
+		assert.Equal(test.ranges, ranges, name)
+	}
+
+	assert.Equal(bson.M{"a": 1}, pipelineMatch([]bson.M{{"$match": bson.M{"a": 1}}, {"$limit": 1}}))
+	assert.Nil(pipelineMatch([]bson.M{{"$sort": bson.M{"a": 1}}}))
+	assert.Nil(pipelineMatch(nil))
+}
+
+func TestQueryPattern(t *testing.T) {
+	assert := assert.New(t)
+
+	p := QueryPattern{
+		Collection:     "versions",
+		EqualityFields: []string{"branch", "r"},
+		RangeFields:    []string{"create_time"},
+		Sort:           []string{"-order", "r"},
+	}
+	assert.Equal([]string{"branch", "r", "-order", "create_time"}, p.SuggestedIndex())
+	assert.Equal("{branch: <value>, r: <value>, create_time: <range>} sort -order,r", p.String())
+
+	assert.True(p.canUseIndex([]string{"r", "branch"}))
+	assert.True(p.canUseIndex([]string{"-create_time"}))
+	assert.False(p.canUseIndex([]string{"order"}))
+	assert.False(p.canUseIndex(nil))
+
+	sortOnly := QueryPattern{Sort: []string{"-order"}}
+	assert.True(sortOnly.canUseIndex([]string{"order", "r"}))
+	assert.False(sortOnly.canUseIndex([]string{"r", "order"}))
+
+	assert.Equal("field", indexKeyField("-field"))
+	assert.Equal("field", indexKeyField("$text:field"))
+	assert.Equal("field", indexKeyField("field"))
+}
+
+func TestQuerySampler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	s := &querySampler{patterns: map[string]*QueryPattern{}}
+	s.sample("find_one", "versions", bson.M{"r": "abc", "branch": "master"}, nil, time.Millisecond)
+	s.sample("find_one", "versions", bson.M{"branch": "other", "r": "def"}, nil, 2*SlowQueryThreshold)
+	s.sample("find_all", "versions", bson.M{"branch": "master"}, []string{"-order"}, time.Millisecond)
+
+	patterns := s.getPatterns()
+	require.Len(patterns, 2)
+	for _, p := range patterns {
+		if len(p.Sort) > 0 {
+			assert.Equal(1, p.Count)
+			continue
+		}
+		assert.Equal(2, p.Count)
+		assert.Equal(1, p.SlowCount)
+		assert.Equal(2*SlowQueryThreshold, p.MaxDuration)
+		assert.Equal(2*SlowQueryThreshold+time.Millisecond, p.TotalDuration)
+	}
+	require.Len(s.slow, 1)
+	assert.Equal("find_one", s.slow[0].Operation)
+	assert.Equal("{branch: <value>, r: <value>}", s.slow[0].Pattern)
+
+	for i := 0; i < maxSlowQueries+10; i++ {
+		s.sample("count", "hosts", bson.M{"status": "running"}, nil, SlowQueryThreshold)
+	}
+	assert.Len(s.slow, maxSlowQueries)
+	assert.Equal("hosts", s.slow[len(s.slow)-1].Collection)
+}
+
+func TestBuildIndexReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	patterns := []QueryPattern{
+		{Collection: "versions", EqualityFields: []string{"branch", "r"}, TotalDuration: time.Second},
+		{Collection: "versions", EqualityFields: []string{"author"}, Sort: []string{"-create_time"}, TotalDuration: time.Minute},
+		{Collection: "versions", TotalDuration: time.Millisecond},
+	}
+	indexes := []mgo.Index{
+		{Name: "_id_", Key: []string{"_id"}},
+		{Name: "branch_1_r_1", Key: []string{"branch", "r"}},
+		{Name: "order_1", Key: []string{"order"}},
+		{Name: "create_time_-1", Key: []string{"-create_time"}},
+	}
+
+	report := buildIndexReport("versions", patterns, indexes, nil)
+	assert.Equal("versions", report.Collection)
+	require.Len(report.Patterns, 3)
+	assert.Equal([]string{"author"}, report.Patterns[0].EqualityFields)
+	require.Len(report.MissingIndexes, 1)
+	assert.Equal([]string{"author", "-create_time"}, report.MissingIndexes[0].Keys)
+	require.Len(report.UnusedIndexes, 2)
+	assert.Equal("order_1", report.UnusedIndexes[0].Name)
+	assert.EqualValues(-1, report.UnusedIndexes[0].Accesses)
+	assert.Equal("create_time_-1", report.UnusedIndexes[1].Name)
+
+	stats := []indexStats{{Name: "_id_"}, {Name: "branch_1_r_1"}, {Name: "order_1"}, {Name: "create_time_-1"}}
+	stats[1].Accesses.Ops = 10
+	stats[2].Accesses.Ops = 3
+	report = buildIndexReport("versions", patterns, indexes, stats)
+	require.Len(report.UnusedIndexes, 1)
+	assert.Equal("create_time_-1", report.UnusedIndexes[0].Name)
+	assert.EqualValues(0, report.UnusedIndexes[0].Accesses)
+}
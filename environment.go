@@ -2,10 +2,13 @@ package evergreen
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/gob"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -45,8 +48,10 @@ func GetEnvironment() Environment { return globalEnvState }
 
 func ResetEnvironment() {
 	globalEnvState = &envState{
-		senders: map[SenderKey]send.Sender{},
-		closers: map[string]func(context.Context) error{},
+		senders:        map[SenderKey]send.Sender{},
+		senderVersions: map[SenderKey]string{},
+		rootSenders:    map[SenderKey]send.Sender{},
+		closers:        map[string]func(context.Context) error{},
 	}
 }
 
@@ -91,6 +96,20 @@ type Environment interface {
 	// all message details.
 	GetSender(SenderKey) (send.Sender, error)
 
+	// RotateSenderCredentials rebuilds any senders whose credentials
+	// differ from the credentials in the given settings, so that
+	// subsequent notifications are sent with the new credentials
+	// without restarting the process.
+	RotateSenderCredentials(*Settings) error
+	// GetSenderCredentialVersion returns a fingerprint of the
+	// credentials that the sender for the key was built with.
+	GetSenderCredentialVersion(SenderKey) string
+	// ReloadSettings replaces the settings object with the given
+	// settings, e.g. after they were changed in the DB, and rotates
+	// the sender credentials to match. Settings that are not
+	// persisted, such as the database settings, are kept.
+	ReloadSettings(*Settings) error
+
 	// RegisterCloser adds a function object to an internal
 	// tracker to be called by the Close method before process
 	// termination. The ID is used in reporting, but must be
@@ -122,6 +141,15 @@ type envState struct {
 	clientConfig       *ClientConfig
 	closers            map[string]func(context.Context) error
 	senders            map[SenderKey]send.Sender
+	senderVersions     map[SenderKey]string
+	rootSenders        map[SenderKey]send.Sender
+	retiringSenders    []*retiringSender
+}
+
+// retiringSender is a sender that was replaced by one with new credentials,
+// and is closed once the notifications queued for it have been sent.
+type retiringSender struct {
+	sender send.Sender
 }
 
 // Configure requires that either the path or DB is sent so that it can construct the
@@ -249,9 +277,8 @@ func (e *envState) createQueues(ctx context.Context) error {
 	if err = e.notificationsQueue.SetRunner(runner); err != nil {
 		return errors.Wrap(err, "failed to set notifications queue runner")
 	}
-	rootSenders := []send.Sender{}
-	for _, s := range e.senders {
-		rootSenders = append(rootSenders, s)
+	for k, s := range e.senders {
+		e.rootSenders[k] = s
 	}
 
 	// duration of time in between calls to queue.Status() within
@@ -291,18 +318,29 @@ func (e *envState) createQueues(ctx context.Context) error {
 
 		e.notificationsQueue.Runner().Close()
 
+		e.mu.Lock()
+		senders := make([]send.Sender, 0, len(e.rootSenders)+len(e.retiringSenders))
+		for _, s := range e.rootSenders {
+			senders = append(senders, s)
+		}
+		for _, retiring := range e.retiringSenders {
+			senders = append(senders, retiring.sender)
+		}
+		e.retiringSenders = nil
+		e.mu.Unlock()
+
 		grip.Debug(message.Fields{
 			"message":     "closed notification queue",
-			"num_senders": len(rootSenders),
+			"num_senders": len(senders),
 			"errors":      catcher.HasErrors(),
 		})
 
-		for _, s := range rootSenders {
+		for _, s := range senders {
 			catcher.Add(s.Close())
 		}
 		grip.Debug(message.Fields{
 			"message":     "closed all root senders",
-			"num_senders": len(rootSenders),
+			"num_senders": len(senders),
 			"errors":      catcher.HasErrors(),
 		})
 
@@ -350,13 +388,31 @@ func (e *envState) initSenders() error {
 		return errors.New("no settings object, cannot build senders")
 	}
 
+	senders, versions, err := makeSenders(e.settings)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for key := range senders {
+		e.senders[key] = senders[key]
+		e.senderVersions[key] = versions[key]
+	}
+
+	return nil
+}
+
+// makeSenders builds the notification senders configured by the settings,
+// along with the version of the credentials each sender was built with.
+func makeSenders(settings *Settings) (map[SenderKey]send.Sender, map[SenderKey]string, error) {
+	senders := map[SenderKey]send.Sender{}
+	versions := senderCredentialVersions(settings)
+
 	levelInfo := send.LevelInfo{
 		Default:   level.Notice,
 		Threshold: level.Notice,
 	}
 
-	if e.settings.Notify.SMTP.From != "" {
-		smtp := e.settings.Notify.SMTP
+	if settings.Notify.SMTP.From != "" {
+		smtp := settings.Notify.SMTP
 		opts := send.SMTPOptions{
 			Name:              "evergreen",
 			Server:            smtp.Server,
@@ -370,37 +426,52 @@ func (e *envState) initSenders() error {
 		}
 		if len(smtp.AdminEmail) == 0 {
 			if err := opts.AddRecipient("", "test@domain.invalid"); err != nil {
-				return errors.Wrap(err, "failed to setup email logger")
+				return nil, nil, errors.Wrap(err, "failed to setup email logger")
 			}
 
 		} else {
 			for i := range smtp.AdminEmail {
 				if err := opts.AddRecipient("", smtp.AdminEmail[i]); err != nil {
-					return errors.Wrap(err, "failed to setup email logger")
+					return nil, nil, errors.Wrap(err, "failed to setup email logger")
 				}
 			}
 		}
 		sender, err := send.NewSMTPLogger(&opts, levelInfo)
 		if err != nil {
-			return errors.Wrap(err, "Failed to setup email logger")
+			return nil, nil, errors.Wrap(err, "Failed to setup email logger")
 		}
-		e.senders[SenderEmail] = sender
+		senders[SenderEmail] = util.NewEmailSender(sender, util.SMTPSettings{
+			Server:   smtp.Server,
+			Port:     smtp.Port,
+			UseSSL:   smtp.UseSSL,
+			Username: smtp.Username,
+			Password: smtp.Password,
+			From:     smtp.From,
+		})
 	}
 
 	var sender send.Sender
 
-	githubToken, err := e.settings.GetGithubOauthToken()
+	githubToken, err := settings.GetGithubOauthToken()
 	if err == nil && len(githubToken) > 0 {
 		sender, err = send.NewGithubStatusLogger("evergreen", &send.GithubOptions{
 			Token: githubToken,
 		}, "")
 		if err != nil {
-			return errors.Wrap(err, "Failed to setup github status logger")
+			return nil, nil, errors.Wrap(err, "Failed to setup github status logger")
 		}
-		e.senders[SenderGithubStatus] = sender
+		senders[SenderGithubStatus] = sender
 	}
 
-	if jira := &e.settings.Jira; len(jira.GetHostURL()) != 0 {
+	if api := &settings.Api; api.GithubAppID != 0 && len(api.GithubAppKey) != 0 {
+		sender, err = util.NewGithubChecksLogger(api.GithubAppID, []byte(api.GithubAppKey))
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "Failed to setup github checks logger")
+		}
+		senders[SenderGithubCheck] = sender
+	}
+
+	if jira := &settings.Jira; len(jira.GetHostURL()) != 0 {
 		sender, err = send.NewJiraLogger(&send.JiraOptions{
 			Name:         "evergreen",
 			BaseURL:      jira.GetHostURL(),
@@ -409,9 +480,9 @@ func (e *envState) initSenders() error {
 			UseBasicAuth: true,
 		}, levelInfo)
 		if err != nil {
-			return errors.Wrap(err, "Failed to setup jira issue logger")
+			return nil, nil, errors.Wrap(err, "Failed to setup jira issue logger")
 		}
-		e.senders[SenderJIRAIssue] = sender
+		senders[SenderJIRAIssue] = sender
 
 		sender, err = send.NewJiraCommentLogger("", &send.JiraOptions{
 			Name:         "evergreen",
@@ -421,40 +492,102 @@ func (e *envState) initSenders() error {
 			UseBasicAuth: true,
 		}, levelInfo)
 		if err != nil {
-			return errors.Wrap(err, "Failed to setup jira comment logger")
+			return nil, nil, errors.Wrap(err, "Failed to setup jira comment logger")
 		}
-		e.senders[SenderJIRAComment] = sender
+		senders[SenderJIRAComment] = sender
 	}
 
-	if slack := &e.settings.Slack; len(slack.Token) != 0 {
+	if slack := &settings.Slack; len(slack.Token) != 0 {
 		// this sender is initialised with an invalid channel. Any
 		// messages sent with it that do not use message.SlackMessage
 		// will not be received
+		iconURL := fmt.Sprintf("%s/static/img/evergreen_green_150x150.png", settings.Ui.Url)
 		sender, err = send.NewSlackLogger(&send.SlackOptions{
 			Channel:  "#",
 			Name:     "evergreen",
 			Username: "Evergreen",
-			IconURL:  fmt.Sprintf("%s/static/img/evergreen_green_150x150.png", e.settings.Ui.Url),
+			IconURL:  iconURL,
 		}, slack.Token, levelInfo)
 		if err != nil {
-			return errors.Wrap(err, "Failed to setup slack logger")
+			return nil, nil, errors.Wrap(err, "Failed to setup slack logger")
 		}
-		e.senders[SenderSlack] = sender
+		senders[SenderSlack] = util.NewSlackSender(sender, util.SlackSettings{
+			Token:    slack.Token,
+			Username: "Evergreen",
+			IconURL:  iconURL,
+		})
 	}
 
 	sender, err = util.NewEvergreenWebhookLogger()
 	if err != nil {
-		return errors.Wrap(err, "Failed to setup evergreen webhook logger")
+		return nil, nil, errors.Wrap(err, "Failed to setup evergreen webhook logger")
+	}
+	senders[SenderEvergreenWebhook] = sender
+
+	sender, err = util.NewOpsgenieLogger()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to setup opsgenie logger")
 	}
-	e.senders[SenderEvergreenWebhook] = sender
+	senders[SenderOpsgenie] = sender
 
 	catcher := grip.NewBasicCatcher()
-	for name, s := range e.senders {
+	for name, s := range senders {
+		s = util.NewNotificationSender(s, notificationSenderOptions(name, settings.Notify.RateLimits))
+		senders[name] = s
 		catcher.Add(s.SetLevel(levelInfo))
 		catcher.Add(s.SetErrorHandler(util.MakeNotificationErrorHandler(name.String())))
 	}
+	if catcher.HasErrors() {
+		return nil, nil, catcher.Resolve()
+	}
 
-	return catcher.Resolve()
+	return senders, versions, nil
+}
+
+const (
+	// senderRetireTimeout is the longest that a replaced sender is kept
+	// open for the notifications queued for it to be sent.
+	senderRetireTimeout = 10 * time.Minute
+	// senderRetireInterval is how often the notifications queue is checked
+	// for pending notifications while a replaced sender is kept open.
+	senderRetireInterval = time.Second
+
+	// notificationBreakerThreshold is the number of consecutive failures
+	// after which a sender stops sending for notificationBreakerCooldown
+	notificationBreakerThreshold = 5
+	notificationBreakerCooldown  = time.Minute
+)
+
+// notificationSenderOptions returns the limits on sending through the sender
+// with the given key.
+func notificationSenderOptions(key SenderKey, limits NotifyRateLimits) util.NotificationSenderOptions {
+	opts := util.NotificationSenderOptions{
+		BreakerThreshold: notificationBreakerThreshold,
+		BreakerCooldown:  notificationBreakerCooldown,
+	}
+
+	switch key {
+	case SenderSlack:
+		opts.PerMinute = limits.SlackPerMinute
+	case SenderJIRAIssue, SenderJIRAComment:
+		opts.PerMinute = limits.JIRAPerMinute
+	case SenderEmail:
+		opts.PerMinute = limits.EmailPerMinute
+	case SenderEvergreenWebhook, SenderOpsgenie, SenderGithubCheck:
+		// webhooks, opsgenie alerts and check runs are sent to many
+		// receivers, so one failing receiver must not stop the others from
+		// being sent to
+		opts.BreakerThreshold = 0
+	}
+
+	return opts
+}
+
+// credentialVersion returns a short fingerprint identifying a set of
+// credentials, which is safe to log and store.
+func credentialVersion(credentials ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(credentials, "\x00")))
+	return hex.EncodeToString(hash[:])[:12]
 }
 
 type BuildBaronProject struct {
@@ -572,6 +705,169 @@ func (e *envState) GetSender(key SenderKey) (send.Sender, error) {
 	return sender, nil
 }
 
+// senderCredentialVersions fingerprints the credentials of each sender
+// configured by the settings without building the senders.
+func senderCredentialVersions(settings *Settings) map[SenderKey]string {
+	versions := map[SenderKey]string{}
+
+	if smtp := settings.Notify.SMTP; smtp.From != "" {
+		versions[SenderEmail] = credentialVersion(smtp.Server, smtp.Username, smtp.Password)
+	}
+	if githubToken, err := settings.GetGithubOauthToken(); err == nil && len(githubToken) > 0 {
+		versions[SenderGithubStatus] = credentialVersion(githubToken)
+	}
+	if api := &settings.Api; api.GithubAppID != 0 && len(api.GithubAppKey) != 0 {
+		versions[SenderGithubCheck] = credentialVersion(strconv.FormatInt(api.GithubAppID, 10), api.GithubAppKey)
+	}
+	if jira := &settings.Jira; len(jira.GetHostURL()) != 0 {
+		jiraVersion := credentialVersion(jira.GetHostURL(), jira.Username, jira.Password)
+		versions[SenderJIRAIssue] = jiraVersion
+		versions[SenderJIRAComment] = jiraVersion
+	}
+	if slack := &settings.Slack; len(slack.Token) != 0 {
+		versions[SenderSlack] = credentialVersion(slack.Token)
+	}
+	// webhook secrets belong to each subscription, so the webhook sender
+	// itself holds no credentials
+	versions[SenderEvergreenWebhook] = credentialVersion()
+	// as are opsgenie API keys
+	versions[SenderOpsgenie] = credentialVersion()
+
+	return versions
+}
+
+func (e *envState) RotateSenderCredentials(settings *Settings) error {
+	if settings == nil {
+		return errors.New("no settings object, cannot rotate sender credentials")
+	}
+
+	if !e.senderCredentialsChanged(senderCredentialVersions(settings)) {
+		return nil
+	}
+
+	senders, versions, err := makeSenders(settings)
+	if err != nil {
+		return errors.Wrap(err, "problem building senders with new credentials")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rotated := []string{}
+	for key, sender := range senders {
+		if version, ok := e.senderVersions[key]; ok && version == versions[key] {
+			grip.Warning(sender.Close())
+			continue
+		}
+
+		// the previous sender may still have notifications buffered
+		// in the queue, so it is closed once they've been sent
+		if previous, ok := e.rootSenders[key]; ok {
+			e.retireSender(previous)
+		}
+		e.rootSenders[key] = sender
+		if e.notificationsQueue != nil {
+			sender = logger.MakeQueueSender(e.notificationsQueue, sender)
+		}
+		e.senders[key] = sender
+		e.senderVersions[key] = versions[key]
+		rotated = append(rotated, key.String())
+	}
+
+	grip.InfoWhen(len(rotated) > 0, message.Fields{
+		"message": "rotated sender credentials",
+		"senders": rotated,
+	})
+
+	return nil
+}
+
+// retireSender closes the replaced sender once the notifications queue has
+// sent the notifications pending when it was replaced, or once
+// senderRetireTimeout has passed. Senders that are still retiring are closed
+// along with the environment. The caller must hold the lock.
+func (e *envState) retireSender(s send.Sender) {
+	retiring := &retiringSender{sender: s}
+	e.retiringSenders = append(e.retiringSenders, retiring)
+	notificationsQueue := e.notificationsQueue
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), senderRetireTimeout)
+		defer cancel()
+		if notificationsQueue != nil {
+			amboy.WaitCtxInterval(ctx, notificationsQueue, senderRetireInterval)
+		}
+
+		if !e.removeRetiringSender(retiring) {
+			// the environment closed it already
+			return
+		}
+		grip.Warning(message.WrapError(s.Close(), message.Fields{
+			"message": "problem closing replaced sender",
+			"sender":  s.Name(),
+		}))
+	}()
+}
+
+// removeRetiringSender removes the sender from the retiring senders,
+// returning false if it had already been removed.
+func (e *envState) removeRetiringSender(retiring *retiringSender) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i := range e.retiringSenders {
+		if e.retiringSenders[i] == retiring {
+			e.retiringSenders = append(e.retiringSenders[:i], e.retiringSenders[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *envState) senderCredentialsChanged(versions map[SenderKey]string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for key, version := range versions {
+		if e.senderVersions[key] != version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *envState) GetSenderCredentialVersion(key SenderKey) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.senderVersions[key]
+}
+
+func (e *envState) ReloadSettings(settings *Settings) error {
+	if settings == nil {
+		return errors.New("no settings object, cannot reload settings")
+	}
+	if err := settings.Validate(); err != nil {
+		return errors.Wrap(err, "problem validating settings")
+	}
+
+	e.mu.Lock()
+	if e.settings != nil {
+		settings.Database = e.settings.Database
+	}
+	e.settings = settings
+	e.mu.Unlock()
+
+	grip.Info(message.Fields{
+		"message": "reloaded settings",
+		"id":      settings.Id,
+	})
+
+	return errors.WithStack(e.RotateSenderCredentials(settings))
+}
+
 func (e *envState) RegisterCloser(name string, closer func(context.Context) error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -587,8 +883,14 @@ func (e *envState) RegisterCloser(name string, closer func(context.Context) erro
 }
 
 func (e *envState) Close(ctx context.Context) error {
+	// closers may use the environment, so they're called without
+	// holding the lock
 	e.mu.RLock()
-	defer e.mu.RUnlock()
+	closers := make(map[string]func(context.Context) error, len(e.closers))
+	for n, closer := range e.closers {
+		closers[n] = closer
+	}
+	e.mu.RUnlock()
 
 	// TODO we could, in the future call all closers in but that
 	// would require more complex waiting and timeout logic
@@ -596,7 +898,7 @@ func (e *envState) Close(ctx context.Context) error {
 	deadline, _ := ctx.Deadline()
 	catcher := grip.NewBasicCatcher()
 	wg := &sync.WaitGroup{}
-	for n, closer := range e.closers {
+	for n, closer := range closers {
 		if closer == nil {
 			continue
 		}
@@ -640,6 +942,10 @@ func getClientConfig(baseURL string) (*ClientConfig, error) {
 			return err
 		}
 
+		if info.IsDir() && path == filepath.Join(root, AgentVersionsDirectory) {
+			// agent versions are not offered as clients
+			return filepath.SkipDir
+		}
 		if info.IsDir() || info.Name() == "version" {
 			return nil
 		}
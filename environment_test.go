@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mongodb/grip/send"
 	"github.com/stretchr/testify/assert"
@@ -36,8 +37,10 @@ func (s *EnvironmentSuite) shouldSkip() {
 
 func (s *EnvironmentSuite) SetupTest() {
 	s.env = &envState{
-		senders: map[SenderKey]send.Sender{},
-		closers: map[string]func(context.Context) error{},
+		senders:        map[SenderKey]send.Sender{},
+		senderVersions: map[SenderKey]string{},
+		rootSenders:    map[SenderKey]send.Sender{},
+		closers:        map[string]func(context.Context) error{},
 	}
 }
 
@@ -72,6 +75,56 @@ func (s *EnvironmentSuite) TestConfigErrorsIfCannotValidateConfig() {
 	s.Contains(err.Error(), "validating settings")
 }
 
+func (s *EnvironmentSuite) TestReloadSettings() {
+	original := &Settings{ApiUrl: "http://localhost:8080", Database: DBSettings{DB: "mci"}}
+	s.env.settings = original
+
+	s.Error(s.env.ReloadSettings(nil))
+	s.Error(s.env.ReloadSettings(&Settings{}))
+	s.Equal(original, s.env.Settings())
+
+	s.shouldSkip()
+	settings, err := NewSettings(s.path)
+	s.Require().NoError(err)
+	settings.Database = DBSettings{}
+	settings.ApiUrl = "http://evergreen.example.com"
+	s.NoError(s.env.ReloadSettings(settings))
+	s.Equal("http://evergreen.example.com", s.env.Settings().ApiUrl)
+	s.Equal("mci", s.env.Settings().Database.DB)
+}
+
+type closeRecordingSender struct {
+	send.Sender
+	closed chan struct{}
+}
+
+func (s *closeRecordingSender) Close() error {
+	close(s.closed)
+	return nil
+}
+
+func (s *EnvironmentSuite) TestRetireSender() {
+	sender := &closeRecordingSender{Sender: send.MakeInternalLogger(), closed: make(chan struct{})}
+	s.env.mu.Lock()
+	s.env.retireSender(sender)
+	s.Len(s.env.retiringSenders, 1)
+	s.env.mu.Unlock()
+
+	select {
+	case <-sender.closed:
+	case <-time.After(time.Second):
+		s.Fail("replaced sender was not closed")
+	}
+	s.env.mu.RLock()
+	s.Empty(s.env.retiringSenders)
+	s.env.mu.RUnlock()
+
+	// senders that were already removed, because the environment closed
+	// them, aren't closed again
+	retiring := &retiringSender{sender: sender}
+	s.False(s.env.removeRetiringSender(retiring))
+}
+
 func (s *EnvironmentSuite) TestGetClientConfig() {
 	root := filepath.Join(FindEvergreenHome(), ClientDirectory)
 	if err := os.Mkdir(root, os.ModeDir|os.ModePerm); err != nil {
@@ -20,6 +20,7 @@ const (
 	HostProvisionFailed = "provision failed"
 	HostQuarantined     = "quarantined"
 	HostDecommissioned  = "decommissioned"
+	HostStopped         = "stopped"
 
 	HostStatusSuccess = "success"
 	HostStatusFailed  = "failed"
@@ -179,6 +180,7 @@ const (
 	ProviderNameDocker      = "docker"
 	ProviderNameDockerMock  = "docker-mock"
 	ProviderNameGce         = "gce"
+	ProviderNameAzure       = "azure"
 	ProviderNameStatic      = "static"
 	ProviderNameOpenstack   = "openstack"
 	ProviderNameVsphere     = "vsphere"
@@ -197,6 +199,7 @@ var (
 		ProviderNameEc2Spot,
 		ProviderNameEc2Auto,
 		ProviderNameGce,
+		ProviderNameAzure,
 		ProviderNameOpenstack,
 		ProviderNameVsphere,
 		ProviderNameMock,
@@ -222,6 +225,7 @@ const (
 	RepotrackerVersionRequester = "gitter_request"
 	TriggerRequester            = "trigger_request"
 	AdHocRequester              = "ad_hoc"
+	ManualVersionRequester      = "manual_request"
 )
 
 const (
@@ -238,6 +242,8 @@ const (
 	SenderJIRAIssue
 	SenderJIRAComment
 	SenderEmail
+	SenderOpsgenie
+	SenderGithubCheck
 )
 
 func (k SenderKey) String() string {
@@ -254,6 +260,10 @@ func (k SenderKey) String() string {
 		return "jira-comment"
 	case SenderJIRAIssue:
 		return "jira-issue"
+	case SenderOpsgenie:
+		return "opsgenie"
+	case SenderGithubCheck:
+		return "github-check"
 	default:
 		return "<error:unkwown>"
 	}
@@ -287,6 +297,7 @@ var (
 		HostStarting,
 		HostProvisioning,
 		HostProvisionFailed,
+		HostStopped,
 	}
 
 	// Hosts in "initializing" status aren't actually running yet:
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/mongodb/anser"
+	"github.com/mongodb/anser/model"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	migrationRepotrackerDisableStates = "project-repotracker-disable-states"
+)
+
+// repotrackerDisableStatesGenerator replaces the repotracker error flag of
+// project refs with a disable state for polling revisions.
+func repotrackerDisableStatesGenerator(env anser.Environment, args migrationGeneratorFactoryOptions) (anser.Generator, error) {
+	const (
+		collection  = "project_ref"
+		existsKey   = "repotracker_error.exists"
+		disabledKey = "repotracker_error.disabled"
+	)
+	opts := model.GeneratorOptions{
+		NS: model.Namespace{
+			DB:         args.db,
+			Collection: collection,
+		},
+		Limit: args.limit,
+		Query: bson.M{
+			existsKey: true,
+		},
+		JobID: args.id,
+	}
+
+	return anser.NewSimpleMigrationGenerator(env, opts, bson.M{
+		"$set": bson.M{
+			disabledKey: []bson.M{
+				{
+					"capability":  "revisions",
+					"reason":      "base revision not found, must confirm on project settings page",
+					"disabled_at": time.Now(),
+				},
+			},
+		},
+		"$unset": bson.M{
+			existsKey: 1,
+		},
+	}), nil
+}
@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser"
+	anserdb "github.com/mongodb/anser/db"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type repotrackerDisableStatesMigrationSuite struct {
+	migrationSuite
+}
+
+func TestRepotrackerDisableStatesMigration(t *testing.T) {
+	suite.Run(t, &repotrackerDisableStatesMigrationSuite{})
+}
+
+func (s *repotrackerDisableStatesMigrationSuite) SetupTest() {
+	const projectRefCollection = "project_ref"
+
+	c, err := s.session.DB(s.database).C(projectRefCollection).RemoveAll(anserdb.Document{})
+	s.Require().NoError(err)
+	s.Require().NotNil(c)
+
+	refs := []anserdb.Document{
+		{
+			"identifier": "1",
+			"repotracker_error": anserdb.Document{
+				"exists":           true,
+				"invalid_revision": "abcdef0123",
+			},
+		},
+		{
+			"identifier": "2",
+			"repotracker_error": anserdb.Document{
+				"exists": false,
+			},
+		},
+	}
+	for _, e := range refs {
+		s.NoError(db.Insert(projectRefCollection, e))
+	}
+}
+
+func (s *repotrackerDisableStatesMigrationSuite) TestMigration() {
+	args := migrationGeneratorFactoryOptions{
+		db:    s.database,
+		limit: 50,
+		id:    "migration-" + migrationRepotrackerDisableStates,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gen, err := repotrackerDisableStatesGenerator(anser.GetEnvironment(), args)
+	s.Require().NoError(err)
+	gen.Run(ctx)
+	s.Require().NoError(gen.Error())
+
+	i := 0
+	for j := range gen.Jobs() {
+		i++
+		j.Run(ctx)
+		s.NoError(j.Error())
+	}
+	s.Equal(1, i)
+
+	out := []bson.M{}
+	s.Require().NoError(db.FindAllQ("project_ref", db.Q{}, &out))
+	s.Len(out, 2)
+
+	for _, e := range out {
+		details, ok := e["repotracker_error"].(bson.M)
+		s.Require().True(ok)
+
+		if e["identifier"] == "1" {
+			s.NotContains(details, "exists")
+			s.Equal("abcdef0123", details["invalid_revision"])
+			disabled, ok := details["disabled"].([]interface{})
+			s.Require().True(ok)
+			s.Require().Len(disabled, 1)
+			s.Equal("revisions", disabled[0].(bson.M)["capability"])
+
+		} else if e["identifier"] == "2" {
+			s.NotContains(details, "disabled")
+
+		} else {
+			s.T().Errorf("unknown project ref")
+		}
+	}
+}
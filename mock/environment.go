@@ -32,6 +32,10 @@ type Environment struct {
 	mu                sync.RWMutex
 
 	InternalSender *send.InternalSender
+
+	// RotatedSettings holds the settings passed to the last call to
+	// RotateSenderCredentials
+	RotatedSettings *evergreen.Settings
 }
 
 func (e *Environment) Configure(ctx context.Context, path string, db *evergreen.DBSettings) error {
@@ -110,6 +114,27 @@ func (e *Environment) GetSender(key evergreen.SenderKey) (send.Sender, error) {
 	return e.InternalSender, nil
 }
 
+func (e *Environment) RotateSenderCredentials(settings *evergreen.Settings) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.RotatedSettings = settings
+	return nil
+}
+
+func (e *Environment) ReloadSettings(settings *evergreen.Settings) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.EvergreenSettings = settings
+	e.RotatedSettings = settings
+	return nil
+}
+
+func (e *Environment) GetSenderCredentialVersion(key evergreen.SenderKey) string {
+	return "mock"
+}
+
 func (e *Environment) RegisterCloser(name string, closer func(context.Context) error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
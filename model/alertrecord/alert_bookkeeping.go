@@ -22,6 +22,7 @@ const (
 	FirstTaskTypeFailureId   = "first_tasktype_failure"
 	TaskFailTransitionId     = "task_transition_failure"
 	FirstRegressionInVersion = "first_regression_in_version"
+	TaskStreakBrokenId       = "task_streak_broken"
 	// TODO: EVG-3408
 	TaskFailedId                    = "task_failed"
 	LastRevisionNotFound            = "last_revision_not_found"
@@ -138,6 +139,15 @@ func ByFirstFailureInTaskType(subscriptionID, versionId, taskName string) db.Q {
 	return db.Query(q).Limit(1)
 }
 
+// ByStreakBroken finds the alert record stored when the task broke a streak
+// of passing runs, so that later executions of the task don't alert again.
+func ByStreakBroken(subscriptionID, taskID string) db.Q {
+	q := subscriptionIDQuery(subscriptionID)
+	q[TypeKey] = TaskStreakBrokenId
+	q[TaskIdKey] = taskID
+	return db.Query(q).Limit(1)
+}
+
 func ByLastRevNotFound(subscriptionID, projectId, versionId string) db.Q {
 	q := subscriptionIDQuery(subscriptionID)
 	q[TypeKey] = LastRevisionNotFound
@@ -0,0 +1,188 @@
+package archive
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/goamz/goamz/aws"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// KeyKey is the field that the documents in an archive collection are
+	// grouped by.
+	KeyKey = "_archive_key"
+
+	bsonContentType = "application/bson"
+)
+
+// Store keeps documents that were moved out of their collections, grouped
+// under keys that they can be looked up by.
+type Store interface {
+	// Put stores each group of documents from the collection under its
+	// key, replacing any documents that were already stored under it.
+	Put(collection string, groups map[string][]bson.M) error
+	// Get populates out, which must be a pointer to a slice, with the
+	// documents from the collection stored under the key.
+	Get(collection, key string, out interface{}) error
+}
+
+// CollectionName returns the name of the collection that documents archived
+// from the collection are kept in.
+func CollectionName(collection string) string {
+	return collection + "_archive"
+}
+
+// GetStore returns the store that the settings archive documents to. If
+// there are no settings, documents are archived to collections, so that
+// lookups only ever find documents that were archived there.
+func GetStore(settings *evergreen.Settings) Store {
+	if settings == nil || settings.DataLifecycle.Target != evergreen.ArchiveTargetS3 {
+		return &collectionStore{}
+	}
+
+	conf := settings.DataLifecycle
+	return &s3Store{
+		auth: &aws.Auth{
+			AccessKey: conf.Key,
+			SecretKey: conf.Secret,
+		},
+		region: conf.Region,
+		bucket: conf.Bucket,
+		prefix: conf.Prefix,
+	}
+}
+
+// Find populates out, which must be a pointer to a slice, with the
+// documents archived from the collection under the key, in the store
+// configured by the environment's settings.
+func Find(collection, key string, out interface{}) error {
+	return GetStore(evergreen.GetEnvironment().Settings()).Get(collection, key, out)
+}
+
+// Move moves at most limit documents that match the query from the
+// collection into the store, grouped by the key that groupKey returns for
+// each of them, and returns the moved documents. Documents are removed from
+// the collection only after they are stored, so a move that fails partway
+// through can be retried.
+func Move(store Store, collection string, query bson.M, limit int, groupKey func(bson.M) string) ([]bson.M, error) {
+	docs := []bson.M{}
+	if err := db.FindAll(collection, query, db.NoProjection, db.NoSort, db.NoSkip, limit, &docs); err != nil {
+		return nil, errors.Wrapf(err, "problem finding documents to archive from '%s'", collection)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	groups := map[string][]bson.M{}
+	ids := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		key := groupKey(doc)
+		groups[key] = append(groups[key], doc)
+		ids = append(ids, doc["_id"])
+	}
+	if err := store.Put(collection, groups); err != nil {
+		return nil, errors.Wrapf(err, "problem archiving documents from '%s'", collection)
+	}
+	if err := db.RemoveAll(collection, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, errors.Wrapf(err, "problem removing archived documents from '%s'", collection)
+	}
+
+	return docs, nil
+}
+
+// collectionStore keeps archived documents in an archive collection next to
+// the collection they came from, so they can still be queried directly.
+type collectionStore struct{}
+
+func (s *collectionStore) Put(collection string, groups map[string][]bson.M) error {
+	archiveCollection := CollectionName(collection)
+	if err := db.EnsureIndex(archiveCollection, mgo.Index{Key: []string{KeyKey}}); err != nil {
+		return errors.Wrapf(err, "problem indexing '%s'", archiveCollection)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for key, docs := range groups {
+		for _, doc := range docs {
+			archived := bson.M{KeyKey: key}
+			for k, v := range doc {
+				archived[k] = v
+			}
+			_, err := db.Upsert(archiveCollection, bson.M{"_id": doc["_id"]}, archived)
+			catcher.Add(errors.Wrapf(err, "problem archiving '%v' to '%s'", doc["_id"], archiveCollection))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+func (s *collectionStore) Get(collection, key string, out interface{}) error {
+	err := db.FindAll(CollectionName(collection), bson.M{KeyKey: key}, db.NoProjection, db.NoSort, db.NoSkip, db.NoLimit, out)
+	return errors.Wrapf(err, "problem finding '%s' in '%s'", key, CollectionName(collection))
+}
+
+// s3Store keeps each group of archived documents in an S3 object, as a BSON
+// document holding the group.
+type s3Store struct {
+	auth   *aws.Auth
+	region string
+	bucket string
+	prefix string
+}
+
+// archivedGroup is the document that a group of archived documents is
+// stored in S3 as.
+type archivedGroup struct {
+	Docs []bson.M `bson:"docs"`
+}
+
+func (s *s3Store) objectKey(collection, key string) string {
+	return path.Join(s.prefix, collection, fmt.Sprintf("%s.bson", key))
+}
+
+func (s *s3Store) Put(collection string, groups map[string][]bson.M) error {
+	catcher := grip.NewBasicCatcher()
+	for key, docs := range groups {
+		body, err := bson.Marshal(archivedGroup{Docs: docs})
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem marshalling '%s' from '%s'", key, collection))
+			continue
+		}
+		catcher.Add(thirdparty.PutS3Object(s.auth, s.region, s.bucket, s.objectKey(collection, key), bsonContentType, body))
+	}
+
+	return catcher.Resolve()
+}
+
+func (s *s3Store) Get(collection, key string, out interface{}) error {
+	body, err := thirdparty.GetS3Object(s.auth, s.region, s.bucket, s.objectKey(collection, key))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if body == nil {
+		body, err = bson.Marshal(archivedGroup{Docs: []bson.M{}})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return errors.Wrapf(unmarshalGroup(body, out), "problem reading '%s' from '%s'", key, collection)
+}
+
+// unmarshalGroup populates out with the documents of the marshalled group.
+func unmarshalGroup(body []byte, out interface{}) error {
+	group := struct {
+		Docs bson.Raw `bson:"docs"`
+	}{}
+	if err := bson.Unmarshal(body, &group); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(group.Docs.Unmarshal(out))
+}
@@ -0,0 +1,54 @@
+package archive
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestGetStore(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.IsType(&collectionStore{}, GetStore(nil))
+	assert.IsType(&collectionStore{}, GetStore(&evergreen.Settings{}))
+
+	store := GetStore(&evergreen.Settings{DataLifecycle: evergreen.DataLifecycleConfig{
+		Target: evergreen.ArchiveTargetS3,
+		Bucket: "bucket",
+		Prefix: "archive",
+	}})
+	s3, ok := store.(*s3Store)
+	if assert.True(ok) {
+		assert.Equal("bucket", s3.bucket)
+		assert.Equal("archive/testresults/t1/0.bson", s3.objectKey("testresults", "t1/0"))
+	}
+	assert.Equal("tasks_archive", CollectionName("tasks"))
+}
+
+func TestUnmarshalGroup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	type doc struct {
+		Id        string `bson:"_id"`
+		Execution int    `bson:"execution"`
+	}
+
+	body, err := bson.Marshal(archivedGroup{Docs: []bson.M{
+		{"_id": "t1", "execution": 2, "unknown": true},
+		{"_id": "t2"},
+	}})
+	require.NoError(err)
+	out := []doc{}
+	require.NoError(unmarshalGroup(body, &out))
+	assert.Equal([]doc{{Id: "t1", Execution: 2}, {Id: "t2"}}, out)
+
+	body, err = bson.Marshal(archivedGroup{Docs: []bson.M{}})
+	require.NoError(err)
+	out = nil
+	require.NoError(unmarshalGroup(body, &out))
+	assert.Len(out, 0)
+}
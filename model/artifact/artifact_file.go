@@ -1,5 +1,7 @@
 package artifact
 
+import "time"
+
 const Collection = "artifact_files"
 
 const (
@@ -38,6 +40,18 @@ type File struct {
 	Visibility string `json:"visibility" bson:"visibility"`
 	// When true, these artifacts are excluded from reproduction
 	IgnoreForFetch bool `bson:"fetch_ignore,omitempty" json:"ignore_for_fetch"`
+
+	// Size and SHA256 describe the content of files uploaded through URLs
+	// signed by the app server.
+	Size   int64  `bson:"size,omitempty" json:"size,omitempty"`
+	SHA256 string `bson:"sha256,omitempty" json:"sha256,omitempty"`
+	// Bucket and FileKey locate files uploaded through signed URLs in the
+	// app server's artifacts bucket.
+	Bucket  string `bson:"bucket,omitempty" json:"bucket,omitempty"`
+	FileKey string `bson:"file_key,omitempty" json:"file_key,omitempty"`
+	// ExpiresAt is when a file uploaded through a signed URL is deleted. If
+	// it is zero, the file is kept forever.
+	ExpiresAt time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
 }
 
 // Array turns the parameter map into an array of File structs.
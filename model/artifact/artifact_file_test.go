@@ -2,6 +2,7 @@ package artifact
 
 import (
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
@@ -31,8 +32,8 @@ func (s *TestArtifactFileSuite) SetupTest() {
 			TaskDisplayName: "Task One",
 			BuildId:         "build1",
 			Files: []File{
-				{"cat_pix", "http://placekitten.com/800/600", "", false},
-				{"fast_download", "https://fastdl.mongodb.org", "", false},
+				{Name: "cat_pix", Link: "http://placekitten.com/800/600"},
+				{Name: "fast_download", Link: "https://fastdl.mongodb.org"},
 			},
 			Execution: 1,
 		},
@@ -41,7 +42,7 @@ func (s *TestArtifactFileSuite) SetupTest() {
 			TaskDisplayName: "Task Two",
 			BuildId:         "build2",
 			Files: []File{
-				{"other", "http://example.com/other", "", false},
+				{Name: "other", Link: "http://example.com/other"},
 			},
 			Execution: 5,
 		},
@@ -59,7 +60,7 @@ func (s *TestArtifactFileSuite) SetupTest() {
 		TaskDisplayName: "Task Two",
 		BuildId:         "build2",
 		Files: []File{
-			{"other", "http://example.com/other", "", false},
+			{Name: "other", Link: "http://example.com/other"},
 		},
 	}))
 
@@ -89,8 +90,8 @@ func (s *TestArtifactFileSuite) TestArtifactFieldsArePresent() {
 
 func (s *TestArtifactFileSuite) TestArtifactFieldsAfterUpdate() {
 	s.testEntries[0].Files = []File{
-		{"cat_pix", "http://placekitten.com/300/400", "", false},
-		{"the_value_of_four", "4", "", false},
+		{Name: "cat_pix", Link: "http://placekitten.com/300/400"},
+		{Name: "the_value_of_four", Link: "4"},
 	}
 	s.NoError(s.testEntries[0].Upsert())
 
@@ -166,3 +167,34 @@ func (s *TestArtifactFileSuite) TestFindByIds() {
 	s.NoError(err)
 	s.Len(entries, 3)
 }
+
+func (s *TestArtifactFileSuite) TestExpiredFiles() {
+	now := time.Now().Round(time.Second)
+	entry := Entry{
+		TaskId:          "task3",
+		TaskDisplayName: "Task Three",
+		BuildId:         "build3",
+		Files: []File{
+			{Name: "expired", Link: "l1", Bucket: "b", FileKey: "k1", ExpiresAt: now.Add(-time.Hour)},
+			{Name: "current", Link: "l2", Bucket: "b", FileKey: "k2", ExpiresAt: now.Add(time.Hour)},
+		},
+		Execution: 0,
+	}
+	s.NoError(entry.Upsert())
+
+	entries, err := FindAll(ByExpiredFiles(now))
+	s.NoError(err)
+	s.Require().Len(entries, 1)
+	s.Equal("task3", entries[0].TaskId)
+
+	s.NoError(RemoveFiles("task3", 0, []string{"k1"}))
+	entries, err = FindAll(ByExpiredFiles(now))
+	s.NoError(err)
+	s.Empty(entries)
+
+	found, err := FindOne(ByTaskIdAndExecution("task3", 0))
+	s.NoError(err)
+	s.Require().NotNil(found)
+	s.Require().Len(found.Files, 1)
+	s.Equal("current", found.Files[0].Name)
+}
@@ -1,6 +1,8 @@
 package artifact
 
 import (
+	"time"
+
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/anser/bsonutil"
 	"gopkg.in/mgo.v2"
@@ -16,6 +18,8 @@ var (
 	ExecutionKey = bsonutil.MustHaveTag(Entry{}, "Execution")
 	NameKey      = bsonutil.MustHaveTag(File{}, "Name")
 	LinkKey      = bsonutil.MustHaveTag(File{}, "Link")
+	FileKeyKey   = bsonutil.MustHaveTag(File{}, "FileKey")
+	ExpiresAtKey = bsonutil.MustHaveTag(File{}, "ExpiresAt")
 )
 
 type TaskIDAndExecution struct {
@@ -76,6 +80,16 @@ func ByBuildId(id string) db.Q {
 	return db.Query(bson.M{BuildIdKey: id}).Sort([]string{TaskNameKey})
 }
 
+// ByExpiredFiles returns a query for entries with a file that expired before
+// the given time.
+func ByExpiredFiles(now time.Time) db.Q {
+	return db.Query(bson.M{
+		bsonutil.GetDottedKeyName(FilesKey, ExpiresAtKey): bson.M{
+			"$lte": now,
+		},
+	})
+}
+
 // === DB Logic ===
 
 // Upsert updates the files entry in the db if an entry already exists,
@@ -119,3 +133,25 @@ func FindAll(query db.Q) ([]Entry, error) {
 	err := db.FindAllQ(Collection, query, &entries)
 	return entries, err
 }
+
+// RemoveFiles removes the files with the given keys from the entry of the task
+// execution.
+func RemoveFiles(taskId string, execution int, fileKeys []string) error {
+	if len(fileKeys) == 0 {
+		return nil
+	}
+	return db.Update(
+		Collection,
+		bson.M{
+			TaskIdKey:    taskId,
+			ExecutionKey: execution,
+		},
+		bson.M{
+			"$pull": bson.M{
+				FilesKey: bson.M{
+					FileKeyKey: bson.M{"$in": fileKeys},
+				},
+			},
+		},
+	)
+}
@@ -58,6 +58,9 @@ type Build struct {
 	// repository) or it was triggered by a developer
 	// patch request
 	Requester string `bson:"r" json:"r,omitempty"`
+
+	// Priority is inherited by the build's tasks whose own priority is unset
+	Priority int64 `bson:"priority,omitempty" json:"priority,omitempty"`
 }
 
 // Returns whether or not the build has finished, based on its status.
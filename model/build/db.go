@@ -1,11 +1,15 @@
 package build
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/archive"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -35,6 +39,7 @@ var (
 	RequesterKey           = bsonutil.MustHaveTag(Build{}, "Requester")
 	PredictedMakespanKey   = bsonutil.MustHaveTag(Build{}, "PredictedMakespan")
 	ActualMakespanKey      = bsonutil.MustHaveTag(Build{}, "ActualMakespan")
+	PriorityKey            = bsonutil.MustHaveTag(Build{}, "Priority")
 
 	// bson fields for the task caches
 	TaskCacheIdKey            = bsonutil.MustHaveTag(TaskCache{}, "Id")
@@ -226,9 +231,22 @@ func FindOne(query db.Q) (*Build, error) {
 	return build, err
 }
 
-// FindOneId returns one build by Id.
+// FindOneId returns one build by Id, looking in the archive if the build
+// isn't in the builds collection.
 func FindOneId(id string) (*Build, error) {
-	return FindOne(ById(id))
+	build, err := FindOne(ById(id))
+	if build != nil || err != nil {
+		return build, err
+	}
+
+	builds := []Build{}
+	if err = archive.Find(Collection, id, &builds); err != nil {
+		return nil, errors.Wrap(err, "error finding archived build")
+	}
+	if len(builds) == 0 {
+		return nil, nil
+	}
+	return &builds[0], nil
 }
 
 // Find returns all builds that satisfy the query.
@@ -265,3 +283,17 @@ func Remove(id string) error {
 		bson.M{IdKey: id},
 	)
 }
+
+// ArchiveFinishedBefore moves at most limit builds that finished before the
+// cutoff into the archive store, and returns how many builds it moved.
+func ArchiveFinishedBefore(store archive.Store, cutoff time.Time, limit int) (int, error) {
+	moved, err := archive.Move(store, Collection, bson.M{
+		StatusKey:     bson.M{"$in": []string{evergreen.BuildSucceeded, evergreen.BuildFailed}},
+		FinishTimeKey: bson.M{"$gt": util.ZeroTime, "$lt": cutoff},
+	}, limit, func(doc bson.M) string { return fmt.Sprint(doc[IdKey]) })
+	if err != nil {
+		return 0, errors.Wrap(err, "error archiving builds")
+	}
+
+	return len(moved), nil
+}
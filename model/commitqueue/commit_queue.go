@@ -0,0 +1,186 @@
+package commitqueue
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// CommitQueueAlias is the project alias defining the variants and tasks
+	// that test the items of a project's commit queue.
+	CommitQueueAlias = "__commit_queue"
+
+	// Statuses of items leaving a commit queue.
+	StatusMerged  = "merged"
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusRemoved = "removed"
+)
+
+// CommitQueueItem is a pull request or a patch waiting in a project's commit
+// queue to be tested against the head of the project's branch and merged.
+type CommitQueueItem struct {
+	// Issue is the number of the pull request, or the ID of the patch.
+	Issue string `bson:"issue"`
+	// Author is the user who added the item to the queue.
+	Author string `bson:"author,omitempty"`
+	// Version is the ID of the patch testing the item against the head of
+	// the branch, once the item is at the front of the queue.
+	Version string `bson:"version,omitempty"`
+	// BaseHash is the revision of the branch the item is tested against.
+	BaseHash    string    `bson:"base_hash,omitempty"`
+	StartTime   time.Time `bson:"start_time,omitempty"`
+	EnqueueTime time.Time `bson:"enqueue_time"`
+}
+
+// CommitQueue is the ordered list of the items waiting to be merged into a
+// project's branch. Only the item at the front of the queue is tested at a
+// time, and it's being tested if the queue is processing.
+type CommitQueue struct {
+	ProjectID  string            `bson:"_id"`
+	Processing bool              `bson:"processing"`
+	Queue      []CommitQueueItem `bson:"queue"`
+}
+
+// FindItem returns the position of the item in the queue, or -1 if it isn't
+// in the queue.
+func (q *CommitQueue) FindItem(issue string) int {
+	for i, item := range q.Queue {
+		if item.Issue == issue {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Next returns the item at the front of the queue.
+func (q *CommitQueue) Next() (CommitQueueItem, bool) {
+	if len(q.Queue) == 0 {
+		return CommitQueueItem{}, false
+	}
+
+	return q.Queue[0], true
+}
+
+// Enqueue adds the item to the back of the queue, returning its position.
+// It's an error to add an item that's already in the queue.
+func (q *CommitQueue) Enqueue(item CommitQueueItem) (int, error) {
+	if item.EnqueueTime.IsZero() {
+		item.EnqueueTime = time.Now().Truncate(time.Millisecond)
+	}
+
+	updated := &CommitQueue{}
+	_, err := db.FindAndModify(Collection,
+		bson.M{
+			IdKey: q.ProjectID,
+			bsonutil.GetDottedKeyName(QueueKey, IssueKey): bson.M{"$ne": item.Issue},
+		},
+		nil,
+		mgo.Change{
+			Update:    bson.M{"$push": bson.M{QueueKey: item}},
+			ReturnNew: true,
+		},
+		updated,
+	)
+	if err == mgo.ErrNotFound {
+		return -1, errors.Errorf("item '%s' is already in the commit queue for '%s'", item.Issue, q.ProjectID)
+	}
+	if err != nil {
+		return -1, errors.Wrapf(err, "can't add item '%s' to the commit queue for '%s'", item.Issue, q.ProjectID)
+	}
+
+	q.Queue = updated.Queue
+	q.Processing = updated.Processing
+	position := q.FindItem(item.Issue)
+	event.LogCommitQueueEnqueueEvent(q.ProjectID, item.Issue, position)
+
+	return position, nil
+}
+
+// Remove takes the item out of the queue, logging the status it left with
+// and the new positions of the items that were behind it. It returns false
+// if the item wasn't in the queue.
+func (q *CommitQueue) Remove(issue, status string) (bool, error) {
+	updated := &CommitQueue{}
+	_, err := db.FindAndModify(Collection,
+		bson.M{
+			IdKey: q.ProjectID,
+			bsonutil.GetDottedKeyName(QueueKey, IssueKey): issue,
+		},
+		nil,
+		mgo.Change{
+			Update:    bson.M{"$pull": bson.M{QueueKey: bson.M{IssueKey: issue}}},
+			ReturnNew: false,
+		},
+		updated,
+	)
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "can't remove item '%s' from the commit queue for '%s'", issue, q.ProjectID)
+	}
+
+	position := updated.FindItem(issue)
+	event.LogCommitQueueDequeueEvent(q.ProjectID, issue, status)
+	for i := position + 1; i < len(updated.Queue); i++ {
+		event.LogCommitQueuePositionChangeEvent(q.ProjectID, updated.Queue[i].Issue, i-1)
+	}
+	q.Queue = append(updated.Queue[:position], updated.Queue[position+1:]...)
+
+	// the item being tested was at the front of the queue
+	if position == 0 && updated.Processing {
+		return true, errors.WithStack(q.SetProcessing(false))
+	}
+
+	return true, nil
+}
+
+// SetProcessing records whether the item at the front of the queue is being
+// tested.
+func (q *CommitQueue) SetProcessing(processing bool) error {
+	if err := updateOne(bson.M{IdKey: q.ProjectID}, bson.M{"$set": bson.M{ProcessingKey: processing}}); err != nil {
+		return errors.Wrapf(err, "can't set the commit queue for '%s' processing", q.ProjectID)
+	}
+	q.Processing = processing
+
+	return nil
+}
+
+// StartItem records the patch testing the item at the front of the queue,
+// and the revision it's tested against, and sets the queue processing.
+func (q *CommitQueue) StartItem(issue, version, baseHash string) error {
+	startTime := time.Now().Truncate(time.Millisecond)
+	err := updateOne(
+		bson.M{
+			IdKey: q.ProjectID,
+			bsonutil.GetDottedKeyName(QueueKey, IssueKey): issue,
+		},
+		bson.M{"$set": bson.M{
+			ProcessingKey: true,
+			bsonutil.GetDottedKeyName(QueueKey, "$", VersionKey):   version,
+			bsonutil.GetDottedKeyName(QueueKey, "$", BaseHashKey):  baseHash,
+			bsonutil.GetDottedKeyName(QueueKey, "$", StartTimeKey): startTime,
+		}},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "can't start item '%s' in the commit queue for '%s'", issue, q.ProjectID)
+	}
+
+	q.Processing = true
+	if i := q.FindItem(issue); i >= 0 {
+		q.Queue[i].Version = version
+		q.Queue[i].BaseHash = baseHash
+		q.Queue[i].StartTime = startTime
+	}
+	event.LogCommitQueueStartTestEvent(q.ProjectID, issue, version)
+
+	return nil
+}
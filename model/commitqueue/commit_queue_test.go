@@ -0,0 +1,104 @@
+package commitqueue
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type commitQueueSuite struct {
+	suite.Suite
+	q *CommitQueue
+}
+
+func TestCommitQueueSuite(t *testing.T) {
+	suite.Run(t, new(commitQueueSuite))
+}
+
+func (s *commitQueueSuite) SetupTest() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	s.Require().NoError(db.ClearCollections(Collection, event.AllLogCollection))
+
+	s.q = &CommitQueue{ProjectID: "mci", Queue: []CommitQueueItem{}}
+	s.Require().NoError(InsertQueue(s.q))
+}
+
+func (s *commitQueueSuite) TestEnqueue() {
+	for i, issue := range []string{"1", "2", "3"} {
+		position, err := s.q.Enqueue(CommitQueueItem{Issue: issue})
+		s.NoError(err)
+		s.Equal(i, position)
+	}
+
+	_, err := s.q.Enqueue(CommitQueueItem{Issue: "2"})
+	s.Error(err)
+
+	dbQueue, err := FindOneId("mci")
+	s.NoError(err)
+	s.Require().NotNil(dbQueue)
+	s.Len(dbQueue.Queue, 3)
+	s.Equal(1, dbQueue.FindItem("2"))
+	s.False(dbQueue.Queue[2].EnqueueTime.IsZero())
+}
+
+func (s *commitQueueSuite) TestRemove() {
+	for _, issue := range []string{"1", "2", "3"} {
+		_, err := s.q.Enqueue(CommitQueueItem{Issue: issue})
+		s.Require().NoError(err)
+	}
+	s.NoError(s.q.StartItem("1", "version", "base"))
+
+	found, err := s.q.Remove("1", StatusMerged)
+	s.NoError(err)
+	s.True(found)
+	s.False(s.q.Processing)
+	s.Equal(0, s.q.FindItem("2"))
+
+	found, err = s.q.Remove("1", StatusRemoved)
+	s.NoError(err)
+	s.False(found)
+
+	dbQueue, err := FindOneId("mci")
+	s.NoError(err)
+	s.Require().NotNil(dbQueue)
+	s.False(dbQueue.Processing)
+	s.Len(dbQueue.Queue, 2)
+	s.Equal(-1, dbQueue.FindItem("1"))
+
+	events, err := event.Find(event.AllLogCollection, db.Query(bson.M{event.ResourceTypeKey: event.ResourceTypeCommitQueue}))
+	s.NoError(err)
+	positionChanges := 0
+	for _, e := range events {
+		if e.EventType == event.CommitQueuePositionChange {
+			positionChanges++
+		}
+	}
+	s.Equal(2, positionChanges)
+}
+
+func (s *commitQueueSuite) TestStartItem() {
+	_, err := s.q.Enqueue(CommitQueueItem{Issue: "1"})
+	s.Require().NoError(err)
+
+	s.NoError(s.q.StartItem("1", "version", "base"))
+	s.True(s.q.Processing)
+
+	dbQueue, err := FindOneId("mci")
+	s.NoError(err)
+	s.Require().NotNil(dbQueue)
+	s.True(dbQueue.Processing)
+	item, ok := dbQueue.Next()
+	s.True(ok)
+	s.Equal("version", item.Version)
+	s.Equal("base", item.BaseHash)
+	s.False(item.StartTime.IsZero())
+
+	s.NoError(s.q.SetProcessing(false))
+	dbQueue, err = FindOneId("mci")
+	s.NoError(err)
+	s.False(dbQueue.Processing)
+}
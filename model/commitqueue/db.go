@@ -0,0 +1,51 @@
+package commitqueue
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	Collection = "commit_queue"
+)
+
+var (
+	// bson fields for the CommitQueue struct
+	IdKey         = bsonutil.MustHaveTag(CommitQueue{}, "ProjectID")
+	QueueKey      = bsonutil.MustHaveTag(CommitQueue{}, "Queue")
+	ProcessingKey = bsonutil.MustHaveTag(CommitQueue{}, "Processing")
+
+	// bson fields for the CommitQueueItem struct
+	IssueKey       = bsonutil.MustHaveTag(CommitQueueItem{}, "Issue")
+	AuthorKey      = bsonutil.MustHaveTag(CommitQueueItem{}, "Author")
+	VersionKey     = bsonutil.MustHaveTag(CommitQueueItem{}, "Version")
+	BaseHashKey    = bsonutil.MustHaveTag(CommitQueueItem{}, "BaseHash")
+	StartTimeKey   = bsonutil.MustHaveTag(CommitQueueItem{}, "StartTime")
+	EnqueueTimeKey = bsonutil.MustHaveTag(CommitQueueItem{}, "EnqueueTime")
+)
+
+// FindOneId returns the commit queue of the project, or nil if the project
+// has no commit queue.
+func FindOneId(id string) (*CommitQueue, error) {
+	cq := &CommitQueue{}
+	err := db.FindOneQ(Collection, db.Query(bson.M{IdKey: id}), cq)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return cq, nil
+}
+
+// InsertQueue inserts a commit queue into the database.
+func InsertQueue(q *CommitQueue) error {
+	return db.Insert(Collection, q)
+}
+
+func updateOne(query interface{}, update interface{}) error {
+	return db.Update(Collection, query, update)
+}
@@ -0,0 +1,95 @@
+package coverage
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the file coverage collection in the database.
+	Collection = "file_coverage"
+)
+
+// FileCoverage records the tasks whose tests exercise a source file of a
+// project, so that changes to the file can select the tasks to run.
+type FileCoverage struct {
+	Id         FileCoverageKey `bson:"_id" json:"id"`
+	Tasks      []string        `bson:"tasks" json:"tasks"`
+	LastUpdate time.Time       `bson:"last_update" json:"last_update"`
+}
+
+// FileCoverageKey identifies a source file of a project.
+type FileCoverageKey struct {
+	Project string `bson:"project" json:"project"`
+	File    string `bson:"file" json:"file"`
+}
+
+var (
+	IdKey         = bsonutil.MustHaveTag(FileCoverage{}, "Id")
+	TasksKey      = bsonutil.MustHaveTag(FileCoverage{}, "Tasks")
+	LastUpdateKey = bsonutil.MustHaveTag(FileCoverage{}, "LastUpdate")
+
+	keyProjectKey = bsonutil.MustHaveTag(FileCoverageKey{}, "Project")
+	keyFileKey    = bsonutil.MustHaveTag(FileCoverageKey{}, "File")
+)
+
+// ByProjectAndFiles returns the coverage of the files of a project.
+func ByProjectAndFiles(project string, files []string) db.Q {
+	return db.Query(bson.M{
+		bsonutil.GetDottedKeyName(IdKey, keyProjectKey): project,
+		bsonutil.GetDottedKeyName(IdKey, keyFileKey): bson.M{
+			"$in": files,
+		},
+	})
+}
+
+// Find returns the file coverage matching the query.
+func Find(query db.Q) ([]FileCoverage, error) {
+	coverage := []FileCoverage{}
+	err := db.FindAllQ(Collection, query, &coverage)
+	return coverage, err
+}
+
+// FindTasksForFiles returns the tasks that cover each of the files of a
+// project that have recorded coverage, keyed by file.
+func FindTasksForFiles(project string, files []string) (map[string][]string, error) {
+	if len(files) == 0 {
+		return map[string][]string{}, nil
+	}
+	coverage, err := Find(ByProjectAndFiles(project, files))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding coverage for project '%s'", project)
+	}
+	tasks := make(map[string][]string, len(coverage))
+	for _, c := range coverage {
+		tasks[c.Id.File] = c.Tasks
+	}
+	return tasks, nil
+}
+
+// Update replaces the tasks that cover each of the files of a project.
+func Update(project string, tasksByFile map[string][]string) error {
+	now := time.Now()
+	for file, tasks := range tasksByFile {
+		key := FileCoverageKey{
+			Project: project,
+			File:    file,
+		}
+		_, err := db.Upsert(Collection,
+			bson.M{IdKey: key},
+			bson.M{
+				"$set": bson.M{
+					TasksKey:      tasks,
+					LastUpdateKey: now,
+				},
+			})
+		if err != nil {
+			return errors.Wrapf(err, "problem updating coverage of file '%s'", file)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestUpdateAndFindTasksForFiles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+	defer func() {
+		assert.NoError(db.Clear(Collection))
+	}()
+
+	require.NoError(Update("proj", map[string][]string{
+		"src/a.go": {"unit"},
+		"src/b.go": {"unit", "integration"},
+	}))
+	require.NoError(Update("other", map[string][]string{
+		"src/c.go": {"lint"},
+	}))
+
+	tasks, err := FindTasksForFiles("proj", []string{"src/a.go", "src/b.go", "src/c.go"})
+	require.NoError(err)
+	assert.Equal(map[string][]string{
+		"src/a.go": {"unit"},
+		"src/b.go": {"unit", "integration"},
+	}, tasks)
+
+	// updating a file replaces its tasks
+	require.NoError(Update("proj", map[string][]string{
+		"src/a.go": {"integration"},
+	}))
+	tasks, err = FindTasksForFiles("proj", []string{"src/a.go"})
+	require.NoError(err)
+	assert.Equal([]string{"integration"}, tasks["src/a.go"])
+
+	tasks, err = FindTasksForFiles("proj", nil)
+	require.NoError(err)
+	assert.Empty(tasks)
+}
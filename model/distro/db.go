@@ -23,6 +23,10 @@ var (
 	ExpansionsKey       = bsonutil.MustHaveTag(Distro{}, "Expansions")
 	DisabledKey         = bsonutil.MustHaveTag(Distro{}, "Disabled")
 	ContainerPoolKey    = bsonutil.MustHaveTag(Distro{}, "ContainerPool")
+	HooksKey            = bsonutil.MustHaveTag(Distro{}, "Hooks")
+	IdlePolicyKey       = bsonutil.MustHaveTag(Distro{}, "IdlePolicy")
+
+	ParentImageRolloutKey = bsonutil.MustHaveTag(Distro{}, "ParentImageRollout")
 )
 
 const Collection = "distro"
@@ -34,6 +34,12 @@ type Distro struct {
 	Disabled     bool        `bson:"disabled,omitempty" json:"disabled,omitempty" mapstructure:"disabled,omitempty"`
 
 	ContainerPool string `bson:"container_pool,omitempty" json:"container_pool,omitempty" mapstructure:"container_pool,omitempty"`
+
+	Hooks HostHooks `bson:"hooks,omitempty" json:"hooks,omitempty" mapstructure:"hooks,omitempty"`
+
+	IdlePolicy IdlePolicy `bson:"idle_policy,omitempty" json:"idle_policy,omitempty" mapstructure:"idle_policy,omitempty"`
+
+	ParentImageRollout ParentImageRollout `bson:"parent_image_rollout,omitempty" json:"parent_image_rollout,omitempty" mapstructure:"parent_image_rollout,omitempty"`
 }
 
 type DistroGroup []Distro
@@ -54,6 +60,8 @@ func init() {
 func (d *Distro) GenerateName() string {
 	// gceMaxNameLength is the maximum length of an instance name permitted by GCE.
 	const gceMaxNameLength = 63
+	// azureMaxNameLength is the maximum length of a Linux VM name permitted by Azure.
+	const azureMaxNameLength = 64
 
 	switch d.Provider {
 	case evergreen.ProviderNameStatic:
@@ -75,6 +83,17 @@ func (d *Distro) GenerateName() string {
 		}
 	}
 
+	if d.Provider == evergreen.ProviderNameAzure {
+		// VM names are also used as host names, so only allow
+		// alphanumerics and hyphens
+		r, _ := regexp.Compile("[^a-zA-Z0-9-]+")
+		name = string(r.ReplaceAll([]byte(name), []byte("")))
+
+		if len(name) > azureMaxNameLength {
+			name = name[:azureMaxNameLength]
+		}
+	}
+
 	return name
 }
 
@@ -112,7 +131,7 @@ func (d *Distro) IsParent(s *evergreen.Settings) bool {
 		}
 	}
 	for _, p := range s.ContainerPools.Pools {
-		if d.Id == p.Distro {
+		if util.StringSliceContains(p.ParentDistros(), d.Id) {
 			return true
 		}
 	}
@@ -131,6 +150,17 @@ func ValidateContainerPoolDistros(s *evergreen.Settings) error {
 		if d.ContainerPool != "" {
 			catcher.Add(fmt.Errorf("container pool %s has invalid distro", pool.Id))
 		}
+		if !pool.IsMixed() {
+			continue
+		}
+		windowsDistro, err := FindOne(ById(pool.WindowsDistro))
+		if err != nil {
+			catcher.Add(fmt.Errorf("error finding windows distro for container pool %s", pool.Id))
+			continue
+		}
+		if windowsDistro.ContainerPool != "" || !windowsDistro.IsWindows() {
+			catcher.Add(fmt.Errorf("container pool %s has invalid windows distro", pool.Id))
+		}
 	}
 	return errors.WithStack(catcher.Resolve())
 }
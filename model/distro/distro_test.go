@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
@@ -53,6 +54,19 @@ func TestGenerateGceName(t *testing.T) {
 	assert.True(r.Match([]byte(tooManyChars)))
 }
 
+func TestGenerateAzureName(t *testing.T) {
+	assert := assert.New(t)
+
+	r, err := regexp.Compile("^[a-zA-Z0-9-]{1,64}$")
+	assert.NoError(err)
+	d := Distro{Id: "ubuntu1804_large.test", Provider: evergreen.ProviderNameAzure}
+
+	assert.True(r.MatchString(d.GenerateName()))
+
+	d.Id = strings.Repeat("abc", 30)
+	assert.True(r.MatchString(d.GenerateName()))
+}
+
 func TestIsParent(t *testing.T) {
 	assert := assert.New(t)
 	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
@@ -153,3 +167,76 @@ func TestGetDistroIds(t *testing.T) {
 	ids := hosts.GetDistroIds()
 	assert.Equal([]string{"d1", "d2", "d3"}, ids)
 }
+
+func TestHostHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	hooks := HostHooks{
+		PreProvision: &HostHook{Script: "register-license"},
+		PreTerminate: &HostHook{Script: "deregister-license", TimeoutSecs: 30},
+	}
+	assert.Equal("register-license", hooks.Get(HookPreProvision).Script)
+	assert.Equal("deregister-license", hooks.Get(HookPreTerminate).Script)
+	assert.Nil(hooks.Get("post_terminate"))
+	assert.Nil(HostHooks{}.Get(HookPreProvision))
+
+	assert.Equal(DefaultHookTimeout, hooks.PreProvision.Timeout())
+	assert.Equal(30*time.Second, hooks.PreTerminate.Timeout())
+	assert.NoError(hooks.Validate())
+}
+
+func TestIdlePolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DefaultMaxIdleTime, IdlePolicy{}.MaxIdleTime())
+	assert.Equal(30*time.Minute, IdlePolicy{MaxIdleMins: 30}.MaxIdleTime())
+
+	// without a policy every idle host may be terminated
+	assert.Equal(3, IdlePolicy{}.NumToTerminate(5, 3))
+
+	policy := IdlePolicy{MinPoolSize: 4, ScaleDownStep: 2}
+	assert.Equal(1, policy.NumToTerminate(5, 3))
+	assert.Equal(2, policy.NumToTerminate(10, 3))
+	assert.Equal(0, policy.NumToTerminate(4, 3))
+	assert.Equal(0, policy.NumToTerminate(2, 2))
+
+	assert.NoError(policy.Validate(4))
+	assert.Error(policy.Validate(3))
+	assert.Error(IdlePolicy{ScaleDownStep: -1}.Validate(3))
+}
+
+func TestParentImageRollout(t *testing.T) {
+	assert := assert.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	assert.NoError(db.Clear(Collection))
+
+	d := &Distro{
+		Id:               "parent",
+		ProviderSettings: &map[string]interface{}{ParentImageSettingsKey: "ami-1"},
+	}
+	assert.NoError(d.Insert())
+	assert.Error(d.RollBackParentImage())
+
+	rolledOut, err := d.RollOutParentImage("ami-1")
+	assert.NoError(err)
+	assert.False(rolledOut)
+
+	rolledOut, err = d.RollOutParentImage("ami-2")
+	assert.NoError(err)
+	assert.True(rolledOut)
+	assert.Equal("ami-2", d.ParentImage())
+	assert.Equal("ami-1", d.ParentImageRollout.PreviousImage)
+
+	assert.NoError(d.RollBackParentImage())
+	dbDistro, err := FindOne(ById(d.Id))
+	assert.NoError(err)
+	assert.Equal("ami-1", dbDistro.ParentImage())
+	assert.Empty(dbDistro.ParentImageRollout.PreviousImage)
+	assert.True(dbDistro.ParentImageRollout.IsRejected("ami-2"))
+
+	// rejected images aren't rolled out again
+	rolledOut, err = dbDistro.RollOutParentImage("ami-2")
+	assert.NoError(err)
+	assert.False(rolledOut)
+	assert.Equal("ami-1", dbDistro.ParentImage())
+}
@@ -0,0 +1,79 @@
+package distro
+
+import (
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	// HookPreProvision is run on a host once it is set up, before it is
+	// marked as provisioned and goes into the distro's pool.
+	HookPreProvision = "pre_provision"
+	// HookPreTerminate is run on a host before its instance is terminated.
+	HookPreTerminate = "pre_terminate"
+
+	// DefaultHookTimeout is the timeout of a hook that does not set one.
+	DefaultHookTimeout = 5 * time.Minute
+	// MaxHookTimeout is the longest timeout a hook may set.
+	MaxHookTimeout = time.Hour
+)
+
+// HostHooks are scripts run on each of a distro's hosts at points in its
+// lifecycle, e.g. to register the host with a license server once it is set
+// up, and to deregister it before it is terminated.
+type HostHooks struct {
+	PreProvision *HostHook `bson:"pre_provision,omitempty" json:"pre_provision,omitempty" mapstructure:"pre_provision,omitempty"`
+	PreTerminate *HostHook `bson:"pre_terminate,omitempty" json:"pre_terminate,omitempty" mapstructure:"pre_terminate,omitempty"`
+}
+
+// HostHook is a script run on a host over SSH.
+type HostHook struct {
+	Script      string `bson:"script" json:"script" mapstructure:"script"`
+	TimeoutSecs int    `bson:"timeout_secs,omitempty" json:"timeout_secs,omitempty" mapstructure:"timeout_secs,omitempty"`
+
+	// AllowFailure lets a host go into the pool even if its pre-provision
+	// hook fails. Otherwise the failure counts as a provisioning failure.
+	// Hosts are terminated whether or not their pre-terminate hook succeeds.
+	AllowFailure bool `bson:"allow_failure,omitempty" json:"allow_failure,omitempty" mapstructure:"allow_failure,omitempty"`
+}
+
+// Get returns the hook for the stage, or nil if the distro does not have one.
+func (h HostHooks) Get(stage string) *HostHook {
+	switch stage {
+	case HookPreProvision:
+		return h.PreProvision
+	case HookPreTerminate:
+		return h.PreTerminate
+	default:
+		return nil
+	}
+}
+
+// Validate checks that each of the hooks has a script and a timeout within
+// bounds.
+func (h HostHooks) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	for _, stage := range []string{HookPreProvision, HookPreTerminate} {
+		hook := h.Get(stage)
+		if hook == nil {
+			continue
+		}
+		if hook.Script == "" {
+			catcher.Add(errors.Errorf("%s hook must have a script", stage))
+		}
+		if hook.TimeoutSecs < 0 || time.Duration(hook.TimeoutSecs)*time.Second > MaxHookTimeout {
+			catcher.Add(errors.Errorf("%s hook timeout must be between 0 and %d seconds", stage, int(MaxHookTimeout.Seconds())))
+		}
+	}
+	return catcher.Resolve()
+}
+
+// Timeout returns how long the hook may run for.
+func (h *HostHook) Timeout() time.Duration {
+	if h.TimeoutSecs <= 0 {
+		return DefaultHookTimeout
+	}
+	return time.Duration(h.TimeoutSecs) * time.Second
+}
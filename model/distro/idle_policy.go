@@ -0,0 +1,84 @@
+package distro
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DefaultMaxIdleTime is how long a host of a distro without an idle policy
+// may be idle before it is terminated.
+const DefaultMaxIdleTime = 4 * time.Minute
+
+// IdlePolicy controls how the idle hosts of a distro are terminated. The zero
+// value terminates every host once it has been idle for DefaultMaxIdleTime.
+type IdlePolicy struct {
+	// MinPoolSize is the number of running hosts below which idle hosts are
+	// kept rather than terminated.
+	MinPoolSize int `bson:"min_pool_size,omitempty" json:"min_pool_size,omitempty" mapstructure:"min_pool_size,omitempty"`
+	// MaxIdleMins is how long a host may be idle before it is terminated.
+	MaxIdleMins int `bson:"max_idle_mins,omitempty" json:"max_idle_mins,omitempty" mapstructure:"max_idle_mins,omitempty"`
+	// ScaleDownStep is the most hosts that are terminated for being idle
+	// at once. If it is 0, every idle host may be terminated.
+	ScaleDownStep int `bson:"scale_down_step,omitempty" json:"scale_down_step,omitempty" mapstructure:"scale_down_step,omitempty"`
+}
+
+// MaxIdleTime returns how long a host may be idle before it is terminated.
+func (p IdlePolicy) MaxIdleTime() time.Duration {
+	if p.MaxIdleMins <= 0 {
+		return DefaultMaxIdleTime
+	}
+	return time.Duration(p.MaxIdleMins) * time.Minute
+}
+
+// NumToTerminate returns how many of the idle hosts of a distro with the
+// given number of running hosts may be terminated at once.
+func (p IdlePolicy) NumToTerminate(numRunning, numIdle int) int {
+	n := numRunning - p.MinPoolSize
+	if numIdle < n {
+		n = numIdle
+	}
+	if p.ScaleDownStep > 0 && p.ScaleDownStep < n {
+		n = p.ScaleDownStep
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Validate checks that the policy's settings are not negative and that it
+// does not keep more hosts than the distro's pool may have.
+func (p IdlePolicy) Validate(poolSize int) error {
+	catcher := grip.NewBasicCatcher()
+	if p.MinPoolSize < 0 {
+		catcher.Add(errors.New("minimum pool size cannot be negative"))
+	}
+	if p.MinPoolSize > poolSize {
+		catcher.Add(errors.Errorf("minimum pool size %d cannot be greater than the pool size %d", p.MinPoolSize, poolSize))
+	}
+	if p.MaxIdleMins < 0 {
+		catcher.Add(errors.New("maximum idle time cannot be negative"))
+	}
+	if p.ScaleDownStep < 0 {
+		catcher.Add(errors.New("scale down step cannot be negative"))
+	}
+	return catcher.Resolve()
+}
+
+// SetIdlePolicy replaces the distro's idle policy.
+func (d *Distro) SetIdlePolicy(policy IdlePolicy) error {
+	err := db.Update(
+		Collection,
+		bson.M{IdKey: d.Id},
+		bson.M{"$set": bson.M{IdlePolicyKey: policy}},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "problem setting idle policy of distro '%s'", d.Id)
+	}
+	d.IdlePolicy = policy
+	return nil
+}
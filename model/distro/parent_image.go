@@ -0,0 +1,106 @@
+package distro
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ParentImageSettingsKey is the provider setting that holds the image that
+// the hosts of a container pool's parent distro are started from.
+const ParentImageSettingsKey = "ami"
+
+// ParentImageRollout records the baked images rolled out to a container
+// pool's parent distro, so that a rollout can be rolled back.
+type ParentImageRollout struct {
+	// PreviousImage is the image that the distro's hosts were started from
+	// before the current image was rolled out.
+	PreviousImage string `bson:"previous_image,omitempty" json:"previous_image,omitempty" mapstructure:"previous_image,omitempty"`
+	// RolledOutAt is when the current image was rolled out or rolled back.
+	RolledOutAt time.Time `bson:"rolled_out_at,omitempty" json:"rolled_out_at,omitempty" mapstructure:"rolled_out_at,omitempty"`
+	// RejectedImages are the images that were rolled back, which are not
+	// rolled out again.
+	RejectedImages []string `bson:"rejected_images,omitempty" json:"rejected_images,omitempty" mapstructure:"rejected_images,omitempty"`
+}
+
+// IsRejected returns whether the image was rolled back.
+func (r ParentImageRollout) IsRejected(imageID string) bool {
+	return util.StringSliceContains(r.RejectedImages, imageID)
+}
+
+// ParentImage returns the image that the distro's hosts are started from.
+func (d *Distro) ParentImage() string {
+	if d.ProviderSettings == nil {
+		return ""
+	}
+	image, _ := (*d.ProviderSettings)[ParentImageSettingsKey].(string)
+	return image
+}
+
+// RollOutParentImage starts the distro's hosts from the image, and keeps the
+// image they were started from before so that it can be rolled back to. It
+// returns whether the image was rolled out, which it isn't if it is already
+// in use or was rolled back before.
+func (d *Distro) RollOutParentImage(imageID string) (bool, error) {
+	current := d.ParentImage()
+	if current == imageID || d.ParentImageRollout.IsRejected(imageID) {
+		return false, nil
+	}
+
+	rollout := d.ParentImageRollout
+	rollout.PreviousImage = current
+	rollout.RolledOutAt = time.Now()
+	if err := d.setParentImage(imageID, rollout); err != nil {
+		return false, errors.Wrapf(err, "problem rolling out image '%s' to distro '%s'", imageID, d.Id)
+	}
+
+	return true, nil
+}
+
+// RollBackParentImage starts the distro's hosts from the image they were
+// started from before the current image was rolled out, and rejects the
+// current image so that it isn't rolled out again.
+func (d *Distro) RollBackParentImage() error {
+	rollout := d.ParentImageRollout
+	if rollout.PreviousImage == "" {
+		return errors.Errorf("distro '%s' has no previous image to roll back to", d.Id)
+	}
+
+	previous := rollout.PreviousImage
+	if current := d.ParentImage(); current != "" && !rollout.IsRejected(current) {
+		rollout.RejectedImages = append(rollout.RejectedImages, current)
+	}
+	rollout.PreviousImage = ""
+	rollout.RolledOutAt = time.Now()
+	if err := d.setParentImage(previous, rollout); err != nil {
+		return errors.Wrapf(err, "problem rolling back distro '%s' to image '%s'", d.Id, previous)
+	}
+
+	return nil
+}
+
+func (d *Distro) setParentImage(imageID string, rollout ParentImageRollout) error {
+	if d.ProviderSettings == nil {
+		return errors.New("distro has no provider settings")
+	}
+
+	err := db.Update(
+		Collection,
+		bson.M{IdKey: d.Id},
+		bson.M{"$set": bson.M{
+			bsonutil.GetDottedKeyName(ProviderSettingsKey, ParentImageSettingsKey): imageID,
+			ParentImageRolloutKey: rollout,
+		}},
+	)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	(*d.ProviderSettings)[ParentImageSettingsKey] = imageID
+	d.ParentImageRollout = rollout
+	return nil
+}
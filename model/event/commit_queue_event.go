@@ -0,0 +1,69 @@
+package event
+
+import (
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+func init() {
+	registry.AddType(ResourceTypeCommitQueue, commitQueueEventDataFactory)
+}
+
+func commitQueueEventDataFactory() interface{} {
+	return &CommitQueueEventData{}
+}
+
+const (
+	ResourceTypeCommitQueue = "COMMIT_QUEUE"
+
+	CommitQueueEnqueue        = "ENQUEUE"
+	CommitQueueDequeue        = "DEQUEUE"
+	CommitQueuePositionChange = "POSITION_CHANGE"
+	CommitQueueStartTest      = "START_TEST"
+)
+
+// CommitQueueEventData describes a change to an item in a project's commit
+// queue. Events are logged with the project as the resource.
+type CommitQueueEventData struct {
+	Issue    string `bson:"issue" json:"issue"`
+	Position int    `bson:"position" json:"position"`
+	Status   string `bson:"status,omitempty" json:"status,omitempty"`
+	Version  string `bson:"version,omitempty" json:"version,omitempty"`
+}
+
+func logCommitQueueEvent(projectID, eventType string, data *CommitQueueEventData) {
+	event := EventLogEntry{
+		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
+		ResourceId:   projectID,
+		ResourceType: ResourceTypeCommitQueue,
+		EventType:    eventType,
+		Data:         data,
+	}
+
+	logger := NewDBEventLogger(AllLogCollection)
+	if err := logger.LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeCommitQueue,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+	}
+}
+
+func LogCommitQueueEnqueueEvent(projectID, issue string, position int) {
+	logCommitQueueEvent(projectID, CommitQueueEnqueue, &CommitQueueEventData{Issue: issue, Position: position})
+}
+
+func LogCommitQueueDequeueEvent(projectID, issue, status string) {
+	logCommitQueueEvent(projectID, CommitQueueDequeue, &CommitQueueEventData{Issue: issue, Status: status})
+}
+
+func LogCommitQueuePositionChangeEvent(projectID, issue string, position int) {
+	logCommitQueueEvent(projectID, CommitQueuePositionChange, &CommitQueueEventData{Issue: issue, Position: position})
+}
+
+func LogCommitQueueStartTestEvent(projectID, issue, version string) {
+	logCommitQueueEvent(projectID, CommitQueueStartTest, &CommitQueueEventData{Issue: issue, Version: version})
+}
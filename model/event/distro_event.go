@@ -21,11 +21,13 @@ const (
 	EventDistroRemoved  = "DISTRO_REMOVED"
 )
 
-// DistroEventData implements EventData.
+// DistroEventData implements EventData. Data is the distro after the
+// event, and Before is the distro before it was modified.
 type DistroEventData struct {
 	DistroId string      `bson:"d_id,omitempty" json:"d_id,omitempty"`
 	UserId   string      `bson:"u_id,omitempty" json:"u_id,omitempty"`
 	Data     interface{} `bson:"dstr,omitempty" json:"dstr,omitempty"`
+	Before   interface{} `bson:"before,omitempty" json:"before,omitempty"`
 }
 
 func LogDistroEvent(distroId string, eventType string, eventData DistroEventData) {
@@ -50,8 +52,8 @@ func LogDistroAdded(distroId, userId string, data interface{}) {
 	LogDistroEvent(distroId, EventDistroAdded, DistroEventData{UserId: userId, Data: data})
 }
 
-func LogDistroModified(distroId, userId string, data interface{}) {
-	LogDistroEvent(distroId, EventDistroModified, DistroEventData{UserId: userId, Data: data})
+func LogDistroModified(distroId, userId string, before, after interface{}) {
+	LogDistroEvent(distroId, EventDistroModified, DistroEventData{UserId: userId, Data: after, Before: before})
 }
 
 func LogDistroRemoved(distroId, userId string, data interface{}) {
@@ -25,7 +25,7 @@ func TestLoggingDistroEvents(t *testing.T) {
 			// log some events, sleeping in between to make sure the times are different
 			LogDistroAdded(distroId, userId, nil)
 			time.Sleep(1 * time.Millisecond)
-			LogDistroModified(distroId, userId, "update")
+			LogDistroModified(distroId, userId, "original", "update")
 			time.Sleep(1 * time.Millisecond)
 			LogDistroRemoved(distroId, userId, nil)
 			time.Sleep(1 * time.Millisecond)
@@ -55,6 +55,7 @@ func TestLoggingDistroEvents(t *testing.T) {
 			So(event.ResourceType, ShouldEqual, ResourceTypeDistro)
 			So(eventData.UserId, ShouldEqual, userId)
 			So(eventData.Data.(string), ShouldEqual, "update")
+			So(eventData.Before.(string), ShouldEqual, "original")
 
 			event = eventsForDistro[2]
 			So(event.EventType, ShouldEqual, EventDistroRemoved)
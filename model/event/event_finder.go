@@ -35,6 +35,68 @@ func Find(coll string, query db.Q) ([]EventLogEntry, error) {
 	return events, err
 }
 
+// FindLogEntryByID returns the event with the given ID from the event log,
+// or nil if there is none.
+func FindLogEntryByID(id string) (*EventLogEntry, error) {
+	events, err := Find(AllLogCollection, db.Query(bson.M{idKey: id}).Limit(1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find event '%s'", id)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return &events[0], nil
+}
+
+// EventCursor is a position in the event log. Events are ordered by their
+// timestamp and then their ID.
+type EventCursor struct {
+	Timestamp time.Time `bson:"ts"`
+	EventID   string    `bson:"event_id"`
+}
+
+// eventSettleTime is how old an event must be before it's returned after a
+// cursor. Events are timestamped before they're inserted, so waiting for
+// them to settle keeps an event that's logged slightly late from being
+// skipped by a cursor that's already past it.
+const eventSettleTime = 10 * time.Second
+
+// FindEventsAfter returns, in the order they were logged, at most limit of
+// the events of the given resource types after the cursor. If any event
+// types are given, only events of those types are returned.
+func FindEventsAfter(cursor EventCursor, resourceTypes, eventTypes []string, limit int) ([]EventLogEntry, error) {
+	filter := bson.M{
+		ResourceTypeKey: bson.M{"$in": resourceTypes},
+		"$and": []bson.M{
+			{TimestampKey: bson.M{"$lte": time.Now().Add(-eventSettleTime)}},
+			{"$or": []bson.M{
+				{TimestampKey: bson.M{"$gt": cursor.Timestamp}},
+				{TimestampKey: cursor.Timestamp, idKey: bson.M{"$gt": cursor.EventID}},
+			}},
+		},
+	}
+	if len(eventTypes) > 0 {
+		filter[TypeKey] = bson.M{"$in": eventTypes}
+	}
+
+	return Find(AllLogCollection, db.Query(filter).Sort([]string{TimestampKey, idKey}).Limit(limit))
+}
+
+// CursorAt returns the position of the event with the given ID, so that
+// replaying from it delivers the events after it.
+func CursorAt(eventID string) (*EventCursor, error) {
+	events, err := Find(AllLogCollection, db.Query(bson.M{idKey: eventID}).Limit(1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find event '%s'", eventID)
+	}
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	return &EventCursor{Timestamp: events[0].Timestamp, EventID: events[0].ID}, nil
+}
+
 // FindUnprocessedEvents returns all unprocessed events in AllLogCollection.
 // Events are considered unprocessed if their "processed_at" time IsZero
 func FindUnprocessedEvents() ([]EventLogEntry, error) {
@@ -154,6 +216,19 @@ func DistroEventsInOrder(id string) db.Q {
 	return DistroEventsForId(id).Sort([]string{TimestampKey})
 }
 
+// Project Events
+// ProjectEventsBefore returns the n most recent changes to the project's
+// settings before the given time.
+func ProjectEventsBefore(id string, before time.Time, n int) db.Q {
+	filter := resourceTypeKeyIs(ResourceTypeProject)
+	filter[ResourceIdKey] = id
+	filter[TimestampKey] = bson.M{
+		"$lt": before,
+	}
+
+	return db.Query(filter).Sort([]string{"-" + TimestampKey}).Limit(n)
+}
+
 // Scheduler Events
 func SchedulerEventsForId(distroID string) db.Q {
 	filter := resourceTypeKeyIs(ResourceTypeScheduler)
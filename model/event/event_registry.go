@@ -110,3 +110,7 @@ func taskProcessResourceEventDataFactory() interface{} {
 func adminEventDataFactory() interface{} {
 	return &rawAdminEventData{}
 }
+
+func projectEventDataFactory() interface{} {
+	return &ProjectChangeEventData{}
+}
@@ -0,0 +1,257 @@
+package event
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	EventWebhooksCollection = "event_webhooks"
+)
+
+// EventWebhookResourceTypes are the resource types whose events can be
+// streamed to event webhooks.
+var EventWebhookResourceTypes = []string{
+	ResourceTypeVersion,
+	ResourceTypeBuild,
+	ResourceTypeTask,
+	ResourceTypeHost,
+}
+
+// nolint: deadcode, megacheck, unused
+var (
+	eventWebhookIDKey            = bsonutil.MustHaveTag(EventWebhook{}, "ID")
+	eventWebhookCursorKey        = bsonutil.MustHaveTag(EventWebhook{}, "Cursor")
+	eventWebhookLastAttemptAtKey = bsonutil.MustHaveTag(EventWebhook{}, "LastAttemptAt")
+	eventWebhookLastErrorKey     = bsonutil.MustHaveTag(EventWebhook{}, "LastError")
+	eventCursorTimestampKey      = bsonutil.MustHaveTag(EventCursor{}, "Timestamp")
+	eventCursorEventIDKey        = bsonutil.MustHaveTag(EventCursor{}, "EventID")
+)
+
+// EventWebhook is an endpoint registered by an admin that receives the
+// events for versions, builds, tasks and hosts, in the order they were
+// logged, as they happen.
+type EventWebhook struct {
+	ID     string `bson:"_id"`
+	URL    string `bson:"url"`
+	Secret []byte `bson:"secret"`
+
+	// ResourceTypes and EventTypes filter the events delivered to the
+	// webhook. No resource types means all of EventWebhookResourceTypes,
+	// and no event types means all events of those resources.
+	ResourceTypes []string `bson:"resource_types,omitempty"`
+	EventTypes    []string `bson:"event_types,omitempty"`
+
+	// Cursor is the position in the event log of the last event delivered
+	// to the webhook.
+	Cursor EventCursor `bson:"cursor"`
+
+	LastAttemptAt time.Time `bson:"last_attempt_at,omitempty"`
+	LastError     string    `bson:"last_error,omitempty"`
+
+	CreatedBy string    `bson:"created_by"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// WebhookEvent is an event as it's delivered to an event webhook.
+type WebhookEvent struct {
+	ID           string      `json:"id"`
+	ResourceType string      `json:"resource_type"`
+	ResourceID   string      `json:"resource_id"`
+	EventType    string      `json:"event_type"`
+	Timestamp    time.Time   `json:"timestamp"`
+	Data         interface{} `json:"data"`
+}
+
+// WebhookEventBatch is the body of a request delivering events to an event
+// webhook. Cursor is the ID of the last event in the batch, which can be
+// used to replay the events after it.
+type WebhookEventBatch struct {
+	WebhookID string         `json:"webhook_id"`
+	Cursor    string         `json:"cursor"`
+	Events    []WebhookEvent `json:"events"`
+}
+
+// NewEventWebhook returns a webhook, with a new secret, that receives the
+// events logged from now on.
+func NewEventWebhook(webhookURL string, resourceTypes, eventTypes []string, user string) (*EventWebhook, error) {
+	now := time.Now().Truncate(time.Millisecond)
+
+	w := &EventWebhook{
+		ID:            bson.NewObjectId().Hex(),
+		URL:           webhookURL,
+		Secret:        []byte(util.RandomString()),
+		ResourceTypes: resourceTypes,
+		EventTypes:    eventTypes,
+		Cursor:        EventCursor{Timestamp: now},
+		CreatedBy:     user,
+		CreatedAt:     now,
+	}
+
+	return w, w.Validate()
+}
+
+func (w *EventWebhook) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	u, err := url.Parse(w.URL)
+	if err != nil {
+		catcher.Add(errors.Wrap(err, "invalid webhook url"))
+	} else if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		catcher.Add(errors.Errorf("webhook url '%s' must be an absolute http or https url", w.URL))
+	}
+	if len(w.Secret) == 0 {
+		catcher.Add(errors.New("webhook secret cannot be empty"))
+	}
+	for _, resourceType := range w.ResourceTypes {
+		if !util.StringSliceContains(EventWebhookResourceTypes, resourceType) {
+			catcher.Add(errors.Errorf("events for resource type '%s' can't be sent to webhooks", resourceType))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+func (w *EventWebhook) Insert() error {
+	return errors.Wrap(db.Insert(EventWebhooksCollection, w), "failed to insert event webhook")
+}
+
+func FindEventWebhook(id string) (*EventWebhook, error) {
+	w := EventWebhook{}
+	err := db.FindOneQ(EventWebhooksCollection, db.Query(bson.M{
+		eventWebhookIDKey: id,
+	}), &w)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &w, errors.Wrapf(err, "failed to find event webhook '%s'", id)
+}
+
+func FindAllEventWebhooks() ([]EventWebhook, error) {
+	webhooks := []EventWebhook{}
+	err := db.FindAllQ(EventWebhooksCollection, db.Query(bson.M{}).Sort([]string{eventWebhookIDKey}), &webhooks)
+
+	return webhooks, errors.Wrap(err, "failed to find event webhooks")
+}
+
+func RemoveEventWebhook(id string) error {
+	err := db.Remove(EventWebhooksCollection, bson.M{
+		eventWebhookIDKey: id,
+	})
+	if err == mgo.ErrNotFound {
+		return errors.Errorf("event webhook '%s' does not exist", id)
+	}
+
+	return errors.Wrapf(err, "failed to remove event webhook '%s'", id)
+}
+
+// Events returns at most limit of the events after the webhook's cursor that
+// pass its filters, in the order they were logged.
+func (w *EventWebhook) Events(limit int) ([]EventLogEntry, error) {
+	resourceTypes := w.ResourceTypes
+	if len(resourceTypes) == 0 {
+		resourceTypes = EventWebhookResourceTypes
+	}
+
+	events, err := FindEventsAfter(w.Cursor, resourceTypes, w.EventTypes, limit)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find events for event webhook '%s'", w.ID)
+	}
+
+	return events, nil
+}
+
+// AdvanceCursor moves the webhook's cursor to the given position, provided
+// it hasn't moved since the webhook was fetched. It returns false if the
+// cursor had moved, because another dispatcher delivered the events or the
+// webhook is being replayed.
+func (w *EventWebhook) AdvanceCursor(cursor EventCursor) (bool, error) {
+	err := db.Update(EventWebhooksCollection, bson.M{
+		eventWebhookIDKey: w.ID,
+		bsonutil.GetDottedKeyName(eventWebhookCursorKey, eventCursorTimestampKey): w.Cursor.Timestamp,
+		bsonutil.GetDottedKeyName(eventWebhookCursorKey, eventCursorEventIDKey):   w.Cursor.EventID,
+	}, bson.M{
+		"$set": bson.M{
+			eventWebhookCursorKey: cursor,
+		},
+		"$unset": bson.M{
+			eventWebhookLastErrorKey: 1,
+		},
+	})
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to advance the cursor of event webhook '%s'", w.ID)
+	}
+
+	w.Cursor = cursor
+	w.LastError = ""
+
+	return true, nil
+}
+
+// SetCursor moves the webhook's cursor to the given position, so that the
+// events after it are delivered again.
+func (w *EventWebhook) SetCursor(cursor EventCursor) error {
+	err := db.Update(EventWebhooksCollection, bson.M{
+		eventWebhookIDKey: w.ID,
+	}, bson.M{
+		"$set": bson.M{
+			eventWebhookCursorKey: cursor,
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the cursor of event webhook '%s'", w.ID)
+	}
+
+	w.Cursor = cursor
+
+	return nil
+}
+
+// RecordAttempt records an attempt to deliver events to the webhook, and
+// the error, if any, that it failed with.
+func (w *EventWebhook) RecordAttempt(at time.Time, deliveryErr error) error {
+	update := bson.M{
+		"$set": bson.M{
+			eventWebhookLastAttemptAtKey: at,
+		},
+	}
+	errMsg := ""
+	if deliveryErr != nil {
+		errMsg = deliveryErr.Error()
+		update["$set"].(bson.M)[eventWebhookLastErrorKey] = errMsg
+	}
+
+	if err := db.Update(EventWebhooksCollection, bson.M{eventWebhookIDKey: w.ID}, update); err != nil {
+		return errors.Wrapf(err, "failed to record delivery attempt for event webhook '%s'", w.ID)
+	}
+
+	w.LastAttemptAt = at
+	if deliveryErr != nil {
+		w.LastError = errMsg
+	}
+
+	return nil
+}
+
+// NewWebhookEvent returns the event as it's delivered to event webhooks.
+func NewWebhookEvent(e *EventLogEntry) WebhookEvent {
+	return WebhookEvent{
+		ID:           e.ID,
+		ResourceType: e.ResourceType,
+		ResourceID:   e.ResourceId,
+		EventType:    e.EventType,
+		Timestamp:    e.Timestamp,
+		Data:         e.Data,
+	}
+}
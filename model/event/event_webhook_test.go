@@ -0,0 +1,92 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventWebhookEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(AllLogCollection, EventWebhooksCollection))
+
+	_, err := NewEventWebhook("not a url", nil, nil, "me")
+	assert.Error(err)
+	_, err = NewEventWebhook("https://example.com", []string{ResourceTypePatch}, nil, "me")
+	assert.Error(err)
+
+	w, err := NewEventWebhook("https://example.com", []string{ResourceTypeTask, ResourceTypeBuild}, nil, "me")
+	require.NoError(err)
+	assert.NotEmpty(w.Secret)
+	w.Cursor = EventCursor{Timestamp: time.Now().Add(-time.Hour).Truncate(time.Millisecond)}
+	require.NoError(w.Insert())
+
+	logger := NewDBEventLogger(AllLogCollection)
+	ts := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	for _, e := range []EventLogEntry{
+		{ID: "e0", ResourceType: ResourceTypeTask, ResourceId: "t0", EventType: TaskStarted, Timestamp: ts, Data: &TaskEventData{}},
+		{ID: "e1", ResourceType: ResourceTypeBuild, ResourceId: "b0", EventType: BuildStateChange, Timestamp: ts, Data: &BuildEventData{}},
+		{ID: "e2", ResourceType: ResourceTypeTask, ResourceId: "t0", EventType: TaskFinished, Timestamp: ts.Add(time.Second), Data: &TaskEventData{}},
+		{ID: "e3", ResourceType: ResourceTypeHost, ResourceId: "h0", EventType: EventHostCreated, Timestamp: ts, Data: &HostEventData{}},
+		// too recent to have settled
+		{ID: "e4", ResourceType: ResourceTypeTask, ResourceId: "t0", EventType: TaskFinished, Timestamp: time.Now(), Data: &TaskEventData{}},
+	} {
+		e := e
+		require.NoError(logger.LogEvent(&e))
+	}
+
+	events, err := w.Events(2)
+	assert.NoError(err)
+	require.Len(events, 2)
+	assert.Equal("e0", events[0].ID)
+	assert.Equal("e1", events[1].ID)
+
+	// a stale copy can't advance the cursor
+	stale := *w
+	advanced, err := w.AdvanceCursor(EventCursor{Timestamp: events[1].Timestamp, EventID: events[1].ID})
+	assert.NoError(err)
+	assert.True(advanced)
+	advanced, err = stale.AdvanceCursor(EventCursor{Timestamp: events[0].Timestamp, EventID: events[0].ID})
+	assert.NoError(err)
+	assert.False(advanced)
+
+	w, err = FindEventWebhook(w.ID)
+	assert.NoError(err)
+	require.NotNil(w)
+	events, err = w.Events(10)
+	assert.NoError(err)
+	require.Len(events, 1)
+	assert.Equal("e2", events[0].ID)
+
+	w.EventTypes = []string{TaskStarted}
+	events, err = w.Events(10)
+	assert.NoError(err)
+	assert.Empty(events)
+
+	// replaying from an event delivers the events after it again
+	cursor, err := CursorAt("e0")
+	assert.NoError(err)
+	require.NotNil(cursor)
+	w.EventTypes = nil
+	require.NoError(w.SetCursor(*cursor))
+	events, err = w.Events(10)
+	assert.NoError(err)
+	require.Len(events, 2)
+	assert.Equal("e1", events[0].ID)
+	assert.Equal("e2", events[1].ID)
+
+	cursor, err = CursorAt("nonexistent")
+	assert.NoError(err)
+	assert.Nil(cursor)
+
+	require.NoError(RemoveEventWebhook(w.ID))
+	w, err = FindEventWebhook(w.ID)
+	assert.NoError(err)
+	assert.Nil(w)
+}
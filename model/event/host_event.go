@@ -39,8 +39,18 @@ const (
 	EventHostMonitorFlag           = "HOST_MONITOR_FLAG"
 	EventTaskFinished              = "HOST_TASK_FINISHED"
 	EventHostTeardown              = "HOST_TEARDOWN"
+	EventHostHookRun               = "HOST_HOOK_RUN"
+	EventHostOwnerChanged          = "HOST_OWNER_CHANGED"
+	EventHostVolumeAttached        = "HOST_VOLUME_ATTACHED"
+	EventHostVolumeDetached        = "HOST_VOLUME_DETACHED"
+	EventHostDrainStarted          = "HOST_DRAIN_STARTED"
+	EventHostDrainStopped          = "HOST_DRAIN_STOPPED"
+	EventHostProblemDetected       = "HOST_PROBLEM_DETECTED"
 	EventHostTerminatedExternally  = "HOST_TERMINATED_EXTERNALLY"
 	EventHostExpirationWarningSent = "HOST_EXPIRATION_WARNING_SENT"
+	EventHostArtifactsCollected    = "HOST_ARTIFACTS_COLLECTED"
+	EventHostCapacityFallback      = "HOST_CAPACITY_FALLBACK"
+	EventHostRegionFailover        = "HOST_REGION_FAILOVER"
 )
 
 // implements EventData
@@ -59,6 +69,15 @@ type HostEventData struct {
 	User          string        `bson:"usr" json:"user,omitempty"`
 	Successful    bool          `bson:"successful,omitempty" json:"successful"`
 	Duration      time.Duration `bson:"duration,omitempty" json:"duration"`
+	Artifacts     []string      `bson:"artifacts,omitempty" json:"artifacts,omitempty"`
+	Reason        string        `bson:"reason,omitempty" json:"reason,omitempty"`
+	Replacement   string        `bson:"replacement,omitempty" json:"replacement,omitempty"`
+	Hook          string        `bson:"hook,omitempty" json:"hook,omitempty"`
+	OldOwner      string        `bson:"o_owner,omitempty" json:"old_owner,omitempty"`
+	NewOwner      string        `bson:"n_owner,omitempty" json:"new_owner,omitempty"`
+	VolumeID      string        `bson:"vol_id,omitempty" json:"volume_id,omitempty"`
+	FailedTasks   []string      `bson:"failed_tasks,omitempty" json:"failed_tasks,omitempty"`
+	Region        string        `bson:"region,omitempty" json:"region,omitempty"`
 }
 
 var (
@@ -155,6 +174,43 @@ func LogHostTeardown(hostId, teardownLogs string, success bool, duration time.Du
 		HostEventData{Logs: teardownLogs, Successful: success, Duration: duration})
 }
 
+// LogHostHookRun records the outcome of running one of the distro's
+// lifecycle hooks on a host.
+func LogHostHookRun(hostId, hook, logs string, success bool, duration time.Duration) {
+	LogHostEvent(hostId, EventHostHookRun,
+		HostEventData{Hook: hook, Logs: logs, Successful: success, Duration: duration})
+}
+
+// LogHostOwnerChanged records that a user transferred a spawn host to
+// another user.
+func LogHostOwnerChanged(hostId, oldOwner, newOwner, user string) {
+	LogHostEvent(hostId, EventHostOwnerChanged,
+		HostEventData{OldOwner: oldOwner, NewOwner: newOwner, User: user})
+}
+
+func LogHostVolumeAttached(hostId, volumeId string) {
+	LogHostEvent(hostId, EventHostVolumeAttached, HostEventData{VolumeID: volumeId})
+}
+
+func LogHostVolumeDetached(hostId, volumeId string) {
+	LogHostEvent(hostId, EventHostVolumeDetached, HostEventData{VolumeID: volumeId})
+}
+
+// LogHostProblemDetected records that the tasks system failed on the host,
+// or on its containers, often enough for it to be taken out of service.
+func LogHostProblemDetected(hostId string, failedTasks []string, logs string) {
+	LogHostEvent(hostId, EventHostProblemDetected, HostEventData{FailedTasks: failedTasks, Logs: logs})
+}
+
+// LogHostDrainSet records that an admin started or stopped draining a host.
+func LogHostDrainSet(hostId string, draining bool, user string) {
+	eventType := EventHostDrainStopped
+	if draining {
+		eventType = EventHostDrainStarted
+	}
+	LogHostEvent(hostId, eventType, HostEventData{User: user})
+}
+
 func LogMonitorOperation(hostId string, op string) {
 	LogHostEvent(hostId, EventHostMonitorFlag, HostEventData{MonitorOp: op})
 }
@@ -163,6 +219,27 @@ func LogExpirationWarningSent(hostID string) {
 	LogHostEvent(hostID, EventHostExpirationWarningSent, HostEventData{})
 }
 
+func LogHostArtifactsCollected(hostId string, artifacts []string) {
+	LogHostEvent(hostId, EventHostArtifactsCollected, HostEventData{Artifacts: artifacts})
+}
+
+// LogHostCapacityFallback records the decision whether to fall back to
+// on-demand capacity for a host whose interruptible capacity failed or was
+// reclaimed, and the host replacing it, if any.
+func LogHostCapacityFallback(hostId, reason, replacement string, fallback bool) {
+	LogHostEvent(hostId, EventHostCapacityFallback, HostEventData{
+		Reason:      reason,
+		Replacement: replacement,
+		Successful:  fallback,
+	})
+}
+
+// LogHostRegionFailover records that a region of the host's distro was out
+// of capacity for the host, so it is spawned in the next region instead.
+func LogHostRegionFailover(hostId, region, reason string) {
+	LogHostEvent(hostId, EventHostRegionFailover, HostEventData{Region: region, Reason: reason})
+}
+
 // UpdateExecutions updates host events to track multiple executions of the same task
 func UpdateExecutions(hostId, taskId string, execution int) error {
 	taskIdKey := bsonutil.MustHaveTag(HostEventData{}, "TaskId")
@@ -0,0 +1,59 @@
+package event
+
+import (
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func init() {
+	registry.AddType(ResourceTypeProject, projectEventDataFactory)
+}
+
+const (
+	// resource type
+	ResourceTypeProject = "PROJECT"
+
+	// event types
+	EventTypeProjectAdded    = "PROJECT_ADDED"
+	EventTypeProjectModified = "PROJECT_MODIFIED"
+)
+
+// ProjectChangeEventData is a user's change to the settings of a project,
+// as read from the event log. The settings before and after the change are
+// defined by the model package, which decodes them.
+type ProjectChangeEventData struct {
+	User   string   `bson:"user" json:"user"`
+	Before bson.Raw `bson:"before" json:"-"`
+	After  bson.Raw `bson:"after" json:"-"`
+}
+
+type projectChangeEventData struct {
+	User   string      `bson:"user"`
+	Before interface{} `bson:"before"`
+	After  interface{} `bson:"after"`
+}
+
+// LogProjectEvent records a user's change to the settings of a project.
+func LogProjectEvent(projectId, eventType, user string, before, after interface{}) error {
+	event := EventLogEntry{
+		ResourceId:   projectId,
+		Timestamp:    time.Now(),
+		EventType:    eventType,
+		Data:         projectChangeEventData{User: user, Before: before, After: after},
+		ResourceType: ResourceTypeProject,
+	}
+
+	if err := NewDBEventLogger(AllLogCollection).LogEvent(&event); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"resource_type": ResourceTypeProject,
+			"message":       "error logging event",
+			"source":        "event-log-fail",
+		}))
+		return errors.Wrap(err, "error logging project event")
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package event
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/evergreen-ci/evergreen/util"
@@ -17,6 +19,8 @@ const (
 	EvergreenWebhookSubscriberType  = "evergreen-webhook"
 	EmailSubscriberType             = "email"
 	SlackSubscriberType             = "slack"
+	OpsgenieSubscriberType          = "opsgenie"
+	GithubCheckSubscriberType       = "github-check"
 )
 
 var SubscriberTypes = []string{
@@ -26,6 +30,8 @@ var SubscriberTypes = []string{
 	EvergreenWebhookSubscriberType,
 	EmailSubscriberType,
 	SlackSubscriberType,
+	OpsgenieSubscriberType,
+	GithubCheckSubscriberType,
 }
 
 //nolint: deadcode, megacheck, unused
@@ -65,6 +71,12 @@ func (s *Subscriber) SetBSON(raw bson.Raw) error {
 	case JIRAIssueSubscriberType:
 		s.Target = &JIRAIssueSubscriber{}
 
+	case OpsgenieSubscriberType:
+		s.Target = &OpsgenieSubscriber{}
+
+	case GithubCheckSubscriberType:
+		s.Target = &GithubCheckSubscriber{}
+
 	case JIRACommentSubscriberType, EmailSubscriberType, SlackSubscriberType:
 		str := ""
 		s.Target = &str
@@ -99,6 +111,16 @@ func (s *Subscriber) String() string {
 	case *JIRAIssueSubscriber:
 		subscriberStr = v.String()
 
+	case OpsgenieSubscriber:
+		subscriberStr = v.String()
+	case *OpsgenieSubscriber:
+		subscriberStr = v.String()
+
+	case GithubCheckSubscriber:
+		subscriberStr = v.String()
+	case *GithubCheckSubscriber:
+		subscriberStr = v.String()
+
 	case string:
 		subscriberStr = v
 	case *string:
@@ -140,6 +162,23 @@ func (s *JIRAIssueSubscriber) String() string {
 	return fmt.Sprintf("%s-%s", s.Project, s.IssueType)
 }
 
+// OpsgenieSubscriber creates alerts with the API key of an Opsgenie
+// integration, optionally assigning them to a team.
+type OpsgenieSubscriber struct {
+	APIKey string `bson:"api_key"`
+	Team   string `bson:"team,omitempty"`
+}
+
+// String identifies the subscriber by a fingerprint of its API key, which
+// is a secret.
+func (s *OpsgenieSubscriber) String() string {
+	if len(s.APIKey) == 0 {
+		return "NIL_API_KEY"
+	}
+	hash := sha256.Sum256([]byte(s.APIKey))
+	return hex.EncodeToString(hash[:])[:12]
+}
+
 type GithubPullRequestSubscriber struct {
 	Owner    string `bson:"owner"`
 	Repo     string `bson:"repo"`
@@ -151,6 +190,25 @@ func (s *GithubPullRequestSubscriber) String() string {
 	return fmt.Sprintf("%s-%s-%d-%s", s.Owner, s.Repo, s.PRNumber, s.Ref)
 }
 
+// GithubCheckSubscriber reports check runs on a commit of a repository with
+// the GitHub checks API.
+type GithubCheckSubscriber struct {
+	Owner string `bson:"owner"`
+	Repo  string `bson:"repo"`
+	Ref   string `bson:"ref"`
+}
+
+func (s *GithubCheckSubscriber) String() string {
+	return fmt.Sprintf("%s-%s-%s", s.Owner, s.Repo, s.Ref)
+}
+
+func NewGithubCheckSubscriber(s GithubCheckSubscriber) Subscriber {
+	return Subscriber{
+		Type:   GithubCheckSubscriberType,
+		Target: s,
+	}
+}
+
 func NewGithubStatusAPISubscriber(s GithubPullRequestSubscriber) Subscriber {
 	return Subscriber{
 		Type:   GithubPullRequestSubscriberType,
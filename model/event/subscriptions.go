@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
@@ -31,6 +32,9 @@ var (
 	subscriptionOwnerKey          = bsonutil.MustHaveTag(Subscription{}, "Owner")
 	subscriptionOwnerTypeKey      = bsonutil.MustHaveTag(Subscription{}, "OwnerType")
 	subscriptionTriggerDataKey    = bsonutil.MustHaveTag(Subscription{}, "TriggerData")
+	subscriptionDigestKey         = bsonutil.MustHaveTag(Subscription{}, "Digest")
+	subscriptionEscalationKey     = bsonutil.MustHaveTag(Subscription{}, "Escalation")
+	subscriptionMutedUntilKey     = bsonutil.MustHaveTag(Subscription{}, "MutedUntil")
 )
 
 type OwnerType string
@@ -40,18 +44,34 @@ const (
 	OwnerTypeProject                        OwnerType = "project"
 	TaskDurationKey                                   = "task-duration-secs"
 	TaskPercentChangeKey                              = "task-percent-change"
+	TaskBaselineVersionsKey                           = "task-baseline-versions"
+	TaskStreakLengthKey                               = "task-streak-length"
 	BuildDurationKey                                  = "build-duration-secs"
 	BuildPercentChangeKey                             = "build-percent-change"
 	VersionDurationKey                                = "version-duration-secs"
 	VersionPercentChangeKey                           = "version-percent-change"
 	TestRegexKey                                      = "test-regex"
 	RenotifyIntervalKey                               = "renotify-interval"
+	IgnoreFlakyTestsKey                               = "ignore-flaky-tests"
 	ImplicitSubscriptionPatchOutcome                  = "patch-outcome"
 	ImplicitSubscriptionBuildBreak                    = "build-break"
 	ImplicitSubscriptionSpawnhostExpiration           = "spawnhost-expiration"
 	ImplicitSubscriptionSpawnHostOutcome              = "spawnhost-outcome"
 )
 
+// Digest windows that subscriptions can batch their notifications into
+const (
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// DigestWindows are the windows that subscriptions can batch their
+// notifications into.
+var DigestWindows = []string{DigestHourly, DigestDaily}
+
+// digestSubscriberTypes are the subscriber types that can receive digests
+var digestSubscriberTypes = []string{EmailSubscriberType, SlackSubscriberType}
+
 type Subscription struct {
 	ID             string            `bson:"_id"`
 	ResourceType   string            `bson:"type"`
@@ -62,6 +82,48 @@ type Subscription struct {
 	OwnerType      OwnerType         `bson:"owner_type"`
 	Owner          string            `bson:"owner"`
 	TriggerData    map[string]string `bson:"trigger_data,omitempty"`
+	// Digest is the window that the subscription's notifications are
+	// batched into, or empty if they are sent immediately
+	Digest string `bson:"digest,omitempty"`
+	// Escalation sends the subscription's notifications on to further
+	// subscribers when they aren't acknowledged; only build break
+	// subscriptions escalate
+	Escalation *Escalation `bson:"escalation,omitempty"`
+	// MutedUntil suppresses the subscription's notifications until the
+	// given time
+	MutedUntil time.Time `bson:"muted_until,omitempty"`
+}
+
+// Escalation notifies each tier of subscribers in turn, waiting Delay
+// minutes between tiers, until one of the notifications is acknowledged.
+type Escalation struct {
+	Delay int              `bson:"delay_mins"`
+	Tiers []EscalationTier `bson:"tiers"`
+}
+
+type EscalationTier struct {
+	Name        string       `bson:"name"`
+	Subscribers []Subscriber `bson:"subscribers"`
+}
+
+func (e *Escalation) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if e.Delay <= 0 {
+		catcher.Add(errors.New("escalation delay must be positive"))
+	}
+	if len(e.Tiers) == 0 {
+		catcher.Add(errors.New("escalation must have at least 1 tier"))
+	}
+	for i, tier := range e.Tiers {
+		if len(tier.Subscribers) == 0 {
+			catcher.Add(errors.Errorf("escalation tier %d has no subscribers", i+1))
+		}
+		for _, subscriber := range tier.Subscribers {
+			catcher.Add(subscriber.Validate())
+		}
+	}
+
+	return catcher.Resolve()
 }
 
 type unmarshalSubscription struct {
@@ -74,6 +136,9 @@ type unmarshalSubscription struct {
 	OwnerType      OwnerType         `bson:"owner_type"`
 	Owner          string            `bson:"owner"`
 	TriggerData    map[string]string `bson:"trigger_data,omitempty"`
+	Digest         string            `bson:"digest,omitempty"`
+	Escalation     *Escalation       `bson:"escalation,omitempty"`
+	MutedUntil     time.Time         `bson:"muted_until,omitempty"`
 }
 
 func (s *Subscription) SetBSON(raw bson.Raw) error {
@@ -92,6 +157,9 @@ func (s *Subscription) SetBSON(raw bson.Raw) error {
 	s.Owner = temp.Owner
 	s.OwnerType = temp.OwnerType
 	s.TriggerData = temp.TriggerData
+	s.Digest = temp.Digest
+	s.Escalation = temp.Escalation
+	s.MutedUntil = temp.MutedUntil
 
 	return nil
 }
@@ -134,10 +202,14 @@ func FindSubscriptions(resourceType string, selectors []Selector) ([]Subscriptio
 	}
 
 	out := []Subscription{}
+	now := time.Now()
 	for i := range rawSubs {
 		if len(rawSubs[i].RegexSelectors) > 0 && !regexSelectorsMatch(selectors, rawSubs[i].RegexSelectors) {
 			continue
 		}
+		if rawSubs[i].MutedUntil.After(now) {
+			continue
+		}
 
 		out = append(out, rawSubs[i])
 	}
@@ -189,6 +261,9 @@ func (s *Subscription) Upsert() error {
 		subscriptionOwnerKey:          s.Owner,
 		subscriptionOwnerTypeKey:      s.OwnerType,
 		subscriptionTriggerDataKey:    s.TriggerData,
+		subscriptionDigestKey:         s.Digest,
+		subscriptionEscalationKey:     s.Escalation,
+		subscriptionMutedUntilKey:     s.MutedUntil,
 	}
 
 	// note: this prevents changing the owner of an existing subscription, which is desired
@@ -260,6 +335,20 @@ func (s *Subscription) Validate() error {
 	if !IsValidOwnerType(string(s.OwnerType)) {
 		catcher.Add(errors.Errorf("%s is not a valid owner type", s.OwnerType))
 	}
+	if s.Digest != "" {
+		if !util.StringSliceContains(DigestWindows, s.Digest) {
+			catcher.Add(errors.Errorf("digest '%s' is not one of %v", s.Digest, DigestWindows))
+		}
+		if !util.StringSliceContains(digestSubscriberTypes, s.Subscriber.Type) {
+			catcher.Add(errors.Errorf("%s subscribers cannot receive digests", s.Subscriber.Type))
+		}
+	}
+	if s.Escalation != nil {
+		if s.Trigger != ImplicitSubscriptionBuildBreak {
+			catcher.Add(errors.Errorf("%s subscriptions cannot escalate", s.Trigger))
+		}
+		catcher.Add(s.Escalation.Validate())
+	}
 	catcher.Add(s.runCustomValidation())
 	catcher.Add(s.Subscriber.Validate())
 	return catcher.Resolve()
@@ -274,6 +363,12 @@ func (s *Subscription) runCustomValidation() error {
 	if taskPercentVal, ok := s.TriggerData[TaskPercentChangeKey]; ok {
 		catcher.Add(validatePositiveFloat(taskPercentVal))
 	}
+	if baselineVersionsVal, ok := s.TriggerData[TaskBaselineVersionsKey]; ok {
+		catcher.Add(validatePositiveInt(baselineVersionsVal))
+	}
+	if streakLengthVal, ok := s.TriggerData[TaskStreakLengthKey]; ok {
+		catcher.Add(validatePositiveInt(streakLengthVal))
+	}
 	if versionDurationVal, ok := s.TriggerData[VersionDurationKey]; ok {
 		catcher.Add(validatePositiveInt(versionDurationVal))
 	}
@@ -292,6 +387,9 @@ func (s *Subscription) runCustomValidation() error {
 	if renotifyInterval, ok := s.TriggerData[RenotifyIntervalKey]; ok {
 		catcher.Add(validatePositiveInt(renotifyInterval))
 	}
+	if ignoreFlakyTests, ok := s.TriggerData[IgnoreFlakyTestsKey]; ok {
+		catcher.Add(validateBool(ignoreFlakyTests))
+	}
 	return catcher.Resolve()
 }
 
@@ -317,6 +415,13 @@ func validatePositiveFloat(s string) error {
 	return nil
 }
 
+func validateBool(s string) error {
+	if _, err := strconv.ParseBool(s); err != nil {
+		return fmt.Errorf("%s must be true or false", s)
+	}
+	return nil
+}
+
 func validateRegex(s string) error {
 	regex, err := regexp.Compile(s)
 	if regex == nil || err != nil {
@@ -356,6 +461,28 @@ func (s *Subscription) String() string {
 	return out
 }
 
+// FindSubscriptionsByIDs returns the subscriptions with the given IDs.
+func FindSubscriptionsByIDs(ids []string) ([]Subscription, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	in := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		in = append(in, id)
+		if bson.IsObjectIdHex(id) {
+			in = append(in, bson.ObjectIdHex(id))
+		}
+	}
+	query := db.Query(bson.M{
+		subscriptionIDKey: bson.M{
+			"$in": in,
+		},
+	})
+	subscriptions := []Subscription{}
+	err := db.FindAllQ(SubscriptionsCollection, query, &subscriptions)
+	return subscriptions, errors.Wrap(err, "error retrieving subscriptions")
+}
+
 func FindSubscriptionsByOwner(owner string, ownerType OwnerType) ([]Subscription, error) {
 	if len(owner) == 0 {
 		return nil, nil
@@ -293,3 +293,52 @@ func (s *subscriptionsSuite) TestFindSubscriptionsByOwner() {
 	s.NoError(err)
 	s.Nil(sub)
 }
+
+func (s *subscriptionsSuite) TestValidateDigest() {
+	sub := s.subscriptions[0]
+	s.NoError(sub.Validate())
+
+	sub.Digest = DigestDaily
+	s.NoError(sub.Validate())
+
+	sub.Digest = "weekly"
+	s.Error(sub.Validate())
+
+	sub.Digest = DigestHourly
+	sub.Subscriber = Subscriber{
+		Type:   EvergreenWebhookSubscriberType,
+		Target: &WebhookSubscriber{URL: "https://example.com", Secret: []byte("secret")},
+	}
+	s.Error(sub.Validate())
+}
+
+func (s *subscriptionsSuite) TestValidateEscalation() {
+	sub := NewBuildBreakSubscriptionByOwner("me", Subscriber{
+		Type:   EmailSubscriberType,
+		Target: "me@example.com",
+	})
+	sub.OwnerType = OwnerTypePerson
+	s.NoError(sub.Validate())
+
+	sub.Escalation = &Escalation{
+		Delay: 30,
+		Tiers: []EscalationTier{
+			{
+				Name:        "team",
+				Subscribers: []Subscriber{{Type: SlackSubscriberType, Target: "#team"}},
+			},
+		},
+	}
+	s.NoError(sub.Validate())
+
+	sub.Escalation.Delay = 0
+	s.Error(sub.Validate())
+
+	sub.Escalation.Delay = 30
+	sub.Escalation.Tiers = append(sub.Escalation.Tiers, EscalationTier{Name: "admins"})
+	s.Error(sub.Validate())
+
+	sub.Escalation.Tiers = sub.Escalation.Tiers[:1]
+	sub.Trigger = "outcome"
+	s.Error(sub.Validate())
+}
@@ -10,6 +10,7 @@ import (
 func init() {
 	registry.AddType(ResourceTypeVersion, versionEventDataFactory)
 	registry.AllowSubscription(ResourceTypeVersion, VersionStateChange)
+	registry.AllowSubscription(ResourceTypeVersion, VersionConfigWarnings)
 }
 
 func versionEventDataFactory() interface{} {
@@ -19,21 +20,36 @@ func versionEventDataFactory() interface{} {
 const (
 	ResourceTypeVersion = "VERSION"
 	VersionStateChange  = "STATE_CHANGE"
+
+	VersionConfigWarnings = "CONFIG_WARNINGS"
 )
 
 type VersionEventData struct {
-	Status string `bson:"status,omitempty" json:"status,omitempty"`
+	Status   string   `bson:"status,omitempty" json:"status,omitempty"`
+	Warnings []string `bson:"warnings,omitempty" json:"warnings,omitempty"`
 }
 
 func LogVersionStateChangeEvent(id, newStatus string) {
+	logVersionEvent(id, VersionStateChange, &VersionEventData{
+		Status: newStatus,
+	})
+}
+
+// LogVersionConfigWarningsEvent logs the project configuration warnings
+// that should be reported to subscribers of the version.
+func LogVersionConfigWarningsEvent(id string, warnings []string) {
+	logVersionEvent(id, VersionConfigWarnings, &VersionEventData{
+		Warnings: warnings,
+	})
+}
+
+func logVersionEvent(id, eventType string, data *VersionEventData) {
 	event := EventLogEntry{
 		Timestamp:    time.Now().Truncate(0).Round(time.Millisecond),
 		ResourceId:   id,
 		ResourceType: ResourceTypeVersion,
-		EventType:    VersionStateChange,
-		Data: &VersionEventData{
-			Status: newStatus,
-		},
+		EventType:    eventType,
+		Data:         data,
 	}
 
 	logger := NewDBEventLogger(AllLogCollection)
@@ -133,18 +133,30 @@ func (h *Host) IncIdleTime(dur time.Duration) error {
 	return nil
 }
 
-func (h *Host) IncCost(amt float64) error {
+// IncCost records the cost of running the host for the duration, along
+// with the hourly price that the cost implies.
+func (h *Host) IncCost(amt float64, dur time.Duration) error {
 	if amt < 0 {
 		return errors.Errorf("cost must be a positive value [%g]", amt)
 	}
+	if dur < 0 {
+		return errors.Errorf("cannot increment by a negative duration value [%s]", dur)
+	}
 
 	query := bson.M{IdKey: h.Id}
 
+	update := bson.M{
+		"$inc": bson.M{
+			TotalCostKey:       amt,
+			TotalCostedTimeKey: dur,
+		},
+	}
+	if amt > 0 && dur > 0 {
+		update["$set"] = bson.M{HourlyPriceKey: amt / dur.Hours()}
+	}
 	change := mgo.Change{
 		ReturnNew: true,
-		Update: bson.M{
-			"$inc": bson.M{TotalCostKey: amt},
-		},
+		Update:    update,
 	}
 
 	info, err := db.FindAndModify(Collection, query, []string{}, change, h)
@@ -66,13 +66,22 @@ var (
 	StartTimeKey                 = bsonutil.MustHaveTag(Host{}, "StartTime")
 	TotalCostKey                 = bsonutil.MustHaveTag(Host{}, "TotalCost")
 	TotalIdleTimeKey             = bsonutil.MustHaveTag(Host{}, "TotalIdleTime")
+	TotalCostedTimeKey           = bsonutil.MustHaveTag(Host{}, "TotalCostedTime")
+	HourlyPriceKey               = bsonutil.MustHaveTag(Host{}, "HourlyPrice")
 	HasContainersKey             = bsonutil.MustHaveTag(Host{}, "HasContainers")
+	DrainingKey                  = bsonutil.MustHaveTag(Host{}, "Draining")
 	ParentIDKey                  = bsonutil.MustHaveTag(Host{}, "ParentID")
+	ImageDigestKey               = bsonutil.MustHaveTag(Host{}, "ImageDigest")
 	ContainerImagesKey           = bsonutil.MustHaveTag(Host{}, "ContainerImages")
 	ContainerBuildAttempt        = bsonutil.MustHaveTag(Host{}, "ContainerBuildAttempt")
 	LastContainerFinishTimeKey   = bsonutil.MustHaveTag(Host{}, "LastContainerFinishTime")
 	SpawnOptionsKey              = bsonutil.MustHaveTag(Host{}, "SpawnOptions")
 	ContainerPoolSettingsKey     = bsonutil.MustHaveTag(Host{}, "ContainerPoolSettings")
+	CapacityFallbackKey          = bsonutil.MustHaveTag(Host{}, "CapacityFallback")
+	RegionKey                    = bsonutil.MustHaveTag(Host{}, "Region")
+	VolumesKey                   = bsonutil.MustHaveTag(Host{}, "Volumes")
+	VolumeAttachmentVolumeIDKey  = bsonutil.MustHaveTag(VolumeAttachment{}, "VolumeID")
+	ProvisionOptionsOwnerIdKey   = bsonutil.MustHaveTag(ProvisionOptions{}, "OwnerId")
 	SpawnOptionsTaskIDKey        = bsonutil.MustHaveTag(SpawnOptions{}, "TaskID")
 	SpawnOptionsBuildIDKey       = bsonutil.MustHaveTag(SpawnOptions{}, "BuildID")
 	SpawnOptionsTimeoutKey       = bsonutil.MustHaveTag(SpawnOptions{}, "TimeoutTeardown")
@@ -415,6 +424,15 @@ func ByNotMonitoredSince(threshold time.Time) db.Q {
 	})
 }
 
+// ByVolumeID produces a query that returns the hosts that are not terminated
+// and have the volume attached.
+func ByVolumeID(volumeID string) db.Q {
+	return db.Query(bson.M{
+		bsonutil.GetDottedKeyName(VolumesKey, VolumeAttachmentVolumeIDKey): volumeID,
+		StatusKey: bson.M{"$ne": evergreen.HostTerminated},
+	})
+}
+
 // ByExpiringBetween produces a query that returns  any user-spawned hosts
 // that will expire between the specified times.
 func ByExpiringBetween(lowerBound time.Time, upperBound time.Time) db.Q {
@@ -589,6 +607,44 @@ func inactiveHostCountPipeline() []bson.M {
 	}
 }
 
+// HostStatusCounts is the number of hosts from a provider with a status.
+type HostStatusCounts struct {
+	Status   string `bson:"status"`
+	Provider string `bson:"provider"`
+	Count    int    `bson:"count"`
+}
+
+// hostStatusCountPipeline counts the hosts that haven't been terminated by
+// status and provider.
+func hostStatusCountPipeline() []bson.M {
+	return []bson.M{
+		{
+			"$match": bson.M{
+				StatusKey: bson.M{"$ne": evergreen.HostTerminated},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id": bson.M{
+					"status":   "$" + StatusKey,
+					"provider": "$" + ProviderKey,
+				},
+				"count": bson.M{
+					"$sum": 1,
+				},
+			},
+		},
+		{
+			"$project": bson.M{
+				"_id":      0,
+				"status":   "$_id.status",
+				"provider": "$_id.provider",
+				"count":    1,
+			},
+		},
+	}
+}
+
 // FinishTime is a struct for storing pairs of host IDs and last container finish times
 type FinishTime struct {
 	Id         string    `bson:"_id"`
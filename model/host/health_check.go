@@ -0,0 +1,72 @@
+package host
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/mongodb/anser/bsonutil"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// HealthCheck is the result of the most recent check that a static host is
+// fit to run tasks.
+type HealthCheck struct {
+	CheckedAt time.Time `bson:"checked_at" json:"checked_at"`
+	// DiskFreeMB is the free space on the disk of the distro's working
+	// directory.
+	DiskFreeMB int `bson:"disk_free_mb" json:"disk_free_mb"`
+	// ClockDrift is how far ahead of the app server's clock the host's
+	// clock is.
+	ClockDrift time.Duration `bson:"clock_drift" json:"clock_drift"`
+	// AgentVersion is the version of the evergreen binary on the host.
+	AgentVersion string `bson:"agent_version" json:"agent_version"`
+	// Failures describe each check the host failed, and is empty if the
+	// host is healthy.
+	Failures []string `bson:"failures,omitempty" json:"failures,omitempty"`
+	// ConsecutiveFailures is the number of checks in a row the host failed.
+	ConsecutiveFailures int `bson:"consecutive_failures" json:"consecutive_failures"`
+}
+
+var HealthCheckKey = bsonutil.MustHaveTag(Host{}, "HealthCheck")
+
+// Healthy returns whether the host passed every check.
+func (c *HealthCheck) Healthy() bool {
+	return len(c.Failures) == 0
+}
+
+// StaticHostsByStatus produces a query that returns the static hosts with any
+// of the statuses. If distroId is not empty, only the hosts of that distro
+// are returned.
+func StaticHostsByStatus(distroId string, statuses []string) db.Q {
+	q := bson.M{
+		ProviderKey: evergreen.HostTypeStatic,
+		StatusKey:   bson.M{"$in": statuses},
+	}
+	if distroId != "" {
+		q[bsonutil.GetDottedKeyName(DistroKey, distro.IdKey)] = distroId
+	}
+	return db.Query(q).Sort([]string{IdKey})
+}
+
+// SetHealthCheck records the result of a health check of the host. The
+// check's consecutive failures are counted from the host's previous check.
+func (h *Host) SetHealthCheck(check HealthCheck) error {
+	check.ConsecutiveFailures = 0
+	if !check.Healthy() {
+		check.ConsecutiveFailures = 1
+		if h.HealthCheck != nil {
+			check.ConsecutiveFailures += h.HealthCheck.ConsecutiveFailures
+		}
+	}
+	if check.CheckedAt.IsZero() {
+		check.CheckedAt = time.Now()
+	}
+
+	if err := UpdateOne(bson.M{IdKey: h.Id}, bson.M{"$set": bson.M{HealthCheckKey: check}}); err != nil {
+		return err
+	}
+	h.HealthCheck = &check
+	return nil
+}
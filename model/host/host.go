@@ -79,6 +79,9 @@ type Host struct {
 	NeedsNewAgent      bool   `bson:"needs_agent" json:"needs_agent"`
 	AgentDeployAttempt int    `bson:"agent_deploy_attempt" json:"agent_deploy_attempt"`
 
+	// Draining hosts finish their running task but are assigned no new ones.
+	Draining bool `bson:"draining,omitempty" json:"draining,omitempty"`
+
 	// for ec2 dynamic hosts, the instance type requested
 	InstanceType string `bson:"instance_type" json:"instance_type,omitempty"`
 	// for ec2 dynamic hosts, the total size of the volumes requested, in GiB
@@ -92,6 +95,12 @@ type Host struct {
 	// where host providers report costs.
 	TotalCost float64 `bson:"total_cost,omitempty" json:"total_cost,omitempty"`
 
+	// TotalCostedTime is the runtime that TotalCost was estimated for, and
+	// HourlyPrice is the provider's price for an hour of the host as of its
+	// most recent cost estimate.
+	TotalCostedTime time.Duration `bson:"total_costed_time,omitempty" json:"total_costed_time,omitempty"`
+	HourlyPrice     float64       `bson:"hourly_price,omitempty" json:"hourly_price,omitempty"`
+
 	// accrues the value of idle time.
 	TotalIdleTime time.Duration `bson:"total_idle_time,omitempty" json:"total_idle_time,omitempty" yaml:"total_idle_time,omitempty"`
 
@@ -102,6 +111,8 @@ type Host struct {
 	ContainerImages map[string]bool `bson:"container_images,omitempty" json:"container_images,omitempty"`
 	// stores the ID of the host a container is on
 	ParentID string `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	// stores the digest of the image a container was created from
+	ImageDigest string `bson:"image_digest,omitempty" json:"image_digest,omitempty"`
 	// stores last expected finish time among all containers on the host
 	LastContainerFinishTime time.Time `bson:"last_container_finish_time,omitempty" json:"last_container_finish_time,omitempty"`
 	// ContainerPoolSettings
@@ -110,6 +121,29 @@ type Host struct {
 
 	// SpawnOptions holds data which the monitor uses to determine when to terminate hosts spawned by tasks.
 	SpawnOptions SpawnOptions `bson:"spawn_options,omitempty" json:"spawn_options,omitempty"`
+
+	// CapacityFallback is true if the host runs on on-demand capacity in
+	// place of the spot or preemptible capacity its distro requests.
+	CapacityFallback bool `bson:"capacity_fallback,omitempty" json:"capacity_fallback,omitempty"`
+
+	// Region is the region the host was spawned in, if its distro lists
+	// several. RegionFailover is true if it was spawned in a lower priority
+	// region because the higher priority ones were out of capacity.
+	Region         string `bson:"region,omitempty" json:"region,omitempty"`
+	RegionFailover bool   `bson:"region_failover,omitempty" json:"region_failover,omitempty"`
+
+	// HealthCheck is the result of the most recent health check of a static
+	// host.
+	HealthCheck *HealthCheck `bson:"health_check,omitempty" json:"health_check,omitempty"`
+
+	// Volumes are the persistent volumes a user has attached to a spawn host.
+	Volumes []VolumeAttachment `bson:"volumes,omitempty" json:"volumes,omitempty"`
+}
+
+// VolumeAttachment is a persistent volume attached to a host.
+type VolumeAttachment struct {
+	VolumeID   string `bson:"volume_id" json:"volume_id"`
+	DeviceName string `bson:"device_name" json:"device_name"`
 }
 
 type HostGroup []Host
@@ -486,6 +520,18 @@ func (h *Host) SetAgentRevision(agentRevision string) error {
 	return nil
 }
 
+// SetImageDigest records the digest of the image the container host was
+// created from.
+func (h *Host) SetImageDigest(digest string) error {
+	err := UpdateOne(bson.M{IdKey: h.Id},
+		bson.M{"$set": bson.M{ImageDigestKey: digest}})
+	if err != nil {
+		return err
+	}
+	h.ImageDigest = digest
+	return nil
+}
+
 // IsWaitingForAgent provides a local predicate for the logic in the
 // "NeedsNewAgent" query.
 func (h *Host) IsWaitingForAgent() bool {
@@ -535,6 +581,120 @@ func (h *Host) SetExpirationTime(expirationTime time.Time) error {
 	)
 }
 
+// UpdateDNSName replaces the DNS name of a host, e.g. once a stopped host is
+// started again.
+func (h *Host) UpdateDNSName(dnsName string) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey: h.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				DNSKey: dnsName,
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	h.Host = dnsName
+	event.LogHostDNSNameSet(h.Id, dnsName)
+	return nil
+}
+
+// SetDraining starts or stops draining the host. A draining host is not
+// assigned new tasks, but finishes the task it is running.
+func (h *Host) SetDraining(draining bool, user string) error {
+	var update bson.M
+	if draining {
+		update = bson.M{"$set": bson.M{DrainingKey: true}}
+	} else {
+		update = bson.M{"$unset": bson.M{DrainingKey: 1}}
+	}
+	if err := UpdateOne(bson.M{IdKey: h.Id}, update); err != nil {
+		return err
+	}
+	event.LogHostDrainSet(h.Id, draining, user)
+	h.Draining = draining
+	return nil
+}
+
+// SetOwner transfers a spawn host to another user.
+func (h *Host) SetOwner(owner, user string) error {
+	set := bson.M{
+		StartedByKey: owner,
+	}
+	if h.ProvisionOptions != nil {
+		set[bsonutil.GetDottedKeyName(ProvisionOptionsKey, ProvisionOptionsOwnerIdKey)] = owner
+	}
+	if err := UpdateOne(bson.M{IdKey: h.Id}, bson.M{"$set": set}); err != nil {
+		return err
+	}
+	event.LogHostOwnerChanged(h.Id, h.StartedBy, owner, user)
+	h.StartedBy = owner
+	if h.ProvisionOptions != nil {
+		h.ProvisionOptions.OwnerId = owner
+	}
+	return nil
+}
+
+// AddVolume records that a volume is attached to the host.
+func (h *Host) AddVolume(attachment VolumeAttachment) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey: h.Id,
+		},
+		bson.M{
+			"$push": bson.M{
+				VolumesKey: attachment,
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	h.Volumes = append(h.Volumes, attachment)
+	event.LogHostVolumeAttached(h.Id, attachment.VolumeID)
+	return nil
+}
+
+// RemoveVolume records that a volume is no longer attached to the host.
+func (h *Host) RemoveVolume(volumeID string) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey: h.Id,
+		},
+		bson.M{
+			"$pull": bson.M{
+				VolumesKey: bson.M{VolumeAttachmentVolumeIDKey: volumeID},
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	volumes := []VolumeAttachment{}
+	for _, v := range h.Volumes {
+		if v.VolumeID != volumeID {
+			volumes = append(volumes, v)
+		}
+	}
+	h.Volumes = volumes
+	event.LogHostVolumeDetached(h.Id, volumeID)
+	return nil
+}
+
+// GetVolume returns the volume attached to the host, or nil if the volume is
+// not attached to it.
+func (h *Host) GetVolume(volumeID string) *VolumeAttachment {
+	for i := range h.Volumes {
+		if h.Volumes[i].VolumeID == volumeID {
+			return &h.Volumes[i]
+		}
+	}
+	return nil
+}
+
 // SetExpirationNotification updates the notification time for a spawn host
 func (h *Host) SetExpirationNotification(thresholdKey string) error {
 	// update the in-memory host, then the database
@@ -647,6 +807,18 @@ func (h *Host) GetElapsedCommunicationTime() time.Duration {
 	return time.Since(h.CreationTime)
 }
 
+// GetRuntime returns how long the host has been running, or how long it ran if
+// it has been terminated.
+func (h *Host) GetRuntime() time.Duration {
+	if h.StartTime.IsZero() {
+		return 0
+	}
+	if h.TerminationTime.After(h.StartTime) {
+		return h.TerminationTime.Sub(h.StartTime)
+	}
+	return time.Since(h.StartTime)
+}
+
 func DecommissionHostsWithDistroId(distroId string) error {
 	err := UpdateAll(
 		ByDistroIdDoc(distroId),
@@ -788,6 +960,17 @@ func CountInactiveHostsByProvider() ([]InactiveHostCounts, error) {
 	return counts, nil
 }
 
+// CountHostsByStatus counts the hosts that haven't been terminated by status
+// and provider.
+func CountHostsByStatus() ([]HostStatusCounts, error) {
+	counts := []HostStatusCounts{}
+	err := db.Aggregate(Collection, hostStatusCountPipeline(), &counts)
+	if err != nil {
+		return nil, errors.Wrap(err, "error aggregating hosts by status")
+	}
+	return counts, nil
+}
+
 // FindAllRunningContainers finds all the containers that are currently running
 func FindAllRunningContainers() ([]Host, error) {
 	query := db.Query(bson.M{
@@ -902,6 +1085,23 @@ func (h *Host) IsIdleParent() (bool, error) {
 	return num == 0, nil
 }
 
+// HasActiveContainers returns whether any of a parent's containers have not
+// been terminated.
+func (h *Host) HasActiveContainers() (bool, error) {
+	if !h.HasContainers {
+		return false, nil
+	}
+	num, err := Count(db.Query(bson.M{
+		ParentIDKey: h.Id,
+		StatusKey:   bson.M{"$ne": evergreen.HostTerminated},
+	}))
+	if err != nil {
+		return false, errors.Wrap(err, "Error counting non-terminated containers")
+	}
+
+	return num > 0, nil
+}
+
 // UpdateLastContainerFinishTime updates latest finish time for a host with containers
 func (h *Host) UpdateLastContainerFinishTime(t time.Time) error {
 	selector := bson.M{
@@ -1071,6 +1271,60 @@ func CountUphostParentsByContainerPool(poolId string) (int, error) {
 	})
 }
 
+// FindAllRunningParentsByContainerPoolDistro returns a slice of hosts of the
+// given distro that are parents of the container pool specified by the given ID
+func FindAllRunningParentsByContainerPoolDistro(poolId, distroId string) ([]Host, error) {
+	hostContainerPoolId := bsonutil.GetDottedKeyName(ContainerPoolSettingsKey, evergreen.ContainerPoolIdKey)
+	query := db.Query(bson.M{
+		HasContainersKey:    true,
+		StatusKey:           evergreen.HostRunning,
+		hostContainerPoolId: poolId,
+		bsonutil.GetDottedKeyName(DistroKey, distro.IdKey): distroId,
+	}).Sort([]string{LastContainerFinishTimeKey})
+	return Find(query)
+}
+
+// CountUphostParentsByContainerPoolDistro returns the number of initializing
+// parent host intent documents of the given distro in the container pool
+func CountUphostParentsByContainerPoolDistro(poolId, distroId string) (int, error) {
+	hostContainerPoolId := bsonutil.GetDottedKeyName(ContainerPoolSettingsKey, evergreen.ContainerPoolIdKey)
+	return db.Count(Collection, bson.M{
+		HasContainersKey:    true,
+		StatusKey:           bson.M{"$in": evergreen.UphostStatus},
+		hostContainerPoolId: poolId,
+		bsonutil.GetDottedKeyName(DistroKey, distro.IdKey): distroId,
+	})
+}
+
+// CountCapacityFallbackHostsByDistro returns the number of up hosts of the
+// distro that run on on-demand capacity in place of interruptible capacity.
+func CountCapacityFallbackHostsByDistro(distroId string) (int, error) {
+	return db.Count(Collection, bson.M{
+		CapacityFallbackKey: true,
+		StatusKey:           bson.M{"$in": evergreen.UphostStatus},
+		bsonutil.GetDottedKeyName(DistroKey, distro.IdKey): distroId,
+	})
+}
+
+// CountUpHostsByDistroInRegions returns the number of up hosts of the
+// distro that were spawned in any of the regions. Hosts spawned before their
+// distro listed regions have no region, and are counted in the region "".
+func CountUpHostsByDistroInRegions(distroId string, regions []string) (int, error) {
+	in := []interface{}{}
+	for _, r := range regions {
+		if r == "" {
+			// the region is omitted when it is empty
+			in = append(in, nil)
+		}
+		in = append(in, r)
+	}
+	return db.Count(Collection, bson.M{
+		RegionKey: bson.M{"$in": in},
+		StatusKey: bson.M{"$in": evergreen.UphostStatus},
+		bsonutil.GetDottedKeyName(DistroKey, distro.IdKey): distroId,
+	})
+}
+
 func InsertMany(hosts []Host) error {
 	docs := make([]interface{}, len(hosts))
 	for idx := range hosts {
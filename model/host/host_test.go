@@ -1576,6 +1576,32 @@ func TestIsIdleParent(t *testing.T) {
 
 }
 
+func TestHasActiveContainers(t *testing.T) {
+	assert := assert.New(t)
+	assert.NoError(db.ClearCollections(Collection))
+
+	parent1 := &Host{Id: "parent1", Status: evergreen.HostRunning, HasContainers: true}
+	parent2 := &Host{Id: "parent2", Status: evergreen.HostRunning, HasContainers: true}
+	container1 := &Host{Id: "container1", Status: evergreen.HostTerminated, ParentID: "parent1"}
+	container2 := &Host{Id: "container2", Status: evergreen.HostRunning, ParentID: "parent2"}
+	assert.NoError(parent1.Insert())
+	assert.NoError(parent2.Insert())
+	assert.NoError(container1.Insert())
+	assert.NoError(container2.Insert())
+
+	active, err := parent1.HasActiveContainers()
+	assert.NoError(err)
+	assert.False(active)
+
+	active, err = parent2.HasActiveContainers()
+	assert.NoError(err)
+	assert.True(active)
+
+	active, err = container2.HasActiveContainers()
+	assert.NoError(err)
+	assert.False(active)
+}
+
 func TestFindParentOfContainer(t *testing.T) {
 	assert := assert.New(t)
 	assert.NoError(db.ClearCollections(Collection))
@@ -2565,3 +2591,106 @@ func TestRemoveStaleInitializing(t *testing.T) {
 	assert.Equal(4, numHosts)
 
 }
+
+func TestSetHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+
+	h := &Host{Id: "h1", Status: evergreen.HostRunning, Provider: evergreen.ProviderNameStatic}
+	require.NoError(h.Insert())
+
+	assert.NoError(h.SetHealthCheck(HealthCheck{Failures: []string{"disk is full"}}))
+	assert.NoError(h.SetHealthCheck(HealthCheck{Failures: []string{"disk is full"}}))
+	dbHost, err := FindOneId(h.Id)
+	require.NoError(err)
+	require.NotNil(dbHost.HealthCheck)
+	assert.False(dbHost.HealthCheck.Healthy())
+	assert.Equal(2, dbHost.HealthCheck.ConsecutiveFailures)
+	assert.False(dbHost.HealthCheck.CheckedAt.IsZero())
+
+	assert.NoError(h.SetHealthCheck(HealthCheck{DiskFreeMB: 2048}))
+	dbHost, err = FindOneId(h.Id)
+	require.NoError(err)
+	assert.True(dbHost.HealthCheck.Healthy())
+	assert.Equal(0, dbHost.HealthCheck.ConsecutiveFailures)
+	assert.Equal(2048, dbHost.HealthCheck.DiskFreeMB)
+}
+
+func TestStaticHostsByStatus(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+
+	hosts := []Host{
+		{Id: "h1", Distro: distro.Distro{Id: "d1"}, Status: evergreen.HostRunning, Provider: evergreen.ProviderNameStatic},
+		{Id: "h2", Distro: distro.Distro{Id: "d2"}, Status: evergreen.HostQuarantined, Provider: evergreen.ProviderNameStatic},
+		{Id: "h3", Distro: distro.Distro{Id: "d1"}, Status: evergreen.HostTerminated, Provider: evergreen.ProviderNameStatic},
+		{Id: "h4", Distro: distro.Distro{Id: "d1"}, Status: evergreen.HostRunning, Provider: evergreen.ProviderNameEc2Auto},
+	}
+	for i := range hosts {
+		require.NoError(hosts[i].Insert())
+	}
+
+	found, err := Find(StaticHostsByStatus("", []string{evergreen.HostRunning, evergreen.HostQuarantined}))
+	assert.NoError(err)
+	require.Len(found, 2)
+	assert.Equal("h1", found[0].Id)
+	assert.Equal("h2", found[1].Id)
+
+	found, err = Find(StaticHostsByStatus("d1", []string{evergreen.HostRunning, evergreen.HostQuarantined}))
+	assert.NoError(err)
+	require.Len(found, 1)
+	assert.Equal("h1", found[0].Id)
+}
+
+func TestCountHostsByStatus(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+
+	hosts := []Host{
+		{Id: "h1", Status: evergreen.HostRunning, Provider: evergreen.ProviderNameEc2Auto},
+		{Id: "h2", Status: evergreen.HostRunning, Provider: evergreen.ProviderNameEc2Auto},
+		{Id: "h3", Status: evergreen.HostRunning, Provider: evergreen.ProviderNameStatic},
+		{Id: "h4", Status: evergreen.HostQuarantined, Provider: evergreen.ProviderNameStatic},
+		{Id: "h5", Status: evergreen.HostTerminated, Provider: evergreen.ProviderNameEc2Auto},
+	}
+	for i := range hosts {
+		require.NoError(hosts[i].Insert())
+	}
+
+	counts, err := CountHostsByStatus()
+	require.NoError(err)
+	require.Len(counts, 3)
+	found := map[string]int{}
+	for _, c := range counts {
+		found[c.Status+" "+c.Provider] = c.Count
+	}
+	assert.Equal(map[string]int{
+		evergreen.HostRunning + " " + evergreen.ProviderNameEc2Auto:    2,
+		evergreen.HostRunning + " " + evergreen.ProviderNameStatic:     1,
+		evergreen.HostQuarantined + " " + evergreen.ProviderNameStatic: 1,
+	}, found)
+}
+
+func TestIncCost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+
+	h := &Host{Id: "h1"}
+	require.NoError(h.Insert())
+
+	assert.NoError(h.IncCost(2, 2*time.Hour))
+	assert.NoError(h.IncCost(0, time.Hour))
+	assert.Error(h.IncCost(-1, time.Hour))
+	assert.Error(h.IncCost(1, -time.Hour))
+
+	dbHost, err := FindOneId("h1")
+	require.NoError(err)
+	require.NotNil(dbHost)
+	assert.InDelta(2, dbHost.TotalCost, 0.001)
+	assert.Equal(3*time.Hour, dbHost.TotalCostedTime)
+	assert.InDelta(1, dbHost.HourlyPrice, 0.001)
+}
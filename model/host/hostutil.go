@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/subprocess"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
@@ -32,12 +33,24 @@ func (h *Host) TearDownCommand() string {
 }
 
 func (h *Host) CurlCommand(url string) string {
-	return fmt.Sprintf("cd ~ && curl -LO '%s/clients/%s' && chmod +x %s",
-		url,
-		h.Distro.ExecutableSubPath(),
+	return h.CurlCommandForVersion(url, evergreen.BuildRevision)
+}
+
+// CurlCommandForVersion returns a command that downloads the given version
+// of the agent to the host.
+func (h *Host) CurlCommandForVersion(url, version string) string {
+	return fmt.Sprintf("cd ~ && curl -LO '%s' && chmod +x %s",
+		h.AgentBinaryURL(url, version),
 		h.Distro.BinaryName())
 }
 
+// AgentBinaryURL returns the URL of the given version of the agent binary
+// for the host's distro.
+func (h *Host) AgentBinaryURL(url, version string) string {
+	return fmt.Sprintf("%s/%s/%s", url, evergreen.ClientDirectory,
+		evergreen.AgentVersionSubPath(version, h.Distro.ExecutableSubPath()))
+}
+
 const (
 	// sshTimeout is the timeout for SSH commands.
 	sshTimeout = 2 * time.Minute
@@ -45,6 +58,12 @@ const (
 
 // RunSSHCommand runs an SSH command on a remote host.
 func (h *Host) RunSSHCommand(ctx context.Context, cmd string, sshOptions []string) (string, error) {
+	return h.RunSSHCommandWithTimeout(ctx, cmd, sshOptions, sshTimeout)
+}
+
+// RunSSHCommandWithTimeout runs an SSH command on a remote host, giving up
+// after the timeout.
+func (h *Host) RunSSHCommandWithTimeout(ctx context.Context, cmd string, sshOptions []string, timeout time.Duration) (string, error) {
 	// compute any info necessary to ssh into the host
 	hostInfo, err := util.ParseSSHInfo(h.Host)
 	if err != nil {
@@ -90,7 +109,7 @@ func (h *Host) RunSSHCommand(ctx context.Context, cmd string, sshOptions []strin
 	})
 
 	var cancel context.CancelFunc
-	ctx, cancel = context.WithTimeout(ctx, sshTimeout)
+	ctx, cancel = context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	err = proc.Run(ctx)
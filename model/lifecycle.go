@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -156,6 +157,108 @@ func AbortVersion(versionId, caller string) error {
 	return nil
 }
 
+// VersionTaskFilter selects the tasks of a version that are restarted or
+// aborted together. Empty fields don't filter the tasks.
+type VersionTaskFilter struct {
+	Statuses     []string
+	VariantRegex string
+	TaskNames    []string
+}
+
+// Validate returns an error if the filter's variant regular expression
+// doesn't compile.
+func (f VersionTaskFilter) Validate() error {
+	if _, err := regexp.Compile(f.VariantRegex); err != nil {
+		return errors.Wrapf(err, "invalid variant regex '%s'", f.VariantRegex)
+	}
+	return nil
+}
+
+// IsEmpty returns true if the filter selects every task.
+func (f VersionTaskFilter) IsEmpty() bool {
+	return len(f.Statuses) == 0 && f.VariantRegex == "" && len(f.TaskNames) == 0
+}
+
+// Matches returns true if the task passes the filter.
+func (f VersionTaskFilter) Matches(t *task.Task) bool {
+	if len(f.Statuses) > 0 && !util.StringSliceContains(f.Statuses, t.Status) {
+		return false
+	}
+	if len(f.TaskNames) > 0 && !util.StringSliceContains(f.TaskNames, t.DisplayName) {
+		return false
+	}
+	if f.VariantRegex != "" {
+		matched, err := regexp.MatchString(f.VariantRegex, t.BuildVariant)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// findTaskIDs returns the IDs of the version's tasks that have one of the
+// statuses and pass the filter. Display tasks aren't included.
+func (f VersionTaskFilter) findTaskIDs(versionId string, statuses []string) ([]string, error) {
+	tasks, err := task.Find(task.ByVersionWithFilter(versionId, statuses, f.VariantRegex, f.TaskNames).
+		WithFields(task.IdKey, task.DisplayOnlyKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding tasks of version '%s'", versionId)
+	}
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.Id)
+	}
+	return ids, nil
+}
+
+// AbortVersionTasks sets the abort flag on the version's abortable tasks that
+// pass the filter with a single update, returning the IDs of the tasks.
+func AbortVersionTasks(versionId string, filter VersionTaskFilter, caller string) ([]string, error) {
+	statuses := evergreen.AbortableStatuses
+	if len(filter.Statuses) > 0 {
+		statuses = util.StringSliceIntersection(statuses, filter.Statuses)
+		if len(statuses) == 0 {
+			return nil, nil
+		}
+	}
+
+	ids, err := filter.findTaskIDs(versionId, statuses)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	_, err = task.UpdateAll(
+		bson.M{
+			task.IdKey:     bson.M{"$in": ids},
+			task.StatusKey: bson.M{"$in": evergreen.AbortableStatuses},
+		},
+		bson.M{"$set": bson.M{task.AbortedKey: true}},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "error setting aborted statuses")
+	}
+	event.LogManyTaskAbortRequests(ids, caller)
+
+	return ids, nil
+}
+
+// RestartVersionTasks restarts the version's tasks that pass the filter, as
+// RestartVersion does, returning the IDs of the tasks.
+func RestartVersionTasks(versionId string, filter VersionTaskFilter, caller string) ([]string, error) {
+	ids, err := filter.findTaskIDs(versionId, filter.Statuses)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return ids, errors.WithStack(RestartVersion(versionId, ids, true, caller))
+}
+
 func MarkVersionStarted(versionId string, startTime time.Time) error {
 	return version.UpdateOne(
 		bson.M{version.IdKey: versionId},
@@ -229,8 +332,16 @@ func MarkVersionCompleted(versionId string, finishTime time.Time, updates *Statu
 	return nil
 }
 
-// SetBuildPriority updates the priority field of all tasks associated with the given build id.
+// SetBuildPriority updates the priority field of all tasks associated with the given build id,
+// and records it on the build so that tasks added to the build later inherit it.
 func SetBuildPriority(buildId string, priority int64) error {
+	if err := build.UpdateOne(
+		bson.M{build.IdKey: buildId},
+		bson.M{"$set": bson.M{build.PriorityKey: priority}},
+	); err != nil && err != mgo.ErrNotFound {
+		return errors.Wrapf(err, "problem setting priority of build '%s'", buildId)
+	}
+
 	modifier := bson.M{task.PriorityKey: priority}
 	//blacklisted - these tasks should never run, so unschedule now
 	if priority < 0 {
@@ -244,9 +355,16 @@ func SetBuildPriority(buildId string, priority int64) error {
 	return err
 }
 
-// SetVersionPriority updates the priority field of all tasks associated with the given build id.
-
+// SetVersionPriority updates the priority field of all tasks associated with the given version id,
+// and records it on the version so that tasks added to the version later inherit it.
 func SetVersionPriority(versionId string, priority int64) error {
+	if err := version.UpdateOne(
+		bson.M{version.IdKey: versionId},
+		bson.M{"$set": bson.M{version.PriorityKey: priority}},
+	); err != nil && err != mgo.ErrNotFound {
+		return errors.Wrapf(err, "problem setting priority of version '%s'", versionId)
+	}
+
 	modifier := bson.M{task.PriorityKey: priority}
 	//blacklisted - these tasks should never run, so unschedule now
 	if priority < 0 {
@@ -459,7 +577,8 @@ func AddTasksToBuild(b *build.Build, project *Project, v *version.Version,
 
 	// create the new tasks for the build
 	taskIds := NewTaskIdTable(project, v)
-	tasks, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy)
+	tasks, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy,
+		newShardAssigner(project.Identifier, buildVariant.Name))
 	if err != nil {
 		return nil, errors.Wrapf(err, "error creating tasks for build '%s'", b.Id)
 	}
@@ -489,21 +608,9 @@ func CreateBuildFromVersion(project *Project, v *version.Version, taskIds TaskId
 		return "", errors.Errorf("could not find build %v in %v project file", buildName, project.Identifier)
 	}
 
-	rev := v.Revision
-	if evergreen.IsPatchRequester(v.Requester) {
-		rev = fmt.Sprintf("patch_%s_%s", v.Revision, v.Id)
-	}
-
-	// create a new build id
-	buildId := fmt.Sprintf("%s_%s_%s_%s",
-		project.Identifier,
-		buildName,
-		rev,
-		v.CreateTime.Format(build.IdTimeLayout))
-
 	// create the build itself
 	b := &build.Build{
-		Id:                  util.CleanName(buildId),
+		Id:                  newBuildId(project, v, buildName),
 		CreateTime:          v.CreateTime,
 		Activated:           activated,
 		Project:             project.Identifier,
@@ -525,13 +632,14 @@ func CreateBuildFromVersion(project *Project, v *version.Version, taskIds TaskId
 	b.BuildNumber = strconv.FormatUint(buildNumber, 10)
 
 	// create all of the necessary tasks for the build
-	tasksForBuild, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy)
+	tasksForBuild, err := createTasksForBuild(project, buildVariant, b, v, taskIds, taskNames, displayNames, generatedBy,
+		newShardAssigner(project.Identifier, buildVariant.Name))
 	if err != nil {
 		return "", errors.Wrapf(err, "error creating tasks for build %s", b.Id)
 	}
 
 	if err = tasksForBuild.InsertUnordered(); err != nil {
-		return "", errors.Wrapf(err, "error inserting task for build '%s'", buildId)
+		return "", errors.Wrapf(err, "error inserting task for build '%s'", b.Id)
 	}
 
 	// create task caches for all of the tasks, and place them into the build
@@ -554,6 +662,20 @@ func CreateBuildFromVersion(project *Project, v *version.Version, taskIds TaskId
 	return b.Id, nil
 }
 
+// newBuildId returns the ID of the version's build of the variant.
+func newBuildId(project *Project, v *version.Version, buildName string) string {
+	rev := v.Revision
+	if evergreen.IsPatchRequester(v.Requester) {
+		rev = fmt.Sprintf("patch_%s_%s", v.Revision, v.Id)
+	}
+
+	return util.CleanName(fmt.Sprintf("%s_%s_%s_%s",
+		project.Identifier,
+		buildName,
+		rev,
+		v.CreateTime.Format(build.IdTimeLayout)))
+}
+
 func CreateTasksFromGroup(in BuildVariantTaskUnit, proj *Project) []BuildVariantTaskUnit {
 	tasks := []BuildVariantTaskUnit{}
 	tg := proj.FindTaskGroup(in.Name)
@@ -600,10 +722,11 @@ func shouldNotPatchBuild(t BuildVariantTaskUnit, requester string) bool {
 // createTasksForBuild creates all of the necessary tasks for the build.  Returns a
 // slice of all of the tasks created, as well as an error if any occurs.
 // The slice of tasks will be in the same order as the project's specified tasks
-// appear in the specified build variant.
+// appear in the specified build variant. The tests of sharded tasks are split
+// between their shards by the shard assigner.
 func createTasksForBuild(project *Project, buildVariant *BuildVariant, b *build.Build,
 	v *version.Version, taskIds TaskIdConfig, taskNames []string,
-	displayNames []string, generatedBy string) (task.Tasks, error) {
+	displayNames []string, generatedBy string, shards *shardAssigner) (task.Tasks, error) {
 
 	// the list of tasks we should create.  if tasks are passed in, then
 	// use those, else use the default set
@@ -693,7 +816,19 @@ func createTasksForBuild(project *Project, buildVariant *BuildVariant, b *build.
 		newTask := createOneTask(execTable.GetId(b.BuildVariant, t.Name), t, project, buildVariant, b, v)
 
 		// set Tags based on the spec
-		newTask.Tags = project.GetSpecForTask(t.Name).Tags
+		spec := project.GetSpecForTask(t.Name)
+		newTask.Tags = spec.Tags
+
+		if spec.ShardOf != "" {
+			newTask.ShardOf = spec.ShardOf
+			newTask.ShardIndex = spec.ShardIndex
+			newTask.ShardCount = spec.ShardCount
+			shardTests, err := shards.testsForShard(spec)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			newTask.ShardTests = shardTests
+		}
 
 		// set the new task's dependencies
 		if len(t.DependsOn) == 1 &&
@@ -907,6 +1042,12 @@ func createOneTask(id string, buildVarTask BuildVariantTaskUnit, project *Projec
 			return nil
 		}
 		t.TaskGroupMaxHosts = tg.MaxHosts
+		for i, name := range tg.Tasks {
+			if name == t.DisplayName {
+				t.TaskGroupOrder = i + 1
+				break
+			}
+		}
 	}
 	return t
 }
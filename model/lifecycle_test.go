@@ -14,6 +14,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/smartystreets/goconvey/convey/reporting"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func init() {
@@ -1728,6 +1729,38 @@ func TestCreateTasksFromGroup(t *testing.T) {
 	assert.Equal("new_dependency", bvts[1].DependsOn[0].Name)
 }
 
+func TestCreateOneTaskInTaskGroup(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	v := &version.Version{
+		Id: "v1",
+		Config: `
+tasks:
+- name: first_task
+- name: second_task
+task_groups:
+- name: tg
+  max_hosts: 1
+  tasks:
+  - first_task
+  - second_task
+`,
+	}
+	p := &Project{}
+	require.NoError(LoadProjectInto([]byte(v.Config), "proj", p))
+	bv := &BuildVariant{Name: "bv", RunOn: []string{"d1"}}
+	b := &build.Build{Id: "b1", BuildVariant: "bv"}
+
+	for i, name := range []string{"first_task", "second_task"} {
+		unit := BuildVariantTaskUnit{Name: name, IsGroup: true, GroupName: "tg"}
+		tsk := createOneTask(name, unit, p, bv, b, v)
+		require.NotNil(tsk)
+		assert.Equal("tg", tsk.TaskGroup)
+		assert.Equal(1, tsk.TaskGroupMaxHosts)
+		assert.Equal(i+1, tsk.TaskGroupOrder)
+	}
+}
+
 func TestMarkAsDispatched(t *testing.T) {
 
 	var (
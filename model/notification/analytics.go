@@ -0,0 +1,229 @@
+package notification
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// NotificationAnalytics summarizes the notifications sent in a time window.
+type NotificationAnalytics struct {
+	Start    time.Time
+	End      time.Time
+	Interval time.Duration
+
+	Sent   int
+	Failed int
+	// MeanLatency is the mean time between creating and sending a
+	// notification, for notifications that record when they were created.
+	MeanLatency time.Duration
+
+	// Volume is the number of notifications sent in each interval of the
+	// window, in chronological order
+	Volume []NotificationVolume
+	// NoisySources are the subscription owners and triggers that sent the
+	// most notifications, in descending order
+	NoisySources []NotificationSource
+}
+
+// SuccessRate returns the fraction of sent notifications that were
+// delivered without error.
+func (a *NotificationAnalytics) SuccessRate() float64 {
+	if a.Sent == 0 {
+		return 0
+	}
+	return float64(a.Sent-a.Failed) / float64(a.Sent)
+}
+
+// NotificationVolume is the number of notifications sent in an interval
+// starting at Start.
+type NotificationVolume struct {
+	Start  time.Time
+	Sent   int
+	Failed int
+}
+
+// NotificationSource is the number of notifications sent by subscriptions
+// with the same owner and trigger.
+type NotificationSource struct {
+	Owner     string
+	OwnerType event.OwnerType
+	Trigger   string
+	Sent      int
+	Failed    int
+}
+
+// notificationAnalyticsGroup is the number of notifications sent by a
+// subscription in an interval.
+type notificationAnalyticsGroup struct {
+	SubscriptionID string    `bson:"subscription_id"`
+	Bucket         time.Time `bson:"bucket"`
+	Sent           int       `bson:"sent"`
+	Failed         int       `bson:"failed"`
+	// LatencyMS is the sum of the latencies of the notifications that
+	// record when they were created, and LatencySamples is their count
+	LatencyMS      int64 `bson:"latency_ms"`
+	LatencySamples int   `bson:"latency_samples"`
+}
+
+func notificationAnalyticsPipeline(start, end time.Time, interval time.Duration) []bson.M {
+	hasCreatedAt := bson.M{"$gt": []interface{}{"$" + createdAtKey, time.Time{}}}
+
+	return []bson.M{
+		{
+			"$match": bson.M{
+				sentAtKey: bson.M{
+					"$gte": start,
+					"$lt":  end,
+				},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id": bson.M{
+					"subscription_id": "$" + subscriptionIDKey,
+					"bucket": bson.M{
+						"$subtract": []interface{}{
+							"$" + sentAtKey,
+							bson.M{"$mod": []interface{}{
+								bson.M{"$subtract": []interface{}{"$" + sentAtKey, start}},
+								int64(interval / time.Millisecond),
+							}},
+						},
+					},
+				},
+				"sent": bson.M{"$sum": 1},
+				"failed": bson.M{"$sum": bson.M{
+					"$cond": []interface{}{bson.M{"$gt": []interface{}{"$" + errorKey, ""}}, 1, 0},
+				}},
+				"latency_ms": bson.M{"$sum": bson.M{
+					"$cond": []interface{}{hasCreatedAt, bson.M{"$subtract": []interface{}{"$" + sentAtKey, "$" + createdAtKey}}, 0},
+				}},
+				"latency_samples": bson.M{"$sum": bson.M{
+					"$cond": []interface{}{hasCreatedAt, 1, 0},
+				}},
+			},
+		},
+		{
+			"$project": bson.M{
+				"_id":             0,
+				"subscription_id": "$_id.subscription_id",
+				"bucket":          "$_id.bucket",
+				"sent":            1,
+				"failed":          1,
+				"latency_ms":      1,
+				"latency_samples": 1,
+			},
+		},
+	}
+}
+
+// CollectNotificationAnalytics summarizes the notifications sent between
+// start and end, with the volume reported per interval and at most limit
+// noisy sources.
+func CollectNotificationAnalytics(start, end time.Time, interval time.Duration, limit int) (*NotificationAnalytics, error) {
+	if !start.Before(end) {
+		return nil, errors.New("analytics window must end after it starts")
+	}
+	if interval <= 0 {
+		return nil, errors.New("analytics interval must be positive")
+	}
+
+	groups := []notificationAnalyticsGroup{}
+	if err := db.Aggregate(Collection, notificationAnalyticsPipeline(start, end, interval), &groups); err != nil {
+		return nil, errors.Wrap(err, "failed to aggregate sent notifications")
+	}
+
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, g := range groups {
+		if g.SubscriptionID != "" && !seen[g.SubscriptionID] {
+			seen[g.SubscriptionID] = true
+			ids = append(ids, g.SubscriptionID)
+		}
+	}
+	subscriptions, err := event.FindSubscriptionsByIDs(ids)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return summarizeNotificationAnalytics(start, end, interval, limit, groups, subscriptions), nil
+}
+
+func summarizeNotificationAnalytics(start, end time.Time, interval time.Duration, limit int, groups []notificationAnalyticsGroup, subscriptions []event.Subscription) *NotificationAnalytics {
+	analytics := &NotificationAnalytics{
+		Start:    start,
+		End:      end,
+		Interval: interval,
+	}
+
+	for bucket := start; bucket.Before(end); bucket = bucket.Add(interval) {
+		analytics.Volume = append(analytics.Volume, NotificationVolume{Start: bucket})
+	}
+
+	bySubscription := map[string]event.Subscription{}
+	for _, s := range subscriptions {
+		bySubscription[s.ID] = s
+	}
+
+	type sourceKey struct {
+		owner     string
+		ownerType event.OwnerType
+		trigger   string
+	}
+	sources := map[sourceKey]*NotificationSource{}
+
+	var latencyMS int64
+	var latencySamples int
+	for _, g := range groups {
+		analytics.Sent += g.Sent
+		analytics.Failed += g.Failed
+		latencyMS += g.LatencyMS
+		latencySamples += g.LatencySamples
+
+		if idx := int(g.Bucket.Sub(start) / interval); idx >= 0 && idx < len(analytics.Volume) {
+			analytics.Volume[idx].Sent += g.Sent
+			analytics.Volume[idx].Failed += g.Failed
+		}
+
+		s, ok := bySubscription[g.SubscriptionID]
+		if !ok {
+			continue
+		}
+		key := sourceKey{owner: s.Owner, ownerType: s.OwnerType, trigger: s.Trigger}
+		source, ok := sources[key]
+		if !ok {
+			source = &NotificationSource{Owner: s.Owner, OwnerType: s.OwnerType, Trigger: s.Trigger}
+			sources[key] = source
+		}
+		source.Sent += g.Sent
+		source.Failed += g.Failed
+	}
+
+	if latencySamples > 0 {
+		analytics.MeanLatency = time.Duration(latencyMS/int64(latencySamples)) * time.Millisecond
+	}
+
+	for _, source := range sources {
+		analytics.NoisySources = append(analytics.NoisySources, *source)
+	}
+	sort.Slice(analytics.NoisySources, func(i, j int) bool {
+		a, b := analytics.NoisySources[i], analytics.NoisySources[j]
+		if a.Sent != b.Sent {
+			return a.Sent > b.Sent
+		}
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		return a.Trigger < b.Trigger
+	})
+	if limit > 0 && len(analytics.NoisySources) > limit {
+		analytics.NoisySources = analytics.NoisySources[:limit]
+	}
+
+	return analytics
+}
@@ -0,0 +1,56 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeNotificationAnalytics(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Hour)
+	subscriptions := []event.Subscription{
+		{ID: "s0", Owner: "mci", OwnerType: event.OwnerTypeProject, Trigger: "outcome"},
+		{ID: "s1", Owner: "mci", OwnerType: event.OwnerTypeProject, Trigger: "outcome"},
+		{ID: "s2", Owner: "me", OwnerType: event.OwnerTypePerson, Trigger: "failure"},
+	}
+	groups := []notificationAnalyticsGroup{
+		{SubscriptionID: "s0", Bucket: start, Sent: 4, Failed: 1, LatencyMS: 4000, LatencySamples: 4},
+		{SubscriptionID: "s1", Bucket: start.Add(2 * time.Hour), Sent: 2, LatencyMS: 6000, LatencySamples: 1},
+		{SubscriptionID: "s2", Bucket: start.Add(2 * time.Hour), Sent: 3, Failed: 2},
+		// notifications that weren't generated by a subscription
+		{Bucket: start, Sent: 1},
+	}
+
+	analytics := summarizeNotificationAnalytics(start, end, time.Hour, 10, groups, subscriptions)
+	assert.Equal(10, analytics.Sent)
+	assert.Equal(3, analytics.Failed)
+	assert.InDelta(0.7, analytics.SuccessRate(), 0.0001)
+	assert.Equal(2*time.Second, analytics.MeanLatency)
+
+	assert.Equal([]NotificationVolume{
+		{Start: start, Sent: 5, Failed: 1},
+		{Start: start.Add(time.Hour)},
+		{Start: start.Add(2 * time.Hour), Sent: 5, Failed: 2},
+	}, analytics.Volume)
+
+	assert.Equal([]NotificationSource{
+		{Owner: "mci", OwnerType: event.OwnerTypeProject, Trigger: "outcome", Sent: 6, Failed: 1},
+		{Owner: "me", OwnerType: event.OwnerTypePerson, Trigger: "failure", Sent: 3, Failed: 2},
+	}, analytics.NoisySources)
+
+	analytics = summarizeNotificationAnalytics(start, end, time.Hour, 1, groups, subscriptions)
+	assert.Len(analytics.NoisySources, 1)
+	assert.Equal("mci", analytics.NoisySources[0].Owner)
+
+	analytics = summarizeNotificationAnalytics(start, end, time.Hour, 10, nil, nil)
+	assert.Zero(analytics.Sent)
+	assert.Zero(analytics.SuccessRate())
+	assert.Zero(analytics.MeanLatency)
+	assert.Len(analytics.Volume, 3)
+	assert.Empty(analytics.NoisySources)
+}
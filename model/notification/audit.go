@@ -0,0 +1,206 @@
+package notification
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	AuditCollection = "notification_audit"
+
+	// auditRetention is how long audit entries are kept before the TTL
+	// index removes them
+	auditRetention = 90 * 24 * time.Hour
+)
+
+// Results of attempts to send notifications
+const (
+	AuditResultSent     = "sent"
+	AuditResultFailed   = "failed"
+	AuditResultRetrying = "retrying"
+	AuditResultDeferred = "deferred"
+	AuditResultDisabled = "disabled"
+	// AuditResultSuppressed notifications were held back, and reported a
+	// failure that was fixed before they could be sent
+	AuditResultSuppressed = "suppressed"
+)
+
+// AuditResults are the results that audit entries can record.
+var AuditResults = []string{
+	AuditResultSent,
+	AuditResultFailed,
+	AuditResultRetrying,
+	AuditResultDeferred,
+	AuditResultDisabled,
+	AuditResultSuppressed,
+}
+
+//nolint: deadcode, megacheck, unused
+var (
+	auditIDKey             = bsonutil.MustHaveTag(AuditEntry{}, "ID")
+	auditNotificationIDKey = bsonutil.MustHaveTag(AuditEntry{}, "NotificationID")
+	auditSubscriberTypeKey = bsonutil.MustHaveTag(AuditEntry{}, "SubscriberType")
+	auditTargetKey         = bsonutil.MustHaveTag(AuditEntry{}, "Target")
+	auditPayloadHashKey    = bsonutil.MustHaveTag(AuditEntry{}, "PayloadHash")
+	auditInitiatorKey      = bsonutil.MustHaveTag(AuditEntry{}, "Initiator")
+	auditSubscriptionIDKey = bsonutil.MustHaveTag(AuditEntry{}, "SubscriptionID")
+	auditResultKey         = bsonutil.MustHaveTag(AuditEntry{}, "Result")
+	auditErrorKey          = bsonutil.MustHaveTag(AuditEntry{}, "Error")
+	auditAttemptKey        = bsonutil.MustHaveTag(AuditEntry{}, "Attempt")
+	auditTimestampKey      = bsonutil.MustHaveTag(AuditEntry{}, "Timestamp")
+)
+
+// AuditEntry records an attempt to send a notification: who it was sent
+// to, a hash identifying its content, who caused it to be sent, and what
+// happened.
+type AuditEntry struct {
+	ID             bson.ObjectId `bson:"_id"`
+	NotificationID string        `bson:"notification_id"`
+	SubscriberType string        `bson:"subscriber_type"`
+	Target         string        `bson:"target"`
+	PayloadHash    string        `bson:"payload_hash"`
+	// Initiator is the user who sent the notification through the REST
+	// API, or who owns the subscription that it was sent for
+	Initiator      string    `bson:"initiator,omitempty"`
+	SubscriptionID string    `bson:"subscription_id,omitempty"`
+	Result         string    `bson:"result"`
+	Error          string    `bson:"error,omitempty"`
+	Attempt        int       `bson:"attempt"`
+	Timestamp      time.Time `bson:"ts"`
+}
+
+// NewAuditEntry records the result of an attempt to send the notification.
+func NewAuditEntry(n *Notification, result string, err error) *AuditEntry {
+	entry := &AuditEntry{
+		ID:             bson.NewObjectId(),
+		NotificationID: n.ID,
+		SubscriberType: n.Subscriber.Type,
+		Target:         strings.TrimPrefix(n.Subscriber.String(), n.Subscriber.Type+"-"),
+		PayloadHash:    payloadHash(n.Payload),
+		Initiator:      n.Initiator,
+		SubscriptionID: n.SubscriptionID,
+		Result:         result,
+		Attempt:        n.Attempts + 1,
+		Timestamp:      time.Now().Truncate(time.Millisecond),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// payloadHash identifies the content of a payload without storing it, so
+// that entries for the same content can be found.
+func payloadHash(payload interface{}) string {
+	out, err := bson.Marshal(bson.M{"payload": payload})
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(out)
+
+	return hex.EncodeToString(hash[:])
+}
+
+var auditIndexOnce sync.Once
+
+// ensureAuditIndexes creates the TTL index that expires old entries, and
+// the index used to list entries.
+func ensureAuditIndexes() {
+	auditIndexOnce.Do(func() {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(db.EnsureIndex(AuditCollection, mgo.Index{
+			Key:         []string{auditTimestampKey},
+			ExpireAfter: auditRetention,
+		}))
+		catcher.Add(db.EnsureIndex(AuditCollection, mgo.Index{
+			Key: []string{auditTargetKey, "-" + auditTimestampKey},
+		}))
+		grip.Error(message.WrapError(catcher.Resolve(), message.Fields{
+			"message":    "failed to create notification audit indexes",
+			"collection": AuditCollection,
+		}))
+	})
+}
+
+func (e *AuditEntry) Insert() error {
+	ensureAuditIndexes()
+
+	return errors.Wrap(db.Insert(AuditCollection, e), "failed to insert notification audit entry")
+}
+
+// AuditFilter selects audit entries. Empty fields match every entry.
+type AuditFilter struct {
+	SubscriberType string
+	Target         string
+	Initiator      string
+	SubscriptionID string
+	NotificationID string
+	Result         string
+	// Before and After bound the time of the entries, exclusively
+	Before time.Time
+	After  time.Time
+	// StartTime and StartID are the time and ID of the first entry to
+	// return, for paginating through entries
+	StartTime time.Time
+	StartID   string
+}
+
+// FindAuditEntries returns at most limit entries matching the filter, most
+// recent first. Entries from the same time are ordered by ID.
+func FindAuditEntries(filter AuditFilter, limit int) ([]AuditEntry, error) {
+	query := bson.M{}
+	if filter.StartID != "" {
+		if !bson.IsObjectIdHex(filter.StartID) {
+			return nil, errors.Errorf("invalid audit entry ID '%s'", filter.StartID)
+		}
+		query["$or"] = []bson.M{
+			{auditTimestampKey: bson.M{"$lt": filter.StartTime}},
+			{
+				auditTimestampKey: filter.StartTime,
+				auditIDKey:        bson.M{"$lte": bson.ObjectIdHex(filter.StartID)},
+			},
+		}
+	}
+	for key, value := range map[string]string{
+		auditSubscriberTypeKey: filter.SubscriberType,
+		auditTargetKey:         filter.Target,
+		auditInitiatorKey:      filter.Initiator,
+		auditSubscriptionIDKey: filter.SubscriptionID,
+		auditNotificationIDKey: filter.NotificationID,
+		auditResultKey:         filter.Result,
+	} {
+		if value != "" {
+			query[key] = value
+		}
+	}
+	ts := bson.M{}
+	if !filter.Before.IsZero() {
+		ts["$lt"] = filter.Before
+	}
+	if !filter.After.IsZero() {
+		ts["$gt"] = filter.After
+	}
+	if len(ts) > 0 {
+		query[auditTimestampKey] = ts
+	}
+
+	entries := []AuditEntry{}
+	err := db.FindAllQ(AuditCollection, db.Query(query).Sort([]string{"-" + auditTimestampKey, "-" + auditIDKey}).Limit(limit), &entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find notification audit entries")
+	}
+
+	return entries, nil
+}
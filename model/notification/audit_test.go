@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestAuditEntries(t *testing.T) {
+	assert := assert.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	assert.NoError(db.ClearCollections(AuditCollection))
+
+	channel := "#evergreen"
+	n := &Notification{
+		ID: "n0",
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: &channel,
+		},
+		Payload:        &SlackPayload{Body: "the build broke"},
+		SubscriptionID: "sub",
+		Initiator:      "me",
+		Attempts:       1,
+	}
+
+	retrying := NewAuditEntry(n, AuditResultRetrying, errors.New("slack is down"))
+	assert.Equal(channel, retrying.Target)
+	assert.Equal(2, retrying.Attempt)
+	assert.Equal("slack is down", retrying.Error)
+	assert.NotEmpty(retrying.PayloadHash)
+	retrying.Timestamp = retrying.Timestamp.Add(-time.Minute)
+	assert.NoError(retrying.Insert())
+
+	n.Attempts = 2
+	sent := NewAuditEntry(n, AuditResultSent, nil)
+	assert.Equal(retrying.PayloadHash, sent.PayloadHash)
+	assert.Empty(sent.Error)
+	assert.NoError(sent.Insert())
+
+	other := &Notification{
+		ID: "n1",
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: &channel,
+		},
+		Payload: &SlackPayload{Body: "the build is fixed"},
+	}
+	otherSent := NewAuditEntry(other, AuditResultSent, nil)
+	assert.NotEqual(sent.PayloadHash, otherSent.PayloadHash)
+	assert.NoError(otherSent.Insert())
+
+	entries, err := FindAuditEntries(AuditFilter{}, 10)
+	assert.NoError(err)
+	assert.Len(entries, 3)
+	assert.Equal(retrying.ID, entries[2].ID)
+
+	entries, err = FindAuditEntries(AuditFilter{Target: channel, Initiator: "me"}, 10)
+	assert.NoError(err)
+	if assert.Len(entries, 2) {
+		assert.Equal(sent.ID, entries[0].ID)
+		assert.Equal(retrying.ID, entries[1].ID)
+	}
+
+	entries, err = FindAuditEntries(AuditFilter{Result: AuditResultSent}, 1)
+	assert.NoError(err)
+	assert.Len(entries, 1)
+
+	entries, err = FindAuditEntries(AuditFilter{Before: sent.Timestamp.Add(-time.Second)}, 10)
+	assert.NoError(err)
+	if assert.Len(entries, 1) {
+		assert.Equal(retrying.ID, entries[0].ID)
+	}
+
+	entries, err = FindAuditEntries(AuditFilter{SubscriptionID: "other"}, 10)
+	assert.NoError(err)
+	assert.Empty(entries)
+
+	// entries from the same time are paginated by ID
+	otherSent.ID = newerObjectID(otherSent.ID)
+	otherSent.Timestamp = sent.Timestamp
+	assert.NoError(otherSent.Insert())
+	entries, err = FindAuditEntries(AuditFilter{}, 10)
+	assert.NoError(err)
+	if assert.Len(entries, 4) {
+		entries, err = FindAuditEntries(AuditFilter{StartTime: entries[1].Timestamp, StartID: entries[1].ID.Hex()}, 10)
+		assert.NoError(err)
+		assert.Len(entries, 3)
+	}
+
+	entries, err = FindAuditEntries(AuditFilter{StartTime: sent.Timestamp, StartID: "garbage"}, 10)
+	assert.Error(err)
+	assert.Nil(entries)
+}
+
+func newerObjectID(id bson.ObjectId) bson.ObjectId {
+	return bson.NewObjectIdWithTime(id.Time().Add(time.Second))
+}
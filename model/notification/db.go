@@ -2,6 +2,7 @@ package notification
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
@@ -9,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
 	adb "github.com/mongodb/anser/db"
+	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	mgo "gopkg.in/mgo.v2"
@@ -21,11 +23,26 @@ const (
 
 //nolint: deadcode, megacheck, unused
 var (
-	idKey         = bsonutil.MustHaveTag(Notification{}, "ID")
-	subscriberKey = bsonutil.MustHaveTag(Notification{}, "Subscriber")
-	payloadKey    = bsonutil.MustHaveTag(Notification{}, "Payload")
-	sentAtKey     = bsonutil.MustHaveTag(Notification{}, "SentAt")
-	errorKey      = bsonutil.MustHaveTag(Notification{}, "Error")
+	idKey                = bsonutil.MustHaveTag(Notification{}, "ID")
+	subscriberKey        = bsonutil.MustHaveTag(Notification{}, "Subscriber")
+	payloadKey           = bsonutil.MustHaveTag(Notification{}, "Payload")
+	createdAtKey         = bsonutil.MustHaveTag(Notification{}, "CreatedAt")
+	sentAtKey            = bsonutil.MustHaveTag(Notification{}, "SentAt")
+	errorKey             = bsonutil.MustHaveTag(Notification{}, "Error")
+	credentialVersionKey = bsonutil.MustHaveTag(Notification{}, "CredentialVersion")
+	subscriptionIDKey    = bsonutil.MustHaveTag(Notification{}, "SubscriptionID")
+	incidentIDKey        = bsonutil.MustHaveTag(Notification{}, "IncidentID")
+	initiatorKey         = bsonutil.MustHaveTag(Notification{}, "Initiator")
+	recipientKey         = bsonutil.MustHaveTag(Notification{}, "Recipient")
+	projectKey           = bsonutil.MustHaveTag(Notification{}, "Project")
+	eventIDKey           = bsonutil.MustHaveTag(Notification{}, "EventID")
+	digestKey            = bsonutil.MustHaveTag(Notification{}, "Digest")
+	escalationIDKey      = bsonutil.MustHaveTag(Notification{}, "EscalationID")
+	deliveryKey          = bsonutil.MustHaveTag(Notification{}, "Delivery")
+	slackMessageKey      = bsonutil.MustHaveTag(Notification{}, "SlackMessage")
+	attemptsKey          = bsonutil.MustHaveTag(Notification{}, "Attempts")
+	deadLetteredKey      = bsonutil.MustHaveTag(Notification{}, "DeadLettered")
+	idempotencyKeyKey    = bsonutil.MustHaveTag(Notification{}, "IdempotencyKey")
 )
 
 type unmarshalNotification struct {
@@ -33,8 +50,27 @@ type unmarshalNotification struct {
 	Subscriber event.Subscriber `bson:"subscriber"`
 	Payload    bson.Raw         `bson:"payload"`
 
-	SentAt time.Time `bson:"sent_at,omitempty"`
-	Error  string    `bson:"error,omitempty"`
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+	SentAt    time.Time `bson:"sent_at,omitempty"`
+	Error     string    `bson:"error,omitempty"`
+
+	CredentialVersion string `bson:"credential_version,omitempty"`
+
+	SubscriptionID string `bson:"subscription_id,omitempty"`
+	IncidentID     string `bson:"incident_id,omitempty"`
+	Initiator      string `bson:"initiator,omitempty"`
+	Recipient      string `bson:"recipient,omitempty"`
+	Project        string `bson:"project,omitempty"`
+	EventID        string `bson:"event_id,omitempty"`
+	Digest         string `bson:"digest,omitempty"`
+	EscalationID   string `bson:"escalation_id,omitempty"`
+
+	Delivery     *util.WebhookDeliveryStatus `bson:"delivery,omitempty"`
+	SlackMessage *util.SlackMessageRef       `bson:"slack_message,omitempty"`
+	Attempts     int                         `bson:"attempts,omitempty"`
+	DeadLettered bool                        `bson:"dead_lettered,omitempty"`
+
+	IdempotencyKey string `bson:"idempotency_key,omitempty"`
 }
 
 func (n *Notification) SetBSON(raw bson.Raw) error {
@@ -48,7 +84,7 @@ func (n *Notification) SetBSON(raw bson.Raw) error {
 		n.Payload = &util.EvergreenWebhook{}
 
 	case event.EmailSubscriberType:
-		n.Payload = &message.Email{}
+		n.Payload = &util.EvergreenEmail{}
 
 	case event.JIRAIssueSubscriberType:
 		n.Payload = &message.JiraIssue{}
@@ -63,6 +99,12 @@ func (n *Notification) SetBSON(raw bson.Raw) error {
 	case event.GithubPullRequestSubscriberType:
 		n.Payload = &message.GithubStatus{}
 
+	case event.OpsgenieSubscriberType:
+		n.Payload = &util.OpsgenieAlert{}
+
+	case event.GithubCheckSubscriberType:
+		n.Payload = &util.GithubCheckRun{}
+
 	default:
 		return errors.Errorf("unknown payload type %s", temp.Subscriber.Type)
 	}
@@ -73,8 +115,23 @@ func (n *Notification) SetBSON(raw bson.Raw) error {
 
 	n.ID = temp.ID
 	n.Subscriber = temp.Subscriber
+	n.CreatedAt = temp.CreatedAt
 	n.SentAt = temp.SentAt
 	n.Error = temp.Error
+	n.CredentialVersion = temp.CredentialVersion
+	n.SubscriptionID = temp.SubscriptionID
+	n.IncidentID = temp.IncidentID
+	n.Initiator = temp.Initiator
+	n.Recipient = temp.Recipient
+	n.Project = temp.Project
+	n.EventID = temp.EventID
+	n.Digest = temp.Digest
+	n.EscalationID = temp.EscalationID
+	n.Delivery = temp.Delivery
+	n.SlackMessage = temp.SlackMessage
+	n.Attempts = temp.Attempts
+	n.DeadLettered = temp.DeadLettered
+	n.IdempotencyKey = temp.IdempotencyKey
 
 	return nil
 }
@@ -113,6 +170,65 @@ func InsertMany(items ...Notification) error {
 	return db.InsertMany(Collection, interfaces...)
 }
 
+var idempotencyIndexOnce sync.Once
+
+// ensureIdempotencyIndex creates the index that prevents notifications
+// with the same idempotency key from being inserted more than once.
+func ensureIdempotencyIndex() {
+	idempotencyIndexOnce.Do(func() {
+		grip.Error(message.WrapError(db.EnsureIndex(Collection, mgo.Index{
+			Key:    []string{idempotencyKeyKey},
+			Unique: true,
+			Sparse: true,
+		}), message.Fields{
+			"message":    "failed to create notification idempotency index",
+			"collection": Collection,
+		}))
+	})
+}
+
+// InsertIdempotent inserts the notification, unless a notification with
+// the same idempotency key already exists. It returns the inserted or
+// existing notification, and whether the notification was inserted.
+func InsertIdempotent(n *Notification) (*Notification, bool, error) {
+	if n.IdempotencyKey == "" {
+		return nil, false, errors.Errorf("notification '%s' has no idempotency key", n.ID)
+	}
+	ensureIdempotencyIndex()
+
+	err := db.Insert(Collection, n)
+	if db.IsDuplicateKey(err) {
+		existing, err := FindByIdempotencyKey(n.IdempotencyKey)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing == nil {
+			return nil, false, errors.Errorf("notification with idempotency key '%s' not found", n.IdempotencyKey)
+		}
+		return existing, false, nil
+	}
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to insert notification")
+	}
+
+	return n, true, nil
+}
+
+// FindByIdempotencyKey returns the notification with the idempotency key,
+// if any.
+func FindByIdempotencyKey(key string) (*Notification, error) {
+	notification := Notification{}
+	err := db.FindOneQ(Collection, db.Query(bson.M{
+		idempotencyKeyKey: key,
+	}), &notification)
+
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &notification, errors.Wrapf(err, "failed to find notification with idempotency key '%s'", key)
+}
+
 func Find(id string) (*Notification, error) {
 	notification := Notification{}
 	err := db.FindOneQ(Collection, byID(id), &notification)
@@ -0,0 +1,272 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	DigestCollection = "notification_digests"
+
+	// digestSubjectTemplate is the subject of digest emails, and the
+	// first line of digest slack messages
+	digestSubjectTemplate = "Evergreen %s digest: %d notifications"
+)
+
+//nolint: deadcode, megacheck, unused
+var (
+	digestIDKey             = bsonutil.MustHaveTag(DigestEntry{}, "ID")
+	digestKeyKey            = bsonutil.MustHaveTag(DigestEntry{}, "Key")
+	digestSubscriberKey     = bsonutil.MustHaveTag(DigestEntry{}, "Subscriber")
+	digestWindowKey         = bsonutil.MustHaveTag(DigestEntry{}, "Window")
+	digestWindowEndKey      = bsonutil.MustHaveTag(DigestEntry{}, "WindowEnd")
+	digestNotificationIDKey = bsonutil.MustHaveTag(DigestEntry{}, "NotificationID")
+	digestSummaryKey        = bsonutil.MustHaveTag(DigestEntry{}, "Summary")
+	digestCreatedAtKey      = bsonutil.MustHaveTag(DigestEntry{}, "CreatedAt")
+)
+
+// DigestEntry is a notification that is waiting to be sent to its
+// subscriber in a digest with the others in the same window.
+type DigestEntry struct {
+	ID bson.ObjectId `bson:"_id"`
+	// Key identifies the subscriber, so that entries to the same
+	// subscriber are grouped into one digest
+	Key        string           `bson:"key"`
+	Subscriber event.Subscriber `bson:"subscriber"`
+	// Window is the digest window, and WindowEnd is when the digest that
+	// the entry is in is sent
+	Window    string    `bson:"window"`
+	WindowEnd time.Time `bson:"window_end"`
+
+	NotificationID string `bson:"notification_id"`
+	EventID        string `bson:"event_id,omitempty"`
+	SubscriptionID string `bson:"subscription_id,omitempty"`
+	Initiator      string `bson:"initiator,omitempty"`
+	Recipient      string `bson:"recipient,omitempty"`
+	Project        string `bson:"project,omitempty"`
+
+	// Summary is the line describing the notification in the digest
+	Summary   string    `bson:"summary"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// DigestWindowEnd returns the end of the digest window that contains t.
+// Windows are aligned to hours and days in UTC.
+func DigestWindowEnd(window string, t time.Time) (time.Time, error) {
+	t = t.UTC()
+	switch window {
+	case event.DigestHourly:
+		return t.Truncate(time.Hour).Add(time.Hour), nil
+	case event.DigestDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1), nil
+	default:
+		return time.Time{}, errors.Errorf("unknown digest window '%s'", window)
+	}
+}
+
+// NewDigestEntry makes an entry for a notification that is batched into a
+// digest, summarizing its payload.
+func NewDigestEntry(n *Notification) (*DigestEntry, error) {
+	windowEnd, err := DigestWindowEnd(n.Digest, n.CreatedAt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't add notification '%s' to a digest", n.ID)
+	}
+	summary, err := digestSummary(n)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't add notification '%s' to a digest", n.ID)
+	}
+
+	return &DigestEntry{
+		ID:             bson.NewObjectId(),
+		Key:            n.Subscriber.String(),
+		Subscriber:     n.Subscriber,
+		Window:         n.Digest,
+		WindowEnd:      windowEnd,
+		NotificationID: n.ID,
+		EventID:        n.EventID,
+		SubscriptionID: n.SubscriptionID,
+		Initiator:      n.Initiator,
+		Recipient:      n.Recipient,
+		Project:        n.Project,
+		Summary:        summary,
+		CreatedAt:      n.CreatedAt,
+	}, nil
+}
+
+// Notification returns the notification that the entry summarizes,
+// without its payload, which isn't kept.
+func (e *DigestEntry) Notification() *Notification {
+	return &Notification{
+		ID:             e.NotificationID,
+		EventID:        e.EventID,
+		Subscriber:     e.Subscriber,
+		SubscriptionID: e.SubscriptionID,
+		Initiator:      e.Initiator,
+		Recipient:      e.Recipient,
+		Project:        e.Project,
+		Digest:         e.Window,
+		CreatedAt:      e.CreatedAt,
+	}
+}
+
+// SetSummary summarizes the notification, after it's rendered again, as
+// the entry's line in the digest.
+func (e *DigestEntry) SetSummary(n *Notification) error {
+	summary, err := digestSummary(n)
+	if err != nil {
+		return errors.Wrapf(err, "can't summarize notification '%s'", n.ID)
+	}
+	e.Summary = summary
+
+	return nil
+}
+
+// digestSummary returns the first line of an email's subject or a slack
+// message's text.
+func digestSummary(n *Notification) (string, error) {
+	var summary string
+	switch payload := n.Payload.(type) {
+	case *message.Email:
+		summary = payload.Subject
+	case *util.EvergreenEmail:
+		summary = payload.Subject
+	case *SlackPayload:
+		summary = payload.Body
+	default:
+		return "", errors.Errorf("%s notifications can't be sent in digests", n.Subscriber.Type)
+	}
+	summary = strings.TrimSpace(strings.SplitN(strings.TrimSpace(summary), "\n", 2)[0])
+	if summary == "" {
+		summary = n.ID
+	}
+
+	return summary, nil
+}
+
+var digestIndexOnce sync.Once
+
+// ensureDigestIndexes creates the index used to find the entries in
+// windows that have ended.
+func ensureDigestIndexes() {
+	digestIndexOnce.Do(func() {
+		grip.Error(message.WrapError(db.EnsureIndex(DigestCollection, mgo.Index{
+			Key: []string{digestWindowEndKey, digestCreatedAtKey},
+		}), message.Fields{
+			"message":    "failed to create notification digest indexes",
+			"collection": DigestCollection,
+		}))
+	})
+}
+
+func (e *DigestEntry) Insert() error {
+	ensureDigestIndexes()
+
+	return errors.Wrap(db.Insert(DigestCollection, e), "failed to insert notification digest entry")
+}
+
+// FindEndedDigestEntries returns the entries in windows that ended at or
+// before now, oldest first.
+func FindEndedDigestEntries(now time.Time) ([]DigestEntry, error) {
+	entries := []DigestEntry{}
+	query := db.Query(bson.M{
+		digestWindowEndKey: bson.M{"$lte": now},
+	}).Sort([]string{digestCreatedAtKey})
+	if err := db.FindAllQ(DigestCollection, query, &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to find notification digest entries")
+	}
+
+	return entries, nil
+}
+
+// RemoveDigestEntries removes the entries, once their digest has been
+// created.
+func RemoveDigestEntries(entries []DigestEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	ids := make([]bson.ObjectId, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	err := db.RemoveAll(DigestCollection, bson.M{
+		digestIDKey: bson.M{"$in": ids},
+	})
+
+	return errors.Wrap(err, "failed to remove notification digest entries")
+}
+
+// GroupDigestEntries groups entries into the digests that they are sent in:
+// one per subscriber per window. Digests are ordered by their first entry,
+// and entries keep their order.
+func GroupDigestEntries(entries []DigestEntry) [][]DigestEntry {
+	out := [][]DigestEntry{}
+	digests := map[string]int{}
+	for _, e := range entries {
+		key := fmt.Sprintf("%s|%s|%d", e.Key, e.Window, e.WindowEnd.Unix())
+		idx, ok := digests[key]
+		if !ok {
+			idx = len(out)
+			digests[key] = idx
+			out = append(out, []DigestEntry{})
+		}
+		out[idx] = append(out[idx], e)
+	}
+
+	return out
+}
+
+// NewDigestNotification renders entries that are in the same digest into
+// one notification to their subscriber. Its ID is derived from the digest,
+// so that it can only be created once.
+func NewDigestNotification(entries []DigestEntry) (*Notification, error) {
+	if len(entries) == 0 {
+		return nil, errors.New("cannot create a digest without entries")
+	}
+	first := entries[0]
+
+	subject := fmt.Sprintf(digestSubjectTemplate, first.Window, len(entries))
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, fmt.Sprintf("%s %s", e.CreatedAt.UTC().Format("15:04"), e.Summary))
+	}
+
+	var payload interface{}
+	switch first.Subscriber.Type {
+	case event.EmailSubscriberType:
+		payload = &message.Email{
+			Subject:           subject,
+			Body:              strings.Join(lines, "\n"),
+			PlainTextContents: true,
+		}
+	case event.SlackSubscriberType:
+		for i := range lines {
+			lines[i] = "• " + lines[i]
+		}
+		payload = &SlackPayload{
+			Body: fmt.Sprintf("*%s*\n%s", subject, strings.Join(lines, "\n")),
+		}
+	default:
+		return nil, errors.Errorf("%s subscribers cannot receive digests", first.Subscriber.Type)
+	}
+
+	return &Notification{
+		ID:         fmt.Sprintf("digest-%s-%d-%s", first.Window, first.WindowEnd.Unix(), first.Key),
+		Subscriber: first.Subscriber,
+		Payload:    payload,
+		CreatedAt:  time.Now().Truncate(time.Millisecond),
+		Recipient:  first.Recipient,
+		Initiator:  first.Initiator,
+	}, nil
+}
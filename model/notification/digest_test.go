@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDigestWindowEnd(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Date(2018, time.June, 30, 23, 15, 0, 0, time.UTC)
+
+	end, err := DigestWindowEnd(event.DigestHourly, now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC), end)
+
+	end, err = DigestWindowEnd(event.DigestDaily, now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2018, time.July, 1, 0, 0, 0, 0, time.UTC), end)
+
+	end, err = DigestWindowEnd(event.DigestDaily, now.Add(time.Hour))
+	assert.NoError(err)
+	assert.Equal(time.Date(2018, time.July, 2, 0, 0, 0, 0, time.UTC), end)
+
+	_, err = DigestWindowEnd("weekly", now)
+	assert.Error(err)
+}
+
+func TestDigests(t *testing.T) {
+	assert := assert.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	assert.NoError(db.ClearCollections(DigestCollection))
+
+	email := "me@example.com"
+	channel := "@me"
+	createdAt := time.Now().Add(-2 * time.Hour).Truncate(time.Millisecond)
+	notifications := []Notification{
+		{
+			ID:         "n0",
+			Subscriber: event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			Payload:    &message.Email{Subject: "task failed\nwith details"},
+			Digest:     event.DigestHourly,
+			Recipient:  "me",
+			CreatedAt:  createdAt,
+		},
+		{
+			ID:         "n1",
+			Subscriber: event.Subscriber{Type: event.SlackSubscriberType, Target: &channel},
+			Payload:    &SlackPayload{Body: "build failed"},
+			Digest:     event.DigestHourly,
+			CreatedAt:  createdAt,
+		},
+		{
+			ID:         "n2",
+			Subscriber: event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			Payload:    &message.Email{Subject: "version failed"},
+			Digest:     event.DigestHourly,
+			Recipient:  "me",
+			CreatedAt:  createdAt.Add(time.Second),
+		},
+		{
+			ID:         "n3",
+			Subscriber: event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			Payload:    &message.Email{Subject: "still pending"},
+			Digest:     event.DigestDaily,
+			CreatedAt:  time.Now(),
+		},
+	}
+	for i := range notifications {
+		entry, err := NewDigestEntry(&notifications[i])
+		assert.NoError(err)
+		assert.NoError(entry.Insert())
+	}
+
+	_, err := NewDigestEntry(&Notification{
+		ID:         "jira",
+		Subscriber: event.Subscriber{Type: event.JIRACommentSubscriberType, Target: &email},
+		Payload:    &email,
+		Digest:     event.DigestHourly,
+	})
+	assert.Error(err)
+
+	entries, err := FindEndedDigestEntries(time.Now())
+	assert.NoError(err)
+	assert.Len(entries, 3)
+	assert.Equal("task failed", entries[0].Summary)
+
+	digests := GroupDigestEntries(entries)
+	if !assert.Len(digests, 2) {
+		return
+	}
+	assert.Len(digests[0], 2)
+	assert.Equal("n0", digests[0][0].NotificationID)
+	assert.Equal("n2", digests[0][1].NotificationID)
+	assert.Len(digests[1], 1)
+
+	n, err := NewDigestNotification(digests[0])
+	assert.NoError(err)
+	assert.Equal("me", n.Recipient)
+	assert.Equal(event.EmailSubscriberType, n.Subscriber.Type)
+	payload, ok := n.Payload.(*message.Email)
+	if assert.True(ok) {
+		assert.Equal("Evergreen hourly digest: 2 notifications", payload.Subject)
+		assert.Contains(payload.Body, "task failed")
+		assert.Contains(payload.Body, "version failed")
+	}
+	again, err := NewDigestNotification(digests[0])
+	assert.NoError(err)
+	assert.Equal(n.ID, again.ID)
+
+	n, err = NewDigestNotification(digests[1])
+	assert.NoError(err)
+	slack, ok := n.Payload.(*SlackPayload)
+	if assert.True(ok) {
+		assert.Contains(slack.Body, "build failed")
+	}
+	_, err = n.Composer()
+	assert.NoError(err)
+
+	assert.NoError(RemoveDigestEntries(digests[0]))
+	entries, err = FindEndedDigestEntries(time.Now())
+	assert.NoError(err)
+	assert.Len(entries, 1)
+}
@@ -0,0 +1,248 @@
+package notification
+
+import (
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	EscalationsCollection = "notification_escalations"
+)
+
+//nolint: deadcode, megacheck, unused
+var (
+	escalationNextTierKey          = bsonutil.MustHaveTag(Escalation{}, "NextTier")
+	escalationEscalateAtKey        = bsonutil.MustHaveTag(Escalation{}, "EscalateAt")
+	escalationNotificationIDsKey   = bsonutil.MustHaveTag(Escalation{}, "NotificationIDs")
+	escalationAcknowledgedAtKey    = bsonutil.MustHaveTag(Escalation{}, "AcknowledgedAt")
+	escalationAcknowledgedByKey    = bsonutil.MustHaveTag(Escalation{}, "AcknowledgedBy")
+	escalationAcknowledgedNotifKey = bsonutil.MustHaveTag(Escalation{}, "AcknowledgedNotification")
+)
+
+// Escalation tracks a notification that is sent on to further tiers of
+// subscribers, one tier at a time, until one of the notifications sent in
+// it is acknowledged.
+type Escalation struct {
+	ID             string `bson:"_id"`
+	SubscriptionID string `bson:"subscription_id"`
+
+	// Tiers are the notifications to send to each tier, which are rendered
+	// when the escalation is created, and NextTier is the index of the
+	// tier that is notified when the escalation is next due
+	Tiers    []EscalationTier `bson:"tiers"`
+	NextTier int              `bson:"next_tier"`
+	// Delay is how long each tier has to acknowledge before the next one
+	// is notified, and EscalateAt is when the next tier is notified. It is
+	// unset once there are no more tiers, or the escalation is acknowledged.
+	Delay      time.Duration `bson:"delay"`
+	EscalateAt time.Time     `bson:"escalate_at,omitempty"`
+
+	// NotificationIDs are the notifications sent so far
+	NotificationIDs []string `bson:"notification_ids"`
+
+	AcknowledgedAt           time.Time `bson:"acknowledged_at,omitempty"`
+	AcknowledgedBy           string    `bson:"acknowledged_by,omitempty"`
+	AcknowledgedNotification string    `bson:"acknowledged_notification,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+type EscalationTier struct {
+	Name          string         `bson:"name"`
+	Notifications []Notification `bson:"notifications"`
+}
+
+// NewEscalation returns an escalation that starts with the notification n,
+// which has been sent to the first tier, and then notifies each of the
+// given tiers after the delay. The notifications in tiers are attached to
+// the escalation.
+func NewEscalation(n *Notification, delay time.Duration, tiers []EscalationTier) (*Escalation, error) {
+	if n == nil {
+		return nil, errors.New("cannot create escalation from nil notification")
+	}
+	if delay <= 0 {
+		return nil, errors.New("escalation delay must be positive")
+	}
+
+	e := &Escalation{
+		ID:              bson.NewObjectId().Hex(),
+		SubscriptionID:  n.SubscriptionID,
+		Tiers:           tiers,
+		Delay:           delay,
+		NotificationIDs: []string{n.ID},
+		CreatedAt:       time.Now().Truncate(time.Millisecond),
+	}
+	if len(tiers) > 0 {
+		e.EscalateAt = e.CreatedAt.Add(delay)
+	}
+	n.EscalationID = e.ID
+	for i := range e.Tiers {
+		for j := range e.Tiers[i].Notifications {
+			e.Tiers[i].Notifications[j].EscalationID = e.ID
+		}
+	}
+
+	return e, nil
+}
+
+var escalationIndexOnce sync.Once
+
+// ensureEscalationIndexes creates the index used to find the escalations
+// that are due.
+func ensureEscalationIndexes() {
+	escalationIndexOnce.Do(func() {
+		grip.Error(message.WrapError(db.EnsureIndex(EscalationsCollection, mgo.Index{
+			Key:    []string{escalationEscalateAtKey},
+			Sparse: true,
+		}), message.Fields{
+			"message":    "failed to create notification escalation indexes",
+			"collection": EscalationsCollection,
+		}))
+	})
+}
+
+func (e *Escalation) Insert() error {
+	ensureEscalationIndexes()
+
+	return errors.Wrap(db.Insert(EscalationsCollection, e), "failed to insert notification escalation")
+}
+
+func (e *Escalation) IsAcknowledged() bool {
+	return !e.AcknowledgedAt.IsZero()
+}
+
+func FindEscalation(id string) (*Escalation, error) {
+	e := Escalation{}
+	err := db.FindOneQ(EscalationsCollection, db.Query(bson.M{
+		idKey: id,
+	}), &e)
+
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &e, errors.Wrapf(err, "failed to find notification escalation '%s'", id)
+}
+
+// FindDueEscalations returns the unacknowledged escalations whose next tier
+// should be notified at or before now.
+func FindDueEscalations(now time.Time) ([]Escalation, error) {
+	escalations := []Escalation{}
+	query := db.Query(bson.M{
+		escalationEscalateAtKey:     bson.M{"$lte": now},
+		escalationAcknowledgedAtKey: bson.M{"$exists": false},
+	}).Sort([]string{escalationEscalateAtKey})
+	if err := db.FindAllQ(EscalationsCollection, query, &escalations); err != nil {
+		return nil, errors.Wrap(err, "failed to find due notification escalations")
+	}
+
+	return escalations, nil
+}
+
+// Acknowledge stops the escalation, recording the user who acknowledged
+// it through the notification n. Acknowledging an escalation that is
+// already acknowledged is a no-op.
+func (e *Escalation) Acknowledge(n *Notification, user string) error {
+	if e.IsAcknowledged() {
+		return nil
+	}
+	if n.EscalationID != e.ID {
+		return errors.Errorf("notification '%s' was not sent in escalation '%s'", n.ID, e.ID)
+	}
+
+	acknowledgedAt := time.Now().Truncate(time.Millisecond)
+	err := db.Update(EscalationsCollection, bson.M{
+		idKey:                       e.ID,
+		escalationAcknowledgedAtKey: bson.M{"$exists": false},
+	}, bson.M{
+		"$set": bson.M{
+			escalationAcknowledgedAtKey:    acknowledgedAt,
+			escalationAcknowledgedByKey:    user,
+			escalationAcknowledgedNotifKey: n.ID,
+		},
+		"$unset": bson.M{
+			escalationEscalateAtKey: 1,
+		},
+	})
+	if err == mgo.ErrNotFound {
+		// it was acknowledged concurrently
+		updated, findErr := FindEscalation(e.ID)
+		if findErr != nil {
+			return findErr
+		}
+		if updated == nil {
+			return errors.Errorf("notification escalation '%s' does not exist", e.ID)
+		}
+		*e = *updated
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to acknowledge notification escalation")
+	}
+
+	e.AcknowledgedAt = acknowledgedAt
+	e.AcknowledgedBy = user
+	e.AcknowledgedNotification = n.ID
+	e.EscalateAt = time.Time{}
+
+	return nil
+}
+
+// Escalate advances the escalation to its next tier and returns that
+// tier's notifications, which the caller is responsible for sending. It
+// returns no notifications if the escalation was acknowledged or advanced
+// since it was fetched.
+func (e *Escalation) Escalate(now time.Time) ([]Notification, error) {
+	if e.IsAcknowledged() || e.NextTier >= len(e.Tiers) {
+		return nil, nil
+	}
+
+	notifications := e.Tiers[e.NextTier].Notifications
+	ids := make([]string, 0, len(notifications))
+	for i := range notifications {
+		notifications[i].CreatedAt = now.Truncate(time.Millisecond)
+		ids = append(ids, notifications[i].ID)
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			escalationNextTierKey: e.NextTier + 1,
+		},
+		"$push": bson.M{
+			escalationNotificationIDsKey: bson.M{"$each": ids},
+		},
+	}
+	escalateAt := time.Time{}
+	if e.NextTier+1 < len(e.Tiers) {
+		escalateAt = now.Add(e.Delay).Truncate(time.Millisecond)
+		update["$set"].(bson.M)[escalationEscalateAtKey] = escalateAt
+	} else {
+		update["$unset"] = bson.M{escalationEscalateAtKey: 1}
+	}
+
+	err := db.Update(EscalationsCollection, bson.M{
+		idKey:                       e.ID,
+		escalationNextTierKey:       e.NextTier,
+		escalationAcknowledgedAtKey: bson.M{"$exists": false},
+	}, update)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to escalate notification escalation '%s'", e.ID)
+	}
+
+	e.NextTier++
+	e.EscalateAt = escalateAt
+	e.NotificationIDs = append(e.NotificationIDs, ids...)
+
+	return notifications, nil
+}
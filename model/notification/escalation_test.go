@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscalations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(EscalationsCollection))
+
+	committer := "@committer"
+	channel := "#team"
+	admin := "@admin"
+	n := &Notification{
+		ID:             "n0",
+		Subscriber:     event.Subscriber{Type: event.SlackSubscriberType, Target: &committer},
+		Payload:        &SlackPayload{Body: "the build broke"},
+		SubscriptionID: "sub",
+	}
+	tiers := []EscalationTier{
+		{
+			Name: "team",
+			Notifications: []Notification{
+				{
+					ID:         "n1",
+					Subscriber: event.Subscriber{Type: event.SlackSubscriberType, Target: &channel},
+					Payload:    &SlackPayload{Body: "the build broke"},
+				},
+			},
+		},
+		{
+			Name: "admins",
+			Notifications: []Notification{
+				{
+					ID:         "n2",
+					Subscriber: event.Subscriber{Type: event.SlackSubscriberType, Target: &admin},
+					Payload:    &SlackPayload{Body: "the build broke"},
+				},
+			},
+		},
+	}
+
+	_, err := NewEscalation(n, 0, tiers)
+	assert.Error(err)
+
+	e, err := NewEscalation(n, time.Hour, tiers)
+	require.NoError(err)
+	assert.Equal(e.ID, n.EscalationID)
+	assert.Equal(e.ID, e.Tiers[1].Notifications[0].EscalationID)
+	assert.Equal("sub", e.SubscriptionID)
+	require.NoError(e.Insert())
+
+	// nothing is due until the delay has passed
+	due, err := FindDueEscalations(time.Now())
+	assert.NoError(err)
+	assert.Empty(due)
+
+	now := time.Now().Add(time.Hour + time.Minute)
+	due, err = FindDueEscalations(now)
+	assert.NoError(err)
+	require.Len(due, 1)
+
+	notifications, err := due[0].Escalate(now)
+	assert.NoError(err)
+	require.Len(notifications, 1)
+	assert.Equal("n1", notifications[0].ID)
+	assert.Equal(e.ID, notifications[0].EscalationID)
+
+	// a stale copy can't escalate again
+	stale, err := e.Escalate(now)
+	assert.NoError(err)
+	assert.Empty(stale)
+
+	due, err = FindDueEscalations(now)
+	assert.NoError(err)
+	assert.Empty(due)
+
+	e, err = FindEscalation(e.ID)
+	assert.NoError(err)
+	require.NotNil(e)
+	assert.Equal(1, e.NextTier)
+	assert.Equal([]string{"n0", "n1"}, e.NotificationIDs)
+
+	// acknowledging stops the escalation
+	assert.Error(e.Acknowledge(&Notification{ID: "other"}, "me"))
+	assert.NoError(e.Acknowledge(&notifications[0], "me"))
+	assert.True(e.IsAcknowledged())
+	due, err = FindDueEscalations(now.Add(2 * time.Hour))
+	assert.NoError(err)
+	assert.Empty(due)
+
+	e, err = FindEscalation(e.ID)
+	assert.NoError(err)
+	require.NotNil(e)
+	assert.Equal("me", e.AcknowledgedBy)
+	assert.Equal("n1", e.AcknowledgedNotification)
+	stale, err = e.Escalate(now.Add(2 * time.Hour))
+	assert.NoError(err)
+	assert.Empty(stale)
+}
@@ -0,0 +1,179 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	IncidentsCollection = "notification_incidents"
+
+	IncidentSourcePagerDuty = "pagerduty"
+	IncidentSourceOpsGenie  = "opsgenie"
+	IncidentSourceManual    = "manual"
+)
+
+var IncidentSources = []string{
+	IncidentSourcePagerDuty,
+	IncidentSourceOpsGenie,
+	IncidentSourceManual,
+}
+
+//nolint: deadcode, megacheck, unused
+var (
+	incidentSubscriptionIDKey = bsonutil.MustHaveTag(Incident{}, "SubscriptionID")
+	incidentSourceKey         = bsonutil.MustHaveTag(Incident{}, "Source")
+	incidentExternalIDKey     = bsonutil.MustHaveTag(Incident{}, "ExternalID")
+	incidentCreatedAtKey      = bsonutil.MustHaveTag(Incident{}, "CreatedAt")
+	incidentResolvedAtKey     = bsonutil.MustHaveTag(Incident{}, "ResolvedAt")
+)
+
+// Incident groups related notifications. While an incident is open, new
+// notifications generated for the same subscription are attached to it.
+type Incident struct {
+	ID             string `bson:"_id"`
+	SubscriptionID string `bson:"subscription_id"`
+	// Source is the system the incident was created in, and ExternalID
+	// is the incident's identifier in that system
+	Source     string    `bson:"source"`
+	ExternalID string    `bson:"external_id,omitempty"`
+	Title      string    `bson:"title,omitempty"`
+	CreatedAt  time.Time `bson:"created_at"`
+	ResolvedAt time.Time `bson:"resolved_at,omitempty"`
+}
+
+// NewIncident returns an open incident for the subscription that generated
+// the given notification.
+func NewIncident(source, externalID, title string, n *Notification) (*Incident, error) {
+	if !util.StringSliceContains(IncidentSources, source) {
+		return nil, errors.Errorf("'%s' is not a valid incident source", source)
+	}
+	if n == nil {
+		return nil, errors.New("cannot create incident from nil notification")
+	}
+	if len(n.SubscriptionID) == 0 {
+		return nil, errors.Errorf("notification '%s' has no subscription", n.ID)
+	}
+
+	return &Incident{
+		ID:             bson.NewObjectId().Hex(),
+		SubscriptionID: n.SubscriptionID,
+		Source:         source,
+		ExternalID:     externalID,
+		Title:          title,
+		CreatedAt:      time.Now().Truncate(time.Millisecond),
+	}, nil
+}
+
+func (i *Incident) Insert() error {
+	return errors.Wrap(db.Insert(IncidentsCollection, i), "failed to insert incident")
+}
+
+func (i *Incident) IsResolved() bool {
+	return !i.ResolvedAt.IsZero()
+}
+
+// Resolve closes the incident so that no further notifications are
+// attached to it.
+func (i *Incident) Resolve() error {
+	if i.IsResolved() {
+		return errors.Errorf("incident '%s' is already resolved", i.ID)
+	}
+
+	resolvedAt := time.Now().Truncate(time.Millisecond)
+	update := bson.M{
+		"$set": bson.M{
+			incidentResolvedAtKey: resolvedAt,
+		},
+	}
+	if err := db.UpdateId(IncidentsCollection, i.ID, update); err != nil {
+		return errors.Wrap(err, "failed to resolve incident")
+	}
+	i.ResolvedAt = resolvedAt
+
+	return nil
+}
+
+func FindIncident(id string) (*Incident, error) {
+	incident := Incident{}
+	err := db.FindOneQ(IncidentsCollection, db.Query(bson.M{
+		idKey: id,
+	}), &incident)
+
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &incident, err
+}
+
+// FindOpenIncidentBySubscription returns the most recently created incident
+// for the subscription that has not been resolved, if any.
+func FindOpenIncidentBySubscription(subscriptionID string) (*Incident, error) {
+	incident := Incident{}
+	err := db.FindOneQ(IncidentsCollection, db.Query(bson.M{
+		incidentSubscriptionIDKey: subscriptionID,
+		incidentResolvedAtKey: bson.M{
+			"$exists": false,
+		},
+	}).Sort([]string{"-" + incidentCreatedAtKey}), &incident)
+
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &incident, err
+}
+
+// FindByIncident returns the notifications attached to the incident.
+func FindByIncident(incidentID string) ([]Notification, error) {
+	notifications := []Notification{}
+	err := db.FindAllQ(Collection, db.Query(bson.M{
+		incidentIDKey: incidentID,
+	}).Sort([]string{sentAtKey}), &notifications)
+
+	return notifications, errors.Wrapf(err, "failed to find notifications for incident '%s'", incidentID)
+}
+
+// LinkIncident attaches the notification to the incident.
+func (n *Notification) LinkIncident(incidentID string) error {
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			incidentIDKey: incidentID,
+		},
+	}
+	if err := db.UpdateId(Collection, n.ID, update); err != nil {
+		return errors.Wrap(err, "failed to link notification to incident")
+	}
+	n.IncidentID = incidentID
+
+	return nil
+}
+
+// AttachToOpenIncident attaches a notification that has not yet been
+// inserted to the open incident for its subscription, if there is one.
+func (n *Notification) AttachToOpenIncident() error {
+	if len(n.SubscriptionID) == 0 || len(n.IncidentID) != 0 {
+		return nil
+	}
+
+	incident, err := FindOpenIncidentBySubscription(n.SubscriptionID)
+	if err != nil {
+		return errors.Wrapf(err, "failed to find open incident for subscription '%s'", n.SubscriptionID)
+	}
+	if incident != nil {
+		n.IncidentID = incident.ID
+	}
+
+	return nil
+}
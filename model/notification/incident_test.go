@@ -0,0 +1,126 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type incidentSuite struct {
+	suite.Suite
+
+	n Notification
+}
+
+func TestIncidents(t *testing.T) {
+	suite.Run(t, &incidentSuite{})
+}
+
+func (s *incidentSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *incidentSuite) SetupTest() {
+	s.NoError(db.ClearCollections(Collection, IncidentsCollection))
+
+	str := "#general"
+	s.n = Notification{
+		ID: "n0",
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: &str,
+		},
+		Payload: &SlackPayload{
+			Body: "hi",
+		},
+		SubscriptionID: "sub",
+	}
+	s.NoError(InsertMany(s.n))
+}
+
+func (s *incidentSuite) TestNewIncident() {
+	incident, err := NewIncident("nagios", "", "", &s.n)
+	s.Error(err)
+	s.Nil(incident)
+
+	incident, err = NewIncident(IncidentSourceManual, "", "", nil)
+	s.Error(err)
+	s.Nil(incident)
+
+	s.n.SubscriptionID = ""
+	incident, err = NewIncident(IncidentSourceManual, "", "", &s.n)
+	s.Error(err)
+	s.Nil(incident)
+
+	s.n.SubscriptionID = "sub"
+	incident, err = NewIncident(IncidentSourcePagerDuty, "PD123", "it's broken", &s.n)
+	s.NoError(err)
+	s.Require().NotNil(incident)
+	s.NotEmpty(incident.ID)
+	s.Equal("sub", incident.SubscriptionID)
+	s.Equal("PD123", incident.ExternalID)
+	s.False(incident.IsResolved())
+}
+
+func (s *incidentSuite) TestLinkAndResolve() {
+	incident, err := NewIncident(IncidentSourceManual, "", "", &s.n)
+	s.Require().NoError(err)
+	s.NoError(incident.Insert())
+
+	s.NoError(s.n.LinkIncident(incident.ID))
+	s.Equal(incident.ID, s.n.IncidentID)
+
+	n, err := Find(s.n.ID)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.Equal(incident.ID, n.IncidentID)
+	s.Equal("sub", n.SubscriptionID)
+
+	notifications, err := FindByIncident(incident.ID)
+	s.NoError(err)
+	s.Len(notifications, 1)
+
+	open, err := FindOpenIncidentBySubscription("sub")
+	s.NoError(err)
+	s.Require().NotNil(open)
+	s.Equal(incident.ID, open.ID)
+
+	s.NoError(incident.Resolve())
+	s.True(incident.IsResolved())
+	s.Error(incident.Resolve())
+
+	open, err = FindOpenIncidentBySubscription("sub")
+	s.NoError(err)
+	s.Nil(open)
+
+	dbIncident, err := FindIncident(incident.ID)
+	s.NoError(err)
+	s.Require().NotNil(dbIncident)
+	s.True(dbIncident.IsResolved())
+}
+
+func (s *incidentSuite) TestAttachToOpenIncident() {
+	next := Notification{
+		ID:             "n1",
+		SubscriptionID: "sub",
+	}
+	s.NoError(next.AttachToOpenIncident())
+	s.Empty(next.IncidentID)
+
+	incident, err := NewIncident(IncidentSourceOpsGenie, "", "", &s.n)
+	s.Require().NoError(err)
+	s.NoError(incident.Insert())
+
+	s.NoError(next.AttachToOpenIncident())
+	s.Equal(incident.ID, next.IncidentID)
+
+	other := Notification{
+		ID:             "n2",
+		SubscriptionID: "other-sub",
+	}
+	s.NoError(other.AttachToOpenIncident())
+	s.Empty(other.IncidentID)
+}
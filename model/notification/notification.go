@@ -16,11 +16,11 @@ import (
 	"gopkg.in/mgo.v2/bson"
 )
 
-// makeNotificationID creates a string representing the notification generated
+// MakeID creates a string representing the notification generated
 // from the given event, with the given trigger, for the given subscriber.
 // This function will produce an ID that will collide to prevent duplicate
 // notifications from being inserted
-func makeNotificationID(eventID, trigger string, subscriber *event.Subscriber) string { //nolint: interfacer
+func MakeID(eventID, trigger string, subscriber *event.Subscriber) string { //nolint: interfacer
 	return fmt.Sprintf("%s-%s-%s", eventID, trigger, subscriber.String())
 }
 
@@ -37,19 +37,93 @@ func New(eventID, trigger string, subscriber *event.Subscriber, payload interfac
 	}
 
 	return &Notification{
-		ID:         makeNotificationID(eventID, trigger, subscriber),
+		ID:         MakeID(eventID, trigger, subscriber),
 		Subscriber: *subscriber,
 		Payload:    payload,
+		CreatedAt:  time.Now().Truncate(time.Millisecond),
 	}, nil
 }
 
+const (
+	// StatusQueued notifications have not been sent yet
+	StatusQueued = "queued"
+	// StatusRetrying notifications failed to send, and will be sent again
+	StatusRetrying = "retrying"
+	// StatusSent notifications were sent successfully
+	StatusSent = "sent"
+	// StatusFailed notifications failed to send, and will not be sent again
+	StatusFailed = "failed"
+)
+
 type Notification struct {
 	ID         string           `bson:"_id"`
 	Subscriber event.Subscriber `bson:"subscriber"`
 	Payload    interface{}      `bson:"payload"`
 
-	SentAt time.Time `bson:"sent_at"`
-	Error  string    `bson:"error,omitempty"`
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+	SentAt    time.Time `bson:"sent_at"`
+	Error     string    `bson:"error,omitempty"`
+
+	// CredentialVersion identifies the sender credentials that were used
+	// to send the notification
+	CredentialVersion string `bson:"credential_version,omitempty"`
+
+	// SubscriptionID is the subscription that generated the notification,
+	// and IncidentID is the incident the notification is attached to
+	SubscriptionID string `bson:"subscription_id,omitempty"`
+	IncidentID     string `bson:"incident_id,omitempty"`
+
+	// Initiator is the user who sent the notification through the REST
+	// API, or who owns the subscription that generated it
+	Initiator string `bson:"initiator,omitempty"`
+
+	// Recipient is the user whose subscription generated the notification,
+	// whose delivery preferences apply to it, and Project is the project
+	// the notification is about
+	Recipient string `bson:"recipient,omitempty"`
+	Project   string `bson:"project,omitempty"`
+
+	// EventID is the event that the notification was generated from, which
+	// it is rendered again from if its delivery is deferred
+	EventID string `bson:"event_id,omitempty"`
+
+	// Digest is the window that the notification is batched into with
+	// others to the same subscriber, instead of being sent on its own
+	Digest string `bson:"digest,omitempty"`
+
+	// EscalationID is the escalation that the notification was sent in;
+	// acknowledging the notification stops the escalation
+	EscalationID string `bson:"escalation_id,omitempty"`
+
+	// Delivery is the outcome of delivering a webhook notification
+	Delivery *util.WebhookDeliveryStatus `bson:"delivery,omitempty"`
+
+	// SlackMessage is the message posted for a slack notification
+	SlackMessage *util.SlackMessageRef `bson:"slack_message,omitempty"`
+
+	// Attempts is the number of failed attempts to send the notification,
+	// and DeadLettered is set when no further attempts will be made
+	Attempts     int  `bson:"attempts,omitempty"`
+	DeadLettered bool `bson:"dead_lettered,omitempty"`
+
+	// IdempotencyKey is the key, scoped to the initiator, that a
+	// notification sent through the REST API was requested with. Requests
+	// with the same key send the notification once.
+	IdempotencyKey string `bson:"idempotency_key,omitempty"`
+}
+
+// Status returns the delivery state of the notification.
+func (n *Notification) Status() string {
+	switch {
+	case n.SentAt.IsZero() && n.Attempts == 0:
+		return StatusQueued
+	case n.SentAt.IsZero():
+		return StatusRetrying
+	case n.Error != "":
+		return StatusFailed
+	default:
+		return StatusSent
+	}
 }
 
 // SenderKey returns an evergreen.SenderKey to get a grip sender for this
@@ -74,6 +148,12 @@ func (n *Notification) SenderKey() (evergreen.SenderKey, error) {
 	case event.GithubPullRequestSubscriberType:
 		return evergreen.SenderGithubStatus, nil
 
+	case event.OpsgenieSubscriberType:
+		return evergreen.SenderOpsgenie, nil
+
+	case event.GithubCheckSubscriberType:
+		return evergreen.SenderGithubCheck, nil
+
 	default:
 		return evergreen.SenderEmail, errors.Errorf("unknown type '%s'", n.Subscriber.Type)
 	}
@@ -107,14 +187,25 @@ func (n *Notification) Composer() (message.Composer, error) {
 			return nil, errors.New("email subscriber is invalid")
 		}
 
-		payload, ok := n.Payload.(*message.Email)
-		if !ok || payload == nil {
+		switch payload := n.Payload.(type) {
+		case *message.Email:
+			if payload == nil {
+				return nil, errors.New("email payload is invalid")
+			}
+			payload.Recipients = []string{*sub}
+			return message.NewEmailMessage(level.Notice, *payload), nil
+
+		case *util.EvergreenEmail:
+			if payload == nil {
+				return nil, errors.New("email payload is invalid")
+			}
+			payload.Recipients = []string{*sub}
+			return util.NewEmailMessage(level.Notice, *payload), nil
+
+		default:
 			return nil, errors.New("email payload is invalid")
 		}
 
-		payload.Recipients = []string{*sub}
-		return message.NewEmailMessage(level.Notice, *payload), nil
-
 	case event.JIRAIssueSubscriberType:
 		jiraIssue, ok := n.Subscriber.Target.(*event.JIRAIssueSubscriber)
 		if !ok {
@@ -154,7 +245,14 @@ func (n *Notification) Composer() (message.Composer, error) {
 			return nil, errors.New("slack payload is invalid")
 		}
 
-		return message.NewSlackMessage(level.Notice, *sub, payload.Body, payload.Attachments), nil
+		return util.NewSlackMessageWithStruct(level.Notice, util.EvergreenSlack{
+			Target:      *sub,
+			Msg:         payload.Body,
+			Attachments: payload.Attachments,
+			Blocks:      payload.Blocks,
+			ThreadTS:    payload.ThreadTS,
+			UpdateTS:    payload.UpdateTS,
+		}), nil
 
 	case event.GithubPullRequestSubscriberType:
 		sub := n.Subscriber.Target.(*event.GithubPullRequestSubscriber)
@@ -168,6 +266,39 @@ func (n *Notification) Composer() (message.Composer, error) {
 
 		return message.NewGithubStatusMessageWithRepo(level.Notice, *payload), nil
 
+	case event.OpsgenieSubscriberType:
+		sub, ok := n.Subscriber.Target.(*event.OpsgenieSubscriber)
+		if !ok {
+			return nil, errors.New("opsgenie subscriber is invalid")
+		}
+
+		payload, ok := n.Payload.(*util.OpsgenieAlert)
+		if !ok || payload == nil {
+			return nil, errors.New("opsgenie payload is invalid")
+		}
+
+		payload.APIKey = sub.APIKey
+		payload.Team = sub.Team
+
+		return util.NewOpsgenieMessage(level.Notice, *payload), nil
+
+	case event.GithubCheckSubscriberType:
+		sub, ok := n.Subscriber.Target.(*event.GithubCheckSubscriber)
+		if !ok {
+			return nil, errors.New("github-check subscriber is invalid")
+		}
+
+		payload, ok := n.Payload.(*util.GithubCheckRun)
+		if !ok || payload == nil {
+			return nil, errors.New("github-check payload is invalid")
+		}
+
+		payload.Owner = sub.Owner
+		payload.Repo = sub.Repo
+		payload.HeadSHA = sub.Ref
+
+		return util.NewGithubCheckRunMessage(level.Notice, *payload), nil
+
 	default:
 		return nil, errors.Errorf("unknown type '%s'", n.Subscriber.Type)
 	}
@@ -193,6 +324,141 @@ func (n *Notification) MarkSent() error {
 	return nil
 }
 
+// SetPayload replaces the notification's payload, when it is rendered again
+// before it is sent
+func (n *Notification) SetPayload(payload interface{}) error {
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+	if payload == nil {
+		return errors.New("cannot set a nil payload")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			payloadKey: payload,
+		},
+	}
+
+	if err := db.UpdateId(Collection, n.ID, update); err != nil {
+		return errors.Wrap(err, "failed to set payload on notification")
+	}
+	n.Payload = payload
+
+	return nil
+}
+
+// SetCredentialVersion records the version of the sender credentials that
+// were used to send the notification
+func (n *Notification) SetCredentialVersion(version string) error {
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			credentialVersionKey: version,
+		},
+	}
+
+	if err := db.UpdateId(Collection, n.ID, update); err != nil {
+		return errors.Wrap(err, "failed to set credential version on notification")
+	}
+	n.CredentialVersion = version
+
+	return nil
+}
+
+// SetDelivery records the outcome of delivering the notification
+func (n *Notification) SetDelivery(delivery *util.WebhookDeliveryStatus) error {
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			deliveryKey: delivery,
+		},
+	}
+
+	if err := db.UpdateId(Collection, n.ID, update); err != nil {
+		return errors.Wrap(err, "failed to set delivery status on notification")
+	}
+	n.Delivery = delivery
+
+	return nil
+}
+
+// SetSlackMessage records the message posted for a slack notification
+func (n *Notification) SetSlackMessage(posted *util.SlackMessageRef) error {
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			slackMessageKey: posted,
+		},
+	}
+
+	if err := db.UpdateId(Collection, n.ID, update); err != nil {
+		return errors.Wrap(err, "failed to set slack message on notification")
+	}
+	n.SlackMessage = posted
+
+	return nil
+}
+
+// MarkAttemptFailed records a failed attempt to send the notification,
+// without marking it as sent, so that it can be sent again
+func (n *Notification) MarkAttemptFailed(sendErr error) error {
+	if sendErr == nil {
+		return nil
+	}
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	errMsg := sendErr.Error()
+	update := bson.M{
+		"$set": bson.M{
+			errorKey: errMsg,
+		},
+		"$inc": bson.M{
+			attemptsKey: 1,
+		},
+	}
+
+	if err := db.UpdateId(Collection, n.ID, update); err != nil {
+		return errors.Wrap(err, "failed to record failed attempt on notification")
+	}
+	n.Error = errMsg
+	n.Attempts++
+
+	return nil
+}
+
+// MarkDeadLettered records that no further attempts will be made to send
+// the notification
+func (n *Notification) MarkDeadLettered() error {
+	if len(n.ID) == 0 {
+		return errors.New("notification has no ID")
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			deadLetteredKey: true,
+		},
+	}
+
+	if err := db.UpdateId(Collection, n.ID, update); err != nil {
+		return errors.Wrap(err, "failed to dead letter notification")
+	}
+	n.DeadLettered = true
+
+	return nil
+}
+
 func (n *Notification) MarkError(sendErr error) error {
 	if sendErr == nil {
 		return nil
@@ -229,6 +495,8 @@ type NotificationStats struct {
 	EvergreenWebhook  int `json:"evergreen_webhook" bson:"evergreen_webhook" yaml:"evergreen_webhook"`
 	Email             int `json:"email" bson:"email" yaml:"email"`
 	Slack             int `json:"slack" bson:"slack" yaml:"slack"`
+	Opsgenie          int `json:"opsgenie" bson:"opsgenie" yaml:"opsgenie"`
+	GithubCheck       int `json:"github_check" bson:"github_check" yaml:"github_check"`
 }
 
 func CollectUnsentNotificationStats() (*NotificationStats, error) {
@@ -282,6 +550,12 @@ func CollectUnsentNotificationStats() (*NotificationStats, error) {
 		case event.SlackSubscriberType:
 			nStats.Slack = data.Count
 
+		case event.OpsgenieSubscriberType:
+			nStats.Opsgenie = data.Count
+
+		case event.GithubCheckSubscriberType:
+			nStats.GithubCheck = data.Count
+
 		default:
 			grip.Error(message.Fields{
 				"message": fmt.Sprintf("unknown subscriber %s", data.Key),
@@ -6,11 +6,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -179,6 +181,39 @@ func (s *notificationSuite) TestInsertMany() {
 	}
 }
 
+func (s *notificationSuite) TestInsertIdempotent() {
+	s.n.ID = "1"
+	_, _, err := InsertIdempotent(&s.n)
+	s.Error(err)
+
+	s.n.IdempotencyKey = "user/key"
+	n, inserted, err := InsertIdempotent(&s.n)
+	s.NoError(err)
+	s.True(inserted)
+	s.Equal("1", n.ID)
+
+	n2 := s.n
+	n2.ID = "2"
+	n, inserted, err = InsertIdempotent(&n2)
+	s.NoError(err)
+	s.False(inserted)
+	s.Require().NotNil(n)
+	s.Equal("1", n.ID)
+
+	n3 := s.n
+	n3.ID = "3"
+	n3.IdempotencyKey = "other/key"
+	n, inserted, err = InsertIdempotent(&n3)
+	s.NoError(err)
+	s.True(inserted)
+	s.Equal("3", n.ID)
+
+	n, err = FindByIdempotencyKey("user/key")
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.Equal("1", n.ID)
+}
+
 func (s *notificationSuite) TestWebhookPayload() {
 	jsonData := `{"iama": "potato"}`
 	s.n.ID = "1"
@@ -267,14 +302,16 @@ func (s *notificationSuite) TestEmailPayload() {
 	s.n.Subscriber.Type = event.EmailSubscriberType
 	email := "a@a.a"
 	s.n.Subscriber.Target = &email
-	s.n.Payload = &message.Email{
-		Headers: map[string][]string{
-			"8":  []string{"9"},
-			"10": []string{"11"},
+	s.n.Payload = &util.EvergreenEmail{
+		Email: message.Email{
+			Headers: map[string][]string{
+				"8":  []string{"9"},
+				"10": []string{"11"},
+			},
+			Subject:    "subject",
+			Body:       "body",
+			Recipients: []string{},
 		},
-		Subject:    "subject",
-		Body:       "body",
-		Recipients: []string{},
 	}
 
 	s.NoError(InsertMany(s.n))
@@ -289,7 +326,7 @@ func (s *notificationSuite) TestEmailPayload() {
 	s.NoError(err)
 	s.Require().NotNil(c)
 
-	_, ok := c.Raw().(*message.Email)
+	_, ok := c.Raw().(*util.EvergreenEmail)
 	s.True(ok)
 	s.True(c.Loggable())
 }
@@ -318,6 +355,117 @@ func (s *notificationSuite) TestSlackPayload() {
 	s.True(c.Loggable())
 }
 
+func (s *notificationSuite) TestSlackPayloadWithBlocks() {
+	s.n.ID = "1"
+	s.n.Subscriber.Type = event.SlackSubscriberType
+	slack := "#general"
+	s.n.Subscriber.Target = &slack
+	s.n.Payload = &SlackPayload{
+		Body:        "Task failed",
+		Attachments: []message.SlackAttachment{},
+		Blocks: []util.SlackBlock{
+			{Type: util.SlackBlockSection, Text: &util.SlackText{Type: util.SlackTextMarkdown, Text: "*Task failed*"}},
+			{Type: util.SlackBlockDivider},
+			{Type: util.SlackBlockActions, Elements: []util.SlackBlockElement{
+				{Type: util.SlackElementButton, Text: "View logs", URL: "https://example.com/logs"},
+			}},
+		},
+	}
+
+	s.NoError(InsertMany(s.n))
+
+	n, err := Find(s.n.ID)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.Equal(s.n, *n)
+
+	c, err := n.Composer()
+	s.NoError(err)
+	s.Require().NotNil(c)
+	s.True(c.Loggable())
+	msg, ok := c.Raw().(*util.EvergreenSlack)
+	s.Require().True(ok)
+	s.Equal("#general", msg.Target)
+	s.Len(msg.Blocks, 3)
+}
+
+func (s *notificationSuite) TestOpsgeniePayload() {
+	s.n.ID = "1"
+	s.n.Subscriber.Type = event.OpsgenieSubscriberType
+	s.n.Subscriber.Target = event.OpsgenieSubscriber{
+		APIKey: "key",
+		Team:   "evergreen",
+	}
+	s.n.Payload = &util.OpsgenieAlert{
+		Message:  "task failed",
+		Alias:    "evergreen-sub-task-1",
+		Priority: util.OpsgeniePriorityModerate,
+	}
+
+	s.NoError(InsertMany(s.n))
+
+	n, err := Find(s.n.ID)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.Equal("evergreen-sub-task-1", n.Payload.(*util.OpsgenieAlert).Alias)
+
+	c, err := n.Composer()
+	s.NoError(err)
+	s.Require().NotNil(c)
+	s.True(c.Loggable())
+	alert, ok := c.Raw().(*util.OpsgenieAlert)
+	s.Require().True(ok)
+	s.Equal("key", alert.APIKey)
+	s.Equal("evergreen", alert.Team)
+
+	key, err := n.SenderKey()
+	s.NoError(err)
+	s.Equal(evergreen.SenderOpsgenie, key)
+}
+
+func (s *notificationSuite) TestGithubCheckPayload() {
+	s.n.ID = "1"
+	s.n.Subscriber.Type = event.GithubCheckSubscriberType
+	s.n.Subscriber.Target = event.GithubCheckSubscriber{
+		Owner: "evergreen-ci",
+		Repo:  "evergreen",
+		Ref:   "abcdef",
+	}
+	s.n.Payload = &util.GithubCheckRun{
+		Name:       "evergreen/ubuntu",
+		ExternalID: "build1",
+		Conclusion: util.GithubCheckConclusionFailure,
+		Output: util.GithubCheckRunOutput{
+			Title:   "build failed",
+			Summary: "1 failed",
+			Annotations: []util.GithubCheckRunAnnotation{
+				{Path: "main.go", StartLine: 3, EndLine: 3, AnnotationLevel: util.GithubAnnotationLevelFailure, Message: "main.go failed"},
+			},
+		},
+	}
+
+	s.NoError(InsertMany(s.n))
+
+	n, err := Find(s.n.ID)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.Len(n.Payload.(*util.GithubCheckRun).Output.Annotations, 1)
+
+	c, err := n.Composer()
+	s.NoError(err)
+	s.Require().NotNil(c)
+	s.True(c.Loggable())
+	run, ok := c.Raw().(*util.GithubCheckRun)
+	s.Require().True(ok)
+	s.Equal("evergreen-ci", run.Owner)
+	s.Equal("evergreen", run.Repo)
+	s.Equal("abcdef", run.HeadSHA)
+
+	key, err := n.SenderKey()
+	s.NoError(err)
+	s.Equal(evergreen.SenderGithubCheck, key)
+}
+
 func (s *notificationSuite) TestGithubPayload() {
 	s.n.ID = "1"
 	s.n.Subscriber.Type = event.GithubPullRequestSubscriberType
@@ -373,3 +521,28 @@ func (s *notificationSuite) TestCollectUnsentNotificationStats() {
 		s.Equal(1, int(f.Int()))
 	}
 }
+
+func TestNotificationSetBSON(t *testing.T) {
+	assert := assert.New(t)
+
+	target := "me@example.com"
+	n := Notification{
+		ID:           "1",
+		Subscriber:   event.Subscriber{Type: event.EmailSubscriberType, Target: &target},
+		Payload:      &message.Email{Subject: "hi"},
+		CreatedAt:    time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC),
+		Delivery:     &util.WebhookDeliveryStatus{Attempts: 2},
+		Attempts:     3,
+		DeadLettered: true,
+	}
+	raw, err := bson.Marshal(&n)
+	assert.NoError(err)
+
+	out := Notification{}
+	assert.NoError(bson.Unmarshal(raw, &out))
+	assert.True(n.CreatedAt.Equal(out.CreatedAt))
+	assert.Equal(n.Delivery, out.Delivery)
+	assert.Equal(3, out.Attempts)
+	assert.True(out.DeadLettered)
+	assert.Equal("hi", out.Payload.(*util.EvergreenEmail).Subject)
+}
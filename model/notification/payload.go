@@ -1,8 +1,22 @@
 package notification
 
-import "github.com/mongodb/grip/message"
+import (
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/message"
+)
 
 type SlackPayload struct {
 	Body        string                    `bson:"body"`
 	Attachments []message.SlackAttachment `bson:"attachments"`
+	// Blocks are Block Kit blocks, which are rendered in place of the
+	// body, which is then only used as the notification text
+	Blocks []util.SlackBlock `bson:"blocks,omitempty"`
+
+	// ThreadKey groups notifications about the same subject, such as a
+	// version, into one thread: the first notification sent to the target
+	// with the key starts the thread, and later ones reply in it. ThreadTS
+	// and UpdateTS instead reply to or replace a specific message.
+	ThreadKey string `bson:"thread_key,omitempty"`
+	ThreadTS  string `bson:"thread_ts,omitempty"`
+	UpdateTS  string `bson:"update_ts,omitempty"`
 }
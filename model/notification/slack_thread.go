@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const SlackThreadsCollection = "notification_slack_threads"
+
+//nolint: deadcode, megacheck, unused
+var (
+	slackThreadTargetKey    = bsonutil.MustHaveTag(SlackThread{}, "Target")
+	slackThreadKeyKey       = bsonutil.MustHaveTag(SlackThread{}, "Key")
+	slackThreadChannelKey   = bsonutil.MustHaveTag(SlackThread{}, "Channel")
+	slackThreadTSKey        = bsonutil.MustHaveTag(SlackThread{}, "TS")
+	slackThreadCreatedAtKey = bsonutil.MustHaveTag(SlackThread{}, "CreatedAt")
+)
+
+// SlackThread is the message that started the thread for a thread key in a
+// slack channel. Later notifications with the same target and key are
+// posted as replies to it.
+type SlackThread struct {
+	ID     string `bson:"_id"`
+	Target string `bson:"target"`
+	Key    string `bson:"key"`
+	// Channel and TS identify the message that started the thread
+	Channel   string    `bson:"channel"`
+	TS        string    `bson:"ts"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func slackThreadID(target, key string) string {
+	return target + "/" + key
+}
+
+// NewSlackThread returns a thread started by the message posted to the
+// target.
+func NewSlackThread(target, key, channel, ts string) *SlackThread {
+	return &SlackThread{
+		ID:        slackThreadID(target, key),
+		Target:    target,
+		Key:       key,
+		Channel:   channel,
+		TS:        ts,
+		CreatedAt: time.Now().Truncate(time.Millisecond),
+	}
+}
+
+// Insert saves the thread. Inserting a thread for a target and key that
+// already has one is not an error, and keeps the existing thread, so that
+// notifications racing to start a thread end up in the same one.
+func (t *SlackThread) Insert() error {
+	err := db.Insert(SlackThreadsCollection, t)
+	if db.IsDuplicateKey(err) {
+		return nil
+	}
+
+	return errors.Wrap(err, "failed to insert slack thread")
+}
+
+// FindSlackThread returns the thread for the key in the target, if any.
+func FindSlackThread(target, key string) (*SlackThread, error) {
+	thread := SlackThread{}
+	err := db.FindOneQ(SlackThreadsCollection, db.Query(bson.M{
+		idKey: slackThreadID(target, key),
+	}), &thread)
+
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &thread, errors.Wrapf(err, "failed to find slack thread '%s' in '%s'", key, target)
+}
@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type slackThreadSuite struct {
+	suite.Suite
+}
+
+func TestSlackThreads(t *testing.T) {
+	suite.Run(t, &slackThreadSuite{})
+}
+
+func (s *slackThreadSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func (s *slackThreadSuite) SetupTest() {
+	s.NoError(db.ClearCollections(Collection, SlackThreadsCollection))
+}
+
+func (s *slackThreadSuite) TestFindAndInsert() {
+	thread, err := FindSlackThread("#general", "version1")
+	s.NoError(err)
+	s.Nil(thread)
+
+	s.NoError(NewSlackThread("#general", "version1", "C123", "1.1").Insert())
+	s.NoError(NewSlackThread("#other", "version1", "C456", "2.1").Insert())
+
+	// a racing notification keeps the existing thread
+	s.NoError(NewSlackThread("#general", "version1", "C123", "1.2").Insert())
+
+	thread, err = FindSlackThread("#general", "version1")
+	s.NoError(err)
+	s.Require().NotNil(thread)
+	s.Equal("#general", thread.Target)
+	s.Equal("version1", thread.Key)
+	s.Equal("C123", thread.Channel)
+	s.Equal("1.1", thread.TS)
+
+	thread, err = FindSlackThread("#other", "version1")
+	s.NoError(err)
+	s.Require().NotNil(thread)
+	s.Equal("2.1", thread.TS)
+}
+
+func (s *slackThreadSuite) TestSetSlackMessage() {
+	target := "#general"
+	n := Notification{
+		ID: "n0",
+		Subscriber: event.Subscriber{
+			Type:   event.SlackSubscriberType,
+			Target: &target,
+		},
+		Payload: &SlackPayload{
+			Body:      "version failed",
+			ThreadKey: "version1",
+		},
+	}
+	s.NoError(InsertMany(n))
+
+	posted := &util.SlackMessageRef{Channel: "C123", TS: "1.1"}
+	s.NoError(n.SetSlackMessage(posted))
+	s.Equal(posted, n.SlackMessage)
+
+	found, err := Find(n.ID)
+	s.NoError(err)
+	s.Require().NotNil(found)
+	s.Equal(posted, found.SlackMessage)
+	s.Equal("version1", found.Payload.(*SlackPayload).ThreadKey)
+
+	n.ID = ""
+	s.Error(n.SetSlackMessage(posted))
+}
@@ -0,0 +1,224 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	ttemplate "text/template"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	TemplatesCollection = "notification_templates"
+)
+
+//nolint: deadcode, megacheck, unused
+var (
+	templateNameKey      = bsonutil.MustHaveTag(Template{}, "Name")
+	templateSubjectKey   = bsonutil.MustHaveTag(Template{}, "Subject")
+	templateBodyKey      = bsonutil.MustHaveTag(Template{}, "Body")
+	templateUpdatedAtKey = bsonutil.MustHaveTag(Template{}, "UpdatedAt")
+)
+
+// Template is a stored notification template. The subject and body are Go
+// templates, which are rendered with a data map when a notification is
+// created from the template. Bodies of email notifications are HTML escaped,
+// and are sent with a plain-text alternative generated from the HTML; all
+// other bodies are rendered as text, such as Slack markdown or JIRA markup.
+type Template struct {
+	Name      string    `bson:"_id"`
+	Subject   string    `bson:"subject,omitempty"`
+	Body      string    `bson:"body"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+// templateFuncs are the helpers available to templates, which take the same
+// arguments as their sprig equivalents.
+var templateFuncs = map[string]interface{}{
+	"upper":    strings.ToUpper,
+	"lower":    strings.ToLower,
+	"title":    strings.Title,
+	"trim":     strings.TrimSpace,
+	"replace":  func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+	"contains": func(substr, s string) bool { return strings.Contains(s, substr) },
+	"join":     joinTemplateValues,
+	"trunc":    truncateTemplateString,
+	"default":  defaultTemplateValue,
+	"date":     formatTemplateDate,
+}
+
+func joinTemplateValues(sep string, elems []interface{}) string {
+	out := make([]string, 0, len(elems))
+	for _, e := range elems {
+		out = append(out, fmt.Sprint(e))
+	}
+	return strings.Join(out, sep)
+}
+
+func truncateTemplateString(length int, s string) string {
+	if length < 0 || len(s) <= length {
+		return s
+	}
+	return s[:length]
+}
+
+// defaultTemplateValue returns the value, or def if the value is missing or
+// empty.
+func defaultTemplateValue(def interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || value[0] == nil {
+		return def
+	}
+	if s, ok := value[0].(string); ok && s == "" {
+		return def
+	}
+	return value[0]
+}
+
+// formatTemplateDate formats a time, or a string holding an RFC3339 time.
+func formatTemplateDate(layout string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", errors.Wrapf(err, "'%s' is not an RFC3339 time", v)
+		}
+		return t.Format(layout), nil
+	default:
+		return "", errors.Errorf("cannot format %T as a date", value)
+	}
+}
+
+// Validate checks that the template has a name and a body, and that the
+// subject and body parse.
+func (t *Template) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if t.Name == "" {
+		catcher.Add(errors.New("name: cannot be empty"))
+	}
+	if t.Body == "" {
+		catcher.Add(errors.New("body: cannot be empty"))
+	}
+	if _, err := ttemplate.New("subject").Funcs(templateFuncs).Parse(t.Subject); err != nil {
+		catcher.Add(errors.Wrap(err, "subject"))
+	}
+	if _, err := htmltemplate.New("body").Funcs(templateFuncs).Parse(t.Body); err != nil {
+		catcher.Add(errors.Wrap(err, "body"))
+	}
+
+	return catcher.Resolve()
+}
+
+// Render renders the subject and body of the template with the data. The
+// body is HTML escaped if html is true.
+func (t *Template) Render(data map[string]interface{}, html bool) (string, string, error) {
+	subjectTmpl, err := ttemplate.New("subject").Funcs(templateFuncs).Option("missingkey=zero").Parse(t.Subject)
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to parse subject of template '%s'", t.Name)
+	}
+	subject := &bytes.Buffer{}
+	if err = subjectTmpl.Execute(subject, data); err != nil {
+		return "", "", errors.Wrapf(err, "failed to render subject of template '%s'", t.Name)
+	}
+
+	body := &bytes.Buffer{}
+	if html {
+		var bodyTmpl *htmltemplate.Template
+		bodyTmpl, err = htmltemplate.New("body").Funcs(templateFuncs).Option("missingkey=zero").Parse(t.Body)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to parse body of template '%s'", t.Name)
+		}
+		err = bodyTmpl.Execute(body, data)
+	} else {
+		var bodyTmpl *ttemplate.Template
+		bodyTmpl, err = ttemplate.New("body").Funcs(templateFuncs).Option("missingkey=zero").Parse(t.Body)
+		if err != nil {
+			return "", "", errors.Wrapf(err, "failed to parse body of template '%s'", t.Name)
+		}
+		err = bodyTmpl.Execute(body, data)
+	}
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to render body of template '%s'", t.Name)
+	}
+
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
+
+// Payload renders the template with the data into a payload for a
+// notification to a subscriber of the given type.
+func (t *Template) Payload(subscriberType string, data map[string]interface{}) (interface{}, error) {
+	subject, body, err := t.Render(data, subscriberType == event.EmailSubscriberType)
+	if err != nil {
+		return nil, err
+	}
+
+	switch subscriberType {
+	case event.EmailSubscriberType:
+		return &util.EvergreenEmail{
+			Email: message.Email{
+				Subject: subject,
+			},
+			HTMLBody: body,
+		}, nil
+
+	case event.SlackSubscriberType:
+		return &SlackPayload{
+			Body: body,
+		}, nil
+
+	case event.JIRAIssueSubscriberType:
+		return &message.JiraIssue{
+			Summary:     subject,
+			Description: body,
+		}, nil
+
+	case event.JIRACommentSubscriberType:
+		return &body, nil
+
+	default:
+		return nil, errors.Errorf("templates cannot be sent to '%s' subscribers", subscriberType)
+	}
+}
+
+// Upsert saves the template, replacing any template with the same name.
+func (t *Template) Upsert() error {
+	t.UpdatedAt = time.Now().Truncate(time.Millisecond)
+	_, err := db.Upsert(TemplatesCollection, bson.M{
+		templateNameKey: t.Name,
+	}, bson.M{
+		"$set": bson.M{
+			templateSubjectKey:   t.Subject,
+			templateBodyKey:      t.Body,
+			templateUpdatedAtKey: t.UpdatedAt,
+		},
+	})
+
+	return errors.Wrapf(err, "failed to save template '%s'", t.Name)
+}
+
+// FindTemplate returns the template with the given name, or nil if there is
+// no such template.
+func FindTemplate(name string) (*Template, error) {
+	t := Template{}
+	err := db.FindOneQ(TemplatesCollection, db.Query(bson.M{
+		templateNameKey: name,
+	}), &t)
+
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &t, errors.Wrapf(err, "failed to fetch template '%s'", name)
+}
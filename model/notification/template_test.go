@@ -0,0 +1,81 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplatePayload(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl := Template{
+		Name:    "deploy",
+		Subject: `{{ .project | upper }} deployed {{ trunc 7 .revision }}`,
+		Body:    `*{{ .author | default "someone" }}* deployed "{{ .message }}" to {{ join ", " .hosts }}`,
+	}
+	assert.NoError(tmpl.Validate())
+	data := map[string]interface{}{
+		"project":  "mci",
+		"revision": "0123456789abcdef",
+		"message":  "<fix> & cleanup",
+		"hosts":    []interface{}{"h1", "h2"},
+	}
+
+	payload, err := tmpl.Payload(event.EmailSubscriberType, data)
+	assert.NoError(err)
+	email, ok := payload.(*util.EvergreenEmail)
+	assert.True(ok)
+	assert.Equal("MCI deployed 0123456", email.Subject)
+	assert.Equal(`*someone* deployed "&lt;fix&gt; &amp; cleanup" to h1, h2`, email.HTMLBody)
+	assert.Empty(email.Body)
+
+	payload, err = tmpl.Payload(event.SlackSubscriberType, data)
+	assert.NoError(err)
+	slack, ok := payload.(*SlackPayload)
+	assert.True(ok)
+	assert.Equal(`*someone* deployed "<fix> & cleanup" to h1, h2`, slack.Body)
+
+	payload, err = tmpl.Payload(event.JIRAIssueSubscriberType, data)
+	assert.NoError(err)
+	issue, ok := payload.(*message.JiraIssue)
+	assert.True(ok)
+	assert.Equal("MCI deployed 0123456", issue.Summary)
+	assert.Equal(slack.Body, issue.Description)
+
+	payload, err = tmpl.Payload(event.JIRACommentSubscriberType, data)
+	assert.NoError(err)
+	comment, ok := payload.(*string)
+	assert.True(ok)
+	assert.Equal(slack.Body, *comment)
+
+	_, err = tmpl.Payload(event.EvergreenWebhookSubscriberType, data)
+	assert.EqualError(err, "templates cannot be sent to 'evergreen-webhook' subscribers")
+
+	tmpl.Subject = ""
+	tmpl.Body = `{{ date "2006-01-02" .time }}`
+	_, err = tmpl.Payload(event.SlackSubscriberType, map[string]interface{}{"time": "yesterday"})
+	assert.Error(err)
+	payload, err = tmpl.Payload(event.SlackSubscriberType, map[string]interface{}{"time": "2018-06-01T12:00:00Z"})
+	assert.NoError(err)
+	assert.Equal("2018-06-01", payload.(*SlackPayload).Body)
+}
+
+func TestTemplateValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl := Template{}
+	err := tmpl.Validate()
+	assert.Error(err)
+	assert.Contains(err.Error(), "name: cannot be empty")
+	assert.Contains(err.Error(), "body: cannot be empty")
+
+	tmpl = Template{Name: "broken", Subject: "{{ .a", Body: "{{ unknown .b }}"}
+	err = tmpl.Validate()
+	assert.Error(err)
+	assert.Contains(err.Error(), "subject")
+	assert.Contains(err.Error(), "body")
+}
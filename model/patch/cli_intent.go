@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -63,6 +64,13 @@ type cliIntent struct {
 
 	// alias defines the variants and tasks to run this patch on.
 	Alias string `bson:"alias"`
+
+	// Modules are the patches of the project's modules, at their revisions
+	// and with their diffs, copied from a previous patch.
+	Modules []ModulePatch `bson:"modules,omitempty"`
+
+	// Parameters are the values given for the project's parameters.
+	Parameters []version.Parameter `bson:"parameters,omitempty"`
 }
 
 // BSON fields for the patches
@@ -83,16 +91,21 @@ var (
 	cliProcessedAtKey   = bsonutil.MustHaveTag(cliIntent{}, "ProcessedAt")
 	cliIntentTypeKey    = bsonutil.MustHaveTag(cliIntent{}, "IntentType")
 	cliAliasKey         = bsonutil.MustHaveTag(cliIntent{}, "Alias")
+	cliModulesKey       = bsonutil.MustHaveTag(cliIntent{}, "Modules")
+	cliParametersKey    = bsonutil.MustHaveTag(cliIntent{}, "Parameters")
 )
 
 func (c *cliIntent) Insert() error {
-	patchFileID := bson.NewObjectId()
-	if err := db.WriteGridFile(GridFSPrefix, patchFileID.Hex(), strings.NewReader(c.PatchContent)); err != nil {
-		return err
+	// patches uploaded as a stream are already in gridfs
+	if c.PatchFileID == "" {
+		patchFileID := bson.NewObjectId()
+		if err := db.WriteGridFile(GridFSPrefix, patchFileID.Hex(), strings.NewReader(c.PatchContent)); err != nil {
+			return err
+		}
+		c.PatchFileID = patchFileID
 	}
 
 	c.PatchContent = ""
-	c.PatchFileID = patchFileID
 	c.CreatedAt = time.Now().Round(time.Millisecond)
 
 	if err := db.Insert(IntentCollection, c); err != nil {
@@ -137,7 +150,7 @@ func (g *cliIntent) RequesterIdentity() string {
 
 // NewPatch creates a patch from the intent
 func (c *cliIntent) NewPatch() *Patch {
-	return &Patch{
+	p := &Patch{
 		Description:   c.Description,
 		Author:        c.User,
 		Project:       c.ProjectID,
@@ -146,6 +159,7 @@ func (c *cliIntent) NewPatch() *Patch {
 		BuildVariants: c.BuildVariants,
 		Alias:         c.Alias,
 		Tasks:         c.Tasks,
+		Parameters:    c.Parameters,
 		Patches: []ModulePatch{
 			{
 				ModuleName: c.Module,
@@ -156,6 +170,9 @@ func (c *cliIntent) NewPatch() *Patch {
 			},
 		},
 	}
+	p.Patches = append(p.Patches, c.Modules...)
+
+	return p
 }
 
 func NewCliIntent(user, project, baseHash, module, patchContent, description string, finalize bool, variants, tasks []string, alias string) (Intent, error) {
@@ -195,6 +212,50 @@ func NewCliIntent(user, project, baseHash, module, patchContent, description str
 	}, nil
 }
 
+// NewUploadedCliIntent is NewCliIntent for a patch whose diff was already
+// uploaded to gridfs with UploadPatchFile.
+func NewUploadedCliIntent(patchFileID bson.ObjectId, user, project, baseHash, module, description string, finalize bool, variants, tasks []string, alias string) (Intent, error) {
+	if !patchFileID.Valid() {
+		return nil, errors.New("no patch file provided")
+	}
+	intent, err := NewCliIntent(user, project, baseHash, module, "", description, finalize, variants, tasks, alias)
+	if err != nil {
+		return nil, err
+	}
+	intent.(*cliIntent).PatchFileID = patchFileID
+
+	return intent, nil
+}
+
+// CopyPreviousModules makes a cli intent's patch also patch the modules that
+// a previous patch did, at the same revisions and with the same diffs.
+func CopyPreviousModules(intent Intent, previous *Patch) error {
+	c, ok := intent.(*cliIntent)
+	if !ok {
+		return errors.Errorf("can't copy modules into intent of type '%s'", intent.GetType())
+	}
+	for _, modulePatch := range previous.Patches {
+		if modulePatch.ModuleName != "" && modulePatch.ModuleName != c.Module {
+			c.Modules = append(c.Modules, modulePatch)
+		}
+	}
+
+	return nil
+}
+
+// SetParameters sets the values that a cli intent's patch gives for the
+// project's parameters, which are checked against the project's definitions
+// when the patch is created.
+func SetParameters(intent Intent, params []version.Parameter) error {
+	c, ok := intent.(*cliIntent)
+	if !ok {
+		return errors.Errorf("can't set parameters of intent of type '%s'", intent.GetType())
+	}
+	c.Parameters = params
+
+	return nil
+}
+
 func (c *cliIntent) GetAlias() string {
 	return c.Alias
 }
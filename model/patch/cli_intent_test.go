@@ -1,11 +1,14 @@
 package patch
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -139,6 +142,25 @@ func (s *CliIntentSuite) TestInsert() {
 	s.Equal(intent.ID(), intents[0].DocumentID)
 }
 
+func (s *CliIntentSuite) TestInsertUploaded() {
+	patchFileID, err := UploadPatchFile(strings.NewReader(s.patchContent))
+	s.Require().NoError(err)
+
+	intent, err := NewUploadedCliIntent(patchFileID, s.user, s.projectID, s.hash, s.module, s.description, true, s.variants, s.tasks, s.alias)
+	s.NoError(err)
+	s.NotNil(intent)
+	s.NoError(intent.Insert())
+
+	var intents []*cliIntent
+	intents, err = findCliIntents(false)
+	s.NoError(err)
+	s.Require().Len(intents, 1)
+	s.Equal(patchFileID, intents[0].PatchFileID)
+
+	_, err = NewUploadedCliIntent("", s.user, s.projectID, s.hash, s.module, s.description, true, s.variants, s.tasks, s.alias)
+	s.Error(err)
+}
+
 func (s *CliIntentSuite) TestSetProcessed() {
 	intent, err := NewCliIntent(s.user, s.projectID, s.hash, s.module, s.patchContent, s.description, true, s.variants, s.tasks, s.alias)
 	s.NoError(err)
@@ -194,3 +216,39 @@ func (s *CliIntentSuite) TestNewPatch() {
 	s.Equal(s.alias, patchDoc.Alias)
 	s.Zero(patchDoc.GithubPatchData)
 }
+
+func TestCopyPreviousModules(t *testing.T) {
+	assert := assert.New(t)
+
+	intent, err := NewCliIntent("octocat", "mci", "abc", "", "diff", "desc", false, nil, nil, "")
+	assert.NoError(err)
+	previous := &Patch{
+		Patches: []ModulePatch{
+			{Githash: "def", PatchSet: PatchSet{PatchFileId: "project-diff"}},
+			{ModuleName: "enterprise", Githash: "123", PatchSet: PatchSet{PatchFileId: "enterprise-diff"}},
+		},
+	}
+	assert.NoError(CopyPreviousModules(intent, previous))
+
+	p := intent.NewPatch()
+	assert.Len(p.Patches, 2)
+	assert.Equal("", p.Patches[0].ModuleName)
+	assert.Equal("abc", p.Patches[0].Githash)
+	assert.Equal("enterprise", p.Patches[1].ModuleName)
+	assert.Equal("123", p.Patches[1].Githash)
+	assert.Equal("enterprise-diff", p.Patches[1].PatchSet.PatchFileId)
+
+	assert.Error(CopyPreviousModules(&githubIntent{}, previous))
+}
+
+func TestSetParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	intent, err := NewCliIntent("octocat", "mci", "abc", "", "diff", "desc", false, nil, nil, "")
+	assert.NoError(err)
+	params := []version.Parameter{{Key: "suite", Value: "smoke"}}
+	assert.NoError(SetParameters(intent, params))
+	assert.Equal(params, intent.NewPatch().Parameters)
+
+	assert.Error(SetParameters(&githubIntent{}, params))
+}
@@ -34,6 +34,7 @@ var (
 	PatchesKey         = bsonutil.MustHaveTag(Patch{}, "Patches")
 	ActivatedKey       = bsonutil.MustHaveTag(Patch{}, "Activated")
 	PatchedConfigKey   = bsonutil.MustHaveTag(Patch{}, "PatchedConfig")
+	PinnedKey          = bsonutil.MustHaveTag(Patch{}, "Pinned")
 	githubPatchDataKey = bsonutil.MustHaveTag(Patch{}, "GithubPatchData")
 
 	// BSON fields for the module patch struct
@@ -44,6 +45,7 @@ var (
 	// BSON fields for the patch set struct
 	PatchSetPatchKey   = bsonutil.MustHaveTag(PatchSet{}, "Patch")
 	PatchSetSummaryKey = bsonutil.MustHaveTag(PatchSet{}, "Summary")
+	PatchSetFileIdKey  = bsonutil.MustHaveTag(PatchSet{}, "PatchFileId")
 
 	// BSON fields for the git patch summary struct
 	GitSummaryNameKey      = bsonutil.MustHaveTag(Summary{}, "Name")
@@ -99,6 +101,15 @@ func ByUserPaginated(user string, ts time.Time, limit int) db.Q {
 	}).Sort([]string{"-" + CreateTimeKey}).Limit(limit)
 }
 
+// MostRecentByUserAndProject produces a query that returns the newest patch
+// of the project by the given user.
+func MostRecentByUserAndProject(user, project string) db.Q {
+	return db.Query(bson.M{
+		AuthorKey:  user,
+		ProjectKey: project,
+	}).Sort([]string{"-" + CreateTimeKey}).Limit(1)
+}
+
 // ByUserProjectAndGitspec produces a query that returns patches by the given
 // patch author, project, and gitspec.
 func ByUserProjectAndGitspec(user string, project string, gitspec string) db.Q {
@@ -175,6 +186,28 @@ func PatchesByProject(projectId string, ts time.Time, limit int) db.Q {
 	}).Sort([]string{"-" + CreateTimeKey}).Limit(limit)
 }
 
+// ExpiredUnfinalized builds a query for the project's patches that were
+// created before the cutoff and never finalized, excluding pinned patches.
+func ExpiredUnfinalized(projectId string, cutoff time.Time) db.Q {
+	return db.Query(bson.M{
+		ProjectKey:    projectId,
+		VersionKey:    "",
+		CreateTimeKey: bson.M{"$lt": cutoff},
+		PinnedKey:     bson.M{"$ne": true},
+	})
+}
+
+// ByGithubHeadHash finds the finalized pull request patches of a repository
+// whose head is the given commit, newest first.
+func ByGithubHeadHash(owner, repo, hash string) db.Q {
+	return db.Query(bson.M{
+		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchBaseOwnerKey): owner,
+		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchBaseRepoKey):  repo,
+		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchHeadHashKey):  hash,
+		VersionKey: bson.M{"$ne": ""},
+	}).Sort([]string{"-" + CreateTimeKey})
+}
+
 func ByGithubPRAndCreatedBefore(t time.Time, owner, repo string, prNumber int) db.Q {
 	return db.Query(bson.M{
 		CreateTimeKey: bson.M{
@@ -1,11 +1,16 @@
 package patch
 
 import (
+	"io"
 	"io/ioutil"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -13,6 +18,13 @@ import (
 // SizeLimit is a hard limit on patch size.
 const SizeLimit = 1024 * 1024 * 100
 
+// UploadSizeLimit is a hard limit on the size of patches uploaded as a stream,
+// which are never held in memory while they're uploaded.
+const UploadSizeLimit = 1024 * 1024 * 500
+
+// ErrUploadTooLarge is returned when an uploaded patch exceeds UploadSizeLimit.
+var ErrUploadTooLarge = errors.Errorf("patch is greater than the upload limit of %d bytes", UploadSizeLimit)
+
 // VariantTasks contains the variant ID and  the set of tasks to be scheduled for that variant
 type VariantTasks struct {
 	Variant      string
@@ -46,6 +58,14 @@ type Patch struct {
 	PatchedConfig   string         `bson:"patched_config"`
 	Alias           string         `bson:"alias"`
 	GithubPatchData GithubPatch    `bson:"github_patch_data,omitempty"`
+
+	// Pinned, if true, indicates that the patch is kept even once it has
+	// gone unfinalized for longer than its project's patch expiration
+	Pinned bool `bson:"pinned,omitempty"`
+
+	// Parameters are the values of the project's parameters for the
+	// patch's version.
+	Parameters []version.Parameter `bson:"parameters,omitempty"`
 }
 
 // GithubPatch stores patch data for patches create from GitHub pull requests
@@ -129,6 +149,42 @@ func (p *Patch) FetchPatchFiles() error {
 	return nil
 }
 
+// UploadPatchFile streams a patch diff into gridfs, returning the ID of the
+// file to reference from the patch. Nothing is stored if the diff exceeds
+// UploadSizeLimit.
+func UploadPatchFile(diff io.Reader) (bson.ObjectId, error) {
+	patchFileID := bson.NewObjectId()
+	source := &uploadLimitReader{r: diff, remaining: UploadSizeLimit}
+	if err := db.WriteGridFile(GridFSPrefix, patchFileID.Hex(), source); err != nil {
+		if err == ErrUploadTooLarge {
+			return "", err
+		}
+		return "", errors.Wrap(err, "problem writing patch file to gridfs")
+	}
+
+	return patchFileID, nil
+}
+
+// uploadLimitReader fails, rather than truncating the patch like an
+// io.LimitedReader, once more than the remaining bytes are read.
+type uploadLimitReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (r *uploadLimitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return 0, ErrUploadTooLarge
+	}
+
+	return n, err
+}
+
 // SyncVariantsTasks updates the patch's Tasks and BuildVariants fields to match with the set
 // in the given list of VariantTasks. This is to ensure schema backwards compatibility for T shaped
 // patches. This mutates the patch in memory but does not update it in the database; for that, use
@@ -283,6 +339,50 @@ func (p *Patch) SetActivation(activated bool) error {
 	)
 }
 
+// SetPinned sets whether the patch is kept once it has gone unfinalized for
+// longer than its project's patch expiration.
+func (p *Patch) SetPinned(pinned bool) error {
+	p.Pinned = pinned
+	return UpdateOne(
+		bson.M{IdKey: p.Id},
+		bson.M{
+			"$set": bson.M{
+				PinnedKey: pinned,
+			},
+		},
+	)
+}
+
+// RemoveWithPatchFiles removes the patch along with the stored diffs of its
+// module patches. Diffs that another patch shares, because it reused the
+// patch's modules, are kept.
+func (p *Patch) RemoveWithPatchFiles() error {
+	catcher := grip.NewBasicCatcher()
+	for _, modulePatch := range p.Patches {
+		fileId := modulePatch.PatchSet.PatchFileId
+		if fileId == "" {
+			continue
+		}
+		shared, err := Count(db.Query(bson.M{
+			IdKey: bson.M{"$ne": p.Id},
+			bsonutil.GetDottedKeyName(PatchesKey, ModulePatchSetKey, PatchSetFileIdKey): fileId,
+		}))
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem finding patches sharing file '%s'", fileId))
+			continue
+		}
+		if shared > 0 {
+			continue
+		}
+		catcher.Add(errors.Wrapf(db.DeleteGridFile(GridFSPrefix, fileId), "problem removing patch file '%s'", fileId))
+	}
+	if catcher.HasErrors() {
+		return catcher.Resolve()
+	}
+
+	return errors.Wrapf(Remove(ById(p.Id)), "problem removing patch '%s'", p.Id.Hex())
+}
+
 // UpdateModulePatch adds or updates a module within a patch.
 func (p *Patch) UpdateModulePatch(modulePatch ModulePatch) error {
 	// check that a patch for this module exists
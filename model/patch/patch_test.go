@@ -1,6 +1,8 @@
 package patch
 
 import (
+	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
 )
 
 func TestConfigChanged(t *testing.T) {
@@ -132,3 +135,70 @@ func (s *patchSuite) TestByGithubPRAndCreatedBefore() {
 	s.NoError(err)
 	s.Len(patches, 1)
 }
+
+func (s *patchSuite) TestRemoveExpiredUnfinalized() {
+	s.Require().NoError(db.ClearCollections(Collection))
+	s.Require().NoError(db.ClearGridCollections(GridFSPrefix))
+
+	for _, name := range []string{"expired", "shared"} {
+		s.Require().NoError(db.WriteGridFile(GridFSPrefix, name, strings.NewReader("diff")))
+	}
+	cutoff := time.Now().Add(-time.Hour)
+	expired := Patch{
+		Id:         bson.NewObjectId(),
+		Project:    "mci",
+		CreateTime: cutoff.Add(-time.Hour),
+		Patches: []ModulePatch{
+			{PatchSet: PatchSet{PatchFileId: "expired"}},
+			{ModuleName: "enterprise", PatchSet: PatchSet{PatchFileId: "shared"}},
+		},
+	}
+	reused := Patch{
+		Id:         bson.NewObjectId(),
+		Project:    "mci",
+		CreateTime: cutoff.Add(time.Minute),
+		Patches:    []ModulePatch{{ModuleName: "enterprise", PatchSet: PatchSet{PatchFileId: "shared"}}},
+	}
+	for _, p := range []Patch{
+		expired,
+		reused,
+		{Id: bson.NewObjectId(), Project: "mci", CreateTime: cutoff.Add(-time.Hour), Version: "finalized"},
+		{Id: bson.NewObjectId(), Project: "mci", CreateTime: cutoff.Add(-time.Hour), Pinned: true},
+		{Id: bson.NewObjectId(), Project: "other", CreateTime: cutoff.Add(-time.Hour)},
+	} {
+		s.Require().NoError(p.Insert())
+	}
+
+	patches, err := Find(ExpiredUnfinalized("mci", cutoff))
+	s.NoError(err)
+	s.Require().Len(patches, 1)
+	s.Equal(expired.Id, patches[0].Id)
+
+	s.NoError(patches[0].RemoveWithPatchFiles())
+	found, err := FindOne(ById(expired.Id))
+	s.NoError(err)
+	s.Nil(found)
+	_, err = db.GetGridFile(GridFSPrefix, "expired")
+	s.Error(err)
+	file, err := db.GetGridFile(GridFSPrefix, "shared")
+	s.Require().NoError(err)
+	s.NoError(file.Close())
+
+	s.NoError(reused.SetPinned(true))
+	patches, err = Find(ExpiredUnfinalized("mci", time.Now()))
+	s.NoError(err)
+	s.Empty(patches)
+}
+
+func TestUploadLimitReader(t *testing.T) {
+	assert := assert.New(t)
+
+	r := &uploadLimitReader{r: strings.NewReader("0123456789"), remaining: 10}
+	out, err := ioutil.ReadAll(r)
+	assert.NoError(err)
+	assert.Equal("0123456789", string(out))
+
+	r = &uploadLimitReader{r: strings.NewReader("0123456789"), remaining: 9}
+	_, err = ioutil.ReadAll(r)
+	assert.Equal(ErrUploadTooLarge, err)
+}
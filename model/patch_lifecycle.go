@@ -234,6 +234,11 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 			"Error marshaling patched project config from repository revision “%v”",
 			p.Githash)
 	}
+	// patches that weren't given parameters take the project's defaults
+	params, err := project.ResolveParameters(p.Parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid patch parameters")
+	}
 
 	projectRef, err := FindOneProjectRef(p.Project)
 	if err != nil {
@@ -263,6 +268,7 @@ func FinalizePatch(ctx context.Context, p *patch.Patch, requester string, github
 		Branch:              projectRef.Branch,
 		RevisionOrderNumber: p.PatchNumber,
 		AuthorID:            p.Author,
+		Parameters:          params,
 	}
 
 	tasks := TaskVariantPairs{}
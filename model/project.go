@@ -27,6 +27,9 @@ const (
 )
 
 type Project struct {
+	// SchemaVersion is the version of the config syntax that the project
+	// is written in. Configs that don't set it are version 1.
+	SchemaVersion   int                        `yaml:"schema_version,omitempty" bson:"schema_version,omitempty"`
 	Enabled         bool                       `yaml:"enabled,omitempty" bson:"enabled"`
 	Stepback        bool                       `yaml:"stepback,omitempty" bson:"stepback"`
 	BatchTime       int                        `yaml:"batchtime,omitempty" bson:"batch_time"`
@@ -50,8 +53,53 @@ type Project struct {
 	Tasks           []ProjectTask              `yaml:"tasks,omitempty" bson:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
 
+	// TestSelection maps source paths to the tasks that test them, so that
+	// mainline versions can run only the tasks affected by their changes.
+	TestSelection []TestSelectionRule `yaml:"test_selection,omitempty" bson:"test_selection,omitempty"`
+
+	// Retry, if set, restarts the project's tasks automatically when they
+	// fail. Tasks can override it.
+	Retry *RetryPolicy `yaml:"retry,omitempty" bson:"retry,omitempty"`
+
+	// Parameters are the values that can be given when a version or patch
+	// of the project is created, which its tasks receive as expansions.
+	Parameters []ParameterDefinition `yaml:"parameters,omitempty" bson:"parameters,omitempty"`
+
 	// Flag that indicates a project as requiring user authentication
 	Private bool `yaml:"private,omitempty" bson:"private"`
+
+	// Deprecations is the deprecated syntax that the config was parsed
+	// from. It isn't stored, since stored configs are rewritten without it.
+	Deprecations []version.ConfigDeprecation `yaml:"-" bson:"-"`
+}
+
+// TestSelectionRule selects tasks to run when a changed file matches one of
+// its paths, which are gitignore-style patterns.
+type TestSelectionRule struct {
+	Paths []string `yaml:"paths,omitempty" bson:"paths"`
+	Tasks []string `yaml:"tasks,omitempty" bson:"tasks"`
+}
+
+// RetryPolicy configures how failed tasks are restarted automatically when
+// they finish.
+type RetryPolicy struct {
+	// MaxRetries is the number of times a task is restarted automatically.
+	// Zero disables retries.
+	MaxRetries int `yaml:"max_retries,omitempty" bson:"max_retries"`
+	// SystemFailuresOnly restricts retries to tasks that failed because of
+	// a system failure rather than a failing test.
+	SystemFailuresOnly bool `yaml:"system_failures_only,omitempty" bson:"system_failures_only"`
+	// BackoffSecs is how long a task waits before each retry, doubling
+	// with each retry of the task.
+	BackoffSecs int `yaml:"backoff_secs,omitempty" bson:"backoff_secs"`
+}
+
+// ParameterDefinition defines a parameter of a project and the value it
+// takes when none is given.
+type ParameterDefinition struct {
+	Key         string `yaml:"key,omitempty" bson:"key"`
+	Value       string `yaml:"value,omitempty" bson:"value"`
+	Description string `yaml:"description,omitempty" bson:"description,omitempty"`
 }
 
 // Unmarshalled from the "tasks" list in an individual build variant. Can be either a task or task group
@@ -69,7 +117,7 @@ type BuildVariantTaskUnit struct {
 	Requires  []TaskUnitRequirement `yaml:"requires,omitempty" bson:"requires"`
 
 	// the distros that the task can be run on
-	Distros []string `yaml:"distros,omitempty" bson:"distros"`
+	Distros []string `yaml:"run_on,omitempty" bson:"distros"`
 
 	// currently unsupported (TODO EVG-578)
 	ExecTimeoutSecs int   `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
@@ -331,6 +379,20 @@ type ProjectTask struct {
 	//   3. false = overriding the project setting with false
 	Patchable *bool `yaml:"patchable,omitempty" bson:"patchable,omitempty"`
 	Stepback  *bool `yaml:"stepback,omitempty" bson:"stepback,omitempty"`
+
+	// Retry overrides the project's retry policy for the task.
+	Retry *RetryPolicy `yaml:"retry,omitempty" bson:"retry,omitempty"`
+
+	// Shards, if greater than 1, splits the task into that many tasks that
+	// each run a share of Tests balanced by the tests' historical runtimes.
+	Shards int      `yaml:"shards,omitempty" bson:"shards,omitempty"`
+	Tests  []string `yaml:"tests,omitempty" bson:"tests,omitempty"`
+
+	// ShardOf, ShardIndex and ShardCount describe a task created by
+	// splitting a sharded task; they are not part of the configuration.
+	ShardOf    string `yaml:"-" bson:"shard_of,omitempty"`
+	ShardIndex int    `yaml:"-" bson:"shard_index,omitempty"`
+	ShardCount int    `yaml:"-" bson:"shard_count,omitempty"`
 }
 
 // TaskIdTable is a map of [variant, task display name]->[task id].
@@ -578,6 +640,17 @@ func populateExpansions(d *distro.Distro, v *version.Version, bv *BuildVariant,
 	expansions.Put("distro_id", d.Id)
 	expansions.Put("created_at", v.CreateTime.Format(build.IdTimeLayout))
 
+	if t.ShardOf != "" {
+		expansions.Put("shard_index", strconv.Itoa(t.ShardIndex))
+		expansions.Put("shard_count", strconv.Itoa(t.ShardCount))
+		expansions.Put("shard_tests", strings.Join(t.ShardTests, " "))
+	}
+
+	if t.Checkpoint != nil {
+		expansions.Put("checkpoint", t.Checkpoint.Data)
+		expansions.Put("checkpoint_sequence", strconv.Itoa(t.Checkpoint.Sequence))
+	}
+
 	if evergreen.IsPatchRequester(v.Requester) {
 		expansions.Put("is_patch", "true")
 		expansions.Put("revision_order_id", fmt.Sprintf("%s_%d", v.Author, v.RevisionOrderNumber))
@@ -597,9 +670,51 @@ func populateExpansions(d *distro.Distro, v *version.Version, bv *BuildVariant,
 		expansions.Put(e.Key, e.Value)
 	}
 	expansions.Update(bv.Expansions)
+	for _, param := range v.Parameters {
+		expansions.Put(param.Key, param.Value)
+	}
 	return expansions
 }
 
+// ResolveParameters returns the values of the project's parameters, which
+// are their defaults unless given. It returns an error if a parameter that
+// isn't defined by the project is given.
+func (p *Project) ResolveParameters(given []version.Parameter) ([]version.Parameter, error) {
+	values := map[string]string{}
+	undefined := []string{}
+	for _, param := range given {
+		if _, ok := values[param.Key]; ok {
+			return nil, errors.Errorf("parameter '%s' is given more than once", param.Key)
+		}
+		values[param.Key] = param.Value
+		if p.findParameter(param.Key) == nil {
+			undefined = append(undefined, param.Key)
+		}
+	}
+	if len(undefined) > 0 {
+		return nil, errors.Errorf("parameters not defined by the project: %s", strings.Join(undefined, ", "))
+	}
+
+	resolved := []version.Parameter{}
+	for _, def := range p.Parameters {
+		value, ok := values[def.Key]
+		if !ok {
+			value = def.Value
+		}
+		resolved = append(resolved, version.Parameter{Key: def.Key, Value: value})
+	}
+	return resolved, nil
+}
+
+func (p *Project) findParameter(key string) *ParameterDefinition {
+	for i := range p.Parameters {
+		if p.Parameters[i].Key == key {
+			return &p.Parameters[i]
+		}
+	}
+	return nil
+}
+
 // GetSpecForTask returns a ProjectTask spec for the given name.
 // Returns an empty ProjectTask if none exists.
 func (p Project) GetSpecForTask(name string) ProjectTask {
@@ -1,7 +1,12 @@
 package model
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -116,3 +121,71 @@ func RemoveProjectAlias(id string) error {
 	}
 	return nil
 }
+
+// RemoveProjectAliasesByName removes every definition of the named alias
+// from the project.
+func RemoveProjectAliasesByName(projectID, alias string) error {
+	err := db.RemoveAll(ProjectAliasCollection, bson.M{
+		projectIDKey: projectID,
+		aliasKey:     alias,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove alias '%s' from project '%s'", alias, projectID)
+	}
+	return nil
+}
+
+// ReplaceProjectAliasesByName replaces the definitions of the named alias in
+// the project with the given definitions.
+func ReplaceProjectAliasesByName(projectID, alias string, aliases []ProjectAlias) error {
+	for i := range aliases {
+		aliases[i].ID = bson.NewObjectId()
+		aliases[i].ProjectID = projectID
+		aliases[i].Alias = alias
+	}
+	if errs := ValidateProjectAliases(aliases); len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	if err := RemoveProjectAliasesByName(projectID, alias); err != nil {
+		return errors.WithStack(err)
+	}
+	for i := range aliases {
+		if err := aliases[i].Upsert(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// ValidateProjectAliases returns a message for each problem with the alias
+// definitions, numbering the definitions from 1.
+func ValidateProjectAliases(aliases []ProjectAlias) []string {
+	errs := []string{}
+	for i, pd := range aliases {
+		if strings.TrimSpace(pd.Alias) == "" {
+			errs = append(errs, fmt.Sprintf("alias name #%d can't be empty string", i+1))
+		}
+		if strings.TrimSpace(pd.Variant) == "" {
+			errs = append(errs, fmt.Sprintf("variant regex #%d can't be empty string", i+1))
+		}
+		if strings.TrimSpace(pd.Task) == "" && len(pd.Tags) == 0 {
+			errs = append(errs, fmt.Sprintf("must specify either task regex or tags on line #%d ", i+1))
+		}
+
+		if _, err := regexp.Compile(pd.Variant); err != nil {
+			errs = append(errs, fmt.Sprintf("variant regex #%d is invalid", i+1))
+		}
+		if _, err := regexp.Compile(pd.Task); err != nil {
+			errs = append(errs, fmt.Sprintf("task regex #%d is invalid", i+1))
+		}
+	}
+	return errs
+}
+
+// ResolveProjectAlias returns the variants and tasks, including their
+// dependencies, that a patch of the project created with the alias would run.
+func (p *Project) ResolveProjectAlias(alias string) []patch.VariantTasks {
+	patchDoc := &patch.Patch{}
+	p.BuildProjectTVPairs(patchDoc, alias)
+	return patchDoc.VariantsTasks
+}
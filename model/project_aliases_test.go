@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -117,3 +118,53 @@ func (s *ProjectAliasSuite) TestFindAliasInProject() {
 	s.NoError(err)
 	s.Len(found, 2)
 }
+
+func (s *ProjectAliasSuite) TestReplaceByName() {
+	for _, a := range s.aliases[:2] {
+		s.NoError(a.Upsert())
+	}
+	other := ProjectAlias{ProjectID: "project-0", Alias: "other", Variant: "v", Task: "t"}
+	s.NoError(other.Upsert())
+
+	s.NoError(ReplaceProjectAliasesByName("project-0", "alias-0", []ProjectAlias{
+		{Variant: "^ubuntu", Task: "^compile$"},
+		{Variant: "^rhel", Tags: []string{"smoke"}},
+	}))
+	out, err := FindAliasInProject("project-0", "alias-0")
+	s.NoError(err)
+	s.Require().Len(out, 2)
+	for _, a := range out {
+		s.Equal("project-0", a.ProjectID)
+		s.Equal("alias-0", a.Alias)
+	}
+	out, err = FindAliasInProject("project-0", "other")
+	s.NoError(err)
+	s.Len(out, 1)
+
+	s.Error(ReplaceProjectAliasesByName("project-0", "alias-0", []ProjectAlias{{Variant: "(", Task: "t"}}))
+	out, err = FindAliasInProject("project-0", "alias-0")
+	s.NoError(err)
+	s.Len(out, 2)
+
+	s.NoError(RemoveProjectAliasesByName("project-0", "alias-0"))
+	out, err = FindAliasInProject("project-0", "alias-0")
+	s.NoError(err)
+	s.Len(out, 0)
+	out, err = FindAliasInProject("project-1", "alias-1")
+	s.NoError(err)
+	s.Len(out, 1)
+}
+
+func TestValidateProjectAliases(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Empty(ValidateProjectAliases([]ProjectAlias{
+		{Alias: "a", Variant: ".*", Task: ".*"},
+		{Alias: "a", Variant: ".*", Tags: []string{"smoke"}},
+	}))
+	assert.Len(ValidateProjectAliases([]ProjectAlias{
+		{Variant: ".*", Task: ".*"},
+		{Alias: "a", Variant: "(", Task: ".*"},
+		{Alias: "a", Variant: ".*"},
+	}), 3)
+}
@@ -0,0 +1,185 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// EvaluateOptions describe the version that a project configuration is
+// evaluated for.
+type EvaluateOptions struct {
+	// Requester is the requester of the version, which is the repotracker
+	// if it's empty. A patch requester leaves out the tasks that variants
+	// mark as not patchable.
+	Requester string
+	Revision  string
+	// Parameters are the values given for the project's parameters, which
+	// otherwise take their defaults.
+	Parameters []version.Parameter
+	// Expansions stand in for the expansions of the distros that the tasks
+	// run on, which aren't known without the database.
+	Expansions map[string]string
+}
+
+// EvaluatedVariant is a build that a version of a project would be created
+// with.
+type EvaluatedVariant struct {
+	Name        string          `yaml:"name" json:"name"`
+	DisplayName string          `yaml:"display_name,omitempty" json:"display_name,omitempty"`
+	BuildId     string          `yaml:"build_id" json:"build_id"`
+	Tasks       []EvaluatedTask `yaml:"tasks" json:"tasks"`
+}
+
+// EvaluatedTask is a task that a version of a project would be created with.
+// Its dependencies and execution tasks are named by their variants and
+// tasks.
+type EvaluatedTask struct {
+	Name           string            `yaml:"name" json:"name"`
+	Id             string            `yaml:"id" json:"id"`
+	Distro         string            `yaml:"distro,omitempty" json:"distro,omitempty"`
+	Priority       int64             `yaml:"priority,omitempty" json:"priority,omitempty"`
+	TaskGroup      string            `yaml:"task_group,omitempty" json:"task_group,omitempty"`
+	DisplayTask    string            `yaml:"display_task,omitempty" json:"display_task,omitempty"`
+	ExecutionTasks []string          `yaml:"execution_tasks,omitempty" json:"execution_tasks,omitempty"`
+	DependsOn      []EvaluatedDep    `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	ShardTests     []string          `yaml:"shard_tests,omitempty" json:"shard_tests,omitempty"`
+	Expansions     map[string]string `yaml:"expansions,omitempty" json:"expansions,omitempty"`
+	Tags           []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+}
+
+// EvaluatedDep is a dependency of an evaluated task.
+type EvaluatedDep struct {
+	Variant string `yaml:"variant" json:"variant"`
+	Name    string `yaml:"name" json:"name"`
+	Status  string `yaml:"status" json:"status"`
+}
+
+// EvaluateProject returns the builds and tasks that a version of the project
+// would be created with, without creating them, along with the expansions
+// that each task would start with. The tests of sharded tasks are split as
+// if they had no history.
+func EvaluateProject(p *Project, opts EvaluateOptions) ([]EvaluatedVariant, error) {
+	if p == nil {
+		return nil, errors.New("project cannot be nil")
+	}
+
+	params, err := p.ResolveParameters(opts.Parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid parameters")
+	}
+	config, err := yaml.Marshal(p)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling config")
+	}
+
+	requester := opts.Requester
+	if requester == "" {
+		requester = evergreen.RepotrackerVersionRequester
+	}
+	v := &version.Version{
+		Id:         util.CleanName(fmt.Sprintf("%s_%s", p.Identifier, opts.Revision)),
+		Identifier: p.Identifier,
+		Revision:   opts.Revision,
+		Requester:  requester,
+		CreateTime: time.Now(),
+		Config:     string(config),
+		Parameters: params,
+		Status:     evergreen.VersionCreated,
+	}
+
+	d := &distro.Distro{}
+	for key, value := range opts.Expansions {
+		d.Expansions = append(d.Expansions, distro.Expansion{Key: key, Value: value})
+	}
+	sort.Slice(d.Expansions, func(i, j int) bool { return d.Expansions[i].Key < d.Expansions[j].Key })
+
+	taskIds := NewTaskIdTable(p, v)
+	names := map[string]TVPair{}
+	for pair, id := range taskIds.ExecutionTasks {
+		names[id] = pair
+	}
+	for pair, id := range taskIds.DisplayTasks {
+		names[id] = pair
+	}
+
+	variants := []EvaluatedVariant{}
+	for i := range p.BuildVariants {
+		bv := &p.BuildVariants[i]
+		if bv.Disabled {
+			continue
+		}
+
+		b := &build.Build{
+			Id:           newBuildId(p, v, bv.Name),
+			CreateTime:   v.CreateTime,
+			Project:      p.Identifier,
+			Revision:     v.Revision,
+			BuildVariant: bv.Name,
+			Version:      v.Id,
+			DisplayName:  bv.DisplayName,
+			Requester:    v.Requester,
+		}
+		tasks, err := createTasksForBuild(p, bv, b, v, taskIds, nil, nil, "", newLocalShardAssigner(p.Identifier, bv.Name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error evaluating tasks of variant '%s'", bv.Name)
+		}
+
+		variant := EvaluatedVariant{
+			Name:        bv.Name,
+			DisplayName: bv.DisplayName,
+			BuildId:     b.Id,
+			Tasks:       make([]EvaluatedTask, 0, len(tasks)),
+		}
+		for _, t := range tasks {
+			if t == nil {
+				return nil, errors.Errorf("could not evaluate a task of variant '%s'", bv.Name)
+			}
+			variant.Tasks = append(variant.Tasks, evaluateTask(bv, v, d, t, names))
+		}
+		variants = append(variants, variant)
+	}
+
+	return variants, nil
+}
+
+// evaluateTask describes the task, naming the tasks that it refers to by the
+// IDs in names.
+func evaluateTask(bv *BuildVariant, v *version.Version, d *distro.Distro, t *task.Task, names map[string]TVPair) EvaluatedTask {
+	out := EvaluatedTask{
+		Name:       t.DisplayName,
+		Id:         t.Id,
+		Distro:     t.DistroId,
+		Priority:   t.Priority,
+		TaskGroup:  t.TaskGroup,
+		ShardTests: t.ShardTests,
+		Tags:       t.Tags,
+	}
+	if t.DisplayTask != nil {
+		out.DisplayTask = t.DisplayTask.DisplayName
+	}
+	for _, id := range t.ExecutionTasks {
+		out.ExecutionTasks = append(out.ExecutionTasks, names[id].TaskName)
+	}
+	for _, dep := range t.DependsOn {
+		pair := names[dep.TaskId]
+		out.DependsOn = append(out.DependsOn, EvaluatedDep{Variant: pair.Variant, Name: pair.TaskName, Status: dep.Status})
+	}
+	if t.DisplayOnly {
+		return out
+	}
+
+	taskDistro := *d
+	taskDistro.Id = t.DistroId
+	out.Expansions = populateExpansions(&taskDistro, v, bv, t, nil).Map()
+	return out
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateProject(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	config := `
+parameters:
+- key: flavor
+  value: vanilla
+tasks:
+- name: compile
+- name: test
+  depends_on:
+  - name: compile
+- name: lint
+- name: setup
+- name: run
+task_groups:
+- name: group
+  tasks: [setup, run]
+buildvariants:
+- name: bv1
+  display_name: Variant 1
+  run_on: [d1]
+  expansions:
+    color: blue
+  tasks:
+  - name: compile
+  - name: test
+  - name: lint
+    patchable: false
+  - name: group
+  display_tasks:
+  - name: checks
+    execution_tasks: [test, lint]
+- name: bv2
+  run_on: [d2]
+  tasks:
+  - name: test
+    depends_on:
+    - name: compile
+      variant: bv1
+- name: disabled
+  disabled: true
+  tasks:
+  - name: compile
+`
+	p := &Project{}
+	require.NoError(LoadProjectInto([]byte(config), "proj", p))
+
+	variants, err := EvaluateProject(p, EvaluateOptions{
+		Revision:   "abc",
+		Parameters: []version.Parameter{{Key: "flavor", Value: "chocolate"}},
+		Expansions: map[string]string{"python": "/usr/bin/python"},
+	})
+	require.NoError(err)
+	require.Len(variants, 2)
+	byName := map[string]EvaluatedVariant{}
+	for _, variant := range variants {
+		byName[variant.Name] = variant
+	}
+
+	bv1 := byName["bv1"]
+	assert.Equal("bv1", bv1.Name)
+	assert.Equal("Variant 1", bv1.DisplayName)
+	tasks := map[string]EvaluatedTask{}
+	for _, task := range bv1.Tasks {
+		tasks[task.Name] = task
+	}
+	require.Len(tasks, 6)
+
+	assert.Equal([]EvaluatedDep{{Variant: "bv1", Name: "compile", Status: evergreen.TaskSucceeded}}, tasks["test"].DependsOn)
+	assert.Equal("checks", tasks["test"].DisplayTask)
+	assert.Equal([]string{"test", "lint"}, tasks["checks"].ExecutionTasks)
+	assert.Empty(tasks["checks"].Expansions)
+	assert.Equal("group", tasks["run"].TaskGroup)
+	assert.Equal("d1", tasks["compile"].Distro)
+	assert.Contains(tasks["compile"].Id, "abc")
+
+	expansions := tasks["compile"].Expansions
+	assert.Equal("compile", expansions["task_name"])
+	assert.Equal("bv1", expansions["build_variant"])
+	assert.Equal("blue", expansions["color"])
+	assert.Equal("chocolate", expansions["flavor"])
+	assert.Equal("/usr/bin/python", expansions["python"])
+	assert.Equal("proj", expansions["project"])
+
+	bv2 := byName["bv2"]
+	require.Len(bv2.Tasks, 1)
+	assert.Equal([]EvaluatedDep{{Variant: "bv1", Name: "compile", Status: evergreen.TaskSucceeded}}, bv2.Tasks[0].DependsOn)
+
+	variants, err = EvaluateProject(p, EvaluateOptions{Requester: evergreen.PatchVersionRequester})
+	require.NoError(err)
+	require.Len(variants, 2)
+	for _, task := range append(variants[0].Tasks, variants[1].Tasks...) {
+		assert.NotEqual("lint", task.Name)
+		if task.Name == "compile" {
+			assert.Equal("vanilla", task.Expansions["flavor"])
+			assert.Equal("true", task.Expansions["is_patch"])
+		}
+	}
+
+	_, err = EvaluateProject(p, EvaluateOptions{Parameters: []version.Parameter{{Key: "unknown", Value: "x"}}})
+	assert.Error(err)
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// ProjectSettings is a snapshot of the settings of a project, which the
+// project's audit log records changes to.
+type ProjectSettings struct {
+	ProjectRef    ProjectRef           `bson:"proj_ref" json:"proj_ref"`
+	Vars          ProjectVars          `bson:"vars" json:"vars"`
+	Aliases       []ProjectAlias       `bson:"aliases" json:"aliases"`
+	Subscriptions []event.Subscription `bson:"subscriptions" json:"subscriptions"`
+}
+
+// ProjectChangeEvent is a user's change to the settings of a project.
+type ProjectChangeEvent struct {
+	User   string          `json:"user"`
+	Before ProjectSettings `json:"before"`
+	After  ProjectSettings `json:"after"`
+}
+
+// GetProjectSettings returns the current settings of the project.
+func GetProjectSettings(projectId string) (*ProjectSettings, error) {
+	projectRef, err := FindOneProjectRef(projectId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding project '%s'", projectId)
+	}
+	if projectRef == nil {
+		return nil, errors.Errorf("project '%s' not found", projectId)
+	}
+	settings := &ProjectSettings{ProjectRef: *projectRef}
+
+	vars, err := FindOneProjectVars(projectId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding variables of project '%s'", projectId)
+	}
+	if vars != nil {
+		settings.Vars = *vars
+	}
+	settings.Aliases, err = FindAliasesForProject(projectId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding aliases of project '%s'", projectId)
+	}
+	settings.Subscriptions, err = event.FindSubscriptionsByOwner(projectId, event.OwnerTypeProject)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding subscriptions of project '%s'", projectId)
+	}
+
+	return settings, nil
+}
+
+// redacted returns a copy of the settings without the values of private
+// variables, which aren't recorded in the audit log.
+func (s ProjectSettings) redacted() ProjectSettings {
+	vars := map[string]string{}
+	for k, v := range s.Vars.Vars {
+		vars[k] = v
+	}
+	s.Vars.Vars = vars
+	s.Vars.RedactPrivateVars()
+	return s
+}
+
+// LogProjectAdded records that the user created the project with the given
+// settings.
+func LogProjectAdded(projectId, user string, settings *ProjectSettings) error {
+	return event.LogProjectEvent(projectId, event.EventTypeProjectAdded, user, ProjectSettings{}, settings.redacted())
+}
+
+// LogProjectModified records that the user changed the settings of the
+// project, unless they are unchanged. A change to the value of a private
+// variable is recorded, although the values are not.
+func LogProjectModified(projectId, user string, before, after *ProjectSettings) error {
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return event.LogProjectEvent(projectId, event.EventTypeProjectModified, user, before.redacted(), after.redacted())
+}
+
+// FindProjectEvents returns the n most recent changes to the settings of
+// the project before the given time. The data of each event is a
+// *ProjectChangeEvent.
+func FindProjectEvents(projectId string, before time.Time, n int) ([]event.EventLogEntry, error) {
+	events, err := event.Find(event.AllLogCollection, event.ProjectEventsBefore(projectId, before, n))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding events of project '%s'", projectId)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	out := make([]event.EventLogEntry, 0, len(events))
+	for _, e := range events {
+		data, ok := e.Data.(*event.ProjectChangeEventData)
+		if !ok {
+			catcher.Add(errors.Errorf("event '%s' is not a project event", e.ID))
+			continue
+		}
+		change := &ProjectChangeEvent{User: data.User}
+		if err = data.Before.Unmarshal(&change.Before); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem decoding settings before event '%s'", e.ID))
+			continue
+		}
+		if err = data.After.Unmarshal(&change.After); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem decoding settings after event '%s'", e.ID))
+			continue
+		}
+		e.Data = change
+		out = append(out, e)
+	}
+
+	return out, catcher.Resolve()
+}
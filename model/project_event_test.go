@@ -0,0 +1,84 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(ProjectRefCollection, ProjectVarsCollection, ProjectAliasCollection,
+		event.SubscriptionsCollection, event.AllLogCollection))
+
+	projectRef := &ProjectRef{Identifier: "mci", DisplayName: "MCI"}
+	require.NoError(projectRef.Insert())
+	vars := &ProjectVars{
+		Id:          "mci",
+		Vars:        map[string]string{"a": "a", "secret": "hunter2"},
+		PrivateVars: map[string]bool{"secret": true},
+	}
+	require.NoError(vars.Insert())
+
+	before, err := GetProjectSettings("mci")
+	require.NoError(err)
+	assert.Equal("MCI", before.ProjectRef.DisplayName)
+	assert.Equal("hunter2", before.Vars.Vars["secret"])
+	require.NoError(LogProjectAdded("mci", "me", before))
+
+	// unchanged settings aren't recorded
+	after, err := GetProjectSettings("mci")
+	require.NoError(err)
+	require.NoError(LogProjectModified("mci", "me", before, after))
+
+	time.Sleep(time.Millisecond)
+	projectRef.DisplayName = "Evergreen"
+	require.NoError(projectRef.Upsert())
+	vars.Vars["secret"] = "correct horse battery staple"
+	_, err = vars.Upsert()
+	require.NoError(err)
+	alias := ProjectAlias{ProjectID: "mci", Alias: "all", Variant: ".*", Task: ".*"}
+	require.NoError(alias.Upsert())
+	after, err = GetProjectSettings("mci")
+	require.NoError(err)
+	require.NoError(LogProjectModified("mci", "you", before, after))
+
+	events, err := FindProjectEvents("mci", time.Now(), 10)
+	require.NoError(err)
+	require.Len(events, 2)
+
+	assert.Equal(event.EventTypeProjectModified, events[0].EventType)
+	change, ok := events[0].Data.(*ProjectChangeEvent)
+	require.True(ok)
+	assert.Equal("you", change.User)
+	assert.Equal("MCI", change.Before.ProjectRef.DisplayName)
+	assert.Equal("Evergreen", change.After.ProjectRef.DisplayName)
+	assert.Empty(change.Before.Aliases)
+	require.Len(change.After.Aliases, 1)
+	assert.Equal("all", change.After.Aliases[0].Alias)
+	assert.Equal("a", change.After.Vars.Vars["a"])
+	assert.Empty(change.Before.Vars.Vars["secret"], "private values aren't recorded")
+	assert.Empty(change.After.Vars.Vars["secret"], "private values aren't recorded")
+	assert.Equal("correct horse battery staple", after.Vars.Vars["secret"], "logging doesn't redact the snapshot")
+
+	assert.Equal(event.EventTypeProjectAdded, events[1].EventType)
+	change, ok = events[1].Data.(*ProjectChangeEvent)
+	require.True(ok)
+	assert.Empty(change.Before.ProjectRef.Identifier)
+	assert.Equal("mci", change.After.ProjectRef.Identifier)
+
+	events, err = FindProjectEvents("mci", events[0].Timestamp, 10)
+	require.NoError(err)
+	assert.Len(events, 1)
+	events, err = FindProjectEvents("other", time.Now(), 10)
+	require.NoError(err)
+	assert.Empty(events)
+
+	_, err = GetProjectSettings("other")
+	assert.Error(err)
+}
@@ -37,6 +37,7 @@ import (
 // configuration YAML. It implements the Unmarshaler interface
 // to allow for flexible handling.
 type parserProject struct {
+	SchemaVersion   int                        `yaml:"schema_version,omitempty"`
 	Enabled         bool                       `yaml:"enabled,omitempty"`
 	Stepback        bool                       `yaml:"stepback,omitempty"`
 	BatchTime       int                        `yaml:"batchtime,omitempty"`
@@ -59,6 +60,9 @@ type parserProject struct {
 	TaskGroups      []parserTaskGroup          `yaml:"task_groups,omitempty"`
 	Tasks           []parserTask               `yaml:"tasks,omitempty"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty"`
+	TestSelection   []TestSelectionRule        `yaml:"test_selection,omitempty"`
+	Retry           *RetryPolicy               `yaml:"retry,omitempty"`
+	Parameters      []ParameterDefinition      `yaml:"parameters,omitempty"`
 
 	// Matrix code
 	Axes []matrixAxis `yaml:"axes,omitempty"`
@@ -97,6 +101,9 @@ type parserTask struct {
 	Tags            parserStringSlice   `yaml:"tags,omitempty"`
 	Patchable       *bool               `yaml:"patchable,omitempty"`
 	Stepback        *bool               `yaml:"stepback,omitempty"`
+	Retry           *RetryPolicy        `yaml:"retry,omitempty"`
+	Shards          int                 `yaml:"shards,omitempty"`
+	Tests           []string            `yaml:"tests,omitempty"`
 }
 
 type displayTask struct {
@@ -313,7 +320,10 @@ type parserBVTaskUnit struct {
 	ExecTimeoutSecs int                `yaml:"exec_timeout_secs,omitempty"`
 	Stepback        *bool              `yaml:"stepback,omitempty"`
 	Distros         parserStringSlice  `yaml:"distros,omitempty"`
-	RunOn           parserStringSlice  `yaml:"run_on,omitempty"` // Alias for "Distros" TODO: deprecate Distros
+	RunOn           parserStringSlice  `yaml:"run_on,omitempty"` // Alias for "Distros", which is deprecated
+
+	// usedDistros is whether the deprecated "distros" field was set.
+	usedDistros bool
 }
 
 // UnmarshalYAML allows the YAML parser to read both a single selector string or
@@ -337,6 +347,7 @@ func (pbvt *parserBVTaskUnit) UnmarshalYAML(unmarshal func(interface{}) error) e
 	if copy.Name == "" {
 		return errors.New("buildvariant task selector must have a name")
 	}
+	copy.usedDistros = len(copy.Distros) > 0
 	// logic for aliasing the "run_on" field to "distros"
 	if len(copy.RunOn) > 0 {
 		if len(copy.Distros) > 0 {
@@ -426,6 +437,11 @@ func projectFromYAML(yml []byte) (*Project, []error) {
 // intermediate project representation (i.e. before selectors or
 // matrix logic has been evaluated).
 func createIntermediateProject(yml []byte) (*parserProject, []error) {
+	// check the schema version first, since a config written for a newer
+	// version may not parse
+	if err := checkSchemaVersion(yml); err != nil {
+		return nil, []error{err}
+	}
 	p := &parserProject{}
 	err := yaml.Unmarshal(yml, p)
 	if err != nil {
@@ -444,6 +460,7 @@ func createIntermediateProject(yml []byte) (*parserProject, []error) {
 func translateProject(pp *parserProject) (*Project, []error) {
 	// Transfer top level fields
 	proj := &Project{
+		SchemaVersion:   pp.SchemaVersion,
 		Enabled:         pp.Enabled,
 		Stepback:        pp.Stepback,
 		BatchTime:       pp.BatchTime,
@@ -463,6 +480,10 @@ func translateProject(pp *parserProject) (*Project, []error) {
 		Modules:         pp.Modules,
 		Functions:       pp.Functions,
 		ExecTimeoutSecs: pp.ExecTimeoutSecs,
+		TestSelection:   pp.TestSelection,
+		Retry:           pp.Retry,
+		Parameters:      pp.Parameters,
+		Deprecations:    findDeprecations(pp),
 	}
 	tse := NewParserTaskSelectorEvaluator(pp.Tasks)
 	tgse := newTaskGroupSelectorEvaluator(pp.TaskGroups)
@@ -477,6 +498,7 @@ func translateProject(pp *parserProject) (*Project, []error) {
 	evalErrs = append(evalErrs, errs...)
 	proj.BuildVariants, errs = evaluateBuildVariants(tse, tgse, vse, pp.BuildVariants, pp.Tasks, proj.TaskGroups)
 	evalErrs = append(evalErrs, errs...)
+	expandShardedTasks(proj)
 	return proj, evalErrs
 }
 
@@ -509,6 +531,9 @@ func evaluateTaskUnits(tse *taskSelectorEvaluator, tgse *tagSelectorEvaluator, v
 			Tags:            pt.Tags,
 			Patchable:       pt.Patchable,
 			Stepback:        pt.Stepback,
+			Retry:           pt.Retry,
+			Shards:          pt.Shards,
+			Tests:           pt.Tests,
 		}
 		t.DependsOn, errs = evaluateDependsOn(tse.tagEval, tgse, vse, pt.DependsOn)
 		evalErrs = append(evalErrs, errs...)
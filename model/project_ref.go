@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
@@ -36,28 +38,161 @@ type ProjectRef struct {
 
 	PRTestingEnabled bool `bson:"pr_testing_enabled" json:"pr_testing_enabled" yaml:"pr_testing_enabled"`
 
+	// GithubChecksEnabled, if true, indicates that the outcome of each build
+	// should be reported to Github as a check run on its commit
+	GithubChecksEnabled bool `bson:"github_checks_enabled" json:"github_checks_enabled" yaml:"github_checks_enabled"`
+
+	// SuppressInheritedWarnings, if true, indicates that notifications about
+	// a version's project configuration warnings should only include the
+	// warnings introduced by that version
+	SuppressInheritedWarnings bool `bson:"suppress_inherited_warnings" json:"suppress_inherited_warnings" yaml:"suppress_inherited_warnings"`
+
 	//Tracked determines whether or not the project is discoverable in the UI
 	Tracked          bool `bson:"tracked" json:"tracked"`
 	PatchingDisabled bool `bson:"patching_disabled" json:"patching_disabled"`
 
+	// Priority is inherited by the tasks of the project whose build,
+	// version and own priorities are unset
+	Priority int64 `bson:"priority,omitempty" json:"priority,omitempty"`
+
+	// StepbackBisect, if true, indicates that stepback activates the task
+	// halfway between the last passing and the failing version instead of
+	// the one immediately before the failure
+	StepbackBisect bool `bson:"stepback_bisect,omitempty" json:"stepback_bisect,omitempty"`
+
+	// TestSelectionEnabled, if true, indicates that mainline versions only
+	// activate the tasks affected by their changed files, except for a
+	// version that runs every task once FullRunIntervalHours have passed
+	// since the last one, DefaultFullRunIntervalHours if 0
+	TestSelectionEnabled bool `bson:"test_selection_enabled,omitempty" json:"test_selection_enabled,omitempty"`
+	FullRunIntervalHours int  `bson:"full_run_interval_hours,omitempty" json:"full_run_interval_hours,omitempty"`
+
+	// PatchExpirationDays is how long a patch can go unfinalized before it
+	// and its stored diffs are removed, DefaultPatchExpirationDays if 0
+	PatchExpirationDays int `bson:"patch_expiration_days,omitempty" json:"patch_expiration_days,omitempty"`
+
 	// Admins contain a list of users who are able to access the projects page.
 	Admins []string `bson:"admins" json:"admins"`
+	// Contributors and Viewers list the users with those roles on the
+	// project; see UserRole for the roles of users who aren't listed.
+	Contributors []string `bson:"contributors,omitempty" json:"contributors,omitempty"`
+	Viewers      []string `bson:"viewers,omitempty" json:"viewers,omitempty"`
 
 	NotifyOnBuildFailure bool `bson:"notify_on_failure" json:"notify_on_failure"`
+	// BuildBreakTeamChannel is the slack channel that build-break
+	// notifications escalate to when the committer doesn't acknowledge
+	// them, before they escalate to the admins, and
+	// BuildBreakEscalationMins is how long each tier has to acknowledge
+	BuildBreakTeamChannel    string `bson:"build_break_team_channel,omitempty" json:"build_break_team_channel,omitempty"`
+	BuildBreakEscalationMins int    `bson:"build_break_escalation_mins,omitempty" json:"build_break_escalation_mins,omitempty"`
 
 	// RepoDetails contain the details of the status of the consistency
 	// between what is in GitHub and what is in Evergreen
 	RepotrackerError *RepositoryErrorDetails `bson:"repotracker_error" json:"repotracker_error"`
 
 	Triggers []TriggerDefinition `bson:"triggers,omitempty" json:"triggers,omitempty"`
+
+	// CommitQueue configures the queue of pull requests and patches that
+	// Evergreen tests against the head of the branch and merges
+	CommitQueue CommitQueueParams `bson:"commit_queue" json:"commit_queue" yaml:"commit_queue"`
 }
 
-// RepositoryErrorDetails indicates whether or not there is an invalid revision and if there is one,
-// what the guessed merge base revision is.
+// CommitQueueParams configures a project's commit queue. MergeMethod is how
+// Github merges pull requests that pass, one of CommitQueueMergeMethods, and
+// Github's default if empty.
+type CommitQueueParams struct {
+	Enabled     bool   `bson:"enabled" json:"enabled" yaml:"enabled"`
+	MergeMethod string `bson:"merge_method" json:"merge_method" yaml:"merge_method"`
+}
+
+var CommitQueueMergeMethods = []string{"merge", "squash", "rebase"}
+
+// RepositoryErrorDetails records which repotracker capabilities are disabled
+// for the project and, if the base revision is invalid, what the guessed merge
+// base revision is.
 type RepositoryErrorDetails struct {
-	Exists            bool   `bson:"exists" json:"exists"`
-	InvalidRevision   string `bson:"invalid_revision" json:"invalid_revision"`
-	MergeBaseRevision string `bson:"merge_base_revision" json:"merge_base_revision"`
+	InvalidRevision   string                    `bson:"invalid_revision" json:"invalid_revision"`
+	MergeBaseRevision string                    `bson:"merge_base_revision" json:"merge_base_revision"`
+	Disabled          []RepotrackerDisableState `bson:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+// RepotrackerCapability is one part of repotracker ingestion that can be
+// disabled independently of the others.
+type RepotrackerCapability string
+
+const (
+	// RepotrackerConfigFetch covers fetching project configuration files
+	// and creating versions for new revisions.
+	RepotrackerConfigFetch RepotrackerCapability = "config-fetch"
+	// RepotrackerRevisions covers polling the repository for new revisions.
+	RepotrackerRevisions RepotrackerCapability = "revisions"
+	// RepotrackerActivation covers activating the builds of new versions.
+	RepotrackerActivation RepotrackerCapability = "activation"
+)
+
+// RepotrackerDisableState records why a repotracker capability is disabled
+// and when it should resume. A zero expiry means the capability stays
+// disabled until it is explicitly enabled.
+type RepotrackerDisableState struct {
+	Capability RepotrackerCapability `bson:"capability" json:"capability"`
+	Reason     string                `bson:"reason" json:"reason"`
+	DisabledAt time.Time             `bson:"disabled_at" json:"disabled_at"`
+	Expires    time.Time             `bson:"expires,omitempty" json:"expires,omitempty"`
+}
+
+// IsExpired returns whether the capability should have resumed by now.
+func (s *RepotrackerDisableState) IsExpired(now time.Time) bool {
+	return !s.Expires.IsZero() && !now.Before(s.Expires)
+}
+
+// IsDisabled returns whether the capability is disabled and not expired.
+func (d *RepositoryErrorDetails) IsDisabled(capability RepotrackerCapability) bool {
+	return d.GetDisableState(capability) != nil
+}
+
+// GetDisableState returns the unexpired disable state of the capability, or
+// nil if it is enabled.
+func (d *RepositoryErrorDetails) GetDisableState(capability RepotrackerCapability) *RepotrackerDisableState {
+	if d == nil {
+		return nil
+	}
+	now := time.Now()
+	for i := range d.Disabled {
+		if d.Disabled[i].Capability == capability && !d.Disabled[i].IsExpired(now) {
+			return &d.Disabled[i]
+		}
+	}
+	return nil
+}
+
+// Disable disables the capability for the given duration, or until it is
+// enabled if the duration is zero, replacing any existing state for it.
+func (d *RepositoryErrorDetails) Disable(capability RepotrackerCapability, reason string, duration time.Duration) {
+	d.Enable(capability)
+
+	state := RepotrackerDisableState{
+		Capability: capability,
+		Reason:     reason,
+		DisabledAt: time.Now(),
+	}
+	if duration > 0 {
+		state.Expires = state.DisabledAt.Add(duration)
+	}
+	d.Disabled = append(d.Disabled, state)
+}
+
+// Enable removes any disable state for the capability, along with any other
+// states that have expired.
+func (d *RepositoryErrorDetails) Enable(capability RepotrackerCapability) {
+	now := time.Now()
+	disabled := []RepotrackerDisableState{}
+	for _, state := range d.Disabled {
+		if state.Capability == capability || state.IsExpired(now) {
+			continue
+		}
+		disabled = append(disabled, state)
+	}
+	d.Disabled = disabled
 }
 
 type AlertConfig struct {
@@ -96,30 +231,51 @@ type EmailAlertData struct {
 
 var (
 	// bson fields for the ProjectRef struct
-	ProjectRefOwnerKey              = bsonutil.MustHaveTag(ProjectRef{}, "Owner")
-	ProjectRefRepoKey               = bsonutil.MustHaveTag(ProjectRef{}, "Repo")
-	ProjectRefBranchKey             = bsonutil.MustHaveTag(ProjectRef{}, "Branch")
-	ProjectRefRepoKindKey           = bsonutil.MustHaveTag(ProjectRef{}, "RepoKind")
-	ProjectRefEnabledKey            = bsonutil.MustHaveTag(ProjectRef{}, "Enabled")
-	ProjectRefPrivateKey            = bsonutil.MustHaveTag(ProjectRef{}, "Private")
-	ProjectRefBatchTimeKey          = bsonutil.MustHaveTag(ProjectRef{}, "BatchTime")
-	ProjectRefIdentifierKey         = bsonutil.MustHaveTag(ProjectRef{}, "Identifier")
-	ProjectRefDisplayNameKey        = bsonutil.MustHaveTag(ProjectRef{}, "DisplayName")
-	ProjectRefDeactivatePreviousKey = bsonutil.MustHaveTag(ProjectRef{}, "DeactivatePrevious")
-	ProjectRefRemotePathKey         = bsonutil.MustHaveTag(ProjectRef{}, "RemotePath")
-	ProjectRefTrackedKey            = bsonutil.MustHaveTag(ProjectRef{}, "Tracked")
-	ProjectRefLocalConfig           = bsonutil.MustHaveTag(ProjectRef{}, "LocalConfig")
-	ProjectRefRepotrackerError      = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerError")
-	ProjectRefAdminsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
-	projectRefTracksPushEventsKey   = bsonutil.MustHaveTag(ProjectRef{}, "TracksPushEvents")
-	projectRefPRTestingEnabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PRTestingEnabled")
-	projectRefPatchingDisabledKey   = bsonutil.MustHaveTag(ProjectRef{}, "PatchingDisabled")
-	projectRefNotifyOnFailureKey    = bsonutil.MustHaveTag(ProjectRef{}, "NotifyOnBuildFailure")
-	projectRefTriggersKey           = bsonutil.MustHaveTag(ProjectRef{}, "Triggers")
+	ProjectRefOwnerKey                     = bsonutil.MustHaveTag(ProjectRef{}, "Owner")
+	ProjectRefRepoKey                      = bsonutil.MustHaveTag(ProjectRef{}, "Repo")
+	ProjectRefBranchKey                    = bsonutil.MustHaveTag(ProjectRef{}, "Branch")
+	ProjectRefRepoKindKey                  = bsonutil.MustHaveTag(ProjectRef{}, "RepoKind")
+	ProjectRefEnabledKey                   = bsonutil.MustHaveTag(ProjectRef{}, "Enabled")
+	ProjectRefPrivateKey                   = bsonutil.MustHaveTag(ProjectRef{}, "Private")
+	ProjectRefBatchTimeKey                 = bsonutil.MustHaveTag(ProjectRef{}, "BatchTime")
+	ProjectRefIdentifierKey                = bsonutil.MustHaveTag(ProjectRef{}, "Identifier")
+	ProjectRefDisplayNameKey               = bsonutil.MustHaveTag(ProjectRef{}, "DisplayName")
+	ProjectRefDeactivatePreviousKey        = bsonutil.MustHaveTag(ProjectRef{}, "DeactivatePrevious")
+	ProjectRefRemotePathKey                = bsonutil.MustHaveTag(ProjectRef{}, "RemotePath")
+	ProjectRefTrackedKey                   = bsonutil.MustHaveTag(ProjectRef{}, "Tracked")
+	ProjectRefLocalConfig                  = bsonutil.MustHaveTag(ProjectRef{}, "LocalConfig")
+	ProjectRefRepotrackerError             = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerError")
+	ProjectRefAdminsKey                    = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
+	projectRefContributorsKey              = bsonutil.MustHaveTag(ProjectRef{}, "Contributors")
+	projectRefViewersKey                   = bsonutil.MustHaveTag(ProjectRef{}, "Viewers")
+	projectRefTracksPushEventsKey          = bsonutil.MustHaveTag(ProjectRef{}, "TracksPushEvents")
+	projectRefSuppressInheritedWarningsKey = bsonutil.MustHaveTag(ProjectRef{}, "SuppressInheritedWarnings")
+	projectRefPRTestingEnabledKey          = bsonutil.MustHaveTag(ProjectRef{}, "PRTestingEnabled")
+	projectRefGithubChecksEnabledKey       = bsonutil.MustHaveTag(ProjectRef{}, "GithubChecksEnabled")
+	projectRefPatchingDisabledKey          = bsonutil.MustHaveTag(ProjectRef{}, "PatchingDisabled")
+	projectRefPatchExpirationDaysKey       = bsonutil.MustHaveTag(ProjectRef{}, "PatchExpirationDays")
+	projectRefPriorityKey                  = bsonutil.MustHaveTag(ProjectRef{}, "Priority")
+	projectRefStepbackBisectKey            = bsonutil.MustHaveTag(ProjectRef{}, "StepbackBisect")
+	projectRefTestSelectionEnabledKey      = bsonutil.MustHaveTag(ProjectRef{}, "TestSelectionEnabled")
+	projectRefFullRunIntervalHoursKey      = bsonutil.MustHaveTag(ProjectRef{}, "FullRunIntervalHours")
+	projectRefNotifyOnFailureKey           = bsonutil.MustHaveTag(ProjectRef{}, "NotifyOnBuildFailure")
+	projectRefBuildBreakTeamChannelKey     = bsonutil.MustHaveTag(ProjectRef{}, "BuildBreakTeamChannel")
+	projectRefBuildBreakEscalationMinsKey  = bsonutil.MustHaveTag(ProjectRef{}, "BuildBreakEscalationMins")
+	projectRefCommitQueueKey               = bsonutil.MustHaveTag(ProjectRef{}, "CommitQueue")
+	commitQueueEnabledKey                  = bsonutil.MustHaveTag(CommitQueueParams{}, "Enabled")
+	projectRefTriggersKey                  = bsonutil.MustHaveTag(ProjectRef{}, "Triggers")
 )
 
 const (
 	ProjectRefCollection = "project_ref"
+
+	// DefaultPatchExpirationDays is how long a patch can go unfinalized
+	// before it is removed, unless the project configures otherwise.
+	DefaultPatchExpirationDays = 30
+
+	// DefaultFullRunIntervalHours is how often a project with test selection
+	// runs every task of a mainline version, unless it configures otherwise.
+	DefaultFullRunIntervalHours = 24
 )
 
 func (projectRef *ProjectRef) Insert() error {
@@ -176,6 +332,41 @@ func FindAllTrackedProjectRefs() ([]ProjectRef, error) {
 	return projectRefs, err
 }
 
+// FindProjectRefsWithCommitQueueEnabled returns the enabled project refs
+// that have a commit queue
+func FindProjectRefsWithCommitQueueEnabled() ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAll(
+		ProjectRefCollection,
+		bson.M{
+			ProjectRefEnabledKey: true,
+			bsonutil.GetDottedKeyName(projectRefCommitQueueKey, commitQueueEnabledKey): true,
+		},
+		db.NoProjection,
+		db.NoSort,
+		db.NoSkip,
+		db.NoLimit,
+		&projectRefs,
+	)
+	return projectRefs, err
+}
+
+// FindProjectRefsWithPriority returns the project refs that set a priority for
+// their tasks to inherit, with only their identifiers and priorities.
+func FindProjectRefsWithPriority() ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAll(
+		ProjectRefCollection,
+		bson.M{projectRefPriorityKey: bson.M{"$ne": 0, "$exists": true}},
+		bson.M{ProjectRefIdentifierKey: 1, projectRefPriorityKey: 1},
+		db.NoSort,
+		db.NoSkip,
+		db.NoLimit,
+		&projectRefs,
+	)
+	return projectRefs, err
+}
+
 // FindAllProjectRefs returns all project refs in the db
 func FindAllProjectRefs() ([]ProjectRef, error) {
 	projectRefs := []ProjectRef{}
@@ -217,6 +408,31 @@ func FindProjectRefsByRepoAndBranch(owner, repoName, branch string) ([]ProjectRe
 	return projectRefs, err
 }
 
+// FindProjectRefsByRepo finds enabled ProjectRefs tracking any branch of the
+// given repo
+func FindProjectRefsByRepo(owner, repoName string) ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+
+	err := db.FindAll(
+		ProjectRefCollection,
+		bson.M{
+			ProjectRefOwnerKey:   owner,
+			ProjectRefRepoKey:    repoName,
+			ProjectRefEnabledKey: true,
+		},
+		db.NoProjection,
+		db.NoSort,
+		db.NoSkip,
+		db.NoLimit,
+		&projectRefs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return projectRefs, err
+}
+
 // FindOneProjectRefByRepoAndBranch finds a signle ProjectRef with matching
 // repo/branch that is enabled and setup for PR testing. If more than one
 // is found, an error is returned
@@ -297,6 +513,28 @@ func UntrackStaleProjectRefs(activeProjects []string) error {
 	return err
 }
 
+// UserRole returns the user's role on the project, not counting superusers.
+// Users who aren't listed are contributors if the project doesn't list any,
+// which lets every user patch the project, and viewers otherwise, unless
+// the project is private and lists its viewers, in which case they have no
+// role.
+func (projectRef *ProjectRef) UserRole(username string) user.Role {
+	switch {
+	case util.StringSliceContains(projectRef.Admins, username):
+		return user.RoleProjectAdmin
+	case util.StringSliceContains(projectRef.Contributors, username):
+		return user.RoleContributor
+	case util.StringSliceContains(projectRef.Viewers, username):
+		return user.RoleViewer
+	case len(projectRef.Contributors) == 0:
+		return user.RoleContributor
+	case projectRef.Private && len(projectRef.Viewers) > 0:
+		return user.RoleNone
+	default:
+		return user.RoleViewer
+	}
+}
+
 // Upsert updates the project ref in the db if an entry already exists,
 // overwriting the existing ref. If no project ref exists, one is created
 func (projectRef *ProjectRef) Upsert() error {
@@ -307,26 +545,38 @@ func (projectRef *ProjectRef) Upsert() error {
 		},
 		bson.M{
 			"$set": bson.M{
-				ProjectRefRepoKindKey:           projectRef.RepoKind,
-				ProjectRefEnabledKey:            projectRef.Enabled,
-				ProjectRefPrivateKey:            projectRef.Private,
-				ProjectRefBatchTimeKey:          projectRef.BatchTime,
-				ProjectRefOwnerKey:              projectRef.Owner,
-				ProjectRefRepoKey:               projectRef.Repo,
-				ProjectRefBranchKey:             projectRef.Branch,
-				ProjectRefDisplayNameKey:        projectRef.DisplayName,
-				ProjectRefDeactivatePreviousKey: projectRef.DeactivatePrevious,
-				ProjectRefTrackedKey:            projectRef.Tracked,
-				ProjectRefRemotePathKey:         projectRef.RemotePath,
-				ProjectRefTrackedKey:            projectRef.Tracked,
-				ProjectRefLocalConfig:           projectRef.LocalConfig,
-				ProjectRefRepotrackerError:      projectRef.RepotrackerError,
-				ProjectRefAdminsKey:             projectRef.Admins,
-				projectRefTracksPushEventsKey:   projectRef.TracksPushEvents,
-				projectRefPRTestingEnabledKey:   projectRef.PRTestingEnabled,
-				projectRefPatchingDisabledKey:   projectRef.PatchingDisabled,
-				projectRefNotifyOnFailureKey:    projectRef.NotifyOnBuildFailure,
-				projectRefTriggersKey:           projectRef.Triggers,
+				ProjectRefRepoKindKey:                  projectRef.RepoKind,
+				ProjectRefEnabledKey:                   projectRef.Enabled,
+				ProjectRefPrivateKey:                   projectRef.Private,
+				ProjectRefBatchTimeKey:                 projectRef.BatchTime,
+				ProjectRefOwnerKey:                     projectRef.Owner,
+				ProjectRefRepoKey:                      projectRef.Repo,
+				ProjectRefBranchKey:                    projectRef.Branch,
+				ProjectRefDisplayNameKey:               projectRef.DisplayName,
+				ProjectRefDeactivatePreviousKey:        projectRef.DeactivatePrevious,
+				projectRefSuppressInheritedWarningsKey: projectRef.SuppressInheritedWarnings,
+				ProjectRefTrackedKey:                   projectRef.Tracked,
+				ProjectRefRemotePathKey:                projectRef.RemotePath,
+				ProjectRefTrackedKey:                   projectRef.Tracked,
+				ProjectRefLocalConfig:                  projectRef.LocalConfig,
+				ProjectRefRepotrackerError:             projectRef.RepotrackerError,
+				ProjectRefAdminsKey:                    projectRef.Admins,
+				projectRefContributorsKey:              projectRef.Contributors,
+				projectRefViewersKey:                   projectRef.Viewers,
+				projectRefTracksPushEventsKey:          projectRef.TracksPushEvents,
+				projectRefPRTestingEnabledKey:          projectRef.PRTestingEnabled,
+				projectRefGithubChecksEnabledKey:       projectRef.GithubChecksEnabled,
+				projectRefPatchingDisabledKey:          projectRef.PatchingDisabled,
+				projectRefPatchExpirationDaysKey:       projectRef.PatchExpirationDays,
+				projectRefPriorityKey:                  projectRef.Priority,
+				projectRefStepbackBisectKey:            projectRef.StepbackBisect,
+				projectRefTestSelectionEnabledKey:      projectRef.TestSelectionEnabled,
+				projectRefFullRunIntervalHoursKey:      projectRef.FullRunIntervalHours,
+				projectRefNotifyOnFailureKey:           projectRef.NotifyOnBuildFailure,
+				projectRefBuildBreakTeamChannelKey:     projectRef.BuildBreakTeamChannel,
+				projectRefCommitQueueKey:               projectRef.CommitQueue,
+				projectRefBuildBreakEscalationMinsKey:  projectRef.BuildBreakEscalationMins,
+				projectRefTriggersKey:                  projectRef.Triggers,
 			},
 		},
 	)
@@ -338,6 +588,64 @@ func (projectRef *ProjectRef) String() string {
 	return projectRef.Identifier
 }
 
+// SetPriority sets the priority that the project's tasks inherit when no
+// narrower scope sets one.
+func (projectRef *ProjectRef) SetPriority(priority int64) error {
+	if priority < 0 {
+		return errors.New("project priority can't be negative")
+	}
+	projectRef.Priority = priority
+	return db.Update(
+		ProjectRefCollection,
+		bson.M{ProjectRefIdentifierKey: projectRef.Identifier},
+		bson.M{"$set": bson.M{projectRefPriorityKey: priority}},
+	)
+}
+
+// RepotrackerDisabled returns whether the repotracker capability is disabled
+// for the project.
+func (projectRef *ProjectRef) RepotrackerDisabled(capability RepotrackerCapability) bool {
+	return projectRef.RepotrackerError.IsDisabled(capability)
+}
+
+// DisableRepotracker disables the repotracker capability for the project; the
+// caller is responsible for saving the project ref.
+func (projectRef *ProjectRef) DisableRepotracker(capability RepotrackerCapability, reason string, duration time.Duration) {
+	if projectRef.RepotrackerError == nil {
+		projectRef.RepotrackerError = &RepositoryErrorDetails{}
+	}
+	projectRef.RepotrackerError.Disable(capability, reason, duration)
+}
+
+// EnableRepotracker re-enables the repotracker capability for the project;
+// the caller is responsible for saving the project ref.
+func (projectRef *ProjectRef) EnableRepotracker(capability RepotrackerCapability) {
+	if projectRef.RepotrackerError == nil {
+		return
+	}
+	projectRef.RepotrackerError.Enable(capability)
+}
+
+// GetPatchExpiration returns how long a patch of the project can go
+// unfinalized before it is removed.
+func (projectRef *ProjectRef) GetPatchExpiration() time.Duration {
+	days := projectRef.PatchExpirationDays
+	if days <= 0 {
+		days = DefaultPatchExpirationDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetFullRunInterval returns how long a project with test selection can go
+// between mainline versions that run every task.
+func (projectRef *ProjectRef) GetFullRunInterval() time.Duration {
+	hours := projectRef.FullRunIntervalHours
+	if hours <= 0 {
+		hours = DefaultFullRunIntervalHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
 // GetBatchTime returns the Batch Time of the ProjectRef
 func (p *ProjectRef) GetBatchTime(variant *BuildVariant) int {
 	var val int = p.BatchTime
@@ -3,8 +3,10 @@ package model
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -211,3 +213,58 @@ func TestFindOneProjectRefByRepoAndBranchWithPRTesting(t *testing.T) {
 	assert.Contains(err.Error(), "found 2 project refs, when 1 was expected")
 	require.Nil(projectRef)
 }
+
+func TestRepotrackerDisableStates(t *testing.T) {
+	assert := assert.New(t)
+
+	ref := &ProjectRef{Identifier: "iden"}
+	assert.False(ref.RepotrackerDisabled(RepotrackerRevisions))
+	ref.EnableRepotracker(RepotrackerRevisions)
+	assert.Nil(ref.RepotrackerError)
+
+	ref.DisableRepotracker(RepotrackerRevisions, "base revision not found", 0)
+	ref.DisableRepotracker(RepotrackerActivation, "paused", time.Hour)
+	assert.True(ref.RepotrackerDisabled(RepotrackerRevisions))
+	assert.True(ref.RepotrackerDisabled(RepotrackerActivation))
+	assert.False(ref.RepotrackerDisabled(RepotrackerConfigFetch))
+
+	state := ref.RepotrackerError.GetDisableState(RepotrackerRevisions)
+	assert.Equal("base revision not found", state.Reason)
+	assert.True(state.Expires.IsZero())
+
+	// disabling again replaces the existing state
+	ref.DisableRepotracker(RepotrackerActivation, "paused again", time.Hour)
+	assert.Len(ref.RepotrackerError.Disabled, 2)
+	assert.Equal("paused again", ref.RepotrackerError.GetDisableState(RepotrackerActivation).Reason)
+
+	// expired states no longer disable the capability
+	ref.RepotrackerError.Disabled[1].Expires = time.Now().Add(-time.Minute)
+	assert.False(ref.RepotrackerDisabled(RepotrackerActivation))
+
+	// enabling removes the state along with any expired states
+	ref.EnableRepotracker(RepotrackerRevisions)
+	assert.False(ref.RepotrackerDisabled(RepotrackerRevisions))
+	assert.Empty(ref.RepotrackerError.Disabled)
+}
+
+func TestProjectRefUserRole(t *testing.T) {
+	assert := assert.New(t)
+
+	ref := &ProjectRef{Identifier: "mci", Admins: []string{"admin"}}
+	assert.Equal(user.RoleProjectAdmin, ref.UserRole("admin"))
+	assert.Equal(user.RoleContributor, ref.UserRole("anyone"))
+
+	ref.Contributors = []string{"contributor"}
+	ref.Viewers = []string{"viewer"}
+	assert.Equal(user.RoleProjectAdmin, ref.UserRole("admin"))
+	assert.Equal(user.RoleContributor, ref.UserRole("contributor"))
+	assert.Equal(user.RoleViewer, ref.UserRole("viewer"))
+	assert.Equal(user.RoleViewer, ref.UserRole("anyone"))
+
+	ref.Private = true
+	assert.Equal(user.RoleViewer, ref.UserRole("viewer"))
+	assert.Equal(user.RoleNone, ref.UserRole("anyone"))
+
+	ref.Viewers = nil
+	assert.Equal(user.RoleViewer, ref.UserRole("anyone"))
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ProjectSchemaVersion is the newest version of the project config syntax
+// that this version of Evergreen understands. Configs that don't declare a
+// schema version are version 1.
+const ProjectSchemaVersion = 2
+
+// deprecatedProjectFields are the top-level project config fields that
+// schema version 2 deprecated in favor of the project's settings, which
+// take precedence over them.
+var deprecatedProjectFields = []string{"owner", "repo", "branch", "repokind", "remote_path"}
+
+// checkSchemaVersion returns an error if the config declares a schema
+// version that this version of Evergreen doesn't support.
+func checkSchemaVersion(yml []byte) error {
+	header := struct {
+		SchemaVersion int `yaml:"schema_version"`
+	}{}
+	if err := yaml.Unmarshal(yml, &header); err != nil {
+		// leave reporting malformed configs to the parser
+		return nil
+	}
+	if header.SchemaVersion > ProjectSchemaVersion {
+		return errors.Errorf("project config schema version %d is newer than %d, the newest version that this version of Evergreen supports",
+			header.SchemaVersion, ProjectSchemaVersion)
+	}
+	if header.SchemaVersion < 0 {
+		return errors.Errorf("project config schema version %d is invalid", header.SchemaVersion)
+	}
+	return nil
+}
+
+// findDeprecations returns the deprecated syntax used in the intermediate
+// project. It must be called before matrices are expanded, so that each use
+// is reported once.
+func findDeprecations(pp *parserProject) []version.ConfigDeprecation {
+	deprecations := []version.ConfigDeprecation{}
+
+	topLevel := map[string]bool{
+		"owner":       pp.Owner != "",
+		"repo":        pp.Repo != "",
+		"branch":      pp.Branch != "",
+		"repokind":    pp.RepoKind != "",
+		"remote_path": pp.RemotePath != "",
+	}
+	for _, field := range deprecatedProjectFields {
+		if !topLevel[field] {
+			continue
+		}
+		deprecations = append(deprecations, version.ConfigDeprecation{
+			Syntax:      field,
+			Replacement: "the project's settings",
+			Since:       2,
+			Locations:   []string{"project"},
+		})
+	}
+
+	distros := []string{}
+	for _, bv := range pp.BuildVariants {
+		name, tasks := bv.Name, bv.Tasks
+		if bv.matrix != nil {
+			name, tasks = fmt.Sprintf("matrix %s", bv.matrix.Id), bv.matrix.Tasks
+		}
+		for _, t := range tasks {
+			if t.usedDistros {
+				distros = append(distros, fmt.Sprintf("buildvariant '%s' task '%s'", name, t.Name))
+			}
+		}
+	}
+	if len(distros) > 0 {
+		deprecations = append(deprecations, version.ConfigDeprecation{
+			Syntax:      "distros",
+			Replacement: "run_on",
+			Since:       2,
+			Locations:   distros,
+		})
+	}
+
+	if len(deprecations) == 0 {
+		return nil
+	}
+	return deprecations
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestProjectSchemaVersion(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	p, errs := projectFromYAML([]byte(`
+schema_version: 2
+tasks:
+- name: t1
+`))
+	require.Empty(errs)
+	assert.Equal(2, p.SchemaVersion)
+
+	// a newer config is refused before the syntax it may use is parsed
+	_, errs = projectFromYAML([]byte(`
+schema_version: 3
+tasks:
+  t1:
+    commands: []
+`))
+	require.Len(errs, 1)
+	assert.Contains(errs[0].Error(), "schema version 3 is newer than 2")
+
+	_, errs = projectFromYAML([]byte("schema_version: -1"))
+	require.Len(errs, 1)
+	assert.Contains(errs[0].Error(), "invalid")
+}
+
+func TestFindDeprecations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+owner: evergreen-ci
+repo: evergreen
+axes:
+- id: os
+  values:
+  - id: linux
+buildvariants:
+- name: bv1
+  tasks:
+  - name: t1
+    distros: d1
+  - name: t2
+    run_on: d1
+- matrix_name: m
+  matrix_spec:
+    os: "*"
+  tasks:
+  - name: t1
+    distros: d1
+tasks:
+- name: t1
+- name: t2
+`
+	p, errs := projectFromYAML([]byte(yml))
+	require.Empty(errs)
+	require.Len(p.Deprecations, 3)
+	assert.Equal("owner", p.Deprecations[0].Syntax)
+	assert.Equal("repo", p.Deprecations[1].Syntax)
+	distros := p.Deprecations[2]
+	assert.Equal("distros", distros.Syntax)
+	assert.Equal("run_on", distros.Replacement)
+	assert.Equal(2, distros.Since)
+	assert.Equal([]string{"buildvariant 'bv1' task 't1'", "buildvariant 'matrix m' task 't1'"}, distros.Locations)
+
+	// stored configs are written with the replacement syntax, so they
+	// aren't reported when they're parsed again
+	p.Owner, p.Repo = "", ""
+	stored, err := yaml.Marshal(p)
+	require.NoError(err)
+	assert.NotContains(string(stored), "distros")
+	p, errs = projectFromYAML(stored)
+	require.Empty(errs)
+	assert.Empty(p.Deprecations)
+	assert.Equal([]string{"d1"}, p.BuildVariants[0].Tasks[0].Distros)
+}
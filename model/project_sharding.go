@@ -0,0 +1,186 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/testruntime"
+	"github.com/pkg/errors"
+)
+
+// shardTaskName returns the name of one shard of a sharded task.
+func shardTaskName(name string, index int) string {
+	return fmt.Sprintf("%s_shard_%d", name, index)
+}
+
+// expandShardedTasks replaces each task that declares more than one shard
+// with its shard tasks, and points the variants, display tasks, task groups
+// and dependencies that reference the task at its shards instead. Variants
+// that run the task get a display task with the task's name, so the shards
+// are shown together, unless the task is already part of a display task.
+func expandShardedTasks(proj *Project) {
+	shards := map[string][]string{}
+	tasks := make([]ProjectTask, 0, len(proj.Tasks))
+	for _, t := range proj.Tasks {
+		if t.Shards <= 1 {
+			tasks = append(tasks, t)
+			continue
+		}
+		for i := 0; i < t.Shards; i++ {
+			shard := t
+			shard.Name = shardTaskName(t.Name, i)
+			shard.Shards = 0
+			shard.ShardOf = t.Name
+			shard.ShardIndex = i
+			shard.ShardCount = t.Shards
+			shards[t.Name] = append(shards[t.Name], shard.Name)
+			tasks = append(tasks, shard)
+		}
+	}
+	if len(shards) == 0 {
+		return
+	}
+
+	proj.Tasks = tasks
+	for i := range proj.Tasks {
+		proj.Tasks[i].DependsOn = expandShardDependencies(proj.Tasks[i].DependsOn, shards)
+		proj.Tasks[i].Requires = expandShardRequirements(proj.Tasks[i].Requires, shards)
+	}
+	for i := range proj.TaskGroups {
+		proj.TaskGroups[i].Tasks = expandShardNames(proj.TaskGroups[i].Tasks, shards)
+	}
+
+	for i := range proj.BuildVariants {
+		bv := &proj.BuildVariants[i]
+		displayed := map[string]bool{}
+		for j := range bv.DisplayTasks {
+			for _, et := range bv.DisplayTasks[j].ExecutionTasks {
+				displayed[et] = true
+			}
+			bv.DisplayTasks[j].ExecutionTasks = expandShardNames(bv.DisplayTasks[j].ExecutionTasks, shards)
+		}
+
+		units := make([]BuildVariantTaskUnit, 0, len(bv.Tasks))
+		for _, unit := range bv.Tasks {
+			unit.DependsOn = expandShardDependencies(unit.DependsOn, shards)
+			unit.Requires = expandShardRequirements(unit.Requires, shards)
+			names, ok := shards[unit.Name]
+			if !ok || unit.IsGroup {
+				units = append(units, unit)
+				continue
+			}
+			for _, name := range names {
+				shardUnit := unit
+				shardUnit.Name = name
+				units = append(units, shardUnit)
+			}
+			if !displayed[unit.Name] {
+				bv.DisplayTasks = append(bv.DisplayTasks, DisplayTask{
+					Name:           unit.Name,
+					ExecutionTasks: names,
+				})
+			}
+		}
+		bv.Tasks = units
+	}
+}
+
+func expandShardNames(names []string, shards map[string][]string) []string {
+	if len(names) == 0 {
+		return names
+	}
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		if shardNames, ok := shards[name]; ok {
+			out = append(out, shardNames...)
+		} else {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func expandShardDependencies(deps []TaskUnitDependency, shards map[string][]string) []TaskUnitDependency {
+	if len(deps) == 0 {
+		return deps
+	}
+	out := make([]TaskUnitDependency, 0, len(deps))
+	for _, dep := range deps {
+		shardNames, ok := shards[dep.Name]
+		if !ok {
+			out = append(out, dep)
+			continue
+		}
+		for _, name := range shardNames {
+			shardDep := dep
+			shardDep.Name = name
+			out = append(out, shardDep)
+		}
+	}
+	return out
+}
+
+func expandShardRequirements(reqs []TaskUnitRequirement, shards map[string][]string) []TaskUnitRequirement {
+	if len(reqs) == 0 {
+		return reqs
+	}
+	out := make([]TaskUnitRequirement, 0, len(reqs))
+	for _, req := range reqs {
+		shardNames, ok := shards[req.Name]
+		if !ok {
+			out = append(out, req)
+			continue
+		}
+		for _, name := range shardNames {
+			out = append(out, TaskUnitRequirement{Name: name, Variant: req.Variant})
+		}
+	}
+	return out
+}
+
+// shardAssigner balances the tests of the sharded tasks of a build variant
+// across their shards by the tests' historical runtimes. Each sharded task
+// is balanced once, so all of its shards agree on the split.
+type shardAssigner struct {
+	project string
+	variant string
+	shards  map[string][][]string
+	// findRuntimes returns the historical runtimes of the tests of a task,
+	// which is a lookup of the stored runtimes unless the tests are
+	// balanced without them.
+	findRuntimes func(project, variant, task string) (map[string]time.Duration, error)
+}
+
+func newShardAssigner(project, variant string) *shardAssigner {
+	return &shardAssigner{
+		project:      project,
+		variant:      variant,
+		shards:       map[string][][]string{},
+		findRuntimes: testruntime.FindByTask,
+	}
+}
+
+// newLocalShardAssigner returns a shard assigner that balances tests as if
+// they had no history, for evaluating a project without the database.
+func newLocalShardAssigner(project, variant string) *shardAssigner {
+	a := newShardAssigner(project, variant)
+	a.findRuntimes = func(string, string, string) (map[string]time.Duration, error) { return nil, nil }
+	return a
+}
+
+// testsForShard returns the tests that the shard task should run.
+func (a *shardAssigner) testsForShard(spec ProjectTask) ([]string, error) {
+	shards, ok := a.shards[spec.ShardOf]
+	if !ok {
+		runtimes, err := a.findRuntimes(a.project, a.variant, spec.ShardOf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding test runtimes for task '%s'", spec.ShardOf)
+		}
+		shards = testruntime.Balance(spec.Tests, runtimes, spec.ShardCount)
+		a.shards[spec.ShardOf] = shards
+	}
+	if spec.ShardIndex >= len(shards) {
+		return nil, errors.Errorf("task '%s' has no shard %d", spec.ShardOf, spec.ShardIndex)
+	}
+	return shards[spec.ShardIndex], nil
+}
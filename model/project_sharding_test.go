@@ -0,0 +1,116 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/testruntime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandShardedTasks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+tasks:
+- name: unit
+  shards: 2
+  tests: [a, b, c]
+- name: lint
+- name: deploy
+  depends_on:
+  - name: unit
+task_groups:
+- name: group
+  tasks: [unit, lint]
+buildvariants:
+- name: bv
+  tasks:
+  - name: unit
+  - name: lint
+    depends_on:
+    - name: unit
+      variant: bv
+  - name: deploy
+- name: displayed
+  tasks:
+  - name: unit
+  display_tasks:
+  - name: tests
+    execution_tasks: [unit]
+`
+	p := &Project{}
+	require.NoError(LoadProjectInto([]byte(yml), "proj", p))
+
+	require.Len(p.Tasks, 4)
+	for i, name := range []string{"unit_shard_0", "unit_shard_1"} {
+		spec := p.GetSpecForTask(name)
+		assert.Equal(name, spec.Name)
+		assert.Equal("unit", spec.ShardOf)
+		assert.Equal(i, spec.ShardIndex)
+		assert.Equal(2, spec.ShardCount)
+		assert.Zero(spec.Shards)
+		assert.Equal([]string{"a", "b", "c"}, spec.Tests)
+	}
+	assert.Empty(p.GetSpecForTask("unit").Name)
+	deploy := p.GetSpecForTask("deploy")
+	require.Len(deploy.DependsOn, 2)
+	assert.Equal("unit_shard_0", deploy.DependsOn[0].Name)
+	assert.Equal("unit_shard_1", deploy.DependsOn[1].Name)
+	assert.Equal([]string{"unit_shard_0", "unit_shard_1", "lint"}, p.FindTaskGroup("group").Tasks)
+
+	bv := p.FindBuildVariant("bv")
+	require.NotNil(bv)
+	require.Len(bv.Tasks, 4)
+	assert.Equal("unit_shard_0", bv.Tasks[0].Name)
+	assert.Equal("unit_shard_1", bv.Tasks[1].Name)
+	require.Len(bv.Tasks[2].DependsOn, 2)
+	assert.Equal("bv", bv.Tasks[2].DependsOn[1].Variant)
+	require.Len(bv.DisplayTasks, 1)
+	assert.Equal("unit", bv.DisplayTasks[0].Name)
+	assert.Equal([]string{"unit_shard_0", "unit_shard_1"}, bv.DisplayTasks[0].ExecutionTasks)
+
+	// shards of a task that is already displayed join that display task
+	displayed := p.FindBuildVariant("displayed")
+	require.NotNil(displayed)
+	require.Len(displayed.DisplayTasks, 1)
+	assert.Equal("tests", displayed.DisplayTasks[0].Name)
+	assert.Equal([]string{"unit_shard_0", "unit_shard_1"}, displayed.DisplayTasks[0].ExecutionTasks)
+}
+
+func TestShardAssigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(testruntime.Collection))
+	defer func() {
+		assert.NoError(db.Clear(testruntime.Collection))
+	}()
+
+	require.NoError(testruntime.Update("proj", "bv", "unit", map[string]time.Duration{
+		"a": 3 * time.Minute,
+		"b": 2 * time.Minute,
+		"c": time.Minute,
+	}))
+	spec := ProjectTask{
+		Name:       "unit_shard_0",
+		Tests:      []string{"a", "b", "c"},
+		ShardOf:    "unit",
+		ShardCount: 2,
+	}
+	shards := newShardAssigner("proj", "bv")
+	tests, err := shards.testsForShard(spec)
+	require.NoError(err)
+	assert.Equal([]string{"a"}, tests)
+
+	spec.ShardIndex = 1
+	tests, err = shards.testsForShard(spec)
+	require.NoError(err)
+	assert.Equal([]string{"b", "c"}, tests)
+
+	spec.ShardIndex = 2
+	_, err = shards.testsForShard(spec)
+	assert.Error(err)
+}
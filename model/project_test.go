@@ -443,6 +443,41 @@ func TestPopulateExpansions(t *testing.T) {
 	assert.Equal("octocat", expansions.Get("github_author"))
 	assert.Equal("42", expansions.Get("github_pr_number"))
 	assert.Equal("wut?", expansions.Get("github_org"))
+
+	// parameters override the variant's expansions
+	v.Parameters = []version.Parameter{{Key: "cake", Value: "real"}, {Key: "suite", Value: "smoke"}}
+	expansions = populateExpansions(d, v, bv, taskDoc, patchDoc)
+	assert.Len(map[string]string(*expansions), 22)
+	assert.Equal("real", expansions.Get("cake"))
+	assert.Equal("smoke", expansions.Get("suite"))
+}
+
+func TestResolveParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &Project{
+		Parameters: []ParameterDefinition{
+			{Key: "suite", Value: "smoke"},
+			{Key: "iterations", Value: "1"},
+		},
+	}
+
+	params, err := p.ResolveParameters(nil)
+	assert.NoError(err)
+	assert.Equal([]version.Parameter{{Key: "suite", Value: "smoke"}, {Key: "iterations", Value: "1"}}, params)
+
+	params, err = p.ResolveParameters([]version.Parameter{{Key: "iterations", Value: "10"}})
+	assert.NoError(err)
+	assert.Equal([]version.Parameter{{Key: "suite", Value: "smoke"}, {Key: "iterations", Value: "10"}}, params)
+
+	_, err = p.ResolveParameters([]version.Parameter{{Key: "undefined", Value: "true"}})
+	assert.Error(err)
+	_, err = p.ResolveParameters([]version.Parameter{{Key: "suite", Value: "a"}, {Key: "suite", Value: "b"}})
+	assert.Error(err)
+
+	params, err = (&Project{}).ResolveParameters(nil)
+	assert.NoError(err)
+	assert.Empty(params)
 }
 
 type projectSuite struct {
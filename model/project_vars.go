@@ -1,8 +1,17 @@
 package model
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -12,6 +21,7 @@ var (
 	projectVarIdKey   = bsonutil.MustHaveTag(ProjectVars{}, "Id")
 	projectVarsMapKey = bsonutil.MustHaveTag(ProjectVars{}, "Vars")
 	privateVarsMapKey = bsonutil.MustHaveTag(ProjectVars{}, "PrivateVars")
+	secretVarsMapKey  = bsonutil.MustHaveTag(ProjectVars{}, "SecretVars")
 )
 
 const (
@@ -35,6 +45,20 @@ type ProjectVars struct {
 	//PrivateVars keeps track of which variables are private and should therefore not
 	//be returned to the UI server.
 	PrivateVars map[string]bool `bson:"private_vars" json:"private_vars"`
+
+	// SecretVars are variables whose values are kept in a secrets manager
+	// rather than in the database. Only the references to the secrets are
+	// stored, and their values are fetched when a task is dispatched.
+	SecretVars map[string]SecretReference `bson:"secret_vars,omitempty" json:"secret_vars"`
+}
+
+// SecretReference names a secret in one of the secrets managers configured
+// in the admin settings. Key, if set, selects one of the values of a secret
+// that holds several.
+type SecretReference struct {
+	Backend string `bson:"backend" json:"backend"`
+	Name    string `bson:"name" json:"name"`
+	Key     string `bson:"key,omitempty" json:"key,omitempty"`
 }
 
 type AWSSSHKey struct {
@@ -95,6 +119,7 @@ func (projectVars *ProjectVars) Upsert() (*mgo.ChangeInfo, error) {
 			"$set": bson.M{
 				projectVarsMapKey: projectVars.Vars,
 				privateVarsMapKey: projectVars.PrivateVars,
+				secretVarsMapKey:  projectVars.SecretVars,
 			},
 		},
 	)
@@ -119,3 +144,156 @@ func (projectVars *ProjectVars) RedactPrivateVars() {
 		}
 	}
 }
+
+// NewSecretsBackends returns the secrets managers that are configured, keyed
+// by the names that secret references use for them.
+func NewSecretsBackends(conf *evergreen.SecretsConfig) (map[string]thirdparty.SecretsBackend, error) {
+	backends := map[string]thirdparty.SecretsBackend{}
+	if conf.AWSEnabled() {
+		backend, err := thirdparty.NewAWSSecretsManager(conf.AWS.Region, conf.AWS.Key, conf.AWS.Secret)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem creating AWS Secrets Manager client")
+		}
+		backends[thirdparty.SecretsBackendAWS] = backend
+	}
+	if conf.VaultEnabled() {
+		backends[thirdparty.SecretsBackendVault] = thirdparty.NewVaultSecrets(conf.Vault.URL, conf.Vault.Token, conf.Vault.MountPath)
+	}
+	return backends, nil
+}
+
+// SecretNamespace returns the path that the secrets of the project are named
+// under. Projects can only reference the secrets in their own namespace, so
+// that they can't read the secrets of other projects.
+func SecretNamespace(prefix, projectID string) string {
+	if prefix == "" {
+		return projectID + "/"
+	}
+	return fmt.Sprintf("%s/%s/", strings.Trim(prefix, "/"), projectID)
+}
+
+// checkSecretReference returns an error if the secret variable doesn't
+// reference a secret in the namespace.
+func checkSecretReference(name string, ref SecretReference, namespace string) error {
+	if ref.Name == "" {
+		return errors.Errorf("secret variable '%s' must name a secret", name)
+	}
+	if !strings.HasPrefix(ref.Name, namespace) || len(ref.Name) == len(namespace) {
+		return errors.Errorf("secret variable '%s' must name a secret under '%s'", name, namespace)
+	}
+	for _, part := range strings.Split(ref.Name, "/") {
+		if part == "" || part == "." || part == ".." {
+			return errors.Errorf("secret variable '%s' has invalid secret name '%s'", name, ref.Name)
+		}
+	}
+	return nil
+}
+
+// ValidateSecretVars checks that the project's secret variables reference
+// secrets in known backends, within the project's namespace under the
+// prefix.
+func (projectVars *ProjectVars) ValidateSecretVars(prefix string) error {
+	catcher := grip.NewBasicCatcher()
+	namespace := SecretNamespace(prefix, projectVars.Id)
+	for name, ref := range projectVars.SecretVars {
+		if !util.StringSliceContains(thirdparty.ValidSecretsBackends, ref.Backend) {
+			catcher.Add(errors.Errorf("secret variable '%s' has invalid backend '%s'", name, ref.Backend))
+		}
+		catcher.Add(checkSecretReference(name, ref, namespace))
+	}
+	return catcher.Resolve()
+}
+
+// ResolveVars returns the project's variables along with the values of its
+// secret variables fetched from their backends, and which of them are
+// private. Secret variables are always private, so that their values are
+// redacted from task logs. Secrets outside of the project's namespace under
+// the prefix are never fetched.
+func (projectVars *ProjectVars) ResolveVars(ctx context.Context, backends map[string]thirdparty.SecretsBackend, prefix string) (map[string]string, map[string]bool, error) {
+	vars := map[string]string{}
+	private := map[string]bool{}
+	if projectVars == nil {
+		return vars, private, nil
+	}
+	for k, v := range projectVars.Vars {
+		vars[k] = v
+	}
+	for k, v := range projectVars.PrivateVars {
+		private[k] = v
+	}
+
+	namespace := SecretNamespace(prefix, projectVars.Id)
+	for name, ref := range projectVars.SecretVars {
+		if err := checkSecretReference(name, ref, namespace); err != nil {
+			return nil, nil, err
+		}
+		backend, ok := backends[ref.Backend]
+		if !ok {
+			return nil, nil, errors.Errorf("secrets backend '%s' of variable '%s' is not configured", ref.Backend, name)
+		}
+		value, err := backend.GetSecret(ctx, ref.Name, ref.Key)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "problem fetching secret variable '%s'", name)
+		}
+		vars[name] = value
+		private[name] = true
+	}
+
+	return vars, private, nil
+}
+
+// MigrateProjectVarsToSecrets moves the values of a project's variables
+// into a secrets manager, replacing them with references to the secrets,
+// and returns the names of the variables that were moved. The secrets are
+// named after the variables, in the project's namespace under the prefix. If
+// privateOnly is set, only private variables are moved.
+func MigrateProjectVarsToSecrets(ctx context.Context, projectID, backendName string, backend thirdparty.SecretsBackend, prefix string, privateOnly bool) ([]string, error) {
+	projectVars, err := FindOneProjectVars(projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding variables of project '%s'", projectID)
+	}
+	if projectVars == nil {
+		return []string{}, nil
+	}
+	if projectVars.SecretVars == nil {
+		projectVars.SecretVars = map[string]SecretReference{}
+	}
+
+	names := []string{}
+	for name := range projectVars.Vars {
+		// the project's AWS key is read by the app server itself
+		if name == ProjectAWSSSHKeyName || name == ProjectAWSSSHKeyValue {
+			continue
+		}
+		if privateOnly && !projectVars.PrivateVars[name] {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	migrated := []string{}
+	catcher := grip.NewBasicCatcher()
+	namespace := SecretNamespace(prefix, projectID)
+	for _, name := range names {
+		secretName := namespace + name
+		if err = backend.PutSecret(ctx, secretName, projectVars.Vars[name]); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem migrating variable '%s'", name))
+			continue
+		}
+		projectVars.SecretVars[name] = SecretReference{Backend: backendName, Name: secretName}
+		delete(projectVars.Vars, name)
+		delete(projectVars.PrivateVars, name)
+		migrated = append(migrated, name)
+	}
+
+	// variables that were stored in the backend are saved as references even
+	// if others failed, so that their plaintext values don't linger
+	if len(migrated) > 0 {
+		if _, err = projectVars.Upsert(); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem saving variables of project '%s'", projectID))
+		}
+	}
+
+	return migrated, catcher.Resolve()
+}
@@ -1,10 +1,13 @@
 package model
 
 import (
+	"context"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -140,3 +143,124 @@ func TestAWSVars(t *testing.T) {
 	assert.Equal(false, found.PrivateVars[ProjectAWSSSHKeyName])
 	assert.Equal(true, found.PrivateVars[ProjectAWSSSHKeyValue])
 }
+
+type mockSecretsBackend struct {
+	secrets map[string]string
+}
+
+func (b *mockSecretsBackend) GetSecret(ctx context.Context, name, key string) (string, error) {
+	value, ok := b.secrets[name+key]
+	if !ok {
+		return "", errors.Errorf("secret '%s' not found", name)
+	}
+	return value, nil
+}
+
+func (b *mockSecretsBackend) PutSecret(ctx context.Context, name, value string) error {
+	b.secrets[name] = value
+	return nil
+}
+
+func TestResolveVars(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	backends := map[string]thirdparty.SecretsBackend{
+		thirdparty.SecretsBackendVault: &mockSecretsBackend{secrets: map[string]string{
+			"mongodb/token":        "secret",
+			"mongodb/sharedpasswd": "hunter2",
+		}},
+	}
+	projectVars := &ProjectVars{
+		Id:          "mongodb",
+		Vars:        map[string]string{"a": "a", "b": "b"},
+		PrivateVars: map[string]bool{"b": true},
+		SecretVars: map[string]SecretReference{
+			"token":    {Backend: thirdparty.SecretsBackendVault, Name: "mongodb/token"},
+			"password": {Backend: thirdparty.SecretsBackendVault, Name: "mongodb/shared", Key: "passwd"},
+		},
+	}
+	assert.NoError(projectVars.ValidateSecretVars(""))
+
+	vars, private, err := projectVars.ResolveVars(ctx, backends, "")
+	require.NoError(err)
+	assert.Equal(map[string]string{"a": "a", "b": "b", "token": "secret", "password": "hunter2"}, vars)
+	assert.Equal(map[string]bool{"b": true, "token": true, "password": true}, private)
+	assert.Len(projectVars.Vars, 2, "resolving shouldn't modify the variables")
+
+	// secrets are only resolved in the project's namespace under the prefix
+	_, _, err = projectVars.ResolveVars(ctx, backends, "evergreen")
+	assert.Error(err)
+
+	projectVars.SecretVars["missing"] = SecretReference{Backend: thirdparty.SecretsBackendVault, Name: "mongodb/missing"}
+	_, _, err = projectVars.ResolveVars(ctx, backends, "")
+	assert.Error(err)
+
+	projectVars.SecretVars["missing"] = SecretReference{Backend: thirdparty.SecretsBackendAWS, Name: "mongodb/token"}
+	_, _, err = projectVars.ResolveVars(ctx, backends, "")
+	assert.Error(err, "the AWS backend isn't configured")
+	delete(projectVars.SecretVars, "missing")
+
+	projectVars.SecretVars["invalid"] = SecretReference{Backend: "keychain", Name: "mongodb/token"}
+	assert.Error(projectVars.ValidateSecretVars(""))
+	delete(projectVars.SecretVars, "invalid")
+
+	for _, name := range []string{"", "mongodb/", "other/token", "mongodbx/token", "mongodb/../other/token", "mongodb//token"} {
+		projectVars.SecretVars["other"] = SecretReference{Backend: thirdparty.SecretsBackendVault, Name: name}
+		assert.Error(projectVars.ValidateSecretVars(""), name)
+		_, _, err = projectVars.ResolveVars(ctx, backends, "")
+		assert.Error(err, name)
+	}
+	prefixed := &ProjectVars{
+		Id:         "mongodb",
+		SecretVars: map[string]SecretReference{"token": {Backend: thirdparty.SecretsBackendVault, Name: "evergreen/mongodb/token"}},
+	}
+	assert.NoError(prefixed.ValidateSecretVars("/evergreen/"))
+	assert.Error(prefixed.ValidateSecretVars(""))
+
+	var none *ProjectVars
+	vars, _, err = none.ResolveVars(ctx, backends, "")
+	assert.NoError(err)
+	assert.Empty(vars)
+}
+
+func TestMigrateProjectVarsToSecrets(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(ProjectVarsCollection))
+
+	projectVars := &ProjectVars{
+		Id: "mongodb",
+		Vars: map[string]string{
+			"a":                   "a",
+			"b":                   "b",
+			ProjectAWSSSHKeyValue: "key",
+		},
+		PrivateVars: map[string]bool{"b": true, ProjectAWSSSHKeyValue: true},
+	}
+	require.NoError(projectVars.Insert())
+
+	ctx := context.Background()
+	backend := &mockSecretsBackend{secrets: map[string]string{}}
+	migrated, err := MigrateProjectVarsToSecrets(ctx, "mongodb", thirdparty.SecretsBackendVault, backend, "evergreen", true)
+	require.NoError(err)
+	assert.Equal([]string{"b"}, migrated)
+	assert.Equal("b", backend.secrets["evergreen/mongodb/b"])
+
+	migrated, err = MigrateProjectVarsToSecrets(ctx, "mongodb", thirdparty.SecretsBackendVault, backend, "", false)
+	require.NoError(err)
+	assert.Equal([]string{"a"}, migrated)
+	assert.Equal("a", backend.secrets["mongodb/a"])
+
+	projectVars, err = FindOneProjectVars("mongodb")
+	require.NoError(err)
+	require.NotNil(projectVars)
+	assert.Equal(map[string]string{ProjectAWSSSHKeyValue: "key"}, projectVars.Vars)
+	assert.Equal(SecretReference{Backend: thirdparty.SecretsBackendVault, Name: "evergreen/mongodb/b"}, projectVars.SecretVars["b"])
+	assert.Equal(SecretReference{Backend: thirdparty.SecretsBackendVault, Name: "mongodb/a"}, projectVars.SecretVars["a"])
+
+	migrated, err = MigrateProjectVarsToSecrets(ctx, "nonexistent", thirdparty.SecretsBackendVault, backend, "", false)
+	assert.NoError(err)
+	assert.Empty(migrated)
+}
@@ -0,0 +1,191 @@
+package projectinsights
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// BuildRun is a finished build of a mainline version.
+type BuildRun struct {
+	Variant string
+	// Order is the revision order number of the version.
+	Order      int
+	Succeeded  bool
+	CreateTime time.Time
+	FinishTime time.Time
+	Duration   time.Duration
+}
+
+// buildBreak is a failed build whose variant's previous build succeeded.
+type buildBreak struct {
+	createTime time.Time
+	detect     time.Duration
+	repair     time.Duration
+	repaired   bool
+}
+
+// Compute returns the insights of a project for each window from the builds
+// of its mainline versions. The builds that a window covers are those
+// created in the days before now, but breaks are found across all of the
+// builds, so the builds should start before the longest window to find the
+// breaks at its start.
+func Compute(project string, runs []BuildRun, now time.Time) []ProjectInsights {
+	breaks := findBreaks(runs)
+
+	insights := make([]ProjectInsights, 0, len(Windows))
+	for _, days := range Windows {
+		start := now.Add(-time.Duration(days) * day)
+		stat := ProjectInsights{
+			Id: ProjectInsightsKey{
+				Project:    project,
+				WindowDays: days,
+			},
+			LastUpdate: now,
+		}
+
+		durations := []time.Duration{}
+		for _, r := range runs {
+			if r.CreateTime.Before(start) || r.CreateTime.After(now) {
+				continue
+			}
+			stat.Builds++
+			if r.Succeeded {
+				stat.SucceededBuilds++
+			}
+			durations = append(durations, r.Duration)
+		}
+		if stat.Builds > 0 {
+			stat.SuccessRate = float64(stat.SucceededBuilds) / float64(stat.Builds)
+		}
+		stat.DurationP50 = percentile(durations, 50)
+		stat.DurationP90 = percentile(durations, 90)
+		stat.DurationP99 = percentile(durations, 99)
+
+		var detect, repair time.Duration
+		repaired := 0
+		for _, b := range breaks {
+			if b.createTime.Before(start) || b.createTime.After(now) {
+				continue
+			}
+			stat.Breaks++
+			detect += b.detect
+			if !b.repaired {
+				stat.OpenBreaks++
+				continue
+			}
+			repaired++
+			repair += b.repair
+		}
+		if stat.Breaks > 0 {
+			stat.MeanTimeToDetect = detect / time.Duration(stat.Breaks)
+		}
+		if repaired > 0 {
+			stat.MeanTimeToRepair = repair / time.Duration(repaired)
+		}
+
+		stat.Periods = computePeriods(runs, start, days)
+		insights = append(insights, stat)
+	}
+
+	return insights
+}
+
+// findBreaks returns the breaks in each variant's builds, in the order of
+// their versions.
+func findBreaks(runs []BuildRun) []buildBreak {
+	byVariant := map[string][]BuildRun{}
+	for _, r := range runs {
+		byVariant[r.Variant] = append(byVariant[r.Variant], r)
+	}
+
+	breaks := []buildBreak{}
+	for _, variantRuns := range byVariant {
+		sort.Slice(variantRuns, func(i, j int) bool { return variantRuns[i].Order < variantRuns[j].Order })
+
+		for i := 1; i < len(variantRuns); i++ {
+			r := variantRuns[i]
+			if r.Succeeded || !variantRuns[i-1].Succeeded {
+				continue
+			}
+			b := buildBreak{
+				createTime: r.CreateTime,
+				detect:     r.FinishTime.Sub(r.CreateTime),
+			}
+			for _, next := range variantRuns[i+1:] {
+				if next.Succeeded {
+					b.repaired = true
+					b.repair = next.FinishTime.Sub(r.FinishTime)
+					break
+				}
+			}
+			breaks = append(breaks, b)
+		}
+	}
+
+	return breaks
+}
+
+// computePeriods divides the window into days, or weeks for windows longer
+// than a month, and summarizes the builds created in each.
+func computePeriods(runs []BuildRun, start time.Time, days int) []Period {
+	length := day
+	if days > 30 {
+		length = 7 * day
+	}
+	count := int(math.Ceil(float64(days) * float64(day) / float64(length)))
+
+	periods := make([]Period, count)
+	durations := make([][]time.Duration, count)
+	succeeded := make([]int, count)
+	for i := range periods {
+		periods[i].Start = start.Add(time.Duration(i) * length)
+	}
+	for _, r := range runs {
+		if r.CreateTime.Before(start) {
+			continue
+		}
+		i := int(r.CreateTime.Sub(start) / length)
+		if i >= count {
+			continue
+		}
+		periods[i].Builds++
+		if r.Succeeded {
+			succeeded[i]++
+		}
+		durations[i] = append(durations[i], r.Duration)
+	}
+	for i := range periods {
+		if periods[i].Builds == 0 {
+			continue
+		}
+		periods[i].SuccessRate = float64(succeeded[i]) / float64(periods[i].Builds)
+		periods[i].DurationP50 = percentile(durations[i], 50)
+		periods[i].DurationP90 = percentile(durations[i], 90)
+	}
+
+	return periods
+}
+
+// percentile returns the duration that the given percent of the durations
+// are within, using the nearest rank. It returns 0 if there are no
+// durations.
+func percentile(durations []time.Duration, percent float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(percent / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,115 @@
+package projectinsights
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Collection is the name of the project insights collection in the
+// database.
+const Collection = "project_insights"
+
+// Windows are the numbers of days before they're computed that insights
+// cover.
+var Windows = []int{7, 30, 90}
+
+// ProjectInsights summarizes how reliable and how fast the builds of a
+// project's mainline versions were over a window of time.
+type ProjectInsights struct {
+	Id ProjectInsightsKey `bson:"_id" json:"id"`
+	// Builds is the number of finished builds created in the window, of
+	// which SucceededBuilds succeeded.
+	Builds          int     `bson:"builds" json:"builds"`
+	SucceededBuilds int     `bson:"succeeded_builds" json:"succeeded_builds"`
+	SuccessRate     float64 `bson:"success_rate" json:"success_rate"`
+	// Breaks is the number of times a variant's build failed after the
+	// previous build of the variant succeeded, of which OpenBreaks haven't
+	// been repaired by a later successful build yet.
+	Breaks     int `bson:"breaks" json:"breaks"`
+	OpenBreaks int `bson:"open_breaks" json:"open_breaks"`
+	// MeanTimeToDetect is the mean time from the creation of a breaking
+	// build to its failure, and MeanTimeToRepair is the mean time from
+	// the failure to the finish of the variant's next successful build.
+	MeanTimeToDetect time.Duration `bson:"mean_time_to_detect" json:"mean_time_to_detect"`
+	MeanTimeToRepair time.Duration `bson:"mean_time_to_repair" json:"mean_time_to_repair"`
+	DurationP50      time.Duration `bson:"duration_p50" json:"duration_p50"`
+	DurationP90      time.Duration `bson:"duration_p90" json:"duration_p90"`
+	DurationP99      time.Duration `bson:"duration_p99" json:"duration_p99"`
+	// Periods divide the window into days, or into weeks for windows
+	// longer than a month, to show the trend across the window.
+	Periods    []Period  `bson:"periods" json:"periods"`
+	LastUpdate time.Time `bson:"last_update" json:"last_update"`
+}
+
+// ProjectInsightsKey identifies the insights of a project over a window.
+type ProjectInsightsKey struct {
+	Project    string `bson:"project" json:"project"`
+	WindowDays int    `bson:"window_days" json:"window_days"`
+}
+
+// Period summarizes the builds created in part of a window.
+type Period struct {
+	Start       time.Time     `bson:"start" json:"start"`
+	Builds      int           `bson:"builds" json:"builds"`
+	SuccessRate float64       `bson:"success_rate" json:"success_rate"`
+	DurationP50 time.Duration `bson:"duration_p50" json:"duration_p50"`
+	DurationP90 time.Duration `bson:"duration_p90" json:"duration_p90"`
+}
+
+var (
+	IdKey         = bsonutil.MustHaveTag(ProjectInsights{}, "Id")
+	LastUpdateKey = bsonutil.MustHaveTag(ProjectInsights{}, "LastUpdate")
+
+	keyProjectKey    = bsonutil.MustHaveTag(ProjectInsightsKey{}, "Project")
+	keyWindowDaysKey = bsonutil.MustHaveTag(ProjectInsightsKey{}, "WindowDays")
+)
+
+// IsWindow returns whether insights are computed over the given number of
+// days.
+func IsWindow(days int) bool {
+	for _, w := range Windows {
+		if w == days {
+			return true
+		}
+	}
+	return false
+}
+
+// ByProject returns the insights of a project, shortest window first. The
+// window narrows the results when it's not 0.
+func ByProject(project string, windowDays int) db.Q {
+	q := bson.M{bsonutil.GetDottedKeyName(IdKey, keyProjectKey): project}
+	if windowDays != 0 {
+		q[bsonutil.GetDottedKeyName(IdKey, keyWindowDaysKey)] = windowDays
+	}
+	return db.Query(q).Sort([]string{bsonutil.GetDottedKeyName(IdKey, keyWindowDaysKey)})
+}
+
+// Find returns the project insights matching the query.
+func Find(query db.Q) ([]ProjectInsights, error) {
+	insights := []ProjectInsights{}
+	err := db.FindAllQ(Collection, query, &insights)
+	return insights, err
+}
+
+// ReplaceProject replaces the stored insights of a project.
+func ReplaceProject(project string, insights []ProjectInsights) error {
+	err := db.RemoveAll(Collection, bson.M{bsonutil.GetDottedKeyName(IdKey, keyProjectKey): project})
+	if err != nil {
+		return errors.Wrapf(err, "problem removing insights of project %s", project)
+	}
+	if len(insights) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(insights))
+	for i := range insights {
+		docs[i] = insights[i]
+	}
+	return errors.Wrapf(db.InsertMany(Collection, docs...),
+		"problem inserting insights of project %s", project)
+}
@@ -0,0 +1,116 @@
+package projectinsights
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestCompute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Date(2019, time.March, 1, 12, 0, 0, 0, time.UTC)
+	run := func(variant string, order int, daysAgo int, succeeded bool, duration time.Duration) BuildRun {
+		created := now.Add(-time.Duration(daysAgo) * day)
+		return BuildRun{
+			Variant:    variant,
+			Order:      order,
+			Succeeded:  succeeded,
+			CreateTime: created,
+			FinishTime: created.Add(duration),
+			Duration:   duration,
+		}
+	}
+	runs := []BuildRun{
+		// a break 60 days ago, repaired by the next build
+		run("bv1", 1, 62, true, time.Hour),
+		run("bv1", 2, 60, false, 2*time.Hour),
+		run("bv1", 3, 59, true, time.Hour),
+		// a break 5 days ago that is still open
+		run("bv1", 4, 6, true, time.Hour),
+		run("bv1", 5, 5, false, 3*time.Hour),
+		run("bv1", 6, 4, false, time.Hour),
+		// a variant that failed from its first build isn't a break
+		run("bv2", 5, 5, false, time.Hour),
+		run("bv2", 6, 4, true, 4*time.Hour),
+	}
+
+	insights := Compute("mci", runs, now)
+	require.Len(insights, 3)
+
+	week := insights[0]
+	assert.Equal(ProjectInsightsKey{Project: "mci", WindowDays: 7}, week.Id)
+	assert.Equal(now, week.LastUpdate)
+	assert.Equal(5, week.Builds)
+	assert.Equal(2, week.SucceededBuilds)
+	assert.Equal(0.4, week.SuccessRate)
+	assert.Equal(1, week.Breaks)
+	assert.Equal(1, week.OpenBreaks)
+	assert.Equal(3*time.Hour, week.MeanTimeToDetect)
+	assert.Zero(week.MeanTimeToRepair)
+	assert.Equal(time.Hour, week.DurationP50)
+	assert.Equal(4*time.Hour, week.DurationP99)
+	require.Len(week.Periods, 7)
+	assert.Equal(now.Add(-7*day), week.Periods[0].Start)
+	assert.Equal(1, week.Periods[1].Builds)
+	assert.Equal(2, week.Periods[2].Builds)
+	assert.Equal(0.5, week.Periods[3].SuccessRate)
+
+	quarter := insights[2]
+	assert.Equal(90, quarter.Id.WindowDays)
+	assert.Equal(8, quarter.Builds)
+	assert.Equal(2, quarter.Breaks)
+	assert.Equal(1, quarter.OpenBreaks)
+	assert.Equal((2*time.Hour+3*time.Hour)/2, quarter.MeanTimeToDetect)
+	assert.Equal(day-time.Hour, quarter.MeanTimeToRepair)
+	assert.Len(quarter.Periods, 13)
+
+	insights = Compute("mci", nil, now)
+	require.Len(insights, 3)
+	assert.Zero(insights[0].Builds)
+	assert.Zero(insights[0].SuccessRate)
+	assert.Zero(insights[0].DurationP50)
+}
+
+func TestIsWindow(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsWindow(7))
+	assert.True(IsWindow(90))
+	assert.False(IsWindow(0))
+	assert.False(IsWindow(14))
+}
+
+func TestReplaceProject(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection))
+
+	now := time.Now().Round(time.Second)
+	require.NoError(ReplaceProject("mci", Compute("mci", nil, now)))
+	require.NoError(ReplaceProject("other", Compute("other", nil, now)))
+
+	insights, err := Find(ByProject("mci", 0))
+	require.NoError(err)
+	require.Len(insights, 3)
+	assert.Equal(7, insights[0].Id.WindowDays)
+	assert.Equal(90, insights[2].Id.WindowDays)
+
+	require.NoError(ReplaceProject("mci", nil))
+	insights, err = Find(ByProject("mci", 30))
+	require.NoError(err)
+	assert.Len(insights, 0)
+	insights, err = Find(ByProject("other", 30))
+	require.NoError(err)
+	require.Len(insights, 1)
+	assert.Equal(30, insights[0].Id.WindowDays)
+}
@@ -0,0 +1,156 @@
+package quota
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the project quota collection in the
+	// database.
+	Collection = "project_quotas"
+	// UsageCollection is the name of the collection recording how projects
+	// share each distro.
+	UsageCollection = "project_quota_usage"
+
+	// DefaultWeight is the weight of projects without a quota.
+	DefaultWeight = 1.0
+)
+
+// ProjectQuota limits how much of each shared distro a project's tasks can
+// take up. Projects share a distro's task queue in proportion to their
+// weights, and once MaxRunningTasks of a project's tasks are running or
+// ahead in the queue of a distro, the rest of its tasks wait behind every
+// other project's.
+type ProjectQuota struct {
+	Project         string    `bson:"_id" json:"project"`
+	Weight          float64   `bson:"weight" json:"weight"`
+	MaxRunningTasks int       `bson:"max_running_tasks,omitempty" json:"max_running_tasks,omitempty"`
+	UpdatedBy       string    `bson:"updated_by" json:"updated_by"`
+	UpdatedAt       time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// Usage records how a project shared a distro the last time the distro's
+// task queue was planned.
+type Usage struct {
+	Id UsageKey `bson:"_id" json:"id"`
+	// Share is the fraction of the distro the project is entitled to,
+	// according to its weight and the weights of the other projects with
+	// tasks for the distro.
+	Share float64 `bson:"share" json:"share"`
+	// Running and Queued count the project's running and queued tasks.
+	Running int `bson:"running" json:"running"`
+	Queued  int `bson:"queued" json:"queued"`
+	// Deferred counts the queued tasks that were moved behind the other
+	// projects' tasks because the project was over its quota.
+	Deferred   int       `bson:"deferred" json:"deferred"`
+	LastUpdate time.Time `bson:"last_update" json:"last_update"`
+}
+
+// UsageKey identifies the usage of a distro by a project.
+type UsageKey struct {
+	Distro  string `bson:"distro" json:"distro"`
+	Project string `bson:"project" json:"project"`
+}
+
+var (
+	ProjectKey         = bsonutil.MustHaveTag(ProjectQuota{}, "Project")
+	WeightKey          = bsonutil.MustHaveTag(ProjectQuota{}, "Weight")
+	MaxRunningTasksKey = bsonutil.MustHaveTag(ProjectQuota{}, "MaxRunningTasks")
+
+	UsageIdKey         = bsonutil.MustHaveTag(Usage{}, "Id")
+	UsageShareKey      = bsonutil.MustHaveTag(Usage{}, "Share")
+	UsageRunningKey    = bsonutil.MustHaveTag(Usage{}, "Running")
+	UsageQueuedKey     = bsonutil.MustHaveTag(Usage{}, "Queued")
+	UsageDeferredKey   = bsonutil.MustHaveTag(Usage{}, "Deferred")
+	UsageLastUpdateKey = bsonutil.MustHaveTag(Usage{}, "LastUpdate")
+
+	usageKeyDistroKey  = bsonutil.MustHaveTag(UsageKey{}, "Distro")
+	usageKeyProjectKey = bsonutil.MustHaveTag(UsageKey{}, "Project")
+)
+
+// Validate checks that the quota's limits make sense.
+func (q *ProjectQuota) Validate() error {
+	if q.Project == "" {
+		return errors.New("quota must have a project")
+	}
+	if q.Weight <= 0 {
+		return errors.New("weight must be positive")
+	}
+	if q.MaxRunningTasks < 0 {
+		return errors.New("max running tasks can't be negative")
+	}
+	return nil
+}
+
+// Upsert replaces the quota of the project.
+func (q *ProjectQuota) Upsert() error {
+	_, err := db.Upsert(Collection, bson.M{ProjectKey: q.Project}, q)
+	return errors.Wrapf(err, "problem updating quota of project '%s'", q.Project)
+}
+
+// FindAll returns the quotas of every project that has one.
+func FindAll() ([]ProjectQuota, error) {
+	quotas := []ProjectQuota{}
+	err := db.FindAllQ(Collection, db.Query(bson.M{}).Sort([]string{ProjectKey}), &quotas)
+	return quotas, errors.Wrap(err, "problem finding project quotas")
+}
+
+// FindOne returns the quota of the project, or nil if it doesn't have one.
+func FindOne(project string) (*ProjectQuota, error) {
+	q := &ProjectQuota{}
+	err := db.FindOneQ(Collection, db.Query(bson.M{ProjectKey: project}), q)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding quota of project '%s'", project)
+	}
+	return q, nil
+}
+
+// Remove deletes the quota of the project, which then gets the default
+// weight and no limit.
+func Remove(project string) error {
+	return errors.Wrapf(db.Remove(Collection, bson.M{ProjectKey: project}),
+		"problem removing quota of project '%s'", project)
+}
+
+// ByDistro returns the usage of the distro by each project.
+func ByDistro(distro string) db.Q {
+	return db.Query(bson.M{
+		bsonutil.GetDottedKeyName(UsageIdKey, usageKeyDistroKey): distro,
+	}).Sort([]string{bsonutil.GetDottedKeyName(UsageIdKey, usageKeyProjectKey)})
+}
+
+// FindUsage returns the usage matching the query.
+func FindUsage(query db.Q) ([]Usage, error) {
+	usage := []Usage{}
+	err := db.FindAllQ(UsageCollection, query, &usage)
+	return usage, err
+}
+
+// RecordUsage replaces the recorded usage of the distro with the given
+// usage, so projects that no longer have tasks for it drop out.
+func RecordUsage(distro string, usage []Usage) error {
+	if err := db.RemoveAll(UsageCollection, bson.M{
+		bsonutil.GetDottedKeyName(UsageIdKey, usageKeyDistroKey): distro,
+	}); err != nil {
+		return errors.Wrapf(err, "problem clearing usage of distro '%s'", distro)
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(usage))
+	for i := range usage {
+		usage[i].Id.Distro = distro
+		docs = append(docs, usage[i])
+	}
+	return errors.Wrapf(db.InsertMany(UsageCollection, docs...), "problem recording usage of distro '%s'", distro)
+}
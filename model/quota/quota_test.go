@@ -0,0 +1,80 @@
+package quota
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestProjectQuotas(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+	defer func() {
+		assert.NoError(db.Clear(Collection))
+	}()
+
+	assert.Error((&ProjectQuota{Weight: 1}).Validate())
+	assert.Error((&ProjectQuota{Project: "p"}).Validate())
+	assert.Error((&ProjectQuota{Project: "p", Weight: 1, MaxRunningTasks: -1}).Validate())
+	assert.NoError((&ProjectQuota{Project: "p", Weight: 0.5}).Validate())
+
+	require.NoError((&ProjectQuota{Project: "b", Weight: 2}).Upsert())
+	require.NoError((&ProjectQuota{Project: "a", Weight: 1, MaxRunningTasks: 10}).Upsert())
+	require.NoError((&ProjectQuota{Project: "a", Weight: 3, MaxRunningTasks: 5}).Upsert())
+
+	quotas, err := FindAll()
+	require.NoError(err)
+	require.Len(quotas, 2)
+	assert.Equal("a", quotas[0].Project)
+	assert.Equal(3.0, quotas[0].Weight)
+	assert.Equal(5, quotas[0].MaxRunningTasks)
+
+	require.NoError(Remove("a"))
+	q, err := FindOne("a")
+	require.NoError(err)
+	assert.Nil(q)
+	q, err = FindOne("b")
+	require.NoError(err)
+	require.NotNil(q)
+	assert.Equal(2.0, q.Weight)
+}
+
+func TestRecordUsage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(UsageCollection))
+	defer func() {
+		assert.NoError(db.Clear(UsageCollection))
+	}()
+
+	require.NoError(RecordUsage("d1", []Usage{
+		{Id: UsageKey{Project: "a"}, Running: 2},
+		{Id: UsageKey{Project: "b"}, Queued: 3},
+	}))
+	require.NoError(RecordUsage("d2", []Usage{
+		{Id: UsageKey{Project: "a"}, Running: 1},
+	}))
+
+	// recording a distro's usage again replaces it
+	require.NoError(RecordUsage("d1", []Usage{
+		{Id: UsageKey{Project: "b"}, Queued: 1, Deferred: 1},
+	}))
+
+	usage, err := FindUsage(ByDistro("d1"))
+	require.NoError(err)
+	require.Len(usage, 1)
+	assert.Equal("b", usage[0].Id.Project)
+	assert.Equal(1, usage[0].Deferred)
+
+	usage, err = FindUsage(ByDistro("d2"))
+	require.NoError(err)
+	assert.Len(usage, 1)
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/archive"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
@@ -62,9 +64,15 @@ var (
 	ExecutionTasksKey       = bsonutil.MustHaveTag(Task{}, "ExecutionTasks")
 	DisplayOnlyKey          = bsonutil.MustHaveTag(Task{}, "DisplayOnly")
 	TaskGroupKey            = bsonutil.MustHaveTag(Task{}, "TaskGroup")
+	TaskGroupOrderKey       = bsonutil.MustHaveTag(Task{}, "TaskGroupOrder")
 	GenerateTaskKey         = bsonutil.MustHaveTag(Task{}, "GenerateTask")
 	GeneratedByKey          = bsonutil.MustHaveTag(Task{}, "GeneratedBy")
+	StepbackTaskIdKey       = bsonutil.MustHaveTag(Task{}, "StepbackTaskId")
+	CheckpointKey           = bsonutil.MustHaveTag(Task{}, "Checkpoint")
 	ResetWhenFinishedKey    = bsonutil.MustHaveTag(Task{}, "ResetWhenFinished")
+	AutoRetriesKey          = bsonutil.MustHaveTag(Task{}, "AutoRetries")
+	RetryAfterKey           = bsonutil.MustHaveTag(Task{}, "RetryAfter")
+	AutoRetriedKey          = bsonutil.MustHaveTag(Task{}, "AutoRetried")
 
 	// BSON fields for the test result struct
 	TestResultStatusKey    = bsonutil.MustHaveTag(TestResult{}, "Status")
@@ -161,6 +169,13 @@ func ByVersion(version string) db.Q {
 	})
 }
 
+// ByVersions creates a query to return the tasks of any of the versions
+func ByVersions(versions []string) db.Q {
+	return db.Query(bson.M{
+		VersionKey: bson.M{"$in": versions},
+	})
+}
+
 // ByIdsBuildIdAndStatus creates a query to return tasks with a certain build id and statuses
 func ByIdsBuildAndStatus(taskIds []string, buildId string, statuses []string) db.Q {
 	return db.Query(bson.M{
@@ -298,6 +313,23 @@ func ByBeforeRevisionWithStatusesAndRequesters(revisionOrder int, statuses []str
 	}).Sort([]string{"-" + RevisionOrderNumberKey})
 }
 
+func ByAfterRevisionWithStatusesAndRequesters(revisionOrder int, statuses []string, buildVariant, displayName, project string, requesters []string) db.Q {
+	return db.Query(bson.M{
+		BuildVariantKey: buildVariant,
+		DisplayNameKey:  displayName,
+		RequesterKey: bson.M{
+			"$in": requesters,
+		},
+		RevisionOrderNumberKey: bson.M{
+			"$gt": revisionOrder,
+		},
+		StatusKey: bson.M{
+			"$in": statuses,
+		},
+		ProjectKey: project,
+	}).Sort([]string{RevisionOrderNumberKey})
+}
+
 // ByTimeRun returns all tasks that are running in between two given times.
 func ByTimeRun(startTime, endTime time.Time) db.Q {
 	return db.Query(
@@ -357,6 +389,17 @@ func ByDifferentFailedBuildVariants(revision, buildVariant, displayName, project
 	})
 }
 
+// BySystemFailedSince returns the tasks that system failed on a host after
+// the given time.
+func BySystemFailedSince(since time.Time) db.Q {
+	return db.Query(bson.M{
+		StatusKey: evergreen.TaskFailed,
+		bsonutil.GetDottedKeyName(DetailsKey, TaskEndDetailType): evergreen.CommandTypeSystem,
+		FinishTimeKey: bson.M{"$gte": since},
+		HostIdKey:     bson.M{"$ne": ""},
+	}).WithFields(IdKey, HostIdKey)
+}
+
 func ByRecentlyFinished(finishTime time.Time, project string, requester string) db.Q {
 	query := bson.M{}
 	andClause := []bson.M{}
@@ -436,6 +479,27 @@ func WithinTimePeriod(startedAfter, finishedBefore time.Time, project string, st
 	})
 }
 
+// ByVersionWithFilter creates a query to return the tasks of a version that
+// have one of the statuses, belong to a build variant matching the regular
+// expression, and have one of the display names. Empty arguments don't
+// filter the tasks.
+func ByVersionWithFilter(versionId string, statuses []string, variantRegex string, displayNames []string) db.Q {
+	q := bson.M{
+		VersionKey: versionId,
+	}
+	if len(statuses) > 0 {
+		q[StatusKey] = bson.M{"$in": statuses}
+	}
+	if variantRegex != "" {
+		q[BuildVariantKey] = bson.RegEx{Pattern: variantRegex}
+	}
+	if len(displayNames) > 0 {
+		q[DisplayNameKey] = bson.M{"$in": displayNames}
+	}
+
+	return db.Query(q)
+}
+
 func ByDispatchedWithIdsVersionAndStatus(taskIds []string, versionId string, statuses []string) db.Q {
 	return db.Query(bson.M{
 		IdKey: bson.M{
@@ -465,6 +529,11 @@ func scheduleableTasksQuery() bson.M {
 		StatusKey:    evergreen.TaskUndispatched,
 		//Filter out blacklisted tasks
 		PriorityKey: bson.M{"$gte": 0},
+		// and tasks waiting to be retried
+		"$or": []bson.M{
+			{RetryAfterKey: bson.M{"$exists": false}},
+			{RetryAfterKey: bson.M{"$lte": time.Now()}},
+		},
 	}
 }
 
@@ -569,6 +638,59 @@ func CostDataByDistroIdPipeline(distroId string, starttime time.Time, duration t
 	return pipeline
 }
 
+// CostDataByProjectIdPipeline returns an aggregation pipeline for fetching
+// cost data of a project's tasks in the given time range, grouped by the
+// distro that the tasks ran on. It is run on both the tasks and their archived
+// executions, so that restarted tasks are counted.
+func CostDataByProjectIdPipeline(projectId string, starttime time.Time, duration time.Duration) []bson.M {
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			ProjectKey:    projectId,
+			FinishTimeKey: bson.M{"$gte": starttime, "$lte": starttime.Add(duration)},
+		}},
+		{"$group": bson.M{
+			"_id":                "$" + DistroIdKey,
+			"sum_time_taken":     bson.M{"$sum": "$" + TimeTakenKey},
+			"sum_estimated_cost": bson.M{"$sum": "$" + CostKey},
+			"num_tasks":          bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{
+			"_id":                0,
+			"distro_id":          "$_id",
+			"sum_time_taken":     1,
+			"sum_estimated_cost": 1,
+			"num_tasks":          1,
+		}},
+		{"$sort": bson.M{"distro_id": 1}},
+	}
+
+	return pipeline
+}
+
+// CostDataByHostIdPipeline returns an aggregation pipeline for fetching
+// cost data of the tasks that ran on a host by its Id. It is run on both the
+// tasks and their archived executions, so that restarted tasks are counted.
+func CostDataByHostIdPipeline(hostId string) []bson.M {
+	pipeline := []bson.M{
+		{"$match": bson.M{HostIdKey: hostId}},
+		{"$group": bson.M{
+			"_id":                "$" + HostIdKey,
+			"sum_time_taken":     bson.M{"$sum": "$" + TimeTakenKey},
+			"sum_estimated_cost": bson.M{"$sum": "$" + CostKey},
+			"num_tasks":          bson.M{"$sum": 1},
+		}},
+		{"$project": bson.M{
+			"_id":                0,
+			"host_id":            "$_id",
+			"sum_time_taken":     1,
+			"sum_estimated_cost": 1,
+			"num_tasks":          1,
+		}},
+	}
+
+	return pipeline
+}
+
 // FindCostTaskByProject fetches all tasks of a project matching the
 // given time range, starting at task's IdKey in sortDir direction.
 func FindCostTaskByProject(project, taskId string, starttime,
@@ -664,7 +786,7 @@ func FindOneId(id string) (*Task, error) {
 	err := db.FindOneQ(Collection, query, task)
 
 	if err == mgo.ErrNotFound {
-		return nil, nil
+		return findOneArchived(Collection, id)
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "error finding task by id")
@@ -673,6 +795,20 @@ func FindOneId(id string) (*Task, error) {
 	return task, nil
 }
 
+// findOneArchived returns the task with the given ID that was archived from
+// the collection, or nil if there is no such task.
+func findOneArchived(collection, id string) (*Task, error) {
+	tasks := []Task{}
+	if err := archive.Find(collection, id, &tasks); err != nil {
+		return nil, errors.Wrap(err, "error finding archived task")
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	return &tasks[0], nil
+}
+
 // FindOneOldNoMerge is a FindOneOld without merging test results.
 func FindOneOldNoMerge(query db.Q) (*Task, error) {
 	task := &Task{}
@@ -823,14 +959,40 @@ func FindOldWithDisplayTasks(query db.Q) ([]Task, error) {
 // FindOneIdOldOrNew attempts to find a given task ID by first looking in the
 // old collection, then the tasks collection
 func FindOneIdOldOrNew(id string, execution int) (*Task, error) {
-	task, err := FindOneOld(ById(fmt.Sprintf("%s_%d", id, execution)))
+	oldId := fmt.Sprintf("%s_%d", id, execution)
+	task, err := FindOneOld(ById(oldId))
 	if task == nil || err != nil {
-		return FindOne(ById(id))
+		task, err = FindOne(ById(id))
+		if task != nil || err != nil {
+			return task, err
+		}
+		return findOneArchivedOldOrNew(oldId, id)
 	}
 
 	return task, err
 }
 
+// findOneArchivedOldOrNew looks for an archived task execution by first
+// looking in the tasks archived from the old collection, then in those
+// archived from the tasks collection.
+func findOneArchivedOldOrNew(oldId, id string) (*Task, error) {
+	task, err := findOneArchived(OldCollection, oldId)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		task, err = findOneArchived(Collection, id)
+		if task == nil || err != nil {
+			return nil, err
+		}
+	}
+	if err = task.MergeNewTestResults(); err != nil {
+		return nil, errors.Wrap(err, "errors merging new test results")
+	}
+
+	return task, nil
+}
+
 // Find returns all tasks that satisfy the query.
 func Find(query db.Q) ([]Task, error) {
 	tasks := []Task{}
@@ -910,7 +1072,99 @@ func Aggregate(pipeline []bson.M, results interface{}) error {
 		results)
 }
 
+// AggregateOld runs the pipeline on the archived executions of tasks.
+func AggregateOld(pipeline []bson.M, results interface{}) error {
+	return db.Aggregate(
+		OldCollection,
+		pipeline,
+		results)
+}
+
 // Count returns the number of hosts that satisfy the given query.
 func Count(query db.Q) (int, error) {
 	return db.CountQ(Collection, query)
 }
+
+// HistoryFilter selects the mainline tasks of a project whose revisions are
+// between two revision order numbers, inclusive. The build variants, display
+// names and statuses don't filter the tasks if they're empty.
+type HistoryFilter struct {
+	Project       string
+	StartOrder    int
+	EndOrder      int
+	BuildVariants []string
+	DisplayNames  []string
+	Statuses      []string
+}
+
+// FindHistoryPage returns at most limit of the tasks passing the filter,
+// sorted by revision order number and ID, that come after the task with the
+// given order number and ID, or from the first task if the ID is empty. Both
+// display and execution tasks are returned.
+func FindHistoryPage(filter HistoryFilter, afterOrder int, afterID string, limit int) ([]Task, error) {
+	q := bson.M{
+		ProjectKey:   filter.Project,
+		RequesterKey: bson.M{"$nin": evergreen.PatchRequesters},
+		RevisionOrderNumberKey: bson.M{
+			"$gte": filter.StartOrder,
+			"$lte": filter.EndOrder,
+		},
+	}
+	if len(filter.BuildVariants) > 0 {
+		q[BuildVariantKey] = bson.M{"$in": filter.BuildVariants}
+	}
+	if len(filter.DisplayNames) > 0 {
+		q[DisplayNameKey] = bson.M{"$in": filter.DisplayNames}
+	}
+	if len(filter.Statuses) > 0 {
+		q[StatusKey] = bson.M{"$in": filter.Statuses}
+	}
+	if afterID != "" {
+		q["$or"] = []bson.M{
+			{RevisionOrderNumberKey: bson.M{"$gt": afterOrder}},
+			{RevisionOrderNumberKey: afterOrder, IdKey: bson.M{"$gt": afterID}},
+		}
+	}
+
+	tasks := []Task{}
+	err := db.FindAllQ(Collection, db.Query(q).Sort([]string{RevisionOrderNumberKey, IdKey}).Limit(limit), &tasks)
+	if err != nil && err != mgo.ErrNotFound {
+		return nil, errors.Wrapf(err, "problem finding tasks of project '%s'", filter.Project)
+	}
+
+	return tasks, nil
+}
+
+// ArchiveFinishedBefore moves at most limit tasks that finished before the
+// cutoff into the archive store, along with their previous executions and
+// the test results of all of their executions, and returns how many tasks
+// it moved. Unlike archiving an execution, the tasks leave the tasks
+// collection, and can then only be found by their IDs.
+func ArchiveFinishedBefore(store archive.Store, cutoff time.Time, limit int) (int, error) {
+	ids, err := findAllTaskIDs(db.Query(bson.M{
+		StatusKey:     bson.M{"$in": evergreen.CompletedStatuses},
+		FinishTimeKey: bson.M{"$gt": util.ZeroTime, "$lt": cutoff},
+	}).WithFields(IdKey).Limit(limit))
+	if err != nil {
+		return 0, errors.Wrap(err, "error finding tasks to archive")
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// the test results and old executions are moved first, so that they
+	// are moved by a later attempt if moving them fails
+	if err = testresult.ArchiveForTasks(store, ids); err != nil {
+		return 0, errors.Wrap(err, "error archiving test results")
+	}
+	byId := func(doc bson.M) string { return fmt.Sprint(doc[IdKey]) }
+	if _, err = archive.Move(store, OldCollection, bson.M{OldTaskIdKey: bson.M{"$in": ids}}, db.NoLimit, byId); err != nil {
+		return 0, errors.Wrap(err, "error archiving old task executions")
+	}
+	moved, err := archive.Move(store, Collection, bson.M{IdKey: bson.M{"$in": ids}}, db.NoLimit, byId)
+	if err != nil {
+		return 0, errors.Wrap(err, "error archiving tasks")
+	}
+
+	return len(moved), nil
+}
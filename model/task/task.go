@@ -38,6 +38,10 @@ const (
 	// length of time to cache the expected duration in the task document
 	predictionTTL = 8 * time.Hour
 
+	// MaxCheckpointSize is the most data a task can record in a checkpoint;
+	// larger state should be saved elsewhere and referred to from it.
+	MaxCheckpointSize = 16 * 1024
+
 	taskBlocked = "blocked"
 	taskPending = "pending"
 )
@@ -74,6 +78,10 @@ type Task struct {
 	Priority          int64  `bson:"priority" json:"priority"`
 	TaskGroup         string `bson:"task_group" json:"task_group"`
 	TaskGroupMaxHosts int    `bson:"task_group_max_hosts,omitempty" json:"task_group_max_hosts,omitempty"`
+	// TaskGroupOrder is the 1-based position of the task within its task
+	// group, so tasks in a group can be dispatched in order without
+	// reparsing the project configuration.
+	TaskGroupOrder int `bson:"task_group_order,omitempty" json:"task_group_order,omitempty"`
 
 	// only relevant if the task is runnin.  the time of the last heartbeat
 	// sent back by the agent
@@ -146,6 +154,41 @@ type Task struct {
 	GenerateTask bool `bson:"generate_task,omitempty" json:"generate_task,omitempty"`
 	// GeneratedBy, if present, is the ID of the task that generated this task.
 	GeneratedBy string `bson:"generated_by,omitempty" json:"generated_by,omitempty"`
+
+	// ShardOf, if present, is the name of the sharded task that this task
+	// runs one shard of, and ShardTests are the tests the shard runs.
+	ShardOf    string   `bson:"shard_of,omitempty" json:"shard_of,omitempty"`
+	ShardIndex int      `bson:"shard_index,omitempty" json:"shard_index,omitempty"`
+	ShardCount int      `bson:"shard_count,omitempty" json:"shard_count,omitempty"`
+	ShardTests []string `bson:"shard_tests,omitempty" json:"shard_tests,omitempty"`
+
+	// StepbackTaskId, if present, is the ID of the task on an earlier
+	// version that stepback activated after this task failed.
+	StepbackTaskId string `bson:"stepback_task_id,omitempty" json:"stepback_task_id,omitempty"`
+
+	// AutoRetries is the number of times the task's retry policy restarted
+	// it, and RetryAfter, if set, is when its latest retry can be scheduled.
+	AutoRetries int       `bson:"auto_retries,omitempty" json:"auto_retries,omitempty"`
+	RetryAfter  time.Time `bson:"retry_after,omitempty" json:"retry_after,omitempty"`
+	// AutoRetried marks an archived execution that the task's retry policy
+	// restarted, so that its failure isn't counted as the task's outcome.
+	AutoRetried bool `bson:"auto_retried,omitempty" json:"auto_retried,omitempty"`
+
+	// Checkpoint, if present, is the latest progress the task recorded. It
+	// carries over to the task's next execution only if the task's host
+	// was reclaimed, so that the task resumes instead of starting over.
+	Checkpoint *Checkpoint `bson:"checkpoint,omitempty" json:"checkpoint,omitempty"`
+}
+
+// Checkpoint is progress recorded by a running task, which the task can
+// resume from if it's rescheduled after its host is reclaimed. Data is
+// opaque to Evergreen, e.g. the last completed step or the URL of saved
+// state.
+type Checkpoint struct {
+	Sequence   int       `bson:"sequence" json:"sequence"`
+	Data       string    `bson:"data" json:"data"`
+	Execution  int       `bson:"execution" json:"execution"`
+	CreateTime time.Time `bson:"create_time" json:"create_time"`
 }
 
 // Dependency represents a task that must be completed before the owning
@@ -174,6 +217,62 @@ type DistroCost struct {
 	NumTasks         int                    `bson:"num_tasks"`
 }
 
+// ProjectCost is service level model for representing cost data related to a project.
+// Distros breaks the aggregated cost down by the distros that the tasks ran on.
+type ProjectCost struct {
+	ProjectId        string        `bson:"project_id"`
+	SumTimeTaken     time.Duration `bson:"sum_time_taken"`
+	SumEstimatedCost float64       `bson:"sum_estimated_cost"`
+	NumTasks         int           `bson:"num_tasks"`
+	Distros          []DistroCost  `bson:"distros"`
+}
+
+// HostCost is service level model for representing cost data related to a host.
+// SumEstimatedCost is the cost attributed to the tasks that ran on the host, and
+// the remainder of TotalCost accrued while the host was idle. CostedTime is the
+// runtime that TotalCost was estimated for, at the provider's HourlyPrice.
+type HostCost struct {
+	HostId           string        `bson:"host_id"`
+	DistroId         string        `bson:"distro_id"`
+	Provider         string        `bson:"provider"`
+	Runtime          time.Duration `bson:"runtime"`
+	CostedTime       time.Duration `bson:"costed_time"`
+	HourlyPrice      float64       `bson:"hourly_price"`
+	TotalCost        float64       `bson:"total_cost"`
+	SumTimeTaken     time.Duration `bson:"sum_time_taken"`
+	SumEstimatedCost float64       `bson:"sum_estimated_cost"`
+	NumTasks         int           `bson:"num_tasks"`
+}
+
+// IdleCost returns the cost of the host that is not attributed to any task.
+func (hc *HostCost) IdleCost() float64 {
+	if hc.TotalCost <= hc.SumEstimatedCost {
+		return 0
+	}
+	return hc.TotalCost - hc.SumEstimatedCost
+}
+
+// HourlyCost returns the average cost of running the host for an hour, over
+// the runtime that its cost was estimated for.
+func (hc *HostCost) HourlyCost() float64 {
+	runtime := hc.CostedTime
+	if runtime <= 0 {
+		runtime = hc.Runtime
+	}
+	if runtime <= 0 {
+		return 0
+	}
+	return hc.TotalCost / runtime.Hours()
+}
+
+// Add adds the cost of the tasks in other to the cost of the tasks that ran on
+// the host.
+func (hc *HostCost) Add(other HostCost) {
+	hc.SumTimeTaken += other.SumTimeTaken
+	hc.SumEstimatedCost += other.SumEstimatedCost
+	hc.NumTasks += other.NumTasks
+}
+
 // SetBSON allows us to use dependency representation of both
 // just task Ids and of true Dependency structs.
 //  TODO eventually drop all of this switching
@@ -433,6 +532,47 @@ func (t *Task) PreviousCompletedTask(project string,
 		t.DisplayName, project, evergreen.SystemVersionRequesterTypes))
 }
 
+// NextCompletedTask finds the first task after this one in the same
+// project, build variant and display name that has one of the statuses.
+func (t *Task) NextCompletedTask(project string, statuses []string) (*Task, error) {
+	if len(statuses) == 0 {
+		statuses = CompletedStatuses
+	}
+	return FindOneNoMerge(ByAfterRevisionWithStatusesAndRequesters(t.RevisionOrderNumber, statuses, t.BuildVariant,
+		t.DisplayName, project, evergreen.SystemVersionRequesterTypes))
+}
+
+// SetStepbackTaskId records the task that stepback activated after this
+// task failed.
+func (t *Task) SetStepbackTaskId(taskId string) error {
+	t.StepbackTaskId = taskId
+	return UpdateOne(
+		bson.M{
+			IdKey: t.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				StepbackTaskIdKey: taskId,
+			},
+		},
+	)
+}
+
+// SetCheckpoint records the task's latest checkpoint.
+func (t *Task) SetCheckpoint(checkpoint *Checkpoint) error {
+	t.Checkpoint = checkpoint
+	return UpdateOne(
+		bson.M{
+			IdKey: t.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				CheckpointKey: checkpoint,
+			},
+		},
+	)
+}
+
 // SetExpectedDuration updates the expected duration field for the task
 func (t *Task) SetExpectedDuration(duration time.Duration) error {
 	return UpdateOne(
@@ -788,6 +928,8 @@ func (t *Task) Reset() error {
 	t.ScheduledTime = util.ZeroTime
 	t.FinishTime = util.ZeroTime
 	t.ResetWhenFinished = false
+	t.Checkpoint = nil
+	t.RetryAfter = time.Time{}
 	reset := bson.M{
 		"$set": bson.M{
 			ActivatedKey:     true,
@@ -801,6 +943,8 @@ func (t *Task) Reset() error {
 		"$unset": bson.M{
 			DetailsKey:           "",
 			ResetWhenFinishedKey: "",
+			CheckpointKey:        "",
+			RetryAfterKey:        "",
 		},
 	}
 
@@ -836,7 +980,9 @@ func ResetTasks(taskIds []string) error {
 			FinishTimeKey:    util.ZeroTime,
 		},
 		"$unset": bson.M{
-			DetailsKey: "",
+			DetailsKey:    "",
+			CheckpointKey: "",
+			RetryAfterKey: "",
 		},
 	}
 
@@ -1218,6 +1364,20 @@ func (t *Task) GetTestResultsForDisplayTask() ([]TestResult, error) {
 	return tasks[0].LocalTestResults, nil
 }
 
+// SetAutoRetry records that the task's retry policy restarted it, and that
+// the restarted task can't be scheduled before retryAfter.
+func (t *Task) SetAutoRetry(retryAfter time.Time) error {
+	t.AutoRetries++
+	t.RetryAfter = retryAfter
+	return UpdateOne(
+		bson.M{IdKey: t.Id},
+		bson.M{
+			"$inc": bson.M{AutoRetriesKey: 1},
+			"$set": bson.M{RetryAfterKey: retryAfter},
+		},
+	)
+}
+
 func (t *Task) SetResetWhenFinished(detail *apimodels.TaskEndDetail) error {
 	if !t.DisplayOnly {
 		return errors.Errorf("%s is not a display task", t.Id)
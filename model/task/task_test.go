@@ -7,6 +7,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/archive"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/evergreen/util"
@@ -986,6 +987,60 @@ func TestFindOneIdOldOrNew(t *testing.T) {
 	assert.Len(task01.LocalTestResults, 1)
 }
 
+func TestArchiveFinishedBefore(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	require.NoError(db.ClearCollections(Collection, OldCollection, testresult.Collection,
+		archive.CollectionName(Collection), archive.CollectionName(OldCollection), archive.CollectionName(testresult.Collection)))
+
+	old := time.Now().AddDate(0, 0, -10)
+	oldTask := Task{Id: "old", Status: evergreen.TaskSucceeded, FinishTime: old}
+	require.NoError(oldTask.Insert())
+	require.NoError(oldTask.Archive())
+	require.NoError((&Task{Id: "recent", Status: evergreen.TaskFailed, FinishTime: time.Now()}).Insert())
+	require.NoError((&Task{Id: "running", Status: evergreen.TaskStarted}).Insert())
+	for _, execution := range []int{0, 1} {
+		result := testresult.TestResult{ID: bson.NewObjectId(), TaskID: "old", Execution: execution, TestFile: "test"}
+		require.NoError(result.Insert())
+	}
+
+	store := archive.GetStore(nil)
+	moved, err := ArchiveFinishedBefore(store, time.Now().AddDate(0, 0, -1), 10)
+	require.NoError(err)
+	assert.Equal(1, moved)
+
+	count, err := Count(All)
+	require.NoError(err)
+	assert.Equal(2, count)
+	count, err = db.Count(OldCollection, bson.M{})
+	require.NoError(err)
+	assert.Zero(count)
+	count, err = db.Count(testresult.Collection, bson.M{})
+	require.NoError(err)
+	assert.Zero(count)
+
+	archived, err := FindOneId("old")
+	require.NoError(err)
+	require.NotNil(archived)
+	assert.Equal(1, archived.Execution)
+
+	execution0, err := FindOneIdOldOrNew("old", 0)
+	require.NoError(err)
+	require.NotNil(execution0)
+	assert.Equal("old_0", execution0.Id)
+	assert.Len(execution0.LocalTestResults, 1)
+	execution1, err := FindOneIdOldOrNew("old", 1)
+	require.NoError(err)
+	require.NotNil(execution1)
+	assert.Equal("old", execution1.Id)
+	assert.Len(execution1.LocalTestResults, 1)
+
+	moved, err = ArchiveFinishedBefore(store, time.Now().AddDate(0, 0, -1), 10)
+	require.NoError(err)
+	assert.Zero(moved)
+}
+
 func TestGetTestResultsForDisplayTask(t *testing.T) {
 	assert := assert.New(t)
 	assert.NoError(db.ClearCollections(Collection, testresult.Collection))
@@ -1151,3 +1206,32 @@ func TestBulkInsert(t *testing.T) {
 		assert.Equal("version", dbTask.Version)
 	}
 }
+
+func TestFindHistoryPage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(Collection))
+
+	for _, task := range []Task{
+		{Id: "t3", Project: "proj", RevisionOrderNumber: 2, BuildVariant: "bv", Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "t1", Project: "proj", RevisionOrderNumber: 1, BuildVariant: "bv", Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "t2", Project: "proj", RevisionOrderNumber: 2, BuildVariant: "bv", Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "t4", Project: "proj", RevisionOrderNumber: 3, BuildVariant: "bv", Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "t5", Project: "proj", RevisionOrderNumber: 2, BuildVariant: "other", Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "p1", Project: "proj", RevisionOrderNumber: 2, BuildVariant: "bv", Requester: evergreen.PatchVersionRequester},
+	} {
+		require.NoError(task.Insert())
+	}
+
+	filter := HistoryFilter{Project: "proj", StartOrder: 1, EndOrder: 2, BuildVariants: []string{"bv"}}
+	tasks, err := FindHistoryPage(filter, 0, "", 2)
+	require.NoError(err)
+	require.Len(tasks, 2)
+	assert.Equal("t1", tasks[0].Id)
+	assert.Equal("t2", tasks[1].Id)
+
+	tasks, err = FindHistoryPage(filter, tasks[1].RevisionOrderNumber, tasks[1].Id, 2)
+	require.NoError(err)
+	require.Len(tasks, 1)
+	assert.Equal("t3", tasks[0].Id)
+}
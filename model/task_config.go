@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sync"
@@ -10,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
 )
@@ -111,7 +113,10 @@ func (c *TaskConfig) GetWorkingDirectory(dir string) (string, error) {
 	return dir, nil
 }
 
-func MakeConfigFromTask(t *task.Task) (*TaskConfig, error) {
+// MakeConfigFromTask returns the configuration that the task runs with,
+// including the project's variables. The values of secret variables are
+// fetched from the secrets managers in the given configuration.
+func MakeConfigFromTask(ctx context.Context, t *task.Task, secrets *evergreen.SecretsConfig) (*TaskConfig, error) {
 	if t == nil {
 		return nil, errors.New("no task to make a TaskConfig from")
 	}
@@ -148,7 +153,20 @@ func MakeConfigFromTask(t *task.Task) (*TaskConfig, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "error finding project vars")
 	}
-	tc.Expansions.Update(projVars.Vars)
-	tc.Redacted = projVars.PrivateVars
+	backends := map[string]thirdparty.SecretsBackend{}
+	prefix := ""
+	if secrets != nil {
+		backends, err = NewSecretsBackends(secrets)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		prefix = secrets.Prefix
+	}
+	vars, private, err := projVars.ResolveVars(ctx, backends, prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving project vars")
+	}
+	tc.Expansions.Update(vars)
+	tc.Redacted = private
 	return tc, nil
 }
@@ -783,6 +783,8 @@ func testHistoryV2Results(params *TestHistoryParameters) ([]task.Task, error) {
 	if err != nil {
 		return nil, err
 	}
+	// automatically retried executions are superseded by their retries
+	tasksQuery[task.AutoRetriedKey] = bson.M{"$ne": true}
 	oldTasks, err := task.FindOld(db.Query(tasksQuery).Project(projection))
 	if err != nil {
 		return nil, err
@@ -99,19 +99,29 @@ func ActivatePreviousTask(taskId, caller string) error {
 		return errors.WithStack(err)
 	}
 
+	_, err = activatePreviousTask(t, caller)
+	return errors.WithStack(err)
+}
+
+// activatePreviousTask activates the task immediately before t and returns
+// it, or returns nil if there was no task to activate.
+func activatePreviousTask(t *task.Task, caller string) (*task.Task, error) {
 	// find previous task limiting to just the last one
 	prevTask, err := task.FindOne(task.ByBeforeRevision(t.RevisionOrderNumber, t.BuildVariant, t.DisplayName, t.Project, t.Requester))
 	if err != nil {
-		return errors.Wrap(err, "Error finding previous task")
+		return nil, errors.Wrap(err, "Error finding previous task")
 	}
 
 	// if this is the first time we're running the task, or it's finished, blacklisted, or already activated
 	if prevTask == nil || prevTask.IsFinished() || prevTask.Priority < 0 || prevTask.Activated {
-		return nil
+		return nil, nil
 	}
 
 	// activate the task
-	return errors.WithStack(SetActiveState(prevTask.Id, caller, true))
+	if err = SetActiveState(prevTask.Id, caller, true); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return prevTask, nil
 }
 
 // reset task finds a task, attempts to archive it, and resets the task and resets the TaskCache in the build as well.
@@ -380,8 +390,80 @@ func doStepback(t *task.Task) error {
 		return errors.Wrap(err, "Error locating previous successful task")
 	}
 
+	ref, err := FindOneProjectRef(t.Project)
+	if err != nil {
+		return errors.Wrapf(err, "error finding project ref for task %s", t.Id)
+	}
+	if ref != nil && ref.StepbackBisect {
+		return errors.WithStack(doBisectStepback(t, prevTask))
+	}
+
 	// activate the previous task to pinpoint regression
-	return errors.WithStack(ActivatePreviousTask(t.Id, evergreen.StepbackTaskActivator))
+	stepbackTask, err := activatePreviousTask(t, evergreen.StepbackTaskActivator)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if stepbackTask == nil {
+		return nil
+	}
+	return errors.Wrapf(t.SetStepbackTaskId(stepbackTask.Id), "error linking task %s to stepback task", t.Id)
+}
+
+// doBisectStepback activates the task halfway between a passing task and a
+// later failing task and links the failing task to it. Nothing is activated
+// while a task between the two is still running or once no inactive tasks
+// remain between them.
+func doBisectStepback(failing, passing *task.Task) error {
+	candidates, err := task.Find(task.ByIntermediateRevisions(passing.RevisionOrderNumber, failing.RevisionOrderNumber,
+		failing.BuildVariant, failing.DisplayName, failing.Project, failing.Requester).Sort([]string{task.RevisionOrderNumberKey}))
+	if err != nil {
+		return errors.Wrapf(err, "error finding tasks between %s and %s", passing.Id, failing.Id)
+	}
+
+	inactive := []task.Task{}
+	for _, t := range candidates {
+		// an earlier failure bounds the search
+		if t.IsFinished() {
+			break
+		}
+		if t.Activated {
+			return nil
+		}
+		if t.Priority < 0 {
+			continue
+		}
+		inactive = append(inactive, t)
+	}
+	if len(inactive) == 0 {
+		return nil
+	}
+
+	stepbackTask := inactive[len(inactive)/2]
+	if err = SetActiveState(stepbackTask.Id, evergreen.StepbackTaskActivator, true); err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.Wrapf(failing.SetStepbackTaskId(stepbackTask.Id), "error linking task %s to stepback task", failing.Id)
+}
+
+// continueBisectStepback narrows the bisection after a task activated by
+// stepback passes, using the next failing task as the upper bound.
+func continueBisectStepback(t *task.Task) error {
+	ref, err := FindOneProjectRef(t.Project)
+	if err != nil {
+		return errors.Wrapf(err, "error finding project ref for task %s", t.Id)
+	}
+	if ref == nil || !ref.StepbackBisect {
+		return nil
+	}
+
+	next, err := t.NextCompletedTask(t.Project, nil)
+	if err != nil {
+		return errors.Wrap(err, "error locating next completed task")
+	}
+	if next == nil || next.Status == evergreen.TaskSucceeded {
+		return nil
+	}
+	return errors.WithStack(doBisectStepback(next, t))
 }
 
 // MarkEnd updates the task as being finished, performs a stepback if necessary, and updates the build status
@@ -406,6 +488,16 @@ func MarkEnd(t *task.Task, caller string, finishTime time.Time, detail *apimodel
 	status := t.ResultStatus()
 	event.LogTaskFinished(t.Id, t.Execution, t.HostId, status)
 
+	// a retried task is running again, so it doesn't step back or fail
+	// its build
+	retried, err := retryTask(t, caller)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if retried {
+		return errors.Wrap(UpdateBuildAndVersionStatusForTask(t.Id, updates), "Error updating build status")
+	}
+
 	if t.IsPartOfDisplay() {
 		if err = UpdateDisplayTask(t.DisplayTask); err != nil {
 			return err
@@ -459,12 +551,19 @@ func evalStepback(t *task.Task, caller, status string, deactivatePrevious bool)
 				return errors.Wrap(err, "Error during step back")
 			}
 		}
-	} else if deactivatePrevious && status == evergreen.TaskSucceeded {
+	} else if status == evergreen.TaskSucceeded {
+		if t.ActivatedBy == evergreen.StepbackTaskActivator {
+			if err := continueBisectStepback(t); err != nil {
+				return errors.Wrap(err, "Error during step back")
+			}
+		}
+
 		// if the task was successful, ignore running previous
 		// activated tasks for this buildvariant
-
-		if err := DeactivatePreviousTasks(t, caller); err != nil {
-			return errors.Wrap(err, "Error deactivating previous task")
+		if deactivatePrevious {
+			if err := DeactivatePreviousTasks(t, caller); err != nil {
+				return errors.Wrap(err, "Error deactivating previous task")
+			}
 		}
 	}
 
@@ -873,6 +972,10 @@ func ClearAndResetStrandedTask(h *host.Host) error {
 		return errors.Wrap(err, "problem marking task failed")
 	}
 
+	if t.Checkpoint != nil && h.Provider == evergreen.ProviderNameEc2Spot && !t.IsPartOfDisplay() {
+		return errors.Wrap(resumeFromCheckpoint(t), "problem rescheduling task from its checkpoint")
+	}
+
 	if time.Since(t.StartTime) < task.UnschedulableThreshold {
 		detail := &apimodels.TaskEndDetail{
 			Status: evergreen.TaskFailed,
@@ -887,6 +990,31 @@ func ClearAndResetStrandedTask(h *host.Host) error {
 	return nil
 }
 
+// resumeFromCheckpoint resets a task whose preemptible host was reclaimed,
+// however long it had run, and carries its latest checkpoint over to its
+// next execution so that it resumes from there.
+func resumeFromCheckpoint(t *task.Task) error {
+	checkpoint := t.Checkpoint
+	detail := &apimodels.TaskEndDetail{
+		Status:      evergreen.TaskFailed,
+		Type:        "system",
+		Description: "host was reclaimed",
+	}
+	if err := TryResetTask(t.Id, "mci", evergreen.MonitorPackage, detail); err != nil {
+		return errors.Wrap(err, "problem resetting task")
+	}
+
+	reset, err := task.FindOneNoMerge(task.ById(t.Id))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding task '%s'", t.Id)
+	}
+	// the task isn't rescheduled once it reaches its max executions
+	if reset == nil || reset.Execution == t.Execution {
+		return nil
+	}
+	return errors.WithStack(reset.SetCheckpoint(checkpoint))
+}
+
 func UpdateDisplayTask(t *task.Task) error {
 	if !t.DisplayOnly {
 		return fmt.Errorf("%s is not a display task", t.Id)
@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
@@ -18,6 +19,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
 )
 
 var (
@@ -2398,3 +2400,144 @@ tasks:
 	assert.NoError(err)
 	assert.True(checkTask.Activated)
 }
+
+func TestEvalStepbackBisect(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(task.Collection, ProjectRefCollection, distro.Collection, build.Collection))
+	yml := `
+stepback: true
+buildvariants:
+- name: "bv"
+  run_on: distro
+  tasks:
+  - name: task
+tasks:
+- name: task
+  `
+	proj := ProjectRef{
+		Identifier:     "proj",
+		LocalConfig:    yml,
+		StepbackBisect: true,
+	}
+	require.NoError(proj.Insert())
+	require.NoError((&distro.Distro{Id: "distro"}).Insert())
+
+	for i := 1; i <= 7; i++ {
+		tsk := task.Task{
+			Id:                  fmt.Sprintf("t%d", i),
+			BuildId:             fmt.Sprintf("b%d", i),
+			Status:              evergreen.TaskUndispatched,
+			BuildVariant:        "bv",
+			DisplayName:         "task",
+			Project:             "proj",
+			RevisionOrderNumber: i,
+			DispatchTime:        util.ZeroTime,
+			Requester:           evergreen.RepotrackerVersionRequester,
+		}
+		switch i {
+		case 1:
+			tsk.Activated = true
+			tsk.Status = evergreen.TaskSucceeded
+		case 7:
+			tsk.Activated = true
+			tsk.Status = evergreen.TaskFailed
+		}
+		require.NoError(tsk.Insert())
+		b := build.Build{
+			Id:           tsk.BuildId,
+			BuildVariant: "bv",
+			Tasks:        []build.TaskCache{{Id: tsk.Id}},
+		}
+		require.NoError(b.Insert())
+	}
+
+	// the failure activates the task halfway back to the last success
+	failing, err := task.FindOneId("t7")
+	require.NoError(err)
+	assert.NoError(evalStepback(failing, "", evergreen.TaskFailed, false))
+	checkTask, err := task.FindOneId("t4")
+	require.NoError(err)
+	assert.True(checkTask.Activated)
+	assert.Equal(evergreen.StepbackTaskActivator, checkTask.ActivatedBy)
+	failing, err = task.FindOneId("t7")
+	require.NoError(err)
+	assert.Equal("t4", failing.StepbackTaskId)
+
+	// nothing else is activated while the stepback task is pending
+	assert.NoError(evalStepback(failing, "", evergreen.TaskFailed, false))
+	for _, id := range []string{"t2", "t3", "t5", "t6"} {
+		checkTask, err = task.FindOneId(id)
+		require.NoError(err)
+		assert.False(checkTask.Activated, id)
+	}
+
+	// a passing stepback task moves the search towards the failure
+	require.NoError(task.UpdateOne(bson.M{task.IdKey: "t4"}, bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskSucceeded}}))
+	passing, err := task.FindOneId("t4")
+	require.NoError(err)
+	assert.NoError(evalStepback(passing, "", evergreen.TaskSucceeded, false))
+	checkTask, err = task.FindOneId("t6")
+	require.NoError(err)
+	assert.True(checkTask.Activated)
+	failing, err = task.FindOneId("t7")
+	require.NoError(err)
+	assert.Equal("t6", failing.StepbackTaskId)
+}
+
+func TestClearAndResetStrandedTaskFromCheckpoint(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(task.Collection, task.OldCollection, build.Collection, version.Collection, host.Collection))
+
+	checkpoint := &task.Checkpoint{Sequence: 3, Data: "step-3", CreateTime: time.Now()}
+	for _, id := range []string{"spot_task", "on_demand_task"} {
+		t := task.Task{
+			Id:         id,
+			BuildId:    "b",
+			Version:    "v",
+			Status:     evergreen.TaskStarted,
+			Activated:  true,
+			Checkpoint: checkpoint,
+		}
+		require.NoError(t.Insert())
+	}
+	require.NoError((&build.Build{
+		Id:      "b",
+		Version: "v",
+		Tasks: []build.TaskCache{
+			{Id: "spot_task", Status: evergreen.TaskStarted, Activated: true},
+			{Id: "on_demand_task", Status: evergreen.TaskStarted, Activated: true},
+		},
+	}).Insert())
+	require.NoError((&version.Version{Id: "v", BuildIds: []string{"b"}}).Insert())
+
+	spot := &host.Host{Id: "spot", Provider: evergreen.ProviderNameEc2Spot, RunningTask: "spot_task"}
+	onDemand := &host.Host{Id: "on_demand", Provider: evergreen.ProviderNameEc2OnDemand, RunningTask: "on_demand_task"}
+	require.NoError(spot.Insert())
+	require.NoError(onDemand.Insert())
+
+	// the task on the reclaimed spot host resumes from its checkpoint
+	require.NoError(ClearAndResetStrandedTask(spot))
+	dbTask, err := task.FindOne(task.ById("spot_task"))
+	require.NoError(err)
+	require.NotNil(dbTask)
+	assert.Equal(evergreen.TaskUndispatched, dbTask.Status)
+	assert.Equal(1, dbTask.Execution)
+	require.NotNil(dbTask.Checkpoint)
+	assert.Equal(3, dbTask.Checkpoint.Sequence)
+	assert.Equal("step-3", dbTask.Checkpoint.Data)
+
+	// restarting a task otherwise starts it over
+	require.NoError(ClearAndResetStrandedTask(onDemand))
+	dbTask, err = task.FindOne(task.ById("on_demand_task"))
+	require.NoError(err)
+	require.NotNil(dbTask)
+	assert.Equal(evergreen.TaskFailed, dbTask.Status)
+	require.NoError(TryResetTask(dbTask.Id, "user", evergreen.UIPackage, nil))
+	dbTask, err = task.FindOne(task.ById("on_demand_task"))
+	require.NoError(err)
+	require.NotNil(dbTask)
+	assert.Equal(1, dbTask.Execution)
+	assert.Nil(dbTask.Checkpoint)
+}
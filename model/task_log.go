@@ -7,6 +7,7 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -221,6 +222,68 @@ func GetRawTaskLogChannel(taskId string, execution int, severities []string,
 	return channel, nil
 }
 
+// FindTaskLogMessagesFromOffset returns at most limit messages of a task's
+// log, starting at the given offset, which is the number of messages before
+// them. The log's documents are read in the order of their IDs, which the
+// database assigns as they're inserted, so the offset of a message does not
+// change as the log grows.
+func FindTaskLogMessagesFromOffset(taskId string, execution, offset, limit int) ([]apimodels.LogMessage, error) {
+	session, db, err := getSessionAndDB()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	query := bson.M{
+		TaskLogTaskIdKey:    taskId,
+		TaskLogExecutionKey: execution,
+	}
+
+	// find the document the offset falls in from the message counts, so
+	// that the documents before it need not be read in full
+	counts := []TaskLog{}
+	err = db.C(TaskLogCollection).Find(query).Select(bson.M{TaskLogIdKey: 1, TaskLogMessageCountKey: 1}).Sort(TaskLogIdKey).All(&counts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding log of task '%s'", taskId)
+	}
+	skip := offset
+	var firstId bson.ObjectId
+	for _, l := range counts {
+		if skip < l.MessageCount {
+			firstId = l.Id
+			break
+		}
+		skip -= l.MessageCount
+	}
+	if firstId == "" {
+		return nil, nil
+	}
+
+	query[TaskLogIdKey] = bson.M{"$gte": firstId}
+	iter := db.C(TaskLogCollection).Find(query).Sort(TaskLogIdKey).Iter()
+	defer iter.Close()
+
+	msgs := []apimodels.LogMessage{}
+	for len(msgs) < limit {
+		logObj := TaskLog{}
+		if !iter.Next(&logObj) {
+			break
+		}
+		if skip < len(logObj.Messages) {
+			msgs = append(msgs, logObj.Messages[skip:]...)
+		}
+		skip = 0
+	}
+	if err = iter.Close(); err != nil {
+		return nil, errors.Wrapf(err, "problem reading log of task '%s'", taskId)
+	}
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+
+	return msgs, nil
+}
+
 /******************************************************
 Functions that operate on individual log messages
 ******************************************************/
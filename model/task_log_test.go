@@ -294,3 +294,59 @@ func TestFindMostRecentLogMessages(t *testing.T) {
 	})
 
 }
+
+func TestFindTaskLogMessagesFromOffset(t *testing.T) {
+
+	Convey("With a task log made up of several documents", t, func() {
+
+		testutil.HandleTestingErr(cleanUpLogDB(), t, "Error cleaning up task log"+
+			" database")
+
+		messages := func(ms ...string) []apimodels.LogMessage {
+			out := []apimodels.LogMessage{}
+			for _, m := range ms {
+				out = append(out, apimodels.LogMessage{Message: m})
+			}
+			return out
+		}
+		for _, msgs := range [][]apimodels.LogMessage{
+			messages("0", "1"),
+			messages("2", "3", "4"),
+			messages("5"),
+		} {
+			taskLog := &TaskLog{
+				TaskId:       "task_id",
+				Timestamp:    time.Now(),
+				MessageCount: len(msgs),
+				Messages:     msgs,
+			}
+			So(taskLog.Insert(), ShouldBeNil)
+		}
+		otherExecution := &TaskLog{
+			TaskId:       "task_id",
+			Execution:    1,
+			MessageCount: 1,
+			Messages:     messages("other"),
+		}
+		So(otherExecution.Insert(), ShouldBeNil)
+
+		Convey("messages should be read from the offset on, across"+
+			" documents", func() {
+
+			read := func(offset, limit int) []string {
+				fromDB, err := FindTaskLogMessagesFromOffset("task_id", 0, offset, limit)
+				So(err, ShouldBeNil)
+				out := []string{}
+				for _, msg := range fromDB {
+					out = append(out, msg.Message)
+				}
+				return out
+			}
+
+			So(read(0, 100), ShouldResemble, []string{"0", "1", "2", "3", "4", "5"})
+			So(read(3, 2), ShouldResemble, []string{"3", "4"})
+			So(read(6, 100), ShouldBeEmpty)
+		})
+
+	})
+}
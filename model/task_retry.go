@@ -0,0 +1,107 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// allowsRetry reports whether the policy restarts a task that failed
+// with the given details after it was already retried the given number of
+// times.
+func (p *RetryPolicy) allowsRetry(retries int, detail apimodels.TaskEndDetail) bool {
+	if p == nil || retries >= p.MaxRetries {
+		return false
+	}
+	if p.SystemFailuresOnly && detail.Type != evergreen.CommandTypeSystem {
+		return false
+	}
+	return true
+}
+
+// backoff returns how long a task waits before its retry after it was
+// already retried the given number of times.
+func (p *RetryPolicy) backoff(retries int) time.Duration {
+	if p == nil || p.BackoffSecs <= 0 {
+		return 0
+	}
+	return time.Duration(p.BackoffSecs) * time.Second << uint(retries)
+}
+
+// getRetryPolicy returns the task's retry policy, which the task's
+// definition can override from the project's.
+func getRetryPolicy(t *task.Task) (*RetryPolicy, error) {
+	project, err := FindProjectFromTask(t)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	projectTask := project.FindProjectTask(t.DisplayName)
+	if projectTask != nil && projectTask.Retry != nil {
+		return projectTask.Retry, nil
+	}
+
+	return project.Retry, nil
+}
+
+// retryTask restarts a task that just failed if its retry policy allows
+// it, and returns whether it did. The failed execution is archived as
+// automatically retried, so that only the task's last execution counts as
+// its outcome.
+func retryTask(t *task.Task, caller string) (bool, error) {
+	if t.Status != evergreen.TaskFailed || t.Aborted || t.DisplayOnly || t.IsPartOfDisplay() {
+		return false, nil
+	}
+	if t.Execution >= evergreen.MaxTaskExecution {
+		return false, nil
+	}
+
+	// a task whose policy can't be found finishes as it would without one
+	policy, err := getRetryPolicy(t)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem finding retry policy, not retrying task",
+			"task_id": t.Id,
+		}))
+		return false, nil
+	}
+	if !policy.allowsRetry(t.AutoRetries, t.Details) {
+		return false, nil
+	}
+
+	execution := t.Execution
+	t.AutoRetried = true
+	err = t.Archive()
+	t.AutoRetried = false
+	if err != nil {
+		return false, errors.Wrapf(err, "problem archiving task %s before retrying it", t.Id)
+	}
+	if err = t.Reset(); err != nil {
+		return false, errors.WithStack(err)
+	}
+	retryAfter := time.Now().Add(policy.backoff(t.AutoRetries))
+	if err = t.SetAutoRetry(retryAfter); err != nil {
+		return false, errors.Wrapf(err, "problem recording retry of task %s", t.Id)
+	}
+	if err = build.ResetCachedTask(t.BuildId, t.Id); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	event.LogTaskRestarted(t.Id, execution, caller)
+	grip.Info(message.Fields{
+		"message":     "automatically retrying failed task",
+		"task_id":     t.Id,
+		"execution":   execution,
+		"retry":       t.AutoRetries,
+		"retry_after": retryAfter,
+	})
+
+	return true, nil
+}
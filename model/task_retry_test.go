@@ -0,0 +1,129 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	var policy *RetryPolicy
+	assert.False(policy.allowsRetry(0, apimodels.TaskEndDetail{}))
+	assert.Zero(policy.backoff(0))
+
+	policy = &RetryPolicy{MaxRetries: 2, BackoffSecs: 10}
+	assert.True(policy.allowsRetry(0, apimodels.TaskEndDetail{Type: evergreen.CommandTypeTest}))
+	assert.True(policy.allowsRetry(1, apimodels.TaskEndDetail{}))
+	assert.False(policy.allowsRetry(2, apimodels.TaskEndDetail{}))
+	assert.Equal(10*time.Second, policy.backoff(0))
+	assert.Equal(40*time.Second, policy.backoff(2))
+
+	policy.SystemFailuresOnly = true
+	assert.False(policy.allowsRetry(0, apimodels.TaskEndDetail{Type: evergreen.CommandTypeTest}))
+	assert.True(policy.allowsRetry(0, apimodels.TaskEndDetail{Type: evergreen.CommandTypeSystem}))
+}
+
+func TestMarkEndRetriesTask(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(ProjectRefCollection, task.Collection, task.OldCollection,
+		build.Collection, version.Collection))
+
+	config := `
+retry:
+  max_retries: 1
+  backoff_secs: 60
+tasks:
+- name: flaky
+- name: infra
+  retry:
+    max_retries: 1
+    system_failures_only: true
+buildvariants:
+- name: bv
+  tasks:
+  - name: flaky
+  - name: infra
+`
+	require.NoError((&ProjectRef{Identifier: "sample", LocalConfig: config}).Insert())
+	v := &version.Version{Id: "v1", Status: evergreen.VersionStarted}
+	require.NoError(v.Insert())
+	b := &build.Build{
+		Id:      "b1",
+		Status:  evergreen.BuildStarted,
+		Version: v.Id,
+		Tasks: []build.TaskCache{
+			{Id: "flaky", Status: evergreen.TaskStarted, Activated: true},
+			{Id: "infra", Status: evergreen.TaskStarted, Activated: true},
+		},
+	}
+	require.NoError(b.Insert())
+	for _, name := range []string{"flaky", "infra"} {
+		tsk := &task.Task{
+			Id:           name,
+			DisplayName:  name,
+			BuildVariant: "bv",
+			BuildId:      b.Id,
+			Version:      v.Id,
+			Project:      "sample",
+			Activated:    true,
+			Status:       evergreen.TaskStarted,
+			Requester:    evergreen.RepotrackerVersionRequester,
+		}
+		require.NoError(tsk.Insert())
+	}
+
+	// the first failure is retried after the backoff
+	flaky, err := task.FindOneId("flaky")
+	require.NoError(err)
+	updates := StatusChanges{}
+	detail := &apimodels.TaskEndDetail{Status: evergreen.TaskFailed, Type: evergreen.CommandTypeTest}
+	require.NoError(MarkEnd(flaky, "test", time.Now(), detail, false, &updates))
+
+	flaky, err = task.FindOneId("flaky")
+	require.NoError(err)
+	assert.Equal(evergreen.TaskUndispatched, flaky.Status)
+	assert.Equal(1, flaky.Execution)
+	assert.Equal(1, flaky.AutoRetries)
+	assert.True(flaky.RetryAfter.After(time.Now().Add(50 * time.Second)))
+	archived, err := task.FindOneOld(task.ById("flaky_0"))
+	require.NoError(err)
+	require.NotNil(archived)
+	assert.True(archived.AutoRetried)
+	assert.Equal(evergreen.TaskFailed, archived.Status)
+	dbBuild, err := build.FindOne(build.ById(b.Id))
+	require.NoError(err)
+	assert.NotEqual(evergreen.BuildFailed, dbBuild.Status)
+
+	// tasks waiting out their backoff aren't scheduled
+	schedulable, err := task.FindSchedulable("")
+	require.NoError(err)
+	assert.Empty(schedulable)
+
+	// the retry's failure is the task's outcome
+	flaky.Status = evergreen.TaskStarted
+	require.NoError(MarkEnd(flaky, "test", time.Now(), detail, false, &updates))
+	flaky, err = task.FindOneId("flaky")
+	require.NoError(err)
+	assert.Equal(evergreen.TaskFailed, flaky.Status)
+	assert.Equal(1, flaky.Execution)
+
+	// test failures aren't retried by a policy for system failures
+	infra, err := task.FindOneId("infra")
+	require.NoError(err)
+	require.NoError(MarkEnd(infra, "test", time.Now(), detail, false, &updates))
+	infra, err = task.FindOneId("infra")
+	require.NoError(err)
+	assert.Equal(evergreen.TaskFailed, infra.Status)
+	assert.Zero(infra.AutoRetries)
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/coverage"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	ignore "github.com/sabhiram/go-git-ignore"
+)
+
+// SelectTasksForFiles returns the names of the tasks affected by changes to
+// the files, according to the project's test selection rules and the
+// coverage recorded for the project. It returns false if a file is matched
+// by neither, since every task may then be affected.
+func SelectTasksForFiles(p *Project, projectId string, files []string) ([]string, bool, error) {
+	if len(files) == 0 {
+		return nil, false, nil
+	}
+	covered, err := coverage.FindTasksForFiles(projectId, files)
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+
+	matchers := make([]*ignore.GitIgnore, 0, len(p.TestSelection))
+	for _, rule := range p.TestSelection {
+		// CompileIgnoreLines has a silly API: it always returns a nil error.
+		matcher, _ := ignore.CompileIgnoreLines(rule.Paths...)
+		matchers = append(matchers, matcher)
+	}
+
+	selected := map[string]bool{}
+	for _, file := range files {
+		coveringTasks, mapped := covered[file]
+		tasks := append([]string{}, coveringTasks...)
+		for i, rule := range p.TestSelection {
+			if matchers[i].MatchesPath(file) {
+				mapped = true
+				tasks = append(tasks, rule.Tasks...)
+			}
+		}
+		if !mapped {
+			return nil, false, nil
+		}
+		for _, t := range tasks {
+			selected[t] = true
+		}
+	}
+
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, true, nil
+}
+
+// ActivateSelectedBuildTasks activates the undispatched tasks of a build
+// that are named in selected, along with their dependencies, and marks the
+// build as activated. Shards of a selected sharded task and execution tasks
+// of a selected display task are activated too.
+func ActivateSelectedBuildTasks(buildId string, selected []string, caller string) error {
+	tasks, err := task.Find(task.ByBuildId(buildId))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding tasks for build %s", buildId)
+	}
+
+	ids := []string{}
+	for _, t := range tasks {
+		if t.DisplayOnly {
+			if util.StringSliceContains(selected, t.DisplayName) {
+				ids = append(ids, t.ExecutionTasks...)
+			}
+			continue
+		}
+		if util.StringSliceContains(selected, t.DisplayName) || (t.ShardOf != "" && util.StringSliceContains(selected, t.ShardOf)) {
+			ids = append(ids, t.Id)
+		}
+	}
+
+	catcher := grip.NewBasicCatcher()
+	activated := map[string]bool{}
+	for _, t := range tasks {
+		if t.Status != evergreen.TaskUndispatched || !util.StringSliceContains(ids, t.Id) || activated[t.Id] {
+			continue
+		}
+		activated[t.Id] = true
+		catcher.Add(SetActiveState(t.Id, caller, true))
+	}
+	if catcher.HasErrors() {
+		return errors.Wrapf(catcher.Resolve(), "problem activating selected tasks for build %s", buildId)
+	}
+
+	return errors.WithStack(build.UpdateActivation(buildId, true, caller))
+}
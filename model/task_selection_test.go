@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/coverage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectTasksForFiles(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(coverage.Collection))
+	defer func() {
+		assert.NoError(db.Clear(coverage.Collection))
+	}()
+
+	require.NoError(coverage.Update("proj", map[string][]string{
+		"src/util.go": {"unit"},
+	}))
+	p := &Project{
+		TestSelection: []TestSelectionRule{
+			{Paths: []string{"docs/"}, Tasks: []string{"docs"}},
+			{Paths: []string{"*.js"}, Tasks: []string{"js"}},
+		},
+	}
+
+	selected, ok, err := SelectTasksForFiles(p, "proj", []string{"src/util.go", "docs/index.md", "web/app.js"})
+	require.NoError(err)
+	assert.True(ok)
+	assert.Equal([]string{"docs", "js", "unit"}, selected)
+
+	// a file matched by neither a rule nor coverage could affect any task
+	_, ok, err = SelectTasksForFiles(p, "proj", []string{"src/util.go", "src/main.go"})
+	require.NoError(err)
+	assert.False(ok)
+
+	_, ok, err = SelectTasksForFiles(p, "proj", nil)
+	require.NoError(err)
+	assert.False(ok)
+}
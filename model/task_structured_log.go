@@ -0,0 +1,164 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const StructuredTaskLogCollection = "structured_task_logs"
+
+// StructuredTaskLog is a single structured log line of a task. Unlike
+// TaskLog, each line is its own document, so that a task's logs can be
+// filtered by level, component, and time.
+type StructuredTaskLog struct {
+	Id        bson.ObjectId          `bson:"_id,omitempty" json:"_id,omitempty"`
+	TaskId    string                 `bson:"t_id" json:"t_id"`
+	Execution int                    `bson:"e" json:"e"`
+	Component string                 `bson:"c" json:"c"`
+	Level     string                 `bson:"l" json:"l"`
+	Priority  int                    `bson:"p" json:"p"`
+	Message   string                 `bson:"m,omitempty" json:"m,omitempty"`
+	Timestamp time.Time              `bson:"ts" json:"ts"`
+	Fields    map[string]interface{} `bson:"f,omitempty" json:"f,omitempty"`
+}
+
+var (
+	StructuredTaskLogTaskIdKey    = bsonutil.MustHaveTag(StructuredTaskLog{}, "TaskId")
+	StructuredTaskLogExecutionKey = bsonutil.MustHaveTag(StructuredTaskLog{}, "Execution")
+	StructuredTaskLogComponentKey = bsonutil.MustHaveTag(StructuredTaskLog{}, "Component")
+	StructuredTaskLogPriorityKey  = bsonutil.MustHaveTag(StructuredTaskLog{}, "Priority")
+	StructuredTaskLogTimestampKey = bsonutil.MustHaveTag(StructuredTaskLog{}, "Timestamp")
+)
+
+// NewStructuredTaskLogs converts the structured log lines an agent sent for
+// the given task execution into documents. Field names that the database
+// does not allow are rewritten.
+func NewStructuredTaskLogs(taskId string, execution int, lines []apimodels.StructuredLogLine) ([]StructuredTaskLog, error) {
+	logs := make([]StructuredTaskLog, 0, len(lines))
+	for i, line := range lines {
+		priority := level.FromString(line.Level)
+		if !level.IsValidPriority(priority) {
+			return nil, errors.Errorf("log line %d has invalid level '%s'", i, line.Level)
+		}
+		if line.Timestamp.IsZero() {
+			return nil, errors.Errorf("log line %d has no timestamp", i)
+		}
+
+		logs = append(logs, StructuredTaskLog{
+			TaskId:    taskId,
+			Execution: execution,
+			Component: line.Component,
+			Level:     priority.String(),
+			Priority:  int(priority),
+			Message:   line.Message,
+			Timestamp: line.Timestamp,
+			Fields:    sanitizeLogFields(line.Fields),
+		})
+	}
+	return logs, nil
+}
+
+// sanitizeLogFields replaces the dots and leading dollar signs in field
+// names, which the database rejects, with underscores.
+func sanitizeLogFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	out := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		k = strings.Replace(k, ".", "_", -1)
+		if strings.HasPrefix(k, "$") {
+			k = "_" + k[1:]
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			v = sanitizeLogFields(nested)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// InsertStructuredTaskLogs inserts the structured log lines.
+func InsertStructuredTaskLogs(logs []StructuredTaskLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	session, db, err := getSessionAndDB()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	docs := make([]interface{}, 0, len(logs))
+	for _, l := range logs {
+		docs = append(docs, l)
+	}
+	return errors.Wrap(db.C(StructuredTaskLogCollection).Insert(docs...), "problem inserting structured task logs")
+}
+
+// StructuredTaskLogFilter selects the structured log lines of a task
+// execution. Lines below MinLevel, from components other than Components,
+// or outside of [Start, End) are left out; empty values do not filter.
+type StructuredTaskLogFilter struct {
+	TaskId     string
+	Execution  int
+	MinLevel   level.Priority
+	Components []string
+	Start      time.Time
+	End        time.Time
+	Limit      int
+}
+
+func (f StructuredTaskLogFilter) query() bson.M {
+	q := bson.M{
+		StructuredTaskLogTaskIdKey:    f.TaskId,
+		StructuredTaskLogExecutionKey: f.Execution,
+	}
+	if f.MinLevel > level.Invalid {
+		q[StructuredTaskLogPriorityKey] = bson.M{"$gte": int(f.MinLevel)}
+	}
+	if len(f.Components) > 0 {
+		q[StructuredTaskLogComponentKey] = bson.M{"$in": f.Components}
+	}
+
+	ts := bson.M{}
+	if !f.Start.IsZero() {
+		ts["$gte"] = f.Start
+	}
+	if !f.End.IsZero() {
+		ts["$lt"] = f.End
+	}
+	if len(ts) > 0 {
+		q[StructuredTaskLogTimestampKey] = ts
+	}
+
+	return q
+}
+
+// FindStructuredTaskLogs returns the structured log lines that match the
+// filter, oldest first.
+func FindStructuredTaskLogs(f StructuredTaskLogFilter) ([]StructuredTaskLog, error) {
+	session, db, err := getSessionAndDB()
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	logs := []StructuredTaskLog{}
+	q := db.C(StructuredTaskLogCollection).Find(f.query()).Sort(StructuredTaskLogTimestampKey, "_id")
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	if err = q.All(&logs); err != nil {
+		return nil, errors.Wrapf(err, "problem finding structured logs of task '%s'", f.TaskId)
+	}
+	return logs, nil
+}
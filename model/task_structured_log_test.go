@@ -0,0 +1,104 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func cleanUpStructuredLogDB() error {
+	session, _, err := db.GetGlobalSessionFactory().GetSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	_, err = session.DB(TaskLogDB).C(StructuredTaskLogCollection).RemoveAll(bson.M{})
+	return err
+}
+
+func TestNewStructuredTaskLogs(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	logs, err := NewStructuredTaskLogs("t1", 2, []apimodels.StructuredLogLine{
+		{
+			Component: "s3.put",
+			Level:     "warning",
+			Message:   "retrying upload",
+			Timestamp: now,
+			Fields: map[string]interface{}{
+				"file.name": "dist.tgz",
+				"$attempt":  2,
+				"request":   map[string]interface{}{"x.id": "abc"},
+			},
+		},
+	})
+	assert.NoError(err)
+	if assert.Len(logs, 1) {
+		assert.Equal("t1", logs[0].TaskId)
+		assert.Equal(2, logs[0].Execution)
+		assert.Equal("s3.put", logs[0].Component)
+		assert.Equal("warning", logs[0].Level)
+		assert.Equal(int(level.Warning), logs[0].Priority)
+		assert.Equal("retrying upload", logs[0].Message)
+		assert.Equal(map[string]interface{}{
+			"file_name": "dist.tgz",
+			"_attempt":  2,
+			"request":   map[string]interface{}{"x_id": "abc"},
+		}, logs[0].Fields)
+	}
+
+	_, err = NewStructuredTaskLogs("t1", 0, []apimodels.StructuredLogLine{{Level: "loud", Timestamp: now}})
+	assert.Error(err)
+	_, err = NewStructuredTaskLogs("t1", 0, []apimodels.StructuredLogLine{{Level: "info"}})
+	assert.Error(err)
+}
+
+func TestFindStructuredTaskLogs(t *testing.T) {
+	assert := assert.New(t)
+	require.NoError(t, cleanUpStructuredLogDB())
+	defer func() {
+		assert.NoError(cleanUpStructuredLogDB())
+	}()
+
+	start := time.Now().Round(time.Second)
+	lines := []apimodels.StructuredLogLine{
+		{Component: "agent", Level: "debug", Message: "0", Timestamp: start},
+		{Component: "task", Level: "info", Message: "1", Timestamp: start.Add(time.Second)},
+		{Component: "task", Level: "error", Message: "2", Timestamp: start.Add(2 * time.Second)},
+		{Component: "s3.put", Level: "warning", Message: "3", Timestamp: start.Add(3 * time.Second)},
+	}
+	logs, err := NewStructuredTaskLogs("t1", 0, lines)
+	require.NoError(t, err)
+	require.NoError(t, InsertStructuredTaskLogs(logs))
+	logs, err = NewStructuredTaskLogs("t1", 1, lines)
+	require.NoError(t, err)
+	require.NoError(t, InsertStructuredTaskLogs(logs))
+
+	messages := func(f StructuredTaskLogFilter) []string {
+		found, err := FindStructuredTaskLogs(f)
+		require.NoError(t, err)
+		out := []string{}
+		for _, l := range found {
+			out = append(out, l.Message)
+		}
+		return out
+	}
+
+	assert.Equal([]string{"0", "1", "2", "3"}, messages(StructuredTaskLogFilter{TaskId: "t1"}))
+	assert.Equal([]string{"0", "1"}, messages(StructuredTaskLogFilter{TaskId: "t1", Execution: 1, Limit: 2}))
+	assert.Equal([]string{"2", "3"}, messages(StructuredTaskLogFilter{TaskId: "t1", MinLevel: level.Warning}))
+	assert.Equal([]string{"1", "2"}, messages(StructuredTaskLogFilter{TaskId: "t1", Components: []string{"task"}}))
+	assert.Equal([]string{"1", "2"}, messages(StructuredTaskLogFilter{
+		TaskId: "t1",
+		Start:  start.Add(time.Second),
+		End:    start.Add(3 * time.Second),
+	}))
+	assert.Empty(messages(StructuredTaskLogFilter{TaskId: "t2"}))
+}
@@ -0,0 +1,135 @@
+package taskruntime
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the task runtimes collection in the
+	// database.
+	Collection = "task_runtimes"
+
+	// WindowSize is how many of a task's most recent runtimes its
+	// percentiles are estimated from, so that the estimates follow the
+	// task's runtime as it changes.
+	WindowSize = 100
+	// MinSamples is how many runtimes a task needs before its estimates
+	// are used to predict its runtime.
+	MinSamples = 5
+)
+
+// TaskRuntime holds the recent runtimes of a task in a project's variant,
+// from which percentiles of its runtime are estimated.
+type TaskRuntime struct {
+	Id         TaskRuntimeKey  `bson:"_id" json:"id"`
+	Samples    []time.Duration `bson:"samples" json:"samples"`
+	NumRuns    int             `bson:"num_runs" json:"num_runs"`
+	LastUpdate time.Time       `bson:"last_update" json:"last_update"`
+}
+
+// TaskRuntimeKey identifies a task in a project's variant.
+type TaskRuntimeKey struct {
+	Project string `bson:"project" json:"project"`
+	Variant string `bson:"variant" json:"variant"`
+	Task    string `bson:"task" json:"task"`
+}
+
+var (
+	IdKey         = bsonutil.MustHaveTag(TaskRuntime{}, "Id")
+	SamplesKey    = bsonutil.MustHaveTag(TaskRuntime{}, "Samples")
+	NumRunsKey    = bsonutil.MustHaveTag(TaskRuntime{}, "NumRuns")
+	LastUpdateKey = bsonutil.MustHaveTag(TaskRuntime{}, "LastUpdate")
+)
+
+// Percentile estimates the runtime that the given percent of the task's
+// runs finish within, using the nearest rank of its recent runtimes. It
+// returns 0 if there are no runtimes.
+func (r *TaskRuntime) Percentile(percent float64) time.Duration {
+	if len(r.Samples) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(r.Samples))
+	copy(sorted, r.Samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(percent / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// Reliable returns whether the task has run enough times for its estimates
+// to be used.
+func (r *TaskRuntime) Reliable() bool {
+	return len(r.Samples) >= MinSamples
+}
+
+// Find returns the task runtimes matching the query.
+func Find(query db.Q) ([]TaskRuntime, error) {
+	runtimes := []TaskRuntime{}
+	err := db.FindAllQ(Collection, query, &runtimes)
+	return runtimes, err
+}
+
+// FindOne returns the runtimes of a task in a project's variant, or nil if
+// none have been recorded.
+func FindOne(project, variant, task string) (*TaskRuntime, error) {
+	r := &TaskRuntime{}
+	err := db.FindOneQ(Collection, db.Query(bson.M{
+		IdKey: TaskRuntimeKey{Project: project, Variant: variant, Task: task},
+	}), r)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding runtimes of task '%s'", task)
+	}
+	return r, nil
+}
+
+// FindByKeys returns the recorded runtimes of each of the tasks, keyed by
+// task.
+func FindByKeys(keys []TaskRuntimeKey) (map[TaskRuntimeKey]TaskRuntime, error) {
+	if len(keys) == 0 {
+		return map[TaskRuntimeKey]TaskRuntime{}, nil
+	}
+	runtimes, err := Find(db.Query(bson.M{IdKey: bson.M{"$in": keys}}))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding task runtimes")
+	}
+	byKey := make(map[TaskRuntimeKey]TaskRuntime, len(runtimes))
+	for _, r := range runtimes {
+		byKey[r.Id] = r
+	}
+	return byKey, nil
+}
+
+// Record adds a runtime of a task in a project's variant, dropping the
+// oldest runtime once there are WindowSize of them.
+func Record(project, variant, task string, runtime time.Duration) error {
+	_, err := db.Upsert(Collection,
+		bson.M{IdKey: TaskRuntimeKey{Project: project, Variant: variant, Task: task}},
+		bson.M{
+			"$push": bson.M{
+				SamplesKey: bson.M{
+					"$each":  []time.Duration{runtime},
+					"$slice": -WindowSize,
+				},
+			},
+			"$inc": bson.M{NumRunsKey: 1},
+			"$set": bson.M{LastUpdateKey: time.Now()},
+		})
+	return errors.Wrapf(err, "problem recording runtime of task '%s'", task)
+}
@@ -0,0 +1,66 @@
+package taskruntime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestPercentile(t *testing.T) {
+	assert := assert.New(t)
+
+	r := &TaskRuntime{}
+	assert.Equal(time.Duration(0), r.Percentile(50))
+	assert.False(r.Reliable())
+
+	for i := 10; i >= 1; i-- {
+		r.Samples = append(r.Samples, time.Duration(i)*time.Minute)
+	}
+	assert.True(r.Reliable())
+	assert.Equal(5*time.Minute, r.Percentile(50))
+	assert.Equal(9*time.Minute, r.Percentile(90))
+	assert.Equal(10*time.Minute, r.Percentile(99))
+	assert.Equal(time.Minute, r.Percentile(0))
+}
+
+func TestRecord(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+	defer func() {
+		assert.NoError(db.Clear(Collection))
+	}()
+
+	for i := 1; i <= WindowSize+2; i++ {
+		require.NoError(Record("p", "bv", "compile", time.Duration(i)*time.Second))
+	}
+	require.NoError(Record("p", "other", "compile", time.Hour))
+
+	r, err := FindOne("p", "bv", "compile")
+	require.NoError(err)
+	require.NotNil(r)
+	assert.Equal(WindowSize+2, r.NumRuns)
+	// only the most recent runtimes are kept
+	require.Len(r.Samples, WindowSize)
+	assert.Equal(3*time.Second, r.Samples[0])
+
+	r, err = FindOne("p", "bv", "lint")
+	require.NoError(err)
+	assert.Nil(r)
+
+	byKey, err := FindByKeys([]TaskRuntimeKey{
+		{Project: "p", Variant: "other", Task: "compile"},
+		{Project: "p", Variant: "bv", Task: "lint"},
+	})
+	require.NoError(err)
+	require.Len(byKey, 1)
+	assert.Equal([]time.Duration{time.Hour}, byKey[TaskRuntimeKey{Project: "p", Variant: "other", Task: "compile"}].Samples)
+}
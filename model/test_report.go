@@ -0,0 +1,378 @@
+package model
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	TestReportFormatJUnit = "junit"
+	TestReportFormatTAP   = "tap"
+)
+
+// ValidTestReportFormats are the formats of test reports that the api server
+// converts into test results.
+var ValidTestReportFormats = []string{TestReportFormatJUnit, TestReportFormatTAP}
+
+// TestReportCase is a test parsed from a test report.
+type TestReportCase struct {
+	Name   string
+	Status string
+	// Start is when the test started, if the report says.
+	Start    time.Time
+	Duration time.Duration
+	// Log is the test's output and failure details, which are stored as the
+	// test's log if there are any.
+	Log []string
+}
+
+// ParseTestReport parses the tests from a report in one of the valid test
+// report formats.
+func ParseTestReport(format string, r io.Reader) ([]TestReportCase, error) {
+	switch format {
+	case TestReportFormatJUnit:
+		return parseJUnitReport(r)
+	case TestReportFormatTAP:
+		return parseTAPReport(r)
+	default:
+		return nil, errors.Errorf("unknown test report format '%s'", format)
+	}
+}
+
+// IngestTestReport parses the report and stores its tests as the task's test
+// results, along with the logs of the tests that have output. Tests are
+// given consecutive start times from now if the report doesn't include them.
+func IngestTestReport(t *task.Task, format string, r io.Reader) ([]task.TestResult, error) {
+	cases, err := ParseTestReport(format, r)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem parsing %s test report", format)
+	}
+	if len(cases) == 0 {
+		return nil, errors.New("test report has no tests")
+	}
+
+	results := make([]task.TestResult, 0, len(cases))
+	next := time.Now()
+	for _, tc := range cases {
+		start := tc.Start
+		if start.IsZero() {
+			start = next
+		}
+		next = start.Add(tc.Duration)
+
+		result := task.TestResult{
+			Status:    tc.Status,
+			TestFile:  util.CleanForPath(tc.Name),
+			StartTime: util.ToPythonTime(start),
+			EndTime:   util.ToPythonTime(start.Add(tc.Duration)),
+		}
+		if len(tc.Log) > 0 {
+			log := &TestLog{
+				Name:          result.TestFile,
+				Task:          t.Id,
+				TaskExecution: t.Execution,
+				Lines:         tc.Log,
+			}
+			if err = log.Insert(); err != nil {
+				return nil, errors.Wrapf(err, "problem inserting log of test '%s'", tc.Name)
+			}
+			result.LogId = log.Id
+			result.LineNum = 1
+			result.URL = log.URL()
+		}
+		results = append(results, result)
+	}
+
+	if err = t.SetResults(results); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return results, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// JUnit
+
+type junitTestSuites struct {
+	Suites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Time      string           `xml:"time,attr"`
+	Suites    []junitTestSuite `xml:"testsuite"`
+	TestCases []junitTestCase  `xml:"testcase"`
+	Error     *junitFailure    `xml:"error"`
+	SysOut    string           `xml:"system-out"`
+	SysErr    string           `xml:"system-err"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure"`
+	Error     *junitFailure `xml:"error"`
+	Skipped   *junitFailure `xml:"skipped"`
+	SysOut    string        `xml:"system-out"`
+	SysErr    string        `xml:"system-err"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Content string `xml:",chardata"`
+}
+
+var junitTimestampLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05"}
+
+// parseJUnitReport parses a JUnit XML report, whose root is either a
+// <testsuites> or a <testsuite> element.
+func parseJUnitReport(r io.Reader) ([]TestReportCase, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading report")
+	}
+
+	root := junitTestSuites{}
+	if err = xml.Unmarshal(data, &root); err != nil {
+		return nil, errors.Wrap(err, "invalid XML")
+	}
+	if len(root.Suites) == 0 {
+		suite := junitTestSuite{}
+		if err = xml.Unmarshal(data, &suite); err != nil {
+			return nil, errors.Wrap(err, "invalid XML")
+		}
+		root.Suites = []junitTestSuite{suite}
+	}
+
+	cases := []TestReportCase{}
+	for i, suite := range root.Suites {
+		cases = append(cases, suite.cases(i)...)
+	}
+	return cases, nil
+}
+
+// cases returns the test cases of the suite and of the suites nested in it.
+// A suite that failed without running any test cases is reported as one
+// failed test.
+func (s junitTestSuite) cases(idx int) []TestReportCase {
+	var start time.Time
+	for _, layout := range junitTimestampLayouts {
+		if ts, err := time.Parse(layout, s.Timestamp); err == nil {
+			start = ts
+			break
+		}
+	}
+
+	testCases := s.TestCases
+	if len(testCases) == 0 && len(s.Suites) == 0 && s.Error != nil {
+		name := s.Name
+		if name == "" {
+			name = fmt.Sprintf("Unnamed Test-%d", idx)
+		}
+		testCases = []junitTestCase{{Name: name, Time: s.Time, Error: s.Error}}
+	}
+
+	cases := make([]TestReportCase, 0, len(testCases))
+	for _, tc := range testCases {
+		c := tc.reportCase()
+		if c.Status == evergreen.TestFailedStatus {
+			c.Log = appendJUnitOutput(c.Log, s.SysOut, s.SysErr)
+		}
+		if !start.IsZero() {
+			c.Start = start
+			start = start.Add(c.Duration)
+		}
+		cases = append(cases, c)
+	}
+	for i, nested := range s.Suites {
+		cases = append(cases, nested.cases(i)...)
+	}
+	return cases
+}
+
+func (tc junitTestCase) reportCase() TestReportCase {
+	c := TestReportCase{
+		Name:     tc.Name,
+		Duration: parseReportSeconds(tc.Time),
+	}
+	if tc.ClassName != "" {
+		c.Name = fmt.Sprintf("%s.%s", tc.ClassName, tc.Name)
+	}
+
+	switch {
+	case tc.Failure != nil:
+		c.Status = evergreen.TestFailedStatus
+		c.Log = tc.Failure.logLines("FAILURE")
+	case tc.Error != nil:
+		c.Status = evergreen.TestFailedStatus
+		c.Log = tc.Error.logLines("ERROR")
+	case tc.Skipped != nil:
+		c.Status = evergreen.TestSkippedStatus
+		c.Log = tc.Skipped.logLines("SKIPPED")
+	default:
+		c.Status = evergreen.TestSucceededStatus
+	}
+	c.Log = appendJUnitOutput(c.Log, tc.SysOut, tc.SysErr)
+
+	return c
+}
+
+func (f junitFailure) logLines(kind string) []string {
+	lines := []string{fmt.Sprintf("%s: %s (%s)", kind, f.Message, f.Type)}
+	if content := strings.TrimSpace(f.Content); content != "" {
+		lines = append(lines, strings.Split(content, "\n")...)
+	}
+	return lines
+}
+
+func appendJUnitOutput(lines []string, sysOut, sysErr string) []string {
+	if out := strings.TrimSpace(sysOut); out != "" {
+		lines = append(lines, "system-out:")
+		lines = append(lines, strings.Split(out, "\n")...)
+	}
+	if out := strings.TrimSpace(sysErr); out != "" {
+		lines = append(lines, "system-err:")
+		lines = append(lines, strings.Split(out, "\n")...)
+	}
+	return lines
+}
+
+func parseReportSeconds(s string) time.Duration {
+	// some reporters format durations with thousands separators
+	secs, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(s), ",", "", -1), 64)
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs * float64(time.Second))
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// TAP
+
+var (
+	tapPlanRegex     = regexp.MustCompile(`^1\.\.(\d+)`)
+	tapTestRegex     = regexp.MustCompile(`^(not )?ok\b(?:\s+(\d+))?\s*(?:-\s*)?([^#]*?)\s*(?:#\s*(.*))?$`)
+	tapDurationRegex = regexp.MustCompile(`^\s*duration_ms:\s*([0-9.]+)`)
+)
+
+// parseTAPReport parses a Test Anything Protocol report. Tests with a SKIP
+// directive, and failing tests with a TODO directive, are skipped. The YAML
+// diagnostics and comments that follow a test are its log, and a
+// duration_ms diagnostic is its duration. Tests that the plan promises but
+// the report lacks fail, as does a report that bails out.
+func parseTAPReport(r io.Reader) ([]TestReportCase, error) {
+	cases := []TestReportCase{}
+	planned := -1
+	inYAML := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if inYAML {
+			if trimmed == "..." {
+				inYAML = false
+				continue
+			}
+			last := &cases[len(cases)-1]
+			last.Log = append(last.Log, line)
+			if m := tapDurationRegex.FindStringSubmatch(line); m != nil {
+				if ms, err := strconv.ParseFloat(m[1], 64); err == nil {
+					last.Duration = time.Duration(ms * float64(time.Millisecond))
+				}
+			}
+			continue
+		}
+		if trimmed == "---" && line != trimmed && len(cases) > 0 {
+			inYAML = true
+			continue
+		}
+		// indented lines other than diagnostics belong to subtests, which
+		// the parent test line summarizes
+		if line != strings.TrimLeft(line, " \t") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Bail out!"):
+			cases = append(cases, TestReportCase{
+				Name:   "Bail out",
+				Status: evergreen.TestFailedStatus,
+				Log:    []string{line},
+			})
+			return cases, nil
+		case strings.HasPrefix(line, "#"):
+			if len(cases) > 0 {
+				last := &cases[len(cases)-1]
+				last.Log = append(last.Log, line)
+			}
+		case tapPlanRegex.MatchString(line):
+			planned, _ = strconv.Atoi(tapPlanRegex.FindStringSubmatch(line)[1])
+		default:
+			m := tapTestRegex.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			cases = append(cases, tapTestCase(len(cases)+1, m))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "problem reading report")
+	}
+
+	for i := len(cases) + 1; i <= planned; i++ {
+		cases = append(cases, TestReportCase{
+			Name:   fmt.Sprintf("test %d", i),
+			Status: evergreen.TestFailedStatus,
+			Log:    []string{fmt.Sprintf("test %d was planned but did not run", i)},
+		})
+	}
+
+	return cases, nil
+}
+
+func tapTestCase(num int, m []string) TestReportCase {
+	failed := m[1] != ""
+	if m[2] != "" {
+		num, _ = strconv.Atoi(m[2])
+	}
+	c := TestReportCase{Name: m[3]}
+	if c.Name == "" {
+		c.Name = fmt.Sprintf("test %d", num)
+	}
+
+	directive := strings.ToUpper(m[4])
+	switch {
+	case strings.HasPrefix(directive, "SKIP"):
+		c.Status = evergreen.TestSkippedStatus
+	case strings.HasPrefix(directive, "TODO") && failed:
+		c.Status = evergreen.TestSkippedStatus
+	case failed:
+		c.Status = evergreen.TestFailedStatus
+	default:
+		c.Status = evergreen.TestSucceededStatus
+	}
+	if m[4] != "" {
+		c.Log = []string{fmt.Sprintf("# %s", m[4])}
+	}
+
+	return c
+}
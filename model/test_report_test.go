@@ -0,0 +1,145 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const junitReport = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="suite" timestamp="2018-06-01T12:00:00" time="3.5">
+    <testcase classname="pkg.Class" name="passes" time="1.5"/>
+    <testcase classname="pkg.Class" name="fails" time="2">
+      <failure message="expected 1" type="AssertionError">at line 12</failure>
+    </testcase>
+    <testcase name="skipped"><skipped message="not on this platform"/></testcase>
+    <system-out>suite output</system-out>
+  </testsuite>
+  <testsuite name="broken">
+    <error message="setup failed" type="Exception"/>
+  </testsuite>
+</testsuites>`
+
+const tapReport = `TAP version 13
+1..6
+ok 1 - adds numbers
+not ok 2 - divides by zero
+  ---
+  message: division by zero
+  duration_ms: 250
+  ...
+# cleaning up
+ok 3 # SKIP no network
+not ok 4 - flaky # TODO fix the race
+ok 5 subtest parent
+    ok 1 - nested
+`
+
+func TestParseJUnitReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cases, err := ParseTestReport(TestReportFormatJUnit, strings.NewReader(junitReport))
+	require.NoError(err)
+	require.Len(cases, 4)
+
+	start := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal("pkg.Class.passes", cases[0].Name)
+	assert.Equal(evergreen.TestSucceededStatus, cases[0].Status)
+	assert.Equal(1500*time.Millisecond, cases[0].Duration)
+	assert.Equal(start, cases[0].Start)
+	assert.Empty(cases[0].Log)
+
+	assert.Equal(evergreen.TestFailedStatus, cases[1].Status)
+	assert.Equal(start.Add(1500*time.Millisecond), cases[1].Start)
+	assert.Equal([]string{"FAILURE: expected 1 (AssertionError)", "at line 12", "system-out:", "suite output"}, cases[1].Log)
+
+	assert.Equal("skipped", cases[2].Name)
+	assert.Equal(evergreen.TestSkippedStatus, cases[2].Status)
+
+	assert.Equal("broken", cases[3].Name)
+	assert.Equal(evergreen.TestFailedStatus, cases[3].Status)
+
+	cases, err = ParseTestReport(TestReportFormatJUnit, strings.NewReader(`<testsuite><testcase name="only"/></testsuite>`))
+	require.NoError(err)
+	require.Len(cases, 1)
+	assert.Equal("only", cases[0].Name)
+	assert.True(cases[0].Start.IsZero())
+
+	_, err = ParseTestReport(TestReportFormatJUnit, strings.NewReader("not xml"))
+	assert.Error(err)
+	_, err = ParseTestReport("csv", strings.NewReader(""))
+	assert.Error(err)
+}
+
+func TestParseTAPReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cases, err := ParseTestReport(TestReportFormatTAP, strings.NewReader(tapReport))
+	require.NoError(err)
+	require.Len(cases, 6)
+
+	assert.Equal("adds numbers", cases[0].Name)
+	assert.Equal(evergreen.TestSucceededStatus, cases[0].Status)
+
+	assert.Equal("divides by zero", cases[1].Name)
+	assert.Equal(evergreen.TestFailedStatus, cases[1].Status)
+	assert.Equal(250*time.Millisecond, cases[1].Duration)
+	assert.Equal([]string{"  message: division by zero", "  duration_ms: 250", "# cleaning up"}, cases[1].Log)
+
+	assert.Equal("test 3", cases[2].Name)
+	assert.Equal(evergreen.TestSkippedStatus, cases[2].Status)
+	assert.Equal([]string{"# SKIP no network"}, cases[2].Log)
+
+	assert.Equal("flaky", cases[3].Name)
+	assert.Equal(evergreen.TestSkippedStatus, cases[3].Status)
+
+	assert.Equal("subtest parent", cases[4].Name)
+	assert.Equal(evergreen.TestSucceededStatus, cases[4].Status)
+
+	assert.Equal("test 6", cases[5].Name)
+	assert.Equal(evergreen.TestFailedStatus, cases[5].Status)
+
+	cases, err = ParseTestReport(TestReportFormatTAP, strings.NewReader("1..3\nok 1\nBail out! database is down\n"))
+	require.NoError(err)
+	require.Len(cases, 2)
+	assert.Equal("Bail out", cases[1].Name)
+	assert.Equal(evergreen.TestFailedStatus, cases[1].Status)
+}
+
+func TestIngestTestReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(TestLogCollection, testresult.Collection))
+
+	tsk := &task.Task{Id: "t1", Execution: 1}
+	results, err := IngestTestReport(tsk, TestReportFormatTAP, strings.NewReader(tapReport))
+	require.NoError(err)
+	require.Len(results, 6)
+	assert.Empty(results[0].LogId)
+	assert.NotEmpty(results[1].LogId)
+	assert.Equal("/test_log/t1/1/divides_by_zero", results[1].URL)
+	assert.InDelta(0.25, results[1].EndTime-results[1].StartTime, 0.001)
+
+	log, err := FindOneTestLogById(results[1].LogId)
+	require.NoError(err)
+	require.NotNil(log)
+	assert.Equal("t1", log.Task)
+	assert.Equal(1, log.TaskExecution)
+
+	stored, err := testresult.Find(testresult.ByTaskIDs([]string{"t1"}))
+	require.NoError(err)
+	assert.Len(stored, 6)
+
+	_, err = IngestTestReport(tsk, TestReportFormatTAP, strings.NewReader("1..0\n"))
+	assert.Error(err)
+}
@@ -0,0 +1,118 @@
+package testflakiness
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+)
+
+// TestRun is the result of one execution of a test in a mainline version.
+type TestRun struct {
+	Variant string
+	Task    string
+	Test    string
+	// Order is the revision order number of the version.
+	Order int
+	// CreateTime is when the version was created.
+	CreateTime time.Time
+	Execution  int
+	Status     string
+}
+
+type testKey struct {
+	variant string
+	task    string
+	test    string
+}
+
+// versionRun is the outcome of a test in one version, across the
+// executions of its task.
+type versionRun struct {
+	order      int
+	createTime time.Time
+	// failed is whether any execution failed and passed is whether the
+	// last execution passed.
+	failed        bool
+	passed        bool
+	lastExecution int
+}
+
+// Compute returns the flakiness of each test of a project that flaked in
+// the runs. A failure is a flake when a later execution of the task in the
+// same version passed, or when the test passed in the versions on either
+// side of it, since in both cases the code didn't change between the
+// failure and a pass. Skipped tests are ignored.
+func Compute(project string, runs []TestRun, now time.Time) []TestFlakiness {
+	byTest := map[testKey]map[int]*versionRun{}
+	for _, r := range runs {
+		if r.Status == evergreen.TestSkippedStatus {
+			continue
+		}
+		key := testKey{variant: r.Variant, task: r.Task, test: r.Test}
+		if byTest[key] == nil {
+			byTest[key] = map[int]*versionRun{}
+		}
+		vr, ok := byTest[key][r.Order]
+		if !ok {
+			vr = &versionRun{order: r.Order, createTime: r.CreateTime, lastExecution: -1}
+			byTest[key][r.Order] = vr
+		}
+		failed := r.Status == evergreen.TestFailedStatus
+		vr.failed = vr.failed || failed
+		if r.Execution >= vr.lastExecution {
+			vr.lastExecution = r.Execution
+			vr.passed = !failed
+		}
+	}
+
+	stats := []TestFlakiness{}
+	for key, versions := range byTest {
+		ordered := make([]*versionRun, 0, len(versions))
+		for _, vr := range versions {
+			ordered = append(ordered, vr)
+		}
+		sort.Slice(ordered, func(i, j int) bool { return ordered[i].order < ordered[j].order })
+
+		stat := TestFlakiness{
+			Id: TestFlakinessKey{
+				Project: project,
+				Variant: key.variant,
+				Task:    key.task,
+				Test:    key.test,
+			},
+			Runs:       len(ordered),
+			LastUpdate: now,
+		}
+		for i, vr := range ordered {
+			if !vr.failed {
+				continue
+			}
+			stat.Failures++
+
+			flaked := vr.passed
+			if !flaked && i > 0 && i < len(ordered)-1 {
+				flaked = ordered[i-1].passed && ordered[i+1].passed
+			}
+			if flaked {
+				stat.Flakes++
+				if vr.createTime.After(stat.LastFlake) {
+					stat.LastFlake = vr.createTime
+				}
+			}
+		}
+		if stat.Flakes == 0 {
+			continue
+		}
+		stat.FlakeRate = float64(stat.Flakes) / float64(stat.Runs)
+		stats = append(stats, stat)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].FlakeRate != stats[j].FlakeRate {
+			return stats[i].FlakeRate > stats[j].FlakeRate
+		}
+		return stats[i].Id.Test < stats[j].Id.Test
+	})
+	return stats
+}
@@ -0,0 +1,146 @@
+package testflakiness
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the test flakiness collection in the
+	// database.
+	Collection = "test_flakiness"
+
+	// FlakyRate is the flake rate at which a test is considered flaky.
+	FlakyRate = 0.1
+	// MinRuns is the number of versions a test must have run in before its
+	// flake rate is trusted, so that one flake in a new test doesn't mark
+	// it flaky.
+	MinRuns = 5
+)
+
+// TestFlakiness is how often a test in a task failed and then passed
+// without any change to the code, across a project's recent mainline
+// versions.
+type TestFlakiness struct {
+	Id TestFlakinessKey `bson:"_id" json:"id"`
+	// Runs is the number of versions the test ran in.
+	Runs int `bson:"runs" json:"runs"`
+	// Failures is the number of versions the test failed in.
+	Failures int `bson:"failures" json:"failures"`
+	// Flakes is the number of those failures that were flakes.
+	Flakes     int       `bson:"flakes" json:"flakes"`
+	FlakeRate  float64   `bson:"flake_rate" json:"flake_rate"`
+	LastFlake  time.Time `bson:"last_flake" json:"last_flake"`
+	LastUpdate time.Time `bson:"last_update" json:"last_update"`
+}
+
+// TestFlakinessKey identifies a test within a task of a project's variant.
+type TestFlakinessKey struct {
+	Project string `bson:"project" json:"project"`
+	Variant string `bson:"variant" json:"variant"`
+	Task    string `bson:"task" json:"task"`
+	Test    string `bson:"test" json:"test"`
+}
+
+var (
+	IdKey         = bsonutil.MustHaveTag(TestFlakiness{}, "Id")
+	RunsKey       = bsonutil.MustHaveTag(TestFlakiness{}, "Runs")
+	FailuresKey   = bsonutil.MustHaveTag(TestFlakiness{}, "Failures")
+	FlakesKey     = bsonutil.MustHaveTag(TestFlakiness{}, "Flakes")
+	FlakeRateKey  = bsonutil.MustHaveTag(TestFlakiness{}, "FlakeRate")
+	LastFlakeKey  = bsonutil.MustHaveTag(TestFlakiness{}, "LastFlake")
+	LastUpdateKey = bsonutil.MustHaveTag(TestFlakiness{}, "LastUpdate")
+
+	keyProjectKey = bsonutil.MustHaveTag(TestFlakinessKey{}, "Project")
+	keyVariantKey = bsonutil.MustHaveTag(TestFlakinessKey{}, "Variant")
+	keyTaskKey    = bsonutil.MustHaveTag(TestFlakinessKey{}, "Task")
+)
+
+// IsFlaky returns whether the test flakes often enough, over enough runs,
+// that its failures shouldn't be treated as regressions.
+func (f *TestFlakiness) IsFlaky() bool {
+	return f.Runs >= MinRuns && f.FlakeRate >= FlakyRate
+}
+
+// ByProject returns the flakiness of the tests of a project, most flaky
+// first. The variant and task narrow the results when they're not empty.
+func ByProject(project, variant, task string) db.Q {
+	q := bson.M{bsonutil.GetDottedKeyName(IdKey, keyProjectKey): project}
+	if variant != "" {
+		q[bsonutil.GetDottedKeyName(IdKey, keyVariantKey)] = variant
+	}
+	if task != "" {
+		q[bsonutil.GetDottedKeyName(IdKey, keyTaskKey)] = task
+	}
+	return db.Query(q).Sort([]string{"-" + FlakeRateKey, "-" + FlakesKey})
+}
+
+// Find returns the test flakiness matching the query.
+func Find(query db.Q) ([]TestFlakiness, error) {
+	stats := []TestFlakiness{}
+	err := db.FindAllQ(Collection, query, &stats)
+	return stats, err
+}
+
+// FindByTask returns the flakiness of each test of a task in a project's
+// variant, keyed by test name.
+func FindByTask(project, variant, task string) (map[string]TestFlakiness, error) {
+	if variant == "" || task == "" {
+		return map[string]TestFlakiness{}, nil
+	}
+	stats, err := Find(ByProject(project, variant, task))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding test flakiness for task %s", task)
+	}
+	byTest := make(map[string]TestFlakiness, len(stats))
+	for _, s := range stats {
+		byTest[s.Id.Test] = s
+	}
+	return byTest, nil
+}
+
+// FindByTasks returns the flakiness of the tests of the tasks in a project's
+// variant, keyed by task name and then test name.
+func FindByTasks(project, variant string, tasks []string) (map[string]map[string]TestFlakiness, error) {
+	byTask := map[string]map[string]TestFlakiness{}
+	if variant == "" || len(tasks) == 0 {
+		return byTask, nil
+	}
+	stats, err := Find(db.Query(bson.M{
+		bsonutil.GetDottedKeyName(IdKey, keyProjectKey): project,
+		bsonutil.GetDottedKeyName(IdKey, keyVariantKey): variant,
+		bsonutil.GetDottedKeyName(IdKey, keyTaskKey):    bson.M{"$in": tasks},
+	}))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding test flakiness for tasks in %s", variant)
+	}
+	for _, s := range stats {
+		if byTask[s.Id.Task] == nil {
+			byTask[s.Id.Task] = map[string]TestFlakiness{}
+		}
+		byTask[s.Id.Task][s.Id.Test] = s
+	}
+	return byTask, nil
+}
+
+// ReplaceProject replaces the stored flakiness of a project's tests.
+func ReplaceProject(project string, stats []TestFlakiness) error {
+	err := db.RemoveAll(Collection, bson.M{bsonutil.GetDottedKeyName(IdKey, keyProjectKey): project})
+	if err != nil {
+		return errors.Wrapf(err, "problem removing test flakiness of project %s", project)
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(stats))
+	for i := range stats {
+		docs[i] = stats[i]
+	}
+	return errors.Wrapf(db.InsertMany(Collection, docs...),
+		"problem inserting test flakiness of project %s", project)
+}
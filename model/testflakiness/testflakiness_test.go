@@ -0,0 +1,122 @@
+package testflakiness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestCompute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	now := time.Now().Round(time.Second)
+	run := func(test string, order, execution int, status string) TestRun {
+		return TestRun{
+			Variant:    "bv",
+			Task:       "task",
+			Test:       test,
+			Order:      order,
+			CreateTime: now.Add(time.Duration(order) * time.Hour),
+			Execution:  execution,
+			Status:     status,
+		}
+	}
+	runs := []TestRun{
+		// passes on restart
+		run("restarted", 1, 0, evergreen.TestFailedStatus),
+		run("restarted", 1, 1, evergreen.TestSucceededStatus),
+		run("restarted", 2, 0, evergreen.TestSucceededStatus),
+		// fails once between passes
+		run("isolated", 1, 0, evergreen.TestSucceededStatus),
+		run("isolated", 2, 0, evergreen.TestFailedStatus),
+		run("isolated", 3, 0, evergreen.TestSkippedStatus),
+		run("isolated", 4, 0, evergreen.TestSucceededStatus),
+		// breaks and stays broken
+		run("broken", 1, 0, evergreen.TestSucceededStatus),
+		run("broken", 2, 0, evergreen.TestFailedStatus),
+		run("broken", 3, 0, evergreen.TestFailedStatus),
+		run("broken", 3, 1, evergreen.TestFailedStatus),
+		// always passes
+		run("stable", 1, 0, evergreen.TestSucceededStatus),
+	}
+
+	stats := Compute("p", runs, now)
+	require.Len(stats, 2)
+
+	assert.Equal(TestFlakinessKey{Project: "p", Variant: "bv", Task: "task", Test: "restarted"}, stats[0].Id)
+	assert.Equal(2, stats[0].Runs)
+	assert.Equal(1, stats[0].Failures)
+	assert.Equal(1, stats[0].Flakes)
+	assert.Equal(0.5, stats[0].FlakeRate)
+	assert.Equal(now.Add(time.Hour), stats[0].LastFlake)
+	assert.Equal(now, stats[0].LastUpdate)
+
+	assert.Equal("isolated", stats[1].Id.Test)
+	assert.Equal(3, stats[1].Runs)
+	assert.Equal(1, stats[1].Flakes)
+	assert.InDelta(1.0/3, stats[1].FlakeRate, 0.0001)
+
+	assert.Empty(Compute("p", nil, now))
+}
+
+func TestIsFlaky(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True((&TestFlakiness{Runs: MinRuns, FlakeRate: FlakyRate}).IsFlaky())
+	assert.False((&TestFlakiness{Runs: MinRuns - 1, FlakeRate: 1}).IsFlaky())
+	assert.False((&TestFlakiness{Runs: 100, FlakeRate: FlakyRate / 2}).IsFlaky())
+}
+
+func TestReplaceProject(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+	defer func() {
+		assert.NoError(db.Clear(Collection))
+	}()
+
+	stat := func(project, test string, rate float64) TestFlakiness {
+		return TestFlakiness{
+			Id:        TestFlakinessKey{Project: project, Variant: "bv", Task: "task", Test: test},
+			Runs:      10,
+			FlakeRate: rate,
+		}
+	}
+	require.NoError(ReplaceProject("p", []TestFlakiness{stat("p", "a", 0.1), stat("p", "b", 0.5)}))
+	require.NoError(ReplaceProject("other", []TestFlakiness{stat("other", "a", 0.2)}))
+
+	stats, err := Find(ByProject("p", "", ""))
+	require.NoError(err)
+	require.Len(stats, 2)
+	assert.Equal("b", stats[0].Id.Test)
+
+	require.NoError(ReplaceProject("p", []TestFlakiness{stat("p", "c", 0.3)}))
+	byTest, err := FindByTask("p", "bv", "task")
+	require.NoError(err)
+	require.Len(byTest, 1)
+	assert.Equal(0.3, byTest["c"].FlakeRate)
+
+	byTask, err := FindByTasks("p", "bv", []string{"task", "missing"})
+	require.NoError(err)
+	require.Len(byTask, 1)
+	assert.Contains(byTask["task"], "c")
+
+	stats, err = Find(ByProject("other", "bv", "task"))
+	require.NoError(err)
+	assert.Len(stats, 1)
+
+	require.NoError(ReplaceProject("p", nil))
+	stats, err = Find(ByProject("p", "", ""))
+	require.NoError(err)
+	assert.Empty(stats)
+}
@@ -1,7 +1,10 @@
 package testresult
 
 import (
+	"fmt"
+
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/archive"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -59,7 +62,42 @@ func FindByTaskIDAndExecution(taskID string, execution int) ([]TestResult, error
 		TaskIDKey:    0,
 		ExecutionKey: 0,
 	})
-	return Find(q)
+	results, err := Find(q)
+	if len(results) > 0 || err != nil {
+		return results, err
+	}
+
+	results = []TestResult{}
+	if err = archive.Find(Collection, ArchiveKey(taskID, execution), &results); err != nil {
+		return nil, errors.Wrap(err, "problem finding archived test results")
+	}
+	for i := range results {
+		results[i].TaskID = ""
+		results[i].Execution = 0
+	}
+	return results, nil
+}
+
+// ArchiveKey returns the key that the test results of a task execution are
+// archived under.
+func ArchiveKey(taskID string, execution int) string {
+	return fmt.Sprintf("%s/%d", taskID, execution)
+}
+
+// ArchiveForTasks moves the test results of all executions of the tasks into
+// the archive store.
+func ArchiveForTasks(store archive.Store, taskIDs []string) error {
+	byExecution := func(doc bson.M) string {
+		execution, _ := doc[ExecutionKey].(int)
+		return ArchiveKey(fmt.Sprint(doc[TaskIDKey]), execution)
+	}
+	for _, id := range taskIDs {
+		if _, err := archive.Move(store, Collection, bson.M{TaskIDKey: id}, db.NoLimit, byExecution); err != nil {
+			return errors.Wrapf(err, "problem archiving test results for task '%s'", id)
+		}
+	}
+
+	return nil
 }
 
 func ByTaskIDs(ids []string) db.Q {
@@ -70,6 +108,20 @@ func ByTaskIDs(ids []string) db.Q {
 	})
 }
 
+// ByTaskIDsAndStatuses creates a query to return the test results of the
+// tasks that have one of the statuses, or any status if none are given.
+func ByTaskIDsAndStatuses(ids []string, statuses []string) db.Q {
+	q := bson.M{
+		TaskIDKey: bson.M{
+			"$in": ids,
+		},
+	}
+	if len(statuses) > 0 {
+		q[StatusKey] = bson.M{"$in": statuses}
+	}
+	return db.Query(q)
+}
+
 // find returns all test results that satisfy the query. Returns an empty slice no tasks match.
 func Find(query db.Q) ([]TestResult, error) {
 	tests := []TestResult{}
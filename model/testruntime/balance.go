@@ -0,0 +1,71 @@
+package testruntime
+
+import (
+	"sort"
+	"time"
+)
+
+// defaultRuntime is the runtime assumed for every test when none of a
+// task's tests have run before.
+const defaultRuntime = time.Minute
+
+// Balance splits the tests into the given number of shards so that the
+// shards' total runtimes are as even as possible. Tests without a recorded
+// runtime are assumed to take the average of the tests that have one. The
+// split only depends on its inputs, so tasks created from the same
+// runtimes get the same shards.
+func Balance(tests []string, runtimes map[string]time.Duration, shards int) [][]string {
+	if shards < 1 {
+		shards = 1
+	}
+
+	estimate := defaultRuntime
+	var total time.Duration
+	var known int
+	for _, test := range tests {
+		if runtime, ok := runtimes[test]; ok {
+			total += runtime
+			known++
+		}
+	}
+	if known > 0 {
+		estimate = total / time.Duration(known)
+	}
+
+	type weightedTest struct {
+		name    string
+		runtime time.Duration
+	}
+	weighted := make([]weightedTest, 0, len(tests))
+	for _, test := range tests {
+		runtime, ok := runtimes[test]
+		if !ok {
+			runtime = estimate
+		}
+		weighted = append(weighted, weightedTest{name: test, runtime: runtime})
+	}
+	sort.SliceStable(weighted, func(i, j int) bool {
+		if weighted[i].runtime != weighted[j].runtime {
+			return weighted[i].runtime > weighted[j].runtime
+		}
+		return weighted[i].name < weighted[j].name
+	})
+
+	// assign the longest remaining test to the least loaded shard
+	result := make([][]string, shards)
+	loads := make([]time.Duration, shards)
+	for _, test := range weighted {
+		shard := 0
+		for i := range loads {
+			if loads[i] < loads[shard] {
+				shard = i
+			}
+		}
+		result[shard] = append(result[shard], test.name)
+		loads[shard] += test.runtime
+	}
+	for i := range result {
+		sort.Strings(result[i])
+	}
+	return result
+}
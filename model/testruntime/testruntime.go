@@ -0,0 +1,117 @@
+package testruntime
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// Collection is the name of the test runtimes collection in the database.
+	Collection = "test_runtimes"
+
+	// driftWeight is how much a new run moves a test's average runtime, so
+	// that shards are rebalanced as runtimes drift without one slow run
+	// reshuffling every shard.
+	driftWeight = 0.3
+)
+
+// TestRuntime is the average runtime of a test in a task, used to balance
+// the tests of a sharded task across its shards.
+type TestRuntime struct {
+	Id         TestRuntimeKey `bson:"_id" json:"id"`
+	Average    time.Duration  `bson:"average" json:"average"`
+	NumRuns    int            `bson:"num_runs" json:"num_runs"`
+	LastUpdate time.Time      `bson:"last_update" json:"last_update"`
+}
+
+// TestRuntimeKey identifies a test within a task of a project's variant.
+type TestRuntimeKey struct {
+	Project string `bson:"project" json:"project"`
+	Variant string `bson:"variant" json:"variant"`
+	Task    string `bson:"task" json:"task"`
+	Test    string `bson:"test" json:"test"`
+}
+
+var (
+	IdKey         = bsonutil.MustHaveTag(TestRuntime{}, "Id")
+	AverageKey    = bsonutil.MustHaveTag(TestRuntime{}, "Average")
+	NumRunsKey    = bsonutil.MustHaveTag(TestRuntime{}, "NumRuns")
+	LastUpdateKey = bsonutil.MustHaveTag(TestRuntime{}, "LastUpdate")
+
+	keyProjectKey = bsonutil.MustHaveTag(TestRuntimeKey{}, "Project")
+	keyVariantKey = bsonutil.MustHaveTag(TestRuntimeKey{}, "Variant")
+	keyTaskKey    = bsonutil.MustHaveTag(TestRuntimeKey{}, "Task")
+)
+
+// ByTask returns the runtimes of the tests of a task in a project's variant.
+func ByTask(project, variant, task string) db.Q {
+	return db.Query(bson.M{
+		bsonutil.GetDottedKeyName(IdKey, keyProjectKey): project,
+		bsonutil.GetDottedKeyName(IdKey, keyVariantKey): variant,
+		bsonutil.GetDottedKeyName(IdKey, keyTaskKey):    task,
+	})
+}
+
+// Find returns the test runtimes matching the query.
+func Find(query db.Q) ([]TestRuntime, error) {
+	runtimes := []TestRuntime{}
+	err := db.FindAllQ(Collection, query, &runtimes)
+	return runtimes, err
+}
+
+// FindByTask returns the average runtime of each test of a task in a
+// project's variant, keyed by test name.
+func FindByTask(project, variant, task string) (map[string]time.Duration, error) {
+	runtimes, err := Find(ByTask(project, variant, task))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding test runtimes for task %s", task)
+	}
+	averages := make(map[string]time.Duration, len(runtimes))
+	for _, r := range runtimes {
+		averages[r.Id.Test] = r.Average
+	}
+	return averages, nil
+}
+
+// Update folds the durations of a run of a task's tests into their average
+// runtimes.
+func Update(project, variant, task string, durations map[string]time.Duration) error {
+	if len(durations) == 0 {
+		return nil
+	}
+	averages, err := FindByTask(project, variant, task)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	now := time.Now()
+	for test, duration := range durations {
+		average := duration
+		if previous, ok := averages[test]; ok {
+			average = previous + time.Duration(driftWeight*float64(duration-previous))
+		}
+		key := TestRuntimeKey{
+			Project: project,
+			Variant: variant,
+			Task:    task,
+			Test:    test,
+		}
+		_, err = db.Upsert(Collection,
+			bson.M{IdKey: key},
+			bson.M{
+				"$set": bson.M{
+					AverageKey:    average,
+					LastUpdateKey: now,
+				},
+				"$inc": bson.M{NumRunsKey: 1},
+			})
+		if err != nil {
+			return errors.Wrapf(err, "problem updating runtime of test %s", test)
+		}
+	}
+	return nil
+}
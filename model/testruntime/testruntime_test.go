@@ -0,0 +1,78 @@
+package testruntime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+}
+
+func TestBalance(t *testing.T) {
+	assert := assert.New(t)
+
+	runtimes := map[string]time.Duration{
+		"a": 8 * time.Minute,
+		"b": 5 * time.Minute,
+		"c": 4 * time.Minute,
+		"d": 3 * time.Minute,
+	}
+	shards := Balance([]string{"a", "b", "c", "d"}, runtimes, 2)
+	assert.Equal([][]string{{"a", "d"}, {"b", "c"}}, shards)
+
+	// unknown tests take the average of the known ones
+	shards = Balance([]string{"a", "b", "new"}, map[string]time.Duration{"a": 2 * time.Minute, "b": 4 * time.Minute}, 2)
+	assert.Equal([][]string{{"b"}, {"a", "new"}}, shards)
+
+	// every shard exists even without enough tests to fill it
+	shards = Balance([]string{"a"}, nil, 3)
+	assert.Len(shards, 3)
+	assert.Equal([]string{"a"}, shards[0])
+	assert.Empty(shards[1])
+	assert.Empty(shards[2])
+
+	assert.Len(Balance(nil, nil, 0), 1)
+}
+
+func TestUpdate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(Collection))
+	defer func() {
+		assert.NoError(db.Clear(Collection))
+	}()
+
+	require.NoError(Update("p", "bv", "task", map[string]time.Duration{
+		"a": 10 * time.Second,
+		"b": 20 * time.Second,
+	}))
+	require.NoError(Update("p", "other", "task", map[string]time.Duration{
+		"a": time.Hour,
+	}))
+	averages, err := FindByTask("p", "bv", "task")
+	require.NoError(err)
+	assert.Equal(map[string]time.Duration{"a": 10 * time.Second, "b": 20 * time.Second}, averages)
+
+	// later runs move the average towards the new runtime
+	require.NoError(Update("p", "bv", "task", map[string]time.Duration{
+		"a": 20 * time.Second,
+	}))
+	averages, err = FindByTask("p", "bv", "task")
+	require.NoError(err)
+	assert.Equal(13*time.Second, averages["a"])
+	assert.Equal(20*time.Second, averages["b"])
+
+	runtimes, err := Find(ByTask("p", "bv", "task"))
+	require.NoError(err)
+	for _, r := range runtimes {
+		if r.Id.Test == "a" {
+			assert.Equal(2, r.NumRuns)
+		}
+	}
+}
@@ -23,7 +23,9 @@ var (
 	SettingsKey         = bsonutil.MustHaveTag(DBUser{}, "Settings")
 	APIKeyKey           = bsonutil.MustHaveTag(DBUser{}, "APIKey")
 	PubKeysKey          = bsonutil.MustHaveTag(DBUser{}, "PubKeys")
+	SystemRolesKey      = bsonutil.MustHaveTag(DBUser{}, "SystemRoles")
 	LoginCacheKey       = bsonutil.MustHaveTag(DBUser{}, "LoginCache")
+	OIDCSubjectKey      = bsonutil.MustHaveTag(DBUser{}, "OIDCSubject")
 	LoginCacheTokenKey  = bsonutil.MustHaveTag(LoginCache{}, "Token")
 	LoginCacheTTLKey    = bsonutil.MustHaveTag(LoginCache{}, "TTL")
 	PubKeyNameKey       = bsonutil.MustHaveTag(PubKey{}, "Name")
@@ -38,8 +40,10 @@ var (
 )
 
 var (
-	SettingsTZKey             = bsonutil.MustHaveTag(UserSettings{}, "Timezone")
-	userSettingsGithubUserKey = bsonutil.MustHaveTag(UserSettings{}, "GithubUser")
+	SettingsTZKey                = bsonutil.MustHaveTag(UserSettings{}, "Timezone")
+	userSettingsGithubUserKey    = bsonutil.MustHaveTag(UserSettings{}, "GithubUser")
+	userSettingsSlackMemberIdKey = bsonutil.MustHaveTag(UserSettings{}, "SlackMemberId")
+	userSettingsDeliveryKey      = bsonutil.MustHaveTag(UserSettings{}, "Delivery")
 )
 
 func FindByGithubUID(uid int) (*DBUser, error) {
@@ -57,6 +61,23 @@ func FindByGithubUID(uid int) (*DBUser, error) {
 	return &u, nil
 }
 
+// FindBySlackMemberId returns the user whose settings name the given slack
+// member ID, if any.
+func FindBySlackMemberId(memberId string) (*DBUser, error) {
+	u := DBUser{}
+	err := db.FindOneQ(Collection, db.Query(bson.M{
+		bsonutil.GetDottedKeyName(SettingsKey, userSettingsSlackMemberIdKey): memberId,
+	}), &u)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch user by slack member ID")
+	}
+
+	return &u, nil
+}
+
 func ById(userId string) db.Q {
 	return db.Query(bson.M{IdKey: userId})
 }
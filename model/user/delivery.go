@@ -0,0 +1,225 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const minutesPerDay = 24 * 60
+
+// DeliveryChannels are the channels that notifications to a user can be
+// re-routed between.
+var DeliveryChannels = []string{
+	event.EmailSubscriberType,
+	event.SlackSubscriberType,
+}
+
+// DeliveryPreferences control when and where notifications for a user's
+// subscriptions are delivered.
+type DeliveryPreferences struct {
+	// QuietHours is the time of day during which notifications are held
+	// until the quiet hours end
+	QuietHours *QuietHours `bson:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+	// ChannelPriority lists delivery channels in order of preference.
+	// Notifications to any of these channels are delivered to the first
+	// one that the user can receive notifications on.
+	ChannelPriority []string `bson:"channel_priority,omitempty" json:"channel_priority,omitempty"`
+	// Projects override these preferences for notifications about
+	// particular projects
+	Projects []ProjectDeliveryPreferences `bson:"projects,omitempty" json:"projects,omitempty"`
+}
+
+// ProjectDeliveryPreferences override a user's delivery preferences for a
+// project. Fields that are not set are not overridden.
+type ProjectDeliveryPreferences struct {
+	Project         string      `bson:"project" json:"project"`
+	QuietHours      *QuietHours `bson:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+	ChannelPriority []string    `bson:"channel_priority,omitempty" json:"channel_priority,omitempty"`
+}
+
+// QuietHours is a time of day, in the user's timezone, given in minutes
+// after midnight. Quiet hours that end before they start span midnight.
+type QuietHours struct {
+	Start int `bson:"start" json:"start"`
+	End   int `bson:"end" json:"end"`
+}
+
+// Validate returns an error describing every invalid preference.
+func (p *DeliveryPreferences) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	validateDeliveryPreferences("", p.QuietHours, p.ChannelPriority, catcher)
+
+	seen := map[string]bool{}
+	for i, project := range p.Projects {
+		field := fmt.Sprintf("projects[%d]", i)
+		if project.Project == "" {
+			catcher.Add(errors.Errorf("%s.project: cannot be empty", field))
+		} else if seen[project.Project] {
+			catcher.Add(errors.Errorf("%s.project: '%s' is repeated", field, project.Project))
+		}
+		seen[project.Project] = true
+		validateDeliveryPreferences(field+".", project.QuietHours, project.ChannelPriority, catcher)
+	}
+
+	return catcher.Resolve()
+}
+
+func validateDeliveryPreferences(prefix string, quietHours *QuietHours, channels []string, catcher grip.Catcher) {
+	if quietHours != nil {
+		if quietHours.Start < 0 || quietHours.Start >= minutesPerDay {
+			catcher.Add(errors.Errorf("%squiet_hours.start: must be between 00:00 and 23:59", prefix))
+		}
+		if quietHours.End < 0 || quietHours.End >= minutesPerDay {
+			catcher.Add(errors.Errorf("%squiet_hours.end: must be between 00:00 and 23:59", prefix))
+		}
+		if quietHours.Start == quietHours.End {
+			catcher.Add(errors.Errorf("%squiet_hours: start and end cannot be the same", prefix))
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, channel := range channels {
+		if !util.StringSliceContains(DeliveryChannels, channel) {
+			catcher.Add(errors.Errorf("%schannel_priority: '%s' is not one of %v", prefix, channel, DeliveryChannels))
+		} else if seen[channel] {
+			catcher.Add(errors.Errorf("%schannel_priority: '%s' is repeated", prefix, channel))
+		}
+		seen[channel] = true
+	}
+}
+
+// ForProject returns the preferences for notifications about the project,
+// with the project's overrides applied.
+func (p *DeliveryPreferences) ForProject(project string) DeliveryPreferences {
+	out := DeliveryPreferences{
+		QuietHours:      p.QuietHours,
+		ChannelPriority: p.ChannelPriority,
+	}
+	if project == "" {
+		return out
+	}
+	for _, override := range p.Projects {
+		if override.Project != project {
+			continue
+		}
+		if override.QuietHours != nil {
+			out.QuietHours = override.QuietHours
+		}
+		if len(override.ChannelPriority) != 0 {
+			out.ChannelPriority = override.ChannelPriority
+		}
+	}
+
+	return out
+}
+
+// SetProject replaces the overrides for the project, or removes them if
+// prefs is nil.
+func (p *DeliveryPreferences) SetProject(project string, prefs *ProjectDeliveryPreferences) {
+	projects := make([]ProjectDeliveryPreferences, 0, len(p.Projects)+1)
+	for _, override := range p.Projects {
+		if override.Project != project {
+			projects = append(projects, override)
+		}
+	}
+	if prefs != nil {
+		prefs.Project = project
+		projects = append(projects, *prefs)
+	}
+	p.Projects = projects
+}
+
+// Remaining returns how long the quiet hours last after now, in the
+// location, or zero if now is not in the quiet hours.
+func (q *QuietHours) Remaining(now time.Time, loc *time.Location) time.Duration {
+	if q == nil {
+		return 0
+	}
+	now = now.In(loc)
+	minute := now.Hour()*60 + now.Minute()
+	if q.Start < q.End && (minute < q.Start || minute >= q.End) {
+		return 0
+	}
+	if q.Start > q.End && minute < q.Start && minute >= q.End {
+		return 0
+	}
+
+	end := time.Date(now.Year(), now.Month(), now.Day(), q.End/60, q.End%60, 0, 0, loc)
+	if !end.After(now) {
+		end = end.AddDate(0, 0, 1)
+	}
+
+	return end.Sub(now)
+}
+
+// QuietHoursRemaining returns how long the user's quiet hours for
+// notifications about the project last after now, or zero if they are not
+// in quiet hours.
+func (u *DBUser) QuietHoursRemaining(project string, now time.Time) time.Duration {
+	loc, err := time.LoadLocation(u.Settings.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	prefs := u.Settings.Delivery.ForProject(project)
+
+	return prefs.QuietHours.Remaining(now, loc)
+}
+
+// PreferredSubscriber returns the subscriber that a notification about the
+// project to the subscriber should be delivered to, according to the
+// user's channel priorities. Only notifications to the channels in the
+// priorities are re-routed, and only to channels that the user has an
+// address for.
+func (u *DBUser) PreferredSubscriber(sub event.Subscriber, project string) event.Subscriber {
+	prefs := u.Settings.Delivery.ForProject(project)
+	if !util.StringSliceContains(prefs.ChannelPriority, sub.Type) {
+		return sub
+	}
+
+	for _, channel := range prefs.ChannelPriority {
+		var target string
+		switch channel {
+		case event.EmailSubscriberType:
+			target = u.Email()
+		case event.SlackSubscriberType:
+			if u.Settings.SlackUsername != "" {
+				target = "@" + u.Settings.SlackUsername
+			}
+		}
+		if target == "" {
+			continue
+		}
+		if channel == sub.Type {
+			return sub
+		}
+
+		return event.Subscriber{
+			Type:   channel,
+			Target: &target,
+		}
+	}
+
+	return sub
+}
+
+// SetDeliveryPreferences replaces the user's delivery preferences.
+func (u *DBUser) SetDeliveryPreferences(prefs DeliveryPreferences) error {
+	update := bson.M{
+		"$set": bson.M{
+			bsonutil.GetDottedKeyName(SettingsKey, userSettingsDeliveryKey): prefs,
+		},
+	}
+	if err := UpdateOne(bson.M{IdKey: u.Id}, update); err != nil {
+		return errors.Wrapf(err, "problem saving delivery preferences for user '%s'", u.Id)
+	}
+	u.Settings.Delivery = prefs
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHoursRemaining(t *testing.T) {
+	assert := assert.New(t)
+
+	day := func(hour, minute int) time.Time {
+		return time.Date(2018, 6, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	var none *QuietHours
+	assert.Zero(none.Remaining(day(12, 0), time.UTC))
+
+	lunch := &QuietHours{Start: 12 * 60, End: 13 * 60}
+	assert.Zero(lunch.Remaining(day(11, 59), time.UTC))
+	assert.Equal(time.Hour, lunch.Remaining(day(12, 0), time.UTC))
+	assert.Equal(30*time.Minute, lunch.Remaining(day(12, 30), time.UTC))
+	assert.Zero(lunch.Remaining(day(13, 0), time.UTC))
+
+	night := &QuietHours{Start: 22 * 60, End: 7 * 60}
+	assert.Zero(night.Remaining(day(21, 0), time.UTC))
+	assert.Equal(9*time.Hour, night.Remaining(day(22, 0), time.UTC))
+	assert.Equal(time.Hour, night.Remaining(day(6, 0), time.UTC))
+	assert.Zero(night.Remaining(day(7, 0), time.UTC))
+
+	// quiet hours are in the user's timezone
+	loc := time.FixedZone("UTC-4", -4*60*60)
+	assert.Equal(3*time.Hour, night.Remaining(day(8, 0), loc))
+
+	u := &DBUser{Settings: UserSettings{
+		Timezone: "not a timezone",
+		Delivery: DeliveryPreferences{
+			QuietHours: night,
+			Projects: []ProjectDeliveryPreferences{
+				{Project: "mci", QuietHours: lunch},
+			},
+		},
+	}}
+	assert.Equal(time.Hour, u.QuietHoursRemaining("", day(6, 0)))
+	assert.Zero(u.QuietHoursRemaining("mci", day(6, 0)))
+	assert.Equal(time.Hour, u.QuietHoursRemaining("mci", day(12, 0)))
+}
+
+func TestPreferredSubscriber(t *testing.T) {
+	assert := assert.New(t)
+
+	u := &DBUser{
+		Id:           "me",
+		EmailAddress: "me@example.com",
+		Settings: UserSettings{
+			Delivery: DeliveryPreferences{
+				ChannelPriority: []string{event.SlackSubscriberType, event.EmailSubscriberType},
+				Projects: []ProjectDeliveryPreferences{
+					{Project: "mci", ChannelPriority: []string{event.EmailSubscriberType}},
+				},
+			},
+		},
+	}
+	email := "me@example.com"
+	emailSub := event.Subscriber{Type: event.EmailSubscriberType, Target: &email}
+
+	// without a slack username, slack is skipped
+	assert.Equal(emailSub, u.PreferredSubscriber(emailSub, ""))
+
+	u.Settings.SlackUsername = "me"
+	sub := u.PreferredSubscriber(emailSub, "")
+	assert.Equal(event.SlackSubscriberType, sub.Type)
+	assert.Equal("@me", *sub.Target.(*string))
+
+	// project overrides apply
+	assert.Equal(emailSub, u.PreferredSubscriber(emailSub, "mci"))
+
+	// channels that aren't in the priorities aren't re-routed
+	webhookSub := event.Subscriber{Type: event.EvergreenWebhookSubscriberType}
+	assert.Equal(webhookSub, u.PreferredSubscriber(webhookSub, ""))
+	channel := "@me"
+	slackSub := event.Subscriber{Type: event.SlackSubscriberType, Target: &channel}
+	assert.Equal(slackSub, u.PreferredSubscriber(slackSub, "mci"))
+}
+
+func TestDeliveryPreferencesValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	prefs := DeliveryPreferences{
+		QuietHours:      &QuietHours{Start: 22 * 60, End: 7 * 60},
+		ChannelPriority: []string{event.SlackSubscriberType, event.EmailSubscriberType},
+	}
+	prefs.SetProject("mci", &ProjectDeliveryPreferences{ChannelPriority: []string{event.EmailSubscriberType}})
+	assert.NoError(prefs.Validate())
+	assert.Len(prefs.Projects, 1)
+	prefs.SetProject("mci", nil)
+	assert.Empty(prefs.Projects)
+
+	prefs = DeliveryPreferences{
+		QuietHours:      &QuietHours{Start: 60, End: 60},
+		ChannelPriority: []string{event.SlackSubscriberType, event.SlackSubscriberType, event.JIRAIssueSubscriberType},
+		Projects: []ProjectDeliveryPreferences{
+			{Project: "mci", QuietHours: &QuietHours{Start: -1, End: 24 * 60}},
+			{Project: "mci"},
+			{},
+		},
+	}
+	err := prefs.Validate()
+	assert.Error(err)
+	assert.Contains(err.Error(), "quiet_hours: start and end cannot be the same")
+	assert.Contains(err.Error(), "channel_priority: 'slack' is repeated")
+	assert.Contains(err.Error(), "channel_priority: 'jira-issue' is not one of")
+	assert.Contains(err.Error(), "projects[0].quiet_hours.start: must be between")
+	assert.Contains(err.Error(), "projects[0].quiet_hours.end: must be between")
+	assert.Contains(err.Error(), "projects[1].project: 'mci' is repeated")
+	assert.Contains(err.Error(), "projects[2].project: cannot be empty")
+}
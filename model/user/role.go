@@ -0,0 +1,89 @@
+package user
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Role is a user's level of access to a project or distro. Each role
+// includes the permissions of the roles below it: viewers can see the
+// project, contributors can also submit patches and act on their own,
+// project admins can also change its settings and act on anyone's patches,
+// and superusers can do anything anywhere.
+type Role string
+
+const (
+	RoleNone         Role = ""
+	RoleViewer       Role = "viewer"
+	RoleContributor  Role = "contributor"
+	RoleProjectAdmin Role = "project_admin"
+	RoleSuperuser    Role = "superuser"
+)
+
+var roleRanks = map[Role]int{
+	RoleNone:         0,
+	RoleViewer:       1,
+	RoleContributor:  2,
+	RoleProjectAdmin: 3,
+	RoleSuperuser:    4,
+}
+
+// Includes returns whether the role has all the permissions of the other
+// role. Unknown roles include nothing but no role.
+func (r Role) Includes(other Role) bool {
+	return roleRanks[r] >= roleRanks[other]
+}
+
+// IsValid returns whether the role is one of the defined roles.
+func (r Role) IsValid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// HighestRole returns the role that includes all the others.
+func HighestRole(roles ...Role) Role {
+	highest := RoleNone
+	for _, r := range roles {
+		if roleRanks[r] > roleRanks[highest] {
+			highest = r
+		}
+	}
+	return highest
+}
+
+// ProjectRoleGrant returns the system role that grants the role on the
+// project, or on every project if the project is empty, e.g.
+// "contributor:mci" or "viewer:*".
+func ProjectRoleGrant(role Role, project string) string {
+	if project == "" {
+		project = "*"
+	}
+	return string(role) + ":" + project
+}
+
+// ProjectRole returns the highest role that the user's system roles grant
+// on the project.
+func (u *DBUser) ProjectRole(project string) Role {
+	granted := RoleNone
+	for _, grant := range u.SystemRoles {
+		parts := strings.SplitN(grant, ":", 2)
+		if len(parts) != 2 || (parts[1] != project && parts[1] != "*") {
+			continue
+		}
+		if r := Role(parts[0]); r.IsValid() && r != RoleSuperuser {
+			granted = HighestRole(granted, r)
+		}
+	}
+	return granted
+}
+
+// SetSystemRoles replaces the user's system roles.
+func (u *DBUser) SetSystemRoles(roles []string) error {
+	if err := UpdateOne(bson.M{IdKey: u.Id}, bson.M{"$set": bson.M{SystemRolesKey: roles}}); err != nil {
+		return errors.Wrapf(err, "problem setting roles of user '%s'", u.Id)
+	}
+	u.SystemRoles = roles
+	return nil
+}
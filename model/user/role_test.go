@@ -0,0 +1,25 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleIncludes(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(RoleSuperuser.Includes(RoleProjectAdmin))
+	assert.True(RoleProjectAdmin.Includes(RoleContributor))
+	assert.True(RoleContributor.Includes(RoleViewer))
+	assert.True(RoleViewer.Includes(RoleViewer))
+	assert.True(RoleNone.Includes(RoleNone))
+	assert.False(RoleViewer.Includes(RoleContributor))
+	assert.False(RoleContributor.Includes(RoleProjectAdmin))
+	assert.False(RoleProjectAdmin.Includes(RoleSuperuser))
+	assert.False(RoleNone.Includes(RoleViewer))
+	assert.False(Role("owner").Includes(RoleViewer))
+
+	assert.True(RoleContributor.IsValid())
+	assert.False(Role("owner").IsValid())
+}
@@ -0,0 +1,215 @@
+package user
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	ServiceKeyCollection = "service_keys"
+
+	// ServiceKeyPrefix starts the tokens of service keys, which
+	// distinguishes them from users' API keys in the Api-Key header.
+	ServiceKeyPrefix = "evgsk_"
+
+	// ServiceKeyUserPrefix starts the usernames of the users that service
+	// keys act as, so that they can't collide with real users.
+	ServiceKeyUserPrefix = "service_key:"
+
+	// ScopeReadOnly allows GET requests to the REST API.
+	ScopeReadOnly = "read_only"
+	// ScopeNotifications allows requests to the notification routes of the
+	// REST API, e.g. to send notifications.
+	ScopeNotifications = "notifications"
+	// ScopeProjectAdminPrefix starts the scopes that allow administering
+	// a project, e.g. "project_admin:mci", including its tasks, builds,
+	// versions and patches.
+	ScopeProjectAdminPrefix = "project_admin:"
+)
+
+// nolint: deadcode, megacheck, unused
+var (
+	serviceKeyIDKey        = bsonutil.MustHaveTag(ServiceKey{}, "ID")
+	serviceKeyNameKey      = bsonutil.MustHaveTag(ServiceKey{}, "Name")
+	serviceKeyHashKey      = bsonutil.MustHaveTag(ServiceKey{}, "Hash")
+	serviceKeyScopesKey    = bsonutil.MustHaveTag(ServiceKey{}, "Scopes")
+	serviceKeyExpiresAtKey = bsonutil.MustHaveTag(ServiceKey{}, "ExpiresAt")
+)
+
+// ServiceKey is an API key for a service account, such as a CI bot, that
+// can only make the requests its scopes allow, until it expires. Only the
+// hash of the key's secret is stored; its token is shown once, when the key
+// is created.
+type ServiceKey struct {
+	ID     string   `bson:"_id"`
+	Name   string   `bson:"name"`
+	Hash   string   `bson:"hash"`
+	Scopes []string `bson:"scopes"`
+
+	// ExpiresAt is when the key stops working. A zero time means the key
+	// doesn't expire.
+	ExpiresAt time.Time `bson:"expires_at,omitempty"`
+
+	CreatedBy string    `bson:"created_by"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+// ProjectAdminScope returns the scope that allows administering the project.
+func ProjectAdminScope(project string) string {
+	return ScopeProjectAdminPrefix + project
+}
+
+// NewServiceKey returns a key with the given scopes, along with the token
+// that authenticates requests as it.
+func NewServiceKey(name string, scopes []string, expiresAt time.Time, user string) (*ServiceKey, string, error) {
+	k := &ServiceKey{
+		ID:        bson.NewObjectId().Hex(),
+		Name:      name,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt.Truncate(time.Millisecond),
+		CreatedBy: user,
+		CreatedAt: time.Now().Truncate(time.Millisecond),
+	}
+	secret := util.RandomString()
+	k.Hash = hashServiceKeySecret(secret)
+
+	return k, fmt.Sprintf("%s%s_%s", ServiceKeyPrefix, k.ID, secret), k.Validate()
+}
+
+func (k *ServiceKey) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if k.Name == "" {
+		catcher.Add(errors.New("service key name cannot be empty"))
+	}
+	if k.Hash == "" {
+		catcher.Add(errors.New("service key hash cannot be empty"))
+	}
+	if len(k.Scopes) == 0 {
+		catcher.Add(errors.New("service key must have at least one scope"))
+	}
+	for _, scope := range k.Scopes {
+		switch {
+		case scope == ScopeReadOnly, scope == ScopeNotifications:
+		case strings.HasPrefix(scope, ScopeProjectAdminPrefix) && len(scope) > len(ScopeProjectAdminPrefix):
+		default:
+			catcher.Add(errors.Errorf("invalid service key scope '%s'", scope))
+		}
+	}
+	if !k.ExpiresAt.IsZero() && !k.ExpiresAt.After(k.CreatedAt) {
+		catcher.Add(errors.New("service key cannot expire before it's created"))
+	}
+
+	return catcher.Resolve()
+}
+
+// IsExpired returns true if the key has expired.
+func (k *ServiceKey) IsExpired() bool {
+	return !k.ExpiresAt.IsZero() && time.Now().After(k.ExpiresAt)
+}
+
+// HasScope returns true if the key has the scope.
+func (k *ServiceKey) HasScope(scope string) bool {
+	return util.StringSliceContains(k.Scopes, scope)
+}
+
+// User returns the user that requests authenticated by the key act as.
+func (k *ServiceKey) User() *DBUser {
+	return &DBUser{
+		Id:       ServiceKeyUserPrefix + k.Name,
+		DispName: k.Name,
+	}
+}
+
+func (k *ServiceKey) Insert() error {
+	return errors.Wrap(db.Insert(ServiceKeyCollection, k), "failed to insert service key")
+}
+
+// FindServiceKeyByToken returns the key the token authenticates as, or nil
+// if the token doesn't match any key. Expired keys are returned, so that
+// callers can tell them apart.
+func FindServiceKeyByToken(token string) (*ServiceKey, error) {
+	id, _, ok := parseServiceKeyToken(token)
+	if !ok {
+		return nil, nil
+	}
+
+	k, err := FindServiceKey(id)
+	if err != nil || k == nil {
+		return nil, err
+	}
+	if !k.Authenticates(token) {
+		return nil, nil
+	}
+
+	return k, nil
+}
+
+// Authenticates returns true if the token is the key's.
+func (k *ServiceKey) Authenticates(token string) bool {
+	id, secret, ok := parseServiceKeyToken(token)
+	if !ok || id != k.ID {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(k.Hash), []byte(hashServiceKeySecret(secret))) == 1
+}
+
+func FindServiceKey(id string) (*ServiceKey, error) {
+	k := ServiceKey{}
+	err := db.FindOneQ(ServiceKeyCollection, db.Query(bson.M{
+		serviceKeyIDKey: id,
+	}), &k)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+
+	return &k, errors.Wrapf(err, "failed to find service key '%s'", id)
+}
+
+func FindAllServiceKeys() ([]ServiceKey, error) {
+	keys := []ServiceKey{}
+	err := db.FindAllQ(ServiceKeyCollection, db.Query(bson.M{}).Sort([]string{serviceKeyIDKey}), &keys)
+
+	return keys, errors.Wrap(err, "failed to find service keys")
+}
+
+func RemoveServiceKey(id string) error {
+	err := db.Remove(ServiceKeyCollection, bson.M{
+		serviceKeyIDKey: id,
+	})
+	if err == mgo.ErrNotFound {
+		return errors.Errorf("service key '%s' does not exist", id)
+	}
+
+	return errors.Wrapf(err, "failed to remove service key '%s'", id)
+}
+
+// parseServiceKeyToken splits a token into the ID of its key and its secret.
+func parseServiceKeyToken(token string) (string, string, bool) {
+	if !strings.HasPrefix(token, ServiceKeyPrefix) {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(token, ServiceKeyPrefix), "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+
+	return parts[0], parts[1], true
+}
+
+func hashServiceKeySecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
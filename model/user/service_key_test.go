@@ -0,0 +1,53 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceKeys(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(ServiceKeyCollection))
+
+	_, _, err := NewServiceKey("bot", []string{"admin"}, time.Time{}, "me")
+	assert.Error(err)
+	_, _, err = NewServiceKey("bot", []string{ScopeProjectAdminPrefix}, time.Time{}, "me")
+	assert.Error(err)
+	_, _, err = NewServiceKey("bot", []string{ScopeReadOnly}, time.Now().Add(-time.Hour), "me")
+	assert.Error(err)
+
+	k, token, err := NewServiceKey("bot", []string{ScopeReadOnly, ProjectAdminScope("mci")}, time.Now().Add(time.Hour), "me")
+	require.NoError(err)
+	require.NoError(k.Insert())
+	assert.False(k.IsExpired())
+	assert.True(k.HasScope(ProjectAdminScope("mci")))
+	assert.False(k.HasScope(ScopeNotifications))
+	assert.Equal(ServiceKeyUserPrefix+"bot", k.User().Username())
+
+	found, err := FindServiceKeyByToken(token)
+	require.NoError(err)
+	require.NotNil(found)
+	assert.Equal(k.ID, found.ID)
+	assert.NotContains(found.Hash, token)
+
+	for _, bad := range []string{"", "apikey", token + "x", ServiceKeyPrefix + k.ID, ServiceKeyPrefix + "nope_secret"} {
+		found, err = FindServiceKeyByToken(bad)
+		assert.NoError(err)
+		assert.Nil(found, bad)
+	}
+
+	keys, err := FindAllServiceKeys()
+	require.NoError(err)
+	assert.Len(keys, 1)
+
+	require.NoError(RemoveServiceKey(k.ID))
+	found, err = FindServiceKeyByToken(token)
+	assert.NoError(err)
+	assert.Nil(found)
+	assert.Error(RemoveServiceKey(k.ID))
+}
@@ -26,6 +26,9 @@ type DBUser struct {
 	APIKey       string       `bson:"apikey"`
 	SystemRoles  []string     `bson:"roles"`
 	LoginCache   LoginCache   `bson:"login_cache,omitempty"`
+	// OIDCSubject is the subject of the OpenID Connect identity that
+	// created the user, which is the only identity that can log in as it.
+	OIDCSubject string `bson:"oidc_subject,omitempty"`
 }
 
 type LoginCache struct {
@@ -50,6 +53,10 @@ type UserSettings struct {
 	GithubUser    GithubUser              `json:"github_user" bson:"github_user,omitempty"`
 	SlackUsername string                  `bson:"slack_username,omitempty" json:"slack_username,omitempty"`
 	Notifications NotificationPreferences `bson:"notifications,omitempty" json:"notifications,omitempty"`
+	Delivery      DeliveryPreferences     `bson:"delivery,omitempty" json:"delivery,omitempty"`
+	// SlackMemberId is the ID of the user's Slack account, which, unlike
+	// their Slack username, they can't change
+	SlackMemberId string `bson:"slack_member_id,omitempty" json:"slack_member_id,omitempty"`
 }
 
 type NotificationPreferences struct {
@@ -223,6 +230,12 @@ func PutLoginCache(g gimlet.User) (string, error) {
 	return token, nil
 }
 
+// ClearLoginCache removes the user's token, which ends the user's session.
+func ClearLoginCache(userId string) error {
+	err := UpdateOne(bson.M{IdKey: userId}, bson.M{"$unset": bson.M{LoginCacheKey: 1}})
+	return errors.Wrap(err, "problem clearing user cache")
+}
+
 // GetLoginCache retrieve a cached user by token.
 // It returns an error if and only if there was an error retrieving the user from the cache.
 // It returns (<user>, true, nil) if the user is present in the cache and is valid.
@@ -36,6 +36,8 @@ func (s *UserTestSuite) SetupTest() {
 					UID:         1234,
 					LastKnownAs: "octocat",
 				},
+				SlackUsername: "test1",
+				SlackMemberId: "U1",
 			},
 			LoginCache: LoginCache{
 				Token: "1234",
@@ -157,6 +159,17 @@ func (s *UserTestSuite) TestFindByGithubUID() {
 	s.Nil(u)
 }
 
+func (s *UserTestSuite) TestFindBySlackMemberId() {
+	u, err := FindBySlackMemberId("U1")
+	s.NoError(err)
+	s.Require().NotNil(u)
+	s.Equal("Test1", u.Id)
+
+	u, err = FindBySlackMemberId("test1")
+	s.NoError(err)
+	s.Nil(u)
+}
+
 func (s *UserTestSuite) TestFindOneByToken() {
 	u, err := FindOneByToken("1234")
 	s.NoError(err)
@@ -55,3 +55,38 @@ func GetOrCreateUser(userId, displayName, email string) (*user.DBUser, error) {
 	}
 	return u, nil
 }
+
+// GetOrCreateOIDCUser fetches the user with the given userId, as long as it
+// was created by the OpenID Connect identity with the given subject, and
+// updates its display name and email. If no document exists for that userId,
+// it inserts one bound to the subject. Users that were created by any other
+// identity can't be fetched, so that an identity can't take over an existing
+// user by claiming its name.
+func GetOrCreateOIDCUser(userId, subject, displayName, email string) (*user.DBUser, error) {
+	if subject == "" {
+		return nil, errors.Errorf("no subject given for user '%s'", userId)
+	}
+
+	u := &user.DBUser{}
+	_, err := db.FindAndModify(user.Collection, bson.M{user.IdKey: userId, user.OIDCSubjectKey: subject}, nil,
+		mgo.Change{
+			Update: bson.M{
+				"$set": bson.M{
+					user.DispNameKey:     displayName,
+					user.EmailAddressKey: email,
+				},
+				"$setOnInsert": bson.M{
+					user.APIKeyKey: util.RandomString(),
+				},
+			},
+			ReturnNew: true,
+			Upsert:    true,
+		}, u)
+	if db.IsDuplicateKey(err) {
+		return nil, errors.Errorf("user '%s' belongs to a different identity", userId)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem find/create user '%s'", userId)
+	}
+	return u, nil
+}
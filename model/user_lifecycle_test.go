@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOrCreateOIDCUser(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(user.Collection))
+	defer func() {
+		assert.NoError(db.Clear(user.Collection))
+	}()
+
+	u, err := GetOrCreateOIDCUser("annie", "subject-1", "Annie", "annie@example.com")
+	require.NoError(err)
+	assert.Equal("subject-1", u.OIDCSubject)
+	assert.NotEmpty(u.APIKey)
+
+	u, err = GetOrCreateOIDCUser("annie", "subject-1", "Annie Admin", "annie@example.com")
+	require.NoError(err)
+	assert.Equal("Annie Admin", u.DispName)
+
+	// other identities can't log in as an existing user
+	_, err = GetOrCreateOIDCUser("annie", "subject-2", "Annie", "annie@example.com")
+	assert.Error(err)
+	_, err = GetOrCreateUser("root", "Root", "root@example.com")
+	require.NoError(err)
+	_, err = GetOrCreateOIDCUser("root", "subject-2", "Root", "root@example.com")
+	assert.Error(err)
+	_, err = GetOrCreateOIDCUser("bob", "", "Bob", "bob@example.com")
+	assert.Error(err)
+}
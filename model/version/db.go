@@ -37,10 +37,16 @@ var (
 	RepoKindKey            = bsonutil.MustHaveTag(Version{}, "RepoKind")
 	ErrorsKey              = bsonutil.MustHaveTag(Version{}, "Errors")
 	WarningsKey            = bsonutil.MustHaveTag(Version{}, "Warnings")
+	InheritedWarningsKey   = bsonutil.MustHaveTag(Version{}, "InheritedWarnings")
+	DeprecationsKey        = bsonutil.MustHaveTag(Version{}, "Deprecations")
 	IdentifierKey          = bsonutil.MustHaveTag(Version{}, "Identifier")
 	RemoteKey              = bsonutil.MustHaveTag(Version{}, "Remote")
 	RemoteURLKey           = bsonutil.MustHaveTag(Version{}, "RemotePath")
 	TriggerIDKey           = bsonutil.MustHaveTag(Version{}, "TriggerID")
+	TagKey                 = bsonutil.MustHaveTag(Version{}, "Tag")
+	PriorityKey            = bsonutil.MustHaveTag(Version{}, "Priority")
+	TaskSelectionKey       = bsonutil.MustHaveTag(Version{}, "TaskSelection")
+	ParametersKey          = bsonutil.MustHaveTag(Version{}, "Parameters")
 )
 
 // ById returns a db.Q object which will filter on {_id : <the id param>}
@@ -108,6 +114,23 @@ func ByProjectIdAndOrder(projectId string, revisionOrderNumber int) db.Q {
 		})
 }
 
+// ByProjectIdUpToOrder finds the versions of a project other than patches,
+// with revision order numbers at most the given one, most recent first. An
+// order number of 0 finds all of the project's versions.
+func ByProjectIdUpToOrder(projectId string, revisionOrderNumber int) db.Q {
+	q := bson.M{
+		IdentifierKey: projectId,
+		RequesterKey: bson.M{
+			"$nin": evergreen.PatchRequesters,
+		},
+	}
+	if revisionOrderNumber > 0 {
+		q[RevisionOrderNumberKey] = bson.M{"$lte": revisionOrderNumber}
+	}
+
+	return db.Query(q).Sort([]string{"-" + RevisionOrderNumberKey})
+}
+
 // ByLastVariantActivation finds the most recent non-patch, non-ignored
 // versions in a project that have a particular variant activated.
 func ByLastVariantActivation(projectId, variant string) db.Q {
@@ -167,6 +190,24 @@ func ByMostRecentNonIgnored(projectId string) db.Q {
 	).Sort([]string{"-" + RevisionOrderNumberKey})
 }
 
+// ByLastFullRun finds the most recent mainline version of a project that
+// runs all of its tasks rather than a selection of them.
+func ByLastFullRun(projectId string) db.Q {
+	return db.Query(
+		bson.M{
+			IdentifierKey: projectId,
+			IgnoredKey:    bson.M{"$ne": true},
+			RequesterKey:  evergreen.RepotrackerVersionRequester,
+			TaskSelectionKey: bson.M{
+				"$exists": false,
+			},
+			ErrorsKey: bson.M{
+				"$exists": false,
+			},
+		},
+	).Sort([]string{"-" + RevisionOrderNumberKey})
+}
+
 func BySuccessfulBeforeRevision(project string, beforeRevision int) db.Q {
 	return db.Query(
 		bson.M{
@@ -182,6 +223,44 @@ func BySuccessfulBeforeRevision(project string, beforeRevision int) db.Q {
 	)
 }
 
+// ByFinishedBeforeRevision finds mainline versions of the project that
+// finished before the given revision, most recent first.
+func ByFinishedBeforeRevision(project string, beforeRevision int) db.Q {
+	return db.Query(
+		bson.M{
+			RequesterKey: bson.M{
+				"$in": evergreen.SystemVersionRequesterTypes,
+			},
+			IdentifierKey: project,
+			StatusKey: bson.M{
+				"$in": []string{evergreen.VersionSucceeded, evergreen.VersionFailed},
+			},
+			RevisionOrderNumberKey: bson.M{
+				"$lt": beforeRevision,
+			},
+		},
+	).Sort([]string{"-" + RevisionOrderNumberKey})
+}
+
+// ByFinishedAfterRevision finds mainline versions of the project that
+// finished after the given revision.
+func ByFinishedAfterRevision(project string, afterRevision int) db.Q {
+	return db.Query(
+		bson.M{
+			RequesterKey: bson.M{
+				"$in": evergreen.SystemVersionRequesterTypes,
+			},
+			IdentifierKey: project,
+			StatusKey: bson.M{
+				"$in": []string{evergreen.VersionSucceeded, evergreen.VersionFailed},
+			},
+			RevisionOrderNumberKey: bson.M{
+				"$gt": afterRevision,
+			},
+		},
+	)
+}
+
 // BaseVersionFromPatch finds the base version for a patch version.
 func BaseVersionFromPatch(projectId, revision string) db.Q {
 	return db.Query(
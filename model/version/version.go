@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -47,6 +48,12 @@ type Version struct {
 	// this field is omitted in the database
 	Errors   []string `bson:"errors,omitempty" json:"errors,omitempty"`
 	Warnings []string `bson:"warnings,omitempty" json:"warnings,omitempty"`
+	// InheritedWarnings is the subset of Warnings that were also present
+	// in the previous version, and so were not introduced by this revision
+	InheritedWarnings []string `bson:"inherited_warnings,omitempty" json:"inherited_warnings,omitempty"`
+	// Deprecations describe the deprecated syntax that the version's project
+	// config uses, which is also reported among its warnings
+	Deprecations []ConfigDeprecation `bson:"deprecations,omitempty" json:"deprecations,omitempty"`
 
 	// AuthorID is an optional reference to the Evergreen user that authored
 	// this comment, if they can be identified
@@ -54,6 +61,86 @@ type Version struct {
 
 	// ID of the document that triggered this version to be created
 	TriggerID string `bson:"trigger_id,omitempty" json:"trigger_id,omitempty"`
+
+	// Tag holds the annotation of the git tag that points at this version's
+	// revision, if the version was tagged
+	Tag *TagMetadata `bson:"tag,omitempty" json:"tag,omitempty"`
+
+	// Priority is inherited by the version's tasks whose build and own
+	// priorities are unset
+	Priority int64 `bson:"priority,omitempty" json:"priority,omitempty"`
+
+	// TaskSelection, if set, limits the tasks activated with the version's
+	// builds to those affected by its changed files. Versions without one
+	// run all of their tasks.
+	TaskSelection *TaskSelection `bson:"task_selection,omitempty" json:"task_selection,omitempty"`
+
+	// Parameters are the values of the project's parameters for the
+	// version, which its tasks receive as expansions.
+	Parameters []Parameter `bson:"parameters,omitempty" json:"parameters,omitempty"`
+}
+
+// Parameter is the value of a project parameter, given when a version or
+// patch is created.
+type Parameter struct {
+	Key   string `bson:"key" json:"key"`
+	Value string `bson:"value" json:"value"`
+}
+
+// TaskSelection records the tasks selected to run for a version's changed
+// files.
+type TaskSelection struct {
+	Tasks        []string `bson:"tasks" json:"tasks"`
+	ChangedFiles []string `bson:"changed_files" json:"changed_files"`
+}
+
+// TagMetadata stores the annotation of a git tag
+type TagMetadata struct {
+	Name              string    `bson:"name" json:"name"`
+	SHA               string    `bson:"sha" json:"sha"`
+	Message           string    `bson:"message,omitempty" json:"message,omitempty"`
+	Tagger            string    `bson:"tagger,omitempty" json:"tagger,omitempty"`
+	TaggerEmail       string    `bson:"tagger_email,omitempty" json:"tagger_email,omitempty"`
+	TagTime           time.Time `bson:"tag_time,omitempty" json:"tag_time,omitempty"`
+	SignatureVerified bool      `bson:"signature_verified" json:"signature_verified"`
+	SignatureReason   string    `bson:"signature_reason,omitempty" json:"signature_reason,omitempty"`
+}
+
+// ConfigDeprecation is deprecated syntax used in a project config, along
+// with the syntax that replaces it.
+type ConfigDeprecation struct {
+	Syntax      string `bson:"syntax" json:"syntax"`
+	Replacement string `bson:"replacement" json:"replacement"`
+	// Since is the schema version that deprecated the syntax.
+	Since int `bson:"since" json:"since"`
+	// Locations are where the syntax is used in the config.
+	Locations []string `bson:"locations,omitempty" json:"locations,omitempty"`
+}
+
+// NewWarnings returns the warnings that were introduced by this version,
+// i.e. those that were not inherited from the previous version.
+func (v *Version) NewWarnings() []string {
+	warnings := []string{}
+	for _, w := range v.Warnings {
+		if !util.StringSliceContains(v.InheritedWarnings, w) {
+			warnings = append(warnings, w)
+		}
+	}
+	return warnings
+}
+
+// MarkInheritedWarnings records which of the version's warnings were
+// already present in the previous version.
+func (v *Version) MarkInheritedWarnings(previous *Version) {
+	v.InheritedWarnings = nil
+	if previous == nil {
+		return
+	}
+	for _, w := range v.Warnings {
+		if util.StringSliceContains(previous.Warnings, w) {
+			v.InheritedWarnings = append(v.InheritedWarnings, w)
+		}
+	}
 }
 
 func (v *Version) LastSuccessful() (*Version, error) {
@@ -76,6 +163,26 @@ func (self *Version) UpdateBuildVariants() error {
 	)
 }
 
+// SetTagMetadata stores the annotation of the tag pointing at the version
+func (v *Version) SetTagMetadata(tag *TagMetadata) error {
+	if tag == nil {
+		return errors.New("tag metadata must not be nil")
+	}
+	err := UpdateOne(
+		bson.M{IdKey: v.Id},
+		bson.M{
+			"$set": bson.M{
+				TagKey: tag,
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error setting tag metadata for version '%s'", v.Id)
+	}
+	v.Tag = tag
+	return nil
+}
+
 func (self *Version) Insert() error {
 	return db.Insert(Collection, self)
 }
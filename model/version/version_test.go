@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -117,3 +118,25 @@ func (s *testFindDuplicateVersionsSuite) TestIt() {
 		}
 	}
 }
+
+func TestInheritedWarnings(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &Version{
+		Warnings: []string{"old warning", "new warning"},
+	}
+	v.MarkInheritedWarnings(nil)
+	assert.Empty(v.InheritedWarnings)
+	assert.Equal(v.Warnings, v.NewWarnings())
+
+	previous := &Version{
+		Warnings: []string{"old warning", "fixed warning"},
+	}
+	v.MarkInheritedWarnings(previous)
+	assert.Equal([]string{"old warning"}, v.InheritedWarnings)
+	assert.Equal([]string{"new warning"}, v.NewWarnings())
+
+	v.Warnings = []string{"old warning"}
+	v.MarkInheritedWarnings(previous)
+	assert.Empty(v.NewWarnings())
+}
@@ -75,7 +75,12 @@ func ActivateElapsedBuilds(v *version.Version) error {
 			})
 
 			// Don't need to set the version in here since we do it ourselves in a single update
-			if err = SetBuildActivation(b.Id, true, evergreen.DefaultTaskActivator); err != nil {
+			if v.TaskSelection != nil {
+				err = ActivateSelectedBuildTasks(b.Id, v.TaskSelection.Tasks, evergreen.DefaultTaskActivator)
+			} else {
+				err = SetBuildActivation(b.Id, true, evergreen.DefaultTaskActivator)
+			}
+			if err != nil {
 				grip.Error(message.WrapError(err, message.Fields{
 					"operation": "project-activation",
 					"message":   "problem activating build",
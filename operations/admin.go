@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
@@ -27,6 +29,7 @@ func Admin() cli.Command {
 			listEvents(),
 			revert(),
 			fetchAllProjectConfigs(),
+			migrateProjectVars(),
 		},
 	}
 }
@@ -170,6 +173,10 @@ func setServiceFlagValues(args []string, target bool, flags *model.APIServiceFla
 			flags.RepotrackerDisabled = target
 		case "scheduler":
 			flags.SchedulerDisabled = target
+		case "api-writes", "read-only", "readonly":
+			flags.APIWritesDisabled = target
+		case "notification-dispatch", "dispatch-notifications":
+			flags.NotificationDispatchDisabled = target
 		default:
 			catcher.Add(errors.Errorf("%s is not a recognized service flag", f))
 		}
@@ -343,3 +350,53 @@ func revert() cli.Command {
 		},
 	}
 }
+
+func migrateProjectVars() cli.Command {
+	const (
+		backendFlagName     = "backend"
+		privateOnlyFlagName = "private-only"
+	)
+
+	return cli.Command{
+		Name:  "migrate-project-vars",
+		Usage: "move a project's variables into a secrets manager",
+		Flags: addProjectFlag(
+			cli.StringFlag{
+				Name:  joinFlagNames(backendFlagName, "b"),
+				Usage: fmt.Sprintf("secrets manager to store the variables in (%s)", strings.Join(thirdparty.ValidSecretsBackends, ", ")),
+			},
+			cli.BoolFlag{
+				Name:  privateOnlyFlagName,
+				Usage: "only move private variables",
+			}),
+		Before: mergeBeforeFuncs(setPlainLogger, requireStringFlag(projectFlagName), requireStringFlag(backendFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			project := c.String(projectFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			migration, err := client.MigrateProjectVarsToSecrets(ctx, project, &model.APIProjectVarsMigration{
+				Backend:     model.ToAPIString(c.String(backendFlagName)),
+				PrivateOnly: c.Bool(privateOnlyFlagName),
+			})
+			if err != nil {
+				return err
+			}
+			for _, name := range migration.Migrated {
+				grip.Infof("Moved variable '%s'", model.FromAPIString(name))
+			}
+			grip.Infof("Moved %d variables of project '%s'", len(migration.Migrated), project)
+
+			return nil
+		},
+	}
+}
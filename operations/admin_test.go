@@ -35,4 +35,10 @@ func TestAdminCommands(t *testing.T) {
 
 	assert.NoError(setServiceFlagValues([]string{"hostinit", "monitor", "agents", "tasks"}, false, flags))
 	assert.Zero(*flags)
+
+	assert.NoError(setServiceFlagValues([]string{"read-only", "notification-dispatch"}, true, flags))
+	assert.True(flags.APIWritesDisabled)
+	assert.True(flags.NotificationDispatchDisabled)
+	assert.NoError(setServiceFlagValues([]string{"api-writes", "notification-dispatch"}, false, flags))
+	assert.Zero(*flags)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
@@ -12,14 +13,19 @@ import (
 
 func Evaluate() cli.Command {
 	const (
-		taskFlagName     = "tasks"
-		variantsFlagName = "variants"
+		taskFlagName      = "tasks"
+		variantsFlagName  = "variants"
+		matrixFlagName    = "matrix"
+		patchFlagName     = "patch"
+		paramFlagName     = "param"
+		expansionFlagName = "expansion"
+		revisionFlagName  = "revision"
 	)
 
 	return cli.Command{
 		Name:  "evaluate",
 		Usage: "reads a project configuration and expands tags and matrix definitions, printing the expanded definitions",
-		Flags: addPathFlag(
+		Flags: addProjectFlag(addPathFlag(
 			cli.BoolFlag{
 				Name:  taskFlagName,
 				Usage: "only show task and function definitions",
@@ -27,7 +33,27 @@ func Evaluate() cli.Command {
 			cli.BoolFlag{
 				Name:  variantsFlagName,
 				Usage: "only show variant definitions",
-			}),
+			},
+			cli.BoolFlag{
+				Name:  matrixFlagName,
+				Usage: "show the builds and tasks that a version would be created with, and the expansions that each task starts with",
+			},
+			cli.BoolFlag{
+				Name:  patchFlagName,
+				Usage: "evaluate the matrix of a patch, which leaves out tasks that aren't patchable",
+			},
+			cli.StringSliceFlag{
+				Name:  paramFlagName,
+				Usage: "set a project parameter for the matrix, as key=value",
+			},
+			cli.StringSliceFlag{
+				Name:  expansionFlagName,
+				Usage: "set an expansion that the tasks' distros would provide for the matrix, as key=value",
+			},
+			cli.StringFlag{
+				Name:  revisionFlagName,
+				Usage: "the revision to evaluate the matrix for",
+			})...),
 		Before: requirePathFlag,
 		Action: func(c *cli.Context) error {
 			path := c.String(pathFlagName)
@@ -40,13 +66,38 @@ func Evaluate() cli.Command {
 			}
 
 			p := &model.Project{}
-			err = model.LoadProjectInto(configBytes, "", p)
+			err = model.LoadProjectInto(configBytes, c.String(projectFlagName), p)
 			if err != nil {
 				return errors.Wrap(err, "error loading project")
 			}
 
 			var out interface{}
-			if showTasks || showVariants {
+			if c.Bool(matrixFlagName) {
+				opts := model.EvaluateOptions{Revision: c.String(revisionFlagName)}
+				if c.Bool(patchFlagName) {
+					opts.Requester = evergreen.PatchVersionRequester
+				}
+				opts.Parameters, err = parsePatchParameters(c.StringSlice(paramFlagName))
+				if err != nil {
+					return errors.WithStack(err)
+				}
+				expansions, err := parsePatchParameters(c.StringSlice(expansionFlagName))
+				if err != nil {
+					return errors.Wrap(err, "invalid expansion")
+				}
+				opts.Expansions = map[string]string{}
+				for _, e := range expansions {
+					opts.Expansions[e.Key] = e.Value
+				}
+
+				variants, err := model.EvaluateProject(p, opts)
+				if err != nil {
+					return errors.Wrap(err, "error evaluating project matrix")
+				}
+				out = struct {
+					Variants []model.EvaluatedVariant `yaml:"buildvariants"`
+				}{variants}
+			} else if showTasks || showVariants {
 				tmp := struct {
 					Functions interface{} `yaml:"functions,omitempty"`
 					Tasks     interface{} `yaml:"tasks,omitempty"`
@@ -10,6 +10,11 @@ func Host() cli.Command {
 			hostCreate(),
 			hostlist(),
 			hostTerminate(),
+			hostStop(),
+			hostStart(),
+			hostAttach(),
+			hostDetach(),
+			hostTransfer(),
 			hostStatus(),
 			hostSetup(),
 			hostTeardown(),
@@ -165,3 +165,191 @@ func hostTerminate() cli.Command {
 		},
 	}
 }
+
+func hostStop() cli.Command {
+	return cli.Command{
+		Name:   "stop",
+		Usage:  "stop a running spawn host",
+		Flags:  addHostFlag(),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.StopSpawnHost(ctx, hostID); err != nil {
+				return errors.Wrap(err, "problem stopping host")
+			}
+
+			grip.Infof("Stopped host '%s'", hostID)
+
+			return nil
+		},
+	}
+}
+
+func hostStart() cli.Command {
+	return cli.Command{
+		Name:   "start",
+		Usage:  "start a stopped spawn host",
+		Flags:  addHostFlag(),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.StartSpawnHost(ctx, hostID); err != nil {
+				return errors.Wrap(err, "problem starting host")
+			}
+
+			grip.Infof("Started host '%s'", hostID)
+
+			return nil
+		},
+	}
+}
+
+func hostAttach() cli.Command {
+	const (
+		volumeFlagName = "volume"
+		deviceFlagName = "device"
+	)
+
+	return cli.Command{
+		Name:  "attach",
+		Usage: "attach a volume to a spawn host",
+		Flags: addHostFlag(
+			cli.StringFlag{
+				Name:  volumeFlagName,
+				Usage: "id of the volume to attach",
+			},
+			cli.StringFlag{
+				Name:  deviceFlagName,
+				Usage: "device name to attach the volume as (e.g. /dev/sdf)",
+			},
+		),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag,
+			requireStringFlag(volumeFlagName), requireStringFlag(deviceFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+			volumeID := c.String(volumeFlagName)
+			deviceName := c.String(deviceFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.AttachVolume(ctx, hostID, volumeID, deviceName); err != nil {
+				return errors.Wrap(err, "problem attaching volume")
+			}
+
+			grip.Infof("Attached volume '%s' to host '%s' as '%s'", volumeID, hostID, deviceName)
+
+			return nil
+		},
+	}
+}
+
+func hostDetach() cli.Command {
+	const volumeFlagName = "volume"
+
+	return cli.Command{
+		Name:  "detach",
+		Usage: "detach a volume from a spawn host",
+		Flags: addHostFlag(
+			cli.StringFlag{
+				Name:  volumeFlagName,
+				Usage: "id of the volume to detach",
+			},
+		),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag, requireStringFlag(volumeFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+			volumeID := c.String(volumeFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.DetachVolume(ctx, hostID, volumeID); err != nil {
+				return errors.Wrap(err, "problem detaching volume")
+			}
+
+			grip.Infof("Detached volume '%s' from host '%s'", volumeID, hostID)
+
+			return nil
+		},
+	}
+}
+
+func hostTransfer() cli.Command {
+	const ownerFlagName = "owner"
+
+	return cli.Command{
+		Name:  "transfer",
+		Usage: "transfer ownership of a spawn host to another user",
+		Flags: addHostFlag(
+			cli.StringFlag{
+				Name:  ownerFlagName,
+				Usage: "id of the user to transfer the host to",
+			},
+		),
+		Before: mergeBeforeFuncs(setPlainLogger, requireHostFlag, requireStringFlag(ownerFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+			owner := c.String(ownerFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.TransferSpawnHost(ctx, hostID, owner); err != nil {
+				return errors.Wrap(err, "problem transferring host")
+			}
+
+			grip.Infof("Transferred host '%s' to '%s'", hostID, owner)
+
+			return nil
+		},
+	}
+}
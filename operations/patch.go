@@ -9,11 +9,14 @@ import (
 )
 
 const (
-	patchDescriptionFlagName = "description"
-	patchFinalizeFlagName    = "finalize"
-	patchVerboseFlagName     = "verbose"
-	patchAliasFlagName       = "alias"
-	patchBrowseFlagName      = "browse"
+	patchDescriptionFlagName  = "description"
+	patchFinalizeFlagName     = "finalize"
+	patchVerboseFlagName      = "verbose"
+	patchAliasFlagName        = "alias"
+	patchBrowseFlagName       = "browse"
+	patchReuseFlagName        = "reuse"
+	patchReuseModulesFlagName = "reuse-modules"
+	patchParamFlagName        = "param"
 )
 
 func getPatchFlags(flags ...cli.Flag) []cli.Flag {
@@ -37,6 +40,18 @@ func getPatchFlags(flags ...cli.Flag) []cli.Flag {
 		cli.BoolFlag{
 			Name:  patchVerboseFlagName,
 			Usage: "show patch summary",
+		},
+		cli.BoolFlag{
+			Name:  patchReuseFlagName,
+			Usage: "run the variants and tasks of your previous patch of the project",
+		},
+		cli.BoolFlag{
+			Name:  patchReuseModulesFlagName,
+			Usage: "with --reuse, also patch the modules of your previous patch, at the same revisions",
+		},
+		cli.StringSliceFlag{
+			Name:  patchParamFlagName,
+			Usage: "set a project parameter for the patch, as key=value (may be specified multiple times)",
 		}))
 }
 
@@ -51,16 +66,19 @@ func Patch() cli.Command {
 			confPath := c.Parent().String(confFlagName)
 			args := c.Args()
 			params := &patchParams{
-				Project:     c.String(projectFlagName),
-				Variants:    c.StringSlice(variantsFlagName),
-				Tasks:       c.StringSlice(tasksFlagName),
-				SkipConfirm: c.Bool(yesFlagName),
-				Description: c.String(patchDescriptionFlagName),
-				Finalize:    c.Bool(patchFinalizeFlagName),
-				Browse:      c.Bool(patchBrowseFlagName),
-				ShowSummary: c.Bool(patchVerboseFlagName),
-				Large:       c.Bool(largeFlagName),
-				Alias:       c.String(patchAliasFlagName),
+				Project:      c.String(projectFlagName),
+				Variants:     c.StringSlice(variantsFlagName),
+				Tasks:        c.StringSlice(tasksFlagName),
+				SkipConfirm:  c.Bool(yesFlagName),
+				Description:  c.String(patchDescriptionFlagName),
+				Finalize:     c.Bool(patchFinalizeFlagName),
+				Browse:       c.Bool(patchBrowseFlagName),
+				ShowSummary:  c.Bool(patchVerboseFlagName),
+				Large:        c.Bool(largeFlagName),
+				Alias:        c.String(patchAliasFlagName),
+				Reuse:        c.Bool(patchReuseFlagName),
+				ReuseModules: c.Bool(patchReuseModulesFlagName),
+				Parameters:   c.StringSlice(patchParamFlagName),
 			}
 
 			ctx, cancel := context.WithCancel(context.Background())
@@ -116,14 +134,17 @@ func PatchFile() cli.Command {
 		Action: func(c *cli.Context) error {
 			confPath := c.Parent().String(confFlagName)
 			params := &patchParams{
-				Project:     c.String(projectFlagName),
-				Variants:    c.StringSlice(variantsFlagName),
-				Tasks:       c.StringSlice(tasksFlagName),
-				SkipConfirm: c.Bool(yesFlagName),
-				Description: c.String(patchDescriptionFlagName),
-				Finalize:    c.Bool(patchFinalizeFlagName),
-				ShowSummary: c.Bool(patchVerboseFlagName),
-				Large:       c.Bool(largeFlagName),
+				Project:      c.String(projectFlagName),
+				Variants:     c.StringSlice(variantsFlagName),
+				Tasks:        c.StringSlice(tasksFlagName),
+				SkipConfirm:  c.Bool(yesFlagName),
+				Description:  c.String(patchDescriptionFlagName),
+				Finalize:     c.Bool(patchFinalizeFlagName),
+				ShowSummary:  c.Bool(patchVerboseFlagName),
+				Large:        c.Bool(largeFlagName),
+				Reuse:        c.Bool(patchReuseFlagName),
+				ReuseModules: c.Bool(patchReuseModulesFlagName),
+				Parameters:   c.StringSlice(patchParamFlagName),
 			}
 			diffPath := c.String(diffPathFlagName)
 			base := c.String(baseFlagName)
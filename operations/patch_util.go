@@ -13,6 +13,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest/client"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
@@ -57,17 +58,26 @@ type patchParams struct {
 	Browse      bool
 	Large       bool
 	ShowSummary bool
+	// Reuse runs the variants and tasks of the user's previous patch of
+	// the project, and ReuseModules also patches its modules.
+	Reuse        bool
+	ReuseModules bool
+	// Parameters set the project's parameters for the patch, as key=value.
+	Parameters []string
 }
 
 type patchSubmission struct {
-	projectId   string
-	patchData   string
-	description string
-	base        string
-	alias       string
-	variants    string
-	tasks       []string
-	finalize    bool
+	projectId    string
+	patchData    string
+	description  string
+	base         string
+	alias        string
+	variants     string
+	tasks        []string
+	finalize     bool
+	reuse        bool
+	reuseModules bool
+	parameters   []version.Parameter
 }
 
 func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffData *localDiff) error {
@@ -89,16 +99,24 @@ func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffDa
 		}
 	}
 
+	params, err := parsePatchParameters(p.Parameters)
+	if err != nil {
+		return err
+	}
+
 	variantsStr := strings.Join(p.Variants, ",")
 	patchSub := patchSubmission{
-		projectId:   p.Project,
-		patchData:   diffData.fullPatch,
-		description: p.Description,
-		base:        diffData.base,
-		variants:    variantsStr,
-		tasks:       p.Tasks,
-		finalize:    p.Finalize,
-		alias:       p.Alias,
+		projectId:    p.Project,
+		patchData:    diffData.fullPatch,
+		description:  p.Description,
+		base:         diffData.base,
+		variants:     variantsStr,
+		tasks:        p.Tasks,
+		finalize:     p.Finalize,
+		alias:        p.Alias,
+		reuse:        p.Reuse,
+		reuseModules: p.ReuseModules,
+		parameters:   params,
 	}
 
 	newPatch, err := ac.PutPatch(patchSub)
@@ -135,6 +153,19 @@ func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffDa
 	return nil
 }
 
+// parsePatchParameters parses parameters of the form key=value.
+func parsePatchParameters(values []string) ([]version.Parameter, error) {
+	params := []version.Parameter{}
+	for _, value := range values {
+		pair := strings.SplitN(value, "=", 2)
+		if len(pair) != 2 || pair[0] == "" {
+			return nil, errors.Errorf("invalid parameter '%s', must be of the form key=value", value)
+		}
+		params = append(params, version.Parameter{Key: pair[0], Value: pair[1]})
+	}
+	return params, nil
+}
+
 func findBrowserCommand() ([]string, error) {
 	browser := os.Getenv("BROWSER")
 	if browser != "" {
@@ -179,7 +210,17 @@ func (p *patchParams) validatePatchCommand(ctx context.Context, conf *ClientSett
 		return
 	}
 
-	if err = p.loadAlias(conf); err != nil {
+	if p.ReuseModules && !p.Reuse {
+		err = errors.New("can't reuse the modules of the previous patch without reusing its tasks")
+		return
+	}
+	if p.Reuse {
+		// the previous patch's variants and tasks replace any others
+		if len(p.Variants) > 0 || len(p.Tasks) > 0 || p.Alias != "" {
+			err = errors.New("can't specify variants, tasks or an alias when reusing the previous patch")
+			return
+		}
+	} else if err = p.loadAlias(conf); err != nil {
 		grip.Warningf("warning - failed to set default alias: %v\n", err)
 	}
 
@@ -212,13 +253,29 @@ func (p *patchParams) validatePatchCommand(ctx context.Context, conf *ClientSett
 		return
 	}
 
+	if !p.Reuse {
+		if err = p.loadDefaultVariantsAndTasks(conf); err != nil {
+			return
+		}
+	}
+
+	if p.Description == "" && !p.SkipConfirm {
+		p.Description = prompt("Enter a description for this patch (optional):")
+	}
+
+	return
+}
+
+// loadDefaultVariantsAndTasks fills in the variants and tasks that weren't
+// given with the defaults for the project, offering to save the ones given as
+// defaults if there are none.
+func (p *patchParams) loadDefaultVariantsAndTasks(conf *ClientSettings) error {
 	// update variants
 	if len(p.Variants) == 0 && p.Alias == "" {
 		p.Variants = conf.FindDefaultVariants(p.Project)
 		if len(p.Variants) == 0 && p.Finalize {
-			err = errors.Errorf("Need to specify at least one buildvariant with -v when finalizing." +
+			return errors.Errorf("Need to specify at least one buildvariant with -v when finalizing." +
 				" Run with `-v all` to finalize against all variants.")
-			return
 		}
 	} else if p.Alias == "" {
 		defaultVariants := conf.FindDefaultVariants(p.Project)
@@ -226,7 +283,7 @@ func (p *patchParams) validatePatchCommand(ctx context.Context, conf *ClientSett
 			confirm(fmt.Sprintf("Set %v as the default variants for project '%v'?",
 				p.Variants, p.Project), false) {
 			conf.SetDefaultVariants(p.Project, p.Variants...)
-			if err = conf.Write(""); err != nil {
+			if err := conf.Write(""); err != nil {
 				grip.Warningf("warning - failed to set default variants: %v\n", err)
 			}
 		}
@@ -236,9 +293,8 @@ func (p *patchParams) validatePatchCommand(ctx context.Context, conf *ClientSett
 	if len(p.Tasks) == 0 {
 		p.Tasks = conf.FindDefaultTasks(p.Project)
 		if len(p.Tasks) == 0 && p.Alias == "" && p.Finalize {
-			err = errors.Errorf("Need to specify at least one task or alias when finalizing." +
+			return errors.Errorf("Need to specify at least one task or alias when finalizing." +
 				" Run with `-t all` to finalize against all tasks.")
-			return
 		}
 	} else if p.Alias == "" {
 		defaultTasks := conf.FindDefaultTasks(p.Project)
@@ -252,11 +308,7 @@ func (p *patchParams) validatePatchCommand(ctx context.Context, conf *ClientSett
 		}
 	}
 
-	if p.Description == "" && !p.SkipConfirm {
-		p.Description = prompt("Enter a description for this patch (optional):")
-	}
-
-	return
+	return nil
 }
 
 // Sets the patch's alias to either the passed in option or the default
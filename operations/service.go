@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
@@ -77,6 +78,8 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), time.Minute, time.Now(), opts, amboy.GroupQueueOperationFactory(
 		units.PopulateHostCreationJobs(env, 0),
 		units.PopulateIdleHostJobs(env),
+		units.PopulateHostProblemDetectionJobs(env),
+		units.PopulateStaticHostHealthCheckJobs(env),
 		units.PopulateHostTerminationJobs(env),
 		units.PopulateHostMonitoring(env),
 		units.PopulateTaskMonitoring(),
@@ -85,8 +88,11 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 		units.PopulateLastContainerFinishTimeJobs(),
 		units.PopulateParentDecommissionJobs(),
 		units.PopulatePeriodicNotificationJobs(1),
+		units.PopulateCommitQueueJobs(env),
 		units.PopulateContainerStateJobs(env),
-		units.PopulateOldestImageRemovalJobs()))
+		units.PopulateContainerImagePrewarmJobs(env),
+		units.PopulateOldestImageRemovalJobs(),
+		units.PopulateSystemPruneJobs()))
 
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), 15*time.Second, time.Now(), opts, amboy.GroupQueueOperationFactory(
 		units.PopulateHostSetupJobs(env, 0),
@@ -99,7 +105,13 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 
 	amboy.IntervalQueueOperation(ctx, env.RemoteQueue(), 15*time.Minute, time.Now(), opts, amboy.GroupQueueOperationFactory(
 		units.PopulateCatchupJobs(30),
-		units.PopulateHostAlertJobs(20)))
+		units.PopulateHostAlertJobs(20),
+		units.PopulatePatchExpirationJobs(),
+		units.PopulateArtifactExpirationJobs(env),
+		units.PopulateDataLifecycleJobs(env),
+		units.PopulateTestFlakinessJobs(),
+		units.PopulateProjectInsightsJobs(),
+		units.PopulateParentImageBakeJobs(env)))
 
 	////////////////////////////////////////////////////////////////////////
 	//
@@ -148,4 +160,39 @@ func startSystemCronJobs(ctx context.Context, env evergreen.Environment) {
 		return queue.Put(units.NewLocalAmboyStatsCollector(env, fmt.Sprintf("amboy-local-stats-%d", time.Now().Unix())))
 	})
 
+	// pick up settings changed through another app server, including
+	// rotated sender credentials, once their change events are logged
+	var lastSettingsChange time.Time
+	if events, err := event.Find(event.AllLogCollection, event.RecentAdminEvents(1)); err == nil && len(events) > 0 {
+		lastSettingsChange = events[0].Timestamp
+	}
+	amboy.IntervalQueueOperation(ctx, env.LocalQueue(), time.Minute, time.Now(), opts, func(queue amboy.Queue) error {
+		events, err := event.Find(event.AllLogCollection, event.RecentAdminEvents(1))
+		if err != nil {
+			grip.Alert(message.WrapError(err, message.Fields{
+				"message":   "problem fetching settings change events",
+				"operation": "settings reload",
+			}))
+			return err
+		}
+		if len(events) == 0 || !events[0].Timestamp.After(lastSettingsChange) {
+			return nil
+		}
+
+		settings, err := evergreen.GetConfig()
+		if err != nil {
+			grip.Alert(message.WrapError(err, message.Fields{
+				"message":   "problem fetching settings",
+				"operation": "settings reload",
+			}))
+			return err
+		}
+		if err = env.ReloadSettings(settings); err != nil {
+			return err
+		}
+		lastSettingsChange = events[0].Timestamp
+
+		return nil
+	})
+
 }
@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+// registerServiceMetrics registers the metrics that are collected from the
+// environment's job queues and from the hosts when they're scraped.
+func registerServiceMetrics(env evergreen.Environment) {
+	util.NewPrometheusGaugeFunc("evergreen_queue_jobs", "Jobs in the background job queues, by queue and state.", func() ([]util.PrometheusSample, error) {
+		samples := []util.PrometheusSample{}
+		for name, q := range map[string]amboy.Queue{"local": env.LocalQueue(), "remote": env.RemoteQueue()} {
+			if q == nil {
+				continue
+			}
+			stats := q.Stats()
+			for state, count := range map[string]int{
+				"pending":   stats.Pending,
+				"running":   stats.Running,
+				"blocked":   stats.Blocked,
+				"completed": stats.Completed,
+			} {
+				samples = append(samples, util.PrometheusSample{LabelValues: []string{name, state}, Value: float64(count)})
+			}
+		}
+		return samples, nil
+	}, "queue", "state")
+
+	util.NewPrometheusGaugeFunc("evergreen_hosts", "Hosts that haven't been terminated, by status and provider.", func() ([]util.PrometheusSample, error) {
+		counts, err := host.CountHostsByStatus()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		samples := make([]util.PrometheusSample, 0, len(counts))
+		for _, c := range counts {
+			samples = append(samples, util.PrometheusSample{LabelValues: []string{c.Status, c.Provider}, Value: float64(c.Count)})
+		}
+		return samples, nil
+	}, "status", "provider")
+}
@@ -209,10 +209,15 @@ func getAdminService(env evergreen.Environment, settings *evergreen.Settings) (h
 	remoteReporting := rest.NewReportingService(remoteReporter).App()
 	remoteReporting.SetPrefix("/amboy/remote/reporting")
 
+	registerServiceMetrics(env)
+	metrics := gimlet.NewApp()
+	metrics.NoVersions = true
+	metrics.AddRoute("/metrics").Get().Handler(util.PrometheusMetrics.ServeHTTP)
+
 	app := gimlet.NewApp()
 	app.AddMiddleware(gimlet.MakeRecoveryLogger())
 
-	handler, err := gimlet.MergeApplications(app, localAbort, remoteAbort, localReporting, remoteReporting, util.GetPprofApp())
+	handler, err := gimlet.MergeApplications(app, localAbort, remoteAbort, localReporting, remoteReporting, metrics, util.GetPprofApp())
 	if err != nil {
 		return nil, errors.Wrap(err, "problem assembling handler")
 	}
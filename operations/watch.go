@@ -0,0 +1,147 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+// watchRetryDelay is how long watching waits to reconnect after the event
+// stream fails.
+const watchRetryDelay = 5 * time.Second
+
+func Watch() cli.Command {
+	return cli.Command{
+		Name:      "watch",
+		Usage:     "follow a version or finalized patch until it finishes, exiting non-zero if it fails",
+		ArgsUsage: "<version or patch id>",
+		Before:    setPlainLogger,
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			versionID := c.Args().Get(0)
+			if versionID == "" {
+				return errors.New("must specify a version or patch to watch")
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSettings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			comm := conf.GetRestCommunicator(ctx)
+			defer comm.Close()
+
+			return watchVersion(ctx, comm, versionID, os.Stdout)
+		},
+	}
+}
+
+// watchVersion writes the state transitions of the version and its tasks to
+// out as they happen until the version finishes, then summarizes its failed
+// tasks. A patch's version has the same ID as the patch. It returns an error
+// if the version failed.
+func watchVersion(ctx context.Context, comm client.Communicator, versionID string, out io.Writer) error {
+	v, err := comm.GetAPIVersion(ctx, versionID)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding version '%s', which must be finalized if it's a patch", versionID)
+	}
+	status := model.FromAPIString(v.Status)
+	fmt.Fprintf(out, "watching version %s of %s: %s\n", versionID, model.FromAPIString(v.Project), status)
+
+	cursor := ""
+	for !isVersionFinished(status) {
+		cursor, err = comm.StreamStatusEvents(ctx, versionID, cursor, func(e model.APIStatusEvent) bool {
+			writeStatusEvent(out, e)
+			if model.FromAPIString(e.ResourceType) == event.ResourceTypeVersion {
+				status = model.FromAPIString(e.Status)
+			}
+			return !isVersionFinished(status)
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return errors.WithStack(ctx.Err())
+			}
+			grip.Warning(errors.Wrap(err, "problem streaming events, reconnecting"))
+			select {
+			case <-ctx.Done():
+				return errors.WithStack(ctx.Err())
+			case <-time.After(watchRetryDelay):
+			}
+		}
+		if isVersionFinished(status) {
+			break
+		}
+
+		// the version may have finished while the stream was closed
+		v, err = comm.GetAPIVersion(ctx, versionID)
+		if err != nil {
+			grip.Warning(errors.Wrapf(err, "problem checking status of version '%s'", versionID))
+			continue
+		}
+		status = model.FromAPIString(v.Status)
+	}
+
+	builds, err := comm.GetVersionBuilds(ctx, versionID)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding the tasks of version '%s'", versionID)
+	}
+	writeFailedTasks(out, builds)
+
+	fmt.Fprintf(out, "version %s finished: %s\n", versionID, status)
+	if status != evergreen.VersionSucceeded {
+		return errors.Errorf("version '%s' %s", versionID, status)
+	}
+	return nil
+}
+
+func isVersionFinished(status string) bool {
+	return status == evergreen.VersionSucceeded || status == evergreen.VersionFailed
+}
+
+func writeStatusEvent(out io.Writer, e model.APIStatusEvent) {
+	timestamp := time.Time(e.Timestamp).Local().Format("15:04:05")
+	status := model.FromAPIString(e.Status)
+
+	switch model.FromAPIString(e.ResourceType) {
+	case event.ResourceTypeVersion:
+		fmt.Fprintf(out, "[%s] version: %s\n", timestamp, status)
+	case event.ResourceTypeTask:
+		name := model.FromAPIString(e.DisplayName)
+		if name == "" {
+			name = model.FromAPIString(e.ResourceID)
+		}
+		if variant := model.FromAPIString(e.BuildVariant); variant != "" {
+			name = fmt.Sprintf("%s / %s", variant, name)
+		}
+		fmt.Fprintf(out, "[%s] task %s: %s\n", timestamp, name, status)
+	}
+}
+
+func writeFailedTasks(out io.Writer, builds []model.APIBuild) {
+	for _, b := range builds {
+		for _, t := range b.TaskCache {
+			if !evergreen.IsFailedTaskStatus(t.Status) {
+				continue
+			}
+			fmt.Fprintf(out, "failed: %s / %s (%s)\n", model.FromAPIString(b.BuildVariant), t.DisplayName, t.Id)
+			if t.StatusDetails.Description != "" {
+				fmt.Fprintf(out, "    %s\n", t.StatusDetails.Description)
+			}
+			for _, test := range t.FailedTestNames {
+				fmt.Fprintf(out, "    failed test: %s\n", test)
+			}
+		}
+	}
+}
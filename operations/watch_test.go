@@ -0,0 +1,71 @@
+package operations
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchVersion(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	statusEvent := func(id, resourceType, resourceID, status string) model.APIStatusEvent {
+		return model.APIStatusEvent{
+			ID:           model.ToAPIString(id),
+			ResourceType: model.ToAPIString(resourceType),
+			ResourceID:   model.ToAPIString(resourceID),
+			Status:       model.ToAPIString(status),
+			Version:      model.ToAPIString("v1"),
+			BuildVariant: model.ToAPIString("bv1"),
+			DisplayName:  model.ToAPIString(resourceID),
+		}
+	}
+
+	comm := client.NewMock("url")
+	comm.Versions = map[string]*model.APIVersion{
+		"v1": {Status: model.ToAPIString(evergreen.VersionStarted), Project: model.ToAPIString("proj")},
+		"v2": {Status: model.ToAPIString(evergreen.VersionSucceeded), Project: model.ToAPIString("proj")},
+	}
+	comm.VersionBuilds = map[string][]model.APIBuild{
+		"v1": {{
+			BuildVariant: model.ToAPIString("bv1"),
+			TaskCache: []model.APITaskCache{
+				{Id: "t1", DisplayName: "compile", Status: evergreen.TaskSucceeded},
+				{Id: "t2", DisplayName: "test", Status: evergreen.TaskFailed, FailedTestNames: []string{"TestFoo"}},
+			},
+		}},
+	}
+	comm.StatusEvents = []model.APIStatusEvent{
+		statusEvent("e1", event.ResourceTypeTask, "compile", evergreen.TaskSucceeded),
+		statusEvent("e2", event.ResourceTypeTask, "test", evergreen.TaskFailed),
+		statusEvent("e3", event.ResourceTypeVersion, "v1", evergreen.VersionFailed),
+		statusEvent("e4", event.ResourceTypeTask, "later", evergreen.TaskStarted),
+	}
+
+	out := &bytes.Buffer{}
+	err := watchVersion(ctx, comm, "v1", out)
+	require.Error(err)
+	assert.Contains(err.Error(), "failed")
+	assert.Contains(out.String(), "task bv1 / test: failed")
+	assert.Contains(out.String(), "version: failed")
+	assert.NotContains(out.String(), "later")
+	assert.Contains(out.String(), "failed: bv1 / test (t2)")
+	assert.Contains(out.String(), "failed test: TestFoo")
+	assert.NotContains(out.String(), "(t1)")
+
+	out.Reset()
+	assert.NoError(watchVersion(ctx, comm, "v2", out))
+	assert.Contains(out.String(), "version v2 finished: success")
+
+	assert.Error(watchVersion(ctx, comm, "v3", out))
+}
@@ -38,6 +38,7 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
 
       $scope.tempPlugins = resp.data.plugins ? jsyaml.safeDump(resp.data.plugins) : ""
       $scope.tempContainerPools = resp.data.container_pools.pools ? jsyaml.safeDump(resp.data.container_pools.pools) : ""
+      $scope.tempAgentRollouts = resp.data.agent_update.distros && resp.data.agent_update.distros.length ? jsyaml.safeDump(resp.data.agent_update.distros) : ""
 
       $scope.Settings = resp.data;
       $scope.Settings.jira_notifications = $scope.Settings.jira_notifications;
@@ -119,6 +120,14 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
 
     $scope.Settings.container_pools.pools = parsedContainerPools;
 
+    try {
+      var parsedAgentRollouts = jsyaml.safeLoad($scope.tempAgentRollouts);
+    } catch(e) {
+      notificationService.pushNotification("Error parsing agent distro rollouts yaml: " + e, "errorHeader");
+      return;
+    }
+    $scope.Settings.agent_update.distros = parsedAgentRollouts || [];
+
     if ($scope.tempPlugins === null || $scope.tempPlugins === undefined || $scope.tempPlugins == "") {
       $scope.Settings.plugins = {};
     }
@@ -146,12 +155,15 @@ mciModule.controller('AdminSettingsController', ['$scope', '$window', 'mciAdminR
     cli_updates_disabled: "cli_updates",
     background_stats_disabled: "background stats",
     "task_logging_disabled": "task logging",
+    api_writes_disabled: "api_writes",
     event_processing_disabled: "event_processing",
     jira_notifications_disabled: "jira_notifications",
     slack_notifications_disabled: "slack_notifications",
     email_notifications_disabled: "email_notifications",
     webhook_notifications_disabled: "webhook_notifications",
-    github_status_api_disabled: "github_status_api"
+    opsgenie_notifications_disabled: "opsgenie_notifications",
+    github_status_api_disabled: "github_status_api",
+    notification_dispatch_disabled: "notification_dispatch"
   }
 
   timestamp = function(ts) {
@@ -34,6 +34,9 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, $anchor
   }, {
     'id': 'vsphere',
     'display': 'VMware vSphere'
+  }, {
+    'id': 'azure',
+    'display': 'Microsoft Azure'
   }];
 
   $scope.architectures = [{
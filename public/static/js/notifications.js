@@ -73,6 +73,8 @@ mciModule.controller('NotificationsController', function($scope, $window, mciUse
       return "emailing " + input.target;
     case "slack":
       return "sending a Slack message to " + input.target;
+    case "opsgenie":
+      return "creating an Opsgenie alert" + (input.target.team ? " for team " + input.target.team : "");
     }
     return input;
   };
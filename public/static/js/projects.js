@@ -39,6 +39,16 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
       resource_type: "VERSION",
       label: "any version fails",
     },
+    {
+      trigger: "status-transition",
+      resource_type: "VERSION",
+      label: "the latest version changes from passing to failing or back",
+    },
+    {
+      trigger: "config-warnings",
+      resource_type: "VERSION",
+      label: "any version introduces project configuration warnings",
+    },
     {
       trigger: "outcome",
       resource_type: "BUILD",
@@ -97,7 +107,8 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
       regex_selectors: taskRegexSelectors(),
       extraFields: [
         {text: "Test names matching regex", key: "test-regex", validator: null},
-        {text: "Re-notify after how many hours (default 48)", key: "renotify-interval", validator: validateDuration}
+        {text: "Re-notify after how many hours (default 48)", key: "renotify-interval", validator: validateDuration},
+        {text: "Ignore known flaky tests (true or false)", key: "ignore-flaky-tests", validator: validateBool}
       ]
     },
     {
@@ -117,6 +128,25 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
       extraFields: [
         {text: "Percent change", key: "task-percent-change", validator: validatePercentage}
       ]
+    },
+    {
+      trigger: "runtime-regression",
+      resource_type: "TASK",
+      label: "the runtime for a successful task exceeds its recent mainline average by some percentage",
+      regex_selectors: taskRegexSelectors(),
+      extraFields: [
+        {text: "Percent increase", key: "task-percent-change", validator: validatePercentage},
+        {text: "Mainline versions to average", key: "task-baseline-versions", validator: validateDuration}
+      ]
+    },
+    {
+      trigger: "streak-broken",
+      resource_type: "TASK",
+      label: "a task fails after passing some number of consecutive mainline runs",
+      regex_selectors: taskRegexSelectors(),
+      extraFields: [
+        {text: "Consecutive passes", key: "task-streak-length", validator: validateDuration}
+      ]
     }
   ];
 
@@ -246,6 +276,7 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
         }
         $scope.projectVars = data.ProjectVars.vars || {};
         $scope.privateVars = data.ProjectVars.private_vars || {};
+        $scope.secretVars = data.ProjectVars.secret_vars || {};
         $scope.githubHookID = data.github_hook.hook_id || 0;
         $scope.prTestingConflicts = data.pr_testing_conflicting_refs || [];
         $scope.prTestingEnabled = data.ProjectRef.pr_testing_enabled || false;
@@ -263,10 +294,15 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
           identifier : $scope.projectRef.identifier,
           project_vars: $scope.projectVars,
           private_vars: $scope.privateVars,
+          secret_vars: $scope.secretVars,
           display_name : $scope.projectRef.display_name,
           remote_path:$scope.projectRef.remote_path,
           batch_time: parseInt($scope.projectRef.batch_time),
           deactivate_previous: $scope.projectRef.deactivate_previous,
+          suppress_inherited_warnings: $scope.projectRef.suppress_inherited_warnings,
+          stepback_bisect: $scope.projectRef.stepback_bisect || false,
+          test_selection_enabled: $scope.projectRef.test_selection_enabled || false,
+          full_run_interval_hours: $scope.projectRef.full_run_interval_hours || "",
           relative_url: $scope.projectRef.relative_url,
           branch_name: $scope.projectRef.branch_name || "master",
           owner_name: $scope.projectRef.owner_name,
@@ -274,14 +310,23 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
           enabled: $scope.projectRef.enabled,
           private: $scope.projectRef.private,
           patching_disabled: $scope.projectRef.patching_disabled,
+          patch_expiration_days: $scope.projectRef.patch_expiration_days || "",
           alert_config: $scope.projectRef.alert_config || {},
           repotracker_error: $scope.projectRef.repotracker_error || {},
           admins : $scope.projectRef.admins || [],
+          contributors : $scope.projectRef.contributors || [],
+          viewers : $scope.projectRef.viewers || [],
           setup_github_hook: $scope.githubHookID != 0,
           tracks_push_events: data.ProjectRef.tracks_push_events || false,
           pr_testing_enabled: data.ProjectRef.pr_testing_enabled || false,
+          github_checks_enabled: data.ProjectRef.github_checks_enabled || false,
+          commit_queue: data.ProjectRef.commit_queue || {enabled: false, merge_method: ""},
           notify_on_failure: $scope.projectRef.notify_on_failure,
+          build_break_team_channel: $scope.projectRef.build_break_team_channel || "",
+          build_break_escalation_mins: $scope.projectRef.build_break_escalation_mins || "",
           force_repotracker_run: false,
+          enable_repotracker: [],
+          pause_activation: {hours: 0, reason: ""},
           delete_aliases: [],
           delete_subscriptions: [],
         };
@@ -335,6 +380,9 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
 
   $scope.saveProject = function() {
     $scope.settingsFormData.batch_time = parseInt($scope.settingsFormData.batch_time);
+    $scope.settingsFormData.build_break_escalation_mins = parseInt($scope.settingsFormData.build_break_escalation_mins) || 0;
+    $scope.settingsFormData.patch_expiration_days = parseInt($scope.settingsFormData.patch_expiration_days) || 0;
+    $scope.settingsFormData.full_run_interval_hours = parseInt($scope.settingsFormData.full_run_interval_hours) || 0;
     if ($scope.proj_var) {
       $scope.addProjectVar();
     }
@@ -393,6 +441,18 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     }
   };
 
+  $scope.addSecretVar = function() {
+    if ($scope.secret_var.name && $scope.secret_var.backend && $scope.secret_var.secret) {
+      $scope.settingsFormData.secret_vars[$scope.secret_var.name] = {
+        backend: $scope.secret_var.backend,
+        name: $scope.secret_var.secret,
+        key: $scope.secret_var.key || "",
+      };
+      delete $scope.secret_var;
+      $scope.isDirty = true;
+    }
+  };
+
   $scope.addGithubAlias = function() {
     if ($scope.github_alias.variant && $scope.github_alias.task) {
       item = Object.assign({}, $scope.github_alias)
@@ -417,6 +477,11 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     $scope.isDirty = true;
   };
 
+  $scope.removeSecretVar = function(name) {
+    delete $scope.settingsFormData.secret_vars[name];
+    $scope.isDirty = true;
+  };
+
   $scope.removeGithubAlias = function(i) {
     if ($scope.github_aliases[i]["_id"]) {
       $scope.settingsFormData.delete_aliases = $scope.settingsFormData.delete_aliases.concat([$scope.github_aliases[i]["_id"]])
@@ -462,8 +527,43 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
     return revision && revision.length >= 40;
   }
 
+  // repotrackerDisabledStates returns the repotracker capabilities that are
+  // disabled and have not expired
+  $scope.repotrackerDisabledStates = function() {
+    var details = $scope.settingsFormData && $scope.settingsFormData.repotracker_error;
+    if (!details || !details.disabled) {
+      return [];
+    }
+    var now = new Date();
+    return _.filter(details.disabled, function(state) {
+      var expires = new Date(state.expires);
+      return !(expires.getFullYear() > 1 && expires <= now);
+    });
+  }
+
+  $scope.repotrackerDisabled = function(capability) {
+    return _.some($scope.repotrackerDisabledStates(), function(state) {
+      return state.capability === capability;
+    });
+  }
+
+  $scope.hasRepotrackerExpiry = function(state) {
+    return new Date(state.expires).getFullYear() > 1;
+  }
+
+  $scope.toggleEnableRepotracker = function(capability) {
+    var enable = $scope.settingsFormData.enable_repotracker;
+    var i = enable.indexOf(capability);
+    if (i === -1) {
+      enable.push(capability);
+    } else {
+      enable.splice(i, 1);
+    }
+    $scope.isDirty = true;
+  }
+
   $scope.setLastRevision = function() {
-    if ($scope.settingsFormData.repotracker_error.exists) {
+    if ($scope.repotrackerDisabled("revisions")) {
       var revisionUrl = '/project/' + $scope.settingsFormData.identifier + "/repo_revision";
       if (!$scope.isValidMergeBaseRevision($scope.settingsFormData.repotracker_error.merge_base_revision)){
         console.log("bad revision");
@@ -471,7 +571,9 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location,
       }
       $http.put(revisionUrl, $scope.settingsFormData.repotracker_error.merge_base_revision).then(
         function(data) {
-          $scope.settingsFormData.repotracker_error.exists = false;
+          $scope.settingsFormData.repotracker_error.disabled = _.reject($scope.settingsFormData.repotracker_error.disabled, function(state) {
+            return state.capability === "revisions";
+          });
         },
         function(resp){
           console.log(resp.status);
@@ -354,6 +354,13 @@ function validatePercentage(percent) {
   return "";
 }
 
+function validateBool(value) {
+  if (value !== "true" && value !== "false") {
+    return value + " must be true or false";
+  }
+  return "";
+}
+
 function buildRegexSelectors() {
   return [
     {
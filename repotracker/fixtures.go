@@ -0,0 +1,141 @@
+package repotracker
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// FixtureRevision is a synthetic revision loaded into a project in place of
+// a commit polled from its repository.
+type FixtureRevision struct {
+	model.Revision
+
+	// Config is the YAML project configuration at the revision. If it is
+	// blank, the default configuration of the fixture is used.
+	Config string
+}
+
+// fixturePoller is a RepoPoller that serves synthetic revisions and project
+// configurations instead of polling a repository, so that ingestion can be
+// exercised end-to-end without touching real repositories.
+type fixturePoller struct {
+	identifier    string
+	defaultConfig string
+	configs       map[string]string
+	// revisions are ordered from newest to oldest
+	revisions []model.Revision
+}
+
+func newFixturePoller(identifier, defaultConfig string, revisions []FixtureRevision) (*fixturePoller, error) {
+	p := &fixturePoller{
+		identifier:    identifier,
+		defaultConfig: defaultConfig,
+		configs:       map[string]string{},
+	}
+
+	catcher := grip.NewSimpleCatcher()
+	for _, r := range revisions {
+		if r.Revision.Revision == "" {
+			catcher.Add(errors.New("fixture revision must not be blank"))
+			continue
+		}
+		if _, ok := p.configs[r.Revision.Revision]; ok {
+			catcher.Add(errors.Errorf("duplicate fixture revision '%s'", r.Revision.Revision))
+			continue
+		}
+		config := r.Config
+		if config == "" {
+			config = defaultConfig
+		}
+		if config == "" {
+			catcher.Add(errors.Errorf("fixture revision '%s' has no project configuration", r.Revision.Revision))
+			continue
+		}
+		// parse the configuration up front so that malformed fixtures are
+		// rejected before any revisions are stored
+		if _, err := p.parseConfig(config); err != nil {
+			catcher.Add(errors.Wrapf(err, "invalid project configuration for fixture revision '%s'", r.Revision.Revision))
+			continue
+		}
+		p.configs[r.Revision.Revision] = config
+		p.revisions = append(p.revisions, r.Revision)
+	}
+
+	return p, catcher.Resolve()
+}
+
+func (p *fixturePoller) parseConfig(config string) (*model.Project, error) {
+	project := &model.Project{}
+	if err := model.LoadProjectInto([]byte(config), p.identifier, project); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return project, nil
+}
+
+func (p *fixturePoller) GetRemoteConfig(_ context.Context, revision string) (*model.Project, error) {
+	config, ok := p.configs[revision]
+	if !ok {
+		return nil, errors.Errorf("no fixture for revision '%s'", revision)
+	}
+	return p.parseConfig(config)
+}
+
+func (p *fixturePoller) GetChangedFiles(_ context.Context, revision string) ([]string, error) {
+	return nil, nil
+}
+
+// GetRevisionsSince returns the fixture revisions newer than the given
+// revision, or all of them if it is not one of the fixture revisions.
+func (p *fixturePoller) GetRevisionsSince(revision string, maxRevisionsToSearch int) ([]model.Revision, error) {
+	for i, r := range p.revisions {
+		if r.Revision == revision {
+			return p.revisions[:i], nil
+		}
+	}
+	return p.revisions, nil
+}
+
+func (p *fixturePoller) GetRecentRevisions(maxRevisionsToSearch int) ([]model.Revision, error) {
+	if maxRevisionsToSearch > 0 && maxRevisionsToSearch < len(p.revisions) {
+		return p.revisions[:maxRevisionsToSearch], nil
+	}
+	return p.revisions, nil
+}
+
+// LoadFixtures runs the repotracker for the project against the given
+// synthetic revisions, ordered from newest to oldest, instead of its
+// repository. Revisions without a configuration use defaultConfig. Versions
+// are created, activated, and logged exactly as for polled revisions.
+func LoadFixtures(ctx context.Context, conf *evergreen.Settings, project *model.ProjectRef, defaultConfig string, revisions []FixtureRevision) error {
+	if !project.Enabled {
+		return errors.Errorf("project disabled: %s", project.Identifier)
+	}
+	if len(revisions) == 0 {
+		return errors.New("no fixture revisions to load")
+	}
+
+	poller, err := newFixturePoller(project.Identifier, defaultConfig, revisions)
+	if err != nil {
+		return errors.Wrap(err, "invalid fixture")
+	}
+
+	tracker := &RepoTracker{
+		Settings:   conf,
+		ProjectRef: project,
+		RepoPoller: poller,
+	}
+
+	grip.Info(message.Fields{
+		"runner":    RunnerName,
+		"message":   "loading test fixture revisions",
+		"project":   project.Identifier,
+		"revisions": len(revisions),
+	})
+
+	return errors.Wrap(tracker.FetchRevisions(ctx), "repotracker encountered error")
+}
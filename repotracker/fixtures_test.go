@@ -0,0 +1,84 @@
+package repotracker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixtureConfig = `
+tasks:
+- name: compile
+buildvariants:
+- name: ubuntu
+  run_on:
+  - ubuntu1604-test
+  tasks:
+  - name: compile
+`
+
+func TestFixturePoller(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	revisions := []FixtureRevision{
+		{Revision: model.Revision{Revision: "c"}},
+		{Revision: model.Revision{Revision: "b"}, Config: fixtureConfig + "\n- name: windows\n  run_on:\n  - windows-test\n  tasks:\n  - name: compile\n"},
+		{Revision: model.Revision{Revision: "a"}},
+	}
+	poller, err := newFixturePoller("project", fixtureConfig, revisions)
+	require.NoError(err)
+
+	recent, err := poller.GetRecentRevisions(2)
+	assert.NoError(err)
+	require.Len(recent, 2)
+	assert.Equal("c", recent[0].Revision)
+
+	since, err := poller.GetRevisionsSince("b", 0)
+	assert.NoError(err)
+	require.Len(since, 1)
+	assert.Equal("c", since[0].Revision)
+
+	since, err = poller.GetRevisionsSince("unknown", 0)
+	assert.NoError(err)
+	assert.Len(since, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	project, err := poller.GetRemoteConfig(ctx, "a")
+	assert.NoError(err)
+	require.NotNil(project)
+	assert.Equal("project", project.Identifier)
+	assert.Len(project.BuildVariants, 1)
+
+	project, err = poller.GetRemoteConfig(ctx, "b")
+	assert.NoError(err)
+	require.NotNil(project)
+	assert.Len(project.BuildVariants, 2)
+
+	_, err = poller.GetRemoteConfig(ctx, "d")
+	assert.Error(err)
+}
+
+func TestFixturePollerRejectsInvalidFixtures(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := newFixturePoller("project", "", []FixtureRevision{{Revision: model.Revision{Revision: "a"}}})
+	assert.Error(err)
+
+	_, err = newFixturePoller("project", fixtureConfig, []FixtureRevision{{}})
+	assert.Error(err)
+
+	_, err = newFixturePoller("project", fixtureConfig, []FixtureRevision{
+		{Revision: model.Revision{Revision: "a"}},
+		{Revision: model.Revision{Revision: "a"}},
+	})
+	assert.Error(err)
+
+	_, err = newFixturePoller("project", "tasks: {", []FixtureRevision{{Revision: model.Revision{Revision: "a"}}})
+	assert.Error(err)
+}
@@ -167,7 +167,6 @@ func (gRepoPoller *GithubRepositoryPoller) GetRevisionsSince(revision string, ma
 	}
 
 	if !foundLatest {
-		var revisionDetails *model.RepositoryErrorDetails
 		var revisionError error
 		var err error
 		var baseRevision string
@@ -191,26 +190,20 @@ func (gRepoPoller *GithubRepositoryPoller) GetRevisionsSince(revision string, ma
 		}
 		if err != nil {
 			// unable to get merge base commit so set projectRef revision details with a blank base revision
-			revisionDetails = &model.RepositoryErrorDetails{
-				Exists:            true,
-				InvalidRevision:   revision[:10],
-				MergeBaseRevision: "",
-			}
+			baseRevision = ""
 			revisionError = errors.Wrapf(err,
 				"unable to find a suggested merge base commit for revision %v, must fix on projects settings page",
 				revision)
 		} else {
-			// update project ref to have an inconsistent status
-			revisionDetails = &model.RepositoryErrorDetails{
-				Exists:            true,
-				InvalidRevision:   revision[:10],
-				MergeBaseRevision: baseRevision,
-			}
 			revisionError = errors.Errorf("base revision, %v not found, suggested base revision, %v found, must confirm on project settings page",
 				revision, baseRevision)
 		}
 
-		gRepoPoller.ProjectRef.RepotrackerError = revisionDetails
+		// update project ref to stop polling for revisions until the base
+		// revision is fixed
+		gRepoPoller.ProjectRef.DisableRepotracker(model.RepotrackerRevisions, revisionError.Error(), 0)
+		gRepoPoller.ProjectRef.RepotrackerError.InvalidRevision = revision[:10]
+		gRepoPoller.ProjectRef.RepotrackerError.MergeBaseRevision = baseRevision
 		if err = gRepoPoller.ProjectRef.Upsert(); err != nil {
 			return []model.Revision{}, errors.Wrap(err, "unable to update projectRef revision details")
 		}
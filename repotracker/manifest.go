@@ -0,0 +1,121 @@
+package repotracker
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
+)
+
+// moduleResolver resolves the revision of a module in the repository with the
+// given owner and name.
+type moduleResolver func(ctx context.Context, owner, repo string, module model.Module) (*manifest.Module, error)
+
+// githubModuleResolver resolves modules against GitHub. A module with a ref
+// is pinned to that revision; otherwise the head of its branch is used.
+func githubModuleResolver(token string) moduleResolver {
+	return func(ctx context.Context, owner, repo string, module model.Module) (*manifest.Module, error) {
+		if module.Ref != "" {
+			commit, err := thirdparty.GetCommitEvent(ctx, token, owner, repo, module.Ref)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem retrieving ref '%s'", module.Ref)
+			}
+			if commit.SHA == nil || commit.URL == nil {
+				return nil, errors.Errorf("incomplete commit for ref '%s'", module.Ref)
+			}
+			return &manifest.Module{
+				Branch:   module.Branch,
+				Revision: *commit.SHA,
+				URL:      *commit.URL,
+			}, nil
+		}
+
+		branch, err := thirdparty.GetBranchEvent(ctx, token, owner, repo, module.Branch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem retrieving branch '%s'", module.Branch)
+		}
+		if branch.Commit == nil || branch.Commit.SHA == nil || branch.Commit.URL == nil {
+			return nil, errors.Errorf("incomplete head commit for branch '%s'", module.Branch)
+		}
+		return &manifest.Module{
+			Branch:   module.Branch,
+			Revision: *branch.Commit.SHA,
+			URL:      *branch.Commit.URL,
+		}, nil
+	}
+}
+
+// buildManifest resolves the revisions of the project's modules into a
+// manifest for the version.
+func buildManifest(ctx context.Context, v *version.Version, project *model.Project, resolve moduleResolver) (*manifest.Manifest, error) {
+	m := &manifest.Manifest{
+		Id:          v.Id,
+		Revision:    v.Revision,
+		ProjectName: v.Identifier,
+		Branch:      v.Branch,
+		Modules:     map[string]*manifest.Module{},
+	}
+
+	for _, module := range project.Modules {
+		owner, repo := module.GetRepoOwnerAndName()
+		if owner == "" || repo == "" {
+			return nil, errors.Errorf("cannot parse repository '%s' of module '%s'", module.Repo, module.Name)
+		}
+		resolved, err := resolve(ctx, owner, repo, module)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem resolving module '%s'", module.Name)
+		}
+		resolved.Owner = owner
+		resolved.Repo = repo
+		m.Modules[module.Name] = resolved
+	}
+
+	return m, nil
+}
+
+// CreateManifest pins the revisions of the project's modules for the version,
+// so that every task of the version checks out the same module revisions. If
+// the version already has a manifest, that manifest is returned instead.
+func CreateManifest(ctx context.Context, v *version.Version, project *model.Project, settings *evergreen.Settings) (*manifest.Manifest, error) {
+	existing, err := manifest.FindOne(manifest.ById(v.Id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error retrieving manifest for version '%s'", v.Id)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	var token string
+	if len(project.Modules) > 0 {
+		token, err = settings.GetGithubOauthToken()
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting github token")
+		}
+	}
+
+	m, err := buildManifest(ctx, v, project, githubModuleResolver(token))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error resolving modules for version '%s'", v.Id)
+	}
+
+	duplicate, err := m.TryInsert()
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem inserting manifest for version '%s'", v.Id)
+	}
+	// another caller pinned the modules first, so use its manifest
+	if duplicate {
+		existing, err = manifest.FindOne(manifest.ById(v.Id))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error retrieving manifest for version '%s'", v.Id)
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
+	return m, nil
+}
@@ -0,0 +1,66 @@
+package repotracker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildManifest(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	v := &version.Version{
+		Id:         "v",
+		Revision:   "rev",
+		Identifier: "project",
+		Branch:     "master",
+	}
+	project := &model.Project{
+		Modules: []model.Module{
+			{Name: "branch", Repo: "git@github.com:evergreen-ci/sample.git", Branch: "develop"},
+			{Name: "pinned", Repo: "git@github.com:evergreen-ci/render.git", Branch: "master", Ref: "1234"},
+		},
+	}
+	resolve := func(_ context.Context, owner, repo string, module model.Module) (*manifest.Module, error) {
+		revision := module.Ref
+		if revision == "" {
+			revision = "head-of-" + module.Branch
+		}
+		return &manifest.Module{Branch: module.Branch, Revision: revision}, nil
+	}
+
+	m, err := buildManifest(ctx, v, project, resolve)
+	require.NoError(err)
+	assert.Equal("v", m.Id)
+	assert.Equal("rev", m.Revision)
+	assert.Equal("project", m.ProjectName)
+	assert.Equal("master", m.Branch)
+	require.Len(m.Modules, 2)
+	require.NotNil(m.Modules["branch"])
+	assert.Equal("head-of-develop", m.Modules["branch"].Revision)
+	assert.Equal("evergreen-ci", m.Modules["branch"].Owner)
+	assert.Equal("sample", m.Modules["branch"].Repo)
+	require.NotNil(m.Modules["pinned"])
+	assert.Equal("1234", m.Modules["pinned"].Revision)
+	assert.Equal("render", m.Modules["pinned"].Repo)
+
+	project.Modules = append(project.Modules, model.Module{Name: "invalid", Repo: "invalid"})
+	_, err = buildManifest(ctx, v, project, resolve)
+	assert.Error(err)
+
+	project.Modules = project.Modules[:2]
+	_, err = buildManifest(ctx, v, project, func(context.Context, string, string, model.Module) (*manifest.Module, error) {
+		return nil, errors.New("github is down")
+	})
+	assert.Error(err)
+}
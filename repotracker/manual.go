@@ -0,0 +1,102 @@
+package repotracker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/validator"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// CreateManualVersion creates and activates a version of the project at the
+// given revision on behalf of a user, so that an ad-hoc build can be cut
+// without pushing a commit. The project configuration is read from the
+// repository at the revision, unless config is given, and params override the
+// defaults of the parameters it defines. Manual versions have their own
+// requester, so they don't affect the project's mainline.
+func CreateManualVersion(ctx context.Context, conf *evergreen.Settings, ref *model.ProjectRef, revision, config, msg, user string, params []version.Parameter) (*version.Version, error) {
+	if !ref.Enabled {
+		return nil, errors.Errorf("project disabled: %s", ref.Identifier)
+	}
+	if revision == "" {
+		return nil, errors.New("revision must not be blank")
+	}
+
+	token, err := conf.GetGithubOauthToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem getting github token")
+	}
+	commit, err := thirdparty.GetCommitEvent(ctx, token, ref.Owner, ref.Repo, revision)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding revision '%s'", revision)
+	}
+	rev := githubCommitToRevision(commit)
+
+	project := &model.Project{}
+	if config != "" {
+		if err = model.LoadProjectInto([]byte(config), ref.Identifier, project); err != nil {
+			return nil, errors.Wrap(err, "invalid project configuration")
+		}
+	} else {
+		tracker := &RepoTracker{
+			Settings:   conf,
+			ProjectRef: ref,
+			RepoPoller: NewGithubRepositoryPoller(ref, token),
+		}
+		project, err = tracker.GetProjectConfig(ctx, rev.Revision)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem getting project configuration at revision '%s'", rev.Revision)
+		}
+	}
+
+	return createManualVersion(ref, rev, project, msg, user, params)
+}
+
+// createManualVersion creates the version of the revision, rejecting
+// configurations with errors rather than storing a version that can't run.
+func createManualVersion(ref *model.ProjectRef, rev model.Revision, project *model.Project, msg, user string, params []version.Parameter) (*version.Version, error) {
+	verrs, err := validator.CheckProjectSyntax(project)
+	if err != nil {
+		return nil, errors.Wrap(err, "error validating project")
+	}
+	projectErrors := []string{}
+	for _, e := range verrs {
+		if e.Level == validator.Error {
+			projectErrors = append(projectErrors, e.Error())
+		}
+	}
+	if len(projectErrors) > 0 {
+		return nil, errors.Errorf("invalid project configuration: %s", strings.Join(projectErrors, "; "))
+	}
+	if _, err = project.ResolveParameters(params); err != nil {
+		return nil, errors.Wrap(err, "invalid version parameters")
+	}
+
+	v, err := CreateVersionFromConfig(ref, project, VersionMetadata{
+		Revision:   rev,
+		Requester:  evergreen.ManualVersionRequester,
+		User:       user,
+		Message:    msg,
+		Parameters: params,
+	}, false, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem creating manual version of revision '%s'", rev.Revision)
+	}
+
+	grip.Info(message.Fields{
+		"runner":   RunnerName,
+		"message":  "created manual version",
+		"project":  ref.Identifier,
+		"revision": rev.Revision,
+		"version":  v.Id,
+		"user":     user,
+	})
+
+	return v, nil
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 	yaml "gopkg.in/yaml.v2"
 )
 
@@ -27,6 +28,10 @@ const (
 	DefaultNumNewRepoRevisionsToFetch = 200
 	DefaultMaxRepoRevisionsToSearch   = 50
 	DefaultNumConcurrentRequests      = 10
+
+	// configFetchBackoff is how long fetching project configs is disabled
+	// after a config cannot be retrieved
+	configFetchBackoff = 15 * time.Minute
 )
 
 // RepoTracker is used to manage polling repository changes and storing such
@@ -108,7 +113,17 @@ func (repoTracker *RepoTracker) FetchRevisions(ctx context.Context) error {
 		lastRevision = repository.LastRevision
 	}
 
-	if lastRevision == "" {
+	if state := projectRef.RepotrackerError.GetDisableState(model.RepotrackerRevisions); state != nil {
+		// if the projectRef can't poll for revisions then skip to activation
+		grip.Warning(message.Fields{
+			"runner":  RunnerName,
+			"message": "repotracker revisions disabled",
+			"reason":  state.Reason,
+			"expires": state.Expires,
+			"project": projectRef.Identifier,
+			"path":    fmt.Sprintf("%s/%s:%s", projectRef.Owner, projectRef.Repo, projectRef.Branch),
+		})
+	} else if lastRevision == "" {
 		numRevisions := settings.RepoTracker.NumNewRepoRevisionsToFetch
 		if numRevisions <= 0 {
 			numRevisions = DefaultNumNewRepoRevisionsToFetch
@@ -129,18 +144,6 @@ func (repoTracker *RepoTracker) FetchRevisions(ctx context.Context) error {
 			"runner":   RunnerName,
 			"revision": lastRevision,
 		})
-		// if the projectRef has a repotracker error then don't get the revisions
-		if projectRef.RepotrackerError != nil {
-			if projectRef.RepotrackerError.Exists {
-				grip.Warning(message.Fields{
-					"runner":  RunnerName,
-					"message": "repotracker error for base revision",
-					"project": projectRef,
-					"path":    fmt.Sprintf("%s/%s:%s", projectRef.Owner, projectRef.Repo, projectRef.Branch),
-				})
-				return nil
-			}
-		}
 		max := settings.RepoTracker.MaxRepoRevisionsToSearch
 		if max <= 0 {
 			max = DefaultMaxRepoRevisionsToSearch
@@ -157,7 +160,18 @@ func (repoTracker *RepoTracker) FetchRevisions(ctx context.Context) error {
 		return nil
 	}
 
-	if len(revisions) > 0 {
+	if state := projectRef.RepotrackerError.GetDisableState(model.RepotrackerConfigFetch); state != nil && len(revisions) > 0 {
+		// leave the last revision in place so that these revisions are
+		// stored once config fetching resumes
+		grip.Warning(message.Fields{
+			"runner":    RunnerName,
+			"message":   "repotracker config fetch disabled, not storing revisions",
+			"reason":    state.Reason,
+			"expires":   state.Expires,
+			"project":   projectRef.Identifier,
+			"revisions": len(revisions),
+		})
+	} else if len(revisions) > 0 {
 		var lastVersion *version.Version
 		lastVersion, err = repoTracker.StoreRevisions(ctx, revisions)
 		if err != nil {
@@ -179,6 +193,17 @@ func (repoTracker *RepoTracker) FetchRevisions(ctx context.Context) error {
 		}
 	}
 
+	if state := projectRef.RepotrackerError.GetDisableState(model.RepotrackerActivation); state != nil {
+		grip.Info(message.Fields{
+			"runner":  RunnerName,
+			"message": "repotracker activation paused",
+			"reason":  state.Reason,
+			"expires": state.Expires,
+			"project": projectRef.Identifier,
+		})
+		return nil
+	}
+
 	if err := model.DoProjectActivation(projectIdentifier); err != nil {
 		grip.Error(message.WrapError(err, message.Fields{
 			"message": "problem activating recent commit for project",
@@ -243,7 +268,7 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 					Errors:   projErr.Errors,
 				}
 				if len(versionErrs.Errors) > 0 {
-					stubVersion, dbErr := shellVersionFromRevision(ref, revisions[i])
+					stubVersion, dbErr := shellVersionFromRevision(ref, VersionMetadata{Revision: revisions[i]})
 					if dbErr != nil {
 						grip.Error(message.WrapError(dbErr, message.Fields{
 							"message":  "error creating shell version",
@@ -254,6 +279,7 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 					}
 					stubVersion.Errors = versionErrs.Errors
 					stubVersion.Warnings = versionErrs.Warnings
+					markInheritedWarnings(stubVersion)
 					err = stubVersion.Insert()
 					grip.Error(message.WrapError(err, message.Fields{
 						"message":  "error inserting shell version",
@@ -261,6 +287,9 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 						"project":  ref.Identifier,
 						"revision": revision,
 					}))
+					if err == nil {
+						logConfigWarnings(ref, stubVersion)
+					}
 					newestVersion = stubVersion
 					continue
 				}
@@ -271,14 +300,25 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 					"project":  ref.Identifier,
 					"revision": revision,
 				}))
+				// back off from fetching configs rather than retrying
+				// the same revision on every run
+				ref.DisableRepotracker(model.RepotrackerConfigFetch,
+					fmt.Sprintf("error getting project config for revision %s: %s", revision, err.Error()),
+					configFetchBackoff)
+				grip.Error(message.WrapError(ref.Upsert(), message.Fields{
+					"message":  "problem disabling config fetch for project",
+					"runner":   RunnerName,
+					"project":  ref.Identifier,
+					"revision": revision,
+				}))
 				return nil, err
 			}
 		}
 
 		// "Ignore" a version if all changes are to ignored files
 		var ignore bool
+		var filenames []string
 		if len(project.Ignore) > 0 {
-			var filenames []string
 			filenames, err = repoTracker.GetChangedFiles(ctx, revision)
 			if err != nil {
 				grip.Error(message.WrapError(err, message.Fields{
@@ -294,7 +334,22 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 			}
 		}
 
-		v, err := CreateVersionFromConfig(ref, project, &revisions[i], ignore, versionErrs)
+		metadata := VersionMetadata{Revision: revisions[i]}
+		if ref.TestSelectionEnabled && !ignore {
+			metadata.TaskSelection, err = repoTracker.selectTasks(ctx, project, revision, filenames)
+			if err != nil {
+				// run every task rather than skip affected ones
+				grip.Error(message.WrapError(err, message.Fields{
+					"message":  "error selecting tasks for changed files",
+					"runner":   RunnerName,
+					"project":  ref.Identifier,
+					"revision": revision,
+				}))
+				metadata.TaskSelection = nil
+			}
+		}
+
+		v, err := CreateVersionFromConfig(ref, project, metadata, ignore, versionErrs)
 		if err != nil {
 			grip.Error(message.WrapError(err, message.Fields{
 				"message":  "error creating version",
@@ -313,12 +368,57 @@ func (repoTracker *RepoTracker) StoreRevisions(ctx context.Context, revisions []
 			}))
 			continue
 		}
+		if len(v.Errors) == 0 && len(project.Modules) > 0 {
+			// pin module revisions now, so that they do not depend on when
+			// the version's tasks run
+			_, err = CreateManifest(ctx, v, project, repoTracker.Settings)
+			grip.Error(message.WrapError(err, message.Fields{
+				"message":  "error pinning module revisions",
+				"runner":   RunnerName,
+				"project":  ref.Identifier,
+				"revision": revision,
+				"version":  v.Id,
+			}))
+		}
 
 		newestVersion = v
 	}
 	return newestVersion, nil
 }
 
+// selectTasks returns the tasks affected by the changed files of a mainline
+// revision, fetching the files if they're nil. It returns nil, so that every
+// task runs, when the project's full run interval has passed since its last
+// version that ran every task, or when a changed file isn't mapped to tasks.
+func (repoTracker *RepoTracker) selectTasks(ctx context.Context, project *model.Project, revision string, filenames []string) (*version.TaskSelection, error) {
+	ref := repoTracker.ProjectRef
+	lastFullRun, err := version.FindOne(version.ByLastFullRun(ref.Identifier))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding last full run")
+	}
+	if lastFullRun == nil || time.Since(lastFullRun.CreateTime) >= ref.GetFullRunInterval() {
+		return nil, nil
+	}
+
+	if filenames == nil {
+		filenames, err = repoTracker.GetChangedFiles(ctx, revision)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem getting changed files")
+		}
+	}
+	tasks, ok, err := model.SelectTasksForFiles(project, ref.Identifier, filenames)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &version.TaskSelection{
+		Tasks:        tasks,
+		ChangedFiles: filenames,
+	}, nil
+}
+
 // GetProjectConfig fetches the project configuration for a given repository
 // returning a remote config if the project references a remote repository
 // configuration file - via the Identifier. Otherwise it defaults to the local
@@ -388,15 +488,30 @@ func (repoTracker *RepoTracker) GetProjectConfig(ctx context.Context, revision s
 	return project, nil
 }
 
+const (
+	// build break notifications escalate through these tiers
+	buildBreakTierCommitter = "committer"
+	buildBreakTierTeam      = "team"
+	buildBreakTierAdmins    = "admins"
+
+	defaultBuildBreakEscalationMins = 60
+)
+
 func addBuildBreakSubscriptions(v *version.Version, projectRef *model.ProjectRef) error {
+	// the subscriptions are scoped to the version's tasks, so that each
+	// build break escalates to the people responsible for its version
 	subscriptionBase := event.Subscription{
-		ResourceType: event.ResourceTypeVersion,
+		ResourceType: event.ResourceTypeTask,
 		Trigger:      "build-break",
 		Selectors: []event.Selector{
 			{
 				Type: "object",
 				Data: "task",
 			},
+			{
+				Type: "in-version",
+				Data: v.Id,
+			},
 			{
 				Type: "project",
 				Data: projectRef.Identifier,
@@ -406,26 +521,56 @@ func addBuildBreakSubscriptions(v *version.Version, projectRef *model.ProjectRef
 				Data: evergreen.RepotrackerVersionRequester,
 			},
 		},
+		// the project owns the subscriptions, so that its admins can manage
+		// them through the REST API
+		OwnerType: event.OwnerTypeProject,
+		Owner:     projectRef.Identifier,
 	}
-	subscribers := []event.Subscriber{}
 
 	// if the commit author has subscribed to build break notifications,
-	// send it to the comitter, but not the admins
+	// they are notified by their own subscription, and no one else is
+	// notified unless the project escalates build breaks
 	catcher := grip.NewSimpleCatcher()
+	var committer *event.Subscriber
 	if v.AuthorID != "" {
 		author, err := user.FindOne(user.ById(v.AuthorID))
 		if err != nil {
 			catcher.Add(errors.Wrap(err, "unable to retrieve user"))
-		} else if author.Settings.Notifications.BuildBreakID != "" {
-			return nil
+		} else if author != nil && author.Settings.Notifications.BuildBreakID != "" {
+			committer, err = makeBuildBreakSubscriber(author.Id)
+			catcher.Add(err)
+			if committer == nil {
+				return catcher.Resolve()
+			}
 		}
 	}
 
-	// Only send to admins if the admins have enabled build break notifications
+	// Only send to others if the admins have enabled build break notifications
 	if !projectRef.NotifyOnBuildFailure {
-		return nil
+		return catcher.Resolve()
+	}
+
+	// unacknowledged build breaks escalate from the committer to the
+	// team channel, then to the admins
+	tiers := []event.EscalationTier{}
+	if committer != nil {
+		tiers = append(tiers, event.EscalationTier{
+			Name:        buildBreakTierCommitter,
+			Subscribers: []event.Subscriber{*committer},
+		})
+	}
+	if projectRef.BuildBreakTeamChannel != "" {
+		tiers = append(tiers, event.EscalationTier{
+			Name: buildBreakTierTeam,
+			Subscribers: []event.Subscriber{
+				{
+					Type:   event.SlackSubscriberType,
+					Target: projectRef.BuildBreakTeamChannel,
+				},
+			},
+		})
 	}
-	// if the project has build break notifications, subscribe admins if no one subscribed
+	admins := event.EscalationTier{Name: buildBreakTierAdmins}
 	for _, admin := range projectRef.Admins {
 		subscriber, err := makeBuildBreakSubscriber(admin)
 		if err != nil {
@@ -433,11 +578,29 @@ func addBuildBreakSubscriptions(v *version.Version, projectRef *model.ProjectRef
 			continue
 		}
 		if subscriber != nil {
-			subscribers = append(subscribers, *subscriber)
+			admins.Subscribers = append(admins.Subscribers, *subscriber)
 		}
 	}
+	if len(admins.Subscribers) > 0 {
+		tiers = append(tiers, admins)
+	}
+	if len(tiers) == 0 || (len(tiers) == 1 && committer != nil) {
+		return catcher.Resolve()
+	}
 
-	for _, subscriber := range subscribers {
+	// the first tier is notified when the build breaks, and the rest
+	// are notified in turn if no one acknowledges it
+	if len(tiers) > 1 {
+		delay := projectRef.BuildBreakEscalationMins
+		if delay <= 0 {
+			delay = defaultBuildBreakEscalationMins
+		}
+		subscriptionBase.Escalation = &event.Escalation{
+			Delay: delay,
+			Tiers: tiers[1:],
+		}
+	}
+	for _, subscriber := range tiers[0].Subscribers {
 		newSubscription := subscriptionBase
 		newSubscription.Subscriber = subscriber
 		catcher.Add(newSubscription.Upsert())
@@ -471,18 +634,48 @@ func makeBuildBreakSubscriber(userID string) (*event.Subscriber, error) {
 	return subscriber, nil
 }
 
-func CreateVersionFromConfig(ref *model.ProjectRef, config *model.Project, rev *model.Revision, ignore bool, versionErrs *VersionErrors) (*version.Version, error) {
+// VersionMetadata describes the revision a version is created from, and who
+// it's created by.
+type VersionMetadata struct {
+	Revision model.Revision
+	// Requester is the requester of the version, which is the repotracker
+	// if it's empty.
+	Requester string
+	// User is the user that created a manual version, and Message, if set,
+	// replaces the revision's message as the version's message.
+	User    string
+	Message string
+	// TaskSelection, if set, limits the version's activated tasks to those
+	// affected by its changed files.
+	TaskSelection *version.TaskSelection
+	// Parameters are the values given for the project's parameters, which
+	// otherwise take their defaults.
+	Parameters []version.Parameter
+}
+
+func (m *VersionMetadata) requester() string {
+	if m.Requester == "" {
+		return evergreen.RepotrackerVersionRequester
+	}
+	return m.Requester
+}
+
+func CreateVersionFromConfig(ref *model.ProjectRef, config *model.Project, metadata VersionMetadata, ignore bool, versionErrs *VersionErrors) (*version.Version, error) {
 	if ref == nil || config == nil {
 		return nil, errors.New("project ref and project cannot be nil")
 	}
 
 	// create a version document
-	v, err := shellVersionFromRevision(ref, *rev)
+	v, err := shellVersionFromRevision(ref, metadata)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create shell version")
 	}
-	if err = sanityCheckOrderNum(v.RevisionOrderNumber, ref.Identifier, rev.Revision); err != nil {
-		return nil, errors.Wrap(err, "inconsistent version order")
+	// manual versions are created for revisions that may already have a
+	// version, and aren't part of the project's mainline
+	if util.StringSliceContains(evergreen.SystemVersionRequesterTypes, v.Requester) {
+		if err = sanityCheckOrderNum(v.RevisionOrderNumber, ref.Identifier, metadata.Revision.Revision); err != nil {
+			return nil, errors.Wrap(err, "inconsistent version order")
+		}
 	}
 	configYaml, err := yaml.Marshal(config)
 	if err != nil {
@@ -490,12 +683,19 @@ func CreateVersionFromConfig(ref *model.ProjectRef, config *model.Project, rev *
 	}
 	v.Config = string(configYaml)
 	v.Ignored = ignore
+	v.TaskSelection = metadata.TaskSelection
+	v.Parameters, err = config.ResolveParameters(metadata.Parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid version parameters")
+	}
 
 	// validate the project
 	verrs, err := validator.CheckProjectSyntax(config)
 	if err != nil {
 		return nil, errors.Wrap(err, "error validating project")
 	}
+	verrs = append(verrs, validator.CheckProjectDeprecations(config)...)
+	v.Deprecations = config.Deprecations
 	if len(verrs) > 0 || versionErrs != nil {
 		// We have syntax errors in the project.
 		// Format them, as we need to store + display them to the user
@@ -515,17 +715,86 @@ func CreateVersionFromConfig(ref *model.ProjectRef, config *model.Project, rev *
 		if versionErrs != nil && versionErrs.Errors != nil {
 			v.Errors = append(v.Errors, versionErrs.Errors...)
 		}
+		markInheritedWarnings(v)
 		if len(v.Errors) > 0 {
-			return v, errors.Wrap(v.Insert(), "error inserting version")
+			if err = v.Insert(); err != nil {
+				return v, errors.Wrap(err, "error inserting version")
+			}
+			logConfigWarnings(ref, v)
+			return v, nil
 		}
 	}
 
-	return v, errors.Wrap(createVersionItems(v, ref, config), "error creating version items")
+	if err = createVersionItems(v, ref, config); err != nil {
+		return v, errors.Wrap(err, "error creating version items")
+	}
+	logConfigWarnings(ref, v)
+	subscribeGithubChecks(ref, v)
+
+	return v, nil
+}
+
+// markInheritedWarnings compares the version's warnings against those of the
+// most recent mainline version of the project, so that warnings that were not
+// introduced by this revision can be told apart.
+func markInheritedWarnings(v *version.Version) {
+	if len(v.Warnings) == 0 {
+		return
+	}
+
+	previous, err := version.FindOne(version.ByMostRecentSystemRequester(v.Identifier))
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem finding previous version to compare warnings",
+			"runner":  RunnerName,
+			"project": v.Identifier,
+			"version": v.Id,
+		}))
+		return
+	}
+	v.MarkInheritedWarnings(previous)
+}
+
+// logConfigWarnings logs an event for the version's project configuration
+// warnings. If the project suppresses inherited warnings, only the warnings
+// introduced by the version are included.
+func logConfigWarnings(ref *model.ProjectRef, v *version.Version) {
+	warnings := v.Warnings
+	if ref.SuppressInheritedWarnings {
+		warnings = v.NewWarnings()
+	}
+	if len(warnings) == 0 {
+		return
+	}
+
+	event.LogVersionConfigWarningsEvent(v.Id, warnings)
+}
+
+// subscribeGithubChecks subscribes to the outcomes of the version's builds
+// to report them as check runs on the version's commit, if the project
+// reports to the Github checks API.
+func subscribeGithubChecks(ref *model.ProjectRef, v *version.Version) {
+	if !ref.GithubChecksEnabled {
+		return
+	}
+
+	sub := event.NewBuildOutcomeSubscriptionByVersion(v.Id, event.NewGithubCheckSubscriber(event.GithubCheckSubscriber{
+		Owner: ref.Owner,
+		Repo:  ref.Repo,
+		Ref:   v.Revision,
+	}))
+	grip.Error(message.WrapError(sub.Upsert(), message.Fields{
+		"message": "problem subscribing version to Github checks",
+		"runner":  RunnerName,
+		"project": ref.Identifier,
+		"version": v.Id,
+	}))
 }
 
 // shellVersionFromRevision populates a new Version with metadata from a model.Revision.
 // Does not populate its config or store anything in the database.
-func shellVersionFromRevision(ref *model.ProjectRef, rev model.Revision) (*version.Version, error) {
+func shellVersionFromRevision(ref *model.ProjectRef, metadata VersionMetadata) (*version.Version, error) {
+	rev := metadata.Revision
 	u, err := user.FindByGithubUID(rev.AuthorGithubUID)
 	grip.Error(message.WrapError(err, message.Fields{
 		"message": fmt.Sprintf("failed to fetch everg user with Github UID %d", rev.AuthorGithubUID),
@@ -547,7 +816,7 @@ func shellVersionFromRevision(ref *model.ProjectRef, rev model.Revision) (*versi
 		RemotePath:          ref.RemotePath,
 		Repo:                ref.Repo,
 		RepoKind:            ref.RepoKind,
-		Requester:           evergreen.RepotrackerVersionRequester,
+		Requester:           metadata.requester(),
 		Revision:            rev.Revision,
 		Status:              evergreen.VersionCreated,
 		RevisionOrderNumber: number,
@@ -555,6 +824,15 @@ func shellVersionFromRevision(ref *model.ProjectRef, rev model.Revision) (*versi
 	if u != nil {
 		v.AuthorID = u.Id
 	}
+	if v.Requester == evergreen.ManualVersionRequester {
+		// there can be any number of manual versions of a revision
+		v.Id = util.CleanName(fmt.Sprintf("%v_%v_%v", ref.String(), rev.Revision, bson.NewObjectId().Hex()))
+		v.CreateTime = time.Now()
+		v.AuthorID = metadata.User
+	}
+	if metadata.Message != "" {
+		v.Message = metadata.Message
+	}
 	return v, nil
 }
 
@@ -593,17 +871,32 @@ func createVersionItems(v *version.Version, ref *model.ProjectRef, project *mode
 	// generate all task Ids so that we can easily reference them for dependencies
 	taskIds := model.NewTaskIdTable(project, v)
 
+	// manual versions run as soon as they're created, rather than being
+	// activated after the project's batch time
+	activateNow := v.Requester == evergreen.ManualVersionRequester
+
 	// create all builds for the version
 	for _, buildvariant := range project.BuildVariants {
 		if buildvariant.Disabled {
 			continue
 		}
 
-		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, buildvariant.Name, false, nil, nil, "")
+		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, buildvariant.Name, activateNow, nil, nil, "")
 		if err != nil {
 			return errors.WithStack(err)
 		}
 
+		if activateNow {
+			v.BuildIds = append(v.BuildIds, buildId)
+			v.BuildVariants = append(v.BuildVariants, version.BuildStatus{
+				BuildVariant: buildvariant.Name,
+				Activated:    true,
+				ActivateAt:   time.Now(),
+				BuildId:      buildId,
+			})
+			continue
+		}
+
 		lastActivated, err := version.FindOne(version.ByLastVariantActivation(ref.Identifier, buildvariant.Name))
 		if err != nil {
 			return errors.Wrap(err, "problem getting activatation time for variant")
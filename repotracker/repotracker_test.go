@@ -569,6 +569,10 @@ func TestBuildBreakSubscriptions(t *testing.T) {
 	assert.NoError(addBuildBreakSubscriptions(&v1, &proj2))
 	assert.NoError(db.FindAllQ(event.SubscriptionsCollection, db.Q{}, &subs))
 	assert.Len(subs, 2)
+	for _, sub := range subs {
+		assert.Equal(event.OwnerTypeProject, sub.OwnerType)
+		assert.Equal(proj2.Identifier, sub.Owner)
+	}
 
 	// project has it enabled, but user doesn't want notifications
 	subs = []event.Subscription{}
@@ -591,6 +595,70 @@ func TestBuildBreakSubscriptions(t *testing.T) {
 	assert.NoError(addBuildBreakSubscriptions(&v3, &proj2))
 	assert.NoError(db.FindAllQ(event.SubscriptionsCollection, db.Q{}, &subs))
 	assert.Len(subs, 2)
+	for _, sub := range subs {
+		assert.Nil(sub.Escalation)
+	}
+
+	// the committer is notified first, then the team channel, then the admins
+	subs = []event.Subscription{}
+	assert.NoError(db.Clear(event.SubscriptionsCollection))
+	u5 := user.DBUser{
+		Id:           "u5",
+		EmailAddress: "valla@blizzard.com",
+		Settings: user.UserSettings{
+			Notifications: user.NotificationPreferences{
+				BuildBreak:   user.PreferenceEmail,
+				BuildBreakID: "u5-build-break",
+			},
+		},
+	}
+	assert.NoError(u5.Insert())
+	proj3 := model.ProjectRef{
+		Identifier:               "proj3",
+		NotifyOnBuildFailure:     true,
+		Admins:                   []string{"u2", "u3"},
+		BuildBreakTeamChannel:    "#proj3",
+		BuildBreakEscalationMins: 15,
+	}
+	v4 := version.Version{
+		Id:         "v4",
+		Identifier: proj3.Identifier,
+		Requester:  evergreen.RepotrackerVersionRequester,
+		Branch:     "branch",
+		AuthorID:   u5.Id,
+	}
+	assert.NoError(addBuildBreakSubscriptions(&v4, &proj3))
+	assert.NoError(db.FindAllQ(event.SubscriptionsCollection, db.Q{}, &subs))
+	if assert.Len(subs, 1) {
+		sub := subs[0]
+		assert.Equal(event.ResourceTypeTask, sub.ResourceType)
+		assert.Equal(event.EmailSubscriberType, sub.Subscriber.Type)
+		assert.Contains(sub.Selectors, event.Selector{Type: "in-version", Data: v4.Id})
+		if assert.NotNil(sub.Escalation) {
+			assert.Equal(15, sub.Escalation.Delay)
+			if assert.Len(sub.Escalation.Tiers, 2) {
+				assert.Equal(buildBreakTierTeam, sub.Escalation.Tiers[0].Name)
+				assert.Equal(event.SlackSubscriberType, sub.Escalation.Tiers[0].Subscribers[0].Type)
+				assert.Equal(buildBreakTierAdmins, sub.Escalation.Tiers[1].Name)
+				assert.Len(sub.Escalation.Tiers[1].Subscribers, 2)
+			}
+		}
+	}
+
+	// without a committer, the team channel is notified first
+	subs = []event.Subscription{}
+	assert.NoError(db.Clear(event.SubscriptionsCollection))
+	v4.AuthorID = ""
+	proj3.BuildBreakEscalationMins = 0
+	assert.NoError(addBuildBreakSubscriptions(&v4, &proj3))
+	assert.NoError(db.FindAllQ(event.SubscriptionsCollection, db.Q{}, &subs))
+	if assert.Len(subs, 1) {
+		assert.Equal(event.SlackSubscriberType, subs[0].Subscriber.Type)
+		if assert.NotNil(subs[0].Escalation) {
+			assert.Equal(defaultBuildBreakEscalationMins, subs[0].Escalation.Delay)
+			assert.Len(subs[0].Escalation.Tiers, 1)
+		}
+	}
 }
 
 type CreateVersionFromConfigSuite struct {
@@ -643,7 +711,7 @@ tasks:
 	p := &model.Project{}
 	err := model.LoadProjectInto([]byte(configYml), s.ref.Identifier, p)
 	s.NoError(err)
-	v, err := CreateVersionFromConfig(s.ref, p, s.rev, false, nil)
+	v, err := CreateVersionFromConfig(s.ref, p, VersionMetadata{Revision: *s.rev}, false, nil)
 	s.NoError(err)
 	s.Require().NotNil(v)
 
@@ -663,6 +731,88 @@ tasks:
 	s.Len(dbTasks, 2)
 }
 
+func (s *CreateVersionFromConfigSuite) TestCreateManualVersion() {
+	configYml := `
+buildvariants:
+- name: bv
+  run_on: d
+  tasks:
+  - name: task1
+tasks:
+- name: task1
+`
+	p := &model.Project{}
+	s.NoError(model.LoadProjectInto([]byte(configYml), s.ref.Identifier, p))
+
+	v, err := createManualVersion(s.ref, *s.rev, p, "release candidate", "release-manager", nil)
+	s.NoError(err)
+	s.Require().NotNil(v)
+
+	// manual versions of the same revision don't collide
+	other, err := createManualVersion(s.ref, *s.rev, p, "", "release-manager", nil)
+	s.NoError(err)
+	s.Require().NotNil(other)
+	s.NotEqual(v.Id, other.Id)
+
+	dbVersion, err := version.FindOneId(v.Id)
+	s.NoError(err)
+	s.Require().NotNil(dbVersion)
+	s.Equal(evergreen.ManualVersionRequester, dbVersion.Requester)
+	s.Equal("release-manager", dbVersion.AuthorID)
+	s.Equal("release candidate", dbVersion.Message)
+	s.Require().Len(dbVersion.BuildVariants, 1)
+	s.True(dbVersion.BuildVariants[0].Activated)
+
+	dbBuild, err := build.FindOneId(v.BuildIds[0])
+	s.NoError(err)
+	s.Require().NotNil(dbBuild)
+	s.True(dbBuild.Activated)
+
+	// invalid configurations are rejected without creating a version
+	p.BuildVariants[0].RunOn = nil
+	_, err = createManualVersion(s.ref, *s.rev, p, "", "release-manager", nil)
+	s.Error(err)
+	versions, err := version.Find(version.ByProjectId(s.ref.Identifier))
+	s.NoError(err)
+	s.Len(versions, 2)
+}
+
+func (s *CreateVersionFromConfigSuite) TestCreateManualVersionWithParameters() {
+	configYml := `
+parameters:
+- key: suite
+  value: smoke
+- key: iterations
+  value: "1"
+buildvariants:
+- name: bv
+  run_on: d
+  tasks:
+  - name: task1
+tasks:
+- name: task1
+`
+	p := &model.Project{}
+	s.NoError(model.LoadProjectInto([]byte(configYml), s.ref.Identifier, p))
+
+	v, err := createManualVersion(s.ref, *s.rev, p, "", "release-manager",
+		[]version.Parameter{{Key: "iterations", Value: "10"}})
+	s.NoError(err)
+	s.Require().NotNil(v)
+	dbVersion, err := version.FindOneId(v.Id)
+	s.NoError(err)
+	s.Require().NotNil(dbVersion)
+	s.Equal([]version.Parameter{{Key: "suite", Value: "smoke"}, {Key: "iterations", Value: "10"}}, dbVersion.Parameters)
+
+	// parameters the project doesn't define are rejected
+	_, err = createManualVersion(s.ref, *s.rev, p, "", "release-manager",
+		[]version.Parameter{{Key: "undefined", Value: "true"}})
+	s.Error(err)
+	versions, err := version.Find(version.ByProjectId(s.ref.Identifier))
+	s.NoError(err)
+	s.Len(versions, 1)
+}
+
 func (s *CreateVersionFromConfigSuite) TestInvalidConfigErrors() {
 	configYml := `
 buildvariants:
@@ -677,7 +827,7 @@ tasks:
 	p := &model.Project{}
 	err := model.LoadProjectInto([]byte(configYml), s.ref.Identifier, p)
 	s.NoError(err)
-	v, err := CreateVersionFromConfig(s.ref, p, s.rev, false, nil)
+	v, err := CreateVersionFromConfig(s.ref, p, VersionMetadata{Revision: *s.rev}, false, nil)
 	s.NoError(err)
 	s.Require().NotNil(v)
 
@@ -714,7 +864,7 @@ tasks:
 		Errors:   []string{"err1"},
 		Warnings: []string{"warn1", "warn2"},
 	}
-	v, err := CreateVersionFromConfig(s.ref, p, s.rev, false, &vErrs)
+	v, err := CreateVersionFromConfig(s.ref, p, VersionMetadata{Revision: *s.rev}, false, &vErrs)
 	s.NoError(err)
 	s.Require().NotNil(v)
 
@@ -0,0 +1,89 @@
+package repotracker
+
+import (
+	"context"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/google/go-github/github"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// ErrNoVersionForRevision is returned when a tagged revision doesn't have a
+// version yet. Tags are often pushed along with the revision they tag, before
+// the repotracker has created its version, so adding the tag is worth
+// retrying.
+var ErrNoVersionForRevision = errors.New("the revision has no version yet")
+
+// AddTagToVersion records the metadata of a tag on the version of the project
+// built from the tagged revision. For annotated tags, the tag object
+// identified by tagSHA is fetched from GitHub so that the tag message,
+// tagger, and signature status are stored. Lightweight tags, whose SHA is the
+// revision itself, only have their name recorded. An error whose cause is
+// ErrNoVersionForRevision is returned if the revision has no version in the
+// project.
+func AddTagToVersion(ctx context.Context, conf *evergreen.Settings, project model.ProjectRef, tagName, tagSHA, revision string) (*version.Version, error) {
+	v, err := version.FindOne(version.ByProjectIdAndRevision(project.Identifier, revision))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding version for revision '%s'", revision)
+	}
+	if v == nil {
+		return nil, errors.Wrapf(ErrNoVersionForRevision, "can't add tag '%s' to revision '%s'", tagName, revision)
+	}
+
+	tag := &version.TagMetadata{
+		Name: tagName,
+		SHA:  tagSHA,
+	}
+
+	if tagSHA != revision {
+		token, err := conf.GetGithubOauthToken()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		githubTag, err := thirdparty.GetGithubTag(ctx, token, project.Owner, project.Repo, tagSHA)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error fetching tag '%s'", tagName)
+		}
+		tag = tagMetadataFromGithub(tagName, githubTag)
+	}
+
+	if err = v.SetTagMetadata(tag); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	grip.Info(message.Fields{
+		"runner":   RunnerName,
+		"message":  "added tag to version",
+		"project":  project.Identifier,
+		"version":  v.Id,
+		"revision": revision,
+		"tag":      tagName,
+	})
+
+	return v, nil
+}
+
+func tagMetadataFromGithub(name string, tag *github.Tag) *version.TagMetadata {
+	metadata := &version.TagMetadata{
+		Name:    name,
+		SHA:     tag.GetSHA(),
+		Message: tag.GetMessage(),
+	}
+	if tag.Tagger != nil {
+		metadata.Tagger = tag.Tagger.GetName()
+		metadata.TaggerEmail = tag.Tagger.GetEmail()
+		metadata.TagTime = tag.Tagger.GetDate()
+	}
+	if tag.Verification != nil {
+		metadata.SignatureVerified = tag.Verification.GetVerified()
+		metadata.SignatureReason = tag.Verification.GetReason()
+	}
+
+	return metadata
+}
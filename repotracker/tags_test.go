@@ -0,0 +1,76 @@
+package repotracker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/google/go-github/github"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagMetadataFromGithub(t *testing.T) {
+	assert := assert.New(t)
+
+	tagTime := time.Now()
+	tag := &github.Tag{
+		SHA:     github.String("tag-sha"),
+		Message: github.String("release"),
+		Tagger: &github.CommitAuthor{
+			Name:  github.String("tagger"),
+			Email: github.String("tagger@example.com"),
+			Date:  &tagTime,
+		},
+		Verification: &github.SignatureVerification{
+			Verified: github.Bool(true),
+			Reason:   github.String("valid"),
+		},
+	}
+
+	metadata := tagMetadataFromGithub("r1", tag)
+	assert.Equal("r1", metadata.Name)
+	assert.Equal("tag-sha", metadata.SHA)
+	assert.Equal("release", metadata.Message)
+	assert.Equal("tagger", metadata.Tagger)
+	assert.Equal("tagger@example.com", metadata.TaggerEmail)
+	assert.Equal(tagTime, metadata.TagTime)
+	assert.True(metadata.SignatureVerified)
+	assert.Equal("valid", metadata.SignatureReason)
+
+	metadata = tagMetadataFromGithub("r2", &github.Tag{SHA: github.String("sha")})
+	assert.Equal("r2", metadata.Name)
+	assert.Empty(metadata.Tagger)
+	assert.False(metadata.SignatureVerified)
+}
+
+func TestAddLightweightTagToVersion(t *testing.T) {
+	assert := assert.New(t)
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+	assert.NoError(db.Clear(version.Collection))
+
+	v := &version.Version{
+		Id:         "v1",
+		Identifier: "proj",
+		Revision:   "abcdef",
+	}
+	assert.NoError(v.Insert())
+
+	ref := model.ProjectRef{Identifier: "proj"}
+	tagged, err := AddTagToVersion(context.Background(), testConfig, ref, "r1", "abcdef", "abcdef")
+	assert.NoError(err)
+	assert.NotNil(tagged)
+
+	dbVersion, err := version.FindOneId("v1")
+	assert.NoError(err)
+	assert.NotNil(dbVersion.Tag)
+	assert.Equal("r1", dbVersion.Tag.Name)
+	assert.Equal("abcdef", dbVersion.Tag.SHA)
+
+	tagged, err = AddTagToVersion(context.Background(), testConfig, ref, "r2", "012345", "012345")
+	assert.Equal(ErrNoVersionForRevision, errors.Cause(err))
+	assert.Nil(tagged)
+}
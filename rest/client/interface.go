@@ -76,21 +76,32 @@ type Communicator interface {
 
 	// Sends a group of log messages to the API Server
 	SendLogMessages(context.Context, TaskData, []apimodels.LogMessage) error
+	// Sends a group of structured log lines to the API Server
+	SendStructuredLogLines(context.Context, TaskData, []apimodels.StructuredLogLine) error
 	SendProcessInfo(context.Context, TaskData, []*message.ProcessInfo) error
 	SendSystemInfo(context.Context, TaskData, *message.SystemInfo) error
 
 	// The following operations use the legacy API server and are
 	// used by task commands.
 	SendTestResults(context.Context, TaskData, *task.LocalTestResults) error
+	// SendTestReport sends a raw JUnit XML or TAP report, which the api
+	// server converts into test results.
+	SendTestReport(context.Context, TaskData, *apimodels.TestReport) error
 	SendTestLog(context.Context, TaskData, *model.TestLog) (string, error)
 	GetTaskPatch(context.Context, TaskData) (*patchmodel.Patch, error)
 	GetPatchFile(context.Context, TaskData, string) (string, error)
 
 	// The following operations are used by
 	AttachFiles(context.Context, TaskData, []*artifact.File) error
+	// CreateArtifactUploads returns signed URLs that upload the files to the
+	// artifacts bucket.
+	CreateArtifactUploads(context.Context, TaskData, []apimodels.ArtifactUploadRequest) ([]apimodels.ArtifactUpload, error)
 	GetManifest(context.Context, TaskData) (*manifest.Manifest, error)
 	S3Copy(context.Context, TaskData, *apimodels.S3CopyRequest) error
 	KeyValInc(context.Context, TaskData, *model.KeyVal) error
+	// SetTaskCheckpoint records progress of the running task, which it
+	// resumes from if its host is reclaimed.
+	SetTaskCheckpoint(context.Context, TaskData, string) (*task.Checkpoint, error)
 
 	// these are for the taskdata/json plugin that saves perf data
 	PostJSONData(context.Context, TaskData, string, interface{}) error
@@ -128,6 +139,7 @@ type Communicator interface {
 	UpdateSettings(context.Context, *restmodel.APIAdminSettings) (*restmodel.APIAdminSettings, error)
 	GetEvents(context.Context, time.Time, int) ([]interface{}, error)
 	RevertSettings(context.Context, string) error
+	MigrateProjectVarsToSecrets(context.Context, string, *restmodel.APIProjectVarsMigration) (*restmodel.APIProjectVarsMigration, error)
 
 	// Host methods
 	GetHostsByUser(context.Context, string) ([]*restmodel.APIHost, error)
@@ -138,6 +150,11 @@ type Communicator interface {
 	TerminateSpawnHost(context.Context, string) error
 	ChangeSpawnHostPassword(context.Context, string, string) error
 	ExtendSpawnHostExpiration(context.Context, string, int) error
+	StopSpawnHost(context.Context, string) error
+	StartSpawnHost(context.Context, string) error
+	AttachVolume(context.Context, string, string, string) error
+	DetachVolume(context.Context, string, string) error
+	TransferSpawnHost(context.Context, string, string) error
 	GetHosts(context.Context, func([]*restmodel.APIHost) error) error
 
 	// Fetch list of distributions evergreen can spawn
@@ -160,4 +177,16 @@ type Communicator interface {
 	// GetSubscriptions fetches the subscriptions for the user defined
 	// in the local evergreen yaml
 	GetSubscriptions(context.Context) ([]event.Subscription, error)
+
+	// Version methods
+	//
+	GetAPIVersion(context.Context, string) (*restmodel.APIVersion, error)
+	GetVersionBuilds(context.Context, string) ([]restmodel.APIBuild, error)
+
+	// StreamStatusEvents passes the version's state transitions, and those
+	// of its builds and tasks, to the handler as the server streams them,
+	// starting after the event with the ID of the cursor. It returns the ID
+	// of the last event passed to the handler, to resume from, when the
+	// server closes the stream or the handler returns false.
+	StreamStatusEvents(context.Context, string, string, func(restmodel.APIStatusEvent) bool) (string, error)
 }
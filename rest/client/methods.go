@@ -295,6 +295,26 @@ func (c *communicatorImpl) SendLogMessages(ctx context.Context, taskData TaskDat
 	return nil
 }
 
+// SendStructuredLogLines posts structured log lines to the api server, which
+// stores them so that they can be filtered by level, component, and time.
+func (c *communicatorImpl) SendStructuredLogLines(ctx context.Context, taskData TaskData, lines []apimodels.StructuredLogLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("structured_log")
+	if _, err := c.retryRequest(ctx, info, lines); err != nil {
+		return errors.Wrapf(err, "problem sending %d structured log lines for task %s", len(lines), taskData.ID)
+	}
+
+	return nil
+}
+
 // SendTaskResults posts a task's results, used by the attach results operations.
 func (c *communicatorImpl) SendTaskResults(ctx context.Context, taskData TaskData, r *task.LocalTestResults) error {
 	if r == nil || len(r.Results) == 0 {
@@ -415,6 +435,23 @@ func (c *communicatorImpl) SendTestResults(ctx context.Context, taskData TaskDat
 	return nil
 }
 
+// SendTestReport posts a raw test report, which the api server converts into
+// test results of the task.
+func (c *communicatorImpl) SendTestReport(ctx context.Context, taskData TaskData, report *apimodels.TestReport) error {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("test_report")
+	resp, err := c.retryRequest(ctx, info, report)
+	if err != nil {
+		return errors.Wrapf(err, "failed to post %s test report for task %s", report.Format, taskData.ID)
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
 // AttachFiles attaches task files.
 func (c *communicatorImpl) AttachFiles(ctx context.Context, taskData TaskData, taskFiles []*artifact.File) error {
 	if len(taskFiles) == 0 {
@@ -436,6 +473,33 @@ func (c *communicatorImpl) AttachFiles(ctx context.Context, taskData TaskData, t
 	return nil
 }
 
+// CreateArtifactUploads requests URLs that upload the files to the artifacts
+// bucket from the api server, which signs them with its own credentials.
+func (c *communicatorImpl) CreateArtifactUploads(ctx context.Context, taskData TaskData, reqs []apimodels.ArtifactUploadRequest) ([]apimodels.ArtifactUpload, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("artifacts/upload_urls")
+	resp, err := c.retryRequest(ctx, info, reqs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem creating artifact uploads for task %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	uploads := []apimodels.ArtifactUpload{}
+	if err = util.ReadJSONInto(resp.Body, &uploads); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing artifact uploads for task %s", taskData.ID)
+	}
+
+	return uploads, nil
+}
+
 func (c *communicatorImpl) GetManifest(ctx context.Context, taskData TaskData) (*manifest.Manifest, error) {
 	info := requestInfo{
 		method:   get,
@@ -493,6 +557,27 @@ func (c *communicatorImpl) KeyValInc(ctx context.Context, taskData TaskData, kv
 	return nil
 }
 
+func (c *communicatorImpl) SetTaskCheckpoint(ctx context.Context, taskData TaskData, data string) (*task.Checkpoint, error) {
+	info := requestInfo{
+		method:   post,
+		taskData: &taskData,
+		version:  apiVersion1,
+	}
+	info.setTaskPathSuffix("checkpoint")
+	resp, err := c.retryRequest(ctx, info, &apimodels.TaskCheckpoint{Data: data})
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem recording checkpoint for %s", taskData.ID)
+	}
+	defer resp.Body.Close()
+
+	checkpoint := &task.Checkpoint{}
+	if err = util.ReadJSONInto(resp.Body, checkpoint); err != nil {
+		return nil, errors.Wrapf(err, "problem parsing checkpoint response %s", taskData.ID)
+	}
+
+	return checkpoint, nil
+}
+
 func (c *communicatorImpl) PostJSONData(ctx context.Context, taskData TaskData, path string, data interface{}) error {
 	info := requestInfo{
 		method:   post,
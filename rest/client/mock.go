@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -57,12 +58,22 @@ type Mock struct {
 	TaskExecution               int
 	GetSubscriptionsFail        bool
 	CreatedHost                 apimodels.CreateHost
+	// ArtifactUploadURL is the URL that the artifact uploads of the mock
+	// are relative to.
+	ArtifactUploadURL string
 
 	AttachedFiles    map[string][]*artifact.File
 	LogID            string
 	LocalTestResults *task.LocalTestResults
 	TestLogs         []*serviceModel.TestLog
 	TestLogCount     int
+	TestReports      []*apimodels.TestReport
+
+	// Versions, VersionBuilds and StatusEvents are what the version methods
+	// of the mock return, by version ID.
+	Versions      map[string]*model.APIVersion
+	VersionBuilds map[string][]model.APIBuild
+	StatusEvents  []model.APIStatusEvent
 
 	// metrics collection
 	ProcInfo map[string][]*message.ProcessInfo
@@ -70,8 +81,10 @@ type Mock struct {
 
 	// data collected by mocked methods
 	logMessages     map[string][]apimodels.LogMessage
+	logLines        map[string][]apimodels.StructuredLogLine
 	PatchFiles      map[string]string
 	keyVal          map[string]*serviceModel.KeyVal
+	Checkpoints     map[string][]task.Checkpoint
 	LastMessageSent time.Time
 
 	mu sync.RWMutex
@@ -89,8 +102,10 @@ func NewMock(serverURL string) *Mock {
 		timeoutStart:  defaultTimeoutStart,
 		timeoutMax:    defaultTimeoutMax,
 		logMessages:   make(map[string][]apimodels.LogMessage),
+		logLines:      make(map[string][]apimodels.StructuredLogLine),
 		PatchFiles:    make(map[string]string),
 		keyVal:        make(map[string]*serviceModel.KeyVal),
+		Checkpoints:   make(map[string][]task.Checkpoint),
 		ProcInfo:      make(map[string][]*message.ProcessInfo),
 		SysInfo:       make(map[string]*message.SystemInfo),
 		AttachedFiles: make(map[string][]*artifact.File),
@@ -284,6 +299,33 @@ func (c *Mock) GetMockMessages() map[string][]apimodels.LogMessage {
 	return out
 }
 
+// SendStructuredLogLines posts structured log lines to the mock.
+func (c *Mock) SendStructuredLogLines(ctx context.Context, td TaskData, lines []apimodels.StructuredLogLine) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.loggingShouldFail {
+		return errors.New("logging failed")
+	}
+
+	c.logLines[td.ID] = append(c.logLines[td.ID], lines...)
+
+	return nil
+}
+
+// GetMockLogLines returns the mock's structured log lines.
+func (c *Mock) GetMockLogLines() map[string][]apimodels.StructuredLogLine {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	out := map[string][]apimodels.StructuredLogLine{}
+	for k, v := range c.logLines {
+		out[k] = append([]apimodels.StructuredLogLine{}, v...)
+	}
+
+	return out
+}
+
 // GetLoggerProducer constructs a single channel log producer.
 func (c *Mock) GetLoggerProducer(ctx context.Context, td TaskData) LoggerProducer {
 	return NewSingleChannelLogHarness(td.ID, newLogSender(ctx, c, apimodels.AgentLogPrefix, td))
@@ -345,6 +387,26 @@ func (*Mock) ExtendSpawnHostExpiration(context.Context, string, int) error {
 	return errors.New("(*Mock) ExtendSpawnHostExpiration is not implemented")
 }
 
+func (*Mock) StopSpawnHost(context.Context, string) error {
+	return errors.New("(*Mock) StopSpawnHost is not implemented")
+}
+
+func (*Mock) StartSpawnHost(context.Context, string) error {
+	return errors.New("(*Mock) StartSpawnHost is not implemented")
+}
+
+func (*Mock) AttachVolume(context.Context, string, string, string) error {
+	return errors.New("(*Mock) AttachVolume is not implemented")
+}
+
+func (*Mock) DetachVolume(context.Context, string, string) error {
+	return errors.New("(*Mock) DetachVolume is not implemented")
+}
+
+func (*Mock) TransferSpawnHost(context.Context, string, string) error {
+	return errors.New("(*Mock) TransferSpawnHost is not implemented")
+}
+
 // GetHosts will return an array with a single mock host
 func (c *Mock) GetHosts(ctx context.Context, f func([]*model.APIHost) error) error {
 	hosts := make([]*model.APIHost, 1)
@@ -371,6 +433,10 @@ func (c *Mock) GetEvents(ctx context.Context, ts time.Time, limit int) ([]interf
 }
 func (c *Mock) RevertSettings(ctx context.Context, guid string) error { return nil }
 
+func (c *Mock) MigrateProjectVarsToSecrets(ctx context.Context, projectID string, req *model.APIProjectVarsMigration) (*model.APIProjectVarsMigration, error) {
+	return req, nil
+}
+
 // SendResults posts a set of test results for the communicator's task.
 // If results are empty or nil, this operation is a noop.
 func (c *Mock) SendTestResults(ctx context.Context, td TaskData, results *task.LocalTestResults) error {
@@ -378,6 +444,15 @@ func (c *Mock) SendTestResults(ctx context.Context, td TaskData, results *task.L
 	return nil
 }
 
+// SendTestReport records the test report.
+func (c *Mock) SendTestReport(ctx context.Context, td TaskData, report *apimodels.TestReport) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.TestReports = append(c.TestReports, report)
+	return nil
+}
+
 // SendFiles attaches task files.
 func (c *Mock) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifact.File) error {
 	c.mu.Lock()
@@ -389,6 +464,30 @@ func (c *Mock) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifa
 	return nil
 }
 
+// CreateArtifactUploads returns uploads to the mock's artifact upload URL.
+func (c *Mock) CreateArtifactUploads(ctx context.Context, td TaskData, reqs []apimodels.ArtifactUploadRequest) ([]apimodels.ArtifactUpload, error) {
+	uploads := make([]apimodels.ArtifactUpload, 0, len(reqs))
+	for _, req := range reqs {
+		key := fmt.Sprintf("%s/%s", td.ID, req.Name)
+		headers := map[string]string{"Content-Length": strconv.FormatInt(req.Size, 10)}
+		if req.ContentType != "" {
+			headers["Content-Type"] = req.ContentType
+		}
+		if req.MD5 != "" {
+			headers["Content-Md5"] = req.MD5
+		}
+		uploads = append(uploads, apimodels.ArtifactUpload{
+			Name:    req.Name,
+			URL:     fmt.Sprintf("%s/%s", c.ArtifactUploadURL, key),
+			Headers: headers,
+			Link:    fmt.Sprintf("%s/%s", c.ArtifactUploadURL, key),
+			Bucket:  "mock",
+			Key:     key,
+		})
+	}
+	return uploads, nil
+}
+
 // SendTestLog posts a test log for a communicator's task. Is a
 // noop if the test Log is nil.
 func (c *Mock) SendTestLog(ctx context.Context, td TaskData, log *serviceModel.TestLog) (string, error) {
@@ -417,6 +516,19 @@ func (c *Mock) KeyValInc(ctx context.Context, td TaskData, kv *serviceModel.KeyV
 	return nil
 }
 
+func (c *Mock) SetTaskCheckpoint(ctx context.Context, td TaskData, data string) (*task.Checkpoint, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	checkpoint := task.Checkpoint{
+		Sequence:   len(c.Checkpoints[td.ID]) + 1,
+		Data:       data,
+		CreateTime: time.Now(),
+	}
+	c.Checkpoints[td.ID] = append(c.Checkpoints[td.ID], checkpoint)
+	return &checkpoint, nil
+}
+
 func (c *Mock) PostJSONData(ctx context.Context, td TaskData, path string, data interface{}) error {
 	return nil
 }
@@ -556,3 +668,37 @@ func (c *Mock) GetSubscriptions(_ context.Context) ([]event.Subscription, error)
 		},
 	}, nil
 }
+
+func (c *Mock) GetAPIVersion(_ context.Context, versionID string) (*model.APIVersion, error) {
+	v, ok := c.Versions[versionID]
+	if !ok {
+		return nil, errors.Errorf("version '%s' not found", versionID)
+	}
+	return v, nil
+}
+
+func (c *Mock) GetVersionBuilds(_ context.Context, versionID string) ([]model.APIBuild, error) {
+	return c.VersionBuilds[versionID], nil
+}
+
+// StreamStatusEvents passes the version's events after the one with the ID
+// of the cursor to the handler, as if the server then closed the stream.
+func (c *Mock) StreamStatusEvents(_ context.Context, versionID, cursor string, handler func(model.APIStatusEvent) bool) (string, error) {
+	start := 0
+	for i := range c.StatusEvents {
+		if model.FromAPIString(c.StatusEvents[i].ID) == cursor {
+			start = i + 1
+		}
+	}
+
+	for _, e := range c.StatusEvents[start:] {
+		if model.FromAPIString(e.Version) != versionID {
+			continue
+		}
+		cursor = model.FromAPIString(e.ID)
+		if !handler(e) {
+			break
+		}
+	}
+	return cursor, nil
+}
@@ -1,11 +1,16 @@
 package client
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -158,6 +163,60 @@ func (c *communicatorImpl) ExtendSpawnHostExpiration(ctx context.Context, hostID
 	return nil
 }
 
+func (c *communicatorImpl) StopSpawnHost(ctx context.Context, hostID string) error {
+	return errors.WithStack(c.modifySpawnHost(ctx, hostID, "stop", "", "stopping host"))
+}
+
+func (c *communicatorImpl) StartSpawnHost(ctx context.Context, hostID string) error {
+	return errors.WithStack(c.modifySpawnHost(ctx, hostID, "start", "", "starting host"))
+}
+
+func (c *communicatorImpl) AttachVolume(ctx context.Context, hostID, volumeID, deviceName string) error {
+	body := model.APIVolumeAttachment{
+		VolumeID:   model.ToAPIString(volumeID),
+		DeviceName: model.ToAPIString(deviceName),
+	}
+	return errors.WithStack(c.modifySpawnHost(ctx, hostID, "attach", body, "attaching volume"))
+}
+
+func (c *communicatorImpl) DetachVolume(ctx context.Context, hostID, volumeID string) error {
+	body := model.APIVolumeAttachment{
+		VolumeID: model.ToAPIString(volumeID),
+	}
+	return errors.WithStack(c.modifySpawnHost(ctx, hostID, "detach", body, "detaching volume"))
+}
+
+func (c *communicatorImpl) TransferSpawnHost(ctx context.Context, hostID, newOwner string) error {
+	body := model.APISpawnHostModify{
+		NewOwner: model.ToAPIString(newOwner),
+	}
+	return errors.WithStack(c.modifySpawnHost(ctx, hostID, "transfer", body, "transferring host"))
+}
+
+// modifySpawnHost posts the body to the action's route for the spawn host,
+// describing the action as the operation in errors.
+func (c *communicatorImpl) modifySpawnHost(ctx context.Context, hostID, action string, body interface{}, operation string) error {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("hosts/%s/%s", hostID, action),
+		version: apiVersion2,
+	}
+	resp, err := c.request(ctx, info, body)
+	if err != nil {
+		return errors.Wrapf(err, "error sending request for %s", operation)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err := util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Wrapf(err, "problem %s and parsing error message", operation)
+		}
+		return errors.Wrapf(errMsg, "problem %s", operation)
+	}
+	return nil
+}
+
 // GetHosts gathers all active hosts and invokes a function on them
 func (c *communicatorImpl) GetHosts(ctx context.Context, f func([]*model.APIHost) error) error {
 	info := requestInfo{
@@ -379,6 +438,33 @@ func (c *communicatorImpl) RevertSettings(ctx context.Context, guid string) erro
 	return nil
 }
 
+func (c *communicatorImpl) MigrateProjectVarsToSecrets(ctx context.Context, projectID string, req *model.APIProjectVarsMigration) (*model.APIProjectVarsMigration, error) {
+	info := requestInfo{
+		method:  post,
+		version: apiVersion2,
+		path:    fmt.Sprintf("admin/project_vars/%s/migrate_secrets", projectID),
+	}
+	resp, err := c.request(ctx, info, req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error migrating variables of project '%s'", projectID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Wrap(err, "problem migrating project variables and parsing error message")
+		}
+		return nil, errors.Wrap(errMsg, "problem migrating project variables")
+	}
+
+	out := &model.APIProjectVarsMigration{}
+	if err = util.ReadJSONInto(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "error parsing response")
+	}
+
+	return out, nil
+}
+
 func (c *communicatorImpl) GetDistrosList(ctx context.Context) ([]model.APIDistro, error) {
 	info := requestInfo{
 		method:  get,
@@ -613,3 +699,135 @@ func (c *communicatorImpl) GetSubscriptions(ctx context.Context) ([]event.Subscr
 
 	return subs, nil
 }
+
+func (c *communicatorImpl) GetAPIVersion(ctx context.Context, versionID string) (*model.APIVersion, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("versions/%s", versionID),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching version '%s'", versionID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Wrapf(err, "problem fetching version '%s' and parsing error message", versionID)
+		}
+		return nil, errors.Wrapf(errMsg, "problem fetching version '%s'", versionID)
+	}
+
+	v := &model.APIVersion{}
+	if err = util.ReadJSONInto(resp.Body, v); err != nil {
+		return nil, errors.Wrapf(err, "error parsing version '%s'", versionID)
+	}
+
+	return v, nil
+}
+
+func (c *communicatorImpl) GetVersionBuilds(ctx context.Context, versionID string) ([]model.APIBuild, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("versions/%s/builds", versionID),
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching builds of version '%s'", versionID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Wrapf(err, "problem fetching builds of version '%s' and parsing error message", versionID)
+		}
+		return nil, errors.Wrapf(errMsg, "problem fetching builds of version '%s'", versionID)
+	}
+
+	builds := []model.APIBuild{}
+	if err = util.ReadJSONInto(resp.Body, &builds); err != nil {
+		return nil, errors.Wrapf(err, "error parsing builds of version '%s'", versionID)
+	}
+
+	return builds, nil
+}
+
+func (c *communicatorImpl) StreamStatusEvents(ctx context.Context, versionID, cursor string, handler func(model.APIStatusEvent) bool) (string, error) {
+	path := fmt.Sprintf("events/stream?version=%s", url.QueryEscape(versionID))
+	if cursor != "" {
+		path += fmt.Sprintf("&cursor=%s", url.QueryEscape(cursor))
+	}
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    path,
+	}
+
+	resp, err := c.request(ctx, info, nil)
+	if err != nil {
+		return cursor, errors.Wrapf(err, "problem streaming events of version '%s'", versionID)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errMsg := gimlet.ErrorResponse{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return cursor, errors.Wrapf(err, "problem streaming events of version '%s' and parsing error message", versionID)
+		}
+		return cursor, errors.Wrapf(errMsg, "problem streaming events of version '%s'", versionID)
+	}
+
+	return readStatusEvents(resp.Body, cursor, handler)
+}
+
+// readStatusEvents passes each status event of a server-sent event stream to
+// the handler until the stream ends or the handler returns false, and
+// returns the ID of the last event passed to it.
+func readStatusEvents(body io.Reader, cursor string, handler func(model.APIStatusEvent) bool) (string, error) {
+	var id, name string
+	data := &bytes.Buffer{}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			if name == "error" {
+				return cursor, errors.Errorf("event stream failed: %s", data.String())
+			}
+
+			e := model.APIStatusEvent{}
+			if err := json.Unmarshal(data.Bytes(), &e); err != nil {
+				return cursor, errors.Wrap(err, "problem parsing streamed event")
+			}
+			if id != "" {
+				cursor = id
+			}
+			id, name = "", ""
+			data.Reset()
+			if !handler(e) {
+				return cursor, nil
+			}
+		case strings.HasPrefix(line, ":"):
+			// comments keep the stream alive
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+
+	return cursor, errors.Wrap(scanner.Err(), "problem reading event stream")
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadStatusEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	stream := "retry: 1000\n\n" +
+		"id: e1\nevent: task\ndata: {\"id\":\"e1\",\"resource_type\":\"TASK\",\"status\":\"started\"}\n\n" +
+		": keepalive\n\n" +
+		"id: e2\nevent: version\ndata: {\"id\":\"e2\",\"resource_type\":\"VERSION\",\"status\":\"failed\"}\n\n" +
+		"id: e3\nevent: task\ndata: {\"id\":\"e3\",\"resource_type\":\"TASK\",\"status\":\"failed\"}\n\n"
+
+	events := []model.APIStatusEvent{}
+	cursor, err := readStatusEvents(strings.NewReader(stream), "e0", func(e model.APIStatusEvent) bool {
+		events = append(events, e)
+		return true
+	})
+	require.NoError(err)
+	assert.Equal("e3", cursor)
+	require.Len(events, 3)
+	assert.Equal("VERSION", model.FromAPIString(events[1].ResourceType))
+	assert.Equal("failed", model.FromAPIString(events[1].Status))
+
+	events = events[:0]
+	cursor, err = readStatusEvents(strings.NewReader(stream), "e0", func(e model.APIStatusEvent) bool {
+		events = append(events, e)
+		return model.FromAPIString(e.ResourceType) != "VERSION"
+	})
+	require.NoError(err)
+	assert.Equal("e2", cursor)
+	assert.Len(events, 2)
+
+	cursor, err = readStatusEvents(strings.NewReader("event: error\ndata: {\"error\":\"oops\"}\n\n"), "e0", func(model.APIStatusEvent) bool {
+		return true
+	})
+	assert.Error(err)
+	assert.Contains(err.Error(), "oops")
+	assert.Equal("e0", cursor)
+}
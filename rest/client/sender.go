@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -78,8 +79,9 @@ func (s *logSender) Close() error {
 	return s.Base.Close()
 }
 
-func (s *logSender) flush(ctx context.Context, buffer []apimodels.LogMessage) {
+func (s *logSender) flush(ctx context.Context, buffer []apimodels.LogMessage, lines []apimodels.StructuredLogLine) {
 	grip.CatchWarning(s.comm.SendLogMessages(ctx, s.logTaskData, buffer))
+	grip.CatchWarning(s.comm.SendStructuredLogLines(ctx, s.logTaskData, lines))
 
 	if s.updateTimeout {
 		s.comm.UpdateLastMessageTime()
@@ -93,6 +95,7 @@ func (s *logSender) startBackgroundSender(ctx context.Context) {
 	}
 	timer := time.NewTimer(bufferTime)
 	buffer := []apimodels.LogMessage{}
+	lines := []apimodels.StructuredLogLine{}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	defer timer.Stop()
@@ -104,15 +107,20 @@ backgroundSender:
 			return
 		case <-timer.C:
 			if len(buffer) > 0 {
-				s.flush(ctx, buffer)
+				s.flush(ctx, buffer, lines)
 				buffer = []apimodels.LogMessage{}
+				lines = []apimodels.StructuredLogLine{}
 			}
 			timer.Reset(bufferTime)
 		case m := <-s.pipe:
 			buffer = append(buffer, s.convertMessage(m))
+			if line, ok := s.convertStructured(m); ok {
+				lines = append(lines, line)
+			}
 			if len(buffer) >= bufferCount/2 {
-				s.flush(ctx, buffer)
+				s.flush(ctx, buffer, lines)
 				buffer = []apimodels.LogMessage{}
+				lines = []apimodels.StructuredLogLine{}
 				timer.Reset(bufferTime)
 			}
 		case <-s.signalEnd:
@@ -128,10 +136,13 @@ backgroundSender:
 	// drain the pipe
 	for msg := range s.pipe {
 		buffer = append(buffer, s.convertMessage(msg))
+		if line, ok := s.convertStructured(msg); ok {
+			lines = append(lines, line)
+		}
 	}
 
 	// send the final batch
-	s.flush(ctx, buffer)
+	s.flush(ctx, buffer, lines)
 
 	// let close return
 	close(s.lastBatch)
@@ -158,6 +169,56 @@ func (s *logSender) convertMessage(m message.Composer) apimodels.LogMessage {
 	}
 }
 
+// convertStructured converts messages made up of key/value pairs into
+// structured log lines. The component of a line is the "component" field of
+// the message if it has one, and otherwise the sender's log channel.
+func (s *logSender) convertStructured(m message.Composer) (apimodels.StructuredLogLine, bool) {
+	fields, ok := m.Raw().(message.Fields)
+	if !ok {
+		return apimodels.StructuredLogLine{}, false
+	}
+
+	line := apimodels.StructuredLogLine{
+		Component: channelComponent(s.logChannel),
+		Level:     m.Priority().String(),
+		Timestamp: time.Now(),
+		Fields:    map[string]interface{}{},
+	}
+	for k, v := range fields {
+		switch k {
+		case message.FieldsMsgName:
+			line.Message = fmt.Sprint(v)
+		case "metadata":
+		case "component":
+			if c, ok := v.(string); ok && c != "" {
+				line.Component = c
+				continue
+			}
+			line.Fields[k] = v
+		default:
+			if err, ok := v.(error); ok {
+				v = err.Error()
+			}
+			line.Fields[k] = v
+		}
+	}
+
+	return line, true
+}
+
+func channelComponent(channel string) string {
+	switch channel {
+	case apimodels.AgentLogPrefix:
+		return "agent"
+	case apimodels.TaskLogPrefix:
+		return "task"
+	case apimodels.SystemLogPrefix:
+		return "system"
+	default:
+		return channel
+	}
+}
+
 func priorityToString(l level.Priority) string {
 	switch l {
 	case level.Trace, level.Debug:
@@ -39,6 +39,44 @@ func TestRestClientLogSenderMessageContents(t *testing.T) {
 	assert.Equal("hello world", m[0].Message)
 }
 
+func TestRestClientLogSenderStructuredLines(t *testing.T) {
+	assert := assert.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	comm := NewMock("url")
+	td := TaskData{ID: "task", Secret: "secret"}
+	s, ok := newLogSender(ctx, comm, apimodels.TaskLogPrefix, td).(*logSender)
+	s.setBufferTime(10 * time.Millisecond)
+	assert.True(ok)
+
+	s.Send(message.NewDefaultMessage(level.Error, "hello world"))
+	s.Send(message.NewFieldsMessage(level.Warning, "disk is full", message.Fields{
+		"path": "/data",
+		"free": 0,
+	}))
+	s.Send(message.NewFields(level.Info, message.Fields{
+		"component": "s3.put",
+		"bucket":    "mciuploads",
+	}))
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(s.Close())
+
+	assert.Len(comm.GetMockMessages()["task"], 3)
+	lines := comm.GetMockLogLines()["task"]
+	if assert.Len(lines, 2) {
+		assert.Equal("task", lines[0].Component)
+		assert.Equal("warning", lines[0].Level)
+		assert.Equal("disk is full", lines[0].Message)
+		assert.Equal(map[string]interface{}{"path": "/data", "free": 0}, lines[0].Fields)
+
+		assert.Equal("s3.put", lines[1].Component)
+		assert.Equal("info", lines[1].Level)
+		assert.Empty(lines[1].Message)
+		assert.Equal(map[string]interface{}{"bucket": "mciuploads"}, lines[1].Fields)
+	}
+}
+
 func TestRestClientLogSenderDoesNotLogInErrorConditions(t *testing.T) {
 	assert := assert.New(t)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/quota"
 	"github.com/evergreen-ci/evergreen/model/user"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/units"
@@ -32,14 +34,49 @@ func (ac *DBAdminConnector) GetBanner() (string, string, error) {
 	return settings.Banner, string(settings.BannerTheme), nil
 }
 
-// SetEvergreenSettings sets the admin settings document in the DB and event logs it
+// GetServiceFlags returns the service flags stored in the DB
+func (ac *DBAdminConnector) GetServiceFlags() (*evergreen.ServiceFlags, error) {
+	flags, err := evergreen.GetServiceFlags()
+	return flags, errors.Wrap(err, "error retrieving service flags from DB")
+}
+
+// SetEvergreenSettings sets the admin settings document in the DB, event logs
+// it, and reloads the settings of this process
 func (ac *DBAdminConnector) SetEvergreenSettings(changes *restModel.APIAdminSettings,
 	oldSettings *evergreen.Settings, u *user.DBUser, persist bool) (*evergreen.Settings, error) {
 
+	newSettings, err := applySettingsChanges(changes, oldSettings)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if persist {
+		err = evergreen.UpdateConfig(&newSettings)
+		if err != nil {
+			return nil, errors.Wrap(err, "error saving new settings")
+		}
+		newSettings.Id = evergreen.ConfigDocID
+		if err = LogConfigChanges(&newSettings, oldSettings, u); err != nil {
+			return nil, errors.Wrap(err, "error logging settings changes")
+		}
+		// other processes pick up the changes from the event log
+		reloaded := newSettings
+		if err = evergreen.GetEnvironment().ReloadSettings(&reloaded); err != nil {
+			return nil, errors.Wrap(err, "error reloading settings")
+		}
+		return &newSettings, nil
+	}
+
+	return &newSettings, nil
+}
+
+// applySettingsChanges returns the settings with the sections and fields
+// that are set in the changes replaced.
+func applySettingsChanges(changes *restModel.APIAdminSettings, oldSettings *evergreen.Settings) (evergreen.Settings, error) {
 	settingsAPI := restModel.NewConfigModel()
 	err := settingsAPI.BuildFromService(oldSettings)
 	if err != nil {
-		return nil, errors.Wrap(err, "error converting existing settings")
+		return evergreen.Settings{}, errors.Wrap(err, "error converting existing settings")
 	}
 	changesReflect := reflect.ValueOf(*changes)
 	settingsReflect := reflect.ValueOf(settingsAPI)
@@ -58,20 +95,9 @@ func (ac *DBAdminConnector) SetEvergreenSettings(changes *restModel.APIAdminSett
 
 	i, err := settingsAPI.ToService()
 	if err != nil {
-		return nil, errors.Wrap(err, "error converting to DB model")
-	}
-	newSettings := i.(evergreen.Settings)
-
-	if persist {
-		err = evergreen.UpdateConfig(&newSettings)
-		if err != nil {
-			return nil, errors.Wrap(err, "error saving new settings")
-		}
-		newSettings.Id = evergreen.ConfigDocID
-		return &newSettings, LogConfigChanges(&newSettings, oldSettings, u)
+		return evergreen.Settings{}, errors.Wrap(err, "error converting to DB model")
 	}
-
-	return &newSettings, nil
+	return i.(evergreen.Settings), nil
 }
 
 func LogConfigChanges(newSettings *evergreen.Settings, oldSettings *evergreen.Settings, u *user.DBUser) error {
@@ -149,6 +175,48 @@ func (ac *DBAdminConnector) SetServiceFlags(flags evergreen.ServiceFlags, u *use
 	return evergreen.SetServiceFlags(flags)
 }
 
+// RotateSenderCredentials saves the new sender credentials in the DB, event
+// logs the change, and rebuilds the senders of this process
+func (ac *DBAdminConnector) RotateSenderCredentials(creds *restModel.APISenderCredentials, u *user.DBUser) (map[string]string, error) {
+	oldSettings, err := evergreen.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving settings from DB")
+	}
+	newSettings := *oldSettings
+	creds.ApplyTo(&newSettings)
+
+	if err = evergreen.UpdateConfig(&newSettings); err != nil {
+		return nil, errors.Wrap(err, "error saving new credentials")
+	}
+	if err = LogConfigChanges(&newSettings, oldSettings, u); err != nil {
+		return nil, errors.Wrap(err, "error logging credential changes")
+	}
+
+	env := evergreen.GetEnvironment()
+	if err = env.RotateSenderCredentials(&newSettings); err != nil {
+		return nil, errors.Wrap(err, "error rotating sender credentials")
+	}
+
+	return senderCredentialVersions(env), nil
+}
+
+func senderCredentialVersions(env evergreen.Environment) map[string]string {
+	versions := map[string]string{}
+	for _, key := range []evergreen.SenderKey{
+		evergreen.SenderGithubStatus,
+		evergreen.SenderEvergreenWebhook,
+		evergreen.SenderSlack,
+		evergreen.SenderJIRAIssue,
+		evergreen.SenderJIRAComment,
+		evergreen.SenderEmail,
+	} {
+		if version := env.GetSenderCredentialVersion(key); version != "" {
+			versions[key.String()] = version
+		}
+	}
+	return versions
+}
+
 // RestartFailedTasks attempts to restart failed tasks that started between 2 times
 func (ac *DBAdminConnector) RestartFailedTasks(queue amboy.Queue, opts model.RestartTaskOptions) (*restModel.RestartTasksResponse, error) {
 	var results model.RestartTaskResults
@@ -196,8 +264,14 @@ func (ac *DBAdminConnector) GetAdminEventLog(before time.Time, n int) ([]restMod
 }
 
 type MockAdminConnector struct {
-	mu           sync.RWMutex
-	MockSettings *evergreen.Settings
+	mu                sync.RWMutex
+	MockSettings      *evergreen.Settings
+	MockEventWebhooks []event.EventWebhook
+	MockServiceKeys   []user.ServiceKey
+	MockProjectQuotas []quota.ProjectQuota
+	MockJobQueues     map[string]amboy.Queue
+	MockIndexReports  []db.IndexReport
+	MockSlowQueries   []db.SlowQuery
 }
 
 // GetEvergreenSettings retrieves the admin settings document from the mock connector
@@ -211,11 +285,32 @@ func (ac *MockAdminConnector) GetBanner() (string, string, error) {
 	return ac.MockSettings.Banner, string(ac.MockSettings.BannerTheme), nil
 }
 
+// GetServiceFlags returns the service flags of the mock settings
+func (ac *MockAdminConnector) GetServiceFlags() (*evergreen.ServiceFlags, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+	if ac.MockSettings == nil {
+		return &evergreen.ServiceFlags{}, nil
+	}
+	flags := ac.MockSettings.ServiceFlags
+	return &flags, nil
+}
+
 // SetEvergreenSettings sets the admin settings document in the mock connector
 func (ac *MockAdminConnector) SetEvergreenSettings(changes *restModel.APIAdminSettings,
 	oldSettings *evergreen.Settings, u *user.DBUser, persist bool) (*evergreen.Settings, error) {
 	ac.mu.Lock()
 	defer ac.mu.Unlock()
+	if oldSettings != nil {
+		newSettings, err := applySettingsChanges(changes, oldSettings)
+		if err != nil {
+			return nil, err
+		}
+		if persist {
+			ac.MockSettings = &newSettings
+		}
+		return &newSettings, nil
+	}
 	i, err := changes.ToService()
 	if err != nil {
 		return nil, err
@@ -255,6 +350,22 @@ func (ac *MockAdminConnector) SetServiceFlags(flags evergreen.ServiceFlags, u *u
 	return nil
 }
 
+// RotateSenderCredentials applies the new sender credentials to the mock
+// settings, and reports the email and slack senders as rotated
+func (ac *MockAdminConnector) RotateSenderCredentials(creds *restModel.APISenderCredentials, u *user.DBUser) (map[string]string, error) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	if ac.MockSettings == nil {
+		ac.MockSettings = &evergreen.Settings{}
+	}
+	creds.ApplyTo(ac.MockSettings)
+
+	return map[string]string{
+		evergreen.SenderEmail.String(): "mock",
+		evergreen.SenderSlack.String(): "mock",
+	}, nil
+}
+
 // RestartFailedTasks mocks a response to restarting failed tasks
 func (ac *MockAdminConnector) RestartFailedTasks(queue amboy.Queue, opts model.RestartTaskOptions) (*restModel.RestartTasksResponse, error) {
 	return &restModel.RestartTasksResponse{
@@ -1,7 +1,13 @@
 package data
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
 )
 
 // DBAliasConnector is a struct that implements the Alias related methods
@@ -20,11 +26,113 @@ func (d *DBAliasConnector) FindProjectAliases(projectId string) ([]model.Project
 	return aliases, nil
 }
 
+// UpdateProjectAlias replaces the definitions of the named alias in the
+// project.
+func (d *DBAliasConnector) UpdateProjectAlias(projectId, alias string, aliases []model.ProjectAlias) error {
+	for i := range aliases {
+		aliases[i].Alias = alias
+	}
+	if errs := model.ValidateProjectAliases(aliases); len(errs) > 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid definitions of alias '%s': %v", alias, errs),
+		}
+	}
+	return errors.WithStack(model.ReplaceProjectAliasesByName(projectId, alias, aliases))
+}
+
+// DeleteProjectAlias removes every definition of the named alias from the
+// project.
+func (d *DBAliasConnector) DeleteProjectAlias(projectId, alias string) error {
+	return errors.WithStack(model.RemoveProjectAliasesByName(projectId, alias))
+}
+
+// ResolveProjectAlias loads the project's configuration at the revision, or
+// its last known good configuration if the revision is empty, and returns the
+// variants and tasks that a patch created with the alias would run.
+func (d *DBAliasConnector) ResolveProjectAlias(ref *model.ProjectRef, revision, alias string) ([]patch.VariantTasks, error) {
+	aliases, err := model.FindAliasInProject(ref.Identifier, alias)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(aliases) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("alias '%s' is not defined for project '%s'", alias, ref.Identifier),
+		}
+	}
+
+	project, err := model.FindProject(revision, ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem loading configuration of project '%s'", ref.Identifier)
+	}
+	if project == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no configuration for project '%s'", ref.Identifier),
+		}
+	}
+
+	return project.ResolveProjectAlias(alias), nil
+}
+
 // MockAliasConnector is a struct that implements mock versions of
 // Alias-related methods for testing.
-type MockAliasConnector struct{}
+type MockAliasConnector struct {
+	CachedAliases         []model.ProjectAlias
+	CachedResolvedAliases map[string][]patch.VariantTasks
+}
 
 // FindAllAliases is a mock implementation for testing.
 func (d *MockAliasConnector) FindProjectAliases(projectId string) ([]model.ProjectAlias, error) {
-	return nil, nil
+	var aliases []model.ProjectAlias
+	for _, alias := range d.CachedAliases {
+		if alias.ProjectID == projectId {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases, nil
+}
+
+func (d *MockAliasConnector) UpdateProjectAlias(projectId, alias string, aliases []model.ProjectAlias) error {
+	for i := range aliases {
+		aliases[i].Alias = alias
+	}
+	if errs := model.ValidateProjectAliases(aliases); len(errs) > 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid definitions of alias '%s': %v", alias, errs),
+		}
+	}
+	if err := d.DeleteProjectAlias(projectId, alias); err != nil {
+		return err
+	}
+	for _, a := range aliases {
+		a.ProjectID = projectId
+		d.CachedAliases = append(d.CachedAliases, a)
+	}
+	return nil
+}
+
+func (d *MockAliasConnector) DeleteProjectAlias(projectId, alias string) error {
+	remaining := []model.ProjectAlias{}
+	for _, a := range d.CachedAliases {
+		if a.ProjectID != projectId || a.Alias != alias {
+			remaining = append(remaining, a)
+		}
+	}
+	d.CachedAliases = remaining
+	return nil
+}
+
+// ResolveProjectAlias returns the cached variants and tasks of the alias.
+func (d *MockAliasConnector) ResolveProjectAlias(ref *model.ProjectRef, revision, alias string) ([]patch.VariantTasks, error) {
+	resolved, ok := d.CachedResolvedAliases[alias]
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("alias '%s' is not defined for project '%s'", alias, ref.Identifier),
+		}
+	}
+	return resolved, nil
 }
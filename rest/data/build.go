@@ -5,10 +5,15 @@ import (
 
 	"fmt"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 )
 
 // DBBuildConnector is a struct that implements the Build related methods
@@ -37,6 +42,43 @@ func (bc *DBBuildConnector) FindProjectByBranch(branch string) (*model.ProjectRe
 	return model.FindOneProjectRef(branch)
 }
 
+// FindLatestMainlineStatus returns the status of the project's latest
+// finished mainline version, or of the variant's latest finished mainline
+// build if the variant isn't empty.
+func (bc *DBBuildConnector) FindLatestMainlineStatus(projectId, variant string) (string, error) {
+	finished := bson.M{"$in": []string{evergreen.BuildSucceeded, evergreen.BuildFailed}}
+	requesters := bson.M{"$in": evergreen.SystemVersionRequesterTypes}
+
+	if variant == "" {
+		v, err := version.FindOne(db.Query(bson.M{
+			version.IdentifierKey: projectId,
+			version.RequesterKey:  requesters,
+			version.StatusKey:     finished,
+		}).Sort([]string{"-" + version.RevisionOrderNumberKey}).WithFields(version.StatusKey))
+		if err != nil {
+			return "", errors.Wrapf(err, "problem finding latest version of project '%s'", projectId)
+		}
+		if v == nil {
+			return "", nil
+		}
+		return v.Status, nil
+	}
+
+	b, err := build.FindOne(db.Query(bson.M{
+		build.ProjectKey:      projectId,
+		build.BuildVariantKey: variant,
+		build.RequesterKey:    requesters,
+		build.StatusKey:       finished,
+	}).Sort([]string{"-" + build.RevisionOrderNumberKey}).WithFields(build.StatusKey))
+	if err != nil {
+		return "", errors.Wrapf(err, "problem finding latest build of variant '%s' in project '%s'", variant, projectId)
+	}
+	if b == nil {
+		return "", nil
+	}
+	return b.Status, nil
+}
+
 // AbortBuild wraps the service level AbortBuild
 func (bc *DBBuildConnector) AbortBuild(buildId string, user string) error {
 	return model.AbortBuild(buildId, user)
@@ -92,6 +134,33 @@ func (bc *MockBuildConnector) FindProjectByBranch(branch string) (*model.Project
 	return proj, nil
 }
 
+// FindLatestMainlineStatus returns the status of the variant's cached
+// mainline build with the highest revision order number. Without a
+// variant, the cached builds of the highest revision order number stand in
+// for their version, which failed if any of them failed.
+func (bc *MockBuildConnector) FindLatestMainlineStatus(projectId, variant string) (string, error) {
+	status := ""
+	order := -1
+	for _, b := range bc.CachedBuilds {
+		if b.Project != projectId || (variant != "" && b.BuildVariant != variant) {
+			continue
+		}
+		if !util.StringSliceContains(evergreen.SystemVersionRequesterTypes, b.Requester) {
+			continue
+		}
+		if b.Status != evergreen.BuildSucceeded && b.Status != evergreen.BuildFailed {
+			continue
+		}
+		if b.RevisionOrderNumber > order {
+			order = b.RevisionOrderNumber
+			status = b.Status
+		} else if b.RevisionOrderNumber == order && b.Status == evergreen.BuildFailed {
+			status = b.Status
+		}
+	}
+	return status, nil
+}
+
 // AbortBuild sets the value of the input build Id in CachedAborted to true.
 func (bc *MockBuildConnector) AbortBuild(buildId string, user string) error {
 	if bc.FailOnAbort {
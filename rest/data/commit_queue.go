@@ -0,0 +1,178 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DBCommitQueueConnector is a struct that implements the commit queue
+// related methods from the Connector through interactions with the backing
+// database.
+type DBCommitQueueConnector struct{}
+
+// GetCommitQueue returns the commit queue of the project.
+func (c *DBCommitQueueConnector) GetCommitQueue(projectID string) (*restModel.APICommitQueue, error) {
+	cq, err := commitqueue.FindOneId(projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't find commit queue for '%s'", projectID)
+	}
+	if cq == nil {
+		if _, err = findCommitQueueProject(projectID); err != nil {
+			return nil, err
+		}
+		cq = &commitqueue.CommitQueue{ProjectID: projectID}
+	}
+
+	apiCommitQueue := &restModel.APICommitQueue{}
+	if err = apiCommitQueue.BuildFromService(cq); err != nil {
+		return nil, errors.Wrap(err, "can't build API commit queue")
+	}
+
+	return apiCommitQueue, nil
+}
+
+// EnqueueItem adds a pull request number or a patch ID to the back of the
+// project's commit queue, returning its position.
+func (c *DBCommitQueueConnector) EnqueueItem(projectID string, item restModel.APICommitQueueItem) (int, error) {
+	ref, err := findCommitQueueProject(projectID)
+	if err != nil {
+		return -1, err
+	}
+	issue := restModel.FromAPIString(item.Issue)
+	if _, err = strconv.Atoi(issue); err != nil {
+		if !bson.IsObjectIdHex(issue) {
+			return -1, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("'%s' is neither a pull request number nor a patch ID", issue),
+			}
+		}
+		var p *patch.Patch
+		p, err = patch.FindOne(patch.ById(bson.ObjectIdHex(issue)))
+		if err != nil {
+			return -1, errors.Wrapf(err, "can't find patch '%s'", issue)
+		}
+		if p == nil || p.Project != ref.Identifier {
+			return -1, gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("patch '%s' not found in project '%s'", issue, projectID),
+			}
+		}
+	}
+
+	cq, err := commitqueue.FindOneId(projectID)
+	if err != nil {
+		return -1, errors.Wrapf(err, "can't find commit queue for '%s'", projectID)
+	}
+	if cq == nil {
+		cq = &commitqueue.CommitQueue{ProjectID: projectID, Queue: []commitqueue.CommitQueueItem{}}
+		if err = commitqueue.InsertQueue(cq); err != nil {
+			return -1, errors.Wrapf(err, "can't create commit queue for '%s'", projectID)
+		}
+	}
+
+	position, err := cq.Enqueue(commitqueue.CommitQueueItem{Issue: issue, Author: restModel.FromAPIString(item.Author)})
+	if err != nil {
+		if cq.FindItem(issue) >= 0 {
+			return -1, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			}
+		}
+		return -1, errors.WithStack(err)
+	}
+
+	return position, nil
+}
+
+// CommitQueueRemoveItem removes the item from the project's commit queue,
+// returning false if it wasn't in the queue.
+func (c *DBCommitQueueConnector) CommitQueueRemoveItem(projectID, issue string) (bool, error) {
+	cq, err := commitqueue.FindOneId(projectID)
+	if err != nil {
+		return false, errors.Wrapf(err, "can't find commit queue for '%s'", projectID)
+	}
+	if cq == nil {
+		return false, nil
+	}
+
+	return cq.Remove(issue, commitqueue.StatusRemoved)
+}
+
+func findCommitQueueProject(projectID string) (*model.ProjectRef, error) {
+	ref, err := model.FindOneProjectRef(projectID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't find project '%s'", projectID)
+	}
+	if ref == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", projectID),
+		}
+	}
+	if !ref.CommitQueue.Enabled {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("commit queue is not enabled for project '%s'", projectID),
+		}
+	}
+
+	return ref, nil
+}
+
+// MockCommitQueueConnector keeps the items of each project's commit queue in
+// memory.
+type MockCommitQueueConnector struct {
+	Queue map[string][]restModel.APICommitQueueItem
+}
+
+func (c *MockCommitQueueConnector) GetCommitQueue(projectID string) (*restModel.APICommitQueue, error) {
+	queue := c.Queue[projectID]
+	if queue == nil {
+		queue = []restModel.APICommitQueueItem{}
+	}
+
+	return &restModel.APICommitQueue{
+		ProjectID: restModel.ToAPIString(projectID),
+		Queue:     queue,
+	}, nil
+}
+
+func (c *MockCommitQueueConnector) EnqueueItem(projectID string, item restModel.APICommitQueueItem) (int, error) {
+	if c.Queue == nil {
+		c.Queue = map[string][]restModel.APICommitQueueItem{}
+	}
+	for _, queued := range c.Queue[projectID] {
+		if restModel.FromAPIString(queued.Issue) == restModel.FromAPIString(item.Issue) {
+			return -1, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("item '%s' is already in the commit queue for '%s'", restModel.FromAPIString(item.Issue), projectID),
+			}
+		}
+	}
+
+	item.EnqueueTime = restModel.NewTime(time.Now())
+	c.Queue[projectID] = append(c.Queue[projectID], item)
+
+	return len(c.Queue[projectID]) - 1, nil
+}
+
+func (c *MockCommitQueueConnector) CommitQueueRemoveItem(projectID, issue string) (bool, error) {
+	for i, item := range c.Queue[projectID] {
+		if restModel.FromAPIString(item.Issue) == issue {
+			c.Queue[projectID] = append(c.Queue[projectID][:i], c.Queue[projectID][i+1:]...)
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+)
+
+func (ac *DBAdminConnector) GetIndexReports() ([]restModel.APIIndexReport, error) {
+	reports, err := db.GetIndexReports()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem building index reports")
+	}
+
+	return buildAPIIndexReports(reports)
+}
+
+func (ac *DBAdminConnector) GetSlowQueries() ([]restModel.APISlowQuery, error) {
+	return buildAPISlowQueries(db.GetSlowQueries())
+}
+
+func (ac *DBAdminConnector) ResetQuerySamples() error {
+	db.ResetQuerySamples()
+	return nil
+}
+
+func buildAPIIndexReports(reports []db.IndexReport) ([]restModel.APIIndexReport, error) {
+	out := make([]restModel.APIIndexReport, 0, len(reports))
+	for _, r := range reports {
+		apiReport := restModel.APIIndexReport{}
+		if err := apiReport.BuildFromService(r); err != nil {
+			return nil, errors.Wrap(err, "problem building index report")
+		}
+		out = append(out, apiReport)
+	}
+
+	return out, nil
+}
+
+func buildAPISlowQueries(queries []db.SlowQuery) ([]restModel.APISlowQuery, error) {
+	out := make([]restModel.APISlowQuery, 0, len(queries))
+	for _, q := range queries {
+		apiQuery := restModel.APISlowQuery{}
+		if err := apiQuery.BuildFromService(q); err != nil {
+			return nil, errors.Wrap(err, "problem building slow query")
+		}
+		out = append(out, apiQuery)
+	}
+
+	return out, nil
+}
+
+func (ac *MockAdminConnector) GetIndexReports() ([]restModel.APIIndexReport, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	return buildAPIIndexReports(ac.MockIndexReports)
+}
+
+func (ac *MockAdminConnector) GetSlowQueries() ([]restModel.APISlowQuery, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	return buildAPISlowQueries(ac.MockSlowQueries)
+}
+
+func (ac *MockAdminConnector) ResetQuerySamples() error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	ac.MockIndexReports = nil
+	ac.MockSlowQueries = nil
+	return nil
+}
@@ -7,9 +7,12 @@ import (
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
 )
 
 // DBDistroConnector is a struct that implements the Distro related methods
@@ -73,11 +76,100 @@ func (tc *DBDistroConnector) ClearTaskQueue(distroId string) error {
 	return model.ClearTaskQueue(distroId)
 }
 
+// SimulateDistroCapacity simulates the distro's task queue at its current
+// pool size and at the proposed one, returning the estimates in that order.
+func (dc *DBDistroConnector) SimulateDistroCapacity(distroId string, poolSize int) ([]scheduler.CapacityEstimate, error) {
+	d, err := findDistro(distroId)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.SimulateDistroCapacity(d.Id, d.PoolSize, poolSize)
+}
+
+// GetDistroIdlePolicy returns the idle policy of the distro.
+func (dc *DBDistroConnector) GetDistroIdlePolicy(distroId string) (*distro.IdlePolicy, error) {
+	d, err := findDistro(distroId)
+	if err != nil {
+		return nil, err
+	}
+	return &d.IdlePolicy, nil
+}
+
+// SetDistroIdlePolicy validates the idle policy against the distro's pool
+// size and replaces the distro's policy with it.
+func (dc *DBDistroConnector) SetDistroIdlePolicy(distroId string, policy distro.IdlePolicy) error {
+	d, err := findDistro(distroId)
+	if err != nil {
+		return err
+	}
+	if err = policy.Validate(d.PoolSize); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return errors.WithStack(d.SetIdlePolicy(policy))
+}
+
+// RollBackDistroParentImage rolls the distro back to the image its hosts
+// started from before its current baked image was rolled out.
+func (dc *DBDistroConnector) RollBackDistroParentImage(distroId, userId string) (*distro.Distro, error) {
+	d, err := findDistro(distroId)
+	if err != nil {
+		return nil, err
+	}
+	if d.ParentImageRollout.PreviousImage == "" {
+		return nil, noPreviousParentImage(distroId)
+	}
+
+	before := *d
+	settings := map[string]interface{}{}
+	if d.ProviderSettings != nil {
+		for k, v := range *d.ProviderSettings {
+			settings[k] = v
+		}
+	}
+	before.ProviderSettings = &settings
+
+	if err = d.RollBackParentImage(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	event.LogDistroModified(d.Id, userId, before, d)
+
+	return d, nil
+}
+
+func findDistro(distroId string) (*distro.Distro, error) {
+	d, err := distro.FindOne(distro.ById(distroId))
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, distroNotFound(distroId)
+		}
+		return nil, errors.Wrapf(err, "error finding distro with id %s", distroId)
+	}
+	return &d, nil
+}
+
+func distroNotFound(distroId string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("distro '%s' not found", distroId),
+	}
+}
+
+func noPreviousParentImage(distroId string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Message:    fmt.Sprintf("distro '%s' has no previous image to roll back to", distroId),
+	}
+}
+
 // MockDistroConnector is a struct that implements mock versions of
 // Distro-related methods for testing.
 type MockDistroConnector struct {
-	CachedDistros []distro.Distro
-	CachedTasks   []task.Task
+	CachedDistros    []distro.Distro
+	CachedTasks      []task.Task
+	CachedTaskQueues map[string][]model.TaskQueueItem
 }
 
 // FindAllDistros is a mock implementation for testing.
@@ -126,3 +218,72 @@ func (mdc *MockDistroConnector) FindCostByDistroId(distroId string,
 func (mdc *MockDistroConnector) ClearTaskQueue(distroId string) error {
 	return errors.New("ClearTaskQueue unimplemented for mock")
 }
+
+// SimulateDistroCapacity simulates the cached task queue of the distro at
+// its current pool size and at the proposed one, with no running tasks.
+func (mdc *MockDistroConnector) SimulateDistroCapacity(distroId string, poolSize int) ([]scheduler.CapacityEstimate, error) {
+	for _, d := range mdc.CachedDistros {
+		if d.Id == distroId {
+			queue := mdc.CachedTaskQueues[distroId]
+			return []scheduler.CapacityEstimate{
+				scheduler.SimulateCapacity(queue, nil, d.PoolSize),
+				scheduler.SimulateCapacity(queue, nil, poolSize),
+			}, nil
+		}
+	}
+	return nil, distroNotFound(distroId)
+}
+
+// GetDistroIdlePolicy returns the idle policy of the cached distro.
+func (mdc *MockDistroConnector) GetDistroIdlePolicy(distroId string) (*distro.IdlePolicy, error) {
+	for _, d := range mdc.CachedDistros {
+		if d.Id == distroId {
+			policy := d.IdlePolicy
+			return &policy, nil
+		}
+	}
+	return nil, distroNotFound(distroId)
+}
+
+// SetDistroIdlePolicy validates the idle policy against the cached distro's
+// pool size and replaces the distro's policy with it.
+func (mdc *MockDistroConnector) SetDistroIdlePolicy(distroId string, policy distro.IdlePolicy) error {
+	for i := range mdc.CachedDistros {
+		if mdc.CachedDistros[i].Id == distroId {
+			if err := policy.Validate(mdc.CachedDistros[i].PoolSize); err != nil {
+				return gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    err.Error(),
+				}
+			}
+			mdc.CachedDistros[i].IdlePolicy = policy
+			return nil
+		}
+	}
+	return distroNotFound(distroId)
+}
+
+// RollBackDistroParentImage rolls the cached distro back to the image its
+// hosts started from before its current baked image was rolled out.
+func (mdc *MockDistroConnector) RollBackDistroParentImage(distroId, userId string) (*distro.Distro, error) {
+	for i := range mdc.CachedDistros {
+		d := &mdc.CachedDistros[i]
+		if d.Id != distroId {
+			continue
+		}
+		if d.ParentImageRollout.PreviousImage == "" {
+			return nil, noPreviousParentImage(distroId)
+		}
+		if d.ProviderSettings == nil {
+			d.ProviderSettings = &map[string]interface{}{}
+		}
+		if current := d.ParentImage(); current != "" {
+			d.ParentImageRollout.RejectedImages = append(d.ParentImageRollout.RejectedImages, current)
+		}
+		(*d.ProviderSettings)[distro.ParentImageSettingsKey] = d.ParentImageRollout.PreviousImage
+		d.ParentImageRollout.PreviousImage = ""
+		d.ParentImageRollout.RolledOutAt = time.Now()
+		return d, nil
+	}
+	return nil, distroNotFound(distroId)
+}
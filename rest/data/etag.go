@@ -0,0 +1,94 @@
+package data
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DBETagConnector computes the entity tags of resources from their
+// documents, which change whenever the resources do. Reading the documents
+// as raw BSON is much cheaper than building and marshaling their API models,
+// and for task lists it avoids the per-task artifact lookups.
+type DBETagConnector struct{}
+
+// VersionETag returns the entity tag of the version, or an empty string if
+// it doesn't exist. The version's project configuration isn't part of its
+// API model, so it's left out.
+func (c *DBETagConnector) VersionETag(versionID string) (string, error) {
+	return hashDocuments(version.Collection, version.ById(versionID).WithoutFields(version.ConfigKey))
+}
+
+// VersionBuildsETag returns the entity tag of the version's builds, or an
+// empty string if it has none.
+func (c *DBETagConnector) VersionBuildsETag(versionID string) (string, error) {
+	return hashDocuments(build.Collection, build.ByVersion(versionID).Sort([]string{build.IdKey}))
+}
+
+// BuildETag returns the entity tag of the build, or an empty string if it
+// doesn't exist.
+func (c *DBETagConnector) BuildETag(buildID string) (string, error) {
+	return hashDocuments(build.Collection, build.ById(buildID))
+}
+
+// BuildTasksETag returns the entity tag of the build's tasks, or an empty
+// string if it has none. Artifacts attached to a running task are picked up
+// when the task next heartbeats.
+func (c *DBETagConnector) BuildTasksETag(buildID string) (string, error) {
+	return hashDocuments(task.Collection, task.ByBuildId(buildID).Sort([]string{task.IdKey}))
+}
+
+// hashDocuments returns a hash of the raw documents matching the query, or
+// an empty string if there are none.
+func hashDocuments(collection string, query db.Q) (string, error) {
+	docs := []bson.Raw{}
+	err := db.FindAllQ(collection, query, &docs)
+	if err != nil && err != mgo.ErrNotFound {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "problem reading documents from '%s'", collection).Error(),
+		}
+	}
+	if len(docs) == 0 {
+		return "", nil
+	}
+
+	hash := sha1.New()
+	for _, doc := range docs {
+		_, _ = hash.Write(doc.Data)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// MockETagConnector serves the entity tags in CachedETags, keyed by the
+// resource type ("version", "version_builds", "build" or "build_tasks") and
+// the resource's ID, separated by a colon.
+type MockETagConnector struct {
+	CachedETags map[string]string
+}
+
+func (c *MockETagConnector) VersionETag(versionID string) (string, error) {
+	return c.CachedETags["version:"+versionID], nil
+}
+
+func (c *MockETagConnector) VersionBuildsETag(versionID string) (string, error) {
+	return c.CachedETags["version_builds:"+versionID], nil
+}
+
+func (c *MockETagConnector) BuildETag(buildID string) (string, error) {
+	return c.CachedETags["build:"+buildID], nil
+}
+
+func (c *MockETagConnector) BuildTasksETag(buildID string) (string, error) {
+	return c.CachedETags["build_tasks:"+buildID], nil
+}
@@ -0,0 +1,62 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestETags(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+	require.NoError(db.ClearCollections(version.Collection, build.Collection, task.Collection))
+
+	require.NoError((&version.Version{Id: "v0", Config: "tasks: []"}).Insert())
+	require.NoError((&build.Build{Id: "b0", Version: "v0"}).Insert())
+	require.NoError((&task.Task{Id: "t0", BuildId: "b0", Status: evergreen.TaskUndispatched}).Insert())
+
+	c := &DBETagConnector{}
+	versionTag, err := c.VersionETag("v0")
+	assert.NoError(err)
+	assert.NotEmpty(versionTag)
+	buildsTag, err := c.VersionBuildsETag("v0")
+	assert.NoError(err)
+	assert.NotEmpty(buildsTag)
+	buildTag, err := c.BuildETag("b0")
+	assert.NoError(err)
+	assert.Equal(buildsTag, buildTag)
+	tasksTag, err := c.BuildTasksETag("b0")
+	assert.NoError(err)
+	assert.NotEmpty(tasksTag)
+
+	// tags are stable until the resources change
+	tag, err := c.BuildTasksETag("b0")
+	assert.NoError(err)
+	assert.Equal(tasksTag, tag)
+	require.NoError(task.UpdateOne(bson.M{task.IdKey: "t0"}, bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskStarted}}))
+	tag, err = c.BuildTasksETag("b0")
+	assert.NoError(err)
+	assert.NotEqual(tasksTag, tag)
+
+	// the config isn't part of the version's model
+	require.NoError(version.UpdateOne(bson.M{version.IdKey: "v0"}, bson.M{"$set": bson.M{version.ConfigKey: "buildvariants: []"}}))
+	tag, err = c.VersionETag("v0")
+	assert.NoError(err)
+	assert.Equal(versionTag, tag)
+
+	// missing resources have no tags
+	tag, err = c.VersionETag("v1")
+	assert.NoError(err)
+	assert.Empty(tag)
+	tag, err = c.BuildTasksETag("b1")
+	assert.NoError(err)
+	assert.Empty(tag)
+}
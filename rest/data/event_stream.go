@@ -0,0 +1,211 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+var (
+	statusEventResourceTypes = []string{
+		event.ResourceTypeVersion,
+		event.ResourceTypeBuild,
+		event.ResourceTypeTask,
+	}
+	// version and build state changes share an event type
+	statusEventTypes = []string{
+		event.VersionStateChange,
+		event.TaskDispatched,
+		event.TaskStarted,
+		event.TaskFinished,
+		event.TaskRestarted,
+	}
+)
+
+// StatusEventFilter selects the status events of a project or a version.
+type StatusEventFilter struct {
+	Project string
+	Version string
+}
+
+func (f *StatusEventFilter) matches(e *restModel.APIStatusEvent) bool {
+	if f.Project != "" && restModel.FromAPIString(e.Project) != f.Project {
+		return false
+	}
+	if f.Version != "" && restModel.FromAPIString(e.Version) != f.Version {
+		return false
+	}
+
+	return true
+}
+
+// DBEventStreamConnector is a struct that implements the event stream
+// related methods from the Connector through interactions with the backing
+// database.
+type DBEventStreamConnector struct{}
+
+// StatusEventCursor returns the position of the event with the given ID,
+// or, if no ID is given, the position of the events logged from now on.
+func (c *DBEventStreamConnector) StatusEventCursor(eventID string) (*event.EventCursor, error) {
+	if eventID == "" {
+		return &event.EventCursor{Timestamp: time.Now()}, nil
+	}
+
+	cursor, err := event.CursorAt(eventID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if cursor == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("event '%s' not found", eventID),
+		}
+	}
+
+	return cursor, nil
+}
+
+// FindStatusEvents scans at most limit events after the cursor, and returns
+// the version, build and task state transitions among them that pass the
+// filter, along with the position of the last event scanned.
+func (c *DBEventStreamConnector) FindStatusEvents(cursor event.EventCursor, filter StatusEventFilter, limit int) ([]restModel.APIStatusEvent, *event.EventCursor, error) {
+	events, err := event.FindEventsAfter(cursor, statusEventResourceTypes, statusEventTypes, limit)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to find status events")
+	}
+	if len(events) == 0 {
+		return nil, &cursor, nil
+	}
+
+	idsByType := map[string][]string{}
+	for _, e := range events {
+		idsByType[e.ResourceType] = append(idsByType[e.ResourceType], e.ResourceId)
+	}
+	resources, err := findStatusEventResources(idsByType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := []restModel.APIStatusEvent{}
+	for i := range events {
+		apiEvent := restModel.APIStatusEvent{}
+		if err = apiEvent.BuildFromService(&events[i]); err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to build status event '%s'", events[i].ID)
+		}
+		resource, ok := resources[events[i].ResourceType][events[i].ResourceId]
+		if !ok {
+			continue
+		}
+		apiEvent.Project = restModel.ToAPIString(resource.Project)
+		apiEvent.Version = restModel.ToAPIString(resource.Version)
+		if resource.BuildVariant != "" {
+			apiEvent.BuildVariant = restModel.ToAPIString(resource.BuildVariant)
+			apiEvent.DisplayName = restModel.ToAPIString(resource.DisplayName)
+		}
+		if filter.matches(&apiEvent) {
+			out = append(out, apiEvent)
+		}
+	}
+
+	last := events[len(events)-1]
+	return out, &event.EventCursor{Timestamp: last.Timestamp, EventID: last.ID}, nil
+}
+
+type statusEventResource struct {
+	Project      string
+	Version      string
+	BuildVariant string
+	DisplayName  string
+}
+
+// findStatusEventResources returns the project, version and display details
+// of the resources with the given IDs, keyed by resource type and ID.
+func findStatusEventResources(idsByType map[string][]string) (map[string]map[string]statusEventResource, error) {
+	out := map[string]map[string]statusEventResource{
+		event.ResourceTypeVersion: {},
+		event.ResourceTypeBuild:   {},
+		event.ResourceTypeTask:    {},
+	}
+
+	if ids := idsByType[event.ResourceTypeVersion]; len(ids) > 0 {
+		versions, err := version.Find(version.ByIds(ids).WithFields(version.IdKey, version.IdentifierKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find versions")
+		}
+		for _, v := range versions {
+			out[event.ResourceTypeVersion][v.Id] = statusEventResource{Project: v.Identifier, Version: v.Id}
+		}
+	}
+	if ids := idsByType[event.ResourceTypeBuild]; len(ids) > 0 {
+		builds, err := build.Find(build.ByIds(ids).WithFields(build.IdKey, build.ProjectKey, build.VersionKey,
+			build.BuildVariantKey, build.DisplayNameKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find builds")
+		}
+		for _, b := range builds {
+			out[event.ResourceTypeBuild][b.Id] = statusEventResource{
+				Project:      b.Project,
+				Version:      b.Version,
+				BuildVariant: b.BuildVariant,
+				DisplayName:  b.DisplayName,
+			}
+		}
+	}
+	if ids := idsByType[event.ResourceTypeTask]; len(ids) > 0 {
+		tasks, err := task.Find(task.ByIds(ids).WithFields(task.IdKey, task.ProjectKey, task.VersionKey,
+			task.BuildVariantKey, task.DisplayNameKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to find tasks")
+		}
+		for _, t := range tasks {
+			out[event.ResourceTypeTask][t.Id] = statusEventResource{
+				Project:      t.Project,
+				Version:      t.Version,
+				BuildVariant: t.BuildVariant,
+				DisplayName:  t.DisplayName,
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// MockEventStreamConnector is a struct that implements the event stream
+// related methods from the Connector through an in-memory list of events.
+type MockEventStreamConnector struct {
+	CachedStatusEvents []restModel.APIStatusEvent
+}
+
+func (c *MockEventStreamConnector) StatusEventCursor(eventID string) (*event.EventCursor, error) {
+	return &event.EventCursor{EventID: eventID}, nil
+}
+
+// FindStatusEvents returns the cached events after the one with the
+// cursor's event ID, or all of them if the cursor has no event ID.
+func (c *MockEventStreamConnector) FindStatusEvents(cursor event.EventCursor, filter StatusEventFilter, limit int) ([]restModel.APIStatusEvent, *event.EventCursor, error) {
+	start := 0
+	for i := range c.CachedStatusEvents {
+		if restModel.FromAPIString(c.CachedStatusEvents[i].ID) == cursor.EventID {
+			start = i + 1
+		}
+	}
+
+	out := []restModel.APIStatusEvent{}
+	next := cursor
+	for i := start; i < len(c.CachedStatusEvents) && i < start+limit; i++ {
+		next = event.EventCursor{EventID: restModel.FromAPIString(c.CachedStatusEvents[i].ID)}
+		if filter.matches(&c.CachedStatusEvents[i]) {
+			out = append(out, c.CachedStatusEvents[i])
+		}
+	}
+
+	return out, &next, nil
+}
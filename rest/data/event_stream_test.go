@@ -0,0 +1,67 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindStatusEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+	require.NoError(db.ClearCollections(event.AllLogCollection, version.Collection, build.Collection, task.Collection))
+
+	require.NoError((&version.Version{Id: "v0", Identifier: "proj"}).Insert())
+	require.NoError((&build.Build{Id: "b0", Version: "v0", Project: "proj", BuildVariant: "bv"}).Insert())
+	require.NoError((&task.Task{Id: "t0", Version: "v0", Project: "proj", BuildVariant: "bv", DisplayName: "compile"}).Insert())
+	require.NoError((&task.Task{Id: "t1", Version: "v1", Project: "other"}).Insert())
+
+	logger := event.NewDBEventLogger(event.AllLogCollection)
+	ts := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	for i, e := range []event.EventLogEntry{
+		{ID: "e0", ResourceType: event.ResourceTypeTask, ResourceId: "t0", EventType: event.TaskStarted, Data: &event.TaskEventData{}},
+		{ID: "e1", ResourceType: event.ResourceTypeTask, ResourceId: "t1", EventType: event.TaskStarted, Data: &event.TaskEventData{}},
+		{ID: "e2", ResourceType: event.ResourceTypeTask, ResourceId: "t0", EventType: event.TaskPriorityChanged, Data: &event.TaskEventData{}},
+		{ID: "e3", ResourceType: event.ResourceTypeTask, ResourceId: "t0", EventType: event.TaskFinished, Data: &event.TaskEventData{Status: "failed"}},
+		{ID: "e4", ResourceType: event.ResourceTypeBuild, ResourceId: "b0", EventType: event.BuildStateChange, Data: &event.BuildEventData{Status: "failed"}},
+		{ID: "e5", ResourceType: event.ResourceTypeVersion, ResourceId: "v0", EventType: event.VersionStateChange, Data: &event.VersionEventData{Status: "failed"}},
+	} {
+		e.Timestamp = ts.Add(time.Duration(i) * time.Second)
+		require.NoError(logger.LogEvent(&e))
+	}
+
+	sc := &DBEventStreamConnector{}
+	cursor, err := sc.StatusEventCursor("e0")
+	require.NoError(err)
+
+	events, next, err := sc.FindStatusEvents(*cursor, StatusEventFilter{Project: "proj"}, 10)
+	assert.NoError(err)
+	require.Len(events, 3)
+	assert.Equal("e3", restModel.FromAPIString(events[0].ID))
+	assert.Equal("failed", restModel.FromAPIString(events[0].Status))
+	assert.Equal("compile", restModel.FromAPIString(events[0].DisplayName))
+	assert.Equal("e4", restModel.FromAPIString(events[1].ID))
+	assert.Equal("v0", restModel.FromAPIString(events[1].Version))
+	assert.Equal("e5", restModel.FromAPIString(events[2].ID))
+	assert.Equal("proj", restModel.FromAPIString(events[2].Project))
+	assert.Equal("e5", next.EventID)
+
+	// the cursor moves past events that don't pass the filter
+	events, next, err = sc.FindStatusEvents(event.EventCursor{Timestamp: ts.Add(-time.Second)}, StatusEventFilter{Version: "v1"}, 2)
+	assert.NoError(err)
+	require.Len(events, 1)
+	assert.Equal("e1", restModel.FromAPIString(events[0].ID))
+	assert.Equal("e1", next.EventID)
+
+	_, err = sc.StatusEventCursor("nonexistent")
+	assert.Error(err)
+}
@@ -0,0 +1,192 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+func (ac *DBAdminConnector) CreateEventWebhook(in *restModel.APIEventWebhook, user string) (*restModel.APIEventWebhook, error) {
+	w, err := newEventWebhook(in, user)
+	if err != nil {
+		return nil, err
+	}
+	if err = w.Insert(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIEventWebhook(w, true)
+}
+
+func (ac *DBAdminConnector) FindEventWebhooks() ([]restModel.APIEventWebhook, error) {
+	webhooks, err := event.FindAllEventWebhooks()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIEventWebhooks(webhooks)
+}
+
+func (ac *DBAdminConnector) DeleteEventWebhook(id string) error {
+	w, err := findEventWebhook(id)
+	if err != nil {
+		return err
+	}
+
+	return errors.WithStack(event.RemoveEventWebhook(w.ID))
+}
+
+func (ac *DBAdminConnector) ReplayEventWebhook(id string, replay *restModel.APIEventWebhookReplay) (*restModel.APIEventWebhook, error) {
+	if err := replay.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	w, err := findEventWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := event.EventCursor{Timestamp: time.Time(replay.Since)}
+	if eventID := restModel.FromAPIString(replay.Cursor); eventID != "" {
+		var found *event.EventCursor
+		found, err = event.CursorAt(eventID)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if found == nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("event '%s' not found", eventID),
+			}
+		}
+		cursor = *found
+	}
+	if err = w.SetCursor(cursor); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIEventWebhook(w, false)
+}
+
+func newEventWebhook(in *restModel.APIEventWebhook, user string) (*event.EventWebhook, error) {
+	w, err := event.NewEventWebhook(restModel.FromAPIString(in.URL), in.ResourceTypes, in.EventTypes, user)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return w, nil
+}
+
+func findEventWebhook(id string) (*event.EventWebhook, error) {
+	w, err := event.FindEventWebhook(id)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if w == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("event webhook '%s' not found", id),
+		}
+	}
+
+	return w, nil
+}
+
+// buildAPIEventWebhook converts the webhook to its API model, which
+// includes its secret only if withSecret is true.
+func buildAPIEventWebhook(w *event.EventWebhook, withSecret bool) (*restModel.APIEventWebhook, error) {
+	apiWebhook := restModel.APIEventWebhook{}
+	if err := apiWebhook.BuildFromService(w); err != nil {
+		return nil, errors.Wrap(err, "failed to build event webhook response")
+	}
+	if withSecret {
+		apiWebhook.Secret = restModel.ToAPIString(string(w.Secret))
+	}
+
+	return &apiWebhook, nil
+}
+
+func buildAPIEventWebhooks(webhooks []event.EventWebhook) ([]restModel.APIEventWebhook, error) {
+	out := make([]restModel.APIEventWebhook, 0, len(webhooks))
+	for i := range webhooks {
+		apiWebhook, err := buildAPIEventWebhook(&webhooks[i], false)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *apiWebhook)
+	}
+
+	return out, nil
+}
+
+func (ac *MockAdminConnector) CreateEventWebhook(in *restModel.APIEventWebhook, user string) (*restModel.APIEventWebhook, error) {
+	w, err := newEventWebhook(in, user)
+	if err != nil {
+		return nil, err
+	}
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.MockEventWebhooks = append(ac.MockEventWebhooks, *w)
+
+	return buildAPIEventWebhook(w, true)
+}
+
+func (ac *MockAdminConnector) FindEventWebhooks() ([]restModel.APIEventWebhook, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	return buildAPIEventWebhooks(ac.MockEventWebhooks)
+}
+
+func (ac *MockAdminConnector) DeleteEventWebhook(id string) error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for i := range ac.MockEventWebhooks {
+		if ac.MockEventWebhooks[i].ID == id {
+			ac.MockEventWebhooks = append(ac.MockEventWebhooks[:i], ac.MockEventWebhooks[i+1:]...)
+			return nil
+		}
+	}
+
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("event webhook '%s' not found", id),
+	}
+}
+
+func (ac *MockAdminConnector) ReplayEventWebhook(id string, replay *restModel.APIEventWebhookReplay) (*restModel.APIEventWebhook, error) {
+	if err := replay.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for i := range ac.MockEventWebhooks {
+		w := &ac.MockEventWebhooks[i]
+		if w.ID != id {
+			continue
+		}
+		w.Cursor = event.EventCursor{
+			Timestamp: time.Time(replay.Since),
+			EventID:   restModel.FromAPIString(replay.Cursor),
+		}
+		return buildAPIEventWebhook(w, false)
+	}
+
+	return nil, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("event webhook '%s' not found", id),
+	}
+}
@@ -0,0 +1,191 @@
+package data
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// exportBatchSize is the number of tasks read at once when exporting task
+// history and test results.
+const exportBatchSize = 500
+
+// ExportFilter selects the mainline tasks of a project, and their test
+// results, whose revisions are between two revision order numbers,
+// inclusive. The other fields don't filter the tasks and tests if empty.
+type ExportFilter struct {
+	Project       string
+	StartOrder    int
+	EndOrder      int
+	BuildVariants []string
+	TaskNames     []string
+	TaskStatuses  []string
+	TestStatuses  []string
+}
+
+func (f *ExportFilter) historyFilter() task.HistoryFilter {
+	return task.HistoryFilter{
+		Project:       f.Project,
+		StartOrder:    f.StartOrder,
+		EndOrder:      f.EndOrder,
+		BuildVariants: f.BuildVariants,
+		DisplayNames:  f.TaskNames,
+		Statuses:      f.TaskStatuses,
+	}
+}
+
+// DBExportConnector is a struct that implements the export related methods
+// from the Connector through interactions with the backing database.
+type DBExportConnector struct{}
+
+// ExportTaskHistory calls fn with each task passing the filter, ordered by
+// revision, reading the tasks in batches so that the whole history is never
+// held in memory. It stops at the first error fn returns.
+func (c *DBExportConnector) ExportTaskHistory(filter ExportFilter, fn func(*restModel.APITaskHistoryRow) error) error {
+	return forEachTaskBatch(filter, func(tasks []task.Task) error {
+		for i := range tasks {
+			row := &restModel.APITaskHistoryRow{}
+			if err := row.BuildFromService(&tasks[i]); err != nil {
+				return errors.Wrap(err, "problem building task history row")
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ExportTestResults calls fn with the results of the latest execution of
+// each task passing the filter, ordered by revision, reading the tasks and
+// their results in batches. It stops at the first error fn returns.
+func (c *DBExportConnector) ExportTestResults(filter ExportFilter, fn func(*restModel.APITestResultRow) error) error {
+	return forEachTaskBatch(filter, func(tasks []task.Task) error {
+		ids := make([]string, 0, len(tasks))
+		for _, t := range tasks {
+			ids = append(ids, t.Id)
+		}
+		results, err := testresult.Find(testresult.ByTaskIDsAndStatuses(ids, filter.TestStatuses))
+		if err != nil {
+			return errors.Wrap(err, "problem finding test results")
+		}
+
+		return exportTestResults(tasks, results, fn)
+	})
+}
+
+func forEachTaskBatch(filter ExportFilter, fn func([]task.Task) error) error {
+	afterOrder, afterID := 0, ""
+	for {
+		tasks, err := task.FindHistoryPage(filter.historyFilter(), afterOrder, afterID, exportBatchSize)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		if err = fn(tasks); err != nil {
+			return err
+		}
+		if len(tasks) < exportBatchSize {
+			return nil
+		}
+		last := tasks[len(tasks)-1]
+		afterOrder, afterID = last.RevisionOrderNumber, last.Id
+	}
+}
+
+// exportTestResults calls fn with the results of the tasks' latest
+// executions, in the order of the tasks, and by test file within a task.
+func exportTestResults(tasks []task.Task, results []testresult.TestResult, fn func(*restModel.APITestResultRow) error) error {
+	byTask := map[string][]testresult.TestResult{}
+	for _, result := range results {
+		byTask[result.TaskID] = append(byTask[result.TaskID], result)
+	}
+
+	for i := range tasks {
+		taskResults := byTask[tasks[i].Id]
+		sort.SliceStable(taskResults, func(a, b int) bool { return taskResults[a].TestFile < taskResults[b].TestFile })
+		for j := range taskResults {
+			if taskResults[j].Execution != tasks[i].Execution {
+				continue
+			}
+			row := &restModel.APITestResultRow{}
+			if err := row.BuildFromService(&tasks[i]); err != nil {
+				return errors.Wrap(err, "problem building test result row")
+			}
+			if err := row.BuildFromService(&taskResults[j]); err != nil {
+				return errors.Wrap(err, "problem building test result row")
+			}
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// MockExportConnector exports the cached tasks and test results that pass
+// the filter.
+type MockExportConnector struct {
+	CachedTasks []task.Task
+	CachedTests []testresult.TestResult
+}
+
+func (c *MockExportConnector) ExportTaskHistory(filter ExportFilter, fn func(*restModel.APITaskHistoryRow) error) error {
+	for _, t := range c.filterTasks(filter) {
+		row := &restModel.APITaskHistoryRow{}
+		if err := row.BuildFromService(&t); err != nil {
+			return errors.Wrap(err, "problem building task history row")
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *MockExportConnector) ExportTestResults(filter ExportFilter, fn func(*restModel.APITestResultRow) error) error {
+	results := []testresult.TestResult{}
+	for _, result := range c.CachedTests {
+		if len(filter.TestStatuses) == 0 || util.StringSliceContains(filter.TestStatuses, result.Status) {
+			results = append(results, result)
+		}
+	}
+	return exportTestResults(c.filterTasks(filter), results, fn)
+}
+
+func (c *MockExportConnector) filterTasks(filter ExportFilter) []task.Task {
+	tasks := []task.Task{}
+	for _, t := range c.CachedTasks {
+		if t.Project != filter.Project || t.RevisionOrderNumber < filter.StartOrder || t.RevisionOrderNumber > filter.EndOrder {
+			continue
+		}
+		if util.StringSliceContains(evergreen.PatchRequesters, t.Requester) {
+			continue
+		}
+		if len(filter.BuildVariants) > 0 && !util.StringSliceContains(filter.BuildVariants, t.BuildVariant) {
+			continue
+		}
+		if len(filter.TaskNames) > 0 && !util.StringSliceContains(filter.TaskNames, t.DisplayName) {
+			continue
+		}
+		if len(filter.TaskStatuses) > 0 && !util.StringSliceContains(filter.TaskStatuses, t.Status) {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].RevisionOrderNumber != tasks[j].RevisionOrderNumber {
+			return tasks[i].RevisionOrderNumber < tasks[j].RevisionOrderNumber
+		}
+		return tasks[i].Id < tasks[j].Id
+	})
+	return tasks
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
@@ -50,6 +51,43 @@ func (hc *DBHostConnector) FindHostById(id string) (*host.Host, error) {
 	return h, nil
 }
 
+// FindCostByHostId queries the backing database for cost data associated
+// with the given hostId. The cost of the tasks that ran on the host,
+// including earlier executions of restarted tasks, is aggregated, and
+// compared against the total cost and runtime recorded for the host.
+func (hc *DBHostConnector) FindCostByHostId(hostId string) (*task.HostCost, error) {
+	h, err := hc.FindHostById(hostId)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	pipeline := task.CostDataByHostIdPipeline(hostId)
+	res := []task.HostCost{}
+	if err = task.Aggregate(pipeline, &res); err != nil {
+		return nil, err
+	}
+	oldRes := []task.HostCost{}
+	if err = task.AggregateOld(pipeline, &oldRes); err != nil {
+		return nil, err
+	}
+	if len(res) > 1 || len(oldRes) > 1 {
+		return nil, errors.Errorf("aggregation query with host_id %s returned %d results but should only return 1 result", hostId, len(res)+len(oldRes))
+	}
+
+	cost := task.HostCost{HostId: hostId}
+	for _, r := range append(res, oldRes...) {
+		cost.Add(r)
+	}
+	cost.DistroId = h.Distro.Id
+	cost.Provider = h.Provider
+	cost.Runtime = h.GetRuntime()
+	cost.CostedTime = h.TotalCostedTime
+	cost.HourlyPrice = h.HourlyPrice
+	cost.TotalCost = h.TotalCost
+
+	return &cost, nil
+}
+
 func (dbc *DBConnector) FindHostByIdWithOwner(hostID string, user gimlet.User) (*host.Host, error) {
 	return findHostByIdWithOwner(dbc, hostID, user)
 }
@@ -90,6 +128,10 @@ func (hc *DBHostConnector) SetHostStatus(host *host.Host, status, user string) e
 	return host.SetStatus(status, user, "")
 }
 
+func (hc *DBHostConnector) SetHostDraining(host *host.Host, draining bool, user string) error {
+	return errors.WithStack(host.SetDraining(draining, user))
+}
+
 func (hc *DBHostConnector) SetHostExpirationTime(host *host.Host, newExp time.Time) error {
 	if err := host.SetExpirationTime(newExp); err != nil {
 		return errors.Wrap(err, "Error extending host expiration time")
@@ -102,10 +144,84 @@ func (hc *DBHostConnector) TerminateHost(ctx context.Context, host *host.Host, u
 	return errors.WithStack(cloud.TerminateSpawnHost(ctx, host, evergreen.GetEnvironment().Settings(), user))
 }
 
+func (hc *DBHostConnector) StopHost(ctx context.Context, host *host.Host, user string) error {
+	return cloudHostOperationError(cloud.StopSpawnHost(ctx, host, evergreen.GetEnvironment().Settings(), user))
+}
+
+func (hc *DBHostConnector) StartHost(ctx context.Context, host *host.Host, user string) error {
+	return cloudHostOperationError(cloud.StartSpawnHost(ctx, host, evergreen.GetEnvironment().Settings(), user))
+}
+
+func (hc *DBHostConnector) AttachVolume(ctx context.Context, h *host.Host, attachment host.VolumeAttachment, user string) error {
+	attached, err := host.FindOne(host.ByVolumeID(attachment.VolumeID))
+	if err != nil {
+		return errors.Wrapf(err, "problem finding host with volume '%s'", attachment.VolumeID)
+	}
+	if attached != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("volume '%s' is already attached to host '%s'", attachment.VolumeID, attached.Id),
+		}
+	}
+
+	settings := evergreen.GetEnvironment().Settings()
+	owner, err := cloud.GetSpawnHostVolumeOwner(ctx, h, settings, attachment.VolumeID)
+	if err != nil {
+		return cloudHostOperationError(err)
+	}
+	if owner != user {
+		return volumeNotOwnedError(attachment.VolumeID, user)
+	}
+
+	return cloudHostOperationError(cloud.AttachSpawnHostVolume(ctx, h, settings, attachment))
+}
+
+func (hc *DBHostConnector) DetachVolume(ctx context.Context, host *host.Host, volumeID string) error {
+	return cloudHostOperationError(cloud.DetachSpawnHostVolume(ctx, host, evergreen.GetEnvironment().Settings(), volumeID))
+}
+
+func (dbc *DBConnector) TransferHost(h *host.Host, newOwner, caller string) error {
+	owner, err := dbc.FindUserById(newOwner)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding user '%s'", newOwner)
+	}
+	if u, ok := owner.(*user.DBUser); !ok || u == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user '%s' not found", newOwner),
+		}
+	}
+
+	return errors.Wrap(h.SetOwner(newOwner, caller), "problem transferring host")
+}
+
+// volumeNotOwnedError returns an unauthorized error for a user attaching a
+// volume that they don't own.
+func volumeNotOwnedError(volumeID, user string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusUnauthorized,
+		Message:    fmt.Sprintf("volume '%s' is not owned by '%s'", volumeID, user),
+	}
+}
+
+// cloudHostOperationError returns a bad request error if the host's
+// provider does not support the operation.
+func cloudHostOperationError(err error) error {
+	if errors.Cause(err) == cloud.ErrUnsupportedOperation {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	return err
+}
+
 // MockHostConnector is a struct that implements the Host related methods
 // from the Connector through interactions with he backing database.
 type MockHostConnector struct {
 	CachedHosts []host.Host
+	// VolumeOwners maps the IDs of volumes to the users that own them.
+	VolumeOwners map[string]string
 }
 
 // FindHostsById searches the mock hosts slice for hosts and returns them
@@ -175,6 +291,26 @@ func (hc *MockHostConnector) FindHostById(id string) (*host.Host, error) {
 	}
 }
 
+// FindCostByHostId returns cost data based on the cached hosts in the
+// MockHostConnector. No tasks are cached, so none of the cost of the host is
+// attributed to tasks.
+func (hc *MockHostConnector) FindCostByHostId(hostId string) (*task.HostCost, error) {
+	h, err := hc.FindHostById(hostId)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &task.HostCost{
+		HostId:      h.Id,
+		DistroId:    h.Distro.Id,
+		Provider:    h.Provider,
+		Runtime:     h.GetRuntime(),
+		CostedTime:  h.TotalCostedTime,
+		HourlyPrice: h.HourlyPrice,
+		TotalCost:   h.TotalCost,
+	}, nil
+}
+
 // NewIntentHost is a method to mock "insert" an intent host given a distro and a public key
 // The public key can be the name of a saved key or the actual key string
 func (hc *MockHostConnector) NewIntentHost(distroID, keyNameOrVal, taskID string, user *user.DBUser, providerSettings *map[string]interface{}) (*host.Host, error) {
@@ -216,6 +352,18 @@ func (hc *MockHostConnector) SetHostStatus(host *host.Host, status, user string)
 	return errors.New("can't find host")
 }
 
+func (hc *MockHostConnector) SetHostDraining(host *host.Host, draining bool, user string) error {
+	for i := range hc.CachedHosts {
+		if hc.CachedHosts[i].Id == host.Id {
+			hc.CachedHosts[i].Draining = draining
+			host.Draining = draining
+			return nil
+		}
+	}
+
+	return errors.New("can't find host")
+}
+
 func (hc *MockHostConnector) SetHostExpirationTime(host *host.Host, newExp time.Time) error {
 	for i, h := range hc.CachedHosts {
 		if h.Id == host.Id {
@@ -238,6 +386,74 @@ func (hc *MockHostConnector) TerminateHost(ctx context.Context, host *host.Host,
 	return errors.New("can't find host")
 }
 
+func (hc *MockHostConnector) StopHost(ctx context.Context, host *host.Host, user string) error {
+	return hc.SetHostStatus(host, evergreen.HostStopped, user)
+}
+
+func (hc *MockHostConnector) StartHost(ctx context.Context, host *host.Host, user string) error {
+	return hc.SetHostStatus(host, evergreen.HostRunning, user)
+}
+
+func (hc *MockHostConnector) AttachVolume(ctx context.Context, h *host.Host, attachment host.VolumeAttachment, user string) error {
+	for _, cached := range hc.CachedHosts {
+		if cached.GetVolume(attachment.VolumeID) != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("volume '%s' is already attached to host '%s'", attachment.VolumeID, cached.Id),
+			}
+		}
+	}
+	if hc.VolumeOwners[attachment.VolumeID] != user {
+		return volumeNotOwnedError(attachment.VolumeID, user)
+	}
+	for i := range hc.CachedHosts {
+		if hc.CachedHosts[i].Id == h.Id {
+			hc.CachedHosts[i].Volumes = append(hc.CachedHosts[i].Volumes, attachment)
+			h.Volumes = append(h.Volumes, attachment)
+			return nil
+		}
+	}
+
+	return errors.New("can't find host")
+}
+
+func (hc *MockHostConnector) DetachVolume(ctx context.Context, h *host.Host, volumeID string) error {
+	for i := range hc.CachedHosts {
+		if hc.CachedHosts[i].Id != h.Id {
+			continue
+		}
+		volumes := []host.VolumeAttachment{}
+		for _, v := range hc.CachedHosts[i].Volumes {
+			if v.VolumeID != volumeID {
+				volumes = append(volumes, v)
+			}
+		}
+		hc.CachedHosts[i].Volumes = volumes
+		h.Volumes = volumes
+		return nil
+	}
+
+	return errors.New("can't find host")
+}
+
+func (dbc *MockConnector) TransferHost(h *host.Host, newOwner, caller string) error {
+	if _, ok := dbc.MockUserConnector.CachedUsers[newOwner]; !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("user '%s' not found", newOwner),
+		}
+	}
+	for i := range dbc.MockHostConnector.CachedHosts {
+		if dbc.MockHostConnector.CachedHosts[i].Id == h.Id {
+			dbc.MockHostConnector.CachedHosts[i].StartedBy = newOwner
+			h.StartedBy = newOwner
+			return nil
+		}
+	}
+
+	return errors.New("can't find host")
+}
+
 func (dbc *MockConnector) FindHostByIdWithOwner(hostID string, user gimlet.User) (*host.Host, error) {
 	return findHostByIdWithOwner(dbc, hostID, user)
 }
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -47,7 +48,7 @@ func (dc *DBCreateHostConnector) ListHostsForTask(taskID string) ([]host.Host, e
 	return hosts, nil
 }
 
-func (dc *DBCreateHostConnector) CreateHostsFromTask(t *task.Task, user user.DBUser, keyNameOrVal string) error {
+func (dc *DBCreateHostConnector) CreateHostsFromTask(ctx context.Context, t *task.Task, user user.DBUser, keyNameOrVal string) error {
 	if t == nil {
 		return errors.New("no task to create hosts from")
 	}
@@ -57,7 +58,11 @@ func (dc *DBCreateHostConnector) CreateHostsFromTask(t *task.Task, user user.DBU
 		keyVal = keyNameOrVal
 	}
 
-	tc, err := model.MakeConfigFromTask(t)
+	var secrets *evergreen.SecretsConfig
+	if settings := evergreen.GetEnvironment().Settings(); settings != nil {
+		secrets = &settings.Secrets
+	}
+	tc, err := model.MakeConfigFromTask(ctx, t, secrets)
 	if err != nil {
 		return err
 	}
@@ -220,6 +225,6 @@ func (*MockCreateHostConnector) MakeIntentHost(taskID, userID, publicKey string,
 	return nil, errors.New("MakeIntentHost not implemented")
 }
 
-func (*MockCreateHostConnector) CreateHostsFromTask(t *task.Task, user user.DBUser, keyNameOrVal string) error {
+func (*MockCreateHostConnector) CreateHostsFromTask(ctx context.Context, t *task.Task, user user.DBUser, keyNameOrVal string) error {
 	return errors.New("CreateHostsFromTask not implemented")
 }
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -132,7 +133,7 @@ buildvariants:
 	assert.NoError(pvars.Insert())
 
 	dc := DBCreateHostConnector{}
-	err := dc.CreateHostsFromTask(&t1, user.DBUser{Id: "me"}, "")
+	err := dc.CreateHostsFromTask(context.Background(), &t1, user.DBUser{Id: "me"}, "")
 	assert.NoError(err)
 
 	createdHosts, err := host.Find(host.IsUninitialized)
@@ -16,6 +16,7 @@ type DBConnector struct {
 	DBHostConnector
 	DBTestConnector
 	DBMetricsConnector
+	DBTaskLogConnector
 	DBBuildConnector
 	DBVersionConnector
 	DBPatchConnector
@@ -30,6 +31,10 @@ type DBConnector struct {
 	DBSubscriptionConnector
 	NotificationConnector
 	DBCreateHostConnector
+	DBEventStreamConnector
+	DBETagConnector
+	DBExportConnector
+	DBCommitQueueConnector
 }
 
 func (ctx *DBConnector) GetSuperUsers() []string   { return ctx.superUsers }
@@ -51,6 +56,7 @@ type MockConnector struct {
 	MockHostConnector
 	MockTestConnector
 	MockMetricsConnector
+	MockTaskLogConnector
 	MockBuildConnector
 	MockVersionConnector
 	MockPatchConnector
@@ -65,6 +71,10 @@ type MockConnector struct {
 	MockSubscriptionConnector
 	MockNotificationConnector
 	MockCreateHostConnector
+	MockEventStreamConnector
+	MockETagConnector
+	MockExportConnector
+	MockCommitQueueConnector
 }
 
 func (ctx *MockConnector) GetSuperUsers() []string   { return ctx.superUsers }
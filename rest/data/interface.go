@@ -3,6 +3,7 @@ package data
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -12,12 +13,18 @@ import (
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/projectinsights"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/taskruntime"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/google/go-github/github"
 	"github.com/mongodb/amboy"
@@ -51,6 +58,9 @@ type Connector interface {
 	SetTaskActivated(string, string, bool) error
 	ResetTask(string, string) error
 	AbortTask(string, string) error
+	// FindTaskRuntime returns the recent runtimes of a task, given its
+	// project, variant and name, or nil if none have been recorded.
+	FindTaskRuntime(string, string, string) (*taskruntime.TaskRuntime, error)
 
 	// FindTasksByBuildId is a method to find a set of tasks which all have the same
 	// BuildId. It takes the buildId being queried for as its first parameter,
@@ -68,9 +78,37 @@ type Connector interface {
 	AbortBuild(string, string) error
 	// RestartBuild is a method to restart the build matching the same BuildId.
 	RestartBuild(string, string) error
+	// FindLatestMainlineStatus returns the status of the project's latest
+	// finished mainline version, or of the latest finished mainline build
+	// of the variant if it isn't empty. It returns an empty status if
+	// nothing has finished.
+	FindLatestMainlineStatus(string, string) (string, error)
 
 	// FindProjects is a method to find projects as ordered by name
 	FindProjects(string, int, int, bool) ([]model.ProjectRef, error)
+	// SetProjectPriority sets the priority that the project's tasks
+	// inherit when neither they, their build nor their version set one.
+	SetProjectPriority(*model.ProjectRef, int64) error
+	// UpdateProjectCoverage replaces the tasks that test each of the given
+	// source files of a project, which test selection uses to pick the
+	// tasks affected by a commit.
+	UpdateProjectCoverage(string, map[string][]string) error
+	// FindProjectInsights returns the insights into a project's mainline
+	// builds over the given window of days, or over every window if it is
+	// 0, shortest window first.
+	FindProjectInsights(string, int) ([]projectinsights.ProjectInsights, error)
+	// MigrateProjectVarsToSecrets moves the variables of the project into
+	// a secrets manager, replacing them with references to the secrets,
+	// and returns the names of the variables that were moved.
+	MigrateProjectVarsToSecrets(context.Context, string, *restModel.APIProjectVarsMigration) ([]string, error)
+	// GetProjectSettings returns a snapshot of the settings of the project,
+	// which LogProjectModified compares to a later snapshot to record a
+	// user's change to them.
+	GetProjectSettings(string) (*model.ProjectSettings, error)
+	LogProjectModified(string, string, *model.ProjectSettings, *model.ProjectSettings) error
+	// GetProjectEventLog returns at most n of the most recent changes to
+	// the settings of the project before the given time.
+	GetProjectEventLog(string, time.Time, int) ([]restModel.APIProjectEvent, error)
 	// FindProjectByBranch is a method to find the projectref given a branch name.
 	FindProjectByBranch(string) (*model.ProjectRef, error)
 	// GetVersionsAndVariants returns recent versions for a project
@@ -85,9 +123,18 @@ type Connector interface {
 	// a given task. It takes a taskId, testName to start from, test status to filter,
 	// limit, and sort to provide additional control over the results.
 	FindTestsByTaskId(string, string, string, int, int) ([]testresult.TestResult, error)
+	// IngestTestReport converts the tests in a JUnit or TAP report into
+	// test results of the task, and returns them.
+	IngestTestReport(*task.Task, string, []byte) ([]testresult.TestResult, error)
+	// FindTestFlakiness returns the flakiness of the tests of a project,
+	// optionally narrowed to a variant and task, most flaky first.
+	FindTestFlakiness(string, string, string) ([]testflakiness.TestFlakiness, error)
 
 	// FindUserById is a method to find a specific user given its ID.
 	FindUserById(string) (gimlet.User, error)
+	// FindUserBySlackMemberId finds the user whose settings name the given
+	// slack member ID.
+	FindUserBySlackMemberId(string) (*user.DBUser, error)
 
 	// FindHostsById is a method to find a sorted list of hosts given an ID to
 	// start from.
@@ -114,6 +161,14 @@ type Connector interface {
 	FindTaskSystemMetrics(string, time.Time, int) ([]*message.SystemInfo, error)
 	FindTaskProcessMetrics(string, time.Time, int) ([][]*message.ProcessInfo, error)
 
+	// FindTaskLogMessages returns at most limit messages of a task
+	// execution's log, starting at the given offset, which is the number of
+	// messages before them.
+	FindTaskLogMessages(string, int, int, int) ([]apimodels.LogMessage, error)
+	// FindStructuredTaskLogs returns the structured log lines of a task
+	// execution that match the filter, oldest first.
+	FindStructuredTaskLogs(model.StructuredTaskLogFilter) ([]model.StructuredTaskLog, error)
+
 	// FindCostByVersionId returns cost data of a version given its ID.
 	FindCostByVersionId(string) (*task.VersionCost, error)
 
@@ -121,12 +176,44 @@ type Connector interface {
 	// Interested time range is given as a start time and duration.
 	FindCostByDistroId(string, time.Time, time.Duration) (*task.DistroCost, error)
 
+	// FindCostByProjectId returns cost data of a project given its ID and a
+	// time range, broken down by distro. Interested time range is given as a
+	// start time and duration.
+	FindCostByProjectId(string, time.Time, time.Duration) (*task.ProjectCost, error)
+
+	// FindCostByHostId returns cost data of a host given its ID, including the
+	// cost attributed to the tasks that ran on it.
+	FindCostByHostId(string) (*task.HostCost, error)
+
 	// ClearTaskQueue deletes all tasks from the task queue for a distro
 	ClearTaskQueue(string) error
 
+	// SimulateDistroCapacity estimates how the distro's task queue drains
+	// at its current pool size and at the given one, in that order.
+	SimulateDistroCapacity(string, int) ([]scheduler.CapacityEstimate, error)
+
+	// GetDistroIdlePolicy returns the idle policy of the distro with the
+	// given ID, and SetDistroIdlePolicy replaces it.
+	GetDistroIdlePolicy(string) (*distro.IdlePolicy, error)
+	SetDistroIdlePolicy(string, distro.IdlePolicy) error
+
+	// RollBackDistroParentImage starts the hosts of the distro with the given
+	// ID from the image they started from before its current baked image
+	// was rolled out, on behalf of the given user.
+	RollBackDistroParentImage(string, string) (*distro.Distro, error)
+
 	// FindVersionById returns version given its ID.
 	FindVersionById(string) (*version.Version, error)
 
+	// FindVersionsByProject returns at most limit versions of the project,
+	// other than patches, starting at the given revision order number and
+	// going back, or at the most recent version if it's 0.
+	FindVersionsByProject(string, int, int) ([]version.Version, error)
+
+	// FindManifestByVersionId returns the manifest pinning the module
+	// revisions of a version given its ID.
+	FindManifestByVersionId(string) (*manifest.Manifest, error)
+
 	// FindPatchesByProject provides access to the patches corresponding to the input project ID
 	// as ordered by creation time.
 	FindPatchesByProject(string, time.Time, int) ([]patch.Patch, error)
@@ -135,9 +222,16 @@ type Connector interface {
 
 	// FindPatchById fetches the patch corresponding to the input patch ID.
 	FindPatchById(string) (*patch.Patch, error)
+	// FindPreviousPatch returns the newest patch, given the user and the
+	// project ID, so that its variants and tasks can be run again.
+	FindPreviousPatch(string, string) (*patch.Patch, error)
+	// ConfigurePatch adds variants and tasks to a finalized patch, given
+	// its ID, creating only the builds and tasks that do not exist yet.
+	ConfigurePatch(string, []patch.VariantTasks) (*patch.Patch, error)
 
-	// AbortVersion aborts all tasks of a version given its ID.
-	AbortVersion(string, string) error
+	// AbortVersion aborts the tasks of a version that pass the filter,
+	// given its ID and the caller.
+	AbortVersion(string, model.VersionTaskFilter, string) error
 
 	// AbortPatch aborts the patch corresponding to the input patch ID and deletes if not finalized.
 	AbortPatch(string, string) error
@@ -145,11 +239,25 @@ type Connector interface {
 	// in the same repository, at the pull request's close time
 	AbortPatchesFromPullRequest(*github.PullRequestEvent) error
 
-	// RestartVersion restarts all completed tasks of a version given its ID and the caller.
-	RestartVersion(string, string) error
+	// FindGithubCheckSuiteVersion returns the ID of the newest version, of a
+	// pull request patch or of a mainline commit, that ran the commit
+	// checked by a GitHub check suite, given the repository's owner and
+	// name, the branch and the commit's hash. It returns an empty string if
+	// no version ran it.
+	FindGithubCheckSuiteVersion(string, string, string, string) (string, error)
+
+	// RestartVersion restarts the completed tasks of a version that pass
+	// the filter, given its ID and the caller.
+	RestartVersion(string, model.VersionTaskFilter, string) error
+	// SetVersionPriority sets the priority of a version's tasks, which
+	// tasks added to the version later inherit, given its ID.
+	SetVersionPriority(string, int64) error
 	// SetPatchPriority and SetPatchActivated change the status of the input patch
 	SetPatchPriority(string, int64) error
 	SetPatchActivated(string, string, bool) error
+	// SetPatchPinned sets whether the input patch is kept once it has gone
+	// unfinalized for longer than its project's patch expiration
+	SetPatchPinned(string, bool) error
 
 	// GetEvergreenSettings/SetEvergreenSettings retrieves/sets the system-wide settings document
 	GetEvergreenSettings() (*evergreen.Settings, error)
@@ -159,38 +267,173 @@ type Connector interface {
 	SetAdminBanner(string, *user.DBUser) error
 	// SetBannerTheme sets set the banner theme in the system-wide settings document
 	SetBannerTheme(string, *user.DBUser) error
+	// GetServiceFlags returns the current service flags
+	GetServiceFlags() (*evergreen.ServiceFlags, error)
 	// SetAdminBanner sets set the service flags in the system-wide settings document
 	SetServiceFlags(evergreen.ServiceFlags, *user.DBUser) error
+	// RotateSenderCredentials persists new notification sender credentials
+	// and rebuilds the affected senders, returning the credential version
+	// of each sender.
+	RotateSenderCredentials(*restModel.APISenderCredentials, *user.DBUser) (map[string]string, error)
 	RestartFailedTasks(amboy.Queue, model.RestartTaskOptions) (*restModel.RestartTasksResponse, error)
 	RevertConfigTo(string, string) error
 	GetAdminEventLog(time.Time, int) ([]restModel.APIAdminEvent, error)
+	// GetJobQueueStats summarizes the jobs in the named background job
+	// queue, either "local" or "remote".
+	GetJobQueueStats(string) (*restModel.APIJobQueueStats, error)
+	// FindQueueJobs returns up to the limit of the named queue's jobs with
+	// the given status and job type, either of which may be empty to match
+	// any job.
+	FindQueueJobs(context.Context, string, string, string, int) ([]restModel.APIQueueJob, error)
+	// AbortQueueJob cancels an in progress job in the named queue.
+	AbortQueueJob(context.Context, string, string) (*restModel.APIQueueJob, error)
+	// RetryQueueJob enqueues a copy of an errored job in the named queue
+	// and returns the copy.
+	RetryQueueJob(string, string) (*restModel.APIQueueJob, error)
+	// GetIndexReports compares the database queries sampled from the
+	// model layer with the indexes of the collections they ran against.
+	GetIndexReports() ([]restModel.APIIndexReport, error)
+	// GetSlowQueries returns the most recent slow database queries.
+	GetSlowQueries() ([]restModel.APISlowQuery, error)
+	// ResetQuerySamples discards the sampled database queries.
+	ResetQuerySamples() error
+	// CreateEventWebhook registers a webhook, created by the given user,
+	// that receives the events passing its filters from now on. The
+	// returned webhook includes the secret its requests are signed with.
+	CreateEventWebhook(*restModel.APIEventWebhook, string) (*restModel.APIEventWebhook, error)
+	// FindEventWebhooks returns all of the registered event webhooks.
+	FindEventWebhooks() ([]restModel.APIEventWebhook, error)
+	// DeleteEventWebhook stops delivering events to the webhook.
+	DeleteEventWebhook(string) error
+	// ReplayEventWebhook moves the webhook's cursor back to an event or a
+	// time, so that the events after it are delivered again.
+	ReplayEventWebhook(string, *restModel.APIEventWebhookReplay) (*restModel.APIEventWebhook, error)
+	// CreateServiceKey creates an API key for a service account, created
+	// by the given user. The returned key includes the token that
+	// authenticates requests as it.
+	CreateServiceKey(*restModel.APIServiceKey, string) (*restModel.APIServiceKey, error)
+	// FindServiceKeys returns all of the service keys.
+	FindServiceKeys() ([]restModel.APIServiceKey, error)
+	// DeleteServiceKey revokes the service key.
+	DeleteServiceKey(string) error
+	// FindServiceKeyByToken returns the service key that the token
+	// authenticates as, or nil if there isn't one.
+	FindServiceKeyByToken(string) (*user.ServiceKey, error)
+
+	// FindProjectQuotas returns the quotas of every project that has one.
+	FindProjectQuotas() ([]restModel.APIProjectQuota, error)
+	// SetProjectQuota replaces the quota of a project, recording the user
+	// who set it.
+	SetProjectQuota(*restModel.APIProjectQuota, string) (*restModel.APIProjectQuota, error)
+	// DeleteProjectQuota removes the quota of a project.
+	DeleteProjectQuota(string) error
 
 	FindCostTaskByProject(string, string, time.Time, time.Time, int, int) ([]task.Task, error)
 
+	// StatusEventCursor returns the position in the event log of the event
+	// with the given ID, or of the events logged from now on if the ID is
+	// empty.
+	StatusEventCursor(string) (*event.EventCursor, error)
+	// FindStatusEvents scans at most limit events after the cursor, and
+	// returns the version, build and task state transitions among them
+	// that pass the filter, along with the position of the last event
+	// scanned.
+	FindStatusEvents(event.EventCursor, StatusEventFilter, int) ([]restModel.APIStatusEvent, *event.EventCursor, error)
+
+	// VersionETag, VersionBuildsETag, BuildETag and BuildTasksETag return
+	// entity tags that change whenever the version, the version's builds,
+	// the build, or the build's tasks do, or an empty string if the
+	// resources don't exist.
+	VersionETag(string) (string, error)
+	VersionBuildsETag(string) (string, error)
+	BuildETag(string) (string, error)
+	BuildTasksETag(string) (string, error)
+
+	// ExportTaskHistory and ExportTestResults call the function with each
+	// of the tasks, or test results, passing the filter, ordered by
+	// revision, stopping at the first error the function returns.
+	ExportTaskHistory(ExportFilter, func(*restModel.APITaskHistoryRow) error) error
+	ExportTestResults(ExportFilter, func(*restModel.APITestResultRow) error) error
+
+	// GetCommitQueue returns the commit queue of the project.
+	GetCommitQueue(string) (*restModel.APICommitQueue, error)
+	// EnqueueItem adds a pull request number or a patch ID to the back of
+	// the project's commit queue, returning its position.
+	EnqueueItem(string, restModel.APICommitQueueItem) (int, error)
+	// CommitQueueRemoveItem removes the item from the project's commit
+	// queue, returning false if it wasn't in the queue.
+	CommitQueueRemoveItem(string, string) (bool, error)
+
 	// FindRecentTasks finds tasks that have recently finished.
 	FindRecentTasks(int) ([]task.Task, *task.ResultCounts, error)
 	// GetHostStatsByDistro returns host stats broken down by distro
 	GetHostStatsByDistro() ([]host.StatsByDistro, error)
+	// FindStaticHostsHealth returns the running and quarantined static
+	// hosts of the distro, or of every distro if it is empty, with the
+	// results of their most recent health checks.
+	FindStaticHostsHealth(string) ([]host.Host, error)
 
 	AddPublicKey(*user.DBUser, string, string) error
 	DeletePublicKey(*user.DBUser, string) error
 	UpdateSettings(*user.DBUser, user.UserSettings) error
+	// UpdateDeliveryPreferences validates and replaces the user's
+	// notification delivery preferences.
+	UpdateDeliveryPreferences(*user.DBUser, user.DeliveryPreferences) error
 
 	AddPatchIntent(patch.Intent, amboy.Queue) error
+	// UploadPatchFile streams a patch diff into storage without buffering
+	// it, returning the ID of the stored file.
+	UploadPatchFile(io.Reader) (string, error)
+	// CreatePatchFromIntent inserts the intent and creates its patch,
+	// returning the patch once it's created.
+	CreatePatchFromIntent(context.Context, patch.Intent) (*patch.Patch, error)
 
 	SetHostStatus(*host.Host, string, string) error
 	SetHostExpirationTime(*host.Host, time.Time) error
+	// SetHostDraining starts or stops draining the given host, given the
+	// user doing so.
+	SetHostDraining(*host.Host, bool, string) error
 
 	// TerminateHost terminates the given host via the cloud provider's API
 	TerminateHost(context.Context, *host.Host, string) error
+	// StopHost stops the given running spawn host via the cloud provider's
+	// API, and StartHost starts it again.
+	StopHost(context.Context, *host.Host, string) error
+	StartHost(context.Context, *host.Host, string) error
+	// AttachVolume attaches a persistent volume that the given user owns to
+	// the given spawn host, and DetachVolume detaches the volume with the
+	// given ID.
+	AttachVolume(context.Context, *host.Host, host.VolumeAttachment, string) error
+	DetachVolume(context.Context, *host.Host, string) error
+	// TransferHost makes the user with the given ID the owner of the spawn
+	// host, given the user making the transfer.
+	TransferHost(*host.Host, string, string) error
 
 	// FindProjectAliases queries the database to find all aliases.
 	FindProjectAliases(string) ([]model.ProjectAlias, error)
+	// UpdateProjectAlias replaces the definitions of an alias, given the
+	// project ID and the alias name.
+	UpdateProjectAlias(string, string, []model.ProjectAlias) error
+	// DeleteProjectAlias removes every definition of an alias, given the
+	// project ID and the alias name.
+	DeleteProjectAlias(string, string) error
+	// ResolveProjectAlias returns the variants and tasks that a patch of
+	// the project at the revision, or at the project's last known good
+	// configuration if the revision is empty, would run with the alias.
+	ResolveProjectAlias(*model.ProjectRef, string, string) ([]patch.VariantTasks, error)
 
 	// TriggerRepotracker creates an amboy job to get the commits from a
 	// Github Push Event
 	TriggerRepotracker(amboy.Queue, string, *github.PushEvent) error
 
+	// LoadRepotrackerFixture runs the repotracker for a project against
+	// synthetic revisions, and returns the IDs of their versions.
+	LoadRepotrackerFixture(context.Context, string, *restModel.APIRepoTrackerFixture) ([]string, error)
+
+	// CreateManualVersion creates and activates a version of the project at
+	// a revision, on behalf of the given user.
+	CreateManualVersion(context.Context, *model.ProjectRef, *restModel.APIManualVersion, string) (*version.Version, error)
+
 	// GetCLIUpdate fetches the current cli version and the urls to download
 	GetCLIUpdate() (*restModel.APICLIUpdate, error)
 
@@ -202,12 +445,69 @@ type Connector interface {
 	// GetSubscriptions returns the subscriptions that belong to a user
 	GetSubscriptions(string, event.OwnerType) ([]restModel.APISubscription, error)
 	DeleteSubscription(id string) error
+	// FindSubscriptionByID returns the subscription with the ID, or nil if
+	// there is none
+	FindSubscriptionByID(string) (*event.Subscription, error)
 
 	// Notifications
 	GetNotificationsStats() (*restModel.APIEventStats, error)
+	// GetNotificationAnalytics summarizes the notifications sent in the
+	// window, reporting the volume per interval and at most limit of the
+	// noisiest subscription owners and triggers.
+	GetNotificationAnalytics(time.Time, time.Time, time.Duration, int) (*restModel.APINotificationAnalytics, error)
+	// GetNotification returns the notification with the given ID.
+	GetNotification(string) (*restModel.APINotification, error)
+	// LinkNotificationToIncident attaches the notification to an existing
+	// incident, or to a new incident if the link has no incident ID.
+	LinkNotificationToIncident(string, *restModel.APIIncidentLink) (*restModel.APIIncident, error)
+	// AcknowledgeNotification acknowledges the escalation that the
+	// notification was sent in on behalf of the user, so that it doesn't
+	// escalate further.
+	AcknowledgeNotification(string, string) (*restModel.APIEscalation, error)
+	// GetIncident returns the incident with the given ID, along with the
+	// IDs of the notifications attached to it.
+	GetIncident(string) (*restModel.APIIncident, error)
+	// ResolveIncident resolves the incident so that no further
+	// notifications are attached to it.
+	ResolveIncident(string) (*restModel.APIIncident, error)
+	// FindNotificationAuditEntries returns at most limit entries from the
+	// notification audit log matching the filter, most recent first.
+	FindNotificationAuditEntries(notification.AuditFilter, int) ([]restModel.APINotificationAuditEntry, error)
+
+	// The methods that send notifications only compose them, without
+	// sending them, when their final argument is true, returning a
+	// preview of the message that would be sent.
+	//
+	// SendWebhook creates a notification delivering the webhook, sent by
+	// the given user, and enqueues a job to send it.
+	SendWebhook(amboy.Queue, *restModel.APIWebhook, string, bool) (*restModel.APINotification, error)
+	// SendEmail creates a notification sending the email to each of its
+	// recipients, sent by the given user, and enqueues jobs to send them.
+	SendEmail(amboy.Queue, *restModel.APIEmail, string, bool) ([]restModel.APINotification, error)
+	// SendSlack creates a notification sending the Slack message, sent by
+	// the given user, and enqueues a job to send it.
+	SendSlack(amboy.Queue, *restModel.APISlack, string, bool) (*restModel.APINotification, error)
+	// SendOpsgenie creates a notification creating the Opsgenie alert,
+	// sent by the given user, and enqueues a job to send it.
+	SendOpsgenie(amboy.Queue, *restModel.APIOpsgenieAlert, string, bool) (*restModel.APINotification, error)
+	// TransitionJiraIssue moves the JIRA issue with the given key through
+	// a workflow transition, updating its fields, and returns the issue.
+	TransitionJiraIssue(string, *restModel.APIJiraIssueTransition) (*restModel.APIJiraIssue, error)
+	// AttachFilesToJiraIssue attaches the files to the JIRA issue with the
+	// given key, and returns the attachments JIRA created.
+	AttachFilesToJiraIssue(string, *restModel.APIJiraIssueAttachments) ([]restModel.APIJiraAttachment, error)
+	// GetNotificationTemplate returns the notification template with the
+	// given name.
+	GetNotificationTemplate(string) (*restModel.APINotificationTemplate, error)
+	// SaveNotificationTemplate creates or replaces a notification template.
+	SaveNotificationTemplate(*restModel.APINotificationTemplate) (*restModel.APINotificationTemplate, error)
+	// SendTemplateNotification creates a notification rendered from a
+	// stored template, sent by the given user, and enqueues a job to send
+	// it.
+	SendTemplateNotification(amboy.Queue, *restModel.APITemplateNotification, string, bool) (*restModel.APINotification, error)
 
 	// ListHostsForTask lists running hosts scoped to the task or the task's build.
 	ListHostsForTask(string) ([]host.Host, error)
 	MakeIntentHost(string, string, string, apimodels.CreateHost) (*host.Host, error)
-	CreateHostsFromTask(*task.Task, user.DBUser, string) error
+	CreateHostsFromTask(context.Context, *task.Task, user.DBUser, string) error
 }
@@ -0,0 +1,283 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const (
+	LocalJobQueue  = "local"
+	RemoteJobQueue = "remote"
+)
+
+func (ac *DBAdminConnector) GetJobQueueStats(name string) (*restModel.APIJobQueueStats, error) {
+	q, err := findEnvJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobQueueStats(name, q)
+}
+
+func (ac *DBAdminConnector) FindQueueJobs(ctx context.Context, name, status, jobType string, limit int) ([]restModel.APIQueueJob, error) {
+	q, err := findEnvJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return findQueueJobs(ctx, q, status, jobType, limit)
+}
+
+func (ac *DBAdminConnector) AbortQueueJob(ctx context.Context, name, id string) (*restModel.APIQueueJob, error) {
+	q, err := findEnvJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return abortQueueJob(ctx, q, id)
+}
+
+func (ac *DBAdminConnector) RetryQueueJob(name, id string) (*restModel.APIQueueJob, error) {
+	q, err := findEnvJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return retryQueueJob(q, id)
+}
+
+func findEnvJobQueue(name string) (amboy.Queue, error) {
+	env := evergreen.GetEnvironment()
+	var q amboy.Queue
+	switch name {
+	case LocalJobQueue:
+		q = env.LocalQueue()
+	case RemoteJobQueue:
+		q = env.RemoteQueue()
+	}
+	if q == nil {
+		return nil, jobQueueNotFound(name)
+	}
+
+	return q, nil
+}
+
+func jobQueueNotFound(name string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("job queue '%s' not found", name),
+	}
+}
+
+func queueJobNotFound(id string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("job '%s' not found", id),
+	}
+}
+
+func jobQueueStats(name string, q amboy.Queue) (*restModel.APIJobQueueStats, error) {
+	stats := &restModel.APIJobQueueStats{}
+	if err := stats.BuildFromService(q.Stats()); err != nil {
+		return nil, errors.Wrap(err, "problem building job queue stats")
+	}
+	stats.Name = restModel.ToAPIString(name)
+
+	return stats, nil
+}
+
+// findQueueJobs returns up to limit of the queue's jobs that have the given
+// status and type, either of which matches any job when it's empty.
+func findQueueJobs(ctx context.Context, q amboy.Queue, status, jobType string, limit int) ([]restModel.APIQueueJob, error) {
+	// Local queues hold their lock while sending stats, so the jobs are
+	// only looked up once the stats have all been read.
+	ids := []string{}
+	for info := range q.JobStats(ctx) {
+		if status == "" || restModel.QueueJobStatus(queueJobStatusInfo(q, info)) == status {
+			ids = append(ids, info.ID)
+		}
+	}
+
+	out := []restModel.APIQueueJob{}
+	for _, id := range ids {
+		j, ok := q.Get(id)
+		if !ok {
+			continue
+		}
+		if jobType != "" && j.Type().Name != jobType {
+			continue
+		}
+
+		apiJob, err := buildAPIQueueJob(q, j)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *apiJob)
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+	}
+
+	return out, nil
+}
+
+// queueJobStatusInfo returns the job's status, marking it in progress when
+// it's running in this process, since local queues don't track that.
+func queueJobStatusInfo(q amboy.Queue, info amboy.JobStatusInfo) amboy.JobStatusInfo {
+	if runner, ok := q.Runner().(amboy.AbortableRunner); ok && !info.Completed && runner.IsRunning(info.ID) {
+		info.InProgress = true
+	}
+
+	return info
+}
+
+// abortQueueJob cancels an in progress job, which must be running in this
+// process.
+func abortQueueJob(ctx context.Context, q amboy.Queue, id string) (*restModel.APIQueueJob, error) {
+	j, ok := q.Get(id)
+	if !ok {
+		return nil, queueJobNotFound(id)
+	}
+	if !queueJobStatusInfo(q, queueJobStatus(j)).InProgress {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("job '%s' is not in progress", id),
+		}
+	}
+	runner, ok := q.Runner().(amboy.AbortableRunner)
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "job queue does not support aborting jobs",
+		}
+	}
+	if err := runner.Abort(ctx, id); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("job '%s' could not be aborted, it may be running on another app server: %s", id, err.Error()),
+		}
+	}
+
+	if aborted, ok := q.Get(id); ok {
+		j = aborted
+	}
+
+	return buildAPIQueueJob(q, j)
+}
+
+// retryQueueJob enqueues a copy of a job that completed with errors, under a
+// new ID, so that it runs again.
+func retryQueueJob(q amboy.Queue, id string) (*restModel.APIQueueJob, error) {
+	j, ok := q.Get(id)
+	if !ok {
+		return nil, queueJobNotFound(id)
+	}
+	if restModel.QueueJobStatus(j.Status()) != restModel.QueueJobStatusErrored {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("job '%s' has not errored", id),
+		}
+	}
+
+	interchange, err := registry.MakeJobInterchange(j, amboy.JSON)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem copying job '%s'", id)
+	}
+	interchange.Status = amboy.JobStatusInfo{}
+	interchange.TimeInfo = amboy.JobTimeInfo{MaxTime: j.TimeInfo().MaxTime}
+	retry, err := interchange.Resolve(amboy.JSON)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem copying job '%s'", id)
+	}
+
+	idSetter, ok := retry.(interface{ SetID(string) })
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("job '%s' cannot be retried", id),
+		}
+	}
+	idSetter.SetID(fmt.Sprintf("%s.retry-%d", id, time.Now().Unix()))
+	retry.SetDependency(dependency.NewAlways())
+	retry.UpdateTimeInfo(amboy.JobTimeInfo{Created: time.Now()})
+
+	if err = q.Put(retry); err != nil {
+		return nil, errors.Wrapf(err, "problem enqueueing retry of job '%s'", id)
+	}
+
+	return buildAPIQueueJob(q, retry)
+}
+
+func queueJobStatus(j amboy.Job) amboy.JobStatusInfo {
+	info := j.Status()
+	info.ID = j.ID()
+
+	return info
+}
+
+func buildAPIQueueJob(q amboy.Queue, j amboy.Job) (*restModel.APIQueueJob, error) {
+	apiJob := &restModel.APIQueueJob{}
+	if err := apiJob.BuildFromService(j); err != nil {
+		return nil, errors.Wrap(err, "problem building job")
+	}
+	apiJob.Status = restModel.ToAPIString(restModel.QueueJobStatus(queueJobStatusInfo(q, queueJobStatus(j))))
+
+	return apiJob, nil
+}
+
+func (ac *MockAdminConnector) findJobQueue(name string) (amboy.Queue, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+	q, ok := ac.MockJobQueues[name]
+	if !ok {
+		return nil, jobQueueNotFound(name)
+	}
+
+	return q, nil
+}
+
+func (ac *MockAdminConnector) GetJobQueueStats(name string) (*restModel.APIJobQueueStats, error) {
+	q, err := ac.findJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobQueueStats(name, q)
+}
+
+func (ac *MockAdminConnector) FindQueueJobs(ctx context.Context, name, status, jobType string, limit int) ([]restModel.APIQueueJob, error) {
+	q, err := ac.findJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return findQueueJobs(ctx, q, status, jobType, limit)
+}
+
+func (ac *MockAdminConnector) AbortQueueJob(ctx context.Context, name, id string) (*restModel.APIQueueJob, error) {
+	q, err := ac.findJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return abortQueueJob(ctx, q, id)
+}
+
+func (ac *MockAdminConnector) RetryQueueJob(name, id string) (*restModel.APIQueueJob, error) {
+	q, err := ac.findJobQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return retryQueueJob(q, id)
+}
@@ -1,13 +1,22 @@
 package data
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/units"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/goamz/goamz/aws"
+	"github.com/mongodb/amboy"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 )
 
 type NotificationConnector struct{}
@@ -44,8 +53,852 @@ func (c *NotificationConnector) GetNotificationsStats() (*restModel.APIEventStat
 	return &stats, nil
 }
 
-type MockNotificationConnector struct{}
+func (c *NotificationConnector) GetNotificationAnalytics(start, end time.Time, interval time.Duration, limit int) (*restModel.APINotificationAnalytics, error) {
+	analytics, err := notification.CollectNotificationAnalytics(start, end, interval, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect notification analytics")
+	}
+
+	apiAnalytics := restModel.APINotificationAnalytics{}
+	if err = apiAnalytics.BuildFromService(analytics); err != nil {
+		return nil, errors.Wrap(err, "failed to build notification analytics response")
+	}
+
+	return &apiAnalytics, nil
+}
+
+func (c *NotificationConnector) GetNotification(id string) (*restModel.APINotification, error) {
+	n, err := findNotification(id)
+	if err != nil {
+		return nil, err
+	}
+
+	apiNotification := restModel.APINotification{}
+	if err = apiNotification.BuildFromService(n); err != nil {
+		return nil, errors.Wrap(err, "failed to build notification response")
+	}
+
+	return &apiNotification, nil
+}
+
+func (c *NotificationConnector) FindNotificationAuditEntries(filter notification.AuditFilter, limit int) ([]restModel.APINotificationAuditEntry, error) {
+	entries, err := notification.FindAuditEntries(filter, limit)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out := make([]restModel.APINotificationAuditEntry, 0, len(entries))
+	for i := range entries {
+		apiEntry := restModel.APINotificationAuditEntry{}
+		if err = apiEntry.BuildFromService(&entries[i]); err != nil {
+			return nil, errors.Wrap(err, "failed to build notification audit entry response")
+		}
+		out = append(out, apiEntry)
+	}
+
+	return out, nil
+}
+
+func (c *NotificationConnector) LinkNotificationToIncident(id string, link *restModel.APIIncidentLink) (*restModel.APIIncident, error) {
+	n, err := findNotification(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var incident *notification.Incident
+	if incidentID := restModel.FromAPIString(link.IncidentID); len(incidentID) != 0 {
+		incident, err = findIncident(incidentID)
+		if err != nil {
+			return nil, err
+		}
+		if incident.SubscriptionID != n.SubscriptionID {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("notification '%s' was not generated by the subscription of incident '%s'", id, incidentID),
+			}
+		}
+	} else {
+		incident, err = notification.NewIncident(restModel.FromAPIString(link.Source),
+			restModel.FromAPIString(link.ExternalID), restModel.FromAPIString(link.Title), n)
+		if err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			}
+		}
+		if err = incident.Insert(); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	if err = n.LinkIncident(incident.ID); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIIncident(incident)
+}
+
+func (c *NotificationConnector) AcknowledgeNotification(id, user string) (*restModel.APIEscalation, error) {
+	n, err := findNotification(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(n.EscalationID) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("notification '%s' does not escalate", id),
+		}
+	}
+
+	e, err := notification.FindEscalation(n.EscalationID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch escalation '%s'", n.EscalationID)
+	}
+	if e == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("escalation '%s' not found", n.EscalationID),
+		}
+	}
+	if err = e.Acknowledge(n, user); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	apiEscalation := restModel.APIEscalation{}
+	if err = apiEscalation.BuildFromService(e); err != nil {
+		return nil, errors.Wrap(err, "failed to build escalation response")
+	}
+
+	return &apiEscalation, nil
+}
+
+func (c *NotificationConnector) GetIncident(id string) (*restModel.APIIncident, error) {
+	incident, err := findIncident(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAPIIncident(incident)
+}
+
+func (c *NotificationConnector) ResolveIncident(id string) (*restModel.APIIncident, error) {
+	incident, err := findIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.IsResolved() {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("incident '%s' is already resolved", id),
+		}
+	}
+
+	if err = incident.Resolve(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIIncident(incident)
+}
+
+func (c *NotificationConnector) SendWebhook(queue amboy.Queue, webhook *restModel.APIWebhook, initiator string, dryRun bool) (*restModel.APINotification, error) {
+	n, err := newWebhookNotification(webhook, initiator)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return buildAPINotification(n, dryRun)
+	}
+	if n.IdempotencyKey == "" {
+		if err = notification.InsertMany(*n); err != nil {
+			return nil, errors.Wrap(err, "failed to insert webhook notification")
+		}
+	} else {
+		var inserted bool
+		n, inserted, err = notification.InsertIdempotent(n)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to insert webhook notification")
+		}
+		if !inserted {
+			// the initiator already queued the webhook with the same
+			// idempotency key
+			return buildAPINotification(n, false)
+		}
+	}
+	if err = queue.Put(units.NewEventNotificationJob(n.ID)); err != nil {
+		return nil, errors.Wrapf(err, "failed to enqueue webhook notification '%s'", n.ID)
+	}
+
+	return buildAPINotification(n, false)
+}
+
+func newWebhookNotification(webhook *restModel.APIWebhook, initiator string) (*notification.Notification, error) {
+	i, err := webhook.ToService()
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	payload := i.(*util.EvergreenWebhook)
+
+	subscriber := &event.Subscriber{
+		Type: event.EvergreenWebhookSubscriberType,
+		Target: &event.WebhookSubscriber{
+			URL:    payload.URL,
+			Secret: payload.Secret,
+		},
+	}
+
+	n, err := notification.New(bson.NewObjectId().Hex(), "webhook", subscriber, payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create webhook notification")
+	}
+	n.Initiator = initiator
+	// idempotency keys are chosen by the initiator, so they only identify
+	// a webhook among the initiator's own
+	if key := restModel.FromAPIString(webhook.IdempotencyKey); key != "" {
+		n.IdempotencyKey = fmt.Sprintf("%s/%s", initiator, key)
+	}
+
+	return n, nil
+}
+
+func (c *NotificationConnector) SendEmail(queue amboy.Queue, email *restModel.APIEmail, initiator string, dryRun bool) ([]restModel.APINotification, error) {
+	if err := checkAttachmentBucket(email.Attachments, evergreen.GetEnvironment().Settings().Notify.Attachments); err != nil {
+		return nil, err
+	}
+	notifications, err := newEmailNotifications(email, initiator)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		if err = notification.InsertMany(notifications...); err != nil {
+			return nil, errors.Wrap(err, "failed to insert email notifications")
+		}
+	}
+
+	out := make([]restModel.APINotification, 0, len(notifications))
+	for i := range notifications {
+		n := &notifications[i]
+		if !dryRun {
+			if err = queue.Put(units.NewEventNotificationJob(n.ID)); err != nil {
+				return nil, errors.Wrapf(err, "failed to enqueue email notification '%s'", n.ID)
+			}
+		}
+
+		apiNotification, err := buildAPINotification(n, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *apiNotification)
+	}
+
+	return out, nil
+}
+
+// newEmailNotifications creates a notification for each recipient of the
+// email, since email subscribers have a single address.
+func newEmailNotifications(email *restModel.APIEmail, initiator string) ([]notification.Notification, error) {
+	i, err := email.ToService()
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	payload := i.(*util.EvergreenEmail)
+
+	eventID := bson.NewObjectId().Hex()
+	notifications := make([]notification.Notification, 0, len(payload.Recipients))
+	for _, recipient := range payload.Recipients {
+		target := recipient
+		subscriber := &event.Subscriber{
+			Type:   event.EmailSubscriberType,
+			Target: &target,
+		}
+		recipientPayload := *payload
+		recipientPayload.Recipients = []string{recipient}
+
+		n, err := notification.New(eventID, "email", subscriber, &recipientPayload)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create email notification")
+		}
+		n.Initiator = initiator
+		notifications = append(notifications, *n)
+	}
+
+	return notifications, nil
+}
+
+func (c *NotificationConnector) SendSlack(queue amboy.Queue, slack *restModel.APISlack, initiator string, dryRun bool) (*restModel.APINotification, error) {
+	n, err := newSlackNotification(slack, initiator)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return buildAPINotification(n, dryRun)
+	}
+	if err = notification.InsertMany(*n); err != nil {
+		return nil, errors.Wrap(err, "failed to insert slack notification")
+	}
+	if err = queue.Put(units.NewEventNotificationJob(n.ID)); err != nil {
+		return nil, errors.Wrapf(err, "failed to enqueue slack notification '%s'", n.ID)
+	}
+
+	return buildAPINotification(n, false)
+}
+
+func newSlackNotification(slack *restModel.APISlack, initiator string) (*notification.Notification, error) {
+	i, err := slack.ToService()
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	msg := i.(*util.EvergreenSlack)
+
+	subscriber := &event.Subscriber{
+		Type:   event.SlackSubscriberType,
+		Target: &msg.Target,
+	}
+	payload := &notification.SlackPayload{
+		Body:        msg.Msg,
+		Attachments: msg.Attachments,
+		Blocks:      msg.Blocks,
+		ThreadKey:   restModel.FromAPIString(slack.ThreadKey),
+		ThreadTS:    msg.ThreadTS,
+		UpdateTS:    msg.UpdateTS,
+	}
+	n, err := notification.New(bson.NewObjectId().Hex(), "slack", subscriber, payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create slack notification")
+	}
+	n.Initiator = initiator
+
+	return n, nil
+}
+
+func (c *NotificationConnector) SendOpsgenie(queue amboy.Queue, alert *restModel.APIOpsgenieAlert, initiator string, dryRun bool) (*restModel.APINotification, error) {
+	n, err := newOpsgenieNotification(alert, initiator)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return buildAPINotification(n, dryRun)
+	}
+	if err = notification.InsertMany(*n); err != nil {
+		return nil, errors.Wrap(err, "failed to insert opsgenie notification")
+	}
+	if err = queue.Put(units.NewEventNotificationJob(n.ID)); err != nil {
+		return nil, errors.Wrapf(err, "failed to enqueue opsgenie notification '%s'", n.ID)
+	}
+
+	return buildAPINotification(n, false)
+}
+
+func newOpsgenieNotification(alert *restModel.APIOpsgenieAlert, initiator string) (*notification.Notification, error) {
+	i, err := alert.ToService()
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	payload := i.(*util.OpsgenieAlert)
+
+	subscriber := &event.Subscriber{
+		Type: event.OpsgenieSubscriberType,
+		Target: &event.OpsgenieSubscriber{
+			APIKey: payload.APIKey,
+			Team:   payload.Team,
+		},
+	}
+	n, err := notification.New(bson.NewObjectId().Hex(), "opsgenie", subscriber, payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create opsgenie notification")
+	}
+	n.Initiator = initiator
+
+	return n, nil
+}
+
+func (c *NotificationConnector) TransitionJiraIssue(key string, transition *restModel.APIJiraIssueTransition) (*restModel.APIJiraIssue, error) {
+	jira, err := jiraHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	return transitionJiraIssue(jira, key, transition)
+}
+
+func (c *NotificationConnector) AttachFilesToJiraIssue(key string, req *restModel.APIJiraIssueAttachments) ([]restModel.APIJiraAttachment, error) {
+	conf := evergreen.GetEnvironment().Settings().Notify.Attachments
+	if err := checkAttachmentBucket(req.Attachments, conf); err != nil {
+		return nil, err
+	}
+	i, err := req.ToService()
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	attachments := i.([]util.Attachment)
+
+	jira, err := jiraHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	auth := &aws.Auth{
+		AccessKey: conf.Key,
+		SecretKey: conf.Secret,
+	}
+	if err = thirdparty.FetchS3Attachments(auth, conf.Bucket, conf.Prefix, attachments, util.AttachmentMaxSize); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return attachFilesToJiraIssue(jira, key, attachments)
+}
+
+// checkAttachmentBucket returns an error if any of the attachments are
+// stored in S3 outside of the configured attachment bucket and prefix.
+func checkAttachmentBucket(attachments []restModel.APIAttachment, conf evergreen.NotifyAttachmentsConfig) error {
+	for _, a := range attachments {
+		s3URL := restModel.FromAPIString(a.S3URL)
+		if s3URL != "" && !thirdparty.S3URLInBucket(s3URL, conf.Bucket, conf.Prefix) {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("attachment '%s' is not in the attachment bucket", restModel.FromAPIString(a.Filename)),
+			}
+		}
+	}
+
+	return nil
+}
+
+// jiraHandler returns a handler for the configured JIRA server, unless JIRA
+// notifications are disabled.
+func jiraHandler() (*thirdparty.JiraHandler, error) {
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get service flags")
+	}
+	if flags.JIRANotificationsDisabled {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "JIRA notifications are disabled",
+		}
+	}
+
+	settings := evergreen.GetEnvironment().Settings()
+	if settings.Jira.Host == "" {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "JIRA is not configured",
+		}
+	}
+	jira := thirdparty.NewJiraHandler(settings.Jira.GetHostURL(), settings.Jira.Username, settings.Jira.Password)
+
+	return &jira, nil
+}
+
+// jiraIssueAttacher is the part of the JIRA API used to attach files to
+// issues.
+type jiraIssueAttacher interface {
+	AttachFiles(string, []util.Attachment) ([]thirdparty.JiraAttachment, error)
+}
+
+func attachFilesToJiraIssue(jira jiraIssueAttacher, key string, attachments []util.Attachment) ([]restModel.APIJiraAttachment, error) {
+	created, err := jira.AttachFiles(key, attachments)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to attach files to JIRA issue '%s'", key)
+	}
+
+	out := make([]restModel.APIJiraAttachment, 0, len(created))
+	for i := range created {
+		attachment := restModel.APIJiraAttachment{}
+		if err = attachment.BuildFromService(&created[i]); err != nil {
+			return nil, errors.Wrap(err, "failed to build JIRA attachment response")
+		}
+		out = append(out, attachment)
+	}
+
+	return out, nil
+}
+
+// jiraIssueTransitioner is the part of the JIRA API used to transition
+// issues.
+type jiraIssueTransitioner interface {
+	GetTransitions(string) ([]thirdparty.JiraTransition, error)
+	TransitionTicket(string, string, map[string]interface{}, string) error
+	GetJIRATicket(string) (*thirdparty.JiraTicket, error)
+}
+
+func transitionJiraIssue(jira jiraIssueTransitioner, key string, req *restModel.APIJiraIssueTransition) (*restModel.APIJiraIssue, error) {
+	transitions, err := jira.GetTransitions(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get transitions of JIRA issue '%s'", key)
+	}
+	transition, err := req.FindTransition(transitions)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	if err = jira.TransitionTicket(key, transition.Id, req.Update, restModel.FromAPIString(req.Comment)); err != nil {
+		return nil, errors.Wrapf(err, "failed to transition JIRA issue '%s' with '%s'", key, transition.Name)
+	}
+
+	ticket, err := jira.GetJIRATicket(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get JIRA issue '%s'", key)
+	}
+	issue := restModel.APIJiraIssue{}
+	if err = issue.BuildFromService(ticket); err != nil {
+		return nil, errors.Wrap(err, "failed to build JIRA issue response")
+	}
+
+	return &issue, nil
+}
+
+func (c *NotificationConnector) GetNotificationTemplate(name string) (*restModel.APINotificationTemplate, error) {
+	t, err := findTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	apiTemplate := restModel.APINotificationTemplate{}
+	if err = apiTemplate.BuildFromService(t); err != nil {
+		return nil, errors.Wrap(err, "failed to build template response")
+	}
+
+	return &apiTemplate, nil
+}
+
+func (c *NotificationConnector) SaveNotificationTemplate(apiTemplate *restModel.APINotificationTemplate) (*restModel.APINotificationTemplate, error) {
+	i, err := apiTemplate.ToService()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert template")
+	}
+	t := i.(*notification.Template)
+	if err = t.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if err = t.Upsert(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out := restModel.APINotificationTemplate{}
+	if err = out.BuildFromService(t); err != nil {
+		return nil, errors.Wrap(err, "failed to build template response")
+	}
+
+	return &out, nil
+}
+
+func (c *NotificationConnector) SendTemplateNotification(queue amboy.Queue, req *restModel.APITemplateNotification, initiator string, dryRun bool) (*restModel.APINotification, error) {
+	t, err := findTemplate(restModel.FromAPIString(req.Template))
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := req.Subscriber.ToService()
+	if err != nil {
+		return nil, err
+	}
+	subscriber := i.(event.Subscriber)
+
+	payload, err := t.Payload(subscriber.Type, req.Data)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	n, err := notification.New(bson.NewObjectId().Hex(), "template-"+t.Name, &subscriber, payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create template notification")
+	}
+	n.Initiator = initiator
+	if dryRun {
+		return buildAPINotification(n, dryRun)
+	}
+	if err = notification.InsertMany(*n); err != nil {
+		return nil, errors.Wrap(err, "failed to insert template notification")
+	}
+	if err = queue.Put(units.NewEventNotificationJob(n.ID)); err != nil {
+		return nil, errors.Wrapf(err, "failed to enqueue template notification '%s'", n.ID)
+	}
+
+	return buildAPINotification(n, false)
+}
+
+func findTemplate(name string) (*notification.Template, error) {
+	t, err := notification.FindTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("template '%s' not found", name),
+		}
+	}
+
+	return t, nil
+}
+
+// buildAPINotification builds the response for the notification, with a
+// preview of the message it would send if it was composed in a dry run.
+func buildAPINotification(n *notification.Notification, dryRun bool) (*restModel.APINotification, error) {
+	apiNotification := restModel.APINotification{}
+	if err := apiNotification.BuildFromService(n); err != nil {
+		return nil, errors.Wrap(err, "failed to build notification response")
+	}
+	if !dryRun {
+		return &apiNotification, nil
+	}
+
+	key, err := n.SenderKey()
+	if err != nil {
+		return nil, errors.Wrap(err, "can't resolve sender for notification")
+	}
+	c, err := n.Composer()
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if !c.Loggable() {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "composed message would not be sent",
+		}
+	}
+	apiNotification.Preview = &restModel.APINotificationPreview{
+		Sender:  restModel.ToAPIString(key.String()),
+		Message: restModel.ToAPIString(c.String()),
+		Payload: c.Raw(),
+	}
+
+	return &apiNotification, nil
+}
+
+func findNotification(id string) (*notification.Notification, error) {
+	n, err := notification.Find(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch notification '%s'", id)
+	}
+	if n == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("notification '%s' not found", id),
+		}
+	}
+
+	return n, nil
+}
+
+func findIncident(id string) (*notification.Incident, error) {
+	incident, err := notification.FindIncident(id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch incident '%s'", id)
+	}
+	if incident == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("incident '%s' not found", id),
+		}
+	}
+
+	return incident, nil
+}
+
+func buildAPIIncident(incident *notification.Incident) (*restModel.APIIncident, error) {
+	notifications, err := notification.FindByIncident(incident.ID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	apiIncident := restModel.APIIncident{}
+	if err = apiIncident.BuildFromService(incident); err != nil {
+		return nil, errors.Wrap(err, "failed to build incident response")
+	}
+	for i := range notifications {
+		apiIncident.NotificationIDs = append(apiIncident.NotificationIDs, restModel.ToAPIString(notifications[i].ID))
+	}
+
+	return &apiIncident, nil
+}
+
+type MockNotificationConnector struct {
+	// IdempotentWebhooks are the webhooks sent with an idempotency key, by
+	// their scoped key
+	IdempotentWebhooks map[string]*notification.Notification
+
+	CachedNotifications []notification.Notification
+	CachedIncidents     []notification.Incident
+}
 
 func (c *MockNotificationConnector) GetNotificationsStats() (*restModel.APIEventStats, error) {
 	return nil, errors.New("not implemented")
 }
+
+func (c *MockNotificationConnector) GetNotificationAnalytics(time.Time, time.Time, time.Duration, int) (*restModel.APINotificationAnalytics, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) GetNotification(id string) (*restModel.APINotification, error) {
+	for i := range c.CachedNotifications {
+		if c.CachedNotifications[i].ID == id {
+			return buildAPINotification(&c.CachedNotifications[i], false)
+		}
+	}
+
+	return nil, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("notification '%s' not found", id),
+	}
+}
+
+func (c *MockNotificationConnector) FindNotificationAuditEntries(notification.AuditFilter, int) ([]restModel.APINotificationAuditEntry, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) LinkNotificationToIncident(string, *restModel.APIIncidentLink) (*restModel.APIIncident, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) AcknowledgeNotification(string, string) (*restModel.APIEscalation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) GetIncident(id string) (*restModel.APIIncident, error) {
+	incident, err := c.findIncident(id)
+	if err != nil {
+		return nil, err
+	}
+
+	apiIncident := restModel.APIIncident{}
+	if err = apiIncident.BuildFromService(incident); err != nil {
+		return nil, errors.Wrap(err, "failed to build incident response")
+	}
+
+	return &apiIncident, nil
+}
+
+func (c *MockNotificationConnector) ResolveIncident(id string) (*restModel.APIIncident, error) {
+	incident, err := c.findIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.IsResolved() {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("incident '%s' is already resolved", id),
+		}
+	}
+	incident.ResolvedAt = time.Now()
+
+	return c.GetIncident(id)
+}
+
+func (c *MockNotificationConnector) findIncident(id string) (*notification.Incident, error) {
+	for i := range c.CachedIncidents {
+		if c.CachedIncidents[i].ID == id {
+			return &c.CachedIncidents[i], nil
+		}
+	}
+
+	return nil, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("incident '%s' not found", id),
+	}
+}
+
+func (c *MockNotificationConnector) SendWebhook(_ amboy.Queue, webhook *restModel.APIWebhook, initiator string, dryRun bool) (*restModel.APINotification, error) {
+	n, err := newWebhookNotification(webhook, initiator)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun && n.IdempotencyKey != "" {
+		if c.IdempotentWebhooks == nil {
+			c.IdempotentWebhooks = map[string]*notification.Notification{}
+		}
+		if existing, ok := c.IdempotentWebhooks[n.IdempotencyKey]; ok {
+			n = existing
+		} else {
+			c.IdempotentWebhooks[n.IdempotencyKey] = n
+		}
+	}
+
+	return buildAPINotification(n, dryRun)
+}
+
+func (c *MockNotificationConnector) SendEmail(_ amboy.Queue, email *restModel.APIEmail, initiator string, dryRun bool) ([]restModel.APINotification, error) {
+	notifications, err := newEmailNotifications(email, initiator)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]restModel.APINotification, 0, len(notifications))
+	for i := range notifications {
+		apiNotification, err := buildAPINotification(&notifications[i], dryRun)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *apiNotification)
+	}
+
+	return out, nil
+}
+
+func (c *MockNotificationConnector) SendSlack(_ amboy.Queue, slack *restModel.APISlack, initiator string, dryRun bool) (*restModel.APINotification, error) {
+	n, err := newSlackNotification(slack, initiator)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAPINotification(n, dryRun)
+}
+
+func (c *MockNotificationConnector) SendOpsgenie(_ amboy.Queue, alert *restModel.APIOpsgenieAlert, initiator string, dryRun bool) (*restModel.APINotification, error) {
+	n, err := newOpsgenieNotification(alert, initiator)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAPINotification(n, dryRun)
+}
+
+func (c *MockNotificationConnector) TransitionJiraIssue(string, *restModel.APIJiraIssueTransition) (*restModel.APIJiraIssue, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) AttachFilesToJiraIssue(string, *restModel.APIJiraIssueAttachments) ([]restModel.APIJiraAttachment, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) GetNotificationTemplate(string) (*restModel.APINotificationTemplate, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) SaveNotificationTemplate(*restModel.APINotificationTemplate) (*restModel.APINotificationTemplate, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *MockNotificationConnector) SendTemplateNotification(amboy.Queue, *restModel.APITemplateNotification, string, bool) (*restModel.APINotification, error) {
+	return nil, errors.New("not implemented")
+}
@@ -0,0 +1,122 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockJiraIssueAttacher struct {
+	attached []util.Attachment
+}
+
+func (j *mockJiraIssueAttacher) AttachFiles(_ string, files []util.Attachment) ([]thirdparty.JiraAttachment, error) {
+	j.attached = files
+	out := []thirdparty.JiraAttachment{}
+	for i, f := range files {
+		out = append(out, thirdparty.JiraAttachment{Id: fmt.Sprint(i), Filename: f.Filename, Size: len(f.Data)})
+	}
+	return out, nil
+}
+
+type mockJiraIssueTransitioner struct {
+	transitions  []thirdparty.JiraTransition
+	transitioned string
+	fields       map[string]interface{}
+	comment      string
+}
+
+func (j *mockJiraIssueTransitioner) GetTransitions(string) ([]thirdparty.JiraTransition, error) {
+	return j.transitions, nil
+}
+
+func (j *mockJiraIssueTransitioner) TransitionTicket(_, id string, fields map[string]interface{}, comment string) error {
+	j.transitioned = id
+	j.fields = fields
+	j.comment = comment
+	return nil
+}
+
+func (j *mockJiraIssueTransitioner) GetJIRATicket(key string) (*thirdparty.JiraTicket, error) {
+	return &thirdparty.JiraTicket{
+		Key: key,
+		Fields: &thirdparty.TicketFields{
+			Summary: "test failed",
+			Status:  &thirdparty.JiraStatus{Name: "Reopened"},
+		},
+	}, nil
+}
+
+func TestTransitionJiraIssue(t *testing.T) {
+	assert := assert.New(t)
+
+	jira := &mockJiraIssueTransitioner{
+		transitions: []thirdparty.JiraTransition{
+			{Id: "2", Name: "Close Issue", To: &thirdparty.JiraStatus{Name: "Closed"}},
+			{Id: "3", Name: "Reopen Issue", To: &thirdparty.JiraStatus{Name: "Reopened"}},
+		},
+	}
+	issue, err := transitionJiraIssue(jira, "BF-1", &restModel.APIJiraIssueTransition{
+		Transition: restModel.ToAPIString("reopened"),
+		Update:     map[string]interface{}{"labels": []string{"refailed"}},
+		Comment:    restModel.ToAPIString("failed again"),
+	})
+	assert.NoError(err)
+	assert.Equal("3", jira.transitioned)
+	assert.Equal(map[string]interface{}{"labels": []string{"refailed"}}, jira.fields)
+	assert.Equal("failed again", jira.comment)
+	assert.Equal("BF-1", restModel.FromAPIString(issue.Key))
+	assert.Equal("Reopened", restModel.FromAPIString(issue.Status))
+
+	_, err = transitionJiraIssue(jira, "BF-1", &restModel.APIJiraIssueTransition{
+		Transition: restModel.ToAPIString("Resolve Issue"),
+	})
+	assert.Error(err)
+	resp, ok := err.(gimlet.ErrorResponse)
+	assert.True(ok)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestAttachFilesToJiraIssue(t *testing.T) {
+	assert := assert.New(t)
+
+	jira := &mockJiraIssueAttacher{}
+	files := []util.Attachment{{Filename: "task.log", Data: []byte("log")}}
+	attachments, err := attachFilesToJiraIssue(jira, "BF-1", files)
+	assert.NoError(err)
+	assert.Equal(files, jira.attached)
+	assert.Len(attachments, 1)
+	assert.Equal("task.log", restModel.FromAPIString(attachments[0].Filename))
+	assert.Equal(3, attachments[0].Size)
+}
+
+func TestCheckAttachmentBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := evergreen.NotifyAttachmentsConfig{Bucket: "attachments", Prefix: "notify/"}
+	attachments := []restModel.APIAttachment{
+		{Filename: restModel.ToAPIString("inline.log"), Content: restModel.ToAPIString("bG9n")},
+		{Filename: restModel.ToAPIString("task.log"), S3URL: restModel.ToAPIString("s3://attachments/notify/task.log")},
+	}
+	assert.NoError(checkAttachmentBucket(attachments, conf))
+
+	attachments = append(attachments, restModel.APIAttachment{
+		Filename: restModel.ToAPIString("secret"),
+		S3URL:    restModel.ToAPIString("s3://secrets/credentials"),
+	})
+	err := checkAttachmentBucket(attachments, conf)
+	assert.Error(err)
+	errResp, ok := err.(gimlet.ErrorResponse)
+	assert.True(ok)
+	assert.Equal(http.StatusBadRequest, errResp.StatusCode)
+
+	// S3 attachments are rejected unless a bucket is configured
+	assert.Error(checkAttachmentBucket(attachments[1:2], evergreen.NotifyAttachmentsConfig{}))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/google/go-github/github"
 	"github.com/pkg/errors"
@@ -80,6 +81,16 @@ func (pc *DBPatchConnector) SetPatchActivated(patchId string, user string, activ
 	return model.SetVersionActivation(patchId, activated, user)
 }
 
+// SetPatchPinned sets whether the patch is kept once it has gone unfinalized
+// for longer than its project's patch expiration.
+func (pc *DBPatchConnector) SetPatchPinned(patchId string, pinned bool) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(p.SetPinned(pinned), "problem pinning patch '%s'", patchId)
+}
+
 func (pc *DBPatchConnector) FindPatchesByUser(user string, ts time.Time, limit int) ([]patch.Patch, error) {
 	patches, err := patch.Find(patch.ByUserPaginated(user, ts, limit))
 	if err != nil {
@@ -107,6 +118,134 @@ func (p *DBPatchConnector) AbortPatchesFromPullRequest(event *github.PullRequest
 	return nil
 }
 
+// FindGithubCheckSuiteVersion returns the ID of the newest pull request
+// patch whose head is the commit, or else of the mainline version of the
+// commit in a project tracking the repository's branch.
+func (pc *DBPatchConnector) FindGithubCheckSuiteVersion(owner, repo, branch, hash string) (string, error) {
+	p, err := patch.FindOne(patch.ByGithubHeadHash(owner, repo, hash))
+	if err != nil {
+		return "", errors.Wrapf(err, "problem finding patches of commit '%s'", hash)
+	}
+	if p != nil {
+		return p.Version, nil
+	}
+
+	projectRefs, err := model.FindProjectRefsByRepoAndBranch(owner, repo, branch)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem finding projects tracking '%s/%s' branch '%s'", owner, repo, branch)
+	}
+	for _, projectRef := range projectRefs {
+		v, err := version.FindOne(version.ByProjectIdAndRevision(projectRef.Identifier, hash))
+		if err != nil {
+			return "", errors.Wrapf(err, "problem finding version of commit '%s'", hash)
+		}
+		if v != nil {
+			return v.Id, nil
+		}
+	}
+
+	return "", nil
+}
+
+// FindPreviousPatch returns the user's newest patch of the project, or a 404
+// error if the user has never patched the project.
+func (pc *DBPatchConnector) FindPreviousPatch(user, projectId string) (*patch.Patch, error) {
+	p, err := patch.FindOne(patch.MostRecentByUserAndProject(user, projectId))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding previous patch of project '%s' by '%s'", projectId, user)
+	}
+	if p == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no previous patch of project '%s' by '%s'", projectId, user),
+		}
+	}
+
+	return p, nil
+}
+
+// ConfigurePatch adds variants and tasks to a finalized patch. The requested
+// pairs are merged with the patch's existing ones and expanded to include
+// their dependencies, and only the builds and tasks that do not exist yet in
+// the patch's version are created.
+func (pc *DBPatchConnector) ConfigurePatch(patchId string, variantsTasks []patch.VariantTasks) (*patch.Patch, error) {
+	if !bson.IsObjectIdHex(patchId) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("'%s' is not a valid patch id", patchId),
+		}
+	}
+	p, err := patch.FindOne(patch.ById(bson.ObjectIdHex(patchId)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding patch '%s'", patchId)
+	}
+	if p == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("patch with id %s not found", patchId),
+		}
+	}
+	if p.Version == "" {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("patch '%s' has not been finalized", patchId),
+		}
+	}
+
+	project := &model.Project{}
+	if err = model.LoadProjectInto([]byte(p.PatchedConfig), p.Project, project); err != nil {
+		return nil, errors.Wrapf(err, "problem loading configuration of patch '%s'", patchId)
+	}
+
+	pairs := model.VariantTasksToTVPairs(append(p.VariantsTasks, variantsTasks...))
+	pairs.ExecTasks = model.IncludePatchDependencies(project, pairs.ExecTasks)
+	pairs.DisplayTasks = uniqueTVPairs(pairs.DisplayTasks)
+	if err = model.ValidateTVPairs(project, pairs.ExecTasks); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	v, err := version.FindOne(version.ById(p.Version))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding version '%s'", p.Version)
+	}
+	if v == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("version '%s' of patch '%s' not found", p.Version, patchId),
+		}
+	}
+
+	// the new builds and tasks are run, as if the patch were scheduled anew
+	p.Activated = true
+	if err = model.AddNewTasksForPatch(p, v, project, pairs); err != nil {
+		return nil, errors.Wrapf(err, "problem creating new tasks for version '%s'", v.Id)
+	}
+	if err = model.AddNewBuildsForPatch(p, v, project, pairs); err != nil {
+		return nil, errors.Wrapf(err, "problem creating new builds for version '%s'", v.Id)
+	}
+	if err = p.SetVariantsTasks(pairs.TVPairsToVariantTasks()); err != nil {
+		return nil, errors.Wrapf(err, "problem updating variants and tasks of patch '%s'", patchId)
+	}
+
+	return p, nil
+}
+
+func uniqueTVPairs(pairs model.TVPairSet) model.TVPairSet {
+	seen := map[model.TVPair]bool{}
+	out := model.TVPairSet{}
+	for _, pair := range pairs {
+		if seen[pair] {
+			continue
+		}
+		seen[pair] = true
+		out = append(out, pair)
+	}
+	return out
+}
+
 // MockPatchConnector is a struct that implements the Patch related methods
 // from the Connector through interactions with he backing database.
 type MockPatchConnector struct {
@@ -187,6 +326,16 @@ func (pc *MockPatchConnector) SetPatchActivated(patchId string, user string, act
 	return nil
 }
 
+// SetPatchPinned sets the boolean pinned field on the input patch.
+func (pc *MockPatchConnector) SetPatchPinned(patchId string, pinned bool) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	p.Pinned = pinned
+	return nil
+}
+
 // FindPatchesByUser iterates through the cached patches slice to find the correct patches
 func (hp *MockPatchConnector) FindPatchesByUser(user string, ts time.Time, limit int) ([]patch.Patch, error) {
 	patchesToReturn := []patch.Patch{}
@@ -230,3 +379,69 @@ func verifyPullRequestEventForAbort(event *github.PullRequestEvent) (string, str
 
 	return baseRepo[0], baseRepo[1], nil
 }
+
+// FindGithubCheckSuiteVersion returns the ID of the version of the newest
+// cached pull request patch whose head is the commit.
+func (c *MockPatchConnector) FindGithubCheckSuiteVersion(owner, repo, branch, hash string) (string, error) {
+	var newest *patch.Patch
+	for i, p := range c.CachedPatches {
+		if p.GithubPatchData.BaseOwner != owner || p.GithubPatchData.BaseRepo != repo ||
+			p.GithubPatchData.HeadHash != hash || p.Version == "" {
+			continue
+		}
+		if newest == nil || p.CreateTime.After(newest.CreateTime) {
+			newest = &c.CachedPatches[i]
+		}
+	}
+	if newest == nil {
+		return "", nil
+	}
+
+	return newest.Version, nil
+}
+
+// FindPreviousPatch returns the newest of the cached patches of the project by
+// the user.
+func (pc *MockPatchConnector) FindPreviousPatch(user, projectId string) (*patch.Patch, error) {
+	var previous *patch.Patch
+	for idx := range pc.CachedPatches {
+		p := &pc.CachedPatches[idx]
+		if p.Author != user || p.Project != projectId {
+			continue
+		}
+		if previous == nil || p.CreateTime.After(previous.CreateTime) {
+			previous = p
+		}
+	}
+	if previous == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("no previous patch of project '%s' by '%s'", projectId, user),
+		}
+	}
+
+	return previous, nil
+}
+
+// ConfigurePatch merges the variants and tasks into those of the cached
+// patch, which must have been finalized.
+func (pc *MockPatchConnector) ConfigurePatch(patchId string, variantsTasks []patch.VariantTasks) (*patch.Patch, error) {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return nil, err
+	}
+	if p.Version == "" {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("patch '%s' has not been finalized", patchId),
+		}
+	}
+
+	pairs := model.VariantTasksToTVPairs(append(p.VariantsTasks, variantsTasks...))
+	pairs.ExecTasks = uniqueTVPairs(pairs.ExecTasks)
+	pairs.DisplayTasks = uniqueTVPairs(pairs.DisplayTasks)
+	p.SyncVariantsTasks(pairs.TVPairsToVariantTasks())
+	p.Activated = true
+
+	return p, nil
+}
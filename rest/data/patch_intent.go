@@ -1,6 +1,9 @@
 package data
 
 import (
+	"context"
+	"io"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/evergreen-ci/evergreen/model"
@@ -64,13 +67,59 @@ func (p *DBPatchIntentConnector) AddPatchIntent(intent patch.Intent, queue amboy
 	return nil
 }
 
+// UploadPatchFile streams the diff into gridfs.
+func (p *DBPatchIntentConnector) UploadPatchFile(diff io.Reader) (string, error) {
+	patchFileID, err := patch.UploadPatchFile(diff)
+	if err == patch.ErrUploadTooLarge {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    err.Error(),
+		}
+	}
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return patchFileID.Hex(), nil
+}
+
+// CreatePatchFromIntent processes the intent immediately, rather than on the
+// queue, so that the caller gets the patch back.
+func (p *DBPatchIntentConnector) CreatePatchFromIntent(ctx context.Context, intent patch.Intent) (*patch.Patch, error) {
+	if err := intent.Insert(); err != nil {
+		return nil, errors.Wrap(err, "couldn't insert patch intent")
+	}
+
+	patchID := bson.NewObjectId()
+	job := units.NewPatchIntentProcessor(patchID, intent)
+	job.Run(ctx)
+	if err := job.Error(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "problem processing patch").Error(),
+		}
+	}
+
+	patchDoc, err := patch.FindOne(patch.ById(patchID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "can't find patch '%s'", patchID.Hex())
+	}
+	if patchDoc == nil {
+		return nil, errors.Errorf("patch '%s' wasn't created", patchID.Hex())
+	}
+
+	return patchDoc, nil
+}
+
 type MockPatchIntentKey struct {
 	intentType string
 	msgID      string
 }
 
 type MockPatchIntentConnector struct {
-	CachedIntents map[MockPatchIntentKey]patch.Intent
+	CachedIntents       map[MockPatchIntentKey]patch.Intent
+	CachedPatchFiles    map[string]string
+	CachedIntentPatches []patch.Patch
 }
 
 func (p *MockPatchIntentConnector) AddPatchIntent(newIntent patch.Intent, _ amboy.Queue) error {
@@ -82,3 +131,36 @@ func (p *MockPatchIntentConnector) AddPatchIntent(newIntent patch.Intent, _ ambo
 
 	return nil
 }
+
+func (p *MockPatchIntentConnector) UploadPatchFile(diff io.Reader) (string, error) {
+	content, err := ioutil.ReadAll(io.LimitReader(diff, patch.UploadSizeLimit+1))
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	if len(content) > patch.UploadSizeLimit {
+		return "", gimlet.ErrorResponse{
+			StatusCode: http.StatusRequestEntityTooLarge,
+			Message:    patch.ErrUploadTooLarge.Error(),
+		}
+	}
+
+	if p.CachedPatchFiles == nil {
+		p.CachedPatchFiles = map[string]string{}
+	}
+	patchFileID := bson.NewObjectId().Hex()
+	p.CachedPatchFiles[patchFileID] = string(content)
+
+	return patchFileID, nil
+}
+
+func (p *MockPatchIntentConnector) CreatePatchFromIntent(_ context.Context, intent patch.Intent) (*patch.Patch, error) {
+	patchDoc := intent.NewPatch()
+	patchFileID := patchDoc.Patches[0].PatchSet.PatchFileId
+	if _, ok := p.CachedPatchFiles[patchFileID]; !ok {
+		return nil, errors.Errorf("patch file '%s' was never uploaded", patchFileID)
+	}
+	patchDoc.Id = bson.NewObjectId()
+	p.CachedIntentPatches = append(p.CachedIntentPatches, *patchDoc)
+
+	return patchDoc, nil
+}
@@ -1,7 +1,23 @@
 package data
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/coverage"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/projectinsights"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -19,11 +35,115 @@ func (pc *DBProjectConnector) FindProjects(key string, limit int, sortDir int, i
 	return projects, nil
 }
 
+// SetProjectPriority sets the priority that the project's tasks inherit.
+func (pc *DBProjectConnector) SetProjectPriority(ref *model.ProjectRef, priority int64) error {
+	if priority < 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "project priority can't be negative",
+		}
+	}
+	return errors.Wrapf(ref.SetPriority(priority), "problem setting priority of project '%s'", ref.Identifier)
+}
+
+// UpdateProjectCoverage replaces the tasks that cover each of the files of
+// the project.
+func (pc *DBProjectConnector) UpdateProjectCoverage(projectId string, tasksByFile map[string][]string) error {
+	return errors.Wrapf(coverage.Update(projectId, tasksByFile), "problem updating coverage of project '%s'", projectId)
+}
+
+// FindProjectInsights returns the insights into the project's mainline
+// builds that were last computed for the window, or for every window if it
+// is 0.
+func (pc *DBProjectConnector) FindProjectInsights(projectId string, windowDays int) ([]projectinsights.ProjectInsights, error) {
+	insights, err := projectinsights.Find(projectinsights.ByProject(projectId, windowDays))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding insights of project '%s'", projectId)
+	}
+	return insights, nil
+}
+
+// MigrateProjectVarsToSecrets moves the project's variables into the
+// requested secrets manager, and returns the names of the variables that
+// were moved.
+func (pc *DBProjectConnector) MigrateProjectVarsToSecrets(ctx context.Context, projectId string, req *restModel.APIProjectVarsMigration) ([]string, error) {
+	backendName := restModel.FromAPIString(req.Backend)
+	if err := validateSecretsBackend(backendName); err != nil {
+		return nil, err
+	}
+	settings := evergreen.GetEnvironment().Settings()
+	if settings == nil {
+		return nil, errors.New("evergreen settings are not loaded")
+	}
+	backends, err := model.NewSecretsBackends(&settings.Secrets)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	backend, ok := backends[backendName]
+	if !ok {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("secrets backend '%s' is not configured", backendName),
+		}
+	}
+
+	return model.MigrateProjectVarsToSecrets(ctx, projectId, backendName, backend,
+		settings.Secrets.Prefix, req.PrivateOnly)
+}
+
+// GetProjectSettings returns a snapshot of the project's settings.
+func (pc *DBProjectConnector) GetProjectSettings(projectId string) (*model.ProjectSettings, error) {
+	return model.GetProjectSettings(projectId)
+}
+
+// LogProjectModified records the user's change to the project's settings.
+func (pc *DBProjectConnector) LogProjectModified(projectId, user string, before, after *model.ProjectSettings) error {
+	return model.LogProjectModified(projectId, user, before, after)
+}
+
+// GetProjectEventLog returns the n most recent changes to the project's
+// settings before the given time.
+func (pc *DBProjectConnector) GetProjectEventLog(projectId string, before time.Time, n int) ([]restModel.APIProjectEvent, error) {
+	events, err := model.FindProjectEvents(projectId, before, n)
+	if err != nil {
+		return nil, err
+	}
+	return buildAPIProjectEvents(events)
+}
+
+func buildAPIProjectEvents(events []event.EventLogEntry) ([]restModel.APIProjectEvent, error) {
+	out := []restModel.APIProjectEvent{}
+	catcher := grip.NewBasicCatcher()
+	for _, evt := range events {
+		apiEvent := restModel.APIProjectEvent{}
+		if err := apiEvent.BuildFromService(evt); err != nil {
+			catcher.Add(err)
+			continue
+		}
+		out = append(out, apiEvent)
+	}
+
+	return out, catcher.Resolve()
+}
+
+func validateSecretsBackend(backend string) error {
+	if !util.StringSliceContains(thirdparty.ValidSecretsBackends, backend) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid secrets backend '%s'", backend),
+		}
+	}
+	return nil
+}
+
 // MockPatchConnector is a struct that implements the Patch related methods
 // from the Connector through interactions with he backing database.
 type MockProjectConnector struct {
 	CachedProjects []model.ProjectRef
 	CachedVars     []*model.ProjectVars
+	CachedCoverage map[string]map[string][]string
+	CachedInsights []projectinsights.ProjectInsights
+	CachedEvents   []event.EventLogEntry
 }
 
 // FindProjects queries the cached projects slice for the matching projects.
@@ -55,3 +175,129 @@ func (pc *MockProjectConnector) FindProjects(key string, limit int, sortDir int,
 	}
 	return projects, nil
 }
+
+// SetProjectPriority sets the priority of the project ref.
+func (pc *MockProjectConnector) SetProjectPriority(ref *model.ProjectRef, priority int64) error {
+	if priority < 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "project priority can't be negative",
+		}
+	}
+	ref.Priority = priority
+	return nil
+}
+
+// UpdateProjectCoverage replaces the cached tasks that cover each of the
+// files of the project.
+func (pc *MockProjectConnector) UpdateProjectCoverage(projectId string, tasksByFile map[string][]string) error {
+	if pc.CachedCoverage == nil {
+		pc.CachedCoverage = map[string]map[string][]string{}
+	}
+	if pc.CachedCoverage[projectId] == nil {
+		pc.CachedCoverage[projectId] = map[string][]string{}
+	}
+	for file, tasks := range tasksByFile {
+		pc.CachedCoverage[projectId][file] = tasks
+	}
+	return nil
+}
+
+// FindProjectInsights returns the cached insights of the project over the
+// window, or over every window if it is 0.
+func (pc *MockProjectConnector) FindProjectInsights(projectId string, windowDays int) ([]projectinsights.ProjectInsights, error) {
+	insights := []projectinsights.ProjectInsights{}
+	for _, i := range pc.CachedInsights {
+		if i.Id.Project != projectId || (windowDays != 0 && i.Id.WindowDays != windowDays) {
+			continue
+		}
+		insights = append(insights, i)
+	}
+	return insights, nil
+}
+
+// MigrateProjectVarsToSecrets replaces the cached variables of the project
+// with references to secrets, without storing their values anywhere.
+func (pc *MockProjectConnector) MigrateProjectVarsToSecrets(ctx context.Context, projectId string, req *restModel.APIProjectVarsMigration) ([]string, error) {
+	backend := restModel.FromAPIString(req.Backend)
+	if err := validateSecretsBackend(backend); err != nil {
+		return nil, err
+	}
+
+	migrated := []string{}
+	for _, vars := range pc.CachedVars {
+		if vars.Id != projectId {
+			continue
+		}
+		if vars.SecretVars == nil {
+			vars.SecretVars = map[string]model.SecretReference{}
+		}
+		for name := range vars.Vars {
+			if name == model.ProjectAWSSSHKeyName || name == model.ProjectAWSSSHKeyValue {
+				continue
+			}
+			if req.PrivateOnly && !vars.PrivateVars[name] {
+				continue
+			}
+			vars.SecretVars[name] = model.SecretReference{Backend: backend, Name: projectId + "/" + name}
+			delete(vars.Vars, name)
+			delete(vars.PrivateVars, name)
+			migrated = append(migrated, name)
+		}
+	}
+	sort.Strings(migrated)
+
+	return migrated, nil
+}
+
+// GetProjectSettings returns a snapshot of the cached project and its
+// variables.
+func (pc *MockProjectConnector) GetProjectSettings(projectId string) (*model.ProjectSettings, error) {
+	settings := &model.ProjectSettings{ProjectRef: model.ProjectRef{Identifier: projectId}}
+	for _, p := range pc.CachedProjects {
+		if p.Identifier == projectId {
+			settings.ProjectRef = p
+		}
+	}
+	for _, vars := range pc.CachedVars {
+		if vars.Id != projectId {
+			continue
+		}
+		settings.Vars = *vars
+		// the snapshot doesn't change along with the cached variables
+		settings.Vars.Vars = map[string]string{}
+		for k, v := range vars.Vars {
+			settings.Vars.Vars[k] = v
+		}
+	}
+	return settings, nil
+}
+
+// LogProjectModified caches the user's change to the project's settings,
+// unless they are unchanged.
+func (pc *MockProjectConnector) LogProjectModified(projectId, user string, before, after *model.ProjectSettings) error {
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	pc.CachedEvents = append(pc.CachedEvents, event.EventLogEntry{
+		ResourceType: event.ResourceTypeProject,
+		ResourceId:   projectId,
+		EventType:    event.EventTypeProjectModified,
+		Timestamp:    time.Now(),
+		Data:         &model.ProjectChangeEvent{User: user, Before: *before, After: *after},
+	})
+	return nil
+}
+
+// GetProjectEventLog returns the n most recent cached changes to the
+// project's settings before the given time.
+func (pc *MockProjectConnector) GetProjectEventLog(projectId string, before time.Time, n int) ([]restModel.APIProjectEvent, error) {
+	events := []event.EventLogEntry{}
+	for i := len(pc.CachedEvents) - 1; i >= 0 && len(events) < n; i-- {
+		evt := pc.CachedEvents[i]
+		if evt.ResourceId == projectId && evt.Timestamp.Before(before) {
+			events = append(events, evt)
+		}
+	}
+	return buildAPIProjectEvents(events)
+}
@@ -0,0 +1,130 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/quota"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+func (ac *DBAdminConnector) FindProjectQuotas() ([]restModel.APIProjectQuota, error) {
+	quotas, err := quota.FindAll()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIProjectQuotas(quotas)
+}
+
+func (ac *DBAdminConnector) SetProjectQuota(in *restModel.APIProjectQuota, updatedBy string) (*restModel.APIProjectQuota, error) {
+	q, err := newProjectQuota(in, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+	if err = q.Upsert(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIProjectQuota(q)
+}
+
+func (ac *DBAdminConnector) DeleteProjectQuota(project string) error {
+	q, err := quota.FindOne(project)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if q == nil {
+		return projectQuotaNotFound(project)
+	}
+
+	return errors.WithStack(quota.Remove(project))
+}
+
+func newProjectQuota(in *restModel.APIProjectQuota, updatedBy string) (*quota.ProjectQuota, error) {
+	i, err := in.ToService()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	q := i.(*quota.ProjectQuota)
+	if err = q.Validate(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	q.UpdatedBy = updatedBy
+	q.UpdatedAt = time.Now()
+
+	return q, nil
+}
+
+func projectQuotaNotFound(project string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("project '%s' has no quota", project),
+	}
+}
+
+func buildAPIProjectQuota(q *quota.ProjectQuota) (*restModel.APIProjectQuota, error) {
+	apiQuota := restModel.APIProjectQuota{}
+	if err := apiQuota.BuildFromService(q); err != nil {
+		return nil, errors.Wrap(err, "failed to build project quota response")
+	}
+
+	return &apiQuota, nil
+}
+
+func buildAPIProjectQuotas(quotas []quota.ProjectQuota) ([]restModel.APIProjectQuota, error) {
+	out := make([]restModel.APIProjectQuota, 0, len(quotas))
+	for i := range quotas {
+		apiQuota, err := buildAPIProjectQuota(&quotas[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *apiQuota)
+	}
+
+	return out, nil
+}
+
+func (ac *MockAdminConnector) FindProjectQuotas() ([]restModel.APIProjectQuota, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	return buildAPIProjectQuotas(ac.MockProjectQuotas)
+}
+
+func (ac *MockAdminConnector) SetProjectQuota(in *restModel.APIProjectQuota, updatedBy string) (*restModel.APIProjectQuota, error) {
+	q, err := newProjectQuota(in, updatedBy)
+	if err != nil {
+		return nil, err
+	}
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for i := range ac.MockProjectQuotas {
+		if ac.MockProjectQuotas[i].Project == q.Project {
+			ac.MockProjectQuotas[i] = *q
+			return buildAPIProjectQuota(q)
+		}
+	}
+	ac.MockProjectQuotas = append(ac.MockProjectQuotas, *q)
+
+	return buildAPIProjectQuota(q)
+}
+
+func (ac *MockAdminConnector) DeleteProjectQuota(project string) error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for i := range ac.MockProjectQuotas {
+		if ac.MockProjectQuotas[i].Project == project {
+			ac.MockProjectQuotas = append(ac.MockProjectQuotas[:i], ac.MockProjectQuotas[i+1:]...)
+			return nil
+		}
+	}
+
+	return projectQuotaNotFound(project)
+}
@@ -1,12 +1,17 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/google/go-github/github"
@@ -17,7 +22,10 @@ import (
 	"github.com/pkg/errors"
 )
 
-const branchRefPrefix = "refs/heads/"
+const (
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+)
 
 type RepoTrackerConnector struct{}
 
@@ -31,7 +39,8 @@ func (c *RepoTrackerConnector) TriggerRepotracker(q amboy.Queue, msgID string, e
 		}))
 		return err
 	}
-	if len(branch) == 0 {
+	isTag := isTagPushEvent(event)
+	if len(branch) == 0 && !isTag {
 		return nil
 	}
 
@@ -51,6 +60,9 @@ func (c *RepoTrackerConnector) TriggerRepotracker(q amboy.Queue, msgID string, e
 		})
 		return errors.New("repotracker is disabled")
 	}
+	if isTag {
+		return triggerTagJobs(q, msgID, event)
+	}
 
 	refs, err := validateProjectRefs(*event.Repo.Owner.Name, *event.Repo.Name, branch)
 	if err != nil {
@@ -128,6 +140,90 @@ func (c *RepoTrackerConnector) TriggerRepotracker(q amboy.Queue, msgID string, e
 	return nil
 }
 
+// LoadRepotrackerFixture runs the repotracker for the project against the
+// fixture's synthetic revisions instead of its repository, and returns the
+// IDs of the versions of the revisions.
+func (c *RepoTrackerConnector) LoadRepotrackerFixture(ctx context.Context, projectID string, fixture *restModel.APIRepoTrackerFixture) ([]string, error) {
+	ref, err := model.FindOneProjectRef(projectID)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+	if ref == nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", projectID),
+		}
+	}
+
+	revisions := fixtureRevisions(fixture)
+	settings := evergreen.GetEnvironment().Settings()
+	if err = repotracker.LoadFixtures(ctx, settings, ref, restModel.FromAPIString(fixture.Config), revisions); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	versionIDs := []string{}
+	for _, r := range revisions {
+		v, err := version.FindOne(version.ByProjectIdAndRevision(ref.Identifier, r.Revision.Revision))
+		if err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    err.Error(),
+			}
+		}
+		if v != nil {
+			versionIDs = append(versionIDs, v.Id)
+		}
+	}
+
+	return versionIDs, nil
+}
+
+// CreateManualVersion creates a version of the project at the requested
+// revision through the repotracker, with the manual requester.
+func (c *RepoTrackerConnector) CreateManualVersion(ctx context.Context, ref *model.ProjectRef, req *restModel.APIManualVersion, user string) (*version.Version, error) {
+	settings := evergreen.GetEnvironment().Settings()
+	v, err := repotracker.CreateManualVersion(ctx, settings, ref, restModel.FromAPIString(req.Revision),
+		restModel.FromAPIString(req.Config), restModel.FromAPIString(req.Message), user, req.ServiceParameters())
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return v, nil
+}
+
+// fixtureRevisions returns the revisions of the fixture. Revisions without
+// a creation time are spaced a minute apart, ending now, in the order given.
+func fixtureRevisions(fixture *restModel.APIRepoTrackerFixture) []repotracker.FixtureRevision {
+	now := time.Now()
+	revisions := make([]repotracker.FixtureRevision, 0, len(fixture.Revisions))
+	for i, r := range fixture.Revisions {
+		createTime := time.Time(r.CreateTime)
+		if createTime.IsZero() {
+			createTime = now.Add(-time.Duration(i) * time.Minute)
+		}
+		revisions = append(revisions, repotracker.FixtureRevision{
+			Revision: model.Revision{
+				Revision:        restModel.FromAPIString(r.Revision),
+				Author:          restModel.FromAPIString(r.Author),
+				AuthorEmail:     restModel.FromAPIString(r.AuthorEmail),
+				RevisionMessage: restModel.FromAPIString(r.Message),
+				CreateTime:      createTime,
+			},
+			Config: restModel.FromAPIString(r.Config),
+		})
+	}
+	return revisions
+}
+
 type MockRepoTrackerConnector struct{}
 
 func (c *MockRepoTrackerConnector) TriggerRepotracker(_ amboy.Queue, _ string, event *github.PushEvent) error {
@@ -144,6 +240,46 @@ func (c *MockRepoTrackerConnector) TriggerRepotracker(_ amboy.Queue, _ string, e
 	return err
 }
 
+// LoadRepotrackerFixture returns the IDs that versions of the fixture's
+// revisions would have, without running the repotracker.
+func (c *MockRepoTrackerConnector) LoadRepotrackerFixture(_ context.Context, projectID string, fixture *restModel.APIRepoTrackerFixture) ([]string, error) {
+	if len(fixture.Revisions) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "no fixture revisions to load",
+		}
+	}
+
+	versionIDs := []string{}
+	for _, r := range fixtureRevisions(fixture) {
+		versionIDs = append(versionIDs, fmt.Sprintf("%s_%s", projectID, r.Revision.Revision))
+	}
+	return versionIDs, nil
+}
+
+// CreateManualVersion returns the version that would be created for the
+// revision, without fetching it or storing anything.
+func (c *MockRepoTrackerConnector) CreateManualVersion(_ context.Context, ref *model.ProjectRef, req *restModel.APIManualVersion, user string) (*version.Version, error) {
+	revision := restModel.FromAPIString(req.Revision)
+	if revision == "" {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "revision must not be blank",
+		}
+	}
+
+	return &version.Version{
+		Id:         fmt.Sprintf("%s_%s", ref.Identifier, revision),
+		Identifier: ref.Identifier,
+		Revision:   revision,
+		Message:    restModel.FromAPIString(req.Message),
+		AuthorID:   user,
+		Requester:  evergreen.ManualVersionRequester,
+		Status:     evergreen.VersionCreated,
+		Parameters: req.ServiceParameters(),
+	}, nil
+}
+
 func validatePushEvent(event *github.PushEvent) (string, error) {
 	if event == nil || event.Ref == nil || event.Repo == nil ||
 		event.Repo.Name == nil || event.Repo.Owner == nil ||
@@ -170,6 +306,55 @@ func validatePushEvent(event *github.PushEvent) (string, error) {
 	return refs[2], nil
 }
 
+func isTagPushEvent(event *github.PushEvent) bool {
+	return strings.HasPrefix(event.GetRef(), tagRefPrefix) && !event.GetDeleted() &&
+		event.GetAfter() != "" && event.HeadCommit != nil && event.HeadCommit.GetID() != ""
+}
+
+// triggerTagJobs enqueues a job to record the pushed tag on the tagged
+// version for each project tracking the repository.
+func triggerTagJobs(q amboy.Queue, msgID string, event *github.PushEvent) error {
+	tagName := strings.TrimPrefix(event.GetRef(), tagRefPrefix)
+	refs, err := model.FindProjectRefsByRepo(*event.Repo.Owner.Name, *event.Repo.Name)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+
+	catcher := grip.NewSimpleCatcher()
+	for i := range refs {
+		if !refs[i].TracksPushEvents {
+			continue
+		}
+		job := units.NewRepotrackerTagJob(fmt.Sprintf("github-push-%s", msgID), refs[i].Identifier,
+			tagName, event.GetAfter(), event.HeadCommit.GetID())
+		if err := q.Put(job); err != nil {
+			catcher.Add(errors.Errorf("failed to add repotracker tag job to queue for project: '%s'", refs[i].Identifier))
+		}
+	}
+
+	grip.Error(message.WrapError(catcher.Resolve(), message.Fields{
+		"source":  "github hook",
+		"msg_id":  msgID,
+		"event":   "push",
+		"owner":   *event.Repo.Owner.Name,
+		"repo":    *event.Repo.Name,
+		"ref":     *event.Ref,
+		"message": "errors occurred while recording tag",
+	}))
+
+	if catcher.HasErrors() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    catcher.Resolve().Error(),
+		}
+	}
+
+	return nil
+}
+
 func validateProjectRefs(owner, repo, branch string) ([]model.ProjectRef, error) {
 	refs, err := model.FindProjectRefsByRepoAndBranch(owner, repo, branch)
 	if err != nil {
@@ -0,0 +1,141 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/user"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+func (ac *DBAdminConnector) CreateServiceKey(in *restModel.APIServiceKey, createdBy string) (*restModel.APIServiceKey, error) {
+	k, token, err := newServiceKey(in, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	if err = k.Insert(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIServiceKey(k, token)
+}
+
+func (ac *DBAdminConnector) FindServiceKeys() ([]restModel.APIServiceKey, error) {
+	keys, err := user.FindAllServiceKeys()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return buildAPIServiceKeys(keys)
+}
+
+func (ac *DBAdminConnector) DeleteServiceKey(id string) error {
+	k, err := user.FindServiceKey(id)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if k == nil {
+		return serviceKeyNotFound(id)
+	}
+
+	return errors.WithStack(user.RemoveServiceKey(k.ID))
+}
+
+func (ac *DBAdminConnector) FindServiceKeyByToken(token string) (*user.ServiceKey, error) {
+	k, err := user.FindServiceKeyByToken(token)
+	return k, errors.WithStack(err)
+}
+
+func newServiceKey(in *restModel.APIServiceKey, createdBy string) (*user.ServiceKey, string, error) {
+	k, token, err := user.NewServiceKey(restModel.FromAPIString(in.Name), in.Scopes, time.Time(in.ExpiresAt), createdBy)
+	if err != nil {
+		return nil, "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return k, token, nil
+}
+
+func serviceKeyNotFound(id string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("service key '%s' not found", id),
+	}
+}
+
+// buildAPIServiceKey converts the key to its API model, which includes the
+// key's token only if it's given.
+func buildAPIServiceKey(k *user.ServiceKey, token string) (*restModel.APIServiceKey, error) {
+	apiKey := restModel.APIServiceKey{}
+	if err := apiKey.BuildFromService(k); err != nil {
+		return nil, errors.Wrap(err, "failed to build service key response")
+	}
+	if token != "" {
+		apiKey.Token = restModel.ToAPIString(token)
+	}
+
+	return &apiKey, nil
+}
+
+func buildAPIServiceKeys(keys []user.ServiceKey) ([]restModel.APIServiceKey, error) {
+	out := make([]restModel.APIServiceKey, 0, len(keys))
+	for i := range keys {
+		apiKey, err := buildAPIServiceKey(&keys[i], "")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *apiKey)
+	}
+
+	return out, nil
+}
+
+func (ac *MockAdminConnector) CreateServiceKey(in *restModel.APIServiceKey, createdBy string) (*restModel.APIServiceKey, error) {
+	k, token, err := newServiceKey(in, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.MockServiceKeys = append(ac.MockServiceKeys, *k)
+
+	return buildAPIServiceKey(k, token)
+}
+
+func (ac *MockAdminConnector) FindServiceKeys() ([]restModel.APIServiceKey, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	return buildAPIServiceKeys(ac.MockServiceKeys)
+}
+
+func (ac *MockAdminConnector) DeleteServiceKey(id string) error {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	for i := range ac.MockServiceKeys {
+		if ac.MockServiceKeys[i].ID == id {
+			ac.MockServiceKeys = append(ac.MockServiceKeys[:i], ac.MockServiceKeys[i+1:]...)
+			return nil
+		}
+	}
+
+	return serviceKeyNotFound(id)
+}
+
+func (ac *MockAdminConnector) FindServiceKeyByToken(token string) (*user.ServiceKey, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+	for i := range ac.MockServiceKeys {
+		if ac.MockServiceKeys[i].Authenticates(token) {
+			k := ac.MockServiceKeys[i]
+			return &k, nil
+		}
+	}
+
+	return nil, nil
+}
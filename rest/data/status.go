@@ -3,10 +3,16 @@ package data
 import (
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
 )
 
+// staticHostHealthStatuses are the statuses of the static hosts whose health
+// is reported.
+var staticHostHealthStatuses = []string{evergreen.HostRunning, evergreen.HostQuarantined}
+
 // DBStatusConnector is a struct that implements the status related methods
 // from the Connector through interactions with the backing database.
 type DBStatusConnector struct{}
@@ -30,12 +36,20 @@ func (c *DBStatusConnector) GetHostStatsByDistro() ([]host.StatsByDistro, error)
 	return host.GetStatsByDistro()
 }
 
+// FindStaticHostsHealth returns the running and quarantined static hosts,
+// with their most recent health checks, of the distro, or of every distro if
+// the distro is empty.
+func (c *DBStatusConnector) FindStaticHostsHealth(distroId string) ([]host.Host, error) {
+	return host.Find(host.StaticHostsByStatus(distroId, staticHostHealthStatuses))
+}
+
 // MockStatusConnector is a struct that implements mock versions of
 // Distro-related methods for testing.
 type MockStatusConnector struct {
-	CachedTasks     []task.Task
-	CachedResults   *task.ResultCounts
-	CachedHostStats []host.StatsByDistro
+	CachedTasks       []task.Task
+	CachedResults     *task.ResultCounts
+	CachedHostStats   []host.StatsByDistro
+	CachedStaticHosts []host.Host
 }
 
 // FindRecentTasks is a mock implementation for testing.
@@ -47,3 +61,19 @@ func (c *MockStatusConnector) FindRecentTasks(minutes int) ([]task.Task, *task.R
 func (c *MockStatusConnector) GetHostStatsByDistro() ([]host.StatsByDistro, error) {
 	return c.CachedHostStats, nil
 }
+
+// FindStaticHostsHealth returns the mock static hosts of the distro, or all
+// of them if the distro is empty.
+func (c *MockStatusConnector) FindStaticHostsHealth(distroId string) ([]host.Host, error) {
+	hosts := []host.Host{}
+	for _, h := range c.CachedStaticHosts {
+		if distroId != "" && h.Distro.Id != distroId {
+			continue
+		}
+		if !util.StringSliceContains(staticHostHealthStatuses, h.Status) {
+			continue
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts, nil
+}
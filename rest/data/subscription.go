@@ -49,6 +49,10 @@ func (dc *DBSubscriptionConnector) DeleteSubscription(id string) error {
 	return event.RemoveSubscription(id)
 }
 
+func (dc *DBSubscriptionConnector) FindSubscriptionByID(id string) (*event.Subscription, error) {
+	return event.FindSubscriptionByID(id)
+}
+
 type MockSubscriptionConnector struct {
 	MockSubscriptions []event.Subscription
 }
@@ -58,9 +62,28 @@ func (mc *MockSubscriptionConnector) GetSubscriptions(user string, ownerType eve
 }
 
 func (mc *MockSubscriptionConnector) SaveSubscriptions(subscriptions []event.Subscription) error {
-	return errors.New("MockSubscriptionConnector unimplemented")
+	for _, subscription := range subscriptions {
+		existing, _ := mc.FindSubscriptionByID(subscription.ID)
+		if existing != nil {
+			*existing = subscription
+		} else {
+			mc.MockSubscriptions = append(mc.MockSubscriptions, subscription)
+		}
+	}
+
+	return nil
 }
 
 func (dc *MockSubscriptionConnector) DeleteSubscription(id string) error {
 	return errors.New("MockSubscriptionConnector unimplemented")
 }
+
+func (mc *MockSubscriptionConnector) FindSubscriptionByID(id string) (*event.Subscription, error) {
+	for i := range mc.MockSubscriptions {
+		if mc.MockSubscriptions[i].ID == id {
+			return &mc.MockSubscriptions[i], nil
+		}
+	}
+
+	return nil, nil
+}
@@ -3,11 +3,13 @@ package data
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	serviceModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/taskruntime"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
@@ -193,6 +195,60 @@ func (tc *DBTaskConnector) FindCostTaskByProject(project, taskId string, startti
 	return tasks, nil
 }
 
+// FindCostByProjectId queries the backing database for cost data associated
+// with the given project. This is done by aggregating TimeTaken and Cost over
+// all tasks of the project that finish in the given time range, including
+// earlier executions of restarted tasks, by distro.
+func (tc *DBTaskConnector) FindCostByProjectId(projectId string,
+	starttime time.Time, duration time.Duration) (*task.ProjectCost, error) {
+	pipeline := task.CostDataByProjectIdPipeline(projectId, starttime, duration)
+	res := []task.DistroCost{}
+	if err := task.Aggregate(pipeline, &res); err != nil {
+		return nil, errors.Wrapf(err, "problem aggregating cost data for project %s", projectId)
+	}
+	oldRes := []task.DistroCost{}
+	if err := task.AggregateOld(pipeline, &oldRes); err != nil {
+		return nil, errors.Wrapf(err, "problem aggregating cost data for old tasks of project %s", projectId)
+	}
+
+	return newProjectCost(projectId, append(res, oldRes...)), nil
+}
+
+// FindTaskRuntime returns the recent runtimes of the task in the project's
+// variant, or nil if none have been recorded.
+func (tc *DBTaskConnector) FindTaskRuntime(project, variant, taskName string) (*taskruntime.TaskRuntime, error) {
+	r, err := taskruntime.FindOne(project, variant, taskName)
+	return r, errors.WithStack(err)
+}
+
+// newProjectCost totals the cost of the project's tasks, merging the costs
+// of the same distro.
+func newProjectCost(projectId string, distros []task.DistroCost) *task.ProjectCost {
+	pc := &task.ProjectCost{
+		ProjectId: projectId,
+		Distros:   []task.DistroCost{},
+	}
+	byDistro := map[string]int{}
+	for _, dc := range distros {
+		pc.SumTimeTaken += dc.SumTimeTaken
+		pc.SumEstimatedCost += dc.SumEstimatedCost
+		pc.NumTasks += dc.NumTasks
+
+		idx, ok := byDistro[dc.DistroId]
+		if !ok {
+			byDistro[dc.DistroId] = len(pc.Distros)
+			pc.Distros = append(pc.Distros, dc)
+			continue
+		}
+		pc.Distros[idx].SumTimeTaken += dc.SumTimeTaken
+		pc.Distros[idx].SumEstimatedCost += dc.SumEstimatedCost
+		pc.Distros[idx].NumTasks += dc.NumTasks
+	}
+	sort.Slice(pc.Distros, func(i, j int) bool { return pc.Distros[i].DistroId < pc.Distros[j].DistroId })
+
+	return pc
+}
+
 // MockTaskConnector stores a cached set of tasks that are queried against by the
 // implementations of the Connector interface's Task related functions.
 type MockTaskConnector struct {
@@ -201,6 +257,8 @@ type MockTaskConnector struct {
 	CachedAborted  map[string]string
 	StoredError    error
 	FailOnAbort    bool
+
+	CachedTaskRuntimes []taskruntime.TaskRuntime
 }
 
 // FindTaskById provides a mock implementation of the functions for the
@@ -367,6 +425,37 @@ func (mtc *MockTaskConnector) FindCostTaskByProject(project, taskId string,
 	return tasks, nil
 }
 
+// FindCostByProjectId returns results based on the cached tasks in the
+// MockTaskConnector.
+func (mtc *MockTaskConnector) FindCostByProjectId(projectId string,
+	starttime time.Time, duration time.Duration) (*task.ProjectCost, error) {
+	endtime := starttime.Add(duration)
+	byDistro := map[string]*task.DistroCost{}
+	distros := []string{}
+	for _, t := range mtc.CachedTasks {
+		if t.Project != projectId || t.FinishTime.Before(starttime) || t.FinishTime.After(endtime) {
+			continue
+		}
+		dc, ok := byDistro[t.DistroId]
+		if !ok {
+			dc = &task.DistroCost{DistroId: t.DistroId}
+			byDistro[t.DistroId] = dc
+			distros = append(distros, t.DistroId)
+		}
+		dc.SumTimeTaken += t.TimeTaken
+		dc.SumEstimatedCost += t.Cost
+		dc.NumTasks++
+	}
+
+	sort.Strings(distros)
+	res := []task.DistroCost{}
+	for _, d := range distros {
+		res = append(res, *byDistro[d])
+	}
+
+	return newProjectCost(projectId, res), nil
+}
+
 func (tc *MockTaskConnector) AbortTask(taskId, user string) error {
 	if tc.FailOnAbort {
 		return errors.New("manufactured fail")
@@ -374,3 +463,16 @@ func (tc *MockTaskConnector) AbortTask(taskId, user string) error {
 	tc.CachedAborted[taskId] = user
 	return nil
 }
+
+// FindTaskRuntime returns the cached runtimes of the task in the project's
+// variant, or nil if there are none.
+func (mtc *MockTaskConnector) FindTaskRuntime(project, variant, taskName string) (*taskruntime.TaskRuntime, error) {
+	key := taskruntime.TaskRuntimeKey{Project: project, Variant: variant, Task: taskName}
+	for i := range mtc.CachedTaskRuntimes {
+		if mtc.CachedTaskRuntimes[i].Id == key {
+			r := mtc.CachedTaskRuntimes[i]
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,76 @@
+package data
+
+import (
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/level"
+)
+
+// DBTaskLogConnector is a struct that implements the task log related
+// methods from the Connector through interactions with the backing database.
+type DBTaskLogConnector struct{}
+
+// FindStructuredTaskLogs returns the structured log lines of a task
+// execution that match the filter.
+func (tc *DBTaskLogConnector) FindStructuredTaskLogs(f model.StructuredTaskLogFilter) ([]model.StructuredTaskLog, error) {
+	return model.FindStructuredTaskLogs(f)
+}
+
+// FindTaskLogMessages returns at most limit messages of a task execution's
+// log, starting at the given offset.
+func (tc *DBTaskLogConnector) FindTaskLogMessages(taskId string, execution, offset, limit int) ([]apimodels.LogMessage, error) {
+	return model.FindTaskLogMessagesFromOffset(taskId, execution, offset, limit)
+}
+
+// MockTaskLogConnector stores a cached set of structured log lines that are
+// queried against by the implementations of the Connector interface's task
+// log related functions.
+type MockTaskLogConnector struct {
+	CachedTaskLogs       []model.TaskLog
+	CachedStructuredLogs []model.StructuredTaskLog
+}
+
+// FindTaskLogMessages reads the messages of the cached task logs in order.
+func (tc *MockTaskLogConnector) FindTaskLogMessages(taskId string, execution, offset, limit int) ([]apimodels.LogMessage, error) {
+	msgs := []apimodels.LogMessage{}
+	for _, l := range tc.CachedTaskLogs {
+		if l.TaskId == taskId && l.Execution == execution {
+			msgs = append(msgs, l.Messages...)
+		}
+	}
+	if offset >= len(msgs) {
+		return nil, nil
+	}
+	msgs = msgs[offset:]
+	if len(msgs) > limit {
+		msgs = msgs[:limit]
+	}
+	return msgs, nil
+}
+
+// FindStructuredTaskLogs filters the cached structured log lines, which are
+// expected to be sorted by time.
+func (tc *MockTaskLogConnector) FindStructuredTaskLogs(f model.StructuredTaskLogFilter) ([]model.StructuredTaskLog, error) {
+	logs := []model.StructuredTaskLog{}
+	for _, l := range tc.CachedStructuredLogs {
+		if l.TaskId != f.TaskId || l.Execution != f.Execution {
+			continue
+		}
+		if f.MinLevel > level.Invalid && l.Priority < int(f.MinLevel) {
+			continue
+		}
+		if len(f.Components) > 0 && !util.StringSliceContains(f.Components, l.Component) {
+			continue
+		}
+		if (!f.Start.IsZero() && l.Timestamp.Before(f.Start)) || (!f.End.IsZero() && !l.Timestamp.Before(f.End)) {
+			continue
+		}
+
+		logs = append(logs, l)
+		if f.Limit > 0 && len(logs) == f.Limit {
+			break
+		}
+	}
+	return logs, nil
+}
@@ -1,12 +1,18 @@
 package data
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
 	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
 )
 
 // DBTestConnector is a struct that implements the Test related methods
@@ -54,11 +60,64 @@ func (tc *DBTestConnector) FindTestsByTaskId(taskId, testId, status string, limi
 	return res, nil
 }
 
+// IngestTestReport converts the tests in the report into test results of the
+// task.
+func (tc *DBTestConnector) IngestTestReport(t *task.Task, format string, report []byte) ([]testresult.TestResult, error) {
+	if !util.StringSliceContains(model.ValidTestReportFormats, format) {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid test report format '%s'", format),
+		}
+	}
+
+	results, err := model.IngestTestReport(t, format, bytes.NewReader(report))
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return newTestResults(t, results), nil
+}
+
+// FindTestFlakiness returns the flakiness of the tests of a project that
+// have flaked in its recent mainline versions, most flaky first. The
+// variant and task narrow the results when they're not empty.
+func (tc *DBTestConnector) FindTestFlakiness(projectId, variant, taskName string) ([]testflakiness.TestFlakiness, error) {
+	stats, err := testflakiness.Find(testflakiness.ByProject(projectId, variant, taskName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding test flakiness of project '%s'", projectId)
+	}
+	return stats, nil
+}
+
+func newTestResults(t *task.Task, results []task.TestResult) []testresult.TestResult {
+	out := make([]testresult.TestResult, 0, len(results))
+	for _, r := range results {
+		out = append(out, testresult.TestResult{
+			TaskID:    t.Id,
+			Execution: t.Execution,
+			Status:    r.Status,
+			TestFile:  r.TestFile,
+			URL:       r.URL,
+			URLRaw:    r.URLRaw,
+			LogID:     r.LogId,
+			LineNum:   r.LineNum,
+			ExitCode:  r.ExitCode,
+			StartTime: r.StartTime,
+			EndTime:   r.EndTime,
+		})
+	}
+	return out
+}
+
 // MockTaskConnector stores a cached set of tests that are queried against by the
 // implementations of the Connector interface's Test related functions.
 type MockTestConnector struct {
-	CachedTests []testresult.TestResult
-	StoredError error
+	CachedTests         []testresult.TestResult
+	CachedTestFlakiness []testflakiness.TestFlakiness
+	StoredError         error
 }
 
 func (mtc *MockTestConnector) FindTestsByTaskId(taskId, testId, status string, limit, execution int) ([]testresult.TestResult, error) {
@@ -81,3 +140,55 @@ func (mtc *MockTestConnector) FindTestsByTaskId(taskId, testId, status string, l
 	}
 	return nil, nil
 }
+
+// IngestTestReport parses the report and caches its tests as test results of
+// the task, without storing their logs.
+func (mtc *MockTestConnector) IngestTestReport(t *task.Task, format string, report []byte) ([]testresult.TestResult, error) {
+	if mtc.StoredError != nil {
+		return nil, mtc.StoredError
+	}
+
+	cases, err := model.ParseTestReport(format, bytes.NewReader(report))
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	results := make([]task.TestResult, 0, len(cases))
+	for _, c := range cases {
+		if c.Start.IsZero() {
+			c.Start = time.Now()
+		}
+		results = append(results, task.TestResult{
+			Status:    c.Status,
+			TestFile:  util.CleanForPath(c.Name),
+			StartTime: util.ToPythonTime(c.Start),
+			EndTime:   util.ToPythonTime(c.Start.Add(c.Duration)),
+		})
+	}
+	tests := newTestResults(t, results)
+	mtc.CachedTests = append(mtc.CachedTests, tests...)
+	return tests, nil
+}
+
+// FindTestFlakiness returns the cached flakiness of the project's tests
+// that match the variant and task.
+func (mtc *MockTestConnector) FindTestFlakiness(projectId, variant, taskName string) ([]testflakiness.TestFlakiness, error) {
+	if mtc.StoredError != nil {
+		return nil, mtc.StoredError
+	}
+
+	stats := []testflakiness.TestFlakiness{}
+	for _, s := range mtc.CachedTestFlakiness {
+		if s.Id.Project != projectId {
+			continue
+		}
+		if (variant != "" && s.Id.Variant != variant) || (taskName != "" && s.Id.Task != taskName) {
+			continue
+		}
+		stats = append(stats, s)
+	}
+	return stats, nil
+}
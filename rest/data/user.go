@@ -27,6 +27,10 @@ func (tc *DBUserConnector) FindUserById(userId string) (gimlet.User, error) {
 	return t, nil
 }
 
+func (u *DBUserConnector) FindUserBySlackMemberId(memberId string) (*user.DBUser, error) {
+	return user.FindBySlackMemberId(memberId)
+}
+
 func (u *DBUserConnector) AddPublicKey(user *user.DBUser, keyName, keyValue string) error {
 	return user.AddPublicKey(keyName, keyValue)
 }
@@ -43,9 +47,26 @@ func (u *DBUserConnector) UpdateSettings(dbUser *user.DBUser, settings user.User
 		}
 	}
 	settings.SlackUsername = strings.TrimPrefix(settings.SlackUsername, "@")
+	settings.SlackMemberId = strings.TrimSpace(settings.SlackMemberId)
+	if settings.SlackMemberId != "" && settings.SlackMemberId != dbUser.Settings.SlackMemberId {
+		// the member ID identifies the user in Slack interactions, so no two
+		// users can have the same one
+		existing, err := user.FindBySlackMemberId(settings.SlackMemberId)
+		if err != nil {
+			return errors.Wrap(err, "problem checking slack member ID")
+		}
+		if existing != nil && existing.Id != dbUser.Id {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "that Slack member ID belongs to another user",
+			}
+		}
+	}
 	settings.Notifications.PatchFinishID = dbUser.Settings.Notifications.PatchFinishID
 	settings.Notifications.SpawnHostOutcomeID = dbUser.Settings.Notifications.SpawnHostOutcomeID
 	settings.Notifications.SpawnHostExpirationID = dbUser.Settings.Notifications.SpawnHostExpirationID
+	// delivery preferences are set separately
+	settings.Delivery = dbUser.Settings.Delivery
 
 	var patchSubscriber event.Subscriber
 	switch settings.Notifications.PatchFinish {
@@ -122,6 +143,17 @@ func (u *DBUserConnector) UpdateSettings(dbUser *user.DBUser, settings user.User
 	return model.SaveUserSettings(dbUser.Id, settings)
 }
 
+func (u *DBUserConnector) UpdateDeliveryPreferences(dbUser *user.DBUser, prefs user.DeliveryPreferences) error {
+	if err := prefs.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return dbUser.SetDeliveryPreferences(prefs)
+}
+
 // MockUserConnector stores a cached set of users that are queried against by the
 // implementations of the UserConnector interface's functions.
 type MockUserConnector struct {
@@ -136,6 +168,15 @@ func (muc *MockUserConnector) FindUserById(userId string) (gimlet.User, error) {
 	return u, nil
 }
 
+func (muc *MockUserConnector) FindUserBySlackMemberId(memberId string) (*user.DBUser, error) {
+	for _, u := range muc.CachedUsers {
+		if u.Settings.SlackMemberId == memberId {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
 func (muc *MockUserConnector) AddPublicKey(dbuser *user.DBUser, keyName, keyValue string) error {
 	u, ok := muc.CachedUsers[dbuser.Id]
 	if !ok {
@@ -181,3 +222,18 @@ func (muc *MockUserConnector) DeletePublicKey(u *user.DBUser, keyName string) er
 func (muc *MockUserConnector) UpdateSettings(user *user.DBUser, settings user.UserSettings) error {
 	return errors.New("UpdateSettings not implemented for mock connector")
 }
+
+func (muc *MockUserConnector) UpdateDeliveryPreferences(dbUser *user.DBUser, prefs user.DeliveryPreferences) error {
+	if err := prefs.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	dbUser.Settings.Delivery = prefs
+	if u, ok := muc.CachedUsers[dbUser.Id]; ok {
+		u.Settings.Delivery = prefs
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/manifest"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
@@ -58,33 +59,54 @@ func (vc *DBVersionConnector) FindVersionById(versionId string) (*version.Versio
 	return v, nil
 }
 
-// AbortVersion aborts all tasks of a version given its ID.
-// It wraps the service level AbortVersion.
-func (vc *DBVersionConnector) AbortVersion(versionId, caller string) error {
-	return model.AbortVersion(versionId, caller)
+// FindVersionsByProject queries the backing database for the versions of
+// the project, leaving out their configurations.
+func (vc *DBVersionConnector) FindVersionsByProject(projectId string, startOrder, limit int) ([]version.Version, error) {
+	versions, err := version.Find(version.ByProjectIdUpToOrder(projectId, startOrder).
+		WithoutFields(version.ConfigKey).Limit(limit))
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem fetching versions for project '%s'", projectId)
+	}
+
+	return versions, nil
 }
 
-// RestartVersion wraps the service level RestartVersion, which restarts
-// completed tasks associated with a given versionId. If abortInProgress is
-// true, it also sets the abort flag on any in-progress tasks. In addition, it
-// updates all builds containing the tasks affected.
-func (vc *DBVersionConnector) RestartVersion(versionId string, caller string) error {
-	// Get a list of all tasks of the given versionId
-	tasks, err := task.Find(task.ByVersion(versionId))
+// FindManifestByVersionId queries the backing database for the manifest
+// pinning the module revisions of the version with the given versionId.
+func (vc *DBVersionConnector) FindManifestByVersionId(versionId string) (*manifest.Manifest, error) {
+	m, err := manifest.FindOne(manifest.ById(versionId))
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if tasks == nil {
-		return gimlet.ErrorResponse{
+	if m == nil {
+		return nil, gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("version with id %s not found", versionId),
+			Message:    fmt.Sprintf("manifest for version with id %s not found", versionId),
 		}
 	}
-	var taskIds []string
-	for _, task := range tasks {
-		taskIds = append(taskIds, task.Id)
-	}
-	return model.RestartVersion(versionId, taskIds, true, caller)
+	return m, nil
+}
+
+// AbortVersion aborts the tasks of a version that pass the filter, given its
+// ID. It wraps the service level AbortVersionTasks.
+func (vc *DBVersionConnector) AbortVersion(versionId string, filter model.VersionTaskFilter, caller string) error {
+	_, err := model.AbortVersionTasks(versionId, filter, caller)
+	return err
+}
+
+// RestartVersion wraps the service level RestartVersionTasks, which restarts
+// the completed tasks of a version that pass the filter, and sets the abort
+// flag on those in progress. In addition, it updates all builds containing
+// the tasks affected.
+func (vc *DBVersionConnector) RestartVersion(versionId string, filter model.VersionTaskFilter, caller string) error {
+	_, err := model.RestartVersionTasks(versionId, filter, caller)
+	return err
+}
+
+// SetVersionPriority wraps the service level method, which also sets the
+// priority of the version's existing tasks.
+func (vc *DBVersionConnector) SetVersionPriority(versionId string, priority int64) error {
+	return model.SetVersionPriority(versionId, priority)
 }
 
 // Fetch versions until 'numVersionElements' elements are created, including
@@ -309,7 +331,9 @@ func addFailedAndStartedTests(rows map[string]restModel.BuildList, failedAndStar
 type MockVersionConnector struct {
 	CachedTasks             []task.Task
 	CachedVersions          []version.Version
+	CachedManifests         []manifest.Manifest
 	CachedRestartedVersions map[string]string
+	CachedRestartFilters    map[string]model.VersionTaskFilter
 }
 
 // FindCostByVersionId is the mock implementation of the function for the Connector interface
@@ -351,11 +375,48 @@ func (mvc *MockVersionConnector) FindVersionById(versionId string) (*version.Ver
 	}
 }
 
-// AbortVersion aborts all tasks of a version given its ID. Specifically, it sets the
-// Aborted key of the tasks to true if they are currently in abortable statuses.
-func (mvc *MockVersionConnector) AbortVersion(versionId, caller string) error {
+// FindVersionsByProject is the mock implementation of the function for the
+// Connector interface without needing to use a database. It returns results
+// based on the cached versions in the MockVersionConnector.
+func (mvc *MockVersionConnector) FindVersionsByProject(projectId string, startOrder, limit int) ([]version.Version, error) {
+	versions := []version.Version{}
+	for _, v := range mvc.CachedVersions {
+		if v.Identifier == projectId && !evergreen.IsPatchRequester(v.Requester) &&
+			(startOrder == 0 || v.RevisionOrderNumber <= startOrder) {
+			versions = append(versions, v)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].RevisionOrderNumber > versions[j].RevisionOrderNumber
+	})
+	if len(versions) > limit {
+		versions = versions[:limit]
+	}
+
+	return versions, nil
+}
+
+// FindManifestByVersionId is the mock implementation of the function for the
+// Connector interface without needing to use a database. It returns results
+// based on the cached manifests in the MockVersionConnector.
+func (mvc *MockVersionConnector) FindManifestByVersionId(versionId string) (*manifest.Manifest, error) {
+	for _, m := range mvc.CachedManifests {
+		if m.Id == versionId {
+			return &m, nil
+		}
+	}
+	return nil, gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("manifest for version with id %s not found", versionId),
+	}
+}
+
+// AbortVersion aborts the tasks of a version that pass the filter, given its
+// ID. Specifically, it sets the Aborted key of the tasks to true if they are
+// currently in abortable statuses.
+func (mvc *MockVersionConnector) AbortVersion(versionId string, filter model.VersionTaskFilter, caller string) error {
 	for idx, t := range mvc.CachedTasks {
-		if t.Version == versionId && (t.Status == evergreen.TaskStarted || t.Status == evergreen.TaskDispatched) {
+		if t.Version == versionId && (t.Status == evergreen.TaskStarted || t.Status == evergreen.TaskDispatched) && filter.Matches(&t) {
 			if !t.Aborted {
 				pt := &mvc.CachedTasks[idx]
 				pt.Aborted = true
@@ -367,12 +428,32 @@ func (mvc *MockVersionConnector) AbortVersion(versionId, caller string) error {
 
 // The main function of the RestartVersion() for the MockVersionConnector is to
 // test connectivity. It sets the value of versionId in CachedRestartedVersions
-// to the caller.
-func (mvc *MockVersionConnector) RestartVersion(versionId string, caller string) error {
+// to the caller, and in CachedRestartFilters to the filter.
+func (mvc *MockVersionConnector) RestartVersion(versionId string, filter model.VersionTaskFilter, caller string) error {
 	mvc.CachedRestartedVersions[versionId] = caller
+	if mvc.CachedRestartFilters == nil {
+		mvc.CachedRestartFilters = map[string]model.VersionTaskFilter{}
+	}
+	mvc.CachedRestartFilters[versionId] = filter
 	return nil
 }
 
 func (mvc *MockVersionConnector) GetVersionsAndVariants(skip, numVersionElements int, project *model.Project) (*restModel.VersionVariantData, error) {
 	return nil, nil
 }
+
+// SetVersionPriority sets the priority of the cached version and of its cached
+// tasks.
+func (mvc *MockVersionConnector) SetVersionPriority(versionId string, priority int64) error {
+	for idx := range mvc.CachedVersions {
+		if mvc.CachedVersions[idx].Id == versionId {
+			mvc.CachedVersions[idx].Priority = priority
+		}
+	}
+	for idx := range mvc.CachedTasks {
+		if mvc.CachedTasks[idx].Version == versionId {
+			mvc.CachedTasks[idx].Priority = priority
+		}
+	}
+	return nil
+}
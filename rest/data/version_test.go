@@ -167,7 +167,7 @@ func (s *VersionConnectorSuite) TestFindVersionByIdFail() {
 
 func (s *VersionConnectorSuite) TestAbortVersion() {
 	versionId := "version1"
-	err := s.ctx.AbortVersion(versionId, "")
+	err := s.ctx.AbortVersion(versionId, model.VersionTaskFilter{}, "")
 	s.NoError(err)
 
 	// NOTE: TestAbort() has been written in this following way because FindTaskbyVersionId()
@@ -203,20 +203,56 @@ func (s *VersionConnectorSuite) TestAbortVersion() {
 	}
 }
 
+func (s *VersionConnectorSuite) TestAbortVersionWithFilter() {
+	if s.isMock {
+		cachedTasks := s.ctx.(*MockConnector).MockVersionConnector.CachedTasks
+		cachedTasks[0].Aborted = false
+		cachedTasks[1].Aborted = false
+	}
+
+	// task3 isn't abortable, so only task2 passes the filter
+	filter := model.VersionTaskFilter{Statuses: []string{evergreen.TaskDispatched, evergreen.TaskInactive}}
+	s.NoError(s.ctx.AbortVersion("version1", filter, "caller"))
+
+	var t1, t2 *task.Task
+	if s.isMock {
+		cachedTasks := s.ctx.(*MockConnector).MockVersionConnector.CachedTasks
+		t1, t2 = &cachedTasks[0], &cachedTasks[1]
+	} else {
+		var err error
+		t1, err = s.ctx.FindTaskById("task1")
+		s.Require().NoError(err)
+		t2, err = s.ctx.FindTaskById("task2")
+		s.Require().NoError(err)
+	}
+	s.False(t1.Aborted)
+	s.True(t2.Aborted)
+
+	// no task passes the filter
+	filter = model.VersionTaskFilter{TaskNames: []string{"compile"}}
+	s.NoError(s.ctx.AbortVersion("version1", filter, "caller"))
+	if !s.isMock {
+		var err error
+		t1, err = s.ctx.FindTaskById("task1")
+		s.Require().NoError(err)
+	}
+	s.False(t1.Aborted)
+}
+
 func (s *VersionConnectorSuite) TestRestartVersion() {
 	if s.isMock {
 		// Testing with versions that have tasks under them should succeed.
-		err := s.ctx.RestartVersion("version1", "caller1")
+		err := s.ctx.RestartVersion("version1", model.VersionTaskFilter{}, "caller1")
 		s.NoError(err)
 		s.Equal(s.ctx.(*MockConnector).CachedRestartedVersions["version1"], "caller1")
 
-		err = s.ctx.RestartVersion("version2", "caller2")
+		err = s.ctx.RestartVersion("version2", model.VersionTaskFilter{}, "caller2")
 		s.NoError(err)
 		s.Equal(s.ctx.(*MockConnector).CachedRestartedVersions["version2"], "caller2")
 
 	} else {
 		versionId := "version3"
-		err := s.ctx.RestartVersion(versionId, "caller3")
+		err := s.ctx.RestartVersion(versionId, model.VersionTaskFilter{}, "caller3")
 		s.NoError(err)
 
 		// When a version is restarted, all of its completed tasks should be reset.
@@ -12,12 +12,15 @@ import (
 
 func NewConfigModel() *APIAdminSettings {
 	return &APIAdminSettings{
+		AgentUpdate:       &APIAgentUpdateConfig{},
 		Alerts:            &APIAlertsConfig{},
 		Amboy:             &APIAmboyConfig{},
 		Api:               &APIapiConfig{},
+		Artifacts:         &APIArtifactsConfig{},
 		AuthConfig:        &APIAuthConfig{},
 		ContainerPools:    &APIContainerPoolsConfig{},
 		Credentials:       map[string]string{},
+		DataLifecycle:     &APIDataLifecycleConfig{},
 		Expansions:        map[string]string{},
 		HostInit:          &APIHostInitConfig{},
 		Jira:              &APIJiraConfig{},
@@ -29,6 +32,7 @@ func NewConfigModel() *APIAdminSettings {
 		Providers:         &APICloudProviders{},
 		RepoTracker:       &APIRepoTrackerConfig{},
 		Scheduler:         &APISchedulerConfig{},
+		Secrets:           &APISecretsConfig{},
 		ServiceFlags:      &APIServiceFlags{},
 		Slack:             &APISlackConfig{},
 		Splunk:            &APISplunkConnectionInfo{},
@@ -38,10 +42,12 @@ func NewConfigModel() *APIAdminSettings {
 
 // APIAdminSettings is the structure of a response to the admin route
 type APIAdminSettings struct {
+	AgentUpdate        *APIAgentUpdateConfig             `json:"agent_update,omitempty"`
 	Alerts             *APIAlertsConfig                  `json:"alerts,omitempty"`
 	Amboy              *APIAmboyConfig                   `json:"amboy,omitempty"`
 	Api                *APIapiConfig                     `json:"api,omitempty"`
 	ApiUrl             APIString                         `json:"api_url,omitempty"`
+	Artifacts          *APIArtifactsConfig               `json:"artifacts,omitempty"`
 	AuthConfig         *APIAuthConfig                    `json:"auth,omitempty"`
 	Banner             APIString                         `json:"banner,omitempty"`
 	BannerTheme        APIString                         `json:"banner_theme,omitempty"`
@@ -49,6 +55,7 @@ type APIAdminSettings struct {
 	ConfigDir          APIString                         `json:"configdir,omitempty"`
 	Credentials        map[string]string                 `json:"credentials,omitempty"`
 	ContainerPools     *APIContainerPoolsConfig          `json:"container_pools,omitempty"`
+	DataLifecycle      *APIDataLifecycleConfig           `json:"data_lifecycle,omitempty"`
 	Expansions         map[string]string                 `json:"expansions,omitempty"`
 	GithubPRCreatorOrg APIString                         `json:"github_pr_creator_org,omitempty"`
 	HostInit           *APIHostInitConfig                `json:"hostinit,omitempty"`
@@ -62,6 +69,7 @@ type APIAdminSettings struct {
 	Providers          *APICloudProviders                `json:"providers,omitempty"`
 	RepoTracker        *APIRepoTrackerConfig             `json:"repotracker,omitempty"`
 	Scheduler          *APISchedulerConfig               `json:"scheduler,omitempty"`
+	Secrets            *APISecretsConfig                 `json:"secrets,omitempty"`
 	ServiceFlags       *APIServiceFlags                  `json:"service_flags,omitempty"`
 	Slack              *APISlackConfig                   `json:"slack,omitempty"`
 	Splunk             *APISplunkConnectionInfo          `json:"splunk,omitempty"`
@@ -191,6 +199,48 @@ func (as *APIAdminSettings) ToService() (interface{}, error) {
 	return settings, nil
 }
 
+// sectionField returns the name of the field of the settings that holds
+// the section with the given id, which is also the name of the field of
+// the API model that holds it.
+func sectionField(id string) (string, bool) {
+	settingsType := reflect.TypeOf(evergreen.Settings{})
+	for i := 0; i < settingsType.NumField(); i++ {
+		if id != "" && settingsType.Field(i).Tag.Get("id") == id {
+			return settingsType.Field(i).Name, true
+		}
+	}
+	return "", false
+}
+
+// Section returns the API model of the settings section with the given
+// id, e.g. "repotracker", and false if there is no such section.
+func (as *APIAdminSettings) Section(id string) (Model, bool) {
+	propName, ok := sectionField(id)
+	if !ok {
+		return nil, false
+	}
+	model, ok := reflect.ValueOf(as).Elem().FieldByName(propName).Interface().(Model)
+	if !ok || reflect.ValueOf(model).IsNil() {
+		return nil, false
+	}
+	return model, true
+}
+
+// SetSection replaces the settings section with the given id.
+func (as *APIAdminSettings) SetSection(id string, section Model) error {
+	propName, ok := sectionField(id)
+	if !ok {
+		return errors.Errorf("'%s' is not a settings section", id)
+	}
+	field := reflect.ValueOf(as).Elem().FieldByName(propName)
+	val := reflect.ValueOf(section)
+	if !val.IsValid() || val.Type() != field.Type() {
+		return errors.Errorf("%T is not the model of settings section '%s'", section, id)
+	}
+	field.Set(val)
+	return nil
+}
+
 type APIAlertsConfig struct {
 	SMTP APISMTPConfig `json:"smtp"`
 }
@@ -298,6 +348,8 @@ func (a *APIAmboyConfig) ToService() (interface{}, error) {
 type APIapiConfig struct {
 	HttpListenAddr      APIString `json:"http_listen_addr"`
 	GithubWebhookSecret APIString `json:"github_webhook_secret"`
+	GithubAppID         int64     `json:"github_app_id"`
+	GithubAppKey        APIString `json:"github_app_key"`
 }
 
 func (a *APIapiConfig) BuildFromService(h interface{}) error {
@@ -305,6 +357,8 @@ func (a *APIapiConfig) BuildFromService(h interface{}) error {
 	case evergreen.APIConfig:
 		a.HttpListenAddr = ToAPIString(v.HttpListenAddr)
 		a.GithubWebhookSecret = ToAPIString(v.GithubWebhookSecret)
+		a.GithubAppID = v.GithubAppID
+		a.GithubAppKey = ToAPIString(v.GithubAppKey)
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -315,6 +369,8 @@ func (a *APIapiConfig) ToService() (interface{}, error) {
 	return evergreen.APIConfig{
 		HttpListenAddr:      FromAPIString(a.HttpListenAddr),
 		GithubWebhookSecret: FromAPIString(a.GithubWebhookSecret),
+		GithubAppID:         a.GithubAppID,
+		GithubAppKey:        FromAPIString(a.GithubAppKey),
 	}, nil
 }
 
@@ -323,6 +379,7 @@ type APIAuthConfig struct {
 	LDAP   *APILDAPConfig       `json:"ldap"`
 	Naive  *APINaiveAuthConfig  `json:"naive"`
 	Github *APIGithubAuthConfig `json:"github"`
+	OIDC   *APIOIDCConfig       `json:"oidc"`
 }
 
 func (a *APIAuthConfig) BuildFromService(h interface{}) error {
@@ -352,6 +409,12 @@ func (a *APIAuthConfig) BuildFromService(h interface{}) error {
 				return err
 			}
 		}
+		if v.OIDC != nil {
+			a.OIDC = &APIOIDCConfig{}
+			if err := a.OIDC.BuildFromService(v.OIDC); err != nil {
+				return err
+			}
+		}
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -363,6 +426,7 @@ func (a *APIAuthConfig) ToService() (interface{}, error) {
 	var ldap *evergreen.LDAPConfig
 	var naive *evergreen.NaiveAuthConfig
 	var github *evergreen.GithubAuthConfig
+	var oidc *evergreen.OIDCConfig
 	i, err := a.Crowd.ToService()
 	if err != nil {
 		return nil, err
@@ -391,11 +455,19 @@ func (a *APIAuthConfig) ToService() (interface{}, error) {
 	if i != nil {
 		github = i.(*evergreen.GithubAuthConfig)
 	}
+	i, err = a.OIDC.ToService()
+	if err != nil {
+		return nil, err
+	}
+	if i != nil {
+		oidc = i.(*evergreen.OIDCConfig)
+	}
 	return evergreen.AuthConfig{
 		Crowd:  crowd,
 		LDAP:   ldap,
 		Naive:  naive,
 		Github: github,
+		OIDC:   oidc,
 	}, nil
 }
 
@@ -586,20 +658,231 @@ func (a *APIGithubAuthConfig) ToService() (interface{}, error) {
 	return &config, nil
 }
 
+type APIOIDCConfig struct {
+	Issuer        APIString          `json:"issuer"`
+	ClientId      APIString          `json:"client_id"`
+	ClientSecret  APIString          `json:"client_secret"`
+	Scopes        []APIString        `json:"scopes"`
+	UsernameClaim APIString          `json:"username_claim"`
+	GroupsClaim   APIString          `json:"groups_claim"`
+	AllowedGroups []APIString        `json:"allowed_groups"`
+	GroupRoles    []APIOIDCGroupRole `json:"group_roles"`
+	SessionHours  int                `json:"session_hours"`
+}
+
+type APIOIDCGroupRole struct {
+	Group    APIString   `json:"group"`
+	Role     APIString   `json:"role"`
+	Projects []APIString `json:"projects"`
+}
+
+func (a *APIOIDCConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case *evergreen.OIDCConfig:
+		if v == nil {
+			return nil
+		}
+		a.Issuer = ToAPIString(v.Issuer)
+		a.ClientId = ToAPIString(v.ClientId)
+		a.ClientSecret = ToAPIString(v.ClientSecret)
+		a.UsernameClaim = ToAPIString(v.UsernameClaim)
+		a.GroupsClaim = ToAPIString(v.GroupsClaim)
+		a.SessionHours = v.SessionHours
+		for _, scope := range v.Scopes {
+			a.Scopes = append(a.Scopes, ToAPIString(scope))
+		}
+		for _, group := range v.AllowedGroups {
+			a.AllowedGroups = append(a.AllowedGroups, ToAPIString(group))
+		}
+		for _, gr := range v.GroupRoles {
+			groupRole := APIOIDCGroupRole{
+				Group: ToAPIString(gr.Group),
+				Role:  ToAPIString(gr.Role),
+			}
+			for _, project := range gr.Projects {
+				groupRole.Projects = append(groupRole.Projects, ToAPIString(project))
+			}
+			a.GroupRoles = append(a.GroupRoles, groupRole)
+		}
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIOIDCConfig) ToService() (interface{}, error) {
+	if a == nil {
+		return nil, nil
+	}
+	config := evergreen.OIDCConfig{
+		Issuer:        FromAPIString(a.Issuer),
+		ClientId:      FromAPIString(a.ClientId),
+		ClientSecret:  FromAPIString(a.ClientSecret),
+		UsernameClaim: FromAPIString(a.UsernameClaim),
+		GroupsClaim:   FromAPIString(a.GroupsClaim),
+		SessionHours:  a.SessionHours,
+	}
+	for _, scope := range a.Scopes {
+		config.Scopes = append(config.Scopes, FromAPIString(scope))
+	}
+	for _, group := range a.AllowedGroups {
+		config.AllowedGroups = append(config.AllowedGroups, FromAPIString(group))
+	}
+	for _, gr := range a.GroupRoles {
+		groupRole := evergreen.OIDCGroupRole{
+			Group: FromAPIString(gr.Group),
+			Role:  FromAPIString(gr.Role),
+		}
+		for _, project := range gr.Projects {
+			groupRole.Projects = append(groupRole.Projects, FromAPIString(project))
+		}
+		config.GroupRoles = append(config.GroupRoles, groupRole)
+	}
+	return &config, nil
+}
+
 // APIBanner is a public structure representing the banner part of the admin settings
 type APIBanner struct {
 	Text  APIString `json:"banner"`
 	Theme APIString `json:"theme"`
 }
 
+type APIAgentUpdateConfig struct {
+	Version        APIString               `json:"version"`
+	RolloutPercent int                     `json:"rollout_percent"`
+	Distros        []APIAgentDistroRollout `json:"distros"`
+}
+
+type APIAgentDistroRollout struct {
+	Distro         APIString `json:"distro"`
+	RolloutPercent int       `json:"rollout_percent"`
+}
+
+func (a *APIAgentUpdateConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.AgentUpdateConfig:
+		a.Version = ToAPIString(v.Version)
+		a.RolloutPercent = v.RolloutPercent
+		a.Distros = []APIAgentDistroRollout{}
+		for _, d := range v.Distros {
+			a.Distros = append(a.Distros, APIAgentDistroRollout{
+				Distro:         ToAPIString(d.Distro),
+				RolloutPercent: d.RolloutPercent,
+			})
+		}
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIAgentUpdateConfig) ToService() (interface{}, error) {
+	config := evergreen.AgentUpdateConfig{
+		Version:        FromAPIString(a.Version),
+		RolloutPercent: a.RolloutPercent,
+	}
+	for _, d := range a.Distros {
+		config.Distros = append(config.Distros, evergreen.AgentDistroRollout{
+			Distro:         FromAPIString(d.Distro),
+			RolloutPercent: d.RolloutPercent,
+		})
+	}
+	return config, nil
+}
+
+type APIArtifactsConfig struct {
+	Bucket            APIString `json:"bucket"`
+	Prefix            APIString `json:"prefix"`
+	Region            APIString `json:"region"`
+	Key               APIString `json:"key"`
+	Secret            APIString `json:"secret"`
+	URLExpirationMins int       `json:"url_expiration_mins"`
+	ExpirationDays    int       `json:"expiration_days"`
+}
+
+func (a *APIArtifactsConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.ArtifactsConfig:
+		a.Bucket = ToAPIString(v.Bucket)
+		a.Prefix = ToAPIString(v.Prefix)
+		a.Region = ToAPIString(v.Region)
+		a.Key = ToAPIString(v.Key)
+		a.Secret = ToAPIString(v.Secret)
+		a.URLExpirationMins = v.URLExpirationMins
+		a.ExpirationDays = v.ExpirationDays
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIArtifactsConfig) ToService() (interface{}, error) {
+	return evergreen.ArtifactsConfig{
+		Bucket:            FromAPIString(a.Bucket),
+		Prefix:            FromAPIString(a.Prefix),
+		Region:            FromAPIString(a.Region),
+		Key:               FromAPIString(a.Key),
+		Secret:            FromAPIString(a.Secret),
+		URLExpirationMins: a.URLExpirationMins,
+		ExpirationDays:    a.ExpirationDays,
+	}, nil
+}
+
+type APIDataLifecycleConfig struct {
+	ArchiveAfterDays int       `json:"archive_after_days"`
+	BatchSize        int       `json:"batch_size"`
+	Target           APIString `json:"target"`
+	Bucket           APIString `json:"bucket"`
+	Prefix           APIString `json:"prefix"`
+	Region           APIString `json:"region"`
+	Key              APIString `json:"key"`
+	Secret           APIString `json:"secret"`
+}
+
+func (a *APIDataLifecycleConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.DataLifecycleConfig:
+		a.ArchiveAfterDays = v.ArchiveAfterDays
+		a.BatchSize = v.BatchSize
+		a.Target = ToAPIString(v.Target)
+		a.Bucket = ToAPIString(v.Bucket)
+		a.Prefix = ToAPIString(v.Prefix)
+		a.Region = ToAPIString(v.Region)
+		a.Key = ToAPIString(v.Key)
+		a.Secret = ToAPIString(v.Secret)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIDataLifecycleConfig) ToService() (interface{}, error) {
+	return evergreen.DataLifecycleConfig{
+		ArchiveAfterDays: a.ArchiveAfterDays,
+		BatchSize:        a.BatchSize,
+		Target:           FromAPIString(a.Target),
+		Bucket:           FromAPIString(a.Bucket),
+		Prefix:           FromAPIString(a.Prefix),
+		Region:           FromAPIString(a.Region),
+		Key:              FromAPIString(a.Key),
+		Secret:           FromAPIString(a.Secret),
+	}, nil
+}
+
 type APIHostInitConfig struct {
-	SSHTimeoutSeconds int64 `json:"ssh_timeout_secs"`
+	SSHTimeoutSeconds           int64 `json:"ssh_timeout_secs"`
+	ProblemHostFailureThreshold int   `json:"problem_host_failure_threshold"`
+	ProblemHostWindowMinutes    int   `json:"problem_host_window_mins"`
+	TerminateProblemHosts       bool  `json:"terminate_problem_hosts"`
 }
 
 func (a *APIHostInitConfig) BuildFromService(h interface{}) error {
 	switch v := h.(type) {
 	case evergreen.HostInitConfig:
 		a.SSHTimeoutSeconds = v.SSHTimeoutSeconds
+		a.ProblemHostFailureThreshold = v.ProblemHostFailureThreshold
+		a.ProblemHostWindowMinutes = v.ProblemHostWindowMinutes
+		a.TerminateProblemHosts = v.TerminateProblemHosts
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -608,7 +891,10 @@ func (a *APIHostInitConfig) BuildFromService(h interface{}) error {
 
 func (a *APIHostInitConfig) ToService() (interface{}, error) {
 	return evergreen.HostInitConfig{
-		SSHTimeoutSeconds: a.SSHTimeoutSeconds,
+		SSHTimeoutSeconds:           a.SSHTimeoutSeconds,
+		ProblemHostFailureThreshold: a.ProblemHostFailureThreshold,
+		ProblemHostWindowMinutes:    a.ProblemHostWindowMinutes,
+		TerminateProblemHosts:       a.TerminateProblemHosts,
 	}, nil
 }
 
@@ -700,9 +986,24 @@ func (a *APILogBuffering) ToService() (interface{}, error) {
 }
 
 type APINotifyConfig struct {
-	BufferTargetPerInterval int           `json:"buffer_target_per_interval"`
-	BufferIntervalSeconds   int           `json:"buffer_interval_seconds"`
-	SMTP                    APISMTPConfig `json:"smtp"`
+	BufferTargetPerInterval int                        `json:"buffer_target_per_interval"`
+	BufferIntervalSeconds   int                        `json:"buffer_interval_seconds"`
+	SMTP                    APISMTPConfig              `json:"smtp"`
+	RateLimits              APINotifyRateLimits        `json:"rate_limits"`
+	Attachments             APINotifyAttachmentsConfig `json:"attachments"`
+}
+
+type APINotifyAttachmentsConfig struct {
+	Bucket APIString `json:"bucket"`
+	Prefix APIString `json:"prefix"`
+	Key    APIString `json:"key"`
+	Secret APIString `json:"secret"`
+}
+
+type APINotifyRateLimits struct {
+	SlackPerMinute int `json:"slack_per_minute"`
+	JIRAPerMinute  int `json:"jira_per_minute"`
+	EmailPerMinute int `json:"email_per_minute"`
 }
 
 func (a *APINotifyConfig) BuildFromService(h interface{}) error {
@@ -714,6 +1015,17 @@ func (a *APINotifyConfig) BuildFromService(h interface{}) error {
 		}
 		a.BufferTargetPerInterval = v.BufferTargetPerInterval
 		a.BufferIntervalSeconds = v.BufferIntervalSeconds
+		a.RateLimits = APINotifyRateLimits{
+			SlackPerMinute: v.RateLimits.SlackPerMinute,
+			JIRAPerMinute:  v.RateLimits.JIRAPerMinute,
+			EmailPerMinute: v.RateLimits.EmailPerMinute,
+		}
+		a.Attachments = APINotifyAttachmentsConfig{
+			Bucket: ToAPIString(v.Attachments.Bucket),
+			Prefix: ToAPIString(v.Attachments.Prefix),
+			Key:    ToAPIString(v.Attachments.Key),
+			Secret: ToAPIString(v.Attachments.Secret),
+		}
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -729,11 +1041,23 @@ func (a *APINotifyConfig) ToService() (interface{}, error) {
 		BufferTargetPerInterval: a.BufferTargetPerInterval,
 		BufferIntervalSeconds:   a.BufferIntervalSeconds,
 		SMTP:                    smtp.(evergreen.SMTPConfig),
+		RateLimits: evergreen.NotifyRateLimits{
+			SlackPerMinute: a.RateLimits.SlackPerMinute,
+			JIRAPerMinute:  a.RateLimits.JIRAPerMinute,
+			EmailPerMinute: a.RateLimits.EmailPerMinute,
+		},
+		Attachments: evergreen.NotifyAttachmentsConfig{
+			Bucket: FromAPIString(a.Attachments.Bucket),
+			Prefix: FromAPIString(a.Attachments.Prefix),
+			Key:    FromAPIString(a.Attachments.Key),
+			Secret: FromAPIString(a.Attachments.Secret),
+		},
 	}, nil
 }
 
 type APICloudProviders struct {
 	AWS       *APIAWSConfig       `json:"aws"`
+	Azure     *APIAzureConfig     `json:"azure"`
 	Docker    *APIDockerConfig    `json:"docker"`
 	GCE       *APIGCEConfig       `json:"gce"`
 	OpenStack *APIOpenStackConfig `json:"openstack"`
@@ -744,6 +1068,7 @@ func (a *APICloudProviders) BuildFromService(h interface{}) error {
 	switch v := h.(type) {
 	case evergreen.CloudProviders:
 		a.AWS = &APIAWSConfig{}
+		a.Azure = &APIAzureConfig{}
 		a.Docker = &APIDockerConfig{}
 		a.GCE = &APIGCEConfig{}
 		a.OpenStack = &APIOpenStackConfig{}
@@ -751,6 +1076,9 @@ func (a *APICloudProviders) BuildFromService(h interface{}) error {
 		if err := a.AWS.BuildFromService(v.AWS); err != nil {
 			return err
 		}
+		if err := a.Azure.BuildFromService(v.Azure); err != nil {
+			return err
+		}
 		if err := a.Docker.BuildFromService(v.Docker); err != nil {
 			return err
 		}
@@ -774,6 +1102,10 @@ func (a *APICloudProviders) ToService() (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	azure, err := a.Azure.ToService()
+	if err != nil {
+		return nil, err
+	}
 	docker, err := a.Docker.ToService()
 	if err != nil {
 		return nil, err
@@ -792,6 +1124,7 @@ func (a *APICloudProviders) ToService() (interface{}, error) {
 	}
 	return evergreen.CloudProviders{
 		AWS:       aws.(evergreen.AWSConfig),
+		Azure:     azure.(evergreen.AzureConfig),
 		Docker:    docker.(evergreen.DockerConfig),
 		GCE:       gce.(evergreen.GCEConfig),
 		OpenStack: openstack.(evergreen.OpenStackConfig),
@@ -836,10 +1169,12 @@ func (a *APIContainerPoolsConfig) ToService() (interface{}, error) {
 }
 
 type APIContainerPool struct {
-	Distro        APIString `json:"distro"`
-	Id            APIString `json:"id"`
-	MaxContainers int       `json:"max_containers"`
-	Port          uint16    `json:"port"`
+	Distro                 APIString `json:"distro"`
+	Id                     APIString `json:"id"`
+	MaxContainers          int       `json:"max_containers"`
+	Port                   uint16    `json:"port"`
+	ImageBakeIntervalHours int       `json:"image_bake_interval_hours"`
+	WindowsDistro          APIString `json:"windows_distro"`
 }
 
 func (a *APIContainerPool) BuildFromService(h interface{}) error {
@@ -849,6 +1184,8 @@ func (a *APIContainerPool) BuildFromService(h interface{}) error {
 		a.Id = ToAPIString(v.Id)
 		a.MaxContainers = v.MaxContainers
 		a.Port = v.Port
+		a.ImageBakeIntervalHours = v.ImageBakeIntervalHours
+		a.WindowsDistro = ToAPIString(v.WindowsDistro)
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -857,10 +1194,12 @@ func (a *APIContainerPool) BuildFromService(h interface{}) error {
 
 func (a *APIContainerPool) ToService() (interface{}, error) {
 	return evergreen.ContainerPool{
-		Distro:        FromAPIString(a.Distro),
-		Id:            FromAPIString(a.Id),
-		MaxContainers: a.MaxContainers,
-		Port:          a.Port,
+		Distro:                 FromAPIString(a.Distro),
+		Id:                     FromAPIString(a.Id),
+		MaxContainers:          a.MaxContainers,
+		Port:                   a.Port,
+		ImageBakeIntervalHours: a.ImageBakeIntervalHours,
+		WindowsDistro:          FromAPIString(a.WindowsDistro),
 	}, nil
 }
 
@@ -888,13 +1227,19 @@ func (a *APIAWSConfig) ToService() (interface{}, error) {
 }
 
 type APIDockerConfig struct {
-	APIVersion APIString `json:"api_version"`
+	APIVersion       APIString `json:"api_version"`
+	ArtifactsBucket  APIString `json:"artifacts_bucket"`
+	PruneThresholdGB int       `json:"prune_threshold_gb"`
+	PruneUnusedHours int       `json:"prune_unused_hours"`
 }
 
 func (a *APIDockerConfig) BuildFromService(h interface{}) error {
 	switch v := h.(type) {
 	case evergreen.DockerConfig:
 		a.APIVersion = ToAPIString(v.APIVersion)
+		a.ArtifactsBucket = ToAPIString(v.ArtifactsBucket)
+		a.PruneThresholdGB = v.PruneThresholdGB
+		a.PruneUnusedHours = v.PruneUnusedHours
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -903,7 +1248,10 @@ func (a *APIDockerConfig) BuildFromService(h interface{}) error {
 
 func (a *APIDockerConfig) ToService() (interface{}, error) {
 	return evergreen.DockerConfig{
-		APIVersion: FromAPIString(a.APIVersion),
+		APIVersion:       FromAPIString(a.APIVersion),
+		ArtifactsBucket:  FromAPIString(a.ArtifactsBucket),
+		PruneThresholdGB: a.PruneThresholdGB,
+		PruneUnusedHours: a.PruneUnusedHours,
 	}, nil
 }
 
@@ -977,6 +1325,35 @@ func (a *APIOpenStackConfig) ToService() (interface{}, error) {
 	}, nil
 }
 
+type APIAzureConfig struct {
+	TenantID       APIString `json:"tenant_id"`
+	ClientID       APIString `json:"client_id"`
+	ClientSecret   APIString `json:"client_secret"`
+	SubscriptionID APIString `json:"subscription_id"`
+}
+
+func (a *APIAzureConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.AzureConfig:
+		a.TenantID = ToAPIString(v.TenantID)
+		a.ClientID = ToAPIString(v.ClientID)
+		a.ClientSecret = ToAPIString(v.ClientSecret)
+		a.SubscriptionID = ToAPIString(v.SubscriptionID)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APIAzureConfig) ToService() (interface{}, error) {
+	return evergreen.AzureConfig{
+		TenantID:       FromAPIString(a.TenantID),
+		ClientID:       FromAPIString(a.ClientID),
+		ClientSecret:   FromAPIString(a.ClientSecret),
+		SubscriptionID: FromAPIString(a.SubscriptionID),
+	}, nil
+}
+
 type APIVSphereConfig struct {
 	Host     APIString `json:"host"`
 	Username APIString `json:"username"`
@@ -1055,6 +1432,60 @@ func (a *APISchedulerConfig) ToService() (interface{}, error) {
 	}, nil
 }
 
+type APISecretsConfig struct {
+	AWS    APIAWSSecretsConfig   `json:"aws"`
+	Vault  APIVaultSecretsConfig `json:"vault"`
+	Prefix APIString             `json:"prefix"`
+}
+
+type APIAWSSecretsConfig struct {
+	Region APIString `json:"region"`
+	Key    APIString `json:"key"`
+	Secret APIString `json:"secret"`
+}
+
+type APIVaultSecretsConfig struct {
+	URL       APIString `json:"url"`
+	Token     APIString `json:"token"`
+	MountPath APIString `json:"mount_path"`
+}
+
+func (a *APISecretsConfig) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case evergreen.SecretsConfig:
+		a.AWS = APIAWSSecretsConfig{
+			Region: ToAPIString(v.AWS.Region),
+			Key:    ToAPIString(v.AWS.Key),
+			Secret: ToAPIString(v.AWS.Secret),
+		}
+		a.Vault = APIVaultSecretsConfig{
+			URL:       ToAPIString(v.Vault.URL),
+			Token:     ToAPIString(v.Vault.Token),
+			MountPath: ToAPIString(v.Vault.MountPath),
+		}
+		a.Prefix = ToAPIString(v.Prefix)
+	default:
+		return errors.Errorf("%T is not a supported type", h)
+	}
+	return nil
+}
+
+func (a *APISecretsConfig) ToService() (interface{}, error) {
+	return evergreen.SecretsConfig{
+		AWS: evergreen.AWSSecretsConfig{
+			Region: FromAPIString(a.AWS.Region),
+			Key:    FromAPIString(a.AWS.Key),
+			Secret: FromAPIString(a.AWS.Secret),
+		},
+		Vault: evergreen.VaultSecretsConfig{
+			URL:       FromAPIString(a.Vault.URL),
+			Token:     FromAPIString(a.Vault.Token),
+			MountPath: FromAPIString(a.Vault.MountPath),
+		},
+		Prefix: FromAPIString(a.Prefix),
+	}, nil
+}
+
 // APIServiceFlags is a public structure representing the admin service flags
 type APIServiceFlags struct {
 	TaskDispatchDisabled         bool `json:"task_dispatch_disabled"`
@@ -1069,20 +1500,24 @@ type APIServiceFlags struct {
 	CLIUpdatesDisabled           bool `json:"cli_updates_disabled"`
 	BackgroundStatsDisabled      bool `json:"background_stats_disabled"`
 	TaskLoggingDisabled          bool `json:"task_logging_disabled"`
+	APIWritesDisabled            bool `json:"api_writes_disabled"`
 
 	// Notifications Flags
-	EventProcessingDisabled      bool `json:"event_processing_disabled"`
-	JIRANotificationsDisabled    bool `json:"jira_notifications_disabled"`
-	SlackNotificationsDisabled   bool `json:"slack_notifications_disabled"`
-	EmailNotificationsDisabled   bool `json:"email_notifications_disabled"`
-	WebhookNotificationsDisabled bool `json:"webhook_notifications_disabled"`
-	GithubStatusAPIDisabled      bool `json:"github_status_api_disabled"`
+	EventProcessingDisabled       bool `json:"event_processing_disabled"`
+	JIRANotificationsDisabled     bool `json:"jira_notifications_disabled"`
+	SlackNotificationsDisabled    bool `json:"slack_notifications_disabled"`
+	EmailNotificationsDisabled    bool `json:"email_notifications_disabled"`
+	WebhookNotificationsDisabled  bool `json:"webhook_notifications_disabled"`
+	OpsgenieNotificationsDisabled bool `json:"opsgenie_notifications_disabled"`
+	GithubStatusAPIDisabled       bool `json:"github_status_api_disabled"`
+	NotificationDispatchDisabled  bool `json:"notification_dispatch_disabled"`
 }
 
 type APISlackConfig struct {
-	Options *APISlackOptions `json:"options"`
-	Token   APIString        `json:"token"`
-	Level   APIString        `json:"level"`
+	Options       *APISlackOptions `json:"options"`
+	Token         APIString        `json:"token"`
+	Level         APIString        `json:"level"`
+	SigningSecret APIString        `json:"signing_secret"`
 }
 
 func (a *APISlackConfig) BuildFromService(h interface{}) error {
@@ -1090,6 +1525,7 @@ func (a *APISlackConfig) BuildFromService(h interface{}) error {
 	case evergreen.SlackConfig:
 		a.Token = ToAPIString(v.Token)
 		a.Level = ToAPIString(v.Level)
+		a.SigningSecret = ToAPIString(v.SigningSecret)
 		if v.Options != nil {
 			a.Options = &APISlackOptions{}
 			if err := a.Options.BuildFromService(*v.Options); err != nil { //nolint: vet
@@ -1109,9 +1545,10 @@ func (a *APISlackConfig) ToService() (interface{}, error) {
 	}
 	options := i.(send.SlackOptions) //nolint: vet
 	return evergreen.SlackConfig{
-		Token:   FromAPIString(a.Token),
-		Level:   FromAPIString(a.Level),
-		Options: &options,
+		Token:         FromAPIString(a.Token),
+		Level:         FromAPIString(a.Level),
+		SigningSecret: FromAPIString(a.SigningSecret),
+		Options:       &options,
 	}, nil
 }
 
@@ -1274,9 +1711,12 @@ func (as *APIServiceFlags) BuildFromService(h interface{}) error {
 		as.SlackNotificationsDisabled = v.SlackNotificationsDisabled
 		as.EmailNotificationsDisabled = v.EmailNotificationsDisabled
 		as.WebhookNotificationsDisabled = v.WebhookNotificationsDisabled
+		as.OpsgenieNotificationsDisabled = v.OpsgenieNotificationsDisabled
 		as.GithubStatusAPIDisabled = v.GithubStatusAPIDisabled
 		as.BackgroundStatsDisabled = v.BackgroundStatsDisabled
 		as.TaskLoggingDisabled = v.TaskLoggingDisabled
+		as.APIWritesDisabled = v.APIWritesDisabled
+		as.NotificationDispatchDisabled = v.NotificationDispatchDisabled
 	default:
 		return errors.Errorf("%T is not a supported service flags type", h)
 	}
@@ -1286,24 +1726,27 @@ func (as *APIServiceFlags) BuildFromService(h interface{}) error {
 // ToService returns a service model from an API model
 func (as *APIServiceFlags) ToService() (interface{}, error) {
 	return evergreen.ServiceFlags{
-		TaskDispatchDisabled:         as.TaskDispatchDisabled,
-		HostinitDisabled:             as.HostinitDisabled,
-		MonitorDisabled:              as.MonitorDisabled,
-		AlertsDisabled:               as.AlertsDisabled,
-		TaskrunnerDisabled:           as.TaskrunnerDisabled,
-		RepotrackerDisabled:          as.RepotrackerDisabled,
-		SchedulerDisabled:            as.SchedulerDisabled,
-		GithubPRTestingDisabled:      as.GithubPRTestingDisabled,
-		RepotrackerPushEventDisabled: as.RepotrackerPushEventDisabled,
-		CLIUpdatesDisabled:           as.CLIUpdatesDisabled,
-		EventProcessingDisabled:      as.EventProcessingDisabled,
-		JIRANotificationsDisabled:    as.JIRANotificationsDisabled,
-		SlackNotificationsDisabled:   as.SlackNotificationsDisabled,
-		EmailNotificationsDisabled:   as.EmailNotificationsDisabled,
-		WebhookNotificationsDisabled: as.WebhookNotificationsDisabled,
-		GithubStatusAPIDisabled:      as.GithubStatusAPIDisabled,
-		BackgroundStatsDisabled:      as.BackgroundStatsDisabled,
-		TaskLoggingDisabled:          as.TaskLoggingDisabled,
+		TaskDispatchDisabled:          as.TaskDispatchDisabled,
+		HostinitDisabled:              as.HostinitDisabled,
+		MonitorDisabled:               as.MonitorDisabled,
+		AlertsDisabled:                as.AlertsDisabled,
+		TaskrunnerDisabled:            as.TaskrunnerDisabled,
+		RepotrackerDisabled:           as.RepotrackerDisabled,
+		SchedulerDisabled:             as.SchedulerDisabled,
+		GithubPRTestingDisabled:       as.GithubPRTestingDisabled,
+		RepotrackerPushEventDisabled:  as.RepotrackerPushEventDisabled,
+		CLIUpdatesDisabled:            as.CLIUpdatesDisabled,
+		EventProcessingDisabled:       as.EventProcessingDisabled,
+		JIRANotificationsDisabled:     as.JIRANotificationsDisabled,
+		SlackNotificationsDisabled:    as.SlackNotificationsDisabled,
+		EmailNotificationsDisabled:    as.EmailNotificationsDisabled,
+		WebhookNotificationsDisabled:  as.WebhookNotificationsDisabled,
+		OpsgenieNotificationsDisabled: as.OpsgenieNotificationsDisabled,
+		GithubStatusAPIDisabled:       as.GithubStatusAPIDisabled,
+		BackgroundStatsDisabled:       as.BackgroundStatsDisabled,
+		TaskLoggingDisabled:           as.TaskLoggingDisabled,
+		APIWritesDisabled:             as.APIWritesDisabled,
+		NotificationDispatchDisabled:  as.NotificationDispatchDisabled,
 	}, nil
 }
 
@@ -1403,3 +1846,48 @@ func (j *APIJIRANotificationsConfig) ToService() (interface{}, error) {
 
 	return config, nil
 }
+
+// APISenderCredentials is the set of notification sender credentials that
+// can be rotated without restarting the service. Unset fields are left
+// unchanged.
+type APISenderCredentials struct {
+	SMTPUsername APIString `json:"smtp_username"`
+	SMTPPassword APIString `json:"smtp_password"`
+	SlackToken   APIString `json:"slack_token"`
+	JiraUsername APIString `json:"jira_username"`
+	JiraPassword APIString `json:"jira_password"`
+	GithubToken  APIString `json:"github_token"`
+}
+
+// ApplyTo sets the credentials that were provided on the given settings.
+func (c *APISenderCredentials) ApplyTo(settings *evergreen.Settings) {
+	if c.SMTPUsername != nil {
+		settings.Notify.SMTP.Username = FromAPIString(c.SMTPUsername)
+	}
+	if c.SMTPPassword != nil {
+		settings.Notify.SMTP.Password = FromAPIString(c.SMTPPassword)
+	}
+	if c.SlackToken != nil {
+		settings.Slack.Token = FromAPIString(c.SlackToken)
+	}
+	if c.JiraUsername != nil {
+		settings.Jira.Username = FromAPIString(c.JiraUsername)
+	}
+	if c.JiraPassword != nil {
+		settings.Jira.Password = FromAPIString(c.JiraPassword)
+	}
+	if c.GithubToken != nil {
+		credentials := map[string]string{}
+		for k, v := range settings.Credentials {
+			credentials[k] = v
+		}
+		credentials["github"] = fmt.Sprintf("token %s", FromAPIString(c.GithubToken))
+		settings.Credentials = credentials
+	}
+}
+
+// APISenderCredentialVersions reports the version of the credentials that
+// each notification sender is currently using.
+type APISenderCredentialVersions struct {
+	Versions map[string]string `json:"credential_versions"`
+}
@@ -46,6 +46,37 @@ func TestConfigModelHasMatchingFieldNames(t *testing.T) {
 	}
 }
 
+func TestConfigModelSections(t *testing.T) {
+	assert := assert.New(t)
+
+	settings := NewConfigModel()
+	for id := range evergreen.ConfigRegistry.GetSections() {
+		if id == evergreen.ConfigDocID {
+			continue
+		}
+		section, ok := settings.Section(id)
+		if assert.True(ok, id) {
+			assert.NoError(settings.SetSection(id, section), id)
+		}
+	}
+
+	section, ok := settings.Section("repotracker")
+	require.True(t, ok)
+	assert.Equal(settings.RepoTracker, section)
+	_, ok = settings.Section("banner")
+	assert.False(ok)
+	_, ok = settings.Section("")
+	assert.False(ok)
+
+	changes := &APIAdminSettings{}
+	assert.Error(changes.SetSection("repotracker", settings.Scheduler))
+	assert.Error(changes.SetSection("nope", settings.RepoTracker))
+	assert.NoError(changes.SetSection("repotracker", settings.RepoTracker))
+	assert.Equal(settings.RepoTracker, changes.RepoTracker)
+	_, ok = changes.Section("scheduler")
+	assert.False(ok)
+}
+
 func TestModelConversion(t *testing.T) {
 	assert := assert.New(t)
 	testSettings := testutil.MockConfig()
@@ -81,6 +112,18 @@ func TestModelConversion(t *testing.T) {
 		}
 	}
 
+	assert.EqualValues(testSettings.AgentUpdate.Version, FromAPIString(apiSettings.AgentUpdate.Version))
+	assert.EqualValues(testSettings.AgentUpdate.RolloutPercent, apiSettings.AgentUpdate.RolloutPercent)
+	assert.EqualValues(testSettings.AgentUpdate.Distros[0].Distro, FromAPIString(apiSettings.AgentUpdate.Distros[0].Distro))
+	assert.EqualValues(testSettings.AgentUpdate.Distros[0].RolloutPercent, apiSettings.AgentUpdate.Distros[0].RolloutPercent)
+	assert.EqualValues(testSettings.Artifacts.Bucket, FromAPIString(apiSettings.Artifacts.Bucket))
+	assert.EqualValues(testSettings.Artifacts.Secret, FromAPIString(apiSettings.Artifacts.Secret))
+	assert.EqualValues(testSettings.Artifacts.ExpirationDays, apiSettings.Artifacts.ExpirationDays)
+	assert.EqualValues(testSettings.DataLifecycle.ArchiveAfterDays, apiSettings.DataLifecycle.ArchiveAfterDays)
+	assert.EqualValues(testSettings.DataLifecycle.Target, FromAPIString(apiSettings.DataLifecycle.Target))
+	assert.EqualValues(testSettings.DataLifecycle.Bucket, FromAPIString(apiSettings.DataLifecycle.Bucket))
+	assert.EqualValues(testSettings.Secrets.AWS.Key, FromAPIString(apiSettings.Secrets.AWS.Key))
+	assert.EqualValues(testSettings.Secrets.Vault.URL, FromAPIString(apiSettings.Secrets.Vault.URL))
 	assert.EqualValues(testSettings.Alerts.SMTP.From, FromAPIString(apiSettings.Alerts.SMTP.From))
 	assert.EqualValues(testSettings.Alerts.SMTP.Port, apiSettings.Alerts.SMTP.Port)
 	assert.Equal(len(testSettings.Alerts.SMTP.AdminEmail), len(apiSettings.Alerts.SMTP.AdminEmail))
@@ -96,6 +139,8 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Port, apiSettings.ContainerPools.Pools[0].Port)
 	assert.EqualValues(testSettings.AuthConfig.Github.ClientId, FromAPIString(apiSettings.AuthConfig.Github.ClientId))
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(apiSettings.AuthConfig.Github.Users))
+	assert.EqualValues(testSettings.AuthConfig.OIDC.Issuer, FromAPIString(apiSettings.AuthConfig.OIDC.Issuer))
+	assert.Len(apiSettings.AuthConfig.OIDC.GroupRoles, len(testSettings.AuthConfig.OIDC.GroupRoles))
 	assert.EqualValues(testSettings.HostInit.SSHTimeoutSeconds, apiSettings.HostInit.SSHTimeoutSeconds)
 	assert.EqualValues(testSettings.Jira.Username, FromAPIString(apiSettings.Jira.Username))
 	assert.EqualValues(testSettings.LoggerConfig.DefaultLevel, FromAPIString(apiSettings.LoggerConfig.DefaultLevel))
@@ -108,11 +153,13 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Providers.GCE.ClientEmail, FromAPIString(apiSettings.Providers.GCE.ClientEmail))
 	assert.EqualValues(testSettings.Providers.OpenStack.IdentityEndpoint, FromAPIString(apiSettings.Providers.OpenStack.IdentityEndpoint))
 	assert.EqualValues(testSettings.Providers.VSphere.Host, FromAPIString(apiSettings.Providers.VSphere.Host))
+	assert.EqualValues(testSettings.Providers.Azure.SubscriptionID, FromAPIString(apiSettings.Providers.Azure.SubscriptionID))
 	assert.EqualValues(testSettings.RepoTracker.MaxConcurrentRequests, apiSettings.RepoTracker.MaxConcurrentRequests)
 	assert.EqualValues(testSettings.Scheduler.TaskFinder, FromAPIString(apiSettings.Scheduler.TaskFinder))
 	assert.EqualValues(testSettings.ServiceFlags.HostinitDisabled, apiSettings.ServiceFlags.HostinitDisabled)
 	assert.EqualValues(testSettings.Slack.Level, FromAPIString(apiSettings.Slack.Level))
 	assert.EqualValues(testSettings.Slack.Options.Channel, FromAPIString(apiSettings.Slack.Options.Channel))
+	assert.EqualValues(testSettings.Slack.SigningSecret, FromAPIString(apiSettings.Slack.SigningSecret))
 	assert.EqualValues(testSettings.Splunk.Channel, FromAPIString(apiSettings.Splunk.Channel))
 	assert.EqualValues(testSettings.Ui.HttpListenAddr, FromAPIString(apiSettings.Ui.HttpListenAddr))
 
@@ -120,6 +167,10 @@ func TestModelConversion(t *testing.T) {
 	dbInterface, err := apiSettings.ToService()
 	assert.NoError(err)
 	dbSettings := dbInterface.(evergreen.Settings)
+	assert.Equal(testSettings.AgentUpdate, dbSettings.AgentUpdate)
+	assert.Equal(testSettings.Artifacts, dbSettings.Artifacts)
+	assert.Equal(testSettings.DataLifecycle, dbSettings.DataLifecycle)
+	assert.Equal(testSettings.Secrets, dbSettings.Secrets)
 	assert.EqualValues(testSettings.Alerts.SMTP.From, dbSettings.Alerts.SMTP.From)
 	assert.EqualValues(testSettings.Alerts.SMTP.Port, dbSettings.Alerts.SMTP.Port)
 	assert.Equal(len(testSettings.Alerts.SMTP.AdminEmail), len(dbSettings.Alerts.SMTP.AdminEmail))
@@ -131,6 +182,7 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.AuthConfig.Naive.Users[0].Username, dbSettings.AuthConfig.Naive.Users[0].Username)
 	assert.EqualValues(testSettings.AuthConfig.Github.ClientId, dbSettings.AuthConfig.Github.ClientId)
 	assert.Equal(len(testSettings.AuthConfig.Github.Users), len(dbSettings.AuthConfig.Github.Users))
+	assert.Equal(testSettings.AuthConfig.OIDC, dbSettings.AuthConfig.OIDC)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Distro, dbSettings.ContainerPools.Pools[0].Distro)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].Id, dbSettings.ContainerPools.Pools[0].Id)
 	assert.EqualValues(testSettings.ContainerPools.Pools[0].MaxContainers, dbSettings.ContainerPools.Pools[0].MaxContainers)
@@ -147,6 +199,7 @@ func TestModelConversion(t *testing.T) {
 	assert.EqualValues(testSettings.Providers.GCE.ClientEmail, dbSettings.Providers.GCE.ClientEmail)
 	assert.EqualValues(testSettings.Providers.OpenStack.IdentityEndpoint, dbSettings.Providers.OpenStack.IdentityEndpoint)
 	assert.EqualValues(testSettings.Providers.VSphere.Host, dbSettings.Providers.VSphere.Host)
+	assert.EqualValues(testSettings.Providers.Azure.SubscriptionID, dbSettings.Providers.Azure.SubscriptionID)
 	assert.EqualValues(testSettings.RepoTracker.MaxConcurrentRequests, dbSettings.RepoTracker.MaxConcurrentRequests)
 	assert.EqualValues(testSettings.Scheduler.TaskFinder, dbSettings.Scheduler.TaskFinder)
 	assert.EqualValues(testSettings.ServiceFlags.HostinitDisabled, dbSettings.ServiceFlags.HostinitDisabled)
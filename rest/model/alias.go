@@ -2,14 +2,16 @@ package model
 
 import (
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/pkg/errors"
 )
 
 // APIAlias is the model to be returned by the API whenever aliass are fetched.
 type APIAlias struct {
-	Alias   APIString `json:"alias"`
-	Variant APIString `json:"variant"`
-	Task    APIString `json:"task"`
+	Alias   APIString   `json:"alias"`
+	Variant APIString   `json:"variant"`
+	Task    APIString   `json:"task"`
+	Tags    []APIString `json:"tags,omitempty"`
 }
 
 // BuildFromService converts from service level structs to an APIAlias.
@@ -19,6 +21,10 @@ func (apiAlias *APIAlias) BuildFromService(h interface{}) error {
 		apiAlias.Alias = ToAPIString(v.Alias)
 		apiAlias.Variant = ToAPIString(v.Variant)
 		apiAlias.Task = ToAPIString(v.Task)
+		apiAlias.Tags = nil
+		for _, tag := range v.Tags {
+			apiAlias.Tags = append(apiAlias.Tags, ToAPIString(tag))
+		}
 	default:
 		return errors.Errorf("incorrect type when fetching converting alias type")
 	}
@@ -27,5 +33,59 @@ func (apiAlias *APIAlias) BuildFromService(h interface{}) error {
 
 // ToService returns a service layer alias using the data from APIAlias.
 func (apiAlias *APIAlias) ToService() (interface{}, error) {
-	return nil, errors.Errorf("ToService() is not impelemented for APIAlias")
+	alias := model.ProjectAlias{
+		Alias:   FromAPIString(apiAlias.Alias),
+		Variant: FromAPIString(apiAlias.Variant),
+		Task:    FromAPIString(apiAlias.Task),
+	}
+	for _, tag := range apiAlias.Tags {
+		alias.Tags = append(alias.Tags, FromAPIString(tag))
+	}
+	return alias, nil
+}
+
+// APIResolvedAlias is the variants and tasks that a patch created with an
+// alias would run.
+type APIResolvedAlias struct {
+	Alias    APIString         `json:"alias"`
+	Project  APIString         `json:"project_id"`
+	Variants []APIAliasVariant `json:"variants"`
+}
+
+// APIAliasVariant is a variant an alias resolves to, with the tasks to run on
+// it.
+type APIAliasVariant struct {
+	Name         APIString   `json:"name"`
+	Tasks        []APIString `json:"tasks"`
+	DisplayTasks []APIString `json:"display_tasks"`
+}
+
+// BuildFromService converts the variants and tasks of a patch to an
+// APIResolvedAlias.
+func (apiAlias *APIResolvedAlias) BuildFromService(h interface{}) error {
+	v, ok := h.([]patch.VariantTasks)
+	if !ok {
+		return errors.Errorf("incorrect type when converting resolved alias type")
+	}
+	apiAlias.Variants = []APIAliasVariant{}
+	for _, vt := range v {
+		variant := APIAliasVariant{
+			Name:         ToAPIString(vt.Variant),
+			Tasks:        []APIString{},
+			DisplayTasks: []APIString{},
+		}
+		for _, t := range vt.Tasks {
+			variant.Tasks = append(variant.Tasks, ToAPIString(t))
+		}
+		for _, dt := range vt.DisplayTasks {
+			variant.DisplayTasks = append(variant.DisplayTasks, ToAPIString(dt.Name))
+		}
+		apiAlias.Variants = append(apiAlias.Variants, variant)
+	}
+	return nil
+}
+
+// ToService is not implemented for APIResolvedAlias.
+func (apiAlias *APIResolvedAlias) ToService() (interface{}, error) {
+	return nil, errors.Errorf("ToService() is not implemented for APIResolvedAlias")
 }
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -11,6 +13,9 @@ type APIFile struct {
 	Link           APIString `json:"url"`
 	Visibility     APIString `json:"visibility"`
 	IgnoreForFetch bool      `json:"ignore_for_fetch"`
+	Size           int64     `json:"size"`
+	SHA256         APIString `json:"sha256"`
+	ExpiresAt      APITime   `json:"expires_at"`
 }
 
 type APIEntry struct {
@@ -28,6 +33,9 @@ func (f *APIFile) BuildFromService(h interface{}) error {
 		f.Link = ToAPIString(v.Link)
 		f.Visibility = ToAPIString(v.Visibility)
 		f.IgnoreForFetch = v.IgnoreForFetch
+		f.Size = v.Size
+		f.SHA256 = ToAPIString(v.SHA256)
+		f.ExpiresAt = NewTime(v.ExpiresAt)
 	default:
 		return errors.Errorf("%T is not a supported type", h)
 	}
@@ -40,6 +48,9 @@ func (f *APIFile) ToService() (interface{}, error) {
 		Link:           FromAPIString(f.Link),
 		Visibility:     FromAPIString(f.Visibility),
 		IgnoreForFetch: f.IgnoreForFetch,
+		Size:           f.Size,
+		SHA256:         FromAPIString(f.SHA256),
+		ExpiresAt:      time.Time(f.ExpiresAt),
 	}, nil
 }
 
@@ -16,6 +16,7 @@ var (
 	patchOrigin   = "patch"
 	triggerOrigin = "trigger"
 	triggerAdHoc  = "ad_hoc"
+	manualOrigin  = "manual"
 )
 
 // APIBuild is the model to be returned by the API whenever builds are fetched.
@@ -43,6 +44,7 @@ type APIBuild struct {
 	ActualMakespan    APIDuration          `json:"actual_makespan_ms"`
 	Origin            APIString            `json:"origin"`
 	StatusCounts      task.TaskStatusCount `json:"status_counts"`
+	Priority          int64                `json:"priority"`
 }
 
 // BuildFromService converts from service level structs to an APIBuild.
@@ -72,6 +74,7 @@ func (apiBuild *APIBuild) BuildFromService(h interface{}) error {
 	apiBuild.DisplayName = ToAPIString(v.DisplayName)
 	apiBuild.PredictedMakespan = NewAPIDuration(v.PredictedMakespan)
 	apiBuild.ActualMakespan = NewAPIDuration(v.ActualMakespan)
+	apiBuild.Priority = v.Priority
 	var origin string
 	switch v.Requester {
 	case evergreen.RepotrackerVersionRequester:
@@ -84,6 +87,8 @@ func (apiBuild *APIBuild) BuildFromService(h interface{}) error {
 		origin = triggerOrigin
 	case evergreen.AdHocRequester:
 		origin = triggerAdHoc
+	case evergreen.ManualVersionRequester:
+		origin = manualOrigin
 	}
 	apiBuild.Origin = ToAPIString(origin)
 	apiBuild.TaskCache = []APITaskCache{}
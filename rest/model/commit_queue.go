@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/pkg/errors"
+)
+
+// APICommitQueue is a project's commit queue, with the item being tested at
+// the front if the queue is processing.
+type APICommitQueue struct {
+	ProjectID  APIString            `json:"queue_id"`
+	Processing bool                 `json:"processing"`
+	Queue      []APICommitQueueItem `json:"queue"`
+}
+
+// APICommitQueueItem is a pull request number or a patch ID in a commit
+// queue, with the patch testing it once it's at the front of the queue.
+type APICommitQueueItem struct {
+	Issue       APIString `json:"issue"`
+	Author      APIString `json:"author"`
+	Version     APIString `json:"version"`
+	EnqueueTime APITime   `json:"enqueue_time"`
+}
+
+func (cq *APICommitQueue) BuildFromService(h interface{}) error {
+	data, ok := h.(*commitqueue.CommitQueue)
+	if !ok {
+		return errors.New("can't convert unknown type to APICommitQueue")
+	}
+
+	cq.ProjectID = ToAPIString(data.ProjectID)
+	cq.Processing = data.Processing
+	cq.Queue = []APICommitQueueItem{}
+	for _, item := range data.Queue {
+		apiItem := APICommitQueueItem{}
+		if err := apiItem.BuildFromService(item); err != nil {
+			return errors.WithStack(err)
+		}
+		cq.Queue = append(cq.Queue, apiItem)
+	}
+
+	return nil
+}
+
+func (cq *APICommitQueue) ToService() (interface{}, error) {
+	return nil, errors.New("(*APICommitQueue) ToService not implemented")
+}
+
+func (item *APICommitQueueItem) BuildFromService(h interface{}) error {
+	data, ok := h.(commitqueue.CommitQueueItem)
+	if !ok {
+		return errors.New("can't convert unknown type to APICommitQueueItem")
+	}
+
+	item.Issue = ToAPIString(data.Issue)
+	item.Author = ToAPIString(data.Author)
+	item.Version = ToAPIString(data.Version)
+	item.EnqueueTime = NewTime(data.EnqueueTime)
+
+	return nil
+}
+
+func (item *APICommitQueueItem) ToService() (interface{}, error) {
+	return commitqueue.CommitQueueItem{
+		Issue:       FromAPIString(item.Issue),
+		Author:      FromAPIString(item.Author),
+		Version:     FromAPIString(item.Version),
+		EnqueueTime: time.Time(item.EnqueueTime),
+	}, nil
+}
+
+// APICommitQueuePosition is the position of an item added to a commit queue,
+// counting from the front of the queue at 0.
+type APICommitQueuePosition struct {
+	Position int `json:"position"`
+}
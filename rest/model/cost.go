@@ -75,3 +75,84 @@ func (apiDistroCost *APIDistroCost) BuildFromService(h interface{}) error {
 func (apiDistroCost *APIDistroCost) ToService() (interface{}, error) {
 	return nil, errors.Errorf("ToService() is not implemented for APIDistroCost")
 }
+
+// APIProjectCost is the model to be returned by the API whenever cost data is fetched by project id.
+type APIProjectCost struct {
+	ProjectId     APIString       `json:"project_id"`
+	SumTimeTaken  APIDuration     `json:"sum_time_taken"`
+	EstimatedCost float64         `json:"estimated_cost"`
+	NumTasks      int             `json:"num_tasks"`
+	Distros       []APIDistroCost `json:"distros"`
+}
+
+// BuildFromService converts from a service level project cost by loading the
+// data into the appropriate fields of the APIProjectCost.
+func (apiProjectCost *APIProjectCost) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case *task.ProjectCost:
+		apiProjectCost.ProjectId = ToAPIString(v.ProjectId)
+		apiProjectCost.SumTimeTaken = NewAPIDuration(v.SumTimeTaken)
+		apiProjectCost.EstimatedCost = v.SumEstimatedCost
+		apiProjectCost.NumTasks = v.NumTasks
+		apiProjectCost.Distros = []APIDistroCost{}
+		for i := range v.Distros {
+			distroCost := APIDistroCost{}
+			if err := distroCost.BuildFromService(&v.Distros[i]); err != nil {
+				return errors.Wrapf(err, "error converting cost of distro '%s'", v.Distros[i].DistroId)
+			}
+			apiProjectCost.Distros = append(apiProjectCost.Distros, distroCost)
+		}
+	default:
+		return errors.Errorf("incorrect type when fetching converting project cost type")
+	}
+	return nil
+}
+
+// ToService returns a service layer project cost using the data from APIProjectCost.
+func (apiProjectCost *APIProjectCost) ToService() (interface{}, error) {
+	return nil, errors.Errorf("ToService() is not implemented for APIProjectCost")
+}
+
+// APIHostCost is the model to be returned by the API whenever cost data is fetched by host id.
+type APIHostCost struct {
+	HostId        APIString   `json:"host_id"`
+	DistroId      APIString   `json:"distro_id"`
+	Provider      APIString   `json:"provider"`
+	Runtime       APIDuration `json:"runtime"`
+	CostedTime    APIDuration `json:"costed_time"`
+	HourlyPrice   float64     `json:"hourly_price"`
+	TotalCost     float64     `json:"total_cost"`
+	HourlyCost    float64     `json:"hourly_cost"`
+	SumTimeTaken  APIDuration `json:"sum_time_taken"`
+	EstimatedCost float64     `json:"estimated_cost"`
+	IdleCost      float64     `json:"idle_cost"`
+	NumTasks      int         `json:"num_tasks"`
+}
+
+// BuildFromService converts from a service level host cost by loading the
+// data into the appropriate fields of the APIHostCost.
+func (apiHostCost *APIHostCost) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case *task.HostCost:
+		apiHostCost.HostId = ToAPIString(v.HostId)
+		apiHostCost.DistroId = ToAPIString(v.DistroId)
+		apiHostCost.Provider = ToAPIString(v.Provider)
+		apiHostCost.Runtime = NewAPIDuration(v.Runtime)
+		apiHostCost.CostedTime = NewAPIDuration(v.CostedTime)
+		apiHostCost.HourlyPrice = v.HourlyPrice
+		apiHostCost.TotalCost = v.TotalCost
+		apiHostCost.HourlyCost = v.HourlyCost()
+		apiHostCost.SumTimeTaken = NewAPIDuration(v.SumTimeTaken)
+		apiHostCost.EstimatedCost = v.SumEstimatedCost
+		apiHostCost.IdleCost = v.IdleCost()
+		apiHostCost.NumTasks = v.NumTasks
+	default:
+		return errors.Errorf("incorrect type when fetching converting host cost type")
+	}
+	return nil
+}
+
+// ToService returns a service layer host cost using the data from APIHostCost.
+func (apiHostCost *APIHostCost) ToService() (interface{}, error) {
+	return nil, errors.Errorf("ToService() is not implemented for APIHostCost")
+}
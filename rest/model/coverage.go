@@ -0,0 +1,7 @@
+package model
+
+// APIProjectCoverage maps source files of a project to the tasks whose tests
+// exercise them, so that changes to the files select those tasks to run.
+type APIProjectCoverage struct {
+	Files map[string][]string `json:"files"`
+}
@@ -0,0 +1,141 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/pkg/errors"
+)
+
+// APIQueryPattern is the shape of the queries sampled for a collection,
+// with how long they took.
+type APIQueryPattern struct {
+	Query          APIString `json:"query"`
+	EqualityFields []string  `json:"equality_fields"`
+	RangeFields    []string  `json:"range_fields"`
+	Sort           []string  `json:"sort"`
+	Count          int       `json:"count"`
+	SlowCount      int       `json:"slow_count"`
+	TotalMS        float64   `json:"total_ms"`
+	MaxMS          float64   `json:"max_ms"`
+	LastSeen       APITime   `json:"last_seen"`
+}
+
+func (p *APIQueryPattern) BuildFromService(h interface{}) error {
+	data, ok := h.(db.QueryPattern)
+	if !ok {
+		return errors.New("can't convert unknown type to APIQueryPattern")
+	}
+
+	p.Query = ToAPIString(data.String())
+	p.EqualityFields = data.EqualityFields
+	p.RangeFields = data.RangeFields
+	p.Sort = data.Sort
+	if p.Sort == nil {
+		p.Sort = []string{}
+	}
+	p.Count = data.Count
+	p.SlowCount = data.SlowCount
+	p.TotalMS = float64(data.TotalDuration.Nanoseconds()) / 1e6
+	p.MaxMS = float64(data.MaxDuration.Nanoseconds()) / 1e6
+	p.LastSeen = NewTime(data.LastSeen)
+
+	return nil
+}
+
+func (p *APIQueryPattern) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIQueryPattern) ToService not implemented")
+}
+
+// APIIndexSuggestion is an index that would serve a query pattern that
+// can't use any of the collection's indexes.
+type APIIndexSuggestion struct {
+	Keys    []string        `json:"keys"`
+	Pattern APIQueryPattern `json:"pattern"`
+}
+
+// APIIndexUsage is an index that goes unused. Its accesses are -1 when the
+// server doesn't report index usage, in which case it's unused by the
+// sampled queries.
+type APIIndexUsage struct {
+	Name     APIString `json:"name"`
+	Keys     []string  `json:"keys"`
+	Accesses int64     `json:"accesses"`
+	Since    APITime   `json:"since"`
+}
+
+// APIIndexReport compares the queries sampled for a collection with its
+// indexes.
+type APIIndexReport struct {
+	Collection     APIString            `json:"collection"`
+	Patterns       []APIQueryPattern    `json:"patterns"`
+	MissingIndexes []APIIndexSuggestion `json:"missing_indexes"`
+	UnusedIndexes  []APIIndexUsage      `json:"unused_indexes"`
+}
+
+func (r *APIIndexReport) BuildFromService(h interface{}) error {
+	data, ok := h.(db.IndexReport)
+	if !ok {
+		return errors.New("can't convert unknown type to APIIndexReport")
+	}
+
+	r.Collection = ToAPIString(data.Collection)
+	r.Patterns = []APIQueryPattern{}
+	for _, p := range data.Patterns {
+		apiPattern := APIQueryPattern{}
+		if err := apiPattern.BuildFromService(p); err != nil {
+			return errors.WithStack(err)
+		}
+		r.Patterns = append(r.Patterns, apiPattern)
+	}
+	r.MissingIndexes = []APIIndexSuggestion{}
+	for _, s := range data.MissingIndexes {
+		suggestion := APIIndexSuggestion{Keys: s.Keys}
+		if err := suggestion.Pattern.BuildFromService(s.Pattern); err != nil {
+			return errors.WithStack(err)
+		}
+		r.MissingIndexes = append(r.MissingIndexes, suggestion)
+	}
+	r.UnusedIndexes = []APIIndexUsage{}
+	for _, u := range data.UnusedIndexes {
+		r.UnusedIndexes = append(r.UnusedIndexes, APIIndexUsage{
+			Name:     ToAPIString(u.Name),
+			Keys:     u.Keys,
+			Accesses: u.Accesses,
+			Since:    NewTime(u.Since),
+		})
+	}
+
+	return nil
+}
+
+func (r *APIIndexReport) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIIndexReport) ToService not implemented")
+}
+
+// APISlowQuery is a database operation that took longer than the slow
+// query threshold.
+type APISlowQuery struct {
+	Collection APIString `json:"collection"`
+	Operation  APIString `json:"operation"`
+	Query      APIString `json:"query"`
+	DurationMS float64   `json:"duration_ms"`
+	Time       APITime   `json:"time"`
+}
+
+func (q *APISlowQuery) BuildFromService(h interface{}) error {
+	data, ok := h.(db.SlowQuery)
+	if !ok {
+		return errors.New("can't convert unknown type to APISlowQuery")
+	}
+
+	q.Collection = ToAPIString(data.Collection)
+	q.Operation = ToAPIString(data.Operation)
+	q.Query = ToAPIString(data.Pattern)
+	q.DurationMS = float64(data.Duration.Nanoseconds()) / 1e6
+	q.Time = NewTime(data.Time)
+
+	return nil
+}
+
+func (q *APISlowQuery) ToService() (interface{}, error) {
+	return nil, errors.New("(*APISlowQuery) ToService not implemented")
+}
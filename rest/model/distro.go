@@ -4,6 +4,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 )
@@ -44,3 +45,113 @@ func (apiDistro *APIDistro) BuildFromService(h interface{}) error {
 func (apiDistro *APIDistro) ToService() (interface{}, error) {
 	return nil, errors.Errorf("ToService() is not impelemented for APIDistro")
 }
+
+// APIDistroIdlePolicy controls how the idle hosts of a distro are
+// terminated.
+type APIDistroIdlePolicy struct {
+	MinPoolSize   int `json:"min_pool_size"`
+	MaxIdleMins   int `json:"max_idle_mins"`
+	ScaleDownStep int `json:"scale_down_step"`
+}
+
+// BuildFromService converts from a distro.IdlePolicy to an
+// APIDistroIdlePolicy.
+func (p *APIDistroIdlePolicy) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case distro.IdlePolicy:
+		p.MinPoolSize = v.MinPoolSize
+		p.MaxIdleMins = v.MaxIdleMins
+		p.ScaleDownStep = v.ScaleDownStep
+	case *distro.IdlePolicy:
+		return p.BuildFromService(*v)
+	default:
+		return errors.Errorf("incorrect type when converting distro idle policy")
+	}
+	return nil
+}
+
+// ToService returns a distro.IdlePolicy using the data from the
+// APIDistroIdlePolicy.
+func (p *APIDistroIdlePolicy) ToService() (interface{}, error) {
+	return distro.IdlePolicy{
+		MinPoolSize:   p.MinPoolSize,
+		MaxIdleMins:   p.MaxIdleMins,
+		ScaleDownStep: p.ScaleDownStep,
+	}, nil
+}
+
+// APIParentImageRollout is the baked image that a container pool's parent
+// distro starts its hosts from, and the image it can be rolled back to.
+type APIParentImageRollout struct {
+	DistroId       APIString `json:"distro_id"`
+	Image          APIString `json:"image"`
+	PreviousImage  APIString `json:"previous_image"`
+	RolledOutAt    APITime   `json:"rolled_out_at"`
+	RejectedImages []string  `json:"rejected_images"`
+}
+
+// BuildFromService converts from a distro.Distro to an
+// APIParentImageRollout.
+func (r *APIParentImageRollout) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case distro.Distro:
+		r.DistroId = ToAPIString(v.Id)
+		r.Image = ToAPIString(v.ParentImage())
+		r.PreviousImage = ToAPIString(v.ParentImageRollout.PreviousImage)
+		r.RolledOutAt = NewTime(v.ParentImageRollout.RolledOutAt)
+		r.RejectedImages = v.ParentImageRollout.RejectedImages
+	case *distro.Distro:
+		return r.BuildFromService(*v)
+	default:
+		return errors.Errorf("incorrect type when converting distro parent image rollout")
+	}
+	return nil
+}
+
+// ToService is not implemented for APIParentImageRollout.
+func (r *APIParentImageRollout) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIParentImageRollout) ToService not implemented")
+}
+
+// APICapacityEstimate is the outcome of simulating a distro's task queue on
+// a pool of hosts of a given size.
+type APICapacityEstimate struct {
+	PoolSize    int         `json:"pool_size"`
+	NumQueued   int         `json:"num_queued"`
+	NumRunning  int         `json:"num_running"`
+	Makespan    APIDuration `json:"makespan_ms"`
+	AverageWait APIDuration `json:"average_wait_ms"`
+	MaxWait     APIDuration `json:"max_wait_ms"`
+	Stalled     bool        `json:"stalled"`
+}
+
+// BuildFromService converts from a scheduler.CapacityEstimate to an
+// APICapacityEstimate.
+func (e *APICapacityEstimate) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case scheduler.CapacityEstimate:
+		e.PoolSize = v.PoolSize
+		e.NumQueued = v.NumQueued
+		e.NumRunning = v.NumRunning
+		e.Makespan = NewAPIDuration(v.Makespan)
+		e.AverageWait = NewAPIDuration(v.AverageWait)
+		e.MaxWait = NewAPIDuration(v.MaxWait)
+		e.Stalled = v.Stalled
+	default:
+		return errors.Errorf("incorrect type when converting capacity estimate")
+	}
+	return nil
+}
+
+// ToService is not implemented for APICapacityEstimate.
+func (e *APICapacityEstimate) ToService() (interface{}, error) {
+	return nil, errors.Errorf("ToService() is not implemented for APICapacityEstimate")
+}
+
+// APIDistroCapacitySimulation compares how a distro's task queue drains at
+// its current pool size with how it would drain at a proposed one.
+type APIDistroCapacitySimulation struct {
+	DistroId APIString           `json:"distro_id"`
+	Current  APICapacityEstimate `json:"current"`
+	Proposed APICapacityEstimate `json:"proposed"`
+}
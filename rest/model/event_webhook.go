@@ -0,0 +1,80 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/pkg/errors"
+)
+
+// APIEventWebhook is an endpoint that receives the version, build, task and
+// host events that pass its filters. Its secret, used to sign each request,
+// is only returned when the webhook is created.
+type APIEventWebhook struct {
+	ID            APIString      `json:"id"`
+	URL           APIString      `json:"url"`
+	Secret        APIString      `json:"secret,omitempty"`
+	ResourceTypes []string       `json:"resource_types"`
+	EventTypes    []string       `json:"event_types"`
+	Cursor        APIEventCursor `json:"cursor"`
+	LastAttemptAt APITime        `json:"last_attempt_at"`
+	LastError     APIString      `json:"last_error"`
+	CreatedBy     APIString      `json:"created_by"`
+	CreatedAt     APITime        `json:"created_at"`
+}
+
+// APIEventCursor is the position in the event log of the last event
+// delivered to a webhook.
+type APIEventCursor struct {
+	Timestamp APITime   `json:"timestamp"`
+	EventID   APIString `json:"event_id"`
+}
+
+func (w *APIEventWebhook) BuildFromService(h interface{}) error {
+	data, ok := h.(*event.EventWebhook)
+	if !ok {
+		return errors.New("can't convert unknown type to APIEventWebhook")
+	}
+
+	w.ID = ToAPIString(data.ID)
+	w.URL = ToAPIString(data.URL)
+	w.ResourceTypes = data.ResourceTypes
+	w.EventTypes = data.EventTypes
+	w.Cursor = APIEventCursor{
+		Timestamp: NewTime(data.Cursor.Timestamp),
+		EventID:   ToAPIString(data.Cursor.EventID),
+	}
+	w.LastAttemptAt = NewTime(data.LastAttemptAt)
+	w.LastError = ToAPIString(data.LastError)
+	w.CreatedBy = ToAPIString(data.CreatedBy)
+	w.CreatedAt = NewTime(data.CreatedAt)
+
+	return nil
+}
+
+func (w *APIEventWebhook) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIEventWebhook) ToService not implemented")
+}
+
+// APIEventWebhookReplay moves a webhook's cursor back, so that the events
+// after the event with ID Cursor, or logged since the time Since, are
+// delivered again.
+type APIEventWebhookReplay struct {
+	Cursor APIString `json:"cursor"`
+	Since  APITime   `json:"since"`
+}
+
+// Validate returns an error unless exactly one of the cursor and the time
+// is given.
+func (r *APIEventWebhookReplay) Validate() error {
+	hasCursor := FromAPIString(r.Cursor) != ""
+	hasSince := !time.Time(r.Since).IsZero()
+	if hasCursor == hasSince {
+		return errors.New("exactly one of cursor and since must be given")
+	}
+	if hasSince && time.Time(r.Since).After(time.Now()) {
+		return errors.New("since cannot be in the future")
+	}
+
+	return nil
+}
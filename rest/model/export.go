@@ -0,0 +1,173 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// APITaskHistoryRow is a task as it's exported, one per row, to external
+// analytics in CSV or JSON lines.
+type APITaskHistoryRow struct {
+	TaskID       APIString   `json:"task_id"`
+	Execution    int         `json:"execution"`
+	Project      APIString   `json:"project"`
+	Revision     APIString   `json:"revision"`
+	Order        int         `json:"order"`
+	BuildVariant APIString   `json:"build_variant"`
+	DisplayName  APIString   `json:"display_name"`
+	Distro       APIString   `json:"distro"`
+	Status       APIString   `json:"status"`
+	DetailsType  APIString   `json:"details_type"`
+	TimedOut     bool        `json:"timed_out"`
+	StartTime    APITime     `json:"start_time"`
+	FinishTime   APITime     `json:"finish_time"`
+	TimeTaken    APIDuration `json:"time_taken_ms"`
+}
+
+func (r *APITaskHistoryRow) BuildFromService(h interface{}) error {
+	t, ok := h.(*task.Task)
+	if !ok {
+		return errors.New("incorrect type when converting task history row")
+	}
+
+	r.TaskID = ToAPIString(t.Id)
+	r.Execution = t.Execution
+	r.Project = ToAPIString(t.Project)
+	r.Revision = ToAPIString(t.Revision)
+	r.Order = t.RevisionOrderNumber
+	r.BuildVariant = ToAPIString(t.BuildVariant)
+	r.DisplayName = ToAPIString(t.DisplayName)
+	r.Distro = ToAPIString(t.DistroId)
+	r.Status = ToAPIString(t.Status)
+	r.DetailsType = ToAPIString(t.Details.Type)
+	r.TimedOut = t.Details.TimedOut
+	r.StartTime = NewTime(t.StartTime)
+	r.FinishTime = NewTime(t.FinishTime)
+	r.TimeTaken = NewAPIDuration(t.TimeTaken)
+
+	return nil
+}
+
+func (r *APITaskHistoryRow) ToService() (interface{}, error) {
+	return nil, errors.New("(*APITaskHistoryRow) ToService not implemented")
+}
+
+// CSVHeader returns the names of the columns of the row's CSV record.
+func (r *APITaskHistoryRow) CSVHeader() []string {
+	return []string{"task_id", "execution", "project", "revision", "order", "build_variant", "display_name",
+		"distro", "status", "details_type", "timed_out", "start_time", "finish_time", "time_taken_ms"}
+}
+
+// CSVRecord returns the row as a CSV record.
+func (r *APITaskHistoryRow) CSVRecord() []string {
+	return []string{
+		FromAPIString(r.TaskID),
+		strconv.Itoa(r.Execution),
+		FromAPIString(r.Project),
+		FromAPIString(r.Revision),
+		strconv.Itoa(r.Order),
+		FromAPIString(r.BuildVariant),
+		FromAPIString(r.DisplayName),
+		FromAPIString(r.Distro),
+		FromAPIString(r.Status),
+		FromAPIString(r.DetailsType),
+		strconv.FormatBool(r.TimedOut),
+		csvTime(r.StartTime),
+		csvTime(r.FinishTime),
+		fmt.Sprint(uint64(r.TimeTaken)),
+	}
+}
+
+// APITestResultRow is a test result, along with the task that ran the test,
+// as it's exported, one per row, to external analytics in CSV or JSON lines.
+type APITestResultRow struct {
+	TaskID       APIString   `json:"task_id"`
+	Execution    int         `json:"execution"`
+	Project      APIString   `json:"project"`
+	Revision     APIString   `json:"revision"`
+	Order        int         `json:"order"`
+	BuildVariant APIString   `json:"build_variant"`
+	TaskName     APIString   `json:"task_name"`
+	TestFile     APIString   `json:"test_file"`
+	Status       APIString   `json:"status"`
+	ExitCode     int         `json:"exit_code"`
+	StartTime    APITime     `json:"start_time"`
+	EndTime      APITime     `json:"end_time"`
+	Duration     APIDuration `json:"duration_ms"`
+}
+
+// BuildFromService sets the row's task fields from a task, and its test
+// fields from a test result.
+func (r *APITestResultRow) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case *task.Task:
+		r.TaskID = ToAPIString(v.Id)
+		r.Execution = v.Execution
+		r.Project = ToAPIString(v.Project)
+		r.Revision = ToAPIString(v.Revision)
+		r.Order = v.RevisionOrderNumber
+		r.BuildVariant = ToAPIString(v.BuildVariant)
+		r.TaskName = ToAPIString(v.DisplayName)
+	case *testresult.TestResult:
+		startTime := util.FromPythonTime(v.StartTime)
+		endTime := util.FromPythonTime(v.EndTime)
+
+		r.TestFile = ToAPIString(v.TestFile)
+		r.Status = ToAPIString(v.Status)
+		r.ExitCode = v.ExitCode
+		r.StartTime = NewTime(startTime)
+		r.EndTime = NewTime(endTime)
+		if endTime.After(startTime) {
+			r.Duration = NewAPIDuration(endTime.Sub(startTime))
+		}
+	default:
+		return errors.New("incorrect type when converting test result row")
+	}
+
+	return nil
+}
+
+func (r *APITestResultRow) ToService() (interface{}, error) {
+	return nil, errors.New("(*APITestResultRow) ToService not implemented")
+}
+
+// CSVHeader returns the names of the columns of the row's CSV record.
+func (r *APITestResultRow) CSVHeader() []string {
+	return []string{"task_id", "execution", "project", "revision", "order", "build_variant", "task_name",
+		"test_file", "status", "exit_code", "start_time", "end_time", "duration_ms"}
+}
+
+// CSVRecord returns the row as a CSV record.
+func (r *APITestResultRow) CSVRecord() []string {
+	return []string{
+		FromAPIString(r.TaskID),
+		strconv.Itoa(r.Execution),
+		FromAPIString(r.Project),
+		FromAPIString(r.Revision),
+		strconv.Itoa(r.Order),
+		FromAPIString(r.BuildVariant),
+		FromAPIString(r.TaskName),
+		FromAPIString(r.TestFile),
+		FromAPIString(r.Status),
+		strconv.Itoa(r.ExitCode),
+		csvTime(r.StartTime),
+		csvTime(r.EndTime),
+		fmt.Sprint(uint64(r.Duration)),
+	}
+}
+
+// csvTime formats the time as it's formatted in JSON, leaving zero times
+// empty.
+func csvTime(t APITime) string {
+	if util.IsZeroTime(time.Time(t)) {
+		return ""
+	}
+	return time.Time(t).UTC().Format(strings.Trim(APITimeFormat, `"`))
+}
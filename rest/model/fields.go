@@ -0,0 +1,220 @@
+package model
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FieldSelection is a set of fields of a REST model, by their JSON names,
+// that a client requested, so that responses only include what the client
+// needs. Each field maps to the selection of its nested fields, which is
+// nil if the field is included whole.
+type FieldSelection map[string]FieldSelection
+
+// ParseFieldSelection parses a comma-separated list of fields, in which
+// nested fields are named with dots, e.g. "status,status_details.type". An
+// empty list selects every field.
+func ParseFieldSelection(fields string) FieldSelection {
+	selection := FieldSelection{}
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		current := selection
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				// selecting a field whole supersedes its nested fields
+				current[part] = nil
+				break
+			}
+			nested, ok := current[part]
+			if ok && nested == nil {
+				// the field is already selected whole
+				break
+			}
+			if !ok {
+				nested = FieldSelection{}
+				current[part] = nested
+			}
+			current = nested
+		}
+	}
+
+	return selection
+}
+
+// IsEmpty returns true if every field is selected.
+func (s FieldSelection) IsEmpty() bool { return len(s) == 0 }
+
+// Includes returns true if the field, or any of its nested fields, is
+// selected, so that work building fields that aren't selected can be
+// skipped.
+func (s FieldSelection) Includes(field string) bool {
+	if s.IsEmpty() {
+		return true
+	}
+	_, ok := s[field]
+	return ok
+}
+
+// Validate returns an error naming the selected fields that the model, or a
+// slice of models, doesn't have.
+func (s FieldSelection) Validate(m interface{}) error {
+	if s.IsEmpty() {
+		return nil
+	}
+	unknown := s.unknownFields(reflect.TypeOf(m), "")
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return errors.Errorf("unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+func (s FieldSelection) unknownFields(t reflect.Type, prefix string) []string {
+	t = selectableType(t)
+	unknown := []string{}
+	if t.Kind() != reflect.Struct || t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		for name := range s {
+			unknown = append(unknown, prefix+name)
+		}
+		return unknown
+	}
+
+	fields := jsonFields(t)
+	for name, nested := range s {
+		field, ok := fields[name]
+		if !ok {
+			unknown = append(unknown, prefix+name)
+			continue
+		}
+		if !nested.IsEmpty() {
+			unknown = append(unknown, nested.unknownFields(t.FieldByIndex(field).Type, prefix+name+".")...)
+		}
+	}
+	return unknown
+}
+
+// Project returns the selected fields of the model, keyed by their JSON
+// names. Slices and maps of models are projected element by element, and
+// nested models by their nested selections. The selection should already be
+// validated against the model.
+func (s FieldSelection) Project(m interface{}) (interface{}, error) {
+	if s.IsEmpty() {
+		return m, nil
+	}
+	return s.project(reflect.ValueOf(m))
+}
+
+func (s FieldSelection) project(v reflect.Value) (interface{}, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil, nil
+		}
+		out := make([]interface{}, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			projected, err := s.project(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, projected)
+		}
+		return out, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		out := make(map[string]interface{}, v.Len())
+		for _, key := range v.MapKeys() {
+			projected, err := s.project(v.MapIndex(key))
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprint(key.Interface())] = projected
+		}
+		return out, nil
+	case reflect.Struct:
+		fields := jsonFields(v.Type())
+		out := make(map[string]interface{}, len(s))
+		for name, nested := range s {
+			index, ok := fields[name]
+			if !ok {
+				return nil, errors.Errorf("unknown field '%s'", name)
+			}
+			field := v.FieldByIndex(index)
+			if nested.IsEmpty() {
+				out[name] = field.Interface()
+				continue
+			}
+			projected, err := nested.project(field)
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem projecting field '%s'", name)
+			}
+			out[name] = projected
+		}
+		return out, nil
+	default:
+		return nil, errors.Errorf("can't select fields of type %s", v.Type())
+	}
+}
+
+// selectableType returns the type whose fields are selected for a value of
+// the given type, looking through pointers, slices and maps.
+func selectableType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+				return t
+			}
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// jsonFields returns the index of each exported field of the struct by its
+// JSON name, including the fields of embedded structs.
+func jsonFields(t reflect.Type) map[string][]int {
+	fields := map[string][]int{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embeddedName, index := range jsonFields(field.Type) {
+				if _, ok := fields[embeddedName]; !ok {
+					fields[embeddedName] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Index
+	}
+
+	return fields
+}
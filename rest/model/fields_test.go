@@ -0,0 +1,94 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldsTestNested struct {
+	Type   string `json:"type"`
+	Desc   string `json:"desc"`
+	Ignore string `json:"-"`
+}
+
+type fieldsTestModel struct {
+	fieldsTestNested
+	Id       APIString                    `json:"_id"`
+	Status   string                       `json:"status"`
+	Details  fieldsTestNested             `json:"details"`
+	Children []fieldsTestNested           `json:"children"`
+	ByName   map[string]*fieldsTestNested `json:"by_name"`
+	When     APITime                      `json:"when"`
+}
+
+func TestParseFieldSelection(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(ParseFieldSelection("").IsEmpty())
+	assert.True(ParseFieldSelection(" , ").IsEmpty())
+
+	fields := ParseFieldSelection("_id, status,details.type,details.desc")
+	assert.Equal(FieldSelection{
+		"_id":     nil,
+		"status":  nil,
+		"details": FieldSelection{"type": nil, "desc": nil},
+	}, fields)
+	assert.True(fields.Includes("details"))
+	assert.False(fields.Includes("children"))
+	assert.True(FieldSelection{}.Includes("children"))
+
+	// selecting a field whole supersedes selecting its nested fields
+	assert.Equal(FieldSelection{"details": nil}, ParseFieldSelection("details.type,details"))
+	assert.Equal(FieldSelection{"details": nil}, ParseFieldSelection("details,details.type"))
+}
+
+func TestValidateFieldSelection(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(FieldSelection{}.Validate(fieldsTestModel{}))
+	assert.NoError(ParseFieldSelection("_id,type,details.desc,children.type,by_name.desc,when").Validate(fieldsTestModel{}))
+	assert.NoError(ParseFieldSelection("_id").Validate([]*fieldsTestModel{}))
+
+	err := ParseFieldSelection("_id,nonexistent,details.nonexistent,status.type,Ignore").Validate(fieldsTestModel{})
+	require.Error(t, err)
+	assert.Equal("unknown fields: Ignore, details.nonexistent, nonexistent, status.type", err.Error())
+
+	assert.Error(ParseFieldSelection("when.year").Validate(fieldsTestModel{}))
+}
+
+func TestProjectFieldSelection(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	m := &fieldsTestModel{
+		fieldsTestNested: fieldsTestNested{Type: "embedded"},
+		Id:               ToAPIString("id"),
+		Status:           "success",
+		Details:          fieldsTestNested{Type: "nested", Desc: "details"},
+		Children:         []fieldsTestNested{{Type: "child", Desc: "first"}},
+		ByName:           map[string]*fieldsTestNested{"one": {Type: "named", Desc: "one"}},
+	}
+
+	projected, err := FieldSelection{}.Project(m)
+	require.NoError(err)
+	assert.Equal(m, projected)
+
+	projected, err = ParseFieldSelection("_id,type,details.desc,children.type,by_name.desc").Project(m)
+	require.NoError(err)
+	assert.Equal(map[string]interface{}{
+		"_id":      ToAPIString("id"),
+		"type":     "embedded",
+		"details":  map[string]interface{}{"desc": "details"},
+		"children": []interface{}{map[string]interface{}{"type": "child"}},
+		"by_name":  map[string]interface{}{"one": map[string]interface{}{"desc": "one"}},
+	}, projected)
+
+	projected, err = ParseFieldSelection("status").Project([]fieldsTestModel{*m})
+	require.NoError(err)
+	assert.Equal([]interface{}{map[string]interface{}{"status": "success"}}, projected)
+
+	_, err = ParseFieldSelection("nonexistent").Project(m)
+	assert.Error(err)
+}
@@ -19,6 +19,18 @@ type APIHost struct {
 	Status      APIString  `json:"status"`
 	RunningTask taskInfo   `json:"running_task"`
 	UserHost    bool       `json:"user_host"`
+	ImageDigest APIString  `json:"image_digest"`
+	Draining    bool       `json:"draining"`
+	Region      APIString  `json:"region"`
+
+	ExpirationTime APITime               `json:"expiration_time"`
+	Volumes        []APIVolumeAttachment `json:"volumes"`
+}
+
+// APIVolumeAttachment is a persistent volume attached to a host.
+type APIVolumeAttachment struct {
+	VolumeID   APIString `json:"volume_id"`
+	DeviceName APIString `json:"device_name"`
 }
 
 // HostPostRequest is a struct that holds the format of a POST request to /hosts
@@ -86,6 +98,17 @@ func (apiHost *APIHost) buildFromHostStruct(h interface{}) error {
 	apiHost.User = ToAPIString(v.User)
 	apiHost.Status = ToAPIString(v.Status)
 	apiHost.UserHost = v.UserHost
+	apiHost.ImageDigest = ToAPIString(v.ImageDigest)
+	apiHost.Draining = v.Draining
+	apiHost.Region = ToAPIString(v.Region)
+	apiHost.ExpirationTime = NewTime(v.ExpirationTime)
+	apiHost.Volumes = make([]APIVolumeAttachment, 0, len(v.Volumes))
+	for _, volume := range v.Volumes {
+		apiHost.Volumes = append(apiHost.Volumes, APIVolumeAttachment{
+			VolumeID:   ToAPIString(volume.VolumeID),
+			DeviceName: ToAPIString(volume.DeviceName),
+		})
+	}
 
 	di := DistroInfo{
 		Id:       ToAPIString(v.Distro.Id),
@@ -113,4 +136,62 @@ type APISpawnHostModify struct {
 	HostID   APIString `json:"host_id"`
 	RDPPwd   APIString `json:"rdp_pwd"`
 	AddHours APIString `json:"add_hours"`
+	NewOwner APIString `json:"new_owner"`
+}
+
+// APIHostHealth is the result of the most recent health check of a static
+// host.
+type APIHostHealth struct {
+	HostID APIString `json:"host_id"`
+	Distro APIString `json:"distro"`
+	Status APIString `json:"status"`
+	// Checked is false if the host has not been checked yet, in which case
+	// the rest of the health check is empty.
+	Checked             bool        `json:"checked"`
+	Healthy             bool        `json:"healthy"`
+	CheckedAt           APITime     `json:"checked_at"`
+	DiskFreeMB          int         `json:"disk_free_mb"`
+	ClockDriftSecs      float64     `json:"clock_drift_secs"`
+	AgentVersion        APIString   `json:"agent_version"`
+	Failures            []APIString `json:"failures"`
+	ConsecutiveFailures int         `json:"consecutive_failures"`
+}
+
+// BuildFromService converts from a service level host.
+func (apiHealth *APIHostHealth) BuildFromService(h interface{}) error {
+	var v *host.Host
+	switch h := h.(type) {
+	case host.Host:
+		v = &h
+	case *host.Host:
+		v = h
+	default:
+		return fmt.Errorf("incorrect type when converting host health (%T)", h)
+	}
+
+	apiHealth.HostID = ToAPIString(v.Id)
+	apiHealth.Distro = ToAPIString(v.Distro.Id)
+	apiHealth.Status = ToAPIString(v.Status)
+	apiHealth.Failures = []APIString{}
+	if v.HealthCheck == nil {
+		return nil
+	}
+
+	check := v.HealthCheck
+	apiHealth.Checked = true
+	apiHealth.Healthy = check.Healthy()
+	apiHealth.CheckedAt = NewTime(check.CheckedAt)
+	apiHealth.DiskFreeMB = check.DiskFreeMB
+	apiHealth.ClockDriftSecs = check.ClockDrift.Seconds()
+	apiHealth.AgentVersion = ToAPIString(check.AgentVersion)
+	for _, failure := range check.Failures {
+		apiHealth.Failures = append(apiHealth.Failures, ToAPIString(failure))
+	}
+	apiHealth.ConsecutiveFailures = check.ConsecutiveFailures
+	return nil
+}
+
+// ToService is not implemented for APIHostHealth.
+func (apiHealth *APIHostHealth) ToService() (interface{}, error) {
+	return nil, fmt.Errorf("ToService() is not implemented for APIHostHealth")
 }
@@ -34,6 +34,7 @@ func TestHostBuildFromService(t *testing.T) {
 					Type:        ToAPIString("testType"),
 					User:        ToAPIString("testUser"),
 					Status:      ToAPIString("testStatus"),
+					ImageDigest: ToAPIString("sha256:testDigest"),
 					RunningTask: taskInfo{
 						Id:           ToAPIString("testRunningTaskId"),
 						Name:         ToAPIString("testRTName"),
@@ -53,6 +54,7 @@ func TestHostBuildFromService(t *testing.T) {
 					InstanceType: "testType",
 					User:         "testUser",
 					Status:       "testStatus",
+					ImageDigest:  "sha256:testDigest",
 				},
 				st: task.Task{
 					Id:           "testRunningTaskId",
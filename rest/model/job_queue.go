@@ -0,0 +1,99 @@
+package model
+
+import (
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
+)
+
+const (
+	QueueJobStatusPending    = "pending"
+	QueueJobStatusInProgress = "in-progress"
+	QueueJobStatusCompleted  = "completed"
+	QueueJobStatusErrored    = "errored"
+)
+
+// QueueJobStatus returns the status of a job in a background job queue, as
+// reported by the API.
+func QueueJobStatus(s amboy.JobStatusInfo) string {
+	switch {
+	case s.Completed && (len(s.Errors) > 0 || s.ErrorCount > 0):
+		return QueueJobStatusErrored
+	case s.Completed:
+		return QueueJobStatusCompleted
+	case s.InProgress:
+		return QueueJobStatusInProgress
+	default:
+		return QueueJobStatusPending
+	}
+}
+
+// APIJobQueueStats summarizes the jobs tracked by one of the background job
+// queues.
+type APIJobQueueStats struct {
+	Name      APIString `json:"name"`
+	Running   int       `json:"running"`
+	Pending   int       `json:"pending"`
+	Blocked   int       `json:"blocked"`
+	Completed int       `json:"completed"`
+	Total     int       `json:"total"`
+}
+
+func (s *APIJobQueueStats) BuildFromService(h interface{}) error {
+	data, ok := h.(amboy.QueueStats)
+	if !ok {
+		return errors.New("can't convert unknown type to APIJobQueueStats")
+	}
+
+	s.Running = data.Running
+	s.Pending = data.Pending
+	s.Blocked = data.Blocked
+	s.Completed = data.Completed
+	s.Total = data.Total
+
+	return nil
+}
+
+func (s *APIJobQueueStats) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIJobQueueStats) ToService not implemented")
+}
+
+// APIQueueJob is a job in one of the background job queues.
+type APIQueueJob struct {
+	ID         APIString `json:"id"`
+	Type       APIString `json:"type"`
+	Status     APIString `json:"status"`
+	Priority   int       `json:"priority"`
+	Errors     []string  `json:"errors"`
+	CreatedAt  APITime   `json:"created_at"`
+	StartedAt  APITime   `json:"started_at"`
+	EndedAt    APITime   `json:"ended_at"`
+	ModifiedAt APITime   `json:"modified_at"`
+}
+
+func (j *APIQueueJob) BuildFromService(h interface{}) error {
+	data, ok := h.(amboy.Job)
+	if !ok {
+		return errors.New("can't convert unknown type to APIQueueJob")
+	}
+
+	status := data.Status()
+	timeInfo := data.TimeInfo()
+	j.ID = ToAPIString(data.ID())
+	j.Type = ToAPIString(data.Type().Name)
+	j.Status = ToAPIString(QueueJobStatus(status))
+	j.Priority = data.Priority()
+	j.Errors = status.Errors
+	if j.Errors == nil {
+		j.Errors = []string{}
+	}
+	j.CreatedAt = NewTime(timeInfo.Created)
+	j.StartedAt = NewTime(timeInfo.Start)
+	j.EndedAt = NewTime(timeInfo.End)
+	j.ModifiedAt = NewTime(status.ModificationTime)
+
+	return nil
+}
+
+func (j *APIQueueJob) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIQueueJob) ToService not implemented")
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"sort"
+
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	"github.com/pkg/errors"
+)
+
+// APIManifest is the model to be returned by the API whenever the module
+// revisions pinned for a version are fetched.
+type APIManifest struct {
+	Id       APIString   `json:"version_id"`
+	Revision APIString   `json:"revision"`
+	Project  APIString   `json:"project"`
+	Branch   APIString   `json:"branch"`
+	Modules  []APIModule `json:"modules"`
+}
+
+// APIModule is the model for the pinned revision of a module.
+type APIModule struct {
+	Name     APIString `json:"name"`
+	Owner    APIString `json:"owner"`
+	Repo     APIString `json:"repo"`
+	Branch   APIString `json:"branch"`
+	Revision APIString `json:"revision"`
+	URL      APIString `json:"url"`
+}
+
+// BuildFromService converts from service level structs to an APIManifest.
+// Modules are ordered by name.
+func (apiManifest *APIManifest) BuildFromService(h interface{}) error {
+	m, ok := h.(*manifest.Manifest)
+	if !ok {
+		return errors.Errorf("incorrect type when fetching converting manifest type")
+	}
+
+	apiManifest.Id = ToAPIString(m.Id)
+	apiManifest.Revision = ToAPIString(m.Revision)
+	apiManifest.Project = ToAPIString(m.ProjectName)
+	apiManifest.Branch = ToAPIString(m.Branch)
+
+	names := make([]string, 0, len(m.Modules))
+	for name := range m.Modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	apiManifest.Modules = []APIModule{}
+	for _, name := range names {
+		module := m.Modules[name]
+		if module == nil {
+			continue
+		}
+		apiManifest.Modules = append(apiManifest.Modules, APIModule{
+			Name:     ToAPIString(name),
+			Owner:    ToAPIString(module.Owner),
+			Repo:     ToAPIString(module.Repo),
+			Branch:   ToAPIString(module.Branch),
+			Revision: ToAPIString(module.Revision),
+			URL:      ToAPIString(module.URL),
+		})
+	}
+
+	return nil
+}
+
+// ToService returns a service layer manifest using the data from APIManifest.
+func (apiManifest *APIManifest) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APIManifest")
+}
@@ -1,9 +1,24 @@
 package model
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
 
@@ -33,6 +48,7 @@ type apiNotificationStats struct {
 	EvergreenWebhook  int `json:"evergreen_webhook"`
 	Email             int `json:"email"`
 	Slack             int `json:"slack"`
+	Opsgenie          int `json:"opsgenie"`
 }
 
 func (n *apiNotificationStats) BuildFromService(h interface{}) error {
@@ -50,6 +66,7 @@ func (n *apiNotificationStats) BuildFromService(h interface{}) error {
 	n.EvergreenWebhook = data.EvergreenWebhook
 	n.Email = data.Email
 	n.Slack = data.Slack
+	n.Opsgenie = data.Opsgenie
 
 	return nil
 }
@@ -57,3 +74,1134 @@ func (n *apiNotificationStats) BuildFromService(h interface{}) error {
 func (n *apiNotificationStats) ToService() (interface{}, error) {
 	return nil, errors.New("(*apiNotificationsStats) ToService not implemented")
 }
+
+type APINotification struct {
+	ID             APIString `json:"id"`
+	SubscriberType APIString `json:"subscriber_type"`
+	SubscriptionID APIString `json:"subscription_id"`
+	IncidentID     APIString `json:"incident_id"`
+	EscalationID   APIString `json:"escalation_id,omitempty"`
+	Initiator      APIString `json:"initiator"`
+	Status         APIString `json:"status"`
+	CreatedAt      APITime   `json:"created_at"`
+	SentAt         APITime   `json:"sent_at"`
+	Error          APIString `json:"error"`
+
+	// DeliveryAttempts and DeliveryStatusCode describe the delivery of
+	// webhook notifications
+	DeliveryAttempts   int `json:"delivery_attempts,omitempty"`
+	DeliveryStatusCode int `json:"delivery_status_code,omitempty"`
+
+	// SlackChannel and SlackTS identify the message posted for slack
+	// notifications, which can be replied to or updated with them
+	SlackChannel APIString `json:"slack_channel,omitempty"`
+	SlackTS      APIString `json:"slack_ts,omitempty"`
+
+	Attempts     int  `json:"attempts"`
+	DeadLettered bool `json:"dead_lettered"`
+
+	// Preview is the message that would be sent, for notifications that
+	// were composed without being sent
+	Preview *APINotificationPreview `json:"preview,omitempty"`
+}
+
+// APINotificationPreview is the message composed for a notification: the
+// sender that would send it, its text, and the payload the sender would
+// be given.
+type APINotificationPreview struct {
+	Sender  APIString   `json:"sender"`
+	Message APIString   `json:"message"`
+	Payload interface{} `json:"payload"`
+}
+
+func (n *APINotification) BuildFromService(h interface{}) error {
+	data, ok := h.(*notification.Notification)
+	if !ok {
+		return errors.New("can't convert unknown type to APINotification")
+	}
+
+	n.ID = ToAPIString(data.ID)
+	n.SubscriberType = ToAPIString(data.Subscriber.Type)
+	n.SubscriptionID = ToAPIString(data.SubscriptionID)
+	n.IncidentID = ToAPIString(data.IncidentID)
+	if data.EscalationID != "" {
+		n.EscalationID = ToAPIString(data.EscalationID)
+	}
+	n.Initiator = ToAPIString(data.Initiator)
+	n.Status = ToAPIString(data.Status())
+	n.CreatedAt = NewTime(data.CreatedAt)
+	n.SentAt = NewTime(data.SentAt)
+	n.Error = ToAPIString(data.Error)
+	n.Attempts = data.Attempts
+	n.DeadLettered = data.DeadLettered
+	if data.Delivery != nil {
+		n.DeliveryAttempts = data.Delivery.Attempts
+		n.DeliveryStatusCode = data.Delivery.StatusCode
+	}
+	if data.SlackMessage != nil {
+		n.SlackChannel = ToAPIString(data.SlackMessage.Channel)
+		n.SlackTS = ToAPIString(data.SlackMessage.TS)
+	}
+
+	return nil
+}
+
+func (n *APINotification) ToService() (interface{}, error) {
+	return nil, errors.New("(*APINotification) ToService not implemented")
+}
+
+// APINotificationAuditEntry is an attempt to send a notification, from the
+// notification audit log.
+type APINotificationAuditEntry struct {
+	ID             APIString `json:"id"`
+	NotificationID APIString `json:"notification_id"`
+	SubscriberType APIString `json:"subscriber_type"`
+	Target         APIString `json:"target"`
+	PayloadHash    APIString `json:"payload_hash"`
+	Initiator      APIString `json:"initiator"`
+	SubscriptionID APIString `json:"subscription_id"`
+	Result         APIString `json:"result"`
+	Error          APIString `json:"error"`
+	Attempt        int       `json:"attempt"`
+	Timestamp      APITime   `json:"timestamp"`
+}
+
+func (e *APINotificationAuditEntry) BuildFromService(h interface{}) error {
+	data, ok := h.(*notification.AuditEntry)
+	if !ok {
+		return errors.New("can't convert unknown type to APINotificationAuditEntry")
+	}
+
+	e.ID = ToAPIString(data.ID.Hex())
+	e.NotificationID = ToAPIString(data.NotificationID)
+	e.SubscriberType = ToAPIString(data.SubscriberType)
+	e.Target = ToAPIString(data.Target)
+	e.PayloadHash = ToAPIString(data.PayloadHash)
+	e.Initiator = ToAPIString(data.Initiator)
+	e.SubscriptionID = ToAPIString(data.SubscriptionID)
+	e.Result = ToAPIString(data.Result)
+	e.Error = ToAPIString(data.Error)
+	e.Attempt = data.Attempt
+	e.Timestamp = NewTime(data.Timestamp)
+
+	return nil
+}
+
+func (e *APINotificationAuditEntry) ToService() (interface{}, error) {
+	return nil, errors.New("(*APINotificationAuditEntry) ToService not implemented")
+}
+
+type APINotificationAnalytics struct {
+	Start           APITime                 `json:"start"`
+	End             APITime                 `json:"end"`
+	IntervalSeconds int64                   `json:"interval_seconds"`
+	Sent            int                     `json:"sent"`
+	Failed          int                     `json:"failed"`
+	SuccessRate     float64                 `json:"success_rate"`
+	MeanLatencyMS   int64                   `json:"mean_latency_ms"`
+	Volume          []APINotificationVolume `json:"volume"`
+	NoisySources    []APINotificationSource `json:"noisy_sources"`
+}
+
+type APINotificationVolume struct {
+	Start  APITime `json:"start"`
+	Sent   int     `json:"sent"`
+	Failed int     `json:"failed"`
+}
+
+type APINotificationSource struct {
+	Owner     APIString `json:"owner"`
+	OwnerType APIString `json:"owner_type"`
+	Trigger   APIString `json:"trigger"`
+	Sent      int       `json:"sent"`
+	Failed    int       `json:"failed"`
+}
+
+func (a *APINotificationAnalytics) BuildFromService(h interface{}) error {
+	data, ok := h.(*notification.NotificationAnalytics)
+	if !ok {
+		return errors.New("can't convert unknown type to APINotificationAnalytics")
+	}
+
+	a.Start = NewTime(data.Start)
+	a.End = NewTime(data.End)
+	a.IntervalSeconds = int64(data.Interval / time.Second)
+	a.Sent = data.Sent
+	a.Failed = data.Failed
+	a.SuccessRate = data.SuccessRate()
+	a.MeanLatencyMS = int64(data.MeanLatency / time.Millisecond)
+
+	a.Volume = make([]APINotificationVolume, 0, len(data.Volume))
+	for _, v := range data.Volume {
+		a.Volume = append(a.Volume, APINotificationVolume{
+			Start:  NewTime(v.Start),
+			Sent:   v.Sent,
+			Failed: v.Failed,
+		})
+	}
+	a.NoisySources = make([]APINotificationSource, 0, len(data.NoisySources))
+	for _, s := range data.NoisySources {
+		a.NoisySources = append(a.NoisySources, APINotificationSource{
+			Owner:     ToAPIString(s.Owner),
+			OwnerType: ToAPIString(string(s.OwnerType)),
+			Trigger:   ToAPIString(s.Trigger),
+			Sent:      s.Sent,
+			Failed:    s.Failed,
+		})
+	}
+
+	return nil
+}
+
+func (a *APINotificationAnalytics) ToService() (interface{}, error) {
+	return nil, errors.New("(*APINotificationAnalytics) ToService not implemented")
+}
+
+// APIWebhook is a request to deliver a payload to an arbitrary URL. The
+// payload is signed with the secret.
+type APIWebhook struct {
+	URL     APIString           `json:"url"`
+	Headers map[string][]string `json:"headers"`
+	Payload json.RawMessage     `json:"payload"`
+	Secret  APIString           `json:"secret"`
+	// IdempotencyKey identifies the webhook, so that retried requests
+	// with the same key deliver the webhook once
+	IdempotencyKey APIString `json:"idempotency_key"`
+}
+
+func (w *APIWebhook) BuildFromService(h interface{}) error {
+	return errors.New("(*APIWebhook) BuildFromService not implemented")
+}
+
+// Validate returns an error describing each invalid field of the webhook.
+func (w *APIWebhook) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if rawURL := FromAPIString(w.URL); rawURL == "" {
+		catcher.Add(errors.New("url: cannot be empty"))
+	} else if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		catcher.Add(errors.Errorf("url: '%s' is not an absolute http or https URL", rawURL))
+	} else if ip := net.ParseIP(u.Hostname()); u.Hostname() == "localhost" || (ip != nil && !util.IsPublicIP(ip)) {
+		// host names are also checked when the webhook is sent, once
+		// they're resolved
+		catcher.Add(errors.Errorf("url: '%s' is not a public address", rawURL))
+	}
+	if FromAPIString(w.Secret) == "" {
+		catcher.Add(errors.New("secret: cannot be empty"))
+	}
+	for k := range w.Headers {
+		if strings.TrimSpace(k) == "" {
+			catcher.Add(errors.New("headers: header names cannot be empty"))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+func (w *APIWebhook) ToService() (interface{}, error) {
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+
+	headers := http.Header{}
+	for k, values := range w.Headers {
+		for _, v := range values {
+			headers.Add(k, v)
+		}
+	}
+
+	return &util.EvergreenWebhook{
+		URL:     FromAPIString(w.URL),
+		Secret:  []byte(FromAPIString(w.Secret)),
+		Body:    []byte(w.Payload),
+		Headers: headers,
+	}, nil
+}
+
+// APIEmail is a request to send an email, which may have an HTML body and
+// attachments.
+type APIEmail struct {
+	From       APIString           `json:"from"`
+	Recipients []APIString         `json:"recipients"`
+	Subject    APIString           `json:"subject"`
+	Headers    map[string][]string `json:"headers"`
+	// Body is the plain-text body of the email. If HTMLBody is set, the
+	// email is sent with both, and Body is generated from HTMLBody if empty
+	Body        APIString       `json:"body"`
+	HTMLBody    APIString       `json:"html_body"`
+	Attachments []APIAttachment `json:"attachments"`
+}
+
+// APIAttachment is a file attached to a notification. The contents are
+// given either base64 encoded in Content, or as an s3://bucket/key URL in
+// S3URL.
+type APIAttachment struct {
+	Filename    APIString `json:"filename"`
+	ContentType APIString `json:"content_type"`
+	Content     APIString `json:"content"`
+	S3URL       APIString `json:"s3_url"`
+}
+
+// validate adds an error to the catcher for each invalid field of the
+// attachment, which is the field named in the request, and returns the size
+// of its content.
+func (a *APIAttachment) validate(field string, catcher grip.Catcher) int {
+	if FromAPIString(a.Filename) == "" {
+		catcher.Add(errors.Errorf("%s: filename cannot be empty", field))
+	}
+	if contentType := FromAPIString(a.ContentType); contentType != "" {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			catcher.Add(errors.Errorf("%s: '%s' is not a valid content type", field, contentType))
+		}
+	}
+
+	size := 0
+	content, s3URL := FromAPIString(a.Content), FromAPIString(a.S3URL)
+	switch {
+	case content != "" && s3URL != "":
+		catcher.Add(errors.Errorf("%s: content and s3_url cannot both be set", field))
+	case content != "":
+		data, err := base64.StdEncoding.DecodeString(content)
+		if err != nil {
+			catcher.Add(errors.Errorf("%s: content is not base64 encoded", field))
+		}
+		size = len(data)
+	case s3URL != "":
+		if u, err := url.Parse(s3URL); err != nil || u.Scheme != "s3" || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+			catcher.Add(errors.Errorf("%s: '%s' is not an s3://bucket/key URL", field, s3URL))
+		}
+	default:
+		catcher.Add(errors.Errorf("%s: one of content and s3_url must be set", field))
+	}
+
+	return size
+}
+
+// toService returns the attachment, which must be valid.
+func (a *APIAttachment) toService() util.Attachment {
+	attachment := util.Attachment{
+		Filename:    FromAPIString(a.Filename),
+		ContentType: FromAPIString(a.ContentType),
+		S3URL:       FromAPIString(a.S3URL),
+	}
+	if content := FromAPIString(a.Content); content != "" {
+		attachment.Data, _ = base64.StdEncoding.DecodeString(content)
+	}
+
+	return attachment
+}
+
+func (e *APIEmail) BuildFromService(h interface{}) error {
+	return errors.New("(*APIEmail) BuildFromService not implemented")
+}
+
+// Validate returns an error describing each invalid field of the email.
+func (e *APIEmail) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if from := FromAPIString(e.From); from != "" {
+		if _, err := mail.ParseAddress(from); err != nil {
+			catcher.Add(errors.Errorf("from: '%s' is not a valid address", from))
+		}
+	}
+	if len(e.Recipients) == 0 {
+		catcher.Add(errors.New("recipients: cannot be empty"))
+	}
+	for _, r := range e.Recipients {
+		if _, err := mail.ParseAddress(FromAPIString(r)); err != nil {
+			catcher.Add(errors.Errorf("recipients: '%s' is not a valid address", FromAPIString(r)))
+		}
+	}
+	if FromAPIString(e.Subject) == "" {
+		catcher.Add(errors.New("subject: cannot be empty"))
+	}
+	if FromAPIString(e.Body) == "" && FromAPIString(e.HTMLBody) == "" {
+		catcher.Add(errors.New("body: body and html_body cannot both be empty"))
+	}
+	for k, v := range e.Headers {
+		if strings.TrimSpace(k) == "" || len(v) == 0 {
+			catcher.Add(errors.New("headers: headers must have a name and a value"))
+		}
+	}
+
+	size := 0
+	for i := range e.Attachments {
+		size += e.Attachments[i].validate(fmt.Sprintf("attachments[%d]", i), catcher)
+	}
+	if size > util.AttachmentMaxSize {
+		catcher.Add(errors.Errorf("attachments: attachments cannot exceed %d bytes", util.AttachmentMaxSize))
+	}
+
+	return catcher.Resolve()
+}
+
+func (e *APIEmail) ToService() (interface{}, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	email := &util.EvergreenEmail{
+		Email: message.Email{
+			From:              FromAPIString(e.From),
+			Subject:           FromAPIString(e.Subject),
+			Body:              FromAPIString(e.Body),
+			PlainTextContents: true,
+			Headers:           e.Headers,
+		},
+		HTMLBody: FromAPIString(e.HTMLBody),
+	}
+	for _, r := range e.Recipients {
+		email.Recipients = append(email.Recipients, FromAPIString(r))
+	}
+	for i := range e.Attachments {
+		email.Attachments = append(email.Attachments, e.Attachments[i].toService())
+	}
+
+	return email, nil
+}
+
+// APISlack is a request to send a Slack message to a channel or user. The
+// message may have legacy attachments and Block Kit blocks; if it has
+// blocks, Msg is only used as the notification text.
+type APISlack struct {
+	Target      APIString            `json:"target"`
+	Msg         APIString            `json:"msg"`
+	Attachments []APISlackAttachment `json:"attachments"`
+	Blocks      []APISlackBlock      `json:"blocks"`
+
+	// ThreadKey threads messages about the same subject, such as a
+	// version: the first message sent to the target with the key starts
+	// a thread, and later ones are posted as replies in it.
+	ThreadKey APIString `json:"thread_key"`
+	// ThreadTS is the timestamp of a message to reply to in its thread,
+	// and UpdateTS is the timestamp of a message to replace with this
+	// one, in which case the target must be the ID of its channel.
+	ThreadTS APIString `json:"thread_ts"`
+	UpdateTS APIString `json:"update_ts"`
+}
+
+var (
+	slackChannelIDRegex = regexp.MustCompile(`^[CGD][A-Z0-9]+$`)
+	slackTSRegex        = regexp.MustCompile(`^[0-9]+\.[0-9]+$`)
+)
+
+// APISlackAttachment is a legacy Slack message attachment.
+type APISlackAttachment struct {
+	Color      APIString                 `json:"color"`
+	Fallback   APIString                 `json:"fallback"`
+	AuthorName APIString                 `json:"author_name"`
+	AuthorIcon APIString                 `json:"author_icon"`
+	Title      APIString                 `json:"title"`
+	TitleLink  APIString                 `json:"title_link"`
+	Text       APIString                 `json:"text"`
+	Fields     []APISlackAttachmentField `json:"fields"`
+	MarkdownIn []APIString               `json:"mrkdwn_in"`
+	Footer     APIString                 `json:"footer"`
+}
+
+type APISlackAttachmentField struct {
+	Title APIString `json:"title"`
+	Value APIString `json:"value"`
+	Short bool      `json:"short"`
+}
+
+// APISlackBlock is a Slack Block Kit block, which is a section, context,
+// actions or divider block.
+type APISlackBlock struct {
+	Type     APIString              `json:"type"`
+	BlockID  APIString              `json:"block_id"`
+	Text     *APISlackText          `json:"text"`
+	Fields   []APISlackText         `json:"fields"`
+	Elements []APISlackBlockElement `json:"elements"`
+}
+
+// APISlackText is a plain_text or mrkdwn text object in a Slack block.
+type APISlackText struct {
+	Type APIString `json:"type"`
+	Text APIString `json:"text"`
+}
+
+// APISlackBlockElement is a text or image element of a context block, or a
+// button in an actions block.
+type APISlackBlockElement struct {
+	Type     APIString `json:"type"`
+	Text     APIString `json:"text"`
+	ImageURL APIString `json:"image_url"`
+	AltText  APIString `json:"alt_text"`
+	ActionID APIString `json:"action_id"`
+	URL      APIString `json:"url"`
+	Value    APIString `json:"value"`
+	Style    APIString `json:"style"`
+}
+
+func (a *APISlackAttachment) BuildFromService(h interface{}) error {
+	data, ok := h.(*message.SlackAttachment)
+	if !ok {
+		return errors.New("can't convert unknown type to APISlackAttachment")
+	}
+
+	a.Color = ToAPIString(data.Color)
+	a.Fallback = ToAPIString(data.Fallback)
+	a.AuthorName = ToAPIString(data.AuthorName)
+	a.AuthorIcon = ToAPIString(data.AuthorIcon)
+	a.Title = ToAPIString(data.Title)
+	a.TitleLink = ToAPIString(data.TitleLink)
+	a.Text = ToAPIString(data.Text)
+	a.Footer = ToAPIString(data.Footer)
+	a.Fields = nil
+	for _, f := range data.Fields {
+		if f == nil {
+			continue
+		}
+		a.Fields = append(a.Fields, APISlackAttachmentField{
+			Title: ToAPIString(f.Title),
+			Value: ToAPIString(f.Value),
+			Short: f.Short,
+		})
+	}
+	a.MarkdownIn = nil
+	for _, m := range data.MarkdownIn {
+		a.MarkdownIn = append(a.MarkdownIn, ToAPIString(m))
+	}
+
+	return nil
+}
+
+func (a *APISlackAttachment) ToService() (interface{}, error) {
+	attachment := message.SlackAttachment{
+		Color:      FromAPIString(a.Color),
+		Fallback:   FromAPIString(a.Fallback),
+		AuthorName: FromAPIString(a.AuthorName),
+		AuthorIcon: FromAPIString(a.AuthorIcon),
+		Title:      FromAPIString(a.Title),
+		TitleLink:  FromAPIString(a.TitleLink),
+		Text:       FromAPIString(a.Text),
+		Footer:     FromAPIString(a.Footer),
+	}
+	for _, f := range a.Fields {
+		attachment.Fields = append(attachment.Fields, &message.SlackAttachmentField{
+			Title: FromAPIString(f.Title),
+			Value: FromAPIString(f.Value),
+			Short: f.Short,
+		})
+	}
+	for _, m := range a.MarkdownIn {
+		attachment.MarkdownIn = append(attachment.MarkdownIn, FromAPIString(m))
+	}
+
+	return &attachment, nil
+}
+
+func (b *APISlackBlock) BuildFromService(h interface{}) error {
+	data, ok := h.(*util.SlackBlock)
+	if !ok {
+		return errors.New("can't convert unknown type to APISlackBlock")
+	}
+
+	b.Type = ToAPIString(data.Type)
+	b.BlockID = ToAPIString(data.BlockID)
+	b.Text = nil
+	if data.Text != nil {
+		b.Text = &APISlackText{
+			Type: ToAPIString(data.Text.Type),
+			Text: ToAPIString(data.Text.Text),
+		}
+	}
+	b.Fields = nil
+	for _, f := range data.Fields {
+		b.Fields = append(b.Fields, APISlackText{
+			Type: ToAPIString(f.Type),
+			Text: ToAPIString(f.Text),
+		})
+	}
+	b.Elements = nil
+	for _, e := range data.Elements {
+		b.Elements = append(b.Elements, APISlackBlockElement{
+			Type:     ToAPIString(e.Type),
+			Text:     ToAPIString(e.Text),
+			ImageURL: ToAPIString(e.ImageURL),
+			AltText:  ToAPIString(e.AltText),
+			ActionID: ToAPIString(e.ActionID),
+			URL:      ToAPIString(e.URL),
+			Value:    ToAPIString(e.Value),
+			Style:    ToAPIString(e.Style),
+		})
+	}
+
+	return nil
+}
+
+func (b *APISlackBlock) ToService() (interface{}, error) {
+	block := util.SlackBlock{
+		Type:    FromAPIString(b.Type),
+		BlockID: FromAPIString(b.BlockID),
+	}
+	if b.Text != nil {
+		block.Text = &util.SlackText{
+			Type: FromAPIString(b.Text.Type),
+			Text: FromAPIString(b.Text.Text),
+		}
+	}
+	for _, f := range b.Fields {
+		block.Fields = append(block.Fields, util.SlackText{
+			Type: FromAPIString(f.Type),
+			Text: FromAPIString(f.Text),
+		})
+	}
+	for _, e := range b.Elements {
+		block.Elements = append(block.Elements, util.SlackBlockElement{
+			Type:     FromAPIString(e.Type),
+			Text:     FromAPIString(e.Text),
+			ImageURL: FromAPIString(e.ImageURL),
+			AltText:  FromAPIString(e.AltText),
+			ActionID: FromAPIString(e.ActionID),
+			URL:      FromAPIString(e.URL),
+			Value:    FromAPIString(e.Value),
+			Style:    FromAPIString(e.Style),
+		})
+	}
+
+	return &block, nil
+}
+
+func (s *APISlack) BuildFromService(h interface{}) error {
+	return errors.New("(*APISlack) BuildFromService not implemented")
+}
+
+// Validate returns an error describing each invalid field of the message.
+func (s *APISlack) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	target := FromAPIString(s.Target)
+	updateTS := FromAPIString(s.UpdateTS)
+	switch {
+	case target == "":
+		catcher.Add(errors.New("target: cannot be empty"))
+	case updateTS != "" && !slackChannelIDRegex.MatchString(target):
+		catcher.Add(errors.Errorf("target: '%s' is not a channel ID, which is required to update a message", target))
+	case updateTS == "" && !strings.HasPrefix(target, "#") && !strings.HasPrefix(target, "@") && !slackChannelIDRegex.MatchString(target):
+		catcher.Add(errors.Errorf("target: '%s' is not a #channel, @user or channel ID", target))
+	}
+	threadTS := FromAPIString(s.ThreadTS)
+	if threadTS != "" && !slackTSRegex.MatchString(threadTS) {
+		catcher.Add(errors.Errorf("thread_ts: '%s' is not a slack message timestamp", threadTS))
+	}
+	if updateTS != "" && !slackTSRegex.MatchString(updateTS) {
+		catcher.Add(errors.Errorf("update_ts: '%s' is not a slack message timestamp", updateTS))
+	}
+	if threadTS != "" && updateTS != "" {
+		catcher.Add(errors.New("thread_ts: cannot be combined with update_ts"))
+	}
+	if FromAPIString(s.ThreadKey) != "" && (threadTS != "" || updateTS != "") {
+		catcher.Add(errors.New("thread_key: cannot be combined with thread_ts or update_ts"))
+	}
+	if FromAPIString(s.Msg) == "" && len(s.Blocks) == 0 {
+		catcher.Add(errors.New("msg: msg and blocks cannot both be empty"))
+	}
+	for i, a := range s.Attachments {
+		if FromAPIString(a.Fallback) == "" {
+			catcher.Add(errors.Errorf("attachments[%d]: fallback cannot be empty", i))
+		}
+	}
+	catcher.Add(util.ValidateSlackBlocks(s.blocks()))
+
+	return catcher.Resolve()
+}
+
+func (s *APISlack) blocks() []util.SlackBlock {
+	blocks := make([]util.SlackBlock, 0, len(s.Blocks))
+	for i := range s.Blocks {
+		block, _ := s.Blocks[i].ToService()
+		blocks = append(blocks, *block.(*util.SlackBlock))
+	}
+
+	return blocks
+}
+
+func (s *APISlack) ToService() (interface{}, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	msg := &util.EvergreenSlack{
+		Target:   FromAPIString(s.Target),
+		Msg:      FromAPIString(s.Msg),
+		Blocks:   s.blocks(),
+		ThreadTS: FromAPIString(s.ThreadTS),
+		UpdateTS: FromAPIString(s.UpdateTS),
+	}
+	for i := range s.Attachments {
+		attachment, _ := s.Attachments[i].ToService()
+		msg.Attachments = append(msg.Attachments, *attachment.(*message.SlackAttachment))
+	}
+
+	return msg, nil
+}
+
+// APIOpsgenieAlert is a request to create an Opsgenie alert with the API key
+// of an integration. Alerts with the same alias as an open alert are
+// deduplicated by Opsgenie.
+type APIOpsgenieAlert struct {
+	APIKey      APIString         `json:"api_key"`
+	Team        APIString         `json:"team"`
+	Message     APIString         `json:"message"`
+	Alias       APIString         `json:"alias"`
+	Description APIString         `json:"description"`
+	Priority    APIString         `json:"priority"`
+	Tags        []string          `json:"tags"`
+	Details     map[string]string `json:"details"`
+	Entity      APIString         `json:"entity"`
+}
+
+func (a *APIOpsgenieAlert) BuildFromService(h interface{}) error {
+	return errors.New("(*APIOpsgenieAlert) BuildFromService not implemented")
+}
+
+// Validate returns an error describing each invalid field of the alert.
+func (a *APIOpsgenieAlert) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if FromAPIString(a.APIKey) == "" {
+		catcher.Add(errors.New("api_key: cannot be empty"))
+	}
+	if msg := FromAPIString(a.Message); msg == "" {
+		catcher.Add(errors.New("message: cannot be empty"))
+	} else if len(msg) > util.OpsgenieMaxMessageLength {
+		catcher.Add(errors.Errorf("message: cannot be longer than %d characters", util.OpsgenieMaxMessageLength))
+	}
+	if len(FromAPIString(a.Alias)) > util.OpsgenieMaxAliasLength {
+		catcher.Add(errors.Errorf("alias: cannot be longer than %d characters", util.OpsgenieMaxAliasLength))
+	}
+	if priority := FromAPIString(a.Priority); priority != "" && !util.StringSliceContains(util.OpsgeniePriorities, priority) {
+		catcher.Add(errors.Errorf("priority: '%s' is not one of %s", priority, strings.Join(util.OpsgeniePriorities, ", ")))
+	}
+	if len(a.Tags) > util.OpsgenieMaxTags {
+		catcher.Add(errors.Errorf("tags: cannot have more than %d tags", util.OpsgenieMaxTags))
+	}
+
+	return catcher.Resolve()
+}
+
+// ToService returns the alert, with Opsgenie's default priority if it has
+// none.
+func (a *APIOpsgenieAlert) ToService() (interface{}, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	alert := &util.OpsgenieAlert{
+		APIKey:      FromAPIString(a.APIKey),
+		Team:        FromAPIString(a.Team),
+		Message:     FromAPIString(a.Message),
+		Alias:       FromAPIString(a.Alias),
+		Description: FromAPIString(a.Description),
+		Priority:    FromAPIString(a.Priority),
+		Tags:        a.Tags,
+		Details:     a.Details,
+		Entity:      FromAPIString(a.Entity),
+		Source:      "evergreen",
+	}
+	if alert.Priority == "" {
+		alert.Priority = util.OpsgeniePriorityModerate
+	}
+
+	return alert, nil
+}
+
+// Action IDs of the buttons in Slack messages that Evergreen handles when
+// they're clicked. The value of each button is the ID of the task,
+// notification, or subscription to act on.
+const (
+	SlackActionRestartTask           = "restart_task"
+	SlackActionAcknowledgeBuildBreak = "acknowledge_build_break"
+	SlackActionMuteNotifications     = "mute_notifications"
+
+	slackInteractionBlockActions = "block_actions"
+	slackResponseURLPrefix       = "https://hooks.slack.com/"
+)
+
+var SlackActions = []string{
+	SlackActionRestartTask,
+	SlackActionAcknowledgeBuildBreak,
+	SlackActionMuteNotifications,
+}
+
+// APISlackInteraction is the payload Slack sends when a user clicks a
+// button in a message.
+type APISlackInteraction struct {
+	Type        APIString                   `json:"type"`
+	User        APISlackInteractionUser     `json:"user"`
+	Actions     []APISlackInteractionAction `json:"actions"`
+	ResponseURL APIString                   `json:"response_url"`
+}
+
+type APISlackInteractionUser struct {
+	ID       APIString `json:"id"`
+	Username APIString `json:"username"`
+}
+
+type APISlackInteractionAction struct {
+	ActionID APIString `json:"action_id"`
+	BlockID  APIString `json:"block_id"`
+	Value    APIString `json:"value"`
+}
+
+func (i *APISlackInteraction) BuildFromService(h interface{}) error {
+	return errors.New("(*APISlackInteraction) BuildFromService not implemented")
+}
+
+func (i *APISlackInteraction) ToService() (interface{}, error) {
+	return nil, errors.New("(*APISlackInteraction) ToService not implemented")
+}
+
+// Validate returns an error describing each invalid field of the
+// interaction.
+func (i *APISlackInteraction) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if interactionType := FromAPIString(i.Type); interactionType != slackInteractionBlockActions {
+		catcher.Add(errors.Errorf("type: '%s' interactions are not supported", interactionType))
+	}
+	if FromAPIString(i.User.ID) == "" {
+		catcher.Add(errors.New("user: id cannot be empty"))
+	}
+	if len(i.Actions) == 0 {
+		catcher.Add(errors.New("actions: cannot be empty"))
+	}
+	for idx, action := range i.Actions {
+		if actionID := FromAPIString(action.ActionID); !util.StringSliceContains(SlackActions, actionID) {
+			catcher.Add(errors.Errorf("actions[%d]: '%s' is not a supported action", idx, actionID))
+		}
+		if FromAPIString(action.Value) == "" {
+			catcher.Add(errors.Errorf("actions[%d]: value cannot be empty", idx))
+		}
+	}
+	if responseURL := FromAPIString(i.ResponseURL); responseURL != "" && !strings.HasPrefix(responseURL, slackResponseURLPrefix) {
+		catcher.Add(errors.Errorf("response_url: '%s' is not a slack URL", responseURL))
+	}
+
+	return catcher.Resolve()
+}
+
+// APIJiraIssue is the current state of a JIRA issue.
+type APIJiraIssue struct {
+	Key        APIString `json:"key"`
+	Summary    APIString `json:"summary"`
+	Status     APIString `json:"status"`
+	Resolution APIString `json:"resolution"`
+}
+
+func (i *APIJiraIssue) BuildFromService(h interface{}) error {
+	data, ok := h.(*thirdparty.JiraTicket)
+	if !ok {
+		return errors.New("can't convert unknown type to APIJiraIssue")
+	}
+
+	i.Key = ToAPIString(data.Key)
+	if data.Fields == nil {
+		return nil
+	}
+	i.Summary = ToAPIString(data.Fields.Summary)
+	if data.Fields.Status != nil {
+		i.Status = ToAPIString(data.Fields.Status.Name)
+	}
+	if data.Fields.Resolution != nil {
+		i.Resolution = ToAPIString(data.Fields.Resolution.Name)
+	}
+
+	return nil
+}
+
+func (i *APIJiraIssue) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIJiraIssue) ToService not implemented")
+}
+
+// APIJiraIssueTransition is a request to move a JIRA issue through a
+// workflow transition, named either by its ID, its name, or the name of the
+// status it moves the issue to. Update sets fields of the issue as part of
+// the transition, and Comment is added to the issue.
+type APIJiraIssueTransition struct {
+	Transition APIString              `json:"transition"`
+	Update     map[string]interface{} `json:"update"`
+	Comment    APIString              `json:"comment"`
+}
+
+// Validate returns an error describing each invalid field of the request.
+func (t *APIJiraIssueTransition) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if strings.TrimSpace(FromAPIString(t.Transition)) == "" {
+		catcher.Add(errors.New("transition: cannot be empty"))
+	}
+	for k := range t.Update {
+		if strings.TrimSpace(k) == "" {
+			catcher.Add(errors.New("update: field names cannot be empty"))
+		}
+	}
+
+	return catcher.Resolve()
+}
+
+// FindTransition returns the transition the request names, checking the
+// transition IDs, then names, then the names of the statuses they move the
+// issue to.
+func (t *APIJiraIssueTransition) FindTransition(transitions []thirdparty.JiraTransition) (*thirdparty.JiraTransition, error) {
+	name := strings.TrimSpace(FromAPIString(t.Transition))
+	for i := range transitions {
+		if transitions[i].Id == name {
+			return &transitions[i], nil
+		}
+	}
+	for i := range transitions {
+		if strings.EqualFold(transitions[i].Name, name) {
+			return &transitions[i], nil
+		}
+	}
+	for i := range transitions {
+		if transitions[i].To != nil && strings.EqualFold(transitions[i].To.Name, name) {
+			return &transitions[i], nil
+		}
+	}
+
+	available := make([]string, 0, len(transitions))
+	for _, tr := range transitions {
+		available = append(available, fmt.Sprintf("'%s'", tr.Name))
+	}
+	if len(available) == 0 {
+		return nil, errors.Errorf("transition: '%s' is not available, since the issue has no transitions", name)
+	}
+
+	return nil, errors.Errorf("transition: '%s' is not one of the available transitions %s", name, strings.Join(available, ", "))
+}
+
+// APIJiraIssueAttachments is a request to attach files to a JIRA issue.
+type APIJiraIssueAttachments struct {
+	Attachments []APIAttachment `json:"attachments"`
+}
+
+// Validate returns an error describing each invalid field of the request.
+func (a *APIJiraIssueAttachments) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if len(a.Attachments) == 0 {
+		catcher.Add(errors.New("attachments: cannot be empty"))
+	}
+	size := 0
+	for i := range a.Attachments {
+		size += a.Attachments[i].validate(fmt.Sprintf("attachments[%d]", i), catcher)
+	}
+	if size > util.AttachmentMaxSize {
+		catcher.Add(errors.Errorf("attachments: attachments cannot exceed %d bytes", util.AttachmentMaxSize))
+	}
+
+	return catcher.Resolve()
+}
+
+func (a *APIJiraIssueAttachments) BuildFromService(h interface{}) error {
+	return errors.New("(*APIJiraIssueAttachments) BuildFromService not implemented")
+}
+
+func (a *APIJiraIssueAttachments) ToService() (interface{}, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	attachments := make([]util.Attachment, 0, len(a.Attachments))
+	for i := range a.Attachments {
+		attachments = append(attachments, a.Attachments[i].toService())
+	}
+
+	return attachments, nil
+}
+
+// APIJiraAttachment is a file attached to a JIRA issue.
+type APIJiraAttachment struct {
+	ID       APIString `json:"id"`
+	Filename APIString `json:"filename"`
+	MimeType APIString `json:"mime_type"`
+	Size     int       `json:"size"`
+	URL      APIString `json:"url"`
+}
+
+func (a *APIJiraAttachment) BuildFromService(h interface{}) error {
+	data, ok := h.(*thirdparty.JiraAttachment)
+	if !ok {
+		return errors.New("can't convert unknown type to APIJiraAttachment")
+	}
+
+	a.ID = ToAPIString(data.Id)
+	a.Filename = ToAPIString(data.Filename)
+	a.MimeType = ToAPIString(data.MimeType)
+	a.Size = data.Size
+	a.URL = ToAPIString(data.Content)
+
+	return nil
+}
+
+func (a *APIJiraAttachment) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIJiraAttachment) ToService not implemented")
+}
+
+// APINotificationTemplate is a stored template for notifications.
+type APINotificationTemplate struct {
+	Name      APIString `json:"name"`
+	Subject   APIString `json:"subject"`
+	Body      APIString `json:"body"`
+	UpdatedAt APITime   `json:"updated_at"`
+}
+
+func (t *APINotificationTemplate) BuildFromService(h interface{}) error {
+	data, ok := h.(*notification.Template)
+	if !ok {
+		return errors.New("can't convert unknown type to APINotificationTemplate")
+	}
+
+	t.Name = ToAPIString(data.Name)
+	t.Subject = ToAPIString(data.Subject)
+	t.Body = ToAPIString(data.Body)
+	t.UpdatedAt = NewTime(data.UpdatedAt)
+
+	return nil
+}
+
+func (t *APINotificationTemplate) ToService() (interface{}, error) {
+	return &notification.Template{
+		Name:    FromAPIString(t.Name),
+		Subject: FromAPIString(t.Subject),
+		Body:    FromAPIString(t.Body),
+	}, nil
+}
+
+// APITemplateNotification is a request to send a notification rendered from
+// a stored template with the data.
+type APITemplateNotification struct {
+	Template   APIString              `json:"template"`
+	Data       map[string]interface{} `json:"data"`
+	Subscriber APISubscriber          `json:"subscriber"`
+}
+
+// Validate returns an error describing each invalid field of the request.
+func (n *APITemplateNotification) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if FromAPIString(n.Template) == "" {
+		catcher.Add(errors.New("template: cannot be empty"))
+	}
+	switch subscriberType := FromAPIString(n.Subscriber.Type); subscriberType {
+	case event.EmailSubscriberType, event.SlackSubscriberType, event.JIRACommentSubscriberType:
+		if target, ok := n.Subscriber.Target.(string); !ok || target == "" {
+			catcher.Add(errors.Errorf("subscriber: target of '%s' subscriber must be a non-empty string", subscriberType))
+		}
+	case event.JIRAIssueSubscriberType:
+		if n.Subscriber.Target == nil {
+			catcher.Add(errors.New("subscriber: target of jira-issue subscriber cannot be empty"))
+		}
+	default:
+		catcher.Add(errors.Errorf("subscriber: templates cannot be sent to '%s' subscribers", subscriberType))
+	}
+
+	return catcher.Resolve()
+}
+
+type APIIncident struct {
+	ID              APIString   `json:"id"`
+	SubscriptionID  APIString   `json:"subscription_id"`
+	Source          APIString   `json:"source"`
+	ExternalID      APIString   `json:"external_id"`
+	Title           APIString   `json:"title"`
+	CreatedAt       APITime     `json:"created_at"`
+	ResolvedAt      APITime     `json:"resolved_at"`
+	NotificationIDs []APIString `json:"notification_ids"`
+}
+
+func (i *APIIncident) BuildFromService(h interface{}) error {
+	data, ok := h.(*notification.Incident)
+	if !ok {
+		return errors.New("can't convert unknown type to APIIncident")
+	}
+
+	i.ID = ToAPIString(data.ID)
+	i.SubscriptionID = ToAPIString(data.SubscriptionID)
+	i.Source = ToAPIString(data.Source)
+	i.ExternalID = ToAPIString(data.ExternalID)
+	i.Title = ToAPIString(data.Title)
+	i.CreatedAt = NewTime(data.CreatedAt)
+	i.ResolvedAt = NewTime(data.ResolvedAt)
+
+	return nil
+}
+
+func (i *APIIncident) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIIncident) ToService not implemented")
+}
+
+// APIEscalation is a notification that escalates to further tiers of
+// subscribers until it is acknowledged.
+type APIEscalation struct {
+	ID              APIString   `json:"id"`
+	SubscriptionID  APIString   `json:"subscription_id"`
+	Tiers           []APIString `json:"tiers"`
+	NextTier        int         `json:"next_tier"`
+	EscalateAt      APITime     `json:"escalate_at"`
+	NotificationIDs []APIString `json:"notification_ids"`
+	AcknowledgedAt  APITime     `json:"acknowledged_at"`
+	AcknowledgedBy  APIString   `json:"acknowledged_by"`
+	CreatedAt       APITime     `json:"created_at"`
+}
+
+func (e *APIEscalation) BuildFromService(h interface{}) error {
+	data, ok := h.(*notification.Escalation)
+	if !ok {
+		return errors.New("can't convert unknown type to APIEscalation")
+	}
+
+	e.ID = ToAPIString(data.ID)
+	e.SubscriptionID = ToAPIString(data.SubscriptionID)
+	e.Tiers = make([]APIString, 0, len(data.Tiers))
+	for _, tier := range data.Tiers {
+		e.Tiers = append(e.Tiers, ToAPIString(tier.Name))
+	}
+	e.NextTier = data.NextTier
+	e.EscalateAt = NewTime(data.EscalateAt)
+	e.NotificationIDs = make([]APIString, 0, len(data.NotificationIDs))
+	for _, id := range data.NotificationIDs {
+		e.NotificationIDs = append(e.NotificationIDs, ToAPIString(id))
+	}
+	e.AcknowledgedAt = NewTime(data.AcknowledgedAt)
+	e.AcknowledgedBy = ToAPIString(data.AcknowledgedBy)
+	e.CreatedAt = NewTime(data.CreatedAt)
+
+	return nil
+}
+
+func (e *APIEscalation) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIEscalation) ToService not implemented")
+}
+
+// APIIncidentLink links a notification to an existing incident, or, if no
+// incident ID is given, to a new incident with the given source.
+type APIIncidentLink struct {
+	IncidentID APIString `json:"incident_id"`
+	Source     APIString `json:"source"`
+	ExternalID APIString `json:"external_id"`
+	Title      APIString `json:"title"`
+}
+
+// Validate returns an error describing each invalid field of the link. A link
+// either names an existing incident, or describes a new one.
+func (l *APIIncidentLink) Validate() error {
+	catcher := grip.NewBasicCatcher()
+
+	if FromAPIString(l.IncidentID) != "" {
+		if FromAPIString(l.Source) != "" || FromAPIString(l.ExternalID) != "" || FromAPIString(l.Title) != "" {
+			catcher.Add(errors.New("incident_id: cannot be combined with source, external_id, or title"))
+		}
+		return catcher.Resolve()
+	}
+
+	if source := FromAPIString(l.Source); !util.StringSliceContains(notification.IncidentSources, source) {
+		catcher.Add(errors.Errorf("source: '%s' is not one of %s", source, strings.Join(notification.IncidentSources, ", ")))
+	}
+
+	return catcher.Resolve()
+}
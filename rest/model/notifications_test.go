@@ -1,11 +1,18 @@
 package model
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/message"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -57,3 +64,370 @@ func TestNotificationStats(t *testing.T) {
 	assert.EqualError(err, "(*apiNotificationsStats) ToService not implemented")
 	assert.Implements((*Model)(nil), &stats)
 }
+
+func TestAPINotification(t *testing.T) {
+	assert := assert.New(t)
+
+	n := notification.Notification{
+		ID: "n",
+		Subscriber: event.Subscriber{
+			Type: event.SlackSubscriberType,
+		},
+		SubscriptionID: "sub",
+		IncidentID:     "incident",
+	}
+
+	apiNotification := APINotification{}
+	assert.EqualError(apiNotification.BuildFromService(n), "can't convert unknown type to APINotification")
+	assert.NoError(apiNotification.BuildFromService(&n))
+	assert.Equal("n", FromAPIString(apiNotification.ID))
+	assert.Equal(event.SlackSubscriberType, FromAPIString(apiNotification.SubscriberType))
+	assert.Equal("sub", FromAPIString(apiNotification.SubscriptionID))
+	assert.Equal("incident", FromAPIString(apiNotification.IncidentID))
+}
+
+func TestAPIIncident(t *testing.T) {
+	assert := assert.New(t)
+
+	incident := notification.Incident{
+		ID:             "incident",
+		SubscriptionID: "sub",
+		Source:         notification.IncidentSourcePagerDuty,
+		ExternalID:     "PD123",
+		CreatedAt:      time.Now(),
+	}
+
+	apiIncident := APIIncident{}
+	assert.NoError(apiIncident.BuildFromService(&incident))
+	assert.Equal("incident", FromAPIString(apiIncident.ID))
+	assert.Equal("sub", FromAPIString(apiIncident.SubscriptionID))
+	assert.Equal(notification.IncidentSourcePagerDuty, FromAPIString(apiIncident.Source))
+	assert.Equal("PD123", FromAPIString(apiIncident.ExternalID))
+
+	x, err := apiIncident.ToService()
+	assert.Nil(x)
+	assert.EqualError(err, "(*APIIncident) ToService not implemented")
+}
+
+func TestNotificationDeliveryStatus(t *testing.T) {
+	assert := assert.New(t)
+
+	created := time.Now().Add(-time.Minute)
+	sent := time.Now()
+	for status, n := range map[string]notification.Notification{
+		notification.StatusQueued:   {ID: "1", CreatedAt: created},
+		notification.StatusRetrying: {ID: "1", CreatedAt: created, Attempts: 1, Error: "slack is down"},
+		notification.StatusSent:     {ID: "1", CreatedAt: created, SentAt: sent},
+		notification.StatusFailed:   {ID: "1", CreatedAt: created, SentAt: sent, Attempts: 4, Error: "slack is down", DeadLettered: true},
+	} {
+		apiNotification := APINotification{}
+		assert.NoError(apiNotification.BuildFromService(&n))
+		assert.Equal(status, FromAPIString(apiNotification.Status))
+		assert.Equal(n.Error, FromAPIString(apiNotification.Error))
+		assert.Equal(n.Attempts, apiNotification.Attempts)
+		assert.Equal(n.DeadLettered, apiNotification.DeadLettered)
+		assert.True(created.Equal(time.Time(apiNotification.CreatedAt)))
+		assert.True(n.SentAt.Equal(time.Time(apiNotification.SentAt)))
+		assert.Nil(apiNotification.SlackTS)
+	}
+
+	n := notification.Notification{ID: "1", SlackMessage: &util.SlackMessageRef{Channel: "C123", TS: "1.1"}}
+	apiNotification := APINotification{}
+	assert.NoError(apiNotification.BuildFromService(&n))
+	assert.Equal("C123", FromAPIString(apiNotification.SlackChannel))
+	assert.Equal("1.1", FromAPIString(apiNotification.SlackTS))
+}
+
+func TestAPIEmail(t *testing.T) {
+	assert := assert.New(t)
+
+	email := APIEmail{
+		Recipients: []APIString{ToAPIString("a@example.com"), ToAPIString("b@example.com")},
+		Subject:    ToAPIString("task failed"),
+		HTMLBody:   ToAPIString("<p>task failed</p>"),
+		Attachments: []APIAttachment{
+			{Filename: ToAPIString("task.log"), ContentType: ToAPIString("text/plain"), Content: ToAPIString(base64.StdEncoding.EncodeToString([]byte("log")))},
+			{Filename: ToAPIString("full.log"), S3URL: ToAPIString("s3://logs/task/full.log")},
+		},
+	}
+	i, err := email.ToService()
+	assert.NoError(err)
+	out, ok := i.(*util.EvergreenEmail)
+	assert.True(ok)
+	assert.Equal([]string{"a@example.com", "b@example.com"}, out.Recipients)
+	assert.Equal("task failed", out.Subject)
+	assert.Equal("<p>task failed</p>", out.HTMLBody)
+	assert.Equal([]util.Attachment{
+		{Filename: "task.log", ContentType: "text/plain", Data: []byte("log")},
+		{Filename: "full.log", S3URL: "s3://logs/task/full.log"},
+	}, out.Attachments)
+
+	email = APIEmail{
+		From:       ToAPIString("not an address"),
+		Recipients: []APIString{ToAPIString("a@example.com")},
+		Subject:    ToAPIString("task failed"),
+		Attachments: []APIAttachment{
+			{Filename: ToAPIString("a"), Content: ToAPIString("bG9n"), S3URL: ToAPIString("s3://logs/a")},
+			{Filename: ToAPIString("b"), S3URL: ToAPIString("https://example.com/b")},
+			{ContentType: ToAPIString("text/"), Content: ToAPIString("bG9n")},
+		},
+	}
+	_, err = email.ToService()
+	assert.Error(err)
+	assert.Contains(err.Error(), "from: 'not an address' is not a valid address")
+	assert.Contains(err.Error(), "body: body and html_body cannot both be empty")
+	assert.Contains(err.Error(), "attachments[0]: content and s3_url cannot both be set")
+	assert.Contains(err.Error(), "attachments[1]: 'https://example.com/b' is not an s3://bucket/key URL")
+	assert.Contains(err.Error(), "attachments[2]: filename cannot be empty")
+	assert.Contains(err.Error(), "attachments[2]: 'text/' is not a valid content type")
+}
+
+func TestAPISlack(t *testing.T) {
+	assert := assert.New(t)
+
+	slack := APISlack{
+		Target: ToAPIString("#evergreen"),
+		Msg:    ToAPIString("task failed"),
+		Attachments: []APISlackAttachment{
+			{
+				Fallback: ToAPIString("task failed"),
+				Title:    ToAPIString("Task"),
+				Fields:   []APISlackAttachmentField{{Title: ToAPIString("Variant"), Value: ToAPIString("ubuntu"), Short: true}},
+			},
+		},
+		Blocks: []APISlackBlock{
+			{Type: ToAPIString("section"), Text: &APISlackText{Type: ToAPIString("mrkdwn"), Text: ToAPIString("*task failed*")}},
+			{Type: ToAPIString("context"), Elements: []APISlackBlockElement{{Type: ToAPIString("plain_text"), Text: ToAPIString("ubuntu")}}},
+			{Type: ToAPIString("divider")},
+			{Type: ToAPIString("actions"), Elements: []APISlackBlockElement{
+				{Type: ToAPIString("button"), Text: ToAPIString("Restart"), ActionID: ToAPIString("restart"), Style: ToAPIString("primary")},
+			}},
+		},
+	}
+	i, err := slack.ToService()
+	assert.NoError(err)
+	out, ok := i.(*util.EvergreenSlack)
+	assert.True(ok)
+	assert.Equal("#evergreen", out.Target)
+	assert.Equal("task failed", out.Msg)
+	assert.Equal([]message.SlackAttachment{{
+		Fallback: "task failed",
+		Title:    "Task",
+		Fields:   []*message.SlackAttachmentField{{Title: "Variant", Value: "ubuntu", Short: true}},
+	}}, out.Attachments)
+	assert.Equal([]util.SlackBlock{
+		{Type: "section", Text: &util.SlackText{Type: "mrkdwn", Text: "*task failed*"}},
+		{Type: "context", Elements: []util.SlackBlockElement{{Type: "plain_text", Text: "ubuntu"}}},
+		{Type: "divider"},
+		{Type: "actions", Elements: []util.SlackBlockElement{{Type: "button", Text: "Restart", ActionID: "restart", Style: "primary"}}},
+	}, out.Blocks)
+
+	apiBlock := APISlackBlock{}
+	assert.NoError(apiBlock.BuildFromService(&out.Blocks[3]))
+	i, err = apiBlock.ToService()
+	assert.NoError(err)
+	assert.Equal(&out.Blocks[3], i)
+	apiAttachment := APISlackAttachment{}
+	assert.NoError(apiAttachment.BuildFromService(&out.Attachments[0]))
+	i, err = apiAttachment.ToService()
+	assert.NoError(err)
+	assert.Equal(&out.Attachments[0], i)
+
+	slack = APISlack{
+		Attachments: []APISlackAttachment{{Text: ToAPIString("no fallback")}},
+		Blocks:      []APISlackBlock{{Type: ToAPIString("divider"), Text: &APISlackText{Type: ToAPIString("mrkdwn"), Text: ToAPIString("text")}}},
+	}
+	_, err = slack.ToService()
+	assert.Error(err)
+	assert.Contains(err.Error(), "target: cannot be empty")
+	assert.Contains(err.Error(), "attachments[0]: fallback cannot be empty")
+	assert.Contains(err.Error(), "blocks[0]: dividers cannot have content")
+
+	slack = APISlack{Target: ToAPIString("@me")}
+	assert.EqualError(slack.Validate(), "msg: msg and blocks cannot both be empty")
+
+	// replies and updates
+	slack = APISlack{Target: ToAPIString("#evergreen"), Msg: ToAPIString("fixed"), ThreadTS: ToAPIString("1500000000.000100")}
+	i, err = slack.ToService()
+	assert.NoError(err)
+	assert.Equal("1500000000.000100", i.(*util.EvergreenSlack).ThreadTS)
+	slack = APISlack{Target: ToAPIString("C123"), Msg: ToAPIString("fixed"), UpdateTS: ToAPIString("1500000000.000100")}
+	i, err = slack.ToService()
+	assert.NoError(err)
+	assert.Equal("1500000000.000100", i.(*util.EvergreenSlack).UpdateTS)
+	slack = APISlack{Target: ToAPIString("#evergreen"), Msg: ToAPIString("failed"), ThreadKey: ToAPIString("version1")}
+	assert.NoError(slack.Validate())
+
+	slack = APISlack{
+		Target:    ToAPIString("#evergreen"),
+		Msg:       ToAPIString("fixed"),
+		ThreadKey: ToAPIString("version1"),
+		ThreadTS:  ToAPIString("yesterday"),
+		UpdateTS:  ToAPIString("1500000000.000100"),
+	}
+	err = slack.Validate()
+	assert.Error(err)
+	assert.Contains(err.Error(), "target: '#evergreen' is not a channel ID, which is required to update a message")
+	assert.Contains(err.Error(), "thread_ts: 'yesterday' is not a slack message timestamp")
+	assert.Contains(err.Error(), "thread_ts: cannot be combined with update_ts")
+	assert.Contains(err.Error(), "thread_key: cannot be combined with thread_ts or update_ts")
+}
+
+func TestAPIOpsgenieAlert(t *testing.T) {
+	assert := assert.New(t)
+
+	alert := APIOpsgenieAlert{
+		APIKey:  ToAPIString("key"),
+		Team:    ToAPIString("evergreen"),
+		Message: ToAPIString("task failed"),
+		Alias:   ToAPIString("task1"),
+		Tags:    []string{"evergreen"},
+		Details: map[string]string{"project": "mci"},
+	}
+	assert.NoError(alert.Validate())
+	out, err := alert.ToService()
+	assert.NoError(err)
+	assert.Equal(&util.OpsgenieAlert{
+		APIKey:   "key",
+		Team:     "evergreen",
+		Message:  "task failed",
+		Alias:    "task1",
+		Priority: util.OpsgeniePriorityModerate,
+		Tags:     []string{"evergreen"},
+		Details:  map[string]string{"project": "mci"},
+		Source:   "evergreen",
+	}, out)
+
+	alert.Priority = ToAPIString(util.OpsgeniePriorityCritical)
+	out, err = alert.ToService()
+	assert.NoError(err)
+	assert.Equal(util.OpsgeniePriorityCritical, out.(*util.OpsgenieAlert).Priority)
+
+	alert = APIOpsgenieAlert{
+		Message:  ToAPIString(strings.Repeat("a", util.OpsgenieMaxMessageLength+1)),
+		Alias:    ToAPIString(strings.Repeat("a", util.OpsgenieMaxAliasLength+1)),
+		Priority: ToAPIString("P0"),
+		Tags:     make([]string, util.OpsgenieMaxTags+1),
+	}
+	err = alert.Validate()
+	assert.Error(err)
+	assert.Contains(err.Error(), "api_key: cannot be empty")
+	assert.Contains(err.Error(), "message: cannot be longer than 130 characters")
+	assert.Contains(err.Error(), "alias: cannot be longer than 512 characters")
+	assert.Contains(err.Error(), "priority: 'P0' is not one of P1, P2, P3, P4, P5")
+	assert.Contains(err.Error(), "tags: cannot have more than 20 tags")
+	_, err = alert.ToService()
+	assert.Error(err)
+}
+
+func TestAPISlackInteraction(t *testing.T) {
+	assert := assert.New(t)
+
+	interaction := APISlackInteraction{}
+	assert.NoError(json.Unmarshal([]byte(`{
+		"type": "block_actions",
+		"user": {"id": "U123", "username": "me"},
+		"actions": [{"action_id": "restart_task", "block_id": "b1", "value": "task1"}],
+		"response_url": "https://hooks.slack.com/actions/T1/1/abc"
+	}`), &interaction))
+	assert.NoError(interaction.Validate())
+	assert.Equal("me", FromAPIString(interaction.User.Username))
+	assert.Equal(SlackActionRestartTask, FromAPIString(interaction.Actions[0].ActionID))
+
+	interaction = APISlackInteraction{
+		Type: ToAPIString("view_submission"),
+		Actions: []APISlackInteractionAction{
+			{ActionID: ToAPIString("deploy")},
+		},
+		ResponseURL: ToAPIString("https://example.com"),
+	}
+	err := interaction.Validate()
+	assert.Error(err)
+	assert.Contains(err.Error(), "type: 'view_submission' interactions are not supported")
+	assert.Contains(err.Error(), "user: id cannot be empty")
+	assert.Contains(err.Error(), "actions[0]: 'deploy' is not a supported action")
+	assert.Contains(err.Error(), "actions[0]: value cannot be empty")
+	assert.Contains(err.Error(), "response_url: 'https://example.com' is not a slack URL")
+
+	interaction.Actions = nil
+	assert.Contains(interaction.Validate().Error(), "actions: cannot be empty")
+}
+
+func TestAPIJiraIssueTransition(t *testing.T) {
+	assert := assert.New(t)
+
+	transition := APIJiraIssueTransition{}
+	err := transition.Validate()
+	assert.Error(err)
+	assert.Contains(err.Error(), "transition: cannot be empty")
+
+	transitions := []thirdparty.JiraTransition{
+		{Id: "2", Name: "Close Issue", To: &thirdparty.JiraStatus{Name: "Closed"}},
+		{Id: "3", Name: "Reopen Issue", To: &thirdparty.JiraStatus{Name: "Reopened"}},
+	}
+	for name, id := range map[string]string{"3": "3", "close issue": "2", "Reopened": "3"} {
+		transition.Transition = ToAPIString(name)
+		assert.NoError(transition.Validate())
+		found, err := transition.FindTransition(transitions)
+		assert.NoError(err)
+		assert.Equal(id, found.Id, name)
+	}
+
+	transition.Transition = ToAPIString("Resolve Issue")
+	_, err = transition.FindTransition(transitions)
+	assert.EqualError(err, "transition: 'Resolve Issue' is not one of the available transitions 'Close Issue', 'Reopen Issue'")
+	_, err = transition.FindTransition(nil)
+	assert.EqualError(err, "transition: 'Resolve Issue' is not available, since the issue has no transitions")
+
+	issue := APIJiraIssue{}
+	assert.NoError(issue.BuildFromService(&thirdparty.JiraTicket{
+		Key: "BF-1",
+		Fields: &thirdparty.TicketFields{
+			Summary:    "test failed",
+			Status:     &thirdparty.JiraStatus{Name: "Closed"},
+			Resolution: &thirdparty.JiraResolution{Name: "Fixed"},
+		},
+	}))
+	assert.Equal("BF-1", FromAPIString(issue.Key))
+	assert.Equal("test failed", FromAPIString(issue.Summary))
+	assert.Equal("Closed", FromAPIString(issue.Status))
+	assert.Equal("Fixed", FromAPIString(issue.Resolution))
+}
+
+func TestAPIJiraIssueAttachments(t *testing.T) {
+	assert := assert.New(t)
+
+	req := APIJiraIssueAttachments{}
+	_, err := req.ToService()
+	assert.EqualError(err, "attachments: cannot be empty")
+
+	req.Attachments = []APIAttachment{
+		{Filename: ToAPIString("task.log"), ContentType: ToAPIString("text/plain"), Content: ToAPIString(base64.StdEncoding.EncodeToString([]byte("log")))},
+		{Filename: ToAPIString("core.tgz"), S3URL: ToAPIString("s3://artifacts/core.tgz")},
+	}
+	i, err := req.ToService()
+	assert.NoError(err)
+	assert.Equal([]util.Attachment{
+		{Filename: "task.log", ContentType: "text/plain", Data: []byte("log")},
+		{Filename: "core.tgz", S3URL: "s3://artifacts/core.tgz"},
+	}, i)
+
+	req.Attachments = append(req.Attachments, APIAttachment{Filename: ToAPIString("big.log"),
+		Content: ToAPIString(base64.StdEncoding.EncodeToString(make([]byte, util.AttachmentMaxSize)))})
+	_, err = req.ToService()
+	assert.Error(err)
+	assert.Contains(err.Error(), "attachments: attachments cannot exceed")
+
+	attachment := APIJiraAttachment{}
+	assert.NoError(attachment.BuildFromService(&thirdparty.JiraAttachment{
+		Id:       "10",
+		Filename: "task.log",
+		MimeType: "text/plain",
+		Size:     3,
+		Content:  "https://jira.example.com/secure/attachment/10/task.log",
+	}))
+	assert.Equal("10", FromAPIString(attachment.ID))
+	assert.Equal("text/plain", FromAPIString(attachment.MimeType))
+	assert.Equal(3, attachment.Size)
+	assert.Equal("https://jira.example.com/secure/attachment/10/task.log", FromAPIString(attachment.URL))
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// OpenAPISchema is the schema of a JSON value in an OpenAPI document.
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPISchemas are the schemas of the REST models in an OpenAPI document,
+// keyed by model name. Each model is described once, and referenced by the
+// schemas that use it.
+type OpenAPISchemas map[string]*OpenAPISchema
+
+const openAPISchemaRefPrefix = "#/components/schemas/"
+
+var (
+	apiStringType     = reflect.TypeOf(APIString(nil))
+	apiTimeType       = reflect.TypeOf(APITime{})
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// SchemaFor returns the schema of the JSON encoding of the value, adding the
+// schemas of the models it contains.
+func (s OpenAPISchemas) SchemaFor(v interface{}) *OpenAPISchema {
+	if v == nil {
+		return &OpenAPISchema{}
+	}
+	return s.schemaForType(reflect.TypeOf(v))
+}
+
+func (s OpenAPISchemas) schemaForType(t reflect.Type) *OpenAPISchema {
+	switch t {
+	case apiStringType:
+		return &OpenAPISchema{Type: "string", Nullable: true}
+	case apiTimeType, timeType:
+		return &OpenAPISchema{Type: "string", Format: "date-time", Nullable: true}
+	}
+	// types with their own encoding can't be described by their fields
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return &OpenAPISchema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return s.schemaForType(t.Elem())
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &OpenAPISchema{Type: "number"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+		return &OpenAPISchema{Type: "array", Items: s.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: s.schemaForType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.structSchema(t)
+		}
+		ref := &OpenAPISchema{Ref: openAPISchemaRefPrefix + t.Name()}
+		if _, ok := s[t.Name()]; ok {
+			return ref
+		}
+		// register the model before describing its fields, so that models
+		// that refer to themselves terminate
+		s[t.Name()] = &OpenAPISchema{}
+		s[t.Name()] = s.structSchema(t)
+		return ref
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+func (s OpenAPISchemas) structSchema(t reflect.Type) *OpenAPISchema {
+	schema := &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		// the fields of embedded structs are encoded as fields of the
+		// struct embedding them
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for k, v := range s.structSchema(embedded).Properties {
+					schema.Properties[k] = v
+				}
+				continue
+			}
+			if field.PkgPath != "" {
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = s.schemaForType(field.Type)
+	}
+
+	return schema
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPITestEmbedded struct {
+	Embedded APIString `json:"embedded"`
+}
+
+type openAPITestModel struct {
+	openAPITestEmbedded
+	Name     APIString                    `json:"name"`
+	When     APITime                      `json:"when"`
+	Count    int64                        `json:"count"`
+	Tags     []string                     `json:"tags"`
+	Data     []byte                       `json:"data"`
+	Extra    map[string]interface{}       `json:"extra"`
+	Children []openAPITestModel           `json:"children"`
+	ByName   map[string]*openAPITestModel `json:"by_name"`
+	Ignored  string                       `json:"-"`
+	hidden   string
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	schemas := OpenAPISchemas{}
+	schema := schemas.SchemaFor([]openAPITestModel{})
+	assert.Equal("array", schema.Type)
+	assert.Equal("#/components/schemas/openAPITestModel", schema.Items.Ref)
+
+	m, ok := schemas["openAPITestModel"]
+	require.True(ok)
+	assert.Len(m.Properties, 9)
+	assert.Equal("string", m.Properties["embedded"].Type)
+	assert.True(m.Properties["name"].Nullable)
+	assert.Equal("date-time", m.Properties["when"].Format)
+	assert.Equal("int64", m.Properties["count"].Format)
+	assert.Equal("string", m.Properties["tags"].Items.Type)
+	assert.Equal("byte", m.Properties["data"].Format)
+	assert.Equal("object", m.Properties["extra"].Type)
+	assert.Equal("#/components/schemas/openAPITestModel", m.Properties["children"].Items.Ref)
+	assert.Equal("#/components/schemas/openAPITestModel", m.Properties["by_name"].AdditionalProperties.Ref)
+	assert.NotContains(m.Properties, "Ignored")
+	assert.NotContains(m.Properties, "hidden")
+}
@@ -7,24 +7,26 @@ import (
 
 // APIPatch is the model to be returned by the API whenever patches are fetched.
 type APIPatch struct {
-	Id              APIString     `json:"patch_id"`
-	Description     APIString     `json:"description"`
-	ProjectId       APIString     `json:"project_id"`
-	Branch          APIString     `json:"branch"`
-	Githash         APIString     `json:"git_hash"`
-	PatchNumber     int           `json:"patch_number"`
-	Author          APIString     `json:"author"`
-	Version         APIString     `json:"version"`
-	Status          APIString     `json:"status"`
-	CreateTime      APITime       `json:"create_time"`
-	StartTime       APITime       `json:"start_time"`
-	FinishTime      APITime       `json:"finish_time"`
-	Variants        []APIString   `json:"builds"`
-	Tasks           []APIString   `json:"tasks"`
-	VariantsTasks   []variantTask `json:"variants_tasks"`
-	Activated       bool          `json:"activated"`
-	Alias           APIString     `json:"alias,omitempty"`
-	GithubPatchData githubPatch   `json:"github_patch_data,omitempty"`
+	Id              APIString      `json:"patch_id"`
+	Description     APIString      `json:"description"`
+	ProjectId       APIString      `json:"project_id"`
+	Branch          APIString      `json:"branch"`
+	Githash         APIString      `json:"git_hash"`
+	PatchNumber     int            `json:"patch_number"`
+	Author          APIString      `json:"author"`
+	Version         APIString      `json:"version"`
+	Status          APIString      `json:"status"`
+	CreateTime      APITime        `json:"create_time"`
+	StartTime       APITime        `json:"start_time"`
+	FinishTime      APITime        `json:"finish_time"`
+	Variants        []APIString    `json:"builds"`
+	Tasks           []APIString    `json:"tasks"`
+	VariantsTasks   []variantTask  `json:"variants_tasks"`
+	Activated       bool           `json:"activated"`
+	Pinned          bool           `json:"pinned"`
+	Alias           APIString      `json:"alias,omitempty"`
+	Parameters      []APIParameter `json:"parameters"`
+	GithubPatchData githubPatch    `json:"github_patch_data,omitempty"`
 }
 type variantTask struct {
 	Name  APIString   `json:"name"`
@@ -72,7 +74,9 @@ func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	}
 	apiPatch.VariantsTasks = variantTasks
 	apiPatch.Activated = v.Activated
+	apiPatch.Pinned = v.Pinned
 	apiPatch.Alias = ToAPIString(v.Alias)
+	apiPatch.Parameters = buildAPIParameters(v.Parameters)
 	apiPatch.GithubPatchData = githubPatch{}
 	return errors.WithStack(apiPatch.GithubPatchData.BuildFromService(v.GithubPatchData))
 }
@@ -8,20 +8,29 @@ import (
 )
 
 type APIProject struct {
-	BatchTime          int         `json:"batch_time"`
-	Branch             APIString   `json:"branch_name"`
-	DisplayName        APIString   `json:"display_name"`
-	Enabled            bool        `json:"enabled"`
-	Identifier         APIString   `json:"identifier"`
-	Owner              APIString   `json:"owner_name"`
-	Private            bool        `json:"private"`
-	RemotePath         APIString   `json:"remote_path"`
-	Repo               APIString   `json:"repo_name"`
-	Tracked            bool        `json:"tracked"`
-	DeactivatePrevious bool        `json:"deactivate_previous"`
-	Admins             []APIString `json:"admins"`
-	TracksPushEvents   bool        `json:"tracks_push_events"`
-	PRTestingEnabled   bool        `json:"pr_testing_enabled"`
+	BatchTime                 int         `json:"batch_time"`
+	Branch                    APIString   `json:"branch_name"`
+	DisplayName               APIString   `json:"display_name"`
+	Enabled                   bool        `json:"enabled"`
+	Identifier                APIString   `json:"identifier"`
+	Owner                     APIString   `json:"owner_name"`
+	Private                   bool        `json:"private"`
+	RemotePath                APIString   `json:"remote_path"`
+	Repo                      APIString   `json:"repo_name"`
+	Tracked                   bool        `json:"tracked"`
+	DeactivatePrevious        bool        `json:"deactivate_previous"`
+	Admins                    []APIString `json:"admins"`
+	Contributors              []APIString `json:"contributors"`
+	Viewers                   []APIString `json:"viewers"`
+	TracksPushEvents          bool        `json:"tracks_push_events"`
+	PRTestingEnabled          bool        `json:"pr_testing_enabled"`
+	GithubChecksEnabled       bool        `json:"github_checks_enabled"`
+	CommitQueueEnabled        bool        `json:"commit_queue_enabled"`
+	SuppressInheritedWarnings bool        `json:"suppress_inherited_warnings"`
+	Priority                  int64       `json:"priority"`
+	StepbackBisect            bool        `json:"stepback_bisect"`
+	TestSelectionEnabled      bool        `json:"test_selection_enabled"`
+	FullRunIntervalHours      int         `json:"full_run_interval_hours"`
 }
 
 func (apiProject *APIProject) BuildFromService(p interface{}) error {
@@ -41,13 +50,30 @@ func (apiProject *APIProject) BuildFromService(p interface{}) error {
 	apiProject.Tracked = v.Tracked
 	apiProject.TracksPushEvents = v.TracksPushEvents
 	apiProject.PRTestingEnabled = v.PRTestingEnabled
+	apiProject.GithubChecksEnabled = v.GithubChecksEnabled
+	apiProject.CommitQueueEnabled = v.CommitQueue.Enabled
 	apiProject.DeactivatePrevious = v.DeactivatePrevious
+	apiProject.SuppressInheritedWarnings = v.SuppressInheritedWarnings
+	apiProject.Priority = v.Priority
+	apiProject.StepbackBisect = v.StepbackBisect
+	apiProject.TestSelectionEnabled = v.TestSelectionEnabled
+	apiProject.FullRunIntervalHours = v.FullRunIntervalHours
 
 	admins := []APIString{}
 	for _, a := range v.Admins {
 		admins = append(admins, ToAPIString(a))
 	}
 	apiProject.Admins = admins
+	contributors := []APIString{}
+	for _, c := range v.Contributors {
+		contributors = append(contributors, ToAPIString(c))
+	}
+	apiProject.Contributors = contributors
+	viewers := []APIString{}
+	for _, viewer := range v.Viewers {
+		viewers = append(viewers, ToAPIString(viewer))
+	}
+	apiProject.Viewers = viewers
 
 	return nil
 }
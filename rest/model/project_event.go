@@ -0,0 +1,90 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/pkg/errors"
+)
+
+// APIProjectSettings is a snapshot of a project's settings. The values of
+// private variables are empty.
+type APIProjectSettings struct {
+	ProjectRef    APIProject                       `json:"proj_ref"`
+	Vars          map[string]string                `json:"vars"`
+	PrivateVars   map[string]bool                  `json:"private_vars"`
+	SecretVars    map[string]model.SecretReference `json:"secret_vars"`
+	Aliases       []APIAlias                       `json:"aliases"`
+	Subscriptions []APISubscription                `json:"subscriptions"`
+}
+
+func (s *APIProjectSettings) BuildFromService(h interface{}) error {
+	v, ok := h.(model.ProjectSettings)
+	if !ok {
+		return errors.Errorf("%T is not project settings", h)
+	}
+
+	if err := s.ProjectRef.BuildFromService(v.ProjectRef); err != nil {
+		return errors.Wrap(err, "problem building project")
+	}
+	s.Vars = v.Vars.Vars
+	s.PrivateVars = v.Vars.PrivateVars
+	s.SecretVars = v.Vars.SecretVars
+	s.Aliases = []APIAlias{}
+	for _, alias := range v.Aliases {
+		apiAlias := APIAlias{}
+		if err := apiAlias.BuildFromService(alias); err != nil {
+			return errors.Wrap(err, "problem building alias")
+		}
+		s.Aliases = append(s.Aliases, apiAlias)
+	}
+	s.Subscriptions = []APISubscription{}
+	for _, sub := range v.Subscriptions {
+		apiSub := APISubscription{}
+		if err := apiSub.BuildFromService(sub); err != nil {
+			return errors.Wrap(err, "problem building subscription")
+		}
+		s.Subscriptions = append(s.Subscriptions, apiSub)
+	}
+
+	return nil
+}
+
+func (s *APIProjectSettings) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APIProjectSettings")
+}
+
+// APIProjectEvent is a user's change to the settings of a project.
+type APIProjectEvent struct {
+	Timestamp APITime            `json:"ts"`
+	EventType APIString          `json:"event_type"`
+	User      APIString          `json:"user"`
+	Before    APIProjectSettings `json:"before"`
+	After     APIProjectSettings `json:"after"`
+}
+
+func (e *APIProjectEvent) BuildFromService(h interface{}) error {
+	v, ok := h.(event.EventLogEntry)
+	if !ok {
+		return errors.Errorf("%T is not the correct event type", h)
+	}
+	data, ok := v.Data.(*model.ProjectChangeEvent)
+	if !ok {
+		return errors.New("unable to convert event type to project event")
+	}
+
+	e.Timestamp = NewTime(v.Timestamp)
+	e.EventType = ToAPIString(v.EventType)
+	e.User = ToAPIString(data.User)
+	if err := e.Before.BuildFromService(data.Before); err != nil {
+		return errors.Wrap(err, "unable to convert 'before' settings")
+	}
+	if err := e.After.BuildFromService(data.After); err != nil {
+		return errors.Wrap(err, "unable to convert 'after' settings")
+	}
+
+	return nil
+}
+
+func (e *APIProjectEvent) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APIProjectEvent")
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen/model/projectinsights"
+	"github.com/pkg/errors"
+)
+
+// APIProjectInsights summarizes the reliability and duration of a project's
+// mainline builds over a window of days.
+type APIProjectInsights struct {
+	Project            APIString                  `json:"project"`
+	WindowDays         int                        `json:"window_days"`
+	Builds             int                        `json:"builds"`
+	SucceededBuilds    int                        `json:"succeeded_builds"`
+	SuccessRate        float64                    `json:"success_rate"`
+	Breaks             int                        `json:"breaks"`
+	OpenBreaks         int                        `json:"open_breaks"`
+	MeanTimeToDetectMS APIDuration                `json:"mean_time_to_detect_ms"`
+	MeanTimeToRepairMS APIDuration                `json:"mean_time_to_repair_ms"`
+	DurationP50MS      APIDuration                `json:"duration_p50_ms"`
+	DurationP90MS      APIDuration                `json:"duration_p90_ms"`
+	DurationP99MS      APIDuration                `json:"duration_p99_ms"`
+	Periods            []APIProjectInsightsPeriod `json:"periods"`
+	LastUpdate         APITime                    `json:"last_update"`
+}
+
+// APIProjectInsightsPeriod summarizes the builds created in a day or week of
+// a window.
+type APIProjectInsightsPeriod struct {
+	Start         APITime     `json:"start"`
+	Builds        int         `json:"builds"`
+	SuccessRate   float64     `json:"success_rate"`
+	DurationP50MS APIDuration `json:"duration_p50_ms"`
+	DurationP90MS APIDuration `json:"duration_p90_ms"`
+}
+
+func (ai *APIProjectInsights) BuildFromService(h interface{}) error {
+	var insights *projectinsights.ProjectInsights
+	switch v := h.(type) {
+	case projectinsights.ProjectInsights:
+		insights = &v
+	case *projectinsights.ProjectInsights:
+		insights = v
+	default:
+		return fmt.Errorf("incorrect type %T when creating APIProjectInsights", h)
+	}
+
+	ai.Project = ToAPIString(insights.Id.Project)
+	ai.WindowDays = insights.Id.WindowDays
+	ai.Builds = insights.Builds
+	ai.SucceededBuilds = insights.SucceededBuilds
+	ai.SuccessRate = insights.SuccessRate
+	ai.Breaks = insights.Breaks
+	ai.OpenBreaks = insights.OpenBreaks
+	ai.MeanTimeToDetectMS = NewAPIDuration(insights.MeanTimeToDetect)
+	ai.MeanTimeToRepairMS = NewAPIDuration(insights.MeanTimeToRepair)
+	ai.DurationP50MS = NewAPIDuration(insights.DurationP50)
+	ai.DurationP90MS = NewAPIDuration(insights.DurationP90)
+	ai.DurationP99MS = NewAPIDuration(insights.DurationP99)
+	ai.Periods = make([]APIProjectInsightsPeriod, 0, len(insights.Periods))
+	for _, p := range insights.Periods {
+		ai.Periods = append(ai.Periods, APIProjectInsightsPeriod{
+			Start:         NewTime(p.Start),
+			Builds:        p.Builds,
+			SuccessRate:   p.SuccessRate,
+			DurationP50MS: NewAPIDuration(p.DurationP50),
+			DurationP90MS: NewAPIDuration(p.DurationP90),
+		})
+	}
+	ai.LastUpdate = NewTime(insights.LastUpdate)
+	return nil
+}
+
+// ToService is not implemented for APIProjectInsights.
+func (ai *APIProjectInsights) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APIProjectInsights")
+}
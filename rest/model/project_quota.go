@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/quota"
+	"github.com/pkg/errors"
+)
+
+// APIProjectQuota is a project's weight and limit on the distros it shares
+// with other projects.
+type APIProjectQuota struct {
+	Project         APIString `json:"project"`
+	Weight          float64   `json:"weight"`
+	MaxRunningTasks int       `json:"max_running_tasks"`
+	UpdatedBy       APIString `json:"updated_by"`
+	UpdatedAt       APITime   `json:"updated_at"`
+}
+
+func (q *APIProjectQuota) BuildFromService(h interface{}) error {
+	data, ok := h.(*quota.ProjectQuota)
+	if !ok {
+		return errors.New("can't convert unknown type to APIProjectQuota")
+	}
+
+	q.Project = ToAPIString(data.Project)
+	q.Weight = data.Weight
+	q.MaxRunningTasks = data.MaxRunningTasks
+	q.UpdatedBy = ToAPIString(data.UpdatedBy)
+	q.UpdatedAt = NewTime(data.UpdatedAt)
+
+	return nil
+}
+
+func (q *APIProjectQuota) ToService() (interface{}, error) {
+	return &quota.ProjectQuota{
+		Project:         FromAPIString(q.Project),
+		Weight:          q.Weight,
+		MaxRunningTasks: q.MaxRunningTasks,
+	}, nil
+}
@@ -0,0 +1,10 @@
+package model
+
+// APIProjectVarsMigration moves a project's variables into a secrets
+// manager, named under the prefix configured in the admin settings. The
+// response lists the variables that were moved.
+type APIProjectVarsMigration struct {
+	Backend     APIString   `json:"backend"`
+	PrivateOnly bool        `json:"private_only"`
+	Migrated    []APIString `json:"migrated"`
+}
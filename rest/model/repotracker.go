@@ -0,0 +1,45 @@
+package model
+
+import "github.com/evergreen-ci/evergreen/model/version"
+
+// APIRepoTrackerFixture is a set of synthetic revisions to load into a
+// project in place of commits polled from its repository.
+type APIRepoTrackerFixture struct {
+	// Config is the YAML project configuration used by revisions that do
+	// not specify their own.
+	Config    APIString                       `json:"config"`
+	Revisions []APIRepoTrackerFixtureRevision `json:"revisions"`
+}
+
+// APIRepoTrackerFixtureRevision is a synthetic revision. Revisions in a
+// fixture are ordered from newest to oldest.
+type APIRepoTrackerFixtureRevision struct {
+	Revision    APIString `json:"revision"`
+	Author      APIString `json:"author"`
+	AuthorEmail APIString `json:"author_email"`
+	Message     APIString `json:"message"`
+	CreateTime  APITime   `json:"create_time"`
+	Config      APIString `json:"config"`
+}
+
+// APIManualVersion is a request to create a version of a project at a
+// revision without the revision being pushed. Config, if given, is the YAML
+// project configuration used instead of the one in the repository, Message
+// replaces the revision's commit message, and Parameters override the
+// defaults of the parameters the configuration defines.
+type APIManualVersion struct {
+	Revision   APIString      `json:"revision"`
+	Config     APIString      `json:"config"`
+	Message    APIString      `json:"message"`
+	Parameters []APIParameter `json:"parameters"`
+}
+
+// ServiceParameters returns the request's parameters as a version's
+// parameters.
+func (v *APIManualVersion) ServiceParameters() []version.Parameter {
+	params := []version.Parameter{}
+	for i := range v.Parameters {
+		params = append(params, v.Parameters[i].ToService())
+	}
+	return params
+}
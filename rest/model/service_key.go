@@ -0,0 +1,39 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/pkg/errors"
+)
+
+// APIServiceKey is an API key for a service account, whose requests are
+// limited to its scopes until it expires. Its token, sent in the Api-Key
+// header, is only returned when the key is created.
+type APIServiceKey struct {
+	ID        APIString `json:"id"`
+	Name      APIString `json:"name"`
+	Token     APIString `json:"token,omitempty"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt APITime   `json:"expires_at"`
+	CreatedBy APIString `json:"created_by"`
+	CreatedAt APITime   `json:"created_at"`
+}
+
+func (k *APIServiceKey) BuildFromService(h interface{}) error {
+	data, ok := h.(*user.ServiceKey)
+	if !ok {
+		return errors.New("can't convert unknown type to APIServiceKey")
+	}
+
+	k.ID = ToAPIString(data.ID)
+	k.Name = ToAPIString(data.Name)
+	k.Scopes = data.Scopes
+	k.ExpiresAt = NewTime(data.ExpiresAt)
+	k.CreatedBy = ToAPIString(data.CreatedBy)
+	k.CreatedAt = NewTime(data.CreatedAt)
+
+	return nil
+}
+
+func (k *APIServiceKey) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIServiceKey) ToService not implemented")
+}
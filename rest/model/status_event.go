@@ -0,0 +1,66 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/pkg/errors"
+)
+
+// APIStatusEvent is a state transition of a version, build or task, as it's
+// pushed to clients of the event stream.
+type APIStatusEvent struct {
+	ID           APIString `json:"id"`
+	ResourceType APIString `json:"resource_type"`
+	ResourceID   APIString `json:"resource_id"`
+	EventType    APIString `json:"event_type"`
+	Timestamp    APITime   `json:"timestamp"`
+	Status       APIString `json:"status"`
+	Execution    int       `json:"execution,omitempty"`
+	Project      APIString `json:"project"`
+	Version      APIString `json:"version"`
+	BuildVariant APIString `json:"build_variant,omitempty"`
+	DisplayName  APIString `json:"display_name,omitempty"`
+}
+
+// BuildFromService fills in the event's fields from the event log entry.
+// The project, version, and for builds and tasks, the build variant and
+// display name, are not recorded in the event, and are left unset.
+func (e *APIStatusEvent) BuildFromService(h interface{}) error {
+	data, ok := h.(*event.EventLogEntry)
+	if !ok {
+		return errors.New("can't convert unknown type to APIStatusEvent")
+	}
+
+	e.ID = ToAPIString(data.ID)
+	e.ResourceType = ToAPIString(data.ResourceType)
+	e.ResourceID = ToAPIString(data.ResourceId)
+	e.EventType = ToAPIString(data.EventType)
+	e.Timestamp = NewTime(data.Timestamp)
+
+	switch eventData := data.Data.(type) {
+	case *event.VersionEventData:
+		e.Status = ToAPIString(eventData.Status)
+	case *event.BuildEventData:
+		e.Status = ToAPIString(eventData.Status)
+	case *event.TaskEventData:
+		e.Execution = eventData.Execution
+		switch data.EventType {
+		case event.TaskDispatched:
+			e.Status = ToAPIString(evergreen.TaskDispatched)
+		case event.TaskStarted:
+			e.Status = ToAPIString(evergreen.TaskStarted)
+		case event.TaskRestarted:
+			e.Status = ToAPIString(evergreen.TaskUndispatched)
+		default:
+			e.Status = ToAPIString(eventData.Status)
+		}
+	default:
+		return errors.Errorf("'%s' events are not status events", data.ResourceType)
+	}
+
+	return nil
+}
+
+func (e *APIStatusEvent) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIStatusEvent) ToService not implemented")
+}
@@ -22,6 +22,12 @@ type APIGithubPRSubscriber struct {
 	Ref      APIString `json:"ref" mapstructure:"ref"`
 }
 
+type APIGithubCheckSubscriber struct {
+	Owner APIString `json:"owner" mapstructure:"owner"`
+	Repo  APIString `json:"repo" mapstructure:"repo"`
+	Ref   APIString `json:"ref" mapstructure:"ref"`
+}
+
 type APIWebhookSubscriber struct {
 	URL    APIString `json:"url" mapstructure:"url"`
 	Secret APIString `json:"secret" mapstructure:"secret"`
@@ -59,6 +65,22 @@ func (s *APISubscriber) BuildFromService(h interface{}) error {
 			}
 			target = sub
 
+		case event.OpsgenieSubscriberType:
+			sub := APIOpsgenieSubscriber{}
+			err := sub.BuildFromService(v.Target)
+			if err != nil {
+				return err
+			}
+			target = sub
+
+		case event.GithubCheckSubscriberType:
+			sub := APIGithubCheckSubscriber{}
+			err := sub.BuildFromService(v.Target)
+			if err != nil {
+				return err
+			}
+			target = sub
+
 		case event.JIRACommentSubscriberType, event.EmailSubscriberType,
 			event.SlackSubscriberType:
 			target = v.Target
@@ -123,6 +145,32 @@ func (s *APISubscriber) ToService() (interface{}, error) {
 			return nil, err
 		}
 
+	case event.OpsgenieSubscriberType:
+		apiModel := APIOpsgenieSubscriber{}
+		if err = mapstructure.Decode(s.Target, &apiModel); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("opsgenie subscriber is malformed: %s", err.Error()),
+			}
+		}
+		target, err = apiModel.ToService()
+		if err != nil {
+			return nil, err
+		}
+
+	case event.GithubCheckSubscriberType:
+		apiModel := APIGithubCheckSubscriber{}
+		if err = mapstructure.Decode(s.Target, &apiModel); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("github check subscriber is malformed: %s", err.Error()),
+			}
+		}
+		target, err = apiModel.ToService()
+		if err != nil {
+			return nil, err
+		}
+
 	case event.JIRACommentSubscriberType, event.EmailSubscriberType,
 		event.SlackSubscriberType:
 		target = s.Target
@@ -163,6 +211,32 @@ func (s *APIGithubPRSubscriber) ToService() (interface{}, error) {
 	}, nil
 }
 
+func (s *APIGithubCheckSubscriber) BuildFromService(h interface{}) error {
+	if v, ok := h.(event.GithubCheckSubscriber); ok {
+		h = &v
+	}
+
+	switch v := h.(type) {
+	case *event.GithubCheckSubscriber:
+		s.Owner = ToAPIString(v.Owner)
+		s.Repo = ToAPIString(v.Repo)
+		s.Ref = ToAPIString(v.Ref)
+
+	default:
+		return errors.New("unknown type for APIGithubCheckSubscriber")
+	}
+
+	return nil
+}
+
+func (s *APIGithubCheckSubscriber) ToService() (interface{}, error) {
+	return event.GithubCheckSubscriber{
+		Owner: FromAPIString(s.Owner),
+		Repo:  FromAPIString(s.Repo),
+		Ref:   FromAPIString(s.Ref),
+	}, nil
+}
+
 func (s *APIWebhookSubscriber) BuildFromService(h interface{}) error {
 	if v, ok := h.(event.WebhookSubscriber); ok {
 		h = &v
@@ -215,3 +289,32 @@ func (s *APIJIRAIssueSubscriber) ToService() (interface{}, error) {
 		IssueType: FromAPIString(s.IssueType),
 	}, nil
 }
+
+type APIOpsgenieSubscriber struct {
+	APIKey APIString `json:"api_key" mapstructure:"api_key"`
+	Team   APIString `json:"team" mapstructure:"team"`
+}
+
+func (s *APIOpsgenieSubscriber) BuildFromService(h interface{}) error {
+	if v, ok := h.(event.OpsgenieSubscriber); ok {
+		h = &v
+	}
+
+	switch v := h.(type) {
+	case *event.OpsgenieSubscriber:
+		s.APIKey = ToAPIString(v.APIKey)
+		s.Team = ToAPIString(v.Team)
+
+	default:
+		return errors.New("unknown type for APIOpsgenieSubscriber")
+	}
+
+	return nil
+}
+
+func (s *APIOpsgenieSubscriber) ToService() (interface{}, error) {
+	return event.OpsgenieSubscriber{
+		APIKey: FromAPIString(s.APIKey),
+		Team:   FromAPIString(s.Team),
+	}, nil
+}
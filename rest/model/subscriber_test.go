@@ -107,6 +107,72 @@ func TestSubscriberModelsJIRAIssue(t *testing.T) {
 	assert.EqualValues(origJIRAIssueSubscriber, serviceModel)
 }
 
+func TestSubscriberModelsOpsgenie(t *testing.T) {
+	assert := assert.New(t)
+
+	opsgenieSubscriber := event.Subscriber{
+		Type: event.OpsgenieSubscriberType,
+		Target: event.OpsgenieSubscriber{
+			APIKey: "key",
+			Team:   "evergreen",
+		},
+	}
+	apiOpsgenieSubscriber := APISubscriber{}
+	err := apiOpsgenieSubscriber.BuildFromService(opsgenieSubscriber)
+	assert.NoError(err)
+
+	origOpsgenieSubscriber, err := apiOpsgenieSubscriber.ToService()
+	assert.NoError(err)
+	assert.EqualValues(opsgenieSubscriber, origOpsgenieSubscriber)
+
+	// incoming subscribers have target serialized as a map
+	incoming := APISubscriber{
+		Type: ToAPIString(event.OpsgenieSubscriberType),
+		Target: map[string]interface{}{
+			"api_key": "key",
+			"team":    "evergreen",
+		},
+	}
+
+	serviceModel, err := incoming.ToService()
+	assert.NoError(err)
+	assert.EqualValues(origOpsgenieSubscriber, serviceModel)
+}
+
+func TestSubscriberModelsGithubCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	checkSubscriber := event.Subscriber{
+		Type: event.GithubCheckSubscriberType,
+		Target: event.GithubCheckSubscriber{
+			Owner: "evergreen-ci",
+			Repo:  "evergreen",
+			Ref:   "abcdef",
+		},
+	}
+	apiCheckSubscriber := APISubscriber{}
+	err := apiCheckSubscriber.BuildFromService(checkSubscriber)
+	assert.NoError(err)
+
+	origCheckSubscriber, err := apiCheckSubscriber.ToService()
+	assert.NoError(err)
+	assert.EqualValues(checkSubscriber, origCheckSubscriber)
+
+	// incoming subscribers have target serialized as a map
+	incoming := APISubscriber{
+		Type: ToAPIString(event.GithubCheckSubscriberType),
+		Target: map[string]interface{}{
+			"owner": "evergreen-ci",
+			"repo":  "evergreen",
+			"ref":   "abcdef",
+		},
+	}
+
+	serviceModel, err := incoming.ToService()
+	assert.NoError(err)
+	assert.EqualValues(origCheckSubscriber, serviceModel)
+}
+
 func TestSubscriberModelsSlack(t *testing.T) {
 	assert := assert.New(t)
 
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/event"
 )
@@ -12,15 +13,83 @@ type APISelector struct {
 }
 
 type APISubscription struct {
-	ID             APIString         `json:"id"`
-	ResourceType   APIString         `json:"resource_type"`
-	Trigger        APIString         `json:"trigger"`
-	Selectors      []APISelector     `json:"selectors"`
-	RegexSelectors []APISelector     `json:"regex_selectors"`
-	Subscriber     APISubscriber     `json:"subscriber"`
-	OwnerType      APIString         `json:"owner_type"`
-	Owner          APIString         `json:"owner"`
-	TriggerData    map[string]string `json:"trigger_data,omitempty"`
+	ID             APIString            `json:"id"`
+	ResourceType   APIString            `json:"resource_type"`
+	Trigger        APIString            `json:"trigger"`
+	Selectors      []APISelector        `json:"selectors"`
+	RegexSelectors []APISelector        `json:"regex_selectors"`
+	Subscriber     APISubscriber        `json:"subscriber"`
+	OwnerType      APIString            `json:"owner_type"`
+	Owner          APIString            `json:"owner"`
+	TriggerData    map[string]string    `json:"trigger_data,omitempty"`
+	Digest         APIString            `json:"digest"`
+	Escalation     *APIEscalationPolicy `json:"escalation,omitempty"`
+	MutedUntil     APITime              `json:"muted_until"`
+}
+
+// APIEscalationPolicy describes the tiers of subscribers that a
+// subscription's notifications escalate to when they aren't acknowledged.
+type APIEscalationPolicy struct {
+	DelayMins int                 `json:"delay_mins"`
+	Tiers     []APIEscalationTier `json:"tiers"`
+}
+
+type APIEscalationTier struct {
+	Name        APIString       `json:"name"`
+	Subscribers []APISubscriber `json:"subscribers"`
+}
+
+func (p *APIEscalationPolicy) BuildFromService(h interface{}) error {
+	v, ok := h.(event.Escalation)
+	if !ok {
+		return errors.New("unrecognized type for APIEscalationPolicy")
+	}
+
+	p.DelayMins = v.Delay
+	p.Tiers = []APIEscalationTier{}
+	for _, tier := range v.Tiers {
+		apiTier := APIEscalationTier{
+			Name:        ToAPIString(tier.Name),
+			Subscribers: []APISubscriber{},
+		}
+		for _, subscriber := range tier.Subscribers {
+			apiSubscriber := APISubscriber{}
+			if err := apiSubscriber.BuildFromService(subscriber); err != nil {
+				return err
+			}
+			apiTier.Subscribers = append(apiTier.Subscribers, apiSubscriber)
+		}
+		p.Tiers = append(p.Tiers, apiTier)
+	}
+
+	return nil
+}
+
+func (p *APIEscalationPolicy) ToService() (interface{}, error) {
+	out := event.Escalation{
+		Delay: p.DelayMins,
+		Tiers: []event.EscalationTier{},
+	}
+	for _, apiTier := range p.Tiers {
+		tier := event.EscalationTier{
+			Name:        FromAPIString(apiTier.Name),
+			Subscribers: []event.Subscriber{},
+		}
+		for _, apiSubscriber := range apiTier.Subscribers {
+			subscriberInterface, err := apiSubscriber.ToService()
+			if err != nil {
+				return nil, err
+			}
+			subscriber, ok := subscriberInterface.(event.Subscriber)
+			if !ok {
+				return nil, errors.New("unable to convert subscriber")
+			}
+			tier.Subscribers = append(tier.Subscribers, subscriber)
+		}
+		out.Tiers = append(out.Tiers, tier)
+	}
+
+	return out, nil
 }
 
 func (s *APISelector) BuildFromService(h interface{}) error {
@@ -51,10 +120,18 @@ func (s *APISubscription) BuildFromService(h interface{}) error {
 		s.Owner = ToAPIString(v.Owner)
 		s.OwnerType = ToAPIString(string(v.OwnerType))
 		s.TriggerData = v.TriggerData
+		s.Digest = ToAPIString(v.Digest)
+		s.MutedUntil = NewTime(v.MutedUntil)
 		err := s.Subscriber.BuildFromService(v.Subscriber)
 		if err != nil {
 			return err
 		}
+		if v.Escalation != nil {
+			s.Escalation = &APIEscalationPolicy{}
+			if err = s.Escalation.BuildFromService(*v.Escalation); err != nil {
+				return err
+			}
+		}
 		s.Selectors = []APISelector{}
 		s.RegexSelectors = []APISelector{}
 		for _, selector := range v.Selectors {
@@ -90,6 +167,8 @@ func (s *APISubscription) ToService() (interface{}, error) {
 		Selectors:      []event.Selector{},
 		RegexSelectors: []event.Selector{},
 		TriggerData:    s.TriggerData,
+		Digest:         FromAPIString(s.Digest),
+		MutedUntil:     time.Time(s.MutedUntil),
 	}
 	subscriberInterface, err := s.Subscriber.ToService()
 	if err != nil {
@@ -100,6 +179,17 @@ func (s *APISubscription) ToService() (interface{}, error) {
 		return nil, errors.New("unable to convert subscriber")
 	}
 	out.Subscriber = subscriber
+	if s.Escalation != nil {
+		escalationInterface, err := s.Escalation.ToService()
+		if err != nil {
+			return nil, err
+		}
+		escalation, ok := escalationInterface.(event.Escalation)
+		if !ok {
+			return nil, errors.New("unable to convert escalation")
+		}
+		out.Escalation = &escalation
+	}
 	for _, selector := range s.Selectors {
 		selectorInterface, err := selector.ToService()
 		if err != nil {
@@ -48,6 +48,9 @@ type APITask struct {
 	PreviousExecutions []APITask        `json:"previous_executions,omitempty"`
 	GenerateTask       bool             `json:"generate_task"`
 	GeneratedBy        string           `json:"generated_by"`
+	StepbackTaskId     APIString        `json:"stepback_task_id"`
+	AutoRetries        int              `json:"auto_retries"`
+	AutoRetried        bool             `json:"auto_retried"`
 	Artifacts          []APIFile        `json:"artifacts"`
 	DisplayOnly        bool             `json:"display_only"`
 	ExecutionTasks     []APIString      `json:"execution_tasks,omitempty"`
@@ -117,6 +120,9 @@ func (at *APITask) BuildFromService(t interface{}) error {
 			EstimatedCost:    v.Cost,
 			GenerateTask:     v.GenerateTask,
 			GeneratedBy:      v.GeneratedBy,
+			StepbackTaskId:   ToAPIString(v.StepbackTaskId),
+			AutoRetries:      v.AutoRetries,
+			AutoRetried:      v.AutoRetried,
 			DisplayOnly:      v.DisplayOnly,
 		}
 		if len(v.ExecutionTasks) > 0 {
@@ -185,6 +191,9 @@ func (ad *APITask) ToService() (interface{}, error) {
 		Cost:             ad.EstimatedCost,
 		GenerateTask:     ad.GenerateTask,
 		GeneratedBy:      ad.GeneratedBy,
+		StepbackTaskId:   FromAPIString(ad.StepbackTaskId),
+		AutoRetries:      ad.AutoRetries,
+		AutoRetried:      ad.AutoRetried,
 		DisplayOnly:      ad.DisplayOnly,
 	}
 	if len(ad.ExecutionTasks) > 0 {
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/pkg/errors"
+)
+
+// APIStructuredTaskLog is a single structured log line of a task.
+type APIStructuredTaskLog struct {
+	TaskId    APIString              `json:"task_id"`
+	Execution int                    `json:"execution"`
+	Component APIString              `json:"component"`
+	Level     APIString              `json:"level"`
+	Message   APIString              `json:"message"`
+	Timestamp APITime                `json:"timestamp"`
+	Fields    map[string]interface{} `json:"fields"`
+}
+
+// BuildFromService converts from a model.StructuredTaskLog.
+func (l *APIStructuredTaskLog) BuildFromService(h interface{}) error {
+	var log model.StructuredTaskLog
+	switch v := h.(type) {
+	case model.StructuredTaskLog:
+		log = v
+	case *model.StructuredTaskLog:
+		log = *v
+	default:
+		return errors.Errorf("can't convert %T to APIStructuredTaskLog", h)
+	}
+
+	l.TaskId = ToAPIString(log.TaskId)
+	l.Execution = log.Execution
+	l.Component = ToAPIString(log.Component)
+	l.Level = ToAPIString(log.Level)
+	l.Message = ToAPIString(log.Message)
+	l.Timestamp = NewTime(log.Timestamp)
+	l.Fields = log.Fields
+
+	return nil
+}
+
+func (l *APIStructuredTaskLog) ToService() (interface{}, error) {
+	return nil, errors.New("(*APIStructuredTaskLog) ToService not implemented")
+}
+
+// APILogMessage is a single message of a task's log.
+type APILogMessage struct {
+	Type      APIString `json:"type"`
+	Severity  APIString `json:"severity"`
+	Message   APIString `json:"message"`
+	Timestamp APITime   `json:"timestamp"`
+}
+
+// BuildFromService converts from an apimodels.LogMessage.
+func (m *APILogMessage) BuildFromService(h interface{}) error {
+	var msg apimodels.LogMessage
+	switch v := h.(type) {
+	case apimodels.LogMessage:
+		msg = v
+	case *apimodels.LogMessage:
+		msg = *v
+	default:
+		return errors.Errorf("can't convert %T to APILogMessage", h)
+	}
+
+	m.Type = ToAPIString(msg.Type)
+	m.Severity = ToAPIString(msg.Severity)
+	m.Message = ToAPIString(msg.Message)
+	m.Timestamp = NewTime(msg.Timestamp)
+
+	return nil
+}
+
+func (m *APILogMessage) ToService() (interface{}, error) {
+	return nil, errors.New("(*APILogMessage) ToService not implemented")
+}
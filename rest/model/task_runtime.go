@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/taskruntime"
+	"github.com/pkg/errors"
+)
+
+// APITaskRuntimePrediction is how long a task is expected to take, estimated
+// from the recent runtimes of the task in its project's variant, and when it
+// is expected to finish if it's running.
+type APITaskRuntimePrediction struct {
+	TaskId     APIString   `json:"task_id"`
+	NumSamples int         `json:"num_samples"`
+	Reliable   bool        `json:"reliable"`
+	P50        APIDuration `json:"p50_ms"`
+	P90        APIDuration `json:"p90_ms"`
+	P99        APIDuration `json:"p99_ms"`
+
+	ExpectedFinish APITime     `json:"expected_finish"`
+	Remaining      APIDuration `json:"remaining_ms"`
+}
+
+// BuildFromService fills in the runtime estimates from a
+// taskruntime.TaskRuntime.
+func (p *APITaskRuntimePrediction) BuildFromService(h interface{}) error {
+	r, ok := h.(*taskruntime.TaskRuntime)
+	if !ok {
+		return errors.New("can't convert unknown type to APITaskRuntimePrediction")
+	}
+
+	p.NumSamples = len(r.Samples)
+	p.Reliable = r.Reliable()
+	p.P50 = NewAPIDuration(r.Percentile(50))
+	p.P90 = NewAPIDuration(r.Percentile(90))
+	p.P99 = NewAPIDuration(r.Percentile(99))
+
+	return nil
+}
+
+func (p *APITaskRuntimePrediction) ToService() (interface{}, error) {
+	return nil, errors.New("(*APITaskRuntimePrediction) ToService not implemented")
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
 )
 
 // APITest contains the data to be returned whenever a test is used in the
@@ -69,3 +71,49 @@ func (at *APITest) ToService() (interface{}, error) {
 		EndTime:   util.ToPythonTime(time.Time(at.EndTime)),
 	}, nil
 }
+
+// APITestFlakiness is how often a test in a task of a project flaked in the
+// project's recent mainline versions.
+type APITestFlakiness struct {
+	Project    APIString `json:"project"`
+	Variant    APIString `json:"build_variant"`
+	Task       APIString `json:"task"`
+	Test       APIString `json:"test_file"`
+	Runs       int       `json:"runs"`
+	Failures   int       `json:"failures"`
+	Flakes     int       `json:"flakes"`
+	FlakeRate  float64   `json:"flake_rate"`
+	Flaky      bool      `json:"flaky"`
+	LastFlake  APITime   `json:"last_flake"`
+	LastUpdate APITime   `json:"last_update"`
+}
+
+func (af *APITestFlakiness) BuildFromService(h interface{}) error {
+	var f *testflakiness.TestFlakiness
+	switch v := h.(type) {
+	case testflakiness.TestFlakiness:
+		f = &v
+	case *testflakiness.TestFlakiness:
+		f = v
+	default:
+		return fmt.Errorf("incorrect type %T when creating APITestFlakiness", h)
+	}
+
+	af.Project = ToAPIString(f.Id.Project)
+	af.Variant = ToAPIString(f.Id.Variant)
+	af.Task = ToAPIString(f.Id.Task)
+	af.Test = ToAPIString(f.Id.Test)
+	af.Runs = f.Runs
+	af.Failures = f.Failures
+	af.Flakes = f.Flakes
+	af.FlakeRate = f.FlakeRate
+	af.Flaky = f.IsFlaky()
+	af.LastFlake = NewTime(f.LastFlake)
+	af.LastUpdate = NewTime(f.LastUpdate)
+	return nil
+}
+
+// ToService is not implemented for APITestFlakiness.
+func (af *APITestFlakiness) ToService() (interface{}, error) {
+	return nil, errors.New("ToService not implemented for APITestFlakiness")
+}
@@ -1,9 +1,12 @@
 package model
 
 import (
+	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -33,6 +36,7 @@ type APIUserSettings struct {
 	Timezone      APIString                   `json:"timezone"`
 	GithubUser    *APIGithubUser              `json:"github_user"`
 	SlackUsername APIString                   `json:"slack_username"`
+	SlackMemberId APIString                   `json:"slack_member_id"`
 	Notifications *APINotificationPreferences `json:"notifications"`
 }
 
@@ -41,6 +45,7 @@ func (s *APIUserSettings) BuildFromService(h interface{}) error {
 	case user.UserSettings:
 		s.Timezone = ToAPIString(v.Timezone)
 		s.SlackUsername = ToAPIString(v.SlackUsername)
+		s.SlackMemberId = ToAPIString(v.SlackMemberId)
 		s.GithubUser = &APIGithubUser{}
 		err := s.GithubUser.BuildFromService(v.GithubUser)
 		if err != nil {
@@ -77,6 +82,7 @@ func (s *APIUserSettings) ToService() (interface{}, error) {
 	return user.UserSettings{
 		Timezone:      FromAPIString(s.Timezone),
 		SlackUsername: FromAPIString(s.SlackUsername),
+		SlackMemberId: FromAPIString(s.SlackMemberId),
 		GithubUser:    githubUser,
 		Notifications: preferences,
 	}, nil
@@ -202,3 +208,123 @@ func ApplyUserChanges(current user.UserSettings, changes APIUserSettings) (APIUs
 
 	return oldSettings, nil
 }
+
+// APIDeliveryPreferences control when and where notifications for a
+// user's subscriptions are delivered. Quiet hours are given as HH:MM in
+// the user's timezone.
+type APIDeliveryPreferences struct {
+	QuietHours      *APIQuietHours                  `json:"quiet_hours"`
+	ChannelPriority []string                        `json:"channel_priority"`
+	Projects        []APIProjectDeliveryPreferences `json:"projects"`
+}
+
+type APIProjectDeliveryPreferences struct {
+	Project         APIString      `json:"project"`
+	QuietHours      *APIQuietHours `json:"quiet_hours"`
+	ChannelPriority []string       `json:"channel_priority"`
+}
+
+type APIQuietHours struct {
+	Start APIString `json:"start"`
+	End   APIString `json:"end"`
+}
+
+func (p *APIDeliveryPreferences) BuildFromService(h interface{}) error {
+	v, ok := h.(user.DeliveryPreferences)
+	if !ok {
+		return errors.Errorf("incorrect type for APIDeliveryPreferences")
+	}
+
+	p.QuietHours = buildAPIQuietHours(v.QuietHours)
+	p.ChannelPriority = v.ChannelPriority
+	p.Projects = make([]APIProjectDeliveryPreferences, 0, len(v.Projects))
+	for _, project := range v.Projects {
+		apiProject := APIProjectDeliveryPreferences{}
+		if err := apiProject.BuildFromService(project); err != nil {
+			return err
+		}
+		p.Projects = append(p.Projects, apiProject)
+	}
+
+	return nil
+}
+
+// ToService returns the preferences, or an error describing every invalid
+// preference.
+func (p *APIDeliveryPreferences) ToService() (interface{}, error) {
+	catcher := grip.NewBasicCatcher()
+	prefs := user.DeliveryPreferences{
+		QuietHours:      quietHoursToService("quiet_hours", p.QuietHours, catcher),
+		ChannelPriority: p.ChannelPriority,
+	}
+	for i := range p.Projects {
+		prefs.Projects = append(prefs.Projects, p.Projects[i].toService(fmt.Sprintf("projects[%d].", i), catcher))
+	}
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+	if err := prefs.Validate(); err != nil {
+		return nil, err
+	}
+
+	return prefs, nil
+}
+
+func (p *APIProjectDeliveryPreferences) BuildFromService(h interface{}) error {
+	v, ok := h.(user.ProjectDeliveryPreferences)
+	if !ok {
+		return errors.Errorf("incorrect type for APIProjectDeliveryPreferences")
+	}
+
+	p.Project = ToAPIString(v.Project)
+	p.QuietHours = buildAPIQuietHours(v.QuietHours)
+	p.ChannelPriority = v.ChannelPriority
+
+	return nil
+}
+
+func (p *APIProjectDeliveryPreferences) ToService() (interface{}, error) {
+	catcher := grip.NewBasicCatcher()
+	prefs := p.toService("", catcher)
+
+	return prefs, catcher.Resolve()
+}
+
+func (p *APIProjectDeliveryPreferences) toService(prefix string, catcher grip.Catcher) user.ProjectDeliveryPreferences {
+	return user.ProjectDeliveryPreferences{
+		Project:         FromAPIString(p.Project),
+		QuietHours:      quietHoursToService(prefix+"quiet_hours", p.QuietHours, catcher),
+		ChannelPriority: p.ChannelPriority,
+	}
+}
+
+func buildAPIQuietHours(q *user.QuietHours) *APIQuietHours {
+	if q == nil {
+		return nil
+	}
+
+	return &APIQuietHours{
+		Start: ToAPIString(fmt.Sprintf("%02d:%02d", q.Start/60, q.Start%60)),
+		End:   ToAPIString(fmt.Sprintf("%02d:%02d", q.End/60, q.End%60)),
+	}
+}
+
+func quietHoursToService(field string, q *APIQuietHours, catcher grip.Catcher) *user.QuietHours {
+	if q == nil {
+		return nil
+	}
+
+	parse := func(name string, in APIString) int {
+		t, err := time.Parse("15:04", FromAPIString(in))
+		if err != nil {
+			catcher.Add(errors.Errorf("%s.%s: '%s' is not a time of day in HH:MM format", field, name, FromAPIString(in)))
+			return 0
+		}
+		return t.Hour()*60 + t.Minute()
+	}
+
+	return &user.QuietHours{
+		Start: parse("start", q.Start),
+		End:   parse("end", q.End),
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/pkg/errors"
 )
@@ -26,6 +27,74 @@ type APIVersion struct {
 	Errors   []APIString `json:"errors"`
 	Warnings []APIString `json:"warnings"`
 	Ignored  bool        `json:"ignored"`
+	Priority int64       `json:"priority"`
+
+	Tag          *APITagMetadata        `json:"tag,omitempty"`
+	Parameters   []APIParameter         `json:"parameters"`
+	Deprecations []APIConfigDeprecation `json:"deprecations"`
+}
+
+// APIConfigDeprecation is deprecated syntax that a version's project config
+// uses.
+type APIConfigDeprecation struct {
+	Syntax      APIString   `json:"syntax"`
+	Replacement APIString   `json:"replacement"`
+	Since       int         `json:"since"`
+	Locations   []APIString `json:"locations"`
+}
+
+func buildAPIConfigDeprecations(deprecations []version.ConfigDeprecation) []APIConfigDeprecation {
+	apiDeprecations := []APIConfigDeprecation{}
+	for _, d := range deprecations {
+		apiDeprecation := APIConfigDeprecation{
+			Syntax:      ToAPIString(d.Syntax),
+			Replacement: ToAPIString(d.Replacement),
+			Since:       d.Since,
+			Locations:   []APIString{},
+		}
+		for _, location := range d.Locations {
+			apiDeprecation.Locations = append(apiDeprecation.Locations, ToAPIString(location))
+		}
+		apiDeprecations = append(apiDeprecations, apiDeprecation)
+	}
+	return apiDeprecations
+}
+
+// APIParameter is the value of a project parameter for a version.
+type APIParameter struct {
+	Key   APIString `json:"key"`
+	Value APIString `json:"value"`
+}
+
+func buildAPIParameters(params []version.Parameter) []APIParameter {
+	apiParams := []APIParameter{}
+	for _, param := range params {
+		apiParams = append(apiParams, APIParameter{
+			Key:   ToAPIString(param.Key),
+			Value: ToAPIString(param.Value),
+		})
+	}
+	return apiParams
+}
+
+// ToService returns the parameter as a version's parameter.
+func (p *APIParameter) ToService() version.Parameter {
+	return version.Parameter{
+		Key:   FromAPIString(p.Key),
+		Value: FromAPIString(p.Value),
+	}
+}
+
+// APITagMetadata is the model for the annotation of the git tag on a version.
+type APITagMetadata struct {
+	Name              APIString `json:"name"`
+	SHA               APIString `json:"sha"`
+	Message           APIString `json:"message"`
+	Tagger            APIString `json:"tagger"`
+	TaggerEmail       APIString `json:"tagger_email"`
+	TagTime           APITime   `json:"tag_time"`
+	SignatureVerified bool      `json:"signature_verified"`
+	SignatureReason   APIString `json:"signature_reason"`
 }
 
 type buildDetail struct {
@@ -53,6 +122,23 @@ func (apiVersion *APIVersion) BuildFromService(h interface{}) error {
 	apiVersion.Branch = ToAPIString(v.Branch)
 	apiVersion.Order = v.RevisionOrderNumber
 	apiVersion.Project = ToAPIString(v.Identifier)
+	apiVersion.Priority = v.Priority
+
+	if v.Tag != nil {
+		apiVersion.Tag = &APITagMetadata{
+			Name:              ToAPIString(v.Tag.Name),
+			SHA:               ToAPIString(v.Tag.SHA),
+			Message:           ToAPIString(v.Tag.Message),
+			Tagger:            ToAPIString(v.Tag.Tagger),
+			TaggerEmail:       ToAPIString(v.Tag.TaggerEmail),
+			TagTime:           NewTime(v.Tag.TagTime),
+			SignatureVerified: v.Tag.SignatureVerified,
+			SignatureReason:   ToAPIString(v.Tag.SignatureReason),
+		}
+	}
+
+	apiVersion.Parameters = buildAPIParameters(v.Parameters)
+	apiVersion.Deprecations = buildAPIConfigDeprecations(v.Deprecations)
 
 	var bd buildDetail
 	for _, t := range v.BuildVariants {
@@ -70,3 +156,32 @@ func (apiVersion *APIVersion) BuildFromService(h interface{}) error {
 func (apiVersion *APIVersion) ToService() (interface{}, error) {
 	return nil, errors.New("not implemented for read-only route")
 }
+
+// APIVersionTaskFilter selects the tasks of a version that are restarted or
+// aborted together, by status, by a regular expression matching their build
+// variant, and by name. Empty fields don't filter the tasks.
+type APIVersionTaskFilter struct {
+	Statuses     []string  `json:"statuses"`
+	VariantRegex APIString `json:"variant_regex"`
+	TaskNames    []string  `json:"task_names"`
+}
+
+func (f *APIVersionTaskFilter) BuildFromService(h interface{}) error {
+	filter, ok := h.(model.VersionTaskFilter)
+	if !ok {
+		return errors.Errorf("incorrect type when fetching converting version task filter")
+	}
+	f.Statuses = filter.Statuses
+	f.VariantRegex = ToAPIString(filter.VariantRegex)
+	f.TaskNames = filter.TaskNames
+	return nil
+}
+
+func (f *APIVersionTaskFilter) ToService() (interface{}, error) {
+	filter := model.VersionTaskFilter{
+		Statuses:     f.Statuses,
+		VariantRegex: FromAPIString(f.VariantRegex),
+		TaskNames:    f.TaskNames,
+	}
+	return filter, filter.Validate()
+}
@@ -77,6 +77,41 @@ func TestVersionBuildFromService(t *testing.T) {
 	assert.Equal(bvs[1].BuildId, ToAPIString(bi2))
 }
 
+func TestVersionBuildFromServiceWithTag(t *testing.T) {
+	assert := assert.New(t)
+
+	tagTime := time.Now()
+	v := &version.Version{
+		Id: "versionId",
+		Tag: &version.TagMetadata{
+			Name:              "r1.0.0",
+			SHA:               "tag-sha",
+			Message:           "release 1.0.0",
+			Tagger:            "tagger",
+			TaggerEmail:       "tagger@example.com",
+			TagTime:           tagTime,
+			SignatureVerified: true,
+			SignatureReason:   "valid",
+		},
+	}
+
+	apiVersion := &APIVersion{}
+	assert.NoError(apiVersion.BuildFromService(v))
+	assert.NotNil(apiVersion.Tag)
+	assert.Equal(ToAPIString("r1.0.0"), apiVersion.Tag.Name)
+	assert.Equal(ToAPIString("tag-sha"), apiVersion.Tag.SHA)
+	assert.Equal(ToAPIString("release 1.0.0"), apiVersion.Tag.Message)
+	assert.Equal(ToAPIString("tagger"), apiVersion.Tag.Tagger)
+	assert.Equal(ToAPIString("tagger@example.com"), apiVersion.Tag.TaggerEmail)
+	assert.Equal(NewTime(tagTime), apiVersion.Tag.TagTime)
+	assert.True(apiVersion.Tag.SignatureVerified)
+	assert.Equal(ToAPIString("valid"), apiVersion.Tag.SignatureReason)
+
+	apiVersion = &APIVersion{}
+	assert.NoError(apiVersion.BuildFromService(&version.Version{Id: "untagged"}))
+	assert.Nil(apiVersion.Tag)
+}
+
 func TestVersionToService(t *testing.T) {
 	assert := assert.New(t)
 	apiVersion := &APIVersion{}
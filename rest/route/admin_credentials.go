@@ -0,0 +1,46 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+func makeRotateSenderCredentials(sc data.Connector) gimlet.RouteHandler {
+	return &senderCredentialsPostHandler{
+		sc: sc,
+	}
+}
+
+type senderCredentialsPostHandler struct {
+	Credentials model.APISenderCredentials
+
+	sc data.Connector
+}
+
+func (h *senderCredentialsPostHandler) Factory() gimlet.RouteHandler {
+	return &senderCredentialsPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *senderCredentialsPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.Credentials), "problem parsing request body")
+}
+
+func (h *senderCredentialsPostHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	versions, err := h.sc.RotateSenderCredentials(&h.Credentials, u)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem rotating sender credentials"))
+	}
+
+	return gimlet.NewJSONResponse(&model.APISenderCredentialVersions{
+		Versions: versions,
+	})
+}
@@ -0,0 +1,51 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateSenderCredentialsRoute(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	sc.MockAdminConnector.MockSettings = &evergreen.Settings{
+		Credentials: map[string]string{"github": "token old"},
+	}
+	sc.MockAdminConnector.MockSettings.Slack.Token = "old-slack"
+
+	postHandler := makeRotateSenderCredentials(sc)
+	assert.NotNil(postHandler)
+
+	ctx := context.Background()
+	ctx = gimlet.AttachUser(ctx, &user.DBUser{Id: "user"})
+
+	body := []byte(`{"smtp_password": "new-password", "github_token": "new"}`)
+	request, err := http.NewRequest("POST", "/admin/notifications/credentials", bytes.NewBuffer(body))
+	assert.NoError(err)
+	assert.NoError(postHandler.Parse(ctx, request))
+	h := postHandler.(*senderCredentialsPostHandler)
+	assert.Equal("new-password", model.FromAPIString(h.Credentials.SMTPPassword))
+	assert.Nil(h.Credentials.SlackToken)
+
+	resp := postHandler.Run(ctx)
+	assert.NotNil(resp)
+	assert.Equal(http.StatusOK, resp.Status())
+	versions, ok := resp.Data().(*model.APISenderCredentialVersions)
+	assert.True(ok)
+	assert.NotEmpty(versions.Versions)
+
+	settings, err := sc.GetEvergreenSettings()
+	assert.NoError(err)
+	assert.Equal("new-password", settings.Notify.SMTP.Password)
+	assert.Equal("token new", settings.Credentials["github"])
+	assert.Equal("old-slack", settings.Slack.Token)
+}
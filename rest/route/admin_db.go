@@ -0,0 +1,108 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/db/indexes
+
+type indexReportsGetHandler struct {
+	sc data.Connector
+}
+
+func makeFetchIndexReports(sc data.Connector) gimlet.RouteHandler {
+	return &indexReportsGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *indexReportsGetHandler) Factory() gimlet.RouteHandler {
+	return &indexReportsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *indexReportsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *indexReportsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	reports, err := h.sc.GetIndexReports()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem fetching index reports"))
+	}
+
+	return gimlet.NewJSONResponse(reports)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/db/slow_queries
+
+type slowQueriesGetHandler struct {
+	sc data.Connector
+}
+
+func makeFetchSlowQueries(sc data.Connector) gimlet.RouteHandler {
+	return &slowQueriesGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *slowQueriesGetHandler) Factory() gimlet.RouteHandler {
+	return &slowQueriesGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *slowQueriesGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *slowQueriesGetHandler) Run(ctx context.Context) gimlet.Responder {
+	queries, err := h.sc.GetSlowQueries()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem fetching slow queries"))
+	}
+
+	return gimlet.NewJSONResponse(queries)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/admin/db/query_samples
+
+type querySamplesDeleteHandler struct {
+	sc data.Connector
+}
+
+func makeResetQuerySamples(sc data.Connector) gimlet.RouteHandler {
+	return &querySamplesDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (h *querySamplesDeleteHandler) Factory() gimlet.RouteHandler {
+	return &querySamplesDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *querySamplesDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *querySamplesDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.ResetQuerySamples(); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem resetting query samples"))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
@@ -0,0 +1,74 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminDBRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := &data.MockConnector{}
+	pattern := db.QueryPattern{
+		Collection:     "versions",
+		EqualityFields: []string{"author"},
+		Sort:           []string{"-create_time"},
+		Count:          3,
+		TotalDuration:  300 * time.Millisecond,
+	}
+	sc.MockAdminConnector.MockIndexReports = []db.IndexReport{{
+		Collection:     "versions",
+		Patterns:       []db.QueryPattern{pattern},
+		MissingIndexes: []db.IndexSuggestion{{Keys: pattern.SuggestedIndex(), Pattern: pattern}},
+		UnusedIndexes:  []db.IndexUsage{{Name: "order_1", Keys: []string{"order"}, Accesses: -1}},
+	}}
+	sc.MockAdminConnector.MockSlowQueries = []db.SlowQuery{{
+		Collection: "versions",
+		Operation:  "find_all",
+		Pattern:    pattern.String(),
+		Duration:   2 * db.SlowQueryThreshold,
+		Time:       time.Now(),
+	}}
+
+	resp := makeFetchIndexReports(sc).Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	reports := resp.Data().([]model.APIIndexReport)
+	require.Len(reports, 1)
+	assert.Equal("versions", model.FromAPIString(reports[0].Collection))
+	require.Len(reports[0].Patterns, 1)
+	assert.Equal(3, reports[0].Patterns[0].Count)
+	assert.Equal(300.0, reports[0].Patterns[0].TotalMS)
+	require.Len(reports[0].MissingIndexes, 1)
+	assert.Equal([]string{"author", "-create_time"}, reports[0].MissingIndexes[0].Keys)
+	require.Len(reports[0].UnusedIndexes, 1)
+	assert.Equal("order_1", model.FromAPIString(reports[0].UnusedIndexes[0].Name))
+	assert.EqualValues(-1, reports[0].UnusedIndexes[0].Accesses)
+
+	resp = makeFetchSlowQueries(sc).Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	queries := resp.Data().([]model.APISlowQuery)
+	require.Len(queries, 1)
+	assert.Equal("find_all", model.FromAPIString(queries[0].Operation))
+	assert.Equal("{author: <value>} sort -create_time", model.FromAPIString(queries[0].Query))
+	assert.Equal(float64(2*db.SlowQueryThreshold/time.Millisecond), queries[0].DurationMS)
+
+	resp = makeResetQuerySamples(sc).Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	resp = makeFetchSlowQueries(sc).Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Len(resp.Data().([]model.APISlowQuery), 0)
+	resp = makeFetchIndexReports(sc).Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Len(resp.Data().([]model.APIIndexReport), 0)
+}
@@ -0,0 +1,158 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/event_webhooks
+
+type eventWebhooksGetHandler struct {
+	sc data.Connector
+}
+
+func makeFetchEventWebhooks(sc data.Connector) gimlet.RouteHandler {
+	return &eventWebhooksGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *eventWebhooksGetHandler) Factory() gimlet.RouteHandler {
+	return &eventWebhooksGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *eventWebhooksGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *eventWebhooksGetHandler) Run(ctx context.Context) gimlet.Responder {
+	webhooks, err := h.sc.FindEventWebhooks()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem fetching event webhooks"))
+	}
+
+	return gimlet.NewJSONResponse(webhooks)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/event_webhooks
+
+type eventWebhookPostHandler struct {
+	webhook model.APIEventWebhook
+
+	sc data.Connector
+}
+
+func makeCreateEventWebhook(sc data.Connector) gimlet.RouteHandler {
+	return &eventWebhookPostHandler{
+		sc: sc,
+	}
+}
+
+func (h *eventWebhookPostHandler) Factory() gimlet.RouteHandler {
+	return &eventWebhookPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *eventWebhookPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.webhook), "problem parsing request body")
+}
+
+func (h *eventWebhookPostHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	webhook, err := h.sc.CreateEventWebhook(&h.webhook, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem creating event webhook"))
+	}
+
+	return gimlet.NewJSONResponse(webhook)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/admin/event_webhooks/{webhook_id}
+
+type eventWebhookDeleteHandler struct {
+	id string
+
+	sc data.Connector
+}
+
+func makeDeleteEventWebhook(sc data.Connector) gimlet.RouteHandler {
+	return &eventWebhookDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (h *eventWebhookDeleteHandler) Factory() gimlet.RouteHandler {
+	return &eventWebhookDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *eventWebhookDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["webhook_id"]
+
+	return nil
+}
+
+func (h *eventWebhookDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.DeleteEventWebhook(h.id); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/event_webhooks/{webhook_id}/replay
+
+type eventWebhookReplayHandler struct {
+	id     string
+	replay model.APIEventWebhookReplay
+
+	sc data.Connector
+}
+
+func makeReplayEventWebhook(sc data.Connector) gimlet.RouteHandler {
+	return &eventWebhookReplayHandler{
+		sc: sc,
+	}
+}
+
+func (h *eventWebhookReplayHandler) Factory() gimlet.RouteHandler {
+	return &eventWebhookReplayHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *eventWebhookReplayHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["webhook_id"]
+	if err := gimlet.GetJSON(r.Body, &h.replay); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+
+	return nil
+}
+
+func (h *eventWebhookReplayHandler) Run(ctx context.Context) gimlet.Responder {
+	webhook, err := h.sc.ReplayEventWebhook(h.id, &h.replay)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(webhook)
+}
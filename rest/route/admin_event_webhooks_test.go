@@ -0,0 +1,91 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventWebhookRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	sc := &data.MockConnector{}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	// create
+	postHandler := makeCreateEventWebhook(sc)
+	body := []byte(`{"url": "https://example.com/events", "resource_types": ["TASK", "BUILD"], "event_types": ["TASK_FINISHED"]}`)
+	request, err := http.NewRequest("POST", "/admin/event_webhooks", bytes.NewBuffer(body))
+	require.NoError(err)
+	require.NoError(postHandler.Parse(ctx, request))
+	resp := postHandler.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	created, ok := resp.Data().(*model.APIEventWebhook)
+	require.True(ok)
+	assert.NotEmpty(model.FromAPIString(created.Secret))
+	assert.Equal("admin", model.FromAPIString(created.CreatedBy))
+	assert.Equal([]string{event.ResourceTypeTask, event.ResourceTypeBuild}, created.ResourceTypes)
+	id := model.FromAPIString(created.ID)
+
+	// invalid webhooks are rejected
+	postHandler = makeCreateEventWebhook(sc)
+	request, err = http.NewRequest("POST", "/admin/event_webhooks", bytes.NewBuffer([]byte(`{"url": "https://example.com", "resource_types": ["PATCH"]}`)))
+	require.NoError(err)
+	require.NoError(postHandler.Parse(ctx, request))
+	assert.Equal(http.StatusBadRequest, postHandler.Run(ctx).Status())
+
+	// list, without secrets
+	getHandler := makeFetchEventWebhooks(sc)
+	request, err = http.NewRequest("GET", "/admin/event_webhooks", nil)
+	require.NoError(err)
+	require.NoError(getHandler.Parse(ctx, request))
+	resp = getHandler.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	webhooks, ok := resp.Data().([]model.APIEventWebhook)
+	require.True(ok)
+	require.Len(webhooks, 1)
+	assert.Equal(id, model.FromAPIString(webhooks[0].ID))
+	assert.Nil(webhooks[0].Secret)
+
+	// replay
+	replayHandler := makeReplayEventWebhook(sc)
+	request, err = http.NewRequest("POST", "/admin/event_webhooks/"+id+"/replay", bytes.NewBuffer([]byte(`{"cursor": "e1"}`)))
+	require.NoError(err)
+	require.NoError(replayHandler.Parse(ctx, request))
+	replayHandler.(*eventWebhookReplayHandler).id = id
+	resp = replayHandler.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	replayed, ok := resp.Data().(*model.APIEventWebhook)
+	require.True(ok)
+	assert.Equal("e1", model.FromAPIString(replayed.Cursor.EventID))
+
+	replayHandler = makeReplayEventWebhook(sc)
+	request, err = http.NewRequest("POST", "/admin/event_webhooks/"+id+"/replay", bytes.NewBuffer([]byte(`{}`)))
+	require.NoError(err)
+	require.NoError(replayHandler.Parse(ctx, request))
+	replayHandler.(*eventWebhookReplayHandler).id = id
+	assert.Equal(http.StatusBadRequest, replayHandler.Run(ctx).Status())
+
+	// delete
+	deleteHandler := makeDeleteEventWebhook(sc)
+	request, err = http.NewRequest("DELETE", "/admin/event_webhooks/"+id, nil)
+	require.NoError(err)
+	require.NoError(deleteHandler.Parse(ctx, request))
+	deleteHandler.(*eventWebhookDeleteHandler).id = id
+	assert.Equal(http.StatusOK, deleteHandler.Run(ctx).Status())
+	assert.Empty(sc.MockAdminConnector.MockEventWebhooks)
+
+	deleteHandler = makeDeleteEventWebhook(sc)
+	require.NoError(deleteHandler.Parse(ctx, request))
+	deleteHandler.(*eventWebhookDeleteHandler).id = id
+	assert.Equal(http.StatusNotFound, deleteHandler.Run(ctx).Status())
+}
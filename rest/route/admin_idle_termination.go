@@ -0,0 +1,212 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/distros/{distro_id}/idle_policy
+
+type distroIdlePolicyGetHandler struct {
+	distroId string
+
+	sc data.Connector
+}
+
+func makeFetchDistroIdlePolicy(sc data.Connector) gimlet.RouteHandler {
+	return &distroIdlePolicyGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroIdlePolicyGetHandler) Factory() gimlet.RouteHandler {
+	return &distroIdlePolicyGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroIdlePolicyGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroId = gimlet.GetVars(r)["distro_id"]
+
+	return nil
+}
+
+func (h *distroIdlePolicyGetHandler) Run(ctx context.Context) gimlet.Responder {
+	policy, err := h.sc.GetDistroIdlePolicy(h.distroId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem fetching idle policy of distro '%s'", h.distroId))
+	}
+
+	apiPolicy := &model.APIDistroIdlePolicy{}
+	if err = apiPolicy.BuildFromService(policy); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(apiPolicy)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/admin/distros/{distro_id}/idle_policy
+
+type distroIdlePolicyPutHandler struct {
+	distroId string
+	policy   model.APIDistroIdlePolicy
+
+	sc data.Connector
+}
+
+func makeSetDistroIdlePolicy(sc data.Connector) gimlet.RouteHandler {
+	return &distroIdlePolicyPutHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroIdlePolicyPutHandler) Factory() gimlet.RouteHandler {
+	return &distroIdlePolicyPutHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroIdlePolicyPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, &h.policy); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	h.distroId = gimlet.GetVars(r)["distro_id"]
+
+	return nil
+}
+
+func (h *distroIdlePolicyPutHandler) Run(ctx context.Context) gimlet.Responder {
+	i, err := h.policy.ToService()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	if err = h.sc.SetDistroIdlePolicy(h.distroId, i.(distro.IdlePolicy)); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem setting idle policy of distro '%s'", h.distroId))
+	}
+
+	return gimlet.NewJSONResponse(&h.policy)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/distros/{distro_id}/parent_image/rollback
+
+type distroParentImageRollbackHandler struct {
+	distroId string
+
+	sc data.Connector
+}
+
+func makeRollBackDistroParentImage(sc data.Connector) gimlet.RouteHandler {
+	return &distroParentImageRollbackHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroParentImageRollbackHandler) Factory() gimlet.RouteHandler {
+	return &distroParentImageRollbackHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroParentImageRollbackHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroId = gimlet.GetVars(r)["distro_id"]
+
+	return nil
+}
+
+func (h *distroParentImageRollbackHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	d, err := h.sc.RollBackDistroParentImage(h.distroId, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem rolling back image of distro '%s'", h.distroId))
+	}
+
+	rollout := &model.APIParentImageRollout{}
+	if err = rollout.BuildFromService(d); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(rollout)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/hosts/{host_id}/drain
+// DELETE /rest/v2/admin/hosts/{host_id}/drain
+
+type hostDrainHandler struct {
+	hostId   string
+	draining bool
+
+	sc data.Connector
+}
+
+func makeDrainHost(sc data.Connector) gimlet.RouteHandler {
+	return &hostDrainHandler{
+		draining: true,
+		sc:       sc,
+	}
+}
+
+func makeUndrainHost(sc data.Connector) gimlet.RouteHandler {
+	return &hostDrainHandler{
+		draining: false,
+		sc:       sc,
+	}
+}
+
+func (h *hostDrainHandler) Factory() gimlet.RouteHandler {
+	return &hostDrainHandler{
+		draining: h.draining,
+		sc:       h.sc,
+	}
+}
+
+func (h *hostDrainHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.hostId = gimlet.GetVars(r)["host_id"]
+
+	return nil
+}
+
+func (h *hostDrainHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	foundHost, err := h.sc.FindHostById(h.hostId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding host '%s'", h.hostId))
+	}
+	if h.draining && foundHost.Status == evergreen.HostTerminated {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("host '%s' is terminated", foundHost.Id),
+		})
+	}
+
+	if foundHost.Draining != h.draining {
+		if err = h.sc.SetHostDraining(foundHost, h.draining, u.Username()); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrapf(err, "problem setting drain mode of host '%s'", foundHost.Id))
+		}
+	}
+
+	apiHost := &model.APIHost{}
+	if err = apiHost.BuildFromService(foundHost); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(apiHost)
+}
@@ -0,0 +1,119 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistroIdlePolicyRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockDistroConnector.CachedDistros = []distro.Distro{{Id: "d", PoolSize: 5}}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	put := func(distroId, body string) gimlet.Responder {
+		r, err := http.NewRequest(http.MethodPut, "/admin/distros/"+distroId+"/idle_policy", bytes.NewBufferString(body))
+		require.NoError(err)
+		h := makeSetDistroIdlePolicy(sc).(*distroIdlePolicyPutHandler)
+		require.NoError(h.Parse(ctx, r))
+		h.distroId = distroId
+		return h.Run(ctx)
+	}
+	assert.Equal(http.StatusNotFound, put("nonexistent", `{"min_pool_size": 1}`).Status())
+	assert.Equal(http.StatusBadRequest, put("d", `{"min_pool_size": 6}`).Status())
+	assert.Equal(http.StatusBadRequest, put("d", `{"scale_down_step": -1}`).Status())
+	require.Equal(http.StatusOK, put("d", `{"min_pool_size": 2, "max_idle_mins": 15, "scale_down_step": 1}`).Status())
+
+	get := makeFetchDistroIdlePolicy(sc).(*distroIdlePolicyGetHandler)
+	get.distroId = "d"
+	resp := get.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	policy, ok := resp.Data().(*model.APIDistroIdlePolicy)
+	require.True(ok)
+	assert.Equal(2, policy.MinPoolSize)
+	assert.Equal(15, policy.MaxIdleMins)
+	assert.Equal(1, policy.ScaleDownStep)
+
+	get.distroId = "nonexistent"
+	assert.Equal(http.StatusNotFound, get.Run(ctx).Status())
+}
+
+func TestDistroParentImageRollbackRoute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockDistroConnector.CachedDistros = []distro.Distro{
+		{
+			Id:                 "parent",
+			ProviderSettings:   &map[string]interface{}{distro.ParentImageSettingsKey: "ami-2"},
+			ParentImageRollout: distro.ParentImageRollout{PreviousImage: "ami-1"},
+		},
+	}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	h := makeRollBackDistroParentImage(sc).(*distroParentImageRollbackHandler)
+	h.distroId = "nonexistent"
+	assert.Equal(http.StatusNotFound, h.Run(ctx).Status())
+
+	h.distroId = "parent"
+	resp := h.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	rollout, ok := resp.Data().(*model.APIParentImageRollout)
+	require.True(ok)
+	assert.Equal("ami-1", model.FromAPIString(rollout.Image))
+	assert.Empty(model.FromAPIString(rollout.PreviousImage))
+	assert.Equal([]string{"ami-2"}, rollout.RejectedImages)
+
+	// there's nothing left to roll back to
+	assert.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+}
+
+func TestHostDrainRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockHostConnector.CachedHosts = []host.Host{
+		{Id: "running", Status: evergreen.HostRunning},
+		{Id: "terminated", Status: evergreen.HostTerminated},
+	}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	drain := makeDrainHost(sc).(*hostDrainHandler)
+	drain.hostId = "nonexistent"
+	assert.Equal(http.StatusNotFound, drain.Run(ctx).Status())
+	drain.hostId = "terminated"
+	assert.Equal(http.StatusBadRequest, drain.Run(ctx).Status())
+
+	drain.hostId = "running"
+	resp := drain.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	apiHost, ok := resp.Data().(*model.APIHost)
+	require.True(ok)
+	assert.True(apiHost.Draining)
+	assert.True(sc.MockHostConnector.CachedHosts[0].Draining)
+
+	undrain := makeUndrainHost(sc).Factory().(*hostDrainHandler)
+	undrain.hostId = "running"
+	resp = undrain.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	apiHost, ok = resp.Data().(*model.APIHost)
+	require.True(ok)
+	assert.False(apiHost.Draining)
+	assert.False(sc.MockHostConnector.CachedHosts[0].Draining)
+}
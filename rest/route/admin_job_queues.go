@@ -0,0 +1,199 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultQueueJobsLimit = 100
+	maxQueueJobsLimit     = 1000
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/queues/{queue}
+
+type jobQueueGetHandler struct {
+	queue string
+
+	sc data.Connector
+}
+
+func makeFetchJobQueue(sc data.Connector) gimlet.RouteHandler {
+	return &jobQueueGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *jobQueueGetHandler) Factory() gimlet.RouteHandler {
+	return &jobQueueGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *jobQueueGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.queue = gimlet.GetVars(r)["queue"]
+
+	return nil
+}
+
+func (h *jobQueueGetHandler) Run(ctx context.Context) gimlet.Responder {
+	stats, err := h.sc.GetJobQueueStats(h.queue)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem fetching job queue"))
+	}
+
+	return gimlet.NewJSONResponse(stats)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/queues/{queue}/jobs
+
+type queueJobsGetHandler struct {
+	queue   string
+	status  string
+	jobType string
+	limit   int
+
+	sc data.Connector
+}
+
+func makeFetchQueueJobs(sc data.Connector) gimlet.RouteHandler {
+	return &queueJobsGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *queueJobsGetHandler) Factory() gimlet.RouteHandler {
+	return &queueJobsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *queueJobsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.queue = gimlet.GetVars(r)["queue"]
+	vals := r.URL.Query()
+
+	h.status = vals.Get("status")
+	switch h.status {
+	case "", model.QueueJobStatusPending, model.QueueJobStatusInProgress, model.QueueJobStatusCompleted, model.QueueJobStatusErrored:
+	default:
+		return gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("invalid status '%s'", h.status),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	h.jobType = vals.Get("type")
+
+	h.limit = defaultQueueJobsLimit
+	if limit := vals.Get("limit"); limit != "" {
+		var err error
+		h.limit, err = strconv.Atoi(limit)
+		if err != nil || h.limit < 1 || h.limit > maxQueueJobsLimit {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("invalid limit '%s'", limit),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *queueJobsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	jobs, err := h.sc.FindQueueJobs(ctx, h.queue, h.status, h.jobType, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem fetching jobs"))
+	}
+
+	return gimlet.NewJSONResponse(jobs)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/queues/{queue}/jobs/{job_id}/abort
+
+type queueJobAbortHandler struct {
+	queue string
+	id    string
+
+	sc data.Connector
+}
+
+func makeAbortQueueJob(sc data.Connector) gimlet.RouteHandler {
+	return &queueJobAbortHandler{
+		sc: sc,
+	}
+}
+
+func (h *queueJobAbortHandler) Factory() gimlet.RouteHandler {
+	return &queueJobAbortHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *queueJobAbortHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.queue = vars["queue"]
+	h.id = vars["job_id"]
+
+	return nil
+}
+
+func (h *queueJobAbortHandler) Run(ctx context.Context) gimlet.Responder {
+	job, err := h.sc.AbortQueueJob(ctx, h.queue, h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem aborting job"))
+	}
+
+	return gimlet.NewJSONResponse(job)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/queues/{queue}/jobs/{job_id}/retry
+
+type queueJobRetryHandler struct {
+	queue string
+	id    string
+
+	sc data.Connector
+}
+
+func makeRetryQueueJob(sc data.Connector) gimlet.RouteHandler {
+	return &queueJobRetryHandler{
+		sc: sc,
+	}
+}
+
+func (h *queueJobRetryHandler) Factory() gimlet.RouteHandler {
+	return &queueJobRetryHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *queueJobRetryHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.queue = vars["queue"]
+	h.id = vars["job_id"]
+
+	return nil
+}
+
+func (h *queueJobRetryHandler) Run(ctx context.Context) gimlet.Responder {
+	job, err := h.sc.RetryQueueJob(h.queue, h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem retrying job"))
+	}
+
+	return gimlet.NewJSONResponse(job)
+}
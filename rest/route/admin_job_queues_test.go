@@ -0,0 +1,209 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/pool"
+	"github.com/mongodb/amboy/queue"
+	"github.com/mongodb/amboy/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const queueTestJobName = "rest-queue-test"
+
+func init() {
+	registry.AddJobType(queueTestJobName, func() amboy.Job { return makeQueueTestJob() })
+}
+
+type queueTestJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	Fail     bool `bson:"fail" json:"fail" yaml:"fail"`
+	Block    bool `bson:"block" json:"block" yaml:"block"`
+}
+
+func makeQueueTestJob() *queueTestJob {
+	j := &queueTestJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    queueTestJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+func newQueueTestJob(id string, fail, block bool) *queueTestJob {
+	j := makeQueueTestJob()
+	j.SetID(id)
+	j.Fail = fail
+	j.Block = block
+	return j
+}
+
+func (j *queueTestJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+	if j.Block {
+		<-ctx.Done()
+		return
+	}
+	if j.Fail {
+		j.AddError(assert.AnError)
+	}
+}
+
+// waitForQueue polls until the condition holds, reporting whether it did
+// before timing out.
+func waitForQueue(cond func() bool) bool {
+	timeout := time.After(5 * time.Second)
+	for !cond() {
+		select {
+		case <-timeout:
+			return false
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	return true
+}
+
+func TestJobQueueRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := queue.NewLocalUnordered(2)
+	runner := pool.NewAbortablePool(2, q)
+	require.NoError(q.SetRunner(runner))
+	require.NoError(q.Start(ctx))
+	require.NoError(q.Put(newQueueTestJob("succeeds", false, false)))
+	require.NoError(q.Put(newQueueTestJob("fails", true, false)))
+	require.NoError(q.Put(newQueueTestJob("blocks", false, true)))
+	require.True(waitForQueue(func() bool {
+		return q.Stats().Completed == 2 && runner.IsRunning("blocks")
+	}))
+
+	sc := &data.MockConnector{}
+	sc.MockAdminConnector.MockJobQueues = map[string]amboy.Queue{data.LocalJobQueue: q}
+
+	t.Run("Stats", func(t *testing.T) {
+		h := makeFetchJobQueue(sc).(*jobQueueGetHandler)
+		h.queue = data.LocalJobQueue
+		resp := h.Run(ctx)
+		require.Equal(http.StatusOK, resp.Status())
+		stats := resp.Data().(*model.APIJobQueueStats)
+		assert.Equal(data.LocalJobQueue, model.FromAPIString(stats.Name))
+		assert.Equal(3, stats.Total)
+		assert.Equal(2, stats.Completed)
+
+		h.queue = data.RemoteJobQueue
+		assert.Equal(http.StatusNotFound, h.Run(ctx).Status())
+	})
+	t.Run("ListJobs", func(t *testing.T) {
+		for status, ids := range map[string][]string{
+			"":                             {"succeeds", "fails", "blocks"},
+			model.QueueJobStatusCompleted:  {"succeeds"},
+			model.QueueJobStatusErrored:    {"fails"},
+			model.QueueJobStatusInProgress: {"blocks"},
+			model.QueueJobStatusPending:    {},
+		} {
+			h := makeFetchQueueJobs(sc).(*queueJobsGetHandler)
+			h.queue = data.LocalJobQueue
+			h.status = status
+			h.limit = defaultQueueJobsLimit
+			resp := h.Run(ctx)
+			require.Equal(http.StatusOK, resp.Status())
+			jobs := resp.Data().([]model.APIQueueJob)
+			found := []string{}
+			for _, j := range jobs {
+				found = append(found, model.FromAPIString(j.ID))
+			}
+			sort.Strings(ids)
+			sort.Strings(found)
+			assert.Equal(ids, found, status)
+		}
+
+		h := makeFetchQueueJobs(sc).(*queueJobsGetHandler)
+		h.queue = data.LocalJobQueue
+		h.limit = 1
+		resp := h.Run(ctx)
+		require.Equal(http.StatusOK, resp.Status())
+		assert.Len(resp.Data().([]model.APIQueueJob), 1)
+
+		h.jobType = "nonexistent"
+		h.limit = defaultQueueJobsLimit
+		resp = h.Run(ctx)
+		require.Equal(http.StatusOK, resp.Status())
+		assert.Empty(resp.Data().([]model.APIQueueJob))
+	})
+	t.Run("ParseListJobs", func(t *testing.T) {
+		for url, code := range map[string]int{
+			"/admin/queues/local/jobs":                   http.StatusOK,
+			"/admin/queues/local/jobs?status=errored":    http.StatusOK,
+			"/admin/queues/local/jobs?status=unknown":    http.StatusBadRequest,
+			"/admin/queues/local/jobs?limit=0":           http.StatusBadRequest,
+			"/admin/queues/local/jobs?limit=many":        http.StatusBadRequest,
+			"/admin/queues/local/jobs?limit=10&type=foo": http.StatusOK,
+		} {
+			h := makeFetchQueueJobs(sc)
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+			err := h.Parse(ctx, r)
+			if code == http.StatusOK {
+				assert.NoError(err, url)
+				continue
+			}
+			require.Error(err, url)
+			assert.Equal(code, err.(gimlet.ErrorResponse).StatusCode, url)
+		}
+	})
+	t.Run("Retry", func(t *testing.T) {
+		h := makeRetryQueueJob(sc).(*queueJobRetryHandler)
+		h.queue = data.LocalJobQueue
+		h.id = "succeeds"
+		assert.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+		h.id = "nonexistent"
+		assert.Equal(http.StatusNotFound, h.Run(ctx).Status())
+
+		h.id = "fails"
+		resp := h.Run(ctx)
+		require.Equal(http.StatusOK, resp.Status())
+		retry := resp.Data().(*model.APIQueueJob)
+		retryID := model.FromAPIString(retry.ID)
+		assert.Contains(retryID, "fails.retry-")
+		assert.Equal(queueTestJobName, model.FromAPIString(retry.Type))
+		assert.Empty(retry.Errors)
+
+		require.True(waitForQueue(func() bool {
+			j, ok := q.Get(retryID)
+			return ok && j.Status().Completed
+		}))
+		j, _ := q.Get(retryID)
+		assert.Len(j.Status().Errors, 1)
+		assert.True(j.(*queueTestJob).Fail)
+	})
+	t.Run("Abort", func(t *testing.T) {
+		h := makeAbortQueueJob(sc).(*queueJobAbortHandler)
+		h.queue = data.LocalJobQueue
+		h.id = "succeeds"
+		assert.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+
+		h.id = "blocks"
+		resp := h.Run(ctx)
+		require.Equal(http.StatusOK, resp.Status())
+		assert.Equal("blocks", model.FromAPIString(resp.Data().(*model.APIQueueJob).ID))
+		assert.False(runner.IsRunning("blocks"))
+	})
+}
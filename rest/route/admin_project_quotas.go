@@ -0,0 +1,135 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/project_quotas
+
+type projectQuotasGetHandler struct {
+	sc data.Connector
+}
+
+func makeFetchProjectQuotas(sc data.Connector) gimlet.RouteHandler {
+	return &projectQuotasGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectQuotasGetHandler) Factory() gimlet.RouteHandler {
+	return &projectQuotasGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectQuotasGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *projectQuotasGetHandler) Run(ctx context.Context) gimlet.Responder {
+	quotas, err := h.sc.FindProjectQuotas()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem fetching project quotas"))
+	}
+
+	return gimlet.NewJSONResponse(quotas)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/admin/project_quotas/{project_id}
+
+type projectQuotaPutHandler struct {
+	quota model.APIProjectQuota
+
+	sc data.Connector
+}
+
+func makeSetProjectQuota(sc data.Connector) gimlet.RouteHandler {
+	return &projectQuotaPutHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectQuotaPutHandler) Factory() gimlet.RouteHandler {
+	return &projectQuotaPutHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectQuotaPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, &h.quota); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	h.quota.Project = model.ToAPIString(gimlet.GetVars(r)["project_id"])
+
+	return nil
+}
+
+func (h *projectQuotaPutHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	project := model.FromAPIString(h.quota.Project)
+	projRef, err := h.sc.FindProjectByBranch(project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding project '%s'", project))
+	}
+	if projRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", project),
+		})
+	}
+
+	q, err := h.sc.SetProjectQuota(&h.quota, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem setting project quota"))
+	}
+
+	return gimlet.NewJSONResponse(q)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/admin/project_quotas/{project_id}
+
+type projectQuotaDeleteHandler struct {
+	project string
+
+	sc data.Connector
+}
+
+func makeDeleteProjectQuota(sc data.Connector) gimlet.RouteHandler {
+	return &projectQuotaDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectQuotaDeleteHandler) Factory() gimlet.RouteHandler {
+	return &projectQuotaDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectQuotaDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+
+	return nil
+}
+
+func (h *projectQuotaDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.DeleteProjectQuota(h.project); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
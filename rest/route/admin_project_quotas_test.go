@@ -0,0 +1,63 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectQuotaRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{
+		"mci": {Identifier: "mci"},
+	}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	put := func(project, body string) gimlet.Responder {
+		r, err := http.NewRequest(http.MethodPut, "/admin/project_quotas/"+project, bytes.NewBufferString(body))
+		require.NoError(err)
+		h := makeSetProjectQuota(sc).(*projectQuotaPutHandler)
+		require.NoError(h.Parse(ctx, r))
+		h.quota.Project = model.ToAPIString(project)
+		return h.Run(ctx)
+	}
+	assert.Equal(http.StatusNotFound, put("nonexistent", `{"weight": 2}`).Status())
+	assert.Equal(http.StatusBadRequest, put("mci", `{"weight": 0}`).Status())
+	assert.Equal(http.StatusBadRequest, put("mci", `{"weight": 1, "max_running_tasks": -1}`).Status())
+
+	resp := put("mci", `{"weight": 2, "max_running_tasks": 50}`)
+	require.Equal(http.StatusOK, resp.Status())
+	q, ok := resp.Data().(*model.APIProjectQuota)
+	require.True(ok)
+	assert.Equal("mci", model.FromAPIString(q.Project))
+	assert.Equal(2.0, q.Weight)
+	assert.Equal(50, q.MaxRunningTasks)
+	assert.Equal("admin", model.FromAPIString(q.UpdatedBy))
+
+	require.Equal(http.StatusOK, put("mci", `{"weight": 3}`).Status())
+	resp = makeFetchProjectQuotas(sc).Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	quotas, ok := resp.Data().([]model.APIProjectQuota)
+	require.True(ok)
+	require.Len(quotas, 1)
+	assert.Equal(3.0, quotas[0].Weight)
+	assert.Equal(0, quotas[0].MaxRunningTasks)
+
+	del := makeDeleteProjectQuota(sc).(*projectQuotaDeleteHandler)
+	del.project = "mci"
+	assert.Equal(http.StatusOK, del.Run(ctx).Status())
+	assert.Empty(sc.MockAdminConnector.MockProjectQuotas)
+	assert.Equal(http.StatusNotFound, del.Run(ctx).Status())
+}
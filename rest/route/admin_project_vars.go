@@ -0,0 +1,75 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/project_vars/{project_id}/migrate_secrets
+
+type projectVarsMigrateHandler struct {
+	project   string
+	migration model.APIProjectVarsMigration
+
+	sc data.Connector
+}
+
+func makeMigrateProjectVars(sc data.Connector) gimlet.RouteHandler {
+	return &projectVarsMigrateHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectVarsMigrateHandler) Factory() gimlet.RouteHandler {
+	return &projectVarsMigrateHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectVarsMigrateHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, &h.migration); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	h.project = gimlet.GetVars(r)["project_id"]
+
+	return nil
+}
+
+func (h *projectVarsMigrateHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, err := h.sc.FindProjectByBranch(h.project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding project '%s'", h.project))
+	}
+	if projRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", h.project),
+		})
+	}
+
+	before, resp := getProjectSettings(h.sc, h.project)
+	if resp != nil {
+		return resp
+	}
+	defer logProjectChange(ctx, h.sc, h.project, before)
+
+	migrated, err := h.sc.MigrateProjectVarsToSecrets(ctx, h.project, &h.migration)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem migrating variables of project '%s'", h.project))
+	}
+
+	h.migration.Migrated = make([]model.APIString, 0, len(migrated))
+	for _, name := range migrated {
+		h.migration.Migrated = append(h.migration.Migrated, model.ToAPIString(name))
+	}
+
+	return gimlet.NewJSONResponse(&h.migration)
+}
@@ -0,0 +1,55 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectVarsMigrateRoute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{
+		"mci": {Identifier: "mci"},
+	}
+	sc.MockProjectConnector.CachedVars = []*dbModel.ProjectVars{{
+		Id:          "mci",
+		Vars:        map[string]string{"a": "a", "b": "b"},
+		PrivateVars: map[string]bool{"b": true},
+	}}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	migrate := func(project, body string) gimlet.Responder {
+		r, err := http.NewRequest(http.MethodPost, "/admin/project_vars/"+project+"/migrate_secrets", bytes.NewBufferString(body))
+		require.NoError(err)
+		h := makeMigrateProjectVars(sc).(*projectVarsMigrateHandler)
+		require.NoError(h.Parse(ctx, r))
+		h.project = project
+		return h.Run(ctx)
+	}
+	assert.Equal(http.StatusNotFound, migrate("nonexistent", `{"backend": "vault"}`).Status())
+	assert.Equal(http.StatusBadRequest, migrate("mci", `{"backend": "keychain"}`).Status())
+
+	resp := migrate("mci", `{"backend": "vault", "private_only": true}`)
+	require.Equal(http.StatusOK, resp.Status())
+	migration, ok := resp.Data().(*model.APIProjectVarsMigration)
+	require.True(ok)
+	assert.Equal([]model.APIString{model.ToAPIString("b")}, migration.Migrated)
+
+	vars := sc.MockProjectConnector.CachedVars[0]
+	assert.Equal(map[string]string{"a": "a"}, vars.Vars)
+	assert.Empty(vars.PrivateVars)
+	assert.Equal(dbModel.SecretReference{Backend: thirdparty.SecretsBackendVault, Name: "mci/b"}, vars.SecretVars["b"])
+}
@@ -0,0 +1,67 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+func makeLoadRepotrackerFixture(sc data.Connector) gimlet.RouteHandler {
+	return &repotrackerFixturePostHandler{
+		sc: sc,
+	}
+}
+
+// repotrackerFixturePostHandler loads synthetic revisions into a project and
+// runs the repotracker against them, so that staging tests can exercise
+// ingestion, activation, and notifications without a real repository. It is
+// only enabled in non-production builds.
+type repotrackerFixturePostHandler struct {
+	projectID string
+	fixture   model.APIRepoTrackerFixture
+
+	sc data.Connector
+}
+
+func (h *repotrackerFixturePostHandler) Factory() gimlet.RouteHandler {
+	return &repotrackerFixturePostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *repotrackerFixturePostHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	if err := gimlet.GetJSON(r.Body, &h.fixture); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if len(h.fixture.Revisions) == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "fixture must contain at least one revision",
+		}
+	}
+	return nil
+}
+
+func (h *repotrackerFixturePostHandler) Run(ctx context.Context) gimlet.Responder {
+	if evergreen.TestFixturesEnabled != "true" {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Message:    "repotracker test fixtures are not allowed in production builds",
+		})
+	}
+
+	versionIDs, err := h.sc.LoadRepotrackerFixture(ctx, h.projectID, &h.fixture)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem loading fixture into project '%s'", h.projectID))
+	}
+
+	return gimlet.NewJSONResponse(struct {
+		Versions []string `json:"versions"`
+	}{versionIDs})
+}
@@ -0,0 +1,50 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRepotrackerFixtureRoute(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	defer func(enabled string) { evergreen.TestFixturesEnabled = enabled }(evergreen.TestFixturesEnabled)
+	evergreen.TestFixturesEnabled = ""
+
+	ctx := context.Background()
+	handler := makeLoadRepotrackerFixture(sc)
+	assert.NotNil(handler)
+
+	body := []byte(`{"config": "tasks: []", "revisions": [{"revision": "def"}, {"revision": "abc", "create_time": "2018-01-01T00:00:00.000Z"}]}`)
+	request, err := http.NewRequest("POST", "/admin/repotracker/fixtures/project", bytes.NewBuffer(body))
+	assert.NoError(err)
+	assert.NoError(handler.Parse(ctx, request))
+	h := handler.(*repotrackerFixturePostHandler)
+	h.projectID = "project"
+	assert.Len(h.fixture.Revisions, 2)
+
+	// fixtures are rejected in production builds
+	resp := handler.Run(ctx)
+	assert.NotNil(resp)
+	assert.Equal(http.StatusForbidden, resp.Status())
+
+	evergreen.TestFixturesEnabled = "true"
+	resp = handler.Run(ctx)
+	assert.NotNil(resp)
+	assert.Equal(http.StatusOK, resp.Status())
+	versions, ok := resp.Data().(struct {
+		Versions []string `json:"versions"`
+	})
+	assert.True(ok)
+	assert.Equal([]string{"project_def", "project_abc"}, versions.Versions)
+
+	request, err = http.NewRequest("POST", "/admin/repotracker/fixtures/project", bytes.NewBuffer([]byte(`{"revisions": []}`)))
+	assert.NoError(err)
+	assert.Error(makeLoadRepotrackerFixture(sc).Parse(ctx, request))
+}
@@ -0,0 +1,117 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/service_keys
+
+type serviceKeysGetHandler struct {
+	sc data.Connector
+}
+
+func makeFetchServiceKeys(sc data.Connector) gimlet.RouteHandler {
+	return &serviceKeysGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *serviceKeysGetHandler) Factory() gimlet.RouteHandler {
+	return &serviceKeysGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceKeysGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *serviceKeysGetHandler) Run(ctx context.Context) gimlet.Responder {
+	keys, err := h.sc.FindServiceKeys()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem fetching service keys"))
+	}
+
+	return gimlet.NewJSONResponse(keys)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/admin/service_keys
+
+type serviceKeyPostHandler struct {
+	key model.APIServiceKey
+
+	sc data.Connector
+}
+
+func makeCreateServiceKey(sc data.Connector) gimlet.RouteHandler {
+	return &serviceKeyPostHandler{
+		sc: sc,
+	}
+}
+
+func (h *serviceKeyPostHandler) Factory() gimlet.RouteHandler {
+	return &serviceKeyPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceKeyPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.key), "problem parsing request body")
+}
+
+func (h *serviceKeyPostHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	key, err := h.sc.CreateServiceKey(&h.key, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem creating service key"))
+	}
+
+	return gimlet.NewJSONResponse(key)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/admin/service_keys/{key_id}
+
+type serviceKeyDeleteHandler struct {
+	id string
+
+	sc data.Connector
+}
+
+func makeDeleteServiceKey(sc data.Connector) gimlet.RouteHandler {
+	return &serviceKeyDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (h *serviceKeyDeleteHandler) Factory() gimlet.RouteHandler {
+	return &serviceKeyDeleteHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *serviceKeyDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["key_id"]
+
+	return nil
+}
+
+func (h *serviceKeyDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	if err := h.sc.DeleteServiceKey(h.id); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
@@ -1,12 +1,17 @@
 package route
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -100,3 +105,143 @@ func (h *adminPostHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(h.model)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/admin/settings/{section_id}
+
+func makeFetchAdminSettingsSection(sc data.Connector) gimlet.RouteHandler {
+	return &adminSectionGetHandler{
+		sc: sc,
+	}
+}
+
+type adminSectionGetHandler struct {
+	sectionId string
+	sc        data.Connector
+}
+
+func (h *adminSectionGetHandler) Factory() gimlet.RouteHandler {
+	return &adminSectionGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *adminSectionGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.sectionId = gimlet.GetVars(r)["section_id"]
+	return nil
+}
+
+func (h *adminSectionGetHandler) Run(ctx context.Context) gimlet.Responder {
+	settings, err := h.sc.GetEvergreenSettings()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	settingsModel := model.NewConfigModel()
+	if err = settingsModel.BuildFromService(settings); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	section, ok := settingsModel.Section(h.sectionId)
+	if !ok {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("settings section '%s' not found", h.sectionId),
+		})
+	}
+
+	return gimlet.NewJSONResponse(section)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/admin/settings/{section_id}
+
+func makeSetAdminSettingsSection(sc data.Connector) gimlet.RouteHandler {
+	return &adminSectionPatchHandler{
+		sc: sc,
+	}
+}
+
+type adminSectionPatchHandler struct {
+	sectionId string
+	body      []byte
+	sc        data.Connector
+}
+
+func (h *adminSectionPatchHandler) Factory() gimlet.RouteHandler {
+	return &adminSectionPatchHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *adminSectionPatchHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.sectionId = gimlet.GetVars(r)["section_id"]
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	var err error
+	h.body, err = ioutil.ReadAll(body)
+	return errors.Wrap(err, "error reading request body")
+}
+
+// Run applies the fields present in the request body to the current
+// settings section, so that fields left out keep their values. Fields that
+// the section does not have and values of the wrong type are rejected.
+func (h *adminSectionPatchHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	oldSettings, err := h.sc.GetEvergreenSettings()
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error retrieving existing settings"))
+	}
+	settingsModel := model.NewConfigModel()
+	if err = settingsModel.BuildFromService(oldSettings); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	section, ok := settingsModel.Section(h.sectionId)
+	if !ok {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("settings section '%s' not found", h.sectionId),
+		})
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(h.body))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(section); err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid settings section '%s': %s", h.sectionId, err.Error()),
+		})
+	}
+	changes := &model.APIAdminSettings{}
+	if err = changes.SetSection(h.sectionId, section); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	newSettings, err := h.sc.SetEvergreenSettings(changes, oldSettings, u, false)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error applying new settings"))
+	}
+	err = newSettings.Validate()
+	if err == nil && h.sectionId == newSettings.ContainerPools.SectionId() {
+		err = distro.ValidateContainerPoolDistros(newSettings)
+	}
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "Validation error").Error(),
+		})
+	}
+
+	if newSettings, err = h.sc.SetEvergreenSettings(changes, oldSettings, u, true); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	settingsModel = model.NewConfigModel()
+	if err = settingsModel.BuildFromService(newSettings); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error building API model"))
+	}
+	section, _ = settingsModel.Section(h.sectionId)
+
+	return gimlet.NewJSONResponse(section)
+}
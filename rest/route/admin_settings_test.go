@@ -0,0 +1,66 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminSettingsSectionRoutes(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "root"})
+	sc := &data.MockConnector{}
+	sc.MockAdminConnector.MockSettings = testutil.MockConfig()
+	before := *sc.MockAdminConnector.MockSettings
+
+	get := func(section string) gimlet.Responder {
+		r, err := http.NewRequest("GET", "/admin/settings/"+section, nil)
+		require.NoError(t, err)
+		h := makeFetchAdminSettingsSection(sc).(*adminSectionGetHandler)
+		require.NoError(t, h.Parse(ctx, r))
+		h.sectionId = section
+		return h.Run(ctx)
+	}
+	patch := func(section, body string) gimlet.Responder {
+		r, err := http.NewRequest("PATCH", "/admin/settings/"+section, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		h := makeSetAdminSettingsSection(sc).(*adminSectionPatchHandler)
+		require.NoError(t, h.Parse(ctx, r))
+		h.sectionId = section
+		return h.Run(ctx)
+	}
+
+	resp := get("repotracker")
+	require.Equal(t, http.StatusOK, resp.Status())
+	repotracker, ok := resp.Data().(*model.APIRepoTrackerConfig)
+	require.True(t, ok)
+	assert.Equal(before.RepoTracker.MaxRepoRevisionsToSearch, repotracker.MaxRepoRevisionsToSearch)
+	assert.Equal(http.StatusNotFound, get("nope").Status())
+	assert.Equal(http.StatusNotFound, get("banner").Status())
+
+	resp = patch("repotracker", `{"max_revs_to_search": 200}`)
+	require.Equal(t, http.StatusOK, resp.Status())
+	repotracker, ok = resp.Data().(*model.APIRepoTrackerConfig)
+	require.True(t, ok)
+	assert.Equal(200, repotracker.MaxRepoRevisionsToSearch)
+	after := sc.MockAdminConnector.MockSettings
+	assert.Equal(200, after.RepoTracker.MaxRepoRevisionsToSearch)
+	assert.Equal(before.RepoTracker.NumNewRepoRevisionsToFetch, after.RepoTracker.NumNewRepoRevisionsToFetch)
+	assert.Equal(before.Jira.Username, after.Jira.Username)
+
+	assert.Equal(http.StatusBadRequest, patch("repotracker", `{"max_revs": 200}`).Status())
+	assert.Equal(http.StatusBadRequest, patch("repotracker", `{"max_revs_to_search": "many"}`).Status())
+	assert.Equal(http.StatusBadRequest, patch("scheduler", `{"task_finder": "nope"}`).Status())
+	assert.Equal(http.StatusNotFound, patch("nope", `{}`).Status())
+	assert.Equal(200, sc.MockAdminConnector.MockSettings.RepoTracker.MaxRepoRevisionsToSearch)
+}
@@ -2,8 +2,10 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
@@ -52,3 +54,181 @@ func (a *aliasGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return resp
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/projects/{project_id}/aliases/{alias}
+
+// aliasPutHandler replaces the definitions of a project's alias, each of
+// which selects the tasks and variants matching its regular expressions or
+// tags, so that patches created with the alias needn't list them.
+type aliasPutHandler struct {
+	project string
+	alias   string
+	aliases []model.APIAlias
+	sc      data.Connector
+}
+
+func makePutProjectAlias(sc data.Connector) gimlet.RouteHandler {
+	return &aliasPutHandler{
+		sc: sc,
+	}
+}
+
+func (a *aliasPutHandler) Factory() gimlet.RouteHandler {
+	return &aliasPutHandler{
+		sc: a.sc,
+	}
+}
+
+func (a *aliasPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	a.project = vars["project_id"]
+	a.alias = vars["alias"]
+	if err := gimlet.GetJSON(r.Body, &a.aliases); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if len(a.aliases) == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "alias must have at least one definition",
+		}
+	}
+
+	return nil
+}
+
+func (a *aliasPutHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, resp := findAdministeredProject(ctx, a.sc, a.project, "modify aliases")
+	if resp != nil {
+		return resp
+	}
+	before, resp := getProjectSettings(a.sc, projRef.Identifier)
+	if resp != nil {
+		return resp
+	}
+	defer logProjectChange(ctx, a.sc, projRef.Identifier, before)
+
+	aliases := []dbModel.ProjectAlias{}
+	for _, apiAlias := range a.aliases {
+		alias, err := apiAlias.ToService()
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		aliases = append(aliases, alias.(dbModel.ProjectAlias))
+	}
+	if err := a.sc.UpdateProjectAlias(projRef.Identifier, a.alias, aliases); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem updating alias '%s'", a.alias))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/projects/{project_id}/aliases/{alias}
+
+type aliasDeleteHandler struct {
+	project string
+	alias   string
+	sc      data.Connector
+}
+
+func makeDeleteProjectAlias(sc data.Connector) gimlet.RouteHandler {
+	return &aliasDeleteHandler{
+		sc: sc,
+	}
+}
+
+func (a *aliasDeleteHandler) Factory() gimlet.RouteHandler {
+	return &aliasDeleteHandler{
+		sc: a.sc,
+	}
+}
+
+func (a *aliasDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	a.project = vars["project_id"]
+	a.alias = vars["alias"]
+	return nil
+}
+
+func (a *aliasDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, resp := findAdministeredProject(ctx, a.sc, a.project, "modify aliases")
+	if resp != nil {
+		return resp
+	}
+	before, resp := getProjectSettings(a.sc, projRef.Identifier)
+	if resp != nil {
+		return resp
+	}
+	defer logProjectChange(ctx, a.sc, projRef.Identifier, before)
+
+	if err := a.sc.DeleteProjectAlias(projRef.Identifier, a.alias); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem deleting alias '%s'", a.alias))
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/aliases/{alias}/resolve
+
+// aliasResolveHandler returns the variants and tasks that a patch created
+// with the alias would run, at a revision of the project, or at its last
+// known good configuration.
+type aliasResolveHandler struct {
+	project  string
+	alias    string
+	revision string
+	sc       data.Connector
+}
+
+func makeResolveProjectAlias(sc data.Connector) gimlet.RouteHandler {
+	return &aliasResolveHandler{
+		sc: sc,
+	}
+}
+
+func (a *aliasResolveHandler) Factory() gimlet.RouteHandler {
+	return &aliasResolveHandler{
+		sc: a.sc,
+	}
+}
+
+func (a *aliasResolveHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	a.project = vars["project_id"]
+	a.alias = vars["alias"]
+	a.revision = r.URL.Query().Get("revision")
+	return nil
+}
+
+func (a *aliasResolveHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, err := a.sc.FindProjectByBranch(a.project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding project '%s'", a.project))
+	}
+	if projRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", a.project),
+		})
+	}
+
+	variantTasks, err := a.sc.ResolveProjectAlias(projRef, a.revision, a.alias)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem resolving alias '%s'", a.alias))
+	}
+
+	resolved := &model.APIResolvedAlias{
+		Alias:   model.ToAPIString(a.alias),
+		Project: model.ToAPIString(projRef.Identifier),
+	}
+	if err = resolved.BuildFromService(variantTasks); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(resolved)
+}
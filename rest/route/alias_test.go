@@ -0,0 +1,115 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProjectAliasRoutesSuite struct {
+	sc *data.MockConnector
+	suite.Suite
+}
+
+func TestProjectAliasRoutesSuite(t *testing.T) {
+	suite.Run(t, new(ProjectAliasRoutesSuite))
+}
+
+func (s *ProjectAliasRoutesSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*dbModel.ProjectRef{
+				"mci": {Identifier: "mci", Admins: []string{"admin"}},
+			},
+		},
+		MockAliasConnector: data.MockAliasConnector{
+			CachedAliases: []dbModel.ProjectAlias{
+				{ProjectID: "mci", Alias: "smoke", Variant: ".*", Task: "old"},
+				{ProjectID: "mci", Alias: "lint", Variant: ".*", Task: "lint"},
+			},
+			CachedResolvedAliases: map[string][]patch.VariantTasks{
+				"smoke": {
+					{Variant: "ubuntu", Tasks: []string{"compile", "smoke"}, DisplayTasks: []patch.DisplayTask{{Name: "tests"}}},
+				},
+			},
+		},
+	}
+}
+
+func (s *ProjectAliasRoutesSuite) TestPut() {
+	body, err := json.Marshal([]model.APIAlias{
+		{Variant: model.ToAPIString("^ubuntu"), Task: model.ToAPIString("^smoke")},
+		{Variant: model.ToAPIString("^rhel"), Tags: []model.APIString{model.ToAPIString("smoke")}},
+	})
+	s.Require().NoError(err)
+	r, err := http.NewRequest(http.MethodPut, "/projects/mci/aliases/smoke", bytes.NewReader(body))
+	s.Require().NoError(err)
+
+	rh := makePutProjectAlias(s.sc).(*aliasPutHandler)
+	s.Require().NoError(rh.Parse(context.Background(), r))
+	rh.project = "mci"
+	rh.alias = "smoke"
+
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "someone"})
+	s.Equal(http.StatusUnauthorized, rh.Run(ctx).Status())
+
+	ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+	s.Equal(http.StatusOK, rh.Run(ctx).Status())
+	aliases, err := s.sc.FindProjectAliases("mci")
+	s.NoError(err)
+	s.Require().Len(aliases, 3)
+	s.Equal("lint", aliases[0].Alias)
+	s.Equal("smoke", aliases[1].Alias)
+	s.Equal("^ubuntu", aliases[1].Variant)
+	s.Equal([]string{"smoke"}, aliases[2].Tags)
+
+	rh.aliases = []model.APIAlias{{Variant: model.ToAPIString("(")}}
+	s.Equal(http.StatusBadRequest, rh.Run(ctx).Status())
+}
+
+func (s *ProjectAliasRoutesSuite) TestDelete() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+	rh := makeDeleteProjectAlias(s.sc).(*aliasDeleteHandler)
+	rh.project = "mci"
+	rh.alias = "smoke"
+	s.Equal(http.StatusOK, rh.Run(ctx).Status())
+
+	aliases, err := s.sc.FindProjectAliases("mci")
+	s.NoError(err)
+	s.Require().Len(aliases, 1)
+	s.Equal("lint", aliases[0].Alias)
+
+	rh.project = "missing"
+	s.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+}
+
+func (s *ProjectAliasRoutesSuite) TestResolve() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "someone"})
+	rh := makeResolveProjectAlias(s.sc).(*aliasResolveHandler)
+	rh.project = "mci"
+	rh.alias = "smoke"
+
+	resp := rh.Run(ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	resolved, ok := resp.Data().(*model.APIResolvedAlias)
+	s.Require().True(ok)
+	s.Equal("smoke", model.FromAPIString(resolved.Alias))
+	s.Equal("mci", model.FromAPIString(resolved.Project))
+	s.Require().Len(resolved.Variants, 1)
+	s.Equal("ubuntu", model.FromAPIString(resolved.Variants[0].Name))
+	s.Len(resolved.Variants[0].Tasks, 2)
+	s.Equal("tests", model.FromAPIString(resolved.Variants[0].DisplayTasks[0]))
+
+	rh.alias = "lint"
+	s.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+}
@@ -0,0 +1,193 @@
+package route
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+const (
+	badgeLabel        = "evergreen"
+	badgeContentType  = "image/svg+xml; charset=utf-8"
+	badgeCacheControl = "no-cache, no-store, must-revalidate"
+
+	// badgeCharWidth and badgePadding estimate the width of the badge's
+	// text, since it is rendered by the browser.
+	badgeCharWidth = 7
+	badgePadding   = 10
+)
+
+// badge is the status of a project, or one of its variants, as shown in a
+// badge.
+type badge struct {
+	Label   string
+	Message string
+	// Color is the shields.io color name, and Hex is the color that an
+	// SVG badge is filled with.
+	Color string
+	Hex   string
+	// IsError is whether the status couldn't be found, which shields.io
+	// shows differently from a status.
+	IsError bool
+}
+
+var (
+	badgePassing  = badge{Message: "passing", Color: "brightgreen", Hex: "#4c1"}
+	badgeFailing  = badge{Message: "failing", Color: "red", Hex: "#e05d44"}
+	badgeUnknown  = badge{Message: "unknown", Color: "lightgrey", Hex: "#9f9f9f"}
+	badgeNotFound = badge{Message: "not found", Color: "lightgrey", Hex: "#9f9f9f", IsError: true}
+	badgeError    = badge{Message: "error", Color: "lightgrey", Hex: "#9f9f9f", IsError: true}
+)
+
+var badgeTemplate = template.Must(template.New("badge").Parse(
+	`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">` +
+		`<title>{{.Label}}: {{.Message}}</title>` +
+		`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` +
+		`<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>` +
+		`<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="20" fill="#555"/>` +
+		`<rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Hex}}"/>` +
+		`<rect width="{{.Width}}" height="20" fill="url(#s)"/></g>` +
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` +
+		`<text x="{{.LabelX}}" y="14">{{.Label}}</text>` +
+		`<text x="{{.MessageX}}" y="14">{{.Message}}</text></g></svg>`))
+
+// svg renders the badge in the flat style of shields.io.
+func (b badge) svg() ([]byte, error) {
+	labelWidth := len(b.Label)*badgeCharWidth + 2*badgePadding
+	messageWidth := len(b.Message)*badgeCharWidth + 2*badgePadding
+
+	out := &bytes.Buffer{}
+	err := badgeTemplate.Execute(out, struct {
+		badge
+		Width        int
+		LabelWidth   int
+		MessageWidth int
+		LabelX       int
+		MessageX     int
+	}{
+		badge:        b,
+		Width:        labelWidth + messageWidth,
+		LabelWidth:   labelWidth,
+		MessageWidth: messageWidth,
+		LabelX:       labelWidth / 2,
+		MessageX:     labelWidth + messageWidth/2,
+	})
+	return out.Bytes(), err
+}
+
+// shieldsBadge is the response that a shields.io endpoint badge is rendered
+// from.
+type shieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	IsError       bool   `json:"isError,omitempty"`
+}
+
+// getProjectBadge returns the badge of the project, or of a variant of it
+// if the request names one, labeled with the variant.
+func getProjectBadge(sc data.Connector, r *http.Request) badge {
+	projectId := gimlet.GetVars(r)["project_id"]
+	variant := r.FormValue("variant")
+
+	b := findProjectBadge(sc, projectId, variant)
+	b.Label = badgeLabel
+	if variant != "" {
+		b.Label = variant
+	}
+	return b
+}
+
+// findProjectBadge returns the badge for the status of the project's latest
+// mainline version, or of the variant's latest mainline build. Private
+// projects are not found, since badges are fetched without credentials.
+func findProjectBadge(sc data.Connector, projectId, variant string) badge {
+	ref, err := sc.FindProjectByBranch(projectId)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "problem finding project for badge",
+			"project": projectId,
+		}))
+		return badgeError
+	}
+	if ref == nil || ref.Private {
+		return badgeNotFound
+	}
+
+	status, err := sc.FindLatestMainlineStatus(projectId, variant)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "problem finding status for badge",
+			"project": projectId,
+			"variant": variant,
+		}))
+		return badgeError
+	}
+	switch status {
+	case evergreen.BuildSucceeded:
+		return badgePassing
+	case evergreen.BuildFailed:
+		return badgeFailing
+	default:
+		return badgeUnknown
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/badge
+
+// makeFetchProjectBadge returns a handler that renders the status of the
+// project's latest mainline version, or of a variant's latest mainline
+// build, as an SVG badge for READMEs to embed.
+func makeFetchProjectBadge(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b := getProjectBadge(sc, r)
+		out, err := b.svg()
+		if err != nil {
+			gimlet.WriteResponse(w, gimlet.MakeJSONInternalErrorResponder(err))
+			return
+		}
+
+		w.Header().Set("Content-Type", badgeContentType)
+		w.Header().Set("Cache-Control", badgeCacheControl)
+		if b.Message == badgeNotFound.Message {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		_, err = w.Write(out)
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem writing badge",
+			"path":    r.URL.Path,
+		}))
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/badge/shields
+
+// makeFetchProjectShieldsBadge returns a handler that describes the same
+// status as the SVG badge for a shields.io endpoint badge. Errors are
+// described in the response rather than by its status code, since
+// shields.io doesn't render error responses.
+func makeFetchProjectShieldsBadge(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		b := getProjectBadge(sc, r)
+
+		w.Header().Set("Cache-Control", badgeCacheControl)
+		gimlet.WriteJSON(w, shieldsBadge{
+			SchemaVersion: 1,
+			Label:         b.Label,
+			Message:       b.Message,
+			Color:         b.Color,
+			IsError:       b.IsError,
+		})
+	}
+}
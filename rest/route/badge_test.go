@@ -0,0 +1,91 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectBadgeRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockBuildConnector.CachedProjects = map[string]*model.ProjectRef{
+		"proj":    {Identifier: "proj"},
+		"private": {Identifier: "private", Private: true},
+	}
+	sc.MockBuildConnector.CachedBuilds = []build.Build{
+		{Id: "b1", Project: "proj", BuildVariant: "bv1", RevisionOrderNumber: 1, Status: evergreen.BuildFailed, Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "b2", Project: "proj", BuildVariant: "bv1", RevisionOrderNumber: 2, Status: evergreen.BuildSucceeded, Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "b3", Project: "proj", BuildVariant: "bv2", RevisionOrderNumber: 2, Status: evergreen.BuildFailed, Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "b4", Project: "proj", BuildVariant: "bv1", RevisionOrderNumber: 3, Status: evergreen.BuildFailed, Requester: evergreen.PatchVersionRequester},
+		{Id: "b5", Project: "proj", BuildVariant: "bv1", RevisionOrderNumber: 3, Status: evergreen.BuildStarted, Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "b6", Project: "private", BuildVariant: "bv1", RevisionOrderNumber: 1, Status: evergreen.BuildSucceeded, Requester: evergreen.RepotrackerVersionRequester},
+	}
+
+	app := gimlet.NewApp()
+	app.AddRoute("/projects/{project_id}/badge").Version(2).Get().Handler(makeFetchProjectBadge(sc))
+	app.AddRoute("/projects/{project_id}/badge/shields").Version(2).Get().Handler(makeFetchProjectShieldsBadge(sc))
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(err)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+	getShields := func(path string) shieldsBadge {
+		rw := get(path)
+		require.Equal(http.StatusOK, rw.Code, path)
+		out := shieldsBadge{}
+		require.NoError(json.Unmarshal(rw.Body.Bytes(), &out))
+		return out
+	}
+
+	rw := get("/v2/projects/proj/badge")
+	require.Equal(http.StatusOK, rw.Code)
+	assert.Equal(badgeContentType, rw.Header().Get("Content-Type"))
+	assert.Equal(badgeCacheControl, rw.Header().Get("Cache-Control"))
+	assert.Contains(rw.Body.String(), "<svg")
+	assert.Contains(rw.Body.String(), ">evergreen</text>")
+	assert.Contains(rw.Body.String(), ">failing</text>")
+	assert.Contains(rw.Body.String(), badgeFailing.Hex)
+
+	rw = get("/v2/projects/proj/badge?variant=bv1")
+	require.Equal(http.StatusOK, rw.Code)
+	assert.Contains(rw.Body.String(), ">bv1</text>")
+	assert.Contains(rw.Body.String(), ">passing</text>")
+
+	rw = get("/v2/projects/private/badge")
+	assert.Equal(http.StatusNotFound, rw.Code)
+	assert.Contains(rw.Body.String(), ">not found</text>")
+	assert.Equal(http.StatusNotFound, get("/v2/projects/none/badge").Code)
+
+	shields := getShields("/v2/projects/proj/badge/shields?variant=bv1")
+	assert.Equal(1, shields.SchemaVersion)
+	assert.Equal("bv1", shields.Label)
+	assert.Equal("passing", shields.Message)
+	assert.Equal("brightgreen", shields.Color)
+	assert.False(shields.IsError)
+
+	shields = getShields("/v2/projects/proj/badge/shields?variant=bv3")
+	assert.Equal("unknown", shields.Message)
+	assert.False(shields.IsError)
+
+	shields = getShields("/v2/projects/private/badge/shields")
+	assert.Equal(badgeLabel, shields.Label)
+	assert.Equal("not found", shields.Message)
+	assert.True(shields.IsError)
+}
@@ -21,6 +21,7 @@ import (
 
 type buildGetHandler struct {
 	buildId string
+	fields  model.FieldSelection
 	sc      data.Connector
 }
 
@@ -38,7 +39,10 @@ func (b *buildGetHandler) Factory() gimlet.RouteHandler {
 
 func (b *buildGetHandler) Parse(ctx context.Context, r *http.Request) error {
 	b.buildId = gimlet.GetVars(r)["build_id"]
-	return nil
+
+	var err error
+	b.fields, err = getFieldSelection(r.URL.Query(), model.APIBuild{})
+	return err
 }
 
 func (b *buildGetHandler) Run(ctx context.Context) gimlet.Responder {
@@ -62,7 +66,7 @@ func (b *buildGetHandler) Run(ctx context.Context) gimlet.Responder {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "API model error"))
 	}
 
-	return gimlet.NewJSONResponse(buildModel)
+	return projectResponse(gimlet.NewJSONResponse(buildModel), b.fields)
 }
 
 ////////////////////////////////////////////////////////////////////////
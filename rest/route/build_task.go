@@ -3,6 +3,7 @@ package route
 import (
 	"context"
 	"net/http"
+	"net/url"
 
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/data"
@@ -18,6 +19,8 @@ type tasksByBuildHandler struct {
 	sc                 data.Connector
 	limit              int
 	key                string
+	fields             model.FieldSelection
+	page               cursorPage
 }
 
 func makeFetchTasksByBuild(sc data.Connector) gimlet.RouteHandler {
@@ -35,7 +38,6 @@ func (tbh *tasksByBuildHandler) Factory() gimlet.RouteHandler {
 }
 
 func (tbh *tasksByBuildHandler) Parse(ctx context.Context, r *http.Request) error {
-	vals := r.URL.Query()
 	tbh.buildId = gimlet.GetVars(r)["build_id"]
 	if tbh.buildId == "" {
 		return gimlet.ErrorResponse{
@@ -44,18 +46,26 @@ func (tbh *tasksByBuildHandler) Parse(ctx context.Context, r *http.Request) erro
 		}
 	}
 
+	var (
+		vals url.Values
+		err  error
+	)
+	tbh.page, tbh.limit, vals, err = getCursorPage(r, defaultLimit, 0, "start_at")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	tbh.status = vals.Get("status")
+	// start_at is the key of the paginator that preceded cursors
 	tbh.key = vals.Get("start_at")
-
-	var err error
-	tbh.limit, err = getLimit(vals)
-	if err != nil {
+	if _, err = tbh.page.Start(&tbh.key); err != nil {
 		return errors.WithStack(err)
 	}
 
 	_, tbh.fetchAllExecutions = vals["fetch_all_executions"]
 
-	return nil
+	tbh.fields, err = getFieldSelection(vals, model.APITask{})
+	return err
 }
 
 func (tbh *tasksByBuildHandler) Run(ctx context.Context) gimlet.Responder {
@@ -75,19 +85,8 @@ func (tbh *tasksByBuildHandler) Run(ctx context.Context) gimlet.Responder {
 	lastIndex := len(tasks)
 	if len(tasks) > tbh.limit {
 		lastIndex = tbh.limit
-		err = resp.SetPages(&gimlet.ResponsePages{
-			Next: &gimlet.Page{
-				Relation:        "next",
-				LimitQueryParam: "limit",
-				KeyQueryParam:   "start_at",
-				BaseURL:         tbh.sc.GetURL(),
-				Key:             tasks[tbh.limit].Id,
-				Limit:           tbh.limit,
-			},
-		})
-		if err != nil {
-			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err,
-				"problem paginating response"))
+		if err = tbh.page.SetNext(resp, tbh.sc.GetURL(), tbh.limit, tasks[tbh.limit].Id); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
 		}
 	}
 
@@ -99,15 +98,17 @@ func (tbh *tasksByBuildHandler) Run(ctx context.Context) gimlet.Responder {
 			return gimlet.MakeJSONErrorResponder(err)
 		}
 
-		if err = taskModel.GetArtifacts(); err != nil {
-			return gimlet.MakeJSONErrorResponder(err)
+		if tbh.fields.Includes("artifacts") {
+			if err = taskModel.GetArtifacts(); err != nil {
+				return gimlet.MakeJSONErrorResponder(err)
+			}
 		}
 
 		if err = taskModel.BuildFromService(tbh.sc.GetURL()); err != nil {
 			return gimlet.MakeJSONErrorResponder(err)
 		}
 
-		if tbh.fetchAllExecutions {
+		if tbh.fetchAllExecutions && tbh.fields.Includes("previous_executions") {
 			var oldTasks []task.Task
 
 			oldTasks, err = tbh.sc.FindOldTasksByIDWithDisplayTasks(tasks[i].Id)
@@ -125,5 +126,5 @@ func (tbh *tasksByBuildHandler) Run(ctx context.Context) gimlet.Responder {
 		}
 	}
 
-	return resp
+	return projectResponse(resp, tbh.fields)
 }
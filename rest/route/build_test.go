@@ -80,6 +80,30 @@ func (s *BuildByIdSuite) TestFindByIdFail() {
 	s.NotEqual(resp.Status(), http.StatusOK)
 }
 
+func (s *BuildByIdSuite) TestFindByIdSelectFields() {
+	r, err := http.NewRequest("GET", "/builds/build1?fields=_id,project_id", nil)
+	s.Require().NoError(err)
+	s.NoError(s.rm.Parse(context.TODO(), r))
+	s.rm.(*buildGetHandler).buildId = "build1"
+
+	resp := s.rm.Run(context.TODO())
+	s.Equal(http.StatusOK, resp.Status())
+	b, ok := resp.Data().(map[string]interface{})
+	s.Require().True(ok)
+	s.Len(b, 2)
+	s.Equal(model.ToAPIString("build1"), b["_id"])
+	s.Equal(model.ToAPIString("project"), b["project_id"])
+}
+
+func (s *BuildByIdSuite) TestSelectUnknownFields() {
+	r, err := http.NewRequest("GET", "/builds/build1?fields=_id,nonexistent", nil)
+	s.Require().NoError(err)
+	err = s.rm.Parse(context.TODO(), r)
+	s.Require().Error(err)
+	s.Contains(err.Error(), "nonexistent")
+	s.Equal(http.StatusBadRequest, err.(gimlet.ErrorResponse).StatusCode)
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // Tests for change build status by id
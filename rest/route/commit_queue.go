@@ -0,0 +1,189 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/commit_queue/{project_id}
+
+type commitQueueGetHandler struct {
+	project string
+
+	sc data.Connector
+}
+
+func makeGetCommitQueueItems(sc data.Connector) gimlet.RouteHandler {
+	return &commitQueueGetHandler{
+		sc: sc,
+	}
+}
+
+func (cq *commitQueueGetHandler) Factory() gimlet.RouteHandler {
+	return &commitQueueGetHandler{
+		sc: cq.sc,
+	}
+}
+
+func (cq *commitQueueGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	cq.project = gimlet.GetVars(r)["project_id"]
+	return nil
+}
+
+func (cq *commitQueueGetHandler) Run(ctx context.Context) gimlet.Responder {
+	if resp := checkProjectVisible(ctx, cq.sc, cq.project, "view the commit queue"); resp != nil {
+		return resp
+	}
+
+	commitQueue, err := cq.sc.GetCommitQueue(cq.project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "can't get commit queue"))
+	}
+
+	return gimlet.NewJSONResponse(commitQueue)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/commit_queue/{project_id}/{item}
+
+type commitQueueEnqueueItemHandler struct {
+	project string
+	item    string
+
+	sc data.Connector
+}
+
+func makeCommitQueueEnqueueItem(sc data.Connector) gimlet.RouteHandler {
+	return &commitQueueEnqueueItemHandler{
+		sc: sc,
+	}
+}
+
+func (cq *commitQueueEnqueueItemHandler) Factory() gimlet.RouteHandler {
+	return &commitQueueEnqueueItemHandler{
+		sc: cq.sc,
+	}
+}
+
+func (cq *commitQueueEnqueueItemHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	cq.project = vars["project_id"]
+	cq.item = vars["item"]
+	return nil
+}
+
+func (cq *commitQueueEnqueueItemHandler) Run(ctx context.Context) gimlet.Responder {
+	if _, resp := findProjectWithRole(ctx, cq.sc, cq.project, user.RoleContributor, "add items to the commit queue"); resp != nil {
+		return resp
+	}
+
+	u := MustHaveUser(ctx)
+	// users can only merge their own patches
+	if bson.IsObjectIdHex(cq.item) {
+		p, err := cq.sc.FindPatchById(cq.item)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "can't find patch '%s'", cq.item))
+		}
+		if p == nil {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("patch '%s' not found", cq.item),
+			})
+		}
+		if p.Author != u.Username() {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusUnauthorized,
+				Message:    fmt.Sprintf("cannot add patch '%s' to the commit queue, because it belongs to '%s'", cq.item, p.Author),
+			})
+		}
+	}
+
+	position, err := cq.sc.EnqueueItem(cq.project, model.APICommitQueueItem{
+		Issue:  model.ToAPIString(cq.item),
+		Author: model.ToAPIString(u.Username()),
+	})
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "can't enqueue item"))
+	}
+
+	return gimlet.NewJSONResponse(model.APICommitQueuePosition{Position: position})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// DELETE /rest/v2/commit_queue/{project_id}/{item}
+
+type commitQueueDeleteItemHandler struct {
+	project string
+	item    string
+
+	sc data.Connector
+}
+
+func makeDeleteCommitQueueItems(sc data.Connector) gimlet.RouteHandler {
+	return &commitQueueDeleteItemHandler{
+		sc: sc,
+	}
+}
+
+func (cq *commitQueueDeleteItemHandler) Factory() gimlet.RouteHandler {
+	return &commitQueueDeleteItemHandler{
+		sc: cq.sc,
+	}
+}
+
+func (cq *commitQueueDeleteItemHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	cq.project = vars["project_id"]
+	cq.item = vars["item"]
+	return nil
+}
+
+func (cq *commitQueueDeleteItemHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, resp := findProject(cq.sc, cq.project)
+	if resp != nil {
+		return resp
+	}
+
+	// only the admins of the project can remove other users' items
+	commitQueue, err := cq.sc.GetCommitQueue(cq.project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "can't get commit queue"))
+	}
+	ownItem := false
+	for _, item := range commitQueue.Queue {
+		if model.FromAPIString(item.Issue) == cq.item {
+			ownItem = model.FromAPIString(item.Author) == MustHaveUser(ctx).Username()
+			break
+		}
+	}
+	if !ownItem {
+		if err = checkProjectRole(ctx, cq.sc, projRef, user.RoleProjectAdmin, "remove other users' items from the commit queue"); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+	}
+
+	found, err := cq.sc.CommitQueueRemoveItem(cq.project, cq.item)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "can't delete item"))
+	}
+	if !found {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("item '%s' not found in the commit queue for '%s'", cq.item, cq.project),
+		})
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
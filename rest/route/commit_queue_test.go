@@ -0,0 +1,94 @@
+package route
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestCommitQueueRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	patchID := bson.NewObjectId()
+	sc := &data.MockConnector{}
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{
+		"mci": &dbModel.ProjectRef{
+			Identifier:   "mci",
+			Admins:       []string{"admin"},
+			Contributors: []string{"me", "you"},
+		},
+		"secret": &dbModel.ProjectRef{
+			Identifier:   "secret",
+			Private:      true,
+			Contributors: []string{"me"},
+			Viewers:      []string{"viewer"},
+		},
+	}
+	sc.MockPatchConnector.CachedPatches = []patch.Patch{
+		{Id: patchID, Author: "me", Project: "mci"},
+		{Id: bson.NewObjectId(), Author: "you", Project: "mci"},
+	}
+	app := gimlet.NewApp()
+	app.AddRoute("/commit_queue/{project_id}").Version(2).Get().RouteHandler(makeGetCommitQueueItems(sc))
+	app.AddRoute("/commit_queue/{project_id}/{item}").Version(2).Put().RouteHandler(makeCommitQueueEnqueueItem(sc))
+	app.AddRoute("/commit_queue/{project_id}/{item}").Version(2).Delete().RouteHandler(makeDeleteCommitQueueItems(sc))
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	do := func(method, path, username string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest(method, path, nil)
+		require.NoError(err)
+		r = r.WithContext(gimlet.AttachUser(r.Context(), &user.DBUser{Id: username}))
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	for i, item := range []string{"1", patchID.Hex(), "2"} {
+		rw := do(http.MethodPut, "/v2/commit_queue/mci/"+item, "me")
+		require.Equal(http.StatusOK, rw.Code)
+		position := model.APICommitQueuePosition{}
+		require.NoError(json.Unmarshal(rw.Body.Bytes(), &position))
+		assert.Equal(i, position.Position)
+	}
+	assert.Equal(http.StatusBadRequest, do(http.MethodPut, "/v2/commit_queue/mci/1", "me").Code)
+
+	// only contributors can add items, and only their own patches
+	assert.Equal(http.StatusUnauthorized, do(http.MethodPut, "/v2/commit_queue/mci/3", "anyone").Code)
+	assert.Equal(http.StatusUnauthorized, do(http.MethodPut, "/v2/commit_queue/mci/"+sc.MockPatchConnector.CachedPatches[1].Id.Hex(), "me").Code)
+	assert.Equal(http.StatusNotFound, do(http.MethodPut, "/v2/commit_queue/mci/"+bson.NewObjectId().Hex(), "me").Code)
+
+	// only the author or an admin can remove an item
+	assert.Equal(http.StatusUnauthorized, do(http.MethodDelete, "/v2/commit_queue/mci/1", "you").Code)
+	assert.Equal(http.StatusOK, do(http.MethodDelete, "/v2/commit_queue/mci/1", "me").Code)
+	assert.Equal(http.StatusNotFound, do(http.MethodDelete, "/v2/commit_queue/mci/1", "admin").Code)
+	assert.Equal(http.StatusNotFound, do(http.MethodDelete, "/v2/commit_queue/other/2", "me").Code)
+
+	rw := do(http.MethodGet, "/v2/commit_queue/mci", "anyone")
+	require.Equal(http.StatusOK, rw.Code)
+	cq := model.APICommitQueue{}
+	require.NoError(json.Unmarshal(rw.Body.Bytes(), &cq))
+	assert.Equal("mci", model.FromAPIString(cq.ProjectID))
+	require.Len(cq.Queue, 2)
+	assert.Equal(patchID.Hex(), model.FromAPIString(cq.Queue[0].Issue))
+	assert.Equal("me", model.FromAPIString(cq.Queue[0].Author))
+	assert.Equal("2", model.FromAPIString(cq.Queue[1].Issue))
+
+	assert.Equal(http.StatusOK, do(http.MethodDelete, "/v2/commit_queue/mci/2", "admin").Code)
+
+	// private commit queues are only visible to the project's users
+	assert.Equal(http.StatusUnauthorized, do(http.MethodGet, "/v2/commit_queue/secret", "anyone").Code)
+	assert.Equal(http.StatusOK, do(http.MethodGet, "/v2/commit_queue/secret", "viewer").Code)
+}
@@ -136,6 +136,93 @@ func (cbvh *costByDistroHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(distroCostModel)
 }
 
+// types and functions for Project Cost Route
+type costByProjectHandler struct {
+	projectID string
+	startTime time.Time
+	duration  time.Duration
+	sc        data.Connector
+}
+
+func makeCostByProjectHandler(sc data.Connector) gimlet.RouteHandler {
+	return &costByProjectHandler{
+		sc: sc,
+	}
+}
+
+func (h *costByProjectHandler) Factory() gimlet.RouteHandler {
+	return &costByProjectHandler{sc: h.sc}
+}
+
+func (h *costByProjectHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	if h.projectID == "" {
+		return errors.New("request data incomplete")
+	}
+
+	st, d, err := parseTime(r)
+	if err != nil {
+		return err
+	}
+	h.startTime = st
+	h.duration = d
+
+	return nil
+}
+
+func (h *costByProjectHandler) Run(ctx context.Context) gimlet.Responder {
+	foundProjectCost, err := h.sc.FindCostByProjectId(h.projectID, h.startTime, h.duration)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	projectCostModel := &model.APIProjectCost{}
+	if err = projectCostModel.BuildFromService(foundProjectCost); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(projectCostModel)
+}
+
+// types and functions for Host Cost Route
+type costByHostHandler struct {
+	hostID string
+	sc     data.Connector
+}
+
+func makeCostByHostHandler(sc data.Connector) gimlet.RouteHandler {
+	return &costByHostHandler{
+		sc: sc,
+	}
+}
+
+func (h *costByHostHandler) Factory() gimlet.RouteHandler {
+	return &costByHostHandler{sc: h.sc}
+}
+
+func (h *costByHostHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.hostID = gimlet.GetVars(r)["host_id"]
+	if h.hostID == "" {
+		return errors.New("request data incomplete")
+	}
+
+	return nil
+}
+
+func (h *costByHostHandler) Run(ctx context.Context) gimlet.Responder {
+	foundHostCost, err := h.sc.FindCostByHostId(h.hostID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	hostCostModel := &model.APIHostCost{}
+	if err = hostCostModel.BuildFromService(foundHostCost); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(hostCostModel)
+}
+
 type costTasksByProjectHandler struct {
 	limit     int
 	key       string
@@ -7,10 +7,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -235,3 +239,124 @@ func (s *DistroCostSuite) TestFindCostByDistroIdFail() {
 	res := handler.Run(context.TODO())
 	s.NotEqual(http.StatusOK, res.Status())
 }
+
+type ProjectCostSuite struct {
+	sc        *data.MockConnector
+	starttime time.Time
+
+	suite.Suite
+}
+
+func TestProjectCostSuite(t *testing.T) {
+	suite.Run(t, new(ProjectCostSuite))
+}
+
+func (s *ProjectCostSuite) SetupSuite() {
+	s.starttime = time.Now()
+	s.sc = &data.MockConnector{
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{
+				{Id: "task1", Project: "project1", DistroId: "distro2", FinishTime: s.starttime,
+					TimeTaken: time.Duration(1) * time.Millisecond, Cost: 1},
+				{Id: "task2", Project: "project1", DistroId: "distro1", FinishTime: s.starttime,
+					TimeTaken: time.Duration(2) * time.Millisecond, Cost: 2},
+				{Id: "task3", Project: "project1", DistroId: "distro2", FinishTime: s.starttime,
+					TimeTaken: time.Duration(3) * time.Millisecond, Cost: 3},
+				{Id: "task4", Project: "project2", DistroId: "distro1", FinishTime: s.starttime,
+					TimeTaken: time.Duration(4) * time.Millisecond, Cost: 4},
+			},
+		},
+	}
+}
+
+// TestFindCostByProjectId tests that the handler aggregates the cost of the
+// project's tasks, broken down by distro.
+func (s *ProjectCostSuite) TestFindCostByProjectId() {
+	handler := &costByProjectHandler{
+		projectID: "project1",
+		startTime: s.starttime,
+		duration:  1,
+		sc:        s.sc,
+	}
+	res := handler.Run(context.TODO())
+	s.NotNil(res)
+	s.Equal(http.StatusOK, res.Status())
+
+	h, ok := (res.Data()).(*model.APIProjectCost)
+	s.True(ok)
+	s.Equal(model.ToAPIString("project1"), h.ProjectId)
+	s.Equal(model.APIDuration(6), h.SumTimeTaken)
+	s.InDelta(6, h.EstimatedCost, 0.001)
+	s.Equal(3, h.NumTasks)
+	s.Require().Len(h.Distros, 2)
+	s.Equal(model.ToAPIString("distro1"), h.Distros[0].DistroId)
+	s.Equal(1, h.Distros[0].NumTasks)
+	s.Equal(model.ToAPIString("distro2"), h.Distros[1].DistroId)
+	s.InDelta(4, h.Distros[1].EstimatedCost, 0.001)
+	s.Equal(2, h.Distros[1].NumTasks)
+}
+
+// TestFindCostByProjectIdNoResult tests that the handler returns no cost when
+// no tasks of the project finished in the given time range.
+func (s *ProjectCostSuite) TestFindCostByProjectIdNoResult() {
+	handler := &costByProjectHandler{
+		projectID: "project1",
+		startTime: s.starttime.AddDate(0, -1, 0),
+		duration:  time.Millisecond,
+		sc:        s.sc,
+	}
+	res := handler.Run(context.TODO())
+	s.NotNil(res)
+	s.Equal(http.StatusOK, res.Status())
+
+	h, ok := (res.Data()).(*model.APIProjectCost)
+	s.True(ok)
+	s.Equal(model.APIDuration(0), h.SumTimeTaken)
+	s.Equal(0, h.NumTasks)
+	s.Empty(h.Distros)
+}
+
+// TestFindCostByHostId tests that the handler reports the runtime, pricing and
+// cost of a host, and how much of it accrued while the host was idle.
+func TestFindCostByHostId(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	start := time.Now().Add(-3 * time.Hour)
+	sc := &data.MockConnector{
+		MockHostConnector: data.MockHostConnector{
+			CachedHosts: []host.Host{
+				{
+					Id:              "host1",
+					Provider:        evergreen.ProviderNameEc2OnDemand,
+					Distro:          distro.Distro{Id: "distro1"},
+					StartTime:       start,
+					TerminationTime: start.Add(2 * time.Hour),
+					TotalCost:       4,
+					TotalCostedTime: 90 * time.Minute,
+					HourlyPrice:     3,
+				},
+			},
+		},
+	}
+
+	handler := &costByHostHandler{hostID: "host1", sc: sc}
+	res := handler.Run(context.TODO())
+	require.NotNil(res)
+	assert.Equal(http.StatusOK, res.Status())
+
+	h, ok := (res.Data()).(*model.APIHostCost)
+	require.True(ok)
+	assert.Equal(model.ToAPIString("host1"), h.HostId)
+	assert.Equal(model.ToAPIString("distro1"), h.DistroId)
+	assert.Equal(model.NewAPIDuration(2*time.Hour), h.Runtime)
+	assert.InDelta(4, h.TotalCost, 0.001)
+	assert.Equal(model.NewAPIDuration(90*time.Minute), h.CostedTime)
+	assert.InDelta(3, h.HourlyPrice, 0.001)
+	assert.InDelta(4/1.5, h.HourlyCost, 0.001)
+	assert.InDelta(4, h.IdleCost, 0.001)
+
+	handler = &costByHostHandler{hostID: "host2", sc: sc}
+	res = handler.Run(context.TODO())
+	assert.Equal(http.StatusNotFound, res.Status())
+}
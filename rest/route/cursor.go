@@ -0,0 +1,194 @@
+package route
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	cursorQueryParam = "cursor"
+	limitQueryParam  = "limit"
+)
+
+// cursorPage is the position of a page in a list route paginated by
+// cursor. A cursor identifies the first item of a page by the values of the
+// route's sort keys, which must be unique together, so that pages neither
+// skip nor repeat items as the list changes between requests.
+//
+// Cursors also carry the query parameters of the request that started the
+// listing, since the Link headers gimlet builds from a response's pages
+// only keep the cursor and limit. The cursor is opaque to clients, which
+// only follow the links.
+type cursorPage struct {
+	keys  []json.RawMessage
+	query url.Values
+}
+
+type pageCursor struct {
+	Keys  []json.RawMessage `json:"keys"`
+	Query url.Values        `json:"query,omitempty"`
+}
+
+// getCursorPage parses the cursor and limit query parameters of a list
+// route, returning the position of the page, its limit, and the query
+// parameters of the listing, which include those carried by the cursor. A
+// maxLimit of 0 doesn't bound the limit. The ignored parameters aren't
+// carried to the next page, e.g. the keys of routes' older paginators.
+func getCursorPage(r *http.Request, defaultLimit, maxLimit int, ignored ...string) (cursorPage, int, url.Values, error) {
+	page := cursorPage{}
+	vals := r.URL.Query()
+
+	limit := defaultLimit
+	if l := vals.Get(limitQueryParam); l != "" {
+		var err error
+		limit, err = strconv.Atoi(l)
+		if err != nil || limit < 1 || (maxLimit > 0 && limit > maxLimit) {
+			return page, 0, nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid limit '%s'", l),
+			}
+		}
+	}
+
+	page.query = url.Values{}
+	for key, values := range vals {
+		if key == cursorQueryParam || key == limitQueryParam || util.StringSliceContains(ignored, key) {
+			continue
+		}
+		page.query[key] = values
+	}
+
+	c := vals.Get(cursorQueryParam)
+	if c == "" {
+		return page, limit, vals, nil
+	}
+
+	cursor, err := decodeCursor(c)
+	if err != nil {
+		return page, 0, nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrapf(err, "invalid cursor '%s'", c).Error(),
+		}
+	}
+	for key, values := range page.query {
+		if !stringSlicesEqual(cursor.Query[key], values) {
+			return page, 0, nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("query parameter '%s' doesn't match the cursor", key),
+			}
+		}
+	}
+	for key, values := range cursor.Query {
+		vals[key] = values
+	}
+	page.keys = cursor.Keys
+	page.query = cursor.Query
+
+	return page, limit, vals, nil
+}
+
+// Start decodes the sort keys of the first item of the page into the
+// pointers, returning false, and leaving them as they are, if the page is
+// the first.
+func (p cursorPage) Start(keys ...interface{}) (bool, error) {
+	if p.keys == nil {
+		return false, nil
+	}
+	if len(p.keys) != len(keys) {
+		return false, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid cursor: expected %d sort keys, found %d", len(keys), len(p.keys)),
+		}
+	}
+	for i := range keys {
+		if err := json.Unmarshal(p.keys[i], keys[i]); err != nil {
+			return false, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    errors.Wrap(err, "invalid cursor").Error(),
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// SetNext adds a link to the next page of the listing, whose first item has
+// the given sort keys, to the response.
+func (p cursorPage) SetNext(resp gimlet.Responder, baseURL string, limit int, keys ...interface{}) error {
+	cursor, err := p.Cursor(keys...)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.Wrap(resp.SetPages(&gimlet.ResponsePages{
+		Next: &gimlet.Page{
+			BaseURL:         baseURL,
+			KeyQueryParam:   cursorQueryParam,
+			LimitQueryParam: limitQueryParam,
+			Relation:        "next",
+			Key:             cursor,
+			Limit:           limit,
+		},
+	}), "problem paginating response")
+}
+
+// Cursor returns the cursor of the page of the listing whose first item has
+// the given sort keys.
+func (p cursorPage) Cursor(keys ...interface{}) (string, error) {
+	cursor := pageCursor{
+		Keys: make([]json.RawMessage, 0, len(keys)),
+	}
+	if len(p.query) > 0 {
+		cursor.Query = p.query
+	}
+	for _, key := range keys {
+		raw, err := json.Marshal(key)
+		if err != nil {
+			return "", errors.Wrap(err, "problem encoding sort key")
+		}
+		cursor.Keys = append(cursor.Keys, raw)
+	}
+
+	out, err := json.Marshal(cursor)
+	if err != nil {
+		return "", errors.Wrap(err, "problem encoding cursor")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(out), nil
+}
+
+func decodeCursor(c string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem decoding cursor")
+	}
+	cursor := &pageCursor{}
+	if err = json.Unmarshal(data, cursor); err != nil {
+		return nil, errors.Wrap(err, "problem decoding cursor")
+	}
+	if len(cursor.Keys) == 0 {
+		return nil, errors.New("cursor has no sort keys")
+	}
+
+	return cursor, nil
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,144 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCursor(t *testing.T, keys ...interface{}) string {
+	cursor, err := cursorPage{}.Cursor(keys...)
+	require.NoError(t, err)
+	return cursor
+}
+
+func TestGetCursorPage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	r, err := http.NewRequest(http.MethodGet, "/hosts?status=running", nil)
+	require.NoError(err)
+	page, limit, vals, err := getCursorPage(r, 10, 100)
+	require.NoError(err)
+	assert.Equal(10, limit)
+	assert.Equal("running", vals.Get("status"))
+	var start string
+	ok, err := page.Start(&start)
+	assert.NoError(err)
+	assert.False(ok)
+
+	// the next page's cursor carries the query
+	cursor, err := page.Cursor("host2")
+	require.NoError(err)
+	r, err = http.NewRequest(http.MethodGet, "/hosts?limit=5&cursor="+cursor, nil)
+	require.NoError(err)
+	page, limit, vals, err = getCursorPage(r, 10, 100)
+	require.NoError(err)
+	assert.Equal(5, limit)
+	assert.Equal("running", vals.Get("status"))
+	ok, err = page.Start(&start)
+	assert.NoError(err)
+	assert.True(ok)
+	assert.Equal("host2", start)
+
+	next, err := page.Cursor("host7")
+	require.NoError(err)
+	decoded, err := decodeCursor(next)
+	require.NoError(err)
+	assert.Equal(url.Values{"status": []string{"running"}}, decoded.Query)
+
+	// repeating the cursor's query is fine, contradicting it isn't
+	r, err = http.NewRequest(http.MethodGet, "/hosts?status=running&cursor="+cursor, nil)
+	require.NoError(err)
+	_, _, _, err = getCursorPage(r, 10, 100)
+	assert.NoError(err)
+
+	for _, query := range []string{
+		"status=terminated&cursor=" + cursor,
+		"cursor=garbage",
+		"cursor=" + testCursor(t),
+		"limit=0",
+		"limit=101",
+		"limit=ten",
+	} {
+		r, err = http.NewRequest(http.MethodGet, "/hosts?"+query, nil)
+		require.NoError(err)
+		_, _, _, err = getCursorPage(r, 10, 100)
+		assert.Error(err, query)
+	}
+
+	// sort keys must match the route's
+	var order int
+	_, err = page.Start(&order)
+	assert.Error(err)
+	_, err = page.Start(&start, &order)
+	assert.Error(err)
+}
+
+func TestProjectVersionsPagination(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{URL: "https://evergreen.example.net"}
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{"proj": {Identifier: "proj"}}
+	for i := 1; i <= 5; i++ {
+		sc.MockVersionConnector.CachedVersions = append(sc.MockVersionConnector.CachedVersions, version.Version{
+			Id:                  fmt.Sprintf("v%d", i),
+			Identifier:          "proj",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i,
+		})
+	}
+	sc.MockVersionConnector.CachedVersions = append(sc.MockVersionConnector.CachedVersions,
+		version.Version{Id: "patch", Identifier: "proj", Requester: evergreen.PatchVersionRequester, RevisionOrderNumber: 6},
+		version.Version{Id: "other", Identifier: "other", Requester: evergreen.RepotrackerVersionRequester, RevisionOrderNumber: 7},
+	)
+
+	app := gimlet.NewApp()
+	app.SetPrefix("rest")
+	app.AddRoute("/projects/{project_id}/versions").Version(2).Get().RouteHandler(makeFetchVersionsByProject(sc))
+	require.NoError(app.Resolve())
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	ids := []string{}
+	target := "/rest/v2/projects/proj/versions?limit=2&fields=version_id"
+	for target != "" {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(http.StatusOK, rw.Code, rw.Body.String())
+
+		var page []model.APIVersion
+		require.NoError(json.Unmarshal(rw.Body.Bytes(), &page))
+		for _, v := range page {
+			ids = append(ids, model.FromAPIString(v.Id))
+		}
+
+		target = ""
+		if link := rw.Header().Get("Link"); link != "" {
+			require.True(strings.HasPrefix(link, "<"+sc.URL), link)
+			target = strings.TrimPrefix(link[1:strings.Index(link, ">")], sc.URL)
+			assert.Contains(link, `rel="next"`)
+		}
+	}
+	assert.Equal([]string{"v5", "v4", "v3", "v2", "v1"}, ids)
+
+	h := makeFetchVersionsByProject(sc).(*projectVersionsGetHandler)
+	r, err := http.NewRequest(http.MethodGet, "/projects/proj/versions?cursor="+testCursor(t, "v3"), nil)
+	require.NoError(err)
+	assert.Error(h.Parse(context.Background(), r))
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -55,3 +56,70 @@ func (dgh *distroGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return resp
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/distros/{distro_id}/capacity?pool_size=N
+
+// distroCapacityHandler simulates how a distro's task queue would drain if
+// its pool size were changed, so the pool can be sized before changing it.
+type distroCapacityHandler struct {
+	distroId string
+	poolSize int
+
+	sc data.Connector
+}
+
+func makeDistroCapacitySimulation(sc data.Connector) gimlet.RouteHandler {
+	return &distroCapacityHandler{
+		sc: sc,
+	}
+}
+
+func (h *distroCapacityHandler) Factory() gimlet.RouteHandler {
+	return &distroCapacityHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *distroCapacityHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroId = gimlet.GetVars(r)["distro_id"]
+
+	poolSize, err := util.GetIntValue(r, "pool_size", -1)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			Message:    "pool_size must be an integer",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	if poolSize < 0 {
+		return gimlet.ErrorResponse{
+			Message:    "Must set a non-negative 'pool_size'",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	h.poolSize = poolSize
+
+	return nil
+}
+
+func (h *distroCapacityHandler) Run(ctx context.Context) gimlet.Responder {
+	estimates, err := h.sc.SimulateDistroCapacity(h.distroId, h.poolSize)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem simulating capacity of distro '%s'", h.distroId))
+	}
+	if len(estimates) != 2 {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Errorf("expected 2 capacity estimates but got %d", len(estimates)))
+	}
+
+	simulation := &model.APIDistroCapacitySimulation{
+		DistroId: model.ToAPIString(h.distroId),
+	}
+	if err = simulation.Current.BuildFromService(estimates[0]); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if err = simulation.Proposed.BuildFromService(estimates[1]); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	return gimlet.NewJSONResponse(simulation)
+}
@@ -0,0 +1,55 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistroCapacitySimulation(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	sc.MockDistroConnector.CachedDistros = []distro.Distro{{Id: "d1", PoolSize: 1}}
+	sc.MockDistroConnector.CachedTaskQueues = map[string][]serviceModel.TaskQueueItem{
+		"d1": {
+			{Id: "t1", ExpectedDuration: 10 * time.Minute},
+			{Id: "t2", ExpectedDuration: 10 * time.Minute},
+		},
+	}
+
+	request := func(query string) *http.Request {
+		r, err := http.NewRequest(http.MethodGet, "/distros/d1/capacity"+query, nil)
+		assert.NoError(err)
+		return r
+	}
+	assert.Error(makeDistroCapacitySimulation(sc).Parse(context.Background(), request("")))
+	assert.Error(makeDistroCapacitySimulation(sc).Parse(context.Background(), request("?pool_size=-2")))
+	assert.Error(makeDistroCapacitySimulation(sc).Parse(context.Background(), request("?pool_size=many")))
+
+	handler := makeDistroCapacitySimulation(sc).(*distroCapacityHandler)
+	assert.NoError(handler.Parse(context.Background(), request("?pool_size=2")))
+	assert.Equal(2, handler.poolSize)
+	handler.distroId = "d1"
+
+	resp := handler.Run(context.Background())
+	assert.Equal(http.StatusOK, resp.Status())
+	simulation, ok := resp.Data().(*model.APIDistroCapacitySimulation)
+	if assert.True(ok) {
+		assert.Equal(1, simulation.Current.PoolSize)
+		assert.Equal(model.NewAPIDuration(20*time.Minute), simulation.Current.Makespan)
+		assert.Equal(model.NewAPIDuration(10*time.Minute), simulation.Current.MaxWait)
+		assert.Equal(2, simulation.Proposed.PoolSize)
+		assert.Equal(model.NewAPIDuration(10*time.Minute), simulation.Proposed.Makespan)
+		assert.Equal(model.NewAPIDuration(0), simulation.Proposed.MaxWait)
+	}
+
+	handler.distroId = "nonexistent"
+	assert.Equal(http.StatusNotFound, handler.Run(context.Background()).Status())
+}
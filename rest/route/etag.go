@@ -0,0 +1,89 @@
+package route
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+// conditionalGetMiddleware adds an ETag header to the responses of a GET
+// route, and answers requests whose If-None-Match header matches the
+// resource's current entity tag with 304 Not Modified, without running the
+// route. Clients polling a resource that hasn't changed skip both the reads
+// behind the route and the marshaling of its response.
+type conditionalGetMiddleware struct {
+	routeVar string
+	etag     func(string) (string, error)
+}
+
+// newConditionalGetMiddleware returns middleware for routes serving the
+// resource identified by the route variable, whose entity tag is computed
+// by etag.
+func newConditionalGetMiddleware(routeVar string, etag func(string) (string, error)) gimlet.Middleware {
+	return &conditionalGetMiddleware{
+		routeVar: routeVar,
+		etag:     etag,
+	}
+}
+
+func (m *conditionalGetMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		next(rw, r)
+		return
+	}
+
+	id := gimlet.GetVars(r)[m.routeVar]
+	tag, err := m.etag(id)
+	if err != nil {
+		// the route reports its own errors
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem computing entity tag",
+			"path":    r.URL.Path,
+			"id":      id,
+		}))
+		next(rw, r)
+		return
+	}
+	if tag == "" {
+		next(rw, r)
+		return
+	}
+
+	etag := responseETag(tag, r.URL.RawQuery)
+	rw.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		rw.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	next(rw, r)
+}
+
+// responseETag returns the entity tag of a response, which depends on the
+// resource, the query parameters shaping the response, and the build of the
+// service, since the API models can change between builds.
+func responseETag(resourceTag, query string) string {
+	hash := sha1.New()
+	_, _ = fmt.Fprintf(hash, "%s\n%s\n%s", evergreen.BuildRevision, resourceTag, query)
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(hash.Sum(nil)))
+}
+
+// etagMatches returns whether an If-None-Match header matches the entity
+// tag. As the header is for conditional GETs, weak tags are compared as if
+// they were strong.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditionalGetMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockETagConnector.CachedETags = map[string]string{"build:b1": "tag1"}
+
+	calls := 0
+	app := gimlet.NewApp()
+	app.AddRoute("/builds/{build_id}").Version(2).Get().Wrap(newConditionalGetMiddleware("build_id", sc.BuildETag)).Handler(func(rw http.ResponseWriter, r *http.Request) {
+		calls++
+		gimlet.WriteJSON(rw, map[string]string{"id": gimlet.GetVars(r)["build_id"]})
+	})
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(err)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	rw := get("/v2/builds/b1", "")
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.Equal(1, calls)
+	etag := rw.Header().Get("ETag")
+	require.NotEmpty(etag)
+
+	// a matching tag skips the route
+	rw = get("/v2/builds/b1", etag)
+	assert.Equal(http.StatusNotModified, rw.Code)
+	assert.Empty(rw.Body.String())
+	assert.Equal(etag, rw.Header().Get("ETag"))
+	assert.Equal(1, calls)
+
+	rw = get("/v2/builds/b1", `"other", W/`+etag)
+	assert.Equal(http.StatusNotModified, rw.Code)
+	assert.Equal(1, calls)
+
+	// query parameters shape the response, so they're part of the tag
+	rw = get("/v2/builds/b1?limit=1", etag)
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotEqual(etag, rw.Header().Get("ETag"))
+	assert.Equal(2, calls)
+
+	// a changed resource is served again
+	sc.MockETagConnector.CachedETags["build:b1"] = "tag2"
+	rw = get("/v2/builds/b1", etag)
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.NotEqual(etag, rw.Header().Get("ETag"))
+	assert.Equal(3, calls)
+
+	// resources without a tag are served without one
+	rw = get("/v2/builds/b2", etag)
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.Empty(rw.Header().Get("ETag"))
+	assert.Equal(4, calls)
+}
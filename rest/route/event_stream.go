@@ -0,0 +1,157 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventStreamPollInterval      = 2 * time.Second
+	eventStreamKeepAliveInterval = 15 * time.Second
+	eventStreamBatchSize         = 500
+	// eventStreamDuration is how long a client is streamed events before
+	// the stream is closed, which must be less than the server's write
+	// timeout. Clients reconnect with the ID of the last event they
+	// received, and the stream resumes after it.
+	eventStreamDuration   = 50 * time.Second
+	eventStreamRetryDelay = time.Second
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/events/stream
+
+// makeEventStream returns a handler that pushes the state transitions of the
+// versions, builds and tasks of a project or version to the client as
+// server-sent events. Clients resume the stream with the Last-Event-ID
+// header, or the cursor parameter, set to the ID of the last event they
+// received.
+func makeEventStream(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := data.StatusEventFilter{
+			Project: r.FormValue("project"),
+			Version: r.FormValue("version"),
+		}
+		if filter.Project == "" && filter.Version == "" {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "must specify a project or a version to stream events for",
+			}))
+			return
+		}
+		if resp := checkEventStreamVisible(r.Context(), sc, filter); resp != nil {
+			gimlet.WriteResponse(w, resp)
+			return
+		}
+
+		lastEventID := r.Header.Get("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = r.FormValue("cursor")
+		}
+		cursor, err := sc.StatusEventCursor(lastEventID)
+		if err != nil {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(err))
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(errors.New("streaming is not supported")))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", eventStreamRetryDelay/time.Millisecond)
+		flusher.Flush()
+
+		ctx, cancel := context.WithTimeout(r.Context(), eventStreamDuration)
+		defer cancel()
+		ticker := time.NewTicker(eventStreamPollInterval)
+		defer ticker.Stop()
+
+		lastWrite := time.Now()
+		for {
+			var events []model.APIStatusEvent
+			events, cursor, err = sc.FindStatusEvents(*cursor, filter, eventStreamBatchSize)
+			if err != nil {
+				grip.Error(message.WrapError(err, message.Fields{
+					"message": "problem finding status events to stream",
+					"project": filter.Project,
+					"version": filter.Version,
+				}))
+				writeStreamEvent(w, "", "error", map[string]string{"error": err.Error()})
+				flusher.Flush()
+				return
+			}
+			for i := range events {
+				writeStreamEvent(w, model.FromAPIString(events[i].ID), strings.ToLower(model.FromAPIString(events[i].ResourceType)), &events[i])
+			}
+			if len(events) > 0 {
+				lastWrite = time.Now()
+				flusher.Flush()
+			} else if time.Since(lastWrite) >= eventStreamKeepAliveInterval {
+				fmt.Fprint(w, ": keepalive\n\n")
+				lastWrite = time.Now()
+				flusher.Flush()
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// checkEventStreamVisible returns an error response if the user can't view
+// the project, or the project of the version, whose events are streamed.
+func checkEventStreamVisible(ctx context.Context, sc data.Connector, filter data.StatusEventFilter) gimlet.Responder {
+	if filter.Version != "" {
+		v, err := sc.FindVersionById(filter.Version)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding version '%s'", filter.Version))
+		}
+		if resp := checkProjectVisible(ctx, sc, v.Identifier, "stream events"); resp != nil {
+			return resp
+		}
+	}
+	if filter.Project != "" {
+		return checkProjectVisible(ctx, sc, filter.Project, "stream events")
+	}
+
+	return nil
+}
+
+// writeStreamEvent writes a server-sent event with the given ID and name,
+// whose data is the JSON-encoded payload.
+func writeStreamEvent(w http.ResponseWriter, id, name string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":  "problem marshalling streamed event",
+			"event_id": id,
+		}))
+		return
+	}
+
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+}
@@ -0,0 +1,115 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStream(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	sc := &data.MockConnector{}
+	sc.MockEventStreamConnector.CachedStatusEvents = []model.APIStatusEvent{
+		{
+			ID:           model.ToAPIString("e0"),
+			ResourceType: model.ToAPIString(event.ResourceTypeTask),
+			ResourceID:   model.ToAPIString("t0"),
+			Status:       model.ToAPIString("started"),
+			Project:      model.ToAPIString("proj"),
+			Version:      model.ToAPIString("v0"),
+		},
+		{
+			ID:           model.ToAPIString("e1"),
+			ResourceType: model.ToAPIString(event.ResourceTypeBuild),
+			ResourceID:   model.ToAPIString("b1"),
+			Status:       model.ToAPIString("failed"),
+			Project:      model.ToAPIString("other"),
+			Version:      model.ToAPIString("v1"),
+		},
+		{
+			ID:           model.ToAPIString("e2"),
+			ResourceType: model.ToAPIString(event.ResourceTypeVersion),
+			ResourceID:   model.ToAPIString("v0"),
+			Status:       model.ToAPIString("success"),
+			Project:      model.ToAPIString("proj"),
+			Version:      model.ToAPIString("v0"),
+		},
+	}
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{
+		"proj":  {Identifier: "proj"},
+		"other": {Identifier: "other"},
+		"secret": {
+			Identifier:   "secret",
+			Private:      true,
+			Contributors: []string{"contributor"},
+			Viewers:      []string{"viewer"},
+		},
+	}
+	sc.MockVersionConnector.CachedVersions = []version.Version{
+		{Id: "v0", Identifier: "proj"},
+		{Id: "v1", Identifier: "other"},
+		{Id: "v2", Identifier: "secret"},
+	}
+	handler := makeEventStream(sc)
+
+	streamAs := func(name, url, lastEventID string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithTimeout(gimlet.AttachUser(context.Background(), &user.DBUser{Id: name}), 100*time.Millisecond)
+		defer cancel()
+		r, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(err)
+		if lastEventID != "" {
+			r.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rw := httptest.NewRecorder()
+		handler(rw, r.WithContext(ctx))
+		return rw
+	}
+	stream := func(url, lastEventID string) *httptest.ResponseRecorder {
+		return streamAs("anyone", url, lastEventID)
+	}
+
+	rw := stream("/rest/v2/events/stream", "")
+	assert.Equal(http.StatusBadRequest, rw.Code)
+
+	rw = stream("/rest/v2/events/stream?project=proj", "")
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.Equal("text/event-stream", rw.Header().Get("Content-Type"))
+	body := rw.Body.String()
+	assert.True(strings.HasPrefix(body, "retry: 1000\n\n"))
+	assert.Contains(body, "id: e0\nevent: task\ndata: {")
+	assert.Contains(body, "id: e2\nevent: version\ndata: {")
+	assert.NotContains(body, "e1")
+	assert.True(strings.Index(body, "id: e0") < strings.Index(body, "id: e2"))
+
+	// resuming from the last event received
+	rw = stream("/rest/v2/events/stream?project=proj", "e0")
+	body = rw.Body.String()
+	assert.NotContains(body, "id: e0")
+	assert.Contains(body, "id: e2")
+
+	rw = stream("/rest/v2/events/stream?version=v1", "")
+	body = rw.Body.String()
+	assert.Contains(body, "id: e1\nevent: build\n")
+	assert.NotContains(body, "id: e0")
+
+	// private projects and their versions are only streamed to their viewers
+	assert.Equal(http.StatusUnauthorized, stream("/rest/v2/events/stream?project=secret", "").Code)
+	assert.Equal(http.StatusUnauthorized, stream("/rest/v2/events/stream?version=v2", "").Code)
+	assert.Equal(http.StatusUnauthorized, stream("/rest/v2/events/stream?project=proj&version=v2", "").Code)
+	assert.Equal(http.StatusOK, streamAs("viewer", "/rest/v2/events/stream?version=v2", "").Code)
+	assert.Equal(http.StatusNotFound, stream("/rest/v2/events/stream?version=nope", "").Code)
+}
@@ -0,0 +1,207 @@
+package route
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	exportFormatCSV   = "csv"
+	exportFormatJSONL = "jsonl"
+
+	// exportMaxRevisions is the largest range of revisions that can be
+	// exported at once.
+	exportMaxRevisions = 1000
+	// exportFlushInterval is the number of rows written between flushes of
+	// the response.
+	exportFlushInterval = 500
+	// exportErrorTrailer is the trailer set when the export fails after
+	// rows have already been written, since the status can't change then.
+	exportErrorTrailer = "X-Export-Error"
+)
+
+// exportRow is a row of an export, written as either a CSV record or a line
+// of JSON.
+type exportRow interface {
+	CSVHeader() []string
+	CSVRecord() []string
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/export/tasks
+
+// makeExportTaskHistory returns a handler that streams the mainline tasks of
+// a project in a range of revisions, as CSV or JSON lines.
+func makeExportTaskHistory(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, format, err := parseExportRequest(r)
+		if err != nil {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(err))
+			return
+		}
+
+		writeExport(w, format, "tasks", filter, func(write func(exportRow) error) error {
+			return sc.ExportTaskHistory(filter, func(row *model.APITaskHistoryRow) error { return write(row) })
+		})
+	}
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/export/tests
+
+// makeExportTestResults returns a handler that streams the test results of
+// the mainline tasks of a project in a range of revisions, as CSV or JSON
+// lines.
+func makeExportTestResults(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, format, err := parseExportRequest(r)
+		if err != nil {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(err))
+			return
+		}
+		filter.TestStatuses = splitExportParam(r.FormValue("test_statuses"))
+
+		writeExport(w, format, "tests", filter, func(write func(exportRow) error) error {
+			return sc.ExportTestResults(filter, func(row *model.APITestResultRow) error { return write(row) })
+		})
+	}
+}
+
+// parseExportRequest reads the filter and the format shared by the export
+// routes from the request.
+func parseExportRequest(r *http.Request) (data.ExportFilter, string, error) {
+	filter := data.ExportFilter{
+		Project:       gimlet.GetVars(r)["project_id"],
+		BuildVariants: splitExportParam(r.FormValue("variants")),
+		TaskNames:     splitExportParam(r.FormValue("tasks")),
+		TaskStatuses:  splitExportParam(r.FormValue("statuses")),
+	}
+
+	format := strings.ToLower(r.FormValue("format"))
+	if format == "" {
+		format = exportFormatCSV
+	}
+	if format != exportFormatCSV && format != exportFormatJSONL {
+		return filter, "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid format '%s', must be '%s' or '%s'", format, exportFormatCSV, exportFormatJSONL),
+		}
+	}
+
+	var err error
+	for param, order := range map[string]*int{"start_order": &filter.StartOrder, "end_order": &filter.EndOrder} {
+		value := r.FormValue(param)
+		if value == "" {
+			return filter, "", gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("must specify %s", param),
+			}
+		}
+		if *order, err = strconv.Atoi(value); err != nil {
+			return filter, "", gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid %s '%s'", param, value),
+			}
+		}
+	}
+	if filter.StartOrder > filter.EndOrder {
+		return filter, "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "start_order must not be after end_order",
+		}
+	}
+	if filter.EndOrder-filter.StartOrder >= exportMaxRevisions {
+		return filter, "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("cannot export more than %d revisions at once", exportMaxRevisions),
+		}
+	}
+
+	return filter, format, nil
+}
+
+func splitExportParam(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// writeExport streams the rows that export passes to its write function to
+// the response in the format, flushing as it goes. Since the status has been
+// sent by the time the rows are read, an error reading them is reported in
+// the X-Export-Error trailer.
+func writeExport(w http.ResponseWriter, format, name string, filter data.ExportFilter, export func(func(exportRow) error) error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(errors.New("streaming is not supported")))
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s-%d-%d.%s", filter.Project, name, filter.StartOrder, filter.EndOrder, format)
+	if format == exportFormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Trailer", exportErrorTrailer)
+	w.WriteHeader(http.StatusOK)
+
+	csvWriter := csv.NewWriter(w)
+	jsonEncoder := json.NewEncoder(w)
+	rows := 0
+	err := export(func(row exportRow) error {
+		if format == exportFormatCSV {
+			if rows == 0 {
+				if err := csvWriter.Write(row.CSVHeader()); err != nil {
+					return errors.Wrap(err, "problem writing CSV header")
+				}
+			}
+			if err := csvWriter.Write(row.CSVRecord()); err != nil {
+				return errors.Wrap(err, "problem writing CSV record")
+			}
+		} else if err := jsonEncoder.Encode(row); err != nil {
+			return errors.Wrap(err, "problem writing JSON line")
+		}
+
+		rows++
+		if rows%exportFlushInterval == 0 {
+			csvWriter.Flush()
+			flusher.Flush()
+		}
+		return nil
+	})
+	csvWriter.Flush()
+	if err == nil {
+		err = csvWriter.Error()
+	}
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":     "problem exporting",
+			"export":      name,
+			"project":     filter.Project,
+			"start_order": filter.StartOrder,
+			"end_order":   filter.EndOrder,
+			"rows":        rows,
+		}))
+		w.Header().Set(exportErrorTrailer, err.Error())
+	}
+	flusher.Flush()
+}
@@ -0,0 +1,104 @@
+package route
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockExportConnector.CachedTasks = []task.Task{
+		{Id: "t2", Project: "proj", RevisionOrderNumber: 2, BuildVariant: "bv1", DisplayName: "compile", Status: evergreen.TaskFailed, Requester: evergreen.RepotrackerVersionRequester, Execution: 1},
+		{Id: "t1", Project: "proj", RevisionOrderNumber: 1, BuildVariant: "bv1", DisplayName: "compile", Status: evergreen.TaskSucceeded, Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "t3", Project: "proj", RevisionOrderNumber: 2, BuildVariant: "bv2", DisplayName: "test", Status: evergreen.TaskSucceeded, Requester: evergreen.RepotrackerVersionRequester},
+		{Id: "p1", Project: "proj", RevisionOrderNumber: 2, BuildVariant: "bv1", DisplayName: "compile", Status: evergreen.TaskFailed, Requester: evergreen.PatchVersionRequester},
+		{Id: "o1", Project: "other", RevisionOrderNumber: 2, BuildVariant: "bv1", DisplayName: "compile", Status: evergreen.TaskFailed, Requester: evergreen.RepotrackerVersionRequester},
+	}
+	sc.MockExportConnector.CachedTests = []testresult.TestResult{
+		{TaskID: "t2", Execution: 0, TestFile: "old", Status: evergreen.TestFailedStatus},
+		{TaskID: "t2", Execution: 1, TestFile: "b", Status: evergreen.TestSucceededStatus},
+		{TaskID: "t2", Execution: 1, TestFile: "a", Status: evergreen.TestFailedStatus, StartTime: 10, EndTime: 12},
+		{TaskID: "p1", Execution: 0, TestFile: "a", Status: evergreen.TestFailedStatus},
+	}
+
+	app := gimlet.NewApp()
+	app.AddRoute("/projects/{project_id}/export/tasks").Version(2).Get().Handler(makeExportTaskHistory(sc))
+	app.AddRoute("/projects/{project_id}/export/tests").Version(2).Get().Handler(makeExportTestResults(sc))
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(err)
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	for _, path := range []string{
+		"/v2/projects/proj/export/tasks",
+		"/v2/projects/proj/export/tasks?start_order=1",
+		"/v2/projects/proj/export/tasks?start_order=1&end_order=x",
+		"/v2/projects/proj/export/tasks?start_order=2&end_order=1",
+		"/v2/projects/proj/export/tasks?start_order=1&end_order=5000",
+		"/v2/projects/proj/export/tests?start_order=1&end_order=2&format=xml",
+	} {
+		assert.Equal(http.StatusBadRequest, get(path).Code, path)
+	}
+
+	rw := get("/v2/projects/proj/export/tasks?start_order=1&end_order=2")
+	require.Equal(http.StatusOK, rw.Code)
+	assert.Equal("text/csv; charset=utf-8", rw.Header().Get("Content-Type"))
+	assert.Contains(rw.Header().Get("Content-Disposition"), "proj-tasks-1-2.csv")
+	records, err := csv.NewReader(rw.Body).ReadAll()
+	require.NoError(err)
+	require.Len(records, 4)
+	assert.Equal((&model.APITaskHistoryRow{}).CSVHeader(), records[0])
+	assert.Equal("t1", records[1][0])
+	assert.Equal("t2", records[2][0])
+	assert.Equal("t3", records[3][0])
+
+	rw = get("/v2/projects/proj/export/tasks?start_order=1&end_order=2&format=jsonl&variants=bv1&statuses=failed")
+	require.Equal(http.StatusOK, rw.Code)
+	assert.Equal("application/x-ndjson", rw.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(rw.Body.String()), "\n")
+	require.Len(lines, 1)
+	row := model.APITaskHistoryRow{}
+	require.NoError(json.Unmarshal([]byte(lines[0]), &row))
+	assert.Equal("t2", model.FromAPIString(row.TaskID))
+	assert.Equal(1, row.Execution)
+
+	rw = get("/v2/projects/proj/export/tests?start_order=1&end_order=2")
+	require.Equal(http.StatusOK, rw.Code)
+	records, err = csv.NewReader(rw.Body).ReadAll()
+	require.NoError(err)
+	require.Len(records, 3)
+	assert.Equal([]string{"t2", "a"}, []string{records[1][0], records[1][7]})
+	assert.Equal("2000", records[1][12])
+	assert.Equal([]string{"t2", "b"}, []string{records[2][0], records[2][7]})
+
+	rw = get("/v2/projects/proj/export/tests?start_order=1&end_order=2&format=jsonl&test_statuses=fail")
+	require.Equal(http.StatusOK, rw.Code)
+	lines = strings.Split(strings.TrimSpace(rw.Body.String()), "\n")
+	require.Len(lines, 1)
+	testRow := model.APITestResultRow{}
+	require.NoError(json.Unmarshal([]byte(lines[0]), &testRow))
+	assert.Equal("a", model.FromAPIString(testRow.TestFile))
+	assert.Equal("compile", model.FromAPIString(testRow.TaskName))
+}
@@ -0,0 +1,64 @@
+package route
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+// getFieldSelection parses the fields query parameter, which lists the
+// fields of the route's model to include in the response, checking that the
+// model has them.
+func getFieldSelection(vals url.Values, m interface{}) (model.FieldSelection, error) {
+	fields := model.ParseFieldSelection(vals.Get("fields"))
+	if err := fields.Validate(m); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "invalid fields").Error(),
+		}
+	}
+
+	return fields, nil
+}
+
+// projectResponse replaces the models in a successful response with their
+// selected fields.
+func projectResponse(resp gimlet.Responder, fields model.FieldSelection) gimlet.Responder {
+	if fields.IsEmpty() || resp.Status() != http.StatusOK {
+		return resp
+	}
+
+	var projected gimlet.Responder
+	if items, ok := resp.Data().([]interface{}); ok {
+		projected = gimlet.NewResponseBuilder()
+		for _, item := range items {
+			out, err := fields.Project(item)
+			if err != nil {
+				return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem selecting fields"))
+			}
+			if err = projected.AddData(out); err != nil {
+				return gimlet.MakeJSONInternalErrorResponder(err)
+			}
+		}
+	} else {
+		out, err := fields.Project(resp.Data())
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem selecting fields"))
+		}
+		projected = gimlet.NewJSONResponse(out)
+	}
+
+	if err := projected.SetFormat(resp.Format()); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+	if resp.Pages() != nil {
+		if err := projected.SetPages(resp.Pages()); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return projected
+}
@@ -2,8 +2,12 @@ package route
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
@@ -18,8 +22,44 @@ const (
 	githubActionOpened      = "opened"
 	githubActionSynchronize = "synchronize"
 	githubActionReopened    = "reopened"
+	githubActionRerequested = "rerequested"
+
+	githubEventCheckRun   = "check_run"
+	githubEventCheckSuite = "check_suite"
+
+	// githubChecksCaller is recorded as the user restarting tasks when a
+	// check is re-run from GitHub.
+	githubChecksCaller = "github_checks"
 )
 
+// githubCheckRunEvent is the part of a GitHub check_run webhook payload
+// needed to re-run the check, which the vendored go-github predates. The
+// external ID of the check runs Evergreen reports is the ID of the build
+// they report on.
+type githubCheckRunEvent struct {
+	Action   *string `json:"action"`
+	CheckRun *struct {
+		ID         *int64  `json:"id"`
+		ExternalID *string `json:"external_id"`
+		HeadSHA    *string `json:"head_sha"`
+	} `json:"check_run"`
+	Repo   *github.Repository `json:"repository"`
+	Sender *github.User       `json:"sender"`
+}
+
+// githubCheckSuiteEvent is the part of a GitHub check_suite webhook payload
+// needed to re-run the suite.
+type githubCheckSuiteEvent struct {
+	Action     *string `json:"action"`
+	CheckSuite *struct {
+		ID         *int64  `json:"id"`
+		HeadBranch *string `json:"head_branch"`
+		HeadSHA    *string `json:"head_sha"`
+	} `json:"check_suite"`
+	Repo   *github.Repository `json:"repository"`
+	Sender *github.User       `json:"sender"`
+}
+
 type githubHookApi struct {
 	queue  amboy.Queue
 	secret []byte
@@ -71,7 +111,16 @@ func (gh *githubHookApi) Parse(ctx context.Context, r *http.Request) error {
 		}
 	}
 
-	gh.event, err = github.ParseWebHook(gh.eventType, body)
+	switch gh.eventType {
+	case githubEventCheckRun:
+		gh.event = &githubCheckRunEvent{}
+		err = json.Unmarshal(body, gh.event)
+	case githubEventCheckSuite:
+		gh.event = &githubCheckSuiteEvent{}
+		err = json.Unmarshal(body, gh.event)
+	default:
+		gh.event, err = github.ParseWebHook(gh.eventType, body)
+	}
 	if err != nil {
 		grip.Error(message.WrapError(err, message.Fields{
 			"source":  "github hook",
@@ -185,6 +234,85 @@ func (gh *githubHookApi) Run(ctx context.Context) gimlet.Responder {
 			return gimlet.MakeJSONErrorResponder(err)
 		}
 		return gimlet.NewJSONResponse(struct{}{})
+
+	case *githubCheckRunEvent:
+		if event.Action == nil || *event.Action != githubActionRerequested {
+			break
+		}
+		if event.CheckRun == nil || event.CheckRun.ExternalID == nil || *event.CheckRun.ExternalID == "" {
+			return gimlet.NewJSONErrorResponse(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "check run has no external ID",
+			})
+		}
+
+		b, err := gh.sc.FindBuildById(*event.CheckRun.ExternalID)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+
+		grip.Info(message.Fields{
+			"source":  "github hook",
+			"msg_id":  gh.msgID,
+			"event":   gh.eventType,
+			"action":  *event.Action,
+			"message": "check run re-requested; restarting build",
+			"build":   b.Id,
+			"sender":  event.Sender.GetLogin(),
+		})
+		if err = gh.sc.RestartBuild(b.Id, githubChecksCaller); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+
+		return gimlet.NewJSONResponse(struct{}{})
+
+	case *githubCheckSuiteEvent:
+		if event.Action == nil || *event.Action != githubActionRerequested {
+			break
+		}
+		if event.CheckSuite == nil || event.CheckSuite.HeadSHA == nil || event.Repo == nil || event.Repo.FullName == nil {
+			return gimlet.NewJSONErrorResponse(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "check suite data is malformed",
+			})
+		}
+		repo := strings.Split(*event.Repo.FullName, "/")
+		if len(repo) != 2 {
+			return gimlet.NewJSONErrorResponse(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    "repository name is invalid",
+			})
+		}
+
+		branch := ""
+		if event.CheckSuite.HeadBranch != nil {
+			branch = *event.CheckSuite.HeadBranch
+		}
+		versionID, err := gh.sc.FindGithubCheckSuiteVersion(repo[0], repo[1], branch, *event.CheckSuite.HeadSHA)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+		if versionID == "" {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("no version ran commit '%s'", *event.CheckSuite.HeadSHA),
+			})
+		}
+
+		grip.Info(message.Fields{
+			"source":  "github hook",
+			"msg_id":  gh.msgID,
+			"event":   gh.eventType,
+			"action":  *event.Action,
+			"message": "check suite re-requested; restarting version",
+			"version": versionID,
+			"sender":  event.Sender.GetLogin(),
+		})
+		if err = gh.sc.RestartVersion(versionID, dbModel.VersionTaskFilter{}, githubChecksCaller); err != nil {
+			return gimlet.MakeJSONErrorResponder(err)
+		}
+
+		return gimlet.NewJSONResponse(struct{}{})
 	}
 
 	return gimlet.NewJSONResponse(struct{}{})
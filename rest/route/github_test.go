@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/testutil"
@@ -186,3 +187,63 @@ func (s *GithubWebhookRouteSuite) TestPushEventTriggersRepoTracker() {
 		s.Equal(http.StatusOK, resp.Status())
 	}
 }
+
+func (s *GithubWebhookRouteSuite) TestCheckRunRerequestedRestartsBuild() {
+	s.sc.MockBuildConnector.CachedBuilds = []build.Build{{Id: "b1"}}
+	ctx := context.Background()
+	secret := []byte(s.conf.Api.GithubWebhookSecret)
+
+	body := []byte(`{"action": "rerequested", "check_run": {"id": 4, "external_id": "b1", "head_sha": "abc"},
+		"repository": {"full_name": "evergreen-ci/evergreen"}, "sender": {"login": "octocat"}}`)
+	req, err := makeRequest("1", body, secret)
+	s.Require().NoError(err)
+	req.Header.Set("X-Github-Event", "check_run")
+	s.Require().NoError(s.h.Parse(ctx, req))
+	s.IsType(&githubCheckRunEvent{}, s.h.event)
+	s.Equal(http.StatusOK, s.h.Run(ctx).Status())
+
+	s.sc.MockBuildConnector.FailOnRestart = true
+	s.NotEqual(http.StatusOK, s.h.Run(ctx).Status())
+
+	body = []byte(`{"action": "rerequested", "check_run": {"id": 4, "external_id": "nope"}}`)
+	req, err = makeRequest("2", body, secret)
+	s.Require().NoError(err)
+	req.Header.Set("X-Github-Event", "check_run")
+	s.Require().NoError(s.h.Parse(ctx, req))
+	s.Equal(http.StatusNotFound, s.h.Run(ctx).Status())
+
+	// other actions are ignored
+	body = []byte(`{"action": "completed", "check_run": {"id": 4, "external_id": "nope"}}`)
+	req, err = makeRequest("3", body, secret)
+	s.Require().NoError(err)
+	req.Header.Set("X-Github-Event", "check_run")
+	s.Require().NoError(s.h.Parse(ctx, req))
+	s.Equal(http.StatusOK, s.h.Run(ctx).Status())
+}
+
+func (s *GithubWebhookRouteSuite) TestCheckSuiteRerequestedRestartsVersion() {
+	s.sc.MockPatchConnector.CachedPatches = []patch.Patch{
+		{Version: "v1", GithubPatchData: patch.GithubPatch{BaseOwner: "evergreen-ci", BaseRepo: "evergreen", HeadHash: "abc"}},
+	}
+	s.sc.MockVersionConnector.CachedRestartedVersions = map[string]string{}
+	ctx := context.Background()
+	secret := []byte(s.conf.Api.GithubWebhookSecret)
+
+	body := []byte(`{"action": "rerequested", "check_suite": {"id": 5, "head_branch": "feature", "head_sha": "abc"},
+		"repository": {"full_name": "evergreen-ci/evergreen"}, "sender": {"login": "octocat"}}`)
+	req, err := makeRequest("1", body, secret)
+	s.Require().NoError(err)
+	req.Header.Set("X-Github-Event", "check_suite")
+	s.Require().NoError(s.h.Parse(ctx, req))
+	s.IsType(&githubCheckSuiteEvent{}, s.h.event)
+	s.Equal(http.StatusOK, s.h.Run(ctx).Status())
+	s.Equal(githubChecksCaller, s.sc.MockVersionConnector.CachedRestartedVersions["v1"])
+
+	body = []byte(`{"action": "rerequested", "check_suite": {"id": 5, "head_sha": "def"},
+		"repository": {"full_name": "evergreen-ci/evergreen"}}`)
+	req, err = makeRequest("2", body, secret)
+	s.Require().NoError(err)
+	req.Header.Set("X-Github-Event", "check_suite")
+	s.Require().NoError(s.h.Parse(ctx, req))
+	s.Equal(http.StatusNotFound, s.h.Run(ctx).Status())
+}
@@ -75,6 +75,8 @@ func (high *hostIDGetHandler) Run(ctx context.Context) gimlet.Responder {
 //
 // GET /hosts
 // GET /users/{user_id}/hosts
+//
+// Hosts are listed in order of their IDs, and paginated by cursor.
 
 func makeFetchHosts(sc data.Connector) gimlet.RouteHandler {
 	return &hostGetHandler{
@@ -87,6 +89,8 @@ type hostGetHandler struct {
 	key    string
 	status string
 	user   string
+	fields model.FieldSelection
+	page   cursorPage
 
 	sc data.Connector
 }
@@ -98,20 +102,27 @@ func (hgh *hostGetHandler) Factory() gimlet.RouteHandler {
 }
 
 func (hgh *hostGetHandler) Parse(ctx context.Context, r *http.Request) error {
-	vals := r.URL.Query()
+	var (
+		vals url.Values
+		err  error
+	)
+	hgh.page, hgh.limit, vals, err = getCursorPage(r, defaultLimit, 0, "host_id")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	hgh.status = vals.Get("status")
+	// host_id is the key of the paginator that preceded cursors
 	hgh.key = vals.Get("host_id")
-	var err error
-
-	hgh.limit, err = getLimit(vals)
-	if err != nil {
+	if _, err = hgh.page.Start(&hgh.key); err != nil {
 		return errors.WithStack(err)
 	}
 
 	// only populated in the case of the /users/{user}/hosts route
 	hgh.user = gimlet.GetVars(r)["user_id"]
 
-	return nil
+	hgh.fields, err = getFieldSelection(vals, model.APIHost{})
+	return err
 }
 
 func (hgh *hostGetHandler) Run(ctx context.Context) gimlet.Responder {
@@ -128,19 +139,8 @@ func (hgh *hostGetHandler) Run(ctx context.Context) gimlet.Responder {
 	lastIndex := len(hosts)
 	if len(hosts) > hgh.limit {
 		lastIndex = hgh.limit
-		err = resp.SetPages(&gimlet.ResponsePages{
-			Next: &gimlet.Page{
-				BaseURL:         hgh.sc.GetURL(),
-				LimitQueryParam: "limit",
-				KeyQueryParam:   "host_id",
-				Relation:        "next",
-				Key:             hosts[hgh.limit].Id,
-				Limit:           hgh.limit,
-			},
-		})
-		if err != nil {
-			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err,
-				"problem paginating response"))
+		if err = hgh.page.SetNext(resp, hgh.sc.GetURL(), hgh.limit, hosts[hgh.limit].Id); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
 		}
 	}
 
@@ -150,7 +150,7 @@ func (hgh *hostGetHandler) Run(ctx context.Context) gimlet.Responder {
 	// Grab the taskIds associated as running on the hosts.
 	taskIds := []string{}
 	for _, h := range hosts {
-		if h.RunningTask != "" {
+		if h.RunningTask != "" && hgh.fields.Includes("running_task") {
 			taskIds = append(taskIds, h.RunningTask)
 		}
 	}
@@ -171,7 +171,7 @@ func (hgh *hostGetHandler) Run(ctx context.Context) gimlet.Responder {
 			return gimlet.MakeJSONErrorResponder(err)
 		}
 
-		if h.RunningTask != "" {
+		if h.RunningTask != "" && hgh.fields.Includes("running_task") {
 			runningTask, ok := tasksById[h.RunningTask]
 			if !ok {
 				continue
@@ -187,7 +187,7 @@ func (hgh *hostGetHandler) Run(ctx context.Context) gimlet.Responder {
 		}
 	}
 
-	return resp
+	return projectResponse(resp, hgh.fields)
 }
 
 func getLimit(vals url.Values) (int, error) {
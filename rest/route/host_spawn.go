@@ -10,6 +10,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
@@ -295,6 +296,311 @@ func (h *hostExtendExpirationHandler) Run(ctx context.Context) gimlet.Responder
 	return gimlet.NewJSONResponse(struct{}{})
 }
 
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hosts/{host_id}/stop
+//
+
+type hostStopHandler struct {
+	hostID string
+	sc     data.Connector
+}
+
+func makeStopHostRoute(sc data.Connector) gimlet.RouteHandler {
+	return &hostStopHandler{
+		sc: sc,
+	}
+}
+
+func (h *hostStopHandler) Factory() gimlet.RouteHandler {
+	return &hostStopHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *hostStopHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	return err
+}
+
+func (h *hostStopHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	host, err := h.sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if host.Status != evergreen.HostRunning {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("host %s is %s, only running hosts can be stopped", host.Id, host.Status),
+		})
+	}
+
+	if err := h.sc.StopHost(ctx, host, u.Username()); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hosts/{host_id}/start
+//
+
+type hostStartHandler struct {
+	hostID string
+	sc     data.Connector
+}
+
+func makeStartHostRoute(sc data.Connector) gimlet.RouteHandler {
+	return &hostStartHandler{
+		sc: sc,
+	}
+}
+
+func (h *hostStartHandler) Factory() gimlet.RouteHandler {
+	return &hostStartHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *hostStartHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	return err
+}
+
+func (h *hostStartHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	host, err := h.sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if host.Status != evergreen.HostStopped {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("host %s is %s, only stopped hosts can be started", host.Id, host.Status),
+		})
+	}
+
+	if err := h.sc.StartHost(ctx, host, u.Username()); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hosts/{host_id}/attach
+//
+
+type hostAttachVolumeHandler struct {
+	hostID     string
+	attachment host.VolumeAttachment
+	sc         data.Connector
+}
+
+func makeAttachVolumeRoute(sc data.Connector) gimlet.RouteHandler {
+	return &hostAttachVolumeHandler{
+		sc: sc,
+	}
+}
+
+func (h *hostAttachVolumeHandler) Factory() gimlet.RouteHandler {
+	return &hostAttachVolumeHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *hostAttachVolumeHandler) Parse(ctx context.Context, r *http.Request) error {
+	volume := model.APIVolumeAttachment{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &volume); err != nil {
+		return err
+	}
+
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	if err != nil {
+		return err
+	}
+
+	h.attachment = host.VolumeAttachment{
+		VolumeID:   model.FromAPIString(volume.VolumeID),
+		DeviceName: model.FromAPIString(volume.DeviceName),
+	}
+	if h.attachment.VolumeID == "" || h.attachment.DeviceName == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a volume ID and a device name",
+		}
+	}
+
+	return nil
+}
+
+func (h *hostAttachVolumeHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	host, err := h.sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if host.Status != evergreen.HostRunning && host.Status != evergreen.HostStopped {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("host %s is %s, volumes can only be attached to running or stopped hosts", host.Id, host.Status),
+		})
+	}
+
+	if err := h.sc.AttachVolume(ctx, host, h.attachment, u.Username()); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hosts/{host_id}/detach
+//
+
+type hostDetachVolumeHandler struct {
+	hostID   string
+	volumeID string
+	sc       data.Connector
+}
+
+func makeDetachVolumeRoute(sc data.Connector) gimlet.RouteHandler {
+	return &hostDetachVolumeHandler{
+		sc: sc,
+	}
+}
+
+func (h *hostDetachVolumeHandler) Factory() gimlet.RouteHandler {
+	return &hostDetachVolumeHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *hostDetachVolumeHandler) Parse(ctx context.Context, r *http.Request) error {
+	volume := model.APIVolumeAttachment{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &volume); err != nil {
+		return err
+	}
+
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	if err != nil {
+		return err
+	}
+
+	h.volumeID = model.FromAPIString(volume.VolumeID)
+	if h.volumeID == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a volume ID",
+		}
+	}
+
+	return nil
+}
+
+func (h *hostDetachVolumeHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	host, err := h.sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if host.GetVolume(h.volumeID) == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("volume %s is not attached to host %s", h.volumeID, host.Id),
+		})
+	}
+
+	if err := h.sc.DetachVolume(ctx, host, h.volumeID); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hosts/{host_id}/transfer
+//
+
+type hostTransferHandler struct {
+	hostID   string
+	newOwner string
+	sc       data.Connector
+}
+
+func makeTransferHostRoute(sc data.Connector) gimlet.RouteHandler {
+	return &hostTransferHandler{
+		sc: sc,
+	}
+}
+
+func (h *hostTransferHandler) Factory() gimlet.RouteHandler {
+	return &hostTransferHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *hostTransferHandler) Parse(ctx context.Context, r *http.Request) error {
+	hostModify := model.APISpawnHostModify{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &hostModify); err != nil {
+		return err
+	}
+
+	var err error
+	h.hostID, err = validateHostID(gimlet.GetVars(r)["host_id"])
+	if err != nil {
+		return err
+	}
+
+	h.newOwner = strings.TrimSpace(model.FromAPIString(hostModify.NewOwner))
+	if h.newOwner == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a new owner",
+		}
+	}
+
+	return nil
+}
+
+func (h *hostTransferHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	host, err := h.sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if host.Status == evergreen.HostTerminated {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "cannot transfer a terminated host",
+		})
+	}
+	if host.StartedBy == h.newOwner {
+		return gimlet.NewJSONResponse(struct{}{})
+	}
+
+	if err := h.sc.TransferHost(host, h.newOwner, u.Username()); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // utility functions
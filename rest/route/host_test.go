@@ -16,6 +16,8 @@ import (
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -508,3 +510,98 @@ func getMockHostsConnector() *data.MockConnector {
 	connector.SetSuperUsers([]string{"root"})
 	return connector
 }
+
+func TestHostStopAndStartHandlers(t *testing.T) {
+	assert := assert.New(t)
+	sc := getMockHostsConnector()
+	ctx := gimlet.AttachUser(context.Background(), sc.MockUserConnector.CachedUsers["user0"])
+
+	stop := makeStopHostRoute(sc).Factory().(*hostStopHandler)
+	stop.hostID = "host1"
+	assert.Equal(http.StatusBadRequest, stop.Run(ctx).Status())
+
+	stop.hostID = "host2"
+	assert.Equal(http.StatusOK, stop.Run(ctx).Status())
+	assert.Equal(evergreen.HostStopped, sc.CachedHosts[1].Status)
+
+	// a stopped host can't be stopped again, but can be started
+	assert.Equal(http.StatusBadRequest, stop.Run(ctx).Status())
+
+	start := makeStartHostRoute(sc).Factory().(*hostStartHandler)
+	start.hostID = "host4"
+	assert.Equal(http.StatusBadRequest, start.Run(ctx).Status())
+
+	start.hostID = "host2"
+	assert.Equal(http.StatusOK, start.Run(ctx).Status())
+	assert.Equal(evergreen.HostRunning, sc.CachedHosts[1].Status)
+
+	// only the owner or a superuser may stop a host
+	stop.hostID = "host2"
+	assert.Equal(http.StatusUnauthorized, stop.Run(gimlet.AttachUser(context.Background(), sc.MockUserConnector.CachedUsers["user1"])).Status())
+	assert.Equal(evergreen.HostRunning, sc.CachedHosts[1].Status)
+}
+
+func TestHostVolumeHandlers(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	sc := getMockHostsConnector()
+	sc.MockHostConnector.VolumeOwners = map[string]string{"vol-1": "user0", "vol-3": "user1"}
+	ctx := gimlet.AttachUser(context.Background(), sc.MockUserConnector.CachedUsers["user0"])
+
+	attach := makeAttachVolumeRoute(sc).Factory().(*hostAttachVolumeHandler)
+	attach.hostID = "host2"
+
+	// users can only attach their own volumes
+	attach.attachment = host.VolumeAttachment{VolumeID: "vol-3", DeviceName: "/dev/sdf"}
+	assert.Equal(http.StatusUnauthorized, attach.Run(ctx).Status())
+	assert.Empty(sc.CachedHosts[1].Volumes)
+
+	attach.attachment = host.VolumeAttachment{VolumeID: "vol-1", DeviceName: "/dev/sdf"}
+	assert.Equal(http.StatusOK, attach.Run(ctx).Status())
+	require.Len(sc.CachedHosts[1].Volumes, 1)
+	assert.Equal(host.VolumeAttachment{VolumeID: "vol-1", DeviceName: "/dev/sdf"}, sc.CachedHosts[1].Volumes[0])
+
+	// a volume can only be attached to one host
+	attach.hostID = "host4"
+	assert.Equal(http.StatusBadRequest, attach.Run(ctx).Status())
+	assert.Empty(sc.CachedHosts[3].Volumes)
+
+	attach.hostID = "host1"
+	assert.Equal(http.StatusBadRequest, attach.Run(ctx).Status())
+
+	detach := makeDetachVolumeRoute(sc).Factory().(*hostDetachVolumeHandler)
+	detach.hostID = "host2"
+	detach.volumeID = "vol-2"
+	assert.Equal(http.StatusNotFound, detach.Run(ctx).Status())
+
+	detach.volumeID = "vol-1"
+	assert.Equal(http.StatusOK, detach.Run(ctx).Status())
+	assert.Empty(sc.CachedHosts[1].Volumes)
+}
+
+func TestHostTransferHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	sc := getMockHostsConnector()
+	ctx := gimlet.AttachUser(context.Background(), sc.MockUserConnector.CachedUsers["user0"])
+
+	h := makeTransferHostRoute(sc).Factory().(*hostTransferHandler)
+	r, err := makeMockHostRequest(model.APISpawnHostModify{})
+	require.NoError(err)
+	assert.Error(h.Parse(ctx, r))
+
+	h.hostID = "host2"
+	h.newOwner = "nobody"
+	assert.Equal(http.StatusNotFound, h.Run(ctx).Status())
+	assert.Equal("user0", sc.CachedHosts[1].StartedBy)
+
+	h.newOwner = "user1"
+	assert.Equal(http.StatusOK, h.Run(ctx).Status())
+	assert.Equal("user1", sc.CachedHosts[1].StartedBy)
+
+	// the previous owner no longer owns the host
+	assert.Equal(http.StatusUnauthorized, h.Run(ctx).Status())
+
+	h.hostID = "host1"
+	assert.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+}
@@ -3,6 +3,7 @@ package route
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/auth"
@@ -10,6 +11,8 @@ import (
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 )
 
 type (
@@ -72,6 +75,64 @@ func NewProjectContextMiddleware(sc data.Connector) gimlet.Middleware {
 	}
 }
 
+type readOnlyMiddleware struct {
+	sc data.Connector
+}
+
+// NewReadOnlyMiddleware returns middleware that rejects requests by users
+// to change anything through the REST API while API writes are disabled.
+// Superusers are exempt, so that they can still respond to an incident and
+// enable writes again, as are requests without a user, such as webhooks.
+func NewReadOnlyMiddleware(sc data.Connector) gimlet.Middleware {
+	return &readOnlyMiddleware{
+		sc: sc,
+	}
+}
+
+func (m *readOnlyMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		next(rw, r)
+		return
+	}
+	if !isRESTv2Path(r.URL.Path) {
+		next(rw, r)
+		return
+	}
+	u := gimlet.GetUser(r.Context())
+	if u == nil || auth.IsSuperUser(m.sc.GetSuperUsers(), u) {
+		next(rw, r)
+		return
+	}
+
+	flags, err := m.sc.GetServiceFlags()
+	if err != nil {
+		// an outage of the flags should not take the API down with it
+		grip.Error(message.WrapError(err, message.Fields{
+			"message": "problem fetching service flags, allowing API write",
+			"path":    r.URL.Path,
+		}))
+		next(rw, r)
+		return
+	}
+	if flags.APIWritesDisabled {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "the API is read-only while writes are disabled",
+		}))
+		return
+	}
+
+	next(rw, r)
+}
+
+// isRESTv2Path returns whether the path is a REST v2 route, which is
+// published under both the UI and the API prefixes.
+func isRESTv2Path(path string) bool {
+	return strings.HasPrefix(path, evergreen.APIRoutePrefixV2+"/") ||
+		strings.HasPrefix(path, "/"+evergreen.APIRoutePrefix+evergreen.APIRoutePrefixV2+"/")
+}
+
 // GetProjectContext returns the project context associated with a
 // given request.
 func GetProjectContext(ctx context.Context) *model.Context {
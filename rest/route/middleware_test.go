@@ -6,12 +6,14 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/gimlet"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 )
 
 // PrefetchProjectContext gets the information related to the project that the request contains
@@ -84,3 +86,37 @@ func TestPrefetchProject(t *testing.T) {
 		})
 	})
 }
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	sc := &data.MockConnector{}
+	sc.SetSuperUsers([]string{"root"})
+	sc.MockAdminConnector.MockSettings = &evergreen.Settings{}
+	m := NewReadOnlyMiddleware(sc)
+
+	serve := func(method, path string, u *user.DBUser) int {
+		r := httptest.NewRequest(method, path, nil)
+		if u != nil {
+			r = r.WithContext(gimlet.AttachUser(r.Context(), u))
+		}
+		rw := httptest.NewRecorder()
+		m.ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) {
+			rw.WriteHeader(http.StatusOK)
+		})
+		return rw.Code
+	}
+	annie := &user.DBUser{Id: "annie"}
+
+	assert.Equal(http.StatusOK, serve(http.MethodPost, "/rest/v2/hosts", annie))
+
+	sc.MockAdminConnector.MockSettings.ServiceFlags.APIWritesDisabled = true
+	assert.Equal(http.StatusServiceUnavailable, serve(http.MethodPost, "/rest/v2/hosts", annie))
+	assert.Equal(http.StatusServiceUnavailable, serve(http.MethodPatch, "/api/rest/v2/tasks/t1", annie))
+	assert.Equal(http.StatusServiceUnavailable, serve(http.MethodDelete, "/rest/v2/hosts/h1/drain", annie))
+	assert.Equal(http.StatusOK, serve(http.MethodGet, "/rest/v2/hosts", annie))
+	assert.Equal(http.StatusOK, serve(http.MethodPost, "/rest/v2/admin/service_flags", &user.DBUser{Id: "root"}))
+	assert.Equal(http.StatusOK, serve(http.MethodPost, "/rest/v2/hooks/github", nil))
+	assert.Equal(http.StatusOK, serve(http.MethodPost, "/api/2/task/t1/start", annie))
+	assert.Equal(http.StatusOK, serve(http.MethodPost, "/rest/v2hosts", annie))
+}
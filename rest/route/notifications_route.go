@@ -2,10 +2,21 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/amboy"
+	"github.com/pkg/errors"
 )
 
 func makeFetchNotifcationStatusRoute(sc data.Connector) gimlet.RouteHandler {
@@ -28,3 +39,865 @@ func (gh *notificationsStatusHandler) Run(ctx context.Context) gimlet.Responder
 
 	return gimlet.NewJSONResponse(stats)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/status/notifications/analytics
+
+const (
+	defaultAnalyticsWindow   = 24 * time.Hour
+	defaultAnalyticsInterval = time.Hour
+	defaultAnalyticsLimit    = 10
+	minAnalyticsInterval     = time.Minute
+	maxAnalyticsIntervals    = 1000
+)
+
+func makeFetchNotificationAnalytics(sc data.Connector) gimlet.RouteHandler {
+	return &notificationAnalyticsHandler{sc: sc}
+}
+
+type notificationAnalyticsHandler struct {
+	start    time.Time
+	end      time.Time
+	interval time.Duration
+	limit    int
+	sc       data.Connector
+}
+
+func (h *notificationAnalyticsHandler) Factory() gimlet.RouteHandler {
+	return &notificationAnalyticsHandler{sc: h.sc}
+}
+
+func (h *notificationAnalyticsHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	vals := r.URL.Query()
+
+	h.end = time.Now()
+	if end := vals.Get("end"); end != "" {
+		h.end, err = time.ParseInLocation(time.RFC3339, end, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("problem parsing time from '%s' (%s)", end, err.Error()),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	h.start = h.end.Add(-defaultAnalyticsWindow)
+	if start := vals.Get("start"); start != "" {
+		h.start, err = time.ParseInLocation(time.RFC3339, start, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("problem parsing time from '%s' (%s)", start, err.Error()),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	if !h.start.Before(h.end) {
+		return gimlet.ErrorResponse{
+			Message:    "start must be before end",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.interval = defaultAnalyticsInterval
+	if interval := vals.Get("interval"); interval != "" {
+		h.interval, err = time.ParseDuration(interval)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("problem parsing duration from '%s' (%s)", interval, err.Error()),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	if h.interval < minAnalyticsInterval {
+		return gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("interval must be at least %s", minAnalyticsInterval),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	if h.end.Sub(h.start)/h.interval > maxAnalyticsIntervals {
+		return gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("window cannot span more than %d intervals", maxAnalyticsIntervals),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	h.limit = defaultAnalyticsLimit
+	if limit := vals.Get("limit"); limit != "" {
+		h.limit, err = strconv.Atoi(limit)
+		if err != nil || h.limit < 1 {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("invalid limit '%s'", limit),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *notificationAnalyticsHandler) Run(ctx context.Context) gimlet.Responder {
+	analytics, err := h.sc.GetNotificationAnalytics(h.start, h.end, h.interval, h.limit)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(analytics)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/notifications
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+func makeFetchNotificationAuditEntries(sc data.Connector) gimlet.RouteHandler {
+	return &notificationAuditHandler{sc: sc}
+}
+
+type notificationAuditHandler struct {
+	filter notification.AuditFilter
+	limit  int
+	page   cursorPage
+	sc     data.Connector
+}
+
+func (h *notificationAuditHandler) Factory() gimlet.RouteHandler {
+	return &notificationAuditHandler{sc: h.sc}
+}
+
+func (h *notificationAuditHandler) Parse(ctx context.Context, r *http.Request) error {
+	var (
+		vals url.Values
+		err  error
+	)
+	h.page, h.limit, vals, err = getCursorPage(r, defaultAuditLimit, maxAuditLimit)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	h.filter = notification.AuditFilter{
+		SubscriberType: vals.Get("subscriber_type"),
+		Target:         vals.Get("target"),
+		Initiator:      vals.Get("initiator"),
+		SubscriptionID: vals.Get("subscription_id"),
+		NotificationID: vals.Get("notification_id"),
+		Result:         vals.Get("result"),
+	}
+	if h.filter.Result != "" && !util.StringSliceContains(notification.AuditResults, h.filter.Result) {
+		return gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("invalid result '%s'", h.filter.Result),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if before := vals.Get("before"); before != "" {
+		h.filter.Before, err = time.ParseInLocation(time.RFC3339, before, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("problem parsing time from '%s' (%s)", before, err.Error()),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	if after := vals.Get("after"); after != "" {
+		h.filter.After, err = time.ParseInLocation(time.RFC3339, after, time.UTC)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("problem parsing time from '%s' (%s)", after, err.Error()),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	if _, err = h.page.Start(&h.filter.StartTime, &h.filter.StartID); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return nil
+}
+
+func (h *notificationAuditHandler) Run(ctx context.Context) gimlet.Responder {
+	entries, err := h.sc.FindNotificationAuditEntries(h.filter, h.limit+1)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	if err = resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	if len(entries) > h.limit {
+		next := entries[h.limit]
+		if err = h.page.SetNext(resp, h.sc.GetURL(), h.limit, time.Time(next.Timestamp), model.FromAPIString(next.ID)); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		entries = entries[:h.limit]
+	}
+
+	for _, entry := range entries {
+		if err = resp.AddData(entry); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return resp
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/*?dry_run=true
+
+// parseDryRun returns whether the request only composes notifications,
+// returning the messages they would send without sending them.
+func parseDryRun(r *http.Request) (bool, error) {
+	dryRun := r.URL.Query().Get("dry_run")
+	if dryRun == "" {
+		return false, nil
+	}
+	out, err := strconv.ParseBool(dryRun)
+	if err != nil {
+		return false, gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("invalid dry_run '%s'", dryRun),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return out, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/webhook
+
+func makeSendWebhook(sc data.Connector, queue amboy.Queue) gimlet.RouteHandler {
+	return &webhookPostHandler{sc: sc, queue: queue}
+}
+
+type webhookPostHandler struct {
+	webhook model.APIWebhook
+	sc      data.Connector
+	queue   amboy.Queue
+	userID  string
+	dryRun  bool
+}
+
+func (h *webhookPostHandler) Factory() gimlet.RouteHandler {
+	return &webhookPostHandler{sc: h.sc, queue: h.queue}
+}
+
+func (h *webhookPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	if h.dryRun, err = parseDryRun(r); err != nil {
+		return err
+	}
+	if err = gimlet.GetJSON(r.Body, &h.webhook); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err = h.webhook.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if u := gimlet.GetUser(ctx); u != nil {
+		h.userID = u.Username()
+	}
+
+	return nil
+}
+
+func (h *webhookPostHandler) Run(ctx context.Context) gimlet.Responder {
+	n, err := h.sc.SendWebhook(h.queue, &h.webhook, h.userID, h.dryRun)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(n)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/email
+
+func makeSendEmail(sc data.Connector, queue amboy.Queue) gimlet.RouteHandler {
+	return &emailPostHandler{sc: sc, queue: queue}
+}
+
+type emailPostHandler struct {
+	email  model.APIEmail
+	sc     data.Connector
+	queue  amboy.Queue
+	userID string
+	dryRun bool
+}
+
+func (h *emailPostHandler) Factory() gimlet.RouteHandler {
+	return &emailPostHandler{sc: h.sc, queue: h.queue}
+}
+
+func (h *emailPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	if h.dryRun, err = parseDryRun(r); err != nil {
+		return err
+	}
+	if err = gimlet.GetJSON(r.Body, &h.email); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err = h.email.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if u := gimlet.GetUser(ctx); u != nil {
+		h.userID = u.Username()
+	}
+
+	return nil
+}
+
+func (h *emailPostHandler) Run(ctx context.Context) gimlet.Responder {
+	notifications, err := h.sc.SendEmail(h.queue, &h.email, h.userID, h.dryRun)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(notifications)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/slack
+
+func makeSendSlack(sc data.Connector, queue amboy.Queue) gimlet.RouteHandler {
+	return &slackPostHandler{sc: sc, queue: queue}
+}
+
+type slackPostHandler struct {
+	slack  model.APISlack
+	sc     data.Connector
+	queue  amboy.Queue
+	userID string
+	dryRun bool
+}
+
+func (h *slackPostHandler) Factory() gimlet.RouteHandler {
+	return &slackPostHandler{sc: h.sc, queue: h.queue}
+}
+
+func (h *slackPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	if h.dryRun, err = parseDryRun(r); err != nil {
+		return err
+	}
+	if err = gimlet.GetJSON(r.Body, &h.slack); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err = h.slack.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if u := gimlet.GetUser(ctx); u != nil {
+		h.userID = u.Username()
+	}
+
+	return nil
+}
+
+func (h *slackPostHandler) Run(ctx context.Context) gimlet.Responder {
+	n, err := h.sc.SendSlack(h.queue, &h.slack, h.userID, h.dryRun)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(n)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/opsgenie
+
+func makeSendOpsgenie(sc data.Connector, queue amboy.Queue) gimlet.RouteHandler {
+	return &opsgeniePostHandler{sc: sc, queue: queue}
+}
+
+type opsgeniePostHandler struct {
+	alert  model.APIOpsgenieAlert
+	sc     data.Connector
+	queue  amboy.Queue
+	userID string
+	dryRun bool
+}
+
+func (h *opsgeniePostHandler) Factory() gimlet.RouteHandler {
+	return &opsgeniePostHandler{sc: h.sc, queue: h.queue}
+}
+
+func (h *opsgeniePostHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	if h.dryRun, err = parseDryRun(r); err != nil {
+		return err
+	}
+	if err = gimlet.GetJSON(r.Body, &h.alert); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err = h.alert.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if u := gimlet.GetUser(ctx); u != nil {
+		h.userID = u.Username()
+	}
+
+	return nil
+}
+
+func (h *opsgeniePostHandler) Run(ctx context.Context) gimlet.Responder {
+	n, err := h.sc.SendOpsgenie(h.queue, &h.alert, h.userID, h.dryRun)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(n)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/jira_issue/{key}/transition
+
+func makeTransitionJiraIssue(sc data.Connector) gimlet.RouteHandler {
+	return &jiraIssueTransitionHandler{sc: sc}
+}
+
+type jiraIssueTransitionHandler struct {
+	key        string
+	transition model.APIJiraIssueTransition
+	sc         data.Connector
+}
+
+func (h *jiraIssueTransitionHandler) Factory() gimlet.RouteHandler {
+	return &jiraIssueTransitionHandler{sc: h.sc}
+}
+
+func (h *jiraIssueTransitionHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.key = gimlet.GetVars(r)["key"]
+	if h.key == "" {
+		return errors.New("JIRA issue key cannot be empty")
+	}
+	if err := gimlet.GetJSON(r.Body, &h.transition); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err := h.transition.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (h *jiraIssueTransitionHandler) Run(ctx context.Context) gimlet.Responder {
+	issue, err := h.sc.TransitionJiraIssue(h.key, &h.transition)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(issue)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/jira_issue/{key}/attachments
+
+func makeAttachFilesToJiraIssue(sc data.Connector) gimlet.RouteHandler {
+	return &jiraIssueAttachmentsHandler{sc: sc}
+}
+
+type jiraIssueAttachmentsHandler struct {
+	key         string
+	attachments model.APIJiraIssueAttachments
+	sc          data.Connector
+}
+
+func (h *jiraIssueAttachmentsHandler) Factory() gimlet.RouteHandler {
+	return &jiraIssueAttachmentsHandler{sc: h.sc}
+}
+
+func (h *jiraIssueAttachmentsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.key = gimlet.GetVars(r)["key"]
+	if h.key == "" {
+		return errors.New("JIRA issue key cannot be empty")
+	}
+	if err := gimlet.GetJSON(r.Body, &h.attachments); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err := h.attachments.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (h *jiraIssueAttachmentsHandler) Run(ctx context.Context) gimlet.Responder {
+	attachments, err := h.sc.AttachFilesToJiraIssue(h.key, &h.attachments)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(attachments)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/template
+
+func makeSendTemplateNotification(sc data.Connector, queue amboy.Queue) gimlet.RouteHandler {
+	return &templateNotificationPostHandler{sc: sc, queue: queue}
+}
+
+type templateNotificationPostHandler struct {
+	req    model.APITemplateNotification
+	sc     data.Connector
+	queue  amboy.Queue
+	userID string
+	dryRun bool
+}
+
+func (h *templateNotificationPostHandler) Factory() gimlet.RouteHandler {
+	return &templateNotificationPostHandler{sc: h.sc, queue: h.queue}
+}
+
+func (h *templateNotificationPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	if h.dryRun, err = parseDryRun(r); err != nil {
+		return err
+	}
+	if err = gimlet.GetJSON(r.Body, &h.req); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err = h.req.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	if u := gimlet.GetUser(ctx); u != nil {
+		h.userID = u.Username()
+	}
+
+	return nil
+}
+
+func (h *templateNotificationPostHandler) Run(ctx context.Context) gimlet.Responder {
+	n, err := h.sc.SendTemplateNotification(h.queue, &h.req, h.userID, h.dryRun)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(n)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/notifications/templates/{template_name}
+
+func makeFetchNotificationTemplate(sc data.Connector) gimlet.RouteHandler {
+	return &notificationTemplateGetHandler{sc: sc}
+}
+
+type notificationTemplateGetHandler struct {
+	name string
+	sc   data.Connector
+}
+
+func (h *notificationTemplateGetHandler) Factory() gimlet.RouteHandler {
+	return &notificationTemplateGetHandler{sc: h.sc}
+}
+
+func (h *notificationTemplateGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.name = gimlet.GetVars(r)["template_name"]
+	return nil
+}
+
+func (h *notificationTemplateGetHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := h.sc.GetNotificationTemplate(h.name)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(t)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/notifications/templates/{template_name}
+
+func makeSaveNotificationTemplate(sc data.Connector) gimlet.RouteHandler {
+	return &notificationTemplatePutHandler{sc: sc}
+}
+
+type notificationTemplatePutHandler struct {
+	template model.APINotificationTemplate
+	sc       data.Connector
+}
+
+func (h *notificationTemplatePutHandler) Factory() gimlet.RouteHandler {
+	return &notificationTemplatePutHandler{sc: h.sc}
+}
+
+func (h *notificationTemplatePutHandler) Parse(ctx context.Context, r *http.Request) error {
+	if err := gimlet.GetJSON(r.Body, &h.template); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	h.template.Name = model.ToAPIString(gimlet.GetVars(r)["template_name"])
+
+	return nil
+}
+
+func (h *notificationTemplatePutHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := h.sc.SaveNotificationTemplate(&h.template)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(t)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/notifications/{notification_id}
+
+func makeFetchNotification(sc data.Connector) gimlet.RouteHandler {
+	return &notificationGetHandler{sc: sc}
+}
+
+type notificationGetHandler struct {
+	notificationID string
+	sc             data.Connector
+}
+
+func (h *notificationGetHandler) Factory() gimlet.RouteHandler {
+	return &notificationGetHandler{sc: h.sc}
+}
+
+func (h *notificationGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.notificationID = gimlet.GetVars(r)["notification_id"]
+	return nil
+}
+
+func (h *notificationGetHandler) Run(ctx context.Context) gimlet.Responder {
+	n, err := h.sc.GetNotification(h.notificationID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(n)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/{notification_id}/incident
+
+func makeLinkNotificationToIncident(sc data.Connector) gimlet.RouteHandler {
+	return &notificationIncidentPostHandler{sc: sc}
+}
+
+type notificationIncidentPostHandler struct {
+	notificationID string
+	link           model.APIIncidentLink
+	sc             data.Connector
+}
+
+func (h *notificationIncidentPostHandler) Factory() gimlet.RouteHandler {
+	return &notificationIncidentPostHandler{sc: h.sc}
+}
+
+func (h *notificationIncidentPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.notificationID = gimlet.GetVars(r)["notification_id"]
+	if err := gimlet.GetJSON(r.Body, &h.link); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if err := h.link.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (h *notificationIncidentPostHandler) Run(ctx context.Context) gimlet.Responder {
+	n, err := h.sc.GetNotification(h.notificationID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if err = checkIncidentAdmin(ctx, h.sc, model.FromAPIString(n.SubscriptionID), "link incidents to notifications"); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	incident, err := h.sc.LinkNotificationToIncident(h.notificationID, &h.link)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(incident)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/notifications/{notification_id}/acknowledge
+
+func makeAcknowledgeNotification(sc data.Connector) gimlet.RouteHandler {
+	return &notificationAcknowledgeHandler{sc: sc}
+}
+
+type notificationAcknowledgeHandler struct {
+	notificationID string
+	user           string
+	sc             data.Connector
+}
+
+func (h *notificationAcknowledgeHandler) Factory() gimlet.RouteHandler {
+	return &notificationAcknowledgeHandler{sc: h.sc}
+}
+
+func (h *notificationAcknowledgeHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.notificationID = gimlet.GetVars(r)["notification_id"]
+	h.user = MustHaveUser(ctx).Username()
+
+	return nil
+}
+
+func (h *notificationAcknowledgeHandler) Run(ctx context.Context) gimlet.Responder {
+	escalation, err := h.sc.AcknowledgeNotification(h.notificationID, h.user)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(escalation)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/incidents/{incident_id}
+
+func makeFetchIncident(sc data.Connector) gimlet.RouteHandler {
+	return &incidentGetHandler{sc: sc}
+}
+
+type incidentGetHandler struct {
+	incidentID string
+	sc         data.Connector
+}
+
+func (h *incidentGetHandler) Factory() gimlet.RouteHandler {
+	return &incidentGetHandler{sc: h.sc}
+}
+
+func (h *incidentGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.incidentID = gimlet.GetVars(r)["incident_id"]
+	return nil
+}
+
+func (h *incidentGetHandler) Run(ctx context.Context) gimlet.Responder {
+	incident, err := h.sc.GetIncident(h.incidentID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(incident)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/incidents/{incident_id}/resolve
+
+func makeResolveIncident(sc data.Connector) gimlet.RouteHandler {
+	return &incidentResolveHandler{sc: sc}
+}
+
+type incidentResolveHandler struct {
+	incidentID string
+	sc         data.Connector
+}
+
+func (h *incidentResolveHandler) Factory() gimlet.RouteHandler {
+	return &incidentResolveHandler{sc: h.sc}
+}
+
+func (h *incidentResolveHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.incidentID = gimlet.GetVars(r)["incident_id"]
+	return nil
+}
+
+func (h *incidentResolveHandler) Run(ctx context.Context) gimlet.Responder {
+	incident, err := h.sc.GetIncident(h.incidentID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if err = checkIncidentAdmin(ctx, h.sc, model.FromAPIString(incident.SubscriptionID), "resolve incidents"); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	incident, err = h.sc.ResolveIncident(h.incidentID)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(incident)
+}
+
+// checkIncidentAdmin returns an error unless the user is a superuser, or an
+// admin of the project that owns the subscription that the incident is, or
+// would be, attached to.
+func checkIncidentAdmin(ctx context.Context, sc data.Connector, subscriptionID, action string) error {
+	u := MustHaveUser(ctx)
+	if util.StringSliceContains(sc.GetSuperUsers(), u.Username()) {
+		return nil
+	}
+
+	subscription, err := sc.FindSubscriptionByID(subscriptionID)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "error finding subscription '%s'", subscriptionID).Error(),
+		}
+	}
+	if subscription == nil || subscription.OwnerType != event.OwnerTypeProject {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("only superusers can %s outside of a project", action),
+		}
+	}
+
+	projectRef, err := sc.FindProjectByBranch(subscription.Owner)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "error finding project '%s'", subscription.Owner).Error(),
+		}
+	}
+	if projectRef == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", subscription.Owner),
+		}
+	}
+
+	return checkProjectRole(ctx, sc, projectRef, user.RoleProjectAdmin, action)
+}
@@ -1,17 +1,23 @@
 package route
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -125,3 +131,262 @@ func (s *notificationSuite) TestStatsCollector() {
 	s.Equal(1, stats.PendingNotificationsByType.Slack)
 	s.Equal(1, stats.PendingNotificationsByType.GithubPullRequest)
 }
+
+func TestWebhookPostHandler(t *testing.T) {
+	assert := assert.New(t)
+
+	h := makeSendWebhook(&data.MockConnector{}, nil).(*webhookPostHandler)
+	h.webhook = model.APIWebhook{
+		URL:     model.ToAPIString("https://example.com/hook"),
+		Secret:  model.ToAPIString("shh"),
+		Headers: map[string][]string{"X-Header": {"value"}},
+		Payload: []byte(`{"status":"ok"}`),
+	}
+
+	resp := h.Run(context.Background())
+	assert.Equal(http.StatusOK, resp.Status())
+	n, ok := resp.Data().(*model.APINotification)
+	assert.True(ok)
+	assert.NotEmpty(model.FromAPIString(n.ID))
+	assert.Equal(event.EvergreenWebhookSubscriberType, model.FromAPIString(n.SubscriberType))
+
+	// webhooks with the same idempotency key are the same notification
+	h.webhook.IdempotencyKey = model.ToAPIString("key")
+	resp = h.Run(context.Background())
+	assert.Equal(http.StatusOK, resp.Status())
+	first := model.FromAPIString(resp.Data().(*model.APINotification).ID)
+	resp = h.Run(context.Background())
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Equal(first, model.FromAPIString(resp.Data().(*model.APINotification).ID))
+
+	// idempotency keys are scoped to the user sending the webhook
+	h.userID = "other"
+	resp = h.Run(context.Background())
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.NotEqual(first, model.FromAPIString(resp.Data().(*model.APINotification).ID))
+
+	// dry runs return the composed message
+	h.dryRun = true
+	resp = h.Run(context.Background())
+	assert.Equal(http.StatusOK, resp.Status())
+	n = resp.Data().(*model.APINotification)
+	if assert.NotNil(n.Preview) {
+		assert.Equal("webhook", model.FromAPIString(n.Preview.Sender))
+		assert.Equal(`{"status":"ok"}`, model.FromAPIString(n.Preview.Message))
+	}
+	h.dryRun = false
+
+	h.webhook.Secret = nil
+	resp = h.Run(context.Background())
+	assert.Equal(http.StatusBadRequest, resp.Status())
+}
+
+func TestNotificationAnalyticsHandlerParse(t *testing.T) {
+	assert := assert.New(t)
+
+	h := makeFetchNotificationAnalytics(&data.MockConnector{}).(*notificationAnalyticsHandler)
+	r, err := http.NewRequest(http.MethodGet, "/status/notifications/analytics", nil)
+	assert.NoError(err)
+	assert.NoError(h.Parse(context.Background(), r))
+	assert.Equal(defaultAnalyticsWindow, h.end.Sub(h.start))
+	assert.Equal(defaultAnalyticsInterval, h.interval)
+	assert.Equal(defaultAnalyticsLimit, h.limit)
+
+	h = h.Factory().(*notificationAnalyticsHandler)
+	r, err = http.NewRequest(http.MethodGet, "/status/notifications/analytics?start=2018-06-01T00:00:00Z&end=2018-06-08T00:00:00Z&interval=24h&limit=3", nil)
+	assert.NoError(err)
+	assert.NoError(h.Parse(context.Background(), r))
+	assert.Equal(time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC), h.start)
+	assert.Equal(time.Date(2018, 6, 8, 0, 0, 0, 0, time.UTC), h.end)
+	assert.Equal(24*time.Hour, h.interval)
+	assert.Equal(3, h.limit)
+
+	for _, query := range []string{
+		"start=yesterday",
+		"start=2018-06-08T00:00:00Z&end=2018-06-01T00:00:00Z",
+		"interval=1s",
+		"start=2018-01-01T00:00:00Z&end=2018-06-01T00:00:00Z&interval=1m",
+		"limit=0",
+	} {
+		h = h.Factory().(*notificationAnalyticsHandler)
+		r, err = http.NewRequest(http.MethodGet, "/status/notifications/analytics?"+query, nil)
+		assert.NoError(err)
+		assert.Error(h.Parse(context.Background(), r), query)
+	}
+}
+
+func TestNotificationAuditHandlerParse(t *testing.T) {
+	assert := assert.New(t)
+
+	h := makeFetchNotificationAuditEntries(&data.MockConnector{}).(*notificationAuditHandler)
+	r, err := http.NewRequest(http.MethodGet, "/notifications", nil)
+	assert.NoError(err)
+	assert.NoError(h.Parse(context.Background(), r))
+	assert.Equal(notification.AuditFilter{}, h.filter)
+	assert.Equal(defaultAuditLimit, h.limit)
+
+	h = h.Factory().(*notificationAuditHandler)
+	r, err = http.NewRequest(http.MethodGet, "/notifications?subscriber_type=slack&target=%23evergreen&initiator=me&result=failed&after=2018-06-01T00:00:00Z&limit=5", nil)
+	assert.NoError(err)
+	assert.NoError(h.Parse(context.Background(), r))
+	assert.Equal(notification.AuditFilter{
+		SubscriberType: event.SlackSubscriberType,
+		Target:         "#evergreen",
+		Initiator:      "me",
+		Result:         notification.AuditResultFailed,
+		After:          time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC),
+	}, h.filter)
+	assert.Equal(5, h.limit)
+
+	for _, query := range []string{
+		"result=paged",
+		"before=yesterday",
+		"limit=0",
+		"limit=100000",
+	} {
+		h = h.Factory().(*notificationAuditHandler)
+		r, err = http.NewRequest(http.MethodGet, "/notifications?"+query, nil)
+		assert.NoError(err)
+		assert.Error(h.Parse(context.Background(), r), query)
+	}
+}
+
+func TestNotificationPostHandlersParse(t *testing.T) {
+	assert := assert.New(t)
+
+	parse := func(h gimlet.RouteHandler, body string) error {
+		r, err := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(body))
+		assert.NoError(err)
+		return h.Factory().Parse(context.Background(), r)
+	}
+
+	webhook := makeSendWebhook(&data.MockConnector{}, nil)
+	assert.NoError(parse(webhook, `{"url": "https://example.com/hook", "secret": "shh", "payload": {"a": 1}}`))
+	h := makeSendWebhook(&data.MockConnector{}, nil).(*webhookPostHandler)
+	r, err := http.NewRequest(http.MethodPost, "/?dry_run=true", bytes.NewBufferString(`{"url": "https://example.com/hook", "secret": "shh", "payload": {"a": 1}}`))
+	assert.NoError(err)
+	assert.NoError(h.Parse(context.Background(), r))
+	assert.True(h.dryRun)
+	r, err = http.NewRequest(http.MethodPost, "/?dry_run=maybe", bytes.NewBufferString(`{}`))
+	assert.NoError(err)
+	assert.Error(h.Factory().Parse(context.Background(), r))
+	assert.Error(parse(webhook, `{"url": `))
+	err = parse(webhook, `{"url": "example.com"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "url: 'example.com' is not an absolute http or https URL")
+	assert.Contains(err.Error(), "secret: cannot be empty")
+	resp, ok := err.(gimlet.ErrorResponse)
+	assert.True(ok)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	for _, url := range []string{"http://169.254.169.254/latest/meta-data", "http://localhost:8080/", "https://10.0.0.1/hook", "http://[::1]/"} {
+		err = parse(webhook, `{"url": "`+url+`", "secret": "shh"}`)
+		if assert.Error(err, url) {
+			assert.Contains(err.Error(), "is not a public address")
+		}
+	}
+
+	email := makeSendEmail(&data.MockConnector{}, nil)
+	assert.NoError(parse(email, `{"recipients": ["me@example.com"], "subject": "failed", "html_body": "<p>failed</p>", "attachments": [{"filename": "task.log", "s3_url": "s3://logs/task.log"}]}`))
+	err = parse(email, `{"recipients": ["me"], "attachments": [{"filename": "task.log", "content": "???"}]}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "recipients: 'me' is not a valid address")
+	assert.Contains(err.Error(), "subject: cannot be empty")
+	assert.Contains(err.Error(), "attachments[0]: content is not base64 encoded")
+
+	slack := makeSendSlack(&data.MockConnector{}, nil)
+	assert.NoError(parse(slack, `{"target": "#evergreen", "msg": "task failed", "blocks": [{"type": "section", "text": {"type": "mrkdwn", "text": "*task failed*"}}, {"type": "divider"}, {"type": "actions", "elements": [{"type": "button", "text": "View logs", "url": "https://example.com/logs"}]}]}`))
+	err = parse(slack, `{"target": "evergreen", "blocks": [{"type": "actions", "elements": [{"type": "button", "text": "Restart"}]}]}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "target: 'evergreen' is not a #channel, @user or channel ID")
+	assert.Contains(err.Error(), "blocks[0].elements[0]: one of action_id and url must be set")
+	assert.NoError(parse(slack, `{"target": "#evergreen", "msg": "version failed", "thread_key": "version1"}`))
+	err = parse(slack, `{"target": "#evergreen", "msg": "version fixed", "update_ts": "1500000000.000100"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "is not a channel ID, which is required to update a message")
+
+	opsgenie := makeSendOpsgenie(&data.MockConnector{}, nil)
+	assert.NoError(parse(opsgenie, `{"api_key": "key", "message": "task failed", "alias": "task1", "priority": "P2", "tags": ["evergreen"]}`))
+	err = parse(opsgenie, `{"message": "", "priority": "urgent"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "api_key: cannot be empty")
+	assert.Contains(err.Error(), "message: cannot be empty")
+	assert.Contains(err.Error(), "priority: 'urgent' is not one of P1, P2, P3, P4, P5")
+
+	transition := makeTransitionJiraIssue(&data.MockConnector{})
+	assert.EqualError(parse(transition, `{"transition": "Reopen Issue"}`), "JIRA issue key cannot be empty")
+
+	attachments := makeAttachFilesToJiraIssue(&data.MockConnector{})
+	assert.EqualError(parse(attachments, `{"attachments": [{"filename": "task.log", "content": "bG9n"}]}`), "JIRA issue key cannot be empty")
+
+	incident := makeLinkNotificationToIncident(&data.MockConnector{})
+	assert.NoError(parse(incident, `{"incident_id": "1"}`))
+	assert.NoError(parse(incident, `{"source": "manual", "title": "outage"}`))
+	assert.Error(parse(incident, `[]`))
+	err = parse(incident, `{"incident_id": "1", "title": "outage"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "incident_id: cannot be combined")
+	err = parse(incident, `{"source": "carrier pigeon"}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "source: 'carrier pigeon' is not one of")
+
+	template := makeSendTemplateNotification(&data.MockConnector{}, nil)
+	assert.NoError(parse(template, `{"template": "deploy", "data": {"a": 1}, "subscriber": {"type": "slack", "target": "#evergreen"}}`))
+	assert.NoError(parse(template, `{"template": "deploy", "subscriber": {"type": "jira-issue", "target": {"project": "EVG", "issue_type": "Bug"}}}`))
+	err = parse(template, `{"subscriber": {"type": "email"}}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "template: cannot be empty")
+	assert.Contains(err.Error(), "subscriber: target of 'email' subscriber must be a non-empty string")
+	err = parse(template, `{"template": "deploy", "subscriber": {"type": "github_pull_request", "target": {}}}`)
+	assert.Error(err)
+	assert.Contains(err.Error(), "templates cannot be sent to 'github_pull_request' subscribers")
+}
+
+func TestIncidentRoutesRequireProjectAdmin(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*dbModel.ProjectRef{
+				"proj": {Identifier: "proj", Admins: []string{"admin"}},
+			},
+		},
+		MockSubscriptionConnector: data.MockSubscriptionConnector{
+			MockSubscriptions: []event.Subscription{
+				{ID: "sub1", Owner: "proj", OwnerType: event.OwnerTypeProject},
+				{ID: "sub2", Owner: "me", OwnerType: event.OwnerTypePerson},
+			},
+		},
+		MockNotificationConnector: data.MockNotificationConnector{
+			CachedNotifications: []notification.Notification{
+				{ID: "n1", SubscriptionID: "sub1", Subscriber: event.Subscriber{Type: event.SlackSubscriberType, Target: "#general"}, Payload: &notification.SlackPayload{Body: "hi"}},
+			},
+			CachedIncidents: []notification.Incident{
+				{ID: "i1", SubscriptionID: "sub1"},
+				{ID: "i2", SubscriptionID: "sub2"},
+			},
+		},
+	}
+	sc.SetSuperUsers([]string{"root"})
+	asUser := func(name string) context.Context {
+		return gimlet.AttachUser(context.Background(), &user.DBUser{Id: name})
+	}
+
+	link := makeLinkNotificationToIncident(sc).(*notificationIncidentPostHandler)
+	link.notificationID = "n1"
+	resp := link.Run(asUser("me"))
+	assert.Equal(http.StatusUnauthorized, resp.Status())
+
+	resolve := makeResolveIncident(sc).(*incidentResolveHandler)
+	resolve.incidentID = "i1"
+	assert.Equal(http.StatusUnauthorized, resolve.Run(asUser("me")).Status())
+	require.Equal(http.StatusOK, resolve.Run(asUser("admin")).Status())
+	assert.True(sc.MockNotificationConnector.CachedIncidents[0].IsResolved())
+
+	// only superusers act on incidents of subscriptions outside a project,
+	// even the subscription's owner
+	resolve.incidentID = "i2"
+	assert.Equal(http.StatusUnauthorized, resolve.Run(asUser("me")).Status())
+	assert.Equal(http.StatusUnauthorized, resolve.Run(asUser("admin")).Status())
+	assert.Equal(http.StatusOK, resolve.Run(asUser("root")).Status())
+}
@@ -0,0 +1,408 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const openAPIVersion = 2
+
+// openAPIOperation describes the bodies of a route in the OpenAPI document.
+// Routes without one are still documented, with untyped bodies.
+type openAPIOperation struct {
+	summary  string
+	request  interface{}
+	response interface{}
+}
+
+// openAPIOperations are keyed by method and route, as they're registered in
+// AttachHandler.
+var openAPIOperations = map[string]openAPIOperation{
+	"GET /admin/banner":                                        {summary: "Fetch the banner", response: model.APIBanner{}},
+	"POST /admin/banner":                                       {summary: "Set the banner", request: model.APIBanner{}},
+	"GET /admin/event_webhooks":                                {summary: "List event webhooks", response: []model.APIEventWebhook{}},
+	"POST /admin/event_webhooks":                               {summary: "Register an event webhook", request: model.APIEventWebhook{}, response: model.APIEventWebhook{}},
+	"DELETE /admin/event_webhooks/{webhook_id}":                {summary: "Remove an event webhook"},
+	"POST /admin/event_webhooks/{webhook_id}/replay":           {summary: "Replay events to an event webhook", request: model.APIEventWebhookReplay{}, response: model.APIEventWebhook{}},
+	"GET /admin/db/indexes":                                    {summary: "Compare sampled database queries with the collections' indexes", response: []model.APIIndexReport{}},
+	"DELETE /admin/db/query_samples":                           {summary: "Discard the sampled database queries"},
+	"GET /admin/db/slow_queries":                               {summary: "List the most recent slow database queries", response: []model.APISlowQuery{}},
+	"GET /admin/distros/{distro_id}/idle_policy":               {summary: "Get a distro's idle host termination policy", response: model.APIDistroIdlePolicy{}},
+	"PUT /admin/distros/{distro_id}/idle_policy":               {summary: "Set a distro's idle host termination policy", request: model.APIDistroIdlePolicy{}, response: model.APIDistroIdlePolicy{}},
+	"POST /admin/hosts/{host_id}/drain":                        {summary: "Stop assigning tasks to a host once its running task finishes", response: model.APIHost{}},
+	"DELETE /admin/hosts/{host_id}/drain":                      {summary: "Resume assigning tasks to a drained host", response: model.APIHost{}},
+	"GET /admin/project_quotas":                                {summary: "List the projects' quotas on shared distros", response: []model.APIProjectQuota{}},
+	"PUT /admin/project_quotas/{project_id}":                   {summary: "Set a project's quota on shared distros", request: model.APIProjectQuota{}, response: model.APIProjectQuota{}},
+	"DELETE /admin/project_quotas/{project_id}":                {summary: "Remove a project's quota on shared distros"},
+	"POST /admin/project_vars/{project_id}/migrate_secrets":    {summary: "Move a project's variables into a secrets manager", request: model.APIProjectVarsMigration{}, response: model.APIProjectVarsMigration{}},
+	"GET /admin/queues/{queue}":                                {summary: "Summarize the jobs in a background job queue", response: model.APIJobQueueStats{}},
+	"GET /admin/queues/{queue}/jobs":                           {summary: "List the jobs in a background job queue", response: []model.APIQueueJob{}},
+	"POST /admin/queues/{queue}/jobs/{job_id}/abort":           {summary: "Abort an in progress background job", response: model.APIQueueJob{}},
+	"POST /admin/queues/{queue}/jobs/{job_id}/retry":           {summary: "Enqueue a copy of an errored background job", response: model.APIQueueJob{}},
+	"POST /admin/repotracker/fixtures/{project_id}":            {summary: "Load repotracker test fixtures into a project", request: model.APIRepoTrackerFixture{}},
+	"GET /admin/service_keys":                                  {summary: "List service account API keys", response: []model.APIServiceKey{}},
+	"POST /admin/service_keys":                                 {summary: "Create a service account API key", request: model.APIServiceKey{}, response: model.APIServiceKey{}},
+	"DELETE /admin/service_keys/{key_id}":                      {summary: "Revoke a service account API key"},
+	"POST /admin/service_flags":                                {summary: "Set the service flags", request: model.APIServiceFlags{}},
+	"GET /admin/settings":                                      {summary: "Fetch the admin settings", response: model.APIAdminSettings{}},
+	"POST /admin/settings":                                     {summary: "Update the admin settings", request: model.APIAdminSettings{}, response: model.APIAdminSettings{}},
+	"GET /admin/settings/{section_id}":                         {summary: "Fetch a section of the admin settings"},
+	"PATCH /admin/settings/{section_id}":                       {summary: "Update some fields of a section of the admin settings and reload them without a restart"},
+	"GET /alias/{name}":                                        {summary: "Fetch a project's aliases", response: []model.APIAlias{}},
+	"GET /builds/{build_id}":                                   {summary: "Fetch a build", response: model.APIBuild{}},
+	"PATCH /builds/{build_id}":                                 {summary: "Change a build's activation or priority", response: model.APIBuild{}},
+	"POST /builds/{build_id}/abort":                            {summary: "Abort a build", response: model.APIBuild{}},
+	"POST /builds/{build_id}/restart":                          {summary: "Restart a build", response: model.APIBuild{}},
+	"GET /builds/{build_id}/tasks":                             {summary: "List a build's tasks", response: []model.APITask{}},
+	"GET /commit_queue/{project_id}":                           {summary: "Fetch a project's commit queue", response: model.APICommitQueue{}},
+	"PUT /commit_queue/{project_id}/{item}":                    {summary: "Add a pull request or patch to a project's commit queue", response: model.APICommitQueuePosition{}},
+	"DELETE /commit_queue/{project_id}/{item}":                 {summary: "Remove a pull request or patch from a project's commit queue"},
+	"GET /cost/distro/{distro_id}":                             {summary: "Fetch a distro's cost", response: model.APIDistroCost{}},
+	"GET /cost/host/{host_id}":                                 {summary: "Fetch a host's cost", response: model.APIHostCost{}},
+	"GET /cost/project/{project_id}":                           {summary: "Fetch a project's cost", response: model.APIProjectCost{}},
+	"GET /cost/project/{project_id}/tasks":                     {summary: "List the costs of a project's tasks", response: []model.APITaskCost{}},
+	"GET /cost/version/{version_id}":                           {summary: "Fetch a version's cost", response: model.APIVersionCost{}},
+	"GET /distros":                                             {summary: "List distros", response: []model.APIDistro{}},
+	"GET /distros/{distro_id}/capacity":                        {summary: "Simulate a distro's task queue at a proposed pool size", response: model.APIDistroCapacitySimulation{}},
+	"GET /events/stream":                                       {summary: "Stream version, build and task state transitions as server-sent events", response: model.APIStatusEvent{}},
+	"GET /hosts":                                               {summary: "List hosts", response: []model.APIHost{}},
+	"POST /hosts":                                              {summary: "Spawn a host", response: model.APIHost{}},
+	"GET /hosts/{host_id}":                                     {summary: "Fetch a host", response: model.APIHost{}},
+	"POST /hosts/{host_id}/attach":                             {summary: "Attach a persistent volume to a spawn host", request: model.APIVolumeAttachment{}},
+	"POST /hosts/{host_id}/detach":                             {summary: "Detach a persistent volume from a spawn host", request: model.APIVolumeAttachment{}},
+	"POST /hosts/{host_id}/start":                              {summary: "Start a stopped spawn host"},
+	"POST /hosts/{host_id}/stop":                               {summary: "Stop a running spawn host"},
+	"POST /hosts/{host_id}/transfer":                           {summary: "Transfer a spawn host to another user", request: model.APISpawnHostModify{}},
+	"GET /keys":                                                {summary: "List the user's public keys", response: []model.APIPubKey{}},
+	"POST /keys":                                               {summary: "Add a public key", request: model.APIPubKey{}},
+	"DELETE /keys/{key_name}":                                  {summary: "Remove a public key"},
+	"GET /notifications/templates/{template_name}":             {summary: "Fetch a notification template", response: model.APINotificationTemplate{}},
+	"PUT /notifications/templates/{template_name}":             {summary: "Save a notification template", request: model.APINotificationTemplate{}},
+	"POST /notifications/email":                                {summary: "Send an email", request: model.APIEmail{}},
+	"POST /notifications/jira_issue/{key}/attachments":         {summary: "Attach files to a JIRA issue", request: model.APIJiraIssueAttachments{}},
+	"POST /notifications/jira_issue/{key}/transition":          {summary: "Transition a JIRA issue", request: model.APIJiraIssueTransition{}},
+	"POST /notifications/opsgenie":                             {summary: "Send an Opsgenie alert", request: model.APIOpsgenieAlert{}},
+	"POST /notifications/slack":                                {summary: "Send a Slack message", request: model.APISlack{}},
+	"POST /notifications/template":                             {summary: "Send a templated notification", request: model.APITemplateNotification{}},
+	"POST /notifications/webhook":                              {summary: "Send a webhook", request: model.APIWebhook{}},
+	"GET /patches/{patch_id}":                                  {summary: "Fetch a patch", response: model.APIPatch{}},
+	"PATCH /patches/{patch_id}":                                {summary: "Change a patch's activation, priority or pinning", response: model.APIPatch{}},
+	"PATCH /patches/{patch_id}/configure":                      {summary: "Add variants and tasks to a finalized patch", response: model.APIPatch{}},
+	"POST /patches/{patch_id}/abort":                           {summary: "Abort a patch", response: model.APIPatch{}},
+	"POST /patches/{patch_id}/restart":                         {summary: "Restart a patch", response: model.APIPatch{}},
+	"GET /projects":                                            {summary: "List projects", response: []model.APIProject{}},
+	"PUT /projects/{project_id}/aliases/{alias}":               {summary: "Replace the definitions of a project's patch alias", request: []model.APIAlias{}},
+	"DELETE /projects/{project_id}/aliases/{alias}":            {summary: "Remove a project's patch alias"},
+	"GET /projects/{project_id}/aliases/{alias}/resolve":       {summary: "Resolve a project's patch alias to variants and tasks", response: model.APIResolvedAlias{}},
+	"GET /projects/{project_id}/badge":                         {summary: "Render the status of a project's latest mainline version, or a variant's latest mainline build, as an SVG badge"},
+	"GET /projects/{project_id}/badge/shields":                 {summary: "Describe the status of a project's latest mainline version, or a variant's latest mainline build, for a shields.io endpoint badge", response: shieldsBadge{}},
+	"GET /projects/{project_id}/events":                        {summary: "List the changes to a project's settings, most recent first", response: []model.APIProjectEvent{}},
+	"GET /projects/{project_id}/export/tasks":                  {summary: "Export a project's mainline task history as CSV or JSON lines", response: model.APITaskHistoryRow{}},
+	"GET /projects/{project_id}/export/tests":                  {summary: "Export the test results of a project's mainline tasks as CSV or JSON lines", response: model.APITestResultRow{}},
+	"GET /projects/{project_id}/insights":                      {summary: "Fetch the success rate, break detection and repair times, and build duration trends of a project's mainline builds", response: []model.APIProjectInsights{}},
+	"GET /projects/{project_id}/patches":                       {summary: "List a project's patches", response: []model.APIPatch{}},
+	"POST /projects/{project_id}/patches":                      {summary: "Create a patch from a raw diff streamed as the request body", response: model.APIPatch{}},
+	"PUT /projects/{project_id}/priority":                      {summary: "Set the priority that a project's tasks inherit", response: model.APIProject{}},
+	"PUT /projects/{project_id}/coverage":                      {summary: "Record the tasks that test a project's source files", request: model.APIProjectCoverage{}},
+	"GET /projects/{project_id}/patches/previous":              {summary: "Fetch your newest patch of a project", response: model.APIPatch{}},
+	"GET /projects/{project_id}/versions":                      {summary: "List a project's versions", response: []model.APIVersion{}},
+	"POST /projects/{project_id}/versions":                     {summary: "Create a version of a project revision", request: model.APIManualVersion{}, response: model.APIVersion{}},
+	"GET /projects/{project_id}/versions/tasks":                {summary: "List the tasks of a project's versions", response: []model.APITask{}},
+	"GET /projects/{project_id}/revisions/{commit_hash}/tasks": {summary: "List the tasks of a project revision", response: []model.APITask{}},
+	"GET /projects/{project_id}/test_flakiness":                {summary: "List how often a project's tests flaked in its recent mainline versions", response: []model.APITestFlakiness{}},
+	"GET /status/hosts/distros":                                {summary: "Fetch host statistics by distro", response: model.APIHostStatsByDistro{}},
+	"GET /status/hosts/health":                                 {summary: "Fetch the health of static hosts", response: []model.APIHostHealth{}},
+	"GET /status/recent_tasks":                                 {summary: "Fetch statistics on recent tasks", response: model.APITaskStats{}},
+	"GET /subscriptions":                                       {summary: "List subscriptions", response: []model.APISubscription{}},
+	"POST /subscriptions":                                      {summary: "Create or update subscriptions", request: []model.APISubscription{}},
+	"GET /subscriptions/{subscription_id}":                     {summary: "Fetch a subscription", response: model.APISubscription{}},
+	"GET /tasks/{task_id}":                                     {summary: "Fetch a task", response: model.APITask{}},
+	"PATCH /tasks/{task_id}":                                   {summary: "Change a task's activation or priority", response: model.APITask{}},
+	"POST /tasks/{task_id}/abort":                              {summary: "Abort a task", response: model.APITask{}},
+	"POST /tasks/{task_id}/restart":                            {summary: "Restart a task", response: model.APITask{}},
+	"GET /tasks/{task_id}/eta":                                 {summary: "Predict a task's runtime and finish time", response: model.APITaskRuntimePrediction{}},
+	"GET /tasks/{task_id}/logs/stream":                         {summary: "Stream a task's log as server-sent events while it runs", response: model.APILogMessage{}},
+	"GET /tasks/{task_id}/metrics/system":                      {summary: "Fetch the system metrics of a task's host", response: []model.APISystemMetrics{}},
+	"GET /tasks/{task_id}/structured_logs":                     {summary: "Filter a task's structured logs by level, component, and time", response: []model.APIStructuredTaskLog{}},
+	"POST /tasks/{task_id}/test_reports":                       {summary: "Convert a JUnit XML or TAP report into a task's test results", response: []model.APITest{}},
+	"GET /tasks/{task_id}/tests":                               {summary: "List a task's tests", response: []model.APITest{}},
+	"GET /user/settings":                                       {summary: "Fetch the user's settings", response: model.APIUserSettings{}},
+	"POST /user/settings":                                      {summary: "Update the user's settings", request: model.APIUserSettings{}},
+	"GET /user/settings/delivery":                              {summary: "Fetch the user's notification delivery preferences", response: model.APIDeliveryPreferences{}},
+	"PUT /user/settings/delivery":                              {summary: "Set the user's notification delivery preferences", request: model.APIDeliveryPreferences{}, response: model.APIDeliveryPreferences{}},
+	"PUT /user/settings/delivery/projects/{project_id}":        {summary: "Set the user's notification delivery preferences for a project", request: model.APIProjectDeliveryPreferences{}},
+	"GET /users/{user_id}/hosts":                               {summary: "List a user's hosts", response: []model.APIHost{}},
+	"GET /users/{user_id}/patches":                             {summary: "List a user's patches", response: []model.APIPatch{}},
+	"PATCH /versions/{version_id}":                             {summary: "Set the priority of a version's tasks", response: model.APIVersion{}},
+	"GET /versions/{version_id}":                               {summary: "Fetch a version", response: model.APIVersion{}},
+	"POST /versions/{version_id}/abort":                        {summary: "Abort a version's tasks", request: model.APIVersionTaskFilter{}, response: model.APIVersion{}},
+	"GET /versions/{version_id}/builds":                        {summary: "List a version's builds", response: []model.APIBuild{}},
+	"GET /versions/{version_id}/manifest":                      {summary: "Fetch a version's manifest", response: model.APIManifest{}},
+	"POST /versions/{version_id}/restart":                      {summary: "Restart a version's tasks", request: model.APIVersionTaskFilter{}, response: model.APIVersion{}},
+}
+
+type openAPIDocument struct {
+	OpenAPI    string                                      `json:"openapi"`
+	Info       openAPIInfo                                 `json:"info"`
+	Servers    []openAPIServer                             `json:"servers"`
+	Security   []map[string][]string                       `json:"security"`
+	Paths      map[string]map[string]*openAPIPathOperation `json:"paths"`
+	Components openAPIComponents                           `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIServer struct {
+	URL string `json:"url"`
+}
+
+type openAPIPathOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary,omitempty"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string               `json:"name"`
+	In       string               `json:"in"`
+	Required bool                 `json:"required"`
+	Schema   *model.OpenAPISchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *model.OpenAPISchema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         model.OpenAPISchemas             `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+// apiRoute is a route registered with a gimlet application.
+type apiRoute struct {
+	method  string
+	path    string
+	version int
+}
+
+var (
+	pathParameterPattern = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+	operationIDPattern   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// registeredRoutes returns the routes registered with the application, in
+// the order they were added. gimlet doesn't export an application's routes,
+// so they're read from its fields.
+func registeredRoutes(app *gimlet.APIApp) ([]apiRoute, error) {
+	methodNames, err := routeMethodNames()
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := appRouteValues(app)
+	if err != nil {
+		return nil, err
+	}
+
+	out := []apiRoute{}
+	for i := 0; i < routes.Len(); i++ {
+		r := routes.Index(i).Elem()
+		methods := r.FieldByName("methods")
+		for j := 0; j < methods.Len(); j++ {
+			name, ok := methodNames[methods.Index(j).Int()]
+			if !ok {
+				return nil, errors.Errorf("unknown method for route '%s'", r.FieldByName("route").String())
+			}
+			out = append(out, apiRoute{
+				method:  name,
+				path:    r.FieldByName("route").String(),
+				version: int(r.FieldByName("version").Int()),
+			})
+		}
+	}
+
+	return out, nil
+}
+
+func appRouteValues(app *gimlet.APIApp) (reflect.Value, error) {
+	routes := reflect.ValueOf(app).Elem().FieldByName("routes")
+	if !routes.IsValid() || routes.Kind() != reflect.Slice {
+		return reflect.Value{}, errors.New("can't read the routes of the application")
+	}
+	for _, field := range []string{"route", "methods", "version"} {
+		if _, ok := routes.Type().Elem().Elem().FieldByName(field); !ok {
+			return reflect.Value{}, errors.Errorf("can't read the %s of the application's routes", field)
+		}
+	}
+
+	return routes, nil
+}
+
+// routeMethodNames maps gimlet's representation of each method to its name,
+// by registering a route for each method.
+func routeMethodNames() (map[int64]string, error) {
+	app := gimlet.NewApp()
+	app.AddRoute("/").Get()
+	app.AddRoute("/").Put()
+	app.AddRoute("/").Post()
+	app.AddRoute("/").Delete()
+	app.AddRoute("/").Patch()
+	app.AddRoute("/").Head()
+	names := []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodPatch, http.MethodHead}
+
+	routes, err := appRouteValues(app)
+	if err != nil {
+		return nil, err
+	}
+	methodNames := map[int64]string{}
+	for i := 0; i < routes.Len(); i++ {
+		methodNames[routes.Index(i).Elem().FieldByName("methods").Index(0).Int()] = names[i]
+	}
+
+	return methodNames, nil
+}
+
+// makeOpenAPIDocument documents the REST v2 routes registered by
+// AttachHandler, describing their bodies with the schemas of the REST
+// models in openAPIOperations.
+func makeOpenAPIDocument() (*openAPIDocument, error) {
+	app := gimlet.NewApp()
+	AttachHandler(app, nil, "", nil, nil)
+	routes, err := registeredRoutes(app)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem reading registered routes")
+	}
+
+	schemas := model.OpenAPISchemas{}
+	errorSchema := schemas.SchemaFor(gimlet.ErrorResponse{})
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.0",
+		Info: openAPIInfo{
+			Title:   "Evergreen REST v2",
+			Version: evergreen.ClientVersion,
+		},
+		Servers:  []openAPIServer{{URL: evergreen.APIRoutePrefixV2}},
+		Security: []map[string][]string{{"ApiUser": {}, "ApiKey": {}}},
+		Paths:    map[string]map[string]*openAPIPathOperation{},
+		Components: openAPIComponents{
+			Schemas: schemas,
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"ApiUser": {Type: "apiKey", In: "header", Name: evergreen.APIUserHeader},
+				"ApiKey":  {Type: "apiKey", In: "header", Name: evergreen.APIKeyHeader},
+			},
+		},
+	}
+
+	for _, r := range routes {
+		if r.version != openAPIVersion {
+			continue
+		}
+		info := openAPIOperations[r.method+" "+r.path]
+		path := pathParameterPattern.ReplaceAllString(r.path, "{$1}")
+
+		op := &openAPIPathOperation{
+			OperationID: strings.Trim(operationIDPattern.ReplaceAllString(strings.ToLower(r.method+"_"+path), "_"), "_"),
+			Summary:     info.summary,
+			Responses: map[string]*openAPIResponse{
+				"200":     {Description: "success"},
+				"default": {Description: "error", Content: map[string]openAPIMediaType{"application/json": {Schema: errorSchema}}},
+			},
+		}
+		for _, match := range pathParameterPattern.FindAllStringSubmatch(r.path, -1) {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name:     match[1],
+				In:       "path",
+				Required: true,
+				Schema:   &model.OpenAPISchema{Type: "string"},
+			})
+		}
+		if info.request != nil {
+			op.RequestBody = &openAPIBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: schemas.SchemaFor(info.request)}},
+			}
+		}
+		if info.response != nil {
+			op.Responses["200"].Content = map[string]openAPIMediaType{"application/json": {Schema: schemas.SchemaFor(info.response)}}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*openAPIPathOperation{}
+		}
+		doc.Paths[path][strings.ToLower(r.method)] = op
+	}
+
+	return doc, nil
+}
+
+var (
+	openAPIDocumentOnce sync.Once
+	openAPIDocumentErr  error
+	cachedOpenAPIDoc    *openAPIDocument
+)
+
+// getOpenAPIDocument returns the OpenAPI document, which is generated once,
+// since routes aren't registered after the service starts.
+func getOpenAPIDocument() (*openAPIDocument, error) {
+	openAPIDocumentOnce.Do(func() {
+		cachedOpenAPIDoc, openAPIDocumentErr = makeOpenAPIDocument()
+	})
+	return cachedOpenAPIDoc, openAPIDocumentErr
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/spec
+
+type openAPISpecHandler struct{}
+
+func makeFetchOpenAPISpec() gimlet.RouteHandler {
+	return &openAPISpecHandler{}
+}
+
+func (h *openAPISpecHandler) Factory() gimlet.RouteHandler {
+	return &openAPISpecHandler{}
+}
+
+func (h *openAPISpecHandler) Parse(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *openAPISpecHandler) Run(ctx context.Context) gimlet.Responder {
+	doc, err := getOpenAPIDocument()
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem generating OpenAPI document"))
+	}
+
+	return gimlet.NewJSONResponse(doc)
+}
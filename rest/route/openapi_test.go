@@ -0,0 +1,77 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := makeOpenAPIDocument()
+	require.NoError(err)
+
+	// every documented operation must be a registered route
+	for key := range openAPIOperations {
+		parts := strings.SplitN(key, " ", 2)
+		require.Len(parts, 2)
+		ops, ok := doc.Paths[parts[1]]
+		if assert.True(ok, "route '%s' is not registered", key) {
+			assert.Contains(ops, strings.ToLower(parts[0]), "route '%s' is not registered", key)
+		}
+	}
+
+	// routes without documented bodies are still listed
+	ops, ok := doc.Paths["/admin/restart"]
+	require.True(ok)
+	require.Contains(ops, "post")
+	assert.Equal("post_admin_restart", ops["post"].OperationID)
+
+	op := doc.Paths["/builds/{build_id}"]["get"]
+	require.NotNil(op)
+	require.Len(op.Parameters, 1)
+	assert.Equal("build_id", op.Parameters[0].Name)
+	assert.Equal("path", op.Parameters[0].In)
+	assert.Equal("#/components/schemas/APIBuild", op.Responses["200"].Content["application/json"].Schema.Ref)
+	build, ok := doc.Components.Schemas["APIBuild"]
+	require.True(ok)
+	assert.Equal("string", build.Properties["_id"].Type)
+
+	op = doc.Paths["/projects/{project_id}/versions"]["post"]
+	require.NotNil(op)
+	require.NotNil(op.RequestBody)
+	assert.Equal("#/components/schemas/APIManualVersion", op.RequestBody.Content["application/json"].Schema.Ref)
+
+	// operation IDs are unique, so that clients can be generated
+	ids := map[string]bool{}
+	for _, methods := range doc.Paths {
+		for _, op := range methods {
+			assert.False(ids[op.OperationID], op.OperationID)
+			ids[op.OperationID] = true
+		}
+	}
+}
+
+func TestOpenAPISpecRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := context.Background()
+	handler := makeFetchOpenAPISpec()
+	request, err := http.NewRequest("GET", "/spec", nil)
+	assert.NoError(err)
+	assert.NoError(handler.Parse(ctx, request))
+
+	resp := handler.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	out, err := json.Marshal(resp.Data())
+	assert.NoError(err)
+	assert.Contains(string(out), `"openapi":"3.0.0"`)
+	assert.Contains(string(out), `"/spec"`)
+}
@@ -3,10 +3,17 @@ package route
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
@@ -14,6 +21,7 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
 )
 
 ////////////////////////////////////////////////////////////////////////
@@ -23,6 +31,7 @@ import (
 type patchChangeStatusHandler struct {
 	Activated *bool  `json:"activated"`
 	Priority  *int64 `json:"priority"`
+	Pinned    *bool  `json:"pinned"`
 
 	patchId string
 	sc      data.Connector
@@ -49,9 +58,9 @@ func (p *patchChangeStatusHandler) Parse(ctx context.Context, r *http.Request) e
 		return errors.Wrap(err, "Argument read error")
 	}
 
-	if p.Activated == nil && p.Priority == nil {
+	if p.Activated == nil && p.Priority == nil && p.Pinned == nil {
 		return gimlet.ErrorResponse{
-			Message:    "Must set 'activated' or 'priority'",
+			Message:    "Must set 'activated', 'priority' or 'pinned'",
 			StatusCode: http.StatusBadRequest,
 		}
 	}
@@ -60,6 +69,9 @@ func (p *patchChangeStatusHandler) Parse(ctx context.Context, r *http.Request) e
 
 func (p *patchChangeStatusHandler) Run(ctx context.Context) gimlet.Responder {
 	user := MustHaveUser(ctx)
+	if resp := checkPatchRole(ctx, p.sc, p.patchId, "change"); resp != nil {
+		return resp
+	}
 
 	if p.Priority != nil {
 		priority := *p.Priority
@@ -79,6 +91,11 @@ func (p *patchChangeStatusHandler) Run(ctx context.Context) gimlet.Responder {
 			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
 		}
 	}
+	if p.Pinned != nil {
+		if err := p.sc.SetPatchPinned(p.patchId, *p.Pinned); err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+		}
+	}
 	foundPatch, err := p.sc.FindPatchById(p.patchId)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
@@ -377,6 +394,9 @@ func (p *patchAbortHandler) Parse(ctx context.Context, r *http.Request) error {
 
 func (p *patchAbortHandler) Run(ctx context.Context) gimlet.Responder {
 	usr := MustHaveUser(ctx)
+	if resp := checkPatchRole(ctx, p.sc, p.patchId, "abort"); resp != nil {
+		return resp
+	}
 
 	if err := p.sc.AbortPatch(p.patchId, usr.Id); err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Abort error"))
@@ -427,8 +447,11 @@ func (p *patchRestartHandler) Parse(ctx context.Context, r *http.Request) error
 func (p *patchRestartHandler) Run(ctx context.Context) gimlet.Responder {
 	// If the version has not been finalized, returns NotFound
 	usr := MustHaveUser(ctx)
+	if resp := checkPatchRole(ctx, p.sc, p.patchId, "restart"); resp != nil {
+		return resp
+	}
 
-	if err := p.sc.RestartVersion(p.patchId, usr.Id); err != nil {
+	if err := p.sc.RestartVersion(p.patchId, dbModel.VersionTaskFilter{}, usr.Id); err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Restart error"))
 	}
 
@@ -444,3 +467,332 @@ func (p *patchRestartHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(patchModel)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/patches/{patch_id}/configure
+
+// patchConfigureHandler adds variants and tasks to a patch that has already
+// been finalized, creating only the builds and tasks that are missing.
+type patchConfigureHandler struct {
+	VariantsTasks []patchConfigureVariant `json:"variants_tasks"`
+
+	patchId string
+	sc      data.Connector
+}
+
+type patchConfigureVariant struct {
+	Name         string   `json:"name"`
+	Tasks        []string `json:"tasks"`
+	DisplayTasks []string `json:"display_tasks"`
+}
+
+func makeConfigurePatch(sc data.Connector) gimlet.RouteHandler {
+	return &patchConfigureHandler{
+		sc: sc,
+	}
+}
+
+func (p *patchConfigureHandler) Factory() gimlet.RouteHandler {
+	return &patchConfigureHandler{
+		sc: p.sc,
+	}
+}
+
+func (p *patchConfigureHandler) Parse(ctx context.Context, r *http.Request) error {
+	p.patchId = gimlet.GetVars(r)["patch_id"]
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	if err := util.ReadJSONInto(body, p); err != nil {
+		return errors.Wrap(err, "Argument read error")
+	}
+
+	if len(p.VariantsTasks) == 0 {
+		return gimlet.ErrorResponse{
+			Message:    "Must set 'variants_tasks'",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	for _, vt := range p.VariantsTasks {
+		if vt.Name == "" {
+			return gimlet.ErrorResponse{
+				Message:    "Must set the 'name' of each variant",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		if len(vt.Tasks) == 0 && len(vt.DisplayTasks) == 0 {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("Must set 'tasks' or 'display_tasks' for variant '%s'", vt.Name),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	return nil
+}
+
+func (p *patchConfigureHandler) Run(ctx context.Context) gimlet.Responder {
+	if resp := checkPatchRole(ctx, p.sc, p.patchId, "configure"); resp != nil {
+		return resp
+	}
+
+	variantsTasks := []patch.VariantTasks{}
+	for _, vt := range p.VariantsTasks {
+		variantTasks := patch.VariantTasks{
+			Variant: vt.Name,
+			Tasks:   vt.Tasks,
+		}
+		for _, dt := range vt.DisplayTasks {
+			variantTasks.DisplayTasks = append(variantTasks.DisplayTasks, patch.DisplayTask{Name: dt})
+		}
+		variantsTasks = append(variantsTasks, variantTasks)
+	}
+
+	foundPatch, err := p.sc.ConfigurePatch(p.patchId, variantsTasks)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Configure error"))
+	}
+
+	patchModel := &model.APIPatch{}
+	if err = patchModel.BuildFromService(*foundPatch); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(patchModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/projects/{project_id}/patches
+
+// patchUploadHandler creates a patch from a raw diff sent as the request
+// body, which is streamed into storage rather than read into memory, so that
+// diffs too large for the CLI's patch route can be submitted, with chunked
+// transfer encoding if need be.
+type patchUploadHandler struct {
+	project     string
+	githash     string
+	module      string
+	description string
+	variants    []string
+	tasks       []string
+	alias       string
+	finalize    bool
+	// reuse runs the variants and tasks of the user's previous patch of
+	// the project, and reuseModules also patches its modules.
+	reuse        bool
+	reuseModules bool
+	// parameters are given as "param" query parameters of the form
+	// key=value.
+	parameters []version.Parameter
+	diff       io.Reader
+
+	sc data.Connector
+}
+
+func makeUploadPatch(sc data.Connector) gimlet.RouteHandler {
+	return &patchUploadHandler{
+		sc: sc,
+	}
+}
+
+func (p *patchUploadHandler) Factory() gimlet.RouteHandler {
+	return &patchUploadHandler{
+		sc: p.sc,
+	}
+}
+
+func (p *patchUploadHandler) Parse(ctx context.Context, r *http.Request) error {
+	p.project = gimlet.GetVars(r)["project_id"]
+	vals := r.URL.Query()
+	p.githash = vals.Get("githash")
+	p.module = vals.Get("module")
+	p.description = vals.Get("description")
+	p.variants = splitExportParam(vals.Get("variants"))
+	p.tasks = splitExportParam(vals.Get("tasks"))
+	p.alias = vals.Get("alias")
+	p.diff = r.Body
+
+	var err error
+	p.parameters, err = parseParameters(vals["param"])
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	if p.githash == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "githash must be given",
+		}
+	}
+	for param, value := range map[string]*bool{"finalize": &p.finalize, "reuse": &p.reuse, "reuse_modules": &p.reuseModules} {
+		if vals.Get(param) == "" {
+			continue
+		}
+		*value, err = strconv.ParseBool(vals.Get(param))
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid %s '%s'", param, vals.Get(param)),
+			}
+		}
+	}
+	if p.reuse && (len(p.variants) > 0 || len(p.tasks) > 0 || p.alias != "") {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "variants, tasks and an alias can't be given when reusing the previous patch",
+		}
+	}
+	if p.reuseModules && !p.reuse {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "the modules of the previous patch can only be reused along with its tasks",
+		}
+	}
+	if p.finalize && !p.reuse && p.alias == "" && (len(p.variants) == 0 || len(p.tasks) == 0) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "variants and tasks, or an alias, must be given to finalize a patch",
+		}
+	}
+
+	return nil
+}
+
+func (p *patchUploadHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	projRef, resp := findProjectWithRole(ctx, p.sc, p.project, user.RoleContributor, "patch")
+	if resp != nil {
+		return resp
+	}
+	if !projRef.Enabled || projRef.PatchingDisabled {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    fmt.Sprintf("patching is disabled for project '%s'", p.project),
+		})
+	}
+
+	if p.alias != "" {
+		aliases, err := p.sc.FindProjectAliases(projRef.Identifier)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding aliases of project '%s'", p.project))
+		}
+		defined := false
+		for _, alias := range aliases {
+			defined = defined || alias.Alias == p.alias
+		}
+		if !defined {
+			return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("alias '%s' is not defined for project '%s'", p.alias, p.project),
+			})
+		}
+	}
+
+	var previous *patch.Patch
+	if p.reuse {
+		var err error
+		previous, err = p.sc.FindPreviousPatch(u.Username(), projRef.Identifier)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem finding previous patch"))
+		}
+		p.variants = previous.BuildVariants
+		p.tasks = previous.Tasks
+	}
+
+	patchFileID, err := p.sc.UploadPatchFile(p.diff)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem uploading patch"))
+	}
+
+	intent, err := patch.NewUploadedCliIntent(bson.ObjectIdHex(patchFileID), u.Username(), projRef.Identifier, p.githash,
+		p.module, p.description, p.finalize, p.variants, p.tasks, p.alias)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		})
+	}
+	if p.reuseModules {
+		if err = patch.CopyPreviousModules(intent, previous); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+	if len(p.parameters) > 0 {
+		if err = patch.SetParameters(intent, p.parameters); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+	patchDoc, err := p.sc.CreatePatchFromIntent(ctx, intent)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "problem creating patch"))
+	}
+
+	patchModel := &model.APIPatch{}
+	if err = patchModel.BuildFromService(*patchDoc); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(patchModel)
+}
+
+// parseParameters parses parameters of the form key=value.
+func parseParameters(values []string) ([]version.Parameter, error) {
+	params := []version.Parameter{}
+	for _, value := range values {
+		pair := strings.SplitN(value, "=", 2)
+		if len(pair) != 2 || pair[0] == "" {
+			return nil, errors.Errorf("invalid parameter '%s', must be of the form key=value", value)
+		}
+		params = append(params, version.Parameter{Key: pair[0], Value: pair[1]})
+	}
+	return params, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/patches/previous
+
+// previousPatchHandler returns the user's newest patch of the project, whose
+// variants and tasks a new patch can reuse.
+type previousPatchHandler struct {
+	project string
+	sc      data.Connector
+}
+
+func makeFetchPreviousPatch(sc data.Connector) gimlet.RouteHandler {
+	return &previousPatchHandler{
+		sc: sc,
+	}
+}
+
+func (p *previousPatchHandler) Factory() gimlet.RouteHandler {
+	return &previousPatchHandler{
+		sc: p.sc,
+	}
+}
+
+func (p *previousPatchHandler) Parse(ctx context.Context, r *http.Request) error {
+	p.project = gimlet.GetVars(r)["project_id"]
+	return nil
+}
+
+func (p *previousPatchHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	previous, err := p.sc.FindPreviousPatch(u.Username(), p.project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	patchModel := &model.APIPatch{}
+	if err = patchModel.BuildFromService(*previous); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(patchModel)
+}
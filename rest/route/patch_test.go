@@ -3,15 +3,19 @@ package route
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -201,8 +205,8 @@ func (s *PatchAbortSuite) SetupSuite() {
 
 	s.data = data.MockPatchConnector{
 		CachedPatches: []patch.Patch{
-			{Id: s.objIds[0], Version: "version1"},
-			{Id: s.objIds[1]},
+			{Id: s.objIds[0], Author: "user1", Version: "version1"},
+			{Id: s.objIds[1], Author: "user1"},
 		},
 		CachedAborted: make(map[string]string),
 	}
@@ -278,8 +282,8 @@ func (s *PatchesChangeStatusSuite) SetupSuite() {
 
 	s.data = data.MockPatchConnector{
 		CachedPatches: []patch.Patch{
-			{Id: s.objIds[0]},
-			{Id: s.objIds[1]},
+			{Id: s.objIds[0], Author: "user1"},
+			{Id: s.objIds[1], Author: "user1"},
 		},
 		CachedAborted:  make(map[string]string),
 		CachedPriority: make(map[string]int64),
@@ -307,6 +311,17 @@ func (s *PatchesChangeStatusSuite) TestChangeStatus() {
 	p, ok := res.Data().(*model.APIPatch)
 	s.True(ok)
 	s.True(p.Activated)
+	s.False(p.Pinned)
+
+	rm = makeChangePatchStatus(s.sc).(*patchChangeStatusHandler)
+	rm.patchId = s.objIds[1].Hex()
+	rm.Pinned = &tmp_true
+	res = rm.Run(ctx)
+	s.Equal(http.StatusOK, res.Status())
+	p, ok = res.Data().(*model.APIPatch)
+	s.True(ok)
+	s.True(p.Pinned)
+	s.False(p.Activated)
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -331,8 +346,8 @@ func (s *PatchRestartSuite) SetupSuite() {
 
 	s.patchData = data.MockPatchConnector{
 		CachedPatches: []patch.Patch{
-			{Id: s.objIds[0], Version: "version1"},
-			{Id: s.objIds[1]},
+			{Id: s.objIds[0], Author: "user1", Version: "version1"},
+			{Id: s.objIds[1], Author: "user1"},
 		},
 		CachedAborted: make(map[string]string),
 	}
@@ -475,3 +490,232 @@ func (s *PatchesByUserSuite) TestEmptyTimeShouldSetNow() {
 	s.NoError(s.route.Parse(context.Background(), req))
 	s.InDelta(time.Now().UnixNano(), s.route.key.UnixNano(), float64(time.Second))
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Tests for creating patches from uploaded diffs
+
+type PatchUploadSuite struct {
+	sc *data.MockConnector
+	suite.Suite
+}
+
+func TestPatchUploadSuite(t *testing.T) {
+	suite.Run(t, new(PatchUploadSuite))
+}
+
+func (s *PatchUploadSuite) SetupTest() {
+	s.sc = &data.MockConnector{
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*dbModel.ProjectRef{
+				"mci":      {Identifier: "mci", Enabled: true},
+				"disabled": {Identifier: "disabled", Enabled: true, PatchingDisabled: true},
+			},
+		},
+	}
+}
+
+func (s *PatchUploadSuite) request(query, diff string) *http.Request {
+	r, err := http.NewRequest(http.MethodPost, "/projects/mci/patches?"+query, strings.NewReader(diff))
+	s.Require().NoError(err)
+	return r
+}
+
+func (s *PatchUploadSuite) TestParse() {
+	ctx := context.Background()
+	rh := makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.NoError(rh.Parse(ctx, s.request("githash=abc&variants=a,b&tasks=t&finalize=true&description=desc", "diff")))
+	s.Equal("abc", rh.githash)
+	s.Equal([]string{"a", "b"}, rh.variants)
+	s.Equal([]string{"t"}, rh.tasks)
+	s.True(rh.finalize)
+	s.Equal("desc", rh.description)
+
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Error(rh.Parse(ctx, s.request("variants=a&tasks=t", "diff")))
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Error(rh.Parse(ctx, s.request("githash=abc&finalize=maybe", "diff")))
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Error(rh.Parse(ctx, s.request("githash=abc&finalize=true&variants=a", "diff")))
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.NoError(rh.Parse(ctx, s.request("githash=abc&finalize=true&alias=__github", "diff")))
+
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.NoError(rh.Parse(ctx, s.request("githash=abc&param=suite=smoke&param=args=", "diff")))
+	s.Equal([]version.Parameter{{Key: "suite", Value: "smoke"}, {Key: "args", Value: ""}}, rh.parameters)
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Error(rh.Parse(ctx, s.request("githash=abc&param=suite", "diff")))
+}
+
+func (s *PatchUploadSuite) TestRun() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user1"})
+	rh := makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Require().NoError(rh.Parse(ctx, s.request("githash=abc&description=desc", "diff --git a/a b/a")))
+	rh.project = "mci"
+
+	resp := rh.Run(ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	apiPatch, ok := resp.Data().(*model.APIPatch)
+	s.Require().True(ok)
+	s.Equal("user1", model.FromAPIString(apiPatch.Author))
+	s.Equal("desc", model.FromAPIString(apiPatch.Description))
+
+	s.Require().Len(s.sc.CachedIntentPatches, 1)
+	patchFileID := s.sc.CachedIntentPatches[0].Patches[0].PatchSet.PatchFileId
+	s.Equal("diff --git a/a b/a", s.sc.CachedPatchFiles[patchFileID])
+}
+
+func (s *PatchUploadSuite) TestRunRejectsUnpatchableProjects() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user1"})
+	for project, status := range map[string]int{"disabled": http.StatusUnauthorized, "missing": http.StatusNotFound} {
+		rh := makeUploadPatch(s.sc).(*patchUploadHandler)
+		s.Require().NoError(rh.Parse(ctx, s.request("githash=abc", "diff")))
+		rh.project = project
+		s.Equal(status, rh.Run(ctx).Status())
+	}
+	s.Empty(s.sc.CachedPatchFiles)
+}
+
+func (s *PatchUploadSuite) TestRunRejectsUndefinedAliases() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user1"})
+	s.sc.CachedAliases = []dbModel.ProjectAlias{{ProjectID: "mci", Alias: "smoke", Variant: ".*", Task: ".*"}}
+
+	rh := makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Require().NoError(rh.Parse(ctx, s.request("githash=abc&finalize=true&alias=lint", "diff")))
+	rh.project = "mci"
+	s.Equal(http.StatusBadRequest, rh.Run(ctx).Status())
+
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Require().NoError(rh.Parse(ctx, s.request("githash=abc&finalize=true&alias=smoke", "diff")))
+	rh.project = "mci"
+	s.Equal(http.StatusOK, rh.Run(ctx).Status())
+	s.Require().Len(s.sc.CachedIntentPatches, 1)
+	s.Equal("smoke", s.sc.CachedIntentPatches[0].Alias)
+}
+
+func (s *PatchUploadSuite) TestRunReusesPreviousPatch() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user1"})
+	now := time.Now()
+	s.sc.MockPatchConnector.CachedPatches = []patch.Patch{
+		{Id: bson.NewObjectId(), Author: "user1", Project: "mci", CreateTime: now.Add(-time.Hour), BuildVariants: []string{"old"}, Tasks: []string{"old"}},
+		{
+			Id:            bson.NewObjectId(),
+			Author:        "user1",
+			Project:       "mci",
+			CreateTime:    now,
+			BuildVariants: []string{"ubuntu"},
+			Tasks:         []string{"compile", "test"},
+			Patches: []patch.ModulePatch{
+				{Githash: "abc"},
+				{ModuleName: "enterprise", Githash: "def"},
+			},
+		},
+		{Id: bson.NewObjectId(), Author: "user2", Project: "mci", CreateTime: now.Add(time.Hour), BuildVariants: []string{"other"}},
+	}
+
+	rh := makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Require().NoError(rh.Parse(ctx, s.request("githash=abc&finalize=true&reuse=true&reuse_modules=true", "diff")))
+	rh.project = "mci"
+	s.Require().Equal(http.StatusOK, rh.Run(ctx).Status())
+
+	s.Require().Len(s.sc.CachedIntentPatches, 1)
+	created := s.sc.CachedIntentPatches[0]
+	s.Equal([]string{"ubuntu"}, created.BuildVariants)
+	s.Equal([]string{"compile", "test"}, created.Tasks)
+	s.Require().Len(created.Patches, 2)
+	s.Equal("enterprise", created.Patches[1].ModuleName)
+	s.Equal("def", created.Patches[1].Githash)
+
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Error(rh.Parse(ctx, s.request("githash=abc&reuse=true&variants=a", "diff")))
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Error(rh.Parse(ctx, s.request("githash=abc&reuse_modules=true", "diff")))
+
+	ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user3"})
+	rh = makeUploadPatch(s.sc).(*patchUploadHandler)
+	s.Require().NoError(rh.Parse(ctx, s.request("githash=abc&reuse=true", "diff")))
+	rh.project = "mci"
+	s.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+}
+
+func (s *PatchUploadSuite) TestFetchPreviousPatch() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user1"})
+	s.sc.MockPatchConnector.CachedPatches = []patch.Patch{
+		{Id: bson.NewObjectId(), Author: "user1", Project: "mci", BuildVariants: []string{"ubuntu"}},
+	}
+
+	rh := makeFetchPreviousPatch(s.sc).(*previousPatchHandler)
+	rh.project = "mci"
+	resp := rh.Run(ctx)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	apiPatch, ok := resp.Data().(*model.APIPatch)
+	s.Require().True(ok)
+	s.Equal(s.sc.MockPatchConnector.CachedPatches[0].Id.Hex(), model.FromAPIString(apiPatch.Id))
+
+	rh.project = "other"
+	s.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Tests for configure patch route
+
+func TestPatchConfigure(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user1"})
+
+	finalized := bson.NewObjectId()
+	unfinalized := bson.NewObjectId()
+	sc := &data.MockConnector{MockPatchConnector: data.MockPatchConnector{
+		CachedPatches: []patch.Patch{
+			{
+				Id:            finalized,
+				Author:        "user1",
+				Version:       finalized.Hex(),
+				VariantsTasks: []patch.VariantTasks{{Variant: "ubuntu", Tasks: []string{"compile"}}},
+			},
+			{Id: unfinalized, Author: "user1"},
+		},
+	}}
+
+	request := func(body string) *http.Request {
+		r, err := http.NewRequest(http.MethodPatch, "/patches/id/configure", strings.NewReader(body))
+		require.NoError(err)
+		return r
+	}
+
+	rh := makeConfigurePatch(sc).(*patchConfigureHandler)
+	assert.Error(rh.Parse(ctx, request(`{}`)))
+	rh = makeConfigurePatch(sc).(*patchConfigureHandler)
+	assert.Error(rh.Parse(ctx, request(`{"variants_tasks": [{"tasks": ["test"]}]}`)))
+	rh = makeConfigurePatch(sc).(*patchConfigureHandler)
+	assert.Error(rh.Parse(ctx, request(`{"variants_tasks": [{"name": "ubuntu"}]}`)))
+
+	rh = makeConfigurePatch(sc).(*patchConfigureHandler)
+	require.NoError(rh.Parse(ctx, request(`{"variants_tasks": [{"name": "ubuntu", "tasks": ["compile", "test"]}, {"name": "windows", "tasks": ["compile"]}]}`)))
+	rh.patchId = finalized.Hex()
+	resp := rh.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	apiPatch, ok := resp.Data().(*model.APIPatch)
+	require.True(ok)
+	assert.True(apiPatch.Activated)
+	assert.Len(apiPatch.Variants, 2)
+	assert.Len(apiPatch.Tasks, 2)
+
+	configured, err := sc.FindPatchById(finalized.Hex())
+	require.NoError(err)
+	require.Len(configured.VariantsTasks, 2)
+	for _, vt := range configured.VariantsTasks {
+		if vt.Variant == "ubuntu" {
+			assert.Len(vt.Tasks, 2)
+		} else {
+			assert.Equal([]string{"compile"}, vt.Tasks)
+		}
+	}
+
+	rh.patchId = unfinalized.Hex()
+	assert.Equal(http.StatusBadRequest, rh.Run(ctx).Status())
+	rh.patchId = bson.NewObjectId().Hex()
+	assert.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+}
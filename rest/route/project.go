@@ -2,13 +2,18 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 
+	"github.com/evergreen-ci/evergreen"
 	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/projectinsights"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -185,3 +190,339 @@ func (h *versionsGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(versions)
 }
+
+// projectVersionsGetHandler lists the versions of a project other than
+// patches, most recent first. Versions are paginated by cursor on their
+// revision order numbers, which are unique within a project.
+type projectVersionsGetHandler struct {
+	project    string
+	startOrder int
+	limit      int
+	page       cursorPage
+	fields     model.FieldSelection
+	sc         data.Connector
+}
+
+func makeFetchVersionsByProject(sc data.Connector) gimlet.RouteHandler {
+	return &projectVersionsGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectVersionsGetHandler) Factory() gimlet.RouteHandler {
+	return &projectVersionsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectVersionsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+
+	var (
+		vals url.Values
+		err  error
+	)
+	h.page, h.limit, vals, err = getCursorPage(r, defaultLimit, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err = h.page.Start(&h.startOrder); err != nil {
+		return errors.WithStack(err)
+	}
+
+	h.fields, err = getFieldSelection(vals, model.APIVersion{})
+	return err
+}
+
+func (h *projectVersionsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	if resp := checkProjectVisible(ctx, h.sc, h.project, "view versions"); resp != nil {
+		return resp
+	}
+
+	versions, err := h.sc.FindVersionsByProject(h.project, h.startOrder, h.limit+1)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	resp := gimlet.NewResponseBuilder()
+	if err = resp.SetFormat(gimlet.JSON); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	if len(versions) > h.limit {
+		if err = h.page.SetNext(resp, h.sc.GetURL(), h.limit, versions[h.limit].RevisionOrderNumber); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		versions = versions[:h.limit]
+	}
+
+	for i := range versions {
+		versionModel := &model.APIVersion{}
+		if err = versionModel.BuildFromService(&versions[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+		}
+		if err = resp.AddData(versionModel); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return projectResponse(resp, h.fields)
+}
+
+// versionCreateHandler creates a version of a project at a revision without
+// the revision being pushed, so that project admins can cut ad-hoc builds.
+type versionCreateHandler struct {
+	project string
+	body    model.APIManualVersion
+	sc      data.Connector
+}
+
+func makeCreateProjectVersion(sc data.Connector) gimlet.RouteHandler {
+	return &versionCreateHandler{
+		sc: sc,
+	}
+}
+
+func (h *versionCreateHandler) Factory() gimlet.RouteHandler {
+	return &versionCreateHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *versionCreateHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+	if err := gimlet.GetJSON(r.Body, &h.body); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	if model.FromAPIString(h.body.Revision) == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "revision must be given",
+		}
+	}
+
+	return nil
+}
+
+func (h *versionCreateHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+
+	projRef, resp := findAdministeredProject(ctx, h.sc, h.project, "create versions")
+	if resp != nil {
+		return resp
+	}
+
+	v, err := h.sc.CreateManualVersion(ctx, projRef, &h.body, u.Username())
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem creating version of project '%s'", h.project))
+	}
+
+	versionModel := &model.APIVersion{}
+	if err = versionModel.BuildFromService(v); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "API model error"))
+	}
+
+	return gimlet.NewJSONResponse(versionModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/projects/{project_id}/priority
+
+// projectPriorityHandler sets the priority that the project's tasks inherit
+// when neither they, their build nor their version set one.
+type projectPriorityHandler struct {
+	Priority *int64 `json:"priority"`
+
+	project string
+	sc      data.Connector
+}
+
+func makeSetProjectPriority(sc data.Connector) gimlet.RouteHandler {
+	return &projectPriorityHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectPriorityHandler) Factory() gimlet.RouteHandler {
+	return &projectPriorityHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectPriorityHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	if err := util.ReadJSONInto(body, h); err != nil {
+		return errors.Wrap(err, "Argument read error")
+	}
+
+	if h.Priority == nil {
+		return gimlet.ErrorResponse{
+			Message:    "Must set 'priority'",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	if *h.Priority < 0 {
+		return gimlet.ErrorResponse{
+			Message:    "project priority can't be negative",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+func (h *projectPriorityHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, resp := findAdministeredProject(ctx, h.sc, h.project, "set the priority")
+	if resp != nil {
+		return resp
+	}
+	priority := *h.Priority
+	if ok := validPriority(priority, MustHaveUser(ctx), h.sc); !ok {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			Message: fmt.Sprintf("Insufficient privilege to set priority to %d, "+
+				"non-superusers can only set priority at or below %d", priority, evergreen.MaxTaskPriority),
+			StatusCode: http.StatusForbidden,
+		})
+	}
+
+	before, resp := getProjectSettings(h.sc, projRef.Identifier)
+	if resp != nil {
+		return resp
+	}
+	defer logProjectChange(ctx, h.sc, projRef.Identifier, before)
+
+	if err := h.sc.SetProjectPriority(projRef, priority); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem setting priority of project '%s'", h.project))
+	}
+
+	projectModel := &model.APIProject{}
+	if err := projectModel.BuildFromService(*projRef); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(projectModel)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/projects/{project_id}/coverage
+
+// projectCoverageHandler records the tasks whose tests exercise source files
+// of a project, which test selection uses along with the project's own rules.
+type projectCoverageHandler struct {
+	coverage model.APIProjectCoverage
+
+	project string
+	sc      data.Connector
+}
+
+func makeUpdateProjectCoverage(sc data.Connector) gimlet.RouteHandler {
+	return &projectCoverageHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectCoverageHandler) Factory() gimlet.RouteHandler {
+	return &projectCoverageHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectCoverageHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project_id"]
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	if err := util.ReadJSONInto(body, &h.coverage); err != nil {
+		return errors.Wrap(err, "Argument read error")
+	}
+
+	if len(h.coverage.Files) == 0 {
+		return gimlet.ErrorResponse{
+			Message:    "Must set 'files'",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	for file := range h.coverage.Files {
+		if file == "" {
+			return gimlet.ErrorResponse{
+				Message:    "file names can't be empty",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+	return nil
+}
+
+func (h *projectCoverageHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, resp := findAdministeredProject(ctx, h.sc, h.project, "record the coverage")
+	if resp != nil {
+		return resp
+	}
+
+	if err := h.sc.UpdateProjectCoverage(projRef.Identifier, h.coverage.Files); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem updating coverage of project '%s'", h.project))
+	}
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/insights
+
+// projectInsightsGetHandler returns the insights into a project's mainline
+// builds that were last computed, for every window or for the one given.
+type projectInsightsGetHandler struct {
+	projectId  string
+	windowDays int
+	sc         data.Connector
+}
+
+func makeFetchProjectInsights(sc data.Connector) gimlet.RouteHandler {
+	return &projectInsightsGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *projectInsightsGetHandler) Factory() gimlet.RouteHandler {
+	return &projectInsightsGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *projectInsightsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectId = gimlet.GetVars(r)["project_id"]
+
+	if window := r.URL.Query().Get("window_days"); window != "" {
+		days, err := strconv.Atoi(window)
+		if err != nil || !projectinsights.IsWindow(days) {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("window_days must be one of %v", projectinsights.Windows),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		h.windowDays = days
+	}
+
+	return nil
+}
+
+func (h *projectInsightsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	insights, err := h.sc.FindProjectInsights(h.projectId, h.windowDays)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding insights of project '%s'", h.projectId))
+	}
+
+	apiInsights := make([]model.APIProjectInsights, 0, len(insights))
+	for i := range insights {
+		apiInsight := model.APIProjectInsights{}
+		if err = apiInsight.BuildFromService(&insights[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		apiInsights = append(apiInsights, apiInsight)
+	}
+
+	return gimlet.NewJSONResponse(apiInsights)
+}
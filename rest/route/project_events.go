@@ -0,0 +1,131 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/events
+
+// projectEventsGetHandler returns the audit log of a project's settings,
+// most recent changes first.
+type projectEventsGetHandler struct {
+	project   string
+	Timestamp time.Time
+	Limit     int
+
+	sc data.Connector
+}
+
+func makeFetchProjectEvents(sc data.Connector) gimlet.RouteHandler {
+	return &projectEventsGetHandler{sc: sc}
+}
+
+func (h *projectEventsGetHandler) Factory() gimlet.RouteHandler {
+	return &projectEventsGetHandler{
+		Timestamp: time.Now(),
+		Limit:     10,
+		sc:        h.sc,
+	}
+}
+
+func (h *projectEventsGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.project = gimlet.GetVars(r)["project_id"]
+	vals := r.URL.Query()
+
+	k, ok := vals["ts"]
+	if ok && len(k) > 0 {
+		h.Timestamp, err = time.Parse(time.RFC3339, k[0])
+		if err != nil {
+			return errors.Wrap(err, "problem parsing time as RFC-3339")
+		}
+	}
+
+	h.Limit, err = getLimit(vals)
+	return errors.WithStack(err)
+}
+
+func (h *projectEventsGetHandler) Run(ctx context.Context) gimlet.Responder {
+	projRef, resp := findAdministeredProject(ctx, h.sc, h.project, "view the events")
+	if resp != nil {
+		return resp
+	}
+
+	events, err := h.sc.GetProjectEventLog(projRef.Identifier, h.Timestamp, h.Limit+1)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "database error"))
+	}
+
+	builder := gimlet.NewResponseBuilder()
+	lastIndex := len(events)
+	if len(events) > h.Limit {
+		lastIndex = h.Limit
+		err = builder.SetPages(&gimlet.ResponsePages{
+			Next: &gimlet.Page{
+				BaseURL:         h.sc.GetURL(),
+				KeyQueryParam:   "ts",
+				LimitQueryParam: "limit",
+				Relation:        "next",
+				Key:             time.Time(events[h.Limit-1].Timestamp).Format(time.RFC3339),
+				Limit:           h.Limit,
+			},
+		})
+		if err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err,
+				"problem paginating response"))
+		}
+	}
+
+	events = events[:lastIndex]
+	catcher := grip.NewBasicCatcher()
+	for i := range events {
+		catcher.Add(builder.AddData(model.Model(&events[i])))
+	}
+	if catcher.HasErrors() {
+		return gimlet.MakeJSONInternalErrorResponder(catcher.Resolve())
+	}
+
+	if err = builder.SetStatus(http.StatusOK); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(err)
+	}
+
+	return builder
+}
+
+// getProjectSettings returns a snapshot of the project's settings before a
+// route changes them, which logProjectChange records the change against.
+func getProjectSettings(sc data.Connector, project string) (*dbModel.ProjectSettings, gimlet.Responder) {
+	settings, err := sc.GetProjectSettings(project)
+	if err != nil {
+		return nil, gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding settings of project '%s'", project))
+	}
+	return settings, nil
+}
+
+// logProjectChange records the change that the user made to the project's
+// settings since the snapshot taken before it. The request doesn't fail if
+// the change can't be recorded, since it was already made.
+func logProjectChange(ctx context.Context, sc data.Connector, project string, before *dbModel.ProjectSettings) {
+	u := MustHaveUser(ctx)
+	after, err := sc.GetProjectSettings(project)
+	if err == nil {
+		err = sc.LogProjectModified(project, u.Username(), before, after)
+	}
+	grip.Error(message.WrapError(err, message.Fields{
+		"message": "problem recording project changes",
+		"project": project,
+		"user":    u.Username(),
+	}))
+}
@@ -0,0 +1,81 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectEventsRoute(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{URL: "https://evergreen.example.net"}
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{
+		"mci": {Identifier: "mci", Admins: []string{"admin"}},
+	}
+	sc.MockProjectConnector.CachedProjects = []dbModel.ProjectRef{{Identifier: "mci", Admins: []string{"admin"}}}
+	sc.MockProjectConnector.CachedVars = []*dbModel.ProjectVars{{
+		Id:          "mci",
+		Vars:        map[string]string{"a": "a", "b": "b"},
+		PrivateVars: map[string]bool{"b": true},
+	}}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	migrate := func(body string) {
+		r, err := http.NewRequest(http.MethodPost, "/admin/project_vars/mci/migrate_secrets", bytes.NewBufferString(body))
+		require.NoError(err)
+		h := makeMigrateProjectVars(sc).(*projectVarsMigrateHandler)
+		require.NoError(h.Parse(ctx, r))
+		h.project = "mci"
+		require.Equal(http.StatusOK, h.Run(ctx).Status())
+	}
+	migrate(`{"backend": "vault", "private_only": true}`)
+	// nothing is left to migrate, so nothing changes
+	migrate(`{"backend": "vault", "private_only": true}`)
+	time.Sleep(time.Millisecond)
+	migrate(`{"backend": "vault"}`)
+	require.Len(sc.MockProjectConnector.CachedEvents, 2)
+
+	get := func(ctx context.Context, limit int) gimlet.Responder {
+		h := makeFetchProjectEvents(sc).Factory().(*projectEventsGetHandler)
+		h.project = "mci"
+		h.Limit = limit
+		return h.Run(ctx)
+	}
+	resp := get(ctx, 1)
+	require.Equal(http.StatusOK, resp.Status())
+	require.NotNil(resp.Pages())
+	events, ok := resp.Data().([]interface{})
+	require.True(ok)
+	require.Len(events, 1)
+	latest, ok := events[0].(*model.APIProjectEvent)
+	require.True(ok)
+	assert.Equal("admin", model.FromAPIString(latest.User))
+	assert.Equal(map[string]string{"a": "a"}, latest.Before.Vars)
+	assert.Empty(latest.After.Vars)
+	assert.Contains(latest.After.SecretVars, "a")
+
+	resp = get(ctx, 10)
+	require.Equal(http.StatusOK, resp.Status())
+	events, ok = resp.Data().([]interface{})
+	require.True(ok)
+	require.Len(events, 2)
+	first, ok := events[1].(*model.APIProjectEvent)
+	require.True(ok)
+	assert.Equal(map[string]string{"a": "a", "b": "b"}, first.Before.Vars)
+	assert.Contains(first.After.SecretVars, "b")
+
+	other := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "someone"})
+	assert.Equal(http.StatusUnauthorized, get(other, 10).Status())
+}
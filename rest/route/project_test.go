@@ -5,11 +5,16 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/projectinsights"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -112,3 +117,152 @@ func (s *ProjectGetSuite) TestGetRecentVersions() {
 	s.NoError(err)
 	s.EqualError(getVersions.Parse(ctx, request), "400 (Bad Request): Invalid offset")
 }
+
+func TestCreateProjectVersion(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	sc.SetSuperUsers([]string{"admin"})
+	sc.MockBuildConnector.CachedProjects = map[string]*serviceModel.ProjectRef{
+		"project": {Identifier: "project", Admins: []string{"release-manager"}},
+	}
+
+	handler := makeCreateProjectVersion(sc)
+	request, err := http.NewRequest("POST", "/projects/project/versions", bytes.NewBuffer([]byte(`{"revision": "abc", "message": "rc1"}`)))
+	assert.NoError(err)
+	assert.NoError(handler.Parse(context.Background(), request))
+	handler.(*versionCreateHandler).project = "project"
+
+	// only project admins and superusers can create versions
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "someone"})
+	resp := handler.Run(ctx)
+	assert.Equal(http.StatusUnauthorized, resp.Status())
+
+	ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "release-manager"})
+	resp = handler.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	v, ok := resp.Data().(*model.APIVersion)
+	if assert.True(ok) {
+		assert.Equal("project_abc", model.FromAPIString(v.Id))
+		assert.Equal("rc1", model.FromAPIString(v.Message))
+	}
+
+	request, err = http.NewRequest("POST", "/projects/project/versions", bytes.NewBuffer([]byte(`{"message": "rc1"}`)))
+	assert.NoError(err)
+	assert.Error(makeCreateProjectVersion(sc).Parse(context.Background(), request))
+}
+
+func TestSetProjectPriority(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	sc.SetSuperUsers([]string{"admin"})
+	sc.MockBuildConnector.CachedProjects = map[string]*serviceModel.ProjectRef{
+		"project": {Identifier: "project", Admins: []string{"release-manager"}},
+	}
+
+	request := func(body string) *http.Request {
+		r, err := http.NewRequest(http.MethodPut, "/projects/project/priority", bytes.NewBufferString(body))
+		assert.NoError(err)
+		return r
+	}
+	assert.Error(makeSetProjectPriority(sc).Parse(context.Background(), request(`{}`)))
+	assert.Error(makeSetProjectPriority(sc).Parse(context.Background(), request(`{"priority": -1}`)))
+
+	handler := makeSetProjectPriority(sc).(*projectPriorityHandler)
+	assert.NoError(handler.Parse(context.Background(), request(`{"priority": 60}`)))
+	handler.project = "project"
+
+	// only project admins and superusers can set the priority
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "someone"})
+	assert.Equal(http.StatusUnauthorized, handler.Run(ctx).Status())
+	assert.Equal(int64(0), sc.MockBuildConnector.CachedProjects["project"].Priority)
+
+	ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "release-manager"})
+	resp := handler.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	p, ok := resp.Data().(*model.APIProject)
+	if assert.True(ok) {
+		assert.Equal(int64(60), p.Priority)
+	}
+	assert.Equal(int64(60), sc.MockBuildConnector.CachedProjects["project"].Priority)
+
+	handler.project = "nonexistent"
+	assert.Equal(http.StatusNotFound, handler.Run(ctx).Status())
+}
+
+func TestFetchProjectInsights(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	sc := &data.MockConnector{}
+	now := time.Now().Round(time.Second)
+	sc.MockProjectConnector.CachedInsights = projectinsights.Compute("project", []projectinsights.BuildRun{
+		{Variant: "bv", Order: 1, Succeeded: true, CreateTime: now.Add(-2 * time.Hour), FinishTime: now.Add(-time.Hour), Duration: time.Hour},
+		{Variant: "bv", Order: 2, CreateTime: now.Add(-time.Hour), FinishTime: now.Add(-30 * time.Minute), Duration: 30 * time.Minute},
+	}, now)
+
+	request := func(query string) *http.Request {
+		r, err := http.NewRequest(http.MethodGet, "/projects/project/insights"+query, nil)
+		require.NoError(err)
+		return r
+	}
+	assert.Error(makeFetchProjectInsights(sc).Parse(context.Background(), request("?window_days=14")))
+	assert.Error(makeFetchProjectInsights(sc).Parse(context.Background(), request("?window_days=week")))
+
+	handler := makeFetchProjectInsights(sc).(*projectInsightsGetHandler)
+	require.NoError(handler.Parse(context.Background(), request("")))
+	handler.projectId = "project"
+	resp := handler.Run(context.Background())
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Len(resp.Data().([]model.APIProjectInsights), len(projectinsights.Windows))
+
+	require.NoError(handler.Parse(context.Background(), request("?window_days=7")))
+	handler.projectId = "project"
+	resp = handler.Run(context.Background())
+	require.Equal(http.StatusOK, resp.Status())
+	insights := resp.Data().([]model.APIProjectInsights)
+	require.Len(insights, 1)
+	assert.Equal(7, insights[0].WindowDays)
+	assert.Equal(2, insights[0].Builds)
+	assert.Equal(0.5, insights[0].SuccessRate)
+	assert.Equal(1, insights[0].Breaks)
+	assert.Equal(1, insights[0].OpenBreaks)
+	assert.EqualValues(30*60*1000, insights[0].MeanTimeToDetectMS)
+	assert.Len(insights[0].Periods, 7)
+
+	handler.projectId = "other"
+	resp = handler.Run(context.Background())
+	require.Equal(http.StatusOK, resp.Status())
+	assert.Len(resp.Data().([]model.APIProjectInsights), 0)
+}
+
+func TestUpdateProjectCoverage(t *testing.T) {
+	assert := assert.New(t)
+	sc := &data.MockConnector{}
+	sc.SetSuperUsers([]string{"admin"})
+	sc.MockBuildConnector.CachedProjects = map[string]*serviceModel.ProjectRef{
+		"project": {Identifier: "project", Admins: []string{"release-manager"}},
+	}
+
+	request := func(body string) *http.Request {
+		r, err := http.NewRequest(http.MethodPut, "/projects/project/coverage", bytes.NewBufferString(body))
+		assert.NoError(err)
+		return r
+	}
+	assert.Error(makeUpdateProjectCoverage(sc).Parse(context.Background(), request(`{}`)))
+	assert.Error(makeUpdateProjectCoverage(sc).Parse(context.Background(), request(`{"files": {"": ["unit"]}}`)))
+
+	handler := makeUpdateProjectCoverage(sc).(*projectCoverageHandler)
+	assert.NoError(handler.Parse(context.Background(), request(`{"files": {"src/a.go": ["unit", "integration"]}}`)))
+	handler.project = "project"
+
+	// only project admins and superusers can record coverage
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "someone"})
+	assert.Equal(http.StatusUnauthorized, handler.Run(ctx).Status())
+	assert.Empty(sc.MockProjectConnector.CachedCoverage["project"])
+
+	ctx = gimlet.AttachUser(context.Background(), &user.DBUser{Id: "release-manager"})
+	assert.Equal(http.StatusOK, handler.Run(ctx).Status())
+	assert.Equal([]string{"unit", "integration"}, sc.MockProjectConnector.CachedCoverage["project"]["src/a.go"])
+
+	handler.project = "nonexistent"
+	assert.Equal(http.StatusNotFound, handler.Run(ctx).Status())
+}
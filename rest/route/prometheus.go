@@ -0,0 +1,156 @@
+package route
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// unmatchedRoute labels the latency of REST v2 requests that don't match a
+// registered route, so that unknown paths don't create a label each.
+const unmatchedRoute = "unmatched"
+
+var restRequestDuration = util.NewPrometheusHistogramVec("evergreen_rest_request_duration_seconds",
+	"Duration of REST v2 requests, by method, route and status code.", util.DefaultPrometheusBuckets, "method", "route", "code")
+
+// routePattern matches the paths of a registered REST v2 route.
+type routePattern struct {
+	method string
+	path   string
+	regexp *regexp.Regexp
+}
+
+var (
+	routePatternsOnce sync.Once
+	routePatternsErr  error
+	cachedPatterns    []routePattern
+)
+
+// getRoutePatterns returns the patterns of the REST v2 routes registered by
+// AttachHandler, which are compiled once, since routes aren't registered
+// after the service starts.
+func getRoutePatterns() ([]routePattern, error) {
+	routePatternsOnce.Do(func() {
+		app := gimlet.NewApp()
+		AttachHandler(app, nil, "", nil, nil)
+		routes, err := registeredRoutes(app)
+		if err != nil {
+			routePatternsErr = errors.Wrap(err, "problem reading registered routes")
+			return
+		}
+		for _, r := range routes {
+			if r.version != openAPIVersion {
+				continue
+			}
+			cachedPatterns = append(cachedPatterns, routePattern{
+				method: r.method,
+				path:   pathParameterPattern.ReplaceAllString(r.path, "{$1}"),
+				regexp: routePathRegexp(r.path),
+			})
+		}
+	})
+
+	return cachedPatterns, routePatternsErr
+}
+
+// routePathRegexp converts a route's path, whose parameters may restrict
+// their values with a regular expression, to a regular expression matching
+// the paths it routes.
+func routePathRegexp(path string) *regexp.Regexp {
+	pattern := "^"
+	last := 0
+	for _, match := range pathParameterPattern.FindAllStringSubmatchIndex(path, -1) {
+		pattern += regexp.QuoteMeta(path[last:match[0]])
+		if match[4] >= 0 {
+			pattern += "(?:" + path[match[4]+1:match[5]] + ")"
+		} else {
+			pattern += "[^/]+"
+		}
+		last = match[1]
+	}
+	pattern += regexp.QuoteMeta(path[last:]) + "/?$"
+
+	return regexp.MustCompile(pattern)
+}
+
+// matchRoute returns the registered REST v2 route that the request's path,
+// relative to the REST v2 prefix, is routed to.
+func matchRoute(method, path string) string {
+	patterns, err := getRoutePatterns()
+	if err != nil {
+		grip.Warning(err)
+		return unmatchedRoute
+	}
+	for _, p := range patterns {
+		if p.method == method && p.regexp.MatchString(path) {
+			return p.path
+		}
+	}
+
+	return unmatchedRoute
+}
+
+type prometheusMiddleware struct{}
+
+// NewPrometheusMiddleware returns a middleware that records the latency of
+// REST v2 requests for each route.
+func NewPrometheusMiddleware() gimlet.Middleware {
+	return &prometheusMiddleware{}
+}
+
+func (m *prometheusMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if !isRESTv2Path(r.URL.Path) {
+		next(rw, r)
+		return
+	}
+
+	sw, ok := rw.(statusWriter)
+	if !ok {
+		sw = &statusResponseWriter{ResponseWriter: rw}
+	}
+
+	start := time.Now()
+	next(sw, r)
+
+	code := sw.Status()
+	if code == 0 {
+		code = http.StatusOK
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/"+evergreen.APIRoutePrefix)
+	route := matchRoute(r.Method, strings.TrimPrefix(path, evergreen.APIRoutePrefixV2))
+	restRequestDuration.Observe(time.Since(start).Seconds(), r.Method, route, strconv.Itoa(code))
+}
+
+// statusWriter is a response writer that reports the status code written to
+// it, as the writers that gimlet passes to middleware do.
+type statusWriter interface {
+	http.ResponseWriter
+	Status() int
+}
+
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Status() int { return w.status }
+
+func (w *statusResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,53 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchRoute(t *testing.T) {
+	assert := assert.New(t)
+
+	for path, expected := range map[string]string{
+		"/builds/b1":                         "/builds/{build_id}",
+		"/builds/b1/":                        "/builds/{build_id}",
+		"/admin/settings":                    "/admin/settings",
+		"/admin/queues/local/jobs/j.1/abort": "/admin/queues/{queue}/jobs/{job_id}/abort",
+		"/builds/b1/unknown":                 unmatchedRoute,
+		"/nonexistent":                       unmatchedRoute,
+	} {
+		method := http.MethodGet
+		if expected == "/admin/queues/{queue}/jobs/{job_id}/abort" {
+			method = http.MethodPost
+		}
+		assert.Equal(expected, matchRoute(method, path), path)
+	}
+	assert.Equal(unmatchedRoute, matchRoute(http.MethodDelete, "/builds/b1"))
+
+	re := routePathRegexp("/tasks/{task_id}/tests/{test_id:[0-9]+}")
+	assert.True(re.MatchString("/tasks/t.1/tests/12"))
+	assert.False(re.MatchString("/tasks/t.1/tests/abc"))
+	assert.False(re.MatchString("/tasks/t/1/tests/12"))
+}
+
+func TestPrometheusMiddleware(t *testing.T) {
+	assert := assert.New(t)
+
+	m := NewPrometheusMiddleware()
+	next := func(rw http.ResponseWriter, r *http.Request) { rw.WriteHeader(http.StatusNotFound) }
+
+	before := restRequestDuration.Count(http.MethodGet, "/builds/{build_id}", "404")
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/rest/v2/builds/b1", nil), next)
+	assert.Equal(before+1, restRequestDuration.Count(http.MethodGet, "/builds/{build_id}", "404"))
+
+	before = restRequestDuration.Count(http.MethodGet, "/builds/{build_id}", "404")
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/rest/v2/builds/b1", nil), next)
+	assert.Equal(before+1, restRequestDuration.Count(http.MethodGet, "/builds/{build_id}", "404"))
+
+	before = restRequestDuration.Count(http.MethodGet, unmatchedRoute, "404")
+	m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/waterfall", nil), next)
+	assert.Equal(before, restRequestDuration.Count(http.MethodGet, unmatchedRoute, "404"))
+}
@@ -0,0 +1,172 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// roleRequirements describe what a user lacks when they don't have a role
+// on a project, for error messages.
+var roleRequirements = map[user.Role]string{
+	user.RoleViewer:       "access to it",
+	user.RoleContributor:  "being one of its contributors",
+	user.RoleProjectAdmin: "being one of its admins",
+	user.RoleSuperuser:    "being a superuser",
+}
+
+// projectRole returns the role of the request's user on the project.
+// Superusers have the superuser role on every project, service keys with
+// the project's admin scope are its admins, and users' system roles can
+// grant them roles beyond those the project gives them.
+func projectRole(ctx context.Context, sc data.Connector, ref *dbModel.ProjectRef) user.Role {
+	u := gimlet.GetUser(ctx)
+	if u == nil {
+		return user.RoleNone
+	}
+	if util.StringSliceContains(sc.GetSuperUsers(), u.Username()) {
+		return user.RoleSuperuser
+	}
+	if serviceKeyHasScope(ctx, user.ProjectAdminScope(ref.Identifier)) {
+		return user.RoleProjectAdmin
+	}
+
+	if dbUser, ok := u.(*user.DBUser); ok {
+		return user.HighestRole(ref.UserRole(u.Username()), dbUser.ProjectRole(ref.Identifier))
+	}
+
+	return ref.UserRole(u.Username())
+}
+
+// findProject returns the project, or an error response if it doesn't
+// exist.
+func findProject(sc data.Connector, project string) (*dbModel.ProjectRef, gimlet.Responder) {
+	projRef, err := sc.FindProjectByBranch(project)
+	if err != nil {
+		return nil, gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding project '%s'", project))
+	}
+	if projRef == nil {
+		return nil, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", project),
+		})
+	}
+
+	return projRef, nil
+}
+
+// findProjectWithRole returns the project, or an error response if it
+// doesn't exist or the user doesn't have the role on it.
+func findProjectWithRole(ctx context.Context, sc data.Connector, project string, role user.Role, action string) (*dbModel.ProjectRef, gimlet.Responder) {
+	projRef, resp := findProject(sc, project)
+	if resp != nil {
+		return nil, resp
+	}
+	if err := checkProjectRole(ctx, sc, projRef, role, action); err != nil {
+		return nil, gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return projRef, nil
+}
+
+// checkProjectVisible returns an error response if the project doesn't
+// exist, or if it's private and the user can't view it. Public projects are
+// visible to everyone, including users who aren't logged in.
+func checkProjectVisible(ctx context.Context, sc data.Connector, project, action string) gimlet.Responder {
+	projRef, resp := findProject(sc, project)
+	if resp != nil {
+		return resp
+	}
+	if !projRef.Private {
+		return nil
+	}
+	if err := checkProjectRole(ctx, sc, projRef, user.RoleViewer, action); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return nil
+}
+
+// findAdministeredProject returns the project, or an error response if it
+// doesn't exist or the user can't administer it.
+func findAdministeredProject(ctx context.Context, sc data.Connector, project, action string) (*dbModel.ProjectRef, gimlet.Responder) {
+	return findProjectWithRole(ctx, sc, project, user.RoleProjectAdmin, action)
+}
+
+// checkProjectRole returns an error if the user doesn't have the role on
+// the project.
+func checkProjectRole(ctx context.Context, sc data.Connector, ref *dbModel.ProjectRef, role user.Role, action string) error {
+	if projectRole(ctx, sc, ref).Includes(role) {
+		return nil
+	}
+
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusUnauthorized,
+		Message:    fmt.Sprintf("cannot %s of project '%s' without %s", action, ref.Identifier, roleRequirements[role]),
+	}
+}
+
+// checkPatchRole returns an error response unless the user can act on the
+// patch: users can act on their own patches, but only the admins of its
+// project can act on other users' patches.
+func checkPatchRole(ctx context.Context, sc data.Connector, patchId, action string) gimlet.Responder {
+	if !bson.IsObjectIdHex(patchId) {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("'%s' is not a valid patch id", patchId),
+		})
+	}
+	p, err := sc.FindPatchById(patchId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding patch '%s'", patchId))
+	}
+	if p == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("patch '%s' not found", patchId),
+		})
+	}
+	if p.Author == MustHaveUser(ctx).Username() {
+		return nil
+	}
+
+	_, resp := findProjectWithRole(ctx, sc, p.Project, user.RoleProjectAdmin, fmt.Sprintf("%s other users' patches", action))
+	return resp
+}
+
+type versionVisibleMiddleware struct {
+	sc data.Connector
+}
+
+// newVersionVisibleMiddleware returns middleware that rejects requests for
+// versions of private projects that the user can't view. It must wrap routes
+// before their conditional GET middleware, which would otherwise disclose
+// whether private versions exist and what their entity tags are.
+func newVersionVisibleMiddleware(sc data.Connector) gimlet.Middleware {
+	return &versionVisibleMiddleware{
+		sc: sc,
+	}
+}
+
+func (m *versionVisibleMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	versionId := gimlet.GetVars(r)["version_id"]
+	v, err := m.sc.FindVersionById(versionId)
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "error finding version '%s'", versionId)))
+		return
+	}
+	if resp := checkProjectVisible(r.Context(), m.sc, v.Identifier, "view versions"); resp != nil {
+		gimlet.WriteResponse(rw, resp)
+		return
+	}
+
+	next(rw, r)
+}
@@ -0,0 +1,123 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestProjectRoles(t *testing.T) {
+	assert := assert.New(t)
+
+	ref := &dbModel.ProjectRef{
+		Identifier:   "mci",
+		Admins:       []string{"admin"},
+		Contributors: []string{"contributor"},
+	}
+	sc := &data.MockConnector{}
+	sc.SetSuperUsers([]string{"root"})
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{"mci": ref}
+	as := func(name string) context.Context {
+		return gimlet.AttachUser(context.Background(), &user.DBUser{Id: name})
+	}
+
+	assert.Equal(user.RoleNone, projectRole(context.Background(), sc, ref))
+	assert.Equal(user.RoleSuperuser, projectRole(as("root"), sc, ref))
+	assert.Equal(user.RoleProjectAdmin, projectRole(as("admin"), sc, ref))
+	assert.Equal(user.RoleContributor, projectRole(as("contributor"), sc, ref))
+	assert.Equal(user.RoleViewer, projectRole(as("anyone"), sc, ref))
+	keyCtx := context.WithValue(as(user.ServiceKeyUserPrefix+"bot"), serviceKeyContext, &user.ServiceKey{Scopes: []string{user.ProjectAdminScope("mci")}})
+	assert.Equal(user.RoleProjectAdmin, projectRole(keyCtx, sc, ref))
+
+	_, resp := findProjectWithRole(as("anyone"), sc, "mci", user.RoleContributor, "patch")
+	assert.Equal(http.StatusUnauthorized, resp.Status())
+	_, resp = findProjectWithRole(as("contributor"), sc, "mci", user.RoleProjectAdmin, "modify aliases")
+	assert.Equal(http.StatusUnauthorized, resp.Status())
+	_, resp = findProjectWithRole(as("anyone"), sc, "nope", user.RoleViewer, "view the events")
+	assert.Equal(http.StatusNotFound, resp.Status())
+	found, resp := findProjectWithRole(as("contributor"), sc, "mci", user.RoleContributor, "patch")
+	assert.Nil(resp)
+	assert.Equal(ref, found)
+
+	sc.MockBuildConnector.CachedProjects["secret"] = &dbModel.ProjectRef{
+		Identifier:   "secret",
+		Private:      true,
+		Contributors: []string{"contributor"},
+		Viewers:      []string{"viewer"},
+	}
+	assert.Nil(checkProjectVisible(context.Background(), sc, "mci", "view versions"))
+	assert.Nil(checkProjectVisible(as("viewer"), sc, "secret", "view versions"))
+	assert.Nil(checkProjectVisible(as("root"), sc, "secret", "view versions"))
+	assert.Equal(http.StatusUnauthorized, checkProjectVisible(context.Background(), sc, "secret", "view versions").Status())
+	assert.Equal(http.StatusUnauthorized, checkProjectVisible(as("anyone"), sc, "secret", "view versions").Status())
+	assert.Equal(http.StatusNotFound, checkProjectVisible(as("root"), sc, "nope", "view versions").Status())
+
+	id := bson.NewObjectId()
+	sc.MockPatchConnector.CachedPatches = []patch.Patch{{Id: id, Author: "contributor", Project: "mci"}}
+	assert.Nil(checkPatchRole(as("contributor"), sc, id.Hex(), "restart"))
+	assert.Nil(checkPatchRole(as("admin"), sc, id.Hex(), "restart"))
+	assert.Nil(checkPatchRole(as("root"), sc, id.Hex(), "restart"))
+	assert.Equal(http.StatusUnauthorized, checkPatchRole(as("anyone"), sc, id.Hex(), "restart").Status())
+	assert.Equal(http.StatusNotFound, checkPatchRole(as("admin"), sc, bson.NewObjectId().Hex(), "restart").Status())
+	assert.Equal(http.StatusBadRequest, checkPatchRole(as("admin"), sc, "nope", "restart").Status())
+}
+
+func TestVersionVisibleMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockBuildConnector.CachedProjects = map[string]*dbModel.ProjectRef{
+		"secret": {
+			Identifier:   "secret",
+			Private:      true,
+			Contributors: []string{"contributor"},
+			Viewers:      []string{"viewer"},
+		},
+	}
+	sc.MockVersionConnector.CachedVersions = []version.Version{{Id: "v1", Identifier: "secret"}}
+	sc.MockETagConnector.CachedETags = map[string]string{"version:v1": "tag1"}
+
+	app := gimlet.NewApp()
+	app.AddRoute("/versions/{version_id}").Version(2).Get().Wrap(newVersionVisibleMiddleware(sc), newConditionalGetMiddleware("version_id", sc.VersionETag)).Handler(func(rw http.ResponseWriter, r *http.Request) {
+		gimlet.WriteJSON(rw, map[string]string{"id": gimlet.GetVars(r)["version_id"]})
+	})
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	get := func(name, path, ifNoneMatch string) *httptest.ResponseRecorder {
+		r, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(err)
+		r = r.WithContext(gimlet.AttachUser(r.Context(), &user.DBUser{Id: name}))
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r)
+		return rw
+	}
+
+	rw := get("viewer", "/v2/versions/v1", "")
+	assert.Equal(http.StatusOK, rw.Code)
+	etag := rw.Header().Get("ETag")
+	require.NotEmpty(etag)
+	assert.Equal(http.StatusNotModified, get("viewer", "/v2/versions/v1", etag).Code)
+
+	// the tag of a private version isn't confirmed to other users
+	rw = get("anyone", "/v2/versions/v1", etag)
+	assert.Equal(http.StatusUnauthorized, rw.Code)
+	assert.Empty(rw.Header().Get("ETag"))
+
+	assert.Equal(http.StatusNotFound, get("viewer", "/v2/versions/v2", "").Code)
+}
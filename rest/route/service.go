@@ -21,71 +21,165 @@ func AttachHandler(app *gimlet.APIApp, queue amboy.Queue, URL string, superUsers
 	superUser := gimlet.NewRestrictAccessToUsers(sc.GetSuperUsers())
 	checkUser := gimlet.NewRequireAuthHandler()
 	addProject := NewProjectContextMiddleware(sc)
+	versionVisible := newVersionVisibleMiddleware(sc)
+	versionETag := newConditionalGetMiddleware("version_id", sc.VersionETag)
+	versionBuildsETag := newConditionalGetMiddleware("version_id", sc.VersionBuildsETag)
+	buildETag := newConditionalGetMiddleware("build_id", sc.BuildETag)
+	buildTasksETag := newConditionalGetMiddleware("build_id", sc.BuildTasksETag)
 
 	// Routes
 	app.AddRoute("/").Version(2).Get().RouteHandler(makePlaceHolderManger(sc))
 	app.AddRoute("/admin").Version(2).Get().RouteHandler(makeLegacyAdminConfig(sc))
 	app.AddRoute("/admin/banner").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchAdminBanner(sc))
 	app.AddRoute("/admin/banner").Version(2).Post().Wrap(superUser).RouteHandler(makeSetAdminBanner(sc))
+	app.AddRoute("/admin/db/indexes").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchIndexReports(sc))
+	app.AddRoute("/admin/db/query_samples").Version(2).Delete().Wrap(superUser).RouteHandler(makeResetQuerySamples(sc))
+	app.AddRoute("/admin/db/slow_queries").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchSlowQueries(sc))
+	app.AddRoute("/admin/distros/{distro_id}/idle_policy").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchDistroIdlePolicy(sc))
+	app.AddRoute("/admin/distros/{distro_id}/idle_policy").Version(2).Put().Wrap(superUser).RouteHandler(makeSetDistroIdlePolicy(sc))
+	app.AddRoute("/admin/distros/{distro_id}/parent_image/rollback").Version(2).Post().Wrap(superUser).RouteHandler(makeRollBackDistroParentImage(sc))
 	app.AddRoute("/admin/events").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchAdminEvents(sc))
+	app.AddRoute("/admin/event_webhooks").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchEventWebhooks(sc))
+	app.AddRoute("/admin/event_webhooks").Version(2).Post().Wrap(superUser).RouteHandler(makeCreateEventWebhook(sc))
+	app.AddRoute("/admin/event_webhooks/{webhook_id}").Version(2).Delete().Wrap(superUser).RouteHandler(makeDeleteEventWebhook(sc))
+	app.AddRoute("/admin/event_webhooks/{webhook_id}/replay").Version(2).Post().Wrap(superUser).RouteHandler(makeReplayEventWebhook(sc))
+	app.AddRoute("/admin/hosts/{host_id}/drain").Version(2).Post().Wrap(superUser).RouteHandler(makeDrainHost(sc))
+	app.AddRoute("/admin/hosts/{host_id}/drain").Version(2).Delete().Wrap(superUser).RouteHandler(makeUndrainHost(sc))
+	app.AddRoute("/admin/notifications/credentials").Version(2).Post().Wrap(superUser).RouteHandler(makeRotateSenderCredentials(sc))
+	app.AddRoute("/admin/project_quotas").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchProjectQuotas(sc))
+	app.AddRoute("/admin/project_quotas/{project_id}").Version(2).Put().Wrap(superUser).RouteHandler(makeSetProjectQuota(sc))
+	app.AddRoute("/admin/project_quotas/{project_id}").Version(2).Delete().Wrap(superUser).RouteHandler(makeDeleteProjectQuota(sc))
+	app.AddRoute("/admin/project_vars/{project_id}/migrate_secrets").Version(2).Post().Wrap(superUser).RouteHandler(makeMigrateProjectVars(sc))
+	app.AddRoute("/admin/queues/{queue}").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchJobQueue(sc))
+	app.AddRoute("/admin/queues/{queue}/jobs").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchQueueJobs(sc))
+	app.AddRoute("/admin/queues/{queue}/jobs/{job_id}/abort").Version(2).Post().Wrap(superUser).RouteHandler(makeAbortQueueJob(sc))
+	app.AddRoute("/admin/queues/{queue}/jobs/{job_id}/retry").Version(2).Post().Wrap(superUser).RouteHandler(makeRetryQueueJob(sc))
 	app.AddRoute("/admin/restart").Version(2).Post().Wrap(superUser).RouteHandler(makeRestartRoute(sc, queue))
+	app.AddRoute("/admin/repotracker/fixtures/{project_id}").Version(2).Post().Wrap(superUser).RouteHandler(makeLoadRepotrackerFixture(sc))
 	app.AddRoute("/admin/revert").Version(2).Post().Wrap(superUser).RouteHandler(makeRevertRouteManager(sc))
+	app.AddRoute("/admin/service_keys").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchServiceKeys(sc))
+	app.AddRoute("/admin/service_keys").Version(2).Post().Wrap(superUser).RouteHandler(makeCreateServiceKey(sc))
+	app.AddRoute("/admin/service_keys/{key_id}").Version(2).Delete().Wrap(superUser).RouteHandler(makeDeleteServiceKey(sc))
 	app.AddRoute("/admin/service_flags").Version(2).Post().Wrap(superUser).RouteHandler(makeSetServiceFlagsRouteManager(sc))
 	app.AddRoute("/admin/settings").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchAdminSettings(sc))
 	app.AddRoute("/admin/settings").Version(2).Post().Wrap(superUser).RouteHandler(makeSetAdminSettings(sc))
+	app.AddRoute("/admin/settings/{section_id}").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchAdminSettingsSection(sc))
+	app.AddRoute("/admin/settings/{section_id}").Version(2).Patch().Wrap(superUser).RouteHandler(makeSetAdminSettingsSection(sc))
 	app.AddRoute("/admin/task_queue").Version(2).Delete().Wrap(superUser).RouteHandler(makeClearTaskQueueHandler(sc))
 	app.AddRoute("/alias/{name}").Version(2).Get().RouteHandler(makeFetchAliases(sc))
-	app.AddRoute("/builds/{build_id}").Version(2).Get().RouteHandler(makeGetBuildByID(sc))
+	app.AddRoute("/builds/{build_id}").Version(2).Get().Wrap(buildETag).RouteHandler(makeGetBuildByID(sc))
 	app.AddRoute("/builds/{build_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangeStatusForBuild(sc))
 	app.AddRoute("/builds/{build_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortBuild(sc))
 	app.AddRoute("/builds/{build_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartBuild(sc))
-	app.AddRoute("/builds/{build_id}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTasksByBuild(sc))
+	app.AddRoute("/builds/{build_id}/tasks").Version(2).Get().Wrap(checkUser, buildTasksETag).RouteHandler(makeFetchTasksByBuild(sc))
+	app.AddRoute("/commit_queue/{project_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeGetCommitQueueItems(sc))
+	app.AddRoute("/commit_queue/{project_id}/{item}").Version(2).Put().Wrap(checkUser).RouteHandler(makeCommitQueueEnqueueItem(sc))
+	app.AddRoute("/commit_queue/{project_id}/{item}").Version(2).Delete().Wrap(checkUser).RouteHandler(makeDeleteCommitQueueItems(sc))
 	app.AddRoute("/cost/distro/{distro_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByDistroHandler(sc))
+	app.AddRoute("/cost/host/{host_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByHostHandler(sc))
+	app.AddRoute("/cost/project/{project_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByProjectHandler(sc))
 	app.AddRoute("/cost/project/{project_id}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeTaskCostByProjectRoute(sc))
 	app.AddRoute("/cost/version/{version_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeCostByVersionHandler(sc))
 	app.AddRoute("/distros").Version(2).Get().Wrap(checkUser).RouteHandler(makeDistroRoute(sc))
+	app.AddRoute("/distros/{distro_id}/capacity").Version(2).Get().Wrap(checkUser).RouteHandler(makeDistroCapacitySimulation(sc))
+	app.AddRoute("/events/stream").Version(2).Get().Wrap(checkUser).Handler(makeEventStream(sc))
 	app.AddRoute("/hooks/github").Version(2).Post().RouteHandler(makeGithubHooksRoute(sc, queue, githubSecret))
+	app.AddRoute("/hooks/slack").Version(2).Post().RouteHandler(makeSlackInteractionRoute(sc))
 	app.AddRoute("/hosts").Version(2).Get().RouteHandler(makeFetchHosts(sc))
 	app.AddRoute("/hosts").Version(2).Post().Wrap(checkUser).RouteHandler(makeSpawnHostCreateRoute(sc))
 	app.AddRoute("/hosts/{host_id}").Version(2).Get().RouteHandler(makeGetHostByID(sc))
 	app.AddRoute("/hosts/{host_id}/change_password").Version(2).Post().Wrap(checkUser).RouteHandler(makeHostChangePassword(sc))
 	app.AddRoute("/hosts/{host_id}/extend_expiration").Version(2).Post().Wrap(checkUser).RouteHandler(makeExtendHostExpiration(sc))
+	app.AddRoute("/hosts/{host_id}/attach").Version(2).Post().Wrap(checkUser).RouteHandler(makeAttachVolumeRoute(sc))
+	app.AddRoute("/hosts/{host_id}/detach").Version(2).Post().Wrap(checkUser).RouteHandler(makeDetachVolumeRoute(sc))
+	app.AddRoute("/hosts/{host_id}/start").Version(2).Post().Wrap(checkUser).RouteHandler(makeStartHostRoute(sc))
+	app.AddRoute("/hosts/{host_id}/stop").Version(2).Post().Wrap(checkUser).RouteHandler(makeStopHostRoute(sc))
 	app.AddRoute("/hosts/{host_id}/terminate").Version(2).Post().Wrap(checkUser).RouteHandler(makeTerminateHostRoute(sc))
+	app.AddRoute("/hosts/{host_id}/transfer").Version(2).Post().Wrap(checkUser).RouteHandler(makeTransferHostRoute(sc))
 	app.AddRoute("/hosts/{task_id}/create").Version(2).Post().RouteHandler(makeHostCreateRouteManager(sc))
 	app.AddRoute("/hosts/{task_id}/list").Version(2).Get().RouteHandler(makeHostListRouteManager(sc))
+	app.AddRoute("/incidents/{incident_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchIncident(sc))
+	app.AddRoute("/incidents/{incident_id}/resolve").Version(2).Post().Wrap(checkUser).RouteHandler(makeResolveIncident(sc))
 	app.AddRoute("/keys").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchKeys(sc))
 	app.AddRoute("/keys").Version(2).Post().Wrap(checkUser).RouteHandler(makeSetKey(sc))
 	app.AddRoute("/keys/{key_name}").Version(2).Delete().Wrap(checkUser).RouteHandler(makeDeleteKeys(sc))
+	app.AddRoute("/notifications").Version(2).Get().Wrap(superUser).RouteHandler(makeFetchNotificationAuditEntries(sc))
+	app.AddRoute("/notifications/webhook").Version(2).Post().Wrap(checkUser).RouteHandler(makeSendWebhook(sc, queue))
+	app.AddRoute("/notifications/email").Version(2).Post().Wrap(checkUser).RouteHandler(makeSendEmail(sc, queue))
+	app.AddRoute("/notifications/slack").Version(2).Post().Wrap(checkUser).RouteHandler(makeSendSlack(sc, queue))
+	app.AddRoute("/notifications/opsgenie").Version(2).Post().Wrap(checkUser).RouteHandler(makeSendOpsgenie(sc, queue))
+	app.AddRoute("/notifications/jira_issue/{key}/transition").Version(2).Post().Wrap(checkUser).RouteHandler(makeTransitionJiraIssue(sc))
+	app.AddRoute("/notifications/jira_issue/{key}/attachments").Version(2).Post().Wrap(checkUser).RouteHandler(makeAttachFilesToJiraIssue(sc))
+	app.AddRoute("/notifications/template").Version(2).Post().Wrap(checkUser).RouteHandler(makeSendTemplateNotification(sc, queue))
+	app.AddRoute("/notifications/templates/{template_name}").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchNotificationTemplate(sc))
+	app.AddRoute("/notifications/templates/{template_name}").Version(2).Put().Wrap(superUser).RouteHandler(makeSaveNotificationTemplate(sc))
+	app.AddRoute("/notifications/{notification_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchNotification(sc))
+	app.AddRoute("/notifications/{notification_id}/incident").Version(2).Post().Wrap(checkUser).RouteHandler(makeLinkNotificationToIncident(sc))
+	app.AddRoute("/notifications/{notification_id}/acknowledge").Version(2).Post().Wrap(checkUser).RouteHandler(makeAcknowledgeNotification(sc))
 	app.AddRoute("/patches/{patch_id}").Version(2).Get().RouteHandler(makeFetchPatchByID(sc))
 	app.AddRoute("/patches/{patch_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangePatchStatus(sc))
+	app.AddRoute("/patches/{patch_id}/configure").Version(2).Patch().Wrap(checkUser).RouteHandler(makeConfigurePatch(sc))
 	app.AddRoute("/patches/{patch_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortPatch(sc))
 	app.AddRoute("/patches/{patch_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartPatch(sc))
 	app.AddRoute("/projects").Version(2).Get().RouteHandler(makeFetchProjectsRoute(sc))
+	app.AddRoute("/projects/{project_id}/aliases/{alias}").Version(2).Put().Wrap(checkUser).RouteHandler(makePutProjectAlias(sc))
+	app.AddRoute("/projects/{project_id}/aliases/{alias}").Version(2).Delete().Wrap(checkUser).RouteHandler(makeDeleteProjectAlias(sc))
+	app.AddRoute("/projects/{project_id}/aliases/{alias}/resolve").Version(2).Get().Wrap(checkUser).RouteHandler(makeResolveProjectAlias(sc))
+	app.AddRoute("/projects/{project_id}/badge").Version(2).Get().Handler(makeFetchProjectBadge(sc))
+	app.AddRoute("/projects/{project_id}/badge/shields").Version(2).Get().Handler(makeFetchProjectShieldsBadge(sc))
+	app.AddRoute("/projects/{project_id}/events").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchProjectEvents(sc))
+	app.AddRoute("/projects/{project_id}/export/tasks").Version(2).Get().Wrap(checkUser).Handler(makeExportTaskHistory(sc))
+	app.AddRoute("/projects/{project_id}/export/tests").Version(2).Get().Wrap(checkUser).Handler(makeExportTestResults(sc))
+	app.AddRoute("/projects/{project_id}/insights").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchProjectInsights(sc))
 	app.AddRoute("/projects/{project_id}/patches").Version(2).Get().Wrap(checkUser).RouteHandler(makePatchesByProjectRoute(sc))
+	app.AddRoute("/projects/{project_id}/patches").Version(2).Post().Wrap(checkUser).RouteHandler(makeUploadPatch(sc))
+	app.AddRoute("/projects/{project_id}/patches/previous").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchPreviousPatch(sc))
+	app.AddRoute("/projects/{project_id}/versions").Version(2).Get().RouteHandler(makeFetchVersionsByProject(sc))
+	app.AddRoute("/projects/{project_id}/versions").Version(2).Post().Wrap(checkUser).RouteHandler(makeCreateProjectVersion(sc))
 	app.AddRoute("/projects/{project_id}/versions/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchProjectTasks(sc))
+	app.AddRoute("/projects/{project_id}/priority").Version(2).Put().Wrap(checkUser).RouteHandler(makeSetProjectPriority(sc))
+	app.AddRoute("/projects/{project_id}/coverage").Version(2).Put().Wrap(checkUser).RouteHandler(makeUpdateProjectCoverage(sc))
 	app.AddRoute("/projects/{project_id}/recent_versions").Version(2).Get().RouteHandler(makeFetchProjectVersions(sc))
+	app.AddRoute("/projects/{project_id}/test_flakiness").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTestFlakiness(sc))
 	app.AddRoute("/projects/{project_id}/revisions/{commit_hash}/tasks").Version(2).Get().Wrap(checkUser).RouteHandler(makeTasksByProjectAndCommitHandler(sc))
+	app.AddRoute("/spec").Version(2).Get().RouteHandler(makeFetchOpenAPISpec())
 	app.AddRoute("/status/cli_version").Version(2).Get().RouteHandler(makeFetchCLIVersionRoute(sc))
 	app.AddRoute("/status/hosts/distros").Version(2).Get().Wrap(checkUser).RouteHandler(makeHostStatusByDistroRoute(sc))
+	app.AddRoute("/status/hosts/health").Version(2).Get().Wrap(checkUser).RouteHandler(makeStaticHostsHealthRoute(sc))
 	app.AddRoute("/status/notifications").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchNotifcationStatusRoute(sc))
+	app.AddRoute("/status/notifications/analytics").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchNotificationAnalytics(sc))
 	app.AddRoute("/status/recent_tasks").Version(2).Get().RouteHandler(makeRecentTaskStatusHandler(sc))
 	app.AddRoute("/subscriptions").Version(2).Delete().Wrap(checkUser).RouteHandler(makeDeleteSubscription(sc))
 	app.AddRoute("/subscriptions").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchSubscription(sc))
 	app.AddRoute("/subscriptions").Version(2).Post().Wrap(checkUser).RouteHandler(makeSetSubscrition(sc))
+	app.AddRoute("/subscriptions/{subscription_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchSubscriptionByID(sc))
+	app.AddRoute("/subscriptions/{subscription_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makePatchSubscription(sc))
+	app.AddRoute("/subscriptions/{subscription_id}").Version(2).Delete().Wrap(checkUser).RouteHandler(makeDeleteSubscription(sc))
 	app.AddRoute("/tasks/{task_id}").Version(2).Get().Wrap(checkUser).RouteHandler(makeGetTaskRoute(sc))
 	app.AddRoute("/tasks/{task_id}").Version(2).Patch().Wrap(checkUser, addProject).RouteHandler(makeModifyTaskRoute(sc))
 	app.AddRoute("/tasks/{task_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeTaskAbortHandler(sc))
+	app.AddRoute("/tasks/{task_id}/eta").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskETA(sc))
 	app.AddRoute("/tasks/{task_id}/generate").Version(2).Post().RouteHandler(makeGenerateTasksHandler(sc))
+	app.AddRoute("/tasks/{task_id}/logs/stream").Version(2).Get().Wrap(checkUser).Handler(makeTaskLogStream(sc))
 	app.AddRoute("/tasks/{task_id}/metrics/process").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskProcessMetrics(sc))
 	app.AddRoute("/tasks/{task_id}/metrics/system").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskSystmMetrics(sc))
 	app.AddRoute("/tasks/{task_id}/restart").Version(2).Post().Wrap(addProject, checkUser).RouteHandler(makeTaskRestartHandler(sc))
+	app.AddRoute("/tasks/{task_id}/structured_logs").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchTaskStructuredLogs(sc))
+	app.AddRoute("/tasks/{task_id}/test_reports").Version(2).Post().Wrap(checkUser, addProject).RouteHandler(makeIngestTestReport(sc))
 	app.AddRoute("/tasks/{task_id}/tests").Version(2).Get().Wrap(addProject).RouteHandler(makeFetchTestsForTask(sc))
 	app.AddRoute("/user/settings").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchUserConfig())
 	app.AddRoute("/user/settings").Version(2).Post().Wrap(checkUser).RouteHandler(makeSetUserConfig(sc))
+	app.AddRoute("/user/settings/delivery").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchUserDeliveryPreferences())
+	app.AddRoute("/user/settings/delivery").Version(2).Put().Wrap(checkUser).RouteHandler(makeSetUserDeliveryPreferences(sc))
+	app.AddRoute("/user/settings/delivery").Version(2).Delete().Wrap(checkUser).RouteHandler(makeDeleteUserDeliveryPreferences(sc))
+	app.AddRoute("/user/settings/delivery/projects/{project_id}").Version(2).Put().Wrap(checkUser).RouteHandler(makeSetUserProjectDeliveryPreferences(sc))
+	app.AddRoute("/user/settings/delivery/projects/{project_id}").Version(2).Delete().Wrap(checkUser).RouteHandler(makeDeleteUserProjectDeliveryPreferences(sc))
 	app.AddRoute("/users/{user_id}/hosts").Version(2).Get().Wrap(checkUser).RouteHandler(makeFetchHosts(sc))
 	app.AddRoute("/users/{user_id}/patches").Version(2).Get().Wrap(checkUser).RouteHandler(makeUserPatchHandler(sc))
-	app.AddRoute("/versions/{version_id}").Version(2).Get().RouteHandler(makeGetVersionByID(sc))
+	app.AddRoute("/versions/{version_id}").Version(2).Get().Wrap(versionVisible, versionETag).RouteHandler(makeGetVersionByID(sc))
+	app.AddRoute("/versions/{version_id}").Version(2).Patch().Wrap(checkUser).RouteHandler(makeChangeVersionPriority(sc))
 	app.AddRoute("/versions/{version_id}/abort").Version(2).Post().Wrap(checkUser).RouteHandler(makeAbortVersion(sc))
-	app.AddRoute("/versions/{version_id}/builds").Version(2).Get().RouteHandler(makeGetVersionBuilds(sc))
+	app.AddRoute("/versions/{version_id}/builds").Version(2).Get().Wrap(versionVisible, versionBuildsETag).RouteHandler(makeGetVersionBuilds(sc))
+	app.AddRoute("/versions/{version_id}/manifest").Version(2).Get().RouteHandler(makeGetVersionManifest(sc))
 	app.AddRoute("/versions/{version_id}/restart").Version(2).Post().Wrap(checkUser).RouteHandler(makeRestartVersion(sc))
 }
@@ -0,0 +1,149 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const serviceKeyContext requestContextKey = 1
+
+// serviceKeyMiddleware authenticates requests whose Api-Key header holds
+// the token of a service key, rather than a user's API key, as the key's
+// user, and rejects the requests that the key's scopes don't allow. It must
+// run before gimlet's user middleware, which would otherwise reject the
+// token as an invalid API key.
+type serviceKeyMiddleware struct {
+	sc data.Connector
+}
+
+// NewServiceKeyMiddleware returns middleware that authenticates requests
+// made with service keys.
+func NewServiceKeyMiddleware(sc data.Connector) gimlet.Middleware {
+	return &serviceKeyMiddleware{
+		sc: sc,
+	}
+}
+
+func (m *serviceKeyMiddleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	token := r.Header.Get(evergreen.APIKeyHeader)
+	if !strings.HasPrefix(token, user.ServiceKeyPrefix) {
+		next(rw, r)
+		return
+	}
+
+	k, err := m.sc.FindServiceKeyByToken(token)
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem finding service key")))
+		return
+	}
+	if k == nil || k.IsExpired() {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "invalid or expired service key",
+		}))
+		return
+	}
+
+	allowed, err := m.allows(k, r.Method, r.URL.Path)
+	if err != nil {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(err))
+		return
+	}
+	if !allowed {
+		gimlet.WriteResponse(rw, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			StatusCode: http.StatusForbidden,
+			Message:    fmt.Sprintf("service key '%s' is not allowed to %s %s", k.Name, r.Method, r.URL.Path),
+		}))
+		return
+	}
+
+	// the request is authenticated as the key's user, so the user
+	// middleware has nothing left to check
+	r.Header.Del(evergreen.APIKeyHeader)
+	r.Header.Del(evergreen.APIUserHeader)
+
+	ctx := gimlet.AttachUser(r.Context(), k.User())
+	ctx = context.WithValue(ctx, serviceKeyContext, k)
+
+	next(rw, r.WithContext(ctx))
+}
+
+// allows returns true if the key's scopes allow the request. Service keys
+// can only be used with the REST v2 API.
+func (m *serviceKeyMiddleware) allows(k *user.ServiceKey, method, path string) (bool, error) {
+	path = strings.TrimPrefix(path, "/"+evergreen.APIRoutePrefix)
+	if !strings.HasPrefix(path, evergreen.APIRoutePrefixV2+"/") {
+		return false, nil
+	}
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, evergreen.APIRoutePrefixV2), "/"), "/")
+
+	if k.HasScope(user.ScopeReadOnly) && (method == http.MethodGet || method == http.MethodHead) {
+		return true, nil
+	}
+	if k.HasScope(user.ScopeNotifications) && segments[0] == "notifications" {
+		return true, nil
+	}
+
+	project, err := m.project(segments)
+	if err != nil {
+		return false, err
+	}
+
+	return project != "" && k.HasScope(user.ProjectAdminScope(project)), nil
+}
+
+// project returns the identifier of the project that the resource at the
+// path belongs to, or an empty string if it doesn't belong to one.
+func (m *serviceKeyMiddleware) project(segments []string) (string, error) {
+	if len(segments) < 2 || segments[1] == "" {
+		return "", nil
+	}
+
+	var taskID, buildID, versionID, patchID string
+	switch segments[0] {
+	case "projects":
+		return segments[1], nil
+	case "tasks":
+		taskID = segments[1]
+	case "builds":
+		buildID = segments[1]
+	case "versions":
+		versionID = segments[1]
+	case "patches":
+		patchID = segments[1]
+	default:
+		return "", nil
+	}
+
+	opCtx, err := m.sc.FetchContext(taskID, buildID, versionID, patchID, "")
+	if err != nil {
+		return "", err
+	}
+	if opCtx.ProjectRef == nil {
+		return "", nil
+	}
+
+	return opCtx.ProjectRef.Identifier, nil
+}
+
+// GetServiceKey returns the service key that authenticated the request, or
+// nil if the request wasn't made with one.
+func GetServiceKey(ctx context.Context) *user.ServiceKey {
+	k, _ := ctx.Value(serviceKeyContext).(*user.ServiceKey)
+	return k
+}
+
+// serviceKeyHasScope returns true if the request was made with a service
+// key that has the scope.
+func serviceKeyHasScope(ctx context.Context, scope string) bool {
+	k := GetServiceKey(ctx)
+	return k != nil && k.HasScope(scope)
+}
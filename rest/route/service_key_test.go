@@ -0,0 +1,117 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceKeyMiddleware(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockContextConnector.CachedContext = dbModel.Context{
+		ProjectRef: &dbModel.ProjectRef{Identifier: "mci"},
+	}
+	createKey := func(expiresAt time.Time, scopes ...string) string {
+		k, err := sc.CreateServiceKey(&model.APIServiceKey{
+			Name:      model.ToAPIString("bot"),
+			Scopes:    scopes,
+			ExpiresAt: model.NewTime(expiresAt),
+		}, "admin")
+		require.NoError(err)
+		return model.FromAPIString(k.Token)
+	}
+	readOnly := createKey(time.Time{}, user.ScopeReadOnly)
+	expired := createKey(time.Now().Add(time.Hour), user.ScopeNotifications)
+	sc.MockAdminConnector.MockServiceKeys[1].ExpiresAt = time.Now().Add(-time.Minute)
+	projectAdmin := createKey(time.Time{}, user.ProjectAdminScope("mci"))
+
+	m := NewServiceKeyMiddleware(sc)
+	for name, test := range map[string]struct {
+		method string
+		path   string
+		token  string
+		code   int
+	}{
+		"UserAPIKeysPassThrough":      {http.MethodPost, "/rest/v2/hosts", "user-api-key", http.StatusNoContent},
+		"UnknownToken":                {http.MethodGet, "/rest/v2/hosts", user.ServiceKeyPrefix + "abc_def", http.StatusUnauthorized},
+		"WrongSecret":                 {http.MethodGet, "/rest/v2/hosts", readOnly + "x", http.StatusUnauthorized},
+		"Expired":                     {http.MethodPost, "/rest/v2/notifications/slack", expired, http.StatusUnauthorized},
+		"ReadOnlyGet":                 {http.MethodGet, "/rest/v2/hosts", readOnly, http.StatusNoContent},
+		"ReadOnlyAPIPrefix":           {http.MethodGet, "/api/rest/v2/versions/v1", readOnly, http.StatusNoContent},
+		"ReadOnlyPost":                {http.MethodPost, "/rest/v2/hosts", readOnly, http.StatusForbidden},
+		"ReadOnlyUI":                  {http.MethodGet, "/waterfall/mci", readOnly, http.StatusForbidden},
+		"ProjectAdminProject":         {http.MethodPost, "/rest/v2/projects/mci/versions", projectAdmin, http.StatusNoContent},
+		"ProjectAdminOtherProject":    {http.MethodPost, "/rest/v2/projects/other/versions", projectAdmin, http.StatusForbidden},
+		"ProjectAdminProjectTask":     {http.MethodPost, "/rest/v2/tasks/t1/restart", projectAdmin, http.StatusNoContent},
+		"ProjectAdminUnscopedRoute":   {http.MethodPost, "/rest/v2/notifications/slack", projectAdmin, http.StatusForbidden},
+		"ProjectAdminUnscopedGetList": {http.MethodGet, "/rest/v2/hosts", projectAdmin, http.StatusForbidden},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, test.path, nil)
+			r.Header.Set(evergreen.APIKeyHeader, test.token)
+			r.Header.Set(evergreen.APIUserHeader, "bot")
+			rw := httptest.NewRecorder()
+			m.ServeHTTP(rw, r, func(rw http.ResponseWriter, r *http.Request) {
+				if test.token != "user-api-key" {
+					u := MustHaveUser(r.Context())
+					assert.Equal(user.ServiceKeyUserPrefix+"bot", u.Username())
+					assert.NotNil(GetServiceKey(r.Context()))
+					assert.Empty(r.Header.Get(evergreen.APIKeyHeader))
+					assert.Empty(r.Header.Get(evergreen.APIUserHeader))
+				} else {
+					assert.Nil(gimlet.GetUser(r.Context()))
+				}
+				rw.WriteHeader(http.StatusNoContent)
+			})
+			assert.Equal(test.code, rw.Code, rw.Body.String())
+		})
+	}
+}
+
+func TestServiceKeyRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "admin"})
+
+	h := makeCreateServiceKey(sc).(*serviceKeyPostHandler)
+	h.key = model.APIServiceKey{
+		Name:   model.ToAPIString("bot"),
+		Scopes: []string{"superuser"},
+	}
+	resp := h.Run(ctx)
+	assert.Equal(http.StatusBadRequest, resp.Status())
+
+	h.key.Scopes = []string{user.ScopeReadOnly, user.ProjectAdminScope("mci")}
+	resp = h.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	created := resp.Data().(*model.APIServiceKey)
+	assert.NotEmpty(model.FromAPIString(created.Token))
+	assert.Equal("admin", model.FromAPIString(created.CreatedBy))
+
+	resp = makeFetchServiceKeys(sc).Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	keys := resp.Data().([]model.APIServiceKey)
+	require.Len(keys, 1)
+	assert.Empty(model.FromAPIString(keys[0].Token))
+
+	d := makeDeleteServiceKey(sc).(*serviceKeyDeleteHandler)
+	d.id = model.FromAPIString(created.ID)
+	assert.Equal(http.StatusOK, d.Run(ctx).Status())
+	assert.Equal(http.StatusNotFound, d.Run(ctx).Status())
+}
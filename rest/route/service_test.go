@@ -84,11 +84,11 @@ func TestHostPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("host%d", hostToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("host%d", hostToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "host_id",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -121,11 +121,11 @@ func TestHostPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("host%d", hostToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("host%d", hostToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "host_id",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -159,11 +159,11 @@ func TestHostPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("host%d", hostToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("host%d", hostToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "host_id",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -196,11 +196,11 @@ func TestHostPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("host%d", hostToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("host%d", hostToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "host_id",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -435,11 +435,11 @@ func TestTaskByBuildPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("build%d", taskToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("build%d", taskToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "start_at",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -472,11 +472,11 @@ func TestTaskByBuildPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("build%d", taskToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("build%d", taskToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "start_at",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -508,11 +508,11 @@ func TestTaskByBuildPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("build%d", taskToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("build%d", taskToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "start_at",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -544,11 +544,11 @@ func TestTaskByBuildPaginator(t *testing.T) {
 				}
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             fmt.Sprintf("build%d", taskToStartAt+limit),
+						Key:             testCursor(t, fmt.Sprintf("build%d", taskToStartAt+limit)),
 						Limit:           limit,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "start_at",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
@@ -577,11 +577,11 @@ func TestTaskByBuildPaginator(t *testing.T) {
 				expectedTasks = append(expectedTasks, nextModelTask)
 				expectedPages := &gimlet.ResponsePages{
 					Next: &gimlet.Page{
-						Key:             "build1",
+						Key:             testCursor(t, "build1"),
 						Limit:           1,
 						Relation:        "next",
 						BaseURL:         serviceContext.GetURL(),
-						KeyQueryParam:   "start_at",
+						KeyQueryParam:   "cursor",
 						LimitQueryParam: "limit",
 					},
 				}
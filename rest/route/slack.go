@@ -0,0 +1,232 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/hooks/slack
+
+// slackInteractionHandler receives the callbacks Slack sends when a user
+// clicks a button in a message from Evergreen. Slack cannot authenticate
+// as an Evergreen user, so requests are verified with the app's signing
+// secret, and actions are performed, with that user's permissions, as the
+// user whose settings name the Slack member who clicked the button.
+type slackInteractionHandler struct {
+	sc          data.Connector
+	interaction model.APISlackInteraction
+	// respond posts a reply to the response URL of an interaction.
+	respond func(responseURL, text string) error
+}
+
+// slackMuteDuration is how long the mute button in a notification
+// suppresses the subscription's notifications.
+const slackMuteDuration = 24 * time.Hour
+
+type slackInteractionResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func makeSlackInteractionRoute(sc data.Connector) gimlet.RouteHandler {
+	return &slackInteractionHandler{
+		sc:      sc,
+		respond: util.RespondToSlackInteraction,
+	}
+}
+
+func (h *slackInteractionHandler) Factory() gimlet.RouteHandler {
+	return &slackInteractionHandler{
+		sc:      h.sc,
+		respond: h.respond,
+	}
+}
+
+func (h *slackInteractionHandler) Parse(ctx context.Context, r *http.Request) error {
+	settings, err := h.sc.GetEvergreenSettings()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "failed to retrieve settings",
+		}
+	}
+	if settings == nil || settings.Slack.SigningSecret == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "slack interactions are not configured and therefore disabled",
+		}
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "failed to read request body",
+		}
+	}
+	err = util.VerifySlackSignature([]byte(settings.Slack.SigningSecret), r.Header.Get("X-Slack-Request-Timestamp"),
+		r.Header.Get("X-Slack-Signature"), body, time.Now())
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"source":  "slack hook",
+			"message": "rejecting slack interaction",
+		}))
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "failed to verify request signature",
+		}
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "request body is not a form",
+		}
+	}
+	if err = json.Unmarshal([]byte(form.Get("payload")), &h.interaction); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "failed to parse interaction payload").Error(),
+		}
+	}
+	if err = h.interaction.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// Run performs each action and replies to the Slack user with the
+// outcome. Failed actions are reported to the user rather than as errors,
+// since Slack doesn't show error responses to them. The reply is posted to
+// the interaction's response URL when it has one, and is otherwise the
+// body of the response, so that the user sees it once.
+func (h *slackInteractionHandler) Run(ctx context.Context) gimlet.Responder {
+	memberId := model.FromAPIString(h.interaction.User.ID)
+	text, err := h.runActions(ctx, memberId)
+	grip.Error(message.WrapError(err, message.Fields{
+		"source":          "slack hook",
+		"message":         "failed to handle slack interaction",
+		"slack_member_id": memberId,
+	}))
+
+	if responseURL := model.FromAPIString(h.interaction.ResponseURL); responseURL != "" {
+		grip.Error(message.WrapError(h.respond(responseURL, text), message.Fields{
+			"source":          "slack hook",
+			"message":         "failed to respond to slack interaction",
+			"slack_member_id": memberId,
+		}))
+		return gimlet.NewJSONResponse(struct{}{})
+	}
+
+	return gimlet.NewJSONResponse(slackInteractionResponse{
+		ResponseType: "ephemeral",
+		Text:         text,
+	})
+}
+
+// runActions performs the actions as the user whose settings name the Slack
+// member. Slack usernames can be changed by their owners, so only the
+// immutable member ID identifies the user.
+func (h *slackInteractionHandler) runActions(ctx context.Context, memberId string) (string, error) {
+	u, err := h.sc.FindUserBySlackMemberId(memberId)
+	if err != nil {
+		return "Evergreen could not look up your user.", err
+	}
+	if u == nil {
+		return fmt.Sprintf("No Evergreen user has the Slack member ID '%s' in their settings.", memberId), nil
+	}
+	ctx = gimlet.AttachUser(ctx, u)
+
+	catcher := grip.NewBasicCatcher()
+	text := ""
+	for _, action := range h.interaction.Actions {
+		reply, err := h.runAction(ctx, u, model.FromAPIString(action.ActionID), model.FromAPIString(action.Value))
+		catcher.Add(err)
+		if text != "" {
+			text += "\n"
+		}
+		text += reply
+	}
+
+	return text, catcher.Resolve()
+}
+
+func (h *slackInteractionHandler) runAction(ctx context.Context, u *user.DBUser, actionID, value string) (string, error) {
+	switch actionID {
+	case model.SlackActionRestartTask:
+		t, err := h.sc.FindTaskById(value)
+		if err != nil {
+			return fmt.Sprintf("Could not find task '%s'.", value), errors.Wrapf(err, "failed to find task '%s'", value)
+		}
+		if t == nil {
+			return fmt.Sprintf("Could not find task '%s'.", value), nil
+		}
+		projectRef, err := h.sc.FindProjectByBranch(t.Project)
+		if err != nil {
+			return fmt.Sprintf("Could not restart task '%s'.", value), errors.Wrapf(err, "failed to find project '%s'", t.Project)
+		}
+		if projectRef == nil {
+			return fmt.Sprintf("Could not restart task '%s', because its project '%s' doesn't exist.", value, t.Project), nil
+		}
+		if err = checkProjectRole(ctx, h.sc, projectRef, user.RoleContributor, "restart tasks"); err != nil {
+			return fmt.Sprintf("Could not restart task '%s': %s.", value, err.Error()), nil
+		}
+		if err = h.sc.ResetTask(value, u.Id); err != nil {
+			return fmt.Sprintf("Could not restart task '%s'.", value), errors.Wrapf(err, "failed to restart task '%s'", value)
+		}
+		return fmt.Sprintf("Restarted task '%s'.", value), nil
+
+	case model.SlackActionAcknowledgeBuildBreak:
+		n, err := h.sc.GetNotification(value)
+		if err != nil {
+			return "Could not find the notification.", errors.Wrapf(err, "failed to find notification '%s'", value)
+		}
+		if _, err = findOwnSubscription(ctx, h.sc, u, model.FromAPIString(n.SubscriptionID), "acknowledge notifications from"); err != nil {
+			return "You can only acknowledge notifications from subscriptions you own.", nil
+		}
+		incident, err := h.sc.LinkNotificationToIncident(value, &model.APIIncidentLink{
+			Source: model.ToAPIString(notification.IncidentSourceManual),
+			Title:  model.ToAPIString(fmt.Sprintf("Acknowledged by %s in Slack", u.Id)),
+		})
+		if err != nil {
+			return "Could not acknowledge the build break.", errors.Wrapf(err, "failed to acknowledge notification '%s'", value)
+		}
+		return fmt.Sprintf("Acknowledged the build break as incident '%s'.", model.FromAPIString(incident.ID)), nil
+
+	case model.SlackActionMuteNotifications:
+		subscription, err := findOwnSubscription(ctx, h.sc, u, value, "mute")
+		if err != nil {
+			return "You can only mute notifications from subscriptions you own.", nil
+		}
+		subscription.MutedUntil = time.Now().Add(slackMuteDuration)
+		if err = h.sc.SaveSubscriptions([]event.Subscription{*subscription}); err != nil {
+			return "Could not mute the notifications.", errors.Wrapf(err, "failed to mute subscription '%s'", value)
+		}
+		return fmt.Sprintf("Muted the notifications from this subscription until %s.", subscription.MutedUntil.UTC().Format(time.RFC1123)), nil
+	}
+
+	return fmt.Sprintf("'%s' is not a supported action.", actionID), nil
+}
@@ -0,0 +1,141 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeSlackInteractionRequest(t *testing.T, secret, payload string) *http.Request {
+	body := []byte(url.Values{"payload": []string{payload}}.Encode())
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte("v0:" + timestamp + ":"))
+	_, _ = mac.Write(body)
+
+	r, err := http.NewRequest(http.MethodPost, "/hooks/slack", bytes.NewReader(body))
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	r.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+
+	return r
+}
+
+func TestSlackInteraction(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	sc := &data.MockConnector{
+		MockAdminConnector: data.MockAdminConnector{
+			MockSettings: &evergreen.Settings{Slack: evergreen.SlackConfig{SigningSecret: "secret"}},
+		},
+		MockUserConnector: data.MockUserConnector{
+			CachedUsers: map[string]*user.DBUser{
+				"me": {Id: "me", Settings: user.UserSettings{SlackUsername: "slackme", SlackMemberId: "U1"}},
+			},
+		},
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{
+				{Id: "task1", Project: "proj", Status: evergreen.TaskFailed},
+				{Id: "task2", Project: "locked", Status: evergreen.TaskFailed},
+			},
+		},
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*dbModel.ProjectRef{
+				"proj":   {Identifier: "proj"},
+				"locked": {Identifier: "locked", Contributors: []string{"someone"}},
+			},
+		},
+		MockSubscriptionConnector: data.MockSubscriptionConnector{
+			MockSubscriptions: []event.Subscription{
+				{ID: "sub1", Owner: "me", OwnerType: event.OwnerTypePerson},
+				{ID: "sub2", Owner: "someone", OwnerType: event.OwnerTypePerson},
+			},
+		},
+	}
+	restart := `{"type": "block_actions", "user": {"id": "U1", "username": "slackme"}, "actions": [{"action_id": "restart_task", "value": "task1"}]}`
+
+	// requests must be signed with the signing secret
+	h := makeSlackInteractionRoute(sc).Factory()
+	err := h.Parse(ctx, makeSlackInteractionRequest(t, "other", restart))
+	require.Error(err)
+	assert.Equal(http.StatusUnauthorized, err.(gimlet.ErrorResponse).StatusCode)
+
+	h = makeSlackInteractionRoute(sc).Factory()
+	err = h.Parse(ctx, makeSlackInteractionRequest(t, "secret", `{"type": "block_actions", "user": {"id": "U1", "username": "slackme"}, "actions": [{"action_id": "deploy", "value": "task1"}]}`))
+	require.Error(err)
+	assert.Equal(http.StatusBadRequest, err.(gimlet.ErrorResponse).StatusCode)
+	assert.Contains(err.Error(), "'deploy' is not a supported action")
+
+	h = makeSlackInteractionRoute(sc).Factory()
+	require.NoError(h.Parse(ctx, makeSlackInteractionRequest(t, "secret", restart)))
+	resp := h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Equal(slackInteractionResponse{ResponseType: "ephemeral", Text: "Restarted task 'task1'."}, resp.Data())
+	assert.Equal(evergreen.TaskUndispatched, sc.MockTaskConnector.CachedTasks[0].Status)
+
+	// restarting tasks requires the contributor role on their project
+	h = makeSlackInteractionRoute(sc).Factory()
+	require.NoError(h.Parse(ctx, makeSlackInteractionRequest(t, "secret", `{"type": "block_actions", "user": {"id": "U1", "username": "slackme"}, "actions": [{"action_id": "restart_task", "value": "task2"}]}`)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Contains(resp.Data().(slackInteractionResponse).Text, "Could not restart task 'task2'")
+	assert.Equal(evergreen.TaskFailed, sc.MockTaskConnector.CachedTasks[1].Status)
+
+	// muting suppresses the user's own subscription rather than deleting it
+	h = makeSlackInteractionRoute(sc).Factory()
+	require.NoError(h.Parse(ctx, makeSlackInteractionRequest(t, "secret", `{"type": "block_actions", "user": {"id": "U1", "username": "slackme"}, "actions": [{"action_id": "mute_notifications", "value": "sub1"}, {"action_id": "mute_notifications", "value": "sub2"}]}`)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Contains(resp.Data().(slackInteractionResponse).Text, "Muted the notifications from this subscription until")
+	assert.Contains(resp.Data().(slackInteractionResponse).Text, "You can only mute notifications from subscriptions you own.")
+	require.Len(sc.MockSubscriptionConnector.MockSubscriptions, 2)
+	assert.True(sc.MockSubscriptionConnector.MockSubscriptions[0].MutedUntil.After(time.Now()))
+	assert.True(sc.MockSubscriptionConnector.MockSubscriptions[1].MutedUntil.IsZero())
+
+	// slack users are identified by their member ID, not their username
+	h = makeSlackInteractionRoute(sc).Factory()
+	require.NoError(h.Parse(ctx, makeSlackInteractionRequest(t, "secret", `{"type": "block_actions", "user": {"id": "U2", "username": "slackme"}, "actions": [{"action_id": "mute_notifications", "value": "sub1"}]}`)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Equal("No Evergreen user has the Slack member ID 'U2' in their settings.", resp.Data().(slackInteractionResponse).Text)
+
+	// replies are posted to the response URL instead of being returned
+	replies := map[string]string{}
+	h = makeSlackInteractionRoute(sc).Factory()
+	h.(*slackInteractionHandler).respond = func(responseURL, text string) error {
+		replies[responseURL] = text
+		return nil
+	}
+	require.NoError(h.Parse(ctx, makeSlackInteractionRequest(t, "secret", `{"type": "block_actions", "user": {"id": "U1", "username": "slackme"}, "response_url": "https://hooks.slack.com/actions/T1/1/abc", "actions": [{"action_id": "restart_task", "value": "task1"}]}`)))
+	resp = h.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Equal(struct{}{}, resp.Data())
+	assert.Equal(map[string]string{"https://hooks.slack.com/actions/T1/1/abc": "Restarted task 'task1'."}, replies)
+
+	// interactions are disabled without a signing secret
+	sc.MockAdminConnector.MockSettings = &evergreen.Settings{}
+	h = makeSlackInteractionRoute(sc).Factory()
+	err = h.Parse(ctx, makeSlackInteractionRequest(t, "", restart))
+	require.Error(err)
+	assert.Equal(http.StatusInternalServerError, err.(gimlet.ErrorResponse).StatusCode)
+}
@@ -126,3 +126,45 @@ func (h *hostStatsByDistroHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(statsModel)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/status/hosts/health
+
+type staticHostsHealthHandler struct {
+	distroId string
+	sc       data.Connector
+}
+
+func makeStaticHostsHealthRoute(sc data.Connector) gimlet.RouteHandler {
+	return &staticHostsHealthHandler{
+		sc: sc,
+	}
+}
+
+func (h *staticHostsHealthHandler) Factory() gimlet.RouteHandler {
+	return &staticHostsHealthHandler{sc: h.sc}
+}
+
+func (h *staticHostsHealthHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.distroId = r.URL.Query().Get("distro_id")
+	return nil
+}
+
+func (h *staticHostsHealthHandler) Run(ctx context.Context) gimlet.Responder {
+	hosts, err := h.sc.FindStaticHostsHealth(h.distroId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	models := make([]model.Model, 0, len(hosts))
+	for i := range hosts {
+		health := &model.APIHostHealth{}
+		if err = health.BuildFromService(&hosts[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		models = append(models, health)
+	}
+
+	return gimlet.NewJSONResponse(models)
+}
@@ -6,12 +6,17 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -199,3 +204,66 @@ func (s *StatusSuite) TaskTaskType() {
 	found = resp.Data().([]interface{})[0].(*model.APITask)
 	s.Equal(model.ToAPIString("task5"), found.Id)
 }
+
+func TestStaticHostsHealth(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockStatusConnector: data.MockStatusConnector{
+			CachedStaticHosts: []host.Host{
+				{
+					Id:     "h1",
+					Distro: distro.Distro{Id: "d1"},
+					Status: evergreen.HostRunning,
+					HealthCheck: &host.HealthCheck{
+						DiskFreeMB:   2048,
+						ClockDrift:   2 * time.Second,
+						AgentVersion: evergreen.ClientVersion,
+					},
+				},
+				{
+					Id:     "h2",
+					Distro: distro.Distro{Id: "d1"},
+					Status: evergreen.HostQuarantined,
+					HealthCheck: &host.HealthCheck{
+						Failures:            []string{"only 10 MB of disk space is free"},
+						ConsecutiveFailures: 3,
+					},
+				},
+				{Id: "h3", Distro: distro.Distro{Id: "d2"}, Status: evergreen.HostRunning},
+				{Id: "h4", Distro: distro.Distro{Id: "d1"}, Status: evergreen.HostTerminated},
+			},
+		},
+	}
+	h := makeStaticHostsHealthRoute(sc).(*staticHostsHealthHandler)
+
+	r, err := http.NewRequest("GET", "https://evergreen.mongodb.com/rest/v2/status/hosts/health?distro_id=d1", &bytes.Buffer{})
+	require.NoError(err)
+	require.NoError(h.Parse(context.Background(), r))
+	assert.Equal("d1", h.distroId)
+
+	resp := h.Run(context.Background())
+	require.Equal(http.StatusOK, resp.Status())
+	health := resp.Data().([]model.Model)
+	require.Len(health, 2)
+	h1 := health[0].(*model.APIHostHealth)
+	assert.Equal(model.ToAPIString("h1"), h1.HostID)
+	assert.True(h1.Checked)
+	assert.True(h1.Healthy)
+	assert.Equal(2048, h1.DiskFreeMB)
+	assert.Equal(2.0, h1.ClockDriftSecs)
+	h2 := health[1].(*model.APIHostHealth)
+	assert.False(h2.Healthy)
+	assert.Equal(3, h2.ConsecutiveFailures)
+	assert.Len(h2.Failures, 1)
+
+	h.distroId = ""
+	resp = h.Run(context.Background())
+	require.Equal(http.StatusOK, resp.Status())
+	health = resp.Data().([]model.Model)
+	require.Len(health, 3)
+	h3 := health[2].(*model.APIHostHealth)
+	assert.False(h3.Checked)
+	assert.False(h3.Healthy)
+}
@@ -2,15 +2,20 @@ package route
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/trigger"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
 )
 
 ////////////////////////////////////////////////////////////////////////
@@ -59,56 +64,129 @@ func (s *subscriptionPostHandler) Parse(ctx context.Context, r *http.Request) er
 			}
 		}
 
-		if !trigger.ValidateTrigger(dbSubscription.ResourceType, dbSubscription.Trigger) {
-			return gimlet.ErrorResponse{
-				StatusCode: http.StatusBadRequest,
-				Message:    fmt.Sprintf("subscription type/trigger is invalid: %s/%s", dbSubscription.ResourceType, dbSubscription.Trigger),
-			}
-		}
-
 		if dbSubscription.OwnerType == event.OwnerTypePerson && dbSubscription.Owner == "" {
 			dbSubscription.Owner = u.Username() // default the current user
 		}
 
-		if dbSubscription.OwnerType == event.OwnerTypePerson && dbSubscription.Owner != u.Username() {
-			return gimlet.ErrorResponse{
-				StatusCode: http.StatusUnauthorized,
-				Message:    "Cannot change subscriptions for anyone other than yourself",
-			}
+		if err = validateSubscription(&dbSubscription); err != nil {
+			return err
 		}
 
-		if ok, msg := isSubscriptionAllowed(dbSubscription); !ok {
-			return gimlet.ErrorResponse{
-				StatusCode: http.StatusBadRequest,
-				Message:    msg,
-			}
+		if err = checkSubscriptionOwner(ctx, s.sc, u, dbSubscription.OwnerType, dbSubscription.Owner, "change"); err != nil {
+			return err
 		}
 
-		if ok, msg := validateSelectors(dbSubscription.Subscriber, dbSubscription.Selectors); !ok {
-			return gimlet.ErrorResponse{
-				StatusCode: http.StatusBadRequest,
-				Message:    fmt.Sprintf("Invalid selectors: %s", msg),
-			}
+		s.dbSubscriptions = append(s.dbSubscriptions, dbSubscription)
+	}
+
+	return nil
+}
+
+// validateSubscription checks that the subscription's trigger exists for
+// its resource type, that its subscriber can be notified about the
+// resource, and that its selectors and other fields are valid.
+func validateSubscription(sub *event.Subscription) error {
+	if !trigger.ValidateTrigger(sub.ResourceType, sub.Trigger) {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("subscription type/trigger is invalid: %s/%s", sub.ResourceType, sub.Trigger),
 		}
-		if ok, msg := validateSelectors(dbSubscription.Subscriber, dbSubscription.RegexSelectors); !ok {
-			return gimlet.ErrorResponse{
-				StatusCode: http.StatusBadRequest,
-				Message:    fmt.Sprintf("Invalid regex selectors: %s", msg),
-			}
+	}
+
+	if ok, msg := isSubscriptionAllowed(*sub); !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    msg,
 		}
+	}
 
-		err = dbSubscription.Validate()
-		if err != nil {
+	if ok, msg := validateSelectors(sub.Subscriber, sub.Selectors); !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Invalid selectors: %s", msg),
+		}
+	}
+	if ok, msg := validateSelectors(sub.Subscriber, sub.RegexSelectors); !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Invalid regex selectors: %s", msg),
+		}
+	}
+
+	if err := sub.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Error validating subscription: " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// checkSubscriptionOwner returns an error if the user can't act on the
+// subscriptions of the owner. Users manage their own subscriptions, project
+// admins manage their project's subscriptions, and superusers manage all
+// subscriptions. Unlike elsewhere, no one is a superuser if none are
+// configured, so that users can't manage each other's subscriptions.
+func checkSubscriptionOwner(ctx context.Context, sc data.Connector, u *user.DBUser, ownerType event.OwnerType, owner, action string) error {
+	if util.StringSliceContains(sc.GetSuperUsers(), u.Username()) {
+		return nil
+	}
+
+	if ownerType != event.OwnerTypeProject {
+		if owner != u.Username() {
 			return gimlet.ErrorResponse{
-				StatusCode: http.StatusBadRequest,
-				Message:    "Error validating subscription: " + err.Error(),
+				StatusCode: http.StatusUnauthorized,
+				Message:    fmt.Sprintf("Cannot %s subscriptions for someone other than yourself", action),
 			}
 		}
+		return nil
+	}
 
-		s.dbSubscriptions = append(s.dbSubscriptions, dbSubscription)
+	projectRef, err := sc.FindProjectByBranch(owner)
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    errors.Wrapf(err, "error finding project '%s'", owner).Error(),
+		}
+	}
+	if projectRef == nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("project '%s' not found", owner),
+		}
 	}
 
-	return nil
+	return checkProjectRole(ctx, sc, projectRef, user.RoleProjectAdmin, fmt.Sprintf("%s subscriptions", action))
+}
+
+// getSubscriptionProjectSettings returns snapshots of the settings of the
+// projects that own any of the subscriptions, keyed by project, before a
+// route changes the subscriptions.
+func getSubscriptionProjectSettings(sc data.Connector, subscriptions []event.Subscription) (map[string]*dbModel.ProjectSettings, gimlet.Responder) {
+	out := map[string]*dbModel.ProjectSettings{}
+	for _, sub := range subscriptions {
+		if sub.OwnerType != event.OwnerTypeProject {
+			continue
+		}
+		if _, ok := out[sub.Owner]; ok {
+			continue
+		}
+		settings, resp := getProjectSettings(sc, sub.Owner)
+		if resp != nil {
+			return nil, resp
+		}
+		out[sub.Owner] = settings
+	}
+	return out, nil
+}
+
+// logSubscriptionProjectChanges records the changes that the user made to
+// the subscriptions of projects since the snapshots of their settings.
+func logSubscriptionProjectChanges(ctx context.Context, sc data.Connector, before map[string]*dbModel.ProjectSettings) {
+	for project, settings := range before {
+		logProjectChange(ctx, sc, project, settings)
+	}
 }
 
 func isSubscriptionAllowed(sub event.Subscription) (bool, string) {
@@ -137,6 +215,12 @@ func validateSelectors(subscriber event.Subscriber, selectors []event.Selector)
 }
 
 func (s *subscriptionPostHandler) Run(ctx context.Context) gimlet.Responder {
+	before, resp := getSubscriptionProjectSettings(s.sc, s.dbSubscriptions)
+	if resp != nil {
+		return resp
+	}
+	defer logSubscriptionProjectChanges(ctx, s.sc, before)
+
 	err := s.sc.SaveSubscriptions(s.dbSubscriptions)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(err)
@@ -148,10 +232,16 @@ func (s *subscriptionPostHandler) Run(ctx context.Context) gimlet.Responder {
 ////////////////////////////////////////////////////////////////////////
 //
 // GET /rest/v2/subscriptions
+//
+// Subscriptions are listed in order of their IDs, and paginated by cursor.
+// An owner has few subscriptions, so they're paginated after being fetched.
 
 type subscriptionGetHandler struct {
 	owner     string
 	ownerType string
+	startID   string
+	limit     int
+	page      cursorPage
 	sc        data.Connector
 }
 
@@ -169,8 +259,17 @@ func (s *subscriptionGetHandler) Factory() gimlet.RouteHandler {
 
 func (s *subscriptionGetHandler) Parse(ctx context.Context, r *http.Request) error {
 	u := MustHaveUser(ctx)
-	s.owner = r.FormValue("owner")
-	s.ownerType = r.FormValue("type")
+	page, limit, vals, err := getCursorPage(r, defaultLimit, 0)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	s.page, s.limit = page, limit
+	if _, err = s.page.Start(&s.startID); err != nil {
+		return errors.WithStack(err)
+	}
+
+	s.owner = vals.Get("owner")
+	s.ownerType = vals.Get("type")
 	if !event.IsValidOwnerType(s.ownerType) {
 		fmt.Println(s.ownerType)
 		return gimlet.ErrorResponse{
@@ -184,14 +283,8 @@ func (s *subscriptionGetHandler) Parse(ctx context.Context, r *http.Request) err
 			Message:    "Owner cannot be blank",
 		}
 	}
-	if s.ownerType == string(event.OwnerTypePerson) && s.owner != u.Username() {
-		return gimlet.ErrorResponse{
-			StatusCode: http.StatusUnauthorized,
-			Message:    "Cannot get subscriptions for someone other than yourself",
-		}
-	}
 
-	return nil
+	return checkSubscriptionOwner(ctx, s.sc, u, event.OwnerType(s.ownerType), s.owner, "get")
 }
 
 func (s *subscriptionGetHandler) Run(ctx context.Context) gimlet.Responder {
@@ -200,7 +293,28 @@ func (s *subscriptionGetHandler) Run(ctx context.Context) gimlet.Responder {
 		return gimlet.MakeJSONErrorResponder(err)
 	}
 
-	return gimlet.NewJSONResponse(subs)
+	sort.Slice(subs, func(i, j int) bool {
+		return model.FromAPIString(subs[i].ID) < model.FromAPIString(subs[j].ID)
+	})
+	start := sort.Search(len(subs), func(i int) bool {
+		return model.FromAPIString(subs[i].ID) >= s.startID
+	})
+	subs = subs[start:]
+
+	var next string
+	if len(subs) > s.limit {
+		next = model.FromAPIString(subs[s.limit].ID)
+		subs = subs[:s.limit]
+	}
+
+	resp := gimlet.NewJSONResponse(subs)
+	if next != "" {
+		if err = s.page.SetNext(resp, s.sc.GetURL(), s.limit, next); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+	}
+
+	return resp
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -223,43 +337,234 @@ func (s *subscriptionDeleteHandler) Factory() gimlet.RouteHandler {
 }
 
 func (s *subscriptionDeleteHandler) Parse(ctx context.Context, r *http.Request) error {
-	u := MustHaveUser(ctx)
-	idString := r.FormValue("id")
-	if idString == "" {
+	s.id = r.FormValue("id")
+	if s.id == "" {
+		s.id = gimlet.GetVars(r)["subscription_id"]
+	}
+	if s.id == "" {
 		return gimlet.ErrorResponse{
 			StatusCode: http.StatusBadRequest,
 			Message:    "Must specify an ID to delete",
 		}
 	}
-	s.id = idString
-	subscription, err := event.FindSubscriptionByID(s.id)
+	_, err := findOwnSubscription(ctx, s.sc, MustHaveUser(ctx), s.id, "delete")
+
+	return err
+}
+
+func (s *subscriptionDeleteHandler) Run(ctx context.Context) gimlet.Responder {
+	subscription, err := s.sc.FindSubscriptionByID(s.id)
 	if err != nil {
-		return gimlet.ErrorResponse{
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	subscriptions := []event.Subscription{}
+	if subscription != nil {
+		subscriptions = append(subscriptions, *subscription)
+	}
+	before, resp := getSubscriptionProjectSettings(s.sc, subscriptions)
+	if resp != nil {
+		return resp
+	}
+	defer logSubscriptionProjectChanges(ctx, s.sc, before)
+
+	err = s.sc.DeleteSubscription(s.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+
+	return gimlet.NewJSONResponse(struct{}{})
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/subscriptions/{subscription_id}
+
+type subscriptionGetByIDHandler struct {
+	subscription *event.Subscription
+	sc           data.Connector
+}
+
+func makeFetchSubscriptionByID(sc data.Connector) gimlet.RouteHandler {
+	return &subscriptionGetByIDHandler{
+		sc: sc,
+	}
+}
+
+func (s *subscriptionGetByIDHandler) Factory() gimlet.RouteHandler {
+	return &subscriptionGetByIDHandler{sc: s.sc}
+}
+
+func (s *subscriptionGetByIDHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	s.subscription, err = findOwnSubscription(ctx, s.sc, MustHaveUser(ctx), gimlet.GetVars(r)["subscription_id"], "get")
+
+	return err
+}
+
+func (s *subscriptionGetByIDHandler) Run(_ context.Context) gimlet.Responder {
+	apiSubscription := model.APISubscription{}
+	if err := apiSubscription.BuildFromService(*s.subscription); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem building subscription"))
+	}
+
+	return gimlet.NewJSONResponse(apiSubscription)
+}
+
+// findOwnSubscription finds the subscription, and checks that the user can
+// act on it.
+func findOwnSubscription(ctx context.Context, sc data.Connector, u *user.DBUser, id, action string) (*event.Subscription, error) {
+	if id == "" {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Must specify a subscription ID",
+		}
+	}
+	subscription, err := sc.FindSubscriptionByID(id)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
 			StatusCode: http.StatusInternalServerError,
 			Message:    err.Error(),
 		}
 	}
 	if subscription == nil {
-		return gimlet.ErrorResponse{
+		return nil, gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
 			Message:    "Subscription not found",
 		}
 	}
-	if subscription.Owner != u.Username() {
+	if err = checkSubscriptionOwner(ctx, sc, u, subscription.OwnerType, subscription.Owner, action); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/subscriptions/{subscription_id}
+
+type subscriptionPatchHandler struct {
+	subscription *event.Subscription
+	sc           data.Connector
+}
+
+func makePatchSubscription(sc data.Connector) gimlet.RouteHandler {
+	return &subscriptionPatchHandler{
+		sc: sc,
+	}
+}
+
+func (s *subscriptionPatchHandler) Factory() gimlet.RouteHandler {
+	return &subscriptionPatchHandler{sc: s.sc}
+}
+
+// Parse applies the fields in the body to the subscription. Fields that
+// are not in the body are unchanged, but the subscription's ID and owner
+// cannot be changed.
+func (s *subscriptionPatchHandler) Parse(ctx context.Context, r *http.Request) error {
+	existing, err := findOwnSubscription(ctx, s.sc, MustHaveUser(ctx), gimlet.GetVars(r)["subscription_id"], "change")
+	if err != nil {
+		return err
+	}
+
+	// the fields in the body replace the subscription's fields in its
+	// JSON representation, which is then parsed like a new subscription
+	apiSubscription := model.APISubscription{}
+	if err = apiSubscription.BuildFromService(*existing); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "problem building subscription: " + err.Error(),
+		}
+	}
+	fields := map[string]json.RawMessage{}
+	if err = util.ReadJSONInto(r.Body, &fields); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Error parsing request body: " + err.Error(),
+		}
+	}
+	if apiSubscription, err = patchAPISubscription(apiSubscription, fields); err != nil {
 		return gimlet.ErrorResponse{
-			StatusCode: http.StatusUnauthorized,
-			Message:    "Cannot delete subscriptions for someone other than yourself",
+			StatusCode: http.StatusBadRequest,
+			Message:    "Error parsing request body: " + err.Error(),
 		}
 	}
 
+	subscriptionInterface, err := apiSubscription.ToService()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Error parsing request body: " + err.Error(),
+		}
+	}
+	subscription, ok := subscriptionInterface.(event.Subscription)
+	if !ok {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Error parsing subscription interface",
+		}
+	}
+	if subscription.ID != existing.ID {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot change the ID of a subscription",
+		}
+	}
+	if subscription.Owner != existing.Owner || subscription.OwnerType != existing.OwnerType {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot change the owner of a subscription",
+		}
+	}
+	if err = validateSubscription(&subscription); err != nil {
+		return err
+	}
+	s.subscription = &subscription
+
 	return nil
 }
 
-func (s *subscriptionDeleteHandler) Run(_ context.Context) gimlet.Responder {
-	err := s.sc.DeleteSubscription(s.id)
+// patchAPISubscription replaces the fields of the subscription with the
+// fields in the patch.
+func patchAPISubscription(sub model.APISubscription, patch map[string]json.RawMessage) (model.APISubscription, error) {
+	out := model.APISubscription{}
+	existing, err := json.Marshal(sub)
 	if err != nil {
+		return out, errors.WithStack(err)
+	}
+	fields := map[string]json.RawMessage{}
+	if err = json.Unmarshal(existing, &fields); err != nil {
+		return out, errors.WithStack(err)
+	}
+	for key, value := range patch {
+		fields[key] = value
+	}
+	patched, err := json.Marshal(fields)
+	if err != nil {
+		return out, errors.WithStack(err)
+	}
+	if err = json.Unmarshal(patched, &out); err != nil {
+		return out, errors.WithStack(err)
+	}
+
+	return out, nil
+}
+
+func (s *subscriptionPatchHandler) Run(ctx context.Context) gimlet.Responder {
+	before, resp := getSubscriptionProjectSettings(s.sc, []event.Subscription{*s.subscription})
+	if resp != nil {
+		return resp
+	}
+	defer logSubscriptionProjectChanges(ctx, s.sc, before)
+
+	if err := s.sc.SaveSubscriptions([]event.Subscription{*s.subscription}); err != nil {
 		return gimlet.MakeJSONErrorResponder(err)
 	}
 
-	return gimlet.NewJSONResponse(struct{}{})
+	apiSubscription := model.APISubscription{}
+	if err := apiSubscription.BuildFromService(*s.subscription); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem building subscription"))
+	}
+
+	return gimlet.NewJSONResponse(apiSubscription)
 }
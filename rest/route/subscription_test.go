@@ -5,16 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -37,7 +41,7 @@ func (s *SubscriptionRouteSuite) SetupSuite() {
 }
 
 func (s *SubscriptionRouteSuite) SetupTest() {
-	s.NoError(db.ClearCollections(event.SubscriptionsCollection))
+	s.NoError(db.ClearCollections(event.SubscriptionsCollection, serviceModel.ProjectRefCollection))
 }
 
 func (s *SubscriptionRouteSuite) TestSubscriptionPost() {
@@ -114,6 +118,8 @@ func (s *SubscriptionRouteSuite) TestSubscriptionPost() {
 func (s *SubscriptionRouteSuite) TestProjectSubscription() {
 	ctx := context.Background()
 	ctx = gimlet.AttachUser(ctx, &user.DBUser{Id: "me"})
+	projectRef := &serviceModel.ProjectRef{Identifier: "myproj", Admins: []string{"me"}}
+	s.NoError(projectRef.Insert())
 	body := []map[string]interface{}{{
 		"resource_type": event.ResourceTypeTask,
 		"trigger":       "outcome",
@@ -176,7 +182,7 @@ func (s *SubscriptionRouteSuite) TestProjectSubscription() {
 	s.Equal(http.StatusOK, resp.Status())
 
 	// get the updated subscription
-	h := &subscriptionGetHandler{sc: s.sc}
+	h := &subscriptionGetHandler{sc: s.sc, limit: defaultLimit}
 	h.owner = "myproj"
 	h.ownerType = string(event.OwnerTypeProject)
 	resp = h.Run(ctx)
@@ -215,14 +221,14 @@ func (s *SubscriptionRouteSuite) TestPostUnauthorizedUser() {
 	buffer := bytes.NewBuffer(jsonBody)
 	request, err := http.NewRequest(http.MethodPost, "/subscriptions", buffer)
 	s.NoError(err)
-	s.EqualError(s.postHandler.Parse(ctx, request), "401 (Unauthorized): Cannot change subscriptions for anyone other than yourself")
+	s.EqualError(s.postHandler.Parse(ctx, request), "401 (Unauthorized): Cannot change subscriptions for someone other than yourself")
 }
 
 func (s *SubscriptionRouteSuite) TestGet() {
 	ctx := context.Background()
 	ctx = gimlet.AttachUser(ctx, &user.DBUser{Id: "me"})
 
-	h := &subscriptionGetHandler{sc: s.sc}
+	h := &subscriptionGetHandler{sc: s.sc, limit: defaultLimit}
 	h.owner = "me"
 	h.ownerType = string(event.OwnerTypePerson)
 	resp := h.Run(ctx)
@@ -241,7 +247,7 @@ func (s *SubscriptionRouteSuite) TestDeleteValidation() {
 	s.NoError(db.Clear(event.SubscriptionsCollection))
 	ctx := context.Background()
 	ctx = gimlet.AttachUser(ctx, &user.DBUser{Id: "thanos"})
-	d := &subscriptionDeleteHandler{}
+	d := &subscriptionDeleteHandler{sc: s.sc}
 
 	r, err := http.NewRequest(http.MethodDelete, "/subscriptions", nil)
 	s.NoError(err)
@@ -508,3 +514,97 @@ func (s *SubscriptionRouteSuite) TestAcceptSubscriptionWithOnlyRegexSelectors()
 	s.NoError(err)
 	s.NoError(s.postHandler.Parse(ctx, request))
 }
+
+func TestSubscriptionByIDRoutes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	email := "me@example.com"
+	sc := &data.MockConnector{}
+	sc.SetPrefix("rest")
+	sc.MockSubscriptionConnector.MockSubscriptions = []event.Subscription{
+		{
+			ID:           "mine",
+			ResourceType: event.ResourceTypeTask,
+			Trigger:      "outcome",
+			Selectors:    []event.Selector{{Type: "object", Data: "task"}},
+			Subscriber:   event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			OwnerType:    event.OwnerTypePerson,
+			Owner:        "me",
+		},
+		{
+			ID:           "project",
+			ResourceType: event.ResourceTypeTask,
+			Trigger:      "outcome",
+			Selectors:    []event.Selector{{Type: "object", Data: "task"}},
+			Subscriber:   event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			OwnerType:    event.OwnerTypeProject,
+			Owner:        "proj",
+		},
+	}
+	sc.MockBuildConnector.CachedProjects = map[string]*serviceModel.ProjectRef{
+		"proj": {Identifier: "proj", Admins: []string{"admin"}},
+	}
+
+	app := gimlet.NewApp()
+	app.SetPrefix(sc.GetPrefix())
+	app.AddRoute("/subscriptions/{subscription_id}").Version(2).Get().RouteHandler(makeFetchSubscriptionByID(sc))
+	app.AddRoute("/subscriptions/{subscription_id}").Version(2).Patch().RouteHandler(makePatchSubscription(sc))
+	require.NoError(app.Resolve())
+	router, err := app.Router()
+	require.NoError(err)
+
+	do := func(userID, method, id string, body interface{}) *httptest.ResponseRecorder {
+		var buffer bytes.Buffer
+		if body != nil {
+			require.NoError(json.NewEncoder(&buffer).Encode(body))
+		}
+		req, err := http.NewRequest(method, "/rest/v2/subscriptions/"+id, &buffer)
+		require.NoError(err)
+		req = req.WithContext(gimlet.AttachUser(context.Background(), &user.DBUser{Id: userID}))
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := do("me", http.MethodGet, "mine", nil)
+	assert.Equal(http.StatusOK, rr.Code)
+	sub := model.APISubscription{}
+	assert.NoError(json.Unmarshal(rr.Body.Bytes(), &sub))
+	assert.Equal("mine", model.FromAPIString(sub.ID))
+
+	assert.Equal(http.StatusNotFound, do("me", http.MethodGet, "missing", nil).Code)
+	assert.Equal(http.StatusUnauthorized, do("you", http.MethodGet, "mine", nil).Code)
+	assert.Equal(http.StatusUnauthorized, do("me", http.MethodGet, "project", nil).Code)
+	assert.Equal(http.StatusOK, do("admin", http.MethodGet, "project", nil).Code)
+
+	// only the fields in the body change
+	rr = do("me", http.MethodPatch, "mine", map[string]interface{}{
+		"trigger": "failure",
+		"digest":  event.DigestDaily,
+	})
+	assert.Equal(http.StatusOK, rr.Code)
+	updated, err := sc.FindSubscriptionByID("mine")
+	assert.NoError(err)
+	assert.Equal("failure", updated.Trigger)
+	assert.Equal(event.DigestDaily, updated.Digest)
+	assert.Equal(event.ResourceTypeTask, updated.ResourceType)
+	assert.Len(updated.Selectors, 1)
+	assert.Equal(event.EmailSubscriberType, updated.Subscriber.Type)
+
+	assert.Equal(http.StatusBadRequest, do("me", http.MethodPatch, "mine", map[string]interface{}{
+		"trigger": "not-a-trigger",
+	}).Code)
+	assert.Equal(http.StatusBadRequest, do("me", http.MethodPatch, "mine", map[string]interface{}{
+		"owner": "you",
+	}).Code)
+	assert.Equal(http.StatusBadRequest, do("me", http.MethodPatch, "mine", map[string]interface{}{
+		"selectors": []map[string]string{{"type": "object"}},
+	}).Code)
+	assert.Equal(http.StatusUnauthorized, do("me", http.MethodPatch, "project", map[string]interface{}{
+		"trigger": "failure",
+	}).Code)
+	assert.Equal(http.StatusOK, do("admin", http.MethodPatch, "project", map[string]interface{}{
+		"trigger": "failure",
+	}).Code)
+}
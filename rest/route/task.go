@@ -24,6 +24,7 @@ import (
 type taskGetHandler struct {
 	taskID             string
 	fetchAllExecutions bool
+	fields             model.FieldSelection
 	sc                 data.Connector
 }
 
@@ -43,7 +44,10 @@ func (tgh *taskGetHandler) Factory() gimlet.RouteHandler {
 func (tgh *taskGetHandler) Parse(ctx context.Context, r *http.Request) error {
 	tgh.taskID = gimlet.GetVars(r)["task_id"]
 	_, tgh.fetchAllExecutions = r.URL.Query()["fetch_all_executions"]
-	return nil
+
+	var err error
+	tgh.fields, err = getFieldSelection(r.URL.Query(), model.APITask{})
+	return err
 }
 
 // Execute calls the data FindTaskById function and returns the task
@@ -84,18 +88,23 @@ func (tgh *taskGetHandler) Run(ctx context.Context) gimlet.Responder {
 		}
 	}
 
-	start, err := dbModel.GetEstimatedStartTime(*foundTask)
-	if err != nil {
-		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error getting estimated start time"))
+	if tgh.fields.Includes("est_wait_to_start_ms") {
+		var start time.Duration
+		start, err = dbModel.GetEstimatedStartTime(*foundTask)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error getting estimated start time"))
+		}
+		taskModel.EstimatedStart = model.NewAPIDuration(start)
 	}
-	taskModel.EstimatedStart = model.NewAPIDuration(start)
 
-	err = taskModel.GetArtifacts()
-	if err != nil {
-		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error retrieving artifacts"))
+	if tgh.fields.Includes("artifacts") {
+		err = taskModel.GetArtifacts()
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error retrieving artifacts"))
+		}
 	}
 
-	return gimlet.NewJSONResponse(taskModel)
+	return projectResponse(gimlet.NewJSONResponse(taskModel), tgh.fields)
 }
 
 ////////////////////////////////////////////////////////////////////////
@@ -108,6 +117,7 @@ type projectTaskGetHandler struct {
 	finishedBefore time.Time
 	projectId      string
 	statuses       []string
+	fields         model.FieldSelection
 	sc             data.Connector
 }
 
@@ -163,7 +173,8 @@ func (h *projectTaskGetHandler) Parse(ctx context.Context, r *http.Request) erro
 		h.statuses = statuses
 	}
 
-	return nil
+	h.fields, err = getFieldSelection(vals, model.APITask{})
+	return err
 }
 
 func (h *projectTaskGetHandler) Run(ctx context.Context) gimlet.Responder {
@@ -200,7 +211,7 @@ func (h *projectTaskGetHandler) Run(ctx context.Context) gimlet.Responder {
 		}
 	}
 
-	return resp
+	return projectResponse(resp, h.fields)
 }
 
 // TaskExecutionPatchHandler implements the route PATCH /task/{task_id}. It
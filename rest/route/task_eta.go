@@ -0,0 +1,76 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/eta
+
+// taskETAHandler predicts how long a task takes from the recent runtimes of
+// the task in its project's variant, and when it finishes if it's running.
+type taskETAHandler struct {
+	taskId string
+
+	sc data.Connector
+}
+
+func makeFetchTaskETA(sc data.Connector) gimlet.RouteHandler {
+	return &taskETAHandler{
+		sc: sc,
+	}
+}
+
+func (h *taskETAHandler) Factory() gimlet.RouteHandler {
+	return &taskETAHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *taskETAHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskId = gimlet.GetVars(r)["task_id"]
+
+	return nil
+}
+
+func (h *taskETAHandler) Run(ctx context.Context) gimlet.Responder {
+	t, err := h.sc.FindTaskById(h.taskId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+
+	prediction := &model.APITaskRuntimePrediction{
+		TaskId: model.ToAPIString(t.Id),
+	}
+	expected := t.ExpectedDuration
+	r, err := h.sc.FindTaskRuntime(t.Project, t.BuildVariant, t.DisplayName)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding runtimes of task '%s'", t.Id))
+	}
+	if r != nil {
+		if err = prediction.BuildFromService(r); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		if prediction.Reliable {
+			expected = prediction.P50.ToDuration()
+		}
+	}
+
+	if t.Status == evergreen.TaskStarted {
+		prediction.ExpectedFinish = model.NewTime(t.StartTime.Add(expected))
+		if remaining := expected - time.Since(t.StartTime); remaining > 0 {
+			prediction.Remaining = model.NewAPIDuration(remaining)
+		}
+	}
+
+	return gimlet.NewJSONResponse(prediction)
+}
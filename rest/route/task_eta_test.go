@@ -0,0 +1,66 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/taskruntime"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTaskETA(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	startTime := time.Now().Add(-4 * time.Minute)
+	sc := &data.MockConnector{}
+	sc.MockTaskConnector.CachedTasks = []task.Task{
+		{Id: "running", Project: "p", BuildVariant: "bv", DisplayName: "compile", Status: evergreen.TaskStarted, StartTime: startTime},
+		{Id: "new", Project: "p", BuildVariant: "bv", DisplayName: "lint", Status: evergreen.TaskStarted, StartTime: startTime, ExpectedDuration: 3 * time.Minute},
+		{Id: "queued", Project: "p", BuildVariant: "bv", DisplayName: "compile", Status: evergreen.TaskUndispatched},
+	}
+	samples := []time.Duration{}
+	for i := 1; i <= 10; i++ {
+		samples = append(samples, time.Duration(i)*time.Minute)
+	}
+	sc.MockTaskConnector.CachedTaskRuntimes = []taskruntime.TaskRuntime{
+		{Id: taskruntime.TaskRuntimeKey{Project: "p", Variant: "bv", Task: "compile"}, Samples: samples},
+	}
+
+	run := func(taskId string) *model.APITaskRuntimePrediction {
+		h := makeFetchTaskETA(sc).(*taskETAHandler)
+		h.taskId = taskId
+		resp := h.Run(context.Background())
+		require.Equal(http.StatusOK, resp.Status())
+		prediction, ok := resp.Data().(*model.APITaskRuntimePrediction)
+		require.True(ok)
+		return prediction
+	}
+
+	prediction := run("running")
+	assert.Equal(10, prediction.NumSamples)
+	assert.True(prediction.Reliable)
+	assert.Equal(model.NewAPIDuration(5*time.Minute), prediction.P50)
+	assert.Equal(model.NewAPIDuration(9*time.Minute), prediction.P90)
+	assert.Equal(model.NewAPIDuration(10*time.Minute), prediction.P99)
+	assert.WithinDuration(startTime.Add(5*time.Minute), time.Time(prediction.ExpectedFinish), time.Second)
+	assert.InDelta(float64(time.Minute/time.Millisecond), float64(prediction.Remaining), 1000)
+
+	// tasks without runtimes fall back to their expected duration
+	prediction = run("new")
+	assert.Equal(0, prediction.NumSamples)
+	assert.False(prediction.Reliable)
+	assert.WithinDuration(startTime.Add(3*time.Minute), time.Time(prediction.ExpectedFinish), time.Second)
+	assert.Equal(model.NewAPIDuration(0), prediction.Remaining)
+
+	prediction = run("queued")
+	assert.Equal(model.NewAPIDuration(5*time.Minute), prediction.P50)
+	assert.True(time.Time(prediction.ExpectedFinish).IsZero())
+}
@@ -0,0 +1,264 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/structured_logs
+
+// taskStructuredLogsHandler returns the structured log lines of a task,
+// oldest first. The lines can be filtered by minimum level, by component,
+// and to the time range [start, end); they're from the task's latest
+// execution unless an execution is given.
+type taskStructuredLogsHandler struct {
+	taskId     string
+	execution  int
+	minLevel   level.Priority
+	components []string
+	start      time.Time
+	end        time.Time
+	limit      int
+
+	sc data.Connector
+}
+
+func makeFetchTaskStructuredLogs(sc data.Connector) gimlet.RouteHandler {
+	return &taskStructuredLogsHandler{
+		sc: sc,
+	}
+}
+
+func (h *taskStructuredLogsHandler) Factory() gimlet.RouteHandler {
+	return &taskStructuredLogsHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *taskStructuredLogsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.taskId = gimlet.GetVars(r)["task_id"]
+	vals := r.URL.Query()
+
+	var err error
+	h.execution = -1
+	if execution := vals.Get("execution"); execution != "" {
+		h.execution, err = strconv.Atoi(execution)
+		if err != nil || h.execution < 0 {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid execution '%s'", execution),
+			}
+		}
+	}
+
+	if l := vals.Get("level"); l != "" {
+		h.minLevel = level.FromString(l)
+		if !level.IsValidPriority(h.minLevel) {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid level '%s'", l),
+			}
+		}
+	}
+
+	h.components = vals["component"]
+
+	for param, t := range map[string]*time.Time{"start": &h.start, "end": &h.end} {
+		if v := vals.Get(param); v != "" {
+			if *t, err = model.ParseTime(v); err != nil {
+				return gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    fmt.Sprintf("invalid %s time '%s'", param, v),
+				}
+			}
+		}
+	}
+
+	h.limit, err = getLimit(vals)
+	return errors.WithStack(err)
+}
+
+func (h *taskStructuredLogsHandler) Run(ctx context.Context) gimlet.Responder {
+	if h.execution < 0 {
+		t, err := h.sc.FindTaskById(h.taskId)
+		if err != nil {
+			return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding task '%s'", h.taskId))
+		}
+		h.execution = t.Execution
+	}
+
+	logs, err := h.sc.FindStructuredTaskLogs(dbModel.StructuredTaskLogFilter{
+		TaskId:     h.taskId,
+		Execution:  h.execution,
+		MinLevel:   h.minLevel,
+		Components: h.components,
+		Start:      h.start,
+		End:        h.end,
+		Limit:      h.limit,
+	})
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding structured logs of task '%s'", h.taskId))
+	}
+
+	apiLogs := make([]model.APIStructuredTaskLog, 0, len(logs))
+	for _, l := range logs {
+		apiLog := model.APIStructuredTaskLog{}
+		if err = apiLog.BuildFromService(l); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		apiLogs = append(apiLogs, apiLog)
+	}
+
+	return gimlet.NewJSONResponse(apiLogs)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/tasks/{task_id}/logs/stream
+
+const taskLogStreamBatchSize = 1000
+
+// makeTaskLogStream returns a handler that streams a task's log to the client
+// as server-sent events while the agent uploads it, and ends the stream once
+// the task is finished and its whole log has been sent. Each event's ID is
+// the offset in the log after its message, so that clients resume the stream
+// from it with the Last-Event-ID header or the offset parameter. The log is
+// of the task's latest execution unless an execution is given, and may be
+// filtered to some types of messages with the type parameter.
+func makeTaskLogStream(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		taskId := gimlet.GetVars(r)["task_id"]
+		types := r.URL.Query()["type"]
+
+		offsetParam := r.Header.Get("Last-Event-ID")
+		if offsetParam == "" {
+			offsetParam = r.FormValue("offset")
+		}
+		offset := 0
+		if offsetParam != "" {
+			var err error
+			offset, err = strconv.Atoi(offsetParam)
+			if err != nil || offset < 0 {
+				gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    fmt.Sprintf("invalid offset '%s'", offsetParam),
+				}))
+				return
+			}
+		}
+
+		t, err := sc.FindTaskById(taskId)
+		if err != nil {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding task '%s'", taskId)))
+			return
+		}
+		execution := t.Execution
+		if e := r.FormValue("execution"); e != "" {
+			execution, err = strconv.Atoi(e)
+			if err != nil || execution < 0 || execution > t.Execution {
+				gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+					StatusCode: http.StatusBadRequest,
+					Message:    fmt.Sprintf("invalid execution '%s'", e),
+				}))
+				return
+			}
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(errors.New("streaming is not supported")))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", eventStreamRetryDelay/time.Millisecond)
+		flusher.Flush()
+
+		ctx, cancel := context.WithTimeout(r.Context(), eventStreamDuration)
+		defer cancel()
+		ticker := time.NewTicker(eventStreamPollInterval)
+		defer ticker.Stop()
+
+		lastWrite := time.Now()
+		for {
+			// the task is checked before its log is read, so that the
+			// messages it logged before it finished are all sent
+			finished := execution < t.Execution || t.IsFinished()
+			var msgs []apimodels.LogMessage
+			msgs, err = sc.FindTaskLogMessages(taskId, execution, offset, taskLogStreamBatchSize)
+			if err != nil {
+				grip.Error(message.WrapError(err, message.Fields{
+					"message":   "problem finding task log messages to stream",
+					"task":      taskId,
+					"execution": execution,
+					"offset":    offset,
+				}))
+				writeStreamEvent(w, "", "error", map[string]string{"error": err.Error()})
+				flusher.Flush()
+				return
+			}
+			for _, msg := range msgs {
+				offset++
+				if len(types) > 0 && !util.StringSliceContains(types, msg.Type) {
+					continue
+				}
+				apiMsg := model.APILogMessage{}
+				if err = apiMsg.BuildFromService(msg); err != nil {
+					continue
+				}
+				writeStreamEvent(w, strconv.Itoa(offset), "log", &apiMsg)
+			}
+			if finished && len(msgs) < taskLogStreamBatchSize {
+				writeStreamEvent(w, strconv.Itoa(offset), "end", map[string]string{"status": t.Status})
+				flusher.Flush()
+				return
+			}
+			if len(msgs) > 0 {
+				lastWrite = time.Now()
+				flusher.Flush()
+			} else if time.Since(lastWrite) >= eventStreamKeepAliveInterval {
+				fmt.Fprint(w, ": keepalive\n\n")
+				lastWrite = time.Now()
+				flusher.Flush()
+			}
+
+			if len(msgs) < taskLogStreamBatchSize {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			} else if ctx.Err() != nil {
+				return
+			}
+
+			if !finished {
+				if t, err = sc.FindTaskById(taskId); err != nil {
+					writeStreamEvent(w, "", "error", map[string]string{"error": err.Error()})
+					flusher.Flush()
+					return
+				}
+			}
+		}
+	}
+}
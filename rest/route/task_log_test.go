@@ -0,0 +1,145 @@
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip/level"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchTaskStructuredLogs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	start := time.Now().Round(time.Second)
+	sc := &data.MockConnector{}
+	sc.MockTaskConnector.CachedTasks = []task.Task{{Id: "t1", Execution: 1}}
+	for execution := 0; execution <= 1; execution++ {
+		sc.MockTaskLogConnector.CachedStructuredLogs = append(sc.MockTaskLogConnector.CachedStructuredLogs,
+			dbModel.StructuredTaskLog{TaskId: "t1", Execution: execution, Component: "agent", Level: "debug", Priority: int(level.Debug), Message: "0", Timestamp: start},
+			dbModel.StructuredTaskLog{TaskId: "t1", Execution: execution, Component: "task", Level: "info", Priority: int(level.Info), Message: "1", Timestamp: start.Add(time.Second)},
+			dbModel.StructuredTaskLog{TaskId: "t1", Execution: execution, Component: "task", Level: "error", Priority: int(level.Error), Message: "2", Timestamp: start.Add(2 * time.Second), Fields: map[string]interface{}{"exit_code": 1}},
+		)
+	}
+
+	run := func(h *taskStructuredLogsHandler) []model.APIStructuredTaskLog {
+		resp := h.Run(context.Background())
+		require.Equal(http.StatusOK, resp.Status())
+		logs, ok := resp.Data().([]model.APIStructuredTaskLog)
+		require.True(ok)
+		return logs
+	}
+	messages := func(logs []model.APIStructuredTaskLog) []string {
+		out := []string{}
+		for _, l := range logs {
+			out = append(out, model.FromAPIString(l.Message))
+		}
+		return out
+	}
+
+	// the latest execution is used by default
+	h := makeFetchTaskStructuredLogs(sc).(*taskStructuredLogsHandler)
+	h.taskId = "t1"
+	h.execution = -1
+	h.minLevel = level.Warning
+	logs := run(h)
+	require.Len(logs, 1)
+	assert.Equal(1, logs[0].Execution)
+	assert.Equal("error", model.FromAPIString(logs[0].Level))
+	assert.Equal(map[string]interface{}{"exit_code": 1}, logs[0].Fields)
+
+	h = makeFetchTaskStructuredLogs(sc).(*taskStructuredLogsHandler)
+	h.taskId = "t1"
+	h.components = []string{"task"}
+	h.limit = 1
+	logs = run(h)
+	assert.Equal([]string{"1"}, messages(logs))
+	assert.Equal(0, logs[0].Execution)
+
+	h = makeFetchTaskStructuredLogs(sc).(*taskStructuredLogsHandler)
+	h.taskId = "t1"
+	h.start = start
+	h.end = start.Add(2 * time.Second)
+	assert.Equal([]string{"0", "1"}, messages(run(h)))
+}
+
+func TestTaskLogStream(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	sc.MockTaskConnector.CachedTasks = []task.Task{
+		{Id: "finished", Execution: 1, Status: evergreen.TaskFailed},
+		{Id: "running", Status: evergreen.TaskStarted},
+	}
+	sc.MockTaskLogConnector.CachedTaskLogs = []dbModel.TaskLog{
+		{TaskId: "finished", Execution: 0, Messages: []apimodels.LogMessage{{Type: apimodels.TaskLogPrefix, Message: "old"}}},
+		{TaskId: "finished", Execution: 1, Messages: []apimodels.LogMessage{
+			{Type: apimodels.TaskLogPrefix, Severity: apimodels.LogInfoPrefix, Message: "m0"},
+			{Type: apimodels.AgentLogPrefix, Severity: apimodels.LogInfoPrefix, Message: "m1"},
+		}},
+		{TaskId: "finished", Execution: 1, Messages: []apimodels.LogMessage{{Type: apimodels.TaskLogPrefix, Severity: apimodels.LogErrorPrefix, Message: "m2"}}},
+		{TaskId: "running", Messages: []apimodels.LogMessage{{Type: apimodels.TaskLogPrefix, Message: "r0"}}},
+	}
+
+	app := gimlet.NewApp()
+	app.AddRoute("/tasks/{task_id}/logs/stream").Version(2).Get().Handler(makeTaskLogStream(sc))
+	handler, err := app.Handler()
+	require.NoError(err)
+
+	stream := func(path, lastEventID string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		r, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(err)
+		if lastEventID != "" {
+			r.Header.Set("Last-Event-ID", lastEventID)
+		}
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, r.WithContext(ctx))
+		return rw
+	}
+
+	assert.Equal(http.StatusBadRequest, stream("/v2/tasks/finished/logs/stream?offset=x", "").Code)
+	assert.Equal(http.StatusBadRequest, stream("/v2/tasks/finished/logs/stream?execution=2", "").Code)
+
+	// the stream of a finished task ends once its whole log has been sent
+	rw := stream("/v2/tasks/finished/logs/stream", "")
+	require.Equal(http.StatusOK, rw.Code)
+	assert.Equal("text/event-stream", rw.Header().Get("Content-Type"))
+	body := rw.Body.String()
+	assert.True(strings.HasPrefix(body, "retry: 1000\n\n"))
+	assert.Contains(body, "id: 1\nevent: log\ndata: {")
+	assert.Contains(body, `"message":"m1"`)
+	assert.Contains(body, "id: 3\nevent: log\n")
+	assert.True(strings.HasSuffix(body, "id: 3\nevent: end\ndata: {\"status\":\"failed\"}\n\n"))
+	assert.NotContains(body, "old")
+
+	// resuming from an offset, and filtering by type
+	body = stream("/v2/tasks/finished/logs/stream?type=T", "1").Body.String()
+	assert.NotContains(body, "m0")
+	assert.NotContains(body, "m1")
+	assert.Contains(body, "id: 3\nevent: log\n")
+
+	body = stream("/v2/tasks/finished/logs/stream?execution=0", "").Body.String()
+	assert.Contains(body, `"message":"old"`)
+	assert.Contains(body, "id: 1\nevent: end\n")
+
+	// the stream of a running task stays open
+	body = stream("/v2/tasks/running/logs/stream", "").Body.String()
+	assert.Contains(body, "id: 1\nevent: log\n")
+	assert.NotContains(body, "event: end")
+}
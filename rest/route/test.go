@@ -2,11 +2,18 @@ package route
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"strconv"
+	"strings"
 
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -123,3 +130,179 @@ func (tgh *testGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return resp
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// POST /rest/v2/tasks/{task_id}/test_reports
+
+// maxTestReportSize is the largest test report that can be ingested.
+const maxTestReportSize = 16 * 1024 * 1024
+
+// testReportPostHandler converts the raw JUnit XML or TAP report in the
+// request body into test results of the task. Only the project's
+// contributors can add test results, and only to tasks that haven't
+// finished.
+type testReportPostHandler struct {
+	task       *task.Task
+	projectRef *dbModel.ProjectRef
+	format     string
+	report     []byte
+	sc         data.Connector
+}
+
+func makeIngestTestReport(sc data.Connector) gimlet.RouteHandler {
+	return &testReportPostHandler{
+		sc: sc,
+	}
+}
+
+func (h *testReportPostHandler) Factory() gimlet.RouteHandler {
+	return &testReportPostHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *testReportPostHandler) Parse(ctx context.Context, r *http.Request) error {
+	projCtx := MustHaveProjectContext(ctx)
+	if projCtx.Task == nil {
+		return gimlet.ErrorResponse{
+			Message:    "Task not found",
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	h.task = projCtx.Task
+	h.projectRef = projCtx.ProjectRef
+
+	h.format = r.URL.Query().Get("format")
+	if !util.StringSliceContains(dbModel.ValidTestReportFormats, h.format) {
+		return gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("format must be one of %s", strings.Join(dbModel.ValidTestReportFormats, ", ")),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	body := http.MaxBytesReader(nil, r.Body, maxTestReportSize)
+	defer body.Close()
+	var err error
+	if h.report, err = ioutil.ReadAll(body); err != nil {
+		if _, ok := err.(*http.MaxBytesError); ok {
+			return gimlet.ErrorResponse{
+				Message:    fmt.Sprintf("test report cannot exceed %d bytes", maxTestReportSize),
+				StatusCode: http.StatusRequestEntityTooLarge,
+			}
+		}
+		return errors.Wrap(err, "problem reading test report")
+	}
+	if len(h.report) == 0 {
+		return gimlet.ErrorResponse{
+			Message:    "No test report sent",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+func (h *testReportPostHandler) Run(ctx context.Context) gimlet.Responder {
+	if h.projectRef == nil {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("project of task '%s' not found", h.task.Id),
+			StatusCode: http.StatusNotFound,
+		})
+	}
+	if err := checkProjectRole(ctx, h.sc, h.projectRef, user.RoleContributor, "add test results to tasks"); err != nil {
+		return gimlet.MakeJSONErrorResponder(err)
+	}
+	if h.task.IsFinished() {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			Message:    fmt.Sprintf("cannot add test results to task '%s', because it has finished", h.task.Id),
+			StatusCode: http.StatusBadRequest,
+		})
+	}
+
+	tests, err := h.sc.IngestTestReport(h.task, h.format, h.report)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem ingesting test report for task '%s'", h.task.Id))
+	}
+
+	apiTests := make([]model.APITest, 0, len(tests))
+	for i := range tests {
+		apiTest := model.APITest{}
+		if err = apiTest.BuildFromService(h.task.Id); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		if err = apiTest.BuildFromService(&tests[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		apiTests = append(apiTests, apiTest)
+	}
+
+	return gimlet.NewJSONResponse(apiTests)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/projects/{project_id}/test_flakiness
+
+// testFlakinessGetHandler lists how often the tests of a project flaked in
+// its recent mainline versions, most flaky first.
+type testFlakinessGetHandler struct {
+	projectId string
+	variant   string
+	taskName  string
+	flakyOnly bool
+	sc        data.Connector
+}
+
+func makeFetchTestFlakiness(sc data.Connector) gimlet.RouteHandler {
+	return &testFlakinessGetHandler{
+		sc: sc,
+	}
+}
+
+func (h *testFlakinessGetHandler) Factory() gimlet.RouteHandler {
+	return &testFlakinessGetHandler{
+		sc: h.sc,
+	}
+}
+
+func (h *testFlakinessGetHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectId = gimlet.GetVars(r)["project_id"]
+	vals := r.URL.Query()
+	h.variant = vals.Get("build_variant")
+	h.taskName = vals.Get("task")
+
+	if flakyOnly := vals.Get("flaky_only"); flakyOnly != "" {
+		var err error
+		h.flakyOnly, err = strconv.ParseBool(flakyOnly)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				Message:    "Invalid flaky_only",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	return nil
+}
+
+func (h *testFlakinessGetHandler) Run(ctx context.Context) gimlet.Responder {
+	stats, err := h.sc.FindTestFlakiness(h.projectId, h.variant, h.taskName)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "problem finding test flakiness of project '%s'", h.projectId))
+	}
+
+	apiStats := make([]model.APITestFlakiness, 0, len(stats))
+	for i := range stats {
+		if h.flakyOnly && !stats[i].IsFlaky() {
+			continue
+		}
+		apiStat := model.APITestFlakiness{}
+		if err = apiStat.BuildFromService(&stats[i]); err != nil {
+			return gimlet.MakeJSONInternalErrorResponder(err)
+		}
+		apiStats = append(apiStats, apiStat)
+	}
+
+	return gimlet.NewJSONResponse(apiStats)
+}
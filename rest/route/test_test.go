@@ -0,0 +1,128 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngestTestReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{}
+	projCtx := dbModel.Context{
+		Task:       &task.Task{Id: "t1", Execution: 1, Status: evergreen.TaskStarted},
+		ProjectRef: &dbModel.ProjectRef{Identifier: "mci", Contributors: []string{"me"}},
+	}
+	ctx := context.WithValue(context.Background(), RequestContext, &projCtx)
+	ctx = gimlet.AttachUser(ctx, &user.DBUser{Id: "me"})
+	report := "1..2\nok 1 - passes\nnot ok 2 - fails\n"
+
+	h := makeIngestTestReport(sc).Factory()
+	r, err := http.NewRequest(http.MethodPost, "/tasks/t1/test_reports?format=tap", bytes.NewBufferString(report))
+	require.NoError(err)
+	require.NoError(h.Parse(ctx, r))
+
+	resp := h.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	tests, ok := resp.Data().([]model.APITest)
+	require.True(ok)
+	require.Len(tests, 2)
+	assert.Equal("t1", model.FromAPIString(tests[0].TaskId))
+	assert.Equal("passes", model.FromAPIString(tests[0].TestFile))
+	assert.Equal(evergreen.TestSucceededStatus, model.FromAPIString(tests[0].Status))
+	assert.Equal(evergreen.TestFailedStatus, model.FromAPIString(tests[1].Status))
+	assert.Len(sc.MockTestConnector.CachedTests, 2)
+
+	h = makeIngestTestReport(sc).Factory()
+	r, err = http.NewRequest(http.MethodPost, "/tasks/t1/test_reports?format=csv", bytes.NewBufferString(report))
+	require.NoError(err)
+	assert.Error(h.Parse(ctx, r))
+
+	h = makeIngestTestReport(sc).Factory()
+	r, err = http.NewRequest(http.MethodPost, "/tasks/t1/test_reports?format=junit", bytes.NewBufferString(""))
+	require.NoError(err)
+	assert.Error(h.Parse(ctx, r))
+
+	h = makeIngestTestReport(sc).Factory()
+	r, err = http.NewRequest(http.MethodPost, "/tasks/t1/test_reports?format=junit", bytes.NewBufferString("not xml"))
+	require.NoError(err)
+	require.NoError(h.Parse(ctx, r))
+	assert.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+
+	h = makeIngestTestReport(sc).Factory()
+	r, err = http.NewRequest(http.MethodPost, "/tasks/t1/test_reports?format=tap", bytes.NewBuffer(make([]byte, maxTestReportSize+1)))
+	require.NoError(err)
+	err = h.Parse(ctx, r)
+	require.Error(err)
+	errResp, ok := err.(gimlet.ErrorResponse)
+	require.True(ok)
+	assert.Equal(http.StatusRequestEntityTooLarge, errResp.StatusCode)
+
+	// only contributors can add test results, and only to unfinished tasks
+	h = makeIngestTestReport(sc).Factory()
+	r, err = http.NewRequest(http.MethodPost, "/tasks/t1/test_reports?format=tap", bytes.NewBufferString(report))
+	require.NoError(err)
+	require.NoError(h.Parse(ctx, r))
+	assert.Equal(http.StatusUnauthorized, h.Run(gimlet.AttachUser(ctx, &user.DBUser{Id: "anyone"})).Status())
+
+	projCtx.Task.Status = evergreen.TaskFailed
+	assert.Equal(http.StatusBadRequest, h.Run(ctx).Status())
+	assert.Len(sc.MockTestConnector.CachedTests, 2)
+}
+
+func TestFetchTestFlakiness(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	stat := func(project, test string, runs int, rate float64) testflakiness.TestFlakiness {
+		return testflakiness.TestFlakiness{
+			Id:        testflakiness.TestFlakinessKey{Project: project, Variant: "bv", Task: "task", Test: test},
+			Runs:      runs,
+			FlakeRate: rate,
+		}
+	}
+	sc := &data.MockConnector{}
+	sc.MockTestConnector.CachedTestFlakiness = []testflakiness.TestFlakiness{
+		stat("p", "flaky", 10, 0.5),
+		stat("p", "rare", 10, 0.05),
+		stat("other", "flaky", 10, 0.5),
+	}
+	ctx := context.Background()
+
+	h := makeFetchTestFlakiness(sc).Factory()
+	r, err := http.NewRequest(http.MethodGet, "/projects/p/test_flakiness?flaky_only=maybe", nil)
+	require.NoError(err)
+	assert.Error(h.Parse(ctx, r))
+
+	h = &testFlakinessGetHandler{sc: sc, projectId: "p"}
+	resp := h.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	stats, ok := resp.Data().([]model.APITestFlakiness)
+	require.True(ok)
+	require.Len(stats, 2)
+	assert.Equal("flaky", model.FromAPIString(stats[0].Test))
+	assert.True(stats[0].Flaky)
+	assert.False(stats[1].Flaky)
+
+	h = &testFlakinessGetHandler{sc: sc, projectId: "p", flakyOnly: true}
+	resp = h.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	stats, ok = resp.Data().([]model.APITestFlakiness)
+	require.True(ok)
+	require.Len(stats, 1)
+	assert.Equal("p", model.FromAPIString(stats[0].Project))
+}
@@ -115,3 +115,143 @@ func (h *userSettingsGetHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(apiSettings)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/user/settings/delivery
+
+type userDeliveryGetHandler struct{}
+
+func makeFetchUserDeliveryPreferences() gimlet.RouteHandler {
+	return &userDeliveryGetHandler{}
+}
+
+func (h *userDeliveryGetHandler) Factory() gimlet.RouteHandler                     { return h }
+func (h *userDeliveryGetHandler) Parse(ctx context.Context, r *http.Request) error { return nil }
+
+func (h *userDeliveryGetHandler) Run(ctx context.Context) gimlet.Responder {
+	return buildDeliveryPreferencesResponse(MustHaveUser(ctx).Settings.Delivery)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/user/settings/delivery
+// DELETE /rest/v2/user/settings/delivery
+
+type userDeliveryPutHandler struct {
+	prefs  user.DeliveryPreferences
+	remove bool
+	sc     data.Connector
+}
+
+func makeSetUserDeliveryPreferences(sc data.Connector) gimlet.RouteHandler {
+	return &userDeliveryPutHandler{sc: sc}
+}
+
+func makeDeleteUserDeliveryPreferences(sc data.Connector) gimlet.RouteHandler {
+	return &userDeliveryPutHandler{sc: sc, remove: true}
+}
+
+func (h *userDeliveryPutHandler) Factory() gimlet.RouteHandler {
+	return &userDeliveryPutHandler{sc: h.sc, remove: h.remove}
+}
+
+func (h *userDeliveryPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	if h.remove {
+		return nil
+	}
+
+	apiPrefs := model.APIDeliveryPreferences{}
+	if err := gimlet.GetJSON(r.Body, &apiPrefs); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	prefs, err := apiPrefs.ToService()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	h.prefs = prefs.(user.DeliveryPreferences)
+
+	return nil
+}
+
+func (h *userDeliveryPutHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	if err := h.sc.UpdateDeliveryPreferences(u, h.prefs); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error saving delivery preferences"))
+	}
+
+	return buildDeliveryPreferencesResponse(u.Settings.Delivery)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PUT /rest/v2/user/settings/delivery/projects/{project_id}
+// DELETE /rest/v2/user/settings/delivery/projects/{project_id}
+
+type userProjectDeliveryPutHandler struct {
+	projectID string
+	prefs     *user.ProjectDeliveryPreferences
+	remove    bool
+	sc        data.Connector
+}
+
+func makeSetUserProjectDeliveryPreferences(sc data.Connector) gimlet.RouteHandler {
+	return &userProjectDeliveryPutHandler{sc: sc}
+}
+
+func makeDeleteUserProjectDeliveryPreferences(sc data.Connector) gimlet.RouteHandler {
+	return &userProjectDeliveryPutHandler{sc: sc, remove: true}
+}
+
+func (h *userProjectDeliveryPutHandler) Factory() gimlet.RouteHandler {
+	return &userProjectDeliveryPutHandler{sc: h.sc, remove: h.remove}
+}
+
+func (h *userProjectDeliveryPutHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.projectID = gimlet.GetVars(r)["project_id"]
+	if h.projectID == "" {
+		return errors.New("project ID cannot be empty")
+	}
+	if h.remove {
+		return nil
+	}
+
+	apiPrefs := model.APIProjectDeliveryPreferences{}
+	if err := gimlet.GetJSON(r.Body, &apiPrefs); err != nil {
+		return errors.Wrap(err, "problem parsing request body")
+	}
+	prefs, err := apiPrefs.ToService()
+	if err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	projectPrefs := prefs.(user.ProjectDeliveryPreferences)
+	h.prefs = &projectPrefs
+
+	return nil
+}
+
+func (h *userProjectDeliveryPutHandler) Run(ctx context.Context) gimlet.Responder {
+	u := MustHaveUser(ctx)
+	prefs := u.Settings.Delivery
+	prefs.SetProject(h.projectID, h.prefs)
+	if err := h.sc.UpdateDeliveryPreferences(u, prefs); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error saving delivery preferences"))
+	}
+
+	return buildDeliveryPreferencesResponse(u.Settings.Delivery)
+}
+
+func buildDeliveryPreferencesResponse(prefs user.DeliveryPreferences) gimlet.Responder {
+	apiPrefs := model.APIDeliveryPreferences{}
+	if err := apiPrefs.BuildFromService(prefs); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "error formatting delivery preferences"))
+	}
+
+	return gimlet.NewJSONResponse(apiPrefs)
+}
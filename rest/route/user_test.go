@@ -11,8 +11,10 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/rest/data"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -101,3 +103,59 @@ func (s *UserRouteSuite) TestUndefinedInput() {
 	s.EqualValues("something", dbUser.Settings.SlackUsername)
 	s.EqualValues("you", dbUser.Settings.GithubUser.LastKnownAs)
 }
+
+func TestUserDeliveryPreferencesHandlers(t *testing.T) {
+	assert := assert.New(t)
+
+	sc := &data.MockConnector{}
+	u := &user.DBUser{Id: "me"}
+	ctx := gimlet.AttachUser(context.Background(), u)
+
+	put := makeSetUserDeliveryPreferences(sc).Factory()
+	request, err := http.NewRequest(http.MethodPut, "/user/settings/delivery", bytes.NewBufferString(`{"quiet_hours": {"start": "22:00", "end": "07:30"}, "channel_priority": ["slack", "email"]}`))
+	assert.NoError(err)
+	assert.NoError(put.Parse(ctx, request))
+	resp := put.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	assert.Equal(&user.QuietHours{Start: 22 * 60, End: 7*60 + 30}, u.Settings.Delivery.QuietHours)
+	assert.Equal([]string{"slack", "email"}, u.Settings.Delivery.ChannelPriority)
+
+	request, err = http.NewRequest(http.MethodPut, "/user/settings/delivery", bytes.NewBufferString(`{"quiet_hours": {"start": "10pm", "end": "07:30"}, "channel_priority": ["pager"]}`))
+	assert.NoError(err)
+	err = put.Factory().Parse(ctx, request)
+	assert.Error(err)
+	assert.Contains(err.Error(), "quiet_hours.start: '10pm' is not a time of day")
+
+	putProject := makeSetUserProjectDeliveryPreferences(sc).Factory()
+	request, err = http.NewRequest(http.MethodPut, "/user/settings/delivery/projects/", bytes.NewBufferString(`{"channel_priority": ["email"]}`))
+	assert.NoError(err)
+	assert.EqualError(putProject.Parse(ctx, request), "project ID cannot be empty")
+	putProject.(*userProjectDeliveryPutHandler).projectID = "mci"
+	putProject.(*userProjectDeliveryPutHandler).prefs = &user.ProjectDeliveryPreferences{ChannelPriority: []string{"email"}}
+	resp = putProject.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	if assert.Len(u.Settings.Delivery.Projects, 1) {
+		assert.Equal("mci", u.Settings.Delivery.Projects[0].Project)
+		assert.Equal([]string{"email"}, u.Settings.Delivery.Projects[0].ChannelPriority)
+	}
+
+	get := makeFetchUserDeliveryPreferences().Factory()
+	resp = get.Run(ctx)
+	assert.Equal(http.StatusOK, resp.Status())
+	apiPrefs := resp.Data().(restModel.APIDeliveryPreferences)
+	assert.Equal("22:00", restModel.FromAPIString(apiPrefs.QuietHours.Start))
+	assert.Equal("07:30", restModel.FromAPIString(apiPrefs.QuietHours.End))
+	assert.Len(apiPrefs.Projects, 1)
+
+	deleteProject := makeDeleteUserProjectDeliveryPreferences(sc).Factory()
+	deleteProject.(*userProjectDeliveryPutHandler).projectID = "mci"
+	assert.Equal(http.StatusOK, deleteProject.Run(ctx).Status())
+	assert.Empty(u.Settings.Delivery.Projects)
+
+	remove := makeDeleteUserDeliveryPreferences(sc).Factory()
+	request, err = http.NewRequest(http.MethodDelete, "/user/settings/delivery", nil)
+	assert.NoError(err)
+	assert.NoError(remove.Parse(ctx, request))
+	assert.Equal(http.StatusOK, remove.Run(ctx).Status())
+	assert.Equal(user.DeliveryPreferences{}, u.Settings.Delivery)
+}
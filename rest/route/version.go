@@ -1,11 +1,18 @@
 package route
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/pkg/errors"
 )
@@ -16,6 +23,7 @@ import (
 
 type versionHandler struct {
 	versionId string
+	fields    model.FieldSelection
 	sc        data.Connector
 }
 
@@ -40,7 +48,9 @@ func (vh *versionHandler) Parse(ctx context.Context, r *http.Request) error {
 		return errors.New("request data incomplete")
 	}
 
-	return nil
+	var err error
+	vh.fields, err = getFieldSelection(r.URL.Query(), model.APIVersion{})
+	return err
 }
 
 // Execute calls the data FindVersionById function and returns the version
@@ -50,15 +60,141 @@ func (vh *versionHandler) Run(ctx context.Context) gimlet.Responder {
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
 	}
+	if resp := checkProjectVisible(ctx, vh.sc, foundVersion.Identifier, "view versions"); resp != nil {
+		return resp
+	}
 
 	versionModel := &model.APIVersion{}
 
+	if err = versionModel.BuildFromService(foundVersion); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return projectResponse(gimlet.NewJSONResponse(versionModel), vh.fields)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// PATCH /rest/v2/versions/{version_id}
+
+// versionChangePriorityHandler sets the priority of a version's tasks, which
+// tasks added to the version later inherit.
+type versionChangePriorityHandler struct {
+	Priority *int64 `json:"priority"`
+
+	versionId string
+	sc        data.Connector
+}
+
+func makeChangeVersionPriority(sc data.Connector) gimlet.RouteHandler {
+	return &versionChangePriorityHandler{
+		sc: sc,
+	}
+}
+
+func (vh *versionChangePriorityHandler) Factory() gimlet.RouteHandler {
+	return &versionChangePriorityHandler{
+		sc: vh.sc,
+	}
+}
+
+func (vh *versionChangePriorityHandler) Parse(ctx context.Context, r *http.Request) error {
+	vh.versionId = gimlet.GetVars(r)["version_id"]
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	if err := util.ReadJSONInto(body, vh); err != nil {
+		return errors.Wrap(err, "Argument read error")
+	}
+
+	if vh.Priority == nil {
+		return gimlet.ErrorResponse{
+			Message:    "Must set 'priority'",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+func (vh *versionChangePriorityHandler) Run(ctx context.Context) gimlet.Responder {
+	user := MustHaveUser(ctx)
+	priority := *vh.Priority
+	if ok := validPriority(priority, user, vh.sc); !ok {
+		return gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+			Message: fmt.Sprintf("Insufficient privilege to set priority to %d, "+
+				"non-superusers can only set priority at or below %d", priority, evergreen.MaxTaskPriority),
+			StatusCode: http.StatusForbidden,
+		})
+	}
+
+	foundVersion, err := vh.sc.FindVersionById(vh.versionId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if err = vh.sc.SetVersionPriority(vh.versionId, priority); err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	foundVersion.Priority = priority
+
+	versionModel := &model.APIVersion{}
 	if err = versionModel.BuildFromService(foundVersion); err != nil {
 		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
 	}
 	return gimlet.NewJSONResponse(versionModel)
 }
 
+////////////////////////////////////////////////////////////////////////
+//
+// GET /rest/v2/versions/{version_id}/manifest
+
+// versionManifestHandler is a RequestHandler for fetching the module
+// revisions pinned for a version
+type versionManifestHandler struct {
+	versionId string
+	sc        data.Connector
+}
+
+func makeGetVersionManifest(sc data.Connector) gimlet.RouteHandler {
+	return &versionManifestHandler{
+		sc: sc,
+	}
+}
+
+// Handler returns a pointer to a new versionManifestHandler.
+func (h *versionManifestHandler) Factory() gimlet.RouteHandler {
+	return &versionManifestHandler{
+		sc: h.sc,
+	}
+}
+
+// ParseAndValidate fetches the versionId from the http request.
+func (h *versionManifestHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.versionId = gimlet.GetVars(r)["version_id"]
+
+	if h.versionId == "" {
+		return errors.New("request data incomplete")
+	}
+
+	return nil
+}
+
+// Execute calls the data FindManifestByVersionId function and returns the
+// manifest from the provider.
+func (h *versionManifestHandler) Run(ctx context.Context) gimlet.Responder {
+	foundManifest, err := h.sc.FindManifestByVersionId(h.versionId)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error"))
+	}
+	if resp := checkProjectVisible(ctx, h.sc, foundManifest.ProjectName, "view versions"); resp != nil {
+		return resp
+	}
+
+	manifestModel := &model.APIManifest{}
+	if err = manifestModel.BuildFromService(foundManifest); err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "API model error"))
+	}
+	return gimlet.NewJSONResponse(manifestModel)
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // GET /rest/v2/versions/{version_id}/builds
@@ -66,6 +202,7 @@ func (vh *versionHandler) Run(ctx context.Context) gimlet.Responder {
 // buildsForVersionHandler is a RequestHandler for fetching all builds for a version
 type buildsForVersionHandler struct {
 	versionId string
+	fields    model.FieldSelection
 	sc        data.Connector
 }
 
@@ -90,7 +227,9 @@ func (h *buildsForVersionHandler) Parse(ctx context.Context, r *http.Request) er
 		return errors.New("request data incomplete")
 	}
 
-	return nil
+	var err error
+	h.fields, err = getFieldSelection(r.URL.Query(), model.APIBuild{})
+	return err
 }
 
 // Execute calls the FindVersionById function to find the version by its ID, calls FindBuildById for each
@@ -117,13 +256,15 @@ func (h *buildsForVersionHandler) Run(ctx context.Context) gimlet.Responder {
 
 		buildModels = append(buildModels, buildModel)
 	}
-	return gimlet.NewJSONResponse(buildModels)
+	return projectResponse(gimlet.NewJSONResponse(buildModels), h.fields)
 }
 
-// versionAbortHandler is a RequestHandler for aborting all tasks of a version.
+// versionAbortHandler is a RequestHandler for aborting the tasks of a version
+// that pass an optional filter.
 type versionAbortHandler struct {
 	versionId string
 	userId    string
+	filter    dbModel.VersionTaskFilter
 	sc        data.Connector
 }
 
@@ -152,12 +293,15 @@ func (h *versionAbortHandler) Parse(ctx context.Context, r *http.Request) error
 		h.userId = u.Username()
 	}
 
-	return nil
+	var err error
+	h.filter, err = parseVersionTaskFilter(r)
+	return err
 }
 
-// Execute calls the data AbortVersion function to abort all tasks of a version.
+// Execute calls the data AbortVersion function to abort the tasks of a
+// version that pass the filter.
 func (h *versionAbortHandler) Run(ctx context.Context) gimlet.Responder {
-	if err := h.sc.AbortVersion(h.versionId, h.userId); err != nil {
+	if err := h.sc.AbortVersion(h.versionId, h.filter, h.userId); err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error in aborting version"))
 	}
 
@@ -174,10 +318,11 @@ func (h *versionAbortHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(versionModel)
 }
 
-// versionRestartHandler is a RequestHandler for restarting all completed tasks
-// of a version.
+// versionRestartHandler is a RequestHandler for restarting the completed
+// tasks of a version that pass an optional filter.
 type versionRestartHandler struct {
 	versionId string
+	filter    dbModel.VersionTaskFilter
 	sc        data.Connector
 }
 
@@ -192,7 +337,8 @@ func (h *versionRestartHandler) Factory() gimlet.RouteHandler {
 	return &versionRestartHandler{sc: h.sc}
 }
 
-// ParseAndValidate fetches the versionId from the http request.
+// ParseAndValidate fetches the versionId and the task filter from the http
+// request.
 func (h *versionRestartHandler) Parse(ctx context.Context, r *http.Request) error {
 	h.versionId = gimlet.GetVars(r)["version_id"]
 
@@ -200,13 +346,16 @@ func (h *versionRestartHandler) Parse(ctx context.Context, r *http.Request) erro
 		return errors.New("request data incomplete")
 	}
 
-	return nil
+	var err error
+	h.filter, err = parseVersionTaskFilter(r)
+	return err
 }
 
-// Execute calls the data RestartVersion function to restart completed tasks of a version.
+// Execute calls the data RestartVersion function to restart the completed
+// tasks of a version that pass the filter.
 func (h *versionRestartHandler) Run(ctx context.Context) gimlet.Responder {
 	// Restart the version
-	err := h.sc.RestartVersion(h.versionId, MustHaveUser(ctx).Id)
+	err := h.sc.RestartVersion(h.versionId, h.filter, MustHaveUser(ctx).Id)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Database error in restarting version"))
 	}
@@ -225,3 +374,36 @@ func (h *versionRestartHandler) Run(ctx context.Context) gimlet.Responder {
 
 	return gimlet.NewJSONResponse(versionModel)
 }
+
+// parseVersionTaskFilter reads the filter selecting the tasks of a version to
+// restart or abort from the request body, which is optional. Without a
+// filter, every task of the version is selected.
+func parseVersionTaskFilter(r *http.Request) (dbModel.VersionTaskFilter, error) {
+	if r.Body == nil {
+		return dbModel.VersionTaskFilter{}, nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return dbModel.VersionTaskFilter{}, errors.Wrap(err, "problem reading request body")
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return dbModel.VersionTaskFilter{}, nil
+	}
+
+	apiFilter := model.APIVersionTaskFilter{}
+	if err = json.Unmarshal(body, &apiFilter); err != nil {
+		return dbModel.VersionTaskFilter{}, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    errors.Wrap(err, "problem parsing request body").Error(),
+		}
+	}
+	filter, err := apiFilter.ToService()
+	if err != nil {
+		return dbModel.VersionTaskFilter{}, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return filter.(dbModel.VersionTaskFilter), nil
+}
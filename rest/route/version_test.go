@@ -1,19 +1,24 @@
 package route
 
 import (
+	"bytes"
 	"context"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	dbModel "github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/manifest"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -103,10 +108,23 @@ func (s *VersionSuite) SetupSuite() {
 			{Version: versionId, Aborted: false, Status: evergreen.TaskStarted},
 			{Version: versionId, Aborted: false, Status: evergreen.TaskDispatched},
 		},
+		CachedManifests: []manifest.Manifest{
+			{
+				Id:          versionId,
+				Revision:    revision,
+				ProjectName: project,
+				Branch:      branch,
+				Modules: map[string]*manifest.Module{
+					"wt":         {Branch: "develop", Repo: "wiredtiger", Owner: "wiredtiger", Revision: "abc", URL: "url-wt"},
+					"enterprise": {Branch: "master", Repo: "enterprise", Owner: "mongodb", Revision: "def", URL: "url-enterprise"},
+				},
+			},
+		},
 		CachedRestartedVersions: make(map[string]string),
 	}
 	s.buildData = data.MockBuildConnector{
-		CachedBuilds: []build.Build{testBuild1, testBuild2},
+		CachedBuilds:   []build.Build{testBuild1, testBuild2},
+		CachedProjects: map[string]*dbModel.ProjectRef{project: {Identifier: project}},
 	}
 	s.sc = &data.MockConnector{
 		MockVersionConnector: s.versionData,
@@ -137,6 +155,58 @@ func (s *VersionSuite) TestFindByVersionId() {
 	s.Equal(model.ToAPIString(project), h.Project)
 }
 
+// TestFindManifestByVersionId tests the route for finding the module revisions
+// pinned for a version.
+func (s *VersionSuite) TestFindManifestByVersionId() {
+	handler := &versionManifestHandler{versionId: "versionId", sc: s.sc}
+	res := handler.Run(context.TODO())
+	s.NotNil(res)
+	s.Equal(http.StatusOK, res.Status())
+
+	h, ok := res.Data().(*model.APIManifest)
+	s.True(ok)
+	s.Equal(model.ToAPIString(versionId), h.Id)
+	s.Equal(model.ToAPIString(revision), h.Revision)
+	s.Equal(model.ToAPIString(project), h.Project)
+	s.Equal(model.ToAPIString(branch), h.Branch)
+	s.Require().Len(h.Modules, 2)
+	s.Equal(model.ToAPIString("enterprise"), h.Modules[0].Name)
+	s.Equal(model.ToAPIString("def"), h.Modules[0].Revision)
+	s.Equal(model.ToAPIString("wt"), h.Modules[1].Name)
+	s.Equal(model.ToAPIString("wiredtiger"), h.Modules[1].Owner)
+	s.Equal(model.ToAPIString("abc"), h.Modules[1].Revision)
+
+	handler = &versionManifestHandler{versionId: "unknown", sc: s.sc}
+	res = handler.Run(context.TODO())
+	s.Equal(http.StatusNotFound, res.Status())
+}
+
+// TestFindPrivateVersion tests that the versions of private projects are
+// only found for the users who can view the project.
+func (s *VersionSuite) TestFindPrivateVersion() {
+	sc := &data.MockConnector{
+		MockVersionConnector: s.versionData,
+		MockBuildConnector: data.MockBuildConnector{
+			CachedProjects: map[string]*dbModel.ProjectRef{
+				project: {Identifier: project, Private: true, Contributors: []string{"contributor"}, Viewers: []string{"viewer"}},
+			},
+		},
+	}
+	anonymous := context.Background()
+	viewer := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "viewer"})
+	other := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "other"})
+
+	for _, h := range []gimlet.RouteHandler{
+		&versionHandler{versionId: versionId, sc: sc},
+		&versionManifestHandler{versionId: versionId, sc: sc},
+		&projectVersionsGetHandler{project: project, limit: defaultLimit, sc: sc},
+	} {
+		s.Equal(http.StatusUnauthorized, h.Run(anonymous).Status())
+		s.Equal(http.StatusUnauthorized, h.Run(other).Status())
+		s.Equal(http.StatusOK, h.Run(viewer).Status())
+	}
+}
+
 // TestFindAllBuildsForVersion tests the route for finding all builds for a version.
 func (s *VersionSuite) TestFindAllBuildsForVersion() {
 	handler := &buildsForVersionHandler{versionId: "versionId", sc: s.sc}
@@ -201,3 +271,82 @@ func (s *VersionSuite) TestRestartVersion() {
 	s.Equal(model.ToAPIString(versionId), h.Id)
 	s.Equal("caller1", s.versionData.CachedRestartedVersions["versionId"])
 }
+
+// TestRestartVersionWithFilter tests restarting the tasks of a version that
+// pass a filter.
+func (s *VersionSuite) TestRestartVersionWithFilter() {
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "caller2"})
+
+	body := `{"statuses": ["failed"], "variant_regex": "^ubuntu", "task_names": ["compile", "lint"]}`
+	r, err := http.NewRequest(http.MethodPost, "/versions/versionId/restart", bytes.NewBufferString(body))
+	s.Require().NoError(err)
+	filter, err := parseVersionTaskFilter(r)
+	s.Require().NoError(err)
+
+	handler := &versionRestartHandler{versionId: versionId, filter: filter, sc: s.sc}
+	res := handler.Run(ctx)
+	s.Equal(http.StatusOK, res.Status())
+	s.Equal(dbModel.VersionTaskFilter{
+		Statuses:     []string{evergreen.TaskFailed},
+		VariantRegex: "^ubuntu",
+		TaskNames:    []string{"compile", "lint"},
+	}, s.sc.MockVersionConnector.CachedRestartFilters[versionId])
+
+	// the filter is optional
+	r, err = http.NewRequest(http.MethodPost, "/versions/versionId/restart", nil)
+	s.Require().NoError(err)
+	filter, err = parseVersionTaskFilter(r)
+	s.NoError(err)
+	s.True(filter.IsEmpty())
+
+	for _, body := range []string{`{"variant_regex": "(ubuntu"}`, `{"statuses": "failed"}`} {
+		r, err = http.NewRequest(http.MethodPost, "/versions/versionId/restart", bytes.NewBufferString(body))
+		s.Require().NoError(err)
+		_, err = parseVersionTaskFilter(r)
+		s.Error(err, body)
+	}
+}
+
+func TestChangeVersionPriority(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := gimlet.AttachUser(context.Background(), &user.DBUser{Id: "user1"})
+	sc := &data.MockConnector{
+		MockVersionConnector: data.MockVersionConnector{
+			CachedVersions: []version.Version{{Id: "v1"}, {Id: "v2"}},
+			CachedTasks:    []task.Task{{Id: "t1", Version: "v1"}, {Id: "t2", Version: "v2"}},
+		},
+	}
+	sc.SetSuperUsers([]string{"admin"})
+
+	request := func(body string) *http.Request {
+		r, err := http.NewRequest(http.MethodPatch, "/versions/v1", bytes.NewBufferString(body))
+		require.NoError(err)
+		return r
+	}
+
+	rh := makeChangeVersionPriority(sc).(*versionChangePriorityHandler)
+	assert.Error(rh.Parse(ctx, request(`{}`)))
+
+	rh = makeChangeVersionPriority(sc).(*versionChangePriorityHandler)
+	require.NoError(rh.Parse(ctx, request(`{"priority": 50}`)))
+	rh.versionId = "v1"
+	resp := rh.Run(ctx)
+	require.Equal(http.StatusOK, resp.Status())
+	apiVersion, ok := resp.Data().(*model.APIVersion)
+	require.True(ok)
+	assert.Equal(int64(50), apiVersion.Priority)
+	assert.Equal(int64(50), sc.MockVersionConnector.CachedVersions[0].Priority)
+	assert.Equal(int64(50), sc.MockVersionConnector.CachedTasks[0].Priority)
+	assert.Equal(int64(0), sc.MockVersionConnector.CachedTasks[1].Priority)
+
+	rh = makeChangeVersionPriority(sc).(*versionChangePriorityHandler)
+	require.NoError(rh.Parse(ctx, request(`{"priority": 1000}`)))
+	rh.versionId = "v1"
+	assert.Equal(http.StatusForbidden, rh.Run(ctx).Status())
+
+	rh = makeChangeVersionPriority(sc).(*versionChangePriorityHandler)
+	require.NoError(rh.Parse(ctx, request(`{"priority": 1}`)))
+	rh.versionId = "v3"
+	assert.Equal(http.StatusNotFound, rh.Run(ctx).Status())
+}
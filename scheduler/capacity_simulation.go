@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/pkg/errors"
+)
+
+// simulatedTaskDuration is the runtime assumed for queued tasks that have no
+// expected duration, matching the default for tasks without history.
+const simulatedTaskDuration = 10 * time.Minute
+
+// CapacityEstimate is the outcome of simulating a distro's task queue on a
+// pool of hosts. Wait times are measured from the time of the simulation
+// until each queued task starts. Stalled is set if the pool has no hosts to
+// run the queued tasks on.
+type CapacityEstimate struct {
+	PoolSize    int
+	NumQueued   int
+	NumRunning  int
+	Makespan    time.Duration
+	AverageWait time.Duration
+	MaxWait     time.Duration
+	Stalled     bool
+}
+
+// SimulateCapacity estimates how long the queue takes to drain on poolSize
+// hosts, given the time left on the tasks already running. Queued tasks are
+// dispatched in order to whichever host frees up first and run for their
+// expected durations. Host startup time, task groups, and dependencies
+// between queued tasks are not taken into account.
+func SimulateCapacity(queue []model.TaskQueueItem, running []time.Duration, poolSize int) CapacityEstimate {
+	estimate := CapacityEstimate{
+		PoolSize:   poolSize,
+		NumQueued:  len(queue),
+		NumRunning: len(running),
+	}
+
+	busy := make([]time.Duration, 0, len(running))
+	for _, left := range running {
+		if left < 0 {
+			left = 0
+		}
+		busy = append(busy, left)
+		if left > estimate.Makespan {
+			estimate.Makespan = left
+		}
+	}
+	if poolSize <= 0 {
+		estimate.Stalled = len(queue) > 0
+		return estimate
+	}
+
+	// The hosts that free up first take on the queued tasks. If the pool
+	// is smaller than the number of running tasks, the rest of the hosts
+	// finish their tasks and go away.
+	sort.Slice(busy, func(i, j int) bool { return busy[i] < busy[j] })
+	hosts := make([]time.Duration, poolSize)
+	for i := 0; i < poolSize && i < len(busy); i++ {
+		hosts[i] = busy[i]
+	}
+
+	var totalWait time.Duration
+	for _, item := range queue {
+		next := 0
+		for i := range hosts {
+			if hosts[i] < hosts[next] {
+				next = i
+			}
+		}
+		start := hosts[next]
+		duration := item.ExpectedDuration
+		if duration <= 0 {
+			duration = simulatedTaskDuration
+		}
+		hosts[next] = start + duration
+
+		totalWait += start
+		if start > estimate.MaxWait {
+			estimate.MaxWait = start
+		}
+		if hosts[next] > estimate.Makespan {
+			estimate.Makespan = hosts[next]
+		}
+	}
+	if len(queue) > 0 {
+		estimate.AverageWait = totalWait / time.Duration(len(queue))
+	}
+
+	return estimate
+}
+
+// SimulateDistroCapacity simulates the distro's current task queue and
+// running tasks on pools of each of the given sizes, using the runtimes of
+// the tasks predicted from their historical runtimes.
+func SimulateDistroCapacity(distroID string, poolSizes ...int) ([]CapacityEstimate, error) {
+	queue, err := model.LoadTaskQueue(distroID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem loading task queue for distro '%s'", distroID)
+	}
+	var items []model.TaskQueueItem
+	if queue != nil {
+		items = queue.Queue
+	}
+
+	hosts, err := host.AllRunningHosts(distroID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding hosts for distro '%s'", distroID)
+	}
+	runningTaskIds := []string{}
+	for _, h := range hosts {
+		if h.RunningTask != "" {
+			runningTaskIds = append(runningTaskIds, h.RunningTask)
+		}
+	}
+	running := []time.Duration{}
+	if len(runningTaskIds) > 0 {
+		runningTasks, err := task.Find(task.ByIds(runningTaskIds))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding running tasks for distro '%s'", distroID)
+		}
+		durations := predictDurations(runningTasks)
+		for _, t := range runningTasks {
+			running = append(running, durations[t.Id]-time.Since(t.StartTime))
+		}
+	}
+
+	estimates := make([]CapacityEstimate, 0, len(poolSizes))
+	for _, size := range poolSizes {
+		estimates = append(estimates, SimulateCapacity(items, running, size))
+	}
+	return estimates, nil
+}
@@ -0,0 +1,46 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimulateCapacity(t *testing.T) {
+	assert := assert.New(t)
+
+	queue := []model.TaskQueueItem{
+		{Id: "t1", ExpectedDuration: 30 * time.Minute},
+		{Id: "t2", ExpectedDuration: 20 * time.Minute},
+		{Id: "t3", ExpectedDuration: 10 * time.Minute},
+		{Id: "t4"},
+	}
+	running := []time.Duration{5 * time.Minute, -time.Minute}
+
+	// t1 and t2 start on the hosts as they free up, t3 follows t2, and t4
+	// runs for the default duration after t1
+	estimate := SimulateCapacity(queue, running, 2)
+	assert.Equal(2, estimate.PoolSize)
+	assert.Equal(4, estimate.NumQueued)
+	assert.Equal(2, estimate.NumRunning)
+	assert.Equal(40*time.Minute, estimate.Makespan)
+	assert.Equal(30*time.Minute, estimate.MaxWait)
+	assert.Equal(15*time.Minute, estimate.AverageWait)
+
+	// more hosts start the tasks sooner
+	estimate = SimulateCapacity(queue, running, 4)
+	assert.Equal(30*time.Minute, estimate.Makespan)
+	assert.Equal(5*time.Minute, estimate.MaxWait)
+	assert.Equal(5*time.Minute/4, estimate.AverageWait)
+
+	// running tasks finish even when the pool shrinks below them
+	estimate = SimulateCapacity(queue[:1], []time.Duration{time.Minute, time.Hour}, 1)
+	assert.Equal(time.Hour, estimate.Makespan)
+	assert.Equal(time.Minute, estimate.MaxWait)
+
+	// an empty pool never drains the queue
+	assert.True(SimulateCapacity(queue, nil, 0).Stalled)
+	assert.False(SimulateCapacity(nil, nil, 0).Stalled)
+}
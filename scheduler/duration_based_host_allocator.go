@@ -173,13 +173,14 @@ func computeRunningTasksDuration(existingDistroHosts []host.Host) (runningTasksD
 	}
 
 	// compute the total time to completion for running tasks
+	durations := predictDurations(runningTasks)
 	for _, runningTaskId := range runningTaskIds {
 		runningTask, ok := runningTasksMap[runningTaskId]
 		if !ok {
 			return runningTasksDuration, errors.Errorf(
 				"Unable to find running task with _id %v", runningTaskId)
 		}
-		expectedDuration := runningTask.FetchExpectedDuration()
+		expectedDuration := durations[runningTask.Id]
 		elapsedTime := time.Since(runningTask.StartTime)
 		if elapsedTime > expectedDuration {
 			// probably an outlier; or an unknown data point
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/quota"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// shareTasks reorders a distro's prioritized tasks so that the projects with
+// tasks for the distro share it according to their quotas, and records how
+// each project is using the distro.
+func shareTasks(distroId string, tasks []task.Task) ([]task.Task, error) {
+	quotas, err := quota.FindAll()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	quotasByProject := make(map[string]quota.ProjectQuota, len(quotas))
+	for _, q := range quotas {
+		quotasByProject[q.Project] = q
+	}
+
+	hosts, err := host.AllRunningHosts(distroId)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding hosts for distro '%s'", distroId)
+	}
+	runningTaskIds := []string{}
+	for _, h := range hosts {
+		if h.RunningTask != "" {
+			runningTaskIds = append(runningTaskIds, h.RunningTask)
+		}
+	}
+	running := map[string]int{}
+	if len(runningTaskIds) > 0 {
+		runningTasks, err := task.Find(task.ByIds(runningTaskIds).WithFields(task.ProjectKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding running tasks for distro '%s'", distroId)
+		}
+		for _, t := range runningTasks {
+			running[t.Project]++
+		}
+	}
+
+	shared, usage := fairShareOrder(tasks, running, quotasByProject)
+
+	now := time.Now()
+	for i := range usage {
+		usage[i].LastUpdate = now
+		grip.Info(message.Fields{
+			"runner":   RunnerName,
+			"distro":   distroId,
+			"stat":     "project-fair-share",
+			"project":  usage[i].Id.Project,
+			"share":    usage[i].Share,
+			"running":  usage[i].Running,
+			"queued":   usage[i].Queued,
+			"deferred": usage[i].Deferred,
+		})
+	}
+	if err = quota.RecordUsage(distroId, usage); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return shared, nil
+}
+
+// fairShareOrder interleaves the projects' tasks so that each project gets
+// to run a number of tasks proportional to its weight, counting the tasks
+// it already has running. The order of each project's own tasks is kept,
+// high priority tasks stay at the front of the queue, and consecutive tasks
+// of a task group stay together. Once a project has MaxRunningTasks tasks
+// running or ahead in the queue, the rest of its tasks go to the back.
+func fairShareOrder(tasks []task.Task, running map[string]int, quotas map[string]quota.ProjectQuota) ([]task.Task, []quota.Usage) {
+	ordered := make([]task.Task, 0, len(tasks))
+	idx := 0
+	for ; idx < len(tasks) && tasks[idx].Priority > evergreen.MaxTaskPriority; idx++ {
+		ordered = append(ordered, tasks[idx])
+	}
+
+	projects := []string{}
+	units := map[string][][]task.Task{}
+	lastGroup := ""
+	for _, t := range tasks[idx:] {
+		if _, ok := units[t.Project]; !ok {
+			projects = append(projects, t.Project)
+		}
+		group := ""
+		if t.TaskGroup != "" {
+			group = fmt.Sprintf("%s-%s-%s-%s", t.TaskGroup, t.BuildVariant, t.Project, t.Version)
+		}
+		projectUnits := units[t.Project]
+		if group != "" && group == lastGroup && len(projectUnits) > 0 {
+			projectUnits[len(projectUnits)-1] = append(projectUnits[len(projectUnits)-1], t)
+		} else {
+			projectUnits = append(projectUnits, []task.Task{t})
+		}
+		units[t.Project] = projectUnits
+		lastGroup = group
+	}
+
+	weight := func(project string) float64 {
+		if q, ok := quotas[project]; ok && q.Weight > 0 {
+			return q.Weight
+		}
+		return quota.DefaultWeight
+	}
+	overQuota := func(project string, assigned int) bool {
+		q, ok := quotas[project]
+		return ok && q.MaxRunningTasks > 0 && assigned >= q.MaxRunningTasks
+	}
+
+	assigned := map[string]int{}
+	for _, p := range projects {
+		assigned[p] = running[p]
+	}
+	deferred := map[string]int{}
+	next := map[string]int{}
+
+	// pick the project furthest below its share each time, first among
+	// the projects under their quotas and then among the rest
+	for _, ignoreQuota := range []bool{false, true} {
+		for {
+			best := ""
+			for _, p := range projects {
+				if next[p] >= len(units[p]) || (!ignoreQuota && overQuota(p, assigned[p])) {
+					continue
+				}
+				if best == "" || float64(assigned[p])/weight(p) < float64(assigned[best])/weight(best) {
+					best = p
+				}
+			}
+			if best == "" {
+				break
+			}
+			unit := units[best][next[best]]
+			next[best]++
+			ordered = append(ordered, unit...)
+			assigned[best] += len(unit)
+			if ignoreQuota {
+				deferred[best] += len(unit)
+			}
+		}
+	}
+
+	// projects that only have running tasks share the distro too
+	idle := []string{}
+	for p := range running {
+		if _, ok := units[p]; !ok {
+			idle = append(idle, p)
+		}
+	}
+	sort.Strings(idle)
+	projects = append(projects, idle...)
+
+	var totalWeight float64
+	for _, p := range projects {
+		totalWeight += weight(p)
+	}
+	usage := make([]quota.Usage, 0, len(projects))
+	for _, p := range projects {
+		queued := 0
+		for _, unit := range units[p] {
+			queued += len(unit)
+		}
+		usage = append(usage, quota.Usage{
+			Id:       quota.UsageKey{Project: p},
+			Share:    weight(p) / totalWeight,
+			Running:  running[p],
+			Queued:   queued,
+			Deferred: deferred[p],
+		})
+	}
+
+	return ordered, usage
+}
@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/quota"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func taskIds(tasks []task.Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.Id)
+	}
+	return ids
+}
+
+func TestFairShareOrder(t *testing.T) {
+	assert := assert.New(t)
+
+	tasks := []task.Task{
+		{Id: "urgent", Project: "big", Priority: 101},
+		{Id: "big1", Project: "big"},
+		{Id: "big2", Project: "big"},
+		{Id: "big3", Project: "big"},
+		{Id: "big4", Project: "big"},
+		{Id: "small1", Project: "small"},
+		{Id: "small2", Project: "small"},
+	}
+
+	// projects take turns, starting with the one with fewer running tasks
+	ordered, usage := fairShareOrder(tasks, map[string]int{"big": 1}, nil)
+	assert.Equal([]string{"urgent", "small1", "big1", "small2", "big2", "big3", "big4"}, taskIds(ordered))
+	if assert.Len(usage, 2) {
+		assert.Equal(quota.Usage{Id: quota.UsageKey{Project: "big"}, Share: 0.5, Running: 1, Queued: 4}, usage[0])
+		assert.Equal(quota.Usage{Id: quota.UsageKey{Project: "small"}, Share: 0.5, Queued: 2}, usage[1])
+	}
+
+	// weights give projects more turns
+	ordered, _ = fairShareOrder(tasks, nil, map[string]quota.ProjectQuota{
+		"big": {Project: "big", Weight: 2},
+	})
+	assert.Equal([]string{"urgent", "big1", "small1", "big2", "big3", "small2", "big4"}, taskIds(ordered))
+
+	// projects over their quota go to the back
+	ordered, usage = fairShareOrder(tasks, map[string]int{"big": 1, "other": 2}, map[string]quota.ProjectQuota{
+		"big": {Project: "big", Weight: 1, MaxRunningTasks: 2},
+	})
+	assert.Equal([]string{"urgent", "small1", "big1", "small2", "big2", "big3", "big4"}, taskIds(ordered))
+	if assert.Len(usage, 3) {
+		assert.Equal(3, usage[0].Deferred)
+		assert.Equal(0, usage[1].Deferred)
+		assert.Equal("other", usage[2].Id.Project)
+		assert.InDelta(1.0/3, usage[2].Share, 0.001)
+	}
+
+	// task groups stay together
+	grouped := []task.Task{
+		{Id: "a1", Project: "a", TaskGroup: "tg", BuildVariant: "bv", Version: "v"},
+		{Id: "a2", Project: "a", TaskGroup: "tg", BuildVariant: "bv", Version: "v"},
+		{Id: "b1", Project: "b"},
+		{Id: "a3", Project: "a"},
+	}
+	ordered, _ = fairShareOrder(grouped, nil, nil)
+	assert.Equal([]string{"a1", "a2", "b1", "a3"}, taskIds(ordered))
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/taskruntime"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+// predictionPercentile is the percentile of a task's recent runtimes that
+// the scheduler expects it to take, which unlike the average isn't thrown
+// off by the occasional hung or unusually quick run.
+const predictionPercentile = 50
+
+// predictDurations returns how long each of the tasks is expected to take,
+// keyed by task ID. Tasks that have run often enough are predicted from
+// their recent runtimes, and the rest fall back to their expected duration.
+func predictDurations(tasks []task.Task) map[string]time.Duration {
+	keys := []taskruntime.TaskRuntimeKey{}
+	seen := map[taskruntime.TaskRuntimeKey]bool{}
+	for _, t := range tasks {
+		key := taskruntime.TaskRuntimeKey{Project: t.Project, Variant: t.BuildVariant, Task: t.DisplayName}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	runtimes, err := taskruntime.FindByKeys(keys)
+	grip.Warning(message.WrapError(err, message.Fields{
+		"runner":    RunnerName,
+		"operation": "predicting task runtimes",
+		"message":   "falling back to expected durations",
+	}))
+
+	durations := make(map[string]time.Duration, len(tasks))
+	for i := range tasks {
+		t := &tasks[i]
+		r, ok := runtimes[taskruntime.TaskRuntimeKey{Project: t.Project, Variant: t.BuildVariant, Task: t.DisplayName}]
+		if ok && r.Reliable() {
+			durations[t.Id] = r.Percentile(predictionPercentile)
+			continue
+		}
+		durations[t.Id] = t.FetchExpectedDuration()
+	}
+	return durations
+}
@@ -0,0 +1,35 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/taskruntime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPredictDurations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.Clear(taskruntime.Collection))
+	defer func() {
+		assert.NoError(db.Clear(taskruntime.Collection))
+	}()
+
+	for _, runtime := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 4 * time.Minute, time.Hour} {
+		require.NoError(taskruntime.Record("p", "bv", "compile", runtime))
+	}
+	require.NoError(taskruntime.Record("p", "bv", "lint", time.Hour))
+
+	durations := predictDurations([]task.Task{
+		{Id: "t1", Project: "p", BuildVariant: "bv", DisplayName: "compile"},
+		{Id: "t2", Project: "p", BuildVariant: "bv", DisplayName: "lint", ExpectedDuration: 5 * time.Minute},
+	})
+	// one slow run doesn't throw off the prediction
+	assert.Equal(3*time.Minute, durations["t1"])
+	// too few runtimes fall back to the expected duration
+	assert.Equal(5*time.Minute, durations["t2"])
+}
@@ -74,6 +74,12 @@ func (s *distroSchedueler) scheduleDistro(distroId string, runnableTasksForDistr
 		return res
 	}
 
+	prioritizedTasks, err = shareTasks(distroId, prioritizedTasks)
+	if err != nil {
+		res.err = errors.Wrap(err, "Error sharing tasks between projects")
+		return res
+	}
+
 	// persist the queue of tasks
 	grip.Debug(message.Fields{
 		"runner":    RunnerName,
@@ -159,8 +165,11 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 	// if distro is container distro, check if there are enough parent hosts to
 	// support new containers
 	if pool != nil {
+		// containers of the distro only run on parents of the matching OS
+		parentDistroId := pool.ParentDistro(d.IsWindows())
+
 		// find all running parents with the specified container pool
-		currentParents, err := host.FindAllRunningParentsByContainerPool(pool.Id)
+		currentParents, err := findRunningParents(d, pool)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not find running parents")
 		}
@@ -172,7 +181,7 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 		}
 
 		// find all uphost parent intent documents
-		numUphostParents, err := host.CountUphostParentsByContainerPool(pool.Id)
+		numUphostParents, err := countUphostParents(d, pool)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not count uphost parents")
 		}
@@ -188,7 +197,7 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 		numNewParents := numNewParentsNeeded(parentsParams)
 
 		// get parent distro from pool
-		parentDistro, err := distro.FindOne(distro.ById(pool.Distro))
+		parentDistro, err := distro.FindOne(distro.ById(parentDistroId))
 		if err != nil {
 			return nil, errors.Wrap(err, "error find parent distro")
 		}
@@ -206,7 +215,7 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 				"runner":          RunnerName,
 				"distro":          d.Id,
 				"pool":            pool.Id,
-				"pool_distro":     pool.Distro,
+				"pool_distro":     parentDistroId,
 				"num_new_parents": numNewParentsToSpawn,
 				"operation":       "spawning new parents",
 				"duration_secs":   time.Since(distroStartTime).Seconds(),
@@ -219,7 +228,7 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 
 	// create intent documents for container hosts
 	if d.ContainerPool != "" {
-		containerIntents, err := generateContainerHostIntents(d, newHostsNeeded)
+		containerIntents, err := generateContainerHostIntents(d, newHostsNeeded, pool)
 		if err != nil {
 			return nil, errors.Wrap(err, "error generating container intent hosts")
 		}
@@ -252,8 +261,8 @@ func spawnHosts(ctx context.Context, d distro.Distro, newHostsNeeded int, pool *
 // generateContainerHostIntents generates container intent documents by going
 // through available parents and packing on the parents with longest expected
 // finish time
-func generateContainerHostIntents(d distro.Distro, newContainersNeeded int) ([]host.Host, error) {
-	parents, err := getNumContainersOnParents(d)
+func generateContainerHostIntents(d distro.Distro, newContainersNeeded int, pool *evergreen.ContainerPool) ([]host.Host, error) {
+	parents, err := getNumContainersOnParents(d, pool)
 	if err != nil {
 		err = errors.Wrap(err, "Could not find number of containers on each parent")
 		return nil, err
@@ -294,8 +303,8 @@ func generateParentHostOptions(pool *evergreen.ContainerPool) cloud.HostOptions
 // getNumContainersOnParents returns a slice of parents and their respective
 // number of current containers currently running in order of longest expected
 // finish time
-func getNumContainersOnParents(d distro.Distro) ([]containersOnParents, error) {
-	allParents, err := host.FindAllRunningParentsByContainerPool(d.ContainerPool)
+func getNumContainersOnParents(d distro.Distro, pool *evergreen.ContainerPool) ([]containersOnParents, error) {
+	allParents, err := findRunningParents(d, pool)
 	if err != nil {
 		return nil, errors.Wrap(err, "Could not find running parent hosts")
 	}
@@ -319,6 +328,28 @@ func getNumContainersOnParents(d distro.Distro) ([]containersOnParents, error) {
 	return numContainersOnParents, nil
 }
 
+// findRunningParents returns the running parents of the distro's container
+// pool that can run its containers. In a pool that mixes Windows and Linux
+// parents, these are the parents of the distro's OS.
+func findRunningParents(d distro.Distro, pool *evergreen.ContainerPool) ([]host.Host, error) {
+	if pool == nil {
+		return host.FindAllRunningParentsByContainerPool(d.ContainerPool)
+	}
+	if pool.IsMixed() {
+		return host.FindAllRunningParentsByContainerPoolDistro(pool.Id, pool.ParentDistro(d.IsWindows()))
+	}
+	return host.FindAllRunningParentsByContainerPool(pool.Id)
+}
+
+// countUphostParents returns the number of initializing parents of the pool
+// that can run containers of the distro.
+func countUphostParents(d distro.Distro, pool *evergreen.ContainerPool) (int, error) {
+	if pool.IsMixed() {
+		return host.CountUphostParentsByContainerPoolDistro(pool.Id, pool.ParentDistro(d.IsWindows()))
+	}
+	return host.CountUphostParentsByContainerPool(pool.Id)
+}
+
 // numNewParentsNeeded returns the number of additional parents needed to
 // accommodate new containers
 func numNewParentsNeeded(params newParentsNeededParams) int {
@@ -484,7 +484,7 @@ func (s *SchedulerSuite) TestFindAvailableParent() {
 	s.NoError(task1.Insert())
 	s.NoError(task2.Insert())
 
-	availableParent, err := getNumContainersOnParents(d)
+	availableParent, err := getNumContainersOnParents(d, nil)
 	s.NoError(err)
 
 	s.Equal(2, len(availableParent))
@@ -548,7 +548,7 @@ func (s *SchedulerSuite) TestFindNoAvailableParent() {
 	s.NoError(task1.Insert())
 	s.NoError(task2.Insert())
 
-	availableParent, err := getNumContainersOnParents(d)
+	availableParent, err := getNumContainersOnParents(d, nil)
 	s.NoError(err)
 	s.Equal(0, len(availableParent))
 }
@@ -4,6 +4,7 @@ import (
 	"sync"
 
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/mongodb/grip"
@@ -352,3 +353,61 @@ func filterTasksWithVersionCache(tasks []task.Task) ([]task.Task, map[string]ver
 
 	return filteredTasks, versions, nil
 }
+
+// inheritPriorities resolves the effective priority of each task, which is its
+// own priority if set, and otherwise that of its build, then of its version,
+// then of its project. Tasks whose effective priority is negative are dropped,
+// since they are not meant to run.
+func inheritPriorities(tasks []task.Task, versions map[string]version.Version) ([]task.Task, error) {
+	ids := make(map[string]struct{})
+	for _, t := range tasks {
+		if t.Priority == 0 {
+			ids[t.BuildId] = struct{}{}
+		}
+	}
+	if len(ids) == 0 {
+		return tasks, nil
+	}
+
+	idlist := []string{}
+	for id := range ids {
+		idlist = append(idlist, id)
+	}
+	builds, err := build.Find(build.ByIds(idlist).WithFields(build.IdKey, build.PriorityKey))
+	if err != nil {
+		return nil, errors.Wrap(err, "problem resolving build priorities")
+	}
+	buildPriorities := make(map[string]int64)
+	for _, b := range builds {
+		buildPriorities[b.Id] = b.Priority
+	}
+
+	refs, err := model.FindProjectRefsWithPriority()
+	if err != nil {
+		return nil, errors.Wrap(err, "problem resolving project priorities")
+	}
+	projectPriorities := make(map[string]int64)
+	for _, ref := range refs {
+		projectPriorities[ref.Identifier] = ref.Priority
+	}
+
+	return applyInheritedPriorities(tasks, buildPriorities, versions, projectPriorities), nil
+}
+
+func applyInheritedPriorities(tasks []task.Task, builds map[string]int64, versions map[string]version.Version, projects map[string]int64) []task.Task {
+	resolved := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		for _, priority := range []int64{builds[t.BuildId], versions[t.Version].Priority, projects[t.Project]} {
+			if t.Priority != 0 {
+				break
+			}
+			t.Priority = priority
+		}
+		if t.Priority < 0 {
+			continue
+		}
+		resolved = append(resolved, t)
+	}
+
+	return resolved
+}
@@ -13,9 +13,11 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/smartystreets/goconvey/convey/reporting"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -567,3 +569,36 @@ func TestCompareTaskRunnersWithHugeTasks(t *testing.T) {
 	}
 	suite.Run(t, s)
 }
+
+func TestApplyInheritedPriorities(t *testing.T) {
+	assert := assert.New(t)
+
+	tasks := []task.Task{
+		{Id: "own", Priority: 5, BuildId: "b1", Version: "v1", Project: "p1"},
+		{Id: "build", BuildId: "b1", Version: "v1", Project: "p1"},
+		{Id: "version", BuildId: "b2", Version: "v1", Project: "p1"},
+		{Id: "project", BuildId: "b2", Version: "v2", Project: "p1"},
+		{Id: "none", BuildId: "b2", Version: "v2", Project: "p2"},
+		{Id: "blacklisted", BuildId: "b3", Version: "v1", Project: "p1"},
+	}
+	builds := map[string]int64{"b1": 10, "b3": -1}
+	versions := map[string]version.Version{
+		"v1": {Id: "v1", Priority: 20},
+		"v2": {Id: "v2"},
+	}
+	projects := map[string]int64{"p1": 30}
+
+	resolved := applyInheritedPriorities(tasks, builds, versions, projects)
+	priorities := map[string]int64{}
+	for _, t := range resolved {
+		priorities[t.Id] = t.Priority
+	}
+	assert.Equal(map[string]int64{
+		"own":     5,
+		"build":   10,
+		"version": 20,
+		"project": 30,
+		"none":    0,
+	}, priorities)
+	assert.Equal(int64(0), tasks[1].Priority)
+}
@@ -184,6 +184,18 @@ func byTaskGroupOrder(t1, t2 task.Task, comparator *CmpBasedTaskComparator) (int
 		return value, nil
 	}
 
+	// tasks created with their position in the group recorded don't need
+	// the project configuration
+	if t1.TaskGroupOrder > 0 && t2.TaskGroupOrder > 0 {
+		if t1.TaskGroupOrder < t2.TaskGroupOrder {
+			return 1, nil
+		}
+		if t1.TaskGroupOrder > t2.TaskGroupOrder {
+			return -1, nil
+		}
+		return 0, nil
+	}
+
 	// find earlier task
 	for _, tg := range comparator.projects[t1.Version].TaskGroups {
 		if tg.Name == t1.TaskGroup {
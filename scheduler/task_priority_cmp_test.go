@@ -354,6 +354,17 @@ task_groups:
 	result, err = byTaskGroupOrder(tasks[0], tasks[1], taskComparator)
 	assert.NoError(err)
 	assert.Equal(-1, result)
+
+	// the order recorded on the tasks takes precedence over the config
+	tasks[0].TaskGroupOrder = 1
+	tasks[1].TaskGroupOrder = 2
+	result, err = byTaskGroupOrder(tasks[0], tasks[1], taskComparator)
+	assert.NoError(err)
+	assert.Equal(1, result)
+	taskComparator.projects = nil
+	result, err = byTaskGroupOrder(tasks[1], tasks[0], taskComparator)
+	assert.NoError(err)
+	assert.Equal(-1, result)
 }
 
 func TestPrioritizeTasksWithSameTaskGroupsAndDifferentBuilds(t *testing.T) {
@@ -19,6 +19,7 @@ type DBTaskQueuePersister struct{}
 // PersistTaskQueue saves the task queue to the database.
 // Returns an error if the db call returns an error.
 func (self *DBTaskQueuePersister) PersistTaskQueue(distro string, tasks []task.Task) ([]model.TaskQueueItem, error) {
+	durations := predictDurations(tasks)
 	taskQueue := make([]model.TaskQueueItem, 0, len(tasks))
 	for _, t := range tasks {
 		taskQueue = append(taskQueue, model.TaskQueueItem{
@@ -29,10 +30,11 @@ func (self *DBTaskQueuePersister) PersistTaskQueue(distro string, tasks []task.T
 			Requester:           t.Requester,
 			Revision:            t.Revision,
 			Project:             t.Project,
-			ExpectedDuration:    t.FetchExpectedDuration(),
+			ExpectedDuration:    durations[t.Id],
 			Priority:            t.Priority,
 			Group:               t.TaskGroup,
 			GroupMaxHosts:       t.TaskGroupMaxHosts,
+			GroupIndex:          t.TaskGroupOrder,
 			Version:             t.Version,
 		})
 
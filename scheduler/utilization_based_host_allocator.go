@@ -148,7 +148,7 @@ func evalHostUtilization(ctx context.Context, d distro.Distro, taskQueue []model
 
 	maxDuration := MaxDurationPerDistroHost
 	if usesContainers {
-		parentDistro, err := distro.FindOne(distro.ById(containerPool.Distro))
+		parentDistro, err := distro.FindOne(distro.ById(containerPool.ParentDistro(d.IsWindows())))
 		if err != nil {
 			return 0, errors.Wrap(err, "error finding parent distro")
 		}
@@ -315,6 +315,7 @@ func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, max
 		return 0.0, err
 	}
 
+	durations := predictDurations(runningTasks)
 	nums := make(chan float64, len(runningTasks))
 	source := make(chan task.Task, len(runningTasks))
 	for _, t := range runningTasks {
@@ -330,7 +331,7 @@ func getSoonToBeFreeHosts(existingHosts []host.Host, freeHostFactor float64, max
 			defer recovery.LogStackTraceAndContinue("panic during free host calculation")
 			defer wg.Done()
 			for t := range source {
-				expectedDuration := t.FetchExpectedDuration()
+				expectedDuration := durations[t.Id]
 				elapsedTime := time.Since(t.StartTime)
 				timeLeft := expectedDuration - elapsedTime
 
@@ -59,6 +59,11 @@ func PlanDistro(ctx context.Context, conf Configuration, s *evergreen.Settings)
 		return errors.Wrap(err, "error getting runnable tasks")
 	}
 
+	runnableTasks, err = inheritPriorities(runnableTasks, versions)
+	if err != nil {
+		return errors.Wrap(err, "error resolving task priorities")
+	}
+
 	ds := &distroSchedueler{
 		TaskPrioritizer: &CmpBasedTaskPrioritizer{
 			runtimeID: schedulerInstance,
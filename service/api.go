@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
@@ -249,8 +250,31 @@ func (as *APIServer) AttachResults(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, "test results successfully attached")
 }
 
+// AttachTestReport converts the received JUnit XML or TAP report into test
+// results of the task, storing the output of each test as its log.
+func (as *APIServer) AttachTestReport(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+	report := &apimodels.TestReport{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), report); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if !util.StringSliceContains(model.ValidTestReportFormats, report.Format) {
+		as.LoggedError(w, r, http.StatusBadRequest, errors.Errorf("invalid test report format '%s'", report.Format))
+		return
+	}
+
+	results, err := model.IngestTestReport(t, report.Format, strings.NewReader(report.Content))
+	if err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	gimlet.WriteJSON(w, fmt.Sprintf("%d test results successfully attached", len(results)))
+}
+
 // FetchProjectVars is an API hook for returning the project variables
-// associated with a task's project.
+// associated with a task's project. The values of secret variables are
+// fetched from their secrets managers, and are private.
 func (as *APIServer) FetchProjectVars(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
 	projectVars, err := model.FindOneProjectVars(t.Project)
@@ -263,7 +287,19 @@ func (as *APIServer) FetchProjectVars(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	gimlet.WriteJSON(w, projectVars)
+	secrets := as.GetSettings().Secrets
+	backends, err := model.NewSecretsBackends(&secrets)
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	vars, private, err := projectVars.ResolveVars(r.Context(), backends, secrets.Prefix)
+	if err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrapf(err, "problem fetching variables of project '%s'", t.Project))
+		return
+	}
+
+	gimlet.WriteJSON(w, apimodels.ExpansionVars{Vars: vars, PrivateVars: private})
 }
 
 // AttachFiles updates file mappings for a task or build
@@ -286,6 +322,11 @@ func (as *APIServer) AttachFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err = checkManagedArtifacts(as.GetSettings().Artifacts, t, entry.Files); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
 	if err := entry.Upsert(); err != nil {
 		message := fmt.Sprintf("Error updating artifact file info for task %v: %v", t.Id, err)
 		grip.Error(message)
@@ -327,6 +368,33 @@ func (as *APIServer) AppendTaskLog(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, "Logs added")
 }
 
+// AppendStructuredTaskLog stores the structured log lines sent by the agent
+// running the task.
+func (as *APIServer) AppendStructuredTaskLog(w http.ResponseWriter, r *http.Request) {
+	if as.GetSettings().ServiceFlags.TaskLoggingDisabled {
+		http.Error(w, "task logging is disabled", http.StatusConflict)
+		return
+	}
+	t := MustHaveTask(r)
+	lines := []apimodels.StructuredLogLine{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &lines); err != nil {
+		http.Error(w, "unable to read log lines from request", http.StatusBadRequest)
+		return
+	}
+
+	logs, err := model.NewStructuredTaskLogs(t.Id, t.Execution, lines)
+	if err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err = model.InsertStructuredTaskLogs(logs); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	gimlet.WriteJSON(w, "Logs added")
+}
+
 // FetchTask loads the task from the database and sends it to the requester.
 func (as *APIServer) FetchTask(w http.ResponseWriter, r *http.Request) {
 	t := MustHaveTask(r)
@@ -350,6 +418,43 @@ func (as *APIServer) Heartbeat(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, heartbeatResponse)
 }
 
+// SetTaskCheckpoint records a checkpoint of a running task, which the task
+// resumes from if it's rescheduled after its host is reclaimed.
+func (as *APIServer) SetTaskCheckpoint(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+	in := &apimodels.TaskCheckpoint{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), in); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if len(in.Data) > task.MaxCheckpointSize {
+		as.LoggedError(w, r, http.StatusBadRequest,
+			errors.Errorf("checkpoint can't be larger than %d bytes", task.MaxCheckpointSize))
+		return
+	}
+	if t.Status != evergreen.TaskStarted {
+		as.LoggedError(w, r, http.StatusConflict,
+			errors.Errorf("task '%s' is %s, not running", t.Id, t.Status))
+		return
+	}
+
+	checkpoint := &task.Checkpoint{
+		Sequence:   1,
+		Data:       in.Data,
+		Execution:  t.Execution,
+		CreateTime: time.Now(),
+	}
+	if t.Checkpoint != nil {
+		checkpoint.Sequence = t.Checkpoint.Sequence + 1
+	}
+	if err := t.SetCheckpoint(checkpoint); err != nil {
+		as.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	gimlet.WriteJSON(w, checkpoint)
+}
+
 // TaskSystemInfo is the handler for the system info collector, which
 // reads grip/message.SystemInfo objects from the request body.
 func (as *APIServer) TaskSystemInfo(w http.ResponseWriter, r *http.Request) {
@@ -447,6 +552,7 @@ func (as *APIServer) validateProjectConfig(w http.ResponseWriter, r *http.Reques
 		return
 	}
 	semanticErrs := validator.CheckProjectSemantics(project)
+	semanticErrs = append(semanticErrs, validator.CheckProjectDeprecations(project)...)
 	if len(syntaxErrs)+len(semanticErrs) != 0 {
 		gimlet.WriteJSONError(w, append(syntaxErrs, semanticErrs...))
 		return
@@ -539,14 +645,18 @@ func (as *APIServer) GetServiceApp() *gimlet.APIApp {
 	app.Route().Version(2).Route("/task/{taskId}/end").Wrap(checkTaskSecret, checkHost).Handler(as.EndTask).Post()
 	app.Route().Version(2).Route("/task/{taskId}/start").Wrap(checkTaskSecret, checkHost).Handler(as.StartTask).Post()
 	app.Route().Version(2).Route("/task/{taskId}/log").Wrap(checkTaskSecret, checkHost).Handler(as.AppendTaskLog).Post()
+	app.Route().Version(2).Route("/task/{taskId}/structured_log").Wrap(checkTaskSecret, checkHost).Handler(as.AppendStructuredTaskLog).Post()
 	app.Route().Version(2).Route("/task/{taskId}/").Wrap(checkTaskSecret).Handler(as.FetchTask).Get()
 	app.Route().Version(2).Route("/task/{taskId}/fetch_vars").Wrap(checkTaskSecret).Handler(as.FetchProjectVars).Get()
+	app.Route().Version(2).Route("/task/{taskId}/checkpoint").Wrap(checkTaskSecret, checkHost).Handler(as.SetTaskCheckpoint).Post()
 	app.Route().Version(2).Route("/task/{taskId}/heartbeat").Wrap(checkTaskSecret, checkHost).Handler(as.Heartbeat).Post()
 	app.Route().Version(2).Route("/task/{taskId}/results").Wrap(checkTaskSecret, checkHost).Handler(as.AttachResults).Post()
+	app.Route().Version(2).Route("/task/{taskId}/test_report").Wrap(checkTaskSecret, checkHost).Handler(as.AttachTestReport).Post()
 	app.Route().Version(2).Route("/task/{taskId}/test_logs").Wrap(checkTaskSecret, checkHost).Handler(as.AttachTestLog).Post()
 	app.Route().Version(2).Route("/task/{taskId}/system_info").Wrap(checkTaskSecret, checkHost).Handler(as.TaskSystemInfo).Post()
 	app.Route().Version(2).Route("/task/{taskId}/process_info").Wrap(checkTaskSecret, checkHost).Handler(as.TaskProcessInfo).Post()
 	app.Route().Version(2).Route("/task/{taskId}/files").Wrap(checkTask, checkHost).Handler(as.AttachFiles).Post()
+	app.Route().Version(2).Route("/task/{taskId}/artifacts/upload_urls").Wrap(checkTaskSecret, checkHost).Handler(as.CreateArtifactUploads).Post()
 	app.Route().Version(2).Route("/task/{taskId}/distro").Wrap(checkTask).Handler(as.GetDistro).Get()
 	app.Route().Version(2).Route("/task/{taskId}/version").Wrap(checkTask).Handler(as.GetVersion).Get()
 	app.Route().Version(2).Route("/task/{taskId}/project_ref").Wrap(checkTask).Handler(as.GetProjectRef).Get()
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/goamz/goamz/aws"
+	"github.com/pkg/errors"
+)
+
+const defaultArtifactContentType = "application/octet-stream"
+
+// CreateArtifactUploads signs URLs that upload the requested files to the
+// task's directory in the artifacts bucket, so that agents upload artifacts
+// without AWS credentials of their own. The task, its execution, and the
+// file's SHA256 digest are stored with each file. Since the bucket isn't
+// public, the files are linked to through the UI, which signs a download
+// URL when they're viewed.
+func (as *APIServer) CreateArtifactUploads(w http.ResponseWriter, r *http.Request) {
+	t := MustHaveTask(r)
+	settings := as.GetSettings()
+	conf := settings.Artifacts
+	if conf.Bucket == "" {
+		as.LoggedError(w, r, http.StatusConflict, errors.New("no artifacts bucket is configured"))
+		return
+	}
+
+	reqs := []apimodels.ArtifactUploadRequest{}
+	if err := util.ReadJSONInto(util.NewRequestReader(r), &reqs); err != nil {
+		as.LoggedError(w, r, http.StatusBadRequest, errors.Wrap(err, "unable to read upload requests"))
+		return
+	}
+
+	auth := &aws.Auth{
+		AccessKey: conf.Key,
+		SecretKey: conf.Secret,
+	}
+	prefix := artifactKeyPrefix(conf, t)
+	expiresAt := conf.ExpiresAt(time.Now())
+	uploads := make([]apimodels.ArtifactUpload, 0, len(reqs))
+	for _, req := range reqs {
+		name, err := cleanArtifactName(req.Name)
+		if err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+		if req.Size < 0 {
+			as.LoggedError(w, r, http.StatusBadRequest, errors.Errorf("artifact '%s' has negative size", req.Name))
+			return
+		}
+		contentType := req.ContentType
+		if contentType == "" {
+			contentType = defaultArtifactContentType
+		}
+
+		key := path.Join(prefix, name)
+		url, headers, err := thirdparty.PresignS3Put(auth, thirdparty.S3PutOptions{
+			Region:        conf.Region,
+			Bucket:        conf.Bucket,
+			Key:           key,
+			ContentType:   contentType,
+			ContentLength: req.Size,
+			ContentMD5:    req.MD5,
+			Metadata: map[string]string{
+				"task":      t.Id,
+				"execution": strconv.Itoa(t.Execution),
+				"sha256":    req.SHA256,
+			},
+		}, conf.URLExpiration())
+		if err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		upload := apimodels.ArtifactUpload{
+			Name:      req.Name,
+			URL:       url,
+			Headers:   map[string]string{},
+			Link:      artifactLink(settings.Ui.Url, t, name),
+			Bucket:    conf.Bucket,
+			Key:       key,
+			ExpiresAt: expiresAt,
+		}
+		for k := range headers {
+			upload.Headers[k] = headers.Get(k)
+		}
+		uploads = append(uploads, upload)
+	}
+
+	gimlet.WriteJSON(w, uploads)
+}
+
+// artifactKeyPrefix returns the directory of the task execution's artifacts in
+// the artifacts bucket.
+func artifactKeyPrefix(conf evergreen.ArtifactsConfig, t *task.Task) string {
+	return path.Join(conf.Prefix, t.Project, t.Id, strconv.Itoa(t.Execution))
+}
+
+// artifactLink returns the UI link that downloads the task execution's
+// artifact.
+func artifactLink(uiURL string, t *task.Task, name string) string {
+	escaped := (&url.URL{Path: name}).EscapedPath()
+	return fmt.Sprintf("%s/task/%s/artifacts/%d/%s", uiURL, url.PathEscape(t.Id), t.Execution, escaped)
+}
+
+// taskArtifact redirects to a signed URL that downloads an artifact from the
+// artifacts bucket, which isn't public. Artifacts are visible to the users who
+// can see their task, subject to the artifact's own visibility.
+func (uis *UIServer) taskArtifact(w http.ResponseWriter, r *http.Request) {
+	projCtx := MustHaveProjectContext(r)
+	if projCtx.Task == nil {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	conf := uis.Settings.Artifacts
+	if conf.Bucket == "" {
+		http.Error(w, "no artifacts bucket is configured", http.StatusNotFound)
+		return
+	}
+
+	vars := gimlet.GetVars(r)
+	execution, err := strconv.Atoi(vars["execution"])
+	if err != nil {
+		http.Error(w, "execution must be an integer", http.StatusBadRequest)
+		return
+	}
+	name, err := cleanArtifactName(vars["name"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t := *projCtx.Task
+	t.Execution = execution
+	key := path.Join(artifactKeyPrefix(conf, &t), name)
+
+	entry, err := artifact.FindOne(artifact.ByTaskIdAndExecution(t.Id, execution))
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "problem finding artifacts"))
+		return
+	}
+	var file *artifact.File
+	if entry != nil {
+		for i := range entry.Files {
+			if entry.Files[i].Bucket == conf.Bucket && entry.Files[i].FileKey == key {
+				file = &entry.Files[i]
+				break
+			}
+		}
+	}
+	if file == nil || file.Visibility == artifact.None {
+		http.Error(w, "Artifact not found", http.StatusNotFound)
+		return
+	}
+	if file.Visibility == artifact.Private && gimlet.GetUser(r.Context()) == nil {
+		uis.RedirectToLogin(w, r)
+		return
+	}
+
+	auth := &aws.Auth{
+		AccessKey: conf.Key,
+		SecretKey: conf.Secret,
+	}
+	signedURL, err := thirdparty.PresignS3Get(auth, conf.Region, file.Bucket, file.FileKey, conf.URLExpiration())
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrapf(err, "problem signing artifact '%s'", name))
+		return
+	}
+
+	http.Redirect(w, r, signedURL, http.StatusFound)
+}
+
+// cleanArtifactName returns the artifact's name as a path within the task's
+// directory, rejecting names that leave it.
+func cleanArtifactName(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(name, "/"))
+	if name == "" || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", errors.Errorf("invalid artifact name '%s'", name)
+	}
+	return cleaned, nil
+}
+
+// checkManagedArtifacts ensures that the files that claim to be in the
+// artifacts bucket are in the task's directory of it, since such files are
+// deleted from the bucket when they expire. Their expiration is set by the
+// server's lifecycle policy rather than trusted from the agent.
+func checkManagedArtifacts(conf evergreen.ArtifactsConfig, t *task.Task, files []artifact.File) error {
+	prefix := artifactKeyPrefix(conf, t) + "/"
+	expiresAt := conf.ExpiresAt(time.Now())
+	for i := range files {
+		if files[i].Bucket == "" && files[i].FileKey == "" {
+			files[i].ExpiresAt = time.Time{}
+			continue
+		}
+		if conf.Bucket == "" || files[i].Bucket != conf.Bucket || !strings.HasPrefix(files[i].FileKey, prefix) {
+			return errors.Errorf("artifact '%s' is not in the task's directory of the artifacts bucket", files[i].Name)
+		}
+		files[i].ExpiresAt = expiresAt
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanArtifactName(t *testing.T) {
+	assert := assert.New(t)
+
+	for name, expected := range map[string]string{
+		"dist.tgz":             "dist.tgz",
+		"/logs/out.txt":        "logs/out.txt",
+		"logs/../out.txt":      "out.txt",
+		"logs//nested/./a.txt": "logs/nested/a.txt",
+	} {
+		cleaned, err := cleanArtifactName(name)
+		assert.NoError(err, name)
+		assert.Equal(expected, cleaned, name)
+	}
+
+	for _, name := range []string{"", ".", "..", "../other/dist.tgz", "logs/../../dist.tgz"} {
+		_, err := cleanArtifactName(name)
+		assert.Error(err, name)
+	}
+}
+
+func TestCheckManagedArtifacts(t *testing.T) {
+	assert := assert.New(t)
+	conf := evergreen.ArtifactsConfig{
+		Bucket:         "artifacts",
+		Prefix:         "evg",
+		ExpirationDays: 30,
+	}
+	tsk := &task.Task{Id: "t1", Project: "p1", Execution: 2}
+
+	files := []artifact.File{
+		{Name: "unmanaged", Link: "https://example.com/a", ExpiresAt: time.Now()},
+		{Name: "managed", Link: "l", Bucket: "artifacts", FileKey: "evg/p1/t1/2/dist.tgz"},
+	}
+	assert.NoError(checkManagedArtifacts(conf, tsk, files))
+	assert.True(files[0].ExpiresAt.IsZero())
+	assert.WithinDuration(time.Now().Add(30*24*time.Hour), files[1].ExpiresAt, time.Minute)
+
+	for _, f := range []artifact.File{
+		{Name: "other bucket", Bucket: "other", FileKey: "evg/p1/t1/2/dist.tgz"},
+		{Name: "other task", Bucket: "artifacts", FileKey: "evg/p1/t2/2/dist.tgz"},
+		{Name: "other execution", Bucket: "artifacts", FileKey: "evg/p1/t1/20/dist.tgz"},
+	} {
+		assert.Error(checkManagedArtifacts(conf, tsk, []artifact.File{f}), f.Name)
+	}
+
+	conf.Bucket = ""
+	assert.Error(checkManagedArtifacts(conf, tsk, files[1:]))
+}
+
+func TestArtifactLink(t *testing.T) {
+	assert := assert.New(t)
+	tsk := &task.Task{Id: "t1", Execution: 2}
+
+	assert.Equal("https://evergreen.example.com/task/t1/artifacts/2/logs/out.txt",
+		artifactLink("https://evergreen.example.com", tsk, "logs/out.txt"))
+	assert.Equal("https://evergreen.example.com/task/t1/artifacts/2/with%20space%3F.txt",
+		artifactLink("https://evergreen.example.com", tsk, "with space?.txt"))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/util"
@@ -36,14 +37,17 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 	dbUser := MustHaveUser(r)
 
 	data := struct {
-		Description string   `json:"desc"`
-		Project     string   `json:"project"`
-		Patch       string   `json:"patch"`
-		Githash     string   `json:"githash"`
-		Variants    string   `json:"buildvariants"`
-		Tasks       []string `json:"tasks"`
-		Finalize    bool     `json:"finalize"`
-		Alias       string   `json:"alias"`
+		Description  string              `json:"desc"`
+		Project      string              `json:"project"`
+		Patch        string              `json:"patch"`
+		Githash      string              `json:"githash"`
+		Variants     string              `json:"buildvariants"`
+		Tasks        []string            `json:"tasks"`
+		Finalize     bool                `json:"finalize"`
+		Alias        string              `json:"alias"`
+		Reuse        bool                `json:"reuse"`
+		ReuseModules bool                `json:"reuse_modules"`
+		Parameters   []version.Parameter `json:"parameters"`
 	}{}
 	if err := util.ReadJSONInto(util.NewRequestReaderWithSize(r, patch.SizeLimit), &data); err != nil {
 		as.LoggedError(w, r, http.StatusBadRequest, err)
@@ -66,16 +70,43 @@ func (as *APIServer) submitPatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var previous *patch.Patch
+	if data.Reuse {
+		previous, err = patch.FindOne(patch.MostRecentByUserAndProject(dbUser.Id, data.Project))
+		if err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "can't find previous patch"))
+			return
+		}
+		if previous == nil {
+			as.LoggedError(w, r, http.StatusBadRequest, errors.Errorf("no previous patch of project '%s' to reuse", data.Project))
+			return
+		}
+		variants = previous.BuildVariants
+		data.Tasks = previous.Tasks
+	}
+
 	intent, err := patch.NewCliIntent(dbUser.Id, data.Project, data.Githash, r.FormValue("module"), data.Patch, data.Description, data.Finalize, variants, data.Tasks, data.Alias)
 	if err != nil {
 		as.LoggedError(w, r, http.StatusBadRequest, err)
 		return
 	}
+	if data.Reuse && data.ReuseModules {
+		if err = patch.CopyPreviousModules(intent, previous); err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
 
 	if intent == nil {
 		as.LoggedError(w, r, http.StatusBadRequest, errors.New("intent could not be created from supplied data"))
 		return
 	}
+	if len(data.Parameters) > 0 {
+		if err = patch.SetParameters(intent, data.Parameters); err != nil {
+			as.LoggedError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
 	if err = intent.Insert(); err != nil {
 		as.LoggedError(w, r, http.StatusBadRequest, err)
 		return
@@ -3,6 +3,8 @@ package service
 import (
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -11,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/taskruntime"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
@@ -109,19 +112,59 @@ func checkHostHealth(h *host.Host) bool {
 	return false
 }
 
-// checkAgentRevision checks that the agent revision is current.
-func checkAgentRevision(h *host.Host) bool {
-	if h.AgentRevision != evergreen.BuildRevision {
+// checkAgentRevision checks that the agent revision is the one the host
+// should run.
+func checkAgentRevision(h *host.Host, revision string) bool {
+	if h.AgentRevision != revision {
 		grip.Info(message.Fields{
 			"message":        "agent has wrong revision, so it should exit",
 			"host_revision":  h.AgentRevision,
-			"agent_revision": evergreen.BuildRevision,
+			"agent_revision": revision,
 		})
 		return true
 	}
 	return false
 }
 
+// getAgentUpdate records the revision of an agent that can update itself,
+// and returns the agent it should update itself to, if any. Agents are only
+// updated to versions whose binaries are in the client directory.
+func getAgentUpdate(h *host.Host, agentRevision, revision, url, clientDir string) (*apimodels.AgentUpdate, error) {
+	if h.AgentRevision != agentRevision {
+		if err := h.SetAgentRevision(agentRevision); err != nil {
+			return nil, errors.Wrapf(err, "error setting agent revision of host %s", h.Id)
+		}
+	}
+	if agentRevision == revision {
+		return nil, nil
+	}
+
+	subPath := evergreen.AgentVersionSubPath(revision, h.Distro.ExecutableSubPath())
+	binary := filepath.Join(clientDir, filepath.FromSlash(subPath))
+	if _, err := os.Stat(binary); err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message":        "agent binary is not available, so not updating agent",
+			"host":           h.Id,
+			"distro":         h.Distro.Id,
+			"host_revision":  agentRevision,
+			"agent_revision": revision,
+			"binary":         binary,
+		}))
+		return nil, nil
+	}
+
+	grip.Info(message.Fields{
+		"message":        "agent has wrong revision, so it should update itself",
+		"host":           h.Id,
+		"host_revision":  agentRevision,
+		"agent_revision": revision,
+	})
+	return &apimodels.AgentUpdate{
+		Version: revision,
+		URL:     h.AgentBinaryURL(url, revision),
+	}, nil
+}
+
 // EndTask creates test results from the request and the project config.
 // It then acquires the lock, and with it, marks tasks as finished or inactive if aborted.
 // If the task is a patch, it will alert the users based on failures
@@ -222,11 +265,26 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if t.ShardOf != "" {
+		grip.Error(message.WrapError(as.queue.Put(units.NewTestRuntimeUpdateJob(t.Id, t.Execution)),
+			message.Fields{
+				"message": "problem queueing job to update test runtimes",
+				"task_id": t.Id,
+			}))
+	}
+
 	// update the bookkeeping entry for the task
 	err = task.UpdateExpectedDuration(t, t.TimeTaken)
 	if err != nil {
 		grip.Errorln("Error updating expected duration:", err)
 	}
+	if t.Status == evergreen.TaskSucceeded && !t.DisplayOnly {
+		grip.Error(message.WrapError(taskruntime.Record(t.Project, t.BuildVariant, t.DisplayName, t.TimeTaken),
+			message.Fields{
+				"message": "problem recording task runtime",
+				"task_id": t.Id,
+			}))
+	}
 
 	if checkHostHealth(currentHost) {
 		// set the needs new agent flag on the host
@@ -407,9 +465,40 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		gimlet.WriteJSON(w, response)
 		return
 	}
-	if checkAgentRevision(h) {
-		details := &apimodels.GetNextTaskDetails{}
-		if err := util.ReadJSONInto(util.NewRequestReader(r), details); err != nil {
+	details := &apimodels.GetNextTaskDetails{}
+	detailsErr := util.ReadJSONInto(util.NewRequestReader(r), details)
+	agentUpdateConf := evergreen.AgentUpdateConfig{}
+	if err := agentUpdateConf.Get(); err != nil {
+		err = errors.Wrap(err, "error retrieving agent update settings")
+		grip.Error(err)
+		gimlet.WriteJSONInternalError(w, err)
+		return
+	}
+	revision := agentUpdateConf.DesiredVersion(h.Distro.Id, h.Id)
+	if detailsErr == nil && details.AgentRevision != "" {
+		clientDir := filepath.Join(evergreen.FindEvergreenHome(), evergreen.ClientDirectory)
+		update, err := getAgentUpdate(h, details.AgentRevision, revision, as.Settings.Ui.Url, clientDir)
+		if err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"host":      h.Id,
+				"operation": "next_task",
+				"message":   "problem checking for agent update",
+				"revision":  revision,
+			}))
+			gimlet.WriteJSONInternalError(w, err)
+			return
+		}
+		if update != nil {
+			response.AgentUpdate = update
+			// outside of a task group the agent updates itself before it
+			// runs another task
+			if details.TaskGroup == "" {
+				gimlet.WriteJSON(w, response)
+				return
+			}
+		}
+	} else if checkAgentRevision(h, revision) {
+		if err := detailsErr; err != nil {
 			if innerErr := h.SetNeedsNewAgent(true); innerErr != nil {
 				grip.Error(message.WrapError(innerErr, message.Fields{
 					"host":      h.Id,
@@ -517,6 +606,17 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a draining host finishes its running task but gets no new ones
+	if h.Draining {
+		grip.Info(message.Fields{
+			"message":   "host is draining, returning no task",
+			"host":      h.Id,
+			"operation": "next_task",
+		})
+		gimlet.WriteJSON(w, response)
+		return
+	}
+
 	// retrieve the next task off the task queue and attempt to assign it to the host.
 	// If there is already a host that has the task, it will error
 	taskQueue, err := model.LoadTaskQueue(h.Distro.Id)
@@ -29,6 +29,8 @@ import (
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/queue"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -559,12 +561,47 @@ func TestCheckHostHealth(t *testing.T) {
 		shouldExit = checkHostHealth(h)
 		So(shouldExit, ShouldBeTrue)
 		Convey("With a host that is running but has a different revision", func() {
-			shouldExit := checkAgentRevision(h)
+			shouldExit := checkAgentRevision(h, evergreen.BuildRevision)
 			So(shouldExit, ShouldBeTrue)
+			shouldExit = checkAgentRevision(h, currentRevision)
+			So(shouldExit, ShouldBeFalse)
 		})
 	})
 }
 
+func TestGetAgentUpdate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	clientDir, err := ioutil.TempDir("", "clients")
+	require.NoError(err)
+	defer os.RemoveAll(clientDir)
+
+	h := &host.Host{
+		Id:            "h1",
+		Distro:        distro.Distro{Arch: "linux_amd64"},
+		AgentRevision: "abc",
+	}
+
+	update, err := getAgentUpdate(h, "abc", "abc", "http://evergreen.example.com", clientDir)
+	assert.NoError(err)
+	assert.Nil(update)
+
+	// the binary of the version is not available
+	update, err = getAgentUpdate(h, "abc", "def", "http://evergreen.example.com", clientDir)
+	assert.NoError(err)
+	assert.Nil(update)
+
+	binaryDir := filepath.Join(clientDir, evergreen.AgentVersionsDirectory, "def", "linux_amd64")
+	require.NoError(os.MkdirAll(binaryDir, 0755))
+	require.NoError(ioutil.WriteFile(filepath.Join(binaryDir, "evergreen"), []byte("binary"), 0755))
+	update, err = getAgentUpdate(h, "abc", "def", "http://evergreen.example.com", clientDir)
+	assert.NoError(err)
+	require.NotNil(update)
+	assert.Equal("def", update.Version)
+	assert.Equal("http://evergreen.example.com/clients/versions/def/linux_amd64/evergreen", update.URL)
+}
+
 func TestTaskLifecycleEndpoints(t *testing.T) {
 	conf := testutil.TestConfig()
 	ctx, cancel := context.WithCancel(context.Background())
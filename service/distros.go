@@ -41,7 +41,7 @@ func (uis *UIServer) distrosPage(w http.ResponseWriter, r *http.Request) {
 	containerPoolIds := make([]string, 0)
 	for _, p := range settings.ContainerPools.Pools {
 		containerPools = append(containerPools, p)
-		containerPoolDistros = append(containerPoolDistros, p.Distro)
+		containerPoolDistros = append(containerPoolDistros, p.ParentDistros()...)
 		containerPoolIds = append(containerPoolIds, p.Id)
 	}
 
@@ -81,7 +81,15 @@ func (uis *UIServer) modifyDistro(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	newDistro := oldDistro
+	// the changes are decoded onto a separate copy of the distro, since
+	// decoding into a copy of the old distro would reuse its slices
+	newDistro, err := distro.FindOne(distro.ById(id))
+	if err != nil {
+		message := fmt.Sprintf("error finding distro: %v", err)
+		PushFlash(uis.CookieStore, r, w, NewErrorFlash(message))
+		http.Error(w, message, http.StatusInternalServerError)
+		return
+	}
 
 	// attempt to unmarshal data into distros field for type validation
 	if err = json.Unmarshal(b, &newDistro); err != nil {
@@ -135,7 +143,7 @@ func (uis *UIServer) modifyDistro(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	event.LogDistroModified(id, u.Username(), newDistro)
+	event.LogDistroModified(id, u.Username(), oldDistro, newDistro)
 
 	message := fmt.Sprintf("Distro %v successfully updated.", id)
 	if shouldDeco {
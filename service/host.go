@@ -5,16 +5,24 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/goamz/goamz/aws"
 	"github.com/pkg/errors"
 )
 
+// hostArtifactLinkExpiration is how long the signed URLs that container
+// artifacts are downloaded from are valid for.
+const hostArtifactLinkExpiration = 15 * time.Minute
+
 var (
 	validUpdateToStatuses = []string{
 		evergreen.HostRunning,
@@ -104,6 +112,47 @@ func (uis *UIServer) hostsPage(w http.ResponseWriter, r *http.Request) {
 		"base", "hosts.html", "base_angular.html", "menu.html")
 }
 
+// hostArtifact redirects to a signed URL that downloads an artifact collected
+// from the container before it was removed, since the artifacts aren't
+// public.
+func (uis *UIServer) hostArtifact(w http.ResponseWriter, r *http.Request) {
+	vars := gimlet.GetVars(r)
+	id := vars["host_id"]
+	name := vars["name"]
+
+	if name != cloud.ContainerLogArtifact && name != cloud.ContainerWorkDirArtifact {
+		http.Error(w, fmt.Sprintf("'%s' is not a container artifact", name), http.StatusNotFound)
+		return
+	}
+	bucket := uis.Settings.Providers.Docker.ArtifactsBucket
+	if bucket == "" {
+		http.Error(w, "container artifacts are not collected", http.StatusNotFound)
+		return
+	}
+
+	h, err := host.FindOne(host.ById(id))
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if h == nil || h.ParentID == "" {
+		http.Error(w, "Container not found", http.StatusNotFound)
+		return
+	}
+
+	auth := &aws.Auth{
+		AccessKey: uis.Settings.Providers.AWS.Id,
+		SecretKey: uis.Settings.Providers.AWS.Secret,
+	}
+	signedURL, err := thirdparty.PresignS3Get(auth, "", bucket, cloud.ContainerArtifactKey(h.Id, name), hostArtifactLinkExpiration)
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, errors.Wrapf(err, "problem signing artifact '%s' of host '%s'", name, h.Id))
+		return
+	}
+
+	http.Redirect(w, r, signedURL, http.StatusFound)
+}
+
 func (uis *UIServer) modifyHost(w http.ResponseWriter, r *http.Request) {
 	env := evergreen.GetEnvironment()
 	queue := env.RemoteQueue()
@@ -187,10 +187,44 @@ func (uis *UIServer) setCORSHeaders(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
-// isAdmin returns false if the user is nil or if its id is not
-// located in ProjectRef's Admins field.
+// isAdmin returns whether the user has the project admin role on the
+// project, not counting superusers.
 func isAdmin(u gimlet.User, project *model.ProjectRef) bool {
-	return util.StringSliceContains(project.Admins, u.Username())
+	return userProjectRole(u, project).Includes(user.RoleProjectAdmin)
+}
+
+// projectRole returns the user's role on the project.
+func (uis *UIServer) projectRole(u gimlet.User, project *model.ProjectRef) user.Role {
+	if u == nil {
+		return user.RoleNone
+	}
+	if uis.isSuperUser(u) {
+		return user.RoleSuperuser
+	}
+	return userProjectRole(u, project)
+}
+
+// userProjectRole returns the user's role on the project, not counting
+// superusers, including the roles the user's system roles grant.
+func userProjectRole(u gimlet.User, project *model.ProjectRef) user.Role {
+	if dbUser, ok := u.(*user.DBUser); ok {
+		return user.HighestRole(project.UserRole(u.Username()), dbUser.ProjectRole(project.Identifier))
+	}
+	return project.UserRole(u.Username())
+}
+
+// canModify returns whether the user can act on the project's version or
+// patch by the author: users can act on their own patches and contributors
+// on mainline versions, for which the author is empty, but only project
+// admins can act on other users' patches.
+func (uis *UIServer) canModify(u gimlet.User, project *model.ProjectRef, author string) bool {
+	if u != nil && author != "" && author == u.Username() {
+		return true
+	}
+	if author != "" {
+		return uis.projectRole(u, project).Includes(user.RoleProjectAdmin)
+	}
+	return uis.projectRole(u, project).Includes(user.RoleContributor)
 }
 
 // RedirectToLogin forces a redirect to the login page. The redirect param is set on the query
@@ -234,6 +268,11 @@ func (uis *UIServer) loadCtx(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		if usr != nil && projCtx.ProjectRef != nil && uis.projectRole(usr, projCtx.ProjectRef) == user.RoleNone {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
 		r = setUIRequestContext(r, projCtx)
 
 		next(w, r)
@@ -241,9 +280,9 @@ func (uis *UIServer) loadCtx(next http.HandlerFunc) http.HandlerFunc {
 }
 
 // populateProjectRefs loads all project refs into the context. If includePrivate is true,
-// all available projects will be included, otherwise only public projects will be loaded.
-// Sets IsAdmin to true if the user id is located in a project's admin list.
-func (pc *projectContext) populateProjectRefs(includePrivate, isSuperUser bool, user gimlet.User) error {
+// the private projects the user has a role on will be included, otherwise only public projects
+// will be loaded. Sets IsAdmin to true if the user id is located in a project's admin list.
+func (pc *projectContext) populateProjectRefs(includePrivate, isSuperUser bool, u gimlet.User) error {
 	allProjs, err := model.FindAllTrackedProjectRefs()
 	if err != nil {
 		return err
@@ -251,14 +290,14 @@ func (pc *projectContext) populateProjectRefs(includePrivate, isSuperUser bool,
 	pc.AllProjects = make([]UIProjectFields, 0, len(allProjs))
 	// User is not logged in, so only include public projects.
 	for _, p := range allProjs {
-		if includePrivate && (isSuperUser || isAdmin(user, &p)) {
+		if includePrivate && (isSuperUser || isAdmin(u, &p)) {
 			pc.IsAdmin = true
 		}
 
 		if !p.Enabled {
 			continue
 		}
-		if !p.Private || includePrivate {
+		if !p.Private || (includePrivate && (isSuperUser || userProjectRole(u, &p) != user.RoleNone)) {
 			uiProj := UIProjectFields{
 				DisplayName: p.DisplayName,
 				Identifier:  p.Identifier,
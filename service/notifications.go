@@ -1,8 +1,11 @@
 package service
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen/rest/data"
+	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/gimlet"
 )
 
@@ -17,3 +20,25 @@ func (uis *UIServer) notificationsPage(w http.ResponseWriter, r *http.Request) {
 	}{uis.GetCommonViewData(w, r, true, true)},
 		"base", "notifications.html", "base_angular.html", "menu.html")
 }
+
+// acknowledgeNotification acknowledges the escalation that a notification
+// was sent in, from the link in the notification, so that it doesn't
+// escalate further.
+func (uis *UIServer) acknowledgeNotification(w http.ResponseWriter, r *http.Request) {
+	u := MustHaveUser(r)
+	id := gimlet.GetVars(r)["notification_id"]
+
+	sc := &data.NotificationConnector{}
+	escalation, err := sc.AcknowledgeNotification(id, u.Username())
+	switch {
+	case err != nil:
+		PushFlash(uis.CookieStore, r, w, NewErrorFlash(fmt.Sprintf("Could not acknowledge the notification: %s", err.Error())))
+	case restModel.FromAPIString(escalation.AcknowledgedBy) != u.Username():
+		PushFlash(uis.CookieStore, r, w, NewInfoFlash(fmt.Sprintf("The notification was already acknowledged by %s",
+			restModel.FromAPIString(escalation.AcknowledgedBy))))
+	default:
+		PushFlash(uis.CookieStore, r, w, NewSuccessFlash("Acknowledged the notification, it will not be escalated further"))
+	}
+
+	http.Redirect(w, r, "/notifications", http.StatusFound)
+}
@@ -97,7 +97,7 @@ func (uis *UIServer) schedulePatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	curUser := gimlet.GetUser(r.Context())
-	if curUser == nil {
+	if curUser == nil || !uis.canModify(curUser, projCtx.ProjectRef, projCtx.Patch.Author) {
 		http.Error(w, "Not authorized to schedule patch", http.StatusUnauthorized)
 		return
 	}
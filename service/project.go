@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
-	"regexp"
 	"strings"
 	"time"
 
@@ -172,34 +171,59 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// changes are recorded even if only some of them are saved
+	before, err := model.GetProjectSettings(id)
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	defer logProjectModified(id, dbUser.Id, before)
+
 	responseRef := struct {
-		Identifier         string               `json:"id"`
-		DisplayName        string               `json:"display_name"`
-		RemotePath         string               `json:"remote_path"`
-		BatchTime          int                  `json:"batch_time"`
-		DeactivatePrevious bool                 `json:"deactivate_previous"`
-		Branch             string               `json:"branch_name"`
-		ProjVarsMap        map[string]string    `json:"project_vars"`
-		ProjectAliases     []model.ProjectAlias `json:"project_aliases"`
-		DeleteAliases      []string             `json:"delete_aliases"`
-		PrivateVars        map[string]bool      `json:"private_vars"`
-		Enabled            bool                 `json:"enabled"`
-		Private            bool                 `json:"private"`
-		Owner              string               `json:"owner_name"`
-		Repo               string               `json:"repo_name"`
-		Admins             []string             `json:"admins"`
-		TracksPushEvents   bool                 `json:"tracks_push_events"`
-		PRTestingEnabled   bool                 `json:"pr_testing_enabled"`
-		PatchingDisabled   bool                 `json:"patching_disabled"`
-		AlertConfig        map[string][]struct {
+		Identifier                string                           `json:"id"`
+		DisplayName               string                           `json:"display_name"`
+		RemotePath                string                           `json:"remote_path"`
+		BatchTime                 int                              `json:"batch_time"`
+		DeactivatePrevious        bool                             `json:"deactivate_previous"`
+		SuppressInheritedWarnings bool                             `json:"suppress_inherited_warnings"`
+		StepbackBisect            bool                             `json:"stepback_bisect"`
+		TestSelectionEnabled      bool                             `json:"test_selection_enabled"`
+		FullRunIntervalHours      int                              `json:"full_run_interval_hours"`
+		Branch                    string                           `json:"branch_name"`
+		ProjVarsMap               map[string]string                `json:"project_vars"`
+		ProjectAliases            []model.ProjectAlias             `json:"project_aliases"`
+		DeleteAliases             []string                         `json:"delete_aliases"`
+		PrivateVars               map[string]bool                  `json:"private_vars"`
+		SecretVars                map[string]model.SecretReference `json:"secret_vars"`
+		Enabled                   bool                             `json:"enabled"`
+		Private                   bool                             `json:"private"`
+		Owner                     string                           `json:"owner_name"`
+		Repo                      string                           `json:"repo_name"`
+		Admins                    []string                         `json:"admins"`
+		Contributors              []string                         `json:"contributors"`
+		Viewers                   []string                         `json:"viewers"`
+		TracksPushEvents          bool                             `json:"tracks_push_events"`
+		PRTestingEnabled          bool                             `json:"pr_testing_enabled"`
+		GithubChecksEnabled       bool                             `json:"github_checks_enabled"`
+		PatchingDisabled          bool                             `json:"patching_disabled"`
+		PatchExpirationDays       int                              `json:"patch_expiration_days"`
+		AlertConfig               map[string][]struct {
 			Provider string                 `json:"provider"`
 			Settings map[string]interface{} `json:"settings"`
 		} `json:"alert_config"`
-		NotifyOnBuildFailure bool                        `json:"notify_on_failure"`
-		SetupGithubHook      bool                        `json:"setup_github_hook"`
-		ForceRepotrackerRun  bool                        `json:"force_repotracker_run"`
-		Subscriptions        []restModel.APISubscription `json:"subscriptions"`
-		DeleteSubscriptions  []string                    `json:"delete_subscriptions"`
+		NotifyOnBuildFailure     bool                    `json:"notify_on_failure"`
+		BuildBreakTeamChannel    string                  `json:"build_break_team_channel"`
+		BuildBreakEscalationMins int                     `json:"build_break_escalation_mins"`
+		CommitQueue              model.CommitQueueParams `json:"commit_queue"`
+		SetupGithubHook          bool                    `json:"setup_github_hook"`
+		ForceRepotrackerRun      bool                    `json:"force_repotracker_run"`
+		EnableRepotracker        []string                `json:"enable_repotracker"`
+		PauseActivation          struct {
+			Hours  int    `json:"hours"`
+			Reason string `json:"reason"`
+		} `json:"pause_activation"`
+		Subscriptions       []restModel.APISubscription `json:"subscriptions"`
+		DeleteSubscriptions []string                    `json:"delete_subscriptions"`
 	}{}
 
 	if err = util.ReadJSONInto(util.NewRequestReader(r), &responseRef); err != nil {
@@ -212,24 +236,22 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	errs := []string{}
-	for i, pd := range responseRef.ProjectAliases {
-		if strings.TrimSpace(pd.Alias) == "" {
-			errs = append(errs, fmt.Sprintf("alias name #%d can't be empty string", i+1))
-		}
-		if strings.TrimSpace(pd.Variant) == "" {
-			errs = append(errs, fmt.Sprintf("variant regex #%d can't be empty string", i+1))
-		}
-		if strings.TrimSpace(pd.Task) == "" && len(pd.Tags) == 0 {
-			errs = append(errs, fmt.Sprintf("must specify either task regex or tags on line #%d ", i+1))
-		}
-
-		if _, err = regexp.Compile(pd.Variant); err != nil {
-			errs = append(errs, fmt.Sprintf("variant regex #%d is invalid", i+1))
-		}
-		if _, err = regexp.Compile(pd.Task); err != nil {
-			errs = append(errs, fmt.Sprintf("task regex #%d is invalid", i+1))
-		}
+	errs := model.ValidateProjectAliases(responseRef.ProjectAliases)
+	if responseRef.BuildBreakEscalationMins < 0 {
+		errs = append(errs, "build break escalation delay can't be negative")
+	}
+	if responseRef.PatchExpirationDays < 0 {
+		errs = append(errs, "patch expiration can't be negative")
+	}
+	if responseRef.FullRunIntervalHours < 0 {
+		errs = append(errs, "full run interval can't be negative")
+	}
+	if responseRef.CommitQueue.MergeMethod != "" && !util.StringSliceContains(model.CommitQueueMergeMethods, responseRef.CommitQueue.MergeMethod) {
+		errs = append(errs, fmt.Sprintf("commit queue merge method must be one of %s", strings.Join(model.CommitQueueMergeMethods, ", ")))
+	}
+	secretVars := &model.ProjectVars{Id: id, SecretVars: responseRef.SecretVars}
+	if err = secretVars.ValidateSecretVars(uis.Settings.Secrets.Prefix); err != nil {
+		errs = append(errs, err.Error())
 	}
 	if len(errs) > 0 {
 		errMsg := ""
@@ -264,13 +286,24 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	projectRef.Private = responseRef.Private
 	projectRef.Owner = responseRef.Owner
 	projectRef.DeactivatePrevious = responseRef.DeactivatePrevious
+	projectRef.SuppressInheritedWarnings = responseRef.SuppressInheritedWarnings
+	projectRef.StepbackBisect = responseRef.StepbackBisect
+	projectRef.TestSelectionEnabled = responseRef.TestSelectionEnabled
+	projectRef.FullRunIntervalHours = responseRef.FullRunIntervalHours
 	projectRef.Repo = responseRef.Repo
 	projectRef.Admins = responseRef.Admins
+	projectRef.Contributors = responseRef.Contributors
+	projectRef.Viewers = responseRef.Viewers
 	projectRef.Identifier = id
 	projectRef.TracksPushEvents = responseRef.TracksPushEvents
 	projectRef.PRTestingEnabled = responseRef.PRTestingEnabled
+	projectRef.GithubChecksEnabled = responseRef.GithubChecksEnabled
+	projectRef.CommitQueue = responseRef.CommitQueue
 	projectRef.PatchingDisabled = responseRef.PatchingDisabled
+	projectRef.PatchExpirationDays = responseRef.PatchExpirationDays
 	projectRef.NotifyOnBuildFailure = responseRef.NotifyOnBuildFailure
+	projectRef.BuildBreakTeamChannel = strings.TrimSpace(responseRef.BuildBreakTeamChannel)
+	projectRef.BuildBreakEscalationMins = responseRef.BuildBreakEscalationMins
 
 	projectVars, err := model.FindOneProjectVars(id)
 	if err != nil {
@@ -327,6 +360,18 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	for _, capability := range responseRef.EnableRepotracker {
+		projectRef.EnableRepotracker(model.RepotrackerCapability(capability))
+	}
+	if responseRef.PauseActivation.Hours > 0 {
+		reason := responseRef.PauseActivation.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("paused by %s", dbUser.Id)
+		}
+		projectRef.DisableRepotracker(model.RepotrackerActivation, reason,
+			time.Duration(responseRef.PauseActivation.Hours)*time.Hour)
+	}
+
 	if responseRef.ForceRepotrackerRun {
 		ts := util.RoundPartOfHour(1).Format(tsFormat)
 		j := units.NewRepotrackerJob(fmt.Sprintf("catchup-%s", ts), projectRef.Identifier)
@@ -392,6 +437,7 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	}
 	projectVars.Vars = responseRef.ProjVarsMap
 	projectVars.PrivateVars = responseRef.PrivateVars
+	projectVars.SecretVars = responseRef.SecretVars
 
 	_, err = projectVars.Upsert()
 	if err != nil {
@@ -464,6 +510,16 @@ func (uis *UIServer) addProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	settings, err := model.GetProjectSettings(newProject.Identifier)
+	if err == nil {
+		err = model.LogProjectAdded(newProject.Identifier, dbUser.Id, settings)
+	}
+	grip.Error(message.WrapError(err, message.Fields{
+		"message": "problem recording new project",
+		"project": newProject.Identifier,
+		"user":    dbUser.Id,
+	}))
+
 	allProjects, err := uis.filterAuthorizedProjects(dbUser)
 
 	if err != nil {
@@ -480,6 +536,20 @@ func (uis *UIServer) addProject(w http.ResponseWriter, r *http.Request) {
 	gimlet.WriteJSON(w, data)
 }
 
+// logProjectModified records the changes that the user made to the
+// project's settings since the snapshot taken before them.
+func logProjectModified(projectId, user string, before *model.ProjectSettings) {
+	after, err := model.GetProjectSettings(projectId)
+	if err == nil {
+		err = model.LogProjectModified(projectId, user, before, after)
+	}
+	grip.Error(message.WrapError(err, message.Fields{
+		"message": "problem recording project changes",
+		"project": projectId,
+		"user":    user,
+	}))
+}
+
 // setRevision sets the latest revision in the Repository
 // database to the revision sent from the projects page.
 func (uis *UIServer) setRevision(w http.ResponseWriter, r *http.Request) {
@@ -515,9 +585,11 @@ func (uis *UIServer) setRevision(w http.ResponseWriter, r *http.Request) {
 		uis.LoggedError(w, r, http.StatusInternalServerError, err)
 		return
 	}
-	projectRef.RepotrackerError.Exists = false
-	projectRef.RepotrackerError.InvalidRevision = ""
-	projectRef.RepotrackerError.MergeBaseRevision = ""
+	projectRef.EnableRepotracker(model.RepotrackerRevisions)
+	if projectRef.RepotrackerError != nil {
+		projectRef.RepotrackerError.InvalidRevision = ""
+		projectRef.RepotrackerError.MergeBaseRevision = ""
+	}
 	err = projectRef.Upsert()
 	if err != nil {
 		uis.LoggedError(w, r, http.StatusInternalServerError, err)
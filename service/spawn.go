@@ -148,7 +148,7 @@ func (uis *UIServer) requestNewHost(w http.ResponseWriter, r *http.Request) {
 			uis.LoggedError(w, r, http.StatusInternalServerError, errors.New("Error finding task"))
 			return
 		}
-		err = hc.CreateHostsFromTask(task, *authedUser, putParams.PublicKey)
+		err = hc.CreateHostsFromTask(r.Context(), task, *authedUser, putParams.PublicKey)
 		if err != nil {
 			uis.LoggedError(w, r, http.StatusInternalServerError, errors.New("Error creating hosts from task"))
 			return
@@ -15,6 +15,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/plugin"
 	"github.com/evergreen-ci/evergreen/util"
@@ -115,6 +116,41 @@ type uiTestResult struct {
 	TestResult task.TestResult `json:"test_result"`
 	TaskId     *string         `json:"task_id"`
 	TaskName   *string         `json:"task_name"`
+	// Flaky is whether the test is known to flake in mainline versions.
+	Flaky     bool    `json:"flaky"`
+	FlakeRate float64 `json:"flake_rate"`
+}
+
+// setTestFlakiness marks the task's test results with how often the tests
+// flake in mainline versions. A failure to find the flakiness only leaves
+// the tests unmarked.
+func setTestFlakiness(t *task.Task, execTasks []uiExecTask, results []uiTestResult) {
+	if len(results) == 0 {
+		return
+	}
+	taskNames := []string{t.DisplayName}
+	for _, et := range execTasks {
+		taskNames = append(taskNames, et.Name)
+	}
+	flakiness, err := testflakiness.FindByTasks(t.Project, t.BuildVariant, taskNames)
+	if err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "problem finding test flakiness",
+			"task":    t.Id,
+		}))
+		return
+	}
+
+	for i := range results {
+		taskName := t.DisplayName
+		if results[i].TaskName != nil {
+			taskName = *results[i].TaskName
+		}
+		if stat, ok := flakiness[taskName][results[i].TestResult.TestFile]; ok {
+			results[i].Flaky = stat.IsFlaky()
+			results[i].FlakeRate = stat.FlakeRate
+		}
+	}
 }
 
 func (uis *UIServer) taskPage(w http.ResponseWriter, r *http.Request) {
@@ -323,6 +359,7 @@ func (uis *UIServer) taskPage(w http.ResponseWriter, r *http.Request) {
 			uiTask.DisplayTaskID = projCtx.Task.DisplayTask.Id
 		}
 	}
+	setTestFlakiness(projCtx.Task, uiTask.ExecutionTasks, uiTask.TestResults)
 
 	ctx := r.Context()
 	usr := gimlet.GetUser(ctx)
@@ -71,6 +71,12 @@ func (uis *UIServer) login(w http.ResponseWriter, r *http.Request) {
 }
 
 func (uis *UIServer) logout(w http.ResponseWriter, r *http.Request) {
+	if u := gimlet.GetUser(r.Context()); u != nil {
+		grip.Warning(message.WrapError(user.ClearLoginCache(u.Username()), message.Fields{
+			"message": "problem ending session",
+			"user":    u.Username(),
+		}))
+	}
 	clearSession(w)
 	loginURL := fmt.Sprintf("%v/login", uis.RootURL)
 	http.Redirect(w, r, loginURL, http.StatusFound)
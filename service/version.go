@@ -169,6 +169,14 @@ func (uis *UIServer) modifyVersion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	user := MustHaveUser(r)
+	author := ""
+	if evergreen.IsPatchRequester(projCtx.Version.Requester) {
+		author = projCtx.Version.Author
+	}
+	if !uis.canModify(user, projCtx.ProjectRef, author) {
+		http.Error(w, "Not authorized to modify version", http.StatusUnauthorized)
+		return
+	}
 
 	jsonMap := struct {
 		Action   string   `json:"action"`
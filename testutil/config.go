@@ -85,6 +85,33 @@ func MockConfig() *evergreen.Settings {
 				Users:        []string{"ghuser"},
 				Organization: "ghorg",
 			},
+			OIDC: &evergreen.OIDCConfig{
+				Issuer:        "https://sso.example.com",
+				ClientId:      "oidcclient",
+				ClientSecret:  "oidcsecret",
+				Scopes:        []string{"openid", "groups"},
+				UsernameClaim: "preferred_username",
+				GroupsClaim:   "groups",
+				AllowedGroups: []string{"engineering"},
+				GroupRoles:    []evergreen.OIDCGroupRole{{Group: "release", Role: "project_admin", Projects: []string{"mci"}}},
+				SessionHours:  12,
+			},
+		},
+		AgentUpdate: evergreen.AgentUpdateConfig{
+			Version:        "agent_version",
+			RolloutPercent: 10,
+			Distros: []evergreen.AgentDistroRollout{
+				{Distro: "valid-distro", RolloutPercent: 50},
+			},
+		},
+		Artifacts: evergreen.ArtifactsConfig{
+			Bucket:            "artifacts_bucket",
+			Prefix:            "artifacts",
+			Region:            "us-east-1",
+			Key:               "artifacts_key",
+			Secret:            "artifacts_secret",
+			URLExpirationMins: 15,
+			ExpirationDays:    90,
 		},
 		Banner:            "banner",
 		BannerTheme:       "important",
@@ -100,7 +127,17 @@ func MockConfig() *evergreen.Settings {
 				},
 			},
 		},
-		Credentials:        map[string]string{"k1": "v1"},
+		Credentials: map[string]string{"k1": "v1"},
+		DataLifecycle: evergreen.DataLifecycleConfig{
+			ArchiveAfterDays: 365,
+			BatchSize:        1000,
+			Target:           evergreen.ArchiveTargetS3,
+			Bucket:           "archive_bucket",
+			Prefix:           "archive",
+			Region:           "us-east-1",
+			Key:              "archive_key",
+			Secret:           "archive_secret",
+		},
 		Expansions:         map[string]string{"k2": "v2"},
 		GithubPRCreatorOrg: "org",
 		HostInit: evergreen.HostInitConfig{
@@ -150,6 +187,12 @@ func MockConfig() *evergreen.Settings {
 				ProjectID:        "project_id",
 				Region:           "region",
 			},
+			Azure: evergreen.AzureConfig{
+				TenantID:       "tenant",
+				ClientID:       "azure_client",
+				ClientSecret:   "azure_secret",
+				SubscriptionID: "subscription",
+			},
 			VSphere: evergreen.VSphereConfig{
 				Host:     "host",
 				Username: "vsphere",
@@ -164,23 +207,37 @@ func MockConfig() *evergreen.Settings {
 		Scheduler: evergreen.SchedulerConfig{
 			TaskFinder: "legacy",
 		},
+		Secrets: evergreen.SecretsConfig{
+			AWS: evergreen.AWSSecretsConfig{
+				Region: "us-east-1",
+				Key:    "secrets_key",
+				Secret: "secrets_secret",
+			},
+			Vault: evergreen.VaultSecretsConfig{
+				URL:       "https://vault.example.com",
+				Token:     "vault_token",
+				MountPath: "secret",
+			},
+			Prefix: "evergreen",
+		},
 		ServiceFlags: evergreen.ServiceFlags{
-			TaskDispatchDisabled:         true,
-			HostinitDisabled:             true,
-			MonitorDisabled:              true,
-			AlertsDisabled:               true,
-			TaskrunnerDisabled:           true,
-			RepotrackerDisabled:          true,
-			SchedulerDisabled:            true,
-			GithubPRTestingDisabled:      true,
-			RepotrackerPushEventDisabled: true,
-			CLIUpdatesDisabled:           true,
-			EventProcessingDisabled:      true,
-			JIRANotificationsDisabled:    true,
-			SlackNotificationsDisabled:   true,
-			EmailNotificationsDisabled:   true,
-			WebhookNotificationsDisabled: true,
-			GithubStatusAPIDisabled:      true,
+			TaskDispatchDisabled:          true,
+			HostinitDisabled:              true,
+			MonitorDisabled:               true,
+			AlertsDisabled:                true,
+			TaskrunnerDisabled:            true,
+			RepotrackerDisabled:           true,
+			SchedulerDisabled:             true,
+			GithubPRTestingDisabled:       true,
+			RepotrackerPushEventDisabled:  true,
+			CLIUpdatesDisabled:            true,
+			EventProcessingDisabled:       true,
+			JIRANotificationsDisabled:     true,
+			SlackNotificationsDisabled:    true,
+			EmailNotificationsDisabled:    true,
+			WebhookNotificationsDisabled:  true,
+			OpsgenieNotificationsDisabled: true,
+			GithubStatusAPIDisabled:       true,
 		},
 		Slack: evergreen.SlackConfig{
 			Options: &send.SlackOptions{
@@ -188,8 +245,9 @@ func MockConfig() *evergreen.Settings {
 				Fields:    true,
 				FieldsSet: map[string]bool{},
 			},
-			Token: "token",
-			Level: "info",
+			Token:         "token",
+			Level:         "info",
+			SigningSecret: "signing_secret",
 		},
 		Splunk: send.SplunkConnectionInfo{
 			ServerURL: "server",
@@ -340,6 +340,94 @@ func GetBranchEvent(ctx context.Context, oauthToken, repoOwner, repo, branch str
 	return branchEvent, nil
 }
 
+// GetGithubPullRequest gets a pull request via an API call to GitHub
+func GetGithubPullRequest(ctx context.Context, oauthToken, repoOwner, repo string, prNumber int) (*github.PullRequest, error) {
+	httpClient, err := getGithubClient(oauthToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	pr, resp, err := client.PullRequests.Get(ctx, repoOwner, repo, prNumber)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, APIResponseError{fmt.Sprintf("error querying '%s/%s' pull request %d: %v", repoOwner, repo, prNumber, err)}
+	}
+
+	return pr, nil
+}
+
+// MergePullRequest merges a pull request with the merge method via an API
+// call to GitHub, as long as the head of the pull request is still sha.
+func MergePullRequest(ctx context.Context, oauthToken, repoOwner, repo string, prNumber int, sha, mergeMethod, commitTitle string) error {
+	httpClient, err := getGithubClient(oauthToken)
+	if err != nil {
+		return errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	result, resp, err := client.PullRequests.Merge(ctx, repoOwner, repo, prNumber, "", &github.PullRequestOptions{
+		CommitTitle: commitTitle,
+		SHA:         sha,
+		MergeMethod: mergeMethod,
+	})
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return APIResponseError{fmt.Sprintf("error merging '%s/%s' pull request %d: %v", repoOwner, repo, prNumber, err)}
+	}
+	if !result.GetMerged() {
+		return errors.Errorf("'%s/%s' pull request %d was not merged: %s", repoOwner, repo, prNumber, result.GetMessage())
+	}
+
+	return nil
+}
+
+// GetGithubTag gets the annotated tag object with the given SHA via an API
+// call to GitHub
+func GetGithubTag(ctx context.Context, oauthToken, repoOwner, repo, tagSHA string) (*github.Tag, error) {
+	httpClient, err := getGithubClient(oauthToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "can't fetch data from github")
+	}
+	defer util.PutHTTPClient(httpClient)
+	client := github.NewClient(httpClient)
+
+	grip.Debugf("requesting github tag for '%s/%s': sha: %s\n", repoOwner, repo, tagSHA)
+
+	tag, resp, err := client.Git.GetTag(ctx, repoOwner, repo, tagSHA)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("error querying '%s/%s': tag: '%s': %v", repoOwner, repo, tagSHA, err)
+		grip.Error(errMsg)
+		return nil, APIResponseError{errMsg}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, ResponseReadError{err.Error()}
+		}
+		requestError := APIRequestError{}
+		if err = json.Unmarshal(respBody, &requestError); err != nil {
+			return nil, APIRequestError{Message: string(respBody)}
+		}
+		return nil, requestError
+	}
+	if tag == nil {
+		return nil, errors.New("tag not found in github")
+	}
+
+	return tag, nil
+}
+
 // githubRequest performs the specified http request. If the oauth token field is empty it will not use oauth
 func githubRequest(ctx context.Context, method string, url string, oauthToken string, data interface{}) (*http.Response, error) {
 	req, err := http.NewRequest(method, url, nil)
@@ -3,6 +3,7 @@ package thirdparty
 import (
 	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 
@@ -19,6 +20,7 @@ type httpClient interface {
 	doGet(string, string, string) (*http.Response, error)
 	doPost(string, string, string, interface{}) (*http.Response, error)
 	doPut(string, string, string, interface{}) (*http.Response, error)
+	doUpload(string, string, string, string, io.Reader) (*http.Response, error)
 }
 
 type liveHttp struct{}
@@ -135,3 +137,29 @@ func (self liveHttp) doPut(url string, username string, password string, content
 	resp, err := self.postOrPut("PUT", url, username, password, content)
 	return resp, errors.WithStack(err)
 }
+
+// doUpload posts the body, which has the given content type, such as a
+// multipart form of files to upload.
+func (self liveHttp) doUpload(url string, username string, password string, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest("POST", url, body)
+	if err != nil {
+		return nil, errors.Wrap(err, "POST")
+	}
+
+	req.Header.Add("Accept", "*/*")
+	req.SetBasicAuth(username, password)
+	req.Header.Add("Content-Type", contentType)
+	// JIRA rejects uploads without this header, to prevent cross-site
+	// request forgery
+	req.Header.Add("X-Atlassian-Token", "no-check")
+
+	client := util.GetHTTPClient()
+	defer util.PutHTTPClient(client)
+
+	var resp *http.Response
+	resp, err = doFollowingRedirectsWithHeaders(client, req)
+	if err != nil {
+		return resp, errors.WithStack(err)
+	}
+	return resp, nil
+}
@@ -1,12 +1,17 @@
 package thirdparty
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
 	"net/url"
 
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
 )
 
@@ -48,6 +53,24 @@ type JiraCreateTicketResponse struct {
 	Self string `json:"self"`
 }
 
+// JiraTransition is a workflow transition that moves a ticket to the status
+// To.
+type JiraTransition struct {
+	Id   string      `json:"id"`
+	Name string      `json:"name"`
+	To   *JiraStatus `json:"to"`
+}
+
+// JiraAttachment is a file attached to a ticket.
+type JiraAttachment struct {
+	Id       string `json:"id"`
+	Self     string `json:"self"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mimeType"`
+	Size     int    `json:"size"`
+	Content  string `json:"content"`
+}
+
 type JiraStatus struct {
 	Id   string `json:"id"`
 	Self string `json:"self"`
@@ -146,6 +169,120 @@ func (jiraHandler *JiraHandler) UpdateTicket(key string, fields map[string]inter
 	return nil
 }
 
+// GetTransitions returns the transitions that can be performed on the ticket
+// with the given key in its current status.
+func (jiraHandler *JiraHandler) GetTransitions(key string) ([]JiraTransition, error) {
+	apiEndpoint := fmt.Sprintf("%s/rest/api/2/issue/%v/transitions", jiraHandler.JiraServer, url.QueryEscape(key))
+
+	res, err := jiraHandler.MyHttp.doGet(apiEndpoint, jiraHandler.UserName, jiraHandler.Password)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if res == nil {
+		return nil, errors.Errorf("HTTP results are nil even though err was nil")
+	}
+	if res.StatusCode >= 300 || res.StatusCode < 200 {
+		return nil, errors.Errorf("HTTP request returned unexpected status `%v`", res.Status)
+	}
+
+	results := struct {
+		Transitions []JiraTransition `json:"transitions"`
+	}{}
+	if err = json.NewDecoder(res.Body).Decode(&results); err != nil {
+		return nil, errors.Wrap(err, "Unable to decode http body")
+	}
+
+	return results.Transitions, nil
+}
+
+// TransitionTicket performs the transition with the given ID on the ticket
+// with the given key, setting the given fields and adding the comment, if
+// any, as part of the transition. Returns any errors JIRA returns.
+func (jiraHandler *JiraHandler) TransitionTicket(key, transitionID string, fields map[string]interface{}, comment string) error {
+	apiEndpoint := fmt.Sprintf("%s/rest/api/2/issue/%v/transitions", jiraHandler.JiraServer, url.QueryEscape(key))
+	postArgs := struct {
+		Transition map[string]string                   `json:"transition"`
+		Fields     map[string]interface{}              `json:"fields,omitempty"`
+		Update     map[string][]map[string]interface{} `json:"update,omitempty"`
+	}{
+		Transition: map[string]string{"id": transitionID},
+		Fields:     fields,
+	}
+	if comment != "" {
+		postArgs.Update = map[string][]map[string]interface{}{
+			"comment": {{"add": map[string]string{"body": comment}}},
+		}
+	}
+
+	res, err := jiraHandler.MyHttp.doPost(apiEndpoint, jiraHandler.UserName, jiraHandler.Password, postArgs)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if res.StatusCode >= 300 || res.StatusCode < 200 {
+		msg, _ := ioutil.ReadAll(res.Body)
+		return errors.Errorf("HTTP request returned unexpected status `%v`: %v", res.Status, string(msg))
+	}
+
+	return nil
+}
+
+// AttachFiles attaches the files, which must have been fetched if they are
+// stored in S3, to the ticket with the given key, and returns the attachments
+// JIRA created.
+func (jiraHandler *JiraHandler) AttachFiles(key string, files []util.Attachment) ([]JiraAttachment, error) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	for _, f := range files {
+		contentType := f.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+			"name":     "file",
+			"filename": f.Filename,
+		}))
+		header.Set("Content-Type", contentType)
+
+		part, err := form.CreatePart(header)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error adding '%s' to request", f.Filename)
+		}
+		if _, err = part.Write(f.Data); err != nil {
+			return nil, errors.Wrapf(err, "error adding '%s' to request", f.Filename)
+		}
+	}
+	if err := form.Close(); err != nil {
+		return nil, errors.Wrap(err, "error encoding request")
+	}
+
+	apiEndpoint := fmt.Sprintf("%s/rest/api/2/issue/%v/attachments", jiraHandler.JiraServer, url.QueryEscape(key))
+	res, err := jiraHandler.MyHttp.doUpload(apiEndpoint, jiraHandler.UserName, jiraHandler.Password, form.FormDataContentType(), body)
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if res.StatusCode >= 300 || res.StatusCode < 200 {
+		msg, _ := ioutil.ReadAll(res.Body)
+		return nil, errors.Errorf("HTTP request returned unexpected status `%v`: %v", res.Status, string(msg))
+	}
+
+	attachments := []JiraAttachment{}
+	if err = json.NewDecoder(res.Body).Decode(&attachments); err != nil {
+		return nil, errors.Wrap(err, "Unable to decode http body")
+	}
+
+	return attachments, nil
+}
+
 // GetJIRATicket returns the ticket with the given key.
 func (jiraHandler *JiraHandler) GetJIRATicket(key string) (*JiraTicket, error) {
 	apiEndpoint := fmt.Sprintf("%s/rest/api/latest/issue/%v", jiraHandler.JiraServer, url.QueryEscape(key))
@@ -2,11 +2,16 @@ package thirdparty
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
 	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"testing"
 
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 )
@@ -28,6 +33,10 @@ func (self stubHttp) doPut(url string, username string, password string, content
 	return self.res, self.err
 }
 
+func (self stubHttp) doUpload(url string, username string, password string, contentType string, body io.Reader) (*http.Response, error) {
+	return self.res, self.err
+}
+
 func TestJiraNetworkFail(t *testing.T) {
 	Convey("With a JIRA rest interface with broken network", t, func() {
 		stub := stubHttp{nil, errors.New("Generic network error")}
@@ -73,3 +82,97 @@ func TestJiraIntegration(t *testing.T) {
 		})
 	})
 }
+
+type recordingHttp struct {
+	stubHttp
+	urls     []string
+	contents []interface{}
+}
+
+func (self *recordingHttp) doGet(url string, username string, password string) (*http.Response, error) {
+	self.urls = append(self.urls, url)
+	return self.res, self.err
+}
+
+func (self *recordingHttp) doPost(url string, username string, password string, content interface{}) (*http.Response, error) {
+	self.urls = append(self.urls, url)
+	self.contents = append(self.contents, content)
+	return self.res, self.err
+}
+
+func (self *recordingHttp) doUpload(url string, username string, password string, contentType string, body io.Reader) (*http.Response, error) {
+	self.urls = append(self.urls, url)
+	self.contents = append(self.contents, contentType)
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	self.contents = append(self.contents, data)
+	return self.res, self.err
+}
+
+func TestJiraTransitions(t *testing.T) {
+	Convey("With a JIRA rest interface that supports transitions", t, func() {
+		stub := &recordingHttp{stubHttp: stubHttp{res: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"}}}
+		jira := JiraHandler{stub, "https://jira.example.com", "user", "password"}
+
+		Convey("the transitions of a ticket should be decoded", func() {
+			stub.res.Body = ioutil.NopCloser(bytes.NewBufferString(
+				`{"transitions": [{"id": "3", "name": "Reopen Issue", "to": {"id": "4", "name": "Reopened"}}]}`))
+			transitions, err := jira.GetTransitions("BF-1")
+			So(err, ShouldBeNil)
+			So(stub.urls, ShouldResemble, []string{"https://jira.example.com/rest/api/2/issue/BF-1/transitions"})
+			So(len(transitions), ShouldEqual, 1)
+			So(transitions[0].Id, ShouldEqual, "3")
+			So(transitions[0].Name, ShouldEqual, "Reopen Issue")
+			So(transitions[0].To.Name, ShouldEqual, "Reopened")
+		})
+
+		Convey("transitioning a ticket should send the fields and comment", func() {
+			stub.res.StatusCode = http.StatusNoContent
+			stub.res.Body = ioutil.NopCloser(&bytes.Buffer{})
+			err := jira.TransitionTicket("BF-1", "3", map[string]interface{}{"assignee": map[string]string{"name": "me"}}, "failed again")
+			So(err, ShouldBeNil)
+			So(stub.urls, ShouldResemble, []string{"https://jira.example.com/rest/api/2/issue/BF-1/transitions"})
+			body, err := json.Marshal(stub.contents[0])
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, `{"transition":{"id":"3"},"fields":{"assignee":{"name":"me"}},"update":{"comment":[{"add":{"body":"failed again"}}]}}`)
+		})
+
+		Convey("a rejected transition should return the error from JIRA", func() {
+			stub.res.StatusCode = http.StatusBadRequest
+			stub.res.Status = "400 Bad Request"
+			stub.res.Body = ioutil.NopCloser(bytes.NewBufferString(`{"errorMessages": ["bad transition"]}`))
+			err := jira.TransitionTicket("BF-1", "99", nil, "")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "bad transition")
+		})
+	})
+}
+
+func TestJiraAttachFiles(t *testing.T) {
+	Convey("With a JIRA rest interface that accepts attachments", t, func() {
+		stub := &recordingHttp{stubHttp: stubHttp{res: &http.Response{StatusCode: http.StatusOK, Status: "200 OK"}}}
+		jira := JiraHandler{stub, "https://jira.example.com", "user", "password"}
+
+		Convey("the files should be uploaded as a multipart form", func() {
+			stub.res.Body = ioutil.NopCloser(bytes.NewBufferString(`[{"id": "10", "filename": "task.log", "mimeType": "text/plain", "size": 3}]`))
+			attachments, err := jira.AttachFiles("BF-1", []util.Attachment{
+				{Filename: "task.log", ContentType: "text/plain", Data: []byte("log")},
+			})
+			So(err, ShouldBeNil)
+			So(stub.urls, ShouldResemble, []string{"https://jira.example.com/rest/api/2/issue/BF-1/attachments"})
+			So(len(attachments), ShouldEqual, 1)
+			So(attachments[0].Id, ShouldEqual, "10")
+			So(attachments[0].Size, ShouldEqual, 3)
+
+			_, params, err := mime.ParseMediaType(stub.contents[0].(string))
+			So(err, ShouldBeNil)
+			form, err := multipart.NewReader(bytes.NewReader(stub.contents[1].([]byte)), params["boundary"]).ReadForm(1024)
+			So(err, ShouldBeNil)
+			So(len(form.File["file"]), ShouldEqual, 1)
+			So(form.File["file"][0].Filename, ShouldEqual, "task.log")
+			So(form.File["file"][0].Header.Get("Content-Type"), ShouldEqual, "text/plain")
+		})
+	})
+}
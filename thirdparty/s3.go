@@ -12,11 +12,13 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"sort"
 	"strings"
 	"time"
 
 	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	awsS3 "github.com/aws/aws-sdk-go/service/s3"
@@ -399,6 +401,239 @@ func GetS3File(auth *aws.Auth, s3URL string) (io.ReadCloser, error) {
 	return rc.Body, nil
 }
 
+// ReadS3File returns the contents of the file at the given S3 URL, or an
+// error if the file is larger than maxSize bytes.
+func ReadS3File(auth *aws.Auth, s3URL string, maxSize int) ([]byte, error) {
+	rc, err := GetS3File(auth, s3URL)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(io.LimitReader(rc, int64(maxSize)+1))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading '%s'", s3URL)
+	}
+	if len(data) > maxSize {
+		return nil, errors.Errorf("'%s' is larger than %d bytes", s3URL, maxSize)
+	}
+
+	return data, nil
+}
+
+// S3URLInBucket returns whether the given s3://bucket/key URL refers to an
+// object in the given bucket whose key begins with the given prefix.
+func S3URLInBucket(s3URL, bucket, prefix string) bool {
+	u, err := url.Parse(s3URL)
+	if err != nil || u.Scheme != "s3" || bucket == "" || u.Host != bucket {
+		return false
+	}
+
+	// the SDK cleans the path of the key, so reject keys that would
+	// resolve outside of the prefix
+	key := strings.TrimPrefix(u.Path, "/")
+	if key == "" || path.Clean("/"+key) != "/"+key {
+		return false
+	}
+
+	return strings.HasPrefix(key, prefix)
+}
+
+// FetchS3Attachments reads the contents of the attachments that are stored
+// in S3, as long as they are all in the given bucket under the given prefix
+// and total no more than maxSize bytes.
+func FetchS3Attachments(auth *aws.Auth, bucket, prefix string, attachments []util.Attachment, maxSize int) error {
+	size := 0
+	for _, a := range attachments {
+		size += len(a.Data)
+	}
+
+	for i := range attachments {
+		a := &attachments[i]
+		if len(a.Data) != 0 || a.S3URL == "" {
+			continue
+		}
+		if !S3URLInBucket(a.S3URL, bucket, prefix) {
+			return errors.Errorf("attachment '%s' is not in the attachment bucket", a.Filename)
+		}
+
+		data, err := ReadS3File(auth, a.S3URL, maxSize-size)
+		if err != nil {
+			return errors.Wrapf(err, "error fetching attachment '%s'", a.Filename)
+		}
+		a.Data = data
+		size += len(data)
+	}
+
+	return nil
+}
+
+// S3PutOptions describes an object that a presigned request uploads to S3.
+type S3PutOptions struct {
+	Region        string
+	Bucket        string
+	Key           string
+	ContentType   string
+	ContentLength int64
+	// ContentMD5 is the base64 encoded MD5 digest of the object, which S3
+	// checks the upload against.
+	ContentMD5 string
+	Metadata   map[string]string
+}
+
+// PresignS3Put returns a URL that uploads the described object to S3 without
+// any further credentials until it expires, and the headers that the upload
+// must be sent with.
+func PresignS3Put(auth *aws.Auth, opts S3PutOptions, expiration time.Duration) (string, http.Header, error) {
+	svc, err := newS3Service(auth, opts.Region)
+	if err != nil {
+		return "", nil, errors.WithStack(err)
+	}
+
+	input := &awsS3.PutObjectInput{
+		Bucket:        awsSDK.String(opts.Bucket),
+		Key:           awsSDK.String(opts.Key),
+		ContentLength: awsSDK.Int64(opts.ContentLength),
+	}
+	if opts.ContentType != "" {
+		input.ContentType = awsSDK.String(opts.ContentType)
+	}
+	if opts.ContentMD5 != "" {
+		input.ContentMD5 = awsSDK.String(opts.ContentMD5)
+	}
+	if len(opts.Metadata) > 0 {
+		input.Metadata = awsSDK.StringMap(opts.Metadata)
+	}
+
+	req, _ := svc.PutObjectRequest(input)
+	url, signedHeaders, err := req.PresignRequest(expiration)
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "problem signing upload of '%s' to bucket '%s'", opts.Key, opts.Bucket)
+	}
+
+	// the signed headers are keyed in lower case, so they're copied to
+	// make them usable with http.Header's methods
+	headers := http.Header{}
+	for k, vals := range signedHeaders {
+		for _, v := range vals {
+			headers.Add(k, v)
+		}
+	}
+	return url, headers, nil
+}
+
+// DeleteS3Files deletes the objects with the given keys from the bucket.
+func DeleteS3Files(auth *aws.Auth, region, bucket string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	svc, err := newS3Service(auth, region)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	objects := make([]*awsS3.ObjectIdentifier, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, &awsS3.ObjectIdentifier{Key: awsSDK.String(key)})
+	}
+	resp, err := svc.DeleteObjects(&awsS3.DeleteObjectsInput{
+		Bucket: awsSDK.String(bucket),
+		Delete: &awsS3.Delete{
+			Objects: objects,
+			Quiet:   awsSDK.Bool(true),
+		},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "problem deleting %d files from bucket '%s'", len(keys), bucket)
+	}
+	if len(resp.Errors) > 0 {
+		return errors.Errorf("problem deleting '%s' from bucket '%s': %s",
+			awsSDK.StringValue(resp.Errors[0].Key), bucket, awsSDK.StringValue(resp.Errors[0].Message))
+	}
+	return nil
+}
+
+// PutS3Object uploads the body to the bucket under the given key.
+func PutS3Object(auth *aws.Auth, region, bucket, key, contentType string, body []byte) error {
+	svc, err := newS3Service(auth, region)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	input := &awsS3.PutObjectInput{
+		Bucket: awsSDK.String(bucket),
+		Key:    awsSDK.String(key),
+		Body:   bytes.NewReader(body),
+	}
+	if contentType != "" {
+		input.ContentType = awsSDK.String(contentType)
+	}
+	if _, err = svc.PutObject(input); err != nil {
+		return errors.Wrapf(err, "problem putting '%s' in bucket '%s'", key, bucket)
+	}
+	return nil
+}
+
+// GetS3Object returns the contents of the object with the given key in the
+// bucket, or nil if there is no such object.
+func GetS3Object(auth *aws.Auth, region, bucket, key string) ([]byte, error) {
+	svc, err := newS3Service(auth, region)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp, err := svc.GetObject(&awsS3.GetObjectInput{
+		Bucket: awsSDK.String(bucket),
+		Key:    awsSDK.String(key),
+	})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == awsS3.ErrCodeNoSuchKey {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem getting '%s' from bucket '%s'", key, bucket)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	return body, errors.Wrapf(err, "problem reading '%s' from bucket '%s'", key, bucket)
+}
+
+func newS3Service(auth *aws.Auth, s3Region string) (*awsS3.S3, error) {
+	if s3Region == "" {
+		s3Region = region
+	}
+	config := &awsSDK.Config{
+		Credentials: credentials.NewStaticCredentials(auth.AccessKey, auth.SecretKey, auth.Token()),
+		Region:      awsSDK.String(s3Region),
+	}
+	session, err := session.NewSession(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating new session")
+	}
+	return awsS3.New(session), nil
+}
+
+// PresignS3Get returns a URL that downloads the object from the bucket
+// without any further credentials until it expires, so that private objects
+// can be linked to.
+func PresignS3Get(auth *aws.Auth, s3Region, bucket, key string, expiration time.Duration) (string, error) {
+	svc, err := newS3Service(auth, s3Region)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	req, _ := svc.GetObjectRequest(&awsS3.GetObjectInput{
+		Bucket: awsSDK.String(bucket),
+		Key:    awsSDK.String(key),
+	})
+	url, err := req.Presign(expiration)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem signing download of '%s' from bucket '%s'", key, bucket)
+	}
+	return url, nil
+}
+
 //Taken from https://github.com/mitchellh/goamz/blob/master/s3/sign.go
 //Modified to access the headers/params on an HTTP req directly.
 func SignAWSRequest(auth aws.Auth, canonicalPath string, req *http.Request) {
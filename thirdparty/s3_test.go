@@ -3,7 +3,9 @@ package thirdparty
 import (
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/goamz/goamz/aws"
@@ -121,3 +123,63 @@ func TestLegacyGetS3FileWithLegacyPut(t *testing.T) {
 	assert.Equal(http.StatusOK, resp.StatusCode)
 	assert.Equal(randStr, string(data[:]))
 }
+
+func TestPresignS3Put(t *testing.T) {
+	assert := assert.New(t)
+	auth := &aws.Auth{
+		AccessKey: "access",
+		SecretKey: "secret",
+	}
+
+	signedURL, headers, err := PresignS3Put(auth, S3PutOptions{
+		Bucket:        "artifacts",
+		Key:           "project/task/0/dist.tgz",
+		ContentType:   "application/x-gzip",
+		ContentLength: 42,
+		ContentMD5:    "1B2M2Y8AsgTpgAmY7PhCfg==",
+		Metadata:      map[string]string{"task": "task"},
+	}, 15*time.Minute)
+	assert.NoError(err)
+
+	parsed, err := url.Parse(signedURL)
+	assert.NoError(err)
+	assert.Equal("artifacts.s3.amazonaws.com", parsed.Host)
+	assert.Equal("/project/task/0/dist.tgz", parsed.Path)
+	assert.Equal("900", parsed.Query().Get("X-Amz-Expires"))
+	assert.Contains(parsed.Query().Get("X-Amz-Credential"), "access/")
+	assert.Equal("application/x-gzip", headers.Get("Content-Type"))
+	assert.Equal("1B2M2Y8AsgTpgAmY7PhCfg==", headers.Get("Content-Md5"))
+	assert.Equal("task", headers.Get("X-Amz-Meta-Task"))
+}
+
+func TestPresignS3Get(t *testing.T) {
+	assert := assert.New(t)
+	auth := &aws.Auth{
+		AccessKey: "access",
+		SecretKey: "secret",
+	}
+
+	signedURL, err := PresignS3Get(auth, "", "artifacts", "containers/host/container.log", 15*time.Minute)
+	assert.NoError(err)
+
+	parsed, err := url.Parse(signedURL)
+	assert.NoError(err)
+	assert.Equal("artifacts.s3.amazonaws.com", parsed.Host)
+	assert.Equal("/containers/host/container.log", parsed.Path)
+	assert.Equal("900", parsed.Query().Get("X-Amz-Expires"))
+	assert.Contains(parsed.Query().Get("X-Amz-Credential"), "access/")
+}
+
+func TestS3URLInBucket(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(S3URLInBucket("s3://attachments/notify/task.log", "attachments", "notify/"))
+	assert.True(S3URLInBucket("s3://attachments/task.log", "attachments", ""))
+
+	assert.False(S3URLInBucket("s3://attachments/task.log", "", ""))
+	assert.False(S3URLInBucket("s3://secrets/notify/task.log", "attachments", "notify/"))
+	assert.False(S3URLInBucket("s3://attachments/other/task.log", "attachments", "notify/"))
+	assert.False(S3URLInBucket("s3://attachments/notify/../other/task.log", "attachments", "notify/"))
+	assert.False(S3URLInBucket("s3://attachments/notify//task.log", "attachments", "notify/"))
+	assert.False(S3URLInBucket("https://attachments/notify/task.log", "attachments", "notify/"))
+}
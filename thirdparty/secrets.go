@@ -0,0 +1,252 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	awsSDK "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol/jsonrpc"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SecretsBackendAWS stores secrets in AWS Secrets Manager.
+	SecretsBackendAWS = "aws_secrets_manager"
+	// SecretsBackendVault stores secrets in the key/value store of a
+	// HashiCorp Vault server.
+	SecretsBackendVault = "vault"
+
+	// vaultValueKey is the key of the data of a Vault secret that holds its
+	// value, unless another key is given.
+	vaultValueKey = "value"
+)
+
+// ValidSecretsBackends are the backends that project variables can be stored
+// in.
+var ValidSecretsBackends = []string{SecretsBackendAWS, SecretsBackendVault}
+
+// SecretsBackend stores secrets by name.
+type SecretsBackend interface {
+	// GetSecret returns the value of a secret. If key is given, the secret
+	// holds a set of values and the one with that key is returned.
+	GetSecret(ctx context.Context, name, key string) (string, error)
+	// PutSecret creates or replaces the value of a secret.
+	PutSecret(ctx context.Context, name, value string) error
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// AWS Secrets Manager
+
+type awsSecretsManager struct {
+	*client.Client
+}
+
+// NewAWSSecretsManager returns a backend for secrets stored in AWS Secrets
+// Manager, accessed with the given credentials.
+func NewAWSSecretsManager(region, key, secret string) (SecretsBackend, error) {
+	sess, err := session.NewSession(&awsSDK.Config{
+		Credentials: credentials.NewStaticCredentials(key, secret, ""),
+		Region:      awsSDK.String(region),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating new session")
+	}
+
+	c := sess.ClientConfig("secretsmanager")
+	sm := &awsSecretsManager{
+		Client: client.New(
+			*c.Config,
+			metadata.ClientInfo{
+				ServiceName:   "secretsmanager",
+				SigningName:   c.SigningName,
+				SigningRegion: c.SigningRegion,
+				Endpoint:      c.Endpoint,
+				APIVersion:    "2017-10-17",
+				JSONVersion:   "1.1",
+				TargetPrefix:  "secretsmanager",
+			},
+			c.Handlers,
+		),
+	}
+	sm.Handlers.Sign.PushBackNamed(v4.SignRequestHandler)
+	sm.Handlers.Build.PushBackNamed(jsonrpc.BuildHandler)
+	sm.Handlers.Unmarshal.PushBackNamed(jsonrpc.UnmarshalHandler)
+	sm.Handlers.UnmarshalMeta.PushBackNamed(jsonrpc.UnmarshalMetaHandler)
+	sm.Handlers.UnmarshalError.PushBackNamed(jsonrpc.UnmarshalErrorHandler)
+
+	return sm, nil
+}
+
+type awsSecretInput struct {
+	_ struct{} `type:"structure"`
+
+	Name         *string `type:"string"`
+	SecretId     *string `type:"string"`
+	SecretString *string `type:"string"`
+}
+
+type awsSecretOutput struct {
+	_ struct{} `type:"structure"`
+
+	SecretString *string `type:"string"`
+}
+
+func (sm *awsSecretsManager) do(ctx context.Context, operation string, input *awsSecretInput) (*awsSecretOutput, error) {
+	output := &awsSecretOutput{}
+	req := sm.NewRequest(&request.Operation{
+		Name:       operation,
+		HTTPMethod: http.MethodPost,
+		HTTPPath:   "/",
+	}, input, output)
+	req.SetContext(ctx)
+	return output, req.Send()
+}
+
+func (sm *awsSecretsManager) GetSecret(ctx context.Context, name, key string) (string, error) {
+	output, err := sm.do(ctx, "GetSecretValue", &awsSecretInput{SecretId: awsSDK.String(name)})
+	if err != nil {
+		return "", errors.Wrapf(err, "problem getting secret '%s' from AWS Secrets Manager", name)
+	}
+	value := awsSDK.StringValue(output.SecretString)
+	if key == "" {
+		return value, nil
+	}
+
+	// secrets with several values hold them as a JSON object
+	values := map[string]string{}
+	if err = json.Unmarshal([]byte(value), &values); err != nil {
+		return "", errors.Wrapf(err, "secret '%s' doesn't hold a set of values", name)
+	}
+	return secretValue(values, name, key)
+}
+
+func (sm *awsSecretsManager) PutSecret(ctx context.Context, name, value string) error {
+	_, err := sm.do(ctx, "PutSecretValue", &awsSecretInput{
+		SecretId:     awsSDK.String(name),
+		SecretString: awsSDK.String(value),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "ResourceNotFoundException" {
+		_, err = sm.do(ctx, "CreateSecret", &awsSecretInput{
+			Name:         awsSDK.String(name),
+			SecretString: awsSDK.String(value),
+		})
+	}
+	return errors.Wrapf(err, "problem storing secret '%s' in AWS Secrets Manager", name)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Vault
+
+type vaultSecrets struct {
+	url       string
+	token     string
+	mountPath string
+}
+
+// NewVaultSecrets returns a backend for secrets stored in the version 2
+// key/value store mounted at the given path of a Vault server. Secrets
+// created by the backend hold their value under the key "value".
+func NewVaultSecrets(url, token, mountPath string) SecretsBackend {
+	return &vaultSecrets{
+		url:       strings.TrimRight(url, "/"),
+		token:     token,
+		mountPath: strings.Trim(mountPath, "/"),
+	}
+}
+
+type vaultSecretData struct {
+	Data map[string]string `json:"data"`
+}
+
+func (v *vaultSecrets) do(ctx context.Context, method, name string, body interface{}) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem marshalling secret")
+		}
+	}
+
+	// escape each segment of the name, so that it can't change the path
+	// outside of the secret
+	segments := strings.Split(strings.Trim(name, "/"), "/")
+	for i := range segments {
+		if segments[i] == "." || segments[i] == ".." {
+			return nil, errors.Errorf("invalid secret name '%s'", name)
+		}
+		segments[i] = url.PathEscape(segments[i])
+	}
+	secretURL := fmt.Sprintf("%s/v1/%s/data/%s", v.url, v.mountPath, strings.Join(segments, "/"))
+	req, err := http.NewRequest(method, secretURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	c := util.GetHTTPClient()
+	defer util.PutHTTPClient(c)
+
+	return c.Do(req)
+}
+
+func (v *vaultSecrets) GetSecret(ctx context.Context, name, key string) (string, error) {
+	resp, err := v.do(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return "", errors.Wrapf(err, "problem getting secret '%s' from Vault", name)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("problem getting secret '%s' from Vault: %s", name, resp.Status)
+	}
+
+	secret := struct {
+		Data vaultSecretData `json:"data"`
+	}{}
+	if err = util.ReadJSONInto(resp.Body, &secret); err != nil {
+		return "", errors.Wrapf(err, "problem reading secret '%s' from Vault", name)
+	}
+	if key == "" {
+		key = vaultValueKey
+	}
+	return secretValue(secret.Data.Data, name, key)
+}
+
+func (v *vaultSecrets) PutSecret(ctx context.Context, name, value string) error {
+	resp, err := v.do(ctx, http.MethodPost, name, vaultSecretData{
+		Data: map[string]string{vaultValueKey: value},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "problem storing secret '%s' in Vault", name)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return errors.Errorf("problem storing secret '%s' in Vault: %s", name, resp.Status)
+	}
+	return nil
+}
+
+func secretValue(values map[string]string, name, key string) (string, error) {
+	value, ok := values[key]
+	if !ok {
+		return "", errors.Errorf("secret '%s' has no value for '%s'", name, key)
+	}
+	return value, nil
+}
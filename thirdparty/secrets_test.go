@@ -0,0 +1,73 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultSecrets(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	secrets := map[string]map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			in := vaultSecretData{}
+			if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			secrets[r.URL.EscapedPath()] = in.Data
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			data, ok := secrets[r.URL.EscapedPath()]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": vaultSecretData{Data: data},
+			})
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	vault := NewVaultSecrets(srv.URL+"/", "token", "/kv/")
+	require.NoError(vault.PutSecret(ctx, "project/var", "value"))
+	assert.Contains(secrets, "/v1/kv/data/project/var")
+
+	value, err := vault.GetSecret(ctx, "project/var", "")
+	require.NoError(err)
+	assert.Equal("value", value)
+
+	_, err = vault.GetSecret(ctx, "project/var", "other")
+	assert.Error(err)
+	_, err = vault.GetSecret(ctx, "missing", "")
+	assert.Error(err)
+
+	secrets["/v1/kv/data/shared"] = map[string]string{"user": "admin", "password": "hunter2"}
+	value, err = vault.GetSecret(ctx, "shared", "password")
+	require.NoError(err)
+	assert.Equal("hunter2", value)
+
+	// names can't change the path outside of the secret
+	require.NoError(vault.PutSecret(ctx, "project/a?b#c", "value"))
+	assert.Contains(secrets, "/v1/kv/data/project/a%3Fb%23c")
+	_, err = vault.GetSecret(ctx, "project/../other/var", "")
+	assert.Error(err)
+
+	_, err = NewVaultSecrets(srv.URL, "wrong", "kv").GetSecret(ctx, "project/var", "")
+	assert.Error(err)
+}
@@ -3,6 +3,7 @@ package trigger
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -251,6 +252,7 @@ func (t *buildTriggers) makeData(sub *event.Subscription, pastTenseOverride stri
 		Project:         t.build.Project,
 		URL:             buildLink(t.uiConfig.Url, t.build.Id),
 		PastTenseStatus: t.data.Status,
+		Build:           t.build,
 		apiModel:        &api,
 	}
 	if t.build.Requester == evergreen.GithubPRRequester && t.build.Status == t.data.Status {
@@ -266,6 +268,7 @@ func (t *buildTriggers) makeData(sub *event.Subscription, pastTenseOverride stri
 		data.PastTenseStatus = pastTenseOverride
 	}
 	data.slack = t.buildAttachments(&data)
+	data.githubCheckSummary = t.githubCheckSummary()
 
 	return &data, nil
 }
@@ -304,6 +307,22 @@ func (t *buildTriggers) buildAttachments(data *commonTemplateData) []message.Sla
 	return attachments
 }
 
+// githubCheckSummary describes the build's tasks in markdown, listing the
+// tasks that didn't succeed with links to them.
+func (t *buildTriggers) githubCheckSummary() string {
+	summary := &strings.Builder{}
+	fmt.Fprintf(summary, "%s\n", taskStatusToDesc(t.build))
+	for i := range t.build.Tasks {
+		if t.build.Tasks[i].Status == evergreen.TaskSucceeded {
+			continue
+		}
+		fmt.Fprintf(summary, "\n* [%s](%s): %s", t.build.Tasks[i].DisplayName,
+			taskLink(t.uiConfig.Url, t.build.Tasks[i].Id, -1), taskFormatFromCache(&t.build.Tasks[i]))
+	}
+
+	return summary.String()
+}
+
 func (t *buildTriggers) generate(sub *event.Subscription, pastTenseOverride string) (*notification.Notification, error) {
 	data, err := t.makeData(sub, pastTenseOverride)
 	if err != nil {
@@ -318,6 +337,19 @@ func (t *buildTriggers) generate(sub *event.Subscription, pastTenseOverride stri
 	return notification.New(t.event.ID, sub.Trigger, &sub.Subscriber, payload)
 }
 
+func (t *buildTriggers) changedSince() (string, bool, error) {
+	if t.data.Status == "" || t.build.Status == t.data.Status {
+		return "", false, nil
+	}
+
+	fixed := t.data.Status == evergreen.BuildFailed && t.build.Status == evergreen.BuildSucceeded
+	return fmt.Sprintf("the build is now %s", t.build.Status), fixed, nil
+}
+
+func (t *buildTriggers) render(sub *event.Subscription) (*notification.Notification, error) {
+	return t.generate(sub, "")
+}
+
 func taskFormatFromCache(t *build.TaskCache) string {
 	if t.Status == evergreen.TaskSucceeded {
 		return fmt.Sprintf("took %s", t.TimeTaken)
@@ -153,6 +153,25 @@ func (s *buildSuite) TestAllTriggers() {
 	s.Len(n, 0)
 }
 
+func (s *buildSuite) TestChangedSince() {
+	change, fixed, err := s.t.changedSince()
+	s.NoError(err)
+	s.Empty(change)
+	s.False(fixed)
+
+	s.data.Status = evergreen.BuildFailed
+	s.build.Status = evergreen.BuildStarted
+	change, fixed, err = s.t.changedSince()
+	s.NoError(err)
+	s.Equal("the build is now started", change)
+	s.False(fixed)
+
+	s.build.Status = evergreen.BuildSucceeded
+	_, fixed, err = s.t.changedSince()
+	s.NoError(err)
+	s.True(fixed)
+}
+
 func (s *buildSuite) TestSuccess() {
 	n, err := s.t.buildSuccess(&s.subs[1])
 	s.NoError(err)
@@ -24,6 +24,8 @@ const (
 	triggerRegression             = "regression"
 	triggerExceedsDuration        = "exceeds-duration"
 	triggerRuntimeChangeByPercent = "runtime-change"
+	triggerStatusTransition       = "status-transition"
+	triggerConfigWarnings         = "config-warnings"
 )
 
 func runtimeExceedsThreshold(threshold, prevDuration, thisDuration float64) (bool, float64) {
@@ -0,0 +1,123 @@
+package trigger
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// RenderAtDelivery renders a notification that was held back, in a digest
+// or until its recipient's quiet hours ended, again against the current
+// state of the resource that it is about, so that its recipient isn't sent
+// a snapshot that's outdated by the time it's delivered. It returns nil if
+// the notification reports a failure that has since been fixed, a
+// re-rendered notification noting the change if the resource has otherwise
+// changed, and the notification itself if it can't be rendered again or is
+// still current.
+func RenderAtDelivery(n *notification.Notification) (*notification.Notification, error) {
+	if n.EventID == "" || n.SubscriptionID == "" {
+		return n, nil
+	}
+
+	e, err := event.FindLogEntryByID(n.EventID)
+	if err != nil {
+		return n, errors.Wrapf(err, "error fetching event for notification '%s'", n.ID)
+	}
+	if e == nil {
+		return n, nil
+	}
+	sub, err := event.FindSubscriptionByID(n.SubscriptionID)
+	if err != nil {
+		return n, errors.Wrapf(err, "error fetching subscription for notification '%s'", n.ID)
+	}
+	if sub == nil {
+		return n, nil
+	}
+
+	h := registry.eventHandler(e.ResourceType, e.EventType)
+	r, ok := h.(deliveryRenderer)
+	if !ok {
+		return n, nil
+	}
+	if err = h.Fetch(e); err != nil {
+		return n, errors.Wrapf(err, "error fetching data for event: %s (%s, %s)", e.ID, e.ResourceType, e.EventType)
+	}
+
+	change, fixed, err := r.changedSince()
+	if err != nil {
+		return n, errors.Wrapf(err, "error checking notification '%s' for changes", n.ID)
+	}
+	if fixed {
+		return nil, nil
+	}
+	if change == "" {
+		return n, nil
+	}
+
+	// the subscriber may have been re-routed by the recipient's delivery
+	// preferences when the notification was generated
+	sub.Subscriber = n.Subscriber
+	rendered, err := r.render(sub)
+	if err != nil {
+		return n, errors.Wrapf(err, "error rendering notification '%s' again", n.ID)
+	}
+
+	out := *n
+	if rendered != nil {
+		out.Payload = rendered.Payload
+	}
+	if out.Payload != nil {
+		out.Payload = annotatePayload(out.Payload, change)
+	}
+
+	return &out, nil
+}
+
+// annotatePayload notes the change to the resource at the top of an email
+// or slack payload. Other payloads are left as they are.
+func annotatePayload(payload interface{}, change string) interface{} {
+	note := fmt.Sprintf("This notification was delayed, and %s.", change)
+	switch p := payload.(type) {
+	case *message.Email:
+		out := *p
+		out.Subject = "[Outdated] " + out.Subject
+		out.Body = annotateEmailBody(out.Body, note, out.PlainTextContents)
+		return &out
+
+	case *util.EvergreenEmail:
+		out := *p
+		out.Subject = "[Outdated] " + out.Subject
+		out.Body = annotateEmailBody(out.Body, note, out.PlainTextContents)
+		if out.HTMLBody != "" {
+			out.HTMLBody = annotateEmailBody(out.HTMLBody, note, false)
+		}
+		return &out
+
+	case *notification.SlackPayload:
+		out := *p
+		out.Body = fmt.Sprintf("_%s_\n%s", note, out.Body)
+		if len(out.Blocks) > 0 {
+			out.Blocks = append([]util.SlackBlock{{
+				Type:     util.SlackBlockContext,
+				Elements: []util.SlackBlockElement{{Type: util.SlackTextMarkdown, Text: fmt.Sprintf("_%s_", note)}},
+			}}, out.Blocks...)
+		}
+		return &out
+
+	default:
+		return payload
+	}
+}
+
+func annotateEmailBody(body, note string, plainText bool) string {
+	if plainText {
+		return fmt.Sprintf("%s\n\n%s", note, body)
+	}
+
+	return fmt.Sprintf("<p><em>%s</em></p>\n%s", html.EscapeString(note), body)
+}
@@ -0,0 +1,38 @@
+package trigger
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnnotatePayload(t *testing.T) {
+	assert := assert.New(t)
+	change := "the task is now started"
+
+	email := &message.Email{Subject: "task failed", Body: "<p>details</p>"}
+	annotated := annotatePayload(email, change).(*message.Email)
+	assert.Equal("[Outdated] task failed", annotated.Subject)
+	assert.Equal("<p><em>This notification was delayed, and the task is now started.</em></p>\n<p>details</p>", annotated.Body)
+	assert.Equal("task failed", email.Subject)
+
+	plain := &util.EvergreenEmail{Email: message.Email{Subject: "task failed", Body: "details", PlainTextContents: true}}
+	annotatedPlain := annotatePayload(plain, change).(*util.EvergreenEmail)
+	assert.Equal("This notification was delayed, and the task is now started.\n\ndetails", annotatedPlain.Body)
+
+	slack := &notification.SlackPayload{
+		Body:   "task failed",
+		Blocks: []util.SlackBlock{{Type: util.SlackBlockDivider}},
+	}
+	annotatedSlack := annotatePayload(slack, change).(*notification.SlackPayload)
+	assert.Equal("_This notification was delayed, and the task is now started._\ntask failed", annotatedSlack.Body)
+	assert.Len(annotatedSlack.Blocks, 2)
+	assert.Equal(util.SlackBlockContext, annotatedSlack.Blocks[0].Type)
+	assert.Len(slack.Blocks, 1)
+
+	comment := "task failed"
+	assert.Equal(&comment, annotatePayload(&comment, change))
+}
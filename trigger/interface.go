@@ -27,6 +27,21 @@ type eventHandler interface {
 	ValidateTrigger(string) bool
 }
 
+// deliveryRenderer is implemented by event handlers whose notifications
+// can be rendered again when they are delivered after being held back, in
+// a digest or until their recipient's quiet hours end, against the current
+// state of the resource.
+type deliveryRenderer interface {
+	// changedSince describes how the fetched resource has changed since
+	// the event, or returns an empty string if it hasn't, and returns
+	// whether a failure that the event reported has since been fixed
+	changedSince() (string, bool, error)
+
+	// render renders the subscription's notification from the fetched
+	// resource without evaluating the trigger again
+	render(*event.Subscription) (*notification.Notification, error)
+}
+
 type trigger func(*event.Subscription) (*notification.Notification, error)
 
 type base struct {
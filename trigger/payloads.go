@@ -7,6 +7,7 @@ import (
 	"html/template"
 	"net/http"
 	"net/url"
+	"strings"
 	ttemplate "text/template"
 
 	"github.com/evergreen-ci/evergreen"
@@ -15,8 +16,10 @@ import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
@@ -39,6 +42,8 @@ type commonTemplateData struct {
 	ID              string
 	EventID         string
 	SubscriptionID  string
+	IncidentID      string
+	AcknowledgeURL  string
 	DisplayName     string
 	Object          string
 	Project         string
@@ -51,6 +56,7 @@ type commonTemplateData struct {
 	Task       *task.Task
 	ProjectRef *model.ProjectRef
 	Build      *build.Build
+	Tag        *version.TagMetadata
 
 	apiModel restModel.Model
 	slack    []message.SlackAttachment
@@ -59,6 +65,8 @@ type commonTemplateData struct {
 	githubState       message.GithubState
 	githubDescription string
 
+	githubCheckSummary string
+
 	emailContent *template.Template
 }
 
@@ -194,6 +202,7 @@ const emailTaskFailTemplate = `
   <td width="20"></td>
 </tr>
 </table>
+{{ if .AcknowledgeURL }}<p>If no one acknowledges this notification, it will be escalated. <a href="{{ .AcknowledgeURL }}">Acknowledge</a></p>{{ end }}
 {{ end }}`
 
 var emailBodyTemplate = template.Must(template.New("emailbody").Parse(emailBodyTemplateBase))
@@ -203,16 +212,19 @@ const emailDefaultContentTemplateString = `{{ define "content"}}
 
 <p>Your Evergreen {{ .Object }} in '{{ .Project }}' <a href="{{ .URL }}">{{ .DisplayName }}</a> has {{ .PastTenseStatus }}.</p>
 <p>{{ .Description }}</p>
+{{ if .Tag }}<p>Tag '{{ .Tag.Name }}'{{ if .Tag.Tagger }} by {{ .Tag.Tagger }}{{ end }}: {{ .Tag.Message }}</p>{{ end }}
+{{ if .IncidentID }}<p>This notification is part of incident {{ .IncidentID }}.</p>{{ end }}
+{{ if .AcknowledgeURL }}<p>If no one acknowledges this notification, it will be escalated. <a href="{{ .AcknowledgeURL }}">Acknowledge</a></p>{{ end }}
 {{ end }}`
 
 var emailDefaultContentTemplate = template.Must(template.New("content").Parse(emailDefaultContentTemplateString))
 var emailTaskContentTemplate = template.Must(template.New("content").Parse(emailTaskFailTemplate))
 
-const jiraCommentTemplate string = `Evergreen {{ .Object }} [{{ .DisplayName }}|{{ .URL }}] in '{{ .Project }}' has {{ .PastTenseStatus }}!`
+const jiraCommentTemplate string = `Evergreen {{ .Object }} [{{ .DisplayName }}|{{ .URL }}] in '{{ .Project }}' has {{ .PastTenseStatus }}!{{ if .IncidentID }} (incident {{ .IncidentID }}){{ end }}`
 
 const jiraIssueTitle string = "Evergreen {{ .Object }} '{{ .DisplayName }}' in '{{ .Project }}' has {{ .PastTenseStatus }}"
 
-const slackTemplate string = `The {{ .Object }} <{{ .URL }}|{{ .DisplayName }}> in '{{ .Project }}' has {{ .PastTenseStatus }}!`
+const slackTemplate string = `The {{ .Object }} <{{ .URL }}|{{ .DisplayName }}> in '{{ .Project }}' has {{ .PastTenseStatus }}!{{ if .IncidentID }} (incident {{ .IncidentID }}){{ end }}{{ if .AcknowledgeURL }} <{{ .AcknowledgeURL }}|Acknowledge>{{ end }}`
 
 func makeHeaders(selectors []event.Selector) http.Header {
 	headers := http.Header{}
@@ -345,6 +357,109 @@ func slack(t *commonTemplateData) (*notification.SlackPayload, error) {
 	}, nil
 }
 
+// opsgenie builds an alert for the object. Alerts are aliased by
+// subscription and object, so that Opsgenie deduplicates repeated alerts
+// about the same object while one is open.
+func opsgenie(t *commonTemplateData) (*util.OpsgenieAlert, error) {
+	msgTmpl, err := ttemplate.New("opsgenie").Parse(jiraIssueTitle)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse opsgenie template")
+	}
+
+	buf := &bytes.Buffer{}
+	if err = msgTmpl.Execute(buf, t); err != nil {
+		return nil, errors.Wrap(err, "failed to make opsgenie alert")
+	}
+	msg, remainder := truncateString(buf.String(), util.OpsgenieMaxMessageLength)
+	desc := t.URL
+	if len(remainder) != 0 {
+		desc = fmt.Sprintf("...%s\n%s", remainder, desc)
+	}
+	alias, _ := truncateString(fmt.Sprintf("evergreen-%s-%s-%s", t.SubscriptionID, t.Object, t.ID), util.OpsgenieMaxAliasLength)
+
+	l := level.Info
+	switch {
+	case t.PastTenseStatus == evergreen.TaskSystemFailed:
+		l = level.Critical
+	case strings.Contains(t.PastTenseStatus, evergreen.TaskFailed):
+		l = level.Error
+	}
+
+	return &util.OpsgenieAlert{
+		Message:     msg,
+		Alias:       alias,
+		Description: desc,
+		Priority:    util.OpsgeniePriority(l),
+		Tags:        []string{"evergreen", t.Object},
+		Details: map[string]string{
+			"project":         t.Project,
+			"status":          t.PastTenseStatus,
+			"url":             t.URL,
+			"event_id":        t.EventID,
+			"subscription_id": t.SubscriptionID,
+		},
+		Entity: t.DisplayName,
+		Source: "evergreen",
+	}, nil
+}
+
+// githubCheckRun builds a check run for the build, annotating the lines of
+// the tests that failed in it, where the test results have them.
+func githubCheckRun(t *commonTemplateData) (*util.GithubCheckRun, error) {
+	if t.Build == nil || len(t.githubCheckSummary) == 0 {
+		return nil, errors.New("Github check subscriber is only supported for build triggers")
+	}
+
+	tasks, err := task.Find(task.ByBuildId(t.Build.Id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find tasks for build '%s'", t.Build.Id)
+	}
+	tasks, err = task.MergeTestResultsBulk(tasks, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find test results for build '%s'", t.Build.Id)
+	}
+
+	annotations := []util.GithubCheckRunAnnotation{}
+	for _, tsk := range tasks {
+		for _, result := range tsk.LocalTestResults {
+			if len(annotations) == util.GithubCheckMaxAnnotations {
+				break
+			}
+			if result.Status != evergreen.TestFailedStatus || result.LineNum <= 0 {
+				continue
+			}
+			annotations = append(annotations, util.GithubCheckRunAnnotation{
+				Path:            result.TestFile,
+				StartLine:       result.LineNum,
+				EndLine:         result.LineNum,
+				AnnotationLevel: util.GithubAnnotationLevelFailure,
+				Title:           fmt.Sprintf("%s failed in %s", result.TestFile, tsk.DisplayName),
+				Message:         fmt.Sprintf("%s failed in task '%s'", result.TestFile, tsk.DisplayName),
+			})
+		}
+	}
+
+	conclusion := util.GithubCheckConclusionFailure
+	if t.Build.Status == evergreen.BuildSucceeded {
+		conclusion = util.GithubCheckConclusionSuccess
+	}
+	summary, _ := truncateString(t.githubCheckSummary, util.GithubCheckMaxSummaryLength)
+
+	return &util.GithubCheckRun{
+		Name:        fmt.Sprintf("evergreen/%s", t.Build.BuildVariant),
+		ExternalID:  t.Build.Id,
+		DetailsURL:  t.URL,
+		Conclusion:  conclusion,
+		StartedAt:   t.Build.StartTime,
+		CompletedAt: t.Build.FinishTime,
+		Output: util.GithubCheckRunOutput{
+			Title:       fmt.Sprintf("%s %s", t.Build.DisplayName, t.PastTenseStatus),
+			Summary:     summary,
+			Annotations: annotations,
+		},
+	}, nil
+}
+
 // truncateString splits a string into two parts, with the following behavior:
 // If the entire string is <= capacity, it's returned unchanged.
 // Otherwise, the string is split at the (capacity-3)'th byte. The first string
@@ -378,6 +493,15 @@ func makeCommonPayload(sub *event.Subscription, selectors []event.Selector,
 	data.Headers = makeHeaders(selectors)
 	data.SubscriptionID = sub.ID
 
+	incident, err := notification.FindOpenIncidentBySubscription(sub.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find open incident for subscription '%s'", sub.ID)
+	}
+	if incident != nil {
+		data.IncidentID = incident.ID
+		data.Headers[evergreenHeaderPrefix+"Incident-Id"] = []string{incident.ID}
+	}
+
 	if data.Task != nil {
 		for i := range data.Task.LocalTestResults {
 			if data.Task.LocalTestResults[i].Status == evergreen.TestFailedStatus {
@@ -416,6 +540,12 @@ func makeCommonPayload(sub *event.Subscription, selectors []event.Selector,
 
 	case event.SlackSubscriberType:
 		return slack(data)
+
+	case event.OpsgenieSubscriberType:
+		return opsgenie(data)
+
+	case event.GithubCheckSubscriberType:
+		return githubCheckRun(data)
 	}
 
 	return nil, errors.Errorf("unknown type: '%s'", sub.Subscriber.Type)
@@ -432,3 +562,7 @@ func buildLink(uiBase string, buildID string) string {
 func versionLink(uiBase string, versionID string) string {
 	return fmt.Sprintf("%s/version/%s/", uiBase, url.PathEscape(versionID))
 }
+
+func acknowledgeLink(uiBase string, notificationID string) string {
+	return fmt.Sprintf("%s/notifications/%s/acknowledge", uiBase, url.PathEscape(notificationID))
+}
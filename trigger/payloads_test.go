@@ -2,13 +2,18 @@ package trigger
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -128,6 +133,80 @@ func (s *payloadSuite) TestSlack() {
 	s.Empty(m.Attachments)
 }
 
+func (s *payloadSuite) TestOpsgenie() {
+	s.t.SubscriptionID = "sub1"
+	s.t.EventID = "event1"
+	m, err := opsgenie(&s.t)
+	s.NoError(err)
+	s.Require().NotNil(m)
+
+	s.Equal("Evergreen patch 'display-1234' in 'test' has failed", m.Message)
+	s.Equal("evergreen-sub1-patch-1234", m.Alias)
+	s.Equal(s.url, m.Description)
+	s.Equal(util.OpsgeniePriorityModerate, m.Priority)
+	s.Equal([]string{"evergreen", "patch"}, m.Tags)
+	s.Equal("failed", m.Details["status"])
+	s.Equal("event1", m.Details["event_id"])
+	s.Equal("display-1234", m.Entity)
+
+	s.t.PastTenseStatus = evergreen.TaskSystemFailed
+	m, err = opsgenie(&s.t)
+	s.NoError(err)
+	s.Equal(util.OpsgeniePriorityHigh, m.Priority)
+
+	s.t.PastTenseStatus = "succeeded"
+	s.t.DisplayName = strings.Repeat("a", util.OpsgenieMaxMessageLength)
+	m, err = opsgenie(&s.t)
+	s.NoError(err)
+	s.Equal(util.OpsgeniePriorityInfo, m.Priority)
+	s.Len(m.Message, util.OpsgenieMaxMessageLength)
+	s.True(strings.HasPrefix(m.Description, "..."))
+	s.True(strings.HasSuffix(m.Description, "\n"+s.url))
+}
+
+func (s *payloadSuite) TestGithubCheckRun() {
+	s.Require().NoError(db.ClearCollections(task.Collection, testresult.Collection))
+	defer func() {
+		s.NoError(db.ClearCollections(task.Collection, testresult.Collection))
+	}()
+	s.Require().NoError((&task.Task{Id: "t1", BuildId: "b1", DisplayName: "compile"}).Insert())
+	s.Require().NoError((&task.Task{Id: "t2", BuildId: "b1", DisplayName: "test"}).Insert())
+	s.Require().NoError((&testresult.TestResult{TaskID: "t2", TestFile: "main_test.go", LineNum: 12, Status: evergreen.TestFailedStatus}).Insert())
+	s.Require().NoError((&testresult.TestResult{TaskID: "t2", TestFile: "other_test.go", Status: evergreen.TestFailedStatus}).Insert())
+	s.Require().NoError((&testresult.TestResult{TaskID: "t2", TestFile: "util_test.go", LineNum: 3, Status: evergreen.TestSucceededStatus}).Insert())
+
+	_, err := githubCheckRun(&s.t)
+	s.Error(err)
+
+	s.t.Object = objectBuild
+	s.t.Build = &build.Build{
+		Id:           "b1",
+		BuildVariant: "ubuntu",
+		DisplayName:  "Ubuntu",
+		Status:       evergreen.BuildFailed,
+	}
+	s.t.githubCheckSummary = "1 failed, 1 succeeded"
+	m, err := githubCheckRun(&s.t)
+	s.NoError(err)
+	s.Require().NotNil(m)
+
+	s.Equal("evergreen/ubuntu", m.Name)
+	s.Equal("b1", m.ExternalID)
+	s.Equal(s.url, m.DetailsURL)
+	s.Equal(util.GithubCheckConclusionFailure, m.Conclusion)
+	s.Equal("Ubuntu failed", m.Output.Title)
+	s.Equal("1 failed, 1 succeeded", m.Output.Summary)
+	s.Require().Len(m.Output.Annotations, 1)
+	s.Equal("main_test.go", m.Output.Annotations[0].Path)
+	s.Equal(12, m.Output.Annotations[0].StartLine)
+	s.Equal(util.GithubAnnotationLevelFailure, m.Output.Annotations[0].AnnotationLevel)
+
+	s.t.Build.Status = evergreen.BuildSucceeded
+	m, err = githubCheckRun(&s.t)
+	s.NoError(err)
+	s.Equal(util.GithubCheckConclusionSuccess, m.Conclusion)
+}
+
 func TestTruncateString(t *testing.T) {
 	assert := assert.New(t)
 
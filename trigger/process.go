@@ -3,6 +3,7 @@ package trigger
 import (
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
@@ -46,10 +47,21 @@ func NotificationsFromEvent(e *event.EventLogEntry) ([]notification.Notification
 	}
 
 	notifications := make([]notification.Notification, 0, len(subscriptions))
+	generated := map[string]int{}
+	project := selectorData(h.Selectors(), selectorProject)
+	recipients := map[string]*user.DBUser{}
 
 	catcher := grip.NewSimpleCatcher()
 	for i := range subscriptions {
-		n, err := h.Process(&subscriptions[i])
+		sub := subscriptions[i]
+		recipient, err := subscriptionRecipient(&sub, recipients)
+		if err != nil {
+			catcher.Add(err)
+		} else if recipient != nil {
+			sub.Subscriber = recipient.PreferredSubscriber(sub.Subscriber, project)
+		}
+
+		n, err := h.Process(&sub)
 		msg := message.Fields{
 			"source":              "events-processing",
 			"message":             "processing subscription",
@@ -57,7 +69,7 @@ func NotificationsFromEvent(e *event.EventLogEntry) ([]notification.Notification
 			"event_type":          e.EventType,
 			"event_resource_type": e.ResourceType,
 			"event_resource":      e.ResourceId,
-			"subscription_id":     subscriptions[i].ID,
+			"subscription_id":     sub.ID,
 			"notification_is_nil": n == nil,
 		}
 		catcher.Add(err)
@@ -67,8 +79,64 @@ func NotificationsFromEvent(e *event.EventLogEntry) ([]notification.Notification
 			continue
 		}
 
+		n.SubscriptionID = sub.ID
+		n.Initiator = sub.Owner
+		n.EventID = e.ID
+		n.Project = project
+		n.Digest = sub.Digest
+		if recipient != nil {
+			n.Recipient = recipient.Id
+		}
+		if err = n.AttachToOpenIncident(); err != nil {
+			catcher.Add(err)
+			grip.Error(message.WrapError(err, msg))
+		}
+
+		// overlapping subscriptions can generate the same notification,
+		// such as a committer's build break subscription and the project's
+		// escalating one, so only one of them is kept, preferring the one
+		// that escalates
+		if idx, ok := generated[n.ID]; ok {
+			if notifications[idx].EscalationID == "" {
+				notifications[idx] = *n
+			}
+			continue
+		}
+		generated[n.ID] = len(notifications)
 		notifications = append(notifications, *n)
 	}
 
 	return notifications, catcher.Resolve()
 }
+
+// subscriptionRecipient returns the user who owns the subscription, whose
+// delivery preferences apply to its notifications, or nil if a user does
+// not own it. Users are cached in recipients.
+func subscriptionRecipient(sub *event.Subscription, recipients map[string]*user.DBUser) (*user.DBUser, error) {
+	if sub.OwnerType != event.OwnerTypePerson || sub.Owner == "" {
+		return nil, nil
+	}
+	if u, ok := recipients[sub.Owner]; ok {
+		return u, nil
+	}
+
+	u, err := user.FindOne(user.ById(sub.Owner))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error fetching owner '%s' of subscription '%s'", sub.Owner, sub.ID)
+	}
+	recipients[sub.Owner] = u
+
+	return u, nil
+}
+
+// selectorData returns the data of the first selector of the type, or an
+// empty string if there is none.
+func selectorData(selectors []event.Selector, selectorType string) string {
+	for _, s := range selectors {
+		if s.Type == selectorType {
+			return s.Data
+		}
+	}
+
+	return ""
+}
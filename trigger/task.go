@@ -2,6 +2,7 @@ package trigger
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
 	"github.com/evergreen-ci/evergreen/model/version"
 	restModel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
@@ -35,7 +37,19 @@ const (
 	triggerTaskFirstFailureInVersion         = "first-failure-in-version"
 	triggerTaskFirstFailureInVersionWithName = "first-failure-in-version-with-name"
 	triggerTaskRegressionByTest              = "regression-by-test"
+	triggerTaskRuntimeRegression             = "runtime-regression"
+	triggerTaskStreakBroken                  = "streak-broken"
 	triggerBuildBreak                        = "build-break"
+
+	// defaultRuntimeBaselineVersions is the number of mainline versions
+	// whose runtimes are averaged into a task's baseline runtime, and
+	// minRuntimeBaselineVersions is the fewest that make a baseline
+	defaultRuntimeBaselineVersions = 10
+	minRuntimeBaselineVersions     = 3
+
+	// defaultStreakLength is the number of consecutive passing mainline
+	// runs that a failure must follow to break a streak
+	defaultStreakLength = 10
 )
 
 func makeTaskTriggers() eventHandler {
@@ -53,6 +67,8 @@ func makeTaskTriggers() eventHandler {
 		triggerRuntimeChangeByPercent:            t.taskRuntimeChange,
 		triggerRegression:                        t.taskRegression,
 		triggerTaskRegressionByTest:              t.taskRegressionByTest,
+		triggerTaskRuntimeRegression:             t.taskRuntimeRegression,
+		triggerTaskStreakBroken:                  t.taskStreakBroken,
 		triggerBuildBreak:                        t.buildBreak,
 	}
 
@@ -140,7 +156,8 @@ type taskTriggers struct {
 }
 
 func (t *taskTriggers) Process(sub *event.Subscription) (*notification.Notification, error) {
-	if t.task.DisplayOnly {
+	// an automatically retried execution isn't the task's outcome
+	if t.task.DisplayOnly || t.task.AutoRetried {
 		return nil, nil
 	}
 
@@ -269,6 +286,9 @@ func (t *taskTriggers) makeData(sub *event.Subscription, pastTenseOverride strin
 		ProjectRef:      projectRef,
 		Build:           buildDoc,
 	}
+	if sub.Escalation != nil {
+		data.AcknowledgeURL = acknowledgeLink(t.uiConfig.Url, notification.MakeID(t.event.ID, sub.Trigger, &sub.Subscriber))
+	}
 	slackColor := evergreenFailColor
 
 	if len(t.task.OldTaskId) != 0 {
@@ -327,6 +347,26 @@ func (t *taskTriggers) generate(sub *event.Subscription, pastTenseOverride strin
 	return notification.New(t.event.ID, sub.Trigger, &sub.Subscriber, payload)
 }
 
+func (t *taskTriggers) changedSince() (string, bool, error) {
+	latest, err := task.FindOneId(t.task.Id)
+	if err != nil {
+		return "", false, errors.Wrapf(err, "failed to fetch task '%s'", t.task.Id)
+	}
+	if latest == nil || (latest.Execution == t.data.Execution && latest.Status == t.data.Status) {
+		return "", false, nil
+	}
+
+	fixed := isFailedTaskStatus(t.data.Status) && latest.Status == evergreen.TaskSucceeded
+	if latest.Execution != t.data.Execution {
+		return fmt.Sprintf("the task has since been restarted, and execution %d is %s", latest.Execution, latest.Status), fixed, nil
+	}
+	return fmt.Sprintf("the task is now %s", latest.Status), fixed, nil
+}
+
+func (t *taskTriggers) render(sub *event.Subscription) (*notification.Notification, error) {
+	return t.generate(sub, "")
+}
+
 func (t *taskTriggers) generateWithAlertRecord(sub *event.Subscription, alertType, pastTenseOverride string) (*notification.Notification, error) {
 	n, err := t.generate(sub, pastTenseOverride)
 	if err != nil {
@@ -579,6 +619,123 @@ func (t *taskTriggers) taskRuntimeChange(sub *event.Subscription) (*notification
 	return t.generate(sub, fmt.Sprintf("changed in runtime by %.1f%% (over threshold of %s%%)", percentChange, percentString))
 }
 
+// taskRuntimeRegression notifies when a successful task takes longer than
+// its average runtime in recent mainline versions by at least the
+// subscription's percentage.
+func (t *taskTriggers) taskRuntimeRegression(sub *event.Subscription) (*notification.Notification, error) {
+	if t.task.Status != evergreen.TaskSucceeded {
+		return nil, nil
+	}
+
+	percentString, ok := sub.TriggerData[event.TaskPercentChangeKey]
+	if !ok {
+		return nil, errors.Errorf("subscription %s has no percentage increase", sub.ID)
+	}
+	percent, err := strconv.ParseFloat(percentString, 64)
+	if err != nil {
+		return nil, errors.Errorf("subscription %s has an invalid percentage", sub.ID)
+	}
+	versions := defaultRuntimeBaselineVersions
+	if versionsString, ok := sub.TriggerData[event.TaskBaselineVersionsKey]; ok {
+		versions, err = strconv.Atoi(versionsString)
+		if err != nil || versions <= 0 {
+			return nil, errors.Errorf("subscription %s has an invalid number of baseline versions", sub.ID)
+		}
+	}
+
+	baseline, samples, err := mainlineRuntimeBaseline(t.task, versions)
+	if err != nil {
+		return nil, errors.Wrap(err, "error computing baseline runtime")
+	}
+	if samples < minRuntimeBaselineVersions && samples < versions {
+		return nil, nil
+	}
+	runtime := t.task.FinishTime.Sub(t.task.StartTime)
+	increase := 100 * (float64(runtime)/float64(baseline) - 1)
+	if !util.IsFiniteNumericFloat(increase) || increase < percent {
+		return nil, nil
+	}
+
+	return t.generate(sub, fmt.Sprintf("took %.1f%% longer than its average of %s over the last %d mainline versions (over threshold of %s%%)",
+		increase, baseline.Round(time.Second), samples, percentString))
+}
+
+// taskStreakBroken notifies when a mainline task fails after passing in
+// each of the subscription's number of mainline versions before it, so
+// that only new failures are notified.
+func (t *taskTriggers) taskStreakBroken(sub *event.Subscription) (*notification.Notification, error) {
+	if !isFailedTaskStatus(t.data.Status) || t.task.Requester != evergreen.RepotrackerVersionRequester {
+		return nil, nil
+	}
+
+	streak := defaultStreakLength
+	if streakString, ok := sub.TriggerData[event.TaskStreakLengthKey]; ok {
+		var err error
+		streak, err = strconv.Atoi(streakString)
+		if err != nil || streak <= 0 {
+			return nil, errors.Errorf("subscription %s has an invalid streak length", sub.ID)
+		}
+	}
+
+	query := task.ByBeforeRevisionWithStatusesAndRequesters(t.task.RevisionOrderNumber, task.CompletedStatuses,
+		t.task.BuildVariant, t.task.DisplayName, t.task.Project, []string{evergreen.RepotrackerVersionRequester})
+	previous, err := task.Find(query.WithFields(task.StatusKey).Limit(streak))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding previous runs of task '%s'", t.task.Id)
+	}
+	if len(previous) < streak {
+		return nil, nil
+	}
+	for _, p := range previous {
+		if p.Status != evergreen.TaskSucceeded {
+			return nil, nil
+		}
+	}
+
+	rec, err := alertrecord.FindOne(alertrecord.ByStreakBroken(sub.ID, t.task.Id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch alertrecord (%s)", triggerTaskStreakBroken)
+	}
+	if rec != nil {
+		return nil, nil
+	}
+
+	return t.generateWithAlertRecord(sub, alertrecord.TaskStreakBrokenId, fmt.Sprintf("failed after passing %d consecutive times", streak))
+}
+
+// mainlineRuntimeBaseline returns the average runtime of the task's most
+// recent successful runs in at most the given number of mainline versions
+// before it, and the number of runs averaged.
+func mainlineRuntimeBaseline(t *task.Task, versions int) (time.Duration, int, error) {
+	revision := t.RevisionOrderNumber
+	if t.Requester != evergreen.RepotrackerVersionRequester {
+		// the revision order numbers of other versions aren't comparable
+		// to mainline versions', so compare with the latest ones
+		revision = math.MaxInt32
+	}
+	query := task.ByBeforeRevisionWithStatusesAndRequesters(revision, []string{evergreen.TaskSucceeded},
+		t.BuildVariant, t.DisplayName, t.Project, []string{evergreen.RepotrackerVersionRequester})
+	previous, err := task.Find(query.WithFields(task.StartTimeKey, task.FinishTimeKey).Limit(versions))
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "error finding previous runs of task '%s'", t.Id)
+	}
+
+	var total time.Duration
+	samples := 0
+	for _, p := range previous {
+		if p.StartTime.IsZero() || p.FinishTime.Before(p.StartTime) {
+			continue
+		}
+		total += p.FinishTime.Sub(p.StartTime)
+		samples++
+	}
+	if samples == 0 {
+		return 0, 0, nil
+	}
+
+	return total / time.Duration(samples), samples, nil
+}
+
 func isFailedTaskStatus(status string) bool {
 	return status == evergreen.TaskFailed || status == evergreen.TaskSystemFailed || status == evergreen.TaskTestTimedOut
 }
@@ -609,6 +766,13 @@ func testMatchesRegex(testName string, sub *event.Subscription) bool {
 	return match
 }
 
+// ignoresFlakyTests returns whether the subscription doesn't want to hear
+// about failures of tests that are known to be flaky.
+func ignoresFlakyTests(sub *event.Subscription) bool {
+	ignore, _ := strconv.ParseBool(sub.TriggerData[event.IgnoreFlakyTestsKey])
+	return ignore
+}
+
 func (t *taskTriggers) shouldIncludeTest(sub *event.Subscription, previousTask *task.Task, test *task.TestResult) (bool, error) {
 	if test.Status != evergreen.TestFailedStatus {
 		return false, nil
@@ -666,11 +830,22 @@ func (t *taskTriggers) taskRegressionByTest(sub *event.Subscription) (*notificat
 		t.oldTestResults = mapTestResultsByTestFile(previousCompleteTask)
 	}
 
+	flakiness := map[string]testflakiness.TestFlakiness{}
+	if ignoresFlakyTests(sub) {
+		flakiness, err = testflakiness.FindByTask(t.task.Project, t.task.BuildVariant, t.task.DisplayName)
+		if err != nil {
+			return nil, errors.Wrap(err, "error fetching test flakiness")
+		}
+	}
+
 	testsToAlert := []task.TestResult{}
 	for i := range t.task.LocalTestResults {
 		if !testMatchesRegex(t.task.LocalTestResults[i].TestFile, sub) {
 			continue
 		}
+		if stat, ok := flakiness[t.task.LocalTestResults[i].TestFile]; ok && stat.IsFlaky() {
+			continue
+		}
 		var shouldInclude bool
 		shouldInclude, err = t.shouldIncludeTest(sub, previousCompleteTask, &t.task.LocalTestResults[i])
 		if err != nil {
@@ -822,5 +997,47 @@ func (t *taskTriggers) buildBreak(sub *event.Subscription) (*notification.Notifi
 	if err != nil {
 		return nil, err
 	}
-	return n, nil
+	if n != nil && sub.Escalation != nil {
+		// the notification is sent even if it can't be escalated
+		err = t.escalate(sub, n, "caused a regression")
+	}
+	return n, err
+}
+
+// escalate renders the notifications to each of the subscription's
+// escalation tiers, and records the escalation that sends them in turn
+// until one of them, or n, is acknowledged.
+func (t *taskTriggers) escalate(sub *event.Subscription, n *notification.Notification, pastTenseOverride string) error {
+	tiers := make([]notification.EscalationTier, 0, len(sub.Escalation.Tiers))
+	for _, tier := range sub.Escalation.Tiers {
+		notifications := make([]notification.Notification, 0, len(tier.Subscribers))
+		for _, subscriber := range tier.Subscribers {
+			tierSub := *sub
+			tierSub.Subscriber = subscriber
+			tierN, err := t.generate(&tierSub, pastTenseOverride)
+			if err != nil {
+				return errors.Wrapf(err, "failed to build notification for escalation tier '%s'", tier.Name)
+			}
+			tierN.SubscriptionID = sub.ID
+			tierN.Initiator = sub.Owner
+			tierN.Project = t.task.Project
+			notifications = append(notifications, *tierN)
+		}
+		tiers = append(tiers, notification.EscalationTier{
+			Name:          tier.Name,
+			Notifications: notifications,
+		})
+	}
+
+	n.SubscriptionID = sub.ID
+	e, err := notification.NewEscalation(n, time.Duration(sub.Escalation.Delay)*time.Minute, tiers)
+	if err != nil {
+		return errors.Wrap(err, "failed to create notification escalation")
+	}
+	if err = e.Insert(); err != nil {
+		n.EscalationID = ""
+		return errors.Wrapf(err, "failed to escalate notification '%s'", n.ID)
+	}
+
+	return nil
 }
@@ -12,10 +12,13 @@ import (
 	"github.com/evergreen-ci/evergreen/model/alertrecord"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
 	"github.com/evergreen-ci/evergreen/model/testresult"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/mongodb/grip/message"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
@@ -712,6 +715,46 @@ func (s *taskSuite) TestRegressionByTestWithDuplicateTestNames() {
 	s.tryDoubleTrigger(true)
 }
 
+func (s *taskSuite) TestRegressionByTestIgnoresFlakyTests() {
+	s.NoError(db.ClearCollections(task.Collection, testresult.Collection, testflakiness.Collection))
+	s.NoError(testflakiness.ReplaceProject(s.task.Project, []testflakiness.TestFlakiness{
+		{
+			Id: testflakiness.TestFlakinessKey{
+				Project: s.task.Project,
+				Variant: s.task.BuildVariant,
+				Task:    s.task.DisplayName,
+				Test:    "flaky",
+			},
+			Runs:      10,
+			Flakes:    5,
+			FlakeRate: 0.5,
+		},
+	}))
+
+	sub := s.subs[2]
+	sub.TriggerData = map[string]string{event.IgnoreFlakyTestsKey: "true"}
+
+	s.makeTask(1, evergreen.TaskFailed)
+	s.makeTest(1, 0, "flaky", evergreen.TestFailedStatus)
+	s.t = s.makeTaskTriggers(s.task.Id, s.task.Execution)
+	n, err := s.t.taskRegressionByTest(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// other subscriptions still hear about the flaky test
+	n, err = s.t.taskRegressionByTest(&s.subs[2])
+	s.NoError(err)
+	s.NotNil(n)
+
+	s.makeTask(2, evergreen.TaskFailed)
+	s.makeTest(2, 0, "flaky", evergreen.TestFailedStatus)
+	s.makeTest(2, 0, "broken", evergreen.TestFailedStatus)
+	s.t = s.makeTaskTriggers(s.task.Id, s.task.Execution)
+	n, err = s.t.taskRegressionByTest(&sub)
+	s.NoError(err)
+	s.NotNil(n)
+}
+
 func (s *taskSuite) TestRegressionByTestWithRegex() {
 	sub := event.Subscription{
 		ID:           bson.NewObjectId().Hex(),
@@ -937,6 +980,195 @@ func (s *taskSuite) TestTaskRuntimeChange() {
 	s.NotNil(n)
 }
 
+func (s *taskSuite) TestTaskRuntimeRegression() {
+	sub := event.Subscription{
+		ID:           bson.NewObjectId().Hex(),
+		ResourceType: event.ResourceTypeTask,
+		Trigger:      triggerTaskRuntimeRegression,
+		Selectors: []event.Selector{
+			{
+				Type: "project",
+				Data: "test_project",
+			},
+		},
+		Subscriber: event.Subscriber{
+			Type:   event.JIRACommentSubscriberType,
+			Target: "A-1",
+		},
+		Owner: "someone",
+		TriggerData: map[string]string{
+			event.TaskPercentChangeKey:    "50",
+			event.TaskBaselineVersionsKey: "3",
+		},
+	}
+	s.t.event = &event.EventLogEntry{
+		EventType: event.TaskFinished,
+	}
+	s.t.task.Status = evergreen.TaskSucceeded
+
+	// too few previous runs should not generate
+	n, err := s.t.taskRuntimeRegression(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// the baseline averages the most recent mainline runs
+	for i, runtime := range []time.Duration{10 * time.Minute, 12 * time.Minute, 14 * time.Minute, time.Minute} {
+		previous := task.Task{
+			Id:                  fmt.Sprintf("previous%d", i),
+			BuildVariant:        "test_build_variant",
+			Project:             "test_project",
+			DisplayName:         "test-display-name",
+			StartTime:           s.task.StartTime.Add(-time.Hour),
+			RevisionOrderNumber: -i,
+			Status:              evergreen.TaskSucceeded,
+			Requester:           evergreen.RepotrackerVersionRequester,
+		}
+		previous.FinishTime = previous.StartTime.Add(runtime)
+		s.NoError(previous.Insert())
+	}
+	patchRun := task.Task{
+		Id:                  "patch",
+		BuildVariant:        "test_build_variant",
+		Project:             "test_project",
+		DisplayName:         "test-display-name",
+		StartTime:           s.task.StartTime.Add(-time.Hour),
+		RevisionOrderNumber: 0,
+		Status:              evergreen.TaskSucceeded,
+		Requester:           evergreen.PatchVersionRequester,
+	}
+	patchRun.FinishTime = patchRun.StartTime.Add(time.Hour)
+	s.NoError(patchRun.Insert())
+
+	baseline, samples, err := mainlineRuntimeBaseline(s.t.task, 3)
+	s.NoError(err)
+	s.Equal(3, samples)
+	s.Equal(12*time.Minute, baseline)
+
+	// 20 minutes is more than 50% over the 12 minute baseline
+	n, err = s.t.taskRuntimeRegression(&sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	// 15 minutes is not
+	s.task.FinishTime = s.task.StartTime.Add(15 * time.Minute)
+	n, err = s.t.taskRuntimeRegression(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// failed tasks should not generate
+	s.task.FinishTime = s.task.StartTime.Add(time.Hour)
+	s.t.task.Status = evergreen.TaskFailed
+	n, err = s.t.taskRuntimeRegression(&sub)
+	s.NoError(err)
+	s.Nil(n)
+}
+
+func (s *taskSuite) TestTaskStreakBroken() {
+	sub := &s.subs[2]
+	sub.TriggerData = map[string]string{event.TaskStreakLengthKey: "3"}
+	s.data.Status = evergreen.TaskFailed
+	s.task.Status = evergreen.TaskFailed
+
+	insertPrevious := func(i int, status string) {
+		previous := task.Task{
+			Id:                  fmt.Sprintf("previous%d", i),
+			BuildVariant:        "test_build_variant",
+			Project:             "test_project",
+			DisplayName:         "test-display-name",
+			RevisionOrderNumber: -i,
+			Status:              status,
+			Requester:           evergreen.RepotrackerVersionRequester,
+		}
+		s.NoError(previous.Insert())
+	}
+
+	// a shorter streak should not generate
+	insertPrevious(0, evergreen.TaskSucceeded)
+	insertPrevious(1, evergreen.TaskSucceeded)
+	n, err := s.t.taskStreakBroken(sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// a failure in the streak should not generate
+	insertPrevious(2, evergreen.TaskFailed)
+	n, err = s.t.taskStreakBroken(sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// failing after enough passes should generate once
+	s.NoError(db.Update(task.Collection, bson.M{"_id": "previous2"}, bson.M{"$set": bson.M{task.StatusKey: evergreen.TaskSucceeded}}))
+	n, err = s.t.taskStreakBroken(sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	n, err = s.t.taskStreakBroken(sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// patches should not generate
+	s.task.Id = "patch"
+	s.task.Requester = evergreen.PatchVersionRequester
+	n, err = s.t.taskStreakBroken(sub)
+	s.NoError(err)
+	s.Nil(n)
+}
+
+func (s *taskSuite) TestBuildBreakEscalation() {
+	s.NoError(db.ClearCollections(notification.EscalationsCollection))
+	lastGreen := task.Task{
+		Id:                  "test1",
+		BuildVariant:        "test_build_variant",
+		Project:             "test_project",
+		DisplayName:         "test-display-name",
+		RevisionOrderNumber: -1,
+		Status:              evergreen.TaskSucceeded,
+		Requester:           evergreen.RepotrackerVersionRequester,
+	}
+	s.NoError(lastGreen.Insert())
+
+	sub := event.NewBuildBreakSubscriptionByOwner("me", event.Subscriber{
+		Type:   event.EmailSubscriberType,
+		Target: "committer@example.com",
+	})
+	sub.Escalation = &event.Escalation{
+		Delay: 30,
+		Tiers: []event.EscalationTier{
+			{
+				Name:        "team",
+				Subscribers: []event.Subscriber{{Type: event.SlackSubscriberType, Target: "#team"}},
+			},
+			{
+				Name:        "admins",
+				Subscribers: []event.Subscriber{{Type: event.EmailSubscriberType, Target: "admin@example.com"}},
+			},
+		},
+	}
+	s.task.Status = evergreen.TaskFailed
+
+	n, err := s.t.buildBreak(&sub)
+	s.NoError(err)
+	s.Require().NotNil(n)
+	s.NotEmpty(n.EscalationID)
+	email, ok := n.Payload.(*message.Email)
+	s.Require().True(ok)
+	s.Contains(email.Body, "/acknowledge")
+
+	e, err := notification.FindEscalation(n.EscalationID)
+	s.NoError(err)
+	s.Require().NotNil(e)
+	s.Equal([]string{n.ID}, e.NotificationIDs)
+	s.Equal(n.CreatedAt.Add(30*time.Minute).Unix(), e.EscalateAt.Unix())
+	s.Require().Len(e.Tiers, 2)
+	s.Equal("team", e.Tiers[0].Name)
+	s.Require().Len(e.Tiers[0].Notifications, 1)
+	team := e.Tiers[0].Notifications[0]
+	s.Equal(e.ID, team.EscalationID)
+	s.Equal(sub.ID, team.SubscriptionID)
+	slack, ok := team.Payload.(*notification.SlackPayload)
+	s.Require().True(ok)
+	s.Contains(slack.Body, "|Acknowledge>")
+}
+
 func (s *taskSuite) TestProjectTrigger() {
 	lastGreen := task.Task{
 		Id:                  "test1",
@@ -3,6 +3,7 @@ package trigger
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
@@ -22,6 +23,7 @@ const (
 
 func init() {
 	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionStateChange, makeVersionTriggers)
+	registry.registerEventHandler(event.ResourceTypeVersion, event.VersionConfigWarnings, makeVersionTriggers)
 }
 
 type versionTriggers struct {
@@ -42,6 +44,8 @@ func makeVersionTriggers() eventHandler {
 		triggerRegression:             t.versionRegression,
 		triggerExceedsDuration:        t.versionExceedsDuration,
 		triggerRuntimeChangeByPercent: t.versionRuntimeChange,
+		triggerStatusTransition:       t.versionStatusTransition,
+		triggerConfigWarnings:         t.versionConfigWarnings,
 	}
 	return t
 }
@@ -89,6 +93,7 @@ func (t *versionTriggers) makeData(sub *event.Subscription, pastTenseOverride st
 		Project:         t.version.Identifier,
 		URL:             versionLink(t.uiConfig.Url, t.version.Id),
 		PastTenseStatus: t.data.Status,
+		Tag:             t.version.Tag,
 		apiModel:        &api,
 	}
 	slackColor := evergreenFailColor
@@ -125,6 +130,19 @@ func (t *versionTriggers) generate(sub *event.Subscription, pastTenseOverride st
 	return notification.New(t.event.ID, sub.Trigger, &sub.Subscriber, payload)
 }
 
+func (t *versionTriggers) changedSince() (string, bool, error) {
+	if t.data.Status == "" || t.version.Status == t.data.Status {
+		return "", false, nil
+	}
+
+	fixed := t.data.Status == evergreen.VersionFailed && t.version.Status == evergreen.VersionSucceeded
+	return fmt.Sprintf("the version is now %s", t.version.Status), fixed, nil
+}
+
+func (t *versionTriggers) render(sub *event.Subscription) (*notification.Notification, error) {
+	return t.generate(sub, "")
+}
+
 func (t *versionTriggers) versionOutcome(sub *event.Subscription) (*notification.Notification, error) {
 	if t.data.Status != evergreen.VersionSucceeded && t.data.Status != evergreen.VersionFailed {
 		return nil, nil
@@ -220,6 +238,61 @@ func (t *versionTriggers) versionRegression(sub *event.Subscription) (*notificat
 	return nil, nil
 }
 
+// versionStatusTransition notifies when the latest finished mainline version
+// of a project changes the health of the branch, i.e. when it fails after
+// the previous version succeeded, or succeeds after the previous one failed.
+func (t *versionTriggers) versionStatusTransition(sub *event.Subscription) (*notification.Notification, error) {
+	if t.data.Status != evergreen.VersionSucceeded && t.data.Status != evergreen.VersionFailed {
+		return nil, nil
+	}
+	if !util.StringSliceContains(evergreen.SystemVersionRequesterTypes, t.version.Requester) {
+		return nil, nil
+	}
+
+	// a newer version already determines the state of the branch
+	newer, err := version.Count(version.ByFinishedAfterRevision(t.version.Identifier, t.version.RevisionOrderNumber))
+	if err != nil {
+		return nil, errors.Wrap(err, "error counting newer versions")
+	}
+	if newer > 0 {
+		return nil, nil
+	}
+
+	previous, err := version.FindOne(version.ByFinishedBeforeRevision(t.version.Identifier, t.version.RevisionOrderNumber))
+	if err != nil {
+		return nil, errors.Wrap(err, "error retrieving previous version")
+	}
+	if previous == nil || previous.Status == t.data.Status {
+		return nil, nil
+	}
+
+	return t.generate(sub, fmt.Sprintf("transitioned from %s to %s", previous.Status, t.data.Status))
+}
+
+// versionConfigWarnings notifies about the project configuration warnings
+// logged for the version. Whether warnings inherited from the previous
+// version are included is decided when the event is logged.
+func (t *versionTriggers) versionConfigWarnings(sub *event.Subscription) (*notification.Notification, error) {
+	if t.event.EventType != event.VersionConfigWarnings || len(t.data.Warnings) == 0 {
+		return nil, nil
+	}
+
+	data, err := t.makeData(sub, fmt.Sprintf("introduced %d project configuration warning(s)", len(t.data.Warnings)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to collect version data")
+	}
+	data.Description = strings.Join(t.data.Warnings, "\n")
+	data.slack[0].Color = evergreenFailColor
+	data.slack[0].Text = data.Description
+
+	payload, err := makeCommonPayload(sub, t.Selectors(), data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build notification")
+	}
+
+	return notification.New(t.event.ID, sub.Trigger, &sub.Subscriber, payload)
+}
+
 func MakeVersionSelectors(v version.Version) []event.Selector {
 	selectors := []event.Selector{
 		{
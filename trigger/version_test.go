@@ -329,3 +329,75 @@ func (s *VersionSuite) TestVersionRuntimeChange() {
 	s.NoError(err)
 	s.NotNil(n)
 }
+
+func (s *VersionSuite) TestVersionStatusTransition() {
+	sub := event.NewSubscriptionByID(event.ResourceTypeVersion, triggerStatusTransition, s.event.ResourceId, s.subs[0].Subscriber)
+
+	// no previous version should not generate
+	s.t.data.Status = evergreen.VersionFailed
+	n, err := s.t.versionStatusTransition(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	previous := version.Version{
+		Id:                  "previous",
+		RevisionOrderNumber: 1,
+		Identifier:          s.version.Identifier,
+		Status:              evergreen.VersionSucceeded,
+		Requester:           evergreen.RepotrackerVersionRequester,
+	}
+	s.NoError(previous.Insert())
+
+	// green to red should generate
+	n, err = s.t.versionStatusTransition(&sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	// green to green should not generate
+	s.t.data.Status = evergreen.VersionSucceeded
+	n, err = s.t.versionStatusTransition(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// unfinished version should not generate
+	s.t.data.Status = evergreen.VersionStarted
+	n, err = s.t.versionStatusTransition(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	// a version that is no longer the latest should not generate
+	newer := version.Version{
+		Id:                  "newer",
+		RevisionOrderNumber: 3,
+		Identifier:          s.version.Identifier,
+		Status:              evergreen.VersionFailed,
+		Requester:           evergreen.RepotrackerVersionRequester,
+	}
+	s.NoError(newer.Insert())
+	s.t.data.Status = evergreen.VersionFailed
+	n, err = s.t.versionStatusTransition(&sub)
+	s.NoError(err)
+	s.Nil(n)
+}
+
+func (s *VersionSuite) TestVersionConfigWarnings() {
+	sub := event.NewSubscriptionByID(event.ResourceTypeVersion, triggerConfigWarnings, s.event.ResourceId, s.subs[0].Subscriber)
+
+	// state change events should not generate
+	s.t.data.Warnings = []string{"new warning"}
+	n, err := s.t.versionConfigWarnings(&sub)
+	s.NoError(err)
+	s.Nil(n)
+
+	s.t.event.EventType = event.VersionConfigWarnings
+	s.t.data.Status = ""
+	n, err = s.t.versionConfigWarnings(&sub)
+	s.NoError(err)
+	s.NotNil(n)
+
+	// no warnings should not generate
+	s.t.data.Warnings = nil
+	n, err = s.t.versionConfigWarnings(&sub)
+	s.NoError(err)
+	s.Nil(n)
+}
@@ -0,0 +1,119 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/goamz/goamz/aws"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	artifactExpirationJobName = "artifact-expiration"
+
+	// artifactExpirationBatchSize is the most task entries whose expired
+	// files a single job deletes.
+	artifactExpirationBatchSize = 500
+)
+
+func init() {
+	registry.AddJobType(artifactExpirationJobName, func() amboy.Job {
+		return makeArtifactExpirationJob()
+	})
+}
+
+type artifactExpirationJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	env evergreen.Environment
+}
+
+func makeArtifactExpirationJob() *artifactExpirationJob {
+	j := &artifactExpirationJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    artifactExpirationJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewArtifactExpirationJob creates a job that deletes the files uploaded to
+// the artifacts bucket that have expired, and removes them from their tasks.
+func NewArtifactExpirationJob(env evergreen.Environment, id string) amboy.Job {
+	j := makeArtifactExpirationJob()
+	j.env = env
+	j.SetID(fmt.Sprintf("%s.%s", artifactExpirationJobName, id))
+	return j
+}
+
+func (j *artifactExpirationJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	conf := j.env.Settings().Artifacts
+	if conf.Bucket == "" {
+		return
+	}
+	auth := &aws.Auth{
+		AccessKey: conf.Key,
+		SecretKey: conf.Secret,
+	}
+
+	now := time.Now()
+	entries, err := artifact.FindAll(artifact.ByExpiredFiles(now).Limit(artifactExpirationBatchSize))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "problem finding expired artifacts"))
+		return
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			break
+		}
+
+		keys := map[string][]string{}
+		for _, f := range entry.Files {
+			if f.FileKey == "" || f.ExpiresAt.IsZero() || f.ExpiresAt.After(now) {
+				continue
+			}
+			keys[f.Bucket] = append(keys[f.Bucket], f.FileKey)
+		}
+
+		for bucket, bucketKeys := range keys {
+			if err = thirdparty.DeleteS3Files(auth, conf.Region, bucket, bucketKeys); err != nil {
+				j.AddError(errors.Wrapf(err, "problem deleting expired artifacts of task '%s'", entry.TaskId))
+				continue
+			}
+			if err = artifact.RemoveFiles(entry.TaskId, entry.Execution, bucketKeys); err != nil {
+				j.AddError(errors.Wrapf(err, "problem removing expired artifacts of task '%s'", entry.TaskId))
+				continue
+			}
+			removed += len(bucketKeys)
+		}
+	}
+
+	grip.InfoWhen(removed > 0, message.Fields{
+		"message": "removed expired artifacts",
+		"job":     j.ID(),
+		"entries": len(entries),
+		"removed": removed,
+	})
+}
@@ -0,0 +1,41 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactExpirationJobWithoutBucket(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(artifact.Collection))
+
+	entry := artifact.Entry{
+		TaskId: "t1",
+		Files: []artifact.File{
+			{Name: "expired", Link: "l", Bucket: "artifacts", FileKey: "k", ExpiresAt: time.Now().Add(-time.Hour)},
+		},
+	}
+	require.NoError(entry.Upsert())
+
+	env := &mock.Environment{EvergreenSettings: &evergreen.Settings{}}
+	j := NewArtifactExpirationJob(env, "id")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	assert.True(j.Status().Completed)
+
+	found, err := artifact.FindOne(artifact.ByTaskId("t1"))
+	require.NoError(err)
+	require.NotNil(found)
+	assert.Len(found.Files, 1)
+}
@@ -0,0 +1,271 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/commitqueue"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	commitQueueJobName = "commit-queue"
+
+	// commitQueuePatchTimeout is how long the patch testing an item has to
+	// be created before the item is considered failed.
+	commitQueuePatchTimeout = 30 * time.Minute
+)
+
+func init() {
+	registry.AddJobType(commitQueueJobName, func() amboy.Job { return makeCommitQueueJob() })
+}
+
+type commitQueueJob struct {
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env       evergreen.Environment
+}
+
+func makeCommitQueueJob() *commitQueueJob {
+	j := &commitQueueJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    commitQueueJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewCommitQueueJob creates a job to advance the commit queue of a project:
+// it starts testing the item at the front of the queue against the head of
+// the project's branch, and merges the item once its patch passes.
+func NewCommitQueueJob(env evergreen.Environment, projectID, id string) amboy.Job {
+	j := makeCommitQueueJob()
+	j.env = env
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s:%s_%s", commitQueueJobName, projectID, id))
+	return j
+}
+
+func (j *commitQueueJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	settings := j.env.Settings()
+	if settings == nil {
+		j.AddError(errors.New("settings is empty"))
+		return
+	}
+	token, err := settings.GetGithubOauthToken()
+	if err != nil {
+		j.AddError(errors.New("github token is missing"))
+		return
+	}
+
+	ref, err := model.FindOneProjectRef(j.ProjectID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "can't find project ref for '%s'", j.ProjectID))
+		return
+	}
+	if ref == nil || !ref.Enabled || !ref.CommitQueue.Enabled {
+		return
+	}
+
+	cq, err := commitqueue.FindOneId(j.ProjectID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "can't find commit queue for '%s'", j.ProjectID))
+		return
+	}
+	if cq == nil {
+		return
+	}
+	item, ok := cq.Next()
+	if !ok {
+		return
+	}
+
+	if !cq.Processing {
+		j.AddError(j.startItem(ctx, cq, item, ref, token))
+		return
+	}
+	j.AddError(j.finishItem(ctx, cq, item, ref, token))
+}
+
+// startItem creates a patch testing the item against the head of the
+// project's branch, removing the item from the queue if it can't be tested.
+func (j *commitQueueJob) startItem(ctx context.Context, cq *commitqueue.CommitQueue, item commitqueue.CommitQueueItem, ref *model.ProjectRef, token string) error {
+	branch, err := thirdparty.GetBranchEvent(ctx, token, ref.Owner, ref.Repo, ref.Branch)
+	if err != nil {
+		return errors.Wrapf(err, "can't get head of branch '%s' for '%s'", ref.Branch, ref.Identifier)
+	}
+	head := branch.GetCommit().GetSHA()
+
+	intent, err := makeCommitQueueIntent(ctx, item, ref, token, head)
+	if err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(errors.Wrapf(err, "can't test item '%s' in the commit queue for '%s'", item.Issue, ref.Identifier))
+		_, err = cq.Remove(item.Issue, commitqueue.StatusFailed)
+		catcher.Add(err)
+		return catcher.Resolve()
+	}
+	if err = intent.Insert(); err != nil {
+		return errors.Wrap(err, "can't insert patch intent")
+	}
+	patchID := bson.NewObjectId()
+	if err = j.env.RemoteQueue().Put(NewPatchIntentProcessor(patchID, intent)); err != nil {
+		return errors.Wrap(err, "can't enqueue patch intent job")
+	}
+
+	grip.Info(message.Fields{
+		"message":   "testing commit queue item",
+		"job":       j.ID(),
+		"project":   ref.Identifier,
+		"item":      item.Issue,
+		"patch_id":  patchID.Hex(),
+		"base_hash": head,
+	})
+
+	return errors.WithStack(cq.StartItem(item.Issue, patchID.Hex(), head))
+}
+
+// makeCommitQueueIntent returns an intent to create a patch of the item's
+// diff against the revision, with the variants and tasks of the commit queue
+// alias. Items are pull request numbers or the IDs of patches.
+func makeCommitQueueIntent(ctx context.Context, item commitqueue.CommitQueueItem, ref *model.ProjectRef, token, baseHash string) (patch.Intent, error) {
+	var user, diff, description string
+
+	if prNumber, err := strconv.Atoi(item.Issue); err == nil {
+		pr, err := thirdparty.GetGithubPullRequest(ctx, token, ref.Owner, ref.Repo, prNumber)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if pr.GetState() != "open" {
+			return nil, errors.Errorf("pull request %d is %s", prNumber, pr.GetState())
+		}
+		if pr.GetBase().GetRef() != ref.Branch {
+			return nil, errors.Errorf("pull request %d is against branch '%s', not '%s'", prNumber, pr.GetBase().GetRef(), ref.Branch)
+		}
+
+		diff, _, err = thirdparty.GetGithubPullRequestDiff(ctx, token, &patch.GithubPatch{
+			BaseOwner: ref.Owner,
+			BaseRepo:  ref.Repo,
+			PRNumber:  prNumber,
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		u, err := findEvergreenUserForPR(int(pr.GetUser().GetID()))
+		if err != nil {
+			return nil, errors.Wrap(err, "can't find pull request author")
+		}
+		user = u.Id
+		description = fmt.Sprintf("Commit Queue Merge: '%s' (#%d)", pr.GetTitle(), prNumber)
+	} else {
+		if !bson.IsObjectIdHex(item.Issue) {
+			return nil, errors.Errorf("'%s' is neither a pull request nor a patch", item.Issue)
+		}
+		p, err := patch.FindOne(patch.ById(bson.ObjectIdHex(item.Issue)))
+		if err != nil {
+			return nil, errors.Wrapf(err, "can't find patch '%s'", item.Issue)
+		}
+		if p == nil {
+			return nil, errors.Errorf("patch '%s' doesn't exist", item.Issue)
+		}
+		if err = p.FetchPatchFiles(); err != nil {
+			return nil, errors.Wrapf(err, "can't get the diff of patch '%s'", item.Issue)
+		}
+		for _, modulePatch := range p.Patches {
+			if modulePatch.ModuleName == "" {
+				diff = modulePatch.PatchSet.Patch
+			}
+		}
+		user = p.Author
+		description = fmt.Sprintf("Commit Queue Merge: '%s' (%s)", p.Description, item.Issue)
+	}
+
+	return patch.NewCliIntent(user, ref.Identifier, baseHash, "", diff, description, true, nil, nil, commitqueue.CommitQueueAlias)
+}
+
+// finishItem checks on the patch testing the item. Once it passes, the item
+// is merged, unless the branch has moved since the item was tested, in which
+// case the item is tested again against the new head.
+func (j *commitQueueJob) finishItem(ctx context.Context, cq *commitqueue.CommitQueue, item commitqueue.CommitQueueItem, ref *model.ProjectRef, token string) error {
+	if !bson.IsObjectIdHex(item.Version) {
+		return errors.WithStack(j.startItem(ctx, cq, item, ref, token))
+	}
+	p, err := patch.FindOne(patch.ById(bson.ObjectIdHex(item.Version)))
+	if err != nil {
+		return errors.Wrapf(err, "can't find patch '%s'", item.Version)
+	}
+	if p == nil {
+		if time.Since(item.StartTime) > commitQueuePatchTimeout {
+			catcher := grip.NewBasicCatcher()
+			catcher.Add(errors.Errorf("patch for item '%s' in the commit queue for '%s' was never created", item.Issue, ref.Identifier))
+			_, err = cq.Remove(item.Issue, commitqueue.StatusFailed)
+			catcher.Add(err)
+			return catcher.Resolve()
+		}
+		return nil
+	}
+
+	switch p.Status {
+	case evergreen.PatchFailed:
+		_, err = cq.Remove(item.Issue, commitqueue.StatusFailed)
+		return errors.WithStack(err)
+	case evergreen.PatchSucceeded:
+	default:
+		return nil
+	}
+
+	branch, err := thirdparty.GetBranchEvent(ctx, token, ref.Owner, ref.Repo, ref.Branch)
+	if err != nil {
+		return errors.Wrapf(err, "can't get head of branch '%s' for '%s'", ref.Branch, ref.Identifier)
+	}
+	if head := branch.GetCommit().GetSHA(); head != item.BaseHash {
+		grip.Info(message.Fields{
+			"message":   "branch moved while testing commit queue item, retesting",
+			"job":       j.ID(),
+			"project":   ref.Identifier,
+			"item":      item.Issue,
+			"base_hash": item.BaseHash,
+			"head":      head,
+		})
+		return errors.WithStack(j.startItem(ctx, cq, item, ref, token))
+	}
+
+	prNumber, err := strconv.Atoi(item.Issue)
+	if err != nil {
+		// Evergreen can't push patches, so their authors push them once
+		// they've passed
+		_, err = cq.Remove(item.Issue, commitqueue.StatusPassed)
+		return errors.WithStack(err)
+	}
+	if err = thirdparty.MergePullRequest(ctx, token, ref.Owner, ref.Repo, prNumber, "", ref.CommitQueue.MergeMethod, ""); err != nil {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(errors.Wrapf(err, "can't merge item '%s' in the commit queue for '%s'", item.Issue, ref.Identifier))
+		_, err = cq.Remove(item.Issue, commitqueue.StatusFailed)
+		catcher.Add(err)
+		return catcher.Resolve()
+	}
+	_, err = cq.Remove(item.Issue, commitqueue.StatusMerged)
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,122 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const containerImagePrewarmJobName = "container-image-prewarm"
+
+func init() {
+	registry.AddJobType(containerImagePrewarmJobName, func() amboy.Job {
+		return makeContainerImagePrewarmJob()
+	})
+}
+
+// containerImagePrewarmJob builds an image on a parent ahead of time. Unlike
+// the building container image job, which builds an image that a container
+// is waiting on, it leaves the parent's build attempts alone, so a failure to
+// pre-warm an image never terminates a parent that is otherwise healthy.
+type containerImagePrewarmJob struct {
+	ParentID string `bson:"parent_id" json:"parent_id" yaml:"parent_id"`
+	job.Base `bson:"base" json:"base" yaml:"base"`
+	ImageURL string `bson:"image_url" json:"image_url" yaml:"image_url"`
+	Provider string `bson:"provider" json:"provider" yaml:"provider"`
+
+	// cache
+	parent   *host.Host
+	env      evergreen.Environment
+	settings *evergreen.Settings
+}
+
+func makeContainerImagePrewarmJob() *containerImagePrewarmJob {
+	j := &containerImagePrewarmJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    containerImagePrewarmJobName,
+				Version: 0,
+			},
+		},
+	}
+
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+func NewContainerImagePrewarmJob(env evergreen.Environment, h *host.Host, imageURL, providerName, ts string) amboy.Job {
+	job := makeContainerImagePrewarmJob()
+
+	job.env = env
+	job.parent = h
+	job.ImageURL = imageURL
+	job.ParentID = h.Id
+	job.Provider = providerName
+
+	job.SetID(fmt.Sprintf("%s.%s.%s.%s", containerImagePrewarmJobName, job.ParentID, job.ImageURL, ts))
+
+	return job
+}
+
+func (j *containerImagePrewarmJob) Run(ctx context.Context) {
+	var cancel context.CancelFunc
+
+	ctx, cancel = context.WithCancel(ctx)
+	defer cancel()
+	defer j.MarkComplete()
+
+	var err error
+	if j.parent == nil {
+		j.parent, err = host.FindOneId(j.ParentID)
+		j.AddError(err)
+	}
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	if j.settings == nil {
+		j.settings = j.env.Settings()
+	}
+
+	if j.HasErrors() {
+		return
+	}
+	if j.parent == nil {
+		j.AddError(errors.Errorf("parent '%s' not found", j.ParentID))
+		return
+	}
+	if j.parent.Status != evergreen.HostRunning || j.parent.ContainerImages[j.ImageURL] {
+		return
+	}
+
+	mgr, err := cloud.GetManager(ctx, j.Provider, j.settings)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error getting Docker manager"))
+		return
+	}
+	containerMgr, err := cloud.ConvertContainerManager(mgr)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error getting Docker manager"))
+		return
+	}
+
+	if err = containerMgr.BuildContainerImage(ctx, j.parent, j.ImageURL); err != nil {
+		j.AddError(errors.Wrapf(err, "error pre-warming container image '%s' on parent '%s'", j.ImageURL, j.parent.Id))
+		return
+	}
+	if j.parent.ContainerImages == nil {
+		j.parent.ContainerImages = make(map[string]bool)
+	}
+	j.parent.ContainerImages[j.ImageURL] = true
+	if _, err = j.parent.Upsert(); err != nil {
+		j.AddError(errors.Wrapf(err, "error upserting parent %s", j.parent.Id))
+		return
+	}
+}
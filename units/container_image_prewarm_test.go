@@ -0,0 +1,49 @@
+package units
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerImagePrewarmJob(t *testing.T) {
+	assert := assert.New(t)
+	testConfig := testutil.TestConfig()
+	db.SetGlobalSessionProvider(testConfig.SessionFactory())
+
+	assert.NoError(db.Clear(host.Collection))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := evergreen.GetEnvironment()
+	assert.NoError(env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+
+	// a parent that has used up its build attempts is left running
+	h := &host.Host{
+		Id:                    "parent-1",
+		Status:                evergreen.HostRunning,
+		HasContainers:         true,
+		ContainerBuildAttempt: containerBuildRetries,
+	}
+	assert.NoError(h.Insert())
+
+	j := NewContainerImagePrewarmJob(env, h, "image-url", evergreen.ProviderNameDockerMock, "ts")
+	j.Run(ctx)
+	assert.NoError(j.Error())
+	assert.True(j.Status().Completed)
+
+	dbHost, err := host.FindOneId(h.Id)
+	assert.NoError(err)
+	require.NotNil(t, dbHost)
+	assert.Equal(evergreen.HostRunning, dbHost.Status)
+	assert.Equal(containerBuildRetries, dbHost.ContainerBuildAttempt)
+	assert.True(dbHost.ContainerImages["image-url"])
+}
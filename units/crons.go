@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/util"
@@ -110,6 +112,24 @@ func PopulateRepotrackerPollingJobs(part int) amboy.QueueOperation {
 	}
 }
 
+func PopulateCommitQueueJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := model.FindProjectRefsWithCommitQueueEnabled()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfMinute(0).Format(tsFormat)
+
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			catcher.Add(queue.Put(NewCommitQueueJob(env, proj.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
 func PopulateActivationJobs(part int) amboy.QueueOperation {
 	return func(queue amboy.Queue) error {
 		flags, err := evergreen.GetServiceFlags()
@@ -313,11 +333,73 @@ func PopulateIdleHostJobs(env evergreen.Environment) amboy.QueueOperation {
 			"num":   len(hosts),
 		})
 
+		hostsByDistro := map[string][]host.Host{}
 		for _, h := range hosts {
-			err := queue.Put(NewIdleHostTerminationJob(env, h, ts))
-			catcher.Add(err)
+			hostsByDistro[h.Distro.Id] = append(hostsByDistro[h.Distro.Id], h)
+		}
+
+		for distroID, distroHosts := range hostsByDistro {
+			toCheck, err := idleHostsToCheck(distroID, distroHosts)
+			if err != nil {
+				catcher.Add(err)
+				continue
+			}
+			for _, h := range toCheck {
+				catcher.Add(queue.Put(NewIdleHostTerminationJob(env, h, ts)))
+			}
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulateHostProblemDetectionJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if flags.MonitorDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "monitor is disabled",
+				"impact":  "not detecting problem hosts",
+				"mode":    "degraded",
+			})
+			return nil
+		}
+
+		ts := util.RoundPartOfHour(5).Format(tsFormat)
+		return queue.Put(NewHostProblemDetectionJob(env, ts))
+	}
+}
+
+func PopulateStaticHostHealthCheckJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		flags, err := evergreen.GetServiceFlags()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if flags.MonitorDisabled {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"message": "monitor is disabled",
+				"impact":  "not checking health of static hosts",
+				"mode":    "degraded",
+			})
+			return nil
 		}
 
+		hosts, err := host.Find(host.StaticHostsByStatus("", []string{evergreen.HostRunning}))
+		if err != nil {
+			return errors.Wrap(err, "problem finding static hosts")
+		}
+
+		ts := util.RoundPartOfHour(15).Format(tsFormat)
+		catcher := grip.NewBasicCatcher()
+		for _, h := range hosts {
+			catcher.Add(queue.Put(NewStaticHostHealthCheckJob(env, h, ts)))
+		}
 		return catcher.Resolve()
 	}
 }
@@ -346,7 +428,9 @@ func PopulateParentDecommissionJobs() amboy.QueueOperation {
 
 		// Create ParentDecommissionJob for each distro
 		for _, c := range containerPools {
-			catcher.Add(queue.Put(NewParentDecommissionJob(ts, c.Distro, c.MaxContainers)))
+			for _, d := range c.ParentDistros() {
+				catcher.Add(queue.Put(NewParentDecommissionJob(ts, d, c.MaxContainers)))
+			}
 		}
 
 		return catcher.Resolve()
@@ -372,6 +456,49 @@ func PopulateContainerStateJobs(env evergreen.Environment) amboy.QueueOperation
 	}
 }
 
+// PopulateContainerImagePrewarmJobs builds the images of container distros on
+// every running parent of their container pools that does not have them yet,
+// so that the first container on a new parent does not wait for the import.
+func PopulateContainerImagePrewarmJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		catcher := grip.NewBasicCatcher()
+		ts := util.RoundPartOfHour(15).Format(tsFormat)
+
+		distros, err := distro.Find(distro.ByProvider(evergreen.ProviderNameDocker))
+		if err != nil {
+			return errors.Wrap(err, "Error finding container distros")
+		}
+
+		for _, d := range distros {
+			if d.ContainerPool == "" || d.ProviderSettings == nil {
+				continue
+			}
+
+			parents, err := host.FindAllRunningParentsByContainerPool(d.ContainerPool)
+			if err != nil {
+				catcher.Add(errors.Wrapf(err, "Error finding parents for container pool '%s'", d.ContainerPool))
+				continue
+			}
+
+			pool := env.Settings().ContainerPools.GetContainerPool(d.ContainerPool)
+			for i := range parents {
+				// containers of the distro only run on parents of the
+				// matching OS in a mixed pool
+				if pool != nil && pool.IsMixed() && parents[i].Distro.Id != pool.ParentDistro(d.IsWindows()) {
+					continue
+				}
+				imageURL, err := cloud.ContainerImageURL(&d, &parents[i])
+				if err != nil || parents[i].ContainerImages[imageURL] {
+					continue
+				}
+				catcher.Add(queue.Put(NewContainerImagePrewarmJob(env, &parents[i], imageURL, evergreen.ProviderNameDocker, ts)))
+			}
+		}
+
+		return catcher.Resolve()
+	}
+}
+
 func PopulateOldestImageRemovalJobs() amboy.QueueOperation {
 	return func(queue amboy.Queue) error {
 		catcher := grip.NewBasicCatcher()
@@ -390,6 +517,123 @@ func PopulateOldestImageRemovalJobs() amboy.QueueOperation {
 	}
 }
 
+func PopulateSystemPruneJobs() amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		catcher := grip.NewBasicCatcher()
+		ts := util.RoundPartOfHour(1).Format(tsFormat)
+
+		parents, err := host.FindAllRunningParents()
+		if err != nil {
+			return errors.Wrap(err, "Error finding parent hosts")
+		}
+
+		// Create systemPruneJob to reclaim disk space when Docker takes up too much
+		for _, p := range parents {
+			catcher.Add(queue.Put(NewSystemPruneJob(&p, evergreen.ProviderNameDocker, ts)))
+		}
+		return catcher.Resolve()
+	}
+}
+
+func PopulatePatchExpirationJobs() amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := model.FindAllProjectRefs()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(0).Format(tsFormat)
+
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			catcher.Add(queue.Put(NewPatchExpirationJob(proj.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulateArtifactExpirationJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		if env.Settings().Artifacts.Bucket == "" {
+			return nil
+		}
+
+		ts := util.RoundPartOfHour(0).Format(tsFormat)
+		return queue.Put(NewArtifactExpirationJob(env, ts))
+	}
+}
+
+func PopulateTestFlakinessJobs() amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := model.FindAllTrackedProjectRefs()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(0).Format(tsFormat)
+
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			if !proj.Enabled {
+				continue
+			}
+			catcher.Add(queue.Put(NewTestFlakinessJob(proj.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulateDataLifecycleJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		if env.Settings().DataLifecycle.ArchiveAfterDays <= 0 {
+			return nil
+		}
+
+		ts := util.RoundPartOfHour(0).Format(tsFormat)
+		return queue.Put(NewDataLifecycleJob(env, ts))
+	}
+}
+
+func PopulateProjectInsightsJobs() amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		projects, err := model.FindAllTrackedProjectRefs()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		ts := util.RoundPartOfHour(0).Format(tsFormat)
+
+		catcher := grip.NewBasicCatcher()
+		for _, proj := range projects {
+			if !proj.Enabled {
+				continue
+			}
+			catcher.Add(queue.Put(NewProjectInsightsJob(proj.Identifier, ts)))
+		}
+
+		return catcher.Resolve()
+	}
+}
+
+func PopulateParentImageBakeJobs(env evergreen.Environment) amboy.QueueOperation {
+	return func(queue amboy.Queue) error {
+		catcher := grip.NewBasicCatcher()
+		ts := util.RoundPartOfHour(0).Format(tsFormat)
+
+		settings := env.Settings()
+		for i := range settings.ContainerPools.Pools {
+			pool := settings.ContainerPools.Pools[i]
+			if pool.ImageBakeIntervalHours <= 0 {
+				continue
+			}
+			catcher.Add(queue.Put(NewParentImageBakeJob(env, &pool, ts)))
+		}
+		return catcher.Resolve()
+	}
+}
+
 func PopulateSchedulerJobs(env evergreen.Environment) amboy.QueueOperation {
 	return func(queue amboy.Queue) error {
 		flags, err := evergreen.GetServiceFlags()
@@ -657,6 +901,15 @@ func PopulatePeriodicNotificationJobs(parts int) amboy.QueueOperation {
 		ts := util.RoundPartOfHour(parts).Format(tsFormat)
 		catcher := grip.NewBasicCatcher()
 		catcher.Add(queue.Put(NewSpawnhostExpirationWarningsJob(ts)))
+		catcher.Add(queue.Put(NewNotificationDigestJob(queue, ts)))
+		catcher.Add(queue.Put(NewNotificationEscalationJob(queue, ts)))
+
+		webhooks, err := event.FindAllEventWebhooks()
+		catcher.Add(err)
+		for _, w := range webhooks {
+			catcher.Add(queue.Put(NewEventWebhookDispatchJob(w.ID, ts)))
+		}
+
 		return catcher.Resolve()
 	}
 }
@@ -0,0 +1,94 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/archive"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const dataLifecycleJobName = "data-lifecycle"
+
+func init() {
+	registry.AddJobType(dataLifecycleJobName, func() amboy.Job {
+		return makeDataLifecycleJob()
+	})
+}
+
+type dataLifecycleJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+
+	env evergreen.Environment
+}
+
+func makeDataLifecycleJob() *dataLifecycleJob {
+	j := &dataLifecycleJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    dataLifecycleJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewDataLifecycleJob creates a job that archives a batch of the tasks and
+// builds that finished longer ago than the configured age.
+func NewDataLifecycleJob(env evergreen.Environment, id string) amboy.Job {
+	j := makeDataLifecycleJob()
+	j.env = env
+	j.SetID(fmt.Sprintf("%s.%s", dataLifecycleJobName, id))
+	return j
+}
+
+func (j *dataLifecycleJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	settings := j.env.Settings()
+	conf := settings.DataLifecycle
+	cutoff := conf.ArchiveCutoff(time.Now())
+	if cutoff.IsZero() {
+		return
+	}
+	store := archive.GetStore(settings)
+
+	tasks, err := task.ArchiveFinishedBefore(store, cutoff, conf.BatchSize)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "problem archiving tasks"))
+		return
+	}
+	if ctx.Err() != nil {
+		j.AddError(ctx.Err())
+		return
+	}
+	builds, err := build.ArchiveFinishedBefore(store, cutoff, conf.BatchSize)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "problem archiving builds"))
+		return
+	}
+
+	grip.InfoWhen(tasks+builds > 0, message.Fields{
+		"message": "archived finished tasks and builds",
+		"job":     j.ID(),
+		"target":  conf.Target,
+		"cutoff":  cutoff,
+		"tasks":   tasks,
+		"builds":  builds,
+	})
+}
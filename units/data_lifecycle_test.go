@@ -0,0 +1,62 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/model/archive"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataLifecycleJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(task.Collection, build.Collection, testresult.Collection,
+		archive.CollectionName(task.Collection), archive.CollectionName(build.Collection), archive.CollectionName(testresult.Collection)))
+
+	old := time.Now().AddDate(0, 0, -60)
+	require.NoError((&task.Task{Id: "old", Status: evergreen.TaskSucceeded, FinishTime: old}).Insert())
+	require.NoError((&task.Task{Id: "new", Status: evergreen.TaskFailed, FinishTime: time.Now()}).Insert())
+	require.NoError((&build.Build{Id: "b1", Status: evergreen.BuildFailed, FinishTime: old}).Insert())
+
+	env := &mock.Environment{EvergreenSettings: &evergreen.Settings{}}
+	j := NewDataLifecycleJob(env, "disabled")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	count, err := task.Count(task.All)
+	require.NoError(err)
+	assert.Equal(2, count)
+
+	env.EvergreenSettings.DataLifecycle = evergreen.DataLifecycleConfig{
+		ArchiveAfterDays: 30,
+		BatchSize:        10,
+		Target:           evergreen.ArchiveTargetCollection,
+	}
+	j = NewDataLifecycleJob(env, "enabled")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+
+	count, err = task.Count(task.All)
+	require.NoError(err)
+	assert.Equal(1, count)
+	found, err := task.FindOneId("old")
+	require.NoError(err)
+	require.NotNil(found)
+	assert.Equal(evergreen.TaskSucceeded, found.Status)
+	b, err := build.FindOneId("b1")
+	require.NoError(err)
+	require.NotNil(b)
+	builds, err := build.Find(build.ById("b1"))
+	require.NoError(err)
+	assert.Len(builds, 0)
+}
@@ -0,0 +1,170 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/trigger"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	notificationDigestJobName = "notification-digests"
+)
+
+func init() {
+	registry.AddJobType(notificationDigestJobName, func() amboy.Job { return makeNotificationDigestJob() })
+}
+
+type notificationDigestJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	q        amboy.Queue
+}
+
+func makeNotificationDigestJob() *notificationDigestJob {
+	j := &notificationDigestJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    notificationDigestJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// NewNotificationDigestJob makes a job that sends the digests of the
+// windows that have ended: one notification per subscriber per window,
+// summarizing the notifications that were batched into it.
+func NewNotificationDigestJob(q amboy.Queue, ts string) amboy.Job {
+	j := makeNotificationDigestJob()
+	j.q = q
+
+	j.SetID(fmt.Sprintf("%s:%s", notificationDigestJobName, ts))
+
+	return j
+}
+
+func (j *notificationDigestJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.q == nil {
+		j.q = evergreen.GetEnvironment().RemoteQueue()
+	}
+	if j.q == nil || !j.q.Started() {
+		j.AddError(errors.New("evergreen environment not setup correctly"))
+		return
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if flags.EventProcessingDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     notificationDigestJobName,
+			"message": "events processing is disabled, not sending digests",
+		})
+		return
+	}
+
+	entries, err := notification.FindEndedDigestEntries(time.Now())
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	digests := notification.GroupDigestEntries(entries)
+	for _, digest := range digests {
+		if ctx.Err() != nil {
+			j.AddError(errors.New("notification digest run canceled"))
+			return
+		}
+		j.AddError(j.send(flags, digest))
+	}
+
+	grip.Info(message.Fields{
+		"job_id":  j.ID(),
+		"job":     notificationDigestJobName,
+		"message": "sent notification digests",
+		"entries": len(entries),
+		"digests": len(digests),
+	})
+}
+
+// send creates and queues the notification for the digest, then removes
+// its entries. If the notification was already created by an earlier run
+// that failed to remove the entries, it is not sent again.
+func (j *notificationDigestJob) send(flags *evergreen.ServiceFlags, entries []notification.DigestEntry) error {
+	current := renderDigestEntries(entries)
+	if len(current) == 0 {
+		return notification.RemoveDigestEntries(entries)
+	}
+
+	n, err := notification.NewDigestNotification(current)
+	if err != nil {
+		return errors.Wrap(err, "can't create digest notification")
+	}
+
+	err = notification.InsertMany(*n)
+	switch {
+	case db.IsDuplicateKey(err):
+	case err != nil:
+		return errors.Wrapf(err, "can't insert digest notification '%s'", n.ID)
+	case notificationIsEnabled(flags, n):
+		if err = j.q.Put(NewEventNotificationJob(n.ID)); err != nil {
+			return errors.Wrapf(err, "can't queue digest notification '%s'", n.ID)
+		}
+	default:
+		if err = n.MarkError(errors.New("sender disabled")); err != nil {
+			return errors.Wrapf(err, "can't mark digest notification '%s' as disabled", n.ID)
+		}
+	}
+
+	return notification.RemoveDigestEntries(entries)
+}
+
+// renderDigestEntries renders the notifications that were batched into a
+// digest again against the current state of the resources they are about.
+// Entries for failures that have since been fixed are left out of the
+// digest, and the summaries of ones whose resources have otherwise changed
+// are updated. Entries that can't be rendered again are kept as they are.
+func renderDigestEntries(entries []notification.DigestEntry) []notification.DigestEntry {
+	out := make([]notification.DigestEntry, 0, len(entries))
+	for _, e := range entries {
+		n := e.Notification()
+		rendered, err := trigger.RenderAtDelivery(n)
+		grip.Error(message.WrapError(err, message.Fields{
+			"job":             notificationDigestJobName,
+			"notification_id": e.NotificationID,
+			"message":         "failed to render digest entry again",
+		}))
+		if err == nil && rendered == nil {
+			continue
+		}
+		if rendered != nil && rendered != n && rendered.Payload != nil {
+			grip.Error(message.WrapError(e.SetSummary(rendered), message.Fields{
+				"job":             notificationDigestJobName,
+				"notification_id": e.NotificationID,
+				"message":         "failed to summarize digest entry",
+			}))
+		}
+		out = append(out, e)
+	}
+
+	return out
+}
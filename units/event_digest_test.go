@@ -0,0 +1,80 @@
+package units
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationDigestJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evergreen.ResetEnvironment()
+	env := evergreen.GetEnvironment()
+	require.NoError(env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+	require.NoError(env.RemoteQueue().Start(ctx))
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(evergreen.ConfigCollection, notification.Collection, notification.DigestCollection))
+
+	flags := evergreen.ServiceFlags{EmailNotificationsDisabled: true}
+	require.NoError(flags.Set())
+
+	email := "me@example.com"
+	notifications := []notification.Notification{
+		{
+			ID:         "n0",
+			Subscriber: event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			Payload:    &message.Email{Subject: "task failed"},
+			Digest:     event.DigestHourly,
+			CreatedAt:  time.Now().Add(-2 * time.Hour),
+		},
+		{
+			ID:         "n1",
+			Subscriber: event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			Payload:    &message.Email{Subject: "version failed"},
+			Digest:     event.DigestHourly,
+			CreatedAt:  time.Now().Add(-2 * time.Hour),
+		},
+		{
+			ID:         "n2",
+			Subscriber: event.Subscriber{Type: event.EmailSubscriberType, Target: &email},
+			Payload:    &message.Email{Subject: "sent now"},
+		},
+	}
+	immediate, err := batchDigests(notifications)
+	assert.NoError(err)
+	if assert.Len(immediate, 1) {
+		assert.Equal("n2", immediate[0].ID)
+	}
+
+	j := NewNotificationDigestJob(env.RemoteQueue(), "1")
+	j.Run(ctx)
+	assert.NoError(j.Error())
+
+	out := []notification.Notification{}
+	assert.NoError(db.FindAllQ(notification.Collection, db.Q{}, &out))
+	if assert.Len(out, 1) {
+		assert.Equal("sender disabled", out[0].Error)
+		payload, ok := out[0].Payload.(*message.Email)
+		if assert.True(ok) {
+			assert.Contains(payload.Body, "task failed")
+			assert.Contains(payload.Body, "version failed")
+		}
+	}
+	entries, err := notification.FindEndedDigestEntries(time.Now())
+	assert.NoError(err)
+	assert.Empty(entries)
+}
@@ -0,0 +1,137 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	notificationEscalationJobName = "notification-escalations"
+)
+
+func init() {
+	registry.AddJobType(notificationEscalationJobName, func() amboy.Job { return makeNotificationEscalationJob() })
+}
+
+type notificationEscalationJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	q        amboy.Queue
+}
+
+func makeNotificationEscalationJob() *notificationEscalationJob {
+	j := &notificationEscalationJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    notificationEscalationJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// NewNotificationEscalationJob makes a job that notifies the next tier of
+// each escalation that hasn't been acknowledged in time.
+func NewNotificationEscalationJob(q amboy.Queue, ts string) amboy.Job {
+	j := makeNotificationEscalationJob()
+	j.q = q
+
+	j.SetID(fmt.Sprintf("%s:%s", notificationEscalationJobName, ts))
+
+	return j
+}
+
+func (j *notificationEscalationJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.q == nil {
+		j.q = evergreen.GetEnvironment().RemoteQueue()
+	}
+	if j.q == nil || !j.q.Started() {
+		j.AddError(errors.New("evergreen environment not setup correctly"))
+		return
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if flags.EventProcessingDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     notificationEscalationJobName,
+			"message": "events processing is disabled, not escalating notifications",
+		})
+		return
+	}
+
+	now := time.Now()
+	escalations, err := notification.FindDueEscalations(now)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	sent := 0
+	for i := range escalations {
+		if ctx.Err() != nil {
+			j.AddError(errors.New("notification escalation run canceled"))
+			return
+		}
+		n, err := j.escalate(flags, &escalations[i], now)
+		j.AddError(err)
+		sent += n
+	}
+
+	grip.Info(message.Fields{
+		"job_id":        j.ID(),
+		"job":           notificationEscalationJobName,
+		"message":       "escalated notifications",
+		"escalations":   len(escalations),
+		"notifications": sent,
+	})
+}
+
+// escalate advances the escalation to its next tier, and sends that tier's
+// notifications, returning the number sent.
+func (j *notificationEscalationJob) escalate(flags *evergreen.ServiceFlags, e *notification.Escalation, now time.Time) (int, error) {
+	notifications, err := e.Escalate(now)
+	if err != nil {
+		return 0, err
+	}
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	catcher := grip.NewSimpleCatcher()
+	for i := range notifications {
+		n := &notifications[i]
+		err = notification.InsertMany(*n)
+		switch {
+		case db.IsDuplicateKey(err):
+		case err != nil:
+			catcher.Add(errors.Wrapf(err, "can't insert escalated notification '%s'", n.ID))
+		case notificationIsEnabled(flags, n):
+			catcher.Add(errors.Wrapf(j.q.Put(NewEventNotificationJob(n.ID)), "can't queue escalated notification '%s'", n.ID))
+		default:
+			catcher.Add(errors.Wrapf(n.MarkError(errors.New("sender disabled")), "can't mark escalated notification '%s' as disabled", n.ID))
+		}
+	}
+
+	return len(notifications), catcher.Resolve()
+}
@@ -0,0 +1,77 @@
+package units
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestNotificationEscalationJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evergreen.ResetEnvironment()
+	env := evergreen.GetEnvironment()
+	require.NoError(env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+	require.NoError(env.RemoteQueue().Start(ctx))
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(evergreen.ConfigCollection, notification.Collection, notification.EscalationsCollection))
+
+	flags := evergreen.ServiceFlags{SlackNotificationsDisabled: true}
+	require.NoError(flags.Set())
+
+	channel := "#team"
+	n := &notification.Notification{
+		ID:         "n0",
+		Subscriber: event.Subscriber{Type: event.SlackSubscriberType, Target: &channel},
+		Payload:    &notification.SlackPayload{Body: "the build broke"},
+	}
+	e, err := notification.NewEscalation(n, time.Minute, []notification.EscalationTier{
+		{
+			Name: "team",
+			Notifications: []notification.Notification{
+				{
+					ID:         "n1",
+					Subscriber: event.Subscriber{Type: event.SlackSubscriberType, Target: &channel},
+					Payload:    &notification.SlackPayload{Body: "the build broke"},
+				},
+			},
+		},
+	})
+	require.NoError(err)
+	require.NoError(e.Insert())
+	require.NoError(db.Update(notification.EscalationsCollection, bson.M{"_id": e.ID}, bson.M{
+		"$set": bson.M{"escalate_at": time.Now().Add(-time.Minute)},
+	}))
+
+	j := NewNotificationEscalationJob(env.RemoteQueue(), "1")
+	j.Run(ctx)
+	assert.NoError(j.Error())
+
+	out := []notification.Notification{}
+	assert.NoError(db.FindAllQ(notification.Collection, db.Q{}, &out))
+	if assert.Len(out, 1) {
+		assert.Equal("n1", out[0].ID)
+		assert.Equal(e.ID, out[0].EscalationID)
+		assert.Equal("sender disabled", out[0].Error)
+	}
+
+	e, err = notification.FindEscalation(e.ID)
+	assert.NoError(err)
+	if assert.NotNil(e) {
+		assert.Equal(1, e.NextTier)
+		assert.True(e.EscalateAt.IsZero())
+	}
+}
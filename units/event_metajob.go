@@ -29,7 +29,7 @@ func init() {
 
 func notificationIsEnabled(flags *evergreen.ServiceFlags, n *notification.Notification) bool {
 	switch n.Subscriber.Type {
-	case event.GithubPullRequestSubscriberType:
+	case event.GithubPullRequestSubscriberType, event.GithubCheckSubscriberType:
 		return !flags.GithubStatusAPIDisabled
 
 	case event.JIRAIssueSubscriberType, event.JIRACommentSubscriberType:
@@ -44,6 +44,9 @@ func notificationIsEnabled(flags *evergreen.ServiceFlags, n *notification.Notifi
 	case event.SlackSubscriberType:
 		return !flags.SlackNotificationsDisabled
 
+	case event.OpsgenieSubscriberType:
+		return !flags.OpsgenieNotificationsDisabled
+
 	default:
 		grip.Alert(message.Fields{
 			"message": "notificationIsEnabled saw unknown subscriber type",
@@ -136,6 +139,8 @@ func (j *eventMetaJob) dispatchLoop(ctx context.Context) error {
 	for i := range j.events {
 		notifications[i], err = tryProcessOneEvent(&j.events[i])
 		catcher.Add(err)
+		notifications[i], err = batchDigests(notifications[i])
+		catcher.Add(err)
 		catcher.Add(notification.InsertMany(notifications[i]...))
 	}
 
@@ -161,6 +166,31 @@ func (j *eventMetaJob) dispatchLoop(ctx context.Context) error {
 	return catcher.Resolve()
 }
 
+// batchDigests adds the notifications for subscriptions that opted into
+// digests to their digest, and returns the rest, which are sent on their
+// own. Notifications that can't be added to a digest are sent on their own.
+func batchDigests(notifications []notification.Notification) ([]notification.Notification, error) {
+	catcher := grip.NewSimpleCatcher()
+	out := make([]notification.Notification, 0, len(notifications))
+	for i := range notifications {
+		if notifications[i].Digest == "" {
+			out = append(out, notifications[i])
+			continue
+		}
+
+		entry, err := notification.NewDigestEntry(&notifications[i])
+		if err == nil {
+			err = entry.Insert()
+		}
+		if err != nil {
+			catcher.Add(err)
+			out = append(out, notifications[i])
+		}
+	}
+
+	return out, catcher.Resolve()
+}
+
 func (j *eventMetaJob) dispatch(notifications []notification.Notification) error {
 	catcher := grip.NewSimpleCatcher()
 	for i := range notifications {
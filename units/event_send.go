@@ -3,10 +3,16 @@ package units
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/notification"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/trigger"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/goamz/goamz/aws"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
 	"github.com/mongodb/amboy/job"
@@ -20,8 +26,22 @@ import (
 
 const (
 	eventNotificationJobName = "event-send"
+
+	// eventNotificationMaxAttempts is the number of times sending a
+	// notification is attempted before it is dead lettered, and
+	// eventNotificationRetryDelay is the delay before the first retry,
+	// which doubles with each subsequent retry
+	eventNotificationMaxAttempts = 4
+	eventNotificationRetryDelay  = time.Minute
+
+	// eventNotificationPausedDelay is how often a notification held
+	// while notification dispatch is disabled is checked again
+	eventNotificationPausedDelay = 5 * time.Minute
 )
 
+var notificationSendOutcomes = util.NewPrometheusCounterVec("evergreen_notification_send_outcomes_total",
+	"Attempts to send notifications, by subscriber type and result.", "type", "result")
+
 func init() {
 	registry.AddJobType(eventNotificationJobName, func() amboy.Job { return makeEventNotificationJob() })
 }
@@ -32,6 +52,9 @@ type eventNotificationJob struct {
 	flags    *evergreen.ServiceFlags
 
 	NotificationID string `bson:"notification_id" json:"notification_id" yaml:"notification_id"`
+	// Deferred is set when the notification was held back, and is
+	// rendered again before it's sent
+	Deferred bool `bson:"deferred,omitempty" json:"deferred,omitempty" yaml:"deferred,omitempty"`
 }
 
 func makeEventNotificationJob() *eventNotificationJob {
@@ -56,6 +79,34 @@ func NewEventNotificationJob(id string) amboy.Job {
 	return j
 }
 
+// newEventNotificationRetryJob makes a job to retry sending the notification
+// after its given number of failed attempts, delayed by an exponential backoff.
+func newEventNotificationRetryJob(id string, attempts int) amboy.Job {
+	j := makeEventNotificationJob()
+	j.NotificationID = id
+
+	j.SetID(fmt.Sprintf("%s:%s:%d", eventNotificationJobName, id, attempts))
+	j.UpdateTimeInfo(amboy.JobTimeInfo{
+		WaitUntil: time.Now().Add(eventNotificationRetryDelay << uint(attempts-1)),
+	})
+	return j
+}
+
+// newEventNotificationDeferredJob makes a job to send the notification after
+// the delay.
+func newEventNotificationDeferredJob(id string, delay time.Duration) amboy.Job {
+	j := makeEventNotificationJob()
+	j.NotificationID = id
+	j.Deferred = true
+
+	waitUntil := time.Now().Add(delay)
+	j.SetID(fmt.Sprintf("%s:%s:deferred-%d", eventNotificationJobName, id, waitUntil.UnixNano()))
+	j.UpdateTimeInfo(amboy.JobTimeInfo{
+		WaitUntil: waitUntil,
+	})
+	return j
+}
+
 func (j *eventNotificationJob) setup() error {
 	if len(j.NotificationID) == 0 {
 		return errors.New("notification ID is not valid")
@@ -93,54 +144,312 @@ func (j *eventNotificationJob) Run(_ context.Context) {
 	if j.HasErrors() {
 		return
 	}
+	// the job may run again for a notification that was already sent, so
+	// that notifications are only sent once
+	if !n.SentAt.IsZero() {
+		return
+	}
 
 	if err = j.checkDegradedMode(n); err != nil {
+		j.audit(notification.NewAuditEntry(n, notification.AuditResultDisabled, err))
 		j.AddError(n.MarkError(err))
 		return
 	}
 
-	err = j.send(n)
+	// notifications are held while dispatch is disabled, or sent now if
+	// they can't be deferred
+	if j.flags.NotificationDispatchDisabled {
+		if err = j.deferSend(n, eventNotificationPausedDelay); err == nil {
+			grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+				"job":             eventNotificationJobName,
+				"notification_id": n.ID,
+				"message":         "notification dispatch is disabled, holding notification",
+			})
+			return
+		}
+		j.AddError(err)
+	}
+
+	// notifications to a recipient in quiet hours are sent when they end,
+	// or now if they can't be deferred
+	delay, err := quietHoursRemaining(n)
+	j.AddError(err)
+	if delay > 0 {
+		if err = j.deferSend(n, delay); err == nil {
+			j.audit(notification.NewAuditEntry(n, notification.AuditResultDeferred, errors.New("recipient is in quiet hours")))
+			return
+		}
+		j.AddError(err)
+	}
+
+	// notifications that were held back are rendered again, so that they
+	// aren't sent out of date
+	if j.Deferred {
+		if n = j.renderAgain(n); n == nil {
+			return
+		}
+	}
+
+	retryable, err := j.send(n)
+	// the entry is made before retrying, which counts the failed attempt
+	entry := notification.NewAuditEntry(n, notification.AuditResultSent, err)
+	if unavailable, ok := errors.Cause(err).(*util.SenderUnavailableError); ok {
+		deferErr := j.deferSend(n, unavailable.RetryAfter)
+		if deferErr == nil {
+			entry.Result = notification.AuditResultDeferred
+			j.audit(entry)
+			grip.Info(message.Fields{
+				"job_id":            j.ID(),
+				"notification_id":   n.ID,
+				"notification_type": n.Subscriber.Type,
+				"reason":            unavailable.Reason,
+				"retry_after":       unavailable.RetryAfter.String(),
+				"message":           "deferred notification",
+			})
+			return
+		}
+		j.AddError(deferErr)
+	}
 	grip.Error(message.WrapError(err, message.Fields{
 		"job_id":            j.ID(),
 		"notification_id":   n.ID,
 		"notification_type": n.Subscriber.Type,
+		"attempts":          n.Attempts + 1,
 		"message":           "send failed",
 	}))
+	if err != nil && retryable {
+		retryErr := j.retry(n, err)
+		if retryErr == nil {
+			entry.Result = notification.AuditResultRetrying
+			j.audit(entry)
+			return
+		}
+		// running out of attempts is expected, but failing to schedule
+		// an attempt is not
+		if n.Attempts < eventNotificationMaxAttempts {
+			j.AddError(retryErr)
+		}
+		j.AddError(n.MarkDeadLettered())
+	}
+	if err != nil {
+		entry.Result = notification.AuditResultFailed
+	}
+	j.audit(entry)
 	j.AddError(err)
 	j.AddError(n.MarkSent())
 	j.AddError(n.MarkError(err))
 }
 
-func (j *eventNotificationJob) send(n *notification.Notification) error {
+// audit records the attempt to send the notification. Failing to record
+// it does not fail the job, since the notification has been handled.
+func (j *eventNotificationJob) audit(entry *notification.AuditEntry) {
+	notificationSendOutcomes.Inc(entry.SubscriberType, entry.Result)
+	grip.Error(message.WrapError(entry.Insert(), message.Fields{
+		"job_id":          j.ID(),
+		"notification_id": entry.NotificationID,
+		"result":          entry.Result,
+		"message":         "failed to record notification audit entry",
+	}))
+}
+
+// renderAgain renders the notification against the current state of the
+// resource it's about, returning nil if it's no longer worth sending, or
+// the notification as it was if it can't be rendered again.
+func (j *eventNotificationJob) renderAgain(n *notification.Notification) *notification.Notification {
+	rendered, err := trigger.RenderAtDelivery(n)
+	if err != nil {
+		j.AddError(err)
+		return n
+	}
+	if rendered == nil {
+		err = errors.New("the failure it reported was fixed before it was sent")
+		j.audit(notification.NewAuditEntry(n, notification.AuditResultSuppressed, err))
+		j.AddError(n.MarkError(errors.Wrap(err, "notification suppressed")))
+		return nil
+	}
+	if rendered != n {
+		if err = n.SetPayload(rendered.Payload); err != nil {
+			j.AddError(err)
+			return rendered
+		}
+	}
+
+	return n
+}
+
+// quietHoursRemaining returns how long the quiet hours of the notification's
+// recipient last, or zero if they are not in quiet hours.
+func quietHoursRemaining(n *notification.Notification) (time.Duration, error) {
+	if n.Recipient == "" {
+		return 0, nil
+	}
+	u, err := user.FindOne(user.ById(n.Recipient))
+	if err != nil {
+		return 0, errors.Wrapf(err, "error fetching recipient '%s' of notification", n.Recipient)
+	}
+	if u == nil {
+		return 0, nil
+	}
+
+	return u.QuietHoursRemaining(n.Project, time.Now()), nil
+}
+
+// retry records the failed attempt to send the notification and schedules
+// another attempt, returning an error if no further attempt will be made.
+func (j *eventNotificationJob) retry(n *notification.Notification, sendErr error) error {
+	if err := n.MarkAttemptFailed(sendErr); err != nil {
+		return errors.WithStack(err)
+	}
+	if n.Attempts >= eventNotificationMaxAttempts {
+		return errors.Errorf("giving up on notification after %d attempts", n.Attempts)
+	}
+
+	queue := j.env.RemoteQueue()
+	if queue == nil {
+		return errors.New("no queue to retry notification on")
+	}
+
+	return errors.Wrap(queue.Put(newEventNotificationRetryJob(n.ID, n.Attempts)),
+		"failed to schedule retry of notification")
+}
+
+// deferSend schedules sending the notification after the delay, without
+// counting this as a failed attempt to send it.
+func (j *eventNotificationJob) deferSend(n *notification.Notification, delay time.Duration) error {
+	queue := j.env.RemoteQueue()
+	if queue == nil {
+		return errors.New("no queue to defer notification on")
+	}
+
+	return errors.Wrap(queue.Put(newEventNotificationDeferredJob(n.ID, delay)),
+		"failed to defer notification")
+}
+
+// send sends the notification, returning whether a failure to send it
+// may succeed if retried.
+func (j *eventNotificationJob) send(n *notification.Notification) (bool, error) {
+	if err := j.fetchAttachments(n); err != nil {
+		return true, errors.WithStack(err)
+	}
+	startsThread, err := j.threadSlackReply(n)
+	if err != nil {
+		return true, errors.WithStack(err)
+	}
+
 	c, err := n.Composer()
 	if err != nil {
-		return err
+		return false, err
 	}
 	if err = c.SetPriority(level.Notice); err != nil {
-		return errors.Wrap(err, "can't set priority")
+		return false, errors.Wrap(err, "can't set priority")
 	}
 	if !c.Loggable() {
-		return errors.New("composer is not loggable")
+		return false, errors.New("composer is not loggable")
 	}
 
 	key, err := n.SenderKey()
 	if err != nil {
-		return errors.Wrap(err, "can't build sender for notification")
+		return false, errors.Wrap(err, "can't build sender for notification")
 	}
 
 	sender, err := j.env.GetSender(key)
 	if err != nil {
-		return errors.Wrap(err, "error building sender for notification")
+		return false, errors.Wrap(err, "error building sender for notification")
+	}
+	if err = n.SetCredentialVersion(j.env.GetSenderCredentialVersion(key)); err != nil {
+		return false, errors.WithStack(err)
 	}
 
-	sender.Send(c)
+	var sendErr error
+	if reporter, ok := sender.(util.NotificationSender); ok {
+		sendErr = reporter.SendWithError(c)
+	} else {
+		sender.Send(c)
+	}
 
-	return nil
+	if recorder, ok := c.(util.WebhookDeliveryRecorder); ok && recorder.DeliveryStatus() != nil {
+		delivery := recorder.DeliveryStatus()
+		if err = n.SetDelivery(delivery); err != nil {
+			return false, errors.WithStack(err)
+		}
+		if !delivery.Succeeded() {
+			return delivery.Retryable(), errors.Errorf("webhook delivery failed after %d attempts (last status %d)", delivery.Attempts, delivery.StatusCode)
+		}
+	}
+	if recorder, ok := c.(util.SlackMessageRecorder); ok && recorder.PostedMessage() != nil {
+		if err = j.recordSlackMessage(n, recorder.PostedMessage(), startsThread); err != nil {
+			return false, errors.WithStack(err)
+		}
+	}
+	if sendErr != nil {
+		return true, errors.Wrap(sendErr, "failed to send notification")
+	}
+
+	return false, nil
+}
+
+// threadSlackReply makes a slack notification with a thread key a reply in
+// the thread for its key, if the thread has been started, and otherwise
+// returns true, since the notification starts the thread.
+func (j *eventNotificationJob) threadSlackReply(n *notification.Notification) (bool, error) {
+	payload, ok := n.Payload.(*notification.SlackPayload)
+	if !ok || payload == nil || payload.ThreadKey == "" || payload.ThreadTS != "" || payload.UpdateTS != "" {
+		return false, nil
+	}
+	target, ok := n.Subscriber.Target.(*string)
+	if !ok {
+		return false, nil
+	}
+
+	thread, err := notification.FindSlackThread(*target, payload.ThreadKey)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	if thread == nil {
+		return true, nil
+	}
+	payload.ThreadTS = thread.TS
+
+	return false, nil
+}
+
+// recordSlackMessage records the message posted for a slack notification,
+// and the thread it started, if any.
+func (j *eventNotificationJob) recordSlackMessage(n *notification.Notification, posted *util.SlackMessageRef, startsThread bool) error {
+	if err := n.SetSlackMessage(posted); err != nil {
+		return errors.WithStack(err)
+	}
+	if !startsThread {
+		return nil
+	}
+
+	target := *n.Subscriber.Target.(*string)
+	key := n.Payload.(*notification.SlackPayload).ThreadKey
+
+	return errors.WithStack(notification.NewSlackThread(target, key, posted.Channel, posted.TS).Insert())
+}
+
+// fetchAttachments fetches the contents of the attachments of an email
+// notification that are stored in S3.
+func (j *eventNotificationJob) fetchAttachments(n *notification.Notification) error {
+	email, ok := n.Payload.(*util.EvergreenEmail)
+	if !ok || email == nil {
+		return nil
+	}
+
+	conf := j.env.Settings().Notify.Attachments
+	auth := &aws.Auth{
+		AccessKey: conf.Key,
+		SecretKey: conf.Secret,
+	}
+
+	return errors.Wrap(thirdparty.FetchS3Attachments(auth, conf.Bucket, conf.Prefix, email.Attachments, util.AttachmentMaxSize),
+		"failed to fetch email attachments")
 }
 
 func (j *eventNotificationJob) checkDegradedMode(n *notification.Notification) error {
 	switch n.Subscriber.Type {
-	case event.GithubPullRequestSubscriberType:
+	case event.GithubPullRequestSubscriberType, event.GithubCheckSubscriberType:
 		return checkFlag(j.flags.GithubStatusAPIDisabled)
 
 	case event.SlackSubscriberType:
@@ -158,6 +467,9 @@ func (j *eventNotificationJob) checkDegradedMode(n *notification.Notification) e
 	case event.EmailSubscriberType:
 		return checkFlag(j.flags.EmailNotificationsDisabled)
 
+	case event.OpsgenieSubscriberType:
+		return checkFlag(j.flags.OpsgenieNotificationsDisabled)
+
 	default:
 		return errors.Errorf("unknown subscriber type: %s", n.Subscriber.Type)
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -49,7 +51,7 @@ func (s *eventNotificationSuite) SetupTest() {
 	s.env = &mock.Environment{}
 	s.NoError(s.env.Configure(s.ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
 
-	s.NoError(db.ClearCollections(notification.Collection, evergreen.ConfigCollection))
+	s.NoError(db.ClearCollections(notification.Collection, notification.SlackThreadsCollection, evergreen.ConfigCollection))
 
 	s.notifications = []notification.Notification{
 		{
@@ -181,6 +183,28 @@ func (s *eventNotificationSuite) TestDegradedMode() {
 	}
 }
 
+func (s *eventNotificationSuite) TestNotificationDispatchDisabled() {
+	flags := evergreen.ServiceFlags{
+		NotificationDispatchDisabled: true,
+	}
+	s.NoError(flags.Set())
+	s.Require().NoError(s.env.Remote.Start(s.ctx))
+
+	job := NewEventNotificationJob(s.webhook.ID).(*eventNotificationJob)
+	job.env = s.env
+	job.Run(s.ctx)
+	s.NoError(job.Error())
+
+	_, recv := s.env.InternalSender.GetMessageSafe()
+	s.False(recv)
+	n, err := notification.Find(s.webhook.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(n)
+	s.Zero(n.SentAt)
+	s.Empty(n.Error)
+	s.Equal(1, s.env.Remote.Stats().Total)
+}
+
 func (s *eventNotificationSuite) TestEvergreenWebhook() {
 	job := NewEventNotificationJob(s.webhook.ID).(*eventNotificationJob)
 	job.env = s.env
@@ -199,23 +223,71 @@ func (s *eventNotificationSuite) TestEvergreenWebhook() {
 }
 
 func (s *eventNotificationSuite) TestSlack() {
+	sent := notificationSendOutcomes.Value(event.SlackSubscriberType, notification.AuditResultSent)
 	job := NewEventNotificationJob(s.slack.ID).(*eventNotificationJob)
 	job.env = s.env
 	job.Run(s.ctx)
 
 	s.NoError(job.Error())
 	s.NotZero(s.notificationHasError(s.slack.ID, ""))
+	s.Equal(sent+1, notificationSendOutcomes.Value(event.SlackSubscriberType, notification.AuditResultSent))
 
 	msg, recv := s.env.InternalSender.GetMessageSafe()
 	s.True(recv)
 	s.NotPanics(func() {
-		slack := msg.Message.Raw().(*message.Slack)
+		slack := msg.Message.Raw().(*util.EvergreenSlack)
 		s.Equal("Hi", slack.Msg)
 		s.Equal("#evg-test-channel", slack.Target)
 		s.Empty(slack.Attachments)
+		s.Empty(slack.ThreadTS)
 	})
 }
 
+func (s *eventNotificationSuite) TestSlackThread() {
+	job := NewEventNotificationJob(s.slack.ID).(*eventNotificationJob)
+	job.env = s.env
+
+	n, err := notification.Find(s.slack.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(n)
+	payload := n.Payload.(*notification.SlackPayload)
+
+	// notifications without a thread key are not threaded
+	startsThread, err := job.threadSlackReply(n)
+	s.NoError(err)
+	s.False(startsThread)
+
+	// the first notification with a key starts the thread
+	payload.ThreadKey = "version1"
+	startsThread, err = job.threadSlackReply(n)
+	s.NoError(err)
+	s.True(startsThread)
+	s.Empty(payload.ThreadTS)
+	posted := &util.SlackMessageRef{Channel: "C123", TS: "1500000000.000100"}
+	s.NoError(job.recordSlackMessage(n, posted, startsThread))
+
+	n, err = notification.Find(s.slack.ID)
+	s.Require().NoError(err)
+	s.Equal(posted, n.SlackMessage)
+	thread, err := notification.FindSlackThread("#evg-test-channel", "version1")
+	s.NoError(err)
+	s.Require().NotNil(thread)
+	s.Equal("C123", thread.Channel)
+	s.Equal("1500000000.000100", thread.TS)
+
+	// later notifications with the key reply in the thread
+	payload = n.Payload.(*notification.SlackPayload)
+	payload.ThreadKey = "version1"
+	startsThread, err = job.threadSlackReply(n)
+	s.NoError(err)
+	s.False(startsThread)
+	s.Equal("1500000000.000100", payload.ThreadTS)
+
+	c, err := n.Composer()
+	s.NoError(err)
+	s.Equal("1500000000.000100", c.Raw().(*util.EvergreenSlack).ThreadTS)
+}
+
 func (s *eventNotificationSuite) TestJIRAComment() {
 	job := NewEventNotificationJob(s.jiraComment.ID).(*eventNotificationJob)
 	job.env = s.env
@@ -269,3 +341,27 @@ func (s *eventNotificationSuite) TestSendFailureResultsInNoMessages() {
 		s.NotZero(s.notificationHasError(s.webhook.ID, "^composer is not loggable$"))
 	}
 }
+
+func TestEventNotificationRetryJob(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Now()
+	j := newEventNotificationRetryJob("1234", 1).(*eventNotificationJob)
+	assert.Equal("event-send:1234:1", j.ID())
+	assert.Equal("1234", j.NotificationID)
+	assert.WithinDuration(start.Add(eventNotificationRetryDelay), j.TimeInfo().WaitUntil, time.Second)
+
+	j = newEventNotificationRetryJob("1234", 3).(*eventNotificationJob)
+	assert.Equal("event-send:1234:3", j.ID())
+	assert.WithinDuration(start.Add(4*eventNotificationRetryDelay), j.TimeInfo().WaitUntil, time.Second)
+}
+
+func TestEventNotificationDeferredJob(t *testing.T) {
+	assert := assert.New(t)
+
+	start := time.Now()
+	j := newEventNotificationDeferredJob("1234", 30*time.Second).(*eventNotificationJob)
+	assert.True(strings.HasPrefix(j.ID(), "event-send:1234:deferred-"))
+	assert.Equal("1234", j.NotificationID)
+	assert.WithinDuration(start.Add(30*time.Second), j.TimeInfo().WaitUntil, time.Second)
+}
@@ -0,0 +1,216 @@
+package units
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventWebhookDispatchJobName = "event-webhook-dispatch"
+
+	// eventWebhookBatchSize is the most events delivered in one request,
+	// and eventWebhookMaxBatches the most requests made by one job.
+	eventWebhookBatchSize  = 100
+	eventWebhookMaxBatches = 10
+
+	eventWebhookIDHeader     = "X-Evergreen-Event-Webhook-ID"
+	eventWebhookCursorHeader = "X-Evergreen-Event-Cursor"
+)
+
+func init() {
+	registry.AddJobType(eventWebhookDispatchJobName, func() amboy.Job { return makeEventWebhookDispatchJob() })
+}
+
+type eventWebhookDispatchJob struct {
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+	WebhookID string `bson:"webhook_id" json:"webhook_id" yaml:"webhook_id"`
+
+	env evergreen.Environment
+}
+
+func makeEventWebhookDispatchJob() *eventWebhookDispatchJob {
+	j := &eventWebhookDispatchJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    eventWebhookDispatchJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// NewEventWebhookDispatchJob makes a job that delivers the events after the
+// event webhook's cursor to it, in order, advancing the cursor past each
+// batch that's delivered. Delivery stops at the first batch that fails, so
+// that the webhook never receives an event before the ones logged before
+// it.
+func NewEventWebhookDispatchJob(webhookID, ts string) amboy.Job {
+	j := makeEventWebhookDispatchJob()
+	j.WebhookID = webhookID
+
+	j.SetID(fmt.Sprintf("%s:%s:%s", eventWebhookDispatchJobName, webhookID, ts))
+
+	return j
+}
+
+func (j *eventWebhookDispatchJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	flags, err := evergreen.GetServiceFlags()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if flags.EventProcessingDisabled || flags.NotificationDispatchDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     eventWebhookDispatchJobName,
+			"message": "events processing or notification dispatch is disabled, not dispatching events to webhooks",
+		})
+		return
+	}
+
+	w, err := event.FindEventWebhook(j.WebhookID)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if w == nil {
+		// the webhook was removed
+		return
+	}
+
+	sender, err := j.env.GetSender(evergreen.SenderEvergreenWebhook)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error building sender for event webhook"))
+		return
+	}
+
+	delivered := 0
+	for i := 0; i < eventWebhookMaxBatches; i++ {
+		if ctx.Err() != nil {
+			j.AddError(errors.New("event webhook dispatch canceled"))
+			break
+		}
+
+		events, err := w.Events(eventWebhookBatchSize)
+		if err != nil {
+			j.AddError(err)
+			break
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		deliveryErr := j.deliver(sender, w, events)
+		j.AddError(w.RecordAttempt(time.Now().Truncate(time.Millisecond), deliveryErr))
+		if deliveryErr != nil {
+			j.AddError(errors.Wrapf(deliveryErr, "failed to deliver events to event webhook '%s'", w.ID))
+			break
+		}
+
+		last := events[len(events)-1]
+		advanced, err := w.AdvanceCursor(event.EventCursor{Timestamp: last.Timestamp, EventID: last.ID})
+		if err != nil {
+			j.AddError(err)
+			break
+		}
+		if !advanced {
+			grip.Info(message.Fields{
+				"job_id":     j.ID(),
+				"job":        eventWebhookDispatchJobName,
+				"webhook_id": w.ID,
+				"message":    "event webhook cursor moved during delivery, stopping",
+			})
+			break
+		}
+		delivered += len(events)
+
+		if len(events) < eventWebhookBatchSize {
+			break
+		}
+	}
+
+	grip.Info(message.Fields{
+		"job_id":     j.ID(),
+		"job":        eventWebhookDispatchJobName,
+		"message":    "dispatched events to webhook",
+		"webhook_id": w.ID,
+		"events":     delivered,
+	})
+}
+
+// deliver sends the batch of events to the webhook, signed with the
+// webhook's secret.
+func (j *eventWebhookDispatchJob) deliver(sender send.Sender, w *event.EventWebhook, events []event.EventLogEntry) error {
+	batch := event.WebhookEventBatch{
+		WebhookID: w.ID,
+		Cursor:    events[len(events)-1].ID,
+		Events:    make([]event.WebhookEvent, 0, len(events)),
+	}
+	for i := range events {
+		batch.Events = append(batch.Events, event.NewWebhookEvent(&events[i]))
+	}
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal events")
+	}
+
+	// the delivery ID is the same when a batch is redelivered, so that
+	// receivers can discard duplicates
+	c := util.NewWebhookMessage(fmt.Sprintf("%s-%s", w.ID, batch.Cursor), w.URL, w.Secret, body, http.Header{
+		"Content-Type":           []string{"application/json"},
+		eventWebhookIDHeader:     []string{w.ID},
+		eventWebhookCursorHeader: []string{batch.Cursor},
+	})
+	if err = c.SetPriority(level.Notice); err != nil {
+		return errors.Wrap(err, "can't set priority")
+	}
+	if !c.Loggable() {
+		return errors.New("composer is not loggable")
+	}
+
+	var sendErr error
+	if reporter, ok := sender.(util.NotificationSender); ok {
+		sendErr = reporter.SendWithError(c)
+	} else {
+		sender.Send(c)
+	}
+	if sendErr != nil {
+		return errors.Wrap(sendErr, "failed to send events")
+	}
+
+	recorder, ok := c.(util.WebhookDeliveryRecorder)
+	if !ok || recorder.DeliveryStatus() == nil {
+		return errors.New("events were not sent")
+	}
+	delivery := recorder.DeliveryStatus()
+	if !delivery.Succeeded() {
+		return errors.Errorf("webhook delivery failed after %d attempts (last status %d)", delivery.Attempts, delivery.StatusCode)
+	}
+
+	return nil
+}
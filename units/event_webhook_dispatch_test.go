@@ -0,0 +1,104 @@
+package units
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventWebhookDispatchJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	evergreen.ResetEnvironment()
+	env := evergreen.GetEnvironment()
+	require.NoError(env.Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings), nil))
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(evergreen.ConfigCollection, event.AllLogCollection, event.EventWebhooksCollection))
+
+	status := http.StatusOK
+	batches := []event.WebhookEventBatch{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(err)
+		batch := event.WebhookEventBatch{}
+		assert.NoError(json.Unmarshal(body, &batch))
+		assert.Equal(batch.Cursor, r.Header.Get(eventWebhookCursorHeader))
+		batches = append(batches, batch)
+		hash, err := util.CalculateHMACHash([]byte("secret"), body)
+		assert.NoError(err)
+		assert.Equal(hash, r.Header.Get("X-Evergreen-Signature"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	w, err := event.NewEventWebhook(server.URL, nil, nil, "me")
+	require.NoError(err)
+	w.Secret = []byte("secret")
+	w.Cursor = event.EventCursor{Timestamp: time.Now().Add(-time.Hour).Truncate(time.Millisecond)}
+	require.NoError(w.Insert())
+
+	logger := event.NewDBEventLogger(event.AllLogCollection)
+	ts := time.Now().Add(-time.Minute).Truncate(time.Millisecond)
+	for i, id := range []string{"e0", "e1", "e2"} {
+		require.NoError(logger.LogEvent(&event.EventLogEntry{
+			ID:           id,
+			ResourceType: event.ResourceTypeTask,
+			ResourceId:   "t0",
+			EventType:    event.TaskStarted,
+			Timestamp:    ts.Add(time.Duration(i) * time.Second),
+			Data:         &event.TaskEventData{},
+		}))
+	}
+
+	j := NewEventWebhookDispatchJob(w.ID, "1")
+	j.Run(ctx)
+	assert.NoError(j.Error())
+	require.Len(batches, 1)
+	assert.Equal("e2", batches[0].Cursor)
+	require.Len(batches[0].Events, 3)
+	for i, id := range []string{"e0", "e1", "e2"} {
+		assert.Equal(id, batches[0].Events[i].ID)
+	}
+
+	w, err = event.FindEventWebhook(w.ID)
+	assert.NoError(err)
+	require.NotNil(w)
+	assert.Equal("e2", w.Cursor.EventID)
+	assert.Empty(w.LastError)
+
+	// a rejected batch doesn't advance the cursor
+	require.NoError(logger.LogEvent(&event.EventLogEntry{
+		ID:           "e3",
+		ResourceType: event.ResourceTypeTask,
+		ResourceId:   "t0",
+		EventType:    event.TaskFinished,
+		Timestamp:    ts.Add(5 * time.Second),
+		Data:         &event.TaskEventData{},
+	}))
+	status = http.StatusBadRequest
+	j = NewEventWebhookDispatchJob(w.ID, "2")
+	j.Run(ctx)
+	assert.Error(j.Error())
+
+	w, err = event.FindEventWebhook(w.ID)
+	assert.NoError(err)
+	require.NotNil(w)
+	assert.Equal("e2", w.Cursor.EventID)
+	assert.NotEmpty(w.LastError)
+}
@@ -0,0 +1,210 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	staticHostHealthCheckJobName = "static-host-health-check"
+
+	// staticHostMinDiskFreeMB is the least free disk space a healthy static
+	// host has in its distro's working directory.
+	staticHostMinDiskFreeMB = 1024
+	// staticHostMaxClockDrift is the most a healthy static host's clock
+	// differs from the app server's.
+	staticHostMaxClockDrift = time.Minute
+	// staticHostHealthCheckFailureLimit is the number of health checks in a
+	// row a static host fails before it is quarantined.
+	staticHostHealthCheckFailureLimit = 3
+	staticHostHealthCheckTimeout      = time.Minute
+)
+
+func init() {
+	registry.AddJobType(staticHostHealthCheckJobName, func() amboy.Job {
+		return makeStaticHostHealthCheckJob()
+	})
+}
+
+type staticHostHealthCheckJob struct {
+	HostID   string `bson:"host_id" json:"host_id" yaml:"host_id"`
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	host *host.Host
+	env  evergreen.Environment
+}
+
+func makeStaticHostHealthCheckJob() *staticHostHealthCheckJob {
+	j := &staticHostHealthCheckJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    staticHostHealthCheckJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewStaticHostHealthCheckJob creates a job that checks over SSH that a
+// static host has enough free disk space, that its clock agrees with the app
+// server's and that it has the current agent. Hosts with an outdated agent
+// are flagged for a new one, and hosts that fail too many checks in a row
+// are quarantined.
+func NewStaticHostHealthCheckJob(env evergreen.Environment, h host.Host, id string) amboy.Job {
+	j := makeStaticHostHealthCheckJob()
+	j.host = &h
+	j.HostID = h.Id
+	j.env = env
+	j.SetID(fmt.Sprintf("%s.%s.%s", staticHostHealthCheckJobName, j.HostID, id))
+	return j
+}
+
+func (j *staticHostHealthCheckJob) Run(ctx context.Context) {
+	var err error
+	defer j.MarkComplete()
+
+	if j.host == nil {
+		j.host, err = host.FindOneId(j.HostID)
+		if err != nil {
+			j.AddError(err)
+			return
+		}
+		if j.host == nil {
+			j.AddError(errors.Errorf("could not find host %s for job %s", j.HostID, j.ID()))
+			return
+		}
+	}
+	if j.host.Status != evergreen.HostRunning {
+		return
+	}
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	cloudHost, err := cloud.GetCloudHost(ctx, j.host, j.env.Settings())
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error getting cloud host for %s", j.HostID))
+		return
+	}
+	sshOptions, err := cloudHost.GetSSHOptions()
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error getting ssh options for host %s", j.HostID))
+		return
+	}
+
+	before := time.Now()
+	output, sshErr := j.host.RunSSHCommandWithTimeout(ctx, healthCheckCommand(j.host), sshOptions, staticHostHealthCheckTimeout)
+	after := time.Now()
+	check := host.HealthCheck{CheckedAt: after}
+	if sshErr != nil {
+		check.Failures = []string{fmt.Sprintf("could not run health check on host: %s", sshErr.Error())}
+	} else {
+		check = evaluateHealthCheck(output, before, after)
+	}
+
+	if err = j.host.SetHealthCheck(check); err != nil {
+		j.AddError(errors.Wrapf(err, "error recording health check of host %s", j.HostID))
+		return
+	}
+
+	reprovision := sshErr == nil && check.AgentVersion != evergreen.ClientVersion
+	quarantine := j.host.HealthCheck.ConsecutiveFailures >= staticHostHealthCheckFailureLimit
+	msg := message.Fields{
+		"message":       "static host failed health check",
+		"host":          j.HostID,
+		"distro":        j.host.Distro.Id,
+		"failures":      check.Failures,
+		"count":         j.host.HealthCheck.ConsecutiveFailures,
+		"agent_version": check.AgentVersion,
+		"reprovision":   reprovision,
+		"quarantine":    quarantine,
+		"job":           j.ID(),
+	}
+	grip.WarningWhen(quarantine, msg)
+	grip.InfoWhen(!quarantine && (reprovision || !check.Healthy()), msg)
+
+	if reprovision {
+		j.AddError(errors.Wrapf(j.host.SetNeedsNewAgent(true), "error flagging host %s for a new agent", j.HostID))
+	}
+	if quarantine {
+		logs := fmt.Sprintf("host failed %d health checks in a row: %s",
+			j.host.HealthCheck.ConsecutiveFailures, strings.Join(check.Failures, "; "))
+		j.AddError(errors.Wrapf(j.host.SetQuarantined(evergreen.User, logs), "error quarantining host %s", j.HostID))
+	}
+}
+
+// healthCheckCommand returns the command that reports the free disk space of
+// the host's working directory, the time on the host and the version of its
+// agent, as one "key=value" line each.
+func healthCheckCommand(h *host.Host) string {
+	workDir := h.Distro.WorkDir
+	if workDir == "" {
+		workDir = "~"
+	}
+	return strings.Join([]string{
+		fmt.Sprintf("echo disk_free_mb=$(df -Pm %s | tail -1 | awk '{print $4}')", workDir),
+		"echo time=$(date +%s)",
+		fmt.Sprintf("echo agent_version=$(%s version)", filepath.Join("~", h.Distro.BinaryName())),
+	}, "; ")
+}
+
+// evaluateHealthCheck checks the output of the health check command, which
+// ran on the host between before and after.
+func evaluateHealthCheck(output string, before, after time.Time) host.HealthCheck {
+	check := host.HealthCheck{CheckedAt: after}
+	values := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 {
+			values[parts[0]] = strings.TrimSpace(parts[1])
+		}
+	}
+
+	diskFree, err := strconv.Atoi(values["disk_free_mb"])
+	if err != nil {
+		check.Failures = append(check.Failures, fmt.Sprintf("could not determine free disk space from '%s'", values["disk_free_mb"]))
+	} else {
+		check.DiskFreeMB = diskFree
+		if diskFree < staticHostMinDiskFreeMB {
+			check.Failures = append(check.Failures, fmt.Sprintf("only %d MB of disk space is free", diskFree))
+		}
+	}
+
+	secs, err := strconv.ParseInt(values["time"], 10, 64)
+	if err != nil {
+		check.Failures = append(check.Failures, fmt.Sprintf("could not determine time from '%s'", values["time"]))
+	} else {
+		// the host's time is only precise to the second, and was read at
+		// some point while the command ran
+		hostTime := time.Unix(secs, 0)
+		if earliest := before.Truncate(time.Second); hostTime.Before(earliest) {
+			check.ClockDrift = hostTime.Sub(earliest)
+		} else if hostTime.After(after) {
+			check.ClockDrift = hostTime.Sub(after)
+		}
+		if check.ClockDrift > staticHostMaxClockDrift || check.ClockDrift < -staticHostMaxClockDrift {
+			check.Failures = append(check.Failures, fmt.Sprintf("clock is off by %s", check.ClockDrift))
+		}
+	}
+
+	check.AgentVersion = values["agent_version"]
+
+	return check
+}
@@ -0,0 +1,61 @@
+package units
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthCheckCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	h := &host.Host{Distro: distro.Distro{WorkDir: "/data/mci"}}
+	cmd := healthCheckCommand(h)
+	assert.Contains(cmd, "df -Pm /data/mci")
+	assert.Contains(cmd, "date +%s")
+	assert.Contains(cmd, "~/evergreen version")
+
+	h.Distro.WorkDir = ""
+	assert.Contains(healthCheckCommand(h), "df -Pm ~")
+}
+
+func TestEvaluateHealthCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	before := time.Now()
+	after := before.Add(2 * time.Second)
+	output := func(diskFree string, hostTime time.Time) string {
+		return fmt.Sprintf("disk_free_mb=%s\r\ntime=%d\r\nagent_version=%s\r\n", diskFree, hostTime.Unix(), evergreen.ClientVersion)
+	}
+
+	check := evaluateHealthCheck(output("20480", before.Add(time.Second)), before, after)
+	assert.True(check.Healthy())
+	assert.Equal(20480, check.DiskFreeMB)
+	assert.Equal(time.Duration(0), check.ClockDrift)
+	assert.Equal(evergreen.ClientVersion, check.AgentVersion)
+	assert.Equal(after, check.CheckedAt)
+
+	check = evaluateHealthCheck(output("100", before), before, after)
+	assert.False(check.Healthy())
+	assert.Len(check.Failures, 1)
+	assert.Equal(100, check.DiskFreeMB)
+
+	check = evaluateHealthCheck(output("20480", after.Add(5*time.Minute)), before, after)
+	assert.False(check.Healthy())
+	assert.Len(check.Failures, 1)
+	assert.True(check.ClockDrift > 4*time.Minute)
+
+	check = evaluateHealthCheck(output("20480", before.Add(-5*time.Minute)), before, after)
+	assert.False(check.Healthy())
+	assert.True(check.ClockDrift < -4*time.Minute)
+
+	check = evaluateHealthCheck("bash: df: command not found\r\n", before, after)
+	assert.False(check.Healthy())
+	assert.Len(check.Failures, 2)
+	assert.Empty(check.AgentVersion)
+}
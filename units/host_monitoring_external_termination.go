@@ -132,6 +132,11 @@ func (j *hostMonitorExternalStateCheckJob) Run(ctx context.Context) {
 
 		// the instance was terminated from outside our control
 		j.AddError(errors.Wrapf(j.host.SetTerminated("external"), "error setting host %s terminated", j.HostID))
+
+		// spot and preemptible instances are terminated when their capacity
+		// is reclaimed, in which case an on-demand host may replace them
+		_, err = cloud.ReplaceReclaimedHost(cloudHost.CloudMgr, j.host)
+		j.AddError(errors.Wrapf(err, "error replacing reclaimed host %s", j.HostID))
 	default:
 		grip.Warning(message.Fields{
 			"message":      "host found with unexpected status",
@@ -3,10 +3,12 @@ package units
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
@@ -15,13 +17,12 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
 )
 
 const (
 	idleHostJobName = "idle-host-termination"
 
-	// idleTimeCutoff is the amount of time we wait for an idle host to be marked as idle.
-	idleTimeCutoff            = 4 * time.Minute
 	idleWaitingForAgentCutoff = 10 * time.Minute
 
 	// MaxTimeNextPayment is the amount of time we wait to have left before marking a host as idle
@@ -43,6 +44,7 @@ type idleHostJob struct {
 	env      evergreen.Environment
 	settings *evergreen.Settings
 	host     *host.Host
+	policy   *distro.IdlePolicy
 }
 
 func makeIdleHostJob() *idleHostJob {
@@ -86,6 +88,18 @@ func (j *idleHostJob) Run(ctx context.Context) {
 		j.settings = j.env.Settings()
 	}
 
+	if j.policy == nil {
+		d, err := distro.FindOne(distro.ById(j.host.Distro.Id))
+		if err != nil && err != mgo.ErrNotFound {
+			j.AddError(errors.Wrapf(err, "error finding distro for host %s", j.host.Id))
+			return
+		}
+		if err == mgo.ErrNotFound {
+			d = j.host.Distro
+		}
+		j.policy = &d.IdlePolicy
+	}
+
 	if j.HasErrors() {
 		return
 	}
@@ -104,6 +118,15 @@ func (j *idleHostJob) Run(ctx context.Context) {
 		return
 	}
 
+	// a draining host is terminated as soon as it finishes its last task
+	if j.host.Draining {
+		j.Terminated = true
+		tjob := NewHostTerminationJob(j.env, *j.host)
+		tjob.Run(ctx)
+		j.AddError(tjob.Error())
+		return
+	}
+
 	// ask the host how long it has been idle
 	idleTime := j.host.IdleTime()
 
@@ -138,6 +161,7 @@ func (j *idleHostJob) Run(ctx context.Context) {
 	}
 
 	// if we haven't heard from the host or it's been idle for longer than the cutoff, we should terminate
+	idleTimeCutoff := j.policy.MaxIdleTime()
 	if communicationTime >= idleTimeCutoff || idleTime >= idleTimeCutoff {
 		j.Terminated = true
 		tjob := NewHostTerminationJob(j.env, *j.host)
@@ -145,3 +169,36 @@ func (j *idleHostJob) Run(ctx context.Context) {
 		j.AddError(tjob.Error())
 	}
 }
+
+// idleHostsToCheck returns the idle hosts of a distro that may be terminated
+// under its idle policy: every draining host, and the longest idle of the
+// others that the distro can lose without dropping below its minimum pool
+// size, at most its scale down step at a time.
+func idleHostsToCheck(distroID string, idle []host.Host) ([]host.Host, error) {
+	d, err := distro.FindOne(distro.ById(distroID))
+	if err != nil && err != mgo.ErrNotFound {
+		return nil, errors.Wrapf(err, "error finding distro %s", distroID)
+	}
+
+	draining := []host.Host{}
+	candidates := []host.Host{}
+	for _, h := range idle {
+		if h.Draining {
+			draining = append(draining, h)
+		} else {
+			candidates = append(candidates, h)
+		}
+	}
+
+	numRunning, err := host.CountRunningHosts(distroID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error counting running hosts for distro %s", distroID)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].IdleTime() > candidates[j].IdleTime()
+	})
+	n := d.IdlePolicy.NumToTerminate(numRunning-len(draining), len(candidates))
+
+	return append(draining, candidates[:n]...), nil
+}
@@ -2,17 +2,21 @@ package units
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/host"
 	modelUtil "github.com/evergreen-ci/evergreen/model/testutil"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/queue"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func flagIdleHosts(ctx context.Context, env evergreen.Environment) ([]string, error) {
@@ -175,3 +179,62 @@ func TestFlaggingIdleHosts(t *testing.T) {
 		})
 	})
 }
+
+func TestIdleHostsToCheck(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(host.Collection, distro.Collection))
+	defer func() {
+		assert.NoError(db.ClearCollections(host.Collection, distro.Collection))
+	}()
+
+	d := distro.Distro{
+		Id:         "d",
+		PoolSize:   10,
+		IdlePolicy: distro.IdlePolicy{MinPoolSize: 2, ScaleDownStep: 1},
+	}
+	require.NoError(d.Insert())
+
+	now := time.Now()
+	idle := []host.Host{}
+	for i, idleMins := range []int{5, 30, 10} {
+		h := host.Host{
+			Id:                    fmt.Sprintf("h%d", i),
+			Distro:                d,
+			Provider:              evergreen.ProviderNameMock,
+			Status:                evergreen.HostRunning,
+			StartedBy:             evergreen.User,
+			CreationTime:          now.Add(-time.Hour),
+			LastTask:              "t",
+			LastTaskCompletedTime: now.Add(-time.Duration(idleMins) * time.Minute),
+		}
+		require.NoError(h.Insert())
+		idle = append(idle, h)
+	}
+	draining := host.Host{
+		Id:        "draining",
+		Distro:    d,
+		Provider:  evergreen.ProviderNameMock,
+		Status:    evergreen.HostRunning,
+		StartedBy: evergreen.User,
+		Draining:  true,
+	}
+	require.NoError(draining.Insert())
+	idle = append(idle, draining)
+
+	// the draining host is always checked, along with the longest idle of
+	// the rest, one at a time
+	toCheck, err := idleHostsToCheck(d.Id, idle)
+	require.NoError(err)
+	require.Len(toCheck, 2)
+	assert.Equal("draining", toCheck[0].Id)
+	assert.Equal("h1", toCheck[1].Id)
+
+	// hosts are kept once the pool is at its minimum size
+	require.NoError(d.SetIdlePolicy(distro.IdlePolicy{MinPoolSize: 3}))
+	toCheck, err = idleHostsToCheck(d.Id, idle)
+	require.NoError(err)
+	require.Len(toCheck, 1)
+	assert.Equal("draining", toCheck[0].Id)
+}
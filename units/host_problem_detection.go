@@ -0,0 +1,163 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const hostProblemDetectionJobName = "host-problem-detection"
+
+func init() {
+	registry.AddJobType(hostProblemDetectionJobName, func() amboy.Job {
+		return makeHostProblemDetectionJob()
+	})
+}
+
+type hostProblemDetectionJob struct {
+	job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+
+	env evergreen.Environment
+}
+
+func makeHostProblemDetectionJob() *hostProblemDetectionJob {
+	j := &hostProblemDetectionJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    hostProblemDetectionJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewHostProblemDetectionJob creates a job that correlates the recent system
+// failures of tasks to the hosts they ran on, and takes the hosts that fail
+// too many tasks out of service. The failures of containers also count
+// against their parent, since a bad parent fails the tasks of all of its
+// containers.
+func NewHostProblemDetectionJob(env evergreen.Environment, id string) amboy.Job {
+	j := makeHostProblemDetectionJob()
+	j.env = env
+	j.SetID(fmt.Sprintf("%s.%s", hostProblemDetectionJobName, id))
+	return j
+}
+
+func (j *hostProblemDetectionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	conf := j.env.Settings().HostInit
+	if conf.ProblemHostFailureThreshold <= 0 {
+		return
+	}
+
+	window := conf.ProblemHostWindow()
+	failed, err := task.Find(task.BySystemFailedSince(time.Now().Add(-window)))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "problem finding system failed tasks"))
+		return
+	}
+	if len(failed) < conf.ProblemHostFailureThreshold {
+		return
+	}
+
+	hostIds := []string{}
+	for _, t := range failed {
+		hostIds = append(hostIds, t.HostId)
+	}
+	hosts, err := host.Find(host.ByIds(hostIds))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "problem finding hosts of system failed tasks"))
+		return
+	}
+
+	failuresByHost := problemHosts(failed, hosts, conf.ProblemHostFailureThreshold)
+	if len(failuresByHost) == 0 {
+		return
+	}
+	problemHostIds := make([]string, 0, len(failuresByHost))
+	for id := range failuresByHost {
+		problemHostIds = append(problemHostIds, id)
+	}
+	problems, err := host.Find(host.ByIds(problemHostIds))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "problem finding problem hosts"))
+		return
+	}
+
+	for _, h := range problems {
+		if h.Status != evergreen.HostRunning {
+			continue
+		}
+		j.AddError(disableProblemHost(&h, failuresByHost[h.Id], window, conf.TerminateProblemHosts))
+	}
+}
+
+// problemHosts returns the system failed tasks of each host that failed at
+// least threshold tasks, either itself or through its containers, keyed by
+// host ID.
+func problemHosts(failed []task.Task, hosts []host.Host, threshold int) map[string][]string {
+	parents := map[string]string{}
+	for _, h := range hosts {
+		if h.ParentID != "" {
+			parents[h.Id] = h.ParentID
+		}
+	}
+
+	failures := map[string][]string{}
+	for _, t := range failed {
+		failures[t.HostId] = append(failures[t.HostId], t.Id)
+		if parent, ok := parents[t.HostId]; ok {
+			failures[parent] = append(failures[parent], t.Id)
+		}
+	}
+
+	for id, tasks := range failures {
+		if len(tasks) < threshold {
+			delete(failures, id)
+			continue
+		}
+		sort.Strings(tasks)
+	}
+	return failures
+}
+
+// disableProblemHost quarantines the host, or decommissions it if problem
+// hosts are to be replaced and the host can be.
+func disableProblemHost(h *host.Host, failedTasks []string, window time.Duration, terminate bool) error {
+	logs := fmt.Sprintf("%d tasks system failed on the host in the last %s", len(failedTasks), window)
+	event.LogHostProblemDetected(h.Id, failedTasks, logs)
+
+	canReplace := terminate && h.Provider != evergreen.ProviderNameStatic && !h.HasContainers
+	grip.Warning(message.Fields{
+		"message":      "taking problem host out of service",
+		"host":         h.Id,
+		"distro":       h.Distro.Id,
+		"provider":     h.Provider,
+		"failed_tasks": failedTasks,
+		"replacing":    canReplace,
+	})
+
+	if canReplace {
+		return errors.Wrapf(h.SetDecommissioned(evergreen.User, logs), "problem decommissioning host '%s'", h.Id)
+	}
+	return errors.Wrapf(h.SetQuarantined(evergreen.User, logs), "problem quarantining host '%s'", h.Id)
+}
@@ -0,0 +1,84 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProblemHosts(t *testing.T) {
+	assert := assert.New(t)
+
+	hosts := []host.Host{
+		{Id: "container1", ParentID: "parent"},
+		{Id: "container2", ParentID: "parent"},
+		{Id: "vm"},
+	}
+	failed := []task.Task{
+		{Id: "t1", HostId: "container1"},
+		{Id: "t2", HostId: "container2"},
+		{Id: "t3", HostId: "container2"},
+		{Id: "t4", HostId: "vm"},
+	}
+
+	// the parent is blamed for the failures of all of its containers
+	assert.Equal(map[string][]string{
+		"parent": {"t1", "t2", "t3"},
+	}, problemHosts(failed, hosts, 3))
+
+	assert.Equal(map[string][]string{
+		"parent":     {"t1", "t2", "t3"},
+		"container2": {"t2", "t3"},
+	}, problemHosts(failed, hosts, 2))
+
+	assert.Empty(problemHosts(failed, hosts, 4))
+}
+
+func TestDisableProblemHost(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(host.Collection, event.AllLogCollection))
+	defer func() {
+		assert.NoError(db.ClearCollections(host.Collection, event.AllLogCollection))
+	}()
+
+	static := host.Host{Id: "static", Provider: evergreen.ProviderNameStatic, Status: evergreen.HostRunning}
+	parent := host.Host{Id: "parent", Provider: evergreen.ProviderNameMock, Status: evergreen.HostRunning, HasContainers: true}
+	vm := host.Host{Id: "vm", Provider: evergreen.ProviderNameMock, Status: evergreen.HostRunning}
+	for _, h := range []host.Host{static, parent, vm} {
+		require.NoError(h.Insert())
+	}
+
+	// hosts that can't be replaced are quarantined
+	require.NoError(disableProblemHost(&static, []string{"t1"}, time.Hour, true))
+	assert.Equal(evergreen.HostQuarantined, static.Status)
+	require.NoError(disableProblemHost(&parent, []string{"t1"}, time.Hour, true))
+	assert.Equal(evergreen.HostQuarantined, parent.Status)
+
+	require.NoError(disableProblemHost(&vm, []string{"t1", "t2"}, time.Hour, true))
+	dbHost, err := host.FindOneId(vm.Id)
+	require.NoError(err)
+	assert.Equal(evergreen.HostDecommissioned, dbHost.Status)
+
+	events, err := event.Find(event.AllLogCollection, event.MostRecentHostEvents(vm.Id, 10))
+	require.NoError(err)
+	found := false
+	for _, e := range events {
+		if e.EventType == event.EventHostProblemDetected {
+			found = true
+			data, ok := e.Data.(*event.HostEventData)
+			require.True(ok)
+			assert.Equal([]string{"t1", "t2"}, data.FailedTasks)
+		}
+	}
+	assert.True(found)
+}
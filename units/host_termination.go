@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -261,6 +262,19 @@ func (j *hostTerminationJob) Run(ctx context.Context) {
 		}
 	}
 
+	// hosts are terminated even if their pre-terminate hook fails, so that
+	// a broken hook cannot leak hosts.
+	if j.host.Host != "" {
+		if err := cloudHost.RunHook(ctx, distro.HookPreTerminate, settings); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"job_type": j.Type().Name,
+				"message":  "Error running pre-terminate hook",
+				"host":     j.host.Id,
+				"distro":   j.host.Distro.Id,
+			}))
+		}
+	}
+
 	if err := cloudHost.TerminateInstance(ctx, evergreen.User); err != nil {
 		j.AddError(err)
 		grip.Error(message.WrapError(err, message.Fields{
@@ -0,0 +1,279 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const parentImageBakeJobName = "parent-image-bake"
+
+func init() {
+	registry.AddJobType(parentImageBakeJobName, func() amboy.Job {
+		return makeParentImageBakeJob()
+	})
+}
+
+type parentImageBakeJob struct {
+	PoolID   string `bson:"pool_id" json:"pool_id" yaml:"pool_id"`
+	job.Base `bson:"base" json:"base" yaml:"base"`
+
+	// cache
+	pool     *evergreen.ContainerPool
+	env      evergreen.Environment
+	settings *evergreen.Settings
+}
+
+func makeParentImageBakeJob() *parentImageBakeJob {
+	j := &parentImageBakeJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    parentImageBakeJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// NewParentImageBakeJob creates a job that bakes a new image from one of the
+// running parents of a container pool once the pool's bake interval has
+// elapsed, and rolls the newest available baked image out to the pool's
+// parent distro so that new parents start from it.
+func NewParentImageBakeJob(env evergreen.Environment, pool *evergreen.ContainerPool, id string) amboy.Job {
+	j := makeParentImageBakeJob()
+
+	j.env = env
+	j.pool = pool
+	j.PoolID = pool.Id
+
+	j.SetID(fmt.Sprintf("%s.%s.%s", parentImageBakeJobName, j.PoolID, id))
+	return j
+}
+
+func (j *parentImageBakeJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	if j.settings == nil {
+		j.settings = j.env.Settings()
+	}
+	if j.pool == nil {
+		j.pool = j.settings.ContainerPools.GetContainerPool(j.PoolID)
+		if j.pool == nil {
+			j.AddError(errors.Errorf("unable to find container pool '%s'", j.PoolID))
+			return
+		}
+	}
+
+	if j.pool.ImageBakeIntervalHours <= 0 {
+		return
+	}
+
+	d, err := distro.FindOne(distro.ById(j.pool.Distro))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding distro '%s'", j.pool.Distro))
+		return
+	}
+
+	// only parents of the distro can be baked into its image, which matters
+	// when the pool mixes Windows and Linux parents
+	parents, err := host.FindAllRunningParentsByContainerPoolDistro(j.PoolID, d.Id)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding parents for container pool '%s'", j.PoolID))
+		return
+	}
+	if len(parents) == 0 {
+		return
+	}
+
+	mgr, err := cloud.GetManager(ctx, d.Provider, j.settings)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error getting cloud manager for distro '%s'", d.Id))
+		return
+	}
+	baker, ok := mgr.(cloud.ImageBaker)
+	if !ok {
+		j.AddError(errors.Errorf("provider '%s' of distro '%s' cannot bake images", d.Provider, d.Id))
+		return
+	}
+
+	// any parent identifies the region that the pool's images are in
+	images, err := baker.GetBakedImages(ctx, &parents[0], j.PoolID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error getting baked images for container pool '%s'", j.PoolID))
+		return
+	}
+
+	latest, lastBaked, pending := summarizeBakedImages(usableBakedImages(images, d.ParentImageRollout))
+	if latest != nil {
+		j.AddError(j.rollOutImage(&d, latest.ID))
+	}
+	j.AddError(j.deleteOldImages(ctx, baker, &parents[0], &d, latest, images))
+
+	interval := time.Duration(j.pool.ImageBakeIntervalHours) * time.Hour
+	if pending || time.Since(lastBaked) < interval {
+		return
+	}
+
+	// baking restarts the parent, so only a parent without containers is
+	// baked, and the bake waits until one is drained
+	parent, err := findDrainedParent(parents)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding a drained parent in container pool '%s'", j.PoolID))
+		return
+	}
+	if parent == nil {
+		grip.Info(message.Fields{
+			"message": "no drained parent to bake an image from",
+			"job":     j.ID(),
+			"pool":    j.PoolID,
+			"distro":  d.Id,
+		})
+		return
+	}
+
+	imageID, err := baker.BakeImage(ctx, parent, j.PoolID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error baking image from parent %s", parent.Id))
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message": "baking new parent image",
+		"job":     j.ID(),
+		"pool":    j.PoolID,
+		"distro":  d.Id,
+		"host":    parent.Id,
+		"image":   imageID,
+	})
+}
+
+// rollOutImage sets the image that new parents of the distro start from,
+// keeping the image they started from before so that the rollout can be
+// rolled back.
+func (j *parentImageBakeJob) rollOutImage(d *distro.Distro, imageID string) error {
+	if d.ProviderSettings == nil {
+		return errors.Errorf("distro '%s' has no provider settings", d.Id)
+	}
+
+	before := *d
+	settings := make(map[string]interface{}, len(*d.ProviderSettings))
+	for k, v := range *d.ProviderSettings {
+		settings[k] = v
+	}
+	before.ProviderSettings = &settings
+
+	rolledOut, err := d.RollOutParentImage(imageID)
+	if err != nil {
+		return errors.Wrapf(err, "error updating image of distro '%s'", d.Id)
+	}
+	if !rolledOut {
+		return nil
+	}
+	event.LogDistroModified(d.Id, evergreen.User, before, d)
+
+	grip.Info(message.Fields{
+		"message":        "rolled out baked parent image",
+		"job":            j.ID(),
+		"pool":           j.PoolID,
+		"distro":         d.Id,
+		"image":          imageID,
+		"previous_image": d.ParentImageRollout.PreviousImage,
+	})
+
+	return nil
+}
+
+// deleteOldImages deletes the pool's baked images other than the image that
+// the distro's parents start from, the image they started from before, which
+// the distro can be rolled back to, and the newest usable image. Images that
+// are still being baked are left alone.
+func (j *parentImageBakeJob) deleteOldImages(ctx context.Context, baker cloud.ImageBaker, h *host.Host, d *distro.Distro, latest *cloud.BakedImage, images []cloud.BakedImage) error {
+	keep := []string{d.ParentImage(), d.ParentImageRollout.PreviousImage}
+	if latest != nil {
+		keep = append(keep, latest.ID)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, image := range images {
+		if image.State == cloud.BakedImageStatePending || util.StringSliceContains(keep, image.ID) {
+			continue
+		}
+		catcher.Add(errors.Wrapf(baker.DeleteBakedImage(ctx, h, image), "error deleting image %s of container pool '%s'", image.ID, j.PoolID))
+	}
+
+	return catcher.Resolve()
+}
+
+// usableBakedImages returns the images that have not been rolled back.
+func usableBakedImages(images []cloud.BakedImage, rollout distro.ParentImageRollout) []cloud.BakedImage {
+	usable := []cloud.BakedImage{}
+	for _, image := range images {
+		if !rollout.IsRejected(image.ID) {
+			usable = append(usable, image)
+		}
+	}
+	return usable
+}
+
+// findDrainedParent returns the first of the parents that has no containers
+// that have not been terminated, if any.
+func findDrainedParent(parents []host.Host) (*host.Host, error) {
+	for i := range parents {
+		active, err := parents[i].HasActiveContainers()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error checking containers of parent %s", parents[i].Id)
+		}
+		if !active {
+			return &parents[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// summarizeBakedImages returns the newest available image, the time the most
+// recent image that has not failed was baked, and whether any image is still
+// being baked.
+func summarizeBakedImages(images []cloud.BakedImage) (*cloud.BakedImage, time.Time, bool) {
+	var latest *cloud.BakedImage
+	var lastBaked time.Time
+	pending := false
+
+	for i := range images {
+		switch images[i].State {
+		case cloud.BakedImageStatePending:
+			pending = true
+		case cloud.BakedImageStateAvailable:
+			if latest == nil || images[i].CreatedAt.After(latest.CreatedAt) {
+				latest = &images[i]
+			}
+		default:
+			continue
+		}
+
+		if images[i].CreatedAt.After(lastBaked) {
+			lastBaked = images[i].CreatedAt
+		}
+	}
+
+	return latest, lastBaked, pending
+}
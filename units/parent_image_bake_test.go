@@ -0,0 +1,56 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParentImageBakeJobFactory(t *testing.T) {
+	assert := assert.New(t)
+
+	j := NewParentImageBakeJob(evergreen.GetEnvironment(), &evergreen.ContainerPool{Id: "pool"}, "ts")
+	assert.Equal("parent-image-bake.pool.ts", j.ID())
+	assert.Equal(parentImageBakeJobName, j.Type().Name)
+}
+
+func TestSummarizeBakedImages(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	latest, lastBaked, pending := summarizeBakedImages(nil)
+	assert.Nil(latest)
+	assert.True(lastBaked.IsZero())
+	assert.False(pending)
+
+	images := []cloud.BakedImage{
+		{ID: "old", State: cloud.BakedImageStateAvailable, CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "new", State: cloud.BakedImageStateAvailable, CreatedAt: now.Add(-24 * time.Hour)},
+		{ID: "failed", State: "failed", CreatedAt: now},
+	}
+	latest, lastBaked, pending = summarizeBakedImages(images)
+	assert.Equal("new", latest.ID)
+	assert.Equal(now.Add(-24*time.Hour), lastBaked)
+	assert.False(pending)
+
+	images = append(images, cloud.BakedImage{ID: "baking", State: cloud.BakedImageStatePending, CreatedAt: now.Add(-time.Hour)})
+	latest, lastBaked, pending = summarizeBakedImages(images)
+	assert.Equal("new", latest.ID)
+	assert.Equal(now.Add(-time.Hour), lastBaked)
+	assert.True(pending)
+}
+
+func TestUsableBakedImages(t *testing.T) {
+	assert := assert.New(t)
+
+	images := []cloud.BakedImage{{ID: "ami-1"}, {ID: "ami-2"}}
+	assert.Len(usableBakedImages(images, distro.ParentImageRollout{}), 2)
+
+	usable := usableBakedImages(images, distro.ParentImageRollout{RejectedImages: []string{"ami-2"}})
+	assert.Len(usable, 1)
+	assert.Equal("ami-1", usable[0].ID)
+}
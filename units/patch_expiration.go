@@ -0,0 +1,96 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const patchExpirationJobName = "patch-expiration"
+
+func init() {
+	registry.AddJobType(patchExpirationJobName, func() amboy.Job {
+		return makePatchExpirationJob()
+	})
+}
+
+type patchExpirationJob struct {
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makePatchExpirationJob() *patchExpirationJob {
+	j := &patchExpirationJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    patchExpirationJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewPatchExpirationJob creates a job that removes the patches of a project
+// that have gone unfinalized for longer than the project's patch expiration,
+// along with their stored diffs. Pinned patches are kept.
+func NewPatchExpirationJob(projectID, id string) amboy.Job {
+	j := makePatchExpirationJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", patchExpirationJobName, projectID, id))
+	return j
+}
+
+func (j *patchExpirationJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	ref, err := model.FindOneProjectRef(j.ProjectID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding project '%s'", j.ProjectID))
+		return
+	}
+	if ref == nil {
+		j.AddError(errors.Errorf("project '%s' not found", j.ProjectID))
+		return
+	}
+
+	cutoff := time.Now().Add(-ref.GetPatchExpiration())
+	patches, err := patch.Find(patch.ExpiredUnfinalized(j.ProjectID, cutoff).WithFields(patch.IdKey, patch.PatchesKey))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding expired patches of project '%s'", j.ProjectID))
+		return
+	}
+
+	removed := 0
+	for _, p := range patches {
+		if ctx.Err() != nil {
+			j.AddError(ctx.Err())
+			break
+		}
+		if err = p.RemoveWithPatchFiles(); err != nil {
+			j.AddError(err)
+			continue
+		}
+		removed++
+	}
+
+	grip.InfoWhen(len(patches) > 0, message.Fields{
+		"message": "removed expired unfinalized patches",
+		"project": j.ProjectID,
+		"job":     j.ID(),
+		"cutoff":  cutoff,
+		"expired": len(patches),
+		"removed": removed,
+	})
+}
@@ -0,0 +1,46 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestPatchExpirationJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(model.ProjectRefCollection, patch.Collection))
+
+	require.NoError((&model.ProjectRef{Identifier: "mci", PatchExpirationDays: 2}).Insert())
+	stale := patch.Patch{Id: bson.NewObjectId(), Project: "mci", CreateTime: time.Now().Add(-72 * time.Hour)}
+	recent := patch.Patch{Id: bson.NewObjectId(), Project: "mci", CreateTime: time.Now().Add(-24 * time.Hour)}
+	pinned := patch.Patch{Id: bson.NewObjectId(), Project: "mci", CreateTime: time.Now().Add(-72 * time.Hour), Pinned: true}
+	for _, p := range []patch.Patch{stale, recent, pinned} {
+		require.NoError(p.Insert())
+	}
+
+	j := NewPatchExpirationJob("mci", "id")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	assert.True(j.Status().Completed)
+
+	remaining, err := patch.Find(patch.ByProject("mci"))
+	require.NoError(err)
+	require.Len(remaining, 2)
+	for _, p := range remaining {
+		assert.NotEqual(stale.Id, p.Id)
+	}
+
+	j = NewPatchExpirationJob("nonexistent", "id")
+	j.Run(context.Background())
+	assert.Error(j.Error())
+}
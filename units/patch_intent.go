@@ -156,8 +156,16 @@ func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.
 	default:
 		return errors.Errorf("Intent type '%s' is unknown", j.IntentType)
 	}
-	if len := len(patchDoc.Patches); len != 1 {
-		catcher.Add(errors.Errorf("patch document should have 1 patch, found %d", len))
+	// patches of modules may be copied from a previous patch, but there
+	// must be one patch of the project itself
+	projectPatches := 0
+	for _, modulePatch := range patchDoc.Patches {
+		if modulePatch.ModuleName == "" {
+			projectPatches++
+		}
+	}
+	if projectPatches != 1 {
+		catcher.Add(errors.Errorf("patch document should have 1 patch, found %d", projectPatches))
 	}
 
 	if err = catcher.Resolve(); err != nil {
@@ -225,6 +233,11 @@ func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.
 		}
 	}
 
+	patchDoc.Parameters, err = project.ResolveParameters(patchDoc.Parameters)
+	if err != nil {
+		return errors.Wrap(err, "invalid patch parameters")
+	}
+
 	// add the project config
 	projectYamlBytes, err := yaml.Marshal(project)
 	if err != nil {
@@ -268,6 +281,16 @@ func (j *patchIntentProcessor) finishPatch(ctx context.Context, patchDoc *patch.
 		if err = buildSub.Upsert(); err != nil {
 			catcher.Add(errors.Wrap(err, "failed to insert build subscription for Github PR"))
 		}
+		if pref.GithubChecksEnabled {
+			checkSub := event.NewBuildOutcomeSubscriptionByVersion(j.PatchID.Hex(), event.NewGithubCheckSubscriber(event.GithubCheckSubscriber{
+				Owner: patchDoc.GithubPatchData.BaseOwner,
+				Repo:  patchDoc.GithubPatchData.BaseRepo,
+				Ref:   patchDoc.GithubPatchData.HeadHash,
+			}))
+			if err = checkSub.Upsert(); err != nil {
+				catcher.Add(errors.Wrap(err, "failed to insert build subscription for Github checks"))
+			}
+		}
 	}
 	if catcher.HasErrors() {
 		grip.Error(message.WrapError(catcher.Resolve(), message.Fields{
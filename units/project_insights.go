@@ -0,0 +1,109 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/projectinsights"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	projectInsightsJobName = "project-insights"
+
+	// projectInsightsLookback is how long before the longest window the
+	// builds that insights are computed from start, so that breaks at the
+	// start of the window are found.
+	projectInsightsLookback = 7 * 24 * time.Hour
+)
+
+func init() {
+	registry.AddJobType(projectInsightsJobName, func() amboy.Job {
+		return makeProjectInsightsJob()
+	})
+}
+
+type projectInsightsJob struct {
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeProjectInsightsJob() *projectInsightsJob {
+	j := &projectInsightsJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    projectInsightsJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewProjectInsightsJob creates a job that recomputes the success rate,
+// break detection and repair times, and duration percentiles of a project's
+// mainline builds.
+func NewProjectInsightsJob(projectID, id string) amboy.Job {
+	j := makeProjectInsightsJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", projectInsightsJobName, projectID, id))
+	return j
+}
+
+func (j *projectInsightsJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	now := time.Now()
+	longest := 0
+	for _, days := range projectinsights.Windows {
+		if days > longest {
+			longest = days
+		}
+	}
+	since := now.Add(-time.Duration(longest)*24*time.Hour - projectInsightsLookback)
+
+	builds, err := build.Find(db.Query(bson.M{
+		build.ProjectKey:    j.ProjectID,
+		build.RequesterKey:  bson.M{"$in": evergreen.SystemVersionRequesterTypes},
+		build.StatusKey:     bson.M{"$in": []string{evergreen.BuildSucceeded, evergreen.BuildFailed}},
+		build.CreateTimeKey: bson.M{"$gte": since},
+	}).WithFields(build.BuildVariantKey, build.RevisionOrderNumberKey, build.StatusKey,
+		build.CreateTimeKey, build.StartTimeKey, build.FinishTimeKey))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding builds of project '%s'", j.ProjectID))
+		return
+	}
+	if ctx.Err() != nil {
+		j.AddError(ctx.Err())
+		return
+	}
+
+	runs := make([]projectinsights.BuildRun, 0, len(builds))
+	for _, b := range builds {
+		run := projectinsights.BuildRun{
+			Variant:    b.BuildVariant,
+			Order:      b.RevisionOrderNumber,
+			Succeeded:  b.Status == evergreen.BuildSucceeded,
+			CreateTime: b.CreateTime,
+			FinishTime: b.FinishTime,
+		}
+		if !b.StartTime.IsZero() && b.FinishTime.After(b.StartTime) {
+			run.Duration = b.FinishTime.Sub(b.StartTime)
+		}
+		runs = append(runs, run)
+	}
+
+	insights := projectinsights.Compute(j.ProjectID, runs, now)
+	j.AddError(errors.Wrapf(projectinsights.ReplaceProject(j.ProjectID, insights),
+		"problem storing insights of project '%s'", j.ProjectID))
+}
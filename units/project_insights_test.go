@@ -0,0 +1,70 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/projectinsights"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectInsightsJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(build.Collection, projectinsights.Collection))
+
+	statuses := []string{
+		evergreen.BuildSucceeded,
+		evergreen.BuildFailed,
+		evergreen.BuildSucceeded,
+		evergreen.BuildSucceeded,
+	}
+	start := time.Now().Add(-48 * time.Hour)
+	for i, status := range statuses {
+		created := start.Add(time.Duration(i) * time.Hour)
+		b := build.Build{
+			Id:                  fmt.Sprintf("b%d", i),
+			Project:             "mci",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			BuildVariant:        "bv",
+			RevisionOrderNumber: i,
+			Status:              status,
+			CreateTime:          created,
+			StartTime:           created,
+			FinishTime:          created.Add(30 * time.Minute),
+		}
+		require.NoError(b.Insert())
+	}
+	patchBuild := build.Build{
+		Id:         "patch",
+		Project:    "mci",
+		Requester:  evergreen.PatchVersionRequester,
+		Status:     evergreen.BuildFailed,
+		CreateTime: start,
+	}
+	require.NoError(patchBuild.Insert())
+
+	j := NewProjectInsightsJob("mci", "id")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	assert.True(j.Status().Completed)
+
+	insights, err := projectinsights.Find(projectinsights.ByProject("mci", 7))
+	require.NoError(err)
+	require.Len(insights, 1)
+	assert.Equal(4, insights[0].Builds)
+	assert.Equal(0.75, insights[0].SuccessRate)
+	assert.Equal(1, insights[0].Breaks)
+	assert.Zero(insights[0].OpenBreaks)
+	assert.Equal(30*time.Minute, insights[0].MeanTimeToDetect)
+	assert.Equal(time.Hour, insights[0].MeanTimeToRepair)
+	assert.Equal(30*time.Minute, insights[0].DurationP50)
+}
@@ -189,12 +189,18 @@ func (j *agentDeployJob) startAgentOnHost(ctx context.Context, settings *evergre
 	}
 	hostObj.Distro = d
 
+	agentUpdate := evergreen.AgentUpdateConfig{}
+	if err = agentUpdate.Get(); err != nil {
+		return errors.Wrap(err, "error getting agent update settings")
+	}
+	version := agentUpdate.DesiredVersion(hostObj.Distro.Id, hostObj.Id)
+
 	// prep the remote host
 	grip.Info(message.Fields{
 		"runner":  "taskrunner",
 		"message": "prepping host for agent",
 		"host":    hostObj.Id})
-	if err = j.prepRemoteHost(ctx, hostObj, sshOptions, settings); err != nil {
+	if err = j.prepRemoteHost(ctx, hostObj, version, sshOptions, settings); err != nil {
 		event.LogHostAgentDeployFailed(hostObj.Id, err)
 		grip.Info(message.Fields{
 			"message": "error prepping remote host",
@@ -228,7 +234,7 @@ func (j *agentDeployJob) startAgentOnHost(ctx context.Context, settings *evergre
 	}
 	grip.Info(message.Fields{"runner": "taskrunner", "message": "agent successfully started for host", "host": hostObj.Id})
 
-	if err = hostObj.SetAgentRevision(evergreen.BuildRevision); err != nil {
+	if err = hostObj.SetAgentRevision(version); err != nil {
 		return errors.Wrapf(err, "error setting agent revision on host %s", hostObj.Id)
 	}
 	if err = hostObj.UpdateLastCommunicated(); err != nil {
@@ -240,10 +246,11 @@ func (j *agentDeployJob) startAgentOnHost(ctx context.Context, settings *evergre
 	return nil
 }
 
-// Prepare the remote machine to run a task.
-func (j *agentDeployJob) prepRemoteHost(ctx context.Context, hostObj host.Host, sshOptions []string, settings *evergreen.Settings) error {
+// Prepare the remote machine to run a task with the given version of the
+// agent.
+func (j *agentDeployJob) prepRemoteHost(ctx context.Context, hostObj host.Host, version string, sshOptions []string, settings *evergreen.Settings) error {
 	// copy over the correct agent binary to the remote host
-	if logs, err := hostObj.RunSSHCommand(ctx, hostObj.CurlCommand(settings.Ui.Url), sshOptions); err != nil {
+	if logs, err := hostObj.RunSSHCommand(ctx, hostObj.CurlCommandForVersion(settings.Ui.Url, version), sshOptions); err != nil {
 		return errors.Wrapf(err, "error downloading agent binary on remote host: %s", logs)
 	}
 
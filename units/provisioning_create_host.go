@@ -198,7 +198,7 @@ func (j *createHostJob) createHost(ctx context.Context) error {
 		}
 	}
 
-	if _, err = cloudManager.SpawnHost(ctx, j.host); err != nil {
+	if _, err = cloud.SpawnHostInRegions(ctx, cloudManager, j.host); err != nil {
 		return errors.Wrapf(err, "error spawning host %s", j.host.Id)
 	}
 
@@ -208,16 +208,21 @@ func (j *createHostJob) createHost(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrapf(err, "problem retrieving intent host '%s'", j.HostID)
 		}
-		if intentHost == nil {
+		// A failed request for interruptible capacity, or for capacity in
+		// another region, may have removed the intent host before the host
+		// was spawned.
+		if intentHost == nil && !j.host.CapacityFallback && !j.host.RegionFailover {
 			return errors.Wrapf(err, "no intent host '%s' found", j.HostID)
 		}
-		if err := intentHost.Remove(); err != nil {
-			grip.Notice(message.WrapError(err, message.Fields{
-				"message": "problem removing intent host",
-				"job":     j.ID(),
-				"host":    j.HostID,
-			}))
-			return errors.Wrapf(errIgnorableCreateHost, "problem removing intent host '%s' [%s]", j.HostID, err.Error())
+		if intentHost != nil {
+			if err := intentHost.Remove(); err != nil {
+				grip.Notice(message.WrapError(err, message.Fields{
+					"message": "problem removing intent host",
+					"job":     j.ID(),
+					"host":    j.HostID,
+				}))
+				return errors.Wrapf(errIgnorableCreateHost, "problem removing intent host '%s' [%s]", j.HostID, err.Error())
+			}
 		}
 	}
 
@@ -276,12 +281,18 @@ func (j *createHostJob) shouldRetryCreateHost(ctx context.Context) bool {
 }
 
 func (j *createHostJob) isImageBuilt(ctx context.Context) (bool, error) {
-	imageURL := (*j.host.Distro.ProviderSettings)["image_url"].(string)
-
 	parent, err := host.FindOneId(j.host.ParentID)
 	if err != nil {
 		return false, errors.Wrapf(err, "problem getting parent for '%s'", j.host.Id)
 	}
+	if parent == nil {
+		return false, errors.Errorf("parent for '%s' not found", j.host.Id)
+	}
+
+	imageURL, err := cloud.ContainerImageURL(&j.host.Distro, parent)
+	if err != nil {
+		return false, errors.Wrapf(err, "problem getting image for '%s'", j.host.Id)
+	}
 	if ok := parent.ContainerImages[imageURL]; ok {
 		return true, nil
 	}
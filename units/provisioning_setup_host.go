@@ -499,6 +499,32 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 		}
 	}
 
+	if err = j.runPreProvisionHook(ctx, h, settings); err != nil {
+		if shouldRetryProvisioning(h) {
+			grip.Debug(message.Fields{
+				"host":     h.Id,
+				"attempts": h.ProvisionAttempts,
+				"distro":   h.Distro.Id,
+				"job":      j.ID(),
+				"error":    err.Error(),
+				"message":  "pre-provision hook failed, but will retry",
+			})
+			return nil
+		}
+
+		event.LogProvisionFailed(h.Id, "")
+
+		grip.Error(message.WrapError(h.SetUnprovisioned(), message.Fields{
+			"operation": "setting host unprovisioned",
+			"attempts":  h.ProvisionAttempts,
+			"distro":    h.Distro.Id,
+			"job":       j.ID(),
+			"host":      h.Id,
+		}))
+
+		return errors.Wrapf(err, "error running pre-provision hook on host %s", h.Id)
+	}
+
 	grip.Info(message.Fields{
 		"message": "setup complete for host",
 		"host":    h.Id,
@@ -524,6 +550,31 @@ func (j *setupHostJob) provisionHost(ctx context.Context, h *host.Host, settings
 	return nil
 }
 
+// runPreProvisionHook runs the distro's pre-provision hook on the host, if
+// it has one. A failure is ignored if the hook allows it.
+func (j *setupHostJob) runPreProvisionHook(ctx context.Context, h *host.Host, settings *evergreen.Settings) error {
+	hook := h.Distro.Hooks.Get(distro.HookPreProvision)
+	if hook == nil {
+		return nil
+	}
+
+	cloudHost, err := cloud.GetCloudHost(ctx, h, settings)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get cloud host for %s", h.Id)
+	}
+	err = cloudHost.RunHook(ctx, distro.HookPreProvision, settings)
+	if err != nil && hook.AllowFailure {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"message": "pre-provision hook failed, but the distro allows it",
+			"host":    h.Id,
+			"distro":  h.Distro.Id,
+			"job":     j.ID(),
+		}))
+		return nil
+	}
+	return err
+}
+
 // loadClientResult indicates the locations on a target host where the CLI binary and it's config
 // file have been written to.
 type loadClientResult struct {
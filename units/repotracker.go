@@ -115,5 +115,12 @@ func (j *repotrackerJob) Run(ctx context.Context) {
 			"project": j.ProjectID,
 		}))
 		j.AddError(err)
+		return
+	}
+
+	// the branch may have moved, so the commit queue may need to test its
+	// next item against the new head
+	if ref.CommitQueue.Enabled {
+		j.AddError(j.env.RemoteQueue().Put(NewCommitQueueJob(j.env, j.ProjectID, fmt.Sprintf("repotracker-%s", j.ID()))))
 	}
 }
@@ -0,0 +1,143 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	repotrackerTagJobName = "repotracker-tag"
+
+	// repotrackerTagMaxAttempts is the number of times adding a tag to
+	// a revision that has no version yet is attempted.
+	repotrackerTagMaxAttempts = 6
+	// repotrackerTagRetryDelay is the delay before the first retry,
+	// which doubles with each attempt after it.
+	repotrackerTagRetryDelay = 5 * time.Minute
+)
+
+func init() {
+	registry.AddJobType(repotrackerTagJobName, func() amboy.Job { return makeRepotrackerTagJob() })
+}
+
+type repotrackerTagJob struct {
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	TagName   string `bson:"tag_name" json:"tag_name" yaml:"tag_name"`
+	TagSHA    string `bson:"tag_sha" json:"tag_sha" yaml:"tag_sha"`
+	Revision  string `bson:"revision" json:"revision" yaml:"revision"`
+	Attempt   int    `bson:"attempt" json:"attempt" yaml:"attempt"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env       evergreen.Environment
+}
+
+func makeRepotrackerTagJob() *repotrackerTagJob {
+	j := &repotrackerTagJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    repotrackerTagJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewRepotrackerTagJob creates a job to record the metadata of a pushed tag
+// on the version built from the tagged revision.
+func NewRepotrackerTagJob(msgID, projectID, tagName, tagSHA, revision string) amboy.Job {
+	job := makeRepotrackerTagJob()
+	job.ProjectID = projectID
+	job.TagName = tagName
+	job.TagSHA = tagSHA
+	job.Revision = revision
+	job.SetID(fmt.Sprintf("%s:%s:%s", repotrackerTagJobName, msgID, projectID))
+	return job
+}
+
+// newRepotrackerTagRetryJob makes a job to add the tag again after a delay
+// that grows with the number of attempts.
+func newRepotrackerTagRetryJob(j *repotrackerTagJob) *repotrackerTagJob {
+	retry := makeRepotrackerTagJob()
+	retry.ProjectID = j.ProjectID
+	retry.TagName = j.TagName
+	retry.TagSHA = j.TagSHA
+	retry.Revision = j.Revision
+	retry.Attempt = j.Attempt + 1
+	retry.env = j.env
+
+	retry.SetID(fmt.Sprintf("%s:%s:%s:%s:%d", repotrackerTagJobName, j.ProjectID, j.TagName, j.Revision, retry.Attempt))
+	retry.UpdateTimeInfo(amboy.JobTimeInfo{
+		WaitUntil: time.Now().Add(repotrackerTagRetryDelay << uint(retry.Attempt-1)),
+	})
+	return retry
+}
+
+func (j *repotrackerTagJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+
+	settings := j.env.Settings()
+	if settings == nil {
+		j.AddError(errors.New("settings is empty"))
+		return
+	}
+
+	ref, err := model.FindOneProjectRef(j.ProjectID)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if ref == nil {
+		j.AddError(errors.New("can't find project ref for project"))
+		return
+	}
+
+	_, err = repotracker.AddTagToVersion(ctx, settings, *ref, j.TagName, j.TagSHA, j.Revision)
+	if errors.Cause(err) == repotracker.ErrNoVersionForRevision {
+		j.AddError(j.retry(err))
+		return
+	}
+	j.AddError(err)
+}
+
+// retry schedules adding the tag again, since the repotracker may not have
+// created the version of the tagged revision yet.
+func (j *repotrackerTagJob) retry(tagErr error) error {
+	if j.Attempt+1 >= repotrackerTagMaxAttempts {
+		return errors.Wrapf(tagErr, "giving up after %d attempts", j.Attempt+1)
+	}
+
+	queue := j.env.RemoteQueue()
+	if queue == nil {
+		return errors.New("no queue to retry adding the tag on")
+	}
+	if err := queue.Put(newRepotrackerTagRetryJob(j)); err != nil {
+		return errors.Wrap(err, "failed to schedule retry of adding the tag")
+	}
+
+	grip.Info(message.Fields{
+		"job_id":   j.ID(),
+		"project":  j.ProjectID,
+		"tag":      j.TagName,
+		"revision": j.Revision,
+		"attempt":  j.Attempt + 1,
+		"message":  "revision has no version yet, retrying adding the tag",
+	})
+	return nil
+}
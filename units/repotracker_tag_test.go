@@ -0,0 +1,41 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/mock"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepotrackerTagJobRetry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := &mock.Environment{}
+	require.NoError(env.Configure(ctx, "", nil))
+	require.NoError(env.Remote.Start(ctx))
+	j := NewRepotrackerTagJob("msg", "proj", "r1", "tag-sha", "abcdef").(*repotrackerTagJob)
+	j.env = env
+
+	assert.NoError(j.retry(repotracker.ErrNoVersionForRevision))
+	require.Equal(1, env.Remote.Stats().Total)
+	queued, ok := env.Remote.Get(newRepotrackerTagRetryJob(j).ID())
+	require.True(ok)
+	retry, ok := queued.(*repotrackerTagJob)
+	require.True(ok)
+	assert.Equal(1, retry.Attempt)
+	assert.Equal("r1", retry.TagName)
+	assert.Equal("abcdef", retry.Revision)
+	assert.WithinDuration(time.Now().Add(repotrackerTagRetryDelay), retry.TimeInfo().WaitUntil, time.Minute)
+
+	j.Attempt = repotrackerTagMaxAttempts - 1
+	assert.Error(j.retry(repotracker.ErrNoVersionForRevision))
+	assert.Equal(1, env.Remote.Stats().Total)
+}
@@ -132,7 +132,7 @@ func (j *collectHostIdleDataJob) Run(ctx context.Context) {
 		if err != nil {
 			j.AddError(err)
 		}
-		if err = j.host.IncCost(cost); err != nil {
+		if err = j.host.IncCost(cost, j.FinishTime.Sub(j.StartTime)); err != nil {
 			j.AddError(err)
 		}
 	}
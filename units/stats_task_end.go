@@ -110,7 +110,7 @@ func (j *collectTaskEndDataJob) Run(ctx context.Context) {
 				if err = j.task.SetCost(cost); err != nil {
 					j.AddError(err)
 				}
-				if err = j.host.IncCost(cost); err != nil {
+				if err = j.host.IncCost(cost, j.task.FinishTime.Sub(j.task.StartTime)); err != nil {
 					j.AddError(err)
 				}
 			}
@@ -0,0 +1,126 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	systemPruneJobName = "system-prune"
+)
+
+func init() {
+	registry.AddJobType(systemPruneJobName, func() amboy.Job {
+		return makeSystemPruneJob()
+	})
+}
+
+type systemPruneJob struct {
+	HostID   string `bson:"host_id" json:"host_id" yaml:"host_id"`
+	job.Base `bson:"base" json:"base" yaml:"base"`
+	Provider string `bson:"provider" json:"provider" yaml:"provider"`
+
+	// cache
+	host     *host.Host
+	env      evergreen.Environment
+	settings *evergreen.Settings
+}
+
+func makeSystemPruneJob() *systemPruneJob {
+	j := &systemPruneJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    systemPruneJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// NewSystemPruneJob creates a job that reclaims disk space on a parent host
+// by pruning unused Docker resources when disk usage exceeds the threshold
+// in the Docker settings.
+func NewSystemPruneJob(h *host.Host, providerName, id string) amboy.Job {
+	j := makeSystemPruneJob()
+
+	j.host = h
+	j.Provider = providerName
+	j.HostID = h.Id
+
+	j.SetID(fmt.Sprintf("%s.%s.%s", systemPruneJobName, j.HostID, id))
+	return j
+}
+
+func (j *systemPruneJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	var err error
+	if j.host == nil {
+		j.host, err = host.FindOneId(j.HostID)
+		j.AddError(err)
+	}
+	if j.env == nil {
+		j.env = evergreen.GetEnvironment()
+	}
+	if j.settings == nil {
+		j.settings = j.env.Settings()
+	}
+
+	if j.HasErrors() {
+		return
+	}
+	if j.host == nil {
+		j.AddError(errors.Errorf("unable to find host %s", j.HostID))
+		return
+	}
+
+	mgr, err := cloud.GetManager(ctx, j.Provider, j.settings)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error getting Docker manager"))
+		return
+	}
+	containerMgr, err := cloud.ConvertContainerManager(mgr)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error getting Docker manager"))
+		return
+	}
+
+	diskUsage, err := containerMgr.GetDiskUsage(ctx, j.host)
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error getting Docker disk usage"))
+		return
+	}
+
+	dockerConf := j.settings.Providers.Docker
+	if diskUsage < dockerConf.PruneThreshold() {
+		return
+	}
+
+	reclaimed, err := containerMgr.PruneSystem(ctx, j.host, dockerConf.PruneUnusedFor())
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error pruning Docker system on parent %s", j.HostID))
+		return
+	}
+
+	grip.Info(message.Fields{
+		"message":         "pruned Docker system on parent",
+		"host":            j.HostID,
+		"job":             j.ID(),
+		"disk_usage":      diskUsage,
+		"space_reclaimed": reclaimed,
+	})
+}
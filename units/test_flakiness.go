@@ -0,0 +1,124 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const (
+	testFlakinessJobName = "test-flakiness"
+
+	// testFlakinessVersions is the number of recent mainline versions whose
+	// test results the flake rates are computed from.
+	testFlakinessVersions = 50
+)
+
+func init() {
+	registry.AddJobType(testFlakinessJobName, func() amboy.Job {
+		return makeTestFlakinessJob()
+	})
+}
+
+type testFlakinessJob struct {
+	ProjectID string `bson:"project_id" json:"project_id" yaml:"project_id"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeTestFlakinessJob() *testFlakinessJob {
+	j := &testFlakinessJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    testFlakinessJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewTestFlakinessJob creates a job that recomputes the flake rates of a
+// project's tests from the test results of its recent mainline versions.
+func NewTestFlakinessJob(projectID, id string) amboy.Job {
+	j := makeTestFlakinessJob()
+	j.ProjectID = projectID
+	j.SetID(fmt.Sprintf("%s.%s.%s", testFlakinessJobName, projectID, id))
+	return j
+}
+
+func (j *testFlakinessJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	versions, err := version.Find(version.ByMostRecentSystemRequester(j.ProjectID).
+		WithFields(version.IdKey, version.RevisionOrderNumberKey, version.CreateTimeKey).
+		Limit(testFlakinessVersions))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding versions of project '%s'", j.ProjectID))
+		return
+	}
+	if len(versions) == 0 {
+		return
+	}
+	versionIDs := make([]string, 0, len(versions))
+	createTimes := make(map[string]time.Time, len(versions))
+	for _, v := range versions {
+		versionIDs = append(versionIDs, v.Id)
+		createTimes[v.Id] = v.CreateTime
+	}
+
+	tasks, err := task.Find(task.ByVersions(versionIDs).WithFields(task.IdKey, task.VersionKey,
+		task.BuildVariantKey, task.DisplayNameKey, task.RevisionOrderNumberKey))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding tasks of project '%s'", j.ProjectID))
+		return
+	}
+	if ctx.Err() != nil {
+		j.AddError(ctx.Err())
+		return
+	}
+	tasksByID := make(map[string]task.Task, len(tasks))
+	taskIDs := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		tasksByID[t.Id] = t
+		taskIDs = append(taskIDs, t.Id)
+	}
+
+	results, err := testresult.Find(testresult.ByTaskIDs(taskIDs).WithFields(testresult.TaskIDKey,
+		testresult.ExecutionKey, testresult.TestFileKey, testresult.StatusKey))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding test results of project '%s'", j.ProjectID))
+		return
+	}
+
+	runs := make([]testflakiness.TestRun, 0, len(results))
+	for _, result := range results {
+		t, ok := tasksByID[result.TaskID]
+		if !ok {
+			continue
+		}
+		runs = append(runs, testflakiness.TestRun{
+			Variant:    t.BuildVariant,
+			Task:       t.DisplayName,
+			Test:       result.TestFile,
+			Order:      t.RevisionOrderNumber,
+			CreateTime: createTimes[t.Version],
+			Execution:  result.Execution,
+			Status:     result.Status,
+		})
+	}
+
+	stats := testflakiness.Compute(j.ProjectID, runs, time.Now())
+	j.AddError(errors.Wrapf(testflakiness.ReplaceProject(j.ProjectID, stats),
+		"problem storing test flakiness of project '%s'", j.ProjectID))
+}
@@ -0,0 +1,69 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testflakiness"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestFlakinessJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(version.Collection, task.Collection, testresult.Collection, testflakiness.Collection))
+
+	statuses := []string{
+		evergreen.TestSucceededStatus,
+		evergreen.TestFailedStatus,
+		evergreen.TestSucceededStatus,
+		evergreen.TestSucceededStatus,
+	}
+	for i, status := range statuses {
+		v := version.Version{
+			Id:                  fmt.Sprintf("v%d", i),
+			Identifier:          "mci",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i,
+			CreateTime:          time.Now().Add(time.Duration(i) * time.Hour),
+		}
+		require.NoError(v.Insert())
+		tsk := task.Task{
+			Id:                  fmt.Sprintf("t%d", i),
+			Version:             v.Id,
+			Project:             "mci",
+			BuildVariant:        "bv",
+			DisplayName:         "test",
+			RevisionOrderNumber: i,
+		}
+		require.NoError(tsk.Insert())
+		require.NoError((&testresult.TestResult{TaskID: tsk.Id, TestFile: "flaky", Status: status}).Insert())
+		require.NoError((&testresult.TestResult{TaskID: tsk.Id, TestFile: "stable", Status: evergreen.TestSucceededStatus}).Insert())
+	}
+	require.NoError(testflakiness.ReplaceProject("mci", []testflakiness.TestFlakiness{
+		{Id: testflakiness.TestFlakinessKey{Project: "mci", Variant: "bv", Task: "test", Test: "old"}},
+	}))
+
+	j := NewTestFlakinessJob("mci", "id")
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	assert.True(j.Status().Completed)
+
+	stats, err := testflakiness.Find(testflakiness.ByProject("mci", "", ""))
+	require.NoError(err)
+	require.Len(stats, 1)
+	assert.Equal(testflakiness.TestFlakinessKey{Project: "mci", Variant: "bv", Task: "test", Test: "flaky"}, stats[0].Id)
+	assert.Equal(4, stats[0].Runs)
+	assert.Equal(1, stats[0].Flakes)
+	assert.Equal(0.25, stats[0].FlakeRate)
+}
@@ -0,0 +1,88 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/model/testruntime"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/pkg/errors"
+)
+
+const testRuntimeUpdateJobName = "test-runtime-update"
+
+func init() {
+	registry.AddJobType(testRuntimeUpdateJobName, func() amboy.Job {
+		return makeTestRuntimeUpdateJob()
+	})
+}
+
+type testRuntimeUpdateJob struct {
+	TaskID    string `bson:"task_id" json:"task_id" yaml:"task_id"`
+	Execution int    `bson:"execution" json:"execution" yaml:"execution"`
+	job.Base  `bson:"job_base" json:"job_base" yaml:"job_base"`
+}
+
+func makeTestRuntimeUpdateJob() *testRuntimeUpdateJob {
+	j := &testRuntimeUpdateJob{
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    testRuntimeUpdateJobName,
+				Version: 0,
+			},
+		},
+	}
+	j.SetDependency(dependency.NewAlways())
+	return j
+}
+
+// NewTestRuntimeUpdateJob creates a job that records the runtimes of the
+// tests run by one execution of a shard of a sharded task, so that later
+// versions can rebalance the task's tests across its shards.
+func NewTestRuntimeUpdateJob(taskID string, execution int) amboy.Job {
+	j := makeTestRuntimeUpdateJob()
+	j.TaskID = taskID
+	j.Execution = execution
+	j.SetID(fmt.Sprintf("%s.%s.%d", testRuntimeUpdateJobName, taskID, execution))
+	return j
+}
+
+func (j *testRuntimeUpdateJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	t, err := task.FindOneId(j.TaskID)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding task '%s'", j.TaskID))
+		return
+	}
+	if t == nil {
+		j.AddError(errors.Errorf("task '%s' does not exist", j.TaskID))
+		return
+	}
+	if t.ShardOf == "" {
+		return
+	}
+
+	results, err := testresult.FindByTaskIDAndExecution(t.Id, j.Execution)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding test results for task '%s'", t.Id))
+		return
+	}
+
+	durations := map[string]time.Duration{}
+	for _, result := range results {
+		if result.EndTime <= result.StartTime {
+			continue
+		}
+		durations[result.TestFile] = time.Duration((result.EndTime - result.StartTime) * float64(time.Second))
+	}
+
+	j.AddError(errors.Wrapf(testruntime.Update(t.Project, t.BuildVariant, t.ShardOf, durations),
+		"problem updating test runtimes for task '%s'", t.Id))
+}
@@ -0,0 +1,51 @@
+package units
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/model/testruntime"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestRuntimeUpdateJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	require.NoError(db.ClearCollections(task.Collection, testresult.Collection, testruntime.Collection))
+
+	shard := task.Task{Id: "shard", Project: "mci", BuildVariant: "bv", ShardOf: "unit", Execution: 1}
+	require.NoError(shard.Insert())
+	unsharded := task.Task{Id: "unsharded", Project: "mci", BuildVariant: "bv", DisplayName: "lint"}
+	require.NoError(unsharded.Insert())
+	require.NoError(testresult.InsertMany([]testresult.TestResult{
+		{TaskID: "shard", Execution: 1, TestFile: "a", StartTime: 10, EndTime: 40},
+		{TaskID: "shard", Execution: 1, TestFile: "b", StartTime: 10, EndTime: 10},
+		{TaskID: "shard", Execution: 0, TestFile: "c", StartTime: 10, EndTime: 20},
+		{TaskID: "unsharded", TestFile: "d", StartTime: 10, EndTime: 20},
+	}))
+
+	j := NewTestRuntimeUpdateJob("shard", 1)
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	runtimes, err := testruntime.FindByTask("mci", "bv", "unit")
+	require.NoError(err)
+	assert.Equal(map[string]time.Duration{"a": 30 * time.Second}, runtimes)
+
+	j = NewTestRuntimeUpdateJob("unsharded", 0)
+	j.Run(context.Background())
+	assert.NoError(j.Error())
+	runtimes, err = testruntime.FindByTask("mci", "bv", "lint")
+	require.NoError(err)
+	assert.Empty(runtimes)
+
+	j = NewTestRuntimeUpdateJob("nonexistent", 0)
+	j.Run(context.Background())
+	assert.Error(j.Error())
+}
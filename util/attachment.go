@@ -0,0 +1,15 @@
+package util
+
+// AttachmentMaxSize is the maximum total size of the files attached to a
+// notification.
+const AttachmentMaxSize = 10 * 1024 * 1024
+
+// Attachment is a file attached to a notification, such as an email or a
+// JIRA issue. The contents are either given as Data, or fetched from S3URL
+// (an s3://bucket/key URL) before the attachment is sent.
+type Attachment struct {
+	Filename    string `bson:"filename" json:"filename"`
+	ContentType string `bson:"content_type" json:"content_type"`
+	Data        []byte `bson:"data,omitempty" json:"data,omitempty"`
+	S3URL       string `bson:"s3_url,omitempty" json:"s3_url,omitempty"`
+}
@@ -0,0 +1,499 @@
+package util
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	evergreenEmailTimeout = 30 * time.Second
+	mimeLineLength        = 76
+)
+
+// EvergreenEmail is an email that may have an HTML body and attachments, in
+// addition to the fields of a grip email. If HTMLBody is set, the email is
+// sent with both the HTML body and a plain-text alternative, which is the
+// Body if set and is otherwise generated from the HTML.
+type EvergreenEmail struct {
+	message.Email `bson:",inline" json:",inline" yaml:",inline"`
+
+	HTMLBody    string       `bson:"html_body,omitempty" json:"html_body,omitempty" yaml:"html_body,omitempty"`
+	Attachments []Attachment `bson:"attachments,omitempty" json:"attachments,omitempty" yaml:"attachments,omitempty"`
+}
+
+// IsMultipart returns true if the email can only be sent as a multipart MIME
+// message.
+func (e *EvergreenEmail) IsMultipart() bool {
+	return e.HTMLBody != "" || len(e.Attachments) > 0
+}
+
+type evergreenEmailMessage struct {
+	raw EvergreenEmail
+
+	message.Base
+}
+
+// NewEmailMessage returns a composer for the email.
+func NewEmailMessage(l level.Priority, e EvergreenEmail) message.Composer {
+	m := &evergreenEmailMessage{
+		raw: e,
+	}
+	_ = m.SetPriority(l)
+
+	return m
+}
+
+func (m *evergreenEmailMessage) Loggable() bool {
+	if m.raw.From != "" {
+		if _, err := mail.ParseAddress(m.raw.From); err != nil {
+			return false
+		}
+	}
+	if len(m.raw.Recipients) == 0 {
+		return false
+	}
+	for _, r := range m.raw.Recipients {
+		if _, err := mail.ParseAddress(r); err != nil {
+			return false
+		}
+	}
+	for _, v := range m.raw.Headers {
+		if len(v) == 0 {
+			return false
+		}
+	}
+	if m.raw.Subject == "" {
+		return false
+	}
+	if m.raw.Body == "" && m.raw.HTMLBody == "" {
+		return false
+	}
+	for _, a := range m.raw.Attachments {
+		if a.Filename == "" || (len(a.Data) == 0 && a.S3URL == "") {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m *evergreenEmailMessage) Raw() interface{} {
+	return &m.raw
+}
+
+func (m *evergreenEmailMessage) String() string {
+	if m.raw.Body != "" {
+		return fmt.Sprintf("%s\n\n%s", m.raw.Subject, m.raw.Body)
+	}
+	return fmt.Sprintf("%s\n\n%s", m.raw.Subject, PlainTextFromHTML(m.raw.HTMLBody))
+}
+
+// PlainTextFromHTML returns the text of the HTML document, with block
+// elements on separate lines and links followed by their URLs.
+func PlainTextFromHTML(doc string) string {
+	out := &bytes.Buffer{}
+	newline := func() {
+		if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n")) {
+			out.WriteString("\n")
+		}
+	}
+
+	skip := 0
+	var href string
+	tokenizer := html.NewTokenizer(strings.NewReader(doc))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(collapseBlankLines(out.String()))
+
+		case html.TextToken:
+			if skip > 0 {
+				continue
+			}
+			text := strings.Join(strings.Fields(string(tokenizer.Text())), " ")
+			if text == "" {
+				continue
+			}
+			if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n")) && !bytes.HasSuffix(out.Bytes(), []byte(" ")) {
+				out.WriteString(" ")
+			}
+			out.WriteString(text)
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Script, atom.Style, atom.Head:
+				skip++
+			case atom.Br:
+				out.WriteString("\n")
+			case atom.Li:
+				newline()
+				out.WriteString("- ")
+			case atom.A:
+				href = ""
+				for hasAttr {
+					var key, val []byte
+					key, val, hasAttr = tokenizer.TagAttr()
+					if string(key) == "href" {
+						href = string(val)
+					}
+				}
+			case atom.P, atom.Div, atom.Tr, atom.Table, atom.Pre, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Ul, atom.Ol:
+				newline()
+			}
+
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Script, atom.Style, atom.Head:
+				if skip > 0 {
+					skip--
+				}
+			case atom.A:
+				if href != "" && !strings.HasPrefix(href, "#") {
+					out.WriteString(" (" + href + ")")
+				}
+				href = ""
+			case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Table, atom.Pre:
+				newline()
+				out.WriteString("\n")
+			case atom.Div, atom.Tr, atom.Li, atom.Ul, atom.Ol:
+				newline()
+			}
+		}
+	}
+}
+
+func collapseBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " ")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// MIME returns the email as a MIME message from the given address. The
+// attachments must have been fetched.
+func (e *EvergreenEmail) MIME(from *mail.Address) ([]byte, error) {
+	recipients := make([]string, 0, len(e.Recipients))
+	for _, r := range e.Recipients {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid recipient '%s'", r)
+		}
+		recipients = append(recipients, addr.String())
+	}
+
+	out := &bytes.Buffer{}
+	headers := []string{
+		fmt.Sprintf("From: %s", from.String()),
+		fmt.Sprintf("To: %s", strings.Join(recipients, ", ")),
+		fmt.Sprintf("Subject: %s", mime.QEncoding.Encode("utf-8", e.Subject)),
+		fmt.Sprintf("Date: %s", time.Now().Format(time.RFC1123Z)),
+		"MIME-Version: 1.0",
+	}
+	for k, values := range e.Headers {
+		switch textproto.CanonicalMIMEHeaderKey(k) {
+		case "To", "From", "Subject", "Date", "Mime-Version", "Content-Type", "Content-Transfer-Encoding":
+			continue
+		}
+		for _, v := range values {
+			headers = append(headers, fmt.Sprintf("%s: %s", k, v))
+		}
+	}
+	for _, h := range headers {
+		out.WriteString(h + "\r\n")
+	}
+
+	if len(e.Attachments) == 0 {
+		if err := e.writeBody(out); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return out.Bytes(), nil
+	}
+
+	mixed := multipart.NewWriter(out)
+	out.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mixed.Boundary()))
+
+	body := &bytes.Buffer{}
+	if err := e.writeBody(body); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	bodyHeader, bodyContents, err := splitMIMEPart(body.Bytes())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	part, err := mixed.CreatePart(bodyHeader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to write email body")
+	}
+	if _, err = part.Write(bodyContents); err != nil {
+		return nil, errors.Wrap(err, "failed to write email body")
+	}
+
+	for _, a := range e.Attachments {
+		if len(a.Data) == 0 && a.S3URL != "" {
+			return nil, errors.Errorf("attachment '%s' has not been fetched from '%s'", a.Filename, a.S3URL)
+		}
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err = mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": a.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to write attachment '%s'", a.Filename)
+		}
+		if err = writeBase64(part, a.Data); err != nil {
+			return nil, errors.Wrapf(err, "failed to write attachment '%s'", a.Filename)
+		}
+	}
+	if err = mixed.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to write email")
+	}
+
+	return out.Bytes(), nil
+}
+
+// writeBody writes the headers and contents of the body of the email, which
+// is a multipart/alternative part if the email has an HTML body.
+func (e *EvergreenEmail) writeBody(w *bytes.Buffer) error {
+	if e.HTMLBody == "" {
+		contentType := "text/html"
+		if e.PlainTextContents {
+			contentType = "text/plain"
+		}
+		return writeTextPart(w, contentType, e.Body)
+	}
+
+	text := e.Body
+	if text == "" {
+		text = PlainTextFromHTML(e.HTMLBody)
+	}
+
+	alternative := multipart.NewWriter(w)
+	w.WriteString(fmt.Sprintf("Content-Type: multipart/alternative; boundary=%s\r\n\r\n", alternative.Boundary()))
+	for _, p := range []struct {
+		contentType string
+		body        string
+	}{
+		{contentType: "text/plain", body: text},
+		{contentType: "text/html", body: e.HTMLBody},
+	} {
+		part, err := alternative.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType + `; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "failed to write %s body", p.contentType)
+		}
+		if err = writeBase64(part, []byte(p.body)); err != nil {
+			return errors.Wrapf(err, "failed to write %s body", p.contentType)
+		}
+	}
+
+	return errors.Wrap(alternative.Close(), "failed to write email body")
+}
+
+func writeTextPart(w *bytes.Buffer, contentType, body string) error {
+	w.WriteString(fmt.Sprintf("Content-Type: %s; charset=\"utf-8\"\r\n", contentType))
+	w.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	return errors.Wrap(writeBase64(w, []byte(body)), "failed to write email body")
+}
+
+// splitMIMEPart splits a part written by writeBody into its headers and
+// contents.
+func splitMIMEPart(part []byte) (textproto.MIMEHeader, []byte, error) {
+	idx := bytes.Index(part, []byte("\r\n\r\n"))
+	if idx < 0 {
+		return nil, nil, errors.New("email body has no headers")
+	}
+
+	header := textproto.MIMEHeader{}
+	for _, line := range strings.Split(string(part[:idx]), "\r\n") {
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) != 2 {
+			return nil, nil, errors.Errorf("invalid email body header '%s'", line)
+		}
+		header.Add(kv[0], kv[1])
+	}
+
+	return header, part[idx+4:], nil
+}
+
+// writeBase64 writes the data base64 encoded, in lines no longer than MIME
+// allows.
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > mimeLineLength {
+		if _, err := io.WriteString(w, encoded[:mimeLineLength]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[mimeLineLength:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+
+	return err
+}
+
+// SMTPSettings are the settings used to send multipart emails.
+type SMTPSettings struct {
+	Server   string
+	Port     int
+	UseSSL   bool
+	Username string
+	Password string
+	From     string
+}
+
+type evergreenEmailSender struct {
+	settings SMTPSettings
+	handler  send.ErrorHandler
+	send.Sender
+}
+
+// NewEmailSender wraps the SMTP sender so that it sends emails with HTML
+// bodies or attachments as multipart MIME messages, which the SMTP sender
+// cannot. All other messages are sent by the SMTP sender.
+func NewEmailSender(sender send.Sender, settings SMTPSettings) send.Sender {
+	if settings.Server == "" {
+		settings.Server = "localhost"
+	}
+	if settings.Port == 0 {
+		settings.Port = 25
+	}
+
+	return &evergreenEmailSender{
+		settings: settings,
+		Sender:   sender,
+	}
+}
+
+func (s *evergreenEmailSender) SetErrorHandler(h send.ErrorHandler) error {
+	if err := s.Sender.SetErrorHandler(h); err != nil {
+		return err
+	}
+	s.handler = h
+
+	return nil
+}
+
+func (s *evergreenEmailSender) Send(m message.Composer) {
+	email, ok := m.Raw().(*EvergreenEmail)
+	if !ok {
+		s.Sender.Send(m)
+		return
+	}
+	if !email.IsMultipart() {
+		s.Sender.Send(message.NewEmailMessage(m.Priority(), email.Email))
+		return
+	}
+	if !s.Level().ShouldLog(m) {
+		return
+	}
+
+	if err := s.sendMail(email); err != nil && s.handler != nil {
+		s.handler(err, m)
+	}
+}
+
+func (s *evergreenEmailSender) sendMail(email *EvergreenEmail) error {
+	from := &mail.Address{Address: s.settings.From}
+	if email.From != "" {
+		var err error
+		from, err = mail.ParseAddress(email.From)
+		if err != nil {
+			return errors.Wrapf(err, "invalid from address '%s'", email.From)
+		}
+	}
+	body, err := email.MIME(from)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	addr := net.JoinHostPort(s.settings.Server, strconv.Itoa(s.settings.Port))
+	conn, err := net.DialTimeout("tcp", addr, evergreenEmailTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to '%s'", addr)
+	}
+	if s.settings.UseSSL {
+		conn = tls.Client(conn, &tls.Config{ServerName: s.settings.Server})
+	}
+	if err = conn.SetDeadline(time.Now().Add(evergreenEmailTimeout)); err != nil {
+		return errors.WithStack(err)
+	}
+	client, err := smtp.NewClient(conn, s.settings.Server)
+	if err != nil {
+		conn.Close()
+		return errors.Wrapf(err, "failed to establish SMTP session with '%s'", addr)
+	}
+	defer client.Close()
+
+	if s.settings.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.settings.Username, s.settings.Password, s.settings.Server)); err != nil {
+			return errors.Wrap(err, "failed to authenticate to SMTP server")
+		}
+	}
+	if err = client.Mail(from.Address); err != nil {
+		return errors.Wrapf(err, "failed to set sender '%s'", from.Address)
+	}
+	for _, r := range email.Recipients {
+		var addr *mail.Address
+		addr, err = mail.ParseAddress(r)
+		if err != nil {
+			return errors.Wrapf(err, "invalid recipient '%s'", r)
+		}
+		if err = client.Rcpt(addr.Address); err != nil {
+			return errors.Wrapf(err, "failed to add recipient '%s'", addr.Address)
+		}
+	}
+
+	wc, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to start sending email")
+	}
+	if _, err = wc.Write(body); err != nil {
+		wc.Close()
+		return errors.Wrap(err, "failed to send email")
+	}
+	if err = wc.Close(); err != nil {
+		return errors.Wrap(err, "failed to send email")
+	}
+
+	return errors.Wrap(client.Quit(), "failed to close SMTP session")
+}
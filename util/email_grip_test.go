@@ -0,0 +1,158 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlainTextFromHTML(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("", PlainTextFromHTML(""))
+	assert.Equal("plain & simple", PlainTextFromHTML("plain &amp; simple"))
+	assert.Equal("Task failed\n\nSee the logs (https://example.com/logs) for details.\n\n- one\n- two\nline\nbreak",
+		PlainTextFromHTML(`<html><head><title>t</title><style>p {}</style></head><body>
+			<h1>Task failed</h1>
+			<p>See the <a href="https://example.com/logs">logs</a> for details.</p>
+			<ul><li>one</li><li>two</li></ul>
+			<div>line<br/>break</div>
+		</body></html>`))
+}
+
+func TestEvergreenEmailMIME(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	from := &mail.Address{Name: "Evergreen", Address: "evergreen@example.com"}
+
+	email := EvergreenEmail{
+		Email: message.Email{
+			Recipients:        []string{"me@example.com"},
+			Subject:           "task failed",
+			Body:              "plain",
+			PlainTextContents: true,
+			Headers:           map[string][]string{"X-Evergreen": {"yes"}, "Content-Type": {"ignored"}},
+		},
+	}
+
+	contents, err := email.MIME(from)
+	require.NoError(err)
+	msg, mediaType, _ := readMIME(t, contents)
+	assert.Equal(`"Evergreen" <evergreen@example.com>`, msg.Header.Get("From"))
+	assert.Equal("<me@example.com>", msg.Header.Get("To"))
+	assert.Equal("task failed", msg.Header.Get("Subject"))
+	assert.Equal("yes", msg.Header.Get("X-Evergreen"))
+	assert.Equal("text/plain", mediaType)
+	body, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, msg.Body))
+	require.NoError(err)
+	assert.Equal("plain", string(body))
+
+	// an HTML body is sent with a generated plain-text alternative
+	email.Body = ""
+	email.HTMLBody = "<p>see <b>logs</b></p>"
+	contents, err = email.MIME(from)
+	require.NoError(err)
+	msg, mediaType, params := readMIME(t, contents)
+	assert.Equal("multipart/alternative", mediaType)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	part, err := reader.NextPart()
+	require.NoError(err)
+	assert.Equal(`text/plain; charset="utf-8"`, part.Header.Get("Content-Type"))
+	assert.Equal("see logs", readBase64Part(t, part))
+	part, err = reader.NextPart()
+	require.NoError(err)
+	assert.Equal(`text/html; charset="utf-8"`, part.Header.Get("Content-Type"))
+	assert.Equal("<p>see <b>logs</b></p>", readBase64Part(t, part))
+
+	// attachments are sent after the body
+	log := bytes.Repeat([]byte("log line\n"), 20)
+	email.Attachments = []Attachment{
+		{Filename: "task.log", ContentType: "text/plain", Data: log},
+		{Filename: "data.bin", Data: []byte{0, 1, 2}},
+	}
+	contents, err = email.MIME(from)
+	require.NoError(err)
+	msg, mediaType, params = readMIME(t, contents)
+	assert.Equal("multipart/mixed", mediaType)
+	reader = multipart.NewReader(msg.Body, params["boundary"])
+
+	part, err = reader.NextPart()
+	require.NoError(err)
+	mediaType, params, err = mime.ParseMediaType(part.Header.Get("Content-Type"))
+	require.NoError(err)
+	assert.Equal("multipart/alternative", mediaType)
+	inner := multipart.NewReader(part, params["boundary"])
+	innerPart, err := inner.NextPart()
+	require.NoError(err)
+	assert.Equal("see logs", readBase64Part(t, innerPart))
+
+	part, err = reader.NextPart()
+	require.NoError(err)
+	assert.Equal("task.log", part.FileName())
+	assert.Equal("text/plain; name=task.log", part.Header.Get("Content-Type"))
+	assert.Equal(string(log), readBase64Part(t, part))
+
+	part, err = reader.NextPart()
+	require.NoError(err)
+	assert.Equal("data.bin", part.FileName())
+	assert.Equal("application/octet-stream; name=data.bin", part.Header.Get("Content-Type"))
+	assert.Equal(string([]byte{0, 1, 2}), readBase64Part(t, part))
+
+	// attachments in S3 must be fetched first
+	email.Attachments = []Attachment{{Filename: "task.log", S3URL: "s3://bucket/task.log"}}
+	_, err = email.MIME(from)
+	assert.EqualError(err, "attachment 'task.log' has not been fetched from 's3://bucket/task.log'")
+
+	email.Recipients = []string{"not an address"}
+	_, err = email.MIME(from)
+	assert.Error(err)
+}
+
+func readMIME(t *testing.T, contents []byte) (*mail.Message, string, map[string]string) {
+	msg, err := mail.ReadMessage(bytes.NewReader(contents))
+	require.NoError(t, err)
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+
+	return msg, mediaType, params
+}
+
+func readBase64Part(t *testing.T, p *multipart.Part) string {
+	assert.Equal(t, "base64", p.Header.Get("Content-Transfer-Encoding"))
+	data, err := ioutil.ReadAll(base64.NewDecoder(base64.StdEncoding, p))
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestEvergreenEmailMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	m := NewEmailMessage(level.Notice, EvergreenEmail{})
+	assert.False(m.Loggable())
+
+	email := EvergreenEmail{
+		Email:    message.Email{Recipients: []string{"me@example.com"}, Subject: "hi"},
+		HTMLBody: "<p>hello</p>",
+	}
+	m = NewEmailMessage(level.Notice, email)
+	assert.True(m.Loggable())
+	assert.Equal("hi\n\nhello", m.String())
+
+	email.Attachments = []Attachment{{Filename: "log.txt"}}
+	assert.False(NewEmailMessage(level.Notice, email).Loggable())
+	email.Attachments[0].S3URL = "s3://bucket/log.txt"
+	assert.True(NewEmailMessage(level.Notice, email).Loggable())
+	assert.Equal(level.Notice, m.Priority())
+	assert.True(strings.HasPrefix(m.String(), "hi"))
+}
@@ -0,0 +1,363 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+// Conclusions of completed GitHub check runs, and levels of their
+// annotations.
+const (
+	GithubCheckConclusionSuccess   = "success"
+	GithubCheckConclusionFailure   = "failure"
+	GithubCheckConclusionNeutral   = "neutral"
+	GithubCheckConclusionCancelled = "cancelled"
+	GithubCheckConclusionTimedOut  = "timed_out"
+
+	GithubAnnotationLevelNotice  = "notice"
+	GithubAnnotationLevelWarning = "warning"
+	GithubAnnotationLevelFailure = "failure"
+
+	// GithubCheckMaxAnnotations is the most annotations GitHub accepts in
+	// a request creating a check run.
+	GithubCheckMaxAnnotations = 50
+	// GithubCheckMaxSummaryLength is the longest summary GitHub accepts.
+	GithubCheckMaxSummaryLength = 65535
+
+	githubAPIURL = "https://api.github.com"
+	// githubChecksPreviewMediaType opts in to the checks API while it's in
+	// preview.
+	githubChecksPreviewMediaType = "application/vnd.github.antiope-preview+json"
+	githubChecksTimeout          = 10 * time.Second
+	// githubAppJWTDuration is how long the tokens authenticating as the
+	// app are valid, which GitHub limits to 10 minutes.
+	githubAppJWTDuration = 9 * time.Minute
+)
+
+var GithubCheckConclusions = []string{
+	GithubCheckConclusionSuccess,
+	GithubCheckConclusionFailure,
+	GithubCheckConclusionNeutral,
+	GithubCheckConclusionCancelled,
+	GithubCheckConclusionTimedOut,
+}
+
+// GithubCheckRun is a completed check run to report on a commit of a
+// repository with the GitHub checks API.
+type GithubCheckRun struct {
+	Owner       string               `bson:"owner,omitempty" json:"-"`
+	Repo        string               `bson:"repo,omitempty" json:"-"`
+	Name        string               `bson:"name" json:"name"`
+	HeadSHA     string               `bson:"head_sha,omitempty" json:"head_sha"`
+	ExternalID  string               `bson:"external_id,omitempty" json:"external_id,omitempty"`
+	DetailsURL  string               `bson:"details_url,omitempty" json:"details_url,omitempty"`
+	Conclusion  string               `bson:"conclusion" json:"conclusion"`
+	StartedAt   time.Time            `bson:"started_at,omitempty" json:"-"`
+	CompletedAt time.Time            `bson:"completed_at" json:"-"`
+	Output      GithubCheckRunOutput `bson:"output" json:"output"`
+}
+
+// GithubCheckRunOutput is the summary of a check run shown on GitHub, along
+// with annotations on the lines of the files the run found problems with.
+type GithubCheckRunOutput struct {
+	Title       string                     `bson:"title" json:"title"`
+	Summary     string                     `bson:"summary" json:"summary"`
+	Text        string                     `bson:"text,omitempty" json:"text,omitempty"`
+	Annotations []GithubCheckRunAnnotation `bson:"annotations,omitempty" json:"annotations,omitempty"`
+}
+
+type GithubCheckRunAnnotation struct {
+	Path            string `bson:"path" json:"path"`
+	StartLine       int    `bson:"start_line" json:"start_line"`
+	EndLine         int    `bson:"end_line" json:"end_line"`
+	AnnotationLevel string `bson:"annotation_level" json:"annotation_level"`
+	Title           string `bson:"title,omitempty" json:"title,omitempty"`
+	Message         string `bson:"message" json:"message"`
+}
+
+type githubCheckRunMessage struct {
+	raw GithubCheckRun
+
+	message.Base
+}
+
+// NewGithubCheckRunMessage returns a composer for the check run.
+func NewGithubCheckRunMessage(l level.Priority, run GithubCheckRun) message.Composer {
+	m := &githubCheckRunMessage{
+		raw: run,
+	}
+	_ = m.SetPriority(l)
+
+	return m
+}
+
+func (m *githubCheckRunMessage) Loggable() bool {
+	if len(m.raw.Owner) == 0 || len(m.raw.Repo) == 0 || len(m.raw.Name) == 0 || len(m.raw.HeadSHA) == 0 {
+		return false
+	}
+	if len(m.raw.Output.Title) == 0 || len(m.raw.Output.Summary) > GithubCheckMaxSummaryLength {
+		return false
+	}
+	if len(m.raw.Output.Annotations) > GithubCheckMaxAnnotations {
+		return false
+	}
+
+	return StringSliceContains(GithubCheckConclusions, m.raw.Conclusion)
+}
+
+func (m *githubCheckRunMessage) Raw() interface{} {
+	return &m.raw
+}
+
+func (m *githubCheckRunMessage) String() string {
+	return fmt.Sprintf("%s/%s@%s %s: %s", m.raw.Owner, m.raw.Repo, m.raw.HeadSHA, m.raw.Name, m.raw.Conclusion)
+}
+
+type githubInstallationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+type githubChecksLogger struct {
+	url    string
+	appID  int64
+	key    *rsa.PrivateKey
+	client *http.Client
+
+	mu sync.Mutex
+	// tokens caches the tokens of the app's installations by repository.
+	tokens map[string]githubInstallationToken
+
+	*send.Base
+}
+
+// NewGithubChecksLogger returns a sender that creates check runs from
+// messages composed by NewGithubCheckRunMessage, authenticating as the
+// installation of the GitHub App on each check run's repository.
+func NewGithubChecksLogger(appID int64, privateKey []byte) (send.Sender, error) {
+	key, err := parseGithubAppKey(privateKey)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &githubChecksLogger{
+		url:    githubAPIURL,
+		appID:  appID,
+		key:    key,
+		tokens: map[string]githubInstallationToken{},
+		Base:   send.NewBase("evergreen"),
+	}, nil
+}
+
+func parseGithubAppKey(privateKey []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return nil, errors.New("github app key is not PEM-encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse github app key")
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+func (g *githubChecksLogger) Send(m message.Composer) {
+	if g.Level().ShouldLog(m) {
+		if err := g.send(m); err != nil {
+			g.ErrorHandler(err, m)
+		}
+	}
+}
+
+func (g *githubChecksLogger) send(m message.Composer) error {
+	run, ok := m.Raw().(*GithubCheckRun)
+	if !ok {
+		return errors.New("github checks sender received unexpected composer")
+	}
+
+	token, err := g.installationToken(run.Owner, run.Repo)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	body := struct {
+		*GithubCheckRun
+		Status      string `json:"status"`
+		StartedAt   string `json:"started_at,omitempty"`
+		CompletedAt string `json:"completed_at"`
+	}{
+		GithubCheckRun: run,
+		Status:         "completed",
+		CompletedAt:    run.CompletedAt.UTC().Format(time.RFC3339),
+	}
+	if !IsZeroTime(run.StartedAt) {
+		body.StartedAt = run.StartedAt.UTC().Format(time.RFC3339)
+	}
+
+	status, _, err := g.do(http.MethodPost, fmt.Sprintf("/repos/%s/%s/check-runs", run.Owner, run.Repo), "token "+token, body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create github check run")
+	}
+	if status == http.StatusUnauthorized {
+		// the installation's token was revoked, so get a new one next time
+		g.mu.Lock()
+		delete(g.tokens, run.Owner+"/"+run.Repo)
+		g.mu.Unlock()
+	}
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return errors.Errorf("github check run response status was %d %s", status, http.StatusText(status))
+	}
+
+	return nil
+}
+
+// installationToken returns a token authenticating as the app's
+// installation on the repository, reusing it until shortly before it
+// expires.
+func (g *githubChecksLogger) installationToken(owner, repo string) (string, error) {
+	repoName := owner + "/" + repo
+	g.mu.Lock()
+	cached, ok := g.tokens[repoName]
+	g.mu.Unlock()
+	if ok && time.Now().Add(time.Minute).Before(cached.expiresAt) {
+		return cached.token, nil
+	}
+
+	jwt, err := g.appJWT()
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	installation := struct {
+		ID int64 `json:"id"`
+	}{}
+	if err = g.doJSON(http.MethodGet, fmt.Sprintf("/repos/%s/%s/installation", owner, repo), "Bearer "+jwt, nil, &installation); err != nil {
+		return "", errors.Wrapf(err, "failed to find github app installation on '%s'", repoName)
+	}
+
+	token := struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}{}
+	if err = g.doJSON(http.MethodPost, fmt.Sprintf("/app/installations/%d/access_tokens", installation.ID), "Bearer "+jwt, nil, &token); err != nil {
+		return "", errors.Wrapf(err, "failed to create github app installation token for '%s'", repoName)
+	}
+
+	g.mu.Lock()
+	g.tokens[repoName] = githubInstallationToken{token: token.Token, expiresAt: token.ExpiresAt}
+	g.mu.Unlock()
+
+	return token.Token, nil
+}
+
+// appJWT returns a token authenticating as the app itself, which is signed
+// with the app's private key.
+func (g *githubChecksLogger) appJWT() (string, error) {
+	now := time.Now()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		// allow for clock drift between us and GitHub
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(githubAppJWTDuration).Unix(),
+		"iss": strconv.FormatInt(g.appID, 10),
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, g.key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign github app token")
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+func (g *githubChecksLogger) doJSON(method, path, auth string, body, out interface{}) error {
+	status, respBody, err := g.do(method, path, auth, body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return errors.Errorf("github response status was %d %s", status, http.StatusText(status))
+	}
+
+	return errors.Wrap(json.Unmarshal(respBody, out), "failed to decode github response")
+}
+
+// do sends a request to the GitHub API, returning the status and the body
+// of the response.
+func (g *githubChecksLogger) do(method, path, auth string, body interface{}) (int, []byte, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return 0, nil, errors.Wrap(err, "failed to encode github request")
+		}
+	}
+
+	req, err := http.NewRequest(method, g.url+path, bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to create github request")
+	}
+	req.Header.Set("Accept", githubChecksPreviewMediaType)
+	req.Header.Set("Authorization", auth)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), githubChecksTimeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	client := g.client
+	if client == nil {
+		client = GetHTTPClient()
+		defer PutHTTPClient(client)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to read github response")
+	}
+
+	return resp.StatusCode, respBody, nil
+}
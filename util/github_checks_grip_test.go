@@ -0,0 +1,147 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGithubCheckRunMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	run := GithubCheckRun{
+		Owner:      "evergreen-ci",
+		Repo:       "evergreen",
+		Name:       "evergreen/ubuntu",
+		HeadSHA:    "abcdef",
+		Conclusion: GithubCheckConclusionFailure,
+		Output:     GithubCheckRunOutput{Title: "build failed", Summary: "1 failed"},
+	}
+	m := NewGithubCheckRunMessage(level.Notice, run)
+	assert.True(m.Loggable())
+	assert.Equal("evergreen-ci/evergreen@abcdef evergreen/ubuntu: failure", m.String())
+	raw, ok := m.Raw().(*GithubCheckRun)
+	require.True(t, ok)
+	assert.Equal(run, *raw)
+
+	invalid := run
+	invalid.HeadSHA = ""
+	assert.False(NewGithubCheckRunMessage(level.Notice, invalid).Loggable())
+
+	invalid = run
+	invalid.Conclusion = "passed"
+	assert.False(NewGithubCheckRunMessage(level.Notice, invalid).Loggable())
+
+	invalid = run
+	invalid.Output.Title = ""
+	assert.False(NewGithubCheckRunMessage(level.Notice, invalid).Loggable())
+
+	invalid = run
+	invalid.Output.Summary = strings.Repeat("a", GithubCheckMaxSummaryLength+1)
+	assert.False(NewGithubCheckRunMessage(level.Notice, invalid).Loggable())
+
+	invalid = run
+	invalid.Output.Annotations = make([]GithubCheckRunAnnotation, GithubCheckMaxAnnotations+1)
+	assert.False(NewGithubCheckRunMessage(level.Notice, invalid).Loggable())
+}
+
+func TestGithubChecksSender(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	tokenRequests := 0
+	checkStatus := http.StatusCreated
+	var posted map[string]interface{}
+	var checkAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(githubChecksPreviewMediaType, r.Header.Get("Accept"))
+		switch r.URL.Path {
+		case "/repos/evergreen-ci/evergreen/installation":
+			assert.True(strings.HasPrefix(r.Header.Get("Authorization"), "Bearer "))
+			_, _ = w.Write([]byte(`{"id": 7}`))
+		case "/app/installations/7/access_tokens":
+			tokenRequests++
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"token": "installation-token", "expires_at": "` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`))
+		case "/repos/evergreen-ci/evergreen/check-runs":
+			checkAuth = r.Header.Get("Authorization")
+			posted = nil
+			assert.NoError(json.NewDecoder(r.Body).Decode(&posted))
+			w.WriteHeader(checkStatus)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	_, err = NewGithubChecksLogger(1, []byte("not a key"))
+	assert.Error(err)
+
+	sender, err := NewGithubChecksLogger(1, pemKey)
+	require.NoError(err)
+	sender.(*githubChecksLogger).url = server.URL
+	require.NoError(sender.SetLevel(send.LevelInfo{Default: level.Notice, Threshold: level.Notice}))
+	var sendErr error
+	require.NoError(sender.SetErrorHandler(func(err error, _ message.Composer) { sendErr = err }))
+
+	run := GithubCheckRun{
+		Owner:       "evergreen-ci",
+		Repo:        "evergreen",
+		Name:        "evergreen/ubuntu",
+		HeadSHA:     "abcdef",
+		ExternalID:  "build1",
+		Conclusion:  GithubCheckConclusionFailure,
+		CompletedAt: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Output: GithubCheckRunOutput{
+			Title:   "build failed",
+			Summary: "1 failed",
+			Annotations: []GithubCheckRunAnnotation{
+				{Path: "main.go", StartLine: 3, EndLine: 3, AnnotationLevel: GithubAnnotationLevelFailure, Message: "main.go failed"},
+			},
+		},
+	}
+	sender.Send(NewGithubCheckRunMessage(level.Notice, run))
+	assert.NoError(sendErr)
+	assert.Equal("token installation-token", checkAuth)
+	assert.Equal("evergreen/ubuntu", posted["name"])
+	assert.Equal("abcdef", posted["head_sha"])
+	assert.Equal("build1", posted["external_id"])
+	assert.Equal("completed", posted["status"])
+	assert.Equal("failure", posted["conclusion"])
+	assert.Equal("2019-01-01T00:00:00Z", posted["completed_at"])
+	assert.NotContains(posted, "started_at")
+	output, ok := posted["output"].(map[string]interface{})
+	require.True(ok)
+	assert.Len(output["annotations"], 1)
+
+	sender.Send(NewGithubCheckRunMessage(level.Notice, run))
+	assert.NoError(sendErr)
+	assert.Equal(1, tokenRequests)
+
+	checkStatus = http.StatusUnauthorized
+	sender.Send(NewGithubCheckRunMessage(level.Notice, run))
+	assert.EqualError(sendErr, "github check run response status was 401 Unauthorized")
+
+	checkStatus = http.StatusCreated
+	sendErr = nil
+	sender.Send(NewGithubCheckRunMessage(level.Notice, run))
+	assert.NoError(sendErr)
+	assert.Equal(2, tokenRequests)
+}
@@ -2,6 +2,8 @@ package util
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -22,3 +24,168 @@ func MakeNotificationErrorHandler(name string) send.ErrorHandler {
 		}))
 	}
 }
+
+// NotificationSender is a sender that reports whether each message was
+// delivered, rather than only passing delivery errors to its error handler.
+type NotificationSender interface {
+	send.Sender
+	// SendWithError sends the message, returning the delivery error, if
+	// any, in addition to passing it to the error handler.
+	SendWithError(message.Composer) error
+}
+
+// NotificationSenderOptions configure the limits that a NotificationSender
+// places on sending.
+type NotificationSenderOptions struct {
+	// PerMinute is the maximum number of messages sent per minute. Zero
+	// means no limit.
+	PerMinute int
+	// BreakerThreshold is the number of consecutive failed sends after
+	// which sending is suspended for BreakerCooldown. After the cooldown,
+	// a single failure suspends sending again. Zero disables the breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// SenderUnavailableError is returned by a NotificationSender that did not
+// attempt to send a message, because it reached its rate limit or its
+// circuit breaker is open. The message can be sent after RetryAfter.
+type SenderUnavailableError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *SenderUnavailableError) Error() string {
+	return fmt.Sprintf("sender is unavailable for %s: %s", e.RetryAfter, e.Reason)
+}
+
+type notificationSender struct {
+	send.Sender
+	opts NotificationSenderOptions
+	now  func() time.Time
+
+	mu      sync.Mutex
+	handler send.ErrorHandler
+
+	windowStart time.Time
+	windowSent  int
+
+	failures    int
+	tripped     bool
+	brokenUntil time.Time
+}
+
+// NewNotificationSender wraps the sender so that it reports delivery errors,
+// and enforces the limits in the options. The wrapped sender's error handler
+// must be set through the returned sender. Messages sent through the
+// returned sender are sent one at a time.
+func NewNotificationSender(sender send.Sender, opts NotificationSenderOptions) NotificationSender {
+	return &notificationSender{
+		Sender: sender,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+func (s *notificationSender) SetErrorHandler(h send.ErrorHandler) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.Sender.SetErrorHandler(h); err != nil {
+		return err
+	}
+	s.handler = h
+
+	return nil
+}
+
+func (s *notificationSender) Send(m message.Composer) {
+	err := s.SendWithError(m)
+	if _, ok := err.(*SenderUnavailableError); ok && s.handler != nil {
+		s.handler(err, m)
+	}
+}
+
+func (s *notificationSender) SendWithError(m message.Composer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkAvailable(); err != nil {
+		return err
+	}
+
+	// senders pass delivery errors to their error handler while sending,
+	// so capture them by swapping the handler for the duration of the send
+	var sendErr error
+	err := s.Sender.SetErrorHandler(func(err error, m message.Composer) {
+		if err == nil {
+			return
+		}
+		sendErr = err
+		if s.handler != nil {
+			s.handler(err, m)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if s.handler != nil {
+			_ = s.Sender.SetErrorHandler(s.handler)
+		}
+	}()
+
+	s.Sender.Send(m)
+	s.recordResult(sendErr)
+
+	return sendErr
+}
+
+// checkAvailable returns an error if the rate limit was reached or the
+// circuit breaker is open, and otherwise counts a message against the
+// rate limit.
+func (s *notificationSender) checkAvailable() error {
+	now := s.now()
+
+	if now.Before(s.brokenUntil) {
+		return &SenderUnavailableError{
+			Reason:     "too many consecutive failures",
+			RetryAfter: s.brokenUntil.Sub(now),
+		}
+	}
+
+	if s.opts.PerMinute > 0 {
+		if now.Sub(s.windowStart) >= time.Minute {
+			s.windowStart = now
+			s.windowSent = 0
+		}
+		if s.windowSent >= s.opts.PerMinute {
+			return &SenderUnavailableError{
+				Reason:     fmt.Sprintf("rate limit of %d per minute reached", s.opts.PerMinute),
+				RetryAfter: s.windowStart.Add(time.Minute).Sub(now),
+			}
+		}
+		s.windowSent++
+	}
+
+	return nil
+}
+
+// recordResult updates the circuit breaker with the result of a send.
+func (s *notificationSender) recordResult(err error) {
+	if s.opts.BreakerThreshold <= 0 {
+		return
+	}
+	if err == nil {
+		s.failures = 0
+		s.tripped = false
+		return
+	}
+
+	s.failures++
+	if s.tripped || s.failures >= s.opts.BreakerThreshold {
+		s.tripped = true
+		s.failures = 0
+		s.brokenUntil = s.now().Add(s.opts.BreakerCooldown)
+	}
+}
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingSender passes an error to its error handler for every message
+// other than "ok", replacing the message as some senders do.
+type failingSender struct {
+	*send.Base
+}
+
+func (s *failingSender) Send(m message.Composer) {
+	if m.String() != "ok" {
+		s.ErrorHandler(errors.New("failed to send"), message.NewString("replaced"))
+	}
+}
+
+func TestNotificationSender(t *testing.T) {
+	assert := assert.New(t)
+
+	sender := NewNotificationSender(&failingSender{Base: send.NewBase("test")}, NotificationSenderOptions{})
+	handled := 0
+	assert.NoError(sender.SetErrorHandler(func(err error, _ message.Composer) {
+		if err != nil {
+			handled++
+		}
+	}))
+
+	assert.NoError(sender.SendWithError(message.NewDefaultMessage(level.Notice, "ok")))
+	assert.Zero(handled)
+
+	assert.EqualError(sender.SendWithError(message.NewDefaultMessage(level.Notice, "not ok")), "failed to send")
+	assert.Equal(1, handled)
+
+	// the configured handler is restored after sending
+	sender.Send(message.NewDefaultMessage(level.Notice, "not ok"))
+	assert.Equal(2, handled)
+	assert.NoError(sender.SendWithError(message.NewDefaultMessage(level.Notice, "ok")))
+	assert.Equal(2, handled)
+}
+
+func TestNotificationSenderRateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	sender := NewNotificationSender(&failingSender{Base: send.NewBase("test")}, NotificationSenderOptions{PerMinute: 2})
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	sender.(*notificationSender).now = func() time.Time { return now }
+	ok := message.NewDefaultMessage(level.Notice, "ok")
+
+	assert.NoError(sender.SendWithError(ok))
+	now = now.Add(20 * time.Second)
+	assert.NoError(sender.SendWithError(ok))
+
+	err := sender.SendWithError(ok)
+	unavailable, isUnavailable := err.(*SenderUnavailableError)
+	assert.True(isUnavailable)
+	assert.Equal(40*time.Second, unavailable.RetryAfter)
+
+	now = now.Add(40 * time.Second)
+	assert.NoError(sender.SendWithError(ok))
+}
+
+func TestNotificationSenderCircuitBreaker(t *testing.T) {
+	assert := assert.New(t)
+
+	sender := NewNotificationSender(&failingSender{Base: send.NewBase("test")}, NotificationSenderOptions{
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+	})
+	now := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
+	sender.(*notificationSender).now = func() time.Time { return now }
+	ok := message.NewDefaultMessage(level.Notice, "ok")
+	notOK := message.NewDefaultMessage(level.Notice, "not ok")
+
+	assert.EqualError(sender.SendWithError(notOK), "failed to send")
+	assert.EqualError(sender.SendWithError(notOK), "failed to send")
+
+	// the breaker is open until the cooldown passes
+	now = now.Add(30 * time.Second)
+	err := sender.SendWithError(ok)
+	unavailable, isUnavailable := err.(*SenderUnavailableError)
+	assert.True(isUnavailable)
+	assert.Equal(30*time.Second, unavailable.RetryAfter)
+
+	// a single failure after the cooldown reopens it
+	now = now.Add(30 * time.Second)
+	assert.EqualError(sender.SendWithError(notOK), "failed to send")
+	_, isUnavailable = sender.SendWithError(ok).(*SenderUnavailableError)
+	assert.True(isUnavailable)
+
+	// and a success closes it
+	now = now.Add(time.Minute)
+	assert.NoError(sender.SendWithError(ok))
+	assert.EqualError(sender.SendWithError(notOK), "failed to send")
+	assert.NoError(sender.SendWithError(ok))
+}
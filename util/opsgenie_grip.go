@@ -0,0 +1,192 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+// Opsgenie alert priorities, from most to least urgent.
+const (
+	OpsgeniePriorityCritical = "P1"
+	OpsgeniePriorityHigh     = "P2"
+	OpsgeniePriorityModerate = "P3"
+	OpsgeniePriorityLow      = "P4"
+	OpsgeniePriorityInfo     = "P5"
+
+	OpsgenieMaxMessageLength = 130
+	OpsgenieMaxAliasLength   = 512
+	OpsgenieMaxTags          = 20
+
+	opsgenieAlertsURL = "https://api.opsgenie.com/v2/alerts"
+	opsgenieTimeout   = 10 * time.Second
+)
+
+var OpsgeniePriorities = []string{
+	OpsgeniePriorityCritical,
+	OpsgeniePriorityHigh,
+	OpsgeniePriorityModerate,
+	OpsgeniePriorityLow,
+	OpsgeniePriorityInfo,
+}
+
+// OpsgeniePriority maps the priority of a message to the priority of the
+// Opsgenie alert for it.
+func OpsgeniePriority(l level.Priority) string {
+	switch {
+	case l >= level.Alert:
+		return OpsgeniePriorityCritical
+	case l >= level.Critical:
+		return OpsgeniePriorityHigh
+	case l >= level.Error:
+		return OpsgeniePriorityModerate
+	case l >= level.Warning:
+		return OpsgeniePriorityLow
+	default:
+		return OpsgeniePriorityInfo
+	}
+}
+
+// OpsgenieAlert is an alert to create with the API key of an Opsgenie
+// integration. Opsgenie deduplicates alerts by alias: an alert with the
+// same alias as an open alert increments the count of the open alert
+// instead of creating a new one.
+type OpsgenieAlert struct {
+	APIKey      string            `bson:"api_key,omitempty" json:"-"`
+	Team        string            `bson:"team,omitempty" json:"-"`
+	Message     string            `bson:"message" json:"message"`
+	Alias       string            `bson:"alias,omitempty" json:"alias,omitempty"`
+	Description string            `bson:"description,omitempty" json:"description,omitempty"`
+	Priority    string            `bson:"priority,omitempty" json:"priority,omitempty"`
+	Tags        []string          `bson:"tags,omitempty" json:"tags,omitempty"`
+	Details     map[string]string `bson:"details,omitempty" json:"details,omitempty"`
+	Entity      string            `bson:"entity,omitempty" json:"entity,omitempty"`
+	Source      string            `bson:"source,omitempty" json:"source,omitempty"`
+}
+
+type opsgenieMessage struct {
+	raw OpsgenieAlert
+
+	message.Base
+}
+
+// NewOpsgenieMessage returns a composer for the alert. Alerts without a
+// priority are created with the priority mapped from the level.
+func NewOpsgenieMessage(l level.Priority, alert OpsgenieAlert) message.Composer {
+	if alert.Priority == "" {
+		alert.Priority = OpsgeniePriority(l)
+	}
+	m := &opsgenieMessage{
+		raw: alert,
+	}
+	_ = m.SetPriority(l)
+
+	return m
+}
+
+func (m *opsgenieMessage) Loggable() bool {
+	if len(m.raw.APIKey) == 0 || len(m.raw.Message) == 0 {
+		return false
+	}
+	if len(m.raw.Message) > OpsgenieMaxMessageLength || len(m.raw.Alias) > OpsgenieMaxAliasLength {
+		return false
+	}
+	if len(m.raw.Tags) > OpsgenieMaxTags {
+		return false
+	}
+
+	return StringSliceContains(OpsgeniePriorities, m.raw.Priority)
+}
+
+func (m *opsgenieMessage) Raw() interface{} {
+	return &m.raw
+}
+
+func (m *opsgenieMessage) String() string {
+	return m.raw.Message
+}
+
+type opsgenieResponder struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type opsgenieLogger struct {
+	url    string
+	client *http.Client
+	*send.Base
+}
+
+// NewOpsgenieLogger returns a sender that creates Opsgenie alerts from
+// messages composed by NewOpsgenieMessage. The API key belongs to each
+// alert, so the sender itself holds no credentials.
+func NewOpsgenieLogger() (send.Sender, error) {
+	return &opsgenieLogger{
+		url:  opsgenieAlertsURL,
+		Base: send.NewBase("evergreen"),
+	}, nil
+}
+
+func (o *opsgenieLogger) Send(m message.Composer) {
+	if o.Level().ShouldLog(m) {
+		if err := o.send(m); err != nil {
+			o.ErrorHandler(err, m)
+		}
+	}
+}
+
+func (o *opsgenieLogger) send(m message.Composer) error {
+	alert, ok := m.Raw().(*OpsgenieAlert)
+	if !ok {
+		return errors.New("opsgenie sender received unexpected composer")
+	}
+
+	body := struct {
+		*OpsgenieAlert
+		Responders []opsgenieResponder `json:"responders,omitempty"`
+	}{
+		OpsgenieAlert: alert,
+	}
+	if alert.Team != "" {
+		body.Responders = []opsgenieResponder{{Name: alert.Team, Type: "team"}}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode opsgenie alert")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, o.url, bytes.NewReader(payload))
+	if err != nil {
+		return errors.Wrap(err, "failed to create opsgenie request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+alert.APIKey)
+
+	ctx, cancel := context.WithTimeout(req.Context(), opsgenieTimeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	client := o.client
+	if client == nil {
+		client = GetHTTPClient()
+		defer PutHTTPClient(client)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to create opsgenie alert")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("opsgenie response status was %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	return nil
+}
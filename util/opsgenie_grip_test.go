@@ -0,0 +1,99 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpsgeniePriority(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(OpsgeniePriorityCritical, OpsgeniePriority(level.Emergency))
+	assert.Equal(OpsgeniePriorityCritical, OpsgeniePriority(level.Alert))
+	assert.Equal(OpsgeniePriorityHigh, OpsgeniePriority(level.Critical))
+	assert.Equal(OpsgeniePriorityModerate, OpsgeniePriority(level.Error))
+	assert.Equal(OpsgeniePriorityLow, OpsgeniePriority(level.Warning))
+	assert.Equal(OpsgeniePriorityInfo, OpsgeniePriority(level.Notice))
+	assert.Equal(OpsgeniePriorityInfo, OpsgeniePriority(level.Debug))
+}
+
+func TestOpsgenieMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(NewOpsgenieMessage(level.Error, OpsgenieAlert{Message: "task failed"}).Loggable())
+	assert.False(NewOpsgenieMessage(level.Error, OpsgenieAlert{APIKey: "key"}).Loggable())
+	assert.False(NewOpsgenieMessage(level.Error, OpsgenieAlert{APIKey: "key", Message: strings.Repeat("a", OpsgenieMaxMessageLength+1)}).Loggable())
+	assert.False(NewOpsgenieMessage(level.Error, OpsgenieAlert{APIKey: "key", Message: "task failed", Priority: "P0"}).Loggable())
+	assert.False(NewOpsgenieMessage(level.Error, OpsgenieAlert{APIKey: "key", Message: "task failed", Tags: make([]string, OpsgenieMaxTags+1)}).Loggable())
+
+	m := NewOpsgenieMessage(level.Error, OpsgenieAlert{APIKey: "key", Message: "task failed", Alias: "task1"})
+	assert.True(m.Loggable())
+	assert.Equal(level.Error, m.Priority())
+	assert.Equal("task failed", m.String())
+	raw, ok := m.Raw().(*OpsgenieAlert)
+	require.True(t, ok)
+	assert.Equal(OpsgeniePriorityModerate, raw.Priority)
+
+	m = NewOpsgenieMessage(level.Notice, OpsgenieAlert{APIKey: "key", Message: "task failed", Priority: OpsgeniePriorityCritical})
+	assert.Equal(OpsgeniePriorityCritical, m.Raw().(*OpsgenieAlert).Priority)
+}
+
+func TestOpsgenieSender(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var posted map[string]interface{}
+	var auth string
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		posted = nil
+		assert.NoError(json.NewDecoder(r.Body).Decode(&posted))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender, err := NewOpsgenieLogger()
+	require.NoError(err)
+	sender.(*opsgenieLogger).url = server.URL
+	require.NoError(sender.SetLevel(send.LevelInfo{Default: level.Notice, Threshold: level.Notice}))
+	var sendErr error
+	require.NoError(sender.SetErrorHandler(func(err error, _ message.Composer) { sendErr = err }))
+
+	sender.Send(NewOpsgenieMessage(level.Error, OpsgenieAlert{
+		APIKey:  "key",
+		Team:    "evergreen",
+		Message: "task failed",
+		Alias:   "task1",
+		Tags:    []string{"evergreen"},
+		Details: map[string]string{"project": "mci"},
+	}))
+	assert.NoError(sendErr)
+	assert.Equal("GenieKey key", auth)
+	assert.Equal(map[string]interface{}{
+		"message":    "task failed",
+		"alias":      "task1",
+		"priority":   "P3",
+		"tags":       []interface{}{"evergreen"},
+		"details":    map[string]interface{}{"project": "mci"},
+		"responders": []interface{}{map[string]interface{}{"name": "evergreen", "type": "team"}},
+	}, posted)
+
+	sender.Send(NewOpsgenieMessage(level.Error, OpsgenieAlert{APIKey: "key", Message: "task failed"}))
+	assert.NoError(sendErr)
+	assert.NotContains(posted, "responders")
+	assert.NotContains(posted, "alias")
+
+	status = http.StatusUnauthorized
+	sender.Send(NewOpsgenieMessage(level.Error, OpsgenieAlert{APIKey: "wrong", Message: "task failed"}))
+	assert.EqualError(sendErr, "opsgenie response status was 401 Unauthorized")
+}
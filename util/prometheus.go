@@ -0,0 +1,395 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition
+// format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultPrometheusBuckets are the upper bounds, in seconds, of the buckets
+// that latency histograms count observations in.
+var DefaultPrometheusBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// PrometheusMetrics is the registry of the metrics that the services export.
+var PrometheusMetrics = NewPrometheusRegistry()
+
+// PrometheusCollector is a metric family that can be written in the
+// Prometheus text exposition format.
+type PrometheusCollector interface {
+	PrometheusName() string
+	WritePrometheus(io.Writer) error
+}
+
+// PrometheusRegistry holds metric families and writes them in the Prometheus
+// text exposition format, so that they can be scraped without depending on
+// the Prometheus client library.
+type PrometheusRegistry struct {
+	mu         sync.RWMutex
+	collectors map[string]PrometheusCollector
+}
+
+func NewPrometheusRegistry() *PrometheusRegistry {
+	return &PrometheusRegistry{collectors: map[string]PrometheusCollector{}}
+}
+
+// Register adds a metric family to the registry, replacing any family with
+// the same name.
+func (r *PrometheusRegistry) Register(c PrometheusCollector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors[c.PrometheusName()] = c
+}
+
+// Write writes all of the registered metric families, sorted by name. A
+// family that fails to collect is logged and left out, so that it doesn't
+// hide the others.
+func (r *PrometheusRegistry) Write(w io.Writer) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	collectors := make([]PrometheusCollector, 0, len(names))
+	sort.Strings(names)
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.RUnlock()
+
+	for _, c := range collectors {
+		buf := &bytes.Buffer{}
+		if err := c.WritePrometheus(buf); err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"message": "problem collecting metric",
+				"metric":  c.PrometheusName(),
+			}))
+			continue
+		}
+		if _, err := buf.WriteTo(w); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP responds with the registered metrics.
+func (r *PrometheusRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	buf := &bytes.Buffer{}
+	if err := r.Write(buf); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", PrometheusContentType)
+	_, _ = buf.WriteTo(w)
+}
+
+// prometheusFamily is the name, help and label names of a metric family.
+type prometheusFamily struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (f prometheusFamily) PrometheusName() string { return f.name }
+
+func (f prometheusFamily) writeHeader(w io.Writer, metricType string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapePrometheusHelp(f.help), f.name, metricType)
+	return err
+}
+
+func (f prometheusFamily) labelKey(values []string) string {
+	if len(values) != len(f.labels) {
+		grip.Critical(message.Fields{
+			"message":  "wrong number of label values for metric",
+			"metric":   f.name,
+			"labels":   f.labels,
+			"values":   values,
+			"expected": len(f.labels),
+		})
+		padded := make([]string, len(f.labels))
+		copy(padded, values)
+		values = padded
+	}
+
+	return strings.Join(values, "\xff")
+}
+
+// formatLabels formats the labels of a sample, including any extra label
+// name and value pairs.
+func (f prometheusFamily) formatLabels(key string, extra ...string) string {
+	pairs := []string{}
+	if len(f.labels) > 0 {
+		for i, value := range strings.Split(key, "\xff") {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, f.labels[i], escapePrometheusLabel(value)))
+		}
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], escapePrometheusLabel(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// PrometheusCounterVec is a counter partitioned by the values of its labels.
+type PrometheusCounterVec struct {
+	prometheusFamily
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewPrometheusCounterVec creates a counter and registers it with the
+// PrometheusMetrics registry.
+func NewPrometheusCounterVec(name, help string, labels ...string) *PrometheusCounterVec {
+	c := &PrometheusCounterVec{
+		prometheusFamily: prometheusFamily{name: name, help: help, labels: labels},
+		values:           map[string]float64{},
+	}
+	PrometheusMetrics.Register(c)
+
+	return c
+}
+
+// Inc increments the counter with the given label values.
+func (c *PrometheusCounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds a non-negative value to the counter with the given label values.
+func (c *PrometheusCounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	key := c.labelKey(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] += v
+}
+
+// Value returns the counter with the given label values.
+func (c *PrometheusCounterVec) Value(labelValues ...string) float64 {
+	key := c.labelKey(labelValues)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+func (c *PrometheusCounterVec) WritePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	values := make(map[string]float64, len(c.values))
+	for key, v := range c.values {
+		values[key] = v
+	}
+	c.mu.Unlock()
+	sort.Strings(keys)
+
+	if err := c.writeHeader(w, "counter"); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.name, c.formatLabels(key), formatPrometheusValue(values[key])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type prometheusHistogramValue struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// PrometheusHistogramVec is a histogram partitioned by the values of its
+// labels.
+type PrometheusHistogramVec struct {
+	prometheusFamily
+	buckets []float64
+	mu      sync.Mutex
+	values  map[string]*prometheusHistogramValue
+}
+
+// NewPrometheusHistogramVec creates a histogram that counts observations in
+// buckets with the given upper bounds, and registers it with the
+// PrometheusMetrics registry.
+func NewPrometheusHistogramVec(name, help string, buckets []float64, labels ...string) *PrometheusHistogramVec {
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+	h := &PrometheusHistogramVec{
+		prometheusFamily: prometheusFamily{name: name, help: help, labels: labels},
+		buckets:          sorted,
+		values:           map[string]*prometheusHistogramValue{},
+	}
+	PrometheusMetrics.Register(h)
+
+	return h
+}
+
+// Observe records an observation in the histogram with the given label
+// values.
+func (h *PrometheusHistogramVec) Observe(v float64, labelValues ...string) {
+	key := h.labelKey(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	value, ok := h.values[key]
+	if !ok {
+		value = &prometheusHistogramValue{buckets: make([]uint64, len(h.buckets))}
+		h.values[key] = value
+	}
+	for i, bound := range h.buckets {
+		if v <= bound {
+			value.buckets[i]++
+		}
+	}
+	value.count++
+	value.sum += v
+}
+
+// Count returns the number of observations in the histogram with the given
+// label values.
+func (h *PrometheusHistogramVec) Count(labelValues ...string) uint64 {
+	key := h.labelKey(labelValues)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if value, ok := h.values[key]; ok {
+		return value.count
+	}
+	return 0
+}
+
+func (h *PrometheusHistogramVec) WritePrometheus(w io.Writer) error {
+	h.mu.Lock()
+	keys := make([]string, 0, len(h.values))
+	values := make(map[string]prometheusHistogramValue, len(h.values))
+	for key, v := range h.values {
+		keys = append(keys, key)
+		values[key] = prometheusHistogramValue{
+			buckets: append([]uint64{}, v.buckets...),
+			count:   v.count,
+			sum:     v.sum,
+		}
+	}
+	h.mu.Unlock()
+	sort.Strings(keys)
+
+	if err := h.writeHeader(w, "histogram"); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		value := values[key]
+		for i, bound := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.formatLabels(key, "le", formatPrometheusValue(bound)), value.buckets[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.formatLabels(key, "le", "+Inf"), value.count); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n", h.name, h.formatLabels(key), formatPrometheusValue(value.sum), h.name, h.formatLabels(key), value.count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrometheusSample is the value of a gauge with the given label values.
+type PrometheusSample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// PrometheusGaugeFunc is a gauge whose values are collected when the metrics
+// are scraped.
+type PrometheusGaugeFunc struct {
+	prometheusFamily
+	collect func() ([]PrometheusSample, error)
+}
+
+// NewPrometheusGaugeFunc creates a gauge that's collected by calling the
+// given function, and registers it with the PrometheusMetrics registry.
+func NewPrometheusGaugeFunc(name, help string, collect func() ([]PrometheusSample, error), labels ...string) *PrometheusGaugeFunc {
+	g := &PrometheusGaugeFunc{
+		prometheusFamily: prometheusFamily{name: name, help: help, labels: labels},
+		collect:          collect,
+	}
+	PrometheusMetrics.Register(g)
+
+	return g
+}
+
+func (g *PrometheusGaugeFunc) WritePrometheus(w io.Writer) error {
+	samples, err := g.collect()
+	if err != nil {
+		return errors.Wrapf(err, "problem collecting '%s'", g.name)
+	}
+	values := map[string]float64{}
+	keys := make([]string, 0, len(samples))
+	for _, s := range samples {
+		key := g.labelKey(s.LabelValues)
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = s.Value
+	}
+	sort.Strings(keys)
+
+	if err := g.writeHeader(w, "gauge"); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", g.name, g.formatLabels(key), formatPrometheusValue(values[key])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func formatPrometheusValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	prometheusHelpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapePrometheusLabel(s string) string { return prometheusLabelReplacer.Replace(s) }
+func escapePrometheusHelp(s string) string  { return prometheusHelpReplacer.Replace(s) }
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusRegistry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	counter := NewPrometheusCounterVec("test_sent_total", "Sent things.\nPer outcome.", "type", "outcome")
+	counter.Inc("email", "sent")
+	counter.Inc("email", "sent")
+	counter.Add(3, "slack", `fail"ed`)
+	counter.Add(-1, "slack", `fail"ed`)
+	assert.Equal(float64(2), counter.Value("email", "sent"))
+	assert.Equal(float64(3), counter.Value("slack", `fail"ed`))
+
+	histogram := NewPrometheusHistogramVec("test_duration_seconds", "Durations.", []float64{1, 0.1}, "route")
+	histogram.Observe(0.05, "/a")
+	histogram.Observe(0.5, "/a")
+	histogram.Observe(5, "/a")
+	assert.EqualValues(3, histogram.Count("/a"))
+	assert.EqualValues(0, histogram.Count("/b"))
+
+	NewPrometheusGaugeFunc("test_hosts", "Hosts.", func() ([]PrometheusSample, error) {
+		return []PrometheusSample{
+			{LabelValues: []string{"running"}, Value: 2},
+			{LabelValues: []string{"decommissioned"}, Value: 1},
+		}, nil
+	}, "status")
+	NewPrometheusGaugeFunc("test_broken", "Broken.", func() ([]PrometheusSample, error) {
+		return nil, errors.New("can't collect")
+	})
+
+	registry := NewPrometheusRegistry()
+	for _, name := range []string{"test_sent_total", "test_duration_seconds", "test_hosts", "test_broken"} {
+		PrometheusMetrics.mu.RLock()
+		c := PrometheusMetrics.collectors[name]
+		PrometheusMetrics.mu.RUnlock()
+		require.NotNil(c, name)
+		registry.Register(c)
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(registry.Write(buf))
+	assert.Equal(strings.Join([]string{
+		`# HELP test_duration_seconds Durations.`,
+		`# TYPE test_duration_seconds histogram`,
+		`test_duration_seconds_bucket{route="/a",le="0.1"} 1`,
+		`test_duration_seconds_bucket{route="/a",le="1"} 2`,
+		`test_duration_seconds_bucket{route="/a",le="+Inf"} 3`,
+		`test_duration_seconds_sum{route="/a"} 5.55`,
+		`test_duration_seconds_count{route="/a"} 3`,
+		`# HELP test_hosts Hosts.`,
+		`# TYPE test_hosts gauge`,
+		`test_hosts{status="decommissioned"} 1`,
+		`test_hosts{status="running"} 2`,
+		`# HELP test_sent_total Sent things.\nPer outcome.`,
+		`# TYPE test_sent_total counter`,
+		`test_sent_total{type="email",outcome="sent"} 2`,
+		`test_sent_total{type="slack",outcome="fail\"ed"} 3`,
+		``,
+	}, "\n"), buf.String())
+
+	rw := httptest.NewRecorder()
+	registry.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(http.StatusOK, rw.Code)
+	assert.Equal(PrometheusContentType, rw.Header().Get("Content-Type"))
+	assert.Equal(buf.String(), rw.Body.String())
+}
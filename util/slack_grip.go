@@ -0,0 +1,519 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+// Slack Block Kit block types.
+const (
+	SlackBlockSection = "section"
+	SlackBlockContext = "context"
+	SlackBlockActions = "actions"
+	SlackBlockDivider = "divider"
+)
+
+// Slack Block Kit text and element types.
+const (
+	SlackTextPlain     = "plain_text"
+	SlackTextMarkdown  = "mrkdwn"
+	SlackElementImage  = "image"
+	SlackElementButton = "button"
+)
+
+// Slack Block Kit button styles.
+const (
+	SlackButtonPrimary = "primary"
+	SlackButtonDanger  = "danger"
+)
+
+const (
+	slackAPIURL             = "https://slack.com/api/"
+	slackTimeout            = 10 * time.Second
+	slackMaxBlocks          = 50
+	slackMaxBlockIDLength   = 255
+	slackMaxSectionText     = 3000
+	slackMaxSectionFields   = 10
+	slackMaxFieldText       = 2000
+	slackMaxContextElements = 10
+	slackMaxActionElements  = 25
+	slackMaxButtonText      = 75
+
+	slackSignatureVersion = "v0"
+	slackSignatureMaxAge  = 5 * time.Minute
+)
+
+// SlackText is a text object in a Slack block, formatted either as plain
+// text or as Slack markdown.
+type SlackText struct {
+	Type string `bson:"type" json:"type" yaml:"type"`
+	Text string `bson:"text" json:"text" yaml:"text"`
+}
+
+func (t *SlackText) validate(field string, maxLength int, catcher grip.Catcher) {
+	if t.Type != SlackTextPlain && t.Type != SlackTextMarkdown {
+		catcher.Add(errors.Errorf("%s: '%s' is not a valid text type", field, t.Type))
+	}
+	if t.Text == "" {
+		catcher.Add(errors.Errorf("%s: text cannot be empty", field))
+	} else if len(t.Text) > maxLength {
+		catcher.Add(errors.Errorf("%s: text cannot be longer than %d characters", field, maxLength))
+	}
+}
+
+// SlackBlockElement is an element of a context or actions block. Context
+// blocks contain text and image elements, and actions blocks contain
+// buttons, which either open the URL or send the action ID and value to
+// Slack's interactivity endpoint when clicked.
+type SlackBlockElement struct {
+	Type     string `bson:"type" json:"type" yaml:"type"`
+	Text     string `bson:"text,omitempty" json:"text,omitempty" yaml:"text,omitempty"`
+	ImageURL string `bson:"image_url,omitempty" json:"image_url,omitempty" yaml:"image_url,omitempty"`
+	AltText  string `bson:"alt_text,omitempty" json:"alt_text,omitempty" yaml:"alt_text,omitempty"`
+	ActionID string `bson:"action_id,omitempty" json:"action_id,omitempty" yaml:"action_id,omitempty"`
+	URL      string `bson:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Value    string `bson:"value,omitempty" json:"value,omitempty" yaml:"value,omitempty"`
+	Style    string `bson:"style,omitempty" json:"style,omitempty" yaml:"style,omitempty"`
+}
+
+// MarshalJSON encodes the element as Slack expects it, where the text of a
+// button is a plain text object rather than a string.
+func (e SlackBlockElement) MarshalJSON() ([]byte, error) {
+	type element SlackBlockElement
+	if e.Type != SlackElementButton {
+		return json.Marshal(element(e))
+	}
+
+	return json.Marshal(struct {
+		element
+		Text SlackText `json:"text"`
+	}{
+		element: element(e),
+		Text:    SlackText{Type: SlackTextPlain, Text: e.Text},
+	})
+}
+
+func (e *SlackBlockElement) validate(field, blockType string, catcher grip.Catcher) {
+	switch {
+	case blockType == SlackBlockContext && (e.Type == SlackTextPlain || e.Type == SlackTextMarkdown):
+		if e.Text == "" {
+			catcher.Add(errors.Errorf("%s: text cannot be empty", field))
+		}
+	case blockType == SlackBlockContext && e.Type == SlackElementImage:
+		if e.ImageURL == "" || e.AltText == "" {
+			catcher.Add(errors.Errorf("%s: image_url and alt_text cannot be empty", field))
+		}
+	case blockType == SlackBlockActions && e.Type == SlackElementButton:
+		if e.Text == "" {
+			catcher.Add(errors.Errorf("%s: text cannot be empty", field))
+		} else if len(e.Text) > slackMaxButtonText {
+			catcher.Add(errors.Errorf("%s: text cannot be longer than %d characters", field, slackMaxButtonText))
+		}
+		if e.ActionID == "" && e.URL == "" {
+			catcher.Add(errors.Errorf("%s: one of action_id and url must be set", field))
+		}
+		if e.Style != "" && e.Style != SlackButtonPrimary && e.Style != SlackButtonDanger {
+			catcher.Add(errors.Errorf("%s: '%s' is not a valid button style", field, e.Style))
+		}
+	default:
+		catcher.Add(errors.Errorf("%s: '%s' elements are not allowed in %s blocks", field, e.Type, blockType))
+	}
+}
+
+// SlackBlock is a Slack Block Kit layout block. Sections have text, fields
+// or both, context blocks have text and image elements, actions blocks have
+// buttons, and dividers have no content.
+type SlackBlock struct {
+	Type     string              `bson:"type" json:"type" yaml:"type"`
+	BlockID  string              `bson:"block_id,omitempty" json:"block_id,omitempty" yaml:"block_id,omitempty"`
+	Text     *SlackText          `bson:"text,omitempty" json:"text,omitempty" yaml:"text,omitempty"`
+	Fields   []SlackText         `bson:"fields,omitempty" json:"fields,omitempty" yaml:"fields,omitempty"`
+	Elements []SlackBlockElement `bson:"elements,omitempty" json:"elements,omitempty" yaml:"elements,omitempty"`
+}
+
+func (b *SlackBlock) validate(field string, catcher grip.Catcher) {
+	if len(b.BlockID) > slackMaxBlockIDLength {
+		catcher.Add(errors.Errorf("%s: block_id cannot be longer than %d characters", field, slackMaxBlockIDLength))
+	}
+
+	switch b.Type {
+	case SlackBlockSection:
+		if b.Text == nil && len(b.Fields) == 0 {
+			catcher.Add(errors.Errorf("%s: sections must have text or fields", field))
+		}
+		if b.Text != nil {
+			b.Text.validate(field+".text", slackMaxSectionText, catcher)
+		}
+		if len(b.Fields) > slackMaxSectionFields {
+			catcher.Add(errors.Errorf("%s: sections cannot have more than %d fields", field, slackMaxSectionFields))
+		}
+		for i := range b.Fields {
+			b.Fields[i].validate(fmt.Sprintf("%s.fields[%d]", field, i), slackMaxFieldText, catcher)
+		}
+		if len(b.Elements) != 0 {
+			catcher.Add(errors.Errorf("%s: sections cannot have elements", field))
+		}
+	case SlackBlockContext, SlackBlockActions:
+		maxElements := slackMaxContextElements
+		if b.Type == SlackBlockActions {
+			maxElements = slackMaxActionElements
+		}
+		if len(b.Elements) == 0 || len(b.Elements) > maxElements {
+			catcher.Add(errors.Errorf("%s: %s blocks must have between 1 and %d elements", field, b.Type, maxElements))
+		}
+		for i := range b.Elements {
+			b.Elements[i].validate(fmt.Sprintf("%s.elements[%d]", field, i), b.Type, catcher)
+		}
+		if b.Text != nil || len(b.Fields) != 0 {
+			catcher.Add(errors.Errorf("%s: %s blocks cannot have text or fields", field, b.Type))
+		}
+	case SlackBlockDivider:
+		if b.Text != nil || len(b.Fields) != 0 || len(b.Elements) != 0 {
+			catcher.Add(errors.Errorf("%s: dividers cannot have content", field))
+		}
+	default:
+		catcher.Add(errors.Errorf("%s: '%s' is not a supported block type", field, b.Type))
+	}
+}
+
+// ValidateSlackBlocks returns an error describing each invalid block, naming
+// it by its index in the blocks field.
+func ValidateSlackBlocks(blocks []SlackBlock) error {
+	catcher := grip.NewBasicCatcher()
+	if len(blocks) > slackMaxBlocks {
+		catcher.Add(errors.Errorf("blocks: messages cannot have more than %d blocks", slackMaxBlocks))
+	}
+	for i := range blocks {
+		blocks[i].validate(fmt.Sprintf("blocks[%d]", i), catcher)
+	}
+
+	return catcher.Resolve()
+}
+
+// SlackBlocksText returns the text of the blocks, which Slack shows in
+// notifications and clients that cannot render blocks.
+func SlackBlocksText(blocks []SlackBlock) string {
+	lines := []string{}
+	for _, b := range blocks {
+		if b.Text != nil {
+			lines = append(lines, b.Text.Text)
+		}
+		for _, f := range b.Fields {
+			lines = append(lines, f.Text)
+		}
+		if b.Type == SlackBlockContext {
+			for _, e := range b.Elements {
+				if e.Text != "" {
+					lines = append(lines, e.Text)
+				}
+			}
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// EvergreenSlack is a Slack message, which may have Block Kit blocks in
+// addition to the text and legacy attachments of a grip Slack message. If
+// the message has blocks, its text is used only as the notification text.
+type EvergreenSlack struct {
+	Target      string                    `bson:"target" json:"target" yaml:"target"`
+	Msg         string                    `bson:"msg" json:"msg" yaml:"msg"`
+	Attachments []message.SlackAttachment `bson:"attachments,omitempty" json:"attachments,omitempty" yaml:"attachments,omitempty"`
+	Blocks      []SlackBlock              `bson:"blocks,omitempty" json:"blocks,omitempty" yaml:"blocks,omitempty"`
+	// ThreadTS is the timestamp of a message to reply to in its thread.
+	// UpdateTS is the timestamp of a message in the target channel to
+	// replace with this one, in which case the target must be a channel ID.
+	ThreadTS string `bson:"thread_ts,omitempty" json:"thread_ts,omitempty" yaml:"thread_ts,omitempty"`
+	UpdateTS string `bson:"update_ts,omitempty" json:"update_ts,omitempty" yaml:"update_ts,omitempty"`
+}
+
+// SlackMessageRef identifies a posted Slack message by the ID of its
+// channel and its timestamp, which are needed to reply to or update it.
+type SlackMessageRef struct {
+	Channel string `bson:"channel" json:"channel" yaml:"channel"`
+	TS      string `bson:"ts" json:"ts" yaml:"ts"`
+}
+
+// SlackMessageRecorder is implemented by composers that record the message
+// Slack created for them when sent by the Slack sender.
+type SlackMessageRecorder interface {
+	PostedMessage() *SlackMessageRef
+}
+
+type evergreenSlackMessage struct {
+	raw    EvergreenSlack
+	posted *SlackMessageRef
+
+	message.Base
+}
+
+// NewSlackMessage returns a composer for a Slack message with blocks.
+func NewSlackMessage(l level.Priority, target, msg string, attachments []message.SlackAttachment, blocks []SlackBlock) message.Composer {
+	return NewSlackMessageWithStruct(l, EvergreenSlack{
+		Target:      target,
+		Msg:         msg,
+		Attachments: attachments,
+		Blocks:      blocks,
+	})
+}
+
+// NewSlackMessageWithStruct returns a composer for the Slack message.
+func NewSlackMessageWithStruct(l level.Priority, raw EvergreenSlack) message.Composer {
+	m := &evergreenSlackMessage{
+		raw: raw,
+	}
+	_ = m.SetPriority(l)
+
+	return m
+}
+
+func (m *evergreenSlackMessage) Loggable() bool {
+	if m.raw.Target == "" {
+		return false
+	}
+	if m.raw.Msg == "" && len(m.raw.Blocks) == 0 {
+		return false
+	}
+	if m.raw.ThreadTS != "" && m.raw.UpdateTS != "" {
+		return false
+	}
+
+	return ValidateSlackBlocks(m.raw.Blocks) == nil
+}
+
+// PostedMessage returns the message Slack created or updated, or nil if the
+// message has not been sent.
+func (m *evergreenSlackMessage) PostedMessage() *SlackMessageRef {
+	return m.posted
+}
+
+func (m *evergreenSlackMessage) Raw() interface{} {
+	return &m.raw
+}
+
+func (m *evergreenSlackMessage) String() string {
+	if m.raw.Msg != "" {
+		return m.raw.Msg
+	}
+
+	return SlackBlocksText(m.raw.Blocks)
+}
+
+// SlackSettings are the settings used to post Slack messages with blocks.
+type SlackSettings struct {
+	Token    string
+	Username string
+	IconURL  string
+}
+
+type evergreenSlackSender struct {
+	settings SlackSettings
+	apiURL   string
+	client   *http.Client
+	handler  send.ErrorHandler
+	send.Sender
+}
+
+// NewSlackSender wraps the Slack sender so that it posts Slack messages
+// composed by NewSlackMessage itself, since the Slack sender cannot send
+// blocks, reply in threads, update messages or report the message it
+// posted. All other messages are sent by the Slack sender.
+func NewSlackSender(sender send.Sender, settings SlackSettings) send.Sender {
+	return &evergreenSlackSender{
+		settings: settings,
+		apiURL:   slackAPIURL,
+		Sender:   sender,
+	}
+}
+
+func (s *evergreenSlackSender) SetErrorHandler(h send.ErrorHandler) error {
+	if err := s.Sender.SetErrorHandler(h); err != nil {
+		return err
+	}
+	s.handler = h
+
+	return nil
+}
+
+func (s *evergreenSlackSender) Send(m message.Composer) {
+	msg, ok := m.Raw().(*EvergreenSlack)
+	if !ok {
+		s.Sender.Send(m)
+		return
+	}
+	if !s.Level().ShouldLog(m) {
+		return
+	}
+
+	posted, err := s.postMessage(msg)
+	if err != nil {
+		if s.handler != nil {
+			s.handler(err, m)
+		}
+		return
+	}
+	if composer, ok := m.(*evergreenSlackMessage); ok {
+		composer.posted = posted
+	}
+}
+
+// slackPostMessageResponse is the body of a chat.postMessage or chat.update
+// response. Slack reports most failures with a 200 status and an error code.
+type slackPostMessageResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// postMessage posts the message, or updates the message it replaces, and
+// returns the message Slack created or updated.
+func (s *evergreenSlackSender) postMessage(msg *EvergreenSlack) (*SlackMessageRef, error) {
+	text := msg.Msg
+	if text == "" {
+		text = SlackBlocksText(msg.Blocks)
+	}
+	method := "chat.postMessage"
+	if msg.UpdateTS != "" {
+		method = "chat.update"
+	}
+	body, err := json.Marshal(struct {
+		Channel     string                    `json:"channel"`
+		Text        string                    `json:"text"`
+		Blocks      []SlackBlock              `json:"blocks,omitempty"`
+		Attachments []message.SlackAttachment `json:"attachments,omitempty"`
+		ThreadTS    string                    `json:"thread_ts,omitempty"`
+		TS          string                    `json:"ts,omitempty"`
+		Username    string                    `json:"username,omitempty"`
+		IconURL     string                    `json:"icon_url,omitempty"`
+	}{
+		Channel:     msg.Target,
+		Text:        text,
+		Blocks:      msg.Blocks,
+		Attachments: msg.Attachments,
+		ThreadTS:    msg.ThreadTS,
+		TS:          msg.UpdateTS,
+		Username:    s.settings.Username,
+		IconURL:     s.settings.IconURL,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode slack message")
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.apiURL+method, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create slack request")
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+s.settings.Token)
+
+	ctx, cancel := context.WithTimeout(req.Context(), slackTimeout)
+	defer cancel()
+	req = req.WithContext(ctx)
+
+	client := s.client
+	if client == nil {
+		client = GetHTTPClient()
+		defer PutHTTPClient(client)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to call slack %s", method)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("slack response status was %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	out := slackPostMessageResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, errors.Wrap(err, "failed to decode slack response")
+	}
+	if !out.OK {
+		return nil, errors.Errorf("slack rejected message to '%s': %s", msg.Target, out.Error)
+	}
+
+	return &SlackMessageRef{Channel: out.Channel, TS: out.TS}, nil
+}
+
+// VerifySlackSignature checks that a request was sent by slack, given the
+// signing secret of the slack app, the X-Slack-Request-Timestamp and
+// X-Slack-Signature headers of the request, and its body. Requests older
+// than five minutes are rejected, so that they cannot be replayed.
+func VerifySlackSignature(secret []byte, timestamp, signature string, body []byte, now time.Time) error {
+	if len(secret) == 0 {
+		return errors.New("no slack signing secret is configured")
+	}
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Errorf("'%s' is not a valid request timestamp", timestamp)
+	}
+	if age := now.Sub(time.Unix(sentAt, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return errors.Errorf("request timestamp is %s from the current time", age)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(slackSignatureVersion + ":" + timestamp + ":"))
+	_, _ = mac.Write(body)
+	expected := slackSignatureVersion + "=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("request signature does not match")
+	}
+
+	return nil
+}
+
+// RespondToSlackInteraction posts a reply, visible only to the user who
+// clicked it, to the response URL of a Slack interaction.
+func RespondToSlackInteraction(responseURL, text string) error {
+	body, err := json.Marshal(struct {
+		ResponseType    string `json:"response_type"`
+		ReplaceOriginal bool   `json:"replace_original"`
+		Text            string `json:"text"`
+	}{
+		ResponseType: "ephemeral",
+		Text:         text,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode slack response")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create slack response request")
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req = req.WithContext(ctx)
+
+	client := GetHTTPClient()
+	defer PutHTTPClient(client)
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post slack response")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("slack response status was %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSlackBlocks() []SlackBlock {
+	return []SlackBlock{
+		{
+			Type: SlackBlockSection,
+			Text: &SlackText{Type: SlackTextMarkdown, Text: "*Task failed*"},
+			Fields: []SlackText{
+				{Type: SlackTextMarkdown, Text: "*Variant*\nubuntu"},
+			},
+		},
+		{
+			Type: SlackBlockContext,
+			Elements: []SlackBlockElement{
+				{Type: SlackElementImage, ImageURL: "https://example.com/icon.png", AltText: "evergreen"},
+				{Type: SlackTextPlain, Text: "finished in 3m"},
+			},
+		},
+		{Type: SlackBlockDivider},
+		{
+			Type: SlackBlockActions,
+			Elements: []SlackBlockElement{
+				{Type: SlackElementButton, Text: "View logs", URL: "https://example.com/logs"},
+				{Type: SlackElementButton, Text: "Restart", ActionID: "restart", Value: "task1", Style: SlackButtonDanger},
+			},
+		},
+	}
+}
+
+func TestValidateSlackBlocks(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateSlackBlocks(nil))
+	assert.NoError(ValidateSlackBlocks(testSlackBlocks()))
+
+	for name, test := range map[string]struct {
+		block SlackBlock
+		err   string
+	}{
+		"UnknownType": {
+			block: SlackBlock{Type: "header"},
+			err:   "blocks[0]: 'header' is not a supported block type",
+		},
+		"EmptySection": {
+			block: SlackBlock{Type: SlackBlockSection},
+			err:   "blocks[0]: sections must have text or fields",
+		},
+		"InvalidSectionText": {
+			block: SlackBlock{Type: SlackBlockSection, Text: &SlackText{Type: "html", Text: "hi"}},
+			err:   "blocks[0].text: 'html' is not a valid text type",
+		},
+		"LongField": {
+			block: SlackBlock{Type: SlackBlockSection, Fields: []SlackText{{Type: SlackTextPlain, Text: strings.Repeat("a", slackMaxFieldText+1)}}},
+			err:   "blocks[0].fields[0]: text cannot be longer than 2000 characters",
+		},
+		"EmptyContext": {
+			block: SlackBlock{Type: SlackBlockContext},
+			err:   "blocks[0]: context blocks must have between 1 and 10 elements",
+		},
+		"ButtonInContext": {
+			block: SlackBlock{Type: SlackBlockContext, Elements: []SlackBlockElement{{Type: SlackElementButton, Text: "hi", URL: "https://example.com"}}},
+			err:   "blocks[0].elements[0]: 'button' elements are not allowed in context blocks",
+		},
+		"ImageWithoutAltText": {
+			block: SlackBlock{Type: SlackBlockContext, Elements: []SlackBlockElement{{Type: SlackElementImage, ImageURL: "https://example.com/icon.png"}}},
+			err:   "blocks[0].elements[0]: image_url and alt_text cannot be empty",
+		},
+		"ButtonWithoutAction": {
+			block: SlackBlock{Type: SlackBlockActions, Elements: []SlackBlockElement{{Type: SlackElementButton, Text: "hi"}}},
+			err:   "blocks[0].elements[0]: one of action_id and url must be set",
+		},
+		"InvalidButtonStyle": {
+			block: SlackBlock{Type: SlackBlockActions, Elements: []SlackBlockElement{{Type: SlackElementButton, Text: "hi", ActionID: "a", Style: "blue"}}},
+			err:   "blocks[0].elements[0]: 'blue' is not a valid button style",
+		},
+		"DividerWithText": {
+			block: SlackBlock{Type: SlackBlockDivider, Text: &SlackText{Type: SlackTextPlain, Text: "hi"}},
+			err:   "blocks[0]: dividers cannot have content",
+		},
+	} {
+		assert.EqualError(ValidateSlackBlocks([]SlackBlock{test.block}), test.err, name)
+	}
+
+	tooMany := make([]SlackBlock, slackMaxBlocks+1)
+	for i := range tooMany {
+		tooMany[i].Type = SlackBlockDivider
+	}
+	assert.EqualError(ValidateSlackBlocks(tooMany), "blocks: messages cannot have more than 50 blocks")
+}
+
+func TestSlackBlockJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	out, err := json.Marshal(testSlackBlocks()[2:])
+	assert.NoError(err)
+	assert.JSONEq(`[
+		{"type": "divider"},
+		{"type": "actions", "elements": [
+			{"type": "button", "text": {"type": "plain_text", "text": "View logs"}, "url": "https://example.com/logs"},
+			{"type": "button", "text": {"type": "plain_text", "text": "Restart"}, "action_id": "restart", "value": "task1", "style": "danger"}
+		]}
+	]`, string(out))
+
+	out, err = json.Marshal(testSlackBlocks()[1])
+	assert.NoError(err)
+	assert.JSONEq(`{"type": "context", "elements": [
+		{"type": "image", "image_url": "https://example.com/icon.png", "alt_text": "evergreen"},
+		{"type": "plain_text", "text": "finished in 3m"}
+	]}`, string(out))
+}
+
+func TestEvergreenSlackMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.False(NewSlackMessage(level.Notice, "", "hi", nil, nil).Loggable())
+	assert.False(NewSlackMessage(level.Notice, "#general", "", nil, nil).Loggable())
+	assert.False(NewSlackMessage(level.Notice, "#general", "hi", nil, []SlackBlock{{Type: "header"}}).Loggable())
+
+	m := NewSlackMessage(level.Notice, "#general", "", nil, testSlackBlocks())
+	assert.True(m.Loggable())
+	assert.Equal(level.Notice, m.Priority())
+	assert.Equal("*Task failed*\n*Variant*\nubuntu\nfinished in 3m", m.String())
+	raw, ok := m.Raw().(*EvergreenSlack)
+	assert.True(ok)
+	assert.Equal("#general", raw.Target)
+
+	assert.Equal("hi", NewSlackMessage(level.Notice, "#general", "hi", nil, testSlackBlocks()).String())
+	assert.False(NewSlackMessageWithStruct(level.Notice, EvergreenSlack{Target: "C123", Msg: "hi", ThreadTS: "1.1", UpdateTS: "1.2"}).Loggable())
+}
+
+func TestEvergreenSlackSender(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var posted map[string]interface{}
+	var auth, method string
+	response := `{"ok": true, "channel": "C123", "ts": "1500000000.000100"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		method = r.URL.Path
+		posted = nil
+		assert.NoError(json.NewDecoder(r.Body).Decode(&posted))
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	base, err := send.NewInternalLogger("slack", send.LevelInfo{Default: level.Info, Threshold: level.Info})
+	require.NoError(err)
+	sender := NewSlackSender(base, SlackSettings{Token: "token", Username: "Evergreen"})
+	sender.(*evergreenSlackSender).apiURL = server.URL + "/"
+	var sendErr error
+	require.NoError(sender.SetErrorHandler(func(err error, _ message.Composer) { sendErr = err }))
+
+	// messages with blocks are posted by the wrapper, which records the
+	// message that slack created
+	m := NewSlackMessage(level.Notice, "#general", "", nil, testSlackBlocks())
+	sender.Send(m)
+	assert.NoError(sendErr)
+	assert.Equal("/chat.postMessage", method)
+	assert.Equal("Bearer token", auth)
+	assert.Equal("#general", posted["channel"])
+	assert.Equal("Evergreen", posted["username"])
+	assert.Equal("*Task failed*\n*Variant*\nubuntu\nfinished in 3m", posted["text"])
+	assert.Len(posted["blocks"], 4)
+	assert.NotContains(posted, "attachments")
+	assert.NotContains(posted, "thread_ts")
+	assert.Equal(&SlackMessageRef{Channel: "C123", TS: "1500000000.000100"}, m.(SlackMessageRecorder).PostedMessage())
+
+	// replies are posted in the thread
+	m = NewSlackMessageWithStruct(level.Notice, EvergreenSlack{Target: "#general", Msg: "fixed", ThreadTS: "1500000000.000100"})
+	sender.Send(m)
+	assert.NoError(sendErr)
+	assert.Equal("/chat.postMessage", method)
+	assert.Equal("1500000000.000100", posted["thread_ts"])
+	assert.Equal("fixed", posted["text"])
+	assert.NotContains(posted, "blocks")
+
+	// updates replace the message
+	sender.Send(NewSlackMessageWithStruct(level.Notice, EvergreenSlack{Target: "C123", Msg: "failed", UpdateTS: "1500000000.000100"}))
+	assert.NoError(sendErr)
+	assert.Equal("/chat.update", method)
+	assert.Equal("C123", posted["channel"])
+	assert.Equal("1500000000.000100", posted["ts"])
+
+	// other slack messages are sent by the wrapped sender
+	posted = nil
+	sender.Send(message.NewSlackMessage(level.Notice, "#general", "hi", nil))
+	assert.Nil(posted)
+	require.True(base.HasMessage())
+	msg := base.GetMessage()
+	assert.IsType(&message.Slack{}, msg.Message.Raw())
+
+	// messages below the threshold are not sent
+	sender.Send(NewSlackMessage(level.Debug, "#general", "", nil, testSlackBlocks()))
+	assert.Nil(posted)
+
+	response = `{"ok": false, "error": "channel_not_found"}`
+	m = NewSlackMessage(level.Notice, "#missing", "", nil, testSlackBlocks())
+	sender.Send(m)
+	assert.EqualError(sendErr, "slack rejected message to '#missing': channel_not_found")
+	assert.Nil(m.(SlackMessageRecorder).PostedMessage())
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	// the example from slack's documentation
+	secret := []byte("8f742231b10e8888abcd99yyyzzz85a5")
+	body := []byte("token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c")
+	signature := "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503"
+	sentAt := time.Unix(1531420618, 0)
+
+	assert.NoError(VerifySlackSignature(secret, "1531420618", signature, body, sentAt.Add(time.Minute)))
+	assert.EqualError(VerifySlackSignature(secret, "1531420618", signature, body, sentAt.Add(time.Hour)),
+		"request timestamp is 1h0m0s from the current time")
+	assert.EqualError(VerifySlackSignature(secret, "yesterday", signature, body, sentAt),
+		"'yesterday' is not a valid request timestamp")
+	assert.EqualError(VerifySlackSignature(secret, "1531420618", signature, append(body, '&'), sentAt),
+		"request signature does not match")
+	assert.EqualError(VerifySlackSignature([]byte("other"), "1531420618", signature, body, sentAt),
+		"request signature does not match")
+	assert.EqualError(VerifySlackSignature(nil, "1531420618", signature, body, sentAt),
+		"no slack signing secret is configured")
+}
+
+func TestRespondToSlackInteraction(t *testing.T) {
+	assert := assert.New(t)
+
+	var posted map[string]interface{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(json.NewDecoder(r.Body).Decode(&posted))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	assert.NoError(RespondToSlackInteraction(server.URL, "Restarted task 'task1'."))
+	assert.Equal(map[string]interface{}{
+		"response_type":    "ephemeral",
+		"replace_original": false,
+		"text":             "Restarted task 'task1'.",
+	}, posted)
+
+	status = http.StatusNotFound
+	assert.EqualError(RespondToSlackInteraction(server.URL, "hi"), "slack response status was 404 Not Found")
+}
@@ -3,8 +3,10 @@ package util
 import (
 	"bytes"
 	"context"
+	"net"
 	"net/http"
 	"net/url"
+	"syscall"
 	"time"
 
 	"github.com/mongodb/grip"
@@ -15,20 +17,50 @@ import (
 
 const (
 	evergreenWebhookTimeout       = 10 * time.Second
+	evergreenWebhookRetries       = 3
+	evergreenWebhookRetryDelay    = time.Second
 	evergreenNotificationIDHeader = "X-Evergreen-Notification-ID"
 	evergreenHMACHeader           = "X-Evergreen-Signature"
 )
 
 type EvergreenWebhook struct {
-	NotificationID string      `bson:"notification_id"`
-	URL            string      `bson:"url"`
-	Secret         []byte      `bson:"secret"`
-	Body           []byte      `bson:"body"`
-	Headers        http.Header `bson:"headers"`
+	NotificationID string      `bson:"notification_id" json:"notification_id"`
+	URL            string      `bson:"url" json:"url"`
+	Secret         []byte      `bson:"secret" json:"-"`
+	Body           []byte      `bson:"body" json:"body"`
+	Headers        http.Header `bson:"headers" json:"headers"`
+}
+
+// WebhookDeliveryStatus records the outcome of delivering a webhook.
+type WebhookDeliveryStatus struct {
+	// Attempts is the number of requests made to deliver the webhook.
+	Attempts int `bson:"attempts" json:"attempts"`
+	// StatusCode is the status code of the last response, if any.
+	StatusCode int `bson:"status_code,omitempty" json:"status_code,omitempty"`
+}
+
+// Succeeded returns true if the receiver accepted the webhook.
+func (s *WebhookDeliveryStatus) Succeeded() bool {
+	return s.StatusCode >= http.StatusOK && s.StatusCode < http.StatusMultipleChoices
+}
+
+// Retryable returns true if delivering the webhook failed in a way that may
+// succeed later: the request could not be made, or the receiver had a
+// transient failure. Webhooks that the receiver rejected are not retryable.
+func (s *WebhookDeliveryStatus) Retryable() bool {
+	return s.StatusCode == 0 || s.StatusCode >= http.StatusInternalServerError ||
+		s.StatusCode == http.StatusTooManyRequests
+}
+
+// WebhookDeliveryRecorder is implemented by composers that record the
+// outcome of their delivery when sent by the evergreen-webhook sender.
+type WebhookDeliveryRecorder interface {
+	DeliveryStatus() *WebhookDeliveryStatus
 }
 
 type evergreenWebhookMessage struct {
-	raw EvergreenWebhook
+	raw      EvergreenWebhook
+	delivery *WebhookDeliveryStatus
 
 	message.Base
 }
@@ -87,14 +119,63 @@ func (w *evergreenWebhookMessage) String() string {
 	return string(w.raw.Body)
 }
 
+// DeliveryStatus returns the outcome of delivering the webhook, or nil if it
+// has not been sent.
+func (w *evergreenWebhookMessage) DeliveryStatus() *WebhookDeliveryStatus {
+	return w.delivery
+}
+
+// IsPublicIP returns true if the address is reachable on the internet, as
+// opposed to a loopback, private, link-local, or otherwise reserved address.
+func IsPublicIP(ip net.IP) bool {
+	return ip != nil && ip.IsGlobalUnicast() && !ip.IsPrivate() && !ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsUnspecified()
+}
+
+// checkPublicAddress refuses connections to addresses that aren't public,
+// so that webhooks can't be used to reach the app server's internal network.
+// It runs on the resolved address of each connection, so host names that
+// resolve to internal addresses, including through redirects, are refused.
+func checkPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid address '%s'", address)
+	}
+	if !IsPublicIP(net.ParseIP(host)) {
+		return errors.Errorf("evergreen-webhook refused to connect to non-public address '%s'", host)
+	}
+	return nil
+}
+
+// newWebhookClient returns a client that only connects to public addresses.
+// It doesn't use a proxy, since the proxy's address would be checked rather
+// than the receiver's.
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: evergreenWebhookTimeout,
+		Control: checkPublicAddress,
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: evergreenWebhookTimeout,
+		},
+	}
+}
+
 type evergreenWebhookLogger struct {
-	client *http.Client
+	client     *http.Client
+	retries    int
+	retryDelay time.Duration
 	*send.Base
 }
 
 func NewEvergreenWebhookLogger() (send.Sender, error) {
 	s := &evergreenWebhookLogger{
-		Base: send.NewBase("evergreen"),
+		client:     newWebhookClient(),
+		retries:    evergreenWebhookRetries,
+		retryDelay: evergreenWebhookRetryDelay,
+		Base:       send.NewBase("evergreen"),
 	}
 
 	return s, nil
@@ -114,15 +195,35 @@ func (w *evergreenWebhookLogger) send(m message.Composer) error {
 		return errors.New("evergreen-webhook sender received unexpected composer")
 	}
 
-	reader := bytes.NewReader(raw.Body)
-	req, err := http.NewRequest(http.MethodPost, raw.URL, reader)
+	hash, err := CalculateHMACHash(raw.Secret, raw.Body)
 	if err != nil {
-		return errors.Wrap(err, "evergreen-webhook failed to create http request")
+		return errors.Wrap(err, "evergreen-webhook failed to calculate hash")
 	}
 
-	hash, err := CalculateHMACHash(raw.Secret, raw.Body)
+	delivery := &WebhookDeliveryStatus{}
+	if msg, ok := m.(*evergreenWebhookMessage); ok {
+		msg.delivery = delivery
+	}
+
+	_, err = Retry(func() (bool, error) {
+		delivery.Attempts++
+		statusCode, err := w.post(w.client, raw, hash)
+		delivery.StatusCode = statusCode
+		if err == nil {
+			return false, nil
+		}
+		return delivery.Retryable(), err
+	}, w.retries, w.retryDelay)
+
+	return err
+}
+
+// post makes a single request to deliver the webhook, returning the status
+// code of the response, if any.
+func (w *evergreenWebhookLogger) post(client *http.Client, raw *EvergreenWebhook, hash string) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, raw.URL, bytes.NewReader(raw.Body))
 	if err != nil {
-		return errors.Wrap(err, "evergreen-webhook failed to calculate hash")
+		return 0, errors.Wrap(err, "evergreen-webhook failed to create http request")
 	}
 
 	for k := range raw.Headers {
@@ -141,22 +242,16 @@ func (w *evergreenWebhookLogger) send(m message.Composer) error {
 
 	req = req.WithContext(ctx)
 
-	var client *http.Client = w.client
-	if client == nil {
-		client = GetHTTPClient()
-		defer PutHTTPClient(client)
-	}
-
 	resp, err := client.Do(req)
 	if resp != nil {
 		defer resp.Body.Close()
 	}
 	if err != nil {
-		return errors.Wrap(err, "evergreen-webhook failed to send webhook data")
+		return 0, errors.Wrap(err, "evergreen-webhook failed to send webhook data")
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return errors.Errorf("evergreen-webhook response status was %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		return resp.StatusCode, errors.Errorf("evergreen-webhook response status was %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
 
-	return nil
+	return resp.StatusCode, nil
 }
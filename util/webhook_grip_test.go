@@ -5,7 +5,9 @@ import (
 	"crypto/hmac"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/mongodb/grip"
@@ -120,10 +122,64 @@ func TestEvergreenWebhookSenderWithBadSecret(t *testing.T) {
 	assert.Equal("https://example.com", transport.lastUrl)
 }
 
+func TestEvergreenWebhookSenderRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	transport := mockWebhookTransport{
+		secret:   []byte("hi"),
+		failures: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+	}
+
+	sender, err := NewEvergreenWebhookLogger()
+	assert.NoError(err)
+	s, ok := sender.(*evergreenWebhookLogger)
+	assert.True(ok)
+	s.client = &http.Client{
+		Transport: &transport,
+	}
+	s.retryDelay = 0
+	assert.NoError(s.SetErrorHandler(func(err error, _ message.Composer) {
+		t.Errorf("error handler was called, but shouldn't have been: %s", err)
+	}))
+
+	m := NewWebhookMessage("evergreen", "https://example.com", []byte("hi"), []byte("something important"), nil)
+	s.Send(m)
+	recorder, ok := m.(WebhookDeliveryRecorder)
+	assert.True(ok)
+	status := recorder.DeliveryStatus()
+	assert.NotNil(status)
+	assert.Equal(3, status.Attempts)
+	assert.Equal(http.StatusNoContent, status.StatusCode)
+
+	// a rejected webhook is not retried
+	transport.failures = []int{http.StatusForbidden}
+	channel := make(chan error, 1)
+	assert.NoError(s.SetErrorHandler(func(err error, _ message.Composer) {
+		channel <- err
+	}))
+	m = NewWebhookMessage("evergreen", "https://example.com", []byte("hi"), []byte("something important"), nil)
+	s.Send(m)
+	assert.EqualError(<-channel, "evergreen-webhook response status was 403 Forbidden")
+	status = m.(WebhookDeliveryRecorder).DeliveryStatus()
+	assert.Equal(1, status.Attempts)
+	assert.Equal(http.StatusForbidden, status.StatusCode)
+
+	// give up after running out of retries
+	transport.failures = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+	m = NewWebhookMessage("evergreen", "https://example.com", []byte("hi"), []byte("something important"), nil)
+	s.Send(m)
+	assert.Error(<-channel)
+	status = m.(WebhookDeliveryRecorder).DeliveryStatus()
+	assert.Equal(evergreenWebhookRetries+1, status.Attempts)
+	assert.Equal(http.StatusBadGateway, status.StatusCode)
+}
+
 type mockWebhookTransport struct {
 	lastUrl string
 	secret  []byte
 	header  http.Header
+	// failures are the status codes of the responses to the next requests
+	failures []int
 }
 
 func (t *mockWebhookTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -132,6 +188,12 @@ func (t *mockWebhookTransport) RoundTrip(req *http.Request) (*http.Response, err
 		StatusCode: http.StatusNoContent,
 		Body:       ioutil.NopCloser(nil),
 	}
+	if len(t.failures) > 0 {
+		resp.StatusCode = t.failures[0]
+		resp.Body = ioutil.NopCloser(bytes.NewBufferString(http.StatusText(t.failures[0])))
+		t.failures = t.failures[1:]
+		return resp, nil
+	}
 	if req.Method != http.MethodPost {
 		resp.StatusCode = http.StatusMethodNotAllowed
 		resp.Body = ioutil.NopCloser(bytes.NewBufferString(fmt.Sprintf("expected method POST, got %s", req.Method)))
@@ -181,3 +243,29 @@ func (t *mockWebhookTransport) RoundTrip(req *http.Request) (*http.Response, err
 
 	return resp, nil
 }
+
+func TestEvergreenWebhookSenderRefusesInternalAddresses(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "192.168.0.1", "169.254.169.254", "0.0.0.0", "::1", "fd00::1", "fe80::1"} {
+		assert.False(IsPublicIP(net.ParseIP(ip)), ip)
+	}
+	assert.True(IsPublicIP(net.ParseIP("93.184.216.34")))
+	assert.True(IsPublicIP(net.ParseIP("2606:2800:220:1::1")))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("webhook was sent to a loopback address")
+	}))
+	defer server.Close()
+
+	sender, err := NewEvergreenWebhookLogger()
+	assert.NoError(err)
+	s, ok := sender.(*evergreenWebhookLogger)
+	assert.True(ok)
+	s.retries = 1
+
+	m := NewWebhookMessage("evergreen", server.URL, []byte("hi"), []byte("something important"), nil)
+	err = s.send(m)
+	assert.Error(err)
+	assert.Contains(err.Error(), "non-public address")
+}
@@ -21,6 +21,8 @@ var distroSyntaxValidators = []distroValidator{
 	ensureValidExpansions,
 	ensureStaticHostsAreNotSpawnable,
 	ensureValidContainerPool,
+	ensureValidHooks,
+	ensureValidIdlePolicy,
 }
 
 // CheckDistro checks if the distro configuration syntax is valid. Returns
@@ -130,6 +132,10 @@ func ensureHasRequiredFields(ctx context.Context, d *distro.Distro, s *evergreen
 		errs = append(errs, ValidationError{Error, err.Error()})
 	}
 
+	if err := cloud.ValidateCapacitySettings(d); err != nil {
+		errs = append(errs, ValidationError{Error, err.Error()})
+	}
+
 	return errs
 }
 
@@ -190,3 +196,21 @@ func ensureValidContainerPool(ctx context.Context, d *distro.Distro, s *evergree
 	}
 	return nil
 }
+
+// ensureValidHooks checks that the distro's lifecycle hooks have scripts and
+// valid timeouts.
+func ensureValidHooks(ctx context.Context, d *distro.Distro, s *evergreen.Settings) ValidationErrors {
+	if err := d.Hooks.Validate(); err != nil {
+		return ValidationErrors{{Error, err.Error()}}
+	}
+	return nil
+}
+
+// ensureValidIdlePolicy checks that the distro's idle policy does not keep
+// more hosts than its pool may have.
+func ensureValidIdlePolicy(ctx context.Context, d *distro.Distro, s *evergreen.Settings) ValidationErrors {
+	if err := d.IdlePolicy.Validate(d.PoolSize); err != nil {
+		return ValidationErrors{{Error, err.Error()}}
+	}
+	return nil
+}
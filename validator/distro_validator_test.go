@@ -319,3 +319,43 @@ func TestEnsureValidContainerPool(t *testing.T) {
 	err = ensureValidContainerPool(ctx, d4, conf)
 	assert.Nil(err)
 }
+
+func TestEnsureValidHooks(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Nil(ensureValidHooks(ctx, &distro.Distro{}, conf))
+	assert.Nil(ensureValidHooks(ctx, &distro.Distro{Hooks: distro.HostHooks{
+		PreProvision: &distro.HostHook{Script: "register-license", TimeoutSecs: 60},
+		PreTerminate: &distro.HostHook{Script: "deregister-license"},
+	}}, conf))
+
+	assert.Len(ensureValidHooks(ctx, &distro.Distro{Hooks: distro.HostHooks{
+		PreProvision: &distro.HostHook{},
+	}}, conf), 1)
+	assert.Len(ensureValidHooks(ctx, &distro.Distro{Hooks: distro.HostHooks{
+		PreTerminate: &distro.HostHook{Script: "flush-caches", TimeoutSecs: 7200},
+	}}, conf), 1)
+	assert.Len(ensureValidHooks(ctx, &distro.Distro{Hooks: distro.HostHooks{
+		PreTerminate: &distro.HostHook{Script: "flush-caches", TimeoutSecs: -1},
+	}}, conf), 1)
+}
+
+func TestEnsureValidIdlePolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Nil(ensureValidIdlePolicy(ctx, &distro.Distro{}, conf))
+	assert.Nil(ensureValidIdlePolicy(ctx, &distro.Distro{PoolSize: 10, IdlePolicy: distro.IdlePolicy{
+		MinPoolSize:   2,
+		MaxIdleMins:   30,
+		ScaleDownStep: 3,
+	}}, conf))
+
+	assert.Len(ensureValidIdlePolicy(ctx, &distro.Distro{PoolSize: 1, IdlePolicy: distro.IdlePolicy{MinPoolSize: 2}}, conf), 1)
+	assert.Len(ensureValidIdlePolicy(ctx, &distro.Distro{PoolSize: 10, IdlePolicy: distro.IdlePolicy{MaxIdleMins: -1}}, conf), 1)
+}
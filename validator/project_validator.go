@@ -25,6 +25,10 @@ const (
 	Warning
 )
 
+// maxRetryBackoffSecs is the longest that a retry policy can make a task
+// wait before it's retried.
+const maxRetryBackoffSecs = 60 * 60
+
 func (vel ValidationErrorLevel) String() string {
 	switch vel {
 	case Error:
@@ -89,6 +93,9 @@ var projectSyntaxValidators = []projectValidator{
 	validateGenerateTasks,
 	validateCreateHosts,
 	validateDuplicateTaskDefinition,
+	validateRetryPolicies,
+	validateParameters,
+	validateSchemaVersion,
 }
 
 // Functions used to validate the semantics of a project configuration file.
@@ -142,6 +149,22 @@ func CheckProjectSemantics(project *model.Project) ValidationErrors {
 	return validationErrs
 }
 
+// CheckProjectDeprecations returns a warning for each deprecated syntax that
+// the project's config was written with. It's separate from the syntax
+// checks, since configs that Evergreen rewrites, such as those of generated
+// tasks, may keep deprecated syntax that their authors didn't write.
+func CheckProjectDeprecations(project *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	for _, d := range project.Deprecations {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("'%s' is deprecated as of schema version %d (replacement: %s; used by %s)",
+				d.Syntax, d.Since, d.Replacement, strings.Join(d.Locations, ", ")),
+			Level: Warning,
+		})
+	}
+	return errs
+}
+
 // verify that the project configuration syntax is valid
 func CheckProjectSyntax(project *model.Project) (ValidationErrors, error) {
 	validationErrs := ValidationErrors{}
@@ -950,6 +973,74 @@ func validateDuplicateTaskDefinition(p *model.Project) ValidationErrors {
 	return errors
 }
 
+// validateRetryPolicies checks that the project's and tasks' retry policies
+// don't retry without bound or back off for longer than a task can wait.
+func validateRetryPolicies(p *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	errs = append(errs, checkRetryPolicy(p.Retry, "project")...)
+	for _, t := range p.Tasks {
+		errs = append(errs, checkRetryPolicy(t.Retry, fmt.Sprintf("task '%s'", t.Name))...)
+	}
+	return errs
+}
+
+func checkRetryPolicy(policy *model.RetryPolicy, owner string) ValidationErrors {
+	errs := ValidationErrors{}
+	if policy == nil {
+		return errs
+	}
+	if policy.MaxRetries < 0 || policy.MaxRetries > evergreen.MaxTaskExecution {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("max_retries of %s must be between 0 and %d", owner, evergreen.MaxTaskExecution),
+			Level:   Error,
+		})
+	}
+	if policy.BackoffSecs < 0 || policy.BackoffSecs > maxRetryBackoffSecs {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("backoff_secs of %s must be between 0 and %d", owner, maxRetryBackoffSecs),
+			Level:   Error,
+		})
+	}
+	return errs
+}
+
+// validateParameters checks that the project's parameters have distinct keys
+// that can be referenced as expansions.
+func validateParameters(p *model.Project) ValidationErrors {
+	errs := ValidationErrors{}
+	keys := map[string]bool{}
+	for _, param := range p.Parameters {
+		if param.Key == "" || strings.ContainsAny(param.Key, " \t\n${}|") {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("parameter key '%s' must be non-empty and can't contain whitespace or any of '${}|'", param.Key),
+				Level:   Error,
+			})
+			continue
+		}
+		if keys[param.Key] {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("parameter '%s' is defined more than once", param.Key),
+				Level:   Error,
+			})
+		}
+		keys[param.Key] = true
+	}
+	return errs
+}
+
+// validateSchemaVersion checks that the project's schema version is one that
+// can be parsed.
+func validateSchemaVersion(p *model.Project) ValidationErrors {
+	if p.SchemaVersion < 0 || p.SchemaVersion > model.ProjectSchemaVersion {
+		return ValidationErrors{{
+			Message: fmt.Sprintf("schema version %d is not supported, the newest version is %d",
+				p.SchemaVersion, model.ProjectSchemaVersion),
+			Level: Error,
+		}}
+	}
+	return nil
+}
+
 func checkOrAddTask(task, variant string, tasksFound map[string]interface{}) *ValidationError {
 	if _, found := tasksFound[task]; found {
 		return &ValidationError{
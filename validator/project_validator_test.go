@@ -2015,3 +2015,125 @@ func TestDuplicateTaskInBV(t *testing.T) {
 	assert.Len(errs, 1)
 	assert.Contains(errs[0].Message, "task 't1' in 'bv' is listed more than once")
 }
+
+func TestValidateRetryPolicies(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+retry:
+  max_retries: 2
+  system_failures_only: true
+  backoff_secs: 30
+tasks:
+- name: t_1
+  retry:
+    max_retries: 1
+buildvariants:
+- name: "bv"
+  tasks:
+  - name: t_1
+`
+	var p model.Project
+	require.NoError(model.LoadProjectInto([]byte(yml), "id", &p))
+	require.NotNil(p.Retry)
+	assert.Equal(2, p.Retry.MaxRetries)
+	assert.True(p.Retry.SystemFailuresOnly)
+	require.NotNil(p.Tasks[0].Retry)
+	assert.Equal(1, p.Tasks[0].Retry.MaxRetries)
+	assert.Empty(validateRetryPolicies(&p))
+
+	p.Retry.MaxRetries = evergreen.MaxTaskExecution + 1
+	p.Tasks[0].Retry.BackoffSecs = -1
+	assert.Len(validateRetryPolicies(&p), 2)
+}
+
+func TestValidateParameters(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+parameters:
+- key: suite
+  value: smoke
+  description: the test suite to run
+- key: iterations
+buildvariants:
+- name: "bv"
+  tasks:
+  - name: t_1
+tasks:
+- name: t_1
+`
+	var p model.Project
+	require.NoError(model.LoadProjectInto([]byte(yml), "id", &p))
+	require.Len(p.Parameters, 2)
+	assert.Equal("smoke", p.Parameters[0].Value)
+	assert.Equal("the test suite to run", p.Parameters[0].Description)
+	assert.Empty(validateParameters(&p))
+
+	p.Parameters = append(p.Parameters,
+		model.ParameterDefinition{Key: "suite"},
+		model.ParameterDefinition{Key: "has space"},
+		model.ParameterDefinition{Key: ""})
+	assert.Len(validateParameters(&p), 3)
+}
+
+func TestValidateSchemaVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &model.Project{}
+	assert.Empty(validateSchemaVersion(p))
+	p.SchemaVersion = model.ProjectSchemaVersion
+	assert.Empty(validateSchemaVersion(p))
+
+	p.SchemaVersion = model.ProjectSchemaVersion + 1
+	errs := validateSchemaVersion(p)
+	assert.Len(errs, 1)
+	assert.Equal(Error, errs[0].Level)
+	p.SchemaVersion = -1
+	assert.Len(validateSchemaVersion(p), 1)
+}
+
+func TestCheckProjectDeprecations(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+owner: evergreen-ci
+buildvariants:
+- name: "bv"
+  tasks:
+  - name: t_1
+    distros: d1
+  - name: t_2
+    run_on: d1
+tasks:
+- name: t_1
+- name: t_2
+`
+	var p model.Project
+	require.NoError(model.LoadProjectInto([]byte(yml), "id", &p))
+	errs := CheckProjectDeprecations(&p)
+	require.Len(errs, 2)
+	assert.Equal(Warning, errs[0].Level)
+	assert.Contains(errs[0].Message, "'owner'")
+	assert.Equal(Warning, errs[1].Level)
+	assert.Contains(errs[1].Message, "'distros'")
+	assert.Contains(errs[1].Message, "replacement: run_on")
+	assert.Contains(errs[1].Message, "buildvariant 'bv' task 't_1'")
+
+	yml = `
+schema_version: 2
+buildvariants:
+- name: "bv"
+  tasks:
+  - name: t_1
+    run_on: d1
+tasks:
+- name: t_1
+`
+	require.NoError(model.LoadProjectInto([]byte(yml), "id", &p))
+	assert.Equal(2, p.SchemaVersion)
+	assert.Empty(CheckProjectDeprecations(&p))
+}